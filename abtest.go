@@ -0,0 +1,112 @@
+package main
+
+import (
+	"github.com/run-bigpig/jcp/internal/meeting"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+// ABTestRequest 专家 A/B 对比请求
+type ABTestRequest struct {
+	StockCode string `json:"stockCode"`
+	AgentID   string `json:"agentId"`
+	Content   string `json:"content"`
+}
+
+// ABTestResponse 专家 A/B 对比结果，回复同时通过 meeting:message 事件推送
+type ABTestResponse struct {
+	Success  bool                 `json:"success"`
+	TestID   string               `json:"testId,omitempty"`
+	Messages []models.ChatMessage `json:"messages"`
+	Error    string               `json:"error,omitempty"`
+}
+
+// RunAgentABTest 将同一问题交给专家的 A（当前配置）与 B（变体）两个版本并排作答
+func (a *App) RunAgentABTest(req ABTestRequest) ABTestResponse {
+	if a.sessionService.GetSession(req.StockCode) == nil {
+		return ABTestResponse{Error: i18n.T("会话不存在")}
+	}
+	variants, err := a.strategyService.GetABTestVariants(req.AgentID)
+	if err != nil {
+		return ABTestResponse{Error: err.Error()}
+	}
+	aiConfig := a.configService.GetConfig().ResolveAIConfig(models.AITaskExpert)
+	if aiConfig == nil {
+		return ABTestResponse{Error: i18n.T("未配置 AI 服务")}
+	}
+
+	meetingCtx, endMeeting := a.beginMeeting(a.ctx, req.StockCode)
+	defer endMeeting()
+
+	a.sessionService.AddMessage(req.StockCode, models.ChatMessage{
+		AgentID:   "user",
+		AgentName: "老韭菜",
+		Content:   req.Content,
+		Mentions:  []string{req.AgentID},
+	})
+
+	var stock models.Stock
+	if stocks, _ := a.marketService.GetStockRealTimeData(req.StockCode); len(stocks) > 0 {
+		stock = stocks[0]
+	}
+	chatReq := meeting.ChatRequest{
+		StockCode: req.StockCode,
+		Stock:     stock,
+		Query:     req.Content,
+		Position:  a.sessionService.GetPosition(req.StockCode),
+	}
+	responses, err := a.meetingService.RunABTest(meetingCtx, aiConfig, chatReq, variants)
+	if err != nil {
+		return ABTestResponse{Error: err.Error()}
+	}
+
+	record := services.ABTestRecord{
+		AgentID:   req.AgentID,
+		AgentName: variants[0].Name,
+		StockCode: req.StockCode,
+		Query:     req.Content,
+	}
+	for i, resp := range responses {
+		record.Variants = append(record.Variants, services.ABTestVariant{
+			Label:       resp.Variant,
+			Instruction: variants[i].Instruction,
+			AIConfigID:  variants[i].AIConfigID,
+			Model:       resp.ModelName,
+			Content:     resp.Content,
+			Error:       resp.Error,
+		})
+	}
+	record, err = a.qualityService.RecordABTest(record)
+	if err != nil {
+		log.Warn("保存 A/B 对比记录失败: %v", err)
+	}
+
+	messages := make([]models.ChatMessage, 0, len(responses))
+	for _, resp := range responses {
+		msg := chatMessageFromResponse(resp)
+		msg.ABTestID = record.ID
+		a.sessionService.AddMessage(req.StockCode, msg)
+		a.eventBus.Emit("meeting:message:"+req.StockCode, msg)
+		messages = append(messages, msg)
+	}
+	return ABTestResponse{Success: true, TestID: record.ID, Messages: messages}
+}
+
+// RateABTest 记录老韭菜在 A/B 对比中更偏好的版本（A/B/tie）
+func (a *App) RateABTest(testID, preferred string) string {
+	if err := a.qualityService.RateABTest(testID, preferred); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// GetAgentABTests 获取专家的 A/B 对比记录（最新的在前）
+func (a *App) GetAgentABTests(agentID string, limit int) []services.ABTestRecord {
+	return a.qualityService.GetABTests(agentID, limit)
+}
+
+// GetAgentQualityStats 获取各专家的 A/B 对比统计
+func (a *App) GetAgentQualityStats() []services.AgentQualityStats {
+	return a.qualityService.GetStats()
+}
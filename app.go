@@ -2,24 +2,33 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/run-bigpig/jcp/internal/adk"
 	"github.com/run-bigpig/jcp/internal/adk/mcp"
 	"github.com/run-bigpig/jcp/internal/adk/tools"
 	"github.com/run-bigpig/jcp/internal/agent"
+	"github.com/run-bigpig/jcp/internal/broker"
+	"github.com/run-bigpig/jcp/internal/eventbus"
 	"github.com/run-bigpig/jcp/internal/logger"
 	"github.com/run-bigpig/jcp/internal/meeting"
 	"github.com/run-bigpig/jcp/internal/memory"
 	"github.com/run-bigpig/jcp/internal/models"
 	"github.com/run-bigpig/jcp/internal/openclaw"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
 	"github.com/run-bigpig/jcp/internal/pkg/paths"
 	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+	"github.com/run-bigpig/jcp/internal/pkg/vault"
 	"github.com/run-bigpig/jcp/internal/services"
 	"github.com/run-bigpig/jcp/internal/services/hottrend"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"google.golang.org/adk/model"
 )
 
 var log = logger.New("app")
@@ -27,20 +36,37 @@ var log = logger.New("app")
 // App struct
 type App struct {
 	ctx               context.Context
+	eventBus          eventbus.Bus // 事件总线（桌面模式为 Wails 运行时，无界面模式为 SSE）
 	configService     *services.ConfigService
 	marketService     *services.MarketService
 	newsService       *services.NewsService
 	hotTrendService   *hottrend.HotTrendService
 	longHuBangService *services.LongHuBangService
+	limitBoardService *services.LimitBoardService
+	stockNewsService  *services.StockNewsService
+	chartService      *services.ChartService
 	marketPusher      *services.MarketDataPusher
+	tradePlanMonitor  *services.TradePlanMonitor
+	paperTrading      *services.PaperTradingService
+	briefingScheduler *services.BriefingScheduler
+	configWatcher     *services.ConfigWatcher
+	notifier          *services.NotificationService
+	pushService       *services.PushService
 	meetingService    *meeting.Service
+	replayStore       *meeting.ReplayStore
+	researchStore     *meeting.ResearchStore
 	sessionService    *services.SessionService
 	strategyService   *services.StrategyService
+	qualityService    *services.AgentQualityService
+	templateService   *services.MeetingTemplateService
+	verdictTracker    *services.VerdictTracker
+	ttsService        *services.TTSService
 	agentContainer    *agent.Container
 	toolRegistry      *tools.Registry
 	mcpManager        *mcp.Manager
 	memoryManager     *memory.Manager
 	updateService     *services.UpdateService
+	stockListUpdater  *services.StockListUpdater
 	openClawServer    *openclaw.Server
 
 	// 会议取消管理
@@ -58,11 +84,16 @@ func NewApp() *App {
 	}
 	logger.SetGlobalLevel(logger.DEBUG)
 
+	// 加载数据加密设置（需在各服务读取数据前）
+	initVault(dataDir)
+
 	// 初始化配置服务
 	configService, err := services.NewConfigService(dataDir)
 	if err != nil {
 		panic(err)
 	}
+	// 工具说明在注册时按语言生成，需在创建工具注册中心前设置
+	i18n.SetLanguage(configService.GetConfig().Language)
 
 	// 初始化研报服务
 	researchReportService := services.NewResearchReportService()
@@ -79,8 +110,47 @@ func NewApp() *App {
 	// 初始化龙虎榜服务
 	longHuBangService := services.NewLongHuBangService()
 
+	// 初始化涨跌停板监控服务
+	limitBoardService := services.NewLimitBoardService()
+
+	// 初始化个股新闻聚合服务
+	stockNewsService := services.NewStockNewsService(newsService)
+
+	// 初始化散户情绪服务（总结使用会议总结的 AI 路由）
+	retailSentimentService := services.NewRetailSentimentService()
+	retailSentimentService.SetLLMProvider(func(ctx context.Context) (model.LLM, error) {
+		aiConfig := configService.GetConfig().ResolveAIConfig(models.AITaskSummary)
+		if aiConfig == nil {
+			return nil, fmt.Errorf("未配置AI服务")
+		}
+		return adk.NewModelFactory().CreateModel(ctx, aiConfig)
+	})
+
+	// 初始化事件日历服务
+	eventService := services.NewEventService()
+
+	// 初始化宏观数据服务
+	macroService := services.NewMacroService()
+
+	// 初始化图表服务
+	chartService := services.NewChartService(marketService, paths.EnsureCacheDir("charts"))
+
+	// 初始化选股服务
+	screenerService := services.NewScreenerService()
+
+	// 初始化模拟盘
+	paperTradingService := services.NewPaperTradingService(dataDir, marketService)
+
 	// 初始化工具注册中心
-	toolRegistry := tools.NewRegistry(marketService, newsService, configService, researchReportService, hotTrendSvc, longHuBangService)
+	toolRegistry := tools.NewRegistry(marketService, newsService, configService, researchReportService, hotTrendSvc, longHuBangService, limitBoardService, stockNewsService, retailSentimentService, eventService, macroService, chartService, screenerService, paperTradingService)
+	toolRegistry.SetSummaryLLMProvider(func(ctx context.Context) (model.LLM, error) {
+		aiConfig := configService.GetConfig().ResolveAIConfig(models.AITaskExtraction)
+		if aiConfig == nil {
+			return nil, fmt.Errorf("未配置AI服务")
+		}
+		return adk.NewModelFactory().CreateModel(ctx, aiConfig)
+	})
+	applyToolConfig(toolRegistry, configService.GetConfig())
 
 	// 初始化 MCP 管理器
 	mcpManager := mcp.NewManager()
@@ -90,41 +160,50 @@ func NewApp() *App {
 
 	// 初始化会议室服务
 	meetingService := meeting.NewServiceFull(toolRegistry, mcpManager)
+	replayStore := meeting.NewReplayStore(filepath.Join(dataDir, "replays"))
+	meetingService.SetReplayStore(replayStore)
+	researchStore := meeting.NewResearchStore(filepath.Join(dataDir, "research"))
+	meetingService.SetResearchStore(researchStore)
+	verdictTracker := services.NewVerdictTracker(dataDir)
+	meetingService.SetVerdictWeigher(verdictTracker.Weight)
+	meetingService.SetSnapshotFetcher(marketService.GetStockSnapshot)
+
+	// 语音朗读：openai 引擎复用 AI 配置的接口地址与 Key，未指定时使用默认配置
+	ttsService := services.NewTTSService(dataDir,
+		func() models.TTSConfig { return configService.GetConfig().TTS },
+		func(id string) *models.AIConfig {
+			cfg := configService.GetConfig()
+			if ai := cfg.FindAIConfig(id); ai != nil {
+				return ai
+			}
+			return cfg.DefaultAIConfig()
+		})
 
 	// 初始化记忆管理器
 	var memoryManager *memory.Manager
 	memConfig := configService.GetConfig().Memory
 	if memConfig.Enabled {
 		memoryManager = memory.NewManagerWithConfig(dataDir, memory.Config{
-			MaxRecentRounds:   memConfig.MaxRecentRounds,
-			MaxKeyFacts:       memConfig.MaxKeyFacts,
-			MaxSummaryLength:  memConfig.MaxSummaryLength,
-			CompressThreshold: memConfig.CompressThreshold,
+			MaxRecentRounds:     memConfig.MaxRecentRounds,
+			MaxKeyFacts:         memConfig.MaxKeyFacts,
+			MaxSummaryLength:    memConfig.MaxSummaryLength,
+			CompressThreshold:   memConfig.CompressThreshold,
+			ConsolidateInterval: memConfig.ConsolidateInterval,
 		})
+		// 按行业关联跨股票的行业记忆池
+		memoryManager.SetSectorResolver(services.GetStockIndex().Industry)
 		meetingService.SetMemoryManager(memoryManager)
-
-		if memConfig.AIConfigID != "" {
-			for i := range configService.GetConfig().AIConfigs {
-				if configService.GetConfig().AIConfigs[i].ID == memConfig.AIConfigID {
-					meetingService.SetMemoryAIConfig(&configService.GetConfig().AIConfigs[i])
-					log.Info("Memory LLM: %s", configService.GetConfig().AIConfigs[i].ModelName)
-					break
-				}
-			}
-		}
 		log.Info("Memory manager enabled")
 	}
 
-	// 设置 Moderator AI 配置
-	if configService.GetConfig().ModeratorAIID != "" {
-		for i := range configService.GetConfig().AIConfigs {
-			if configService.GetConfig().AIConfigs[i].ID == configService.GetConfig().ModeratorAIID {
-				meetingService.SetModeratorAIConfig(&configService.GetConfig().AIConfigs[i])
-				log.Info("Moderator LLM: %s", configService.GetConfig().AIConfigs[i].ModelName)
-				break
-			}
-		}
-	}
+	// 设置单次会议费用上限与用户画像
+	meetingService.SetMeetingBudget(configService.GetConfig().MeetingBudget)
+	meetingService.SetMaxConcurrentMeetings(configService.GetConfig().MaxConcurrentMeetings)
+	meetingService.SetUserProfile(configService.GetConfig().UserProfile)
+	meetingService.SetContextBudget(configService.GetConfig().ContextBudget)
+
+	// 按任务路由表设置小韭菜、总结、记忆、提取使用的 AI 配置
+	applyAIRouting(meetingService, configService.GetConfig())
 
 	// 初始化Session服务
 	sessionService := services.NewSessionService(dataDir)
@@ -139,18 +218,16 @@ func NewApp() *App {
 	// 初始化更新服务
 	updateService := services.NewUpdateService("run-bigpig", "jcp", Version)
 
+	// 初始化股票列表自动更新（覆盖嵌入的基础数据，补充新股与更名）
+	stockListUpdater := services.NewStockListUpdater(dataDir)
+
 	// 初始化 OpenClaw 服务
 	openClawServer := openclaw.NewServer(meetingService, agentContainer, func(aiConfigID string) *models.AIConfig {
 		cfg := configService.GetConfig()
 		if aiConfigID == "" {
-			aiConfigID = cfg.DefaultAIID
+			return cfg.ResolveAIConfig(models.AITaskExpert)
 		}
-		for i := range cfg.AIConfigs {
-			if cfg.AIConfigs[i].ID == aiConfigID {
-				return &cfg.AIConfigs[i]
-			}
-		}
-		return nil
+		return cfg.FindAIConfig(aiConfigID)
 	}, func(code string) (*models.Stock, error) {
 		stocks, err := marketService.GetStockRealTimeData(code)
 		if err != nil {
@@ -170,14 +247,25 @@ func NewApp() *App {
 		newsService:       newsService,
 		hotTrendService:   hotTrendSvc,
 		longHuBangService: longHuBangService,
+		limitBoardService: limitBoardService,
+		stockNewsService:  stockNewsService,
+		chartService:      chartService,
+		paperTrading:      paperTradingService,
 		meetingService:    meetingService,
+		replayStore:       replayStore,
+		researchStore:     researchStore,
 		sessionService:    sessionService,
 		strategyService:   strategyService,
+		qualityService:    services.NewAgentQualityService(dataDir),
+		templateService:   services.NewMeetingTemplateService(dataDir),
+		verdictTracker:    verdictTracker,
+		ttsService:        ttsService,
 		agentContainer:    agentContainer,
 		toolRegistry:      toolRegistry,
 		mcpManager:        mcpManager,
 		memoryManager:     memoryManager,
 		updateService:     updateService,
+		stockListUpdater:  stockListUpdater,
 		openClawServer:    openClawServer,
 		meetingCancels:    make(map[string]context.CancelFunc),
 	}
@@ -187,15 +275,33 @@ func NewApp() *App {
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	if a.eventBus == nil {
+		a.eventBus = eventbus.NewWailsBus(ctx)
+	}
 
 	// 初始化代理配置
 	proxy.GetManager().SetConfig(&a.configService.GetConfig().Proxy)
 
-	// 初始化 MCP 管理器（绑定主 context，预创建 toolset）
+	// 初始化桌面通知（系统通知 + 应用内 notification 事件）
+	a.notifier = services.NewNotificationService(func() models.NotificationConfig {
+		return a.configService.GetConfig().Notification
+	}, func(n services.Notification) {
+		a.eventBus.Emit("notification", n)
+	})
+	// 外部推送渠道（企业微信/钉钉/Telegram/Bark）
+	a.pushService = services.NewPushService(func() []models.PushChannelConfig {
+		return a.configService.GetConfig().PushChannels
+	})
+	a.notifier.SetPusher(a.pushService.Push)
+
+	// 初始化 MCP 管理器（绑定主 context，预创建 toolset），断开时通知
 	if a.mcpManager != nil {
 		if err := a.mcpManager.Initialize(ctx); err != nil {
 			log.Warn("MCP 初始化失败: %v", err)
 		}
+		a.mcpManager.WatchHealth(ctx, func(name, errMsg string) {
+			a.notify(services.Notification{Category: services.NotifyMCP, Title: "MCP 服务器已断开", Body: name + ": " + errMsg, Key: "mcp:" + name})
+		})
 	}
 
 	// 设置 Meeting 服务的 AI 配置解析器
@@ -205,14 +311,61 @@ func (a *App) startup(ctx context.Context) {
 
 	// 初始化更新服务
 	if a.updateService != nil {
-		a.updateService.Startup(ctx)
+		a.updateService.Startup(a.eventBus)
+	}
+
+	// 启动股票列表后台更新
+	if a.stockListUpdater != nil {
+		a.stockListUpdater.Start(ctx)
 	}
 
 	// 初始化并启动市场数据推送服务（需要 context）
-	a.marketPusher = services.NewMarketDataPusher(a.marketService, a.configService, a.newsService)
-	a.marketPusher.Start(ctx)
+	a.marketPusher = services.NewMarketDataPusher(a.eventBus, a.marketService, a.configService, a.newsService, a.limitBoardService)
+	a.marketPusher.Start()
 	log.Info("市场数据推送服务已启动")
 
+	// 启动交易计划监控（止损/目标价触发提醒）
+	a.tradePlanMonitor = services.NewTradePlanMonitor(a.sessionService, a.marketService, a.onTradePlanAlert)
+	a.tradePlanMonitor.Start(ctx)
+
+	// 启动模拟盘撮合，专家委托待用户确认时通知前端
+	a.paperTrading.SetPendingHandler(func(order models.PaperOrder) {
+		a.eventBus.Emit("paper:order:pending", order)
+		a.notify(services.Notification{
+			Category: services.NotifyAlert,
+			Title:    "模拟委托待确认",
+			Body:     fmt.Sprintf("%s 提交 %s %s %d股", order.Source, order.Side, order.Name, order.Shares),
+			Key:      "paper:" + order.ID,
+		})
+	})
+	a.paperTrading.Start(ctx)
+
+	// 启动盘前早报调度
+	a.briefingScheduler = services.NewBriefingScheduler(a.marketService, a.generateBriefings)
+	a.briefingScheduler.Start(ctx)
+
+	// 每日巡检持仓与会议建议背离
+	go a.adviceDivergenceLoop(ctx)
+
+	// 配置变更后热更新各服务；外部编辑配置文件时校验后热加载
+	a.configService.OnChange(a.onConfigChanged)
+	a.configWatcher = services.NewConfigWatcher(a.configService, a.onConfigInvalid)
+	a.configWatcher.Start(ctx)
+
+	// 已接入券商时启动后同步一次持仓（数据未解锁时在解锁后同步）
+	if a.configService.GetConfig().Broker.Enabled && !vault.Default().Locked() {
+		go func() {
+			if resp := a.SyncBrokerPositions(); !resp.Success {
+				log.Warn("券商持仓同步失败: %s", resp.Error)
+			}
+		}()
+	}
+
+	// 启动舆情热点后台刷新
+	if a.hotTrendService != nil {
+		a.hotTrendService.Start(ctx, a.eventBus)
+	}
+
 	// 启动 OpenClaw 服务（如果已启用）
 	cfg := a.configService.GetConfig()
 	if cfg.OpenClaw.Enabled && cfg.OpenClaw.Port > 0 {
@@ -231,6 +384,24 @@ func (a *App) shutdown(ctx context.Context) {
 	if a.marketPusher != nil {
 		a.marketPusher.Stop()
 	}
+	if a.tradePlanMonitor != nil {
+		a.tradePlanMonitor.Stop()
+	}
+	if a.paperTrading != nil {
+		a.paperTrading.Stop()
+	}
+	if a.briefingScheduler != nil {
+		a.briefingScheduler.Stop()
+	}
+	if a.configWatcher != nil {
+		a.configWatcher.Stop()
+	}
+	if a.hotTrendService != nil {
+		a.hotTrendService.Stop()
+	}
+	if a.stockListUpdater != nil {
+		a.stockListUpdater.Stop()
+	}
 	logger.Close()
 }
 
@@ -244,40 +415,141 @@ func (a *App) GetConfig() *models.AppConfig {
 	return a.configService.GetConfig()
 }
 
-// UpdateConfig 更新配置
+// UpdateConfig 校验并更新配置，各服务通过变更事件热更新
 func (a *App) UpdateConfig(config *models.AppConfig) string {
 	if err := a.configService.UpdateConfig(config); err != nil {
 		return err.Error()
 	}
+	return "success"
+}
+
+// ConfigChangedEvent 配置变更事件（推送给前端）
+type ConfigChangedEvent struct {
+	Sections []services.ConfigSection `json:"sections"`
+	Source   services.ConfigSource    `json:"source"`
+	Warnings []string                 `json:"warnings,omitempty"` // 引用缺失等不阻止生效的问题
+}
+
+// onConfigChanged 配置生效后按变更分区热更新各服务
+func (a *App) onConfigChanged(change services.ConfigChange) {
+	config := change.New
 	// 重新加载 MCP 配置
-	if a.mcpManager != nil && config.MCPServers != nil {
+	if change.Has(services.ConfigSectionMCP) && a.mcpManager != nil {
 		if err := a.mcpManager.LoadConfigs(config.MCPServers); err != nil {
 			log.Warn("MCP reload error: %v", err)
 		}
 	}
+	// 重新加载自定义工具与禁用列表
+	if change.Has(services.ConfigSectionTools) {
+		applyToolConfig(a.toolRegistry, config)
+	}
+	// 更新后端文案语言（已注册的内置工具说明重启后生效）
+	if change.Has(services.ConfigSectionOther) {
+		i18n.SetLanguage(config.Language)
+	}
 	// 更新代理配置
-	proxy.GetManager().SetConfig(&config.Proxy)
-	// 更新记忆管理器的 LLM 配置
-	if a.meetingService != nil && config.Memory.AIConfigID != "" {
-		for i := range config.AIConfigs {
-			if config.AIConfigs[i].ID == config.Memory.AIConfigID {
-				a.meetingService.SetMemoryAIConfig(&config.AIConfigs[i])
-				break
+	if change.Has(services.ConfigSectionProxy) {
+		proxy.GetManager().SetConfig(&config.Proxy)
+	}
+	// 释放已删除 AI 配置的 API Key 池
+	if change.Has(services.ConfigSectionAI) {
+		ids := make([]string, 0, len(config.AIConfigs))
+		for _, ai := range config.AIConfigs {
+			ids = append(ids, ai.ID)
+		}
+		adk.PruneKeyPools(ids)
+	}
+	// 更新任务路由、会议费用上限、并发上限与用户画像
+	if a.meetingService != nil && (change.Has(services.ConfigSectionAI) || change.Has(services.ConfigSectionMeeting)) {
+		applyAIRouting(a.meetingService, config)
+		a.meetingService.SetMeetingBudget(config.MeetingBudget)
+		a.meetingService.SetMaxConcurrentMeetings(config.MaxConcurrentMeetings)
+		a.meetingService.SetUserProfile(config.UserProfile)
+		a.meetingService.SetContextBudget(config.ContextBudget)
+	}
+	// 更新 OpenClaw 服务配置（热更新）
+	if change.Has(services.ConfigSectionOpenClaw) {
+		a.applyOpenClawConfig(&config.OpenClaw)
+	}
+
+	log.Info("配置已更新 [%s]: %v", change.Source, change.Sections)
+	a.eventBus.Emit("config:changed", ConfigChangedEvent{
+		Sections: change.Sections,
+		Source:   change.Source,
+		Warnings: config.ReferenceWarnings(),
+	})
+	go a.checkConfigConnectivity(change)
+}
+
+// onConfigInvalid 外部编辑的配置文件未通过校验，原配置继续生效
+func (a *App) onConfigInvalid(err error) {
+	a.eventBus.Emit("config:invalid", err.Error())
+	a.notify(services.Notification{
+		Category: services.NotifyAlert,
+		Title:    "配置文件修改未生效",
+		Body:     err.Error(),
+		Key:      fmt.Sprintf("config:invalid:%d", time.Now().UnixNano()),
+	})
+}
+
+// checkConfigConnectivity 对新增或修改的 AI / MCP 配置做连通性检查，失败时推送 config:warning
+// 网络可能暂时不可用，检查结果只作提示，不回滚已生效的配置
+func (a *App) checkConfigConnectivity(change services.ConfigChange) {
+	var warnings []string
+	if change.Has(services.ConfigSectionAI) {
+		factory := adk.NewModelFactory()
+		for _, ai := range change.New.AIConfigs {
+			if !aiConfigChanged(change.Old.FindAIConfig(ai.ID), &ai) {
+				continue
+			}
+			if err := factory.TestConnection(a.ctx, &ai); err != nil {
+				warnings = append(warnings, fmt.Sprintf("AI 配置 %q 连接失败: %v", ai.Name, err))
 			}
 		}
 	}
-	// 更新 Moderator AI 配置
-	if a.meetingService != nil && config.ModeratorAIID != "" {
-		for i := range config.AIConfigs {
-			if config.AIConfigs[i].ID == config.ModeratorAIID {
-				a.meetingService.SetModeratorAIConfig(&config.AIConfigs[i])
-				break
+	if change.Has(services.ConfigSectionMCP) {
+		oldServers := make(map[string]models.MCPServerConfig, len(change.Old.MCPServers))
+		for _, srv := range change.Old.MCPServers {
+			oldServers[srv.ID] = srv
+		}
+		for _, srv := range change.New.MCPServers {
+			if old, ok := oldServers[srv.ID]; !srv.Enabled || (ok && reflect.DeepEqual(old, srv)) {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
+			err := mcp.Ping(ctx, &srv)
+			cancel()
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("MCP 服务器 %q 连接失败: %v", srv.Name, err))
 			}
 		}
 	}
-	// 更新 OpenClaw 服务配置（热更新）
-	a.applyOpenClawConfig(&config.OpenClaw)
-	return "success"
+	if len(warnings) == 0 {
+		return
+	}
+	log.Warn("配置连通性检查: %s", strings.Join(warnings, "; "))
+	a.eventBus.Emit("config:warning", warnings)
+}
+
+// aiConfigChanged 判断 AI 配置的连接参数是否变化（忽略自动探测写入的能力字段）
+func aiConfigChanged(old, new *models.AIConfig) bool {
+	if old == nil {
+		return true
+	}
+	a, b := *old, *new
+	a.Capabilities, b.Capabilities = models.ModelCapabilities{}, models.ModelCapabilities{}
+	a.NoSystemRole, b.NoSystemRole = false, false
+	return !reflect.DeepEqual(a, b)
+}
+
+// applyToolConfig 应用 Python 工具、自定义工具、工具禁用与结果上限配置
+func applyToolConfig(registry *tools.Registry, config *models.AppConfig) {
+	registry.SetPythonTool(config.PythonTool)
+	registry.SetOutputLimits(config.ContextBudget)
+	if err := registry.LoadCustomTools(config.CustomTools); err != nil {
+		log.Warn("custom tools load error: %v", err)
+	}
+	registry.SetDisabledTools(config.DisabledTools)
 }
 
 // applyOpenClawConfig 应用 OpenClaw 配置变更
@@ -369,8 +641,8 @@ func (a *App) AddToWatchlist(stock models.Stock) string {
 	if err := a.configService.AddToWatchlist(stock); err != nil {
 		return err.Error()
 	}
-	// 同步添加到推送订阅
-	a.marketPusher.AddSubscription(stock.Symbol)
+	// 同步更新推送订阅（仅当前可见分组）
+	a.marketPusher.RefreshSubscriptions()
 	return "success"
 }
 
@@ -380,7 +652,7 @@ func (a *App) RemoveFromWatchlist(symbol string) string {
 		return err.Error()
 	}
 	// 同步移除推送订阅
-	a.marketPusher.RemoveSubscription(symbol)
+	a.marketPusher.RefreshSubscriptions()
 	// 清空该股票的聊天记录
 	a.sessionService.ClearMessages(symbol)
 	// 同步清除该股票的记忆
@@ -392,18 +664,77 @@ func (a *App) RemoveFromWatchlist(symbol string) string {
 	return "success"
 }
 
+// GetWatchlistGroups 获取自选股分组
+func (a *App) GetWatchlistGroups() []models.WatchlistGroup {
+	return a.configService.GetWatchlistGroups()
+}
+
+// AddWatchlistGroup 新建自选股分组
+func (a *App) AddWatchlistGroup(name string) string {
+	if _, err := a.configService.AddWatchlistGroup(name); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// RemoveWatchlistGroup 删除自选股分组
+func (a *App) RemoveWatchlistGroup(id string) string {
+	if err := a.configService.RemoveWatchlistGroup(id); err != nil {
+		return err.Error()
+	}
+	a.marketPusher.RefreshSubscriptions()
+	return "success"
+}
+
+// AddStockToGroup 将自选股加入分组
+func (a *App) AddStockToGroup(groupID, symbol string) string {
+	if err := a.configService.AddToGroup(groupID, symbol); err != nil {
+		return err.Error()
+	}
+	a.marketPusher.RefreshSubscriptions()
+	return "success"
+}
+
+// RemoveStockFromGroup 将股票移出分组
+func (a *App) RemoveStockFromGroup(groupID, symbol string) string {
+	if err := a.configService.RemoveFromGroup(groupID, symbol); err != nil {
+		return err.Error()
+	}
+	a.marketPusher.RefreshSubscriptions()
+	return "success"
+}
+
+// ReorderWatchlist 保存拖拽排序（groupID 为空时调整全部自选股顺序）
+func (a *App) ReorderWatchlist(groupID string, symbols []string) string {
+	if err := a.configService.ReorderWatchlist(groupID, symbols); err != nil {
+		return err.Error()
+	}
+	a.marketPusher.RefreshSubscriptions()
+	return "success"
+}
+
 // GetStockRealTimeData 获取股票实时数据
 func (a *App) GetStockRealTimeData(codes []string) []models.Stock {
 	stocks, _ := a.marketService.GetStockRealTimeData(codes...)
 	return stocks
 }
 
-// GetKLineData 获取K线数据
-func (a *App) GetKLineData(code string, period string, days int) []models.KLineData {
-	data, _ := a.marketService.GetKLineData(code, period, days)
+// GetKLineData 获取K线数据，adjust 为复权方式: none/qfq/hfq
+func (a *App) GetKLineData(code string, period string, days int, adjust string) []models.KLineData {
+	data, _ := a.marketService.GetKLineData(code, period, days, adjust)
 	return data
 }
 
+// GetIntradayHistory 分页获取跨交易日的分钟级K线（before 为空取最新一页）
+func (a *App) GetIntradayHistory(code, period, before string, limit int) services.IntradayPage {
+	page, err := a.marketService.GetIntradayHistory(code, period, before, limit)
+	if err != nil {
+		log.Warn("get intraday history error: %v", err)
+		return services.IntradayPage{Data: []models.KLineData{}}
+	}
+	return page
+}
+
 // GetOrderBook 获取盘口数据（真实五档）
 func (a *App) GetOrderBook(code string) models.OrderBook {
 	orderBook, _ := a.marketService.GetRealOrderBook(code)
@@ -415,35 +746,42 @@ func (a *App) SearchStocks(keyword string) []services.StockSearchResult {
 	return a.configService.SearchStocks(keyword, 20)
 }
 
-// getDefaultAIConfig 获取默认AI配置
-func (a *App) getDefaultAIConfig(config *models.AppConfig) *models.AIConfig {
-	for i := range config.AIConfigs {
-		if config.AIConfigs[i].ID == config.DefaultAIID {
-			return &config.AIConfigs[i]
-		}
-		if config.AIConfigs[i].IsDefault {
-			return &config.AIConfigs[i]
-		}
+// LookupStock 按代码查询股票名称/行业等基础信息
+func (a *App) LookupStock(symbol string) *services.StockBasicInfo {
+	info, ok := services.GetStockIndex().Lookup(symbol)
+	if !ok {
+		return nil
 	}
-	if len(config.AIConfigs) > 0 {
-		return &config.AIConfigs[0]
+	return &info
+}
+
+// applyAIRouting 将任务路由表应用到会议服务（未路由的任务传 nil，沿用会议 LLM）
+func applyAIRouting(meetingService *meeting.Service, config *models.AppConfig) {
+	routes := []struct {
+		task models.AITask
+		set  func(*models.AIConfig)
+	}{
+		{models.AITaskModerator, meetingService.SetModeratorAIConfig},
+		{models.AITaskSummary, meetingService.SetSummaryAIConfig},
+		{models.AITaskMemory, meetingService.SetMemoryAIConfig},
+		{models.AITaskExtraction, meetingService.SetExtractionAIConfig},
+	}
+	for _, r := range routes {
+		aiConfig := config.RoutedAIConfig(r.task)
+		r.set(aiConfig)
+		if aiConfig != nil {
+			log.Info("%s LLM: %s", r.task, aiConfig.ModelName)
+		}
 	}
-	return nil
 }
 
-// getAIConfigByID 根据ID获取AI配置，找不到则返回默认配置
+// getAIConfigByID 根据ID获取AI配置，找不到则返回专家默认配置
 func (a *App) getAIConfigByID(aiConfigID string) *models.AIConfig {
 	config := a.configService.GetConfig()
-	// 如果指定了ID，尝试查找
-	if aiConfigID != "" {
-		for i := range config.AIConfigs {
-			if config.AIConfigs[i].ID == aiConfigID {
-				return &config.AIConfigs[i]
-			}
-		}
+	if aiConfig := config.FindAIConfig(aiConfigID); aiConfig != nil {
+		return aiConfig
 	}
-	// 找不到则返回默认配置
-	return a.getDefaultAIConfig(config)
+	return config.ResolveAIConfig(models.AITaskExpert)
 }
 
 // ========== Session API ==========
@@ -473,6 +811,10 @@ func (a *App) ClearSessionMessages(stockCode string) string {
 	if err := a.sessionService.ClearMessages(stockCode); err != nil {
 		return err.Error()
 	}
+	// 放弃未回答的追问
+	a.meetingService.CancelClarification(stockCode)
+	a.replayStore.DeleteByStock(stockCode)
+	a.researchStore.DeleteByStock(stockCode)
 	// 同步清除该股票的记忆
 	if a.memoryManager != nil {
 		if err := a.memoryManager.DeleteMemory(stockCode); err != nil {
@@ -487,9 +829,372 @@ func (a *App) UpdateStockPosition(stockCode string, shares int64, costPrice floa
 	if a.sessionService == nil {
 		return "service not ready"
 	}
+	if a.configService.GetConfig().Broker.Enabled {
+		return "已接入券商，持仓以券商同步为准"
+	}
 	if err := a.sessionService.UpdatePosition(stockCode, shares, costPrice); err != nil {
 		return err.Error()
 	}
+	// 持仓变动与上次会议建议背离时温和提醒
+	if notice := a.sessionService.CheckAdviceDivergence(stockCode); notice != nil {
+		a.notifyDivergence(*notice)
+	}
+	return "success"
+}
+
+// adviceDivergenceCheckInterval 背离巡检的检查间隔，每个自然日只巡检一次
+const adviceDivergenceCheckInterval = time.Hour
+
+// adviceDivergenceLoop 启动时及之后每天巡检一次全部会话的持仓与建议背离（建议观察期满后补发提醒）
+// 不依赖早报调度，关闭早报或长期不开会时仍会提醒；数据未解锁时等解锁后再巡检
+func (a *App) adviceDivergenceLoop(ctx context.Context) {
+	ticker := time.NewTicker(adviceDivergenceCheckInterval)
+	defer ticker.Stop()
+	lastDate := ""
+	for {
+		if today := time.Now().Format("2006-01-02"); today != lastDate && !vault.Default().Locked() {
+			lastDate = today
+			a.checkAdviceDivergences()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAdviceDivergences 检查全部会话的持仓与建议背离
+func (a *App) checkAdviceDivergences() {
+	for _, notice := range a.sessionService.CheckAllAdviceDivergence() {
+		a.notifyDivergence(notice)
+	}
+}
+
+// notifyDivergence 推送持仓与会议建议背离提醒（应用内、系统通知与外部推送渠道）
+func (a *App) notifyDivergence(notice services.DivergenceNotice) {
+	log.Info("持仓与会议建议背离: %s", notice.StockCode)
+	a.eventBus.Emit("advice:divergence", notice)
+	a.notify(services.Notification{
+		Category: services.NotifyAlert,
+		Title:    fmt.Sprintf("%s 持仓与会议建议背离", notice.StockName),
+		Body:     notice.Message,
+		Key:      "advice:divergence:" + notice.StockCode,
+	})
+}
+
+// BrokerSyncResponse 券商持仓同步响应
+type BrokerSyncResponse struct {
+	Success   bool              `json:"success"`
+	Error     string            `json:"error,omitempty"`
+	Positions []broker.Position `json:"positions,omitempty"`
+	Cleared   []string          `json:"cleared,omitempty"` // 券商已无持仓、被清空的股票
+}
+
+// SyncBrokerPositions 从券商同步持仓（只读）：覆盖会话持仓，持有的股票加入自选股「持仓」分组
+func (a *App) SyncBrokerPositions() BrokerSyncResponse {
+	b, err := a.newBroker()
+	if err != nil {
+		return BrokerSyncResponse{Success: false, Error: err.Error()}
+	}
+	ctx, cancel := context.WithTimeout(a.ctx, 30*time.Second)
+	defer cancel()
+	positions, err := b.Positions(ctx)
+	if err != nil {
+		return BrokerSyncResponse{Success: false, Error: err.Error()}
+	}
+
+	held := make(map[string]models.StockPosition, len(positions))
+	names := make(map[string]string, len(positions))
+	codes := make([]string, 0, len(positions))
+	for _, p := range positions {
+		held[p.Symbol] = models.StockPosition{Shares: p.Shares, CostPrice: p.CostPrice}
+		names[p.Symbol] = p.Name
+		codes = append(codes, p.Symbol)
+	}
+	if len(codes) > 0 {
+		if stocks, err := a.marketService.GetStockRealTimeData(codes...); err == nil {
+			for _, st := range stocks {
+				if names[st.Symbol] == "" {
+					names[st.Symbol] = st.Name
+				}
+				if err := a.configService.AddToWatchlist(st); err == nil {
+					a.configService.AddToGroup("holding", st.Symbol)
+				}
+			}
+		}
+	}
+
+	cleared, err := a.sessionService.SyncPositions(held, names)
+	if err != nil {
+		return BrokerSyncResponse{Success: false, Error: err.Error()}
+	}
+	for i := range positions {
+		positions[i].Name = names[positions[i].Symbol]
+	}
+	log.Info("%s 持仓同步完成: %d 只持仓, %d 只清空", b.Name(), len(positions), len(cleared))
+	return BrokerSyncResponse{Success: true, Positions: positions, Cleared: cleared}
+}
+
+// BrokerOrdersResponse 券商当日委托响应
+type BrokerOrdersResponse struct {
+	Success bool           `json:"success"`
+	Error   string         `json:"error,omitempty"`
+	Orders  []broker.Order `json:"orders,omitempty"`
+}
+
+// GetBrokerOrders 查询券商当日委托（只读）
+func (a *App) GetBrokerOrders() BrokerOrdersResponse {
+	b, err := a.newBroker()
+	if err != nil {
+		return BrokerOrdersResponse{Success: false, Error: err.Error()}
+	}
+	ctx, cancel := context.WithTimeout(a.ctx, 30*time.Second)
+	defer cancel()
+	orders, err := b.Orders(ctx)
+	if err != nil {
+		return BrokerOrdersResponse{Success: false, Error: err.Error()}
+	}
+	return BrokerOrdersResponse{Success: true, Orders: orders}
+}
+
+// newBroker 按当前配置创建券商接口
+func (a *App) newBroker() (broker.Broker, error) {
+	cfg := a.configService.GetConfig().Broker
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("未启用券商接口")
+	}
+	return broker.New(cfg)
+}
+
+// TradePlanResponse 交易计划生成响应
+type TradePlanResponse struct {
+	Success bool              `json:"success"`
+	Error   string            `json:"error,omitempty"`
+	Plan    *models.TradePlan `json:"plan,omitempty"`
+}
+
+// CreateTradePlan 将最近一次会议结论转换为交易计划并开始监控
+// autoReview 为 true 时触发止损/目标价后自动召开复盘会议
+func (a *App) CreateTradePlan(stockCode string, autoReview bool) TradePlanResponse {
+	var price float64
+	if stocks, err := a.marketService.GetStockRealTimeData(stockCode); err == nil && len(stocks) > 0 {
+		price = stocks[0].Price
+	}
+	plan, err := a.sessionService.CreateTradePlan(stockCode, price, autoReview)
+	if err != nil {
+		return TradePlanResponse{Success: false, Error: err.Error()}
+	}
+	log.Info("交易计划已创建: %s, 止损 %.2f, 目标 %v", stockCode, plan.StopLoss, plan.Targets)
+	return TradePlanResponse{Success: true, Plan: plan}
+}
+
+// GetTradePlan 获取股票的交易计划
+func (a *App) GetTradePlan(stockCode string) *models.TradePlan {
+	session := a.sessionService.GetSession(stockCode)
+	if session == nil {
+		return nil
+	}
+	return session.TradePlan
+}
+
+// CancelTradePlan 取消交易计划监控
+func (a *App) CancelTradePlan(stockCode string) string {
+	if err := a.sessionService.CancelTradePlan(stockCode); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// onTradePlanAlert 交易计划触发：会议室插入提醒，按计划设置自动召开复盘会议
+func (a *App) onTradePlanAlert(alert services.TradePlanAlert) {
+	log.Info("交易计划触发: %s", alert.Message)
+	a.eventBus.Emit("tradeplan:alert", alert)
+	a.notify(services.Notification{
+		Category: services.NotifyAlert,
+		Title:    "交易计划触发",
+		Body:     alert.Message,
+		Key:      fmt.Sprintf("tradeplan:%s:%s:%.2f", alert.StockCode, alert.Kind, alert.Level),
+	})
+
+	msg := models.ChatMessage{
+		AgentID:   "moderator",
+		AgentName: "小韭菜",
+		Role:      "会议主持",
+		Content:   alert.Message,
+		MsgType:   "alert",
+	}
+	if err := a.sessionService.AddMessage(alert.StockCode, msg); err == nil {
+		a.eventBus.Emit("meeting:message:"+alert.StockCode, msg)
+	}
+
+	if alert.Review {
+		go a.sendMeetingMessage(MeetingMessageRequest{
+			StockCode: alert.StockCode,
+			Content:   services.TradePlanReviewQuery(alert),
+		}, meeting.PriorityAlert)
+	}
+}
+
+// ========== Briefing API ==========
+
+// BriefingResponse 盘前早报生成响应
+type BriefingResponse struct {
+	Success  bool             `json:"success"`
+	Error    string           `json:"error,omitempty"`
+	Briefing *models.Briefing `json:"briefing,omitempty"`
+}
+
+// BriefingReadyEvent 早报生成完成事件
+type BriefingReadyEvent struct {
+	StockCode string          `json:"stockCode"`
+	StockName string          `json:"stockName"`
+	Briefing  models.Briefing `json:"briefing"`
+}
+
+// GenerateBriefing 手动生成个股盘前早报（不召集专家）
+func (a *App) GenerateBriefing(stockCode string) BriefingResponse {
+	stocks, err := a.marketService.GetStockRealTimeData(stockCode)
+	if err != nil || len(stocks) == 0 {
+		return BriefingResponse{Success: false, Error: i18n.T("获取股票数据失败")}
+	}
+	briefing, err := a.generateBriefing(a.ctx, stocks[0], services.BriefingDateToday(), meeting.PriorityManual)
+	if err != nil {
+		return BriefingResponse{Success: false, Error: err.Error()}
+	}
+	return BriefingResponse{Success: true, Briefing: briefing}
+}
+
+// GetBriefing 获取股票最近一次盘前早报
+func (a *App) GetBriefing(stockCode string) *models.Briefing {
+	session := a.sessionService.GetSession(stockCode)
+	if session == nil {
+		return nil
+	}
+	return session.Briefing
+}
+
+// generateBriefings 为自选股逐只生成当日早报，已生成的跳过
+func (a *App) generateBriefings(ctx context.Context, date string) {
+	watchlist := a.configService.GetWatchlist()
+	var codes []string
+	for _, s := range watchlist {
+		if a.sessionService.BriefingDate(s.Symbol) != date {
+			codes = append(codes, s.Symbol)
+		}
+	}
+	if len(codes) == 0 {
+		return
+	}
+	stocks, err := a.marketService.GetStockRealTimeData(codes...)
+	if err != nil {
+		log.Warn("早报获取行情失败: %v", err)
+		return
+	}
+
+	log.Info("开始生成盘前早报: %d 只", len(stocks))
+	var names, details []string
+	for _, stock := range stocks {
+		if ctx.Err() != nil {
+			return
+		}
+		briefing, err := a.generateBriefing(ctx, stock, date, meeting.PriorityScheduled)
+		if err != nil {
+			log.Warn("早报生成失败 %s: %v", stock.Symbol, err)
+			continue
+		}
+		names = append(names, stock.Name)
+		details = append(details, fmt.Sprintf("▍%s\n%s", stock.Name, briefing.Content))
+	}
+	if len(names) > 0 {
+		detail := strings.Join(details, "\n\n")
+		if a.configService.GetConfig().TTS.AutoBriefing {
+			if speech := a.speakBriefings(ctx, date, detail); speech != nil {
+				detail += "\n\n语音版：" + speech.Path
+			}
+		}
+		a.notify(services.Notification{
+			Category: services.NotifyBriefing,
+			Title:    fmt.Sprintf("盘前早报已生成（%d 只）", len(names)),
+			Body:     strings.Join(names, "、"),
+			Detail:   detail,
+			Key:      "briefing:" + date,
+		})
+	}
+}
+
+// generateBriefing 生成单只股票早报，保存到会话并推送 briefing:ready 事件
+func (a *App) generateBriefing(ctx context.Context, stock models.Stock, date string, priority meeting.Priority) (*models.Briefing, error) {
+	aiConfig := a.configService.GetConfig().ResolveAIConfig(models.AITaskSummary)
+	position := a.sessionService.GetPosition(stock.Symbol)
+	content, err := a.meetingService.GenerateBriefing(ctx, aiConfig, stock, position, priority)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := a.sessionService.SaveBriefing(stock.Symbol, stock.Name, date, content)
+	if err != nil {
+		return nil, err
+	}
+	briefing := models.Briefing{Date: date, Content: content, CreatedAt: msg.Timestamp}
+	a.eventBus.Emit("meeting:message:"+stock.Symbol, msg)
+	a.eventBus.Emit("briefing:ready", BriefingReadyEvent{StockCode: stock.Symbol, StockName: stock.Name, Briefing: briefing})
+	return &briefing, nil
+}
+
+// ========== Paper Trading API ==========
+
+// PaperOrderResponse 模拟盘委托响应
+type PaperOrderResponse struct {
+	Success bool               `json:"success"`
+	Error   string             `json:"error,omitempty"`
+	Order   *models.PaperOrder `json:"order,omitempty"`
+}
+
+// GetPaperAccount 获取模拟盘账户（资金、持仓、委托、权益曲线）
+func (a *App) GetPaperAccount() models.PaperAccount {
+	return a.paperTrading.GetAccount()
+}
+
+// PlacePaperOrder 用户手动下单，直接报单撮合
+func (a *App) PlacePaperOrder(req services.PaperOrderRequest) PaperOrderResponse {
+	order, err := a.paperTrading.PlaceOrder(req, "user", false)
+	if err != nil {
+		return PaperOrderResponse{Success: false, Error: err.Error()}
+	}
+	return PaperOrderResponse{Success: true, Order: order}
+}
+
+// ApprovePaperOrder 确认专家提交的模拟盘委托
+func (a *App) ApprovePaperOrder(orderID string) PaperOrderResponse {
+	order, err := a.paperTrading.ApproveOrder(orderID)
+	if err != nil {
+		return PaperOrderResponse{Success: false, Error: err.Error()}
+	}
+	return PaperOrderResponse{Success: true, Order: order}
+}
+
+// RejectPaperOrder 拒绝专家提交的模拟盘委托
+func (a *App) RejectPaperOrder(orderID string) string {
+	if err := a.paperTrading.RejectOrder(orderID); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// CancelPaperOrder 撤销未成交的模拟盘委托
+func (a *App) CancelPaperOrder(orderID string) string {
+	if err := a.paperTrading.CancelOrder(orderID); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// ResetPaperAccount 重置模拟盘账户，initialCash 不大于0时使用默认资金
+func (a *App) ResetPaperAccount(initialCash float64) string {
+	if err := a.paperTrading.Reset(initialCash); err != nil {
+		return err.Error()
+	}
 	return "success"
 }
 
@@ -512,6 +1217,8 @@ func (a *App) AddAgentConfig(config models.AgentConfig) string {
 		Tools:       config.Tools,
 		MCPServers:  config.MCPServers,
 		Enabled:     config.Enabled,
+
+		UnifiedTools: config.UnifiedTools,
 	}
 	if err := a.strategyService.AddAgentToActiveStrategy(agent); err != nil {
 		return err.Error()
@@ -532,6 +1239,8 @@ func (a *App) UpdateAgentConfig(config models.AgentConfig) string {
 		Tools:       config.Tools,
 		MCPServers:  config.MCPServers,
 		Enabled:     config.Enabled,
+
+		UnifiedTools: config.UnifiedTools,
 	}
 	if err := a.strategyService.UpdateAgentInActiveStrategy(agent); err != nil {
 		return err.Error()
@@ -549,6 +1258,29 @@ func (a *App) DeleteAgentConfig(id string) string {
 	return "success"
 }
 
+// GetAgentPresets 获取专家预设库
+func (a *App) GetAgentPresets() []services.AgentPresetInfo {
+	return a.strategyService.GetAgentPresets()
+}
+
+// InstallAgentPresets 将专家预设添加到当前策略
+func (a *App) InstallAgentPresets(ids []string) string {
+	if _, err := a.strategyService.InstallAgentPresets(ids); err != nil {
+		return err.Error()
+	}
+	a.agentContainer.LoadAgents(a.strategyService.GetAllAgents())
+	return "success"
+}
+
+// UpgradeAgentPresets 将当前策略中的预设专家升级到最新版本，overwrite 为 true 时覆盖用户修改
+func (a *App) UpgradeAgentPresets(ids []string, overwrite bool) string {
+	if _, err := a.strategyService.UpgradeAgentPresets(ids, overwrite); err != nil {
+		return err.Error()
+	}
+	a.agentContainer.LoadAgents(a.strategyService.GetAllAgents())
+	return "success"
+}
+
 // ========== Strategy API ==========
 
 // GetStrategies 获取所有策略
@@ -569,7 +1301,7 @@ func (a *App) SetActiveStrategy(id string) string {
 	// 重新加载Agent容器
 	a.agentContainer.LoadAgents(a.strategyService.GetAllAgents())
 	// 通知前端策略已切换
-	runtime.EventsEmit(a.ctx, "strategy:changed", id)
+	a.eventBus.Emit("strategy:changed", id)
 	return "success"
 }
 
@@ -612,24 +1344,11 @@ type GenerateStrategyResponse struct {
 
 // GenerateStrategy AI生成策略
 func (a *App) GenerateStrategy(req GenerateStrategyRequest) GenerateStrategyResponse {
-	// 获取策略生成AI配置（优先使用 StrategyAIID，否则使用默认）
+	// 获取策略生成AI配置（按任务路由，否则使用默认）
 	config := a.configService.GetConfig()
-	var aiConfig *models.AIConfig
-	targetAIID := config.StrategyAIID
-	if targetAIID == "" {
-		targetAIID = config.DefaultAIID
-	}
-	for i := range config.AIConfigs {
-		if config.AIConfigs[i].ID == targetAIID {
-			aiConfig = &config.AIConfigs[i]
-			break
-		}
-	}
-	if aiConfig == nil && len(config.AIConfigs) > 0 {
-		aiConfig = &config.AIConfigs[0]
-	}
+	aiConfig := config.ResolveAIConfig(models.AITaskStrategy)
 	if aiConfig == nil {
-		return GenerateStrategyResponse{Success: false, Error: "未配置AI服务"}
+		return GenerateStrategyResponse{Success: false, Error: i18n.T("未配置AI服务")}
 	}
 
 	// 创建LLM
@@ -647,6 +1366,9 @@ func (a *App) GenerateStrategy(req GenerateStrategyRequest) GenerateStrategyResp
 
 	// 获取可用工具列表
 	for _, t := range a.toolRegistry.GetAllToolInfos() {
+		if !t.Enabled {
+			continue
+		}
 		input.Tools = append(input.Tools, services.ToolInfoForGen{
 			Name:        t.Name,
 			Description: t.Description,
@@ -706,24 +1428,10 @@ type EnhancePromptResponse struct {
 
 // EnhancePrompt 增强Agent提示词
 func (a *App) EnhancePrompt(req EnhancePromptRequest) EnhancePromptResponse {
-	// 获取策略生成AI配置（优先使用 StrategyAIID，否则使用默认）
-	config := a.configService.GetConfig()
-	var aiConfig *models.AIConfig
-	targetAIID := config.StrategyAIID
-	if targetAIID == "" {
-		targetAIID = config.DefaultAIID
-	}
-	for i := range config.AIConfigs {
-		if config.AIConfigs[i].ID == targetAIID {
-			aiConfig = &config.AIConfigs[i]
-			break
-		}
-	}
-	if aiConfig == nil && len(config.AIConfigs) > 0 {
-		aiConfig = &config.AIConfigs[0]
-	}
+	// 获取策略生成AI配置（按任务路由，否则使用默认）
+	aiConfig := a.configService.GetConfig().ResolveAIConfig(models.AITaskStrategy)
 	if aiConfig == nil {
-		return EnhancePromptResponse{Success: false, Error: "未配置AI服务"}
+		return EnhancePromptResponse{Success: false, Error: i18n.T("未配置AI服务")}
 	}
 
 	// 创建LLM
@@ -761,6 +1469,9 @@ type MeetingMessageRequest struct {
 	MentionIds   []string `json:"mentionIds"`
 	ReplyToId    string   `json:"replyToId"`
 	ReplyContent string   `json:"replyContent"`
+	CompareCode  string   `json:"compareCode,omitempty"` // 对比股票代码，为空时从问题中识别「A vs B」
+	Smart        bool     `json:"smart,omitempty"`       // 有 @ 时仍由小韭菜召开会议，被 @ 的专家必定参会
+	echoUser     bool     // 用户消息由后端推送（前端未本地展示，如会议模板）
 }
 
 // cancelMeetingInternal 内部取消会议方法
@@ -773,6 +1484,53 @@ func (a *App) cancelMeetingInternal(stockCode string) {
 	a.meetingCancelsMu.Unlock()
 }
 
+// beginMeeting 为已占用会议锁的会议创建可取消的 context（供 CancelMeeting 取消），会议结束后调用返回的函数清理
+// 会议锁保证同一股票同时只有一场会议，因此无需先取消旧会议
+func (a *App) beginMeeting(parent context.Context, stockCode string) (context.Context, func()) {
+	meetingCtx, cancel := context.WithCancel(parent)
+	a.meetingCancelsMu.Lock()
+	a.meetingCancels[stockCode] = cancel
+	a.meetingCancelsMu.Unlock()
+
+	return meetingCtx, func() {
+		a.meetingCancelsMu.Lock()
+		delete(a.meetingCancels, stockCode)
+		a.meetingCancelsMu.Unlock()
+	}
+}
+
+// claimMeeting 占用该股票的会议锁，已有会议进行中时推送 meeting:busy 事件并返回 false
+// 避免重复点击「开会」时同一股票跑两场会议、重复写入记忆
+func (a *App) claimMeeting(stockCode string) (context.Context, func(), bool) {
+	ctx, release, err := a.meetingService.Claim(a.ctx, stockCode)
+	if err != nil {
+		log.Warn("meeting already running for %s", stockCode)
+		a.eventBus.Emit("meeting:busy:"+stockCode, err.Error())
+		return nil, nil, false
+	}
+	return ctx, release, true
+}
+
+// IsMeetingRunning 该股票是否有会议正在进行（前端调用）
+func (a *App) IsMeetingRunning(stockCode string) bool {
+	return a.meetingService.IsMeetingRunning(stockCode)
+}
+
+// AttachMeeting 等待该股票进行中的会议结束并返回全部发言（前端调用）
+// 会议过程中的发言与进度仍通过 meeting:message/meeting:progress 事件推送
+func (a *App) AttachMeeting(stockCode string) []models.ChatMessage {
+	responses, err := a.meetingService.AttachMeeting(a.ctx, stockCode, nil, nil)
+	if err != nil {
+		log.Warn("AttachMeeting %s: %v", stockCode, err)
+		return []models.ChatMessage{}
+	}
+	messages := make([]models.ChatMessage, 0, len(responses))
+	for _, resp := range responses {
+		messages = append(messages, chatMessageFromResponse(resp))
+	}
+	return messages
+}
+
 // CancelMeeting 取消指定股票的会议（前端调用）
 func (a *App) CancelMeeting(stockCode string) bool {
 	a.cancelMeetingInternal(stockCode)
@@ -782,6 +1540,11 @@ func (a *App) CancelMeeting(stockCode string) bool {
 
 // SendMeetingMessage 发送会议室消息（@指定成员回复）
 func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage {
+	return a.sendMeetingMessage(req, meeting.PriorityManual)
+}
+
+// sendMeetingMessage 按指定排队优先级发送会议室消息
+func (a *App) sendMeetingMessage(req MeetingMessageRequest, priority meeting.Priority) []models.ChatMessage {
 	// 获取Session
 	session := a.sessionService.GetSession(req.StockCode)
 	if session == nil {
@@ -789,23 +1552,7 @@ func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage
 		return []models.ChatMessage{}
 	}
 
-	// 取消之前该股票的会议（如果有）
-	a.cancelMeetingInternal(req.StockCode)
-
-	// 创建可取消的 context
-	meetingCtx, cancel := context.WithCancel(a.ctx)
-	a.meetingCancelsMu.Lock()
-	a.meetingCancels[req.StockCode] = cancel
-	a.meetingCancelsMu.Unlock()
-
-	// 会议结束后清理
-	defer func() {
-		a.meetingCancelsMu.Lock()
-		delete(a.meetingCancels, req.StockCode)
-		a.meetingCancelsMu.Unlock()
-	}()
-
-	// 先保存用户消息
+	// 先保存用户消息：已有会议进行中时本次不开会，但消息仍保留在记录中
 	userMsg := models.ChatMessage{
 		AgentID:   "user",
 		AgentName: "老韭菜",
@@ -814,6 +1561,29 @@ func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage
 		Mentions:  req.MentionIds,
 	}
 	a.sessionService.AddMessage(req.StockCode, userMsg)
+	if req.echoUser {
+		a.eventBus.Emit("meeting:message:"+req.StockCode, userMsg)
+	}
+
+	var claimCtx context.Context
+	var release func()
+	if priority == meeting.PriorityAlert {
+		// 交易计划复盘不能丢弃，排队等进行中的会议结束
+		var err error
+		if claimCtx, release, err = a.meetingService.ClaimWait(a.ctx, req.StockCode); err != nil {
+			log.Warn("queued meeting for %s canceled: %v", req.StockCode, err)
+			return []models.ChatMessage{}
+		}
+	} else {
+		var ok bool
+		if claimCtx, release, ok = a.claimMeeting(req.StockCode); !ok {
+			return []models.ChatMessage{}
+		}
+	}
+	defer release()
+
+	meetingCtx, endMeeting := a.beginMeeting(claimCtx, req.StockCode)
+	defer endMeeting()
 
 	// 获取股票数据
 	stocks, _ := a.marketService.GetStockRealTimeData(req.StockCode)
@@ -824,7 +1594,7 @@ func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage
 
 	// 获取默认AI配置
 	config := a.configService.GetConfig()
-	aiConfig := a.getDefaultAIConfig(config)
+	aiConfig := config.ResolveAIConfig(models.AITaskExpert)
 	if aiConfig == nil {
 		log.Warn("no AI config found")
 		return []models.ChatMessage{}
@@ -833,45 +1603,89 @@ func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage
 	// 获取持仓信息
 	position := a.sessionService.GetPosition(req.StockCode)
 
-	// 判断是否为智能模式（无 @ 任何人）
-	if len(req.MentionIds) == 0 {
-		return a.runSmartMeeting(meetingCtx, req.StockCode, stock, req.Content, aiConfig, position)
+	// 判断是否为智能模式（无 @ 任何人，或 @ 专家后仍要求召开会议）
+	if len(req.MentionIds) == 0 || req.Smart {
+		compareStock := a.resolveCompareStock(req, stock.Symbol)
+		return a.runSmartMeeting(meetingCtx, req.StockCode, stock, compareStock, req.Content, req.MentionIds, aiConfig, position, priority)
 	}
 
 	// 原有逻辑：@ 指定专家
 	return a.runDirectMeeting(meetingCtx, req, stock, aiConfig, position)
 }
 
-// runSmartMeeting 智能会议模式
-func (a *App) runSmartMeeting(ctx context.Context, stockCode string, stock models.Stock, query string, aiConfig *models.AIConfig, position *models.StockPosition) []models.ChatMessage {
+// resolveCompareStock 解析双股对比的第二只股票，非对比问题返回 nil
+func (a *App) resolveCompareStock(req MeetingMessageRequest, current string) *models.Stock {
+	code := req.CompareCode
+	if code == "" {
+		left, right, ok := meeting.ParseCompareQuery(req.Content)
+		if !ok {
+			return nil
+		}
+		// 一侧通常是当前股票，取另一侧；两侧都不是当前股票时以右侧为准
+		leftCode, rightCode := matchStockKeyword(left, true), matchStockKeyword(right, false)
+		code = rightCode
+		if rightCode == "" || rightCode == current {
+			code = leftCode
+		}
+	}
+	if code == "" || code == current {
+		return nil
+	}
+	stocks, err := a.marketService.GetStockRealTimeData(code)
+	if err != nil || len(stocks) == 0 {
+		log.Warn("compare stock %s not found: %v", code, err)
+		return nil
+	}
+	return &stocks[0]
+}
+
+// matchStockKeyword 在本地股票索引中匹配关键词；关键词常夹带问句，
+// 左侧关键词逐步去掉前缀（「帮我看茅台」），右侧逐步去掉后缀（「五粮液怎么选」）
+func matchStockKeyword(keyword string, trimPrefix bool) string {
+	runes := []rune(keyword)
+	for n := len(runes); n >= 2; n-- {
+		kw := string(runes[:n])
+		if trimPrefix {
+			kw = string(runes[len(runes)-n:])
+		}
+		if matches := services.GetStockIndex().Search(kw, 1); len(matches) > 0 {
+			return matches[0].Symbol
+		}
+	}
+	return ""
+}
+
+// runSmartMeeting 智能会议模式，compareStock 不为空时进入双股对比模式
+// mentions 为老韭菜 @ 的专家，小韭菜选择专家时必定包含
+func (a *App) runSmartMeeting(ctx context.Context, stockCode string, stock models.Stock, compareStock *models.Stock, query string, mentions []string, aiConfig *models.AIConfig, position *models.StockPosition, priority meeting.Priority) []models.ChatMessage {
 	allAgents := a.strategyService.GetEnabledAgents()
 	chatReq := meeting.ChatRequest{
-		StockCode: stockCode,
-		Stock:     stock,
-		Query:     query,
-		AllAgents: allAgents,
-		Position:  position,
+		StockCode:    stockCode,
+		Stock:        stock,
+		Query:        query,
+		AllAgents:    allAgents,
+		Position:     position,
+		CompareStock: compareStock,
+		Mentions:     mentions,
+		// 上次建议未被执行时注入上下文，形成行为闭环
+		ExtraContext: a.sessionService.GetAdviceContext(stockCode),
+		Priority:     priority,
 	}
 
 	// 响应回调：每次发言完成后推送
 	respCallback := func(resp meeting.ChatResponse) {
-		msg := models.ChatMessage{
-			AgentID:     resp.AgentID,
-			AgentName:   resp.AgentName,
-			Role:        resp.Role,
-			Content:     resp.Content,
-			Round:       resp.Round,
-			MsgType:     resp.MsgType,
-			Error:       resp.Error,
-			MeetingMode: resp.MeetingMode,
-		}
+		msg := chatMessageFromResponse(resp)
 		a.sessionService.AddMessage(stockCode, msg)
-		runtime.EventsEmit(a.ctx, "meeting:message:"+stockCode, msg)
+		a.eventBus.Emit("meeting:message:"+stockCode, msg)
+		// 记录会议结论（对比结论不针对单只股票，不记录）
+		if resp.MsgType == "summary" && resp.MeetingMode != meeting.MeetingModeCompare {
+			a.recordSummary(stockCode, resp)
+		}
 	}
 
 	// 进度回调：工具调用、流式输出等细粒度事件
 	progressCallback := func(event meeting.ProgressEvent) {
-		runtime.EventsEmit(a.ctx, "meeting:progress:"+stockCode, event)
+		a.eventBus.Emit("meeting:progress:"+stockCode, event)
 	}
 
 	responses, err := a.meetingService.RunSmartMeetingWithCallback(ctx, aiConfig, chatReq, respCallback, progressCallback)
@@ -883,20 +1697,48 @@ func (a *App) runSmartMeeting(ctx context.Context, stockCode string, stock model
 	// 返回所有响应（前端可能已通过事件收到，这里作为备份）
 	var messages []models.ChatMessage
 	for _, resp := range responses {
-		messages = append(messages, models.ChatMessage{
-			AgentID:     resp.AgentID,
-			AgentName:   resp.AgentName,
-			Role:        resp.Role,
-			Content:     resp.Content,
-			Round:       resp.Round,
-			MsgType:     resp.MsgType,
-			Error:       resp.Error,
-			MeetingMode: resp.MeetingMode,
-		})
+		messages = append(messages, chatMessageFromResponse(resp))
+		if resp.MsgType == "summary" && resp.Error == "" {
+			body := []rune(resp.Content)
+			if len(body) > 60 {
+				body = append(body[:60], []rune("...")...)
+			}
+			a.notify(services.Notification{
+				Category: services.NotifyMeeting,
+				Title:    "会议结束：" + stock.Name,
+				Body:     string(body),
+				Detail:   resp.Content,
+				Key:      fmt.Sprintf("meeting:%s:%d", stockCode, time.Now().UnixNano()),
+			})
+		}
 	}
 	return messages
 }
 
+// TestPushChannel 向推送渠道发送一条测试消息
+func (a *App) TestPushChannel(channel models.PushChannelConfig) string {
+	if a.pushService == nil {
+		return "service not ready"
+	}
+	err := a.pushService.SendWithRetry(channel, services.Notification{
+		Category:  services.NotifyAlert,
+		Title:     "推送测试",
+		Body:      "韭菜盘推送渠道配置成功",
+		Timestamp: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// notify 发送桌面通知（通知服务在 startup 中初始化）
+func (a *App) notify(n services.Notification) {
+	if a.notifier != nil {
+		a.notifier.Notify(n)
+	}
+}
+
 // runDirectMeeting 直接 @ 指定专家模式（带事件推送）
 func (a *App) runDirectMeeting(ctx context.Context, req MeetingMessageRequest, stock models.Stock, aiConfig *models.AIConfig, position *models.StockPosition) []models.ChatMessage {
 	agentConfigs := a.strategyService.GetAgentsByIDs(req.MentionIds)
@@ -905,6 +1747,7 @@ func (a *App) runDirectMeeting(ctx context.Context, req MeetingMessageRequest, s
 	}
 
 	chatReq := meeting.ChatRequest{
+		StockCode:    req.StockCode,
 		Stock:        stock,
 		Agents:       agentConfigs,
 		Query:        req.Content,
@@ -912,7 +1755,12 @@ func (a *App) runDirectMeeting(ctx context.Context, req MeetingMessageRequest, s
 		Position:     position,
 	}
 
-	responses, err := a.meetingService.SendMessage(ctx, aiConfig, chatReq)
+	// 进度回调：多位专家并行发言，事件按 agentId 区分
+	progressCallback := func(event meeting.ProgressEvent) {
+		a.eventBus.Emit("meeting:progress:"+req.StockCode, event)
+	}
+
+	responses, err := a.meetingService.SendMessageWithCallback(ctx, aiConfig, chatReq, progressCallback)
 	if err != nil {
 		log.Error("runDirectMeeting error: %v", err)
 		return []models.ChatMessage{}
@@ -922,25 +1770,41 @@ func (a *App) runDirectMeeting(ctx context.Context, req MeetingMessageRequest, s
 	return a.convertSaveAndEmitResponses(req.StockCode, responses, req.ReplyToId)
 }
 
+// chatMessageFromResponse 将会议响应转换为聊天消息
+func chatMessageFromResponse(resp meeting.ChatResponse) models.ChatMessage {
+	return models.ChatMessage{
+		AgentID:       resp.AgentID,
+		AgentName:     resp.AgentName,
+		Role:          resp.Role,
+		Content:       resp.Content,
+		Round:         resp.Round,
+		MsgType:       resp.MsgType,
+		Error:         resp.Error,
+		ErrorCode:     string(resp.ErrorCode),
+		MeetingMode:   resp.MeetingMode,
+		AnsweredBy:    resp.AnsweredBy,
+		ToolCalls:     resp.ToolCalls,
+		MeetingID:     resp.MeetingID,
+		Variant:       resp.Variant,
+		DurationMs:    resp.DurationMs,
+		Retries:       resp.Retries,
+		ToolCallCount: resp.ToolCallCount,
+		ModelName:     resp.ModelName,
+		Report:        resp.Report,
+		Consensus:     resp.Consensus,
+	}
+}
+
 // convertSaveAndEmitResponses 转换响应、保存并推送事件（统一体验）
 func (a *App) convertSaveAndEmitResponses(stockCode string, responses []meeting.ChatResponse, replyTo string) []models.ChatMessage {
 	var messages []models.ChatMessage
 	for _, resp := range responses {
-		msg := models.ChatMessage{
-			AgentID:     resp.AgentID,
-			AgentName:   resp.AgentName,
-			Role:        resp.Role,
-			Content:     resp.Content,
-			ReplyTo:     replyTo,
-			Round:       resp.Round,
-			MsgType:     resp.MsgType,
-			Error:       resp.Error,
-			MeetingMode: resp.MeetingMode,
-		}
+		msg := chatMessageFromResponse(resp)
+		msg.ReplyTo = replyTo
 		// 保存单条消息
 		a.sessionService.AddMessage(stockCode, msg)
 		// 推送事件（与智能模式一致）
-		runtime.EventsEmit(a.ctx, "meeting:message:"+stockCode, msg)
+		a.eventBus.Emit("meeting:message:"+stockCode, msg)
 		messages = append(messages, msg)
 	}
 	return messages
@@ -957,17 +1821,17 @@ func (a *App) RetryAgent(stockCode string, agentId string, query string) models.
 
 	// 获取 AI 配置
 	config := a.configService.GetConfig()
-	aiConfig := a.getDefaultAIConfig(config)
+	aiConfig := config.ResolveAIConfig(models.AITaskExpert)
 	if aiConfig == nil {
 		log.Warn("RetryAgent: no AI config")
-		return models.ChatMessage{AgentID: agentId, Error: "未配置 AI 服务"}
+		return models.ChatMessage{AgentID: agentId, Error: i18n.T("未配置 AI 服务")}
 	}
 
 	// 获取专家配置
 	agents := a.strategyService.GetAgentsByIDs([]string{agentId})
 	if len(agents) == 0 {
 		log.Warn("RetryAgent: agent not found: %s", agentId)
-		return models.ChatMessage{AgentID: agentId, Error: "专家不存在"}
+		return models.ChatMessage{AgentID: agentId, Error: i18n.T("专家不存在")}
 	}
 	agentCfg := agents[0]
 
@@ -975,31 +1839,22 @@ func (a *App) RetryAgent(stockCode string, agentId string, query string) models.
 
 	// 进度回调
 	progressCallback := func(event meeting.ProgressEvent) {
-		runtime.EventsEmit(a.ctx, "meeting:progress:"+stockCode, event)
+		a.eventBus.Emit("meeting:progress:"+stockCode, event)
 	}
 
 	resp, err := a.meetingService.RetrySingleAgent(a.ctx, aiConfig, &agentCfg, &stock, query, progressCallback, position)
 
-	msg := models.ChatMessage{
-		AgentID:     resp.AgentID,
-		AgentName:   resp.AgentName,
-		Role:        resp.Role,
-		Content:     resp.Content,
-		Round:       resp.Round,
-		MsgType:     resp.MsgType,
-		Error:       resp.Error,
-		MeetingMode: resp.MeetingMode,
-	}
+	msg := chatMessageFromResponse(resp)
 
 	if err != nil {
 		log.Error("RetryAgent failed: %v", err)
-		runtime.EventsEmit(a.ctx, "meeting:message:"+stockCode, msg)
+		a.eventBus.Emit("meeting:message:"+stockCode, msg)
 		return msg
 	}
 
 	// 成功：保存并推送
 	a.sessionService.AddMessage(stockCode, msg)
-	runtime.EventsEmit(a.ctx, "meeting:message:"+stockCode, msg)
+	a.eventBus.Emit("meeting:message:"+stockCode, msg)
 	return msg
 }
 
@@ -1010,37 +1865,28 @@ func (a *App) RetryAgentAndContinue(stockCode string) []models.ChatMessage {
 		return []models.ChatMessage{}
 	}
 
-	// 创建可取消的 context
-	meetingCtx, cancel := context.WithCancel(a.ctx)
-	a.meetingCancelsMu.Lock()
-	a.meetingCancels[stockCode] = cancel
-	a.meetingCancelsMu.Unlock()
+	claimCtx, release, ok := a.claimMeeting(stockCode)
+	if !ok {
+		return []models.ChatMessage{}
+	}
+	defer release()
 
-	defer func() {
-		a.meetingCancelsMu.Lock()
-		delete(a.meetingCancels, stockCode)
-		a.meetingCancelsMu.Unlock()
-	}()
+	meetingCtx, endMeeting := a.beginMeeting(claimCtx, stockCode)
+	defer endMeeting()
 
 	// 响应回调
 	respCallback := func(resp meeting.ChatResponse) {
-		msg := models.ChatMessage{
-			AgentID:     resp.AgentID,
-			AgentName:   resp.AgentName,
-			Role:        resp.Role,
-			Content:     resp.Content,
-			Round:       resp.Round,
-			MsgType:     resp.MsgType,
-			Error:       resp.Error,
-			MeetingMode: resp.MeetingMode,
-		}
+		msg := chatMessageFromResponse(resp)
 		a.sessionService.AddMessage(stockCode, msg)
-		runtime.EventsEmit(a.ctx, "meeting:message:"+stockCode, msg)
+		a.eventBus.Emit("meeting:message:"+stockCode, msg)
+		if resp.MsgType == "summary" {
+			a.recordSummary(stockCode, resp)
+		}
 	}
 
 	// 进度回调
 	progressCallback := func(event meeting.ProgressEvent) {
-		runtime.EventsEmit(a.ctx, "meeting:progress:"+stockCode, event)
+		a.eventBus.Emit("meeting:progress:"+stockCode, event)
 	}
 
 	responses, err := a.meetingService.ContinueMeeting(meetingCtx, stockCode, respCallback, progressCallback)
@@ -1051,26 +1897,81 @@ func (a *App) RetryAgentAndContinue(stockCode string) []models.ChatMessage {
 
 	var messages []models.ChatMessage
 	for _, resp := range responses {
-		messages = append(messages, models.ChatMessage{
-			AgentID:     resp.AgentID,
-			AgentName:   resp.AgentName,
-			Role:        resp.Role,
-			Content:     resp.Content,
-			Round:       resp.Round,
-			MsgType:     resp.MsgType,
-			Error:       resp.Error,
-			MeetingMode: resp.MeetingMode,
-		})
+		messages = append(messages, chatMessageFromResponse(resp))
 	}
 	return messages
 }
 
+// recordSummary 记录会议结论：操作建议、多空共识分，以及各专家观点（到期后回测准确率）
+func (a *App) recordSummary(stockCode string, resp meeting.ChatResponse) {
+	if err := a.sessionService.RecordAdvice(stockCode, resp.Content); err != nil {
+		log.Warn("record advice error: %v", err)
+	}
+	if resp.Consensus == nil {
+		return
+	}
+	if err := a.sessionService.RecordConsensus(stockCode, resp.Consensus); err != nil {
+		log.Warn("record consensus error: %v", err)
+	}
+	if err := a.verdictTracker.Record(stockCode, resp.Consensus.Price, resp.Consensus.Votes); err != nil {
+		log.Warn("record verdicts error: %v", err)
+	}
+	go a.evaluateVerdicts()
+}
+
+// evaluateVerdicts 用日K线检验到期的专家观点
+func (a *App) evaluateVerdicts() {
+	for _, code := range a.verdictTracker.PendingStocks(time.Now()) {
+		klines, err := a.marketService.GetKLineData(code, "1d", 120, services.AdjustNone)
+		if err != nil {
+			log.Warn("evaluate verdicts %s: %v", code, err)
+			continue
+		}
+		if n, err := a.verdictTracker.Evaluate(code, klines); err != nil {
+			log.Warn("save verdicts error: %v", err)
+		} else if n > 0 {
+			log.Info("evaluated %d verdicts for %s", n, code)
+		}
+	}
+}
+
+// GetAgentAccuracy 获取各专家多空观点的历史准确率及其在共识分中的权重
+func (a *App) GetAgentAccuracy() []services.AgentAccuracy {
+	return a.verdictTracker.GetAccuracy()
+}
+
+// GetAgentToolUsage 统计当前策略各专家的工具调用次数与引用率，并列出建议移除的未使用工具
+func (a *App) GetAgentToolUsage() []meeting.AgentToolUsage {
+	var messages []models.ChatMessage
+	a.sessionService.ForEachMessage(func(_ string, msg models.ChatMessage) {
+		if msg.AgentID != "user" {
+			messages = append(messages, msg)
+		}
+	})
+	return meeting.AnalyzeToolUsage(messages, a.strategyService.GetAllAgents())
+}
+
 // CancelInterruptedMeeting 取消中断的会议（用户放弃重试）
 func (a *App) CancelInterruptedMeeting(stockCode string) bool {
 	a.meetingService.CancelInterruptedMeeting(stockCode)
 	return true
 }
 
+// GetMeetingReplays 获取股票的会议回放列表（最新的在前）
+func (a *App) GetMeetingReplays(stockCode string) []meeting.ReplaySummary {
+	return a.replayStore.List(stockCode)
+}
+
+// GetMeetingReplay 获取会议的完整事件流，用于逐步回放工具调用、流式输出与重试
+func (a *App) GetMeetingReplay(meetingID string) *meeting.MeetingReplay {
+	replay, err := a.replayStore.Get(meetingID)
+	if err != nil {
+		log.Warn("get meeting replay %s: %v", meetingID, err)
+		return nil
+	}
+	return replay
+}
+
 // ========== News API ==========
 
 // GetTelegraphList 获取快讯列表
@@ -1082,6 +1983,16 @@ func (a *App) GetTelegraphList() []services.Telegraph {
 	return telegraphs
 }
 
+// GetStockNews 获取个股相关新闻（多来源聚合）
+func (a *App) GetStockNews(code string, limit int) []services.StockNews {
+	news, err := a.stockNewsService.GetStockNews(code, limit)
+	if err != nil {
+		log.Error("获取个股新闻失败: %v", err)
+		return []services.StockNews{}
+	}
+	return news
+}
+
 // OpenURL 在浏览器中打开URL
 func (a *App) OpenURL(url string) {
 	runtime.BrowserOpenURL(a.ctx, url)
@@ -1094,6 +2005,17 @@ func (a *App) GetAvailableTools() []tools.ToolInfo {
 	return a.toolRegistry.GetAllToolInfos()
 }
 
+// SetToolEnabled 运行时启用或禁用工具，并持久化到配置
+func (a *App) SetToolEnabled(name string, enabled bool) string {
+	a.toolRegistry.SetToolEnabled(name, enabled)
+	config := a.configService.CloneConfig()
+	config.DisabledTools = a.toolRegistry.GetDisabledTools()
+	if err := a.configService.UpdateConfig(config); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
 // ========== MCP API ==========
 
 // GetMCPServers 获取 MCP 服务器配置列表
@@ -1107,39 +2029,26 @@ func (a *App) GetMCPServers() []models.MCPServerConfig {
 
 // AddMCPServer 添加 MCP 服务器配置
 func (a *App) AddMCPServer(server models.MCPServerConfig) string {
-	config := a.configService.GetConfig()
+	config := a.configService.CloneConfig()
 	config.MCPServers = append(config.MCPServers, server)
-	if err := a.configService.UpdateConfig(config); err != nil {
-		return err.Error()
-	}
-	// 重新加载 MCP 配置
-	if err := a.mcpManager.LoadConfigs(config.MCPServers); err != nil {
-		return err.Error()
-	}
-	return "success"
+	return a.UpdateConfig(config)
 }
 
 // UpdateMCPServer 更新 MCP 服务器配置
 func (a *App) UpdateMCPServer(server models.MCPServerConfig) string {
-	config := a.configService.GetConfig()
+	config := a.configService.CloneConfig()
 	for i, s := range config.MCPServers {
 		if s.ID == server.ID {
 			config.MCPServers[i] = server
 			break
 		}
 	}
-	if err := a.configService.UpdateConfig(config); err != nil {
-		return err.Error()
-	}
-	if err := a.mcpManager.LoadConfigs(config.MCPServers); err != nil {
-		return err.Error()
-	}
-	return "success"
+	return a.UpdateConfig(config)
 }
 
 // DeleteMCPServer 删除 MCP 服务器配置
 func (a *App) DeleteMCPServer(id string) string {
-	config := a.configService.GetConfig()
+	config := a.configService.CloneConfig()
 	var newServers []models.MCPServerConfig
 	for _, s := range config.MCPServers {
 		if s.ID != id {
@@ -1147,13 +2056,7 @@ func (a *App) DeleteMCPServer(id string) string {
 		}
 	}
 	config.MCPServers = newServers
-	if err := a.configService.UpdateConfig(config); err != nil {
-		return err.Error()
-	}
-	if err := a.mcpManager.LoadConfigs(config.MCPServers); err != nil {
-		return err.Error()
-	}
-	return "success"
+	return a.UpdateConfig(config)
 }
 
 // GetMCPStatus 获取所有 MCP 服务器连接状态
@@ -1167,7 +2070,7 @@ func (a *App) TestMCPConnection(serverID string) *mcp.ServerStatus {
 }
 
 // TestAIConnection 测试 AI 配置连通性
-// 连接成功后自动检测是否支持 system role，并持久化结果
+// 连接成功后自动探测模型能力（system role、流式、工具调用、JSON 模式），并持久化结果
 func (a *App) TestAIConnection(config models.AIConfig) string {
 	factory := adk.NewModelFactory()
 	ctx := context.Background()
@@ -1177,21 +2080,26 @@ func (a *App) TestAIConnection(config models.AIConfig) string {
 	}
 	log.Info("AI 连接测试成功 [%s]", config.Name)
 
-	// 连接成功后，探测是否支持 system role
-	noSystemRole := factory.DetectSystemRoleSupport(ctx, &config)
-	config.NoSystemRole = noSystemRole
+	// 连接成功后，探测模型能力；无法判断的项沿用已保存的结果（换了模型则重新开始）
+	config.Capabilities = models.ModelCapabilities{}
+	if appConfig := a.configService.GetConfig(); appConfig != nil {
+		if saved := appConfig.FindAIConfig(config.ID); saved != nil && saved.ModelName == config.ModelName {
+			config.Capabilities = saved.Capabilities
+		}
+	}
+	caps := factory.ProbeCapabilities(ctx, &config)
+	noSystemRole := !caps.SystemRole
 
 	// 持久化检测结果到配置
-	if appConfig := a.configService.GetConfig(); appConfig != nil {
-		for i := range appConfig.AIConfigs {
-			if appConfig.AIConfigs[i].ID == config.ID {
-				appConfig.AIConfigs[i].NoSystemRole = noSystemRole
-				if err := a.configService.UpdateConfig(appConfig); err != nil {
-					log.Warn("保存 NoSystemRole 检测结果失败: %v", err)
-				} else {
-					log.Info("模型 [%s] NoSystemRole=%v 已保存", config.Name, noSystemRole)
-				}
-				break
+	if appConfig := a.configService.CloneConfig(); appConfig != nil {
+		if target := appConfig.FindAIConfig(config.ID); target != nil {
+			target.NoSystemRole = noSystemRole
+			target.Capabilities = caps
+			target.DetectedAPIStyle = config.DetectedAPIStyle
+			if err := a.configService.UpdateConfig(appConfig); err != nil {
+				log.Warn("保存模型能力探测结果失败: %v", err)
+			} else {
+				log.Info("模型 [%s] 能力探测结果已保存 (NoSystemRole=%v)", config.Name, noSystemRole)
 			}
 		}
 	}
@@ -1199,6 +2107,88 @@ func (a *App) TestAIConnection(config models.AIConfig) string {
 	return "success"
 }
 
+// PromptPreviewRequest 专家提示词预览请求
+type PromptPreviewRequest struct {
+	StockCode string                    `json:"stockCode"`
+	AgentID   string                    `json:"agentId"`
+	Query     string                    `json:"query"`
+	History   []meeting.DiscussionEntry `json:"history"` // 假定已发言的专家（可选）
+}
+
+// PromptPreviewResponse 专家提示词预览响应
+type PromptPreviewResponse struct {
+	Preview *meeting.PromptPreview `json:"preview,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// PreviewAgentPrompt 预览专家在会议中收到的完整提示词（专家指令、画像、记忆、前面发言与工具说明），不调用模型
+func (a *App) PreviewAgentPrompt(req PromptPreviewRequest) PromptPreviewResponse {
+	config := a.configService.GetConfig()
+	aiConfig := config.ResolveAIConfig(models.AITaskExpert)
+	if aiConfig == nil {
+		return PromptPreviewResponse{Error: i18n.T("未配置 AI 服务")}
+	}
+	agents := a.strategyService.GetAgentsByIDs([]string{req.AgentID})
+	if len(agents) == 0 {
+		return PromptPreviewResponse{Error: i18n.T("专家不存在")}
+	}
+
+	stocks, _ := a.marketService.GetStockRealTimeData(req.StockCode)
+	stock := models.Stock{Symbol: req.StockCode}
+	if len(stocks) > 0 {
+		stock = stocks[0]
+	}
+
+	preview := a.meetingService.PreviewPrompt(aiConfig, meeting.PromptPreviewRequest{
+		Agent:        agents[0],
+		Stock:        stock,
+		Query:        req.Query,
+		Position:     a.sessionService.GetPosition(req.StockCode),
+		ExtraContext: a.sessionService.GetAdviceContext(req.StockCode),
+		History:      req.History,
+	})
+	return PromptPreviewResponse{Preview: preview}
+}
+
+// EstimatePromptTokens 估算提问发送给专家的 prompt token 数，输入框据此提示“预计 prompt 1.2k tokens”
+func (a *App) EstimatePromptTokens(stockCode, query string) int {
+	aiConfig := a.configService.GetConfig().ResolveAIConfig(models.AITaskExpert)
+	return a.meetingService.EstimatePromptTokens(aiConfig, models.Stock{Symbol: stockCode}, query)
+}
+
+// GetProviderPresets 获取内置服务商预设（DeepSeek、通义千问、智谱、Moonshot 等）
+func (a *App) GetProviderPresets() []models.ProviderPreset {
+	return models.GetProviderPresets()
+}
+
+// ListModelsResponse 模型列表查询响应
+type ListModelsResponse struct {
+	Models []string `json:"models"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// ListAIModels 查询 AI 配置可用的模型列表
+// 接口不支持在线查询时，返回匹配服务商预设中的已知模型
+func (a *App) ListAIModels(config models.AIConfig) ListModelsResponse {
+	preset, hasPreset := models.FindProviderPresetByBaseURL(config.BaseURL)
+	if !hasPreset || preset.ListModels {
+		names, err := adk.NewModelFactory().ListModels(context.Background(), &config)
+		if err == nil {
+			return ListModelsResponse{Models: names}
+		}
+		log.Warn("查询模型列表失败 [%s]: %v", config.Name, err)
+		if !hasPreset {
+			return ListModelsResponse{Models: []string{}, Error: err.Error()}
+		}
+	}
+
+	names := make([]string, 0, len(preset.Models))
+	for _, m := range preset.Models {
+		names = append(names, m.Name)
+	}
+	return ListModelsResponse{Models: names}
+}
+
 // GetMCPServerTools 获取指定 MCP 服务器的工具列表
 func (a *App) GetMCPServerTools(serverID string) []mcp.ToolInfo {
 	tools, err := a.mcpManager.GetServerTools(serverID)
@@ -1235,7 +2225,7 @@ func (a *App) GetHotTrendPlatforms() []hottrend.PlatformInfo {
 // GetHotTrend 获取单个平台的热点数据
 func (a *App) GetHotTrend(platform string) hottrend.HotTrendResult {
 	if a.hotTrendService == nil {
-		return hottrend.HotTrendResult{Platform: platform, Error: "服务未初始化"}
+		return hottrend.HotTrendResult{Platform: platform, Error: i18n.T("服务未初始化")}
 	}
 	return a.hotTrendService.GetHotTrend(platform)
 }
@@ -1253,7 +2243,7 @@ func (a *App) GetAllHotTrends() []hottrend.HotTrendResult {
 // CheckForUpdate 检查更新
 func (a *App) CheckForUpdate() services.UpdateInfo {
 	if a.updateService == nil {
-		return services.UpdateInfo{Error: "更新服务未初始化"}
+		return services.UpdateInfo{Error: i18n.T("更新服务未初始化")}
 	}
 	return a.updateService.CheckForUpdate()
 }
@@ -1335,6 +2325,29 @@ func (a *App) GetLongHuBangDetail(code, tradeDate string) []models.LongHuBangDet
 	return details
 }
 
+// GetMarketBreadth 获取涨跌家数与行业板块排行
+func (a *App) GetMarketBreadth() *models.MarketBreadth {
+	breadth, err := a.marketService.GetMarketBreadth()
+	if err != nil {
+		log.Error("获取市场宽度失败: %v", err)
+		return nil
+	}
+	return breadth
+}
+
+// GetLimitBoard 获取涨停/跌停/炸板池（date 为空取当日）
+func (a *App) GetLimitBoard(date string) *models.LimitBoard {
+	if a.limitBoardService == nil {
+		return nil
+	}
+	board, err := a.limitBoardService.GetLimitBoard(date)
+	if err != nil {
+		log.Error("获取涨跌停池失败: %v", err)
+		return nil
+	}
+	return board
+}
+
 // NotifyFrontendReady 前端通知已准备好，开始推送数据
 func (a *App) NotifyFrontendReady() {
 	if a.marketPusher != nil {
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/paths"
+	"github.com/run-bigpig/jcp/internal/services"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// BackupResponse 备份导出/恢复结果
+type BackupResponse struct {
+	Success   bool   `json:"success"`
+	Cancelled bool   `json:"cancelled,omitempty"` // 用户取消了文件选择
+	Path      string `json:"path,omitempty"`
+	Files     int    `json:"files"`
+	Error     string `json:"error,omitempty"`
+}
+
+// backupManifest 当前应用与工作区的备份清单信息
+func backupManifest() services.BackupManifest {
+	return services.BackupManifest{AppVersion: Version, Profile: paths.GetProfile()}
+}
+
+// ExportBackup 将配置、会话、记忆、自选股与模拟盘导出为备份文件
+func (a *App) ExportBackup() BackupResponse {
+	target, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出备份",
+		DefaultFilename: fmt.Sprintf("jcp-backup-%s-%s.zip", paths.GetProfile(), time.Now().Format("20060102")),
+		Filters:         []runtime.FileFilter{{DisplayName: "备份文件 (*.zip)", Pattern: "*.zip"}},
+	})
+	if err != nil {
+		return BackupResponse{Error: err.Error()}
+	}
+	if target == "" {
+		return BackupResponse{Cancelled: true}
+	}
+	manifest, err := services.ExportBackupFile(paths.GetDataDir(), target, backupManifest())
+	if err != nil {
+		log.Error("导出备份失败: %v", err)
+		return BackupResponse{Error: err.Error()}
+	}
+	log.Info("已导出备份: %s (%d 个文件)", target, manifest.Files)
+	return BackupResponse{Success: true, Path: target, Files: manifest.Files}
+}
+
+// ImportBackup 从备份文件恢复数据，当前数据先另存到数据目录的 backups/ 下，恢复后自动重启
+func (a *App) ImportBackup() BackupResponse {
+	source, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "从备份恢复",
+		Filters: []runtime.FileFilter{{DisplayName: "备份文件 (*.zip)", Pattern: "*.zip"}},
+	})
+	if err != nil {
+		return BackupResponse{Error: err.Error()}
+	}
+	if source == "" {
+		return BackupResponse{Cancelled: true}
+	}
+	manifest, err := services.ImportBackup(paths.GetDataDir(), source, backupManifest())
+	if err != nil {
+		log.Error("恢复备份失败: %v", err)
+		return BackupResponse{Error: err.Error()}
+	}
+	log.Info("已从备份恢复: %s (版本 %s, %d 个文件)", source, manifest.AppVersion, manifest.Files)
+
+	// 各服务已缓存旧数据，重启后重新加载，避免旧缓存覆盖恢复的文件
+	if result := a.RestartApp(); result != "success" {
+		return BackupResponse{Success: true, Path: source, Files: manifest.Files, Error: "恢复完成，请手动重启应用: " + result}
+	}
+	return BackupResponse{Success: true, Path: source, Files: manifest.Files}
+}
@@ -0,0 +1,259 @@
+// Command jcpcli 在终端中召开多专家会议，适合脚本化的盘后分析
+//
+// 用法：
+//
+//	jcpcli -code sh600519 -q "明天适合加仓吗？" -agents 价值投资,技术分析 -o report.md
+//
+// 读取桌面端同一数据目录下的 AI 配置、专家与记忆；进度输出到 stderr，发言输出到 stdout。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/meeting"
+	"github.com/run-bigpig/jcp/internal/memory"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/pkg/paths"
+	"github.com/run-bigpig/jcp/internal/pkg/vault"
+	"github.com/run-bigpig/jcp/internal/services"
+	"github.com/run-bigpig/jcp/pkg/market"
+	"github.com/run-bigpig/jcp/pkg/tools"
+)
+
+// defaultQuery 未指定问题时的默认议题
+const defaultQuery = "请结合最新行情、资讯与资金面全面分析这只股票，并给出操作建议"
+
+// options 命令行参数
+type options struct {
+	code       string
+	query      string
+	agents     string
+	compare    string
+	aiID       string
+	dataDir    string
+	profile    string
+	output     string
+	timeout    time.Duration
+	quiet      bool
+	verbose    bool
+	noMemory   bool
+	showPrompt bool
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.code, "code", "", "股票代码，如 sh600519（必填）")
+	flag.StringVar(&opts.query, "q", defaultQuery, "会议议题")
+	flag.StringVar(&opts.agents, "agents", "", "参会专家 ID 或名称，逗号分隔（默认全部已启用专家）")
+	flag.StringVar(&opts.compare, "compare", "", "对比股票代码，设置后进入双股对比模式")
+	flag.StringVar(&opts.aiID, "ai", "", "专家使用的 AI 配置 ID（默认按任务路由）")
+	flag.StringVar(&opts.dataDir, "data", "", "数据目录（默认与桌面端相同）")
+	flag.StringVar(&opts.profile, "profile", "", "工作区名称（默认读取 JCP_PROFILE 或桌面端上次选择的工作区）")
+	flag.StringVar(&opts.output, "o", "", "导出文件，.json 导出全部发言，其他扩展名导出 Markdown")
+	flag.DurationVar(&opts.timeout, "timeout", 15*time.Minute, "会议超时")
+	flag.BoolVar(&opts.quiet, "quiet", false, "只输出最终总结")
+	flag.BoolVar(&opts.verbose, "v", false, "输出运行日志")
+	flag.BoolVar(&opts.noMemory, "no-memory", false, "不读写会议记忆")
+	flag.BoolVar(&opts.showPrompt, "show-prompt", false, "只输出各专家的完整提示词，不召开会议")
+	flag.Parse()
+
+	if opts.code == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(opts); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// run 初始化会议服务并召开会议
+func run(opts options) error {
+	dataDir := opts.dataDir
+	if dataDir == "" {
+		if err := paths.SetProfile(paths.ResolveProfile(opts.profile)); err != nil {
+			return err
+		}
+		dataDir = paths.GetDataDir()
+	}
+	if !opts.verbose {
+		logger.SetConsoleOutput(false)
+	}
+	if err := logger.InitFileLogger(filepath.Join(dataDir, "logs")); err == nil {
+		defer logger.Close()
+	}
+
+	configService, err := services.NewConfigService(dataDir)
+	if err != nil {
+		return err
+	}
+	config := configService.GetConfig()
+	i18n.SetLanguage(config.Language)
+
+	// 会话与记忆已加密时通过 JCP_PASSPHRASE 解锁
+	if v, err := vault.Init(dataDir); err != nil {
+		return err
+	} else if v.Locked() {
+		if err := v.Unlock(os.Getenv("JCP_PASSPHRASE")); err != nil {
+			return fmt.Errorf("数据已加密，请通过 JCP_PASSPHRASE 环境变量提供正确的密码: %w", err)
+		}
+	}
+
+	aiConfig := config.ResolveAIConfig(models.AITaskExpert)
+	if opts.aiID != "" {
+		aiConfig = config.FindAIConfig(opts.aiID)
+	}
+	if aiConfig == nil {
+		return errors.New("未找到可用的 AI 配置，请先在桌面端设置")
+	}
+
+	agents, err := selectAgents(services.NewStrategyService(dataDir).GetEnabledAgents(), opts.agents)
+	if err != nil {
+		return err
+	}
+
+	marketClient := market.New()
+	registry, err := tools.New(tools.Options{DataDir: dataDir, Market: marketClient})
+	if err != nil {
+		return err
+	}
+	svc := newMeetingService(registry, config, dataDir, !opts.noMemory)
+
+	req, err := buildRequest(marketClient, opts, agents)
+	if err != nil {
+		return err
+	}
+	req.Position = services.NewSessionService(dataDir).GetPosition(opts.code)
+
+	if opts.showPrompt {
+		for _, agent := range agents {
+			printPromptPreview(os.Stdout, svc.PreviewPrompt(aiConfig, meeting.PromptPreviewRequest{
+				Agent:    agent,
+				Stock:    req.Stock,
+				Query:    req.Query,
+				Position: req.Position,
+			}))
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	started := time.Now()
+	printer := newPrinter(os.Stdout, os.Stderr, opts.quiet)
+	responses, err := svc.RunSmartMeetingWithCallback(ctx, aiConfig, req, printer.response, printer.progress)
+	if err != nil {
+		return err
+	}
+
+	report := newReport(req, responses, started)
+	if opts.quiet {
+		fmt.Fprintln(os.Stdout, report.Summary)
+	}
+	if opts.output != "" {
+		if err := report.WriteFile(opts.output); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "已导出:", opts.output)
+	}
+	if report.Summary == "" {
+		return errors.New("会议未产生总结")
+	}
+	return nil
+}
+
+// newMeetingService 按桌面端配置创建会议服务（预算、用户画像、AI 路由、记忆）
+func newMeetingService(registry *tools.Registry, config *models.AppConfig, dataDir string, useMemory bool) *meeting.Service {
+	svc := meeting.NewServiceFull(registry, nil)
+	svc.SetMeetingBudget(config.MeetingBudget)
+	svc.SetUserProfile(config.UserProfile)
+	svc.SetContextBudget(config.ContextBudget)
+	svc.SetModeratorAIConfig(config.RoutedAIConfig(models.AITaskModerator))
+	svc.SetSummaryAIConfig(config.RoutedAIConfig(models.AITaskSummary))
+	svc.SetMemoryAIConfig(config.RoutedAIConfig(models.AITaskMemory))
+	svc.SetExtractionAIConfig(config.RoutedAIConfig(models.AITaskExtraction))
+
+	if useMemory && config.Memory.Enabled {
+		mem := memory.NewManagerWithConfig(dataDir, memory.Config{
+			MaxRecentRounds:     config.Memory.MaxRecentRounds,
+			MaxKeyFacts:         config.Memory.MaxKeyFacts,
+			MaxSummaryLength:    config.Memory.MaxSummaryLength,
+			CompressThreshold:   config.Memory.CompressThreshold,
+			ConsolidateInterval: config.Memory.ConsolidateInterval,
+		})
+		mem.SetSectorResolver(services.GetStockIndex().Industry)
+		svc.SetMemoryManager(mem)
+	}
+	return svc
+}
+
+// buildRequest 拉取行情并构建会议请求
+func buildRequest(marketClient *market.Client, opts options, agents []models.AgentConfig) (meeting.ChatRequest, error) {
+	stock, err := marketClient.Quote(opts.code)
+	if err != nil {
+		return meeting.ChatRequest{}, err
+	}
+	if stock == nil {
+		return meeting.ChatRequest{}, fmt.Errorf("未获取到股票行情: %s", opts.code)
+	}
+	req := meeting.ChatRequest{
+		StockCode: stock.Symbol,
+		Stock:     *stock,
+		Query:     opts.query,
+		AllAgents: agents,
+	}
+	if opts.compare != "" {
+		compare, err := marketClient.Quote(opts.compare)
+		if err != nil {
+			return meeting.ChatRequest{}, err
+		}
+		if compare == nil {
+			return meeting.ChatRequest{}, fmt.Errorf("未获取到股票行情: %s", opts.compare)
+		}
+		req.CompareStock = compare
+	}
+	return req, nil
+}
+
+// selectAgents 按 ID 或名称筛选专家，selection 为空时返回全部
+func selectAgents(all []models.AgentConfig, selection string) ([]models.AgentConfig, error) {
+	if len(all) == 0 {
+		return nil, errors.New("没有已启用的专家")
+	}
+	if strings.TrimSpace(selection) == "" {
+		return all, nil
+	}
+	var result []models.AgentConfig
+	for _, key := range strings.Split(selection, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		found := false
+		for _, a := range all {
+			if a.ID == key || a.Name == key {
+				result = append(result, a)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("未找到专家: %s", key)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/meeting"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// printer 终端输出：进度写 stderr，完整发言写 stdout
+type printer struct {
+	out, progressOut io.Writer
+	quiet            bool
+	mu               sync.Mutex
+}
+
+func newPrinter(out, progressOut io.Writer, quiet bool) *printer {
+	return &printer{out: out, progressOut: progressOut, quiet: quiet}
+}
+
+// progress 输出发言开始、工具调用、模型降级等关键进度
+func (p *printer) progress(e meeting.ProgressEvent) {
+	var line string
+	switch e.Type {
+	case "agent_start":
+		line = fmt.Sprintf("▶ %s %s", e.AgentName, e.Detail)
+	case "tool_call":
+		line = fmt.Sprintf("  · %s 调用 %s", e.AgentName, e.Detail)
+	case "agent_invited":
+		line = fmt.Sprintf("+ 小韭菜邀请 %s：%s", e.AgentName, e.Detail)
+	case "failover":
+		line = fmt.Sprintf("  ! %s 切换模型: %s", e.AgentName, e.Detail)
+	case "budget_exceeded":
+		line = fmt.Sprintf("  ! 超出会议预算: %s", e.Detail)
+	default:
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.progressOut, line)
+}
+
+// response 输出一条完整发言
+func (p *printer) response(resp meeting.ChatResponse) {
+	if p.quiet {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if resp.Error != "" {
+		fmt.Fprintf(p.out, "\n## %s（失败）\n\n%s\n", resp.AgentName, resp.Error)
+		return
+	}
+	fmt.Fprintf(p.out, "\n## %s%s\n\n%s\n", resp.AgentName, roleSuffix(resp.Role), resp.Content)
+}
+
+// printPromptPreview 输出专家的完整提示词（-show-prompt）
+func printPromptPreview(w io.Writer, preview *meeting.PromptPreview) {
+	fmt.Fprintf(w, "\n===== %s · %s（%d 字）=====\n", preview.AgentName, preview.ModelName, preview.Length)
+	if len(preview.Tools) > 0 || len(preview.MCPServers) > 0 {
+		fmt.Fprintf(w, "工具: %s  MCP: %s\n", strings.Join(preview.Tools, ","), strings.Join(preview.MCPServers, ","))
+		if !preview.ToolsEnabled {
+			fmt.Fprintln(w, "（模型不支持工具调用，运行时将忽略工具配置）")
+		}
+	}
+	fmt.Fprintf(w, "\n[系统指令]\n%s\n\n[用户消息]\n%s\n", preview.Instruction, preview.UserMessage)
+}
+
+// report 会议导出内容
+type report struct {
+	StockCode   string                 `json:"stockCode"`
+	StockName   string                 `json:"stockName"`
+	CompareCode string                 `json:"compareCode,omitempty"`
+	Query       string                 `json:"query"`
+	StartedAt   time.Time              `json:"startedAt"`
+	Duration    string                 `json:"duration"`
+	Summary     string                 `json:"summary"`
+	Report      *models.SummaryReport  `json:"report,omitempty"` // 结构化总结，小韭菜未按分节输出时为空
+	Responses   []meeting.ChatResponse `json:"responses"`
+}
+
+func newReport(req meeting.ChatRequest, responses []meeting.ChatResponse, started time.Time) report {
+	r := report{
+		StockCode: req.StockCode,
+		StockName: req.Stock.Name,
+		Query:     req.Query,
+		StartedAt: started,
+		Duration:  time.Since(started).Round(time.Second).String(),
+		Responses: responses,
+	}
+	if req.CompareStock != nil {
+		r.CompareCode = req.CompareStock.Symbol
+	}
+	for _, resp := range responses {
+		if resp.MsgType == "summary" && resp.Error == "" {
+			r.Summary = resp.Content
+			r.Report = resp.Report
+		}
+	}
+	return r
+}
+
+// WriteFile 按扩展名导出：.json 为完整 JSON，其他为 Markdown
+func (r report) WriteFile(path string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var err error
+		if data, err = json.MarshalIndent(r, "", "  "); err != nil {
+			return err
+		}
+	} else {
+		data = []byte(r.Markdown())
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Markdown 渲染为 Markdown：总结在前（有结构化总结时按分节输出），各专家发言附后
+func (r report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s（%s）会议纪要\n\n", r.StockName, r.StockCode)
+	fmt.Fprintf(&b, "- 议题：%s\n", r.Query)
+	if r.CompareCode != "" {
+		fmt.Fprintf(&b, "- 对比：%s\n", r.CompareCode)
+	}
+	fmt.Fprintf(&b, "- 时间：%s（用时 %s）\n\n", r.StartedAt.Format("2006-01-02 15:04"), r.Duration)
+	b.WriteString("## 会议总结\n\n")
+	if r.Report != nil {
+		for i, sec := range r.Report.Sections() {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "### %s\n\n%s\n", sec.Title, sec.Content)
+		}
+	} else if r.Summary == "" {
+		b.WriteString("（未产生总结）\n")
+	} else {
+		b.WriteString(r.Summary + "\n")
+	}
+	for _, resp := range r.Responses {
+		if resp.MsgType == "summary" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s%s\n\n", resp.AgentName, roleSuffix(resp.Role))
+		if resp.Error != "" {
+			b.WriteString("发言失败：" + resp.Error + "\n")
+		} else {
+			b.WriteString(resp.Content + "\n")
+		}
+	}
+	return b.String()
+}
+
+// roleSuffix 角色后缀，如「（技术分析师）」
+func roleSuffix(role string) string {
+	if role == "" {
+		return ""
+	}
+	return "（" + role + "）"
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/meeting"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestReport(t *testing.T) {
+	req := meeting.ChatRequest{StockCode: "sh600519", Stock: models.Stock{Symbol: "sh600519", Name: "贵州茅台"}, Query: "能加仓吗"}
+	responses := []meeting.ChatResponse{
+		{AgentID: "tech", AgentName: "技术派", Role: "技术分析师", Content: "均线多头", MsgType: "opinion"},
+		{AgentID: "value", AgentName: "价值派", Error: "timeout", MsgType: "opinion"},
+		{AgentID: "moderator", AgentName: "小韭菜", Content: "可分批加仓", MsgType: "summary"},
+	}
+	r := newReport(req, responses, time.Date(2025, 6, 3, 20, 0, 0, 0, time.Local))
+	if r.Summary != "可分批加仓" {
+		t.Fatalf("summary: %q", r.Summary)
+	}
+
+	md := r.Markdown()
+	for _, want := range []string{"# 贵州茅台（sh600519）会议纪要", "## 会议总结\n\n可分批加仓", "## 技术派（技术分析师）\n\n均线多头", "发言失败：timeout"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Index(md, "可分批加仓") > strings.Index(md, "均线多头") {
+		t.Error("summary should come first")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nightly", "sh600519.json")
+	if err := r.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	var decoded report
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Responses) != 3 || decoded.Summary != r.Summary {
+		t.Errorf("json export: %v %s", err, data)
+	}
+}
+
+func TestReportSections(t *testing.T) {
+	req := meeting.ChatRequest{StockCode: "sh600519", Stock: models.Stock{Symbol: "sh600519", Name: "贵州茅台"}, Query: "能加仓吗"}
+	summary := "### 结论\n可分批加仓\n### 风险提示\n消费复苏不及预期"
+	responses := []meeting.ChatResponse{
+		{AgentID: "moderator", AgentName: "小韭菜", Content: summary, MsgType: "summary", Report: models.ParseSummaryReport(summary)},
+	}
+	md := newReport(req, responses, time.Now()).Markdown()
+	if !strings.Contains(md, "## 会议总结\n\n### 结论\n\n可分批加仓\n\n### 风险提示\n\n消费复苏不及预期\n") {
+		t.Errorf("markdown sections:\n%s", md)
+	}
+}
+
+func TestSelectAgents(t *testing.T) {
+	all := []models.AgentConfig{{ID: "tech", Name: "技术派"}, {ID: "value", Name: "价值派"}}
+	got, err := selectAgents(all, "value, 技术派")
+	if err != nil || len(got) != 2 || got[0].ID != "value" || got[1].ID != "tech" {
+		t.Errorf("selectAgents: %v %+v", err, got)
+	}
+	if got, _ := selectAgents(all, ""); len(got) != 2 {
+		t.Errorf("empty selection should return all: %+v", got)
+	}
+	if _, err := selectAgents(all, "macro"); err == nil {
+		t.Error("unknown agent should fail")
+	}
+}
+
+func TestPrintPromptPreview(t *testing.T) {
+	var b strings.Builder
+	printPromptPreview(&b, &meeting.PromptPreview{
+		AgentName: "技术派", ModelName: "deepseek-chat", Instruction: "你是一位技术分析师", Length: 9,
+		UserMessage: "能加仓吗", Tools: []string{"get_kline_data"},
+	})
+	out := b.String()
+	for _, want := range []string{"技术派 · deepseek-chat（9 字）", "工具: get_kline_data", "模型不支持工具调用", "[系统指令]\n你是一位技术分析师", "[用户消息]\n能加仓吗"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
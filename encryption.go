@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+
+	"github.com/run-bigpig/jcp/internal/pkg/paths"
+	"github.com/run-bigpig/jcp/internal/pkg/vault"
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+// initVault 加载数据加密设置，设置了 JCP_PASSPHRASE 时直接解锁，否则等待前端输入密码
+func initVault(dataDir string) {
+	v, err := vault.Init(dataDir)
+	if err != nil {
+		log.Error("加载数据加密设置失败: %v", err)
+		return
+	}
+	if pass := os.Getenv("JCP_PASSPHRASE"); pass != "" && v.Locked() {
+		if err := v.Unlock(pass); err != nil {
+			log.Warn("JCP_PASSPHRASE 解锁失败: %v", err)
+		}
+	}
+}
+
+// EncryptionStatus 数据加密状态
+type EncryptionStatus struct {
+	Enabled bool `json:"enabled"`
+	Locked  bool `json:"locked"` // 已加密但尚未输入密码
+}
+
+// GetEncryptionStatus 获取数据加密状态
+func (a *App) GetEncryptionStatus() EncryptionStatus {
+	v := vault.Default()
+	return EncryptionStatus{Enabled: v.Enabled(), Locked: v.Locked()}
+}
+
+// UnlockData 输入密码解锁加密数据，解锁后重新加载会话、交易计划与模拟盘
+func (a *App) UnlockData(passphrase string) string {
+	v := vault.Default()
+	if !v.Locked() {
+		return "success"
+	}
+	if err := v.Unlock(passphrase); err != nil {
+		return err.Error()
+	}
+	a.sessionService.Reset()
+	a.paperTrading.Reload()
+	if a.configService.GetConfig().Broker.Enabled {
+		go func() {
+			if resp := a.SyncBrokerPositions(); !resp.Success {
+				log.Warn("券商持仓同步失败: %s", resp.Error)
+			}
+		}()
+	}
+	log.Info("数据已解锁")
+	a.eventBus.Emit("data:unlocked", nil)
+	return "success"
+}
+
+// EnableDataEncryption 启用数据加密，并加密已有的会话、记忆与模拟盘数据
+// 密码无法找回，遗忘后加密数据将无法读取
+func (a *App) EnableDataEncryption(passphrase string) string {
+	if err := vault.Default().Enable(passphrase, services.EncryptedDataFiles(paths.GetDataDir())); err != nil {
+		return err.Error()
+	}
+	log.Info("已启用数据加密")
+	return "success"
+}
+
+// DisableDataEncryption 校验密码后关闭数据加密，已加密文件恢复为明文
+func (a *App) DisableDataEncryption(passphrase string) string {
+	if err := vault.Default().Disable(passphrase, services.EncryptedDataFiles(paths.GetDataDir())); err != nil {
+		return err.Error()
+	}
+	log.Info("已关闭数据加密")
+	return "success"
+}
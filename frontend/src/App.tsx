@@ -2,26 +2,30 @@ import React, { useState, useEffect, useMemo, useCallback, useRef } from 'react'
 import { StockList } from './components/StockList';
 import { StockChartLW } from './components/StockChartLW';
 import { OrderBook as OrderBookComponent } from './components/OrderBook';
+import { TickList } from './components/TickList';
 import { AgentRoom } from './components/AgentRoom';
 import { SettingsDialog } from './components/SettingsDialog';
 import { PositionDialog } from './components/PositionDialog';
 import { HotTrendDialog } from './components/HotTrendDialog';
 import { LongHuBangDialog } from './components/LongHuBangDialog';
+import { LimitBoardDialog } from './components/LimitBoardDialog';
+import { MarketHeatmapDialog } from './components/MarketHeatmapDialog';
 import { WelcomePage } from './components/WelcomePage';
 import { ThemeSwitcher } from './components/ThemeSwitcher';
 import { useTheme } from './contexts/ThemeContext';
 import { useCandleColor } from './contexts/CandleColorContext';
 import { ResizeHandle } from './components/ResizeHandle';
-import { getWatchlist, addToWatchlist, removeFromWatchlist } from './services/watchlistService';
-import { getKLineData, getOrderBook } from './services/stockService';
+import { getWatchlist, addToWatchlist, removeFromWatchlist, getWatchlistGroups, addWatchlistGroup, addStockToGroup, removeStockFromGroup, reorderWatchlist } from './services/watchlistService';
+import { getKLineData, getIntradayHistory, getOrderBook } from './services/stockService';
 import { getOrCreateSession, StockSession, updateStockPosition } from './services/sessionService';
-import { getConfig, updateConfig } from './services/configService';
-import { useMarketEvents } from './hooks/useMarketEvents';
+import { getConfig, updateConfig, getProfiles } from './services/configService';
+import { useMarketEvents, OrderBookDelta, TicksUpdateData } from './hooks/useMarketEvents';
 import { useMarketStatus } from './hooks/useMarketStatus';
-import { Stock, KLineData, OrderBook, TimePeriod, Telegraph, MarketIndex } from './types';
-import { Radio, Settings, List, Minus, Square, X, Copy, Briefcase, TrendingUp, BarChart3 } from 'lucide-react';
+import { Stock, KLineData, OrderBook, TimePeriod, Telegraph, MarketIndex, WatchlistGroup, TickTrade, KLineAdjust, MINUTE_PERIODS } from './types';
+import { Radio, Settings, List, Minus, Square, X, Copy, Briefcase, TrendingUp, BarChart3, Flame, LayoutGrid } from 'lucide-react';
 import logo from './assets/images/logo.png';
 import { GetTelegraphList, OpenURL, WindowMinimize, WindowMaximize, WindowClose } from '../wailsjs/go/main/App';
+import { models } from '../wailsjs/go/models';
 import { WindowIsMaximised, WindowSetSize, WindowGetSize } from '../wailsjs/runtime/runtime';
 
 // 布局配置常量
@@ -47,12 +51,19 @@ const App: React.FC = () => {
   const { colors } = useTheme();
   const cc = useCandleColor();
   const [watchlist, setWatchlist] = useState<Stock[]>([]);
+  const [watchlistGroups, setWatchlistGroups] = useState<WatchlistGroup[]>([]);
+  const [activeGroup, setActiveGroup] = useState<string>('');
   const [selectedSymbol, setSelectedSymbol] = useState<string>('');
+  const [profile, setProfile] = useState<string>('default');
   const [currentSession, setCurrentSession] = useState<StockSession | null>(null);
   const [timePeriod, setTimePeriod] = useState<TimePeriod>('1m');
+  const [klineAdjust, setKLineAdjust] = useState<KLineAdjust>('qfq');
+  // 复权仅对日/周/月K生效，分时与分钟K线始终不复权
+  const effectiveAdjust: KLineAdjust = timePeriod === '1m' || MINUTE_PERIODS.includes(timePeriod) ? 'none' : klineAdjust;
   const [kLineData, setKLineData] = useState<KLineData[]>([]);
   const [kLineUpdateMode, setKLineUpdateMode] = useState<KLineUpdateMode>('full');
   const [orderBook, setOrderBook] = useState<OrderBook>({ bids: [], asks: [] });
+  const [ticks, setTicks] = useState<TickTrade[]>([]);
   const [marketMessage, setMarketMessage] = useState<string>('市场数据加载中...');
   const [telegraphList, setTelegraphList] = useState<Telegraph[]>([]);
   const [showTelegraphList, setShowTelegraphList] = useState(false);
@@ -62,9 +73,15 @@ const App: React.FC = () => {
   const [showPosition, setShowPosition] = useState(false);
   const [showHotTrend, setShowHotTrend] = useState(false);
   const [showLongHuBang, setShowLongHuBang] = useState(false);
+  const [showLimitBoard, setShowLimitBoard] = useState(false);
+  const [limitBoard, setLimitBoard] = useState<models.LimitBoard | null>(null);
+  const [showHeatmap, setShowHeatmap] = useState(false);
+  const [marketBreadth, setMarketBreadth] = useState<models.MarketBreadth | null>(null);
   const [marketIndices, setMarketIndices] = useState<MarketIndex[]>([]);
   const [isMaximized, setIsMaximized] = useState(false);
   const klineRequestIdRef = useRef(0);
+  const klineHasMoreRef = useRef(false);
+  const klineLoadingMoreRef = useRef(false);
 
   // 使用纯前端市场状态判断
   const { status: marketStatus } = useMarketStatus();
@@ -79,16 +96,11 @@ const App: React.FC = () => {
     watchlist.find(s => s.symbol === selectedSymbol) || watchlist[0]
   , [selectedSymbol, watchlist]);
 
-  // 处理股票数据更新（来自后端推送）
+  // 处理股票数据更新（来自后端推送，仅包含有变化的股票）
   const handleStockUpdate = useCallback((stocks: Stock[]) => {
-    if (!stocks || !Array.isArray(stocks)) return;
-    setWatchlist(prev => {
-      // 更新已有股票的数据
-      return prev.map(stock => {
-        const updated = stocks.find(s => s.symbol === stock.symbol);
-        return updated || stock;
-      });
-    });
+    if (!stocks || !Array.isArray(stocks) || stocks.length === 0) return;
+    const updates = new Map(stocks.map(s => [s.symbol, s]));
+    setWatchlist(prev => prev.map(stock => updates.get(stock.symbol) || stock));
   }, []);
 
   // 处理盘口数据更新（来自后端推送）
@@ -96,6 +108,28 @@ const App: React.FC = () => {
     setOrderBook(data);
   }, []);
 
+  // 处理盘口增量更新：按档位替换变化的数据
+  const handleOrderBookDelta = useCallback((delta: OrderBookDelta) => {
+    if (!delta || delta.code !== selectedSymbol) return;
+    setOrderBook(prev => {
+      const apply = (levels: OrderBook['bids'], changes: OrderBookDelta['bids']) => {
+        if (!changes || changes.length === 0) return levels;
+        const next = [...levels];
+        changes.forEach(c => {
+          if (c.index < next.length) next[c.index] = c.item;
+        });
+        return next;
+      };
+      return { bids: apply(prev.bids, delta.bids), asks: apply(prev.asks, delta.asks), metrics: delta.metrics ?? prev.metrics };
+    });
+  }, [selectedSymbol]);
+
+  // 处理逐笔成交更新：增量追加，保留最近 200 笔
+  const handleTicksUpdate = useCallback((data: TicksUpdateData) => {
+    if (!data || data.code !== selectedSymbol || !Array.isArray(data.data)) return;
+    setTicks(prev => (data.incremental ? [...prev, ...data.data] : data.data).slice(-200));
+  }, [selectedSymbol]);
+
   // 处理快讯数据更新（来自后端推送）
   const handleTelegraphUpdate = useCallback((data: Telegraph) => {
     if (data && data.content) {
@@ -113,6 +147,7 @@ const App: React.FC = () => {
   // 处理K线数据更新（来自后端推送，支持增量）
   const handleKLineUpdate = useCallback((data: { code: string; period: string; data: KLineData[]; incremental?: boolean }) => {
     if (!data || data.code !== selectedSymbol || data.period !== timePeriod) return;
+    if ((data.adjust || 'none') !== effectiveAdjust) return;
 
     if (data.incremental && data.data.length > 0) {
       setKLineUpdateMode('incremental');
@@ -133,10 +168,15 @@ const App: React.FC = () => {
       // 后端定时推送：用 refresh 模式更新数据但保留用户缩放状态
       if (Array.isArray(data.data) && data.data.length > 0) {
         setKLineUpdateMode('refresh');
-        setKLineData(data.data);
+        // 分钟K线保留已向前翻页加载的历史，只替换推送覆盖的区间
+        setKLineData(prev => {
+          if (!MINUTE_PERIODS.includes(timePeriod) || prev.length === 0) return data.data;
+          const first = data.data[0].time;
+          return [...prev.filter(d => d.time < first), ...data.data];
+        });
       }
     }
-  }, [selectedSymbol, timePeriod]);
+  }, [selectedSymbol, timePeriod, effectiveAdjust]);
 
   const syncWindowMaximizedState = useCallback(async () => {
     try {
@@ -212,6 +252,11 @@ const App: React.FC = () => {
     saveLayoutConfig(leftPanelWidth, rightPanelWidth, bottomPanelHeight);
   }, [leftPanelWidth, rightPanelWidth, bottomPanelHeight, saveLayoutConfig]);
 
+  // 当前工作区（非默认时在标题栏标注，避免误操作实盘数据）
+  useEffect(() => {
+    getProfiles().then(info => setProfile(info.current)).catch(() => {});
+  }, []);
+
   // 监听窗口 resize 事件
   useEffect(() => {
     const windowResizeTimeoutRef = { current: null as ReturnType<typeof setTimeout> | null };
@@ -257,12 +302,16 @@ const App: React.FC = () => {
   };
 
   // 使用市场事件 Hook
-  const { subscribeOrderBook, subscribeKLine } = useMarketEvents({
+  const { subscribeOrderBook, subscribeKLine, subscribeGroup } = useMarketEvents({
     onStockUpdate: handleStockUpdate,
     onOrderBookUpdate: handleOrderBookUpdate,
+    onOrderBookDelta: handleOrderBookDelta,
     onTelegraphUpdate: handleTelegraphUpdate,
     onMarketIndicesUpdate: handleMarketIndicesUpdate,
     onKLineUpdate: handleKLineUpdate,
+    onTicksUpdate: handleTicksUpdate,
+    onLimitBoardUpdate: setLimitBoard,
+    onMarketBreadthUpdate: setMarketBreadth,
   });
 
   // Handle Adding Stock
@@ -272,6 +321,7 @@ const App: React.FC = () => {
       setWatchlist(prev => [...prev, newStock]);
       // 添加后自动选中新股票并加载数据
       setSelectedSymbol(newStock.symbol);
+      setTicks([]);
       // 先清空 session，避免显示旧股票的消息
       setCurrentSession(null);
       subscribeOrderBook(newStock.symbol);
@@ -289,6 +339,7 @@ const App: React.FC = () => {
   const handleRemoveStock = async (symbol: string) => {
     await removeFromWatchlist(symbol);
     setWatchlist(prev => prev.filter(s => s.symbol !== symbol));
+    setWatchlistGroups(prev => prev.map(g => ({ ...g, symbols: g.symbols.filter(sym => sym !== symbol) })));
     // 如果删除的是当前选中的股票，切换到第一个
     if (symbol === selectedSymbol) {
       const remaining = watchlist.filter(s => s.symbol !== symbol);
@@ -298,9 +349,40 @@ const App: React.FC = () => {
     }
   };
 
+  // 切换分组：后端只推送当前分组的行情
+  const handleGroupChange = (groupId: string) => {
+    setActiveGroup(groupId);
+    subscribeGroup(groupId);
+  };
+
+  const handleAddGroup = async (name: string) => {
+    await addWatchlistGroup(name);
+    setWatchlistGroups(await getWatchlistGroups());
+  };
+
+  const handleToggleGroup = async (groupId: string, symbol: string, inGroup: boolean) => {
+    if (inGroup) {
+      await addStockToGroup(groupId, symbol);
+    } else {
+      await removeStockFromGroup(groupId, symbol);
+    }
+    setWatchlistGroups(await getWatchlistGroups());
+  };
+
+  // 拖拽排序：先本地更新再持久化
+  const handleReorder = async (symbols: string[]) => {
+    if (activeGroup) {
+      setWatchlistGroups(prev => prev.map(g => g.id === activeGroup ? { ...g, symbols } : g));
+    } else {
+      setWatchlist(prev => symbols.map(sym => prev.find(s => s.symbol === sym)).filter((s): s is Stock => !!s));
+    }
+    await reorderWatchlist(activeGroup, symbols);
+  };
+
   // Handle Stock Selection - Load Session and sync data
   const handleSelectStock = async (symbol: string) => {
     setSelectedSymbol(symbol);
+    setTicks([]);
     // 订阅该股票的盘口推送
     subscribeOrderBook(symbol);
     const stock = watchlist.find(s => s.symbol === symbol);
@@ -331,8 +413,9 @@ const App: React.FC = () => {
           }
         }
 
-        const list = await getWatchlist();
+        const [list, groups] = await Promise.all([getWatchlist(), getWatchlistGroups()]);
         setWatchlist(list);
+        setWatchlistGroups(groups);
         if (list.length > 0) {
           setSelectedSymbol(list[0].symbol);
           // 订阅第一个股票的盘口推送
@@ -365,16 +448,26 @@ const App: React.FC = () => {
     // 清空旧数据，避免切换期间出现“新股票 + 旧K线”错配
     setKLineData([]);
     // 订阅K线推送
-    subscribeKLine(selectedSymbol, timePeriod);
+    subscribeKLine(selectedSymbol, timePeriod, effectiveAdjust);
+
+    klineHasMoreRef.current = false;
 
     const loadKLineData = async () => {
       // 与后端推送统一数据长度，降低周/月K空响应概率
       const dataLen = timePeriod === '1m' ? 250 : 240;
+      const isMinuteK = MINUTE_PERIODS.includes(timePeriod);
       const maxRetries = 2;
       for (let attempt = 0; attempt <= maxRetries; attempt += 1) {
         if (requestId !== klineRequestIdRef.current) return;
         try {
-          const data = await getKLineData(selectedSymbol, timePeriod, dataLen);
+          let data: KLineData[];
+          if (isMinuteK) {
+            const page = await getIntradayHistory(selectedSymbol, timePeriod, '', dataLen);
+            klineHasMoreRef.current = page.hasMore;
+            data = page.data;
+          } else {
+            data = await getKLineData(selectedSymbol, timePeriod, dataLen, effectiveAdjust);
+          }
           if (requestId !== klineRequestIdRef.current) return;
           if (Array.isArray(data) && data.length > 0) {
             setKLineUpdateMode('full');
@@ -393,7 +486,29 @@ const App: React.FC = () => {
     };
 
     void loadKLineData();
-  }, [selectedSymbol, timePeriod, subscribeKLine]);
+  }, [selectedSymbol, timePeriod, effectiveAdjust, subscribeKLine]);
+
+  // 分钟K线滚动到最左侧时向前加载更早的数据
+  const handleLoadMoreKLine = useCallback(async () => {
+    if (!selectedSymbol || !klineHasMoreRef.current || klineLoadingMoreRef.current) return;
+    const first = kLineData[0];
+    if (!first) return;
+    const requestId = klineRequestIdRef.current;
+    klineLoadingMoreRef.current = true;
+    try {
+      const page = await getIntradayHistory(selectedSymbol, timePeriod, first.time, 240);
+      if (requestId !== klineRequestIdRef.current) return;
+      klineHasMoreRef.current = page.hasMore;
+      if (page.data.length > 0) {
+        setKLineUpdateMode('refresh');
+        setKLineData(prev => [...page.data.filter(d => d.time < (prev[0]?.time ?? first.time)), ...prev]);
+      }
+    } catch (err) {
+      console.error(`[kline] load more failed for ${selectedSymbol} ${timePeriod}`, err);
+    } finally {
+      klineLoadingMoreRef.current = false;
+    }
+  }, [selectedSymbol, timePeriod, kLineData]);
 
   // 初始化窗口最大化状态
   useEffect(() => {
@@ -425,6 +540,9 @@ const App: React.FC = () => {
         <div className="flex items-center gap-2" style={{ '--wails-draggable': 'no-drag' } as React.CSSProperties}>
           <img src={logo} alt="logo" className="h-8 w-8 rounded-lg" />
           <span className={`font-bold text-lg tracking-tight ${colors.isDark ? 'text-white' : 'text-slate-800'}`}>韭菜盘 <span className="text-accent-2">AI</span></span>
+          {profile !== 'default' && (
+            <span className="text-xs px-2 py-0.5 rounded-full border border-[var(--accent)] text-accent-2">{profile}</span>
+          )}
         </div>
         
         <div className="flex items-center gap-4 fin-panel-soft px-4 py-1.5 rounded-full border fin-divider relative" style={{ '--wails-draggable': 'no-drag' } as React.CSSProperties}>
@@ -479,6 +597,20 @@ const App: React.FC = () => {
           >
             <BarChart3 className="h-4 w-4" />
           </button>
+          <button
+            onClick={() => setShowLimitBoard(true)}
+            className={`p-2 rounded-lg fin-panel border fin-divider transition-colors ${colors.isDark ? 'text-slate-300 hover:text-white' : 'text-slate-600 hover:text-slate-900'} hover:border-red-400/40`}
+            title="涨跌停监控"
+          >
+            <Flame className="h-4 w-4" />
+          </button>
+          <button
+            onClick={() => setShowHeatmap(true)}
+            className={`p-2 rounded-lg fin-panel border fin-divider transition-colors ${colors.isDark ? 'text-slate-300 hover:text-white' : 'text-slate-600 hover:text-slate-900'} hover:border-accent/40`}
+            title="板块热力图"
+          >
+            <LayoutGrid className="h-4 w-4" />
+          </button>
           <button
             onClick={() => setShowHotTrend(true)}
             className={`p-2 rounded-lg fin-panel border fin-divider transition-colors ${colors.isDark ? 'text-slate-300 hover:text-white' : 'text-slate-600 hover:text-slate-900'} hover:border-orange-400/40`}
@@ -542,6 +674,12 @@ const App: React.FC = () => {
             onAddStock={handleAddStock}
             onRemoveStock={handleRemoveStock}
             marketIndices={marketIndices}
+            groups={watchlistGroups}
+            activeGroup={activeGroup}
+            onGroupChange={handleGroupChange}
+            onAddGroup={handleAddGroup}
+            onToggleGroup={handleToggleGroup}
+            onReorder={handleReorder}
           />
         </div>
 
@@ -618,7 +756,10 @@ const App: React.FC = () => {
                   data={kLineData}
                   updateMode={kLineUpdateMode}
                   period={timePeriod}
+                  adjust={klineAdjust}
+                  onAdjustChange={setKLineAdjust}
                   onPeriodChange={setTimePeriod}
+                  onLoadMore={MINUTE_PERIODS.includes(timePeriod) ? handleLoadMoreKLine : undefined}
                   stock={selectedStock}
                />
             </div>
@@ -626,11 +767,14 @@ const App: React.FC = () => {
             {/* Bottom Resize Handle */}
             <ResizeHandle direction="vertical" onResize={handleBottomResize} onResizeEnd={handleResizeEnd} />
 
-            {/* Bottom Info Panel: Order Book Only */}
+            {/* Bottom Info Panel: Order Book & Ticks */}
             <div style={{ height: bottomPanelHeight }} className="border-t fin-divider-soft flex shrink-0">
                <div className="flex-1 overflow-hidden relative">
                   <OrderBookComponent data={orderBook} />
                </div>
+               <div className="w-56 shrink-0 overflow-hidden">
+                  <TickList ticks={ticks} />
+               </div>
             </div>
           </div>
         </div>
@@ -665,6 +809,18 @@ const App: React.FC = () => {
       />
       <HotTrendDialog isOpen={showHotTrend} onClose={() => setShowHotTrend(false)} />
       <LongHuBangDialog isOpen={showLongHuBang} onClose={() => setShowLongHuBang(false)} />
+      <LimitBoardDialog
+        isOpen={showLimitBoard}
+        onClose={() => setShowLimitBoard(false)}
+        board={limitBoard}
+        onBoardLoaded={setLimitBoard}
+      />
+      <MarketHeatmapDialog
+        isOpen={showHeatmap}
+        onClose={() => setShowHeatmap(false)}
+        breadth={marketBreadth}
+        onBreadthLoaded={setMarketBreadth}
+      />
     </div>
   );
 };
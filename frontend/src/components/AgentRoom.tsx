@@ -1,33 +1,68 @@
 import React, { useState, useEffect, useRef } from 'react';
 import { Stock, KLineData } from '../types';
 import { getAgentConfigs, AgentConfig } from '../services/strategyService';
-import { StockSession, ChatMessage, sendMeetingMessage, MeetingMessageRequest, getSessionMessages, retryAgent, retryAgentAndContinue, cancelInterruptedMeeting } from '../services/sessionService';
-import { MessageSquare, Loader2, Send, User, Users, X, Reply, Trash2, Wrench, CheckCircle2, AlertCircle, Copy, Check, RotateCcw, Pencil, Square } from 'lucide-react';
+import { StockSession, ChatMessage, sendMeetingMessage, MeetingMessageRequest, getSessionMessages, retryAgent, retryAgentAndContinue, cancelInterruptedMeeting, runAgentABTest, rateABTest, runMeetingTemplate, MeetingTemplate, startDeepResearch, resumeDeepResearch, getDeepResearches, ResearchSummary, ErrorCode, estimatePromptTokens } from '../services/sessionService';
+import { MessageSquare, Loader2, Send, User, Users, X, Reply, Trash2, Wrench, CheckCircle2, AlertCircle, Copy, Check, RotateCcw, Pencil, Square, History, LayoutTemplate, Microscope, PlayCircle, Volume2, VolumeX } from 'lucide-react';
 import { clearSessionMessages } from '../services/sessionService';
 import { NodeRenderer } from 'markstream-react';
 import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
 import { useMentionPicker } from '../hooks/useMentionPicker';
+import { ToolCallSources, linkCitations } from './ToolCallSources';
+import { MeetingReplayDialog } from './MeetingReplayDialog';
+import { MeetingTemplateDialog } from './MeetingTemplateDialog';
+import { SummaryReportCards } from './SummaryReportCards';
+import { ConsensusBar } from './ConsensusBar';
 import { useTheme } from '../contexts/ThemeContext';
+import { useSpeech } from '../hooks/useSpeech';
 import { CancelMeeting } from '../../wailsjs/go/main/App';
 import 'markstream-react/index.css';
 
 // 进度事件类型
 interface ProgressEvent {
-  type: 'agent_start' | 'agent_done' | 'tool_call' | 'tool_result' | 'streaming' | 'agent_error' | 'meeting_interrupted';
+  type: 'agent_start' | 'agent_done' | 'tool_call' | 'tool_result' | 'streaming' | 'agent_error' | 'meeting_interrupted' | 'queued' | 'retry' | 'agent_invited' | 'research_step';
   agentId: string;
   agentName: string;
   detail?: string;
   content?: string;
+  meetingId?: string;
+  errorCode?: ErrorCode;
 }
 
-// 进度状态
-interface ProgressState {
-  currentAgent: string | null;
-  currentAgentName: string | null;
+// 单个专家的进度
+interface AgentProgress {
+  agentId: string;
+  agentName: string;
   steps: { type: string; detail: string; done: boolean }[];
   streamingText: string;
 }
 
+// 进度状态：智能模式同一时间只有一位专家，@ 多位专家时并行发言
+interface ProgressState {
+  agents: AgentProgress[];
+  queued?: string; // 排队提示，会议开始后清除
+}
+
+const STREAMING_PREVIEW_CHARS = 120; // 流式输出预览的字数
+
+// 失败卡片标题：按失败原因给出针对性的提示，未归类时显示通用文案
+const ERROR_TITLES: Record<ErrorCode, string> = {
+  rate_limited: '模型服务限流',
+  auth_failed: '鉴权失败',
+  context_too_long: '上下文过长',
+  tool_failed: '数据工具调用失败',
+  timeout: '响应超时',
+};
+
+// 专家发言统计，如“思考了 34s，调用了 3 个工具，使用 deepseek-v3”
+const formatAgentStats = (msg: ChatMessage): string => {
+  if (!msg.durationMs) return '';
+  const parts = [`思考了 ${Math.max(1, Math.round(msg.durationMs / 1000))}s`];
+  if (msg.toolCallCount) parts.push(`调用了 ${msg.toolCallCount} 个工具`);
+  if (msg.retries) parts.push(`重试 ${msg.retries} 次`);
+  if (msg.modelName) parts.push(`使用 ${msg.modelName}`);
+  return parts.join('，');
+};
+
 interface AgentRoomProps {
   stock: Stock;
   kLineData: KLineData[];
@@ -78,14 +113,32 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
   const [copiedId, setCopiedId] = useState<string | null>(null);
   const [failedUserMsgId, setFailedUserMsgId] = useState<string | null>(null);
   const [retryingAgentId, setRetryingAgentId] = useState<string | null>(null);
+  // A/B 对比：仅 @ 单个专家时可开启；abRatings 记录本次打开后的评价结果
+  const [abMode, setAbMode] = useState(false);
+  const [smartMode, setSmartMode] = useState(false); // @ 专家后仍由小韭菜召开会议
+  const [abRatings, setAbRatings] = useState<Record<string, string>>({});
+  const [replayMeetingId, setReplayMeetingId] = useState<string | null>(null);
+  const [showTemplates, setShowTemplates] = useState(false);
+  const [researchMode, setResearchMode] = useState(false); // 深度研究：研究员分步调研后输出长篇报告
+  const [pendingResearch, setPendingResearch] = useState<ResearchSummary | null>(null); // 未完成可恢复的深度研究
+  const [promptTokens, setPromptTokens] = useState(0); // 输入框下方显示的预计 prompt token 数
 
   // 进度状态
-  const [progress, setProgress] = useState<ProgressState>({
-    currentAgent: null,
-    currentAgentName: null,
-    steps: [],
-    streamingText: '',
-  });
+  const [progress, setProgress] = useState<ProgressState>({ agents: [] });
+  const speech = useSpeech();
+
+  // 输入停顿后估算 prompt token 数
+  useEffect(() => {
+    const query = userQuery.trim();
+    if (!query || !session?.stockCode) {
+      setPromptTokens(0);
+      return;
+    }
+    const timer = setTimeout(() => {
+      estimatePromptTokens(session.stockCode, query).then(setPromptTokens).catch(() => setPromptTokens(0));
+    }, 400);
+    return () => clearTimeout(timer);
+  }, [userQuery, session?.stockCode]);
 
   // 在聊天窗口中添加系统提示消息
   const addSystemMessage = (text: string) => {
@@ -108,12 +161,7 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
     // 前端状态重置
     meetingCancelledRef.current[stockCode] = true;
     setSimulatingMap(prev => ({ ...prev, [stockCode]: false }));
-    setProgress({
-      currentAgent: null,
-      currentAgentName: null,
-      steps: [],
-      streamingText: '',
-    });
+    setProgress({ agents: [] });
     addSystemMessage('讨论已停止');
   };
 
@@ -203,28 +251,57 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
       if (currentStockCodeRef.current !== stockCode) return;
 
       setProgress(prev => {
+        // 按 agentId 更新对应专家的进度
+        const update = (fn: (a: AgentProgress) => AgentProgress): ProgressState => ({
+          ...prev,
+          agents: prev.agents.map(a => a.agentId === event.agentId ? fn(a) : a),
+        });
         switch (event.type) {
           case 'agent_start':
             return {
-              currentAgent: event.agentId,
-              currentAgentName: event.agentName,
-              steps: [],
-              streamingText: '',
+              agents: [
+                ...prev.agents.filter(a => a.agentId !== event.agentId),
+                { agentId: event.agentId, agentName: event.agentName, steps: [], streamingText: '' },
+              ],
             };
           case 'agent_done':
-            return { ...prev, currentAgent: null, currentAgentName: null, steps: [], streamingText: '' };
+            return { ...prev, agents: prev.agents.filter(a => a.agentId !== event.agentId) };
           case 'tool_call':
-            return {
-              ...prev,
-              steps: [...prev.steps, { type: 'tool_call', detail: event.detail || '', done: false }],
-            };
+            return update(a => ({
+              ...a,
+              steps: [...a.steps, { type: 'tool_call', detail: event.detail || '', done: false }],
+            }));
           case 'tool_result':
-            const updatedSteps = prev.steps.map(s =>
-              s.type === 'tool_call' && s.detail === event.detail ? { ...s, done: true } : s
-            );
-            return { ...prev, steps: updatedSteps };
+            return update(a => ({
+              ...a,
+              steps: a.steps.map(s =>
+                s.type === 'tool_call' && s.detail === event.detail ? { ...s, done: true } : s
+              ),
+            }));
           case 'streaming':
-            return { ...prev, streamingText: prev.streamingText + (event.content || '') };
+            return update(a => ({ ...a, streamingText: a.streamingText + (event.content || '') }));
+          case 'retry':
+            // 重试时丢弃上次未完成的流式输出
+            return update(a => ({
+              ...a,
+              steps: [...a.steps, { type: 'retry', detail: `重试 ${event.detail || ''}`, done: true }],
+              streamingText: '',
+            }));
+          case 'queued':
+            return { ...prev, queued: event.detail || '排队中' };
+          case 'research_step': {
+            // 深度研究进入新阶段：保留已完成阶段，丢弃上一阶段的工具调用与流式输出
+            const researcher = prev.agents.find(a => a.agentId === event.agentId);
+            const phases = (researcher?.steps || []).filter(s => s.type === 'research_step').map(s => ({ ...s, done: true }));
+            return {
+              agents: [{
+                agentId: event.agentId,
+                agentName: event.agentName,
+                steps: [...phases, { type: 'research_step', detail: event.detail || '', done: false }],
+                streamingText: '',
+              }],
+            };
+          }
           case 'meeting_interrupted':
             return prev; // 状态在外部处理
           default:
@@ -236,6 +313,28 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
       if (event.type === 'meeting_interrupted') {
         setSimulatingMap(prev => ({ ...prev, [stockCode]: false }));
       }
+      // 小韭菜中途追加邀请专家
+      if (event.type === 'agent_invited') {
+        addSystemMessage(`小韭菜邀请 ${event.agentName} 加入讨论${event.detail ? `：${event.detail}` : ''}`);
+      }
+    });
+
+    return () => {
+      EventsOff(eventName);
+      if (cleanup) cleanup();
+    };
+  }, [session?.stockCode]);
+
+  // 订阅会议占用事件（该股票已有会议进行中，本次请求未发起新会议）
+  useEffect(() => {
+    if (!session?.stockCode) return;
+
+    const stockCode = session.stockCode;
+    const eventName = `meeting:busy:${stockCode}`;
+    const cleanup = EventsOn(eventName, (detail: string) => {
+      if (currentStockCodeRef.current === stockCode) {
+        addSystemMessage(detail);
+      }
     });
 
     return () => {
@@ -284,11 +383,20 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
         content: query,
         mentionIds: mentions,
         replyToId: replyTo?.id || '',
-        replyContent: replyTo?.content || ''
+        replyContent: replyTo?.content || '',
+        smart: smartMode && mentions.length > 0 && !abMode
       };
 
-      // 统一模式：无论智能模式还是直接@模式，消息都通过事件实时推送
-      await sendMeetingMessage(req);
+      if (abMode && mentions.length === 1) {
+        // A/B 对比：同一问题交给专家的 A、B 两个版本作答，回复通过事件实时推送
+        const result = await runAgentABTest(stockCode, mentions[0], query);
+        if (!result.success) {
+          addSystemMessage(result.error || 'A/B 对比失败');
+        }
+      } else {
+        // 统一模式：无论智能模式还是直接@模式，消息都通过事件实时推送
+        await sendMeetingMessage(req);
+      }
       // 消息已通过事件实时添加，更新session
       onSessionUpdate({
         ...session,
@@ -315,11 +423,78 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
     }
   };
 
+  // 按会议模板开会：问题由后端替换占位符后通过事件推送
+  // 查询该股最近一次未完成的深度研究
+  const refreshPendingResearch = async (stockCode: string) => {
+    try {
+      const list = await getDeepResearches(stockCode);
+      if (currentStockCodeRef.current !== stockCode) return;
+      setPendingResearch(list.find(r => r.status !== 'done') || null);
+    } catch (e) {
+      console.error('[AgentRoom] getDeepResearches error:', e);
+    }
+  };
+
+  // 切换股票时停止朗读
+  useEffect(() => speech.stop(), [session?.stockCode]);
+
+  useEffect(() => {
+    setPendingResearch(null);
+    if (session?.stockCode) refreshPendingResearch(session.stockCode);
+  }, [session?.stockCode]);
+
+  // 发起或恢复深度研究，报告通过事件推送
+  const handleDeepResearch = async (query: string, resumeId?: string) => {
+    if (!session || isSimulating) return;
+    const stockCode = session.stockCode;
+    meetingCancelledRef.current[stockCode] = false;
+    setSimulatingMap(prev => ({ ...prev, [stockCode]: true }));
+    setPendingResearch(null);
+    try {
+      const result = resumeId ? await resumeDeepResearch(resumeId) : await startDeepResearch(stockCode, query);
+      if (!result.success) {
+        addSystemMessage(`深度研究中断：${result.error || '未知错误'}，已完成的步骤已保存，可稍后继续`);
+      }
+    } catch (e) {
+      console.error('[AgentRoom] deep research error:', e);
+      addSystemMessage('深度研究失败，请稍后重试');
+    } finally {
+      setSimulatingMap(prev => ({ ...prev, [stockCode]: false }));
+      refreshPendingResearch(stockCode);
+    }
+  };
+
+  const handleRunTemplate = async (tpl: MeetingTemplate) => {
+    if (!session || isSimulating) return;
+    const stockCode = session.stockCode;
+    meetingCancelledRef.current[stockCode] = false;
+    setSimulatingMap(prev => ({ ...prev, [stockCode]: true }));
+    try {
+      const result = await runMeetingTemplate(tpl.id, stockCode);
+      if (!result.success) {
+        addSystemMessage(result.error || '按模板开会失败');
+      }
+    } catch (e) {
+      console.error('[AgentRoom] runMeetingTemplate error:', e);
+      addSystemMessage('按模板开会失败，请稍后重试');
+    } finally {
+      setSimulatingMap(prev => ({ ...prev, [stockCode]: false }));
+    }
+  };
+
   const handleSubmit = (e: React.FormEvent) => {
     e.preventDefault();
     if (!userQuery.trim() || isSimulating) return;
     // 允许不@任何人（智能模式）
 
+    if (researchMode && mentionedAgents.length === 0) {
+      const topic = userQuery;
+      setUserQuery('');
+      closePicker();
+      handleDeepResearch(topic);
+      return;
+    }
+
     // 保存当前状态用于发送
     const queryToSend = userQuery;
     const mentionsToSend = [...mentionedAgents];
@@ -397,6 +572,16 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
     inputRef.current?.focus();
   };
 
+  // 记录 A/B 对比中更好的版本
+  const handleRateABTest = async (testId: string, preferred: string) => {
+    const result = await rateABTest(testId, preferred);
+    if (result === 'success') {
+      setAbRatings(prev => ({ ...prev, [testId]: preferred }));
+    } else {
+      addSystemMessage(result);
+    }
+  };
+
   // 重试失败专家（根据 meetingMode 区分行为）
   const handleRetryAgent = async (msg: ChatMessage) => {
     if (!session || retryingAgentId) return;
@@ -468,14 +653,24 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
             <Users style={{ color: 'var(--accent)' }} />
             韭菜讨论中心
           </h2>
-          <button
-            onClick={handleClearMessages}
-            disabled={isSimulating || messages.length === 0}
-            className={`p-1.5 rounded transition-colors disabled:opacity-30 disabled:cursor-not-allowed ${colors.isDark ? 'text-slate-400 hover:text-red-400 hover:bg-slate-800' : 'text-slate-500 hover:text-red-500 hover:bg-slate-200'}`}
-            title="清空聊天记录"
-          >
-            <Trash2 size={16} />
-          </button>
+          <div className="flex items-center gap-1">
+            <button
+              onClick={() => setShowTemplates(true)}
+              disabled={isSimulating}
+              className={`p-1.5 rounded transition-colors disabled:opacity-30 disabled:cursor-not-allowed ${colors.isDark ? 'text-slate-400 hover:text-white hover:bg-slate-800' : 'text-slate-500 hover:text-slate-700 hover:bg-slate-200'}`}
+              title="会议模板"
+            >
+              <LayoutTemplate size={16} />
+            </button>
+            <button
+              onClick={handleClearMessages}
+              disabled={isSimulating || messages.length === 0}
+              className={`p-1.5 rounded transition-colors disabled:opacity-30 disabled:cursor-not-allowed ${colors.isDark ? 'text-slate-400 hover:text-red-400 hover:bg-slate-800' : 'text-slate-500 hover:text-red-500 hover:bg-slate-200'}`}
+              title="清空聊天记录"
+            >
+              <Trash2 size={16} />
+            </button>
+          </div>
         </div>
         <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>@韭菜提问，引用观点深入讨论</p>
       </div>
@@ -564,29 +759,38 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
 
           // 小韭菜消息（开场白/总结）
           const isModerator = msg.agentId === 'moderator';
-          if (isModerator) {
+          const isResearch = msg.msgType === 'research';
+          if (isModerator || isResearch) {
             const isOpening = msg.msgType === 'opening';
             const isSummary = msg.msgType === 'summary';
+            const isClarify = msg.msgType === 'clarify';
+            const isAnswer = msg.msgType === 'answer';
             return (
               <div key={msg.id} className="flex gap-3 animate-in fade-in slide-in-from-bottom-2 duration-300 group">
                 <div className="w-8 h-8 rounded-full flex items-center justify-center text-xs font-bold shrink-0 bg-gradient-to-br from-amber-500 to-orange-500 text-white shadow-md ring-2 ring-slate-900">
-                  <Users size={14} />
+                  {isResearch ? <Microscope size={14} /> : <Users size={14} />}
                 </div>
                 <div className="flex-1 max-w-[90%]">
                   <div className="flex items-baseline gap-2 mb-1">
                     <span className="text-xs font-bold text-amber-400">{msg.agentName}</span>
                     <span className={`text-[9px] border border-amber-500/30 px-1 rounded ${colors.isDark ? 'text-amber-500/70' : 'text-amber-600/70'}`}>
-                      {isOpening ? '开场' : isSummary ? '总结' : msg.role}
+                      {isOpening ? '开场' : isSummary ? '总结' : isClarify ? '追问' : isAnswer ? '直答' : isResearch ? '深度研究' : msg.role}
                     </span>
                   </div>
                   <div className="relative">
                     <div className={`text-sm p-3 rounded-2xl rounded-tl-none leading-relaxed shadow-sm agent-message-content ${
-                      isSummary
+                      isSummary || isResearch
                         ? (colors.isDark ? 'bg-gradient-to-br from-amber-900/40 to-orange-900/30 border border-amber-500/30 text-amber-100' : 'bg-gradient-to-br from-amber-100 to-orange-100 border border-amber-400/30 text-amber-900')
                         : (colors.isDark ? 'bg-slate-800/70 border border-amber-500/20 text-slate-200' : 'bg-slate-100 border border-amber-400/20 text-slate-700')
                     }`}>
-                      <NodeRenderer content={msg.content} />
+                      {isSummary && msg.report ? (
+                        <SummaryReportCards report={msg.report} />
+                      ) : (
+                        <NodeRenderer content={linkCitations(msg.content, msg.toolCalls)} />
+                      )}
+                      {isSummary && msg.consensus && <ConsensusBar consensus={msg.consensus} />}
                     </div>
+                    {msg.toolCalls && msg.toolCalls.length > 0 && <ToolCallSources calls={msg.toolCalls} />}
                     {/* 复制按钮 */}
                     <button
                       onClick={() => handleCopy(msg.id, msg.content)}
@@ -595,6 +799,24 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                     >
                       {copiedId === msg.id ? <Check size={12} className="text-green-400" /> : <Copy size={12} />}
                     </button>
+                    {/* 朗读按钮 */}
+                    <button
+                      onClick={async () => {
+                        const err = await speech.toggle(msg.id, msg.content);
+                        if (err) addSystemMessage(`朗读失败：${err}`);
+                      }}
+                      disabled={speech.loadingId === msg.id}
+                      className={`absolute -right-2 top-9 transition-opacity p-1.5 rounded-full shadow-lg ${speech.speakingId === msg.id || speech.loadingId === msg.id ? 'opacity-100' : 'opacity-0 group-hover:opacity-100'} ${colors.isDark ? 'bg-slate-700 hover:bg-slate-600 text-slate-300' : 'bg-white hover:bg-slate-100 text-slate-500 border border-slate-200'}`}
+                      title={speech.speakingId === msg.id ? '停止朗读' : '朗读'}
+                    >
+                      {speech.loadingId === msg.id ? (
+                        <Loader2 size={12} className="animate-spin" />
+                      ) : speech.speakingId === msg.id ? (
+                        <VolumeX size={12} className="text-accent-2" />
+                      ) : (
+                        <Volume2 size={12} />
+                      )}
+                    </button>
                   </div>
                 </div>
               </div>
@@ -613,6 +835,9 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                 <div className="flex items-baseline gap-2 mb-1">
                   <span className={`text-xs font-bold ${msg.error ? 'text-red-400' : (colors.isDark ? 'text-slate-300' : 'text-slate-600')}`}>{msg.agentName || agent?.name}</span>
                   <span className={`text-[9px] uppercase border fin-divider px-1 rounded fin-chip ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>{msg.role || agent?.role}</span>
+                  {msg.variant && (
+                    <span className="text-[9px] px-1 rounded bg-purple-500/20 text-purple-400 border border-purple-500/30">版本 {msg.variant}</span>
+                  )}
                   {msg.error && (
                     <span className="text-[9px] px-1 rounded bg-red-500/20 text-red-400 border border-red-500/30">失败</span>
                   )}
@@ -622,38 +847,47 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                     <div className={`text-sm p-3 rounded-2xl rounded-tl-none leading-relaxed shadow-sm border ${colors.isDark ? 'bg-red-950/30 border-red-500/30 text-red-300' : 'bg-red-50 border-red-300 text-red-600'}`}>
                       <div className="flex items-center gap-2 mb-2">
                         <AlertCircle size={14} />
-                        <span>分析失败</span>
+                        <span>{(msg.errorCode && ERROR_TITLES[msg.errorCode]) || '分析失败'}</span>
                       </div>
                       <div className={`text-xs ${colors.isDark ? 'text-red-400/70' : 'text-red-500/70'}`}>{msg.error}</div>
-                      <div className="flex items-center gap-2 mt-2">
-                        <button
-                          onClick={() => handleRetryAgent(msg)}
-                          disabled={retryingAgentId === msg.agentId}
-                          className={`flex items-center gap-1 text-xs px-3 py-1.5 rounded-lg transition-colors ${
-                            retryingAgentId === msg.agentId
-                              ? 'opacity-50 cursor-not-allowed'
-                              : (colors.isDark ? 'text-amber-400 hover:text-amber-300 bg-amber-500/10 hover:bg-amber-500/20' : 'text-amber-600 hover:text-amber-500 bg-amber-500/10 hover:bg-amber-500/20')
-                          }`}
-                        >
-                          {retryingAgentId === msg.agentId ? <Loader2 size={12} className="animate-spin" /> : <RotateCcw size={12} />}
-                          {retryingAgentId === msg.agentId ? '重试中...' : (msg.meetingMode === 'smart' ? '重试并继续' : '重试')}
-                        </button>
-                        {msg.meetingMode === 'smart' && (
+                      {msg.durationMs ? (
+                        <div className={`mt-1 text-[10px] ${colors.isDark ? 'text-red-400/50' : 'text-red-500/50'}`}>{formatAgentStats(msg)}</div>
+                      ) : null}
+                      {msg.meetingMode !== 'compare' && (
+                        <div className="flex items-center gap-2 mt-2">
                           <button
-                            onClick={() => handleAbandonMeeting(msg)}
-                            className={`flex items-center gap-1 text-xs px-3 py-1.5 rounded-lg transition-colors ${colors.isDark ? 'text-slate-400 bg-slate-500/10 hover:bg-slate-500/20' : 'text-slate-500 bg-slate-500/10 hover:bg-slate-500/20'}`}
+                            onClick={() => handleRetryAgent(msg)}
+                            disabled={retryingAgentId === msg.agentId}
+                            className={`flex items-center gap-1 text-xs px-3 py-1.5 rounded-lg transition-colors ${
+                              retryingAgentId === msg.agentId
+                                ? 'opacity-50 cursor-not-allowed'
+                                : (colors.isDark ? 'text-amber-400 hover:text-amber-300 bg-amber-500/10 hover:bg-amber-500/20' : 'text-amber-600 hover:text-amber-500 bg-amber-500/10 hover:bg-amber-500/20')
+                            }`}
                           >
-                            <X size={12} />
-                            放弃剩余
+                            {retryingAgentId === msg.agentId ? <Loader2 size={12} className="animate-spin" /> : <RotateCcw size={12} />}
+                            {retryingAgentId === msg.agentId ? '重试中...' : (msg.meetingMode === 'smart' ? '重试并继续' : '重试')}
                           </button>
-                        )}
-                      </div>
+                          {msg.meetingMode === 'smart' && (
+                            <button
+                              onClick={() => handleAbandonMeeting(msg)}
+                              className={`flex items-center gap-1 text-xs px-3 py-1.5 rounded-lg transition-colors ${colors.isDark ? 'text-slate-400 bg-slate-500/10 hover:bg-slate-500/20' : 'text-slate-500 bg-slate-500/10 hover:bg-slate-500/20'}`}
+                            >
+                              <X size={12} />
+                              放弃剩余
+                            </button>
+                          )}
+                        </div>
+                      )}
                     </div>
                   ) : (
                     <>
                       <div className={`text-sm p-3 rounded-2xl rounded-tl-none leading-relaxed shadow-sm agent-message-content ${colors.isDark ? 'text-slate-200 bg-slate-800/70 border border-slate-700/40' : 'text-slate-700 bg-white border border-slate-200'}`}>
-                        <NodeRenderer content={msg.content} />
+                        <NodeRenderer content={linkCitations(msg.content, msg.toolCalls)} />
                       </div>
+                      {msg.toolCalls && msg.toolCalls.length > 0 && <ToolCallSources calls={msg.toolCalls} />}
+                      {msg.durationMs ? (
+                        <div className={`mt-1 text-[10px] ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>{formatAgentStats(msg)}</div>
+                      ) : null}
                       {/* 操作按钮组 */}
                       <div className="absolute -right-2 top-1 flex flex-col gap-1 opacity-0 group-hover:opacity-100 transition-opacity">
                         <button
@@ -671,10 +905,38 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                         >
                           <Reply size={12} />
                         </button>
+                        {msg.meetingId && msg.meetingMode !== 'abtest' && (
+                          <button
+                            onClick={() => setReplayMeetingId(msg.meetingId!)}
+                            className={`p-1.5 rounded-full shadow-lg ${colors.isDark ? 'bg-slate-700 hover:bg-slate-600 text-slate-300' : 'bg-white hover:bg-slate-100 text-slate-500 border border-slate-200'}`}
+                            title="会议回放"
+                          >
+                            <History size={12} />
+                          </button>
+                        )}
                       </div>
                     </>
                   )}
                 </div>
+                {/* A/B 对比评价：显示在 B 版本回复下方 */}
+                {msg.abTestId && msg.variant === 'B' && (
+                  <div className="flex items-center gap-2 mt-2">
+                    <span className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>哪个版本更好？</span>
+                    {[['A', 'A 更好'], ['B', 'B 更好'], ['tie', '差不多']].map(([value, label]) => (
+                      <button
+                        key={value}
+                        onClick={() => handleRateABTest(msg.abTestId!, value)}
+                        className={`text-xs px-2 py-1 rounded-lg transition-colors ${
+                          abRatings[msg.abTestId!] === value
+                            ? 'bg-accent/20 text-accent-2'
+                            : (colors.isDark ? 'text-slate-400 bg-slate-500/10 hover:bg-slate-500/20' : 'text-slate-500 bg-slate-500/10 hover:bg-slate-500/20')
+                        }`}
+                      >
+                        {label}
+                      </button>
+                    ))}
+                  </div>
+                )}
               </div>
             </div>
           );
@@ -682,34 +944,47 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
         {/* 进度显示 */}
         {isSimulating && (
           <div className={`mx-4 p-3 fin-panel-soft rounded-xl border animate-in fade-in duration-300 ${colors.isDark ? 'border-slate-700/50' : 'border-slate-300/50'}`}>
-            {progress.currentAgent ? (
-              <div className="space-y-2">
-                <div className="flex items-center gap-2">
-                  <Loader2 className="animate-spin h-4 w-4 text-accent-2" />
-                  <span className="text-sm text-accent-2 font-medium">{progress.currentAgentName}</span>
-                  <span className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>正在分析...</span>
-                </div>
-                {progress.steps.length > 0 && (
-                  <div className="pl-6 space-y-1">
-                    {progress.steps.map((step, i) => (
-                      <div key={i} className="flex items-center gap-2 text-xs">
-                        {step.done ? (
-                          <CheckCircle2 className="h-3 w-3 text-green-400" />
-                        ) : (
-                          <Wrench className="h-3 w-3 text-amber-400 animate-pulse" />
-                        )}
-                        <span className={step.done ? (colors.isDark ? 'text-slate-400' : 'text-slate-500') : 'text-amber-400'}>
-                          {step.detail}
-                        </span>
+            {progress.agents.length > 0 ? (
+              <div className="space-y-3">
+                {progress.agents.map(agentProgress => (
+                  <div key={agentProgress.agentId} className="space-y-2">
+                    <div className="flex items-center gap-2">
+                      <Loader2 className="animate-spin h-4 w-4 text-accent-2" />
+                      <span className="text-sm text-accent-2 font-medium">{agentProgress.agentName}</span>
+                      <span className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>正在分析...</span>
+                    </div>
+                    {agentProgress.steps.length > 0 && (
+                      <div className="pl-6 space-y-1">
+                        {agentProgress.steps.map((step, i) => (
+                          <div key={i} className="flex items-center gap-2 text-xs">
+                            {step.type === 'retry' ? (
+                              <RotateCcw className="h-3 w-3 text-amber-400" />
+                            ) : step.done ? (
+                              <CheckCircle2 className="h-3 w-3 text-green-400" />
+                            ) : (
+                              <Wrench className="h-3 w-3 text-amber-400 animate-pulse" />
+                            )}
+                            <span className={step.done ? (colors.isDark ? 'text-slate-400' : 'text-slate-500') : 'text-amber-400'}>
+                              {step.detail}
+                            </span>
+                          </div>
+                        ))}
                       </div>
-                    ))}
+                    )}
+                    {/* 流式输出预览：只显示最新片段 */}
+                    {agentProgress.streamingText && (
+                      <div className={`pl-6 text-xs whitespace-pre-wrap break-all ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>
+                        {agentProgress.streamingText.length > STREAMING_PREVIEW_CHARS ? '…' : ''}
+                        {agentProgress.streamingText.slice(-STREAMING_PREVIEW_CHARS)}
+                      </div>
+                    )}
                   </div>
-                )}
+                ))}
               </div>
             ) : (
               <div className="flex items-center gap-2 justify-center">
                 <Loader2 className="animate-spin h-3 w-3 text-accent-2" />
-                <span className={`text-xs animate-pulse ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>会议进行中...</span>
+                <span className={`text-xs animate-pulse ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>{progress.queued || '会议进行中...'}</span>
               </div>
             )}
           </div>
@@ -793,6 +1068,25 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
             </div>
           )}
 
+          {/* 未完成的深度研究 */}
+          {pendingResearch && !isSimulating && (
+            <div className={`flex items-center gap-2 mb-2 p-2 rounded-lg text-xs border ${colors.isDark ? 'bg-slate-800/50 border-amber-500/20 text-slate-300' : 'bg-amber-50 border-amber-400/30 text-slate-600'}`}>
+              <Microscope size={12} className="text-amber-400 shrink-0" />
+              <span className="flex-1 truncate" title={pendingResearch.error}>
+                深度研究「{pendingResearch.query}」未完成
+                {pendingResearch.steps > 0 && `（已完成 ${pendingResearch.completed}/${pendingResearch.steps} 步）`}
+              </span>
+              <button
+                type="button"
+                onClick={() => handleDeepResearch(pendingResearch.query, pendingResearch.id)}
+                className="flex items-center gap-1 px-2 py-0.5 rounded text-amber-400 hover:bg-amber-500/10 transition-colors"
+              >
+                <PlayCircle size={12} />
+                继续研究
+              </button>
+            </div>
+          )}
+
           {/* 输入框 */}
           <form onSubmit={handleSubmit} className="flex gap-2">
             <input
@@ -802,9 +1096,50 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                onChange={handleInputChange}
                onKeyDown={handleKeyDown}
                disabled={isSimulating}
-               placeholder="直接提问或输入 @ 选择韭菜专家..."
+               placeholder={researchMode ? '输入深度研究课题，研究员将分步调研并撰写长篇报告...' : '直接提问或输入 @ 选择韭菜专家...'}
                className="flex-1 fin-input rounded-lg px-4 py-2 text-sm placeholder-slate-500 border fin-divider"
             />
+            {mentionedAgents.length === 0 && (
+              <button
+                type="button"
+                onClick={() => setResearchMode(v => !v)}
+                disabled={isSimulating}
+                className={`px-2 h-10 rounded-lg text-xs border transition-colors flex items-center gap-1 ${
+                  researchMode ? 'bg-amber-500/20 text-amber-400 border-amber-500/30' : (colors.isDark ? 'text-slate-400 border-slate-700 hover:bg-slate-800' : 'text-slate-500 border-slate-300 hover:bg-slate-100')
+                }`}
+                title="深度研究：研究员拟定计划后分步调用工具调研（耗时数分钟），每步保存进度，中断后可继续，最后输出长篇报告"
+              >
+                <Microscope size={12} />
+                深度研究
+              </button>
+            )}
+            {mentionedAgents.length > 0 && (
+              <button
+                type="button"
+                onClick={() => { setSmartMode(v => !v); setAbMode(false); }}
+                disabled={isSimulating}
+                className={`px-2 h-10 rounded-lg text-xs border transition-colors flex items-center gap-1 ${
+                  smartMode ? 'bg-accent/20 text-accent-2 border-accent/30' : (colors.isDark ? 'text-slate-400 border-slate-700 hover:bg-slate-800' : 'text-slate-500 border-slate-300 hover:bg-slate-100')
+                }`}
+                title="开会：由小韭菜召集会议，被 @ 的专家必定参会，其余专家由小韭菜按需邀请"
+              >
+                <Users size={12} />
+                开会
+              </button>
+            )}
+            {mentionedAgents.length === 1 && (
+              <button
+                type="button"
+                onClick={() => { setAbMode(v => !v); setSmartMode(false); }}
+                disabled={isSimulating}
+                className={`px-2 h-10 rounded-lg text-xs border transition-colors ${
+                  abMode ? 'bg-purple-500/20 text-purple-400 border-purple-500/30' : (colors.isDark ? 'text-slate-400 border-slate-700 hover:bg-slate-800' : 'text-slate-500 border-slate-300 hover:bg-slate-100')
+                }`}
+                title="A/B 对比：同一问题由专家的当前配置与 B 变体并排作答"
+              >
+                A/B
+              </button>
+            )}
             {isSimulating ? (
               <button
                 type="button"
@@ -827,7 +1162,14 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
           </form>
         </div>
         <div className="mt-1 text-center">
-          <span className={`text-[10px] ${colors.isDark ? 'text-slate-600' : 'text-slate-400'}`}>直接提问由小韭菜安排韭菜专家，@ 可指定韭菜专家</span>
+          <span className={`text-[10px] ${colors.isDark ? 'text-slate-600' : 'text-slate-400'}`}>
+            直接提问由小韭菜安排韭菜专家，@ 可指定韭菜专家
+            {promptTokens > 0 && (
+              <span title="老韭菜画像、股票记忆与问题的估算值，不含专家指令与前面专家的发言">
+                {' · '}预计 prompt {promptTokens >= 1000 ? `${(promptTokens / 1000).toFixed(1)}k` : promptTokens} tokens
+              </span>
+            )}
+          </span>
         </div>
       </div>
 
@@ -860,6 +1202,19 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
         </div>
       )}
 
+      <MeetingReplayDialog
+        isOpen={replayMeetingId !== null}
+        onClose={() => setReplayMeetingId(null)}
+        meetingId={replayMeetingId || ''}
+      />
+
+      <MeetingTemplateDialog
+        isOpen={showTemplates}
+        onClose={() => setShowTemplates(false)}
+        agents={allAgents}
+        stockName={session?.stockName || ''}
+        onRun={handleRunTemplate}
+      />
     </div>
   );
 };
@@ -0,0 +1,49 @@
+import React from 'react';
+import { Gauge } from 'lucide-react';
+import { ConsensusScore } from '../services/sessionService';
+import { useTheme } from '../contexts/ThemeContext';
+import { useCandleColor } from '../contexts/CandleColorContext';
+
+interface ConsensusBarProps {
+  consensus: ConsensusScore;
+}
+
+const stanceLabels: Record<string, string> = { bull: '看多', bear: '看空', neutral: '中性' };
+
+// 多空共识分：-100（一致看空）到 100（一致看多），按专家信心与历史准确率加权
+export const ConsensusBar: React.FC<ConsensusBarProps> = ({ consensus }) => {
+  const { colors } = useTheme();
+  const cc = useCandleColor();
+  const { score } = consensus;
+  const tone = score >= 30 ? cc.upClass : score <= -30 ? cc.downClass : 'text-amber-400';
+  // 以中点为起点向左（空）或向右（多）填充
+  const fill = Math.min(Math.abs(score), 100) / 2;
+  const detail = consensus.votes
+    .map(v => `${v.agentName}：${stanceLabels[v.stance] || v.stance}，信心 ${v.confidence}，权重 ${v.weight}`)
+    .join('\n');
+
+  return (
+    <div className="mt-2 text-xs" title={detail}>
+      <div className="flex items-center gap-2 mb-1">
+        <Gauge size={12} className={tone} />
+        <span className={colors.isDark ? 'text-slate-400' : 'text-slate-500'}>多空共识</span>
+        <span className={`font-mono font-bold ${tone}`}>{score > 0 ? '+' : ''}{score}</span>
+        <span className={tone}>{consensus.label}</span>
+        <span className={`ml-auto ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+          多 {consensus.bull} · 空 {consensus.bear} · 中性 {consensus.neutral}
+        </span>
+      </div>
+      <div className={`relative h-1.5 rounded-full ${colors.isDark ? 'bg-slate-700' : 'bg-slate-200'}`}>
+        <div className={`absolute top-0 h-full w-px ${colors.isDark ? 'bg-slate-500' : 'bg-slate-400'}`} style={{ left: '50%' }} />
+        <div
+          className="absolute top-0 h-full rounded-full"
+          style={{
+            backgroundColor: score >= 0 ? cc.upColor : cc.downColor,
+            width: `${fill}%`,
+            ...(score >= 0 ? { left: '50%' } : { right: '50%' }),
+          }}
+        />
+      </div>
+    </div>
+  );
+};
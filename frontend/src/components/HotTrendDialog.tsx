@@ -1,6 +1,7 @@
 import React, { useState, useEffect } from 'react';
 import { X, TrendingUp, RefreshCw, ExternalLink } from 'lucide-react';
 import { GetAllHotTrends, OpenURL } from '../../wailsjs/go/main/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
 import { hottrend } from '../../wailsjs/go/models';
 import { useTheme } from '../contexts/ThemeContext';
 
@@ -35,6 +36,21 @@ export const HotTrendDialog: React.FC<HotTrendDialogProps> = ({ isOpen, onClose
     }
   }, [isOpen]);
 
+  // 后台刷新检测到榜单明显变化时，按平台替换对应结果
+  useEffect(() => {
+    const cleanup = EventsOn('hottrend:update', (changed: hottrend.HotTrendResult[]) => {
+      if (!changed || changed.length === 0) return;
+      setResults(prev => {
+        if (prev.length === 0) return prev;
+        const byPlatform = new Map(changed.map(r => [r.platform, r]));
+        return prev.map(r => byPlatform.get(r.platform) || r);
+      });
+    });
+    return () => {
+      if (cleanup) cleanup();
+    };
+  }, []);
+
   if (!isOpen) return null;
 
   const currentResult = results.find(r => r.platform === selectedPlatform);
@@ -0,0 +1,176 @@
+import React, { useState, useEffect, useMemo } from 'react';
+import { X, Flame, RefreshCw } from 'lucide-react';
+import { GetLimitBoard } from '../../wailsjs/go/main/App';
+import { models } from '../../wailsjs/go/models';
+import { useCandleColor } from '../contexts/CandleColorContext';
+
+interface LimitBoardDialogProps {
+  isOpen: boolean;
+  onClose: () => void;
+  board: models.LimitBoard | null; // 后端推送的最新数据
+  onBoardLoaded: (board: models.LimitBoard) => void;
+}
+
+type PoolTab = 'up' | 'broken' | 'down';
+
+const TABS: { id: PoolTab; label: string }[] = [
+  { id: 'up', label: '涨停' },
+  { id: 'broken', label: '炸板' },
+  { id: 'down', label: '跌停' },
+];
+
+// 格式化金额为亿/万
+const formatAmount = (v: number) => {
+  if (!v) return '-';
+  if (Math.abs(v) >= 1e8) return (v / 1e8).toFixed(2) + '亿';
+  return (v / 1e4).toFixed(0) + '万';
+};
+
+export const LimitBoardDialog: React.FC<LimitBoardDialogProps> = ({ isOpen, onClose, board, onBoardLoaded }) => {
+  const cc = useCandleColor();
+  const [tab, setTab] = useState<PoolTab>('up');
+  const [loading, setLoading] = useState(false);
+
+  const load = async () => {
+    setLoading(true);
+    try {
+      const result = await GetLimitBoard('');
+      if (result) onBoardLoaded(result);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    if (isOpen) void load();
+  }, [isOpen]);
+
+  const limitUp = board?.limitUp || [];
+  const limitDown = board?.limitDown || [];
+  const broken = board?.broken || [];
+
+  // 连板梯队：连板数降序
+  const ladder = useMemo(() => {
+    const counts = new Map<number, number>();
+    for (const item of limitUp) {
+      const n = Math.max(item.boards, 1);
+      counts.set(n, (counts.get(n) || 0) + 1);
+    }
+    return [...counts.entries()].sort((a, b) => b[0] - a[0]);
+  }, [limitUp]);
+
+  const sealRate = limitUp.length + broken.length > 0
+    ? (limitUp.length * 100) / (limitUp.length + broken.length)
+    : 0;
+
+  const items = tab === 'up' ? limitUp : tab === 'broken' ? broken : limitDown;
+
+  if (!isOpen) return null;
+
+  return (
+    <div className="fixed inset-0 z-50 flex items-center justify-center">
+      <div className="absolute inset-0 bg-black/60 backdrop-blur-sm" onClick={onClose} />
+      <div className="relative w-[900px] h-[680px] fin-panel border fin-divider rounded-xl shadow-2xl flex flex-col overflow-hidden">
+        {/* Header */}
+        <div className="flex items-center justify-between px-4 py-3 border-b fin-divider">
+          <div className="flex items-center gap-2">
+            <Flame className="h-5 w-5 text-red-500" />
+            <span className="font-bold">涨跌停监控</span>
+            {board?.date && <span className="text-xs text-slate-500">{board.date}</span>}
+          </div>
+          <div className="flex items-center gap-2">
+            <button
+              onClick={() => void load()}
+              className="p-1.5 rounded hover:bg-slate-700/40 transition-colors"
+              title="刷新"
+            >
+              <RefreshCw className={`h-4 w-4 ${loading ? 'animate-spin' : ''}`} />
+            </button>
+            <button onClick={onClose} className="p-1.5 rounded hover:bg-slate-700/40 transition-colors">
+              <X className="h-4 w-4" />
+            </button>
+          </div>
+        </div>
+
+        {/* Summary */}
+        <div className="px-4 py-2 border-b fin-divider text-xs flex flex-wrap items-center gap-x-4 gap-y-1">
+          <span className={cc.upClass}>涨停 {limitUp.length}</span>
+          <span className={cc.downClass}>跌停 {limitDown.length}</span>
+          <span className="text-yellow-500">炸板 {broken.length}</span>
+          <span className="text-slate-400">封板率 {sealRate.toFixed(1)}%</span>
+          {ladder.length > 0 && (
+            <span className="text-slate-400">
+              连板梯队：
+              {ladder.map(([n, count]) => (
+                <span key={n} className="ml-2">
+                  <span className={n > 1 ? cc.upClass : ''}>{n === 1 ? '首板' : `${n}板`}</span>
+                  <span className="ml-0.5">{count}</span>
+                </span>
+              ))}
+            </span>
+          )}
+        </div>
+
+        {/* Tabs */}
+        <div className="flex gap-1 px-4 pt-2">
+          {TABS.map(t => (
+            <button
+              key={t.id}
+              onClick={() => setTab(t.id)}
+              className={`text-xs px-3 py-1 rounded transition-colors ${
+                tab === t.id ? 'bg-slate-700/60 text-accent-2 font-bold' : 'text-slate-400 hover:text-slate-200'
+              }`}
+            >
+              {t.label}
+            </button>
+          ))}
+        </div>
+
+        {/* List */}
+        <div className="flex-1 overflow-auto px-4 py-2">
+          <table className="w-full text-xs">
+            <thead className="text-slate-500 sticky top-0 fin-panel">
+              <tr className="text-left">
+                <th className="py-1.5 font-normal">名称</th>
+                <th className="py-1.5 font-normal text-right">最新价</th>
+                <th className="py-1.5 font-normal text-right">涨跌幅</th>
+                <th className="py-1.5 font-normal text-right">{tab === 'down' ? '连跌' : '连板'}</th>
+                <th className="py-1.5 font-normal text-right">首封</th>
+                <th className="py-1.5 font-normal text-right">{tab === 'down' ? '开板' : '炸板'}</th>
+                <th className="py-1.5 font-normal text-right">封单</th>
+                <th className="py-1.5 font-normal text-right">换手</th>
+                <th className="py-1.5 font-normal pl-3">行业</th>
+              </tr>
+            </thead>
+            <tbody>
+              {items.length === 0 ? (
+                <tr>
+                  <td colSpan={9} className="py-8 text-center text-slate-500">
+                    {loading ? '加载中...' : '暂无数据'}
+                  </td>
+                </tr>
+              ) : items.map(item => (
+                <tr key={item.code} className="border-t fin-divider hover:bg-slate-700/20">
+                  <td className="py-1.5">
+                    <div>{item.name}</div>
+                    <div className="text-[10px] text-slate-500 font-mono">{item.code}</div>
+                  </td>
+                  <td className="py-1.5 text-right font-mono">{item.price.toFixed(2)}</td>
+                  <td className={`py-1.5 text-right font-mono ${item.changePercent >= 0 ? cc.upClass : cc.downClass}`}>
+                    {item.changePercent.toFixed(2)}%
+                  </td>
+                  <td className="py-1.5 text-right font-mono">{tab === 'broken' ? '-' : Math.max(item.boards, 1)}</td>
+                  <td className="py-1.5 text-right font-mono">{item.firstSealTime || '-'}</td>
+                  <td className="py-1.5 text-right font-mono">{item.openTimes}</td>
+                  <td className="py-1.5 text-right font-mono">{formatAmount(item.sealFund)}</td>
+                  <td className="py-1.5 text-right font-mono">{item.turnoverRate.toFixed(2)}%</td>
+                  <td className="py-1.5 pl-3 text-slate-400">{item.industry}</td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </div>
+  );
+};
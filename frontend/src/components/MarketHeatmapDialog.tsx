@@ -0,0 +1,111 @@
+import React, { useState, useEffect } from 'react';
+import { X, LayoutGrid, RefreshCw } from 'lucide-react';
+import { GetMarketBreadth } from '../../wailsjs/go/main/App';
+import { models } from '../../wailsjs/go/models';
+import { useCandleColor } from '../contexts/CandleColorContext';
+
+interface MarketHeatmapDialogProps {
+  isOpen: boolean;
+  onClose: () => void;
+  breadth: models.MarketBreadth | null; // 后端推送的最新数据
+  onBreadthLoaded: (breadth: models.MarketBreadth) => void;
+}
+
+// 涨跌幅映射为色块透明度，±5% 封顶
+const cellStyle = (change: number, upColor: string, downColor: string): React.CSSProperties => {
+  const alpha = Math.min(Math.abs(change) / 5, 1) * 0.85 + 0.1;
+  const base = change >= 0 ? upColor : downColor;
+  const hex = Math.round(alpha * 255).toString(16).padStart(2, '0');
+  return { backgroundColor: change === 0 ? '#64748b40' : base + hex };
+};
+
+export const MarketHeatmapDialog: React.FC<MarketHeatmapDialogProps> = ({ isOpen, onClose, breadth, onBreadthLoaded }) => {
+  const cc = useCandleColor();
+  const [loading, setLoading] = useState(false);
+
+  const load = async () => {
+    setLoading(true);
+    try {
+      const result = await GetMarketBreadth();
+      if (result) onBreadthLoaded(result);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    if (isOpen) void load();
+  }, [isOpen]);
+
+  if (!isOpen) return null;
+
+  const sectors = breadth?.sectors || [];
+  const total = (breadth?.up || 0) + (breadth?.down || 0) + (breadth?.flat || 0);
+  const upRatio = total > 0 ? ((breadth?.up || 0) * 100) / total : 0;
+
+  return (
+    <div className="fixed inset-0 z-50 flex items-center justify-center">
+      <div className="absolute inset-0 bg-black/60 backdrop-blur-sm" onClick={onClose} />
+      <div className="relative w-[960px] h-[680px] fin-panel border fin-divider rounded-xl shadow-2xl flex flex-col overflow-hidden">
+        {/* Header */}
+        <div className="flex items-center justify-between px-4 py-3 border-b fin-divider">
+          <div className="flex items-center gap-2">
+            <LayoutGrid className="h-5 w-5 text-accent-2" />
+            <span className="font-bold">板块热力图</span>
+          </div>
+          <div className="flex items-center gap-2">
+            <button
+              onClick={() => void load()}
+              className="p-1.5 rounded hover:bg-slate-700/40 transition-colors"
+              title="刷新"
+            >
+              <RefreshCw className={`h-4 w-4 ${loading ? 'animate-spin' : ''}`} />
+            </button>
+            <button onClick={onClose} className="p-1.5 rounded hover:bg-slate-700/40 transition-colors">
+              <X className="h-4 w-4" />
+            </button>
+          </div>
+        </div>
+
+        {/* Breadth */}
+        {breadth && (
+          <div className="px-4 py-2 border-b fin-divider text-xs flex items-center gap-4">
+            <span className={cc.upClass}>上涨 {breadth.up}</span>
+            <span className={cc.downClass}>下跌 {breadth.down}</span>
+            <span className="text-slate-400">平盘 {breadth.flat}</span>
+            <div className="flex-1 h-1.5 rounded overflow-hidden flex bg-slate-700/40">
+              <div style={{ width: `${upRatio}%`, backgroundColor: cc.upColor }} />
+              <div className="flex-1" style={{ backgroundColor: cc.downColor }} />
+            </div>
+            <span className="text-slate-400">{upRatio.toFixed(1)}%</span>
+          </div>
+        )}
+
+        {/* Heatmap */}
+        <div className="flex-1 overflow-auto p-3">
+          {sectors.length === 0 ? (
+            <div className="h-full flex items-center justify-center text-sm text-slate-500">
+              {loading ? '加载中...' : '暂无数据'}
+            </div>
+          ) : (
+            <div className="grid grid-cols-8 gap-1">
+              {sectors.map(s => (
+                <div
+                  key={s.code}
+                  className="rounded px-1.5 py-2 text-center text-white"
+                  style={cellStyle(s.changePercent, cc.upColor, cc.downColor)}
+                  title={`${s.name} 涨${s.upCount}/跌${s.downCount}${s.leaderName ? ` 领涨:${s.leaderName} ${s.leaderChange.toFixed(2)}%` : ''}`}
+                >
+                  <div className="text-xs truncate">{s.name}</div>
+                  <div className="text-xs font-mono font-bold">
+                    {s.changePercent > 0 ? '+' : ''}{s.changePercent.toFixed(2)}%
+                  </div>
+                </div>
+              ))}
+            </div>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
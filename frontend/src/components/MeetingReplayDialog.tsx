@@ -0,0 +1,280 @@
+import React, { useState, useEffect, useMemo } from 'react';
+import { X, History, Loader2, SkipBack, SkipForward, ChevronLeft, ChevronRight, Play, Pause, Wrench, CheckCircle2, RotateCcw, AlertCircle } from 'lucide-react';
+import { getMeetingReplay, MeetingReplay, ReplayEvent } from '../services/sessionService';
+import { useTheme } from '../contexts/ThemeContext';
+
+interface MeetingReplayDialogProps {
+  isOpen: boolean;
+  onClose: () => void;
+  meetingId: string;
+}
+
+// 回放中的一步，合并流式片段时一步可包含多个连续的 streaming 事件
+interface ReplayStep {
+  offset: number;
+  events: ReplayEvent[];
+}
+
+// 截至当前步骤单个专家的状态
+interface AgentReplayState {
+  agentId: string;
+  agentName: string;
+  text: string;
+  steps: { type: string; detail: string; done: boolean }[];
+  final?: string;
+  error?: string;
+}
+
+const PLAY_INTERVAL = 400; // 自动播放间隔(毫秒)
+
+const isStreaming = (e: ReplayEvent) => e.progress?.type === 'streaming';
+
+// 步骤在时间线上的描述
+const describeStep = (step: ReplayStep): string => {
+  const e = step.events[0];
+  if (e.response) {
+    return `${e.response.agentName} 发言${e.response.error ? '失败' : ''}`;
+  }
+  const p = e.progress!;
+  switch (p.type) {
+    case 'agent_start': return `${p.agentName} 开始分析`;
+    case 'agent_done': return `${p.agentName} 完成`;
+    case 'tool_call': return `${p.agentName} 调用 ${p.detail}`;
+    case 'tool_result': return `${p.agentName} 获得 ${p.detail} 结果`;
+    case 'streaming': {
+      const chars = step.events.reduce((n, ev) => n + (ev.progress?.content.length || 0), 0);
+      return `${p.agentName} 输出 ${chars} 字`;
+    }
+    case 'agent_invited': return `邀请 ${p.agentName}`;
+    case 'retry': return `${p.agentName} 重试 ${p.detail}`;
+    case 'failover': return `${p.agentName} 切换模型 ${p.detail}`;
+    case 'agent_error': return `${p.agentName} 出错`;
+    default: return `${p.type}${p.detail ? ` ${p.detail}` : ''}`;
+  }
+};
+
+// 按步骤重放事件，得到截至当前步骤各专家的状态
+const buildAgentStates = (steps: ReplayStep[], upTo: number): AgentReplayState[] => {
+  const agents = new Map<string, AgentReplayState>();
+  const get = (id: string, name: string) => {
+    let s = agents.get(id);
+    if (!s) {
+      s = { agentId: id, agentName: name, text: '', steps: [] };
+      agents.set(id, s);
+    }
+    return s;
+  };
+  steps.slice(0, upTo + 1).forEach(step => step.events.forEach(e => {
+    if (e.response) {
+      const s = get(e.response.agentId, e.response.agentName);
+      s.final = e.response.content;
+      s.error = e.response.error;
+      return;
+    }
+    const p = e.progress!;
+    if (!p.agentId) return;
+    const s = get(p.agentId, p.agentName);
+    switch (p.type) {
+      case 'streaming':
+        s.text += p.content;
+        break;
+      case 'tool_call':
+        s.steps.push({ type: 'tool_call', detail: p.detail, done: false });
+        break;
+      case 'tool_result':
+        s.steps = s.steps.map(st => st.type === 'tool_call' && st.detail === p.detail ? { ...st, done: true } : st);
+        break;
+      case 'retry':
+        // 重试时上次的流式输出作废
+        s.text = '';
+        s.steps.push({ type: 'retry', detail: `重试 ${p.detail}${p.content ? `（${p.content}）` : ''}`, done: true });
+        break;
+      case 'failover':
+        s.steps.push({ type: 'retry', detail: `切换模型 ${p.detail}`, done: true });
+        break;
+      case 'agent_invited':
+        s.steps.push({ type: 'invite', detail: `受邀加入${p.detail ? `：${p.detail}` : ''}`, done: true });
+        break;
+    }
+  }));
+  return Array.from(agents.values());
+};
+
+// 会议回放：逐步查看一场会议的工具调用、流式输出与重试过程
+export const MeetingReplayDialog: React.FC<MeetingReplayDialogProps> = ({ isOpen, onClose, meetingId }) => {
+  const { colors } = useTheme();
+  const [replay, setReplay] = useState<MeetingReplay | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState('');
+  const [mergeStreaming, setMergeStreaming] = useState(true);
+  const [current, setCurrent] = useState(0);
+  const [playing, setPlaying] = useState(false);
+
+  useEffect(() => {
+    if (!isOpen || !meetingId) return;
+    setLoading(true);
+    setError('');
+    setReplay(null);
+    setPlaying(false);
+    getMeetingReplay(meetingId)
+      .then(r => {
+        if (r) setReplay(r);
+        else setError('该会议没有回放记录');
+      })
+      .catch(() => setError('加载会议回放失败'))
+      .finally(() => setLoading(false));
+  }, [isOpen, meetingId]);
+
+  const steps = useMemo<ReplayStep[]>(() => {
+    const result: ReplayStep[] = [];
+    (replay?.events || []).forEach(e => {
+      const last = result[result.length - 1];
+      if (mergeStreaming && last && isStreaming(e) && isStreaming(last.events[0])
+        && last.events[0].progress!.agentId === e.progress!.agentId) {
+        last.events.push(e);
+        return;
+      }
+      result.push({ offset: e.offset, events: [e] });
+    });
+    return result;
+  }, [replay, mergeStreaming]);
+
+  useEffect(() => {
+    setCurrent(steps.length > 0 ? steps.length - 1 : 0);
+  }, [steps]);
+
+  useEffect(() => {
+    if (!playing) return;
+    if (current >= steps.length - 1) {
+      setPlaying(false);
+      return;
+    }
+    const timer = setTimeout(() => setCurrent(c => c + 1), PLAY_INTERVAL);
+    return () => clearTimeout(timer);
+  }, [playing, current, steps.length]);
+
+  const agents = useMemo(() => buildAgentStates(steps, current), [steps, current]);
+
+  if (!isOpen) return null;
+
+  const muted = colors.isDark ? 'text-slate-500' : 'text-slate-400';
+  const btnClass = `p-1.5 rounded transition-colors disabled:opacity-40 ${colors.isDark ? 'hover:bg-slate-700 text-slate-300' : 'hover:bg-slate-200 text-slate-600'}`;
+  const last = steps.length - 1;
+
+  const togglePlay = () => {
+    if (!playing && current >= last) setCurrent(0);
+    setPlaying(!playing);
+  };
+
+  return (
+    <div className="fixed inset-0 z-50 flex items-center justify-center">
+      <div className="absolute inset-0 bg-black/60" onClick={onClose} />
+      <div className="relative w-[900px] max-w-[95vw] h-[80vh] flex flex-col fin-panel border fin-divider rounded-xl shadow-2xl">
+        {/* Header */}
+        <div className="flex items-center justify-between p-4 border-b fin-divider">
+          <div className="flex items-center gap-2 min-w-0">
+            <History className="h-5 w-5 text-accent-2 shrink-0" />
+            <span className={`font-bold ${colors.isDark ? 'text-slate-100' : 'text-slate-800'}`}>会议回放</span>
+            {replay && (
+              <span className={`text-xs truncate ${muted}`}>
+                {replay.stockName} · {new Date(replay.startedAt).toLocaleString()} · {replay.query}
+              </span>
+            )}
+          </div>
+          <button
+            onClick={onClose}
+            className={`p-1 rounded transition-colors ${colors.isDark ? 'hover:bg-slate-700 text-slate-400 hover:text-white' : 'hover:bg-slate-200 text-slate-500 hover:text-slate-700'}`}
+          >
+            <X className="h-5 w-5" />
+          </button>
+        </div>
+
+        {loading ? (
+          <div className="flex-1 flex items-center justify-center">
+            <Loader2 className="animate-spin h-5 w-5 text-accent-2" />
+          </div>
+        ) : error || steps.length === 0 ? (
+          <div className={`flex-1 flex items-center justify-center text-sm ${muted}`}>{error || '回放中没有事件'}</div>
+        ) : (
+          <>
+            {/* Controls */}
+            <div className="flex items-center gap-1 px-4 py-2 border-b fin-divider">
+              <button className={btnClass} onClick={() => setCurrent(0)} disabled={current === 0} title="第一步"><SkipBack size={14} /></button>
+              <button className={btnClass} onClick={() => setCurrent(c => Math.max(0, c - 1))} disabled={current === 0} title="上一步"><ChevronLeft size={14} /></button>
+              <button className={btnClass} onClick={togglePlay} title={playing ? '暂停' : '播放'}>{playing ? <Pause size={14} /> : <Play size={14} />}</button>
+              <button className={btnClass} onClick={() => setCurrent(c => Math.min(last, c + 1))} disabled={current >= last} title="下一步"><ChevronRight size={14} /></button>
+              <button className={btnClass} onClick={() => setCurrent(last)} disabled={current >= last} title="最后一步"><SkipForward size={14} /></button>
+              <span className={`ml-2 text-xs font-mono ${muted}`}>
+                {current + 1}/{steps.length} · +{(steps[current].offset / 1000).toFixed(1)}s
+              </span>
+              <div className="flex-1" />
+              <label className={`flex items-center gap-1 text-xs cursor-pointer ${muted}`}>
+                <input type="checkbox" checked={mergeStreaming} onChange={e => setMergeStreaming(e.target.checked)} />
+                合并流式片段
+              </label>
+            </div>
+
+            <div className="flex-1 flex min-h-0">
+              {/* Timeline */}
+              <div className="w-64 shrink-0 border-r fin-divider overflow-y-auto fin-scrollbar text-xs">
+                {steps.map((step, i) => (
+                  <button
+                    key={i}
+                    onClick={() => { setPlaying(false); setCurrent(i); }}
+                    className={`w-full flex items-center gap-2 px-3 py-1.5 text-left transition-colors ${
+                      i === current
+                        ? 'bg-accent/20 text-accent-2'
+                        : i < current
+                          ? (colors.isDark ? 'text-slate-300 hover:bg-slate-800/60' : 'text-slate-600 hover:bg-slate-100')
+                          : `${muted} ${colors.isDark ? 'hover:bg-slate-800/60' : 'hover:bg-slate-100'}`
+                    }`}
+                  >
+                    <span className="shrink-0 w-12 font-mono">+{(step.offset / 1000).toFixed(1)}s</span>
+                    <span className="truncate">{describeStep(step)}</span>
+                  </button>
+                ))}
+              </div>
+
+              {/* State */}
+              <div className="flex-1 overflow-y-auto fin-scrollbar p-4 space-y-3 text-left">
+                {agents.length === 0 && <div className={`text-xs ${muted}`}>会议尚未有专家发言</div>}
+                {agents.map(agent => (
+                  <div key={agent.agentId} className={`p-3 rounded-lg border ${colors.isDark ? 'border-slate-700/50 bg-slate-800/30' : 'border-slate-200 bg-slate-50'}`}>
+                    <div className="flex items-center gap-2 mb-1">
+                      <span className={`text-sm font-medium ${colors.isDark ? 'text-slate-100' : 'text-slate-800'}`}>{agent.agentName}</span>
+                      {agent.final !== undefined && !agent.error && <CheckCircle2 className="h-3 w-3 text-green-400" />}
+                      {agent.error && <AlertCircle className="h-3 w-3 text-red-400" />}
+                    </div>
+                    {agent.steps.length > 0 && (
+                      <div className="space-y-1 mb-2">
+                        {agent.steps.map((st, i) => (
+                          <div key={i} className="flex items-center gap-2 text-xs">
+                            {st.type === 'retry' ? (
+                              <RotateCcw className="h-3 w-3 text-amber-400" />
+                            ) : st.done ? (
+                              <CheckCircle2 className="h-3 w-3 text-green-400" />
+                            ) : (
+                              <Wrench className="h-3 w-3 text-amber-400" />
+                            )}
+                            <span className={muted}>{st.detail}</span>
+                          </div>
+                        ))}
+                      </div>
+                    )}
+                    {agent.error ? (
+                      <div className="text-xs text-red-400">{agent.error}</div>
+                    ) : (agent.final ?? agent.text) && (
+                      <div className={`text-xs whitespace-pre-wrap ${colors.isDark ? 'text-slate-300' : 'text-slate-600'}`}>
+                        {agent.final ?? agent.text}
+                      </div>
+                    )}
+                  </div>
+                ))}
+              </div>
+            </div>
+          </>
+        )}
+      </div>
+    </div>
+  );
+};
@@ -0,0 +1,211 @@
+import React, { useState, useEffect } from 'react';
+import { X, LayoutTemplate, Plus, Play, Pencil, Trash2, Loader2 } from 'lucide-react';
+import { getMeetingTemplates, saveMeetingTemplate, deleteMeetingTemplate, MeetingTemplate } from '../services/sessionService';
+import { AgentConfig } from '../services/strategyService';
+import { useTheme } from '../contexts/ThemeContext';
+
+interface MeetingTemplateDialogProps {
+  isOpen: boolean;
+  onClose: () => void;
+  agents: AgentConfig[];
+  stockName: string;
+  onRun: (tpl: MeetingTemplate) => void;
+}
+
+const MODE_LABELS: Record<MeetingTemplate['mode'], string> = {
+  smart: '小韭菜开会',
+  direct: '专家独立作答',
+};
+
+const emptyTemplate = (): MeetingTemplate => ({
+  id: '',
+  name: '',
+  agentIds: [],
+  mode: 'smart',
+  query: '对 {stock} 做财报季前瞻',
+  createdAt: 0,
+  updatedAt: 0,
+});
+
+// 会议模板：保存常用的专家阵容与问题，对任意股票一键开会
+export const MeetingTemplateDialog: React.FC<MeetingTemplateDialogProps> = ({ isOpen, onClose, agents, stockName, onRun }) => {
+  const { colors } = useTheme();
+  const [templates, setTemplates] = useState<MeetingTemplate[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [editing, setEditing] = useState<MeetingTemplate | null>(null);
+  const [error, setError] = useState('');
+
+  const reload = () => {
+    setLoading(true);
+    getMeetingTemplates()
+      .then(setTemplates)
+      .finally(() => setLoading(false));
+  };
+
+  useEffect(() => {
+    if (!isOpen) return;
+    setEditing(null);
+    setError('');
+    reload();
+  }, [isOpen]);
+
+  if (!isOpen) return null;
+
+  const muted = colors.isDark ? 'text-slate-500' : 'text-slate-400';
+  const iconBtn = `p-1.5 rounded transition-colors ${colors.isDark ? 'hover:bg-slate-700 text-slate-400' : 'hover:bg-slate-200 text-slate-500'}`;
+  const agentName = (id: string) => agents.find(a => a.id === id)?.name || id;
+
+  const handleSave = async () => {
+    if (!editing) return;
+    const result = await saveMeetingTemplate(editing);
+    if (result !== 'success') {
+      setError(result);
+      return;
+    }
+    setEditing(null);
+    setError('');
+    reload();
+  };
+
+  const handleDelete = async (id: string) => {
+    const result = await deleteMeetingTemplate(id);
+    if (result !== 'success') {
+      setError(result);
+      return;
+    }
+    reload();
+  };
+
+  const toggleAgent = (id: string) => {
+    if (!editing) return;
+    const agentIds = editing.agentIds.includes(id)
+      ? editing.agentIds.filter(a => a !== id)
+      : [...editing.agentIds, id];
+    setEditing({ ...editing, agentIds });
+  };
+
+  return (
+    <div className="fixed inset-0 z-50 flex items-center justify-center">
+      <div className="absolute inset-0 bg-black/60" onClick={onClose} />
+      <div className="relative w-[640px] max-w-[95vw] max-h-[80vh] flex flex-col fin-panel border fin-divider rounded-xl shadow-2xl">
+        {/* Header */}
+        <div className="flex items-center justify-between p-4 border-b fin-divider">
+          <div className="flex items-center gap-2">
+            <LayoutTemplate className="h-5 w-5 text-accent-2" />
+            <span className={`font-bold ${colors.isDark ? 'text-slate-100' : 'text-slate-800'}`}>会议模板</span>
+          </div>
+          <div className="flex items-center gap-1">
+            {!editing && (
+              <button onClick={() => { setEditing(emptyTemplate()); setError(''); }} className={iconBtn} title="新建模板">
+                <Plus className="h-4 w-4" />
+              </button>
+            )}
+            <button onClick={onClose} className={iconBtn}>
+              <X className="h-5 w-5" />
+            </button>
+          </div>
+        </div>
+
+        <div className="flex-1 overflow-y-auto fin-scrollbar p-4 space-y-3 text-left">
+          {error && <div className="text-xs text-red-400">{error}</div>}
+
+          {editing ? (
+            <div className="space-y-3 text-sm">
+              <input
+                value={editing.name}
+                onChange={e => setEditing({ ...editing, name: e.target.value })}
+                placeholder="模板名称，如：财报季前瞻"
+                className="w-full fin-input rounded-lg px-3 py-2 border fin-divider"
+              />
+              <div>
+                <textarea
+                  value={editing.query}
+                  onChange={e => setEditing({ ...editing, query: e.target.value })}
+                  rows={3}
+                  className="w-full fin-input rounded-lg px-3 py-2 border fin-divider resize-none"
+                />
+                <div className={`text-[10px] ${muted}`}>可用占位符：{'{stock}'} 股票名称、{'{code}'} 股票代码、{'{date}'} 当天日期</div>
+              </div>
+              <div className="flex gap-2">
+                {(Object.keys(MODE_LABELS) as MeetingTemplate['mode'][]).map(mode => (
+                  <button
+                    key={mode}
+                    onClick={() => setEditing({ ...editing, mode })}
+                    className={`px-3 py-1 rounded-lg text-xs border transition-colors ${
+                      editing.mode === mode ? 'bg-accent/20 text-accent-2 border-accent/30' : (colors.isDark ? 'text-slate-400 border-slate-700 hover:bg-slate-800' : 'text-slate-500 border-slate-300 hover:bg-slate-100')
+                    }`}
+                  >
+                    {MODE_LABELS[mode]}
+                  </button>
+                ))}
+              </div>
+              <div>
+                <div className={`text-xs mb-1 ${muted}`}>
+                  {editing.mode === 'smart' ? '必定参会的专家（不选则由小韭菜安排）' : '参与作答的专家'}
+                </div>
+                <div className="flex flex-wrap gap-2">
+                  {agents.map(agent => (
+                    <button
+                      key={agent.id}
+                      onClick={() => toggleAgent(agent.id)}
+                      className={`px-2 py-1 rounded-full text-xs border transition-colors ${
+                        editing.agentIds.includes(agent.id) ? 'bg-accent/20 text-accent-2 border-accent/30' : (colors.isDark ? 'text-slate-400 border-slate-700 hover:bg-slate-800' : 'text-slate-500 border-slate-300 hover:bg-slate-100')
+                      }`}
+                    >
+                      {agent.name}
+                    </button>
+                  ))}
+                </div>
+              </div>
+              <div className="flex justify-end gap-2">
+                <button
+                  onClick={() => { setEditing(null); setError(''); }}
+                  className={`px-4 py-1.5 text-sm rounded-lg transition-colors ${colors.isDark ? 'text-slate-400 hover:bg-slate-700/60' : 'text-slate-500 hover:bg-slate-200/60'}`}
+                >
+                  取消
+                </button>
+                <button onClick={handleSave} className="px-4 py-1.5 text-sm rounded-lg bg-accent text-white hover:opacity-90">
+                  保存
+                </button>
+              </div>
+            </div>
+          ) : loading ? (
+            <div className="flex justify-center py-8">
+              <Loader2 className="animate-spin h-5 w-5 text-accent-2" />
+            </div>
+          ) : templates.length === 0 ? (
+            <div className={`text-sm text-center py-8 ${muted}`}>还没有会议模板，点击右上角 + 新建</div>
+          ) : (
+            templates.map(tpl => (
+              <div key={tpl.id} className={`p-3 rounded-lg border ${colors.isDark ? 'border-slate-700/50 bg-slate-800/30' : 'border-slate-200 bg-slate-50'}`}>
+                <div className="flex items-center gap-2">
+                  <span className={`text-sm font-medium ${colors.isDark ? 'text-slate-100' : 'text-slate-800'}`}>{tpl.name}</span>
+                  <span className={`text-[10px] ${muted}`}>{MODE_LABELS[tpl.mode] || tpl.mode}</span>
+                  <div className="flex-1" />
+                  <button
+                    onClick={() => { onRun(tpl); onClose(); }}
+                    className="px-2 py-1 rounded text-xs flex items-center gap-1 bg-accent/20 text-accent-2 hover:bg-accent/30"
+                    title={`对 ${stockName} 开会`}
+                  >
+                    <Play size={12} />
+                    开会
+                  </button>
+                  <button onClick={() => { setEditing(tpl); setError(''); }} className={iconBtn} title="编辑">
+                    <Pencil size={12} />
+                  </button>
+                  <button onClick={() => handleDelete(tpl.id)} className={`${iconBtn} hover:text-red-400`} title="删除">
+                    <Trash2 size={12} />
+                  </button>
+                </div>
+                <div className={`text-xs mt-1 ${colors.isDark ? 'text-slate-300' : 'text-slate-600'}`}>{tpl.query}</div>
+                {tpl.agentIds.length > 0 && (
+                  <div className={`text-[10px] mt-1 ${muted}`}>{tpl.agentIds.map(agentName).join('、')}</div>
+                )}
+              </div>
+            ))
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
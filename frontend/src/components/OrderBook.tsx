@@ -25,6 +25,14 @@ export const OrderBook: React.FC<OrderBookProps> = ({ data }) => {
   const weibiBuy = totalSize > 0 ? (totalBidSize / totalSize * 100).toFixed(1) : '0';
   const weibiSell = totalSize > 0 ? (totalAskSize / totalSize * 100).toFixed(1) : '0';
 
+  // 后端计算的大单档位与疑似撤单
+  const largeBids = new Set(data?.metrics?.largeBids ?? []);
+  const largeAsks = new Set(data?.metrics?.largeAsks ?? []);
+  const withdrawn = data?.metrics?.withdrawn ?? [];
+  const sizeClass = (large: boolean) => large
+    ? 'font-bold text-amber-500'
+    : colors.isDark ? 'text-slate-300' : 'text-slate-600';
+
   return (
     <div className="h-full flex flex-row fin-panel border-l fin-divider overflow-hidden text-xs font-mono select-none">
        {/* 买盘 */}
@@ -41,7 +49,7 @@ export const OrderBook: React.FC<OrderBookProps> = ({ data }) => {
                     style={{ width: `${Math.min(bid.percent * 5, 100)}%` }}
                   />
                   <span className={`${cc.downClass} relative z-10`}>{bid.price.toFixed(2)}</span>
-                  <span className={`relative z-10 ${colors.isDark ? 'text-slate-300' : 'text-slate-600'}`}>{bid.size}</span>
+                  <span className={`relative z-10 ${sizeClass(largeBids.has(i))}`} title={largeBids.has(i) ? '大单' : undefined}>{bid.size}</span>
                 </div>
              ))}
           </div>
@@ -56,6 +64,14 @@ export const OrderBook: React.FC<OrderBookProps> = ({ data }) => {
              <span className="mx-1">/</span>
              <span className={cc.downClass}>{weibiSell}%</span>
            </div>
+           {withdrawn.length > 0 && (
+             <div
+               className="mt-1 text-[10px] text-amber-500"
+               title={withdrawn.map(w => `${w.side === 'bid' ? '买' : '卖'} ${w.price.toFixed(2)} 撤 ${w.size} 手`).join('\n')}
+             >
+               疑似撤单 {withdrawn.length}
+             </div>
+           )}
        </div>
 
        {/* 卖盘 */}
@@ -72,7 +88,7 @@ export const OrderBook: React.FC<OrderBookProps> = ({ data }) => {
                     style={{ width: `${Math.min(ask.percent * 5, 100)}%` }}
                   />
                   <span className={`${cc.upClass} relative z-10`}>{ask.price.toFixed(2)}</span>
-                  <span className={`relative z-10 ${colors.isDark ? 'text-slate-300' : 'text-slate-600'}`}>{ask.size}</span>
+                  <span className={`relative z-10 ${sizeClass(largeAsks.has(i))}`} title={largeAsks.has(i) ? '大单' : undefined}>{ask.size}</span>
                 </div>
             ))}
           </div>
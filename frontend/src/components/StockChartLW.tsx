@@ -16,7 +16,7 @@ import {
   SeriesType,
   MouseEventParams,
 } from 'lightweight-charts';
-import { KLineData, TimePeriod, Stock } from '../types';
+import { KLineData, TimePeriod, KLineAdjust, Stock, MINUTE_PERIODS } from '../types';
 import { useTheme } from '../contexts/ThemeContext';
 import { useCandleColor } from '../contexts/CandleColorContext';
 import { ResizeHandle } from './ResizeHandle';
@@ -34,12 +34,16 @@ import {
 const VOLUME_MIN = 20;
 const VOLUME_MAX = 200;
 const VOLUME_DEFAULT = 72;
+const LOAD_MORE_THRESHOLD = 10; // 可视区左侧剩余不足该根数时加载更早数据
 
 interface StockChartProps {
   data: KLineData[];
   updateMode: 'full' | 'incremental' | 'refresh';
   period: TimePeriod;
   onPeriodChange: (p: TimePeriod) => void;
+  adjust?: KLineAdjust;
+  onAdjustChange?: (a: KLineAdjust) => void;
+  onLoadMore?: () => void; // 滚动到最左侧时加载更早的数据
   stock?: Stock;
 }
 
@@ -71,6 +75,14 @@ function formatTimestamp(ts: number): string {
   return `${Y}-${M}-${D} ${h}:${m}`;
 }
 
+// 格式化资金净流入金额（元 → 万/亿，带正负号）
+function formatFlow(v: number): string {
+  const sign = v > 0 ? '+' : v < 0 ? '-' : '';
+  const abs = Math.abs(v);
+  if (abs >= 1e8) return sign + (abs / 1e8).toFixed(2) + '亿';
+  return sign + (abs / 1e4).toFixed(0) + '万';
+}
+
 // 格式化时间显示，统一为 YYYY-MM-DD HH:MM:SS
 function formatTimeDisplay(timeStr: string): string {
   if (timeStr.length > 10) {
@@ -79,7 +91,7 @@ function formatTimeDisplay(timeStr: string): string {
   return timeStr.slice(0, 10) + ' 00:00:00';
 }
 
-export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, period, onPeriodChange, stock }) => {
+export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, period, onPeriodChange, adjust, onAdjustChange, onLoadMore, stock }) => {
   const { colors } = useTheme();
   const cc = useCandleColor();
   const { config: indicatorConfig, updateIndicator } = useIndicator();
@@ -95,6 +107,9 @@ export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, peri
   const subSeriesRefs = useRef<ISeriesApi<SeriesType, Time>[]>([]);
   const seriesTypeRef = useRef<'line' | 'candle' | null>(null);
   const hasFittedRef = useRef(false);
+  const firstTimeRef = useRef<string | null>(null);
+  const onLoadMoreRef = useRef(onLoadMore);
+  onLoadMoreRef.current = onLoadMore;
 
   const [volumeHeight, setVolumeHeight] = useState(VOLUME_DEFAULT);
 
@@ -118,6 +133,7 @@ export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, peri
 
   const safeData = data || [];
   const isIntraday = period === '1m';
+  const isMinuteK = MINUTE_PERIODS.includes(period);
   const preClose = stock?.preClose || 0;
 
   const [hoverData, setHoverData] = React.useState<KLineData | null>(null);
@@ -135,11 +151,21 @@ export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, peri
 
   const periods: { id: TimePeriod; label: string }[] = [
     { id: '1m', label: '分时' },
+    { id: '5m', label: '5分' },
+    { id: '15m', label: '15分' },
+    { id: '30m', label: '30分' },
+    { id: '60m', label: '60分' },
     { id: '1d', label: '日K' },
     { id: '1w', label: '周K' },
     { id: '1mo', label: '月K' },
   ];
 
+  const adjusts: { id: KLineAdjust; label: string }[] = [
+    { id: 'none', label: '不复权' },
+    { id: 'qfq', label: '前复权' },
+    { id: 'hfq', label: '后复权' },
+  ];
+
   const getPriceColor = useCallback((price: number) => {
     if (preClose <= 0) return colors.isDark ? 'text-slate-100' : 'text-slate-700';
     if (price > preClose) return cc.upClass;
@@ -221,9 +247,11 @@ export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, peri
     chartRef.current = chart;
     volumeChartRef.current = volumeChart;
 
-    // 同步时间轴
+    // 同步时间轴；接近最左侧时触发向前加载
     chart.timeScale().subscribeVisibleLogicalRangeChange(range => {
-      if (range) volumeChart.timeScale().setVisibleLogicalRange(range);
+      if (!range) return;
+      volumeChart.timeScale().setVisibleLogicalRange(range);
+      if (range.from < LOAD_MORE_THRESHOLD) onLoadMoreRef.current?.();
     });
     volumeChart.timeScale().subscribeVisibleLogicalRangeChange(range => {
       if (range) chart.timeScale().setVisibleLogicalRange(range);
@@ -306,16 +334,16 @@ export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, peri
     if (!chart || !volumeChart) return;
 
     chart.applyOptions({
-      timeScale: { timeVisible: isIntraday, secondsVisible: false },
+      timeScale: { timeVisible: isIntraday || isMinuteK, secondsVisible: false },
       handleScroll: !isIntraday,
       handleScale: !isIntraday,
     });
     volumeChart.applyOptions({
-      timeScale: { timeVisible: isIntraday, secondsVisible: false },
+      timeScale: { timeVisible: isIntraday || isMinuteK, secondsVisible: false },
       handleScroll: !isIntraday,
       handleScale: !isIntraday,
     });
-  }, [isIntraday]);
+  }, [isIntraday, isMinuteK]);
 
   // 切换周期时重置 fit 状态，避免沿用旧 X 轴可视范围
   useEffect(() => {
@@ -352,6 +380,14 @@ export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, peri
         color: d.close >= d.open ? chartColors.upColor + '99' : chartColors.downColor + '99',
       }));
       volumeSeriesRef.current.setData(volData);
+      // 分时叠加当日累计资金净流入（独立坐标，不影响成交量柱）
+      if (chartData.some(d => d.netInflow)) {
+        const flowSeries = volumeChart.addSeries(LineSeries, {
+          color: '#f59e0b', lineWidth: 1, priceScaleId: 'flow', priceLineVisible: false, lastValueVisible: false, title: '净流入',
+        });
+        flowSeries.setData(chartData.map(d => ({ time: parseTime(d.time), value: d.netInflow || 0 })));
+        subSeriesRefs.current = [flowSeries];
+      }
     } else if (type === 'macd') {
       const { dif, dea, histogram } = calculateMACD(
         chartData, indicatorConfig.macd.fast, indicatorConfig.macd.slow, indicatorConfig.macd.signal,
@@ -530,6 +566,17 @@ export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, peri
     const subChartTypeToRender: SubChartType = isIntraday ? 'volume' : subChartTypeRef.current;
     renderSubChart(subChartTypeToRender, safeData);
 
+    // 向前加载了更早的数据：平移可视范围，保持用户当前看到的K线不动
+    const prevFirst = firstTimeRef.current;
+    firstTimeRef.current = safeData[0].time;
+    if (updateMode === 'refresh' && prevFirst && safeData[0].time < prevFirst) {
+      const prepended = safeData.findIndex(d => d.time >= prevFirst);
+      const range = chart.timeScale().getVisibleLogicalRange();
+      if (range && prepended > 0) {
+        chart.timeScale().setVisibleLogicalRange({ from: range.from + prepended, to: range.to + prepended });
+      }
+    }
+
     // full: 用户主动切换股票/周期 → fitContent；refresh: 定时刷新 → 保留缩放；增量仅首次 fit
     const shouldFit = safeData.length > 0 && (
       updateMode === 'full' || (!hasFittedRef.current && safeData.length > 1)
@@ -641,6 +688,7 @@ export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, peri
   const totalVolume = useMemo(() => safeData.reduce((sum, d) => sum + d.volume, 0), [safeData]);
   const currentPrice = stock?.price || lastData?.close || 0;
   const currentAvg = lastData?.avg || 0;
+  const currentNetInflow = lastData?.netInflow || 0;
 
   // ========== 渲染（图表容器始终保留在 DOM 中，避免销毁重建） ==========
   const hasData = safeData.length > 0;
@@ -672,6 +720,18 @@ export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, peri
               {p.label}
             </button>
           ))}
+          {!isIntraday && !isMinuteK && adjust && onAdjustChange && (
+            <select
+              value={adjust}
+              onChange={(e) => onAdjustChange(e.target.value as KLineAdjust)}
+              className={`ml-2 text-xs px-1 py-0.5 rounded border bg-transparent ${colors.isDark ? 'border-slate-700 text-slate-300' : 'border-slate-300 text-slate-600'}`}
+              title="复权方式"
+            >
+              {adjusts.map((a) => (
+                <option key={a.id} value={a.id}>{a.label}</option>
+              ))}
+            </select>
+          )}
           {!isIntraday && (
             <div className={`flex items-center gap-2 ml-3 pl-3 border-l ${colors.isDark ? 'border-slate-700' : 'border-slate-300'}`}>
               <div className={`flex items-center gap-1 text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
@@ -724,6 +784,7 @@ export const StockChartLW: React.FC<StockChartProps> = ({ data, updateMode, peri
           </div>
           <div className="flex gap-4">
             <span className={colors.isDark ? 'text-slate-500' : 'text-slate-400'}>均价: <span className="text-yellow-500">{currentAvg.toFixed(2)}</span></span>
+            <span className={colors.isDark ? 'text-slate-500' : 'text-slate-400'}>净流入: <span className={currentNetInflow >= 0 ? cc.upClass : cc.downClass}>{formatFlow(currentNetInflow)}</span></span>
             <span className={colors.isDark ? 'text-slate-500' : 'text-slate-400'}>总量: <span className={colors.isDark ? 'text-slate-300' : 'text-slate-600'}>{(totalVolume / 100).toFixed(0)}手</span></span>
           </div>
         </div>
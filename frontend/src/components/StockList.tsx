@@ -1,7 +1,7 @@
 import React, { useState, useEffect, useRef } from 'react';
-import { Stock, MarketIndex } from '../types';
+import { Stock, MarketIndex, WatchlistGroup } from '../types';
 import { searchStocks, StockSearchResult } from '../services/stockService';
-import { TrendingUp, TrendingDown, Search, X } from 'lucide-react';
+import { TrendingUp, TrendingDown, Search, X, FolderPlus, Check, Plus } from 'lucide-react';
 import { MarketIndices } from './MarketIndices';
 import { useTheme } from '../contexts/ThemeContext';
 import { useCandleColor } from '../contexts/CandleColorContext';
@@ -13,6 +13,12 @@ interface StockListProps {
   onAddStock: (stock: Stock) => void;
   onRemoveStock?: (symbol: string) => void;
   marketIndices?: MarketIndex[];
+  groups?: WatchlistGroup[];
+  activeGroup?: string; // 当前分组 ID，空为全部
+  onGroupChange?: (groupId: string) => void;
+  onAddGroup?: (name: string) => void;
+  onToggleGroup?: (groupId: string, symbol: string, inGroup: boolean) => void;
+  onReorder?: (symbols: string[]) => void; // 当前分组拖拽排序后的股票代码
 }
 
 export const StockList: React.FC<StockListProps> = ({
@@ -21,7 +27,13 @@ export const StockList: React.FC<StockListProps> = ({
   onSelect,
   onAddStock,
   onRemoveStock,
-  marketIndices
+  marketIndices,
+  groups = [],
+  activeGroup = '',
+  onGroupChange,
+  onAddGroup,
+  onToggleGroup,
+  onReorder,
 }) => {
   const { colors } = useTheme();
   const cc = useCandleColor();
@@ -31,6 +43,30 @@ export const StockList: React.FC<StockListProps> = ({
   const [isSearching, setIsSearching] = useState(false);
   const searchRef = useRef<HTMLDivElement>(null);
   const debounceRef = useRef<ReturnType<typeof setTimeout>>();
+  const [dragSymbol, setDragSymbol] = useState<string | null>(null);
+  const [groupMenuSymbol, setGroupMenuSymbol] = useState<string | null>(null);
+  const [newGroupName, setNewGroupName] = useState<string | null>(null);
+
+  // 当前分组内的股票（分组按自身顺序排列）
+  const currentGroup = groups.find(g => g.id === activeGroup);
+  const visibleStocks = currentGroup
+    ? currentGroup.symbols.map(sym => stocks.find(s => s.symbol === sym)).filter((s): s is Stock => !!s)
+    : stocks;
+
+  // 拖拽放下：把拖动的股票移到目标位置
+  const handleDrop = (targetSymbol: string) => {
+    if (!dragSymbol || dragSymbol === targetSymbol) return;
+    const order = visibleStocks.map(s => s.symbol).filter(sym => sym !== dragSymbol);
+    order.splice(order.indexOf(targetSymbol), 0, dragSymbol);
+    onReorder?.(order);
+    setDragSymbol(null);
+  };
+
+  const submitNewGroup = () => {
+    const name = newGroupName?.trim();
+    if (name) onAddGroup?.(name);
+    setNewGroupName(null);
+  };
 
   // 点击外部关闭下拉
   useEffect(() => {
@@ -144,8 +180,49 @@ export const StockList: React.FC<StockListProps> = ({
         </div>
       </div>
 
+      {/* 分组切换 */}
+      {groups.length > 0 && (
+        <div className="flex items-center gap-1 px-3 py-2 border-b fin-divider-soft overflow-x-auto fin-scrollbar">
+          {[{ id: '', name: '全部' }, ...groups].map(g => (
+            <button
+              key={g.id || 'all'}
+              onClick={() => onGroupChange?.(g.id)}
+              className={`shrink-0 px-2.5 py-1 rounded text-xs transition-colors ${
+                activeGroup === g.id
+                  ? 'bg-accent/20 text-accent-2'
+                  : (colors.isDark ? 'text-slate-400 hover:text-slate-200' : 'text-slate-500 hover:text-slate-700')
+              }`}
+            >
+              {g.name}
+            </button>
+          ))}
+          {onAddGroup && (newGroupName === null ? (
+            <button
+              onClick={() => setNewGroupName('')}
+              title="新建分组"
+              className={`shrink-0 p-1 rounded ${colors.isDark ? 'text-slate-500 hover:text-slate-200' : 'text-slate-400 hover:text-slate-700'}`}
+            >
+              <Plus size={12} />
+            </button>
+          ) : (
+            <input
+              autoFocus
+              value={newGroupName}
+              onChange={(e) => setNewGroupName(e.target.value)}
+              onBlur={submitNewGroup}
+              onKeyDown={(e) => {
+                if (e.key === 'Enter') submitNewGroup();
+                if (e.key === 'Escape') setNewGroupName(null);
+              }}
+              placeholder="分组名"
+              className="w-16 shrink-0 fin-input rounded px-1.5 py-0.5 text-xs"
+            />
+          ))}
+        </div>
+      )}
+
       <div className="flex-1 overflow-y-auto fin-scrollbar">
-        {stocks.map((stock) => {
+        {visibleStocks.map((stock) => {
           const isSelected = stock.symbol === selectedSymbol;
           const isPositive = stock.change >= 0;
 
@@ -153,7 +230,12 @@ export const StockList: React.FC<StockListProps> = ({
             <div
               key={stock.symbol}
               onClick={() => onSelect(stock.symbol)}
-              className={`group p-4 border-b fin-divider-soft cursor-pointer transition-colors ${colors.isDark ? 'hover:bg-slate-800/40' : 'hover:bg-slate-100/60'} ${isSelected ? (colors.isDark ? 'bg-slate-800/40' : 'bg-slate-100/60') + ' border-l-4 border-l-accent' : 'border-l-4 border-l-transparent'}`}
+              draggable={!!onReorder}
+              onDragStart={() => setDragSymbol(stock.symbol)}
+              onDragOver={(e) => e.preventDefault()}
+              onDrop={() => handleDrop(stock.symbol)}
+              onDragEnd={() => setDragSymbol(null)}
+              className={`group relative p-4 ${dragSymbol === stock.symbol ? 'opacity-50' : ''} border-b fin-divider-soft cursor-pointer transition-colors ${colors.isDark ? 'hover:bg-slate-800/40' : 'hover:bg-slate-100/60'} ${isSelected ? (colors.isDark ? 'bg-slate-800/40' : 'bg-slate-100/60') + ' border-l-4 border-l-accent' : 'border-l-4 border-l-transparent'}`}
             >
               <div className="flex justify-between items-start mb-1">
                 <div className="flex-1 min-w-0">
@@ -170,7 +252,40 @@ export const StockList: React.FC<StockListProps> = ({
                         <X size={14} />
                       </button>
                     )}
+                    {onToggleGroup && groups.length > 0 && (
+                      <button
+                        onClick={(e) => {
+                          e.stopPropagation();
+                          setGroupMenuSymbol(groupMenuSymbol === stock.symbol ? null : stock.symbol);
+                        }}
+                        title="设置分组"
+                        className={`opacity-0 group-hover:opacity-100 p-0.5 rounded hover:bg-accent/20 hover:text-accent-2 transition-all ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}
+                      >
+                        <FolderPlus size={14} />
+                      </button>
+                    )}
                   </div>
+                  {groupMenuSymbol === stock.symbol && (
+                    <div
+                      onClick={(e) => e.stopPropagation()}
+                      onMouseLeave={() => setGroupMenuSymbol(null)}
+                      className={`absolute left-4 top-10 z-40 min-w-[96px] rounded-lg shadow-xl py-1 text-left ${colors.isDark ? 'bg-slate-800 border border-slate-600' : 'bg-white border border-slate-300'}`}
+                    >
+                      {groups.map(g => {
+                        const inGroup = g.symbols.includes(stock.symbol);
+                        return (
+                          <div
+                            key={g.id}
+                            onClick={() => onToggleGroup(g.id, stock.symbol, !inGroup)}
+                            className={`flex items-center justify-between gap-3 px-3 py-1.5 text-xs cursor-pointer ${colors.isDark ? 'text-slate-200 hover:bg-slate-700' : 'text-slate-700 hover:bg-slate-100'}`}
+                          >
+                            {g.name}
+                            {inGroup && <Check size={12} className="text-accent-2" />}
+                          </div>
+                        );
+                      })}
+                    </div>
+                  )}
                   <div className={`text-xs font-mono truncate text-left ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>{stock.symbol}</div>
                 </div>
                 <div className="text-right">
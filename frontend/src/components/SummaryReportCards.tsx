@@ -0,0 +1,50 @@
+import React from 'react';
+import { Target, Scale, BarChart3, ShieldAlert, ListChecks } from 'lucide-react';
+import { NodeRenderer } from 'markstream-react';
+import { SummaryReport } from '../services/sessionService';
+import { useTheme } from '../contexts/ThemeContext';
+
+interface SummaryReportCardsProps {
+  report: SummaryReport;
+}
+
+// 会议总结的分节卡片：结论置顶，其余分节两列排布
+export const SummaryReportCards: React.FC<SummaryReportCardsProps> = ({ report }) => {
+  const { colors } = useTheme();
+
+  const sections = [
+    { key: 'divergence', title: '多空分歧', icon: Scale, content: report.divergence, tone: 'text-sky-400' },
+    { key: 'keyData', title: '关键数据', icon: BarChart3, content: report.keyData, tone: 'text-violet-400' },
+    { key: 'risks', title: '风险提示', icon: ShieldAlert, content: report.risks, tone: 'text-red-400' },
+    { key: 'actions', title: '操作建议', icon: ListChecks, content: report.actions, tone: 'text-green-400' },
+  ].filter(s => s.content);
+
+  const card = `p-2.5 rounded-xl border ${colors.isDark ? 'bg-slate-900/40 border-amber-500/20' : 'bg-white/70 border-amber-400/30'}`;
+
+  return (
+    <div className="space-y-2">
+      <div className={card}>
+        <div className="flex items-center gap-1 mb-1 text-xs font-bold text-amber-400">
+          <Target size={12} />
+          结论
+        </div>
+        <NodeRenderer content={report.conclusion} />
+      </div>
+      {sections.length > 0 && (
+        <div className="grid grid-cols-1 md:grid-cols-2 gap-2">
+          {sections.map(({ key, title, icon: Icon, content, tone }) => (
+            <div key={key} className={card}>
+              <div className={`flex items-center gap-1 mb-1 text-xs font-bold ${tone}`}>
+                <Icon size={12} />
+                {title}
+              </div>
+              <div className="text-xs">
+                <NodeRenderer content={content!} />
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+};
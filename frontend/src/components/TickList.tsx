@@ -0,0 +1,44 @@
+import React from 'react';
+import { TickTrade } from '../types';
+import { useTheme } from '../contexts/ThemeContext';
+import { useCandleColor } from '../contexts/CandleColorContext';
+
+interface TickListProps {
+  ticks: TickTrade[];
+}
+
+// 逐笔成交面板（最新成交在最上方）
+export const TickList: React.FC<TickListProps> = ({ ticks }) => {
+  const { colors } = useTheme();
+  const cc = useCandleColor();
+
+  const directionClass = (d: TickTrade['direction']) => {
+    if (d === 'buy') return cc.upClass;
+    if (d === 'sell') return cc.downClass;
+    return colors.isDark ? 'text-slate-400' : 'text-slate-500';
+  };
+
+  return (
+    <div className="h-full flex flex-col fin-panel border-l fin-divider overflow-hidden text-xs font-mono select-none">
+      <div className={`p-2 border-b fin-divider font-bold flex justify-between fin-panel-strong ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>
+        <span>逐笔成交</span>
+        <span className="text-[10px] font-normal opacity-70">手</span>
+      </div>
+      <div className="flex-1 overflow-y-auto fin-scrollbar">
+        {ticks.length === 0 && (
+          <div className={`p-3 text-center ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>暂无成交</div>
+        )}
+        {[...ticks].reverse().map((t, i) => (
+          <div key={`${t.time}-${i}`} className="flex justify-between px-2 py-0.5">
+            <span className={colors.isDark ? 'text-slate-500' : 'text-slate-400'}>{t.time}</span>
+            <span className={directionClass(t.direction)}>{t.price.toFixed(2)}</span>
+            <span className={directionClass(t.direction)}>
+              {Math.round(t.volume / 100)}
+              <span className="ml-1 opacity-70">{t.direction === 'buy' ? 'B' : t.direction === 'sell' ? 'S' : ' '}</span>
+            </span>
+          </div>
+        ))}
+      </div>
+    </div>
+  );
+};
@@ -0,0 +1,66 @@
+import React, { useState } from 'react';
+import { ChevronDown, ChevronRight, Database } from 'lucide-react';
+import { ToolCallRecord } from '../services/sessionService';
+import { useTheme } from '../contexts/ThemeContext';
+
+interface ToolCallSourcesProps {
+  calls: ToolCallRecord[];
+}
+
+// 将回答中的引用标注 [n] 转为带悬浮提示的 Markdown 链接，提示内容为对应的工具调用
+export const linkCitations = (content: string, calls?: ToolCallRecord[]): string => {
+  if (!calls || calls.length === 0) return content;
+  const byCitation = new Map(calls.filter(c => c.citation > 0).map(c => [c.citation, c]));
+  return content.replace(/\[(\d{1,2})\](?!\()/g, (marker, n) => {
+    const call = byCitation.get(Number(n));
+    if (!call) return marker;
+    const title = `${call.tool} ${call.args || ''}`.trim().replace(/["\\]/g, "'");
+    return `[[${n}]](#cite-${n} "${title}")`;
+  });
+};
+
+// 专家发言下方的“数据来源”：可展开查看每次工具调用的参数、结果与耗时
+export const ToolCallSources: React.FC<ToolCallSourcesProps> = ({ calls }) => {
+  const { colors } = useTheme();
+  const [open, setOpen] = useState(false);
+  const [expanded, setExpanded] = useState<number | null>(null);
+
+  const muted = colors.isDark ? 'text-slate-500' : 'text-slate-400';
+
+  return (
+    <div className="mt-1 text-xs">
+      <button
+        onClick={() => setOpen(!open)}
+        className={`flex items-center gap-1 transition-colors ${muted} ${colors.isDark ? 'hover:text-slate-300' : 'hover:text-slate-600'}`}
+      >
+        {open ? <ChevronDown size={12} /> : <ChevronRight size={12} />}
+        <Database size={12} />
+        数据来源（{calls.length}次工具调用）
+      </button>
+      {open && (
+        <div className={`mt-1 rounded-lg border divide-y ${colors.isDark ? 'border-slate-700/50 divide-slate-700/50 bg-slate-900/40' : 'border-slate-200 divide-slate-200 bg-slate-50'}`}>
+          {calls.map((c, i) => (
+            <div key={i} className="px-2 py-1.5">
+              <button
+                onClick={() => setExpanded(expanded === i ? null : i)}
+                className="w-full flex items-center gap-2 text-left"
+              >
+                {c.citation > 0 && (
+                  <span className={`shrink-0 font-mono ${c.cited ? 'text-amber-400' : muted}`} title={c.cited ? '回答中已引用' : '未被引用'}>[{c.citation}]</span>
+                )}
+                <span className="font-mono text-accent-2">{c.tool}</span>
+                <span className={`flex-1 truncate font-mono ${muted}`}>{c.args}</span>
+                <span className={`shrink-0 ${muted}`}>{c.result ? `${c.duration}ms` : '无结果'}</span>
+              </button>
+              {expanded === i && (
+                <pre className={`mt-1 max-h-48 overflow-auto fin-scrollbar whitespace-pre-wrap break-all font-mono text-[11px] ${colors.isDark ? 'text-slate-400' : 'text-slate-600'}`}>
+                  {c.result || '工具未返回结果'}
+                </pre>
+              )}
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+};
@@ -0,0 +1,90 @@
+import React, { useState, useEffect } from 'react';
+import { Lock, Loader2, X } from 'lucide-react';
+import { WindowClose } from '../../wailsjs/go/main/App';
+import { getEncryptionStatus, unlockData } from '../services/configService';
+import { useTheme } from '../contexts/ThemeContext';
+import logo from '../assets/images/logo.png';
+
+// 数据已加密时先输入密码解锁，解锁后再加载主界面
+export const UnlockGate: React.FC<{ children: React.ReactNode }> = ({ children }) => {
+  const { colors } = useTheme();
+  const [locked, setLocked] = useState<boolean | null>(null);
+  const [passphrase, setPassphrase] = useState('');
+  const [unlocking, setUnlocking] = useState(false);
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    getEncryptionStatus()
+      .then(status => setLocked(status.locked))
+      .catch(() => setLocked(false));
+  }, []);
+
+  const handleUnlock = async () => {
+    if (!passphrase || unlocking) return;
+    setUnlocking(true);
+    setError('');
+    try {
+      const result = await unlockData(passphrase);
+      if (result === 'success') {
+        setLocked(false);
+      } else {
+        setError(result);
+      }
+    } finally {
+      setUnlocking(false);
+    }
+  };
+
+  if (locked === null) return <div className="h-screen w-screen flex items-center justify-center fin-app text-white">加载中...</div>;
+  if (!locked) return <>{children}</>;
+
+  return (
+    <div className="h-screen w-screen flex flex-col items-center justify-center fin-app relative" style={{ '--wails-draggable': 'drag' } as React.CSSProperties}>
+      <div className="absolute top-3 right-3" style={{ '--wails-draggable': 'no-drag' } as React.CSSProperties}>
+        <button
+          onClick={() => WindowClose()}
+          className={`p-1.5 rounded hover:bg-red-500/80 transition-colors ${colors.isDark ? 'text-slate-400 hover:text-white' : 'text-slate-500 hover:text-white'}`}
+          title="关闭"
+        >
+          <X className="h-4 w-4" />
+        </button>
+      </div>
+
+      <div className="flex items-center gap-3 mb-8">
+        <img src={logo} alt="Logo" className="h-14 w-14 rounded-lg" />
+        <div>
+          <h1 className={`text-3xl font-bold ${colors.isDark ? 'text-white' : 'text-slate-800'}`}>
+            韭菜盘 <span className="text-accent-2">AI</span>
+          </h1>
+          <p className={`text-sm ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>数据已加密，请输入密码解锁</p>
+        </div>
+      </div>
+
+      <div className="w-96" style={{ '--wails-draggable': 'no-drag' } as React.CSSProperties}>
+        <div className="flex gap-2">
+          <div className="relative flex-1">
+            <Lock className={`absolute left-3 top-2.5 h-4 w-4 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`} />
+            <input
+              type="password"
+              autoFocus
+              value={passphrase}
+              onChange={(e) => setPassphrase(e.target.value)}
+              onKeyDown={(e) => e.key === 'Enter' && handleUnlock()}
+              placeholder="密码"
+              className={`w-full fin-input rounded-lg pl-9 pr-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+            />
+          </div>
+          <button
+            onClick={handleUnlock}
+            disabled={!passphrase || unlocking}
+            className="flex items-center gap-1 px-4 py-2 bg-gradient-to-br from-[var(--accent)] to-[var(--accent-2)] text-white rounded-lg text-sm disabled:opacity-50"
+          >
+            {unlocking && <Loader2 className="h-4 w-4 animate-spin" />}
+            解锁
+          </button>
+        </div>
+        {error && <p className="text-xs mt-2 text-red-400">{error}</p>}
+      </div>
+    </div>
+  );
+};
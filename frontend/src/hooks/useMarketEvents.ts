@@ -1,32 +1,68 @@
 import { useEffect, useCallback, useRef } from 'react';
 import { EventsOn, EventsOff, EventsEmit } from '@wailsjs/runtime/runtime';
 import { NotifyFrontendReady } from '../../wailsjs/go/main/App';
-import { Stock, OrderBook, Telegraph, MarketIndex, KLineData } from '../types';
+import { models } from '../../wailsjs/go/models';
+import { Stock, OrderBook, OrderBookItem, OrderBookMetrics, Telegraph, MarketIndex, KLineData, TickTrade, KLineAdjust } from '../types';
 
 // K线推送数据结构
 interface KLineUpdateData {
   code: string;
   period: string;
+  adjust?: string; // 复权方式，空为不复权
   data: KLineData[];
   incremental?: boolean; // 是否增量推送
 }
 
+// 逐笔成交推送数据结构
+export interface TicksUpdateData {
+  code: string;
+  data: TickTrade[];
+  incremental: boolean; // 是否仅包含新增成交
+}
+
+// 盘口增量：仅包含发生变化的档位
+export interface OrderBookDelta {
+  code: string;
+  bids: { index: number; item: OrderBookItem }[] | null;
+  asks: { index: number; item: OrderBookItem }[] | null;
+  metrics?: OrderBookMetrics; // 变化后整份盘口的衍生指标
+}
+
 // 事件名称常量，与后端保持一致
 const EVENT_STOCK_UPDATE = 'market:stock:update';
 const EVENT_ORDERBOOK_UPDATE = 'market:orderbook:update';
+const EVENT_ORDERBOOK_DELTA = 'market:orderbook:delta';
 const EVENT_TELEGRAPH_UPDATE = 'market:telegraph:update';
 const EVENT_MARKET_INDICES_UPDATE = 'market:indices:update';
 const EVENT_MARKET_SUBSCRIBE = 'market:subscribe';
 const EVENT_ORDERBOOK_SUBSCRIBE = 'market:orderbook:subscribe';
 const EVENT_KLINE_UPDATE = 'market:kline:update';
+const EVENT_TICKS_UPDATE = 'market:ticks:update';
 const EVENT_KLINE_SUBSCRIBE = 'market:kline:subscribe';
+const EVENT_GROUP_SUBSCRIBE = 'market:group:subscribe';
+const EVENT_LIMIT_BOARD_UPDATE = 'market:limitboard:update';
+const EVENT_MARKET_BREADTH_UPDATE = 'market:breadth:update';
+const EVENT_WINDOW_VISIBILITY = 'market:visibility';
+
+// K线订阅变更（支持多图同时订阅，throttleMs 为该订阅的最小推送间隔）
+export interface KLineSubscriptionOp {
+  action: 'add' | 'remove';
+  code: string;
+  period: string;
+  adjust?: KLineAdjust;
+  throttleMs?: number;
+}
 
 interface UseMarketEventsOptions {
   onStockUpdate?: (stocks: Stock[]) => void;
   onOrderBookUpdate?: (orderBook: OrderBook) => void;
+  onOrderBookDelta?: (delta: OrderBookDelta) => void;
   onTelegraphUpdate?: (telegraph: Telegraph) => void;
   onMarketIndicesUpdate?: (indices: MarketIndex[]) => void;
   onKLineUpdate?: (data: KLineUpdateData) => void;
+  onTicksUpdate?: (data: TicksUpdateData) => void;
+  onLimitBoardUpdate?: (board: models.LimitBoard) => void;
+  onMarketBreadthUpdate?: (breadth: models.MarketBreadth) => void;
 }
 
 /**
@@ -34,23 +70,33 @@ interface UseMarketEventsOptions {
  * 监听后端推送的实时市场数据
  */
 export function useMarketEvents(options: UseMarketEventsOptions) {
-  const { onStockUpdate, onOrderBookUpdate, onTelegraphUpdate, onMarketIndicesUpdate, onKLineUpdate } = options;
+  const { onStockUpdate, onOrderBookUpdate, onOrderBookDelta, onTelegraphUpdate, onMarketIndicesUpdate, onKLineUpdate, onTicksUpdate, onLimitBoardUpdate, onMarketBreadthUpdate } = options;
 
   // 使用 ref 保存回调，避免重复注册
   const stockCallbackRef = useRef(onStockUpdate);
   const orderBookCallbackRef = useRef(onOrderBookUpdate);
+  const orderBookDeltaCallbackRef = useRef(onOrderBookDelta);
   const telegraphCallbackRef = useRef(onTelegraphUpdate);
   const marketIndicesCallbackRef = useRef(onMarketIndicesUpdate);
   const klineCallbackRef = useRef(onKLineUpdate);
+  const ticksCallbackRef = useRef(onTicksUpdate);
+  const limitBoardCallbackRef = useRef(onLimitBoardUpdate);
+  const breadthCallbackRef = useRef(onMarketBreadthUpdate);
+  // 主图当前订阅，切换股票/周期时先取消旧订阅
+  const mainKLineRef = useRef<{ code: string; period: string; adjust: KLineAdjust } | null>(null);
 
   // 更新 ref
   useEffect(() => {
     stockCallbackRef.current = onStockUpdate;
     orderBookCallbackRef.current = onOrderBookUpdate;
+    orderBookDeltaCallbackRef.current = onOrderBookDelta;
     telegraphCallbackRef.current = onTelegraphUpdate;
     marketIndicesCallbackRef.current = onMarketIndicesUpdate;
     klineCallbackRef.current = onKLineUpdate;
-  }, [onStockUpdate, onOrderBookUpdate, onTelegraphUpdate, onMarketIndicesUpdate, onKLineUpdate]);
+    ticksCallbackRef.current = onTicksUpdate;
+    limitBoardCallbackRef.current = onLimitBoardUpdate;
+    breadthCallbackRef.current = onMarketBreadthUpdate;
+  }, [onStockUpdate, onOrderBookUpdate, onOrderBookDelta, onTelegraphUpdate, onMarketIndicesUpdate, onKLineUpdate, onTicksUpdate, onLimitBoardUpdate, onMarketBreadthUpdate]);
 
   // 注册事件监听
   useEffect(() => {
@@ -64,6 +110,11 @@ export function useMarketEvents(options: UseMarketEventsOptions) {
       orderBookCallbackRef.current?.(orderBook);
     });
 
+    // 监听盘口增量更新
+    EventsOn(EVENT_ORDERBOOK_DELTA, (delta: OrderBookDelta) => {
+      orderBookDeltaCallbackRef.current?.(delta);
+    });
+
     // 监听快讯数据更新
     EventsOn(EVENT_TELEGRAPH_UPDATE, (telegraph: Telegraph) => {
       telegraphCallbackRef.current?.(telegraph);
@@ -79,6 +130,21 @@ export function useMarketEvents(options: UseMarketEventsOptions) {
       klineCallbackRef.current?.(data);
     });
 
+    // 监听逐笔成交更新
+    EventsOn(EVENT_TICKS_UPDATE, (data: TicksUpdateData) => {
+      ticksCallbackRef.current?.(data);
+    });
+
+    // 监听涨跌停池更新
+    EventsOn(EVENT_LIMIT_BOARD_UPDATE, (board: models.LimitBoard) => {
+      limitBoardCallbackRef.current?.(board);
+    });
+
+    // 监听市场宽度与板块排行更新
+    EventsOn(EVENT_MARKET_BREADTH_UPDATE, (breadth: models.MarketBreadth) => {
+      breadthCallbackRef.current?.(breadth);
+    });
+
     // 通知后端前端已准备好，循环调用直到成功
     const notifyReady = async () => {
       let success = false;
@@ -93,13 +159,26 @@ export function useMarketEvents(options: UseMarketEventsOptions) {
     };
     notifyReady();
 
+    // 窗口隐藏/最小化时通知后端暂停高频推送，恢复可见或获得焦点时通知补推（后端只处理状态变化）
+    const reportVisibility = () => {
+      EventsEmit(EVENT_WINDOW_VISIBILITY, document.visibilityState === 'visible');
+    };
+    document.addEventListener('visibilitychange', reportVisibility);
+    window.addEventListener('focus', reportVisibility);
+
     // 清理函数
     return () => {
+      document.removeEventListener('visibilitychange', reportVisibility);
+      window.removeEventListener('focus', reportVisibility);
       EventsOff(EVENT_STOCK_UPDATE);
       EventsOff(EVENT_ORDERBOOK_UPDATE);
+      EventsOff(EVENT_ORDERBOOK_DELTA);
       EventsOff(EVENT_TELEGRAPH_UPDATE);
       EventsOff(EVENT_MARKET_INDICES_UPDATE);
       EventsOff(EVENT_KLINE_UPDATE);
+      EventsOff(EVENT_TICKS_UPDATE);
+      EventsOff(EVENT_LIMIT_BOARD_UPDATE);
+      EventsOff(EVENT_MARKET_BREADTH_UPDATE);
     };
   }, []);
 
@@ -113,10 +192,30 @@ export function useMarketEvents(options: UseMarketEventsOptions) {
     EventsEmit(EVENT_ORDERBOOK_SUBSCRIBE, code);
   }, []);
 
-  // 订阅K线（指定股票代码和周期）
-  const subscribeKLine = useCallback((code: string, period: string) => {
-    EventsEmit(EVENT_KLINE_SUBSCRIBE, code, period);
+  // 批量变更K线订阅（多图网格视图）
+  const updateKLineSubscriptions = useCallback((ops: KLineSubscriptionOp[]) => {
+    if (ops.length > 0) {
+      EventsEmit(EVENT_KLINE_SUBSCRIBE, ops);
+    }
+  }, []);
+
+  // 订阅主图K线（指定股票代码、周期和复权方式，自动取消上一次的主图订阅）
+  const subscribeKLine = useCallback((code: string, period: string, adjust: KLineAdjust = 'none') => {
+    const prev = mainKLineRef.current;
+    if (prev && prev.code === code && prev.period === period && prev.adjust === adjust) return;
+    const ops: KLineSubscriptionOp[] = [];
+    if (prev) {
+      ops.push({ action: 'remove', code: prev.code, period: prev.period, adjust: prev.adjust });
+    }
+    ops.push({ action: 'add', code, period, adjust });
+    mainKLineRef.current = { code, period, adjust };
+    updateKLineSubscriptions(ops);
+  }, [updateKLineSubscriptions]);
+
+  // 订阅自选股分组（后端只推送当前可见分组，空为全部）
+  const subscribeGroup = useCallback((groupId: string) => {
+    EventsEmit(EVENT_GROUP_SUBSCRIBE, groupId);
   }, []);
 
-  return { subscribe, subscribeOrderBook, subscribeKLine };
+  return { subscribe, subscribeOrderBook, subscribeKLine, updateKLineSubscriptions, subscribeGroup };
 }
@@ -0,0 +1,55 @@
+import { useState, useRef, useEffect, useCallback } from 'react';
+import { synthesizeSpeech } from '../services/sessionService';
+
+interface UseSpeechReturn {
+  speakingId: string | null; // 正在朗读的消息 ID
+  loadingId: string | null;  // 正在合成的消息 ID
+  toggle: (id: string, text: string) => Promise<string | undefined>; // 失败时返回错误信息
+  stop: () => void;
+}
+
+// 语音朗读：合成后在页面内播放，同一时间只朗读一条，再次点击停止
+export const useSpeech = (): UseSpeechReturn => {
+  const audioRef = useRef<HTMLAudioElement | null>(null);
+  const [speakingId, setSpeakingId] = useState<string | null>(null);
+  const [loadingId, setLoadingId] = useState<string | null>(null);
+
+  const stop = useCallback(() => {
+    audioRef.current?.pause();
+    audioRef.current = null;
+    setSpeakingId(null);
+  }, []);
+
+  const toggle = useCallback(async (id: string, text: string) => {
+    const wasSpeaking = speakingId === id;
+    stop();
+    if (wasSpeaking) return undefined;
+
+    setLoadingId(id);
+    try {
+      const result = await synthesizeSpeech(text);
+      if (!result.success || !result.audio) {
+        return result.error || '语音合成失败';
+      }
+      const audio = new Audio(`data:${result.mimeType};base64,${result.audio}`);
+      audio.onended = () => {
+        if (audioRef.current === audio) stop();
+      };
+      audioRef.current = audio;
+      setSpeakingId(id);
+      await audio.play();
+      return undefined;
+    } catch (e) {
+      console.error('[useSpeech] speak error:', e);
+      stop();
+      return '语音播放失败';
+    } finally {
+      setLoadingId(null);
+    }
+  }, [speakingId, stop]);
+
+  // 卸载时停止播放
+  useEffect(() => stop, [stop]);
+
+  return { speakingId, loadingId, toggle, stop };
+};
@@ -5,6 +5,7 @@ import App from './App'
 import { ThemeProvider } from './contexts/ThemeContext'
 import { CandleColorProvider } from './contexts/CandleColorContext'
 import { IndicatorProvider } from './contexts/IndicatorContext'
+import { UnlockGate } from './components/UnlockGate'
 
 const container = document.getElementById('root')
 
@@ -15,7 +16,9 @@ root.render(
         <ThemeProvider>
             <CandleColorProvider>
                 <IndicatorProvider>
-                    <App/>
+                    <UnlockGate>
+                        <App/>
+                    </UnlockGate>
                 </IndicatorProvider>
             </CandleColorProvider>
         </ThemeProvider>
@@ -1,5 +1,5 @@
 // 配置服务 - 调用后端API
-import { GetConfig, UpdateConfig, GetAvailableTools, TestAIConnection } from '@wailsjs/go/main/App';
+import { GetConfig, UpdateConfig, GetAvailableTools, SetToolEnabled, TestAIConnection, GetProfiles, SwitchProfile, GetEncryptionStatus, UnlockData, EnableDataEncryption, DisableDataEncryption, ExportBackup, ImportBackup } from '@wailsjs/go/main/App';
 import type { models } from '@wailsjs/go/models';
 
 export type AppConfig = models.AppConfig;
@@ -8,6 +8,8 @@ export type AppConfig = models.AppConfig;
 export interface ToolInfo {
   name: string;
   description: string;
+  custom?: boolean;   // 用户自定义 HTTP 工具
+  enabled?: boolean;  // 是否启用（禁用后专家不再获得该工具）
 }
 
 export const getConfig = async (): Promise<AppConfig> => {
@@ -23,7 +25,73 @@ export const getAvailableTools = async (): Promise<ToolInfo[]> => {
   return await GetAvailableTools();
 };
 
+// 运行时启用或禁用工具
+export const setToolEnabled = async (name: string, enabled: boolean): Promise<string> => {
+  return await SetToolEnabled(name, enabled);
+};
+
 // 测试 AI 配置连通性
 export const testAIConnection = async (config: models.AIConfig): Promise<string> => {
   return await TestAIConnection(config);
 };
+
+// 工作区信息
+export interface ProfilesInfo {
+  current: string;
+  profiles: string[];
+  dataDir: string;
+}
+
+// 获取当前工作区与已有工作区列表
+export const getProfiles = async (): Promise<ProfilesInfo> => {
+  return await GetProfiles();
+};
+
+// 切换工作区（不存在时创建），成功后应用自动重启
+export const switchProfile = async (name: string, copyConfig: boolean): Promise<string> => {
+  return await SwitchProfile(name, copyConfig);
+};
+
+// 数据加密状态
+export interface EncryptionStatus {
+  enabled: boolean;
+  locked: boolean; // 已加密但尚未输入密码
+}
+
+export const getEncryptionStatus = async (): Promise<EncryptionStatus> => {
+  return await GetEncryptionStatus();
+};
+
+// 输入密码解锁加密数据
+export const unlockData = async (passphrase: string): Promise<string> => {
+  return await UnlockData(passphrase);
+};
+
+// 启用数据加密（加密会话、记忆与模拟盘数据）
+export const enableDataEncryption = async (passphrase: string): Promise<string> => {
+  return await EnableDataEncryption(passphrase);
+};
+
+// 关闭数据加密（已加密文件恢复为明文）
+export const disableDataEncryption = async (passphrase: string): Promise<string> => {
+  return await DisableDataEncryption(passphrase);
+};
+
+// 备份导出/恢复结果
+export interface BackupResult {
+  success: boolean;
+  cancelled?: boolean; // 用户取消了文件选择
+  path?: string;
+  files: number;
+  error?: string;
+}
+
+// 导出配置、会话、记忆、自选股与模拟盘备份
+export const exportBackup = async (): Promise<BackupResult> => {
+  return await ExportBackup();
+};
+
+// 从备份恢复（成功后应用自动重启）
+export const importBackup = async (): Promise<BackupResult> => {
+  return await ImportBackup();
+};
@@ -1,4 +1,4 @@
-import { GetOrCreateSession, GetSessionMessages, ClearSessionMessages, SendMeetingMessage, UpdateStockPosition, RetryAgent, RetryAgentAndContinue, CancelInterruptedMeeting } from '../../wailsjs/go/main/App';
+import { GetOrCreateSession, GetSessionMessages, ClearSessionMessages, SendMeetingMessage, UpdateStockPosition, RetryAgent, RetryAgentAndContinue, CancelInterruptedMeeting, RunAgentABTest, RateABTest, CreateTradePlan, GetTradePlan, CancelTradePlan, GetMeetingReplays, GetMeetingReplay, GetMeetingTemplates, AddMeetingTemplate, UpdateMeetingTemplate, DeleteMeetingTemplate, RunMeetingTemplate, StartDeepResearch, ResumeDeepResearch, GetDeepResearches, SynthesizeSpeech, EstimatePromptTokens } from '../../wailsjs/go/main/App';
 import type { StockPosition } from '../types';
 
 export interface StockSession {
@@ -7,10 +7,39 @@ export interface StockSession {
   stockName: string;
   messages: ChatMessage[];
   position?: StockPosition; // 持仓信息
+  tradePlan?: TradePlan;    // 由会议结论生成的交易计划
+  lastConsensus?: ConsensusScore; // 最近一次会议的多空共识分
   createdAt: number;
   updatedAt: number;
 }
 
+// 交易计划（买入区间、止损、目标价），后台监控触发提醒
+export interface TradePlan {
+  entryLow?: number;
+  entryHigh?: number;
+  stopLoss?: number;
+  targets?: number[];
+  autoReview: boolean; // 触发止损/目标价时自动召开复盘会议
+  summary: string;
+  status: string;      // active/stopped/done/cancelled
+  hits?: { kind: string; level: number; price: number; at: number }[];
+  createdAt: number;
+}
+
+// 交易计划触发提醒（tradeplan:alert 事件）
+export interface TradePlanAlert {
+  stockCode: string;
+  stockName: string;
+  kind: string; // entry/stop/target
+  level: number;
+  price: number;
+  message: string;
+  review: boolean;
+}
+
+// 专家失败原因分类
+export type ErrorCode = 'rate_limited' | 'auth_failed' | 'context_too_long' | 'tool_failed' | 'timeout';
+
 export interface ChatMessage {
   id: string;
   agentId: string;
@@ -23,7 +52,59 @@ export interface ChatMessage {
   round?: number;
   msgType?: string;
   error?: string;  // 失败时的错误信息
-  meetingMode?: string; // smart=串行, direct=独立
+  errorCode?: ErrorCode; // 失败原因分类，未归类时为空
+  meetingMode?: string; // smart=串行, direct=独立, compare=双股对比
+  answeredBy?: string;  // 发生降级时实际作答的模型名
+  toolCalls?: ToolCallRecord[]; // 发言期间的工具调用（数据来源）
+  meetingId?: string;   // 所属会议 ID
+  variant?: string;     // A/B 对比模式下的变体标签
+  abTestId?: string;    // 所属 A/B 对比记录 ID
+  durationMs?: number;  // 专家发言耗时(毫秒)
+  retries?: number;     // 重试次数
+  toolCallCount?: number; // 工具调用次数
+  modelName?: string;   // 使用的模型名
+  report?: SummaryReport; // 结构化总结（仅 summary）
+  consensus?: ConsensusScore; // 多空共识分（仅 summary）
+}
+
+// 单个专家对共识分的贡献
+export interface ConsensusVote {
+  agentId: string;
+  agentName: string;
+  stance: 'bull' | 'bear' | 'neutral';
+  confidence: number;
+  weight: number;
+}
+
+// 按信心与历史准确率加权的多空共识分
+export interface ConsensusScore {
+  score: number; // -100（一致看空）~ 100（一致看多）
+  label: string;
+  bull: number;
+  bear: number;
+  neutral: number;
+  votes: ConsensusVote[];
+  price?: number;
+}
+
+// 结构化的会议总结
+export interface SummaryReport {
+  conclusion: string;
+  divergence?: string;
+  keyData?: string;
+  risks?: string;
+  actions?: string;
+}
+
+// 工具调用记录
+export interface ToolCallRecord {
+  tool: string;
+  args?: string;    // 调用参数 JSON
+  result?: string;  // 调用结果 JSON（已截断）
+  duration: number; // 耗时(毫秒)
+  agentId: string;
+  citation: number; // 引用编号，对应回答中的 [n]
+  cited?: boolean;  // 回答中是否引用了该结果
 }
 
 // 会议室消息请求
@@ -33,6 +114,8 @@ export interface MeetingMessageRequest {
   mentionIds: string[];
   replyToId: string;
   replyContent: string;
+  compareCode?: string; // 对比股票代码，为空时后端从问题中识别「A vs B」
+  smart?: boolean;      // 有 @ 时仍由小韭菜召开会议，被 @ 的专家必定参会
 }
 
 // 获取或创建Session
@@ -74,3 +157,171 @@ export const retryAgentAndContinue = async (stockCode: string): Promise<ChatMess
 export const cancelInterruptedMeeting = async (stockCode: string): Promise<boolean> => {
   return await CancelInterruptedMeeting(stockCode);
 };
+
+// 专家 A/B 对比结果（回复同时通过 meeting:message 事件推送）
+export interface ABTestResponse {
+  success: boolean;
+  testId?: string;
+  messages: ChatMessage[];
+  error?: string;
+}
+
+// 将问题同时交给专家的 A（当前配置）与 B（变体）两个版本作答
+export const runAgentABTest = async (stockCode: string, agentId: string, content: string): Promise<ABTestResponse> => {
+  return await RunAgentABTest({ stockCode, agentId, content });
+};
+
+// 记录 A/B 对比中更好的版本（A/B/tie）
+export const rateABTest = async (testId: string, preferred: string): Promise<string> => {
+  return await RateABTest(testId, preferred);
+};
+
+// 将最近一次会议结论转换为交易计划并开始监控
+export const createTradePlan = async (stockCode: string, autoReview: boolean): Promise<{ success: boolean; error?: string; plan?: TradePlan }> => {
+  return await CreateTradePlan(stockCode, autoReview);
+};
+
+// 获取交易计划
+export const getTradePlan = async (stockCode: string): Promise<TradePlan | null> => {
+  return await GetTradePlan(stockCode);
+};
+
+// 取消交易计划监控
+export const cancelTradePlan = async (stockCode: string): Promise<string> => {
+  return await CancelTradePlan(stockCode);
+};
+
+// 会议回放事件：进度事件或专家发言，offset 为距会议开始的毫秒数
+export interface ReplayEvent {
+  offset: number;
+  progress?: {
+    type: string;
+    agentId: string;
+    agentName: string;
+    detail: string;
+    content: string;
+  };
+  response?: Omit<ChatMessage, 'id' | 'timestamp'>;
+}
+
+// 会议回放概要
+export interface ReplaySummary {
+  meetingId: string;
+  stockCode: string;
+  stockName: string;
+  query: string;
+  mode: string;
+  startedAt: number;
+  endedAt: number;
+  events: number;
+}
+
+// 会议回放（完整事件流）
+export interface MeetingReplay extends Omit<ReplaySummary, 'events'> {
+  events: ReplayEvent[];
+}
+
+// 获取股票的会议回放列表（最新的在前）
+export const getMeetingReplays = async (stockCode: string): Promise<ReplaySummary[]> => {
+  return (await GetMeetingReplays(stockCode)) || [];
+};
+
+// 获取会议回放，不存在时返回 null
+export const getMeetingReplay = async (meetingId: string): Promise<MeetingReplay | null> => {
+  return await GetMeetingReplay(meetingId);
+};
+
+// 会议模板：固定的专家阵容、开会方式与带占位符（{stock}/{code}/{date}）的问题
+export interface MeetingTemplate {
+  id: string;
+  name: string;
+  agentIds: string[];
+  mode: 'smart' | 'direct';
+  query: string;
+  createdAt: number;
+  updatedAt: number;
+}
+
+// 按模板开会的结果（发言同时通过 meeting:message 事件推送）
+export interface MeetingTemplateRunResponse {
+  success: boolean;
+  query?: string;
+  messages: ChatMessage[];
+  error?: string;
+}
+
+// 获取全部会议模板
+export const getMeetingTemplates = async (): Promise<MeetingTemplate[]> => {
+  return ((await GetMeetingTemplates()) || []) as MeetingTemplate[];
+};
+
+// 保存会议模板，无 ID 时新增
+export const saveMeetingTemplate = async (tpl: MeetingTemplate): Promise<string> => {
+  return tpl.id ? await UpdateMeetingTemplate(tpl) : await AddMeetingTemplate(tpl);
+};
+
+// 删除会议模板
+export const deleteMeetingTemplate = async (id: string): Promise<string> => {
+  return await DeleteMeetingTemplate(id);
+};
+
+// 对股票按模板开会
+export const runMeetingTemplate = async (templateId: string, stockCode: string): Promise<MeetingTemplateRunResponse> => {
+  return await RunMeetingTemplate(templateId, stockCode);
+};
+
+// 深度研究概要，status 为 running/failed 时可继续
+export interface ResearchSummary {
+  id: string;
+  stockCode: string;
+  query: string;
+  status: string;     // running/failed/done
+  steps: number;      // 研究计划步数，0 表示尚未拟定计划
+  completed: number;  // 已完成步数
+  error?: string;
+  startedAt: number;
+  updatedAt: number;
+}
+
+// 深度研究结果（报告同时通过 meeting:message 事件推送）
+export interface DeepResearchResponse {
+  success: boolean;
+  researchId?: string;
+  message?: ChatMessage;
+  error?: string;
+}
+
+// 发起深度研究，query 为空时研究整体投资价值
+export const startDeepResearch = async (stockCode: string, query: string): Promise<DeepResearchResponse> => {
+  return await StartDeepResearch(stockCode, query);
+};
+
+// 从检查点继续未完成的深度研究
+export const resumeDeepResearch = async (researchId: string): Promise<DeepResearchResponse> => {
+  return await ResumeDeepResearch(researchId);
+};
+
+// 获取股票的深度研究列表（最新的在前）
+export const getDeepResearches = async (stockCode: string): Promise<ResearchSummary[]> => {
+  return (await GetDeepResearches(stockCode)) || [];
+};
+
+// 语音合成结果，audio 为 base64 编码的音频数据
+export interface SpeechResponse {
+  success: boolean;
+  id?: string;
+  path?: string;      // 语音文件路径
+  mimeType?: string;
+  audio?: string;
+  error?: string;
+}
+
+// 将消息内容合成为语音
+export const synthesizeSpeech = async (text: string): Promise<SpeechResponse> => {
+  return await SynthesizeSpeech(text);
+};
+
+// 估算提问发送给专家的 prompt token 数（老韭菜画像、股票记忆与问题）
+export const estimatePromptTokens = async (stockCode: string, query: string): Promise<number> => {
+  return await EstimatePromptTokens(stockCode, query);
+};
@@ -1,6 +1,6 @@
 // 市场数据服务 - 调用后端API
-import { GetStockRealTimeData, GetKLineData, GetOrderBook, SearchStocks } from '@wailsjs/go/main/App';
-import type { Stock, KLineData, OrderBook } from '../types';
+import { GetStockRealTimeData, GetKLineData, GetIntradayHistory, GetOrderBook, SearchStocks } from '@wailsjs/go/main/App';
+import type { Stock, KLineData, OrderBook, KLineAdjust } from '../types';
 
 // 股票搜索结果类型
 export interface StockSearchResult {
@@ -14,8 +14,16 @@ export const getStockRealTimeData = async (codes: string[]): Promise<Stock[]> =>
   return await GetStockRealTimeData(codes);
 };
 
-export const getKLineData = async (code: string, period: string, days: number): Promise<KLineData[]> => {
-  return await GetKLineData(code, period, days);
+export const getKLineData = async (code: string, period: string, days: number, adjust: KLineAdjust = 'none'): Promise<KLineData[]> => {
+  return await GetKLineData(code, period, days, adjust);
+};
+
+// 分页获取分钟K线历史，before 为空取最新一页，否则取早于 before 的数据
+export const getIntradayHistory = async (
+  code: string, period: string, before: string, limit: number,
+): Promise<{ data: KLineData[]; hasMore: boolean }> => {
+  const page = await GetIntradayHistory(code, period, before, limit);
+  return { data: (page?.data || []) as KLineData[], hasMore: !!page?.hasMore };
 };
 
 // 获取真实五档盘口数据
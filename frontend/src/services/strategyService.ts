@@ -1,4 +1,10 @@
-import { GetStrategies, GetActiveStrategyID, SetActiveStrategy, AddStrategy, UpdateStrategy, DeleteStrategy, GenerateStrategy, EnhancePrompt, GetAgentConfigs, AddAgentConfig, UpdateAgentConfig, DeleteAgentConfig } from '../../wailsjs/go/main/App';
+import { GetStrategies, GetActiveStrategyID, SetActiveStrategy, AddStrategy, UpdateStrategy, DeleteStrategy, GenerateStrategy, EnhancePrompt, GetAgentConfigs, AddAgentConfig, UpdateAgentConfig, DeleteAgentConfig, GetAgentPresets, InstallAgentPresets, UpgradeAgentPresets, GetAgentABTests, GetAgentQualityStats, GetAgentAccuracy, GetAgentToolUsage } from '../../wailsjs/go/main/App';
+
+// 专家的 A/B 对比变体，留空的字段沿用专家本身的配置
+export interface AgentVariant {
+  instruction?: string;
+  aiConfigId?: string;
+}
 
 // 策略专属专家配置
 export interface StrategyAgent {
@@ -12,6 +18,11 @@ export interface StrategyAgent {
   mcpServers: string[];
   enabled: boolean;
   aiConfigId: string;
+  unifiedTools?: boolean; // 将行情类工具合并为一个 market_data 工具
+  variant?: AgentVariant; // A/B 对比的 B 变体
+  presetId?: string;
+  presetVersion?: number;
+  presetHash?: string;
 }
 
 export interface Strategy {
@@ -104,6 +115,7 @@ export interface AgentConfig {
   mcpServers: string[];
   enabled: boolean;
   aiConfigId: string;
+  unifiedTools?: boolean;
 }
 
 // 获取所有已启用的Agent配置
@@ -125,3 +137,115 @@ export const updateAgentConfig = async (config: AgentConfig): Promise<string> =>
 export const deleteAgentConfig = async (id: string): Promise<string> => {
   return await DeleteAgentConfig(id);
 };
+
+// ========== 专家预设库 API ==========
+
+export interface AgentPresetInfo {
+  id: string;
+  category: string;
+  sector?: string;
+  summary: string;
+  version: number;
+  agent: StrategyAgent;
+  installed: boolean;
+  upgradable: boolean;
+  customized: boolean;
+}
+
+// 获取专家预设库及其在当前策略中的状态
+export const getAgentPresets = async (): Promise<AgentPresetInfo[]> => {
+  return await GetAgentPresets();
+};
+
+// 将预设添加到当前策略
+export const installAgentPresets = async (ids: string[]): Promise<string> => {
+  return await InstallAgentPresets(ids);
+};
+
+// 升级当前策略中的预设专家，overwrite 为 true 时覆盖用户修改
+export const upgradeAgentPresets = async (ids: string[], overwrite: boolean): Promise<string> => {
+  return await UpgradeAgentPresets(ids, overwrite);
+};
+
+// ========== 专家质量日志 API ==========
+
+export interface ABTestVariant {
+  label: string;
+  instruction: string;
+  aiConfigId?: string;
+  model?: string;
+  content: string;
+  error?: string;
+}
+
+export interface ABTestRecord {
+  id: string;
+  agentId: string;
+  agentName: string;
+  stockCode: string;
+  query: string;
+  variants: ABTestVariant[];
+  preferred?: string; // A/B/tie
+  createdAt: number;
+  ratedAt?: number;
+}
+
+export interface AgentQualityStats {
+  agentId: string;
+  agentName: string;
+  tests: number;
+  rated: number;
+  winsA: number;
+  winsB: number;
+  ties: number;
+}
+
+// 获取专家的 A/B 对比记录（最新的在前）
+export const getAgentABTests = async (agentId: string, limit: number): Promise<ABTestRecord[]> => {
+  return await GetAgentABTests(agentId, limit);
+};
+
+// 获取各专家的 A/B 对比统计
+export const getAgentQualityStats = async (): Promise<AgentQualityStats[]> => {
+  return await GetAgentQualityStats();
+};
+
+// 专家多空观点的历史准确率（观点给出 5 个交易日后检验）
+export interface AgentAccuracy {
+  agentId: string;
+  agentName: string;
+  total: number;
+  evaluated: number;
+  hits: number;
+  weight: number; // 共识分中的权重，无历史记录时为 1
+}
+
+// 获取各专家的观点准确率
+export const getAgentAccuracy = async (): Promise<AgentAccuracy[]> => {
+  return await GetAgentAccuracy();
+};
+
+// 专家对单个工具的使用统计
+export interface ToolUsageStats {
+  tool: string;
+  calls: number;
+  cited: number;      // 结果被回答引用的次数
+  citeRate: number;   // 引用率 = cited / calls
+  configured: boolean;
+  lastUsed?: number;
+}
+
+// 专家的工具使用统计与精简建议
+export interface AgentToolUsage {
+  agentId: string;
+  agentName: string;
+  speeches: number;
+  toolCalls: number;
+  tools: ToolUsageStats[];
+  unused?: string[]; // 建议移除：已挂载但从未调用
+}
+
+// 获取当前策略各专家的工具使用统计
+export const getAgentToolUsage = async (): Promise<AgentToolUsage[]> => {
+  return await GetAgentToolUsage();
+};
@@ -1,6 +1,10 @@
 // 自选股服务 - 调用后端API
-import { GetWatchlist, AddToWatchlist, RemoveFromWatchlist } from '@wailsjs/go/main/App';
-import type { Stock } from '../types';
+import {
+  GetWatchlist, AddToWatchlist, RemoveFromWatchlist,
+  GetWatchlistGroups, AddWatchlistGroup, RemoveWatchlistGroup,
+  AddStockToGroup, RemoveStockFromGroup, ReorderWatchlist,
+} from '@wailsjs/go/main/App';
+import type { Stock, WatchlistGroup } from '../types';
 
 export const getWatchlist = async (): Promise<Stock[]> => {
   return await GetWatchlist() as Stock[];
@@ -13,3 +17,28 @@ export const addToWatchlist = async (stock: Stock): Promise<string> => {
 export const removeFromWatchlist = async (symbol: string): Promise<string> => {
   return await RemoveFromWatchlist(symbol);
 };
+
+export const getWatchlistGroups = async (): Promise<WatchlistGroup[]> => {
+  return (await GetWatchlistGroups() || []) as WatchlistGroup[];
+};
+
+export const addWatchlistGroup = async (name: string): Promise<string> => {
+  return await AddWatchlistGroup(name);
+};
+
+export const removeWatchlistGroup = async (id: string): Promise<string> => {
+  return await RemoveWatchlistGroup(id);
+};
+
+export const addStockToGroup = async (groupId: string, symbol: string): Promise<string> => {
+  return await AddStockToGroup(groupId, symbol);
+};
+
+export const removeStockFromGroup = async (groupId: string, symbol: string): Promise<string> => {
+  return await RemoveStockFromGroup(groupId, symbol);
+};
+
+// 保存拖拽排序，groupId 为空表示「全部」
+export const reorderWatchlist = async (groupId: string, symbols: string[]): Promise<string> => {
+  return await ReorderWatchlist(groupId, symbols);
+};
@@ -12,6 +12,18 @@ export interface Stock {
   high: number;
   low: number;
   preClose: number;
+  turnoverRate?: number; // 换手率(%)
+  pe?: number;           // 市盈率(动)，亏损为负
+  pb?: number;           // 市净率
+  floatShares?: number;  // 流通股本(股)
+  totalShares?: number;  // 总股本(股)
+}
+
+// 自选股分组
+export interface WatchlistGroup {
+  id: string;
+  name: string;
+  symbols: string[]; // 组内股票代码（按拖拽排序）
 }
 
 // 股票持仓信息
@@ -28,6 +40,7 @@ export interface KLineData {
   close: number;
   volume: number;
   avg?: number; // For intraday average price line
+  netInflow?: number; // 分时当日累计资金净流入（元）
   // 均线数据
   ma5?: number;
   ma10?: number;
@@ -41,9 +54,28 @@ export interface OrderBookItem {
   percent: number; // For visual bar depth
 }
 
+// 逐笔成交
+export interface TickTrade {
+  time: string;
+  price: number;
+  volume: number; // 成交量(股)
+  direction: 'buy' | 'sell' | 'neutral';
+}
+
+// 盘口衍生指标（后端计算）
+export interface OrderBookMetrics {
+  bidVolume: number;
+  askVolume: number;
+  imbalance: number; // 委比，范围 -1~1
+  largeBids?: number[]; // 大单所在档位下标
+  largeAsks?: number[];
+  withdrawn?: { side: 'bid' | 'ask'; price: number; size: number }[]; // 疑似撤单
+}
+
 export interface OrderBook {
   bids: OrderBookItem[];
   asks: OrderBookItem[];
+  metrics?: OrderBookMetrics;
 }
 
 export enum AgentRole {
@@ -76,9 +108,15 @@ export interface ChatMessage {
 }
 
 // 消息类型
-export type MsgType = 'opening' | 'opinion' | 'summary';
+export type MsgType = 'opening' | 'opinion' | 'summary' | 'clarify' | 'answer' | 'research';
+
+export type TimePeriod = '1m' | '5m' | '15m' | '30m' | '60m' | '1d' | '1w' | '1mo';
+
+// 分钟K线周期（可跨交易日分页加载历史）
+export const MINUTE_PERIODS: TimePeriod[] = ['5m', '15m', '30m', '60m'];
 
-export type TimePeriod = '1m' | '1d' | '1w' | '1mo';
+// 复权方式：不复权 / 前复权 / 后复权（仅日/周/月K生效）
+export type KLineAdjust = 'none' | 'qfq' | 'hfq';
 
 // 快讯数据结构
 export interface Telegraph {
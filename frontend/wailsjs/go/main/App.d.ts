@@ -6,52 +6,103 @@ import {main} from '../models';
 import {hottrend} from '../models';
 import {tools} from '../models';
 import {mcp} from '../models';
+import {meeting} from '../models';
 
 export function AddAgentConfig(arg1:models.AgentConfig):Promise<string>;
 
 export function AddMCPServer(arg1:models.MCPServerConfig):Promise<string>;
 
+export function AddMeetingTemplate(arg1:services.MeetingTemplate):Promise<string>;
+
+export function AddStockToGroup(arg1:string,arg2:string):Promise<string>;
+
 export function AddStrategy(arg1:models.Strategy):Promise<string>;
 
 export function AddToWatchlist(arg1:models.Stock):Promise<string>;
 
+export function AddWatchlistGroup(arg1:string):Promise<string>;
+
+export function ApprovePaperOrder(arg1:string):Promise<main.PaperOrderResponse>;
+
+export function AttachMeeting(arg1:string):Promise<Array<models.ChatMessage>>;
+
 export function CancelInterruptedMeeting(arg1:string):Promise<boolean>;
 
 export function CancelMeeting(arg1:string):Promise<boolean>;
 
+export function CancelPaperOrder(arg1:string):Promise<string>;
+
+export function CancelTradePlan(arg1:string):Promise<string>;
+
 export function CheckForUpdate():Promise<services.UpdateInfo>;
 
 export function ClearSessionMessages(arg1:string):Promise<string>;
 
+export function CreateTradePlan(arg1:string,arg2:boolean):Promise<main.TradePlanResponse>;
+
 export function DeleteAgentConfig(arg1:string):Promise<string>;
 
 export function DeleteMCPServer(arg1:string):Promise<string>;
 
+export function DeleteMeetingTemplate(arg1:string):Promise<string>;
+
 export function DeleteStrategy(arg1:string):Promise<string>;
 
+export function DisableDataEncryption(arg1:string):Promise<string>;
+
 export function DoUpdate():Promise<string>;
 
+export function EnableDataEncryption(arg1:string):Promise<string>;
+
 export function EnhancePrompt(arg1:main.EnhancePromptRequest):Promise<main.EnhancePromptResponse>;
 
+export function EstimatePromptTokens(arg1:string,arg2:string):Promise<number>;
+
+export function ExportBackup():Promise<main.BackupResponse>;
+
+export function GenerateBriefing(arg1:string):Promise<main.BriefingResponse>;
+
 export function GenerateStrategy(arg1:main.GenerateStrategyRequest):Promise<main.GenerateStrategyResponse>;
 
 export function GetActiveStrategyID():Promise<string>;
 
+export function GetAgentABTests(arg1:string,arg2:number):Promise<Array<services.ABTestRecord>>;
+
+export function GetAgentAccuracy():Promise<Array<services.AgentAccuracy>>;
+
 export function GetAgentConfigs():Promise<Array<models.AgentConfig>>;
 
+export function GetAgentPresets():Promise<Array<services.AgentPresetInfo>>;
+
+export function GetAgentQualityStats():Promise<Array<services.AgentQualityStats>>;
+
+export function GetAgentToolUsage():Promise<Array<meeting.AgentToolUsage>>;
+
 export function GetAllHotTrends():Promise<Array<hottrend.HotTrendResult>>;
 
 export function GetAvailableTools():Promise<Array<tools.ToolInfo>>;
 
+export function GetBriefing(arg1:string):Promise<models.Briefing>;
+
+export function GetBrokerOrders():Promise<main.BrokerOrdersResponse>;
+
 export function GetConfig():Promise<models.AppConfig>;
 
 export function GetCurrentVersion():Promise<string>;
 
+export function GetDeepResearches(arg1:string):Promise<Array<meeting.ResearchSummary>>;
+
+export function GetEncryptionStatus():Promise<main.EncryptionStatus>;
+
 export function GetHotTrend(arg1:string):Promise<hottrend.HotTrendResult>;
 
 export function GetHotTrendPlatforms():Promise<Array<hottrend.PlatformInfo>>;
 
-export function GetKLineData(arg1:string,arg2:string,arg3:number):Promise<Array<models.KLineData>>;
+export function GetIntradayHistory(arg1:string,arg2:string,arg3:string,arg4:number):Promise<services.IntradayPage>;
+
+export function GetKLineData(arg1:string,arg2:string,arg3:number,arg4:string):Promise<Array<models.KLineData>>;
+
+export function GetLimitBoard(arg1:string):Promise<models.LimitBoard>;
 
 export function GetLongHuBangDetail(arg1:string,arg2:string):Promise<Array<models.LongHuBangDetail>>;
 
@@ -63,14 +114,30 @@ export function GetMCPServers():Promise<Array<models.MCPServerConfig>>;
 
 export function GetMCPStatus():Promise<Array<mcp.ServerStatus>>;
 
+export function GetMarketBreadth():Promise<models.MarketBreadth>;
+
+export function GetMeetingReplay(arg1:string):Promise<meeting.MeetingReplay>;
+
+export function GetMeetingReplays(arg1:string):Promise<Array<meeting.ReplaySummary>>;
+
+export function GetMeetingTemplates():Promise<Array<services.MeetingTemplate>>;
+
 export function GetOpenClawStatus():Promise<Record<string, any>>;
 
 export function GetOrCreateSession(arg1:string,arg2:string):Promise<models.StockSession>;
 
 export function GetOrderBook(arg1:string):Promise<models.OrderBook>;
 
+export function GetPaperAccount():Promise<models.PaperAccount>;
+
+export function GetProfiles():Promise<main.ProfilesInfo>;
+
+export function GetProviderPresets():Promise<Array<models.ProviderPreset>>;
+
 export function GetSessionMessages(arg1:string):Promise<Array<models.ChatMessage>>;
 
+export function GetStockNews(arg1:string,arg2:number):Promise<Array<services.StockNews>>;
+
 export function GetStockRealTimeData(arg1:Array<string>):Promise<Array<models.Stock>>;
 
 export function GetStrategies():Promise<Array<models.Strategy>>;
@@ -79,44 +146,100 @@ export function GetTelegraphList():Promise<Array<services.Telegraph>>;
 
 export function GetTradeDates(arg1:number):Promise<Array<string>>;
 
+export function GetTradePlan(arg1:string):Promise<models.TradePlan>;
+
 export function GetTradingSchedule():Promise<services.TradingSchedule>;
 
 export function GetWatchlist():Promise<Array<models.Stock>>;
 
+export function GetWatchlistGroups():Promise<Array<models.WatchlistGroup>>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportBackup():Promise<main.BackupResponse>;
+
+export function InstallAgentPresets(arg1:Array<string>):Promise<string>;
+
+export function IsMeetingRunning(arg1:string):Promise<boolean>;
+
+export function ListAIModels(arg1:models.AIConfig):Promise<main.ListModelsResponse>;
+
+export function LoadSpeech(arg1:string):Promise<main.SpeechResponse>;
+
+export function LookupStock(arg1:string):Promise<services.StockBasicInfo>;
+
 export function NotifyFrontendReady():Promise<void>;
 
 export function OpenURL(arg1:string):Promise<void>;
 
+export function PlacePaperOrder(arg1:services.PaperOrderRequest):Promise<main.PaperOrderResponse>;
+
+export function PreviewAgentPrompt(arg1:main.PromptPreviewRequest):Promise<main.PromptPreviewResponse>;
+
+export function RateABTest(arg1:string,arg2:string):Promise<string>;
+
+export function RejectPaperOrder(arg1:string):Promise<string>;
+
 export function RemoveFromWatchlist(arg1:string):Promise<string>;
 
+export function RemoveStockFromGroup(arg1:string,arg2:string):Promise<string>;
+
+export function RemoveWatchlistGroup(arg1:string):Promise<string>;
+
+export function ReorderWatchlist(arg1:string,arg2:Array<string>):Promise<string>;
+
+export function ResetPaperAccount(arg1:number):Promise<string>;
+
 export function RestartApp():Promise<string>;
 
+export function ResumeDeepResearch(arg1:string):Promise<main.DeepResearchResponse>;
+
 export function RetryAgent(arg1:string,arg2:string,arg3:string):Promise<models.ChatMessage>;
 
 export function RetryAgentAndContinue(arg1:string):Promise<Array<models.ChatMessage>>;
 
+export function RunAgentABTest(arg1:main.ABTestRequest):Promise<main.ABTestResponse>;
+
+export function RunMeetingTemplate(arg1:string,arg2:string):Promise<main.MeetingTemplateRunResponse>;
+
 export function SearchStocks(arg1:string):Promise<Array<services.StockSearchResult>>;
 
 export function SendMeetingMessage(arg1:main.MeetingMessageRequest):Promise<Array<models.ChatMessage>>;
 
 export function SetActiveStrategy(arg1:string):Promise<string>;
 
+export function SetToolEnabled(arg1:string,arg2:boolean):Promise<string>;
+
+export function StartDeepResearch(arg1:string,arg2:string):Promise<main.DeepResearchResponse>;
+
+export function SwitchProfile(arg1:string,arg2:boolean):Promise<string>;
+
+export function SyncBrokerPositions():Promise<main.BrokerSyncResponse>;
+
+export function SynthesizeSpeech(arg1:string):Promise<main.SpeechResponse>;
+
 export function TestAIConnection(arg1:models.AIConfig):Promise<string>;
 
 export function TestMCPConnection(arg1:string):Promise<mcp.ServerStatus>;
 
+export function TestPushChannel(arg1:models.PushChannelConfig):Promise<string>;
+
+export function UnlockData(arg1:string):Promise<string>;
+
 export function UpdateAgentConfig(arg1:models.AgentConfig):Promise<string>;
 
 export function UpdateConfig(arg1:models.AppConfig):Promise<string>;
 
 export function UpdateMCPServer(arg1:models.MCPServerConfig):Promise<string>;
 
+export function UpdateMeetingTemplate(arg1:services.MeetingTemplate):Promise<string>;
+
 export function UpdateStockPosition(arg1:string,arg2:number,arg3:number):Promise<string>;
 
 export function UpdateStrategy(arg1:models.Strategy):Promise<string>;
 
+export function UpgradeAgentPresets(arg1:Array<string>,arg2:boolean):Promise<string>;
+
 export function WindowClose():Promise<void>;
 
 export function WindowMaximize():Promise<void>;
@@ -10,6 +10,14 @@ export function AddMCPServer(arg1) {
   return window['go']['main']['App']['AddMCPServer'](arg1);
 }
 
+export function AddMeetingTemplate(arg1) {
+  return window['go']['main']['App']['AddMeetingTemplate'](arg1);
+}
+
+export function AddStockToGroup(arg1, arg2) {
+  return window['go']['main']['App']['AddStockToGroup'](arg1, arg2);
+}
+
 export function AddStrategy(arg1) {
   return window['go']['main']['App']['AddStrategy'](arg1);
 }
@@ -18,6 +26,18 @@ export function AddToWatchlist(arg1) {
   return window['go']['main']['App']['AddToWatchlist'](arg1);
 }
 
+export function AddWatchlistGroup(arg1) {
+  return window['go']['main']['App']['AddWatchlistGroup'](arg1);
+}
+
+export function ApprovePaperOrder(arg1) {
+  return window['go']['main']['App']['ApprovePaperOrder'](arg1);
+}
+
+export function AttachMeeting(arg1) {
+  return window['go']['main']['App']['AttachMeeting'](arg1);
+}
+
 export function CancelInterruptedMeeting(arg1) {
   return window['go']['main']['App']['CancelInterruptedMeeting'](arg1);
 }
@@ -26,6 +46,14 @@ export function CancelMeeting(arg1) {
   return window['go']['main']['App']['CancelMeeting'](arg1);
 }
 
+export function CancelPaperOrder(arg1) {
+  return window['go']['main']['App']['CancelPaperOrder'](arg1);
+}
+
+export function CancelTradePlan(arg1) {
+  return window['go']['main']['App']['CancelTradePlan'](arg1);
+}
+
 export function CheckForUpdate() {
   return window['go']['main']['App']['CheckForUpdate']();
 }
@@ -34,6 +62,10 @@ export function ClearSessionMessages(arg1) {
   return window['go']['main']['App']['ClearSessionMessages'](arg1);
 }
 
+export function CreateTradePlan(arg1, arg2) {
+  return window['go']['main']['App']['CreateTradePlan'](arg1, arg2);
+}
+
 export function DeleteAgentConfig(arg1) {
   return window['go']['main']['App']['DeleteAgentConfig'](arg1);
 }
@@ -42,18 +74,42 @@ export function DeleteMCPServer(arg1) {
   return window['go']['main']['App']['DeleteMCPServer'](arg1);
 }
 
+export function DeleteMeetingTemplate(arg1) {
+  return window['go']['main']['App']['DeleteMeetingTemplate'](arg1);
+}
+
 export function DeleteStrategy(arg1) {
   return window['go']['main']['App']['DeleteStrategy'](arg1);
 }
 
+export function DisableDataEncryption(arg1) {
+  return window['go']['main']['App']['DisableDataEncryption'](arg1);
+}
+
 export function DoUpdate() {
   return window['go']['main']['App']['DoUpdate']();
 }
 
+export function EnableDataEncryption(arg1) {
+  return window['go']['main']['App']['EnableDataEncryption'](arg1);
+}
+
 export function EnhancePrompt(arg1) {
   return window['go']['main']['App']['EnhancePrompt'](arg1);
 }
 
+export function EstimatePromptTokens(arg1, arg2) {
+  return window['go']['main']['App']['EstimatePromptTokens'](arg1, arg2);
+}
+
+export function ExportBackup() {
+  return window['go']['main']['App']['ExportBackup']();
+}
+
+export function GenerateBriefing(arg1) {
+  return window['go']['main']['App']['GenerateBriefing'](arg1);
+}
+
 export function GenerateStrategy(arg1) {
   return window['go']['main']['App']['GenerateStrategy'](arg1);
 }
@@ -62,10 +118,30 @@ export function GetActiveStrategyID() {
   return window['go']['main']['App']['GetActiveStrategyID']();
 }
 
+export function GetAgentABTests(arg1, arg2) {
+  return window['go']['main']['App']['GetAgentABTests'](arg1, arg2);
+}
+
+export function GetAgentAccuracy() {
+  return window['go']['main']['App']['GetAgentAccuracy']();
+}
+
 export function GetAgentConfigs() {
   return window['go']['main']['App']['GetAgentConfigs']();
 }
 
+export function GetAgentPresets() {
+  return window['go']['main']['App']['GetAgentPresets']();
+}
+
+export function GetAgentQualityStats() {
+  return window['go']['main']['App']['GetAgentQualityStats']();
+}
+
+export function GetAgentToolUsage() {
+  return window['go']['main']['App']['GetAgentToolUsage']();
+}
+
 export function GetAllHotTrends() {
   return window['go']['main']['App']['GetAllHotTrends']();
 }
@@ -74,6 +150,14 @@ export function GetAvailableTools() {
   return window['go']['main']['App']['GetAvailableTools']();
 }
 
+export function GetBriefing(arg1) {
+  return window['go']['main']['App']['GetBriefing'](arg1);
+}
+
+export function GetBrokerOrders() {
+  return window['go']['main']['App']['GetBrokerOrders']();
+}
+
 export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
@@ -82,6 +166,14 @@ export function GetCurrentVersion() {
   return window['go']['main']['App']['GetCurrentVersion']();
 }
 
+export function GetDeepResearches(arg1) {
+  return window['go']['main']['App']['GetDeepResearches'](arg1);
+}
+
+export function GetEncryptionStatus() {
+  return window['go']['main']['App']['GetEncryptionStatus']();
+}
+
 export function GetHotTrend(arg1) {
   return window['go']['main']['App']['GetHotTrend'](arg1);
 }
@@ -90,8 +182,16 @@ export function GetHotTrendPlatforms() {
   return window['go']['main']['App']['GetHotTrendPlatforms']();
 }
 
-export function GetKLineData(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GetKLineData'](arg1, arg2, arg3);
+export function GetIntradayHistory(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetIntradayHistory'](arg1, arg2, arg3, arg4);
+}
+
+export function GetKLineData(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetKLineData'](arg1, arg2, arg3, arg4);
+}
+
+export function GetLimitBoard(arg1) {
+  return window['go']['main']['App']['GetLimitBoard'](arg1);
 }
 
 export function GetLongHuBangDetail(arg1, arg2) {
@@ -114,6 +214,22 @@ export function GetMCPStatus() {
   return window['go']['main']['App']['GetMCPStatus']();
 }
 
+export function GetMarketBreadth() {
+  return window['go']['main']['App']['GetMarketBreadth']();
+}
+
+export function GetMeetingReplay(arg1) {
+  return window['go']['main']['App']['GetMeetingReplay'](arg1);
+}
+
+export function GetMeetingReplays(arg1) {
+  return window['go']['main']['App']['GetMeetingReplays'](arg1);
+}
+
+export function GetMeetingTemplates() {
+  return window['go']['main']['App']['GetMeetingTemplates']();
+}
+
 export function GetOpenClawStatus() {
   return window['go']['main']['App']['GetOpenClawStatus']();
 }
@@ -126,10 +242,26 @@ export function GetOrderBook(arg1) {
   return window['go']['main']['App']['GetOrderBook'](arg1);
 }
 
+export function GetPaperAccount() {
+  return window['go']['main']['App']['GetPaperAccount']();
+}
+
+export function GetProfiles() {
+  return window['go']['main']['App']['GetProfiles']();
+}
+
+export function GetProviderPresets() {
+  return window['go']['main']['App']['GetProviderPresets']();
+}
+
 export function GetSessionMessages(arg1) {
   return window['go']['main']['App']['GetSessionMessages'](arg1);
 }
 
+export function GetStockNews(arg1, arg2) {
+  return window['go']['main']['App']['GetStockNews'](arg1, arg2);
+}
+
 export function GetStockRealTimeData(arg1) {
   return window['go']['main']['App']['GetStockRealTimeData'](arg1);
 }
@@ -146,6 +278,10 @@ export function GetTradeDates(arg1) {
   return window['go']['main']['App']['GetTradeDates'](arg1);
 }
 
+export function GetTradePlan(arg1) {
+  return window['go']['main']['App']['GetTradePlan'](arg1);
+}
+
 export function GetTradingSchedule() {
   return window['go']['main']['App']['GetTradingSchedule']();
 }
@@ -154,10 +290,38 @@ export function GetWatchlist() {
   return window['go']['main']['App']['GetWatchlist']();
 }
 
+export function GetWatchlistGroups() {
+  return window['go']['main']['App']['GetWatchlistGroups']();
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportBackup() {
+  return window['go']['main']['App']['ImportBackup']();
+}
+
+export function InstallAgentPresets(arg1) {
+  return window['go']['main']['App']['InstallAgentPresets'](arg1);
+}
+
+export function IsMeetingRunning(arg1) {
+  return window['go']['main']['App']['IsMeetingRunning'](arg1);
+}
+
+export function ListAIModels(arg1) {
+  return window['go']['main']['App']['ListAIModels'](arg1);
+}
+
+export function LoadSpeech(arg1) {
+  return window['go']['main']['App']['LoadSpeech'](arg1);
+}
+
+export function LookupStock(arg1) {
+  return window['go']['main']['App']['LookupStock'](arg1);
+}
+
 export function NotifyFrontendReady() {
   return window['go']['main']['App']['NotifyFrontendReady']();
 }
@@ -166,14 +330,50 @@ export function OpenURL(arg1) {
   return window['go']['main']['App']['OpenURL'](arg1);
 }
 
+export function PlacePaperOrder(arg1) {
+  return window['go']['main']['App']['PlacePaperOrder'](arg1);
+}
+
+export function PreviewAgentPrompt(arg1) {
+  return window['go']['main']['App']['PreviewAgentPrompt'](arg1);
+}
+
+export function RateABTest(arg1, arg2) {
+  return window['go']['main']['App']['RateABTest'](arg1, arg2);
+}
+
+export function RejectPaperOrder(arg1) {
+  return window['go']['main']['App']['RejectPaperOrder'](arg1);
+}
+
 export function RemoveFromWatchlist(arg1) {
   return window['go']['main']['App']['RemoveFromWatchlist'](arg1);
 }
 
+export function RemoveStockFromGroup(arg1, arg2) {
+  return window['go']['main']['App']['RemoveStockFromGroup'](arg1, arg2);
+}
+
+export function RemoveWatchlistGroup(arg1) {
+  return window['go']['main']['App']['RemoveWatchlistGroup'](arg1);
+}
+
+export function ReorderWatchlist(arg1, arg2) {
+  return window['go']['main']['App']['ReorderWatchlist'](arg1, arg2);
+}
+
+export function ResetPaperAccount(arg1) {
+  return window['go']['main']['App']['ResetPaperAccount'](arg1);
+}
+
 export function RestartApp() {
   return window['go']['main']['App']['RestartApp']();
 }
 
+export function ResumeDeepResearch(arg1) {
+  return window['go']['main']['App']['ResumeDeepResearch'](arg1);
+}
+
 export function RetryAgent(arg1, arg2, arg3) {
   return window['go']['main']['App']['RetryAgent'](arg1, arg2, arg3);
 }
@@ -182,6 +382,14 @@ export function RetryAgentAndContinue(arg1) {
   return window['go']['main']['App']['RetryAgentAndContinue'](arg1);
 }
 
+export function RunAgentABTest(arg1) {
+  return window['go']['main']['App']['RunAgentABTest'](arg1);
+}
+
+export function RunMeetingTemplate(arg1, arg2) {
+  return window['go']['main']['App']['RunMeetingTemplate'](arg1, arg2);
+}
+
 export function SearchStocks(arg1) {
   return window['go']['main']['App']['SearchStocks'](arg1);
 }
@@ -194,6 +402,26 @@ export function SetActiveStrategy(arg1) {
   return window['go']['main']['App']['SetActiveStrategy'](arg1);
 }
 
+export function SetToolEnabled(arg1, arg2) {
+  return window['go']['main']['App']['SetToolEnabled'](arg1, arg2);
+}
+
+export function StartDeepResearch(arg1, arg2) {
+  return window['go']['main']['App']['StartDeepResearch'](arg1, arg2);
+}
+
+export function SwitchProfile(arg1, arg2) {
+  return window['go']['main']['App']['SwitchProfile'](arg1, arg2);
+}
+
+export function SyncBrokerPositions() {
+  return window['go']['main']['App']['SyncBrokerPositions']();
+}
+
+export function SynthesizeSpeech(arg1) {
+  return window['go']['main']['App']['SynthesizeSpeech'](arg1);
+}
+
 export function TestAIConnection(arg1) {
   return window['go']['main']['App']['TestAIConnection'](arg1);
 }
@@ -202,6 +430,14 @@ export function TestMCPConnection(arg1) {
   return window['go']['main']['App']['TestMCPConnection'](arg1);
 }
 
+export function TestPushChannel(arg1) {
+  return window['go']['main']['App']['TestPushChannel'](arg1);
+}
+
+export function UnlockData(arg1) {
+  return window['go']['main']['App']['UnlockData'](arg1);
+}
+
 export function UpdateAgentConfig(arg1) {
   return window['go']['main']['App']['UpdateAgentConfig'](arg1);
 }
@@ -214,6 +450,10 @@ export function UpdateMCPServer(arg1) {
   return window['go']['main']['App']['UpdateMCPServer'](arg1);
 }
 
+export function UpdateMeetingTemplate(arg1) {
+  return window['go']['main']['App']['UpdateMeetingTemplate'](arg1);
+}
+
 export function UpdateStockPosition(arg1, arg2, arg3) {
   return window['go']['main']['App']['UpdateStockPosition'](arg1, arg2, arg3);
 }
@@ -222,6 +462,10 @@ export function UpdateStrategy(arg1) {
   return window['go']['main']['App']['UpdateStrategy'](arg1);
 }
 
+export function UpgradeAgentPresets(arg1, arg2) {
+  return window['go']['main']['App']['UpgradeAgentPresets'](arg1, arg2);
+}
+
 export function WindowClose() {
   return window['go']['main']['App']['WindowClose']();
 }
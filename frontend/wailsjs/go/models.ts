@@ -1,3 +1,62 @@
+export namespace broker {
+	
+	export class Order {
+	    id: string;
+	    symbol: string;
+	    name: string;
+	    side: string;
+	    price: number;
+	    shares: number;
+	    filledShares: number;
+	    filledPrice: number;
+	    status: string;
+	    message?: string;
+	    time: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Order(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.symbol = source["symbol"];
+	        this.name = source["name"];
+	        this.side = source["side"];
+	        this.price = source["price"];
+	        this.shares = source["shares"];
+	        this.filledShares = source["filledShares"];
+	        this.filledPrice = source["filledPrice"];
+	        this.status = source["status"];
+	        this.message = source["message"];
+	        this.time = source["time"];
+	    }
+	}
+	export class Position {
+	    symbol: string;
+	    name: string;
+	    shares: number;
+	    available: number;
+	    costPrice: number;
+	    marketValue: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Position(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.symbol = source["symbol"];
+	        this.name = source["name"];
+	        this.shares = source["shares"];
+	        this.available = source["available"];
+	        this.costPrice = source["costPrice"];
+	        this.marketValue = source["marketValue"];
+	    }
+	}
+
+}
+
 export namespace hottrend {
 	
 	export class HotItem {
@@ -86,6 +145,232 @@ export namespace hottrend {
 
 export namespace main {
 	
+	export class ABTestRequest {
+	    stockCode: string;
+	    agentId: string;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ABTestRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.agentId = source["agentId"];
+	        this.content = source["content"];
+	    }
+	}
+	export class ABTestResponse {
+	    success: boolean;
+	    testId?: string;
+	    messages: models.ChatMessage[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ABTestResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.testId = source["testId"];
+	        this.messages = this.convertValues(source["messages"], models.ChatMessage);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BackupResponse {
+	    success: boolean;
+	    cancelled?: boolean;
+	    path?: string;
+	    files: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.cancelled = source["cancelled"];
+	        this.path = source["path"];
+	        this.files = source["files"];
+	        this.error = source["error"];
+	    }
+	}
+	export class BriefingResponse {
+	    success: boolean;
+	    error?: string;
+	    briefing?: models.Briefing;
+	
+	    static createFrom(source: any = {}) {
+	        return new BriefingResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.briefing = this.convertValues(source["briefing"], models.Briefing);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BrokerOrdersResponse {
+	    success: boolean;
+	    error?: string;
+	    orders?: broker.Order[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BrokerOrdersResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.orders = this.convertValues(source["orders"], broker.Order);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BrokerSyncResponse {
+	    success: boolean;
+	    error?: string;
+	    positions?: broker.Position[];
+	    cleared?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BrokerSyncResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.positions = this.convertValues(source["positions"], broker.Position);
+	        this.cleared = source["cleared"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeepResearchResponse {
+	    success: boolean;
+	    researchId?: string;
+	    message?: models.ChatMessage;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeepResearchResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.researchId = source["researchId"];
+	        this.message = this.convertValues(source["message"], models.ChatMessage);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EncryptionStatus {
+	    enabled: boolean;
+	    locked: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EncryptionStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.locked = source["locked"];
+	    }
+	}
 	export class EnhancePromptRequest {
 	    originalPrompt: string;
 	    agentRole: string;
@@ -166,12 +451,28 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class ListModelsResponse {
+	    models: string[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ListModelsResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.models = source["models"];
+	        this.error = source["error"];
+	    }
+	}
 	export class MeetingMessageRequest {
 	    stockCode: string;
 	    content: string;
 	    mentionIds: string[];
 	    replyToId: string;
 	    replyContent: string;
+	    compareCode?: string;
+	    smart?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new MeetingMessageRequest(source);
@@ -184,236 +485,1715 @@ export namespace main {
 	        this.mentionIds = source["mentionIds"];
 	        this.replyToId = source["replyToId"];
 	        this.replyContent = source["replyContent"];
+	        this.compareCode = source["compareCode"];
+	        this.smart = source["smart"];
 	    }
 	}
-
-}
-
-export namespace mcp {
-	
-	export class ServerStatus {
-	    id: string;
-	    connected: boolean;
-	    error: string;
+	export class MeetingTemplateRunResponse {
+	    success: boolean;
+	    query?: string;
+	    messages: models.ChatMessage[];
+	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ServerStatus(source);
+	        return new MeetingTemplateRunResponse(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.connected = source["connected"];
+	        this.success = source["success"];
+	        this.query = source["query"];
+	        this.messages = this.convertValues(source["messages"], models.ChatMessage);
 	        this.error = source["error"];
 	    }
-	}
-	export class ToolInfo {
-	    name: string;
-	    description: string;
-	    serverId: string;
-	    serverName: string;
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PaperOrderResponse {
+	    success: boolean;
+	    error?: string;
+	    order?: models.PaperOrder;
 	
 	    static createFrom(source: any = {}) {
-	        return new ToolInfo(source);
+	        return new PaperOrderResponse(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.serverId = source["serverId"];
-	        this.serverName = source["serverName"];
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.order = this.convertValues(source["order"], models.PaperOrder);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProfilesInfo {
+	    current: string;
+	    profiles: string[];
+	    dataDir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProfilesInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.current = source["current"];
+	        this.profiles = source["profiles"];
+	        this.dataDir = source["dataDir"];
+	    }
+	}
+	export class PromptPreviewRequest {
+	    stockCode: string;
+	    agentId: string;
+	    query: string;
+	    history: meeting.DiscussionEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptPreviewRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.agentId = source["agentId"];
+	        this.query = source["query"];
+	        this.history = this.convertValues(source["history"], meeting.DiscussionEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptPreviewResponse {
+	    preview?: meeting.PromptPreview;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptPreviewResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.preview = this.convertValues(source["preview"], meeting.PromptPreview);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SpeechResponse {
+	    success: boolean;
+	    id?: string;
+	    path?: string;
+	    mimeType?: string;
+	    audio?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SpeechResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.id = source["id"];
+	        this.path = source["path"];
+	        this.mimeType = source["mimeType"];
+	        this.audio = source["audio"];
+	        this.error = source["error"];
+	    }
+	}
+	export class TradePlanResponse {
+	    success: boolean;
+	    error?: string;
+	    plan?: models.TradePlan;
+	
+	    static createFrom(source: any = {}) {
+	        return new TradePlanResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.plan = this.convertValues(source["plan"], models.TradePlan);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
 
-export namespace models {
+export namespace mcp {
 	
-	export class AIConfig {
+	export class ServerStatus {
 	    id: string;
-	    name: string;
-	    provider: string;
-	    baseUrl: string;
-	    apiKey: string;
-	    modelName: string;
-	    maxTokens: number;
-	    temperature: number;
-	    timeout: number;
-	    isDefault: boolean;
-	    useResponses: boolean;
-	    noSystemRole: boolean;
-	    project: string;
-	    location: string;
-	    credentialsJson: string;
+	    connected: boolean;
+	    error: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new AIConfig(source);
+	        return new ServerStatus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.provider = source["provider"];
-	        this.baseUrl = source["baseUrl"];
-	        this.apiKey = source["apiKey"];
-	        this.modelName = source["modelName"];
-	        this.maxTokens = source["maxTokens"];
-	        this.temperature = source["temperature"];
-	        this.timeout = source["timeout"];
-	        this.isDefault = source["isDefault"];
-	        this.useResponses = source["useResponses"];
-	        this.noSystemRole = source["noSystemRole"];
-	        this.project = source["project"];
-	        this.location = source["location"];
-	        this.credentialsJson = source["credentialsJson"];
+	        this.connected = source["connected"];
+	        this.error = source["error"];
 	    }
 	}
-	export class AgentConfig {
-	    id: string;
+	export class ToolInfo {
 	    name: string;
-	    role: string;
-	    avatar: string;
-	    color: string;
-	    instruction: string;
-	    tools: string[];
-	    mcpServers: string[];
-	    enabled: boolean;
-	    aiConfigId: string;
+	    description: string;
+	    serverId: string;
+	    serverName: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new AgentConfig(source);
+	        return new ToolInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
 	        this.name = source["name"];
-	        this.role = source["role"];
-	        this.avatar = source["avatar"];
-	        this.color = source["color"];
-	        this.instruction = source["instruction"];
-	        this.tools = source["tools"];
-	        this.mcpServers = source["mcpServers"];
-	        this.enabled = source["enabled"];
-	        this.aiConfigId = source["aiConfigId"];
+	        this.description = source["description"];
+	        this.serverId = source["serverId"];
+	        this.serverName = source["serverName"];
 	    }
 	}
-	export class KDJConfig {
-	    enabled: boolean;
-	    period: number;
-	    k: number;
-	    d: number;
+
+}
+
+export namespace meeting {
+	
+	export class ToolUsageStats {
+	    tool: string;
+	    calls: number;
+	    cited: number;
+	    citeRate: number;
+	    configured: boolean;
+	    lastUsed?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new KDJConfig(source);
+	        return new ToolUsageStats(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.period = source["period"];
-	        this.k = source["k"];
-	        this.d = source["d"];
+	        this.tool = source["tool"];
+	        this.calls = source["calls"];
+	        this.cited = source["cited"];
+	        this.citeRate = source["citeRate"];
+	        this.configured = source["configured"];
+	        this.lastUsed = source["lastUsed"];
 	    }
 	}
-	export class RSIConfig {
-	    enabled: boolean;
-	    period: number;
+	export class AgentToolUsage {
+	    agentId: string;
+	    agentName: string;
+	    speeches: number;
+	    toolCalls: number;
+	    tools: ToolUsageStats[];
+	    unused?: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new RSIConfig(source);
+	        return new AgentToolUsage(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.period = source["period"];
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.speeches = source["speeches"];
+	        this.toolCalls = source["toolCalls"];
+	        this.tools = this.convertValues(source["tools"], ToolUsageStats);
+	        this.unused = source["unused"];
 	    }
-	}
-	export class MACDConfig {
-	    enabled: boolean;
-	    fast: number;
-	    slow: number;
-	    signal: number;
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChatResponse {
+	    agentId: string;
+	    agentName: string;
+	    role: string;
+	    content: string;
+	    round: number;
+	    msgType: string;
+	    error?: string;
+	    meetingMode?: string;
+	    answeredBy?: string;
+	    toolCalls?: models.ToolCallRecord[];
+	    meetingId?: string;
+	    variant?: string;
+	    durationMs?: number;
+	    retries?: number;
+	    toolCallCount?: number;
+	    modelName?: string;
+	    report?: models.SummaryReport;
+	    consensus?: models.ConsensusScore;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChatResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.role = source["role"];
+	        this.content = source["content"];
+	        this.round = source["round"];
+	        this.msgType = source["msgType"];
+	        this.error = source["error"];
+	        this.meetingMode = source["meetingMode"];
+	        this.answeredBy = source["answeredBy"];
+	        this.toolCalls = this.convertValues(source["toolCalls"], models.ToolCallRecord);
+	        this.meetingId = source["meetingId"];
+	        this.variant = source["variant"];
+	        this.durationMs = source["durationMs"];
+	        this.retries = source["retries"];
+	        this.toolCallCount = source["toolCallCount"];
+	        this.modelName = source["modelName"];
+	        this.report = this.convertValues(source["report"], models.SummaryReport);
+	        this.consensus = this.convertValues(source["consensus"], models.ConsensusScore);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DiscussionEntry {
+	    round: number;
+	    agentId: string;
+	    agentName: string;
+	    role: string;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiscussionEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.round = source["round"];
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.role = source["role"];
+	        this.content = source["content"];
+	    }
+	}
+	export class ReplayEvent {
+	    offset: number;
+	    progress?: ProgressEvent;
+	    response?: ChatResponse;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplayEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.offset = source["offset"];
+	        this.progress = this.convertValues(source["progress"], ProgressEvent);
+	        this.response = this.convertValues(source["response"], ChatResponse);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MeetingReplay {
+	    meetingId: string;
+	    stockCode: string;
+	    stockName: string;
+	    query: string;
+	    mode: string;
+	    startedAt: number;
+	    endedAt: number;
+	    events: ReplayEvent[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingReplay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.meetingId = source["meetingId"];
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.query = source["query"];
+	        this.mode = source["mode"];
+	        this.startedAt = source["startedAt"];
+	        this.endedAt = source["endedAt"];
+	        this.events = this.convertValues(source["events"], ReplayEvent);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProgressEvent {
+	    type: string;
+	    agentId: string;
+	    agentName: string;
+	    detail: string;
+	    content: string;
+	    meetingId?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProgressEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.detail = source["detail"];
+	        this.content = source["content"];
+	        this.meetingId = source["meetingId"];
+	    }
+	}
+	export class PromptPreview {
+	    agentId: string;
+	    agentName: string;
+	    modelName: string;
+	    instruction: string;
+	    userMessage: string;
+	    length: number;
+	    tokens: number;
+	    userProfile: string;
+	    extraContext: string;
+	    memoryContext: string;
+	    previousContext: string;
+	    toolsEnabled: boolean;
+	    tools: string[];
+	    mcpServers: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.modelName = source["modelName"];
+	        this.instruction = source["instruction"];
+	        this.userMessage = source["userMessage"];
+	        this.length = source["length"];
+	        this.tokens = source["tokens"];
+	        this.userProfile = source["userProfile"];
+	        this.extraContext = source["extraContext"];
+	        this.memoryContext = source["memoryContext"];
+	        this.previousContext = source["previousContext"];
+	        this.toolsEnabled = source["toolsEnabled"];
+	        this.tools = source["tools"];
+	        this.mcpServers = source["mcpServers"];
+	    }
+	}
+	export class ReplaySummary {
+	    meetingId: string;
+	    stockCode: string;
+	    stockName: string;
+	    query: string;
+	    mode: string;
+	    startedAt: number;
+	    endedAt: number;
+	    events: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplaySummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.meetingId = source["meetingId"];
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.query = source["query"];
+	        this.mode = source["mode"];
+	        this.startedAt = source["startedAt"];
+	        this.endedAt = source["endedAt"];
+	        this.events = source["events"];
+	    }
+	}
+	export class ResearchSummary {
+	    id: string;
+	    stockCode: string;
+	    query: string;
+	    status: string;
+	    steps: number;
+	    completed: number;
+	    error?: string;
+	    startedAt: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ResearchSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.stockCode = source["stockCode"];
+	        this.query = source["query"];
+	        this.status = source["status"];
+	        this.steps = source["steps"];
+	        this.completed = source["completed"];
+	        this.error = source["error"];
+	        this.startedAt = source["startedAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+
+}
+
+export namespace models {
+	
+	export class SafetySetting {
+	    category: string;
+	    threshold: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SafetySetting(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.category = source["category"];
+	        this.threshold = source["threshold"];
+	    }
+	}
+	export class ModelPricing {
+	    inputPerMillion: number;
+	    outputPerMillion: number;
+	    cachedInputPerMillion: number;
+	    cacheWriteInputPerMillion: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelPricing(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inputPerMillion = source["inputPerMillion"];
+	        this.outputPerMillion = source["outputPerMillion"];
+	        this.cachedInputPerMillion = source["cachedInputPerMillion"];
+	        this.cacheWriteInputPerMillion = source["cacheWriteInputPerMillion"];
+	    }
+	}
+	export class ModelCapabilities {
+	    probedAt: number;
+	    streaming: boolean;
+	    toolCalling: boolean;
+	    jsonMode: boolean;
+	    systemRole: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelCapabilities(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.probedAt = source["probedAt"];
+	        this.streaming = source["streaming"];
+	        this.toolCalling = source["toolCalling"];
+	        this.jsonMode = source["jsonMode"];
+	        this.systemRole = source["systemRole"];
+	    }
+	}
+	export class AIHTTPConfig {
+	    connectTimeout: number;
+	    readTimeout: number;
+	    streamIdleTimeout: number;
+	    idleConnTimeout: number;
+	    disableKeepAlives: boolean;
+	    caCert: string;
+	    insecureSkipVerify: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new AIHTTPConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connectTimeout = source["connectTimeout"];
+	        this.readTimeout = source["readTimeout"];
+	        this.streamIdleTimeout = source["streamIdleTimeout"];
+	        this.idleConnTimeout = source["idleConnTimeout"];
+	        this.disableKeepAlives = source["disableKeepAlives"];
+	        this.caCert = source["caCert"];
+	        this.insecureSkipVerify = source["insecureSkipVerify"];
+	    }
+	}
+	export class AIConfig {
+	    id: string;
+	    name: string;
+	    provider: string;
+	    baseUrl: string;
+	    apiKey: string;
+	    apiKeys?: string[];
+	    modelName: string;
+	    maxTokens: number;
+	    temperature: number;
+	    timeout: number;
+	    isDefault: boolean;
+	    useResponses: boolean;
+	    apiStyle?: string;
+	    detectedApiStyle?: string;
+	    noSystemRole: boolean;
+	    capabilities: ModelCapabilities;
+	    toolGuide?: string;
+	    fallbackIds?: string[];
+	    pricing: ModelPricing;
+	    proxy: ProxyConfig;
+	    http: AIHTTPConfig;
+	    extraHeaders?: Record<string, string>;
+	    extraBody?: Record<string, any>;
+	    project: string;
+	    location: string;
+	    credentialsJson: string;
+	    thinkingBudget?: number;
+	    safetySettings?: SafetySetting[];
+	    scenario?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AIConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.provider = source["provider"];
+	        this.baseUrl = source["baseUrl"];
+	        this.apiKey = source["apiKey"];
+	        this.apiKeys = source["apiKeys"];
+	        this.modelName = source["modelName"];
+	        this.maxTokens = source["maxTokens"];
+	        this.temperature = source["temperature"];
+	        this.timeout = source["timeout"];
+	        this.isDefault = source["isDefault"];
+	        this.useResponses = source["useResponses"];
+	        this.apiStyle = source["apiStyle"];
+	        this.detectedApiStyle = source["detectedApiStyle"];
+	        this.noSystemRole = source["noSystemRole"];
+	        this.capabilities = this.convertValues(source["capabilities"], ModelCapabilities);
+	        this.toolGuide = source["toolGuide"];
+	        this.fallbackIds = source["fallbackIds"];
+	        this.pricing = this.convertValues(source["pricing"], ModelPricing);
+	        this.proxy = this.convertValues(source["proxy"], ProxyConfig);
+	        this.http = this.convertValues(source["http"], AIHTTPConfig);
+	        this.extraHeaders = source["extraHeaders"];
+	        this.extraBody = source["extraBody"];
+	        this.project = source["project"];
+	        this.location = source["location"];
+	        this.credentialsJson = source["credentialsJson"];
+	        this.thinkingBudget = source["thinkingBudget"];
+	        this.safetySettings = this.convertValues(source["safetySettings"], SafetySetting);
+	        this.scenario = source["scenario"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AIRoutingConfig {
+	    expert: string;
+	    moderator: string;
+	    summary: string;
+	    memory: string;
+	    extraction: string;
+	    strategy: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AIRoutingConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.expert = source["expert"];
+	        this.moderator = source["moderator"];
+	        this.summary = source["summary"];
+	        this.memory = source["memory"];
+	        this.extraction = source["extraction"];
+	        this.strategy = source["strategy"];
+	    }
+	}
+	export class AgentConfig {
+	    id: string;
+	    name: string;
+	    role: string;
+	    avatar: string;
+	    color: string;
+	    instruction: string;
+	    tools: string[];
+	    mcpServers: string[];
+	    enabled: boolean;
+	    aiConfigId: string;
+	    unifiedTools?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.role = source["role"];
+	        this.avatar = source["avatar"];
+	        this.color = source["color"];
+	        this.instruction = source["instruction"];
+	        this.tools = source["tools"];
+	        this.mcpServers = source["mcpServers"];
+	        this.enabled = source["enabled"];
+	        this.aiConfigId = source["aiConfigId"];
+	        this.unifiedTools = source["unifiedTools"];
+	    }
+	}
+	export class KDJConfig {
+	    enabled: boolean;
+	    period: number;
+	    k: number;
+	    d: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new KDJConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.period = source["period"];
+	        this.k = source["k"];
+	        this.d = source["d"];
+	    }
+	}
+	export class RSIConfig {
+	    enabled: boolean;
+	    period: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RSIConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.period = source["period"];
+	    }
+	}
+	export class MACDConfig {
+	    enabled: boolean;
+	    fast: number;
+	    slow: number;
+	    signal: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MACDConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.fast = source["fast"];
+	        this.slow = source["slow"];
+	        this.signal = source["signal"];
+	    }
+	}
+	export class BOLLConfig {
+	    enabled: boolean;
+	    period: number;
+	    multiplier: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BOLLConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.period = source["period"];
+	        this.multiplier = source["multiplier"];
+	    }
+	}
+	export class EMAConfig {
+	    enabled: boolean;
+	    periods: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EMAConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.periods = source["periods"];
+	    }
+	}
+	export class MAConfig {
+	    enabled: boolean;
+	    periods: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MAConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.periods = source["periods"];
+	    }
+	}
+	export class IndicatorConfig {
+	    ma: MAConfig;
+	    ema: EMAConfig;
+	    boll: BOLLConfig;
+	    macd: MACDConfig;
+	    rsi: RSIConfig;
+	    kdj: KDJConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new IndicatorConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ma = this.convertValues(source["ma"], MAConfig);
+	        this.ema = this.convertValues(source["ema"], EMAConfig);
+	        this.boll = this.convertValues(source["boll"], BOLLConfig);
+	        this.macd = this.convertValues(source["macd"], MACDConfig);
+	        this.rsi = this.convertValues(source["rsi"], RSIConfig);
+	        this.kdj = this.convertValues(source["kdj"], KDJConfig);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OpenClawConfig {
+	    enabled: boolean;
+	    port: number;
+	    apiKey: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OpenClawConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	        this.apiKey = source["apiKey"];
+	    }
+	}
+	export class LayoutConfig {
+	    leftPanelWidth: number;
+	    rightPanelWidth: number;
+	    bottomPanelHeight: number;
+	    windowWidth: number;
+	    windowHeight: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LayoutConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.leftPanelWidth = source["leftPanelWidth"];
+	        this.rightPanelWidth = source["rightPanelWidth"];
+	        this.bottomPanelHeight = source["bottomPanelHeight"];
+	        this.windowWidth = source["windowWidth"];
+	        this.windowHeight = source["windowHeight"];
+	    }
+	}
+	export class ProxyConfig {
+	    mode: string;
+	    customUrl: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProxyConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.customUrl = source["customUrl"];
+	    }
+	}
+	export class MemoryConfig {
+	    enabled: boolean;
+	    aiConfigId: string;
+	    maxRecentRounds: number;
+	    maxKeyFacts: number;
+	    maxSummaryLength: number;
+	    compressThreshold: number;
+	    consolidateInterval: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.aiConfigId = source["aiConfigId"];
+	        this.maxRecentRounds = source["maxRecentRounds"];
+	        this.maxKeyFacts = source["maxKeyFacts"];
+	        this.maxSummaryLength = source["maxSummaryLength"];
+	        this.compressThreshold = source["compressThreshold"];
+	        this.consolidateInterval = source["consolidateInterval"];
+	    }
+	}
+	export class MCPServerConfig {
+	    id: string;
+	    name: string;
+	    transportType: string;
+	    endpoint: string;
+	    command: string;
+	    args: string[];
+	    toolFilter: string[];
+	    enabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPServerConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.transportType = source["transportType"];
+	        this.endpoint = source["endpoint"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.toolFilter = source["toolFilter"];
+	        this.enabled = source["enabled"];
+	    }
+	}
+	export class UserProfile {
+	    riskTolerance: string;
+	    holdingHorizon: string;
+	    strategies: string[];
+	    accountSize: string;
+	    notes: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UserProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.riskTolerance = source["riskTolerance"];
+	        this.holdingHorizon = source["holdingHorizon"];
+	        this.strategies = source["strategies"];
+	        this.accountSize = source["accountSize"];
+	        this.notes = source["notes"];
+	    }
+	}
+	export class CustomToolConfig {
+	    name: string;
+	    description: string;
+	    method: string;
+	    url: string;
+	    headers: Record<string, string>;
+	    body: string;
+	    inputSchema: string;
+	    extract: string;
+	    enabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CustomToolConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.method = source["method"];
+	        this.url = source["url"];
+	        this.headers = source["headers"];
+	        this.body = source["body"];
+	        this.inputSchema = source["inputSchema"];
+	        this.extract = source["extract"];
+	        this.enabled = source["enabled"];
+	    }
+	}
+	export class PythonToolConfig {
+	    enabled: boolean;
+	    interpreter: string;
+	    timeout: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PythonToolConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.interpreter = source["interpreter"];
+	        this.timeout = source["timeout"];
+	    }
+	}
+	export class BrokerConfig {
+	    enabled: boolean;
+	    type: string;
+	    baseUrl: string;
+	    token: string;
+	    accountId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BrokerConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.type = source["type"];
+	        this.baseUrl = source["baseUrl"];
+	        this.token = source["token"];
+	        this.accountId = source["accountId"];
+	    }
+	}
+	export class NotificationConfig {
+	    muted: boolean;
+	    disabledCategories: string[];
+	    quietStart: string;
+	    quietEnd: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.muted = source["muted"];
+	        this.disabledCategories = source["disabledCategories"];
+	        this.quietStart = source["quietStart"];
+	        this.quietEnd = source["quietEnd"];
+	    }
+	}
+	export class PushChannelConfig {
+	    id: string;
+	    name: string;
+	    type: string;
+	    enabled: boolean;
+	    webhookUrl: string;
+	    secret?: string;
+	    botToken?: string;
+	    chatId?: string;
+	    categories?: string[];
+	    template?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PushChannelConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.enabled = source["enabled"];
+	        this.webhookUrl = source["webhookUrl"];
+	        this.secret = source["secret"];
+	        this.botToken = source["botToken"];
+	        this.chatId = source["chatId"];
+	        this.categories = source["categories"];
+	        this.template = source["template"];
+	    }
+	}
+	export class TTSConfig {
+	    provider: string;
+	    aiConfigId: string;
+	    model: string;
+	    voice: string;
+	    speed: number;
+	    autoBriefing: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TTSConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.aiConfigId = source["aiConfigId"];
+	        this.model = source["model"];
+	        this.voice = source["voice"];
+	        this.speed = source["speed"];
+	        this.autoBriefing = source["autoBriefing"];
+	    }
+	}
+	export class ContextBudgetConfig {
+	    memory: number;
+	    speeches: number;
+	    toolGuide: number;
+	    llmCompress: boolean;
+	    toolOutput: number;
+	    toolOutputLimits?: Record<string, number>;
+	    toolSummarize: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ContextBudgetConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.memory = source["memory"];
+	        this.speeches = source["speeches"];
+	        this.toolGuide = source["toolGuide"];
+	        this.llmCompress = source["llmCompress"];
+	        this.toolOutput = source["toolOutput"];
+	        this.toolOutputLimits = source["toolOutputLimits"];
+	        this.toolSummarize = source["toolSummarize"];
+	    }
+	}
+	export class AppConfig {
+	    theme: string;
+	    candleColorMode: string;
+	    language: string;
+	    aiConfigs: AIConfig[];
+	    defaultAiId: string;
+	    strategyAiId: string;
+	    moderatorAiId: string;
+	    mcpServers: MCPServerConfig[];
+	    customTools: CustomToolConfig[];
+	    disabledTools: string[];
+	    pythonTool: PythonToolConfig;
+	    memory: MemoryConfig;
+	    proxy: ProxyConfig;
+	    layout: LayoutConfig;
+	    openClaw: OpenClawConfig;
+	    indicators: IndicatorConfig;
+	    meetingBudget: number;
+	    maxConcurrentMeetings: number;
+	    contextBudget: ContextBudgetConfig;
+	    aiRouting: AIRoutingConfig;
+	    userProfile: UserProfile;
+	    broker: BrokerConfig;
+	    notification: NotificationConfig;
+	    pushChannels: PushChannelConfig[];
+	    tts: TTSConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new AppConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.theme = source["theme"];
+	        this.candleColorMode = source["candleColorMode"];
+	        this.language = source["language"];
+	        this.aiConfigs = this.convertValues(source["aiConfigs"], AIConfig);
+	        this.defaultAiId = source["defaultAiId"];
+	        this.strategyAiId = source["strategyAiId"];
+	        this.moderatorAiId = source["moderatorAiId"];
+	        this.mcpServers = this.convertValues(source["mcpServers"], MCPServerConfig);
+	        this.customTools = this.convertValues(source["customTools"], CustomToolConfig);
+	        this.disabledTools = source["disabledTools"];
+	        this.pythonTool = this.convertValues(source["pythonTool"], PythonToolConfig);
+	        this.memory = this.convertValues(source["memory"], MemoryConfig);
+	        this.proxy = this.convertValues(source["proxy"], ProxyConfig);
+	        this.layout = this.convertValues(source["layout"], LayoutConfig);
+	        this.openClaw = this.convertValues(source["openClaw"], OpenClawConfig);
+	        this.indicators = this.convertValues(source["indicators"], IndicatorConfig);
+	        this.meetingBudget = source["meetingBudget"];
+	        this.maxConcurrentMeetings = source["maxConcurrentMeetings"];
+	        this.contextBudget = this.convertValues(source["contextBudget"], ContextBudgetConfig);
+	        this.aiRouting = this.convertValues(source["aiRouting"], AIRoutingConfig);
+	        this.userProfile = this.convertValues(source["userProfile"], UserProfile);
+	        this.broker = this.convertValues(source["broker"], BrokerConfig);
+	        this.notification = this.convertValues(source["notification"], NotificationConfig);
+	        this.pushChannels = this.convertValues(source["pushChannels"], PushChannelConfig);
+	        this.tts = this.convertValues(source["tts"], TTSConfig);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ConsensusVote {
+	    agentId: string;
+	    agentName: string;
+	    stance: string;
+	    confidence: number;
+	    weight: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConsensusVote(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.stance = source["stance"];
+	        this.confidence = source["confidence"];
+	        this.weight = source["weight"];
+	    }
+	}
+	export class ConsensusScore {
+	    score: number;
+	    label: string;
+	    bull: number;
+	    bear: number;
+	    neutral: number;
+	    votes: ConsensusVote[];
+	    price?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConsensusScore(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.score = source["score"];
+	        this.label = source["label"];
+	        this.bull = source["bull"];
+	        this.bear = source["bear"];
+	        this.neutral = source["neutral"];
+	        this.votes = this.convertValues(source["votes"], ConsensusVote);
+	        this.price = source["price"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SummaryReport {
+	    conclusion: string;
+	    divergence?: string;
+	    keyData?: string;
+	    risks?: string;
+	    actions?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SummaryReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.conclusion = source["conclusion"];
+	        this.divergence = source["divergence"];
+	        this.keyData = source["keyData"];
+	        this.risks = source["risks"];
+	        this.actions = source["actions"];
+	    }
+	}
+	export class ChatMessage {
+	    id: string;
+	    agentId: string;
+	    agentName: string;
+	    role: string;
+	    content: string;
+	    timestamp: number;
+	    replyTo?: string;
+	    mentions?: string[];
+	    round?: number;
+	    msgType?: string;
+	    error?: string;
+	    errorCode?: string;
+	    meetingMode?: string;
+	    answeredBy?: string;
+	    toolCalls?: ToolCallRecord[];
+	    meetingId?: string;
+	    variant?: string;
+	    abTestId?: string;
+	    durationMs?: number;
+	    retries?: number;
+	    toolCallCount?: number;
+	    modelName?: string;
+	    report?: SummaryReport;
+	    consensus?: ConsensusScore;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChatMessage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.role = source["role"];
+	        this.content = source["content"];
+	        this.timestamp = source["timestamp"];
+	        this.replyTo = source["replyTo"];
+	        this.mentions = source["mentions"];
+	        this.round = source["round"];
+	        this.msgType = source["msgType"];
+	        this.error = source["error"];
+	        this.errorCode = source["errorCode"];
+	        this.meetingMode = source["meetingMode"];
+	        this.answeredBy = source["answeredBy"];
+	        this.toolCalls = this.convertValues(source["toolCalls"], ToolCallRecord);
+	        this.meetingId = source["meetingId"];
+	        this.variant = source["variant"];
+	        this.abTestId = source["abTestId"];
+	        this.durationMs = source["durationMs"];
+	        this.retries = source["retries"];
+	        this.toolCallCount = source["toolCallCount"];
+	        this.modelName = source["modelName"];
+	        this.report = this.convertValues(source["report"], SummaryReport);
+	        this.consensus = this.convertValues(source["consensus"], ConsensusScore);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class KLineData {
+	    time: string;
+	    open: number;
+	    high: number;
+	    low: number;
+	    close: number;
+	    volume: number;
+	    amount?: number;
+	    avg?: number;
+	    netInflow?: number;
+	    ma5?: number;
+	    ma10?: number;
+	    ma20?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new KLineData(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.open = source["open"];
+	        this.high = source["high"];
+	        this.low = source["low"];
+	        this.close = source["close"];
+	        this.volume = source["volume"];
+	        this.amount = source["amount"];
+	        this.avg = source["avg"];
+	        this.netInflow = source["netInflow"];
+	        this.ma5 = source["ma5"];
+	        this.ma10 = source["ma10"];
+	        this.ma20 = source["ma20"];
+	    }
+	}
+	
+	export class LimitBoardItem {
+	    code: string;
+	    name: string;
+	    price: number;
+	    changePercent: number;
+	    amount: number;
+	    turnoverRate: number;
+	    floatCap: number;
+	    sealFund: number;
+	    firstSealTime: string;
+	    lastSealTime: string;
+	    openTimes: number;
+	    boards: number;
+	    industry: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LimitBoardItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.price = source["price"];
+	        this.changePercent = source["changePercent"];
+	        this.amount = source["amount"];
+	        this.turnoverRate = source["turnoverRate"];
+	        this.floatCap = source["floatCap"];
+	        this.sealFund = source["sealFund"];
+	        this.firstSealTime = source["firstSealTime"];
+	        this.lastSealTime = source["lastSealTime"];
+	        this.openTimes = source["openTimes"];
+	        this.boards = source["boards"];
+	        this.industry = source["industry"];
+	    }
+	}
+	export class LimitBoard {
+	    date: string;
+	    limitUp: LimitBoardItem[];
+	    limitDown: LimitBoardItem[];
+	    broken: LimitBoardItem[];
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LimitBoard(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.limitUp = this.convertValues(source["limitUp"], LimitBoardItem);
+	        this.limitDown = this.convertValues(source["limitDown"], LimitBoardItem);
+	        this.broken = this.convertValues(source["broken"], LimitBoardItem);
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LongHuBangDetail {
+	    rank: number;
+	    operName: string;
+	    buyAmt: number;
+	    buyPercent: number;
+	    sellAmt: number;
+	    sellPercent: number;
+	    netAmt: number;
+	    direction: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new MACDConfig(source);
+	        return new LongHuBangDetail(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.fast = source["fast"];
-	        this.slow = source["slow"];
-	        this.signal = source["signal"];
+	        this.rank = source["rank"];
+	        this.operName = source["operName"];
+	        this.buyAmt = source["buyAmt"];
+	        this.buyPercent = source["buyPercent"];
+	        this.sellAmt = source["sellAmt"];
+	        this.sellPercent = source["sellPercent"];
+	        this.netAmt = source["netAmt"];
+	        this.direction = source["direction"];
 	    }
 	}
-	export class BOLLConfig {
-	    enabled: boolean;
-	    period: number;
-	    multiplier: number;
+	export class LongHuBangItem {
+	    tradeDate: string;
+	    code: string;
+	    secuCode: string;
+	    name: string;
+	    closePrice: number;
+	    changePercent: number;
+	    netBuyAmt: number;
+	    buyAmt: number;
+	    sellAmt: number;
+	    totalAmt: number;
+	    turnoverRate: number;
+	    freeCap: number;
+	    reason: string;
+	    reasonDetail: string;
+	    accumAmount: number;
+	    dealRatio: number;
+	    netRatio: number;
+	    d1Change: number;
+	    d2Change: number;
+	    d5Change: number;
+	    d10Change: number;
+	    securityType: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new BOLLConfig(source);
+	        return new LongHuBangItem(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.period = source["period"];
-	        this.multiplier = source["multiplier"];
+	        this.tradeDate = source["tradeDate"];
+	        this.code = source["code"];
+	        this.secuCode = source["secuCode"];
+	        this.name = source["name"];
+	        this.closePrice = source["closePrice"];
+	        this.changePercent = source["changePercent"];
+	        this.netBuyAmt = source["netBuyAmt"];
+	        this.buyAmt = source["buyAmt"];
+	        this.sellAmt = source["sellAmt"];
+	        this.totalAmt = source["totalAmt"];
+	        this.turnoverRate = source["turnoverRate"];
+	        this.freeCap = source["freeCap"];
+	        this.reason = source["reason"];
+	        this.reasonDetail = source["reasonDetail"];
+	        this.accumAmount = source["accumAmount"];
+	        this.dealRatio = source["dealRatio"];
+	        this.netRatio = source["netRatio"];
+	        this.d1Change = source["d1Change"];
+	        this.d2Change = source["d2Change"];
+	        this.d5Change = source["d5Change"];
+	        this.d10Change = source["d10Change"];
+	        this.securityType = source["securityType"];
 	    }
 	}
-	export class EMAConfig {
-	    enabled: boolean;
-	    periods: number[];
 	
-	    static createFrom(source: any = {}) {
-	        return new EMAConfig(source);
-	    }
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.periods = source["periods"];
-	    }
-	}
-	export class MAConfig {
-	    enabled: boolean;
-	    periods: number[];
+	
+	export class SectorPerf {
+	    code: string;
+	    name: string;
+	    changePercent: number;
+	    amount: number;
+	    netInflow: number;
+	    upCount: number;
+	    downCount: number;
+	    leaderCode: string;
+	    leaderName: string;
+	    leaderChange: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new MAConfig(source);
+	        return new SectorPerf(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.periods = source["periods"];
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.changePercent = source["changePercent"];
+	        this.amount = source["amount"];
+	        this.netInflow = source["netInflow"];
+	        this.upCount = source["upCount"];
+	        this.downCount = source["downCount"];
+	        this.leaderCode = source["leaderCode"];
+	        this.leaderName = source["leaderName"];
+	        this.leaderChange = source["leaderChange"];
 	    }
 	}
-	export class IndicatorConfig {
-	    ma: MAConfig;
-	    ema: EMAConfig;
-	    boll: BOLLConfig;
-	    macd: MACDConfig;
-	    rsi: RSIConfig;
-	    kdj: KDJConfig;
+	export class MarketBreadth {
+	    up: number;
+	    down: number;
+	    flat: number;
+	    sectors: SectorPerf[];
+	    updatedAt: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new IndicatorConfig(source);
+	        return new MarketBreadth(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.ma = this.convertValues(source["ma"], MAConfig);
-	        this.ema = this.convertValues(source["ema"], EMAConfig);
-	        this.boll = this.convertValues(source["boll"], BOLLConfig);
-	        this.macd = this.convertValues(source["macd"], MACDConfig);
-	        this.rsi = this.convertValues(source["rsi"], RSIConfig);
-	        this.kdj = this.convertValues(source["kdj"], KDJConfig);
+	        this.up = source["up"];
+	        this.down = source["down"];
+	        this.flat = source["flat"];
+	        this.sectors = this.convertValues(source["sectors"], SectorPerf);
+	        this.updatedAt = source["updatedAt"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -434,136 +2214,136 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class OpenClawConfig {
-	    enabled: boolean;
-	    port: number;
-	    apiKey: string;
+	export class MeetingAdvice {
+	    action: string;
+	    summary: string;
+	    shares: number;
+	    createdAt: number;
+	    diverged: boolean;
+	    divergedAt?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new OpenClawConfig(source);
+	        return new MeetingAdvice(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.port = source["port"];
-	        this.apiKey = source["apiKey"];
+	        this.action = source["action"];
+	        this.summary = source["summary"];
+	        this.shares = source["shares"];
+	        this.createdAt = source["createdAt"];
+	        this.diverged = source["diverged"];
+	        this.divergedAt = source["divergedAt"];
 	    }
 	}
-	export class LayoutConfig {
-	    leftPanelWidth: number;
-	    rightPanelWidth: number;
-	    bottomPanelHeight: number;
-	    windowWidth: number;
-	    windowHeight: number;
 	
-	    static createFrom(source: any = {}) {
-	        return new LayoutConfig(source);
-	    }
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.leftPanelWidth = source["leftPanelWidth"];
-	        this.rightPanelWidth = source["rightPanelWidth"];
-	        this.bottomPanelHeight = source["bottomPanelHeight"];
-	        this.windowWidth = source["windowWidth"];
-	        this.windowHeight = source["windowHeight"];
-	    }
-	}
-	export class ProxyConfig {
-	    mode: string;
-	    customUrl: string;
+	export class ModelPreset {
+	    name: string;
+	    contextWindow: number;
+	    reasoning: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new ProxyConfig(source);
+	        return new ModelPreset(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.mode = source["mode"];
-	        this.customUrl = source["customUrl"];
+	        this.name = source["name"];
+	        this.contextWindow = source["contextWindow"];
+	        this.reasoning = source["reasoning"];
 	    }
 	}
-	export class MemoryConfig {
-	    enabled: boolean;
-	    aiConfigId: string;
-	    maxRecentRounds: number;
-	    maxKeyFacts: number;
-	    maxSummaryLength: number;
-	    compressThreshold: number;
+	
+	
+	export class OrderBookItem {
+	    price: number;
+	    size: number;
+	    total: number;
+	    percent: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new MemoryConfig(source);
+	        return new OrderBookItem(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.aiConfigId = source["aiConfigId"];
-	        this.maxRecentRounds = source["maxRecentRounds"];
-	        this.maxKeyFacts = source["maxKeyFacts"];
-	        this.maxSummaryLength = source["maxSummaryLength"];
-	        this.compressThreshold = source["compressThreshold"];
+	        this.price = source["price"];
+	        this.size = source["size"];
+	        this.total = source["total"];
+	        this.percent = source["percent"];
 	    }
 	}
-	export class MCPServerConfig {
-	    id: string;
-	    name: string;
-	    transportType: string;
-	    endpoint: string;
-	    command: string;
-	    args: string[];
-	    toolFilter: string[];
-	    enabled: boolean;
+	export class WithdrawnOrder {
+	    side: string;
+	    price: number;
+	    size: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new MCPServerConfig(source);
+	        return new WithdrawnOrder(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.transportType = source["transportType"];
-	        this.endpoint = source["endpoint"];
-	        this.command = source["command"];
-	        this.args = source["args"];
-	        this.toolFilter = source["toolFilter"];
-	        this.enabled = source["enabled"];
+	        this.side = source["side"];
+	        this.price = source["price"];
+	        this.size = source["size"];
 	    }
 	}
-	export class AppConfig {
-	    theme: string;
-	    candleColorMode: string;
-	    aiConfigs: AIConfig[];
-	    defaultAiId: string;
-	    strategyAiId: string;
-	    moderatorAiId: string;
-	    mcpServers: MCPServerConfig[];
-	    memory: MemoryConfig;
-	    proxy: ProxyConfig;
-	    layout: LayoutConfig;
-	    openClaw: OpenClawConfig;
-	    indicators: IndicatorConfig;
+	export class OrderBookMetrics {
+	    bidVolume: number;
+	    askVolume: number;
+	    imbalance: number;
+	    largeBids?: number[];
+	    largeAsks?: number[];
+	    withdrawn?: WithdrawnOrder[];
 	
 	    static createFrom(source: any = {}) {
-	        return new AppConfig(source);
+	        return new OrderBookMetrics(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.theme = source["theme"];
-	        this.candleColorMode = source["candleColorMode"];
-	        this.aiConfigs = this.convertValues(source["aiConfigs"], AIConfig);
-	        this.defaultAiId = source["defaultAiId"];
-	        this.strategyAiId = source["strategyAiId"];
-	        this.moderatorAiId = source["moderatorAiId"];
-	        this.mcpServers = this.convertValues(source["mcpServers"], MCPServerConfig);
-	        this.memory = this.convertValues(source["memory"], MemoryConfig);
-	        this.proxy = this.convertValues(source["proxy"], ProxyConfig);
-	        this.layout = this.convertValues(source["layout"], LayoutConfig);
-	        this.openClaw = this.convertValues(source["openClaw"], OpenClawConfig);
-	        this.indicators = this.convertValues(source["indicators"], IndicatorConfig);
+	        this.bidVolume = source["bidVolume"];
+	        this.askVolume = source["askVolume"];
+	        this.imbalance = source["imbalance"];
+	        this.largeBids = source["largeBids"];
+	        this.largeAsks = source["largeAsks"];
+	        this.withdrawn = this.convertValues(source["withdrawn"], WithdrawnOrder);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OrderBook {
+	    bids: OrderBookItem[];
+	    asks: OrderBookItem[];
+	    metrics?: OrderBookMetrics;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrderBook(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bids = this.convertValues(source["bids"], OrderBookItem);
+	        this.asks = this.convertValues(source["asks"], OrderBookItem);
+	        this.metrics = this.convertValues(source["metrics"], OrderBookMetrics);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -585,191 +2365,146 @@ export namespace models {
 		}
 	}
 	
-	export class ChatMessage {
-	    id: string;
-	    agentId: string;
-	    agentName: string;
-	    role: string;
-	    content: string;
-	    timestamp: number;
-	    replyTo?: string;
-	    mentions?: string[];
-	    round?: number;
-	    msgType?: string;
-	    error?: string;
-	    meetingMode?: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new ChatMessage(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.agentId = source["agentId"];
-	        this.agentName = source["agentName"];
-	        this.role = source["role"];
-	        this.content = source["content"];
-	        this.timestamp = source["timestamp"];
-	        this.replyTo = source["replyTo"];
-	        this.mentions = source["mentions"];
-	        this.round = source["round"];
-	        this.msgType = source["msgType"];
-	        this.error = source["error"];
-	        this.meetingMode = source["meetingMode"];
-	    }
-	}
-	
-	
-	
-	export class KLineData {
-	    time: string;
-	    open: number;
-	    high: number;
-	    low: number;
-	    close: number;
-	    volume: number;
-	    amount?: number;
-	    avg?: number;
-	    ma5?: number;
-	    ma10?: number;
-	    ma20?: number;
+	export class PaperEquityPoint {
+	    date: string;
+	    equity: number;
+	    cash: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new KLineData(source);
+	        return new PaperEquityPoint(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.time = source["time"];
-	        this.open = source["open"];
-	        this.high = source["high"];
-	        this.low = source["low"];
-	        this.close = source["close"];
-	        this.volume = source["volume"];
-	        this.amount = source["amount"];
-	        this.avg = source["avg"];
-	        this.ma5 = source["ma5"];
-	        this.ma10 = source["ma10"];
-	        this.ma20 = source["ma20"];
+	        this.date = source["date"];
+	        this.equity = source["equity"];
+	        this.cash = source["cash"];
 	    }
 	}
-	
-	export class LongHuBangDetail {
-	    rank: number;
-	    operName: string;
-	    buyAmt: number;
-	    buyPercent: number;
-	    sellAmt: number;
-	    sellPercent: number;
-	    netAmt: number;
-	    direction: string;
+	export class PaperOrder {
+	    id: string;
+	    symbol: string;
+	    name: string;
+	    side: string;
+	    type: string;
+	    price: number;
+	    shares: number;
+	    status: string;
+	    fillPrice: number;
+	    commission: number;
+	    source: string;
+	    reason?: string;
+	    createdAt: number;
+	    filledAt?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new LongHuBangDetail(source);
+	        return new PaperOrder(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.rank = source["rank"];
-	        this.operName = source["operName"];
-	        this.buyAmt = source["buyAmt"];
-	        this.buyPercent = source["buyPercent"];
-	        this.sellAmt = source["sellAmt"];
-	        this.sellPercent = source["sellPercent"];
-	        this.netAmt = source["netAmt"];
-	        this.direction = source["direction"];
+	        this.id = source["id"];
+	        this.symbol = source["symbol"];
+	        this.name = source["name"];
+	        this.side = source["side"];
+	        this.type = source["type"];
+	        this.price = source["price"];
+	        this.shares = source["shares"];
+	        this.status = source["status"];
+	        this.fillPrice = source["fillPrice"];
+	        this.commission = source["commission"];
+	        this.source = source["source"];
+	        this.reason = source["reason"];
+	        this.createdAt = source["createdAt"];
+	        this.filledAt = source["filledAt"];
 	    }
 	}
-	export class LongHuBangItem {
-	    tradeDate: string;
-	    code: string;
-	    secuCode: string;
+	export class PaperPosition {
+	    symbol: string;
 	    name: string;
-	    closePrice: number;
-	    changePercent: number;
-	    netBuyAmt: number;
-	    buyAmt: number;
-	    sellAmt: number;
-	    totalAmt: number;
-	    turnoverRate: number;
-	    freeCap: number;
-	    reason: string;
-	    reasonDetail: string;
-	    accumAmount: number;
-	    dealRatio: number;
-	    netRatio: number;
-	    d1Change: number;
-	    d2Change: number;
-	    d5Change: number;
-	    d10Change: number;
-	    securityType: string;
+	    shares: number;
+	    todayShares: number;
+	    tradeDate: string;
+	    costPrice: number;
+	    lastPrice: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new LongHuBangItem(source);
+	        return new PaperPosition(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.tradeDate = source["tradeDate"];
-	        this.code = source["code"];
-	        this.secuCode = source["secuCode"];
+	        this.symbol = source["symbol"];
 	        this.name = source["name"];
-	        this.closePrice = source["closePrice"];
-	        this.changePercent = source["changePercent"];
-	        this.netBuyAmt = source["netBuyAmt"];
-	        this.buyAmt = source["buyAmt"];
-	        this.sellAmt = source["sellAmt"];
-	        this.totalAmt = source["totalAmt"];
-	        this.turnoverRate = source["turnoverRate"];
-	        this.freeCap = source["freeCap"];
-	        this.reason = source["reason"];
-	        this.reasonDetail = source["reasonDetail"];
-	        this.accumAmount = source["accumAmount"];
-	        this.dealRatio = source["dealRatio"];
-	        this.netRatio = source["netRatio"];
-	        this.d1Change = source["d1Change"];
-	        this.d2Change = source["d2Change"];
-	        this.d5Change = source["d5Change"];
-	        this.d10Change = source["d10Change"];
-	        this.securityType = source["securityType"];
+	        this.shares = source["shares"];
+	        this.todayShares = source["todayShares"];
+	        this.tradeDate = source["tradeDate"];
+	        this.costPrice = source["costPrice"];
+	        this.lastPrice = source["lastPrice"];
 	    }
 	}
-	
-	
-	
-	
-	
-	export class OrderBookItem {
-	    price: number;
-	    size: number;
-	    total: number;
-	    percent: number;
+	export class PaperAccount {
+	    initialCash: number;
+	    cash: number;
+	    positions: PaperPosition[];
+	    orders: PaperOrder[];
+	    equityCurve: PaperEquityPoint[];
+	    equity: number;
+	    createdAt: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new OrderBookItem(source);
+	        return new PaperAccount(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.price = source["price"];
-	        this.size = source["size"];
-	        this.total = source["total"];
-	        this.percent = source["percent"];
+	        this.initialCash = source["initialCash"];
+	        this.cash = source["cash"];
+	        this.positions = this.convertValues(source["positions"], PaperPosition);
+	        this.orders = this.convertValues(source["orders"], PaperOrder);
+	        this.equityCurve = this.convertValues(source["equityCurve"], PaperEquityPoint);
+	        this.equity = source["equity"];
+	        this.createdAt = source["createdAt"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class OrderBook {
-	    bids: OrderBookItem[];
-	    asks: OrderBookItem[];
+	export class ProviderPreset {
+	    id: string;
+	    name: string;
+	    provider: string;
+	    baseUrl: string;
+	    listModels: boolean;
+	    models: ModelPreset[];
 	
 	    static createFrom(source: any = {}) {
-	        return new OrderBook(source);
+	        return new ProviderPreset(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.bids = this.convertValues(source["bids"], OrderBookItem);
-	        this.asks = this.convertValues(source["asks"], OrderBookItem);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.provider = source["provider"];
+	        this.baseUrl = source["baseUrl"];
+	        this.listModels = source["listModels"];
+	        this.models = this.convertValues(source["models"], ModelPreset);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -807,6 +2542,11 @@ export namespace models {
 	    high: number;
 	    low: number;
 	    preClose: number;
+	    turnoverRate?: number;
+	    pe?: number;
+	    pb?: number;
+	    floatShares?: number;
+	    totalShares?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new Stock(source);
@@ -827,6 +2567,11 @@ export namespace models {
 	        this.high = source["high"];
 	        this.low = source["low"];
 	        this.preClose = source["preClose"];
+	        this.turnoverRate = source["turnoverRate"];
+	        this.pe = source["pe"];
+	        this.pb = source["pb"];
+	        this.floatShares = source["floatShares"];
+	        this.totalShares = source["totalShares"];
 	    }
 	}
 	export class StockPosition {
@@ -843,12 +2588,96 @@ export namespace models {
 	        this.costPrice = source["costPrice"];
 	    }
 	}
+	export class Briefing {
+	    date: string;
+	    content: string;
+	    createdAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Briefing(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.content = source["content"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class TradePlanHit {
+	    kind: string;
+	    level: number;
+	    price: number;
+	    at: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TradePlanHit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.level = source["level"];
+	        this.price = source["price"];
+	        this.at = source["at"];
+	    }
+	}
+	export class TradePlan {
+	    entryLow?: number;
+	    entryHigh?: number;
+	    stopLoss?: number;
+	    targets?: number[];
+	    autoReview: boolean;
+	    summary: string;
+	    status: string;
+	    hits?: TradePlanHit[];
+	    createdAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TradePlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entryLow = source["entryLow"];
+	        this.entryHigh = source["entryHigh"];
+	        this.stopLoss = source["stopLoss"];
+	        this.targets = source["targets"];
+	        this.autoReview = source["autoReview"];
+	        this.summary = source["summary"];
+	        this.status = source["status"];
+	        this.hits = this.convertValues(source["hits"], TradePlanHit);
+	        this.createdAt = source["createdAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class StockSession {
 	    id: string;
 	    stockCode: string;
 	    stockName: string;
 	    messages: ChatMessage[];
 	    position?: StockPosition;
+	    lastAdvice?: MeetingAdvice;
+	    lastConsensus?: ConsensusScore;
+	    tradePlan?: TradePlan;
+	    briefing?: Briefing;
 	    createdAt: number;
 	    updatedAt: number;
 	
@@ -863,6 +2692,10 @@ export namespace models {
 	        this.stockName = source["stockName"];
 	        this.messages = this.convertValues(source["messages"], ChatMessage);
 	        this.position = this.convertValues(source["position"], StockPosition);
+	        this.lastAdvice = this.convertValues(source["lastAdvice"], MeetingAdvice);
+	        this.lastConsensus = this.convertValues(source["lastConsensus"], ConsensusScore);
+	        this.tradePlan = this.convertValues(source["tradePlan"], TradePlan);
+	        this.briefing = this.convertValues(source["briefing"], Briefing);
 	        this.createdAt = source["createdAt"];
 	        this.updatedAt = source["updatedAt"];
 	    }
@@ -885,6 +2718,20 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class AgentVariant {
+	    instruction?: string;
+	    aiConfigId?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentVariant(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.instruction = source["instruction"];
+	        this.aiConfigId = source["aiConfigId"];
+	    }
+	}
 	export class StrategyAgent {
 	    id: string;
 	    name: string;
@@ -896,6 +2743,11 @@ export namespace models {
 	    mcpServers: string[];
 	    enabled: boolean;
 	    aiConfigId: string;
+	    unifiedTools?: boolean;
+	    variant?: AgentVariant;
+	    presetId?: string;
+	    presetVersion?: number;
+	    presetHash?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new StrategyAgent(source);
@@ -913,7 +2765,30 @@ export namespace models {
 	        this.mcpServers = source["mcpServers"];
 	        this.enabled = source["enabled"];
 	        this.aiConfigId = source["aiConfigId"];
+	        this.unifiedTools = source["unifiedTools"];
+	        this.variant = this.convertValues(source["variant"], AgentVariant);
+	        this.presetId = source["presetId"];
+	        this.presetVersion = source["presetVersion"];
+	        this.presetHash = source["presetHash"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class Strategy {
 	    id: string;
@@ -961,11 +2836,243 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class ToolCallRecord {
+	    tool: string;
+	    args?: string;
+	    result?: string;
+	    duration: number;
+	    agentId: string;
+	    citation: number;
+	    cited?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolCallRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool = source["tool"];
+	        this.args = source["args"];
+	        this.result = source["result"];
+	        this.duration = source["duration"];
+	        this.agentId = source["agentId"];
+	        this.citation = source["citation"];
+	        this.cited = source["cited"];
+	    }
+	}
+	export class WatchlistGroup {
+	    id: string;
+	    name: string;
+	    symbols: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchlistGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.symbols = source["symbols"];
+	    }
+	}
 
 }
 
 export namespace services {
 	
+	export class ABTestVariant {
+	    label: string;
+	    instruction: string;
+	    aiConfigId?: string;
+	    model?: string;
+	    content: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ABTestVariant(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.instruction = source["instruction"];
+	        this.aiConfigId = source["aiConfigId"];
+	        this.model = source["model"];
+	        this.content = source["content"];
+	        this.error = source["error"];
+	    }
+	}
+	export class ABTestRecord {
+	    id: string;
+	    agentId: string;
+	    agentName: string;
+	    stockCode: string;
+	    query: string;
+	    variants: ABTestVariant[];
+	    preferred?: string;
+	    createdAt: number;
+	    ratedAt?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ABTestRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.stockCode = source["stockCode"];
+	        this.query = source["query"];
+	        this.variants = this.convertValues(source["variants"], ABTestVariant);
+	        this.preferred = source["preferred"];
+	        this.createdAt = source["createdAt"];
+	        this.ratedAt = source["ratedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AgentAccuracy {
+	    agentId: string;
+	    agentName: string;
+	    total: number;
+	    evaluated: number;
+	    hits: number;
+	    weight: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentAccuracy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.total = source["total"];
+	        this.evaluated = source["evaluated"];
+	        this.hits = source["hits"];
+	        this.weight = source["weight"];
+	    }
+	}
+	export class AgentPresetInfo {
+	    id: string;
+	    category: string;
+	    sector?: string;
+	    summary: string;
+	    version: number;
+	    agent: models.StrategyAgent;
+	    installed: boolean;
+	    upgradable: boolean;
+	    customized: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentPresetInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.category = source["category"];
+	        this.sector = source["sector"];
+	        this.summary = source["summary"];
+	        this.version = source["version"];
+	        this.agent = this.convertValues(source["agent"], models.StrategyAgent);
+	        this.installed = source["installed"];
+	        this.upgradable = source["upgradable"];
+	        this.customized = source["customized"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AgentQualityStats {
+	    agentId: string;
+	    agentName: string;
+	    tests: number;
+	    rated: number;
+	    winsA: number;
+	    winsB: number;
+	    ties: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentQualityStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agentId = source["agentId"];
+	        this.agentName = source["agentName"];
+	        this.tests = source["tests"];
+	        this.rated = source["rated"];
+	        this.winsA = source["winsA"];
+	        this.winsB = source["winsB"];
+	        this.ties = source["ties"];
+	    }
+	}
+	export class IntradayPage {
+	    data: models.KLineData[];
+	    hasMore: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new IntradayPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.data = this.convertValues(source["data"], models.KLineData);
+	        this.hasMore = source["hasMore"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class LongHuBangListResult {
 	    items: models.LongHuBangItem[];
 	    total: number;
@@ -998,6 +3105,96 @@ export namespace services {
 		    return a;
 		}
 	}
+	export class MeetingTemplate {
+	    id: string;
+	    name: string;
+	    agentIds: string[];
+	    mode: string;
+	    query: string;
+	    createdAt: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.agentIds = source["agentIds"];
+	        this.mode = source["mode"];
+	        this.query = source["query"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	export class PaperOrderRequest {
+	    symbol: string;
+	    side: string;
+	    type: string;
+	    price: number;
+	    shares: number;
+	    reason?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PaperOrderRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.symbol = source["symbol"];
+	        this.side = source["side"];
+	        this.type = source["type"];
+	        this.price = source["price"];
+	        this.shares = source["shares"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class StockBasicInfo {
+	    symbol: string;
+	    code: string;
+	    name: string;
+	    industry: string;
+	    market: string;
+	    area: string;
+	    spell: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockBasicInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.symbol = source["symbol"];
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.industry = source["industry"];
+	        this.market = source["market"];
+	        this.area = source["area"];
+	        this.spell = source["spell"];
+	    }
+	}
+	export class StockNews {
+	    title: string;
+	    summary: string;
+	    source: string;
+	    time: string;
+	    url: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockNews(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.summary = source["summary"];
+	        this.source = source["source"];
+	        this.time = source["time"];
+	        this.url = source["url"];
+	    }
+	}
 	export class StockSearchResult {
 	    symbol: string;
 	    name: string;
@@ -1114,6 +3311,8 @@ export namespace tools {
 	export class ToolInfo {
 	    name: string;
 	    description: string;
+	    custom: boolean;
+	    enabled: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new ToolInfo(source);
@@ -1123,6 +3322,8 @@ export namespace tools {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
 	        this.description = source["description"];
+	        this.custom = source["custom"];
+	        this.enabled = source["enabled"];
 	    }
 	}
 
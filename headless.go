@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/run-bigpig/jcp/internal/eventbus"
+	"github.com/run-bigpig/jcp/internal/headless"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/paths"
+)
+
+// runHeadless 无界面模式：不启动窗口，通过 HTTP + SSE 对外提供行情、会议与提醒
+func runHeadless(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8765", "监听地址")
+	token := fs.String("token", os.Getenv("JCP_TOKEN"), "访问令牌，默认读取 JCP_TOKEN 环境变量")
+	profile := fs.String("profile", "", "工作区名称，默认读取 JCP_PROFILE 或上次选择的工作区")
+	fs.Parse(args)
+
+	if err := paths.SetProfile(paths.ResolveProfile(*profile)); err != nil {
+		return err
+	}
+
+	if *token == "" {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return err
+		}
+		*token = hex.EncodeToString(buf)
+		fmt.Println("未指定访问令牌，已随机生成:", *token)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := NewApp()
+	// 无界面时无法输入密码，加密数据需通过环境变量解锁
+	if app.GetEncryptionStatus().Locked {
+		return errors.New("数据已加密，请通过 JCP_PASSPHRASE 环境变量提供密码")
+	}
+	bus := eventbus.NewMemoryBus()
+	app.eventBus = bus
+	app.startup(ctx)
+	// 无前端握手，直接开始推送行情
+	app.NotifyFrontendReady()
+
+	server := headless.NewServer(headlessBackend{app: app}, bus, *token, app.chartService.FileHandler())
+	if err := server.Start(*addr); err != nil {
+		app.shutdown(ctx)
+		return err
+	}
+
+	<-ctx.Done()
+	server.Stop()
+	app.shutdown(context.Background())
+	return nil
+}
+
+// headlessBackend 将 App 适配为无界面服务的后端
+type headlessBackend struct {
+	app *App
+}
+
+func (b headlessBackend) Watchlist() []models.Stock {
+	return b.app.GetWatchlist()
+}
+
+func (b headlessBackend) Quotes(codes []string) []models.Stock {
+	return b.app.GetStockRealTimeData(codes)
+}
+
+func (b headlessBackend) Messages(stockCode string) []models.ChatMessage {
+	return b.app.GetSessionMessages(stockCode)
+}
+
+// StartMeeting 校验后在后台召开会议，消息通过 meeting:message:<code> 事件推送
+func (b headlessBackend) StartMeeting(req headless.MeetingRequest) error {
+	info := b.app.LookupStock(req.StockCode)
+	if info == nil {
+		return fmt.Errorf("未知股票代码: %s", req.StockCode)
+	}
+	if b.app.configService.GetConfig().ResolveAIConfig(models.AITaskExpert) == nil {
+		return fmt.Errorf("未配置 AI")
+	}
+	b.app.GetOrCreateSession(info.Symbol, info.Name)
+	go b.app.SendMeetingMessage(MeetingMessageRequest{
+		StockCode:  info.Symbol,
+		Content:    req.Content,
+		MentionIds: req.MentionIds,
+	})
+	return nil
+}
+
+func (b headlessBackend) CancelMeeting(stockCode string) bool {
+	return b.app.CancelMeeting(stockCode)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 )
@@ -60,17 +61,19 @@ func toAnthropicRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 	}
 
 	// 非官方 API 或不支持 system role：降级为第一条 user message
+	// 系统指令在同一专家的多次调用间保持不变，设置缓存断点
 	if systemText != "" {
+		systemBlock := ContentBlock{Type: "text", Text: systemText, CacheControl: ephemeralCache}
 		if !noSystemRole {
-			ar.System = systemText
+			ar.System = []ContentBlock{systemBlock}
 		} else {
 			systemMsg := Message{
 				Role:    "user",
-				Content: []ContentBlock{{Type: "text", Text: systemText}},
+				Content: []ContentBlock{systemBlock},
 			}
 			// 如果第一条也是 user，合并避免连续 user
 			if len(msgs) > 0 && msgs[0].Role == "user" {
-				msgs[0].Content = append([]ContentBlock{{Type: "text", Text: systemText}}, msgs[0].Content...)
+				msgs[0].Content = append([]ContentBlock{systemBlock}, msgs[0].Content...)
 			} else {
 				msgs = append([]Message{systemMsg}, msgs...)
 			}
@@ -85,6 +88,10 @@ func toAnthropicRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 		if err != nil {
 			return nil, err
 		}
+		// 工具定义位于缓存前缀最前端，在最后一个工具上设置断点
+		if len(tools) > 0 {
+			tools[len(tools)-1].CacheControl = ephemeralCache
+		}
 		ar.Tools = tools
 	}
 
@@ -313,23 +320,56 @@ func convertAnthropicResponse(resp *MessagesResponse) (*model.LLMResponse, error
 	}
 
 	return &model.LLMResponse{
-		Content:       content,
-		UsageMetadata: convertUsage(&resp.Usage),
-		FinishReason:  convertStopReason(resp.StopReason),
-		TurnComplete:  true,
+		Content:        content,
+		UsageMetadata:  convertUsage(&resp.Usage),
+		CustomMetadata: usageMetadata(&resp.Usage),
+		FinishReason:   convertStopReason(resp.StopReason),
+		TurnComplete:   true,
 	}, nil
 }
 
 // convertUsage 转换 token 用量
+// Anthropic 的 input_tokens 不含缓存部分，这里合并为总输入，命中数单独记入 CachedContentTokenCount，
+// 写入缓存的部分按更高价格计费，由 usageMetadata 另行放入 CustomMetadata
 func convertUsage(u *Usage) *genai.GenerateContentResponseUsageMetadata {
 	if u == nil {
 		return nil
 	}
+	input := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
 	return &genai.GenerateContentResponseUsageMetadata{
-		PromptTokenCount:     int32(u.InputTokens),
-		CandidatesTokenCount: int32(u.OutputTokens),
-		TotalTokenCount:      int32(u.InputTokens + u.OutputTokens),
+		PromptTokenCount:        int32(input),
+		CandidatesTokenCount:    int32(u.OutputTokens),
+		CachedContentTokenCount: int32(u.CacheReadInputTokens),
+		TotalTokenCount:         int32(input + u.OutputTokens),
+	}
+}
+
+// usageMetadata 写入缓存的 token 数，genai 用量结构没有对应字段
+func usageMetadata(u *Usage) map[string]any {
+	if u == nil || u.CacheCreationInputTokens == 0 {
+		return nil
+	}
+	return map[string]any{models.CacheWriteTokensKey: int64(u.CacheCreationInputTokens)}
+}
+
+// mergeUsage 合并 message_delta 中的累计用量
+// message_delta 通常只带 output_tokens，输入与缓存数以 message_start 为准
+func mergeUsage(base, delta *Usage) *Usage {
+	if base == nil {
+		return delta
+	}
+	merged := *base
+	merged.OutputTokens = delta.OutputTokens
+	if delta.InputTokens > 0 {
+		merged.InputTokens = delta.InputTokens
+	}
+	if delta.CacheCreationInputTokens > 0 {
+		merged.CacheCreationInputTokens = delta.CacheCreationInputTokens
+	}
+	if delta.CacheReadInputTokens > 0 {
+		merged.CacheReadInputTokens = delta.CacheReadInputTokens
 	}
+	return &merged
 }
 
 // convertStopReason 转换停止原因
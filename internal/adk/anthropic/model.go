@@ -14,6 +14,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
 	"github.com/run-bigpig/jcp/internal/logger"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
@@ -94,7 +95,7 @@ func (m *AnthropicModel) doRequest(ctx context.Context, ar *MessagesRequest) (*h
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
 		resp.Body.Close()
 		modelLog.Error("API 响应异常: status=%d, body=%s", resp.StatusCode, string(body))
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, llmerr.New(resp.StatusCode, string(body))
 	}
 
 	return resp, nil
@@ -221,6 +222,18 @@ func (m *AnthropicModel) processStream(body io.Reader, yield func(*model.LLMResp
 
 var errStopIteration = errors.New("stop iteration")
 
+// sseErrorStatus 流式 error 事件的错误类型对应的 HTTP 状态码（与非流式响应一致）
+var sseErrorStatus = map[string]int{
+	"invalid_request_error": http.StatusBadRequest,
+	"authentication_error":  http.StatusUnauthorized,
+	"permission_error":      http.StatusForbidden,
+	"not_found_error":       http.StatusNotFound,
+	"request_too_large":     http.StatusRequestEntityTooLarge,
+	"rate_limit_error":      http.StatusTooManyRequests,
+	"api_error":             http.StatusInternalServerError,
+	"overloaded_error":      529,
+}
+
 // handleSSEEvent 处理单个 SSE 事件
 func (m *AnthropicModel) handleSSEEvent(
 	eventType string, data []byte,
@@ -262,7 +275,7 @@ func (m *AnthropicModel) handleSSEEvent(
 		}
 		*stopReason = ev.Delta.StopReason
 		if ev.Usage != nil {
-			*usage = ev.Usage
+			*usage = mergeUsage(*usage, ev.Usage)
 		}
 
 	case "message_stop":
@@ -273,7 +286,8 @@ func (m *AnthropicModel) handleSSEEvent(
 		if err := json.Unmarshal(data, &ev); err != nil {
 			return fmt.Errorf("SSE error: %s", string(data))
 		}
-		return fmt.Errorf("Anthropic API error: %s - %s", ev.Error.Type, ev.Error.Message)
+		err := fmt.Errorf("Anthropic API error: %s - %s", ev.Error.Type, ev.Error.Message)
+		return llmerr.Wrap(err, sseErrorStatus[ev.Error.Type], ev.Error.Message)
 
 	case "ping":
 		// 忽略
@@ -376,11 +390,12 @@ func (m *AnthropicModel) emitFinalResponse(
 	}
 
 	finalResp := &model.LLMResponse{
-		Content:       aggregated,
-		UsageMetadata: convertUsage(usage),
-		FinishReason:  convertStopReason(stopReason),
-		Partial:       false,
-		TurnComplete:  true,
+		Content:        aggregated,
+		UsageMetadata:  convertUsage(usage),
+		CustomMetadata: usageMetadata(usage),
+		FinishReason:   convertStopReason(stopReason),
+		Partial:        false,
+		TurnComplete:   true,
 	}
 	yield(finalResp, nil)
 }
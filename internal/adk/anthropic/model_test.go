@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/run-bigpig/jcp/internal/models"
+
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 )
@@ -38,8 +40,10 @@ func TestToAnthropicRequest_Basic(t *testing.T) {
 	if ar.MaxTokens != 1024 {
 		t.Errorf("max_tokens = %d, want 1024", ar.MaxTokens)
 	}
-	if ar.System != "You are helpful." {
-		t.Errorf("system = %q, want %q", ar.System, "You are helpful.")
+	if len(ar.System) != 1 || ar.System[0].Text != "You are helpful." {
+		t.Errorf("system = %+v, want %q", ar.System, "You are helpful.")
+	} else if ar.System[0].CacheControl == nil {
+		t.Error("system block should carry cache_control")
 	}
 	if ar.Temperature == nil {
 		t.Error("temperature is nil")
@@ -210,6 +214,20 @@ func TestConvertAnthropicResponse_TextAndToolUse(t *testing.T) {
 	}
 }
 
+func TestConvertUsage_CacheWrite(t *testing.T) {
+	usage := &Usage{InputTokens: 10, CacheCreationInputTokens: 1000, CacheReadInputTokens: 200, OutputTokens: 5}
+	meta := convertUsage(usage)
+	if meta.PromptTokenCount != 1210 || meta.CachedContentTokenCount != 200 {
+		t.Errorf("prompt = %d, cached = %d", meta.PromptTokenCount, meta.CachedContentTokenCount)
+	}
+	if got := usageMetadata(usage)[models.CacheWriteTokensKey]; got != int64(1000) {
+		t.Errorf("cache write tokens = %v, want 1000", got)
+	}
+	if usageMetadata(&Usage{InputTokens: 10}) != nil {
+		t.Error("expected no custom metadata without cache writes")
+	}
+}
+
 func TestConvertStopReason(t *testing.T) {
 	tests := []struct {
 		reason string
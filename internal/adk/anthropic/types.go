@@ -4,20 +4,20 @@ import "encoding/json"
 
 // Anthropic Messages API 请求
 type MessagesRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	System      string    `json:"system,omitempty"`
-	MaxTokens   int       `json:"max_tokens"`
-	Temperature *float64  `json:"temperature,omitempty"`
-	TopP        *float64  `json:"top_p,omitempty"`
-	Stream      bool      `json:"stream,omitempty"`
-	Tools       []Tool    `json:"tools,omitempty"`
-	StopSequences []string `json:"stop_sequences,omitempty"`
+	Model         string         `json:"model"`
+	Messages      []Message      `json:"messages"`
+	System        []ContentBlock `json:"system,omitempty"` // 使用内容块形式以便设置 cache_control
+	MaxTokens     int            `json:"max_tokens"`
+	Temperature   *float64       `json:"temperature,omitempty"`
+	TopP          *float64       `json:"top_p,omitempty"`
+	Stream        bool           `json:"stream,omitempty"`
+	Tools         []Tool         `json:"tools,omitempty"`
+	StopSequences []string       `json:"stop_sequences,omitempty"`
 }
 
 // Message 消息
 type Message struct {
-	Role    string        `json:"role"` // user / assistant
+	Role    string         `json:"role"` // user / assistant
 	Content []ContentBlock `json:"content"`
 }
 
@@ -41,16 +41,28 @@ type ContentBlock struct {
 	ToolUseID  string          `json:"tool_use_id,omitempty"`
 	RawContent json.RawMessage `json:"-"` // 自定义序列化，不走默认 tag
 	IsError    bool            `json:"is_error,omitempty"`
+
+	// 提示词缓存断点（仅 text 块输出）
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
+}
+
+// CacheControl 提示词缓存控制
+type CacheControl struct {
+	Type string `json:"type"` // ephemeral
 }
 
+// ephemeralCache 默认缓存断点（5 分钟有效）
+var ephemeralCache = &CacheControl{Type: "ephemeral"}
+
 // MarshalJSON 按 Type 输出对应字段，避免多余字段导致 Anthropic 拒绝
 func (b ContentBlock) MarshalJSON() ([]byte, error) {
 	switch b.Type {
 	case "text":
 		return json.Marshal(struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		}{b.Type, b.Text})
+			Type         string        `json:"type"`
+			Text         string        `json:"text"`
+			CacheControl *CacheControl `json:"cache_control,omitempty"`
+		}{b.Type, b.Text, b.CacheControl})
 	case "thinking":
 		return json.Marshal(struct {
 			Type     string `json:"type"`
@@ -79,9 +91,10 @@ func (b ContentBlock) MarshalJSON() ([]byte, error) {
 
 // Tool 工具定义
 type Tool struct {
-	Name        string          `json:"name"`
-	Description string          `json:"description,omitempty"`
-	InputSchema json.RawMessage `json:"input_schema"`
+	Name         string          `json:"name"`
+	Description  string          `json:"description,omitempty"`
+	InputSchema  json.RawMessage `json:"input_schema"`
+	CacheControl *CacheControl   `json:"cache_control,omitempty"`
 }
 
 // ---- 响应类型 ----
@@ -93,22 +106,25 @@ type MessagesResponse struct {
 	Role         string         `json:"role"` // assistant
 	Content      []ContentBlock `json:"content"`
 	Model        string         `json:"model"`
-	StopReason   string         `json:"stop_reason"`   // end_turn / max_tokens / tool_use
+	StopReason   string         `json:"stop_reason"` // end_turn / max_tokens / tool_use
 	StopSequence *string        `json:"stop_sequence"`
 	Usage        Usage          `json:"usage"`
 }
 
 // Usage token 用量
+// 开启提示词缓存后 input_tokens 不含缓存写入/命中部分
 type Usage struct {
-	InputTokens  int `json:"input_tokens"`
-	OutputTokens int `json:"output_tokens"`
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
 }
 
 // ---- SSE 事件类型 ----
 
 // SSEMessageStart message_start 事件
 type SSEMessageStart struct {
-	Type    string          `json:"type"`
+	Type    string           `json:"type"`
 	Message MessagesResponse `json:"message"`
 }
 
@@ -128,10 +144,10 @@ type SSEContentBlockDelta struct {
 
 // Delta 增量内容
 type Delta struct {
-	Type     string          `json:"type"` // text_delta / input_json_delta / thinking_delta
-	Text     string          `json:"text,omitempty"`
-	Thinking string          `json:"thinking,omitempty"`
-	PartialJSON string       `json:"partial_json,omitempty"`
+	Type        string `json:"type"` // text_delta / input_json_delta / thinking_delta
+	Text        string `json:"text,omitempty"`
+	Thinking    string `json:"thinking,omitempty"`
+	PartialJSON string `json:"partial_json,omitempty"`
 }
 
 // SSEContentBlockStop content_block_stop 事件
@@ -142,9 +158,9 @@ type SSEContentBlockStop struct {
 
 // SSEMessageDelta message_delta 事件
 type SSEMessageDelta struct {
-	Type  string     `json:"type"`
+	Type  string       `json:"type"`
 	Delta MessageDelta `json:"delta"`
-	Usage *Usage     `json:"usage,omitempty"`
+	Usage *Usage       `json:"usage,omitempty"`
 }
 
 // MessageDelta 消息级增量
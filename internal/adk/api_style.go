@@ -0,0 +1,94 @@
+package adk
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/model"
+)
+
+// detectedAPIStyles auto 模式运行时检测到的接口风格，按 BaseURL + 模型缓存，避免每轮发言都先撞一次 404
+var detectedAPIStyles sync.Map
+
+// unsupportedEndpoint 网关没有实现该接口（404/405），换用另一种接口风格
+func unsupportedEndpoint(err error) bool {
+	code := llmerr.StatusCode(err)
+	return code == http.StatusNotFound || code == http.StatusMethodNotAllowed
+}
+
+// autoAPIStyleLLM auto 模式：优先使用 Responses API，网关返回 404/405 时回退到 Chat Completions
+type autoAPIStyleLLM struct {
+	key       string
+	responses model.LLM
+	chat      model.LLM
+}
+
+// newAutoAPIStyleLLM 创建 auto 模式模型
+func newAutoAPIStyleLLM(config *models.AIConfig, responses, chat model.LLM) *autoAPIStyleLLM {
+	return &autoAPIStyleLLM{
+		key:       normalizeOpenAIBaseURL(config.BaseURL) + "|" + config.ModelName,
+		responses: responses,
+		chat:      chat,
+	}
+}
+
+func (m *autoAPIStyleLLM) Name() string {
+	return m.chat.Name()
+}
+
+// GenerateContent 按已检测的接口风格调用；尚未检测时先试 Responses API，
+// 在输出任何内容之前收到 404/405 才回退，已输出内容后的错误原样返回
+func (m *autoAPIStyleLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		if style, ok := detectedAPIStyles.Load(m.key); ok && style == models.APIStyleChat {
+			for resp, err := range m.chat.GenerateContent(ctx, req, stream) {
+				if !yield(resp, err) {
+					return
+				}
+			}
+			return
+		}
+
+		yielded := false
+		for resp, err := range m.responses.GenerateContent(ctx, req, stream) {
+			if err != nil && !yielded && unsupportedEndpoint(err) {
+				log.Info("模型 [%s] 不支持 Responses API，回退到 Chat Completions", m.chat.Name())
+				detectedAPIStyles.Store(m.key, models.APIStyleChat)
+				for resp, err := range m.chat.GenerateContent(ctx, req, stream) {
+					if !yield(resp, err) {
+						return
+					}
+				}
+				return
+			}
+			if err == nil && !yielded {
+				detectedAPIStyles.Store(m.key, models.APIStyleResponses)
+			}
+			yielded = true
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// detectOpenAIAPIStyle 连接测试时检测接口风格：Responses API 可用时优先使用，
+// 返回 404/405 时再测试 Chat Completions，两者都失败时返回错误
+func (f *ModelFactory) detectOpenAIAPIStyle(ctx context.Context, config *models.AIConfig) (models.APIStyle, error) {
+	err := f.probeOpenAIEndpoint(ctx, config, models.APIStyleResponses)
+	if err == nil {
+		return models.APIStyleResponses, nil
+	}
+	if !unsupportedEndpoint(err) {
+		return "", err
+	}
+	if err := f.probeOpenAIEndpoint(ctx, config, models.APIStyleChat); err != nil {
+		return "", err
+	}
+	return models.APIStyleChat, nil
+}
@@ -0,0 +1,64 @@
+package adk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// chatOnlyServer 只实现 Chat Completions 的网关，Responses API 返回 404
+func chatOnlyServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chat/completions":
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"id":"c1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"pong"},"finish_reason":"stop"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAutoAPIStyleFallsBackToChat(t *testing.T) {
+	srv := chatOnlyServer(t)
+	config := &models.AIConfig{Provider: models.AIProviderOpenAI, BaseURL: srv.URL, ModelName: "auto-fallback", APIKey: "k", APIStyle: models.APIStyleAuto}
+
+	factory := NewModelFactory()
+	if err := factory.TestConnection(context.Background(), config); err != nil {
+		t.Fatalf("TestConnection: %v", err)
+	}
+	if config.DetectedAPIStyle != models.APIStyleChat {
+		t.Fatalf("DetectedAPIStyle = %q, want chat", config.DetectedAPIStyle)
+	}
+
+	// 未写入检测结果时运行期同样回退
+	config.DetectedAPIStyle = ""
+	llm, err := factory.CreateModel(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("ping", genai.RoleUser)}}
+	var text string
+	for resp, err := range llm.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent: %v", err)
+		}
+		if resp.Content != nil && len(resp.Content.Parts) > 0 {
+			text += resp.Content.Parts[0].Text
+		}
+	}
+	if text != "pong" {
+		t.Fatalf("text = %q, want pong", text)
+	}
+	if style, _ := detectedAPIStyles.Load(normalizeOpenAIBaseURL(srv.URL) + "|auto-fallback"); style != models.APIStyleChat {
+		t.Errorf("cached style = %v, want chat", style)
+	}
+}
@@ -0,0 +1,239 @@
+package adk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// probeTimeout 单项能力探测超时
+const probeTimeout = 20 * time.Second
+
+// probeMaxTokens 探测请求的输出上限
+const probeMaxTokens = 256
+
+// probeReasoningMaxTokens 推理模型的探测输出上限，预留思考 token，避免思考耗尽预算后误判为不支持
+const probeReasoningMaxTokens = 4096
+
+// probeToolName 工具调用探测使用的函数名
+const probeToolName = "probe_echo"
+
+// probeResult 单项探测结果
+type probeResult int
+
+const (
+	probeUnknown     probeResult = iota // 出错、超时或回复不完整，无法判断
+	probeSupported                      // 确认支持
+	probeUnsupported                    // 确认不支持：接口以 4xx 拒绝该功能，或完整回复中没有该功能的输出
+)
+
+// apply 按探测结果更新能力值，无法判断时保留 prev
+func (r probeResult) apply(prev bool) bool {
+	switch r {
+	case probeSupported:
+		return true
+	case probeUnsupported:
+		return false
+	default:
+		return prev
+	}
+}
+
+// ProbeCapabilities 探测模型能力：system role、流式输出、工具调用、JSON 模式
+// 各项探测互相独立；只有明确的结果才会改变能力值，出错或超时时沿用 config.Capabilities 中的旧值
+// （首次探测时沿用“未探测默认支持”），避免一次网络抖动让专家丢失全部工具
+func (f *ModelFactory) ProbeCapabilities(ctx context.Context, config *models.AIConfig) models.ModelCapabilities {
+	caps := config.Capabilities
+	if !caps.Probed() {
+		caps.SystemRole, caps.Streaming, caps.ToolCalling, caps.JSONMode = true, true, true, true
+	}
+	caps.ProbedAt = time.Now().UnixMilli()
+	if config.Provider == models.AIProviderMock {
+		// Mock 模型按场景回复，能力均视为支持
+		caps.SystemRole, caps.Streaming, caps.ToolCalling, caps.JSONMode = true, true, true, true
+		return caps
+	}
+	caps.SystemRole = !f.DetectSystemRoleSupport(ctx, config)
+
+	// 后续探测按检测到的 system role 支持情况创建模型
+	probeConfig := *config
+	probeConfig.NoSystemRole = !caps.SystemRole
+	llm, err := f.CreateModel(ctx, &probeConfig)
+	if err != nil {
+		log.Warn("模型 [%s] 能力探测创建失败，沿用原有能力: %v", config.ModelName, err)
+		return caps
+	}
+
+	maxTokens := int32(probeMaxTokens)
+	if isReasoningModel(config.ModelName) {
+		maxTokens = probeReasoningMaxTokens
+	}
+	caps.Streaming = probeStreaming(ctx, llm, maxTokens).apply(caps.Streaming)
+	caps.ToolCalling = probeToolCalling(ctx, llm, maxTokens).apply(caps.ToolCalling)
+	caps.JSONMode = probeJSONMode(ctx, llm, maxTokens).apply(caps.JSONMode)
+
+	log.Info("模型 [%s] 能力探测: systemRole=%v streaming=%v tools=%v json=%v",
+		config.ModelName, caps.SystemRole, caps.Streaming, caps.ToolCalling, caps.JSONMode)
+	return caps
+}
+
+// isReasoningModel 是否为会先输出思考过程的推理模型
+func isReasoningModel(modelName string) bool {
+	if p, ok := models.LookupModelPreset(modelName); ok && p.Reasoning {
+		return true
+	}
+	name := strings.ToLower(modelName)
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	for _, prefix := range []string{"o1", "o3", "o4"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	for _, kw := range []string{"reason", "think", "-r1", "qwq"} {
+		if strings.Contains(name, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// probeErrorResult 探测请求出错：接口以 4xx 拒绝请求视为不支持，鉴权、限流、超时等无法判断
+func probeErrorResult(err error) probeResult {
+	code := llmerr.StatusCode(err)
+	switch {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return probeUnknown
+	case code >= 400 && code < 500 && !llmerr.RetryableStatus(code):
+		return probeUnsupported
+	default:
+		return probeUnknown
+	}
+}
+
+// probeStreaming 探测流式输出
+func probeStreaming(ctx context.Context, llm model.LLM, maxTokens int32) probeResult {
+	req := newProbeRequest("Reply with: ok", maxTokens)
+	resp, err := runProbe(ctx, llm, req, true)
+	if err != nil {
+		log.Warn("流式输出探测失败: %v", err)
+		return probeErrorResult(err)
+	}
+	if resp == nil || resp.Content == nil {
+		return probeUnknown
+	}
+	return probeSupported
+}
+
+// probeToolCalling 探测标准 function call
+func probeToolCalling(ctx context.Context, llm model.LLM, maxTokens int32) probeResult {
+	req := newProbeRequest(fmt.Sprintf(`Call the %s tool with value "ok". Do not reply with text.`, probeToolName), maxTokens)
+	req.Config.Tools = []*genai.Tool{{
+		FunctionDeclarations: []*genai.FunctionDeclaration{{
+			Name:        probeToolName,
+			Description: "Echo the given value back.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"value": {Type: genai.TypeString},
+				},
+				Required: []string{"value"},
+			},
+		}},
+	}}
+	resp, err := runProbe(ctx, llm, req, false)
+	if err != nil {
+		log.Warn("工具调用探测失败: %v", err)
+		return probeErrorResult(err)
+	}
+	if resp == nil || resp.Content == nil {
+		return probeUnknown
+	}
+	for _, part := range resp.Content.Parts {
+		if part.FunctionCall != nil && part.FunctionCall.Name == probeToolName {
+			return probeSupported
+		}
+	}
+	// 只有完整的文本回复才能说明模型不调用工具；思考耗尽输出上限时无法判断
+	if resp.FinishReason == genai.FinishReasonMaxTokens || probeText(resp.Content) == "" {
+		return probeUnknown
+	}
+	return probeUnsupported
+}
+
+// probeJSONMode 探测 JSON 结构化输出
+func probeJSONMode(ctx context.Context, llm model.LLM, maxTokens int32) probeResult {
+	req := newProbeRequest(`Reply with the JSON object {"ok": true} and nothing else.`, maxTokens)
+	req.Config.ResponseMIMEType = "application/json"
+	resp, err := runProbe(ctx, llm, req, false)
+	if err != nil {
+		log.Warn("JSON 模式探测失败: %v", err)
+		return probeErrorResult(err)
+	}
+	if resp == nil || resp.FinishReason == genai.FinishReasonMaxTokens {
+		return probeUnknown
+	}
+	text := strings.TrimSpace(probeText(resp.Content))
+	if text == "" {
+		return probeUnknown
+	}
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "```"), "```")
+	if json.Valid([]byte(strings.TrimSpace(text))) {
+		return probeSupported
+	}
+	return probeUnsupported
+}
+
+// newProbeRequest 构造最小探测请求
+func newProbeRequest(prompt string, maxTokens int32) *model.LLMRequest {
+	return &model.LLMRequest{
+		Contents: []*genai.Content{
+			{Role: "user", Parts: []*genai.Part{{Text: prompt}}},
+		},
+		Config: &genai.GenerateContentConfig{
+			MaxOutputTokens: maxTokens,
+		},
+	}
+}
+
+// runProbe 执行探测请求，返回最终的完整回复
+func runProbe(ctx context.Context, llm model.LLM, req *model.LLMRequest, stream bool) (*model.LLMResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	var final *model.LLMResponse
+	for resp, err := range llm.GenerateContent(ctx, req, stream) {
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil && !resp.Partial && resp.Content != nil {
+			final = resp
+		}
+	}
+	return final, nil
+}
+
+// probeText 提取非思考文本
+func probeText(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, part := range content.Parts {
+		if part.Text != "" && !part.Thought {
+			sb.WriteString(part.Text)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,108 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// scriptedLLM 返回固定回复或错误的探测用模型
+type scriptedLLM struct {
+	resp *model.LLMResponse
+	err  error
+}
+
+func (m scriptedLLM) Name() string { return "scripted" }
+
+func (m scriptedLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		if m.err != nil {
+			yield(nil, m.err)
+			return
+		}
+		yield(m.resp, nil)
+	}
+}
+
+func textResponse(text string, finish genai.FinishReason) *model.LLMResponse {
+	return &model.LLMResponse{
+		Content:      &genai.Content{Role: "model", Parts: []*genai.Part{{Text: text}}},
+		FinishReason: finish,
+	}
+}
+
+func TestProbeToolCallingResult(t *testing.T) {
+	call := &model.LLMResponse{Content: &genai.Content{Role: "model", Parts: []*genai.Part{
+		{FunctionCall: &genai.FunctionCall{Name: probeToolName, Args: map[string]any{"value": "ok"}}},
+	}}}
+	thoughtOnly := &model.LLMResponse{Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "thinking", Thought: true}}}}
+
+	cases := []struct {
+		name string
+		llm  scriptedLLM
+		want probeResult
+	}{
+		{"function call", scriptedLLM{resp: call}, probeSupported},
+		{"plain text reply", scriptedLLM{resp: textResponse("ok", genai.FinishReasonStop)}, probeUnsupported},
+		{"truncated by max tokens", scriptedLLM{resp: textResponse("Let me", genai.FinishReasonMaxTokens)}, probeUnknown},
+		{"thought only", scriptedLLM{resp: thoughtOnly}, probeUnknown},
+		{"tools rejected", scriptedLLM{err: llmerr.New(400, `{"error":"tools is not supported"}`)}, probeUnsupported},
+		{"rate limited", scriptedLLM{err: llmerr.New(429, "too many requests")}, probeUnknown},
+		{"auth failed", scriptedLLM{err: llmerr.New(401, "invalid api key")}, probeUnknown},
+		{"server error", scriptedLLM{err: llmerr.New(502, "bad gateway")}, probeUnknown},
+		{"timeout", scriptedLLM{err: context.DeadlineExceeded}, probeUnknown},
+		{"network", scriptedLLM{err: errors.New("connection reset")}, probeUnknown},
+	}
+	for _, tc := range cases {
+		if got := probeToolCalling(context.Background(), tc.llm, probeMaxTokens); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestProbeJSONModeResult(t *testing.T) {
+	cases := []struct {
+		llm  scriptedLLM
+		want probeResult
+	}{
+		{scriptedLLM{resp: textResponse("```json\n{\"ok\": true}\n```", genai.FinishReasonStop)}, probeSupported},
+		{scriptedLLM{resp: textResponse("ok: true", genai.FinishReasonStop)}, probeUnsupported},
+		{scriptedLLM{resp: textResponse("{\"ok\":", genai.FinishReasonMaxTokens)}, probeUnknown},
+		{scriptedLLM{err: context.DeadlineExceeded}, probeUnknown},
+	}
+	for i, tc := range cases {
+		if got := probeJSONMode(context.Background(), tc.llm, probeMaxTokens); got != tc.want {
+			t.Errorf("case %d: got %v, want %v", i, got, tc.want)
+		}
+	}
+}
+
+func TestProbeResultApply(t *testing.T) {
+	if !probeUnknown.apply(true) || probeUnknown.apply(false) {
+		t.Error("unknown result should keep previous value")
+	}
+	if probeUnsupported.apply(true) || !probeSupported.apply(false) {
+		t.Error("definitive result should override previous value")
+	}
+}
+
+func TestIsReasoningModel(t *testing.T) {
+	for name, want := range map[string]bool{
+		"deepseek-reasoner":       true,
+		"deepseek-ai/DeepSeek-R1": true,
+		"o3-mini":                 true,
+		"qwq-32b":                 true,
+		"claude-3-7-sonnet-think": true,
+		"gpt-4o-mini":             false,
+		"glm-4-flash":             false,
+	} {
+		if got := isReasoningModel(name); got != want {
+			t.Errorf("isReasoningModel(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package adk
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/tool"
+)
+
+// CitationKey 工具结果中注入的引用编号字段，值形如 "[1]"
+const CitationKey = "citation"
+
+// newCitationCallback 为每次工具调用的结果注入递增的引用编号，专家据此在回答中标注数据来源
+// 每次构建 Agent 创建一个新的回调，编号从 1 开始
+func newCitationCallback() llmagent.AfterToolCallback {
+	var n atomic.Int32
+	return func(ctx tool.Context, t tool.Tool, args, result map[string]any, err error) (map[string]any, error) {
+		if err != nil || result == nil {
+			return nil, nil
+		}
+		result[CitationKey] = fmt.Sprintf("[%d]", n.Add(1))
+		return result, nil
+	}
+}
+
+// ParseCitation 解析引用编号 "[n]"，无效时返回 0
+func ParseCitation(v any) int {
+	s, ok := v.(string)
+	if !ok {
+		return 0
+	}
+	var n int
+	if _, err := fmt.Sscanf(s, "[%d]", &n); err != nil || n < 1 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,37 @@
+package adk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCitationCallback(t *testing.T) {
+	cb := newCitationCallback()
+	for want := 1; want <= 3; want++ {
+		result, err := cb(nil, nil, nil, map[string]any{"data": "x"}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := ParseCitation(result[CitationKey]); got != want {
+			t.Errorf("citation = %v, want %d", result[CitationKey], want)
+		}
+	}
+
+	// 工具出错时不注入编号，也不消耗编号
+	if result, _ := cb(nil, nil, nil, nil, errors.New("boom")); result != nil {
+		t.Errorf("error result should not be modified: %v", result)
+	}
+	result, _ := cb(nil, nil, nil, map[string]any{}, nil)
+	if got := ParseCitation(result[CitationKey]); got != 4 {
+		t.Errorf("citation after error = %d, want 4", got)
+	}
+}
+
+func TestParseCitation(t *testing.T) {
+	cases := map[any]int{"[12]": 12, "[0]": 0, "1": 0, 3: 0, nil: 0}
+	for in, want := range cases {
+		if got := ParseCitation(in); got != want {
+			t.Errorf("ParseCitation(%v) = %d, want %d", in, got, want)
+		}
+	}
+}
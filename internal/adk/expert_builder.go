@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/run-bigpig/jcp/internal/adk/mcp"
 	"github.com/run-bigpig/jcp/internal/adk/tools"
 	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/pkg/tokens"
+	"github.com/run-bigpig/jcp/internal/services"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
@@ -22,8 +26,15 @@ type ExpertAgentBuilder struct {
 	aiConfig     *models.AIConfig // AI 配置（包含 temperature、maxTokens）
 	toolRegistry *tools.Registry
 	mcpManager   *mcp.Manager
+	compareStock *models.Stock // 双股对比模式的第二只股票
+	longForm     bool          // 长篇输出（深度研究），放宽字数限制且不要求观点标记
+	toolBudget   int           // 工具使用说明的 token 预算，0 表示不限制
+	snapshot     SnapshotFetcher
 }
 
+// SnapshotFetcher 按股票代码获取个股快照，获取失败返回 nil
+type SnapshotFetcher func(code string) *models.StockSnapshot
+
 // NewExpertAgentBuilder 创建专家 Agent 构建器
 func NewExpertAgentBuilder(llm model.LLM, aiConfig *models.AIConfig) *ExpertAgentBuilder {
 	return &ExpertAgentBuilder{llm: llm, aiConfig: aiConfig}
@@ -39,19 +50,58 @@ func NewExpertAgentBuilderFull(llm model.LLM, aiConfig *models.AIConfig, registr
 	return &ExpertAgentBuilder{llm: llm, aiConfig: aiConfig, toolRegistry: registry, mcpManager: mcpMgr}
 }
 
+// SupportsStreaming 模型是否可使用流式输出
+func (b *ExpertAgentBuilder) SupportsStreaming() bool {
+	return b.aiConfig == nil || b.aiConfig.SupportsStreaming()
+}
+
+// SetCompareStock 设置对比股票，指令中同时注入两只股票的行情
+func (b *ExpertAgentBuilder) SetCompareStock(stock *models.Stock) {
+	b.compareStock = stock
+}
+
+// SetLongForm 启用长篇输出，用于深度研究的笔记与报告
+func (b *ExpertAgentBuilder) SetLongForm() {
+	b.longForm = true
+}
+
+// SetToolGuideBudget 设置工具使用说明的 token 预算，超出时先截短工具描述，仍超出则只列工具名称
+// 工具的完整定义仍随请求发送给模型，说明只用于引导调用
+func (b *ExpertAgentBuilder) SetToolGuideBudget(maxTokens int) {
+	b.toolBudget = maxTokens
+}
+
+// SetSnapshotFetcher 设置个股快照来源，单股分析时指令开头注入当日统计、区间与估值，减少专家的工具调用
+func (b *ExpertAgentBuilder) SetSnapshotFetcher(fetch SnapshotFetcher) {
+	b.snapshot = fetch
+}
+
+// supportsTools 模型是否可挂载工具
+func (b *ExpertAgentBuilder) supportsTools() bool {
+	return b.aiConfig == nil || b.aiConfig.SupportsTools()
+}
+
 // BuildAgentWithContext 根据配置构建 LLM Agent（支持引用上下文）
 func (b *ExpertAgentBuilder) BuildAgentWithContext(config *models.AgentConfig, stock *models.Stock, query string, replyContent string, position *models.StockPosition) (agent.Agent, error) {
 	instruction := b.buildInstructionWithContext(config, stock, query, replyContent, position)
 
 	// 获取 Agent 配置的工具
 	var agentTools []tool.Tool
-	if b.toolRegistry != nil && len(config.Tools) > 0 {
-		agentTools = b.toolRegistry.GetTools(config.Tools)
+	toolsEnabled := b.supportsTools()
+	if !toolsEnabled && (len(config.Tools) > 0 || len(config.MCPServers) > 0) {
+		log.Warn("Agent %s 的模型 [%s] 不支持工具调用，已忽略工具配置", config.ID, b.aiConfig.ModelName)
+	}
+	if toolsEnabled && b.toolRegistry != nil && len(config.Tools) > 0 {
+		if config.UnifiedTools {
+			agentTools = b.toolRegistry.GetUnifiedTools(config.Tools)
+		} else {
+			agentTools = b.toolRegistry.GetTools(config.Tools)
+		}
 	}
 
 	// 获取 MCP toolsets
 	var toolsets []tool.Toolset
-	if b.mcpManager != nil && len(config.MCPServers) > 0 {
+	if toolsEnabled && b.mcpManager != nil && len(config.MCPServers) > 0 {
 		log.Info("Agent %s 请求 MCP servers: %v", config.ID, config.MCPServers)
 		toolsets = b.mcpManager.GetToolsetsByIDs(config.MCPServers)
 		log.Info("Agent %s 获取到 %d 个 toolsets", config.ID, len(toolsets))
@@ -73,6 +123,28 @@ func (b *ExpertAgentBuilder) BuildAgentWithContext(config *models.AgentConfig, s
 		}
 	}
 
+	// 有工具时为工具结果注入引用编号，模型一次请求多个工具时并发执行
+	var afterToolCallbacks []llmagent.AfterToolCallback
+	var afterModelCallbacks []llmagent.AfterModelCallback
+	if len(agentTools) > 0 || len(toolsets) > 0 {
+		// 先压缩超长结果再编号；ADK 遇到首个返回结果的回调即停止，需串联执行
+		var toolCallbacks []llmagent.AfterToolCallback
+		if b.toolRegistry != nil {
+			toolCallbacks = append(toolCallbacks, b.toolRegistry.OutputLimitCallback(b.tokenCounter()))
+		}
+		toolCallbacks = append(toolCallbacks, newCitationCallback())
+		afterToolCallbacks = append(afterToolCallbacks, chainAfterToolCallbacks(toolCallbacks...))
+
+		group := newParallelToolGroup(MaxParallelToolCalls)
+		for i, t := range agentTools {
+			agentTools[i] = group.wrap(t)
+		}
+		for i, ts := range toolsets {
+			toolsets[i] = group.wrapToolset(ts)
+		}
+		afterModelCallbacks = append(afterModelCallbacks, group.afterModel())
+	}
+
 	return llmagent.New(llmagent.Config{
 		Name:                  config.ID,
 		Model:                 b.llm,
@@ -81,44 +153,82 @@ func (b *ExpertAgentBuilder) BuildAgentWithContext(config *models.AgentConfig, s
 		Tools:                 agentTools,
 		Toolsets:              toolsets,
 		GenerateContentConfig: generateConfig,
+		AfterToolCallbacks:    afterToolCallbacks,
+		AfterModelCallbacks:   afterModelCallbacks,
 	})
 }
 
+// BuildInstruction 构建与 BuildAgentWithContext 相同的完整指令，不创建 Agent（用于提示词预览）
+func (b *ExpertAgentBuilder) BuildInstruction(config *models.AgentConfig, stock *models.Stock, query string, replyContent string, position *models.StockPosition) string {
+	return b.buildInstructionWithContext(config, stock, query, replyContent, position)
+}
+
+// marketStatusText 将交易日历给出的市场状态转换为提示词中的描述
+func marketStatusText(status services.MarketStatus, now time.Time) string {
+	var text string
+	switch {
+	case !status.IsTradeDay:
+		text = i18n.Sprintf("休市（%s）", i18n.T(status.HolidayName))
+	case status.Status == "trading" && now.In(time.FixedZone("CST", 8*60*60)).Hour() < 12:
+		text = i18n.T("盘中（上午交易时段）")
+	case status.Status == "trading":
+		text = i18n.T("盘中（下午交易时段）")
+	case status.Status == "lunch_break":
+		text = i18n.T("午间休市")
+	case status.Status == "pre_market":
+		text = i18n.T("盘前")
+	default:
+		text = i18n.T("盘后")
+	}
+	if status.NextTradeDay != "" {
+		text += i18n.Sprintf("，下一交易日 %s", status.NextTradeDay)
+	}
+	return text
+}
+
+// formatSnapshot 个股快照的补充信息（价格与涨跌幅已在股票信息中给出），缺失的数据不输出
+func formatSnapshot(snap *models.StockSnapshot) string {
+	var sb strings.Builder
+	if snap.Open > 0 {
+		sb.WriteString(i18n.Sprintf("今日: 开盘 %.2f，最高 %.2f，最低 %.2f，昨收 %.2f，振幅 %.2f%%，成交额 %.2f亿\n",
+			snap.Open, snap.High, snap.Low, snap.PreClose, snap.Amplitude, snap.Amount/1e8))
+	}
+	if snap.TurnoverRate > 0 || snap.PB > 0 {
+		pe := "-"
+		switch {
+		case snap.PE > 0:
+			pe = fmt.Sprintf("%.2f", snap.PE)
+		case snap.PE < 0:
+			pe = i18n.T("亏损")
+		}
+		sb.WriteString(i18n.Sprintf("换手率: %.2f%%，市盈率(动): %s，市净率: %.2f\n", snap.TurnoverRate, pe, snap.PB))
+	}
+	if snap.High5D > 0 {
+		sb.WriteString(i18n.Sprintf("近5日: 区间 %.2f ~ %.2f，涨跌幅 %.2f%%\n", snap.Low5D, snap.High5D, snap.Change5D))
+	}
+	if snap.High52W > snap.Low52W && snap.Low52W > 0 {
+		position := (snap.Price - snap.Low52W) / (snap.High52W - snap.Low52W) * 100
+		sb.WriteString(i18n.Sprintf("52周区间: %.2f ~ %.2f，当前处于区间 %.0f%% 位置\n", snap.Low52W, snap.High52W, position))
+	}
+	return sb.String()
+}
+
 // buildInstructionWithContext 构建 Agent 指令（支持引用上下文）
 func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentConfig, stock *models.Stock, query string, replyContent string, position *models.StockPosition) string {
 	baseInstruction := config.Instruction
 	if baseInstruction == "" {
-		baseInstruction = fmt.Sprintf("你是一位%s，名字是%s。", config.Role, config.Name)
+		baseInstruction = i18n.Sprintf("你是一位%s，名字是%s。", config.Role, config.Name)
 	}
 
 	// 构建可用工具说明
 	toolsDescription := b.buildToolsDescription(config)
 
-	// 获取当前时间和盘中状态
+	// 获取当前时间和盘中状态（按交易日历判断，节假日不会误判为盘中）
 	now := time.Now()
 	timeStr := now.Format("2006-01-02 15:04:05")
-	weekday := now.Weekday()
-	hour, minute := now.Hour(), now.Minute()
-	currentMinutes := hour*60 + minute
-
-	// 判断盘中状态（A股交易时间：9:30-11:30, 13:00-15:00，周一至周五）
-	var marketStatus string
-	if weekday == time.Saturday || weekday == time.Sunday {
-		marketStatus = "休市（周末）"
-	} else if currentMinutes >= 9*60+30 && currentMinutes <= 11*60+30 {
-		marketStatus = "盘中（上午交易时段）"
-	} else if currentMinutes >= 13*60 && currentMinutes <= 15*60 {
-		marketStatus = "盘中（下午交易时段）"
-	} else if currentMinutes < 9*60+30 {
-		marketStatus = "盘前"
-	} else if currentMinutes > 15*60 {
-		marketStatus = "盘后"
-	} else {
-		marketStatus = "午间休市"
-	}
+	marketStatus := marketStatusText(services.GetTradingCalendar().Status(now), now)
 
-	prompt := fmt.Sprintf(`%s
-%s
+	prompt := baseInstruction + "\n" + i18n.Sprintf(`%s
 当前时间: %s
 市场状态: %s
 
@@ -133,10 +243,26 @@ func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentCon
 - 任何类似 <xxx:tool_call> 格式的标签
 直接使用 API 提供的 tool_calls 功能，不要在文本中模拟工具调用。
 
-股票: %s (%s)
+`, toolsDescription, timeStr, marketStatus)
+
+	if b.compareStock != nil {
+		prompt += i18n.Sprintf(`## 双股对比
+股票A: %s (%s)，当前价格 %.2f，涨跌幅 %.2f%%
+股票B: %s (%s)，当前价格 %.2f，涨跌幅 %.2f%%
+工具的股票代码参数可传入任意一只股票的代码，两只股票都要查询，并在同一维度下对比给出结论。
+`, stock.Symbol, stock.Name, stock.Price, stock.ChangePercent,
+			b.compareStock.Symbol, b.compareStock.Name, b.compareStock.Price, b.compareStock.ChangePercent)
+	} else {
+		prompt += i18n.Sprintf(`股票: %s (%s)
 当前价格: %.2f
 涨跌幅: %.2f%%
-`, baseInstruction, toolsDescription, timeStr, marketStatus, stock.Symbol, stock.Name, stock.Price, stock.ChangePercent)
+`, stock.Symbol, stock.Name, stock.Price, stock.ChangePercent)
+		if b.snapshot != nil {
+			if snap := b.snapshot(stock.Symbol); snap != nil {
+				prompt += formatSnapshot(snap)
+			}
+		}
+	}
 
 	// 如果有持仓信息，加入上下文
 	if position != nil && position.Shares > 0 {
@@ -147,66 +273,107 @@ func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentCon
 		if costAmount > 0 {
 			profitPercent = (profitLoss / costAmount) * 100
 		}
-		prompt += fmt.Sprintf(`
+		prompt += i18n.Sprintf(`
 用户持仓: %d股，成本价 %.2f
 持仓市值: %.2f，盈亏: %.2f (%.2f%%)
 `, position.Shares, position.CostPrice, marketValue, profitLoss, profitPercent)
 	}
 
+	// 对比两只股票需要更多篇幅
+	wordLimit := 150
+	if b.compareStock != nil {
+		wordLimit = 250
+	}
+	if b.longForm {
+		wordLimit = 1500
+	}
+
+	// 单股分析要求末尾给出结构化观点，用于计算多空共识分
+	verdictHint := ""
+	if b.compareStock == nil && !b.longForm {
+		verdictHint = i18n.Sprintf("\n回答最后单独一行给出你的观点：%s看多/看空/中性，信心 1-10（如「%s看多，信心 7/10」），不计入字数。", models.VerdictMarker, models.VerdictMarker)
+	}
+
 	// 如果有引用内容，加入上下文
 	if replyContent != "" {
-		prompt += fmt.Sprintf(`--- 引用的观点 ---
+		prompt += i18n.Sprintf(`--- 引用的观点 ---
 %s
 ---
 
 你的分析任务: %s
 
-请结合以上引用的观点，发表你的专业看法。可以赞同、补充或反驳。回复控制在150字以内。`, replyContent, query)
+请结合以上引用的观点，发表你的专业看法。可以赞同、补充或反驳。回复控制在%d字以内。%s`, replyContent, query, wordLimit, verdictHint)
 	} else {
-		prompt += fmt.Sprintf(`你的分析任务: %s
+		prompt += i18n.Sprintf(`你的分析任务: %s
 
-请用简洁专业的语言回答，控制在150字以内。`, query)
+请用简洁专业的语言回答，控制在%d字以内。%s`, query, wordLimit, verdictHint)
 	}
 
-	return prompt
+	return prompt + i18n.ReplyInstruction()
+}
+
+// toolGuideDescRunes 超出预算时工具描述保留的字数
+const toolGuideDescRunes = 40
+
+// toolGuideEntry 工具说明条目
+type toolGuideEntry struct {
+	name   string
+	desc   string
+	server string // MCP 服务器名称，内置工具为空
+}
+
+// line 格式化说明行：descRunes > 0 时截短描述，< 0 时只保留名称
+func (e toolGuideEntry) line(descRunes int) string {
+	if descRunes < 0 {
+		return "- " + e.name
+	}
+	desc := e.desc
+	if descRunes > 0 && utf8.RuneCountInString(desc) > descRunes {
+		desc = string([]rune(desc)[:descRunes]) + "…"
+	}
+	if e.server != "" {
+		return i18n.Sprintf("- %s: %s (来自 %s)", e.name, desc, e.server)
+	}
+	return fmt.Sprintf("- %s: %s", e.name, desc)
 }
 
 // buildToolsDescription 构建可用工具说明
 func (b *ExpertAgentBuilder) buildToolsDescription(config *models.AgentConfig) string {
-	var searchTools []string // 搜索类工具
-	var dataTools []string   // 数据查询工具
-	var otherTools []string  // 其他工具
+	if !b.supportsTools() {
+		return ""
+	}
+
+	var searchTools []toolGuideEntry // 搜索类工具
+	var dataTools []toolGuideEntry   // 数据查询工具
+	var otherTools []toolGuideEntry  // 其他工具
 
 	// 搜索类工具关键词
 	searchKeywords := []string{"search", "搜索", "web", "网页", "tavily", "google", "bing"}
+	classify := func(entry toolGuideEntry) {
+		if b.isSearchTool(entry.name, entry.desc, searchKeywords) {
+			searchTools = append(searchTools, entry)
+		} else if b.isDataTool(entry.name) {
+			dataTools = append(dataTools, entry)
+		} else {
+			otherTools = append(otherTools, entry)
+		}
+	}
 
 	// 获取内置工具信息并分类
 	if b.toolRegistry != nil && len(config.Tools) > 0 {
-		toolInfos := b.toolRegistry.GetToolInfosByNames(config.Tools)
-		for _, info := range toolInfos {
-			desc := fmt.Sprintf("- %s: %s", info.Name, info.Description)
-			if b.isSearchTool(info.Name, info.Description, searchKeywords) {
-				searchTools = append(searchTools, desc)
-			} else if b.isDataTool(info.Name) {
-				dataTools = append(dataTools, desc)
-			} else {
-				otherTools = append(otherTools, desc)
-			}
+		infos := b.toolRegistry.GetToolInfosByNames(config.Tools)
+		if config.UnifiedTools {
+			infos = b.toolRegistry.GetUnifiedToolInfos(config.Tools)
+		}
+		for _, info := range infos {
+			classify(toolGuideEntry{name: info.Name, desc: info.Description})
 		}
 	}
 
 	// 获取 MCP 工具信息并分类
 	if b.mcpManager != nil && len(config.MCPServers) > 0 {
-		mcpTools := b.mcpManager.GetToolInfosByServerIDs(config.MCPServers)
-		for _, info := range mcpTools {
-			desc := fmt.Sprintf("- %s: %s (来自 %s)", info.Name, info.Description, info.ServerName)
-			if b.isSearchTool(info.Name, info.Description, searchKeywords) {
-				searchTools = append(searchTools, desc)
-			} else if b.isDataTool(info.Name) {
-				dataTools = append(dataTools, desc)
-			} else {
-				otherTools = append(otherTools, desc)
-			}
+		for _, info := range b.mcpManager.GetToolInfosByServerIDs(config.MCPServers) {
+			classify(toolGuideEntry{name: info.Name, desc: info.Description, server: info.ServerName})
 		}
 	}
 
@@ -214,7 +381,30 @@ func (b *ExpertAgentBuilder) buildToolsDescription(config *models.AgentConfig) s
 		return ""
 	}
 
-	return b.formatToolsInstruction(searchTools, dataTools, otherTools)
+	lines := func(entries []toolGuideEntry, descRunes int) []string {
+		result := make([]string, 0, len(entries))
+		for _, e := range entries {
+			result = append(result, e.line(descRunes))
+		}
+		return result
+	}
+	format := func(descRunes int) string {
+		return b.formatToolsInstruction(lines(searchTools, descRunes), lines(dataTools, descRunes), lines(otherTools, descRunes))
+	}
+
+	// 原生工具声明已包含描述时只列名称
+	if b.aiConfig != nil && !b.aiConfig.NeedsToolDescriptions() {
+		return format(-1)
+	}
+	counter := b.tokenCounter()
+	guide := format(0)
+	for _, descRunes := range []int{toolGuideDescRunes, -1} {
+		if b.toolBudget <= 0 || counter.Count(guide) <= b.toolBudget {
+			break
+		}
+		guide = format(descRunes)
+	}
+	return guide
 }
 
 // isSearchTool 判断是否为搜索类工具
@@ -231,7 +421,7 @@ func (b *ExpertAgentBuilder) isSearchTool(name, description string, keywords []s
 
 // isDataTool 判断是否为数据查询工具
 func (b *ExpertAgentBuilder) isDataTool(name string) bool {
-	dataKeywords := []string{"kline", "k线", "realtime", "实时", "orderbook", "盘口", "news", "新闻"}
+	dataKeywords := []string{tools.MarketDataToolName, "kline", "k线", "realtime", "实时", "orderbook", "盘口", "tick", "逐笔", "news", "新闻"}
 	nameLower := strings.ToLower(name)
 	for _, kw := range dataKeywords {
 		if strings.Contains(nameLower, kw) {
@@ -245,21 +435,20 @@ func (b *ExpertAgentBuilder) isDataTool(name string) bool {
 func (b *ExpertAgentBuilder) formatToolsInstruction(searchTools, dataTools, otherTools []string) string {
 	var result strings.Builder
 
-	result.WriteString("\n## 工具使用规则（必须遵守）\n\n")
+	result.WriteString(i18n.T("\n## 工具使用规则（必须遵守）\n\n"))
 
 	// 搜索工具 - 强制使用
 	if len(searchTools) > 0 {
-		result.WriteString("### 搜索工具（遇到信息查询必须调用）\n")
+		result.WriteString(i18n.T("### 搜索工具（遇到信息查询必须调用）\n"))
 		for _, t := range searchTools {
 			result.WriteString(t + "\n")
 		}
-		result.WriteString("\n**重要**: 当用户询问新闻、事件、公告、研报、市场动态等信息时，")
-		result.WriteString("你**必须先调用搜索工具**获取最新信息，**禁止凭记忆回答**。\n\n")
+		result.WriteString(i18n.T("\n**重要**: 当用户询问新闻、事件、公告、研报、市场动态等信息时，你**必须先调用搜索工具**获取最新信息，**禁止凭记忆回答**。\n\n"))
 	}
 
 	// 数据工具
 	if len(dataTools) > 0 {
-		result.WriteString("### 数据查询工具\n")
+		result.WriteString(i18n.T("### 数据查询工具\n"))
 		for _, t := range dataTools {
 			result.WriteString(t + "\n")
 		}
@@ -268,7 +457,7 @@ func (b *ExpertAgentBuilder) formatToolsInstruction(searchTools, dataTools, othe
 
 	// 其他工具
 	if len(otherTools) > 0 {
-		result.WriteString("### 其他工具\n")
+		result.WriteString(i18n.T("### 其他工具\n"))
 		for _, t := range otherTools {
 			result.WriteString(t + "\n")
 		}
@@ -276,10 +465,39 @@ func (b *ExpertAgentBuilder) formatToolsInstruction(searchTools, dataTools, othe
 	}
 
 	// 通用指导
-	result.WriteString("### 工具调用原则\n")
-	result.WriteString("1. 需要实时数据时，必须调用工具，不要编造数据\n")
-	result.WriteString("2. 搜索类工具优先用于获取最新信息\n")
-	result.WriteString("3. 工具返回结果后再组织回答\n")
+	result.WriteString(i18n.T("### 工具调用原则\n"))
+	result.WriteString(i18n.T("1. 需要实时数据时，必须调用工具，不要编造数据\n"))
+	result.WriteString(i18n.T("2. 搜索类工具优先用于获取最新信息\n"))
+	result.WriteString(i18n.T("3. 工具返回结果后再组织回答\n"))
+	result.WriteString(i18n.T("4. 每个工具结果都带有 citation 引用编号（如 [1]），引用工具数据时在对应句末标注该编号，如“市盈率约25倍[1]”，不要编造编号\n"))
 
 	return result.String()
 }
+
+// tokenCounter 按专家模型选择 token 计数口径
+func (b *ExpertAgentBuilder) tokenCounter() tokens.Counter {
+	if b.aiConfig == nil {
+		return tokens.Default
+	}
+	return tokens.ForModel(string(b.aiConfig.Provider), b.aiConfig.ModelName)
+}
+
+// chainAfterToolCallbacks 依次执行工具回调，前一个修改后的结果传给下一个
+func chainAfterToolCallbacks(cbs ...llmagent.AfterToolCallback) llmagent.AfterToolCallback {
+	return func(ctx tool.Context, t tool.Tool, args, result map[string]any, err error) (map[string]any, error) {
+		changed := false
+		for _, cb := range cbs {
+			out, cbErr := cb(ctx, t, args, result, err)
+			if cbErr != nil {
+				return out, cbErr
+			}
+			if out != nil {
+				result, changed = out, true
+			}
+		}
+		if !changed {
+			return nil, nil
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,73 @@
+package adk
+
+import (
+	"context"
+	"iter"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// geminiHTTPOptions 构建 Gemini / Vertex AI 的 HTTP 选项（BaseURL 非空时覆盖默认端点）
+func geminiHTTPOptions(config *models.AIConfig) genai.HTTPOptions {
+	var opts genai.HTTPOptions
+	if baseURL := strings.TrimSpace(config.BaseURL); baseURL != "" {
+		opts.BaseURL = strings.TrimRight(baseURL, "/") + "/"
+	}
+	return opts
+}
+
+// geminiOptionsLLM 为每次请求注入 AIConfig 中的思考预算与安全设置
+type geminiOptionsLLM struct {
+	model.LLM
+	thinkingBudget *int32
+	safetySettings []*genai.SafetySetting
+}
+
+// withGeminiOptions 包装 Gemini 模型（未配置任何选项时原样返回）
+func withGeminiOptions(llm model.LLM, config *models.AIConfig) model.LLM {
+	if config.ThinkingBudget == nil && len(config.SafetySettings) == 0 {
+		return llm
+	}
+	w := &geminiOptionsLLM{LLM: llm, thinkingBudget: config.ThinkingBudget}
+	for _, ss := range config.SafetySettings {
+		if ss.Category == "" || ss.Threshold == "" {
+			continue
+		}
+		w.safetySettings = append(w.safetySettings, &genai.SafetySetting{
+			Category:  genai.HarmCategory(ss.Category),
+			Threshold: genai.HarmBlockThreshold(ss.Threshold),
+		})
+	}
+	return w
+}
+
+// GenerateContent 合并生成配置后透传（不修改调用方的请求对象）
+func (g *geminiOptionsLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	cfg := &genai.GenerateContentConfig{}
+	if req.Config != nil {
+		cp := *req.Config
+		cfg = &cp
+	}
+	if g.thinkingBudget != nil {
+		thinking := &genai.ThinkingConfig{}
+		if cfg.ThinkingConfig != nil {
+			cp := *cfg.ThinkingConfig
+			thinking = &cp
+		}
+		if thinking.ThinkingBudget == nil {
+			thinking.ThinkingBudget = g.thinkingBudget
+		}
+		cfg.ThinkingConfig = thinking
+	}
+	if len(g.safetySettings) > 0 && len(cfg.SafetySettings) == 0 {
+		cfg.SafetySettings = g.safetySettings
+	}
+
+	patched := *req
+	patched.Config = cfg
+	return g.LLM.GenerateContent(ctx, &patched, stream)
+}
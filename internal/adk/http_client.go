@@ -0,0 +1,130 @@
+package adk
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// 连接设置默认值
+const (
+	defaultConnectTimeout  = 30 * time.Second
+	defaultReadTimeout     = 120 * time.Second
+	defaultIdleConnTimeout = 90 * time.Second
+)
+
+// seconds 秒数转 Duration，非正数时使用默认值
+func seconds(v int, def time.Duration) time.Duration {
+	if v <= 0 {
+		return def
+	}
+	return time.Duration(v) * time.Second
+}
+
+// llmTransport 按 AI 配置的代理、连接与 TLS 设置创建 Transport
+func llmTransport(config *models.AIConfig) *http.Transport {
+	transport := proxy.GetManager().TransportFor(&config.Proxy)
+	h := config.HTTP
+
+	transport.DialContext = (&net.Dialer{
+		Timeout:   seconds(h.ConnectTimeout, defaultConnectTimeout),
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.IdleConnTimeout = seconds(h.IdleConnTimeout, defaultIdleConnTimeout)
+	transport.DisableKeepAlives = h.DisableKeepAlives
+
+	if h.CACert == "" && !h.InsecureSkipVerify {
+		return transport
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: h.InsecureSkipVerify}
+	if h.CACert != "" {
+		// 在系统根证书基础上追加，公网地址仍可正常校验
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if pool.AppendCertsFromPEM([]byte(h.CACert)) {
+			tlsConfig.RootCAs = pool
+		} else {
+			log.Warn("AI 配置 [%s] 的 CA 证书无效，已忽略", config.Name)
+		}
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
+
+// llmRoundTripper 模型调用使用的 RoundTripper，在 llmTransport 基础上限制读取超时
+func llmRoundTripper(config *models.AIConfig) http.RoundTripper {
+	return &readTimeoutTransport{
+		base:    llmTransport(config),
+		timeout: seconds(config.HTTP.ReadTimeout, defaultReadTimeout),
+	}
+}
+
+// readTimeoutTransport 收到响应头后，两次读取响应体之间超过 timeout 时取消请求
+// 不限制等待响应头的时间（推理模型首字可能很慢，由调用方的超时兜底），也不限制总时长，
+// 长时间的流式输出只要持续有数据就不会被中断
+type readTimeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *readTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	var fired atomic.Bool
+	timer := time.AfterFunc(t.timeout, func() {
+		fired.Store(true)
+		cancel()
+	})
+	resp.Body = &readTimeoutBody{ReadCloser: resp.Body, timer: timer, timeout: t.timeout, cancel: cancel, fired: &fired}
+	return resp, nil
+}
+
+// errReadTimeout 读取超时，不包装 context.Canceled，避免被当作主动取消而静默结束
+var errReadTimeout = errors.New("读取响应超时")
+
+// readTimeoutBody 每次读到数据后重置读取超时
+type readTimeoutBody struct {
+	io.ReadCloser
+	timer   *time.Timer
+	timeout time.Duration
+	cancel  context.CancelFunc
+	fired   *atomic.Bool
+	once    sync.Once
+}
+
+func (b *readTimeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.timer.Reset(b.timeout)
+	}
+	if err != nil && err != io.EOF && b.fired.Load() {
+		return n, fmt.Errorf("%w: %v 内未收到新数据", errReadTimeout, b.timeout)
+	}
+	return n, err
+}
+
+func (b *readTimeoutBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.timer.Stop()
+		b.cancel()
+	})
+	return err
+}
@@ -0,0 +1,109 @@
+package adk
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestReadTimeoutAllowsSlowStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 总时长超过读取超时，但每次数据间隔都在超时内
+		for i := 0; i < 4; i++ {
+			io.WriteString(w, "data\n")
+			w.(http.Flusher).Flush()
+			time.Sleep(60 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &readTimeoutTransport{base: http.DefaultTransport, timeout: 150 * time.Millisecond}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, err := io.ReadAll(resp.Body); err != nil || len(body) != 20 {
+		t.Fatalf("body = %q, err = %v", body, err)
+	}
+}
+
+func TestReadTimeoutAllowsSlowFirstByte(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 推理模型思考时迟迟不返回响应头，不应受读取超时限制
+		time.Sleep(250 * time.Millisecond)
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &readTimeoutTransport{base: http.DefaultTransport, timeout: 100 * time.Millisecond}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, err := io.ReadAll(resp.Body); err != nil || string(body) != "ok" {
+		t.Fatalf("body = %q, err = %v", body, err)
+	}
+}
+
+func TestReadTimeoutCancelsStalledResponse(t *testing.T) {
+	stall := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "data\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-stall:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(stall)
+
+	client := &http.Client{Transport: &readTimeoutTransport{base: http.DefaultTransport, timeout: 100 * time.Millisecond}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	start := time.Now()
+	if _, err := io.ReadAll(resp.Body); !errors.Is(err, errReadTimeout) || errors.Is(err, context.Canceled) {
+		t.Fatalf("stalled body err = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("read took %v", elapsed)
+	}
+}
+
+func TestLLMTransportCustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	get := func(h models.AIHTTPConfig) error {
+		cfg := &models.AIConfig{Name: "gw", Proxy: models.ProxyConfig{Mode: models.ProxyModeNone}, HTTP: h}
+		resp, err := (&http.Client{Transport: llmRoundTripper(cfg)}).Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	if err := get(models.AIHTTPConfig{}); err == nil {
+		t.Error("self-signed certificate should be rejected by default")
+	}
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := get(models.AIHTTPConfig{CACert: string(caPEM)}); err != nil {
+		t.Errorf("custom CA: %v", err)
+	}
+	if err := get(models.AIHTTPConfig{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("insecure: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package adk
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// KeyCooldown 单个 API Key 触发限流（HTTP 429）后的默认冷却时长
+const KeyCooldown = 60 * time.Second
+
+// apiKeyPool 多 API Key 轮询池
+// 轮询选择未处于冷却期的 Key；全部冷却时选择最早解除冷却的 Key（最久未被限流）
+type apiKeyPool struct {
+	mu       sync.Mutex
+	keys     []string
+	next     int
+	cooldown map[string]time.Time // key → 冷却截止时间
+}
+
+func newAPIKeyPool(keys []string) *apiKeyPool {
+	return &apiKeyPool{keys: keys, cooldown: make(map[string]time.Time)}
+}
+
+// pick 选择下一个可用 Key
+func (p *apiKeyPool) pick() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for i := 0; i < len(p.keys); i++ {
+		key := p.keys[(p.next+i)%len(p.keys)]
+		if until, ok := p.cooldown[key]; !ok || now.After(until) {
+			p.next = (p.next + i + 1) % len(p.keys)
+			return key
+		}
+	}
+
+	// 全部处于冷却期：选择最早解除冷却的 Key
+	best := p.keys[0]
+	for _, key := range p.keys[1:] {
+		if p.cooldown[key].Before(p.cooldown[best]) {
+			best = key
+		}
+	}
+	return best
+}
+
+// markLimited 标记 Key 被限流，进入冷却期
+func (p *apiKeyPool) markLimited(key string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cooldown[key] = time.Now().Add(d)
+}
+
+// keyPools 按 AI 配置 ID 共享的 Key 池（冷却状态跨模型实例保留）
+// Key 列表变更时替换为新池，配置删除后由 PruneKeyPools 移除
+var (
+	keyPools   = make(map[string]*apiKeyPool)
+	keyPoolsMu sync.Mutex
+)
+
+// getKeyPool 获取 AI 配置对应的 Key 池，Key 少于 2 个时返回 nil
+func getKeyPool(config *models.AIConfig) *apiKeyPool {
+	keys := config.AllAPIKeys()
+
+	keyPoolsMu.Lock()
+	defer keyPoolsMu.Unlock()
+	old := keyPools[config.ID]
+	if len(keys) < 2 {
+		delete(keyPools, config.ID)
+		return nil
+	}
+	if old != nil && slices.Equal(old.keys, keys) {
+		return old
+	}
+	pool := newAPIKeyPool(keys)
+	if old != nil {
+		// 保留仍在列表中的 Key 的冷却状态
+		old.mu.Lock()
+		for _, key := range keys {
+			if until, ok := old.cooldown[key]; ok {
+				pool.cooldown[key] = until
+			}
+		}
+		old.mu.Unlock()
+	}
+	keyPools[config.ID] = pool
+	return pool
+}
+
+// PruneKeyPools 移除已删除的 AI 配置的 Key 池，configIDs 为当前仍存在的配置
+func PruneKeyPools(configIDs []string) {
+	keyPoolsMu.Lock()
+	defer keyPoolsMu.Unlock()
+	for id := range keyPools {
+		if !slices.Contains(configIDs, id) {
+			delete(keyPools, id)
+		}
+	}
+}
+
+// authHeaders 各服务商携带 API Key 的请求头
+var authHeaders = []string{"Authorization", "x-api-key", "x-goog-api-key"}
+
+// keyPoolTransport 包装 RoundTripper，为每个请求轮换 API Key，429 时冷却该 Key
+type keyPoolTransport struct {
+	base http.RoundTripper
+	pool *apiKeyPool
+}
+
+func (t *keyPoolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := t.pool.pick()
+	req = req.Clone(req.Context())
+	for _, h := range authHeaders {
+		v := req.Header.Get(h)
+		if v == "" {
+			continue
+		}
+		if strings.HasPrefix(v, "Bearer ") {
+			req.Header.Set(h, "Bearer "+key)
+		} else {
+			req.Header.Set(h, key)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		cooldown := retryAfter(resp.Header.Get("Retry-After"))
+		t.pool.markLimited(key, cooldown)
+		log.Warn("API key ...%s rate limited, cooldown %v", keySuffix(key), cooldown)
+	}
+	return resp, err
+}
+
+// retryAfter 解析 Retry-After 头（秒数），缺省使用 KeyCooldown
+func retryAfter(v string) time.Duration {
+	if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return KeyCooldown
+}
+
+// keySuffix 日志中只显示 Key 末 4 位
+func keySuffix(key string) string {
+	if len(key) <= 4 {
+		return key
+	}
+	return key[len(key)-4:]
+}
+
+// withKeyPool 为配置了多个 API Key 的 AI 配置挂载 Key 轮换
+func withKeyPool(base http.RoundTripper, config *models.AIConfig) http.RoundTripper {
+	pool := getKeyPool(config)
+	if pool == nil {
+		return base
+	}
+	return &keyPoolTransport{base: base, pool: pool}
+}
@@ -0,0 +1,62 @@
+package adk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestAPIKeyPoolRoundRobinSkipsCooldown(t *testing.T) {
+	pool := newAPIKeyPool([]string{"k1", "k2", "k3"})
+
+	if got := []string{pool.pick(), pool.pick(), pool.pick()}; got[0] != "k1" || got[1] != "k2" || got[2] != "k3" {
+		t.Fatalf("round robin = %v", got)
+	}
+
+	pool.markLimited("k1", time.Minute)
+	for i := 0; i < 4; i++ {
+		if key := pool.pick(); key == "k1" {
+			t.Fatalf("pick %d returned key in cooldown", i)
+		}
+	}
+}
+
+func TestAPIKeyPoolAllLimitedPicksEarliestRecovery(t *testing.T) {
+	pool := newAPIKeyPool([]string{"k1", "k2"})
+	pool.markLimited("k1", 2*time.Minute)
+	pool.markLimited("k2", time.Minute)
+
+	if key := pool.pick(); key != "k2" {
+		t.Fatalf("pick = %q, want k2", key)
+	}
+}
+
+func TestGetKeyPoolReplacesAndPrunes(t *testing.T) {
+	cfg := &models.AIConfig{ID: "pool-test", APIKeys: []string{"k1", "k2"}}
+	pool := getKeyPool(cfg)
+	pool.markLimited("k2", time.Minute)
+	if getKeyPool(cfg) != pool {
+		t.Fatal("same keys should reuse the pool")
+	}
+
+	// Key 列表变更：替换同一配置的池，保留仍存在的 Key 的冷却状态
+	cfg.APIKeys = []string{"k2", "k3"}
+	updated := getKeyPool(cfg)
+	if updated == pool || keyPools["pool-test"] != updated {
+		t.Fatal("pool not replaced")
+	}
+	if key := updated.pick(); key != "k3" {
+		t.Errorf("pick = %q, want k3 (k2 still cooling down)", key)
+	}
+
+	// 配置删除后移除
+	PruneKeyPools([]string{"other"})
+	if _, ok := keyPools["pool-test"]; ok {
+		t.Error("pool not pruned")
+	}
+	cfg.APIKeys = []string{"k1"}
+	if getKeyPool(cfg) != nil || keyPools["pool-test"] != nil {
+		t.Error("single key should not keep a pool")
+	}
+}
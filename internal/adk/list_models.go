@@ -0,0 +1,134 @@
+package adk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	go_openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/genai"
+)
+
+// ListModels 在线查询接口可用的模型列表
+func (f *ModelFactory) ListModels(ctx context.Context, config *models.AIConfig) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	var (
+		names []string
+		err   error
+	)
+	switch config.Provider {
+	case models.AIProviderOpenAI:
+		names, err = f.listOpenAIModels(ctx, config)
+	case models.AIProviderAnthropic:
+		names, err = f.listAnthropicModels(ctx, config)
+	case models.AIProviderGemini:
+		names, err = f.listGeminiModels(ctx, config)
+	case models.AIProviderMock:
+		names = []string{"mock"}
+	default:
+		return nil, fmt.Errorf("不支持查询模型列表的 provider: %s", config.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// listOpenAIModels 查询 OpenAI 兼容接口 /models
+func (f *ModelFactory) listOpenAIModels(ctx context.Context, config *models.AIConfig) ([]string, error) {
+	openaiCfg := go_openai.DefaultConfig(config.APIKey)
+	openaiCfg.BaseURL = normalizeOpenAIBaseURL(config.BaseURL)
+	openaiCfg.HTTPClient = &http.Client{
+		Transport: &uaTransport{base: llmTransport(config)},
+	}
+	list, err := go_openai.NewClientWithConfig(openaiCfg).ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list.Models))
+	for _, m := range list.Models {
+		names = append(names, m.ID)
+	}
+	return names, nil
+}
+
+// listAnthropicModels 查询 Anthropic /v1/models
+func (f *ModelFactory) listAnthropicModels(ctx context.Context, config *models.AIConfig) ([]string, error) {
+	endpoint, err := url.JoinPath(normalizeAnthropicBaseURL(config.BaseURL), "v1", "models")
+	if err != nil {
+		return nil, fmt.Errorf("无效 BaseURL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?limit=1000", nil)
+	if err != nil {
+		return nil, fmt.Errorf("请求创建失败: %w", err)
+	}
+	req.Header.Set("x-api-key", config.APIKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	req.Header.Set("User-Agent", cherryStudioUA)
+
+	client := &http.Client{Transport: llmTransport(config)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	names := make([]string, 0, len(result.Data))
+	for _, m := range result.Data {
+		names = append(names, m.ID)
+	}
+	return names, nil
+}
+
+// listGeminiModels 查询 Gemini 可用于生成内容的模型
+func (f *ModelFactory) listGeminiModels(ctx context.Context, config *models.AIConfig) ([]string, error) {
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:  config.APIKey,
+		Backend: genai.BackendGeminiAPI,
+		HTTPClient: &http.Client{
+			Transport: &uaTransport{base: llmTransport(config)},
+		},
+		HTTPOptions: geminiHTTPOptions(config),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("客户端创建失败: %w", err)
+	}
+
+	var names []string
+	for m, err := range client.Models.All(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		for _, action := range m.SupportedActions {
+			if action == "generateContent" {
+				names = append(names, strings.TrimPrefix(m.Name, "models/"))
+				break
+			}
+		}
+	}
+	return names, nil
+}
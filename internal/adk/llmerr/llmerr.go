@@ -0,0 +1,74 @@
+// Package llmerr 模型调用的类型化错误：各模型适配层把上游 HTTP 状态包装为 StatusError，
+// 上层按状态码判断是否重试、归类失败原因，不再依赖错误文本
+package llmerr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/genai"
+)
+
+// maxMessageLen 错误信息中保留的响应体长度
+const maxMessageLen = 512
+
+// StatusError 上游返回非 2xx 状态码
+type StatusError struct {
+	StatusCode int
+	Message    string // 上游返回的错误信息（截断）
+	Err        error  // SDK 原始错误，没有时为 nil
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// New 根据状态码与响应体创建 StatusError
+func New(statusCode int, body string) *StatusError {
+	if r := []rune(body); len(r) > maxMessageLen {
+		body = string(r[:maxMessageLen]) + "..."
+	}
+	return &StatusError{StatusCode: statusCode, Message: body}
+}
+
+// Wrap 包装 SDK 错误并附带状态码，statusCode 为 0 时原样返回
+func Wrap(err error, statusCode int, message string) error {
+	if err == nil || statusCode == 0 {
+		return err
+	}
+	se := New(statusCode, message)
+	se.Err = err
+	return se
+}
+
+// StatusCode 取错误链中的 HTTP 状态码，没有时返回 0
+// Gemini/VertexAI 由 ADK 直接返回 genai.APIError，这里一并识别
+func StatusCode(err error) int {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode
+	}
+	var ge genai.APIError
+	if errors.As(err, &ge) {
+		return ge.Code
+	}
+	var gep *genai.APIError
+	if errors.As(err, &gep) && gep != nil {
+		return gep.Code
+	}
+	return 0
+}
+
+// RetryableStatus 状态码是否值得重试：请求超时、限流与服务端错误可重试，其余 4xx 重试也不会成功
+func RetryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return code >= 500
+}
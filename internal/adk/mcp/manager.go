@@ -10,6 +10,7 @@ import (
 
 	"github.com/run-bigpig/jcp/internal/logger"
 	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/adk/tool"
@@ -106,12 +107,12 @@ func (m *Manager) LoadConfigs(configs []models.MCPServerConfig) error {
 	return nil
 }
 
-// createTransport 根据配置创建 MCP 传输层
+// createTransport 根据配置创建 MCP 传输层，HTTP/SSE 传输使用全局代理
 func createTransport(cfg *models.MCPServerConfig) mcp.Transport {
 	switch cfg.TransportType {
 	case models.MCPTransportSSE:
 		log.Warn("创建 SSE 传输 [%s]: %s (已废弃)", cfg.Name, cfg.Endpoint)
-		return &mcp.SSEClientTransport{Endpoint: cfg.Endpoint}
+		return &mcp.SSEClientTransport{Endpoint: cfg.Endpoint, HTTPClient: proxy.GetManager().ClientFor(nil, 0)}
 	case models.MCPTransportCommand:
 		log.Info("创建 Command 传输 [%s]: %s %v", cfg.Name, cfg.Command, cfg.Args)
 		return &mcp.CommandTransport{Command: exec.Command(cfg.Command, cfg.Args...)}
@@ -119,6 +120,7 @@ func createTransport(cfg *models.MCPServerConfig) mcp.Transport {
 		log.Info("创建 StreamableHTTP 传输 [%s]: %s", cfg.Name, cfg.Endpoint)
 		return &mcp.StreamableClientTransport{
 			Endpoint:   cfg.Endpoint,
+			HTTPClient: proxy.GetManager().ClientFor(nil, 0),
 			MaxRetries: 3,
 		}
 	}
@@ -225,11 +227,7 @@ func (m *Manager) TestConnection(serverID string) *ServerStatus {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	impl := &mcp.Implementation{Name: cfg.Name, Version: "1.0.0"}
-	client := mcp.NewClient(impl, nil)
-	_, err := client.Connect(ctx, createTransport(cfg), nil)
-
-	if err != nil {
+	if err := Ping(ctx, cfg); err != nil {
 		log.Error("测试连接失败 [%s]: %v", cfg.Name, err)
 		return &ServerStatus{ID: serverID, Connected: false, Error: err.Error()}
 	}
@@ -237,6 +235,60 @@ func (m *Manager) TestConnection(serverID string) *ServerStatus {
 	return &ServerStatus{ID: serverID, Connected: true}
 }
 
+// Ping 建立一次连接后立即关闭，用于检测服务器可用性
+func Ping(ctx context.Context, cfg *models.MCPServerConfig) error {
+	impl := &mcp.Implementation{Name: cfg.Name, Version: "1.0.0"}
+	client := mcp.NewClient(impl, nil)
+	session, err := client.Connect(ctx, createTransport(cfg), nil)
+	if err != nil {
+		return err
+	}
+	session.Close()
+	return nil
+}
+
+// healthCheckEvery MCP 服务器健康检查间隔
+const healthCheckEvery = 5 * time.Minute
+
+// WatchHealth 后台定期检测已配置服务器的连接，服务器由可用变为断开时回调 onDown（生命周期绑定 ctx）
+func (m *Manager) WatchHealth(ctx context.Context, onDown func(name, errMsg string)) {
+	go func() {
+		ticker := time.NewTicker(healthCheckEvery)
+		defer ticker.Stop()
+
+		down := make(map[string]bool)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			m.mu.RLock()
+			configs := make([]*models.MCPServerConfig, 0, len(m.configs))
+			for _, cfg := range m.configs {
+				configs = append(configs, cfg)
+			}
+			m.mu.RUnlock()
+
+			for _, cfg := range configs {
+				pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+				err := Ping(pingCtx, cfg)
+				cancel()
+				if err == nil {
+					delete(down, cfg.ID)
+					continue
+				}
+				if !down[cfg.ID] {
+					down[cfg.ID] = true
+					log.Warn("MCP 服务器断开 [%s]: %v", cfg.Name, err)
+					onDown(cfg.Name, err.Error())
+				}
+			}
+		}
+	}()
+}
+
 // GetServerTools 获取指定 MCP 服务器的工具列表
 func (m *Manager) GetServerTools(serverID string) ([]ToolInfo, error) {
 	m.mu.RLock()
@@ -0,0 +1,182 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/run-bigpig/jcp/internal/pkg/tokens"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// 确保实现 model.LLM 接口
+var _ model.LLM = &Model{}
+
+// Model 按场景返回脚本化回复的模型
+type Model struct {
+	name     string
+	scenario *Scenario
+}
+
+// NewModel 创建 Mock 模型
+func NewModel(name string, scenario *Scenario) *Model {
+	if name == "" {
+		name = "mock"
+	}
+	return &Model{name: name, scenario: scenario}
+}
+
+// Name 返回模型名称
+func (m *Model) Name() string {
+	return m.name
+}
+
+// GenerateContent 实现 model.LLM 接口
+// 命中带工具调用的规则且上一条消息不是工具结果时先发起工具调用，否则返回回复文本
+func (m *Model) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		prompt := requestText(req)
+		idx := m.scenario.match(prompt)
+		if m.scenario.shouldFail(idx) {
+			msg := m.scenario.Rules[idx].Error
+			if msg == "" {
+				msg = "模拟接口错误 (HTTP 503)"
+			}
+			yield(nil, errors.New(msg))
+			return
+		}
+
+		reply := m.scenario.Default
+		var calls []ToolCall
+		if idx >= 0 {
+			rule := m.scenario.Rules[idx]
+			reply = rule.Reply
+			if len(rule.ToolCalls) > 0 && !hasToolResult(req) {
+				calls = rule.ToolCalls
+			}
+		}
+		reply = strings.TrimRight(expand(reply, prompt), "\n")
+		usage := &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount:     int32(tokens.Estimate(prompt)),
+			CandidatesTokenCount: int32(tokens.Estimate(reply)),
+		}
+
+		if len(calls) > 0 {
+			content := &genai.Content{Role: genai.RoleModel}
+			for i, tc := range calls {
+				content.Parts = append(content.Parts, &genai.Part{FunctionCall: &genai.FunctionCall{
+					ID:   fmt.Sprintf("mock_call_%d", i+1),
+					Name: tc.Name,
+					Args: expandArgs(tc.Args, prompt),
+				}})
+			}
+			yield(&model.LLMResponse{Content: content, TurnComplete: true, FinishReason: genai.FinishReasonStop, UsageMetadata: usage}, nil)
+			return
+		}
+
+		if stream {
+			for _, chunk := range splitChunks(reply, m.scenario.ChunkSize) {
+				select {
+				case <-ctx.Done():
+					yield(nil, ctx.Err())
+					return
+				case <-time.After(m.scenario.delay):
+				}
+				resp := &model.LLMResponse{
+					Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{Text: chunk}}},
+					Partial: true,
+				}
+				if !yield(resp, nil) {
+					return
+				}
+			}
+		} else if err := ctx.Err(); err != nil {
+			yield(nil, err)
+			return
+		}
+		yield(&model.LLMResponse{
+			Content:       genai.NewContentFromText(reply, genai.RoleModel),
+			TurnComplete:  true,
+			FinishReason:  genai.FinishReasonStop,
+			UsageMetadata: usage,
+		}, nil)
+	}
+}
+
+// requestText 拼接系统指令与全部消息文本
+func requestText(req *model.LLMRequest) string {
+	var sb strings.Builder
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		for _, p := range req.Config.SystemInstruction.Parts {
+			sb.WriteString(p.Text)
+			sb.WriteByte('\n')
+		}
+	}
+	for _, c := range req.Contents {
+		if c == nil {
+			continue
+		}
+		for _, p := range c.Parts {
+			sb.WriteString(p.Text)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// hasToolResult 最后一条消息是否为工具结果
+func hasToolResult(req *model.LLMRequest) bool {
+	if len(req.Contents) == 0 || req.Contents[len(req.Contents)-1] == nil {
+		return false
+	}
+	for _, p := range req.Contents[len(req.Contents)-1].Parts {
+		if p.FunctionResponse != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// expandArgs 替换工具参数中字符串值的占位符
+func expandArgs(args map[string]any, prompt string) map[string]any {
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		switch val := v.(type) {
+		case string:
+			out[k] = expand(val, prompt)
+		case []any:
+			list := make([]any, len(val))
+			for i, item := range val {
+				if s, ok := item.(string); ok {
+					item = expand(s, prompt)
+				}
+				list[i] = item
+			}
+			out[k] = list
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// splitChunks 按字符数切分流式输出
+func splitChunks(text string, size int) []string {
+	var chunks []string
+	for text != "" {
+		n, i := 0, 0
+		for i < len(text) && n < size {
+			_, w := utf8.DecodeRuneInString(text[i:])
+			i += w
+			n++
+		}
+		chunks = append(chunks, text[:i])
+		text = text[i:]
+	}
+	return chunks
+}
@@ -0,0 +1,137 @@
+package mock
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestParseYAML(t *testing.T) {
+	src := `# 注释
+name: "带 # 号" # 行尾注释
+count: 3
+ok: true
+list:
+- a
+- 'it''s'
+nested:
+  items:
+    - key: v1
+      flow: {"x": [1, 2]}
+    - key: v2
+text: |
+  第一行
+    缩进保留
+
+  第三段
+folded: >-
+  a
+  b
+`
+	got, err := parseYAML([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"name":  "带 # 号",
+		"count": int64(3),
+		"ok":    true,
+		"list":  []any{"a", "it's"},
+		"nested": map[string]any{"items": []any{
+			map[string]any{"key": "v1", "flow": map[string]any{"x": []any{float64(1), float64(2)}}},
+			map[string]any{"key": "v2"},
+		}},
+		"text":   "第一行\n  缩进保留\n\n第三段\n",
+		"folded": "a b",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseYAML =\n%#v\nwant\n%#v", got, want)
+	}
+
+	if _, err := parseYAML([]byte("a: 1\n   b: 2\n")); err == nil {
+		t.Error("expected indentation error")
+	}
+}
+
+func TestDefaultScenario(t *testing.T) {
+	s, err := ParseScenario(defaultScenario)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Rules) != 6 || s.Rules[3].ToolCalls[0].Args["code"] != "{{stock}}" || s.Rules[5].Fail != 3 {
+		t.Errorf("scenario = %+v", s.Rules)
+	}
+}
+
+func generate(t *testing.T, m *Model, req *model.LLMRequest, stream bool) (partial []string, final *model.LLMResponse, err error) {
+	t.Helper()
+	for resp, e := range m.GenerateContent(context.Background(), req, stream) {
+		if e != nil {
+			return partial, nil, e
+		}
+		if resp.Partial {
+			partial = append(partial, resp.Content.Parts[0].Text)
+			continue
+		}
+		final = resp
+	}
+	return partial, final, nil
+}
+
+func TestModel(t *testing.T) {
+	s, err := ParseScenario([]byte(`
+chunk_size: 4
+chunk_delay: 1ms
+default: 默认
+rules:
+  - match: 技术
+    tool_calls:
+      - name: get_kline_data
+        args: {"code": "{{stock}}"}
+    reply: 均线多头排列
+  - match: 风控
+    fail: 1
+    error: 限流
+    reply: 注意回撤
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel("", s)
+	userReq := func(text string) *model.LLMRequest {
+		return &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText(text, genai.RoleUser)}}
+	}
+
+	// 先发起工具调用，收到工具结果后流式回复
+	req := userReq("技术面看 sz000001")
+	_, final, err := generate(t, m, req, true)
+	if err != nil || final == nil || final.Content.Parts[0].FunctionCall == nil {
+		t.Fatalf("final = %+v, err = %v", final, err)
+	}
+	if call := final.Content.Parts[0].FunctionCall; call.Name != "get_kline_data" || call.Args["code"] != "sz000001" {
+		t.Errorf("call = %+v", call)
+	}
+	req.Contents = append(req.Contents, final.Content, &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{
+		{FunctionResponse: &genai.FunctionResponse{Name: "get_kline_data", Response: map[string]any{"data": "..."}}},
+	}})
+	partial, final, err := generate(t, m, req, true)
+	if err != nil || strings.Join(partial, "") != "均线多头排列" || len(partial) != 2 || final.Content.Parts[0].Text != "均线多头排列" {
+		t.Errorf("partial = %q, final = %+v, err = %v", partial, final, err)
+	}
+
+	// 前 N 次命中返回错误
+	if _, _, err := generate(t, m, userReq("风控"), false); err == nil || err.Error() != "限流" {
+		t.Errorf("err = %v, want 限流", err)
+	}
+	if _, final, err := generate(t, m, userReq("风控"), false); err != nil || final.Content.Parts[0].Text != "注意回撤" {
+		t.Errorf("retry final = %+v, err = %v", final, err)
+	}
+
+	if _, final, _ := generate(t, m, userReq("其他"), false); final.Content.Parts[0].Text != "默认" {
+		t.Errorf("default = %+v", final)
+	}
+}
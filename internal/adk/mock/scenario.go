@@ -0,0 +1,170 @@
+// Package mock 按 YAML 场景文件返回脚本化回复与工具调用的 model.LLM，
+// 用于在没有 API Key 的情况下演示和测试完整的会议流程（流式输出、工具调用、中断与恢复）
+package mock
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed default.yaml
+var defaultScenario []byte
+
+// 流式输出默认参数
+const (
+	defaultChunkSize  = 6
+	defaultChunkDelay = 30 * time.Millisecond
+)
+
+// Scenario 脚本场景：规则按顺序匹配提示词（系统指令与全部消息），都不匹配时返回 Default
+type Scenario struct {
+	ChunkSize  int    `json:"chunk_size"`  // 流式输出每块字符数
+	ChunkDelay string `json:"chunk_delay"` // 流式输出每块间隔，如 30ms
+	Default    string `json:"default"`
+	Rules      []Rule `json:"rules"`
+
+	delay time.Duration
+	mu    sync.Mutex
+	fails map[int]int // 规则下标 -> 已模拟失败次数
+}
+
+// Rule 单条脚本规则
+type Rule struct {
+	Match     string     `json:"match"`      // 提示词包含该文本时命中
+	Reply     string     `json:"reply"`      // 回复内容，{{stock}} 替换为提示词中的第一个股票代码
+	ToolCalls []ToolCall `json:"tool_calls"` // 先发起的工具调用，收到工具结果后再回复 Reply
+	Fail      int        `json:"fail"`       // 前 N 次命中时返回 Error，用于演示重试与会议中断
+	Error     string     `json:"error"`
+}
+
+// ToolCall 脚本化的工具调用
+type ToolCall struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args"`
+}
+
+var (
+	scenarioMu    sync.Mutex
+	scenarioCache = make(map[string]*cachedScenario)
+)
+
+type cachedScenario struct {
+	modTime  time.Time
+	scenario *Scenario
+}
+
+// LoadScenario 加载场景文件，path 为空时使用内置演示场景
+// 同一文件在未修改时复用同一场景，失败计数跨会议保留，便于演示中断后恢复
+func LoadScenario(path string) (*Scenario, error) {
+	scenarioMu.Lock()
+	defer scenarioMu.Unlock()
+
+	var modTime time.Time
+	if path != "" {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取场景文件失败: %w", err)
+		}
+		modTime = info.ModTime()
+	}
+	if c, ok := scenarioCache[path]; ok && c.modTime.Equal(modTime) {
+		return c.scenario, nil
+	}
+
+	data := defaultScenario
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("读取场景文件失败: %w", err)
+		}
+	}
+	s, err := ParseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析场景文件失败: %w", err)
+	}
+	scenarioCache[path] = &cachedScenario{modTime: modTime, scenario: s}
+	return s, nil
+}
+
+// ParseScenario 解析 YAML 场景
+func ParseScenario(data []byte) (*Scenario, error) {
+	v, err := parseYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	s := &Scenario{fails: make(map[int]int)}
+	if v != nil {
+		if err := json.Unmarshal(raw, s); err != nil {
+			return nil, err
+		}
+	}
+
+	s.delay = defaultChunkDelay
+	if s.ChunkDelay != "" {
+		if s.delay, err = time.ParseDuration(s.ChunkDelay); err != nil {
+			return nil, fmt.Errorf("chunk_delay 格式错误: %w", err)
+		}
+	}
+	if s.ChunkSize <= 0 {
+		s.ChunkSize = defaultChunkSize
+	}
+	for i, r := range s.Rules {
+		if r.Match == "" {
+			return nil, fmt.Errorf("第 %d 条规则缺少 match", i+1)
+		}
+		for _, tc := range r.ToolCalls {
+			if tc.Name == "" {
+				return nil, fmt.Errorf("第 %d 条规则的工具调用缺少 name", i+1)
+			}
+		}
+	}
+	return s, nil
+}
+
+// match 返回命中的规则下标，-1 表示使用默认回复
+func (s *Scenario) match(prompt string) int {
+	for i, r := range s.Rules {
+		if strings.Contains(prompt, r.Match) {
+			return i
+		}
+	}
+	return -1
+}
+
+// shouldFail 规则是否还需要模拟失败（每次调用计数一次）
+func (s *Scenario) shouldFail(idx int) bool {
+	if idx < 0 || s.Rules[idx].Fail <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fails[idx] >= s.Rules[idx].Fail {
+		return false
+	}
+	s.fails[idx]++
+	return true
+}
+
+var stockCodePattern = regexp.MustCompile(`(?:sh|sz|bj)\d{6}`)
+
+// expand 替换回复与工具参数中的占位符
+func expand(text, prompt string) string {
+	if !strings.Contains(text, "{{stock}}") {
+		return text
+	}
+	code := stockCodePattern.FindString(prompt)
+	if code == "" {
+		code = "sh000001"
+	}
+	return strings.ReplaceAll(text, "{{stock}}", code)
+}
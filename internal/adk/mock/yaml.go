@@ -0,0 +1,266 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML 解析场景文件使用的 YAML 子集：块映射、块序列、纯量/引号标量、
+// 块标量（| 与 >）以及 JSON 写法的行内集合，结果为 map[string]any / []any / 标量
+func parseYAML(data []byte) (any, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	p := &yamlParser{lines: strings.Split(strings.ReplaceAll(text, "\t", "  "), "\n")}
+	indent, _, ok := p.peek()
+	if !ok {
+		return nil, nil
+	}
+	v, err := p.parseBlock(indent)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, ok := p.peek(); ok {
+		return nil, p.errorf("缩进不一致")
+	}
+	return v, nil
+}
+
+type yamlParser struct {
+	lines []string
+	pos   int
+}
+
+func (p *yamlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("第 %d 行: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// peek 跳过空行与注释行，返回下一行的缩进与内容
+func (p *yamlParser) peek() (int, string, bool) {
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		content := strings.TrimLeft(line, " ")
+		if content == "" || strings.HasPrefix(content, "#") || content == "---" {
+			continue
+		}
+		return len(line) - len(content), strings.TrimRight(content, " "), true
+	}
+	return 0, "", false
+}
+
+func isSeqItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	_, content, _ := p.peek()
+	if isSeqItem(content) {
+		return p.parseSeq(indent)
+	}
+	return p.parseMap(indent)
+}
+
+func (p *yamlParser) parseMap(indent int) (any, error) {
+	m := make(map[string]any)
+	for {
+		ind, content, ok := p.peek()
+		if !ok || ind < indent || (ind == indent && isSeqItem(content)) {
+			return m, nil
+		}
+		if ind > indent {
+			return nil, p.errorf("缩进不一致")
+		}
+		key, rest, ok := splitKey(content)
+		if !ok {
+			return nil, p.errorf("缺少冒号: %s", content)
+		}
+		p.pos++
+		v, err := p.parseValue(rest, indent, true)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+}
+
+func (p *yamlParser) parseSeq(indent int) (any, error) {
+	list := []any{}
+	for {
+		ind, content, ok := p.peek()
+		if !ok || ind != indent || !isSeqItem(content) {
+			return list, nil
+		}
+		item := strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+		col := indent + len(content) - len(item)
+		if _, _, isMap := splitKey(item); isMap && item[0] != '"' && item[0] != '\'' && item[0] != '{' && item[0] != '[' {
+			// 「- key: value」：把本行改写为以条目内容开头的映射继续解析
+			p.lines[p.pos] = strings.Repeat(" ", col) + item
+			v, err := p.parseMap(col)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			continue
+		}
+		p.pos++
+		v, err := p.parseValue(item, indent, false)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+}
+
+// parseValue 解析冒号或短横线后的值，为空时读取下一层缩进的块
+func (p *yamlParser) parseValue(rest string, indent int, inMap bool) (any, error) {
+	rest = stripComment(rest)
+	switch {
+	case rest == "":
+		ind, content, ok := p.peek()
+		if !ok {
+			return nil, nil
+		}
+		if ind > indent {
+			return p.parseBlock(ind)
+		}
+		if inMap && ind == indent && isSeqItem(content) {
+			return p.parseSeq(indent)
+		}
+		return nil, nil
+	case rest[0] == '|' || rest[0] == '>':
+		return p.blockScalar(rest, indent), nil
+	}
+	return p.scalar(rest)
+}
+
+// blockScalar 读取缩进大于 indent 的后续行作为块标量
+func (p *yamlParser) blockScalar(header string, indent int) string {
+	var raw []string
+	blockIndent := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		content := strings.TrimLeft(line, " ")
+		if content == "" {
+			raw = append(raw, "")
+			continue
+		}
+		ind := len(line) - len(content)
+		if ind <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = ind
+		}
+		raw = append(raw, line[min(ind, blockIndent):])
+	}
+	for len(raw) > 0 && raw[len(raw)-1] == "" {
+		raw = raw[:len(raw)-1]
+	}
+
+	var s string
+	if header[0] == '>' {
+		var sb strings.Builder
+		for i, line := range raw {
+			switch {
+			case i == 0:
+			case line == "" || raw[i-1] == "":
+				sb.WriteByte('\n')
+			default:
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(line)
+		}
+		s = sb.String()
+	} else {
+		s = strings.Join(raw, "\n")
+	}
+	if !strings.Contains(header, "-") && s != "" {
+		s += "\n"
+	}
+	return s
+}
+
+func (p *yamlParser) scalar(s string) (any, error) {
+	switch s[0] {
+	case '"':
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, p.errorf("字符串格式错误: %s", s)
+		}
+		return v, nil
+	case '\'':
+		if len(s) < 2 || s[len(s)-1] != '\'' {
+			return nil, p.errorf("字符串格式错误: %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case '{', '[':
+		var v any
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, p.errorf("行内集合需为 JSON 格式: %v", err)
+		}
+		return v, nil
+	}
+	switch s {
+	case "null", "~":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return s, nil
+}
+
+// splitKey 拆分「key: value」，引号内的冒号不参与拆分
+func splitKey(content string) (string, string, bool) {
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ':' && (i == len(content)-1 || content[i+1] == ' '):
+			key := strings.TrimSpace(content[:i])
+			if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+				key = key[1 : len(key)-1]
+			}
+			return key, strings.TrimSpace(content[i+1:]), key != ""
+		case c == '#' && i > 0 && content[i-1] == ' ':
+			return "", "", false
+		}
+	}
+	return "", "", false
+}
+
+// stripComment 去掉行尾注释，引号内的 # 保留
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 {
+				quote = c
+			}
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return strings.TrimRight(s[:i], " ")
+		}
+	}
+	return s
+}
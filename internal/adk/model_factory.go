@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -15,9 +16,10 @@ import (
 	"cloud.google.com/go/auth/credentials"
 	"cloud.google.com/go/auth/httptransport"
 	"github.com/run-bigpig/jcp/internal/adk/anthropic"
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
+	"github.com/run-bigpig/jcp/internal/adk/mock"
 	"github.com/run-bigpig/jcp/internal/adk/openai"
 	"github.com/run-bigpig/jcp/internal/models"
-	"github.com/run-bigpig/jcp/internal/pkg/proxy"
 
 	"github.com/run-bigpig/jcp/internal/logger"
 	go_openai "github.com/sashabaranov/go-openai"
@@ -56,12 +58,25 @@ func (f *ModelFactory) CreateModel(ctx context.Context, config *models.AIConfig)
 	case models.AIProviderVertexAI:
 		return f.createVertexAIModel(ctx, config)
 	case models.AIProviderOpenAI:
-		if config.UseResponses {
+		switch config.ResolvedAPIStyle() {
+		case models.APIStyleResponses:
 			return f.createOpenAIResponsesModel(config)
+		case models.APIStyleAuto:
+			responses, err := f.createOpenAIResponsesModel(config)
+			if err != nil {
+				return nil, err
+			}
+			chat, err := f.createOpenAIModel(config)
+			if err != nil {
+				return nil, err
+			}
+			return newAutoAPIStyleLLM(config, responses, chat), nil
 		}
 		return f.createOpenAIModel(config)
 	case models.AIProviderAnthropic:
 		return f.createAnthropicModel(config)
+	case models.AIProviderMock:
+		return f.createMockModel(config)
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", config.Provider)
 	}
@@ -74,17 +89,22 @@ func (f *ModelFactory) createGeminiModel(ctx context.Context, config *models.AIC
 		Backend: genai.BackendGeminiAPI,
 		// 注入代理 Transport
 		HTTPClient: &http.Client{
-			Transport: &uaTransport{base: proxy.GetManager().GetTransport()},
+			Transport: &uaTransport{base: withKeyPool(llmRoundTripper(config), config)},
 		},
+		HTTPOptions: geminiHTTPOptions(config),
 	}
 
-	return gemini.NewModel(ctx, config.ModelName, clientConfig)
+	llm, err := gemini.NewModel(ctx, config.ModelName, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	return withGeminiOptions(llm, config), nil
 }
 
 // createVertexAIModel 创建 Vertex AI 模型
 func (f *ModelFactory) createVertexAIModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
 	// 获取代理 Transport
-	uaRT := &uaTransport{base: proxy.GetManager().GetTransport()}
+	uaRT := &uaTransport{base: llmRoundTripper(config)}
 
 	// 获取凭证
 	var creds *auth.Credentials
@@ -116,34 +136,54 @@ func (f *ModelFactory) createVertexAIModel(ctx context.Context, config *models.A
 		Location:    config.Location,
 		Credentials: creds,
 		HTTPClient:  httpClient,
+		HTTPOptions: geminiHTTPOptions(config),
 	}
 
-	return gemini.NewModel(ctx, config.ModelName, clientConfig)
+	llm, err := gemini.NewModel(ctx, config.ModelName, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	return withGeminiOptions(llm, config), nil
 }
 
+// apiVersionSuffix 匹配以 API 版本号结尾的路径（如 /v1、/v4）
+var apiVersionSuffix = regexp.MustCompile(`/v\d+$`)
+
 // normalizeOpenAIBaseURL 规范化 OpenAI BaseURL
-// 确保 URL 以 /v1 结尾，兼容用户填写带或不带 /v1 的地址
+// 确保 URL 以版本号结尾（默认补 /v1），兼容用户填写带或不带 /v1 的地址，
+// 智谱等使用 /v4 的接口保持原样
 func normalizeOpenAIBaseURL(baseURL string) string {
 	if baseURL == "" {
 		return "https://api.openai.com/v1"
 	}
-	baseURL = strings.TrimRight(baseURL, "/")
-	if !strings.HasSuffix(baseURL, "/v1") {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if !apiVersionSuffix.MatchString(baseURL) {
 		baseURL += "/v1"
 	}
 	return baseURL
 }
 
+// createMockModel 创建按场景文件回复的 Mock 模型
+func (f *ModelFactory) createMockModel(config *models.AIConfig) (model.LLM, error) {
+	scenario, err := mock.LoadScenario(config.Scenario)
+	if err != nil {
+		return nil, err
+	}
+	return mock.NewModel(config.ModelName, scenario), nil
+}
+
 // createOpenAIModel 创建 OpenAI 兼容模型
 func (f *ModelFactory) createOpenAIModel(config *models.AIConfig) (model.LLM, error) {
 	openaiCfg := go_openai.DefaultConfig(config.APIKey)
 	openaiCfg.BaseURL = normalizeOpenAIBaseURL(config.BaseURL)
 	// 注入代理 Transport
 	openaiCfg.HTTPClient = &http.Client{
-		Transport: &uaTransport{base: proxy.GetManager().GetTransport()},
+		Transport: requestExtras(config).Transport(&uaTransport{base: withKeyPool(llmRoundTripper(config), config)}),
 	}
 
-	return openai.NewOpenAIModel(config.ModelName, openaiCfg, config.NoSystemRole), nil
+	llm := openai.NewOpenAIModel(config.ModelName, openaiCfg, config.NoSystemRole)
+	llm.StreamIdleTimeout = seconds(config.HTTP.StreamIdleTimeout, openai.DefaultStreamIdleTimeout)
+	return llm, nil
 }
 
 // normalizeAnthropicBaseURL 规范化 Anthropic BaseURL
@@ -160,7 +200,7 @@ func normalizeAnthropicBaseURL(baseURL string) string {
 func (f *ModelFactory) createAnthropicModel(config *models.AIConfig) (model.LLM, error) {
 	baseURL := normalizeAnthropicBaseURL(config.BaseURL)
 	httpClient := &http.Client{
-		Transport: &uaTransport{base: proxy.GetManager().GetTransport()},
+		Transport: &uaTransport{base: withKeyPool(llmRoundTripper(config), config)},
 	}
 	return anthropic.NewAnthropicModel(config.ModelName, config.APIKey, baseURL, httpClient, config.NoSystemRole), nil
 }
@@ -171,9 +211,17 @@ func (f *ModelFactory) createOpenAIResponsesModel(config *models.AIConfig) (mode
 
 	// 使用代理管理器的 HTTP Client
 	httpClient := &http.Client{
-		Transport: &uaTransport{base: proxy.GetManager().GetTransport()},
+		Transport: &uaTransport{base: withKeyPool(llmRoundTripper(config), config)},
 	}
-	return openai.NewResponsesModel(config.ModelName, config.APIKey, baseURL, httpClient, config.NoSystemRole), nil
+	llm := openai.NewResponsesModel(config.ModelName, config.APIKey, baseURL, httpClient, config.NoSystemRole)
+	llm.StreamIdleTimeout = seconds(config.HTTP.StreamIdleTimeout, openai.DefaultStreamIdleTimeout)
+	llm.Extras = requestExtras(config)
+	return llm, nil
+}
+
+// requestExtras OpenAI 兼容接口的自定义请求头与附加请求体字段
+func requestExtras(config *models.AIConfig) openai.RequestExtras {
+	return openai.RequestExtras{Headers: config.ExtraHeaders, Body: config.ExtraBody}
 }
 
 // TestConnection 测试 AI 配置的连通性
@@ -191,6 +239,9 @@ func (f *ModelFactory) TestConnection(ctx context.Context, config *models.AIConf
 		return f.testVertexAIConnection(ctx, config)
 	case models.AIProviderAnthropic:
 		return f.testAnthropicConnection(ctx, config)
+	case models.AIProviderMock:
+		_, err := mock.LoadScenario(config.Scenario)
+		return err
 	default:
 		return fmt.Errorf("不支持的 provider: %s", config.Provider)
 	}
@@ -219,7 +270,7 @@ func (f *ModelFactory) detectOpenAISystemRole(ctx context.Context, config *model
 	defer cancel()
 
 	baseURL := normalizeOpenAIBaseURL(config.BaseURL)
-	transport := proxy.GetManager().GetTransport()
+	transport := requestExtras(config).Transport(llmTransport(config))
 
 	systemPrompt := fmt.Sprintf(
 		"You must reply with exactly: %s. Do not add anything else.",
@@ -229,7 +280,8 @@ func (f *ModelFactory) detectOpenAISystemRole(ctx context.Context, config *model
 	var body map[string]any
 	var endpoint string
 
-	if config.UseResponses {
+	useResponses := config.ResolvedAPIStyle() == models.APIStyleResponses
+	if useResponses {
 		endpoint = strings.TrimSuffix(baseURL, "/") + "/responses"
 		body = map[string]any{
 			"model":             config.ModelName,
@@ -261,7 +313,7 @@ func (f *ModelFactory) detectOpenAISystemRole(ctx context.Context, config *model
 		return true
 	}
 
-	replyText := f.extractReplyText(respBody, config.UseResponses)
+	replyText := f.extractReplyText(respBody, useResponses)
 	if strings.Contains(replyText, systemRoleProbeKeyword) {
 		log.Info("模型 [%s] 支持 system role（暗号匹配）", config.ModelName)
 		return false
@@ -278,7 +330,7 @@ func (f *ModelFactory) detectAnthropicSystemRole(ctx context.Context, config *mo
 	defer cancel()
 
 	baseURL := normalizeAnthropicBaseURL(config.BaseURL)
-	transport := proxy.GetManager().GetTransport()
+	transport := llmTransport(config)
 
 	body := map[string]any{
 		"model":      config.ModelName,
@@ -347,15 +399,28 @@ func (f *ModelFactory) extractAnthropicReplyText(respBody []byte) string {
 }
 
 // testOpenAIConnection 测试 OpenAI 兼容接口连通性
-// 根据 UseResponses 配置决定使用 Responses API 或 Chat Completions API
+// 按接口风格测试 Responses API 或 Chat Completions API，auto 模式检测后写入 DetectedAPIStyle
 func (f *ModelFactory) testOpenAIConnection(ctx context.Context, config *models.AIConfig) error {
+	if config.APIStyle == models.APIStyleAuto {
+		style, err := f.detectOpenAIAPIStyle(ctx, config)
+		if err != nil {
+			return err
+		}
+		config.DetectedAPIStyle = style
+		return nil
+	}
+	return f.probeOpenAIEndpoint(ctx, config, config.ResolvedAPIStyle())
+}
+
+// probeOpenAIEndpoint 向指定风格的接口发送最小请求，非 200 时返回带状态码的错误
+func (f *ModelFactory) probeOpenAIEndpoint(ctx context.Context, config *models.AIConfig, style models.APIStyle) error {
 	baseURL := normalizeOpenAIBaseURL(config.BaseURL)
-	transport := proxy.GetManager().GetTransport()
+	transport := requestExtras(config).Transport(llmTransport(config))
 
 	var body map[string]interface{}
 	var endpoint string
 
-	if config.UseResponses {
+	if style == models.APIStyleResponses {
 		// 使用 Responses API 端点测试
 		endpoint = strings.TrimSuffix(baseURL, "/") + "/responses"
 		body = map[string]interface{}{
@@ -398,7 +463,7 @@ func (f *ModelFactory) testOpenAIConnection(ctx context.Context, config *models.
 	}
 
 	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+	return llmerr.New(resp.StatusCode, string(respBody))
 }
 
 // testGeminiConnection 测试 Gemini 连通性
@@ -424,7 +489,7 @@ func (f *ModelFactory) testVertexAIConnection(ctx context.Context, config *model
 // testAnthropicConnection 测试 Anthropic 连通性
 func (f *ModelFactory) testAnthropicConnection(ctx context.Context, config *models.AIConfig) error {
 	baseURL := normalizeAnthropicBaseURL(config.BaseURL)
-	transport := proxy.GetManager().GetTransport()
+	transport := llmTransport(config)
 
 	body := map[string]any{
 		"model":      config.ModelName,
@@ -462,7 +527,7 @@ func (f *ModelFactory) testAnthropicConnection(ctx context.Context, config *mode
 	}
 
 	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+	return llmerr.New(resp.StatusCode, string(respBody))
 }
 
 // testViaGenerate 通过 GenerateContent 发送最小请求测试连通性
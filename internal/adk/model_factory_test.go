@@ -23,3 +23,22 @@ func TestNormalizeAnthropicBaseURL(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeOpenAIBaseURL(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantURL string
+	}{
+		{input: "", wantURL: "https://api.openai.com/v1"},
+		{input: "https://api.deepseek.com", wantURL: "https://api.deepseek.com/v1"},
+		{input: "https://api.moonshot.cn/v1/", wantURL: "https://api.moonshot.cn/v1"},
+		{input: "https://open.bigmodel.cn/api/paas/v4", wantURL: "https://open.bigmodel.cn/api/paas/v4"},
+		{input: "https://dashscope.aliyuncs.com/compatible-mode/v1", wantURL: "https://dashscope.aliyuncs.com/compatible-mode/v1"},
+	}
+
+	for _, tc := range tests {
+		if got := normalizeOpenAIBaseURL(tc.input); got != tc.wantURL {
+			t.Errorf("normalizeOpenAIBaseURL(%q) = %q, want %q", tc.input, got, tc.wantURL)
+		}
+	}
+}
@@ -225,32 +225,12 @@ func toOpenAIChatCompletionRequest(req *model.LLMRequest, modelName string, noSy
 		// 处理系统指令
 		if req.Config.SystemInstruction != nil {
 			systemText := extractTextFromContent(req.Config.SystemInstruction)
-			if noSystemRole {
-				// 不支持 system role，将系统指令注入到第一条 user 消息前面
-				injected := false
-				for i, msg := range openaiMessages {
-					if msg.Role == openai.ChatMessageRoleUser {
-						openaiMessages[i].Content = systemText + "\n\n" + msg.Content
-						injected = true
-						break
-					}
-				}
-				if !injected {
-					// 没有 user 消息，作为独立 user 消息插入
-					userMsg := openai.ChatCompletionMessage{
-						Role:    openai.ChatMessageRoleUser,
-						Content: systemText,
-					}
-					openaiMessages = append([]openai.ChatCompletionMessage{userMsg}, openaiMessages...)
-				}
-			} else {
-				systemMsg := openai.ChatCompletionMessage{
+			if systemText != "" {
+				openaiMessages = append([]openai.ChatCompletionMessage{{
 					Role:    openai.ChatMessageRoleSystem,
 					Content: systemText,
-				}
-				openaiMessages = append([]openai.ChatCompletionMessage{systemMsg}, openaiMessages...)
+				}}, openaiMessages...)
 			}
-			openaiReq.Messages = openaiMessages
 		}
 
 		// 处理 JSON 模式
@@ -261,9 +241,54 @@ func toOpenAIChatCompletionRequest(req *model.LLMRequest, modelName string, noSy
 		}
 	}
 
+	// 不支持 system role：降级为 user 消息
+	if noSystemRole {
+		openaiMessages = downgradeSystemMessages(openaiMessages)
+	}
+	openaiReq.Messages = openaiMessages
+
 	return openaiReq, nil
 }
 
+// systemDowngradeDelimiter 系统指令降级合并到 user 消息时使用的分隔符
+const systemDowngradeDelimiter = "\n\n---\n\n"
+
+// downgradeSystemMessages 将 system 消息降级为 user 内容，供不接受 system role 的接口使用
+// 所有 system 文本按顺序合并，放在对话最前面：
+// 首条消息是 user 时用分隔符合并进去，否则（如历史以工具调用开头）插入独立 user 消息，
+// 不会挪到后续 user 消息中，保证 assistant tool_calls 与 tool 结果的相对顺序不变
+func downgradeSystemMessages(msgs []openai.ChatCompletionMessage) []openai.ChatCompletionMessage {
+	var systemTexts []string
+	rest := make([]openai.ChatCompletionMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg.Role == openai.ChatMessageRoleSystem {
+			if msg.Content != "" {
+				systemTexts = append(systemTexts, msg.Content)
+			}
+			continue
+		}
+		rest = append(rest, msg)
+	}
+	if len(systemTexts) == 0 {
+		return rest
+	}
+
+	systemText := strings.Join(systemTexts, "\n\n")
+	if len(rest) > 0 && rest[0].Role == openai.ChatMessageRoleUser && len(rest[0].MultiContent) == 0 {
+		if rest[0].Content != "" {
+			rest[0].Content = systemText + systemDowngradeDelimiter + rest[0].Content
+		} else {
+			rest[0].Content = systemText
+		}
+		return rest
+	}
+	userMsg := openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleUser,
+		Content: systemText,
+	}
+	return append([]openai.ChatCompletionMessage{userMsg}, rest...)
+}
+
 // toOpenAIChatCompletionMessage 将 genai.Content 转换为 OpenAI 消息
 // 关键：处理 thinking 模型的 reasoning_content
 func toOpenAIChatCompletionMessage(content *genai.Content) ([]openai.ChatCompletionMessage, error) {
@@ -472,11 +497,7 @@ func convertChatCompletionResponse(resp *openai.ChatCompletionResponse) (*model.
 	// 处理 usage
 	var usageMetadata *genai.GenerateContentResponseUsageMetadata
 	if resp.Usage.TotalTokens > 0 {
-		usageMetadata = &genai.GenerateContentResponseUsageMetadata{
-			PromptTokenCount:     int32(resp.Usage.PromptTokens),
-			CandidatesTokenCount: int32(resp.Usage.CompletionTokens),
-			TotalTokenCount:      int32(resp.Usage.TotalTokens),
-		}
+		usageMetadata = convertUsage(&resp.Usage)
 	}
 
 	return &model.LLMResponse{
@@ -487,6 +508,19 @@ func convertChatCompletionResponse(resp *openai.ChatCompletionResponse) (*model.
 	}, nil
 }
 
+// convertUsage 转换 token 用量（含提示词缓存命中数）
+func convertUsage(u *openai.Usage) *genai.GenerateContentResponseUsageMetadata {
+	usage := &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     int32(u.PromptTokens),
+		CandidatesTokenCount: int32(u.CompletionTokens),
+		TotalTokenCount:      int32(u.TotalTokens),
+	}
+	if u.PromptTokensDetails != nil {
+		usage.CachedContentTokenCount = int32(u.PromptTokensDetails.CachedTokens)
+	}
+	return usage
+}
+
 // convertFinishReason 转换结束原因
 func convertFinishReason(reason string) genai.FinishReason {
 	switch reason {
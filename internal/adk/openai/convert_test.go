@@ -0,0 +1,104 @@
+package openai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func newSystemRequest(contents ...*genai.Content) *model.LLMRequest {
+	return &model.LLMRequest{
+		Contents: contents,
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: "You are helpful."}}},
+		},
+	}
+}
+
+func roles(msgs []openai.ChatCompletionMessage) string {
+	rs := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		rs = append(rs, m.Role)
+	}
+	return strings.Join(rs, ",")
+}
+
+// TestSystemRoleKept 支持 system role 时系统指令作为首条 system 消息
+func TestSystemRoleKept(t *testing.T) {
+	req := newSystemRequest(&genai.Content{Role: "user", Parts: []*genai.Part{{Text: "hi"}}})
+	got, err := toOpenAIChatCompletionRequest(req, "m", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if roles(got.Messages) != "system,user" {
+		t.Fatalf("roles = %s, want system,user", roles(got.Messages))
+	}
+}
+
+// TestNoSystemRoleMergeIntoFirstUser 降级时合并进首条 user 消息，并使用分隔符
+func TestNoSystemRoleMergeIntoFirstUser(t *testing.T) {
+	req := newSystemRequest(&genai.Content{Role: "user", Parts: []*genai.Part{{Text: "hi"}}})
+	got, err := toOpenAIChatCompletionRequest(req, "m", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if roles(got.Messages) != "user" {
+		t.Fatalf("roles = %s, want user", roles(got.Messages))
+	}
+	want := "You are helpful." + systemDowngradeDelimiter + "hi"
+	if got.Messages[0].Content != want {
+		t.Errorf("content = %q, want %q", got.Messages[0].Content, want)
+	}
+}
+
+// TestNoSystemRoleToolCallOrdering 历史以工具调用开头时插入独立 user 消息，不打乱 tool_calls/tool 顺序
+func TestNoSystemRoleToolCallOrdering(t *testing.T) {
+	req := newSystemRequest(
+		&genai.Content{Role: "model", Parts: []*genai.Part{{
+			FunctionCall: &genai.FunctionCall{ID: "call_1", Name: "get_kline", Args: map[string]any{"code": "sh600519"}},
+		}}},
+		&genai.Content{Role: "user", Parts: []*genai.Part{{
+			FunctionResponse: &genai.FunctionResponse{ID: "call_1", Name: "get_kline", Response: map[string]any{"ok": true}},
+		}}},
+		&genai.Content{Role: "user", Parts: []*genai.Part{{Text: "继续分析"}}},
+	)
+	got, err := toOpenAIChatCompletionRequest(req, "m", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if roles(got.Messages) != "user,assistant,tool,user" {
+		t.Fatalf("roles = %s, want user,assistant,tool,user", roles(got.Messages))
+	}
+	if got.Messages[0].Content != "You are helpful." {
+		t.Errorf("first user = %q", got.Messages[0].Content)
+	}
+	if got.Messages[1].ToolCalls[0].ID != got.Messages[2].ToolCallID {
+		t.Error("tool 结果应紧跟对应的 tool_calls")
+	}
+	if got.Messages[3].Content != "继续分析" {
+		t.Errorf("后续 user 消息不应被修改: %q", got.Messages[3].Content)
+	}
+}
+
+// TestNoSystemRoleInlineSystemContent 历史中的 system 内容同样降级
+func TestNoSystemRoleInlineSystemContent(t *testing.T) {
+	req := newSystemRequest(
+		&genai.Content{Role: "user", Parts: []*genai.Part{{Text: "hi"}}},
+		&genai.Content{Role: "system", Parts: []*genai.Part{{Text: "extra"}}},
+	)
+	got, err := toOpenAIChatCompletionRequest(req, "m", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, m := range got.Messages {
+		if m.Role == openai.ChatMessageRoleSystem {
+			t.Fatalf("降级后不应存在 system 消息: %s", roles(got.Messages))
+		}
+	}
+	if !strings.HasPrefix(got.Messages[0].Content, "You are helpful.\n\nextra"+systemDowngradeDelimiter) {
+		t.Errorf("content = %q", got.Messages[0].Content)
+	}
+}
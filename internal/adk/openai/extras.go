@@ -0,0 +1,96 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// RequestExtras 附加到每个请求上的自定义请求头与请求体字段，
+// 用于需要版本号、租户 ID 或非标准参数（enable_thinking、repetition_penalty 等）的 OpenAI 兼容网关
+type RequestExtras struct {
+	Headers map[string]string
+	Body    map[string]any
+}
+
+// reservedBodyFields 由程序构造的核心字段，附加字段不能覆盖
+var reservedBodyFields = map[string]bool{
+	"model":                true,
+	"messages":             true,
+	"input":                true,
+	"stream":               true,
+	"tools":                true,
+	"previous_response_id": true,
+}
+
+// IsReservedBodyField 字段是否由程序构造、不允许通过附加字段覆盖
+func IsReservedBodyField(name string) bool {
+	return reservedBodyFields[name]
+}
+
+// SetHeaders 写入自定义请求头，同名请求头以自定义值为准
+func (e RequestExtras) SetHeaders(h http.Header) {
+	for k, v := range e.Headers {
+		h.Set(k, v)
+	}
+}
+
+// MergeBody 将附加字段合并进 JSON 请求体，核心字段保持不变
+func (e RequestExtras) MergeBody(body []byte) ([]byte, error) {
+	if len(e.Body) == 0 {
+		return body, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range e.Body {
+		if reservedBodyFields[k] {
+			continue
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		fields[k] = raw
+	}
+	return json.Marshal(fields)
+}
+
+// Transport 包装 RoundTripper，在 go-openai 等第三方客户端构造的请求上附加请求头与请求体字段
+// 没有附加内容时原样返回 base
+func (e RequestExtras) Transport(base http.RoundTripper) http.RoundTripper {
+	if len(e.Headers) == 0 && len(e.Body) == 0 {
+		return base
+	}
+	return &extrasTransport{base: base, extras: e}
+}
+
+type extrasTransport struct {
+	base   http.RoundTripper
+	extras RequestExtras
+}
+
+func (t *extrasTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper 不应修改原请求，复制后再改
+	req = req.Clone(req.Context())
+	t.extras.SetHeaders(req.Header)
+	if len(t.extras.Body) > 0 && req.Body != nil && req.Method == http.MethodPost {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		// 非 JSON 请求体（如文件上传）保持原样
+		if merged, err := t.extras.MergeBody(body); err == nil {
+			body = merged
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,79 @@
+package openai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// captureServer 记录收到的请求头与请求体，返回固定的 Chat Completions / Responses 结果
+func captureServer(t *testing.T, headers *http.Header, body *map[string]any) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*headers = r.Header.Clone()
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/responses" {
+			io.WriteString(w, `{"id":"resp_1","output":[{"type":"message","content":[{"type":"output_text","text":"ok"}]}]}`)
+			return
+		}
+		io.WriteString(w, `{"choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+var testExtras = RequestExtras{
+	Headers: map[string]string{"X-Api-Version": "2024-05", "X-Tenant-Id": "t1"},
+	Body:    map[string]any{"enable_thinking": false, "repetition_penalty": 1.05, "model": "hijacked"},
+}
+
+func checkExtras(t *testing.T, headers http.Header, body map[string]any) {
+	t.Helper()
+	if headers.Get("X-Api-Version") != "2024-05" || headers.Get("X-Tenant-Id") != "t1" {
+		t.Errorf("headers = %v", headers)
+	}
+	if body["enable_thinking"] != false || body["repetition_penalty"] != 1.05 {
+		t.Errorf("extra body fields missing: %v", body)
+	}
+	if body["model"] != "gpt" {
+		t.Errorf("reserved field overridden: model = %v", body["model"])
+	}
+}
+
+// TestChatRequestExtras 测试 Chat Completions 请求附带自定义请求头与请求体字段
+func TestChatRequestExtras(t *testing.T) {
+	var headers http.Header
+	var body map[string]any
+	srv := captureServer(t, &headers, &body)
+
+	cfg := openai.DefaultConfig("key")
+	cfg.BaseURL = srv.URL
+	cfg.HTTPClient = &http.Client{Transport: testExtras.Transport(http.DefaultTransport)}
+	for _, err := range NewOpenAIModel("gpt", cfg, false).GenerateContent(t.Context(), userRequest(), false) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkExtras(t, headers, body)
+}
+
+// TestResponsesRequestExtras 测试 Responses API 请求附带自定义请求头与请求体字段
+func TestResponsesRequestExtras(t *testing.T) {
+	var headers http.Header
+	var body map[string]any
+	srv := captureServer(t, &headers, &body)
+
+	llm := NewResponsesModel("gpt", "key", srv.URL, nil, false)
+	llm.Extras = testExtras
+	for _, err := range llm.GenerateContent(t.Context(), userRequest(), false) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkExtras(t, headers, body)
+}
@@ -7,11 +7,13 @@ import (
 	"io"
 	"iter"
 	"slices"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
 	"github.com/run-bigpig/jcp/internal/logger"
 )
 
@@ -28,6 +30,8 @@ type OpenAIModel struct {
 	Client       *openai.Client
 	ModelName    string
 	NoSystemRole bool // 不支持 system role 时需要降级处理
+	// 流式输出两个 chunk 之间的最长间隔，为 0 时使用 DefaultStreamIdleTimeout
+	StreamIdleTimeout time.Duration
 }
 
 // NewOpenAIModel 创建 OpenAI 模型
@@ -53,6 +57,19 @@ func (o *OpenAIModel) GenerateContent(ctx context.Context, req *model.LLMRequest
 	return o.generate(ctx, req)
 }
 
+// wrapAPIError 将 go-openai 返回的错误包装为带状态码的 llmerr.StatusError，便于上层判断是否重试
+func wrapAPIError(err error) error {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return llmerr.Wrap(err, apiErr.HTTPStatusCode, apiErr.Message)
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return llmerr.Wrap(err, reqErr.HTTPStatusCode, string(reqErr.Body))
+	}
+	return err
+}
+
 // generate 非流式生成
 func (o *OpenAIModel) generate(ctx context.Context, req *model.LLMRequest) iter.Seq2[*model.LLMResponse, error] {
 	return func(yield func(*model.LLMResponse, error) bool) {
@@ -64,7 +81,7 @@ func (o *OpenAIModel) generate(ctx context.Context, req *model.LLMRequest) iter.
 
 		resp, err := o.Client.CreateChatCompletion(ctx, openaiReq)
 		if err != nil {
-			yield(nil, err)
+			yield(nil, wrapAPIError(err))
 			return
 		}
 
@@ -87,20 +104,26 @@ func (o *OpenAIModel) generateStream(ctx context.Context, req *model.LLMRequest)
 			return
 		}
 		openaiReq.Stream = true
+		// 请求在最后一个 chunk 中返回 token 用量（用于费用估算）
+		openaiReq.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
 
-		stream, err := o.Client.CreateChatCompletionStream(ctx, openaiReq)
+		w := newIdleWatchdog(ctx, o.StreamIdleTimeout)
+		defer w.stop()
+
+		stream, err := o.Client.CreateChatCompletionStream(w.ctx, openaiReq)
 		if err != nil {
-			yield(nil, err)
+			yield(nil, wrapAPIError(err))
 			return
 		}
 		defer stream.Close()
 
-		o.processStream(stream, yield)
+		w.touch()
+		o.processStream(stream, w, yield)
 	}
 }
 
 // processStream 处理流式响应
-func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, yield func(*model.LLMResponse, error) bool) {
+func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, w *idleWatchdog, yield func(*model.LLMResponse, error) bool) {
 	aggregatedContent := &genai.Content{
 		Role:  "model",
 		Parts: []*genai.Part{},
@@ -134,17 +157,29 @@ func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, yield f
 	var streamErr error
 	for {
 		chunk, err := stream.Recv()
+		if err != nil && w.fired() {
+			modelLog.Warn("流式输出空闲超时（%v），中止请求", w.timeout)
+			yield(nil, w.err(err))
+			return
+		}
 		if errors.Is(err, context.Canceled) {
 			return
 		}
 		if err != nil {
 			if !errors.Is(err, io.EOF) {
-				streamErr = fmt.Errorf("流式读取错误: %w", err)
+				streamErr = fmt.Errorf("流式读取错误: %w", wrapAPIError(err))
 				modelLog.Warn("流式读取中断: %v", err)
 			}
 			break
 		}
 
+		w.touch()
+
+		// include_usage 时用量在 choices 为空的最后一个 chunk 中返回
+		if chunk.Usage != nil {
+			usageMetadata = convertUsage(chunk.Usage)
+		}
+
 		if len(chunk.Choices) == 0 {
 			continue
 		}
@@ -192,14 +227,6 @@ func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, yield f
 		if choice.FinishReason != "" {
 			finishReason = convertFinishReason(string(choice.FinishReason))
 		}
-
-		if chunk.Usage != nil {
-			usageMetadata = &genai.GenerateContentResponseUsageMetadata{
-				PromptTokenCount:     int32(chunk.Usage.PromptTokens),
-				CandidatesTokenCount: int32(chunk.Usage.CompletionTokens),
-				TotalTokenCount:      int32(chunk.Usage.TotalTokens),
-			}
-		}
 	}
 
 	// 刷新流式标签解析器（处理标签跨 chunk 场景）
@@ -0,0 +1,137 @@
+package openai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+)
+
+// maxChainEntries 最多记录的待续接响应数，超出时淘汰最早的
+const maxChainEntries = 256
+
+// responseChain 记录带工具调用的响应 ID，工具结果回传时使用 previous_response_id 续接，
+// 只发送新增的 function_call_output，无需重放完整历史
+type responseChain struct {
+	mu       sync.Mutex
+	byCallID map[string]*chainEntry
+	order    []*chainEntry
+	disabled bool // 接口不支持 previous_response_id 时关闭
+}
+
+// chainEntry 一次带工具调用的响应
+type chainEntry struct {
+	responseID string
+	callIDs    []string
+	prefixLen  int    // 产生该响应的请求 Contents 数量
+	prefixHash string // 请求 Contents 的摘要，用于确认历史未被改写
+}
+
+func newResponseChain() *responseChain {
+	return &responseChain{byCallID: make(map[string]*chainEntry)}
+}
+
+// remember 记录响应：contents 为产生该响应的请求历史，output 为模型输出
+// 只记录含标准工具调用的响应（第三方文本标记解析出的调用服务端并不知道）
+func (c *responseChain) remember(contents []*genai.Content, responseID string, output *genai.Content) {
+	callIDs := functionCallIDs(output)
+	if responseID == "" || len(callIDs) == 0 {
+		return
+	}
+	for _, id := range callIDs {
+		if id == "" || strings.HasPrefix(id, "vendor_call_") {
+			return
+		}
+	}
+	hash, ok := hashContents(contents)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.disabled {
+		return
+	}
+	entry := &chainEntry{responseID: responseID, callIDs: callIDs, prefixLen: len(contents), prefixHash: hash}
+	for _, id := range callIDs {
+		c.byCallID[id] = entry
+	}
+	c.order = append(c.order, entry)
+	if len(c.order) > maxChainEntries {
+		for _, id := range c.order[0].callIDs {
+			if c.byCallID[id] == c.order[0] {
+				delete(c.byCallID, id)
+			}
+		}
+		c.order = c.order[1:]
+	}
+}
+
+// resolve 若 contents 是已记录响应的工具结果回传，返回 previous_response_id 与模型输出之后的新增内容；
+// 否则返回空 ID 与原始 contents
+func (c *responseChain) resolve(contents []*genai.Content) (string, []*genai.Content) {
+	idx := -1
+	for i := len(contents) - 1; i >= 0; i-- {
+		if contents[i] != nil && contents[i].Role == genai.RoleModel {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return "", contents
+	}
+	callIDs := functionCallIDs(contents[idx])
+	if len(callIDs) == 0 {
+		return "", contents
+	}
+
+	c.mu.Lock()
+	entry := c.byCallID[callIDs[0]]
+	disabled := c.disabled
+	c.mu.Unlock()
+	if disabled || entry == nil || entry.prefixLen != idx || !slices.Equal(entry.callIDs, callIDs) {
+		return "", contents
+	}
+	if hash, ok := hashContents(contents[:idx]); !ok || hash != entry.prefixHash {
+		return "", contents
+	}
+	return entry.responseID, contents[idx+1:]
+}
+
+// disable 关闭续接并清空记录
+func (c *responseChain) disable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disabled = true
+	c.byCallID = make(map[string]*chainEntry)
+	c.order = nil
+}
+
+// functionCallIDs 提取内容中的工具调用 ID
+func functionCallIDs(content *genai.Content) []string {
+	if content == nil {
+		return nil
+	}
+	var ids []string
+	for _, part := range content.Parts {
+		if part != nil && part.FunctionCall != nil {
+			ids = append(ids, part.FunctionCall.ID)
+		}
+	}
+	return ids
+}
+
+// hashContents 计算历史内容摘要
+func hashContents(contents []*genai.Content) (string, bool) {
+	data, err := json.Marshal(contents)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), true
+}
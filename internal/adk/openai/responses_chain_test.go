@@ -0,0 +1,87 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// TestResponsesToolFollowUpUsesPreviousResponseID 测试工具结果回传时续接上一轮响应，只发送 function_call_output
+func TestResponsesToolFollowUpUsesPreviousResponseID(t *testing.T) {
+	var requests []CreateResponseRequest
+	rejectChain := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CreateResponseRequest
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &req)
+		requests = append(requests, req)
+		if rejectChain && req.PreviousResponseID != "" {
+			http.Error(w, `{"error":{"message":"Unsupported parameter: previous_response_id"}}`, http.StatusBadRequest)
+			return
+		}
+		n := len(requests)
+		json.NewEncoder(w).Encode(CreateResponseResponse{
+			ID: fmt.Sprintf("resp_%d", n),
+			Output: []ResponsesOutputItem{
+				{Type: "function_call", ID: fmt.Sprintf("fc_%d", n), CallID: fmt.Sprintf("call_%d", n), Name: "get_quote", Arguments: `{"code":"600519"}`},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	llm := NewResponsesModel("gpt", "key", srv.URL, nil, false)
+	run := func(contents []*genai.Content) *model.LLMResponse {
+		req := &model.LLMRequest{
+			Contents: contents,
+			Config:   &genai.GenerateContentConfig{SystemInstruction: genai.NewContentFromText("你是分析师", "")},
+		}
+		for resp, err := range llm.GenerateContent(context.Background(), req, false) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resp
+		}
+		return nil
+	}
+
+	history := []*genai.Content{genai.NewContentFromText("贵州茅台怎么样", genai.RoleUser)}
+	first := run(history)
+	followUp := append(history, first.Content, &genai.Content{
+		Role:  genai.RoleUser,
+		Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{ID: "call_1", Name: "get_quote", Response: map[string]any{"price": 1500}}}},
+	})
+	run(followUp)
+
+	got := requests[1]
+	items, _ := got.Input.([]any)
+	if got.PreviousResponseID != "resp_1" || len(items) != 1 || got.Instructions != "你是分析师" {
+		t.Fatalf("follow-up request = %+v", got)
+	}
+	if item := items[0].(map[string]any); item["type"] != "function_call_output" || item["call_id"] != "call_1" {
+		t.Errorf("input item = %v", item)
+	}
+
+	// 历史被改写时不续接
+	edited := append([]*genai.Content{genai.NewContentFromText("另一个问题", genai.RoleUser)}, followUp[1:]...)
+	run(edited)
+	if requests[2].PreviousResponseID != "" {
+		t.Errorf("edited history should replay full input: %+v", requests[2])
+	}
+
+	// 接口不支持时回退为完整历史
+	rejectChain = true
+	run(followUp)
+	if n := len(requests); n != 5 || requests[3].PreviousResponseID == "" || requests[4].PreviousResponseID != "" {
+		t.Fatalf("fallback requests = %+v", requests[3:])
+	}
+	if items, _ := requests[4].Input.([]any); len(items) != 3 {
+		t.Errorf("fallback should send full history: %v", requests[4].Input)
+	}
+}
@@ -259,11 +259,7 @@ func convertResponsesResponse(resp *CreateResponseResponse) (*model.LLMResponse,
 	// 处理 usage
 	var usageMetadata *genai.GenerateContentResponseUsageMetadata
 	if resp.Usage != nil {
-		usageMetadata = &genai.GenerateContentResponseUsageMetadata{
-			PromptTokenCount:     int32(resp.Usage.InputTokens),
-			CandidatesTokenCount: int32(resp.Usage.OutputTokens),
-			TotalTokenCount:      int32(resp.Usage.TotalTokens),
-		}
+		usageMetadata = convertResponsesUsage(resp.Usage)
 	}
 
 	return &model.LLMResponse{
@@ -273,3 +269,16 @@ func convertResponsesResponse(resp *CreateResponseResponse) (*model.LLMResponse,
 		TurnComplete:  true,
 	}, nil
 }
+
+// convertResponsesUsage 转换 Responses API 用量（含提示词缓存命中数）
+func convertResponsesUsage(u *ResponsesUsage) *genai.GenerateContentResponseUsageMetadata {
+	usage := &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     int32(u.InputTokens),
+		CandidatesTokenCount: int32(u.OutputTokens),
+		TotalTokenCount:      int32(u.TotalTokens),
+	}
+	if u.InputTokensDetails != nil {
+		usage.CachedContentTokenCount = int32(u.InputTokensDetails.CachedTokens)
+	}
+	return usage
+}
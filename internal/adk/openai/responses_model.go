@@ -10,15 +10,20 @@ import (
 	"iter"
 	"net/http"
 	"strings"
+	"time"
 
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
 	"github.com/run-bigpig/jcp/internal/logger"
 )
 
 var respLog = logger.New("openai:responses")
 
+// defaultHTTPTimeout 未传入 HTTP 客户端时的请求总超时，避免连接挂起导致协程泄漏
+const defaultHTTPTimeout = 10 * time.Minute
+
 // sseMaxBufferSize SSE 扫描器最大缓冲区（1MB），防止超长工具参数被截断
 const sseMaxBufferSize = 1024 * 1024
 
@@ -36,12 +41,17 @@ type ResponsesModel struct {
 	apiKey       string
 	modelName    string
 	NoSystemRole bool // 不支持 system role 时需要降级处理
+	// 流式输出两个事件之间的最长间隔，为 0 时使用 DefaultStreamIdleTimeout
+	StreamIdleTimeout time.Duration
+	// 自定义请求头与附加请求体字段
+	Extras RequestExtras
+	chain  *responseChain
 }
 
 // NewResponsesModel 创建 Responses API 模型
 func NewResponsesModel(modelName, apiKey, baseURL string, httpClient HTTPDoer, noSystemRole bool) *ResponsesModel {
 	if httpClient == nil {
-		httpClient = http.DefaultClient
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout}
 	}
 	return &ResponsesModel{
 		httpClient:   httpClient,
@@ -49,6 +59,7 @@ func NewResponsesModel(modelName, apiKey, baseURL string, httpClient HTTPDoer, n
 		apiKey:       apiKey,
 		modelName:    modelName,
 		NoSystemRole: noSystemRole,
+		chain:        newResponseChain(),
 	}
 }
 
@@ -84,26 +95,66 @@ func (r *ResponsesModel) doRequest(ctx context.Context, body []byte, stream bool
 		req.Header.Set("Cache-Control", "no-cache")
 		req.Header.Set("Connection", "keep-alive")
 	}
+	r.Extras.SetHeaders(req.Header)
 	return r.httpClient.Do(req)
 }
 
-// generate 非流式生成
-func (r *ResponsesModel) generate(ctx context.Context, req *model.LLMRequest) iter.Seq2[*model.LLMResponse, error] {
-	return func(yield func(*model.LLMResponse, error) bool) {
-		apiReq, err := toResponsesRequest(req, r.modelName, r.NoSystemRole)
-		if err != nil {
-			yield(nil, err)
-			return
+// buildRequest 构造请求体；stateful 时若为工具结果回传，使用 previous_response_id 只发送新增内容
+// 不支持 system role 时系统指令注入在首条 user 消息中，无法续接
+func (r *ResponsesModel) buildRequest(req *model.LLMRequest, stream, stateful bool) ([]byte, string, error) {
+	var prevID string
+	if stateful && !r.NoSystemRole {
+		var contents []*genai.Content
+		if prevID, contents = r.chain.resolve(req.Contents); prevID != "" {
+			trimmed := *req
+			trimmed.Contents = contents
+			req = &trimmed
 		}
-		apiReq.Stream = false
+	}
 
-		body, err := json.Marshal(apiReq)
-		if err != nil {
-			yield(nil, fmt.Errorf("序列化请求失败: %w", err))
-			return
-		}
+	apiReq, err := toResponsesRequest(req, r.modelName, r.NoSystemRole)
+	if err != nil {
+		return nil, "", err
+	}
+	apiReq.Stream = stream
+	apiReq.PreviousResponseID = prevID
+
+	body, err := json.Marshal(apiReq)
+	if err == nil {
+		body, err = r.Extras.MergeBody(body)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("序列化请求失败: %w", err)
+	}
+	return body, prevID, nil
+}
+
+// send 发送请求，续接失败（接口不支持 previous_response_id 或响应已过期）时关闭续接并改为发送完整历史
+func (r *ResponsesModel) send(ctx context.Context, req *model.LLMRequest, stream bool) (*http.Response, error) {
+	body, prevID, err := r.buildRequest(req, stream, true)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.doRequest(ctx, body, stream)
+	if err != nil || prevID == "" || (resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusNotFound) {
+		return resp, err
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	resp.Body.Close()
+	respLog.Warn("previous_response_id 续接失败 (HTTP %d)，改为发送完整历史: %s", resp.StatusCode, string(respBody))
+	r.chain.disable()
+
+	if body, _, err = r.buildRequest(req, stream, false); err != nil {
+		return nil, err
+	}
+	return r.doRequest(ctx, body, stream)
+}
 
-		resp, err := r.doRequest(ctx, body, false)
+// generate 非流式生成
+func (r *ResponsesModel) generate(ctx context.Context, req *model.LLMRequest) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		resp, err := r.send(ctx, req, false)
 		if err != nil {
 			yield(nil, err)
 			return
@@ -112,7 +163,7 @@ func (r *ResponsesModel) generate(ctx context.Context, req *model.LLMRequest) it
 
 		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
 			respBody, _ := io.ReadAll(resp.Body)
-			yield(nil, fmt.Errorf("Responses API 错误 (HTTP %d): %s", resp.StatusCode, string(respBody)))
+			yield(nil, fmt.Errorf("Responses API 错误: %w", llmerr.New(resp.StatusCode, string(respBody))))
 			return
 		}
 
@@ -127,6 +178,7 @@ func (r *ResponsesModel) generate(ctx context.Context, req *model.LLMRequest) it
 			yield(nil, err)
 			return
 		}
+		r.chain.remember(req.Contents, apiResp.ID, llmResp.Content)
 		yield(llmResp, nil)
 	}
 }
@@ -134,20 +186,10 @@ func (r *ResponsesModel) generate(ctx context.Context, req *model.LLMRequest) it
 // generateStream 流式生成
 func (r *ResponsesModel) generateStream(ctx context.Context, req *model.LLMRequest) iter.Seq2[*model.LLMResponse, error] {
 	return func(yield func(*model.LLMResponse, error) bool) {
-		apiReq, err := toResponsesRequest(req, r.modelName, r.NoSystemRole)
-		if err != nil {
-			yield(nil, err)
-			return
-		}
-		apiReq.Stream = true
-
-		body, err := json.Marshal(apiReq)
-		if err != nil {
-			yield(nil, fmt.Errorf("序列化请求失败: %w", err))
-			return
-		}
+		w := newIdleWatchdog(ctx, r.StreamIdleTimeout)
+		defer w.stop()
 
-		resp, err := r.doRequest(ctx, body, true)
+		resp, err := r.send(w.ctx, req, true)
 		if err != nil {
 			yield(nil, err)
 			return
@@ -156,16 +198,17 @@ func (r *ResponsesModel) generateStream(ctx context.Context, req *model.LLMReque
 
 		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
 			respBody, _ := io.ReadAll(resp.Body)
-			yield(nil, fmt.Errorf("Responses API 流式错误 (HTTP %d): %s", resp.StatusCode, string(respBody)))
+			yield(nil, fmt.Errorf("Responses API 流式错误: %w", llmerr.New(resp.StatusCode, string(respBody))))
 			return
 		}
 
-		r.processResponsesStream(resp.Body, yield)
+		w.touch()
+		r.processResponsesStream(resp.Body, req.Contents, w, yield)
 	}
 }
 
 // processResponsesStream 处理 Responses API 的 SSE 流
-func (r *ResponsesModel) processResponsesStream(body io.Reader, yield func(*model.LLMResponse, error) bool) {
+func (r *ResponsesModel) processResponsesStream(body io.Reader, contents []*genai.Content, w *idleWatchdog, yield func(*model.LLMResponse, error) bool) {
 	scanner := bufio.NewScanner(body)
 	scanner.Buffer(make([]byte, 0, 64*1024), sseMaxBufferSize)
 
@@ -175,6 +218,7 @@ func (r *ResponsesModel) processResponsesStream(body io.Reader, yield func(*mode
 	toolCallsMap := make(map[string]*responsesToolCallBuilder)
 	var toolCallOrder []string
 	var usageMetadata *genai.GenerateContentResponseUsageMetadata
+	var responseID string
 	var currentEventType string
 	thinkParser := newThinkTagStreamParser()
 
@@ -189,6 +233,7 @@ func (r *ResponsesModel) processResponsesStream(body io.Reader, yield func(*mode
 		if !ok || data == "" {
 			continue
 		}
+		w.touch()
 
 		switch currentEventType {
 		case "response.output_text.delta":
@@ -202,12 +247,17 @@ func (r *ResponsesModel) processResponsesStream(body io.Reader, yield func(*mode
 		case "response.output_item.done":
 			r.handleOutputItemDone(data, toolCallsMap, &toolCallOrder)
 		case "response.completed":
-			r.handleCompleted(data, &usageMetadata)
+			r.handleCompleted(data, &responseID, &usageMetadata)
 		}
 
 		currentEventType = ""
 	}
 
+	if err := scanner.Err(); err != nil && w.fired() {
+		respLog.Warn("流式输出空闲超时（%v），中止请求", w.timeout)
+		yield(nil, w.err(err))
+		return
+	}
 	if err := scanner.Err(); err != nil {
 		respLog.Warn("SSE 流读取错误: %v", err)
 		yield(nil, fmt.Errorf("SSE 流读取错误: %w", err))
@@ -255,6 +305,7 @@ func (r *ResponsesModel) processResponsesStream(body io.Reader, yield func(*mode
 		aggregatedContent.Parts = append([]*genai.Part{{Text: thoughtContent, Thought: true}}, aggregatedContent.Parts...)
 	}
 
+	r.chain.remember(contents, responseID, aggregatedContent)
 	finalResp := &model.LLMResponse{
 		Content:       aggregatedContent,
 		UsageMetadata: usageMetadata,
@@ -373,17 +424,14 @@ func (r *ResponsesModel) handleOutputItemDone(data string, toolCallsMap map[stri
 }
 
 // handleCompleted 处理 response.completed 事件
-func (r *ResponsesModel) handleCompleted(data string, usageMetadata **genai.GenerateContentResponseUsageMetadata) {
+func (r *ResponsesModel) handleCompleted(data string, responseID *string, usageMetadata **genai.GenerateContentResponseUsageMetadata) {
 	var completed ResponsesCompleted
 	if err := json.Unmarshal([]byte(data), &completed); err != nil {
 		respLog.Warn("解析完成事件失败: %v", err)
 		return
 	}
+	*responseID = completed.Response.ID
 	if completed.Response.Usage != nil {
-		*usageMetadata = &genai.GenerateContentResponseUsageMetadata{
-			PromptTokenCount:     int32(completed.Response.Usage.InputTokens),
-			CandidatesTokenCount: int32(completed.Response.Usage.OutputTokens),
-			TotalTokenCount:      int32(completed.Response.Usage.TotalTokens),
-		}
+		*usageMetadata = convertResponsesUsage(completed.Response.Usage)
 	}
 }
@@ -0,0 +1,63 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultStreamIdleTimeout 流式输出两个数据片段之间的默认最长间隔
+const DefaultStreamIdleTimeout = 60 * time.Second
+
+// ErrStreamIdle 流式输出空闲超时：上游停止发送但未断开连接，可重试
+var ErrStreamIdle = errors.New("流式输出空闲超时")
+
+// idleWatchdog 流式输出看门狗，超过 timeout 未收到新数据时以 ErrStreamIdle 取消请求，
+// 避免挂起的连接一直等到 Agent 层的总超时
+type idleWatchdog struct {
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+// newIdleWatchdog 创建看门狗，请求需使用返回的 ctx；timeout 为 0 时使用默认值
+func newIdleWatchdog(ctx context.Context, timeout time.Duration) *idleWatchdog {
+	if timeout <= 0 {
+		timeout = DefaultStreamIdleTimeout
+	}
+	w := &idleWatchdog{timeout: timeout}
+	w.ctx, w.cancel = context.WithCancelCause(ctx)
+	return w
+}
+
+// touch 流建立或收到数据时调用，首次调用开始计时
+func (w *idleWatchdog) touch() {
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.timeout, func() { w.cancel(ErrStreamIdle) })
+		return
+	}
+	w.timer.Reset(w.timeout)
+}
+
+// stop 结束看门狗并释放 ctx
+func (w *idleWatchdog) stop() {
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.cancel(nil)
+}
+
+// fired 是否因空闲超时而取消
+func (w *idleWatchdog) fired() bool {
+	return errors.Is(context.Cause(w.ctx), ErrStreamIdle)
+}
+
+// err 空闲超时时返回可识别的 ErrStreamIdle（不包装 context.Canceled，上层按可重试错误处理），否则原样返回
+func (w *idleWatchdog) err(err error) error {
+	if w.fired() {
+		return fmt.Errorf("%w: %v 内未收到新数据", ErrStreamIdle, w.timeout)
+	}
+	return err
+}
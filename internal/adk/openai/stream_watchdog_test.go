@@ -0,0 +1,82 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// stallingServer 先输出一个片段，之后保持连接但不再发送数据
+func stallingServer(t *testing.T, first string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, first)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func userRequest() *model.LLMRequest {
+	return &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("hi", genai.RoleUser)}}
+}
+
+// collectStream 收集流式输出的文本与最后的错误
+func collectStream(llm model.LLM) (string, error) {
+	var text string
+	var lastErr error
+	for resp, err := range llm.GenerateContent(context.Background(), userRequest(), true) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.Partial && resp.Content != nil {
+			for _, p := range resp.Content.Parts {
+				text += p.Text
+			}
+		}
+	}
+	return text, lastErr
+}
+
+// TestChatStreamIdleTimeout 测试 Chat Completions 流挂起时返回可重试的空闲超时错误
+func TestChatStreamIdleTimeout(t *testing.T) {
+	srv := stallingServer(t, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"部分\"}}]}\n\n")
+	cfg := openai.DefaultConfig("key")
+	cfg.BaseURL = srv.URL
+	llm := NewOpenAIModel("gpt", cfg, false)
+	llm.StreamIdleTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	text, err := collectStream(llm)
+	if !errors.Is(err, ErrStreamIdle) || errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v", err)
+	}
+	if text != "部分" || time.Since(start) > 2*time.Second {
+		t.Errorf("text = %q after %v", text, time.Since(start))
+	}
+}
+
+// TestResponsesStreamIdleTimeout 测试 Responses API 流挂起时返回可重试的空闲超时错误
+func TestResponsesStreamIdleTimeout(t *testing.T) {
+	srv := stallingServer(t, "event: response.output_text.delta\ndata: {\"delta\":\"部分\"}\n\n")
+	llm := NewResponsesModel("gpt", "key", srv.URL, nil, false)
+	llm.StreamIdleTimeout = 100 * time.Millisecond
+
+	text, err := collectStream(llm)
+	if !errors.Is(err, ErrStreamIdle) || errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v", err)
+	}
+	if text != "部分" {
+		t.Errorf("text = %q", text)
+	}
+}
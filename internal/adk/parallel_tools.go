@@ -0,0 +1,210 @@
+package adk
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// MaxParallelToolCalls 单轮发言中同时执行的工具调用上限
+const MaxParallelToolCalls = 4
+
+// functionTool 可执行的函数工具（与 ADK 内部的 FunctionTool 接口一致）
+type functionTool interface {
+	tool.Tool
+	Declaration() *genai.FunctionDeclaration
+	Run(ctx tool.Context, args any) (map[string]any, error)
+}
+
+// toolFuture 提前启动的工具调用
+type toolFuture struct {
+	done   chan struct{}
+	result map[string]any
+	err    error
+}
+
+// parallelToolGroup 单个 Agent 的并行工具执行器
+// ADK 按顺序逐个执行模型一次返回的多个工具调用；这里在模型回复后记下本轮调用，
+// 第一个工具开始执行时并发启动全部调用（受 limit 限制），后续工具直接等待各自的结果
+type parallelToolGroup struct {
+	limit int
+
+	mu      sync.Mutex
+	tools   map[string]functionTool
+	batch   []*genai.FunctionCall    // 最近一次模型回复中的工具调用，尚未启动
+	pending map[string][]*toolFuture // 调用签名（工具名 + 参数）-> 已启动的调用
+}
+
+func newParallelToolGroup(limit int) *parallelToolGroup {
+	return &parallelToolGroup{limit: limit, tools: make(map[string]functionTool), pending: make(map[string][]*toolFuture)}
+}
+
+// wrap 包装函数工具，其他类型的工具原样返回
+func (g *parallelToolGroup) wrap(t tool.Tool) tool.Tool {
+	ft, ok := t.(functionTool)
+	if !ok {
+		return t
+	}
+	g.mu.Lock()
+	g.tools[ft.Name()] = ft
+	g.mu.Unlock()
+	return &parallelTool{functionTool: ft, group: g}
+}
+
+// wrapToolset 包装 MCP 等工具集返回的工具
+func (g *parallelToolGroup) wrapToolset(ts tool.Toolset) tool.Toolset {
+	return &parallelToolset{Toolset: ts, group: g}
+}
+
+// afterModel 记录模型回复中的工具调用，少于两个时无需并发
+func (g *parallelToolGroup) afterModel() llmagent.AfterModelCallback {
+	return func(ctx agent.CallbackContext, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error) {
+		if respErr != nil || resp == nil || resp.Partial || resp.Content == nil {
+			return nil, nil
+		}
+		var calls []*genai.FunctionCall
+		for _, part := range resp.Content.Parts {
+			if part.FunctionCall != nil {
+				calls = append(calls, part.FunctionCall)
+			}
+		}
+		g.mu.Lock()
+		g.batch = nil
+		if len(calls) > 1 {
+			g.batch = calls
+		}
+		g.mu.Unlock()
+		return nil, nil
+	}
+}
+
+// start 并发启动本轮记录的全部工具调用
+// 内置工具与 MCP 工具只把 ctx 用作取消信号，同一轮的调用共用第一个工具的上下文
+func (g *parallelToolGroup) start(ctx tool.Context) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.batch) == 0 {
+		return
+	}
+	batch := g.batch
+	g.batch = nil
+	clear(g.pending)
+
+	sem := make(chan struct{}, max(g.limit, 1))
+	for _, call := range batch {
+		t, ok := g.tools[call.Name]
+		if !ok {
+			continue
+		}
+		key := callKey(call.Name, call.Args)
+		f := &toolFuture{done: make(chan struct{})}
+		g.pending[key] = append(g.pending[key], f)
+		go func(t functionTool, args map[string]any) {
+			defer close(f.done)
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				f.err = ctx.Err()
+				return
+			}
+			defer func() {
+				if r := recover(); r != nil {
+					f.err = fmt.Errorf("panic in tool %q: %v", t.Name(), r)
+				}
+			}()
+			f.result, f.err = t.Run(ctx, args)
+		}(t, call.Args)
+	}
+}
+
+// take 取出已提前启动的调用，没有时返回 nil
+func (g *parallelToolGroup) take(name string, args any) *toolFuture {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := callKey(name, args)
+	futures := g.pending[key]
+	if len(futures) == 0 {
+		return nil
+	}
+	g.pending[key] = futures[1:]
+	return futures[0]
+}
+
+// callKey 调用签名，参数按 JSON 序列化（map 键有序）
+func callKey(name string, args any) string {
+	data, _ := json.Marshal(args)
+	return name + "\x00" + string(data)
+}
+
+// parallelTool 并行执行的函数工具
+type parallelTool struct {
+	functionTool
+	group *parallelToolGroup
+}
+
+// Run 本轮有多个调用时等待提前启动的结果，否则直接执行
+func (t *parallelTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	t.group.start(ctx)
+	if f := t.group.take(t.Name(), args); f != nil {
+		select {
+		case <-f.done:
+			return f.result, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return t.functionTool.Run(ctx, args)
+}
+
+// ProcessRequest 将工具声明加入请求，工具表中登记包装后的工具（内部工具的 ProcessRequest 会登记自身）
+func (t *parallelTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	if req.Tools == nil {
+		req.Tools = make(map[string]any)
+	}
+	name := t.Name()
+	if _, ok := req.Tools[name]; ok {
+		return fmt.Errorf("duplicate tool: %q", name)
+	}
+	req.Tools[name] = t
+
+	decl := t.Declaration()
+	if decl == nil {
+		return nil
+	}
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	for _, gt := range req.Config.Tools {
+		if gt != nil && gt.FunctionDeclarations != nil {
+			gt.FunctionDeclarations = append(gt.FunctionDeclarations, decl)
+			return nil
+		}
+	}
+	req.Config.Tools = append(req.Config.Tools, &genai.Tool{FunctionDeclarations: []*genai.FunctionDeclaration{decl}})
+	return nil
+}
+
+// parallelToolset 包装工具集返回的工具
+type parallelToolset struct {
+	tool.Toolset
+	group *parallelToolGroup
+}
+
+func (ts *parallelToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools, err := ts.Toolset.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := make([]tool.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = ts.group.wrap(t)
+	}
+	return wrapped, nil
+}
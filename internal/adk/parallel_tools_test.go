@@ -0,0 +1,101 @@
+package adk
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+// testToolContext 只提供取消信号的工具上下文
+type testToolContext struct {
+	tool.Context
+	ctx context.Context
+}
+
+func (c testToolContext) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c testToolContext) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c testToolContext) Err() error                  { return c.ctx.Err() }
+func (c testToolContext) Value(key any) any           { return c.ctx.Value(key) }
+
+type slowInput struct {
+	Code string `json:"code"`
+}
+
+type slowOutput struct {
+	Data string `json:"data"`
+}
+
+func TestParallelToolsRunConcurrently(t *testing.T) {
+	var running, peak, calls atomic.Int32
+	newSlowTool := func(name string) tool.Tool {
+		tl, err := functiontool.New(functiontool.Config{Name: name, Description: name}, func(ctx tool.Context, in slowInput) (slowOutput, error) {
+			calls.Add(1)
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			running.Add(-1)
+			return slowOutput{Data: name + ":" + in.Code}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tl
+	}
+
+	group := newParallelToolGroup(MaxParallelToolCalls)
+	realtime := group.wrap(newSlowTool("get_stock_realtime")).(functionTool)
+	kline := group.wrap(newSlowTool("get_kline_data")).(functionTool)
+	news := group.wrap(newSlowTool("get_news")).(functionTool)
+
+	resp := &model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+		{FunctionCall: &genai.FunctionCall{Name: "get_stock_realtime", Args: map[string]any{"code": "sh600519"}}},
+		{FunctionCall: &genai.FunctionCall{Name: "get_kline_data", Args: map[string]any{"code": "sh600519"}}},
+		{FunctionCall: &genai.FunctionCall{Name: "get_news", Args: map[string]any{"code": "sh600519"}}},
+	}}}
+	if _, err := group.afterModel()(nil, resp, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// ADK 逐个执行，结果仍按各自的调用返回
+	ctx := testToolContext{ctx: context.Background()}
+	start := time.Now()
+	for _, tl := range []functionTool{realtime, kline, news} {
+		result, err := tl.Run(ctx, map[string]any{"code": "sh600519"})
+		if err != nil {
+			t.Fatalf("%s: %v", tl.Name(), err)
+		}
+		if want := tl.Name() + ":sh600519"; result["data"] != want {
+			t.Errorf("%s result = %v, want %s", tl.Name(), result["data"], want)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 120*time.Millisecond {
+		t.Errorf("elapsed %v, tools did not run concurrently", elapsed)
+	}
+	if peak.Load() < 2 || calls.Load() != 3 {
+		t.Errorf("peak concurrency = %d, calls = %d", peak.Load(), calls.Load())
+	}
+
+	// 单个调用不提前启动，直接执行
+	if _, err := group.afterModel()(nil, &model.LLMResponse{Content: &genai.Content{Parts: []*genai.Part{
+		{FunctionCall: &genai.FunctionCall{Name: "get_news", Args: map[string]any{"code": "sz000001"}}},
+	}}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := news.Run(ctx, map[string]any{"code": "sz000001"}); err != nil || result["data"] != "get_news:sz000001" {
+		t.Fatalf("single call = %v, %v", result, err)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("calls = %d, want 4", calls.Load())
+	}
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetMarketBreadthInput 市场宽度输入参数
+type GetMarketBreadthInput struct {
+	Top int `json:"top,omitzero" jsonschema:"领涨、领跌板块各返回条数，默认10，最大30"`
+}
+
+// GetMarketBreadthOutput 市场宽度输出
+type GetMarketBreadthOutput struct {
+	Data string `json:"data" jsonschema:"涨跌家数与板块排行"`
+}
+
+// createMarketBreadthTool 创建市场宽度工具
+func (r *Registry) createMarketBreadthTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetMarketBreadthInput) (GetMarketBreadthOutput, error) {
+		fmt.Printf("[Tool:get_market_breadth] 调用开始, top=%d\n", input.Top)
+
+		top := input.Top
+		if top <= 0 {
+			top = 10
+		}
+		if top > 30 {
+			top = 30
+		}
+
+		breadth, err := r.marketService.GetMarketBreadth()
+		if err != nil {
+			fmt.Printf("[Tool:get_market_breadth] 错误: %v\n", err)
+			return GetMarketBreadthOutput{}, err
+		}
+
+		var sb strings.Builder
+		total := breadth.Up + breadth.Down + breadth.Flat
+		fmt.Fprintf(&sb, "沪深涨跌: 上涨%d 下跌%d 平盘%d", breadth.Up, breadth.Down, breadth.Flat)
+		if total > 0 {
+			fmt.Fprintf(&sb, " 上涨占比%.1f%%", float64(breadth.Up)*100/float64(total))
+		}
+		sb.WriteString("\n")
+
+		sectors := breadth.Sectors
+		n := min(top, len(sectors))
+		sb.WriteString("\n【领涨板块】\n")
+		for i, s := range sectors[:n] {
+			writeSectorLine(&sb, i+1, s)
+		}
+		sb.WriteString("\n【领跌板块】\n")
+		for i := 0; i < n; i++ {
+			writeSectorLine(&sb, i+1, sectors[len(sectors)-1-i])
+		}
+
+		fmt.Printf("[Tool:get_market_breadth] 调用完成, 板块%d个\n", len(sectors))
+		return GetMarketBreadthOutput{Data: sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "get_market_breadth",
+		Description: i18n.T("获取沪深两市涨跌家数与行业板块涨跌排行，包括板块领涨股和主力净流入，用于判断市场整体情绪与主线"),
+	}, handler)
+}
+
+func writeSectorLine(sb *strings.Builder, rank int, s models.SectorPerf) {
+	sb.WriteString(i18n.Sprintf("%d. %s %.2f%% 涨%d/跌%d 主力净流入%s", rank, s.Name, s.ChangePercent,
+		s.UpCount, s.DownCount, i18n.Amount(s.NetInflow)))
+	if s.LeaderName != "" {
+		sb.WriteString(i18n.Sprintf(" 领涨:%s(%.2f%%)", s.LeaderName, s.LeaderChange))
+	}
+	sb.WriteString("\n")
+}
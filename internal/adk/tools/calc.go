@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/pkg/calc"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	calcDefaultBars = 60
+	calcMaxBars     = 500
+)
+
+// RunCalcInput 计算工具输入参数
+type RunCalcInput struct {
+	Script string `json:"script" jsonschema:"计算脚本：语句以分号或换行分隔，支持 name = 表达式 赋值、+ - * / % ^、比较与 && ||、下标 a[-1]，数组与数字逐元素运算"`
+	Code   string `json:"code,omitempty" jsonschema:"股票代码，如 sh600519；提供时注入K线数组 open/high/low/close/volume/amount（按时间升序）"`
+	Period string `json:"period,omitempty" jsonschema:"K线周期: 5m/15m/30m/60m, 1d, 1w, 1mo，默认1d"`
+	Bars   int    `json:"bars,omitzero" jsonschema:"K线根数，默认60，最大500"`
+	Adjust string `json:"adjust,omitempty" jsonschema:"复权方式: none, qfq, hfq，默认qfq"`
+}
+
+// RunCalcOutput 计算工具输出
+type RunCalcOutput struct {
+	Data string `json:"data" jsonschema:"计算结果"`
+}
+
+// createCalcTool 创建计算工具
+func (r *Registry) createCalcTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input RunCalcInput) (RunCalcOutput, error) {
+		fmt.Printf("[Tool:run_calc] 调用开始, code=%s, period=%s, bars=%d\n", input.Code, input.Period, input.Bars)
+
+		if strings.TrimSpace(input.Script) == "" {
+			return RunCalcOutput{Data: "请提供计算脚本"}, nil
+		}
+
+		var sb strings.Builder
+		vars := make(map[string]calc.Value)
+		if input.Code != "" {
+			period := input.Period
+			if period == "" {
+				period = "1d"
+			}
+			bars := input.Bars
+			if bars <= 0 {
+				bars = calcDefaultBars
+			}
+			bars = min(bars, calcMaxBars)
+			adjust := input.Adjust
+			if adjust == "" {
+				adjust = services.AdjustQFQ
+			}
+
+			klines, err := r.marketService.GetKLineData(input.Code, period, bars, adjust)
+			if err != nil {
+				fmt.Printf("[Tool:run_calc] 错误: %v\n", err)
+				return RunCalcOutput{}, err
+			}
+			if len(klines) == 0 {
+				return RunCalcOutput{Data: fmt.Sprintf("未获取到 %s 的K线数据", input.Code)}, nil
+			}
+
+			n := len(klines)
+			open, high, low, closes := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+			volume, amount := make([]float64, n), make([]float64, n)
+			for i, k := range klines {
+				open[i], high[i], low[i], closes[i] = k.Open, k.High, k.Low, k.Close
+				volume[i], amount[i] = float64(k.Volume), k.Amount
+			}
+			vars["open"] = calc.Array(open)
+			vars["high"] = calc.Array(high)
+			vars["low"] = calc.Array(low)
+			vars["close"] = calc.Array(closes)
+			vars["volume"] = calc.Array(volume)
+			vars["amount"] = calc.Array(amount)
+			fmt.Fprintf(&sb, "数据: %s %s K线%d根（%s ~ %s，%s）\n", input.Code, period, n, klines[0].Time, klines[n-1].Time, adjust)
+		}
+
+		results, err := calc.Eval(input.Script, vars)
+		if err != nil {
+			fmt.Printf("[Tool:run_calc] 错误: %v\n", err)
+			// 脚本错误返回给模型以便修正，而不是中断调用
+			return RunCalcOutput{Data: "计算失败: " + err.Error()}, nil
+		}
+		for _, res := range results {
+			fmt.Fprintf(&sb, "%s = %s\n", res.Expr, res.Value)
+		}
+
+		fmt.Printf("[Tool:run_calc] 调用完成, %d条结果\n", len(results))
+		return RunCalcOutput{Data: sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "run_calc",
+		Description: i18n.T(runCalcDescription) + i18n.Sprintf("示例: %s。内置函数: %s", runCalcExample, calc.Usage()),
+	}, handler)
+}
+
+// runCalcDescription 工具描述，创建工具时附带示例与内置函数列表，便于模型直接编写脚本
+const runCalcDescription = "在沙箱中确定性地执行数值计算脚本，可注入个股K线数组，用于计算波动率、回撤、收益率、均线等自定义指标，避免在文字中手算。"
+
+// runCalcExample 计算脚本示例
+const runCalcExample = "r = logret(close); vol = std(tail(r, 20)) * sqrt(252); dd = maxdd(close)"
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// RenderChartInput 图表工具输入参数
+type RenderChartInput struct {
+	Code   string `json:"code" jsonschema:"股票代码，如 sh600519"`
+	Period string `json:"period,omitempty" jsonschema:"K线周期: 1m, 5m/15m/30m/60m, 1d, 1w, 1mo，默认1d"`
+	Bars   int    `json:"bars,omitzero" jsonschema:"K线根数，默认60，最大250"`
+	Adjust string `json:"adjust,omitempty" jsonschema:"复权方式: none, qfq, hfq，默认qfq"`
+}
+
+// RenderChartOutput 图表工具输出
+type RenderChartOutput struct {
+	Data string `json:"data" jsonschema:"图表 Markdown 图片链接"`
+}
+
+// createChartTool 创建K线图表工具
+func (r *Registry) createChartTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input RenderChartInput) (RenderChartOutput, error) {
+		fmt.Printf("[Tool:render_chart] 调用开始, code=%s, period=%s, bars=%d\n", input.Code, input.Period, input.Bars)
+
+		if input.Code == "" {
+			fmt.Println("[Tool:render_chart] 错误: 未提供股票代码")
+			return RenderChartOutput{Data: "请提供股票代码"}, nil
+		}
+
+		greenUp := r.configService.GetConfig().CandleColorMode == "green-up"
+		chart, err := r.chartService.RenderKLine(input.Code, input.Period, input.Bars, input.Adjust, greenUp)
+		if err != nil {
+			fmt.Printf("[Tool:render_chart] 错误: %v\n", err)
+			return RenderChartOutput{}, err
+		}
+
+		fmt.Printf("[Tool:render_chart] 调用完成, %s\n", chart.File)
+		return RenderChartOutput{
+			Data: fmt.Sprintf("已生成%d根K线图（含MA5/10/20与成交量）。如需在回复中展示，请原样插入（标题行为图片说明）:\n**%s**\n![%s](%s)", chart.Bars, chart.Title, chart.Title, chart.URL),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "render_chart",
+		Description: i18n.T("生成个股K线图（蜡烛图、MA5/10/20均线、成交量）PNG，返回可直接插入回复的 Markdown 图片链接，让分析结论更直观"),
+	}, handler)
+}
@@ -0,0 +1,316 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	customToolTimeout  = 20 * time.Second
+	customToolMaxRunes = 4000    // 返回给模型的最大字符数
+	customToolMaxBody  = 4 << 20 // 读取响应体的上限，防止异常接口返回超大内容耗尽内存
+)
+
+var (
+	customToolNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,63}$`)
+	templateParamRegex  = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]+)\s*\}\}`)
+)
+
+// CustomToolOutput 自定义工具输出
+type CustomToolOutput struct {
+	Data string `json:"data" jsonschema:"接口返回数据"`
+}
+
+// newCustomHTTPTool 根据配置创建自定义 HTTP 工具
+func newCustomHTTPTool(cfg models.CustomToolConfig) (tool.Tool, error) {
+	if !customToolNameRegex.MatchString(cfg.Name) {
+		return nil, fmt.Errorf("工具名称无效: %q", cfg.Name)
+	}
+	if strings.TrimSpace(cfg.URL) == "" {
+		return nil, fmt.Errorf("工具 %s 未配置 URL", cfg.Name)
+	}
+	method := strings.ToUpper(strings.TrimSpace(cfg.Method))
+	if method == "" {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet && method != http.MethodPost {
+		return nil, fmt.Errorf("工具 %s 不支持的请求方法: %s", cfg.Name, cfg.Method)
+	}
+
+	schema := &jsonschema.Schema{Type: "object"}
+	if strings.TrimSpace(cfg.InputSchema) != "" {
+		if err := json.Unmarshal([]byte(cfg.InputSchema), schema); err != nil {
+			return nil, fmt.Errorf("工具 %s 参数 Schema 解析失败: %w", cfg.Name, err)
+		}
+	}
+	extract, err := parseExtractPath(cfg.Extract)
+	if err != nil {
+		return nil, fmt.Errorf("工具 %s 提取路径无效: %w", cfg.Name, err)
+	}
+
+	handler := func(ctx tool.Context, args map[string]any) (CustomToolOutput, error) {
+		fmt.Printf("[Tool:%s] 调用开始, args=%v\n", cfg.Name, args)
+
+		req, err := buildCustomRequest(cfg, method, args)
+		if err != nil {
+			fmt.Printf("[Tool:%s] 错误: %v\n", cfg.Name, err)
+			return CustomToolOutput{}, err
+		}
+		resp, err := proxy.GetManager().GetClientWithTimeout(customToolTimeout).Do(req.WithContext(ctx))
+		if err != nil {
+			fmt.Printf("[Tool:%s] 错误: %v\n", cfg.Name, err)
+			return CustomToolOutput{}, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, customToolMaxBody+1))
+		if err != nil {
+			return CustomToolOutput{}, err
+		}
+		truncated := len(body) > customToolMaxBody
+		if truncated {
+			body = body[:customToolMaxBody]
+		}
+		if resp.StatusCode >= 400 {
+			err := fmt.Errorf("接口返回 %d: %s", resp.StatusCode, truncateText(string(body), 200))
+			fmt.Printf("[Tool:%s] 错误: %v\n", cfg.Name, err)
+			return CustomToolOutput{}, err
+		}
+
+		data := string(body)
+		if extract != nil {
+			if truncated {
+				err := fmt.Errorf("响应超过 %d MB，无法提取", customToolMaxBody>>20)
+				fmt.Printf("[Tool:%s] 错误: %v\n", cfg.Name, err)
+				return CustomToolOutput{}, err
+			}
+			if data, err = extractJSON(body, extract); err != nil {
+				fmt.Printf("[Tool:%s] 错误: %v\n", cfg.Name, err)
+				return CustomToolOutput{}, err
+			}
+		}
+
+		fmt.Printf("[Tool:%s] 调用完成, 返回%d字节\n", cfg.Name, len(data))
+		return CustomToolOutput{Data: truncateText(data, customToolMaxRunes)}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: schema,
+	}, handler)
+}
+
+// buildCustomRequest 渲染 URL、请求头与请求体模板
+func buildCustomRequest(cfg models.CustomToolConfig, method string, args map[string]any) (*http.Request, error) {
+	target := renderTemplate(cfg.URL, args, url.QueryEscape)
+
+	var body io.Reader
+	if method == http.MethodPost {
+		if cfg.Body != "" {
+			body = strings.NewReader(renderTemplate(cfg.Body, args, jsonEscape))
+		} else {
+			data, err := json.Marshal(args)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(data)
+		}
+	}
+
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, renderTemplate(v, args, nil))
+	}
+	return req, nil
+}
+
+// renderTemplate 将 {{参数名}} 替换为参数值，缺失的参数替换为空串
+func renderTemplate(tmpl string, args map[string]any, escape func(string) string) string {
+	return templateParamRegex.ReplaceAllStringFunc(tmpl, func(m string) string {
+		name := templateParamRegex.FindStringSubmatch(m)[1]
+		v, ok := args[name]
+		if !ok || v == nil {
+			return ""
+		}
+		s := formatArg(v)
+		if escape != nil {
+			s = escape(s)
+		}
+		return s
+	})
+}
+
+// formatArg 参数值转为字符串，整数不带小数点
+func formatArg(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// jsonEscape 转义为 JSON 字符串内容（不含引号），用于请求体模板
+func jsonEscape(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data[1 : len(data)-1])
+}
+
+// extractStep 提取路径的一步：字段、下标或展开数组
+type extractStep struct {
+	key     string
+	index   int
+	isIndex bool
+	iterate bool
+}
+
+// parseExtractPath 解析 JQ 风格的提取路径，支持 .a.b、[0]、[] 及 ["key"]
+func parseExtractPath(path string) ([]extractStep, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil
+	}
+	if path == "." {
+		return []extractStep{}, nil
+	}
+	if path[0] != '.' && path[0] != '[' {
+		return nil, fmt.Errorf("路径需以 . 开头: %s", path)
+	}
+
+	var steps []extractStep
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+			j := i
+			for j < len(path) && path[j] != '.' && path[j] != '[' {
+				j++
+			}
+			if j > i {
+				steps = append(steps, extractStep{key: path[i:j]})
+			}
+			i = j
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("缺少 ]: %s", path)
+			}
+			inner := strings.TrimSpace(path[i+1 : i+end])
+			i += end + 1
+			switch {
+			case inner == "":
+				steps = append(steps, extractStep{iterate: true})
+			case inner[0] == '"':
+				key, err := strconv.Unquote(inner)
+				if err != nil {
+					return nil, fmt.Errorf("字段名无效: %s", inner)
+				}
+				steps = append(steps, extractStep{key: key})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("下标无效: %s", inner)
+				}
+				steps = append(steps, extractStep{index: n, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("无法解析: %s", path[i:])
+		}
+	}
+	return steps, nil
+}
+
+// extractJSON 按路径提取 JSON 响应，多个结果按行输出
+func extractJSON(body []byte, steps []extractStep) (string, error) {
+	var root any
+	if err := json.Unmarshal(body, &root); err != nil {
+		return "", fmt.Errorf("响应不是有效的 JSON: %w", err)
+	}
+
+	values := []any{root}
+	for _, step := range steps {
+		var next []any
+		for _, v := range values {
+			switch {
+			case step.iterate:
+				switch x := v.(type) {
+				case []any:
+					next = append(next, x...)
+				case map[string]any:
+					for _, item := range x {
+						next = append(next, item)
+					}
+				}
+			case step.isIndex:
+				if arr, ok := v.([]any); ok {
+					idx := step.index
+					if idx < 0 {
+						idx += len(arr)
+					}
+					if idx >= 0 && idx < len(arr) {
+						next = append(next, arr[idx])
+					}
+				}
+			default:
+				if m, ok := v.(map[string]any); ok {
+					if item, ok := m[step.key]; ok {
+						next = append(next, item)
+					}
+				}
+			}
+		}
+		values = next
+	}
+
+	if len(values) == 0 {
+		return "", fmt.Errorf("提取结果为空")
+	}
+	lines := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			lines = append(lines, s)
+			continue
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, string(data))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// truncateText 按字符截断文本
+func truncateText(s string, maxRunes int) string {
+	r := []rune(s)
+	if len(r) <= maxRunes {
+		return s
+	}
+	return string(r[:maxRunes]) + "...(已截断)"
+}
@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/tool"
+)
+
+func TestExtractJSON(t *testing.T) {
+	body := []byte(`{"data":{"items":[{"name":"茅台","pe":30.5},{"name":"五粮液","pe":20}],"total":2},"a.b":"dot"}`)
+	cases := map[string]string{
+		".data.total":         "2",
+		".data.items[0].name": "茅台",
+		".data.items[-1].pe":  "20",
+		".data.items[].name":  "茅台\n五粮液",
+		`.["a.b"]`:            "dot",
+		".data.items[1]":      `{"name":"五粮液","pe":20}`,
+	}
+	for path, want := range cases {
+		steps, err := parseExtractPath(path)
+		if err != nil {
+			t.Fatalf("parseExtractPath(%q): %v", path, err)
+		}
+		got, err := extractJSON(body, steps)
+		if err != nil {
+			t.Fatalf("extractJSON(%q): %v", path, err)
+		}
+		if got != want {
+			t.Errorf("extractJSON(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	steps, _ := parseExtractPath(".data.missing")
+	if _, err := extractJSON(body, steps); err == nil {
+		t.Error("missing path should return error")
+	}
+	for _, bad := range []string{"data", ".items[x]", ".items[0"} {
+		if _, err := parseExtractPath(bad); err == nil {
+			t.Errorf("parseExtractPath(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	args := map[string]any{"code": "600519", "days": float64(5), "q": "a b&c"}
+
+	cfg := models.CustomToolConfig{
+		Name: "demo",
+		URL:  "https://example.com/{{code}}?days={{ days }}&q={{q}}&x={{missing}}",
+	}
+	req, err := buildCustomRequest(cfg, "GET", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := req.URL.String(), "https://example.com/600519?days=5&q=a+b%26c&x="; got != want {
+		t.Errorf("url = %q, want %q", got, want)
+	}
+
+	if got := renderTemplate(`{"note":"{{q}}"}`, map[string]any{"q": `a"b`}, jsonEscape); got != `{"note":"a\"b"}` {
+		t.Errorf("json body = %s", got)
+	}
+	if got := renderTemplate("{{days}}/{{code}}", args, nil); got != "5/600519" {
+		t.Errorf("render = %s", got)
+	}
+}
+
+func TestNewCustomHTTPToolValidation(t *testing.T) {
+	valid := models.CustomToolConfig{
+		Name:        "my_quote",
+		URL:         "https://example.com/{{code}}",
+		InputSchema: `{"type":"object","properties":{"code":{"type":"string"}},"required":["code"]}`,
+		Extract:     ".data",
+	}
+	if _, err := newCustomHTTPTool(valid); err != nil {
+		t.Fatalf("valid config: %v", err)
+	}
+
+	invalid := []func(c *models.CustomToolConfig){
+		func(c *models.CustomToolConfig) { c.Name = "bad name" },
+		func(c *models.CustomToolConfig) { c.URL = "" },
+		func(c *models.CustomToolConfig) { c.Method = "DELETE" },
+		func(c *models.CustomToolConfig) { c.InputSchema = "{" },
+		func(c *models.CustomToolConfig) { c.Extract = "data" },
+	}
+	for i, mutate := range invalid {
+		c := valid
+		mutate(&c)
+		if _, err := newCustomHTTPTool(c); err == nil {
+			t.Errorf("case %d should fail", i)
+		}
+	}
+}
+
+func TestRegistryCustomToolsAndDisable(t *testing.T) {
+	r := &Registry{
+		tools:       make(map[string]tool.Tool),
+		toolInfos:   make(map[string]ToolInfo),
+		customTools: make(map[string]bool),
+		disabled:    make(map[string]bool),
+	}
+	cfgs := []models.CustomToolConfig{
+		{Name: "t1", URL: "https://example.com", Enabled: true},
+		{Name: "t2", URL: "https://example.com"},
+		{Name: "t1", URL: "https://example.com", Enabled: true},
+	}
+	if err := r.LoadCustomTools(cfgs); err == nil {
+		t.Error("duplicate name should be reported")
+	}
+	if _, ok := r.GetTool("t1"); !ok {
+		t.Fatal("t1 should be registered")
+	}
+	if _, ok := r.GetTool("t2"); ok {
+		t.Error("disabled config should not be registered")
+	}
+
+	r.SetToolEnabled("t1", false)
+	if len(r.GetTools([]string{"t1"})) != 0 || len(r.GetAllToolNames()) != 0 {
+		t.Error("disabled tool should be hidden")
+	}
+	if infos := r.GetAllToolInfos(); len(infos) != 1 || infos[0].Enabled || !infos[0].Custom {
+		t.Errorf("infos = %+v", infos)
+	}
+	r.SetToolEnabled("t1", true)
+	if len(r.GetAllTools()) != 1 {
+		t.Error("t1 should be enabled again")
+	}
+
+	// 重新加载时移除旧的自定义工具
+	if err := r.LoadCustomTools(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.GetAllToolInfos()) != 0 {
+		t.Error("custom tools should be removed on reload")
+	}
+}
+
+// bgToolContext 只提供 context 能力的工具上下文
+type bgToolContext struct {
+	tool.Context
+	ctx context.Context
+}
+
+func (c bgToolContext) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c bgToolContext) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c bgToolContext) Err() error                  { return c.ctx.Err() }
+func (c bgToolContext) Value(key any) any           { return c.ctx.Value(key) }
+
+func TestCustomHTTPToolLimitsBody(t *testing.T) {
+	big := strings.Repeat("a", customToolMaxBody+1024)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":"`+big+`"}`)
+	}))
+	defer srv.Close()
+
+	run := func(extract string) (map[string]any, error) {
+		tl, err := newCustomHTTPTool(models.CustomToolConfig{Name: "big", URL: srv.URL, Extract: extract})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := bgToolContext{ctx: context.Background()}
+		return tl.(runnableTool).Run(ctx, map[string]any{})
+	}
+	// 超出上限时截断读取，返回给模型的内容仍按字符上限截断
+	result, err := run("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := result["data"].(string); len([]rune(data)) > customToolMaxRunes+10 {
+		t.Errorf("data length = %d", len(data))
+	}
+	// 截断后的 JSON 无法提取，明确报错
+	if _, err := run(".data"); err == nil || !strings.Contains(err.Error(), "MB") {
+		t.Errorf("extract from oversized body: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetEventsInput 事件日历输入参数
+type GetEventsInput struct {
+	Code string `json:"code,omitempty" jsonschema:"股票代码，如 sh600519；为空则查询全部自选股"`
+	Days int    `json:"days,omitzero" jsonschema:"查询未来多少天内的事件，默认30天，最大180天"`
+}
+
+// GetEventsOutput 事件日历输出
+type GetEventsOutput struct {
+	Data string `json:"data" jsonschema:"事件列表"`
+}
+
+// createEventsTool 创建事件日历工具
+func (r *Registry) createEventsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetEventsInput) (GetEventsOutput, error) {
+		fmt.Printf("[Tool:get_events] 调用开始, code=%s, days=%d\n", input.Code, input.Days)
+
+		days := input.Days
+		if days <= 0 {
+			days = 30
+		}
+		if days > 180 {
+			days = 180
+		}
+
+		var events []services.StockEvent
+		var err error
+		if input.Code != "" {
+			events, err = r.eventService.GetStockEvents(input.Code, days)
+		} else {
+			watchlist := r.configService.GetWatchlist()
+			if len(watchlist) == 0 {
+				return GetEventsOutput{Data: "自选股为空，请提供股票代码"}, nil
+			}
+			codes := make([]string, 0, len(watchlist))
+			for _, s := range watchlist {
+				codes = append(codes, s.Symbol)
+			}
+			events, err = r.eventService.GetEvents(codes, days)
+		}
+		if err != nil {
+			fmt.Printf("[Tool:get_events] 错误: %v\n", err)
+			return GetEventsOutput{}, err
+		}
+		if len(events) == 0 {
+			return GetEventsOutput{Data: fmt.Sprintf("未来%d天内暂无财报、股东大会、解禁、分红等事件", days)}, nil
+		}
+
+		var sb strings.Builder
+		for _, e := range events {
+			fmt.Fprintf(&sb, "[%s][%s] %s(%s) %s", e.Date, services.EventTypeNames[e.Type], e.Name, e.Code, e.Title)
+			if e.Detail != "" {
+				fmt.Fprintf(&sb, "｜%s", e.Detail)
+			}
+			sb.WriteString("\n")
+		}
+
+		fmt.Printf("[Tool:get_events] 调用完成, 返回%d个事件\n", len(events))
+		return GetEventsOutput{Data: sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "get_events",
+		Description: i18n.T("获取个股或全部自选股近期的事件日历，包括财报预约披露日、股东大会、限售解禁、分红除权除息日，用于提示短期催化剂"),
+	}, handler)
+}
@@ -2,8 +2,10 @@ package tools
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
 	"github.com/run-bigpig/jcp/internal/services/hottrend"
 
 	"google.golang.org/adk/tool"
@@ -12,7 +14,7 @@ import (
 
 // GetHotTrendInput 舆情热点输入参数
 type GetHotTrendInput struct {
-	Platform string `json:"platform,omitzero" jsonschema:"平台名称，可选值：weibo/zhihu/bilibili/baidu/douyin/toutiao，不填则获取所有平台"`
+	Platform string `json:"platform,omitzero" jsonschema:"平台名称，可选值：weibo/zhihu/bilibili/baidu/douyin/toutiao/xueqiu/guba/ths，不填则获取所有平台"`
 	Limit    int    `json:"limit,omitzero" jsonschema:"每个平台返回的热点条数，默认10条"`
 }
 
@@ -59,7 +61,7 @@ func (r *Registry) createHotTrendTool() (tool.Tool, error) {
 
 	return functiontool.New(functiontool.Config{
 		Name:        "get_hottrend",
-		Description: "获取全网舆情热点，支持微博、知乎、B站、百度、抖音、头条等平台的实时热搜榜单",
+		Description: i18n.T("获取全网舆情热点，支持微博、知乎、B站、百度、抖音、头条等平台的实时热搜榜单，以及雪球、东财股吧、同花顺的个股人气榜"),
 	}, handler)
 }
 
@@ -84,3 +86,67 @@ func formatTrendResult(sb *strings.Builder, tr hottrend.HotTrendResult, limit in
 		count++
 	}
 }
+
+// GetHotTrendStocksInput 热点关联股票输入参数
+type GetHotTrendStocksInput struct {
+	Platform string `json:"platform,omitzero" jsonschema:"平台名称，可选值：weibo/zhihu/bilibili/baidu/douyin/toutiao/xueqiu/guba/ths，不填则扫描所有平台"`
+}
+
+// GetHotTrendStocksOutput 热点关联股票输出
+type GetHotTrendStocksOutput struct {
+	Data string `json:"data" jsonschema:"热点与关联股票/行业"`
+}
+
+// createHotTrendStocksTool 创建热点关联股票工具
+func (r *Registry) createHotTrendStocksTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetHotTrendStocksInput) (GetHotTrendStocksOutput, error) {
+		fmt.Printf("[Tool:get_hottrend_stocks] 调用开始, platform=%s\n", input.Platform)
+
+		if r.hotTrendService == nil {
+			return GetHotTrendStocksOutput{}, fmt.Errorf("舆情服务未初始化")
+		}
+
+		var platforms []string
+		if input.Platform != "" {
+			platforms = []string{input.Platform}
+		}
+		links := r.hotTrendService.GetHotTrendStocks(platforms)
+		if len(links) == 0 {
+			return GetHotTrendStocksOutput{Data: "当前热搜中未发现可关联的A股标的或行业"}, nil
+		}
+
+		var sb strings.Builder
+		mentions := make(map[string]int)
+		var order []hottrend.LinkedStock
+		sb.WriteString("【热点关联】\n")
+		for _, link := range links {
+			fmt.Fprintf(&sb, "[%s#%d] %s\n", link.PlatformCN, link.Rank, link.Title)
+			for _, s := range link.Stocks {
+				fmt.Fprintf(&sb, "  → %s(%s) %s 命中「%s」\n", s.Name, s.Symbol, s.Industry, s.Keyword)
+				if mentions[s.Symbol] == 0 {
+					order = append(order, s)
+				}
+				mentions[s.Symbol]++
+			}
+			if len(link.Sectors) > 0 {
+				fmt.Fprintf(&sb, "  → 相关行业: %s\n", strings.Join(link.Sectors, "、"))
+			}
+		}
+
+		if len(order) > 0 {
+			sort.SliceStable(order, func(i, j int) bool { return mentions[order[i].Symbol] > mentions[order[j].Symbol] })
+			sb.WriteString("\n【上榜标的】\n")
+			for _, s := range order {
+				fmt.Fprintf(&sb, "%s(%s) 出现%d次\n", s.Name, s.Symbol, mentions[s.Symbol])
+			}
+		}
+
+		fmt.Printf("[Tool:get_hottrend_stocks] 调用完成, 关联%d条热点\n", len(links))
+		return GetHotTrendStocksOutput{Data: sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "get_hottrend_stocks",
+		Description: i18n.T("扫描全网热搜，将热点标题关联到相关A股股票与行业板块，用于发现舆情驱动的交易标的"),
+	}, handler)
+}
@@ -2,6 +2,12 @@ package tools
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/analysis"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/services"
 
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
@@ -10,8 +16,9 @@ import (
 // GetKLineInput K线数据输入参数
 type GetKLineInput struct {
 	Code   string `json:"code" jsonschema:"股票代码，如 sh600519"`
-	Period string `json:"period,omitempty" jsonschema:"K线周期: 1m(5分钟), 1d(日线), 1w(周线), 1mo(月线)，默认1d"`
-	Days   int    `json:"days,omitzero" jsonschema:"获取天数，默认30"`
+	Period string `json:"period,omitempty" jsonschema:"K线周期: 1m(当日1分钟分时), 5m/15m/30m/60m(分钟线，可跨多个交易日), 1d(日线), 1w(周线), 1mo(月线)，默认1d"`
+	Days   int    `json:"days,omitzero" jsonschema:"获取K线根数，默认30"`
+	Adjust string `json:"adjust,omitempty" jsonschema:"复权方式: none(不复权), qfq(前复权), hfq(后复权)，默认qfq；分时不复权"`
 }
 
 // GetKLineOutput K线数据输出
@@ -22,7 +29,7 @@ type GetKLineOutput struct {
 // createKLineTool 创建K线数据工具
 func (r *Registry) createKLineTool() (tool.Tool, error) {
 	handler := func(ctx tool.Context, input GetKLineInput) (GetKLineOutput, error) {
-		fmt.Printf("[Tool:get_kline_data] 调用开始, code=%s, period=%s, days=%d\n", input.Code, input.Period, input.Days)
+		fmt.Printf("[Tool:get_kline_data] 调用开始, code=%s, period=%s, days=%d, adjust=%s\n", input.Code, input.Period, input.Days, input.Adjust)
 
 		if input.Code == "" {
 			fmt.Println("[Tool:get_kline_data] 错误: 未提供股票代码")
@@ -38,22 +45,38 @@ func (r *Registry) createKLineTool() (tool.Tool, error) {
 			days = 30
 		}
 
-		klines, err := r.marketService.GetKLineData(input.Code, period, days)
+		// 默认前复权，避免除权缺口干扰支撑/压力位判断
+		adjust := input.Adjust
+		if adjust == "" {
+			adjust = services.AdjustQFQ
+		}
+		if period == "1m" {
+			adjust = services.AdjustNone
+		}
+
+		klines, err := r.marketService.GetKLineData(input.Code, period, days, adjust)
 		if err != nil {
 			fmt.Printf("[Tool:get_kline_data] 错误: %v\n", err)
 			return GetKLineOutput{}, err
 		}
 
 		// 格式化输出（只取最近10条避免过长）
-		var result string
+		var sb strings.Builder
 		start := 0
 		if len(klines) > 10 {
 			start = len(klines) - 10
 		}
 		for _, k := range klines[start:] {
-			result += fmt.Sprintf("%s: 开%.2f 高%.2f 低%.2f 收%.2f 量%d\n",
-				k.Time, k.Open, k.High, k.Low, k.Close, k.Volume)
+			fmt.Fprintf(&sb, "%s: 开%.2f 高%.2f 低%.2f 收%.2f 量%d", k.Time, k.Open, k.High, k.Low, k.Close, k.Volume)
+			if k.MA20 > 0 {
+				fmt.Fprintf(&sb, " MA5 %.2f MA10 %.2f MA20 %.2f", k.MA5, k.MA10, k.MA20)
+			}
+			sb.WriteString("\n")
 		}
+		if period != "1m" {
+			writeKLineAnalysis(&sb, klines)
+		}
+		result := sb.String()
 
 		fmt.Printf("[Tool:get_kline_data] 调用完成, 返回%d条数据\n", len(klines))
 		return GetKLineOutput{Data: result}, nil
@@ -61,6 +84,33 @@ func (r *Registry) createKLineTool() (tool.Tool, error) {
 
 	return functiontool.New(functiontool.Config{
 		Name:        "get_kline_data",
-		Description: "获取股票K线数据，支持5分钟线、日线、周线、月线",
+		Description: i18n.T("获取股票K线数据（含MA5/10/20、ATR与未回补缺口），支持分时、5/15/30/60分钟线、日线、周线、月线，可选前复权/后复权"),
 	}, handler)
 }
+
+// klineATRPeriod 工具输出的 ATR 周期
+const klineATRPeriod = 14
+
+// writeKLineAnalysis 追加 ATR 与未回补缺口，供判断波动与支撑压力位
+func writeKLineAnalysis(sb *strings.Builder, klines []models.KLineData) {
+	if len(klines) == 0 {
+		return
+	}
+	last := klines[len(klines)-1]
+	if atr := analysis.KLineATR(klines, klineATRPeriod)[len(klines)-1]; atr > 0 && last.Close > 0 {
+		fmt.Fprintf(sb, "ATR%d: %.2f（占收盘价%.2f%%）\n", klineATRPeriod, atr, atr/last.Close*100)
+	}
+	gaps := analysis.OpenGaps(klines)
+	if len(gaps) == 0 {
+		return
+	}
+	sb.WriteString("未回补缺口:")
+	for _, g := range gaps[max(len(gaps)-3, 0):] {
+		dir := "向下"
+		if g.Up {
+			dir = "向上"
+		}
+		fmt.Fprintf(sb, " %s%s跳空 %.2f~%.2f;", g.Time, dir, g.Lower, g.Upper)
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+var limitBoardLog = logger.New("tool:limitboard")
+
+// GetLimitBoardInput 涨跌停板输入参数
+type GetLimitBoardInput struct {
+	TradeDate string `json:"trade_date,omitzero" jsonschema:"交易日期，格式YYYY-MM-DD，为空则取当日"`
+	Pool      string `json:"pool,omitzero" jsonschema:"池类型: up(涨停), down(跌停), broken(炸板), all(全部)，默认all"`
+	Limit     int    `json:"limit,omitzero" jsonschema:"每个池最多返回条数，默认30，最大100"`
+}
+
+// GetLimitBoardOutput 涨跌停板输出
+type GetLimitBoardOutput struct {
+	Data string `json:"data" jsonschema:"涨跌停池数据"`
+}
+
+// createLimitBoardTool 创建涨跌停板工具
+func (r *Registry) createLimitBoardTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetLimitBoardInput) (GetLimitBoardOutput, error) {
+		limitBoardLog.Debug("调用开始, date=%s, pool=%s, limit=%d", input.TradeDate, input.Pool, input.Limit)
+
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 30
+		}
+		if limit > 100 {
+			limit = 100
+		}
+		pool := input.Pool
+		if pool == "" {
+			pool = "all"
+		}
+
+		board, err := r.limitBoardService.GetLimitBoard(input.TradeDate)
+		if err != nil {
+			limitBoardLog.Error("获取涨跌停池失败: %v", err)
+			return GetLimitBoardOutput{}, err
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "=== %s 涨跌停概况 ===\n", board.Date)
+		fmt.Fprintf(&sb, "涨停%d家 跌停%d家 炸板%d家", len(board.LimitUp), len(board.LimitDown), len(board.Broken))
+		if total := len(board.LimitUp) + len(board.Broken); total > 0 {
+			fmt.Fprintf(&sb, " 封板率%.1f%%", float64(len(board.LimitUp))*100/float64(total))
+		}
+		sb.WriteString("\n")
+		writeBoardLadder(&sb, board.LimitUp)
+
+		if pool == "all" || pool == "up" {
+			sb.WriteString("\n【涨停池】\n")
+			for i, item := range headLimitItems(board.LimitUp, limit) {
+				fmt.Fprintf(&sb, "%d. %s(%s) %d板 %.2f 首封%s 末封%s 炸板%d次 封单%.0f万 换手%.2f%% [%s]\n",
+					i+1, item.Name, item.Code, max(item.Boards, 1), item.Price, item.FirstSealTime, item.LastSealTime,
+					item.OpenTimes, item.SealFund/10000, item.TurnoverRate, item.Industry)
+			}
+		}
+		if pool == "all" || pool == "broken" {
+			sb.WriteString("\n【炸板池】\n")
+			for i, item := range headLimitItems(board.Broken, limit) {
+				fmt.Fprintf(&sb, "%d. %s(%s) %.2f 涨幅%.2f%% 首封%s 炸板%d次 换手%.2f%% [%s]\n",
+					i+1, item.Name, item.Code, item.Price, item.ChangePercent, item.FirstSealTime,
+					item.OpenTimes, item.TurnoverRate, item.Industry)
+			}
+		}
+		if pool == "all" || pool == "down" {
+			sb.WriteString("\n【跌停池】\n")
+			for i, item := range headLimitItems(board.LimitDown, limit) {
+				fmt.Fprintf(&sb, "%d. %s(%s) 连续跌停%d天 %.2f 封单%.0f万 开板%d次 [%s]\n",
+					i+1, item.Name, item.Code, max(item.Boards, 1), item.Price, item.SealFund/10000,
+					item.OpenTimes, item.Industry)
+			}
+		}
+
+		limitBoardLog.Debug("调用完成, 涨停%d 跌停%d 炸板%d", len(board.LimitUp), len(board.LimitDown), len(board.Broken))
+		return GetLimitBoardOutput{Data: sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "get_limit_board",
+		Description: i18n.T("获取A股涨停池、跌停池、炸板池及连板梯队，包括连板数、封板时间、炸板次数、封单资金，适合短线情绪分析"),
+	}, handler)
+}
+
+// writeBoardLadder 输出连板梯队，如「连板梯队: 5板1家 3板2家 2板6家 首板40家」
+func writeBoardLadder(sb *strings.Builder, items []models.LimitBoardItem) {
+	ladder := services.BoardLadder(items)
+	if len(ladder) == 0 {
+		return
+	}
+	levels := make([]int, 0, len(ladder))
+	for n := range ladder {
+		levels = append(levels, n)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(levels)))
+
+	sb.WriteString("连板梯队:")
+	for _, n := range levels {
+		if n == 1 {
+			fmt.Fprintf(sb, " 首板%d家", ladder[n])
+		} else {
+			fmt.Fprintf(sb, " %d板%d家", n, ladder[n])
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// headLimitItems 截取前 n 条
+func headLimitItems(items []models.LimitBoardItem, n int) []models.LimitBoardItem {
+	if len(items) > n {
+		return items[:n]
+	}
+	return items
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
 
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
@@ -48,16 +49,11 @@ func (r *Registry) createLongHuBangTool() (tool.Tool, error) {
 
 		var result string
 		for i, item := range listResult.Items {
-			// 格式化金额为万元
-			netBuyWan := item.NetBuyAmt / 10000
-			buyWan := item.BuyAmt / 10000
-			sellWan := item.SellAmt / 10000
-
 			result += fmt.Sprintf("%d. [%s] %s(%s) 收盘:%.2f 涨跌:%.2f%% 换手:%.2f%%\n",
 				i+1, item.TradeDate, item.Name, item.SecuCode,
 				item.ClosePrice, item.ChangePercent, item.TurnoverRate)
-			result += fmt.Sprintf("   净买:%.0f万 买入:%.0f万 卖出:%.0f万 占比:%.2f%%\n",
-				netBuyWan, buyWan, sellWan, item.DealRatio)
+			result += i18n.Sprintf("   净买:%s 买入:%s 卖出:%s 占比:%.2f%%\n",
+				i18n.Amount(item.NetBuyAmt), i18n.Amount(item.BuyAmt), i18n.Amount(item.SellAmt), item.DealRatio)
 			result += fmt.Sprintf("   原因:%s\n", item.Reason)
 			if item.D1Change != 0 {
 				result += fmt.Sprintf("   后续表现: 次日%.2f%% 5日%.2f%% 10日%.2f%%\n",
@@ -71,7 +67,7 @@ func (r *Registry) createLongHuBangTool() (tool.Tool, error) {
 
 	return functiontool.New(functiontool.Config{
 		Name:        "get_longhubang",
-		Description: "获取A股龙虎榜数据，包括上榜股票、净买入金额、买卖金额、上榜原因等信息，数据来源于东方财富",
+		Description: i18n.T("获取A股龙虎榜数据，包括上榜股票、净买入金额、买卖金额、上榜原因等信息，数据来源于东方财富"),
 	}, handler)
 }
 
@@ -135,6 +131,6 @@ func (r *Registry) createLongHuBangDetailTool() (tool.Tool, error) {
 
 	return functiontool.New(functiontool.Config{
 		Name:        "get_longhubang_detail",
-		Description: "获取个股龙虎榜营业部买卖明细，需要提供股票代码和交易日期",
+		Description: i18n.T("获取个股龙虎榜营业部买卖明细，需要提供股票代码和交易日期"),
 	}, handler)
 }
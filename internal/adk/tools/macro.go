@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetMacroDataInput 宏观数据输入参数
+type GetMacroDataInput struct {
+	Indicators []string `json:"indicators,omitempty" jsonschema:"指标分组: cpi, ppi, pmi, lpr, m2, social_financing(社融), gdp；为空返回全部"`
+	Periods    int      `json:"periods,omitzero" jsonschema:"每个指标返回最近多少期，默认6，最大24"`
+}
+
+// GetMacroDataOutput 宏观数据输出
+type GetMacroDataOutput struct {
+	Data string `json:"data" jsonschema:"宏观数据"`
+}
+
+// createMacroDataTool 创建宏观数据工具
+func (r *Registry) createMacroDataTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetMacroDataInput) (GetMacroDataOutput, error) {
+		fmt.Printf("[Tool:get_macro_data] 调用开始, indicators=%v, periods=%d\n", input.Indicators, input.Periods)
+
+		series, err := r.macroService.GetMacroData(input.Indicators, input.Periods)
+		if err != nil {
+			fmt.Printf("[Tool:get_macro_data] 错误: %v\n", err)
+			return GetMacroDataOutput{}, err
+		}
+
+		var sb strings.Builder
+		for _, s := range series {
+			if len(s.Points) == 0 {
+				fmt.Fprintf(&sb, "【%s】暂无数据\n\n", s.Name)
+				continue
+			}
+			latest := s.Points[0]
+			fmt.Fprintf(&sb, "【%s】最新(%s): %g%s", s.Name, latest.Period, latest.Value, s.Unit)
+			if len(s.Points) >= 2 {
+				fmt.Fprintf(&sb, "，前值 %g%s，变化 %+g，%s", s.Points[1].Value, s.Unit, s.Change, s.Trend)
+			}
+			sb.WriteString("\n  近期: ")
+			for i, p := range s.Points {
+				if i > 0 {
+					sb.WriteString(" | ")
+				}
+				fmt.Fprintf(&sb, "%s %g", p.Period, p.Value)
+			}
+			sb.WriteString("\n\n")
+		}
+
+		fmt.Printf("[Tool:get_macro_data] 调用完成, 返回%d个序列\n", len(series))
+		return GetMacroDataOutput{Data: sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "get_macro_data",
+		Description: i18n.T("获取中国关键宏观经济数据的最新值与近期走势，包括CPI、PPI、PMI、LPR、M1/M2、社融规模增量、GDP"),
+	}, handler)
+}
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// MarketDataToolName 合并行情类工具后的统一工具名
+const MarketDataToolName = "market_data"
+
+var marketDataLog = logger.New("tool:market_data")
+
+// marketDataAction market_data 的子命令，转发到对应的内置工具
+type marketDataAction struct {
+	Action string
+	Tool   string
+	Desc   string
+	Args   func(in MarketDataInput) map[string]any
+}
+
+// marketDataActions 可合并的行情类工具，按 action 枚举顺序排列
+var marketDataActions = []marketDataAction{
+	{"realtime", "get_stock_realtime", "实时行情与估值", func(in MarketDataInput) map[string]any {
+		return map[string]any{"codes": splitCodes(in.Code)}
+	}},
+	{"kline", "get_kline_data", "K线与均线", func(in MarketDataInput) map[string]any {
+		return map[string]any{"code": in.Code, "period": in.Period, "days": in.Limit}
+	}},
+	{"orderbook", "get_orderbook", "五档盘口", func(in MarketDataInput) map[string]any {
+		return map[string]any{"code": in.Code}
+	}},
+	{"tick", "get_tick_data", "逐笔成交", func(in MarketDataInput) map[string]any {
+		return map[string]any{"code": in.Code, "count": in.Limit}
+	}},
+	{"money_flow", "get_money_flow", "当日资金流向", func(in MarketDataInput) map[string]any {
+		return map[string]any{"code": in.Code}
+	}},
+	{"news", "get_news", "财经快讯", func(in MarketDataInput) map[string]any {
+		return map[string]any{"limit": in.Limit}
+	}},
+	{"stock_news", "get_stock_news", "个股新闻", func(in MarketDataInput) map[string]any {
+		return map[string]any{"code": in.Code, "limit": in.Limit}
+	}},
+	{"sentiment", "get_retail_sentiment", "散户情绪", func(in MarketDataInput) map[string]any {
+		return map[string]any{"code": in.Code}
+	}},
+	{"events", "get_events", "事件日历", func(in MarketDataInput) map[string]any {
+		return map[string]any{"code": in.Code, "days": in.Limit}
+	}},
+	{"research", "get_research_report", "研报列表", func(in MarketDataInput) map[string]any {
+		return map[string]any{"code": in.Code, "pageSize": in.Limit}
+	}},
+	{"breadth", "get_market_breadth", "涨跌家数与板块排行", func(in MarketDataInput) map[string]any {
+		return map[string]any{"top": in.Limit}
+	}},
+	{"limit_board", "get_limit_board", "涨跌停池", func(in MarketDataInput) map[string]any {
+		return map[string]any{"limit": in.Limit}
+	}},
+}
+
+// MarketDataInput 统一行情工具输入参数，各 action 只使用需要的字段
+type MarketDataInput struct {
+	Action string `json:"action" jsonschema:"要查询的数据类型"`
+	Code   string `json:"code,omitempty" jsonschema:"股票代码，如 sh600519；realtime 可用逗号分隔多个"`
+	Period string `json:"period,omitempty" jsonschema:"仅 kline: 1m/5m/15m/30m/60m/1d/1w/1mo，默认1d"`
+	Limit  int    `json:"limit,omitzero" jsonschema:"返回条数（kline 为K线根数，events 为未来天数），不填使用默认值"`
+}
+
+// runnableTool 可直接执行的函数工具
+type runnableTool interface {
+	Run(ctx tool.Context, args any) (map[string]any, error)
+}
+
+// MarketDataActionTool market_data 的 action 对应的内置工具名，未知时返回空
+func MarketDataActionTool(action any) string {
+	name, _ := action.(string)
+	for _, a := range marketDataActions {
+		if a.Action == name {
+			return a.Tool
+		}
+	}
+	return ""
+}
+
+// unifyNames 从工具名列表中拆出可合并的行情工具，少于两个时不合并
+// 调用方需持有读锁
+func (r *Registry) unifyNames(names []string) (actions []marketDataAction, rest []string) {
+	for _, a := range marketDataActions {
+		if slices.Contains(names, a.Tool) && r.tools[a.Tool] != nil && !r.disabled[a.Tool] {
+			actions = append(actions, a)
+		}
+	}
+	if len(actions) < 2 {
+		return nil, names
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(actions, func(a marketDataAction) bool { return a.Tool == name }) {
+			rest = append(rest, name)
+		}
+	}
+	return actions, rest
+}
+
+// GetUnifiedTools 与 GetTools 相同，但将其中的行情类工具合并为一个 market_data 工具
+func (r *Registry) GetUnifiedTools(names []string) []tool.Tool {
+	r.mu.RLock()
+	actions, rest := r.unifyNames(names)
+	r.mu.RUnlock()
+	result := r.GetTools(rest)
+	if len(actions) == 0 {
+		return result
+	}
+	t, err := r.newMarketDataTool(actions)
+	if err != nil {
+		marketDataLog.Warn("创建 %s 工具失败，改用独立工具: %v", MarketDataToolName, err)
+		return r.GetTools(names)
+	}
+	return append([]tool.Tool{t}, result...)
+}
+
+// GetUnifiedToolInfos 与 GetToolInfosByNames 相同，合并后的行情工具以 market_data 一项列出
+func (r *Registry) GetUnifiedToolInfos(names []string) []ToolInfo {
+	r.mu.RLock()
+	actions, rest := r.unifyNames(names)
+	r.mu.RUnlock()
+	infos := r.GetToolInfosByNames(rest)
+	if len(actions) == 0 {
+		return infos
+	}
+	info := ToolInfo{Name: MarketDataToolName, Description: marketDataDescription(actions), Enabled: true}
+	return append([]ToolInfo{info}, infos...)
+}
+
+// marketDataDescription 列出各 action 的用途
+func marketDataDescription(actions []marketDataAction) string {
+	parts := make([]string, len(actions))
+	for i, a := range actions {
+		parts[i] = a.Action + "=" + i18n.T(a.Desc)
+	}
+	return i18n.T("统一行情数据查询，通过 action 选择数据类型：") + strings.Join(parts, "，")
+}
+
+// newMarketDataTool 创建只包含指定 action 的统一行情工具
+func (r *Registry) newMarketDataTool(actions []marketDataAction) (tool.Tool, error) {
+	schema, err := jsonschema.For[MarketDataInput](nil)
+	if err != nil {
+		return nil, err
+	}
+	enum := make([]any, len(actions))
+	for i, a := range actions {
+		enum[i] = a.Action
+	}
+	schema.Properties["action"].Enum = enum
+
+	handler := func(ctx tool.Context, input MarketDataInput) (map[string]any, error) {
+		fmt.Printf("[Tool:%s] 调用开始, action=%s, code=%s\n", MarketDataToolName, input.Action, input.Code)
+
+		i := slices.IndexFunc(actions, func(a marketDataAction) bool { return a.Action == input.Action })
+		if i < 0 {
+			return map[string]any{"data": i18n.Sprintf("不支持的 action: %s", input.Action)}, nil
+		}
+		t, ok := r.GetTool(actions[i].Tool)
+		if !ok {
+			return map[string]any{"data": i18n.Sprintf("数据工具 %s 已禁用", actions[i].Tool)}, nil
+		}
+		rt, ok := t.(runnableTool)
+		if !ok {
+			return nil, fmt.Errorf("工具 %s 不可直接执行", actions[i].Tool)
+		}
+		return rt.Run(ctx, actions[i].Args(input))
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        MarketDataToolName,
+		Description: marketDataDescription(actions),
+		InputSchema: schema,
+	}, handler)
+}
+
+// splitCodes 拆分逗号分隔的多个股票代码
+func splitCodes(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '，' || r == ' ' })
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"testing"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// echoTool 返回收到的参数，用于验证 market_data 的转发
+func echoTool(t *testing.T, name string) tool.Tool {
+	t.Helper()
+	tl, err := functiontool.New(functiontool.Config{Name: name, Description: name}, func(ctx tool.Context, args map[string]any) (map[string]any, error) {
+		return map[string]any{"tool": name, "args": args}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tl
+}
+
+func TestGetUnifiedTools(t *testing.T) {
+	r := &Registry{tools: map[string]tool.Tool{}, toolInfos: map[string]ToolInfo{}, disabled: map[string]bool{}}
+	for _, name := range []string{"get_stock_realtime", "get_kline_data", "get_orderbook", "search_stocks"} {
+		r.tools[name] = echoTool(t, name)
+		r.toolInfos[name] = ToolInfo{Name: name, Description: name}
+	}
+	r.disabled["get_orderbook"] = true
+
+	names := []string{"get_stock_realtime", "get_kline_data", "get_orderbook", "search_stocks"}
+	got := r.GetUnifiedTools(names)
+	if len(got) != 2 || got[0].Name() != MarketDataToolName || got[1].Name() != "search_stocks" {
+		t.Fatalf("tools = %v", toolNames(got))
+	}
+	if infos := r.GetUnifiedToolInfos(names); len(infos) != 2 || infos[0].Name != MarketDataToolName {
+		t.Errorf("infos = %v", infos)
+	}
+
+	md := got[0].(interface {
+		Run(ctx tool.Context, args any) (map[string]any, error)
+	})
+	result, err := md.Run(nil, map[string]any{"action": "kline", "code": "sh600519", "period": "1w", "limit": 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, _ := result["args"].(map[string]any)
+	if result["tool"] != "get_kline_data" || args["code"] != "sh600519" || args["period"] != "1w" || args["days"] != float64(20) {
+		t.Errorf("kline result = %v", result)
+	}
+	result, err = md.Run(nil, map[string]any{"action": "realtime", "code": "sh600519,sz000001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args, _ := result["args"].(map[string]any); result["tool"] != "get_stock_realtime" || len(args["codes"].([]any)) != 2 {
+		t.Errorf("realtime result = %v", result)
+	}
+	// 已禁用的工具不在 action 枚举中
+	if _, err := md.Run(nil, map[string]any{"action": "orderbook", "code": "sh600519"}); err == nil {
+		t.Error("disabled action accepted")
+	}
+
+	// 可合并的工具少于两个时不合并
+	if got := r.GetUnifiedTools([]string{"get_kline_data", "search_stocks"}); len(got) != 2 || got[0].Name() != "get_kline_data" {
+		t.Errorf("single data tool merged: %v", toolNames(got))
+	}
+}
+
+func toolNames(ts []tool.Tool) []string {
+	names := make([]string, len(ts))
+	for i, t := range ts {
+		names[i] = t.Name()
+	}
+	return names
+}
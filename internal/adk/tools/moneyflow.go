@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/analysis"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// moneyFlowBucketMinutes 资金流向分段汇总的区间长度（分钟）
+const moneyFlowBucketMinutes = 30
+
+// GetMoneyFlowInput 资金流向输入参数
+type GetMoneyFlowInput struct {
+	Code string `json:"code" jsonschema:"股票代码，如 sh600519"`
+}
+
+// GetMoneyFlowOutput 资金流向输出
+type GetMoneyFlowOutput struct {
+	Data string `json:"data" jsonschema:"当日分时均价、资金流入流出及分段净流入"`
+}
+
+// createMoneyFlowTool 创建资金流向工具
+func (r *Registry) createMoneyFlowTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetMoneyFlowInput) (GetMoneyFlowOutput, error) {
+		fmt.Printf("[Tool:get_money_flow] 调用开始, code=%s\n", input.Code)
+
+		if input.Code == "" {
+			fmt.Println("[Tool:get_money_flow] 错误: 未提供股票代码")
+			return GetMoneyFlowOutput{Data: "请提供股票代码"}, nil
+		}
+
+		points, err := r.marketService.GetMoneyFlow(input.Code)
+		if err != nil {
+			fmt.Printf("[Tool:get_money_flow] 错误: %v\n", err)
+			return GetMoneyFlowOutput{}, err
+		}
+		if len(points) == 0 {
+			return GetMoneyFlowOutput{Data: "暂无分时数据（可能为非交易日）"}, nil
+		}
+
+		var inflow, outflow float64
+		for _, p := range points {
+			inflow += p.Inflow
+			outflow += p.Outflow
+		}
+		last := points[len(points)-1]
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "【资金流向】%s %s（按每分钟涨跌划分流入流出，金额单位：万元）\n", input.Code, last.Time)
+		fmt.Fprintf(&sb, "分时均价: %.2f\n", last.VWAP)
+		fmt.Fprintf(&sb, "全天: 流入 %.0f，流出 %.0f，净流入 %+.0f\n", inflow/1e4, outflow/1e4, last.CumNet/1e4)
+		sb.WriteString("近期净流入:")
+		for _, window := range []int{5, 15, 30} {
+			rolling := analysis.RollingFlow(points, window)
+			fmt.Fprintf(&sb, " 近%d分钟 %+.0f;", window, rolling[len(rolling)-1].Net/1e4)
+		}
+		fmt.Fprintf(&sb, "\n每%d分钟净流入:\n", moneyFlowBucketMinutes)
+		for _, b := range analysis.FlowBuckets(points, moneyFlowBucketMinutes) {
+			fmt.Fprintf(&sb, "%s 净流入 %+.0f，累计 %+.0f，均价 %.2f\n", b.Time[11:16], b.Net/1e4, b.CumNet/1e4, b.VWAP)
+		}
+
+		fmt.Printf("[Tool:get_money_flow] 调用完成, 分钟数=%d\n", len(points))
+		return GetMoneyFlowOutput{Data: sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "get_money_flow",
+		Description: i18n.T("获取股票当日分时资金流向：分时均价(VWAP)、流入流出金额、近5/15/30分钟净流入及每30分钟分段净流入"),
+	}, handler)
+}
@@ -3,6 +3,8 @@ package tools
 import (
 	"fmt"
 
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
 )
@@ -48,6 +50,6 @@ func (r *Registry) createNewsTool() (tool.Tool, error) {
 
 	return functiontool.New(functiontool.Config{
 		Name:        "get_news",
-		Description: "获取最新财经快讯，来源于财联社",
+		Description: i18n.T("获取最新财经快讯，来源于财联社"),
 	}, handler)
 }
@@ -2,6 +2,11 @@ package tools
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
 
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
@@ -14,7 +19,7 @@ type GetOrderBookInput struct {
 
 // GetOrderBookOutput 盘口数据输出
 type GetOrderBookOutput struct {
-	Data string `json:"data" jsonschema:"五档盘口数据"`
+	Data string `json:"data" jsonschema:"五档盘口数据及委比、大单、疑似撤单"`
 }
 
 // createOrderBookTool 创建盘口数据工具
@@ -27,22 +32,28 @@ func (r *Registry) createOrderBookTool() (tool.Tool, error) {
 			return GetOrderBookOutput{Data: "请提供股票代码"}, nil
 		}
 
-		ob, err := r.marketService.GetRealOrderBook(input.Code)
+		ob, err := r.marketService.GetOrderBookWithMetrics(input.Code)
 		if err != nil {
 			fmt.Printf("[Tool:get_orderbook] 错误: %v\n", err)
 			return GetOrderBookOutput{}, err
 		}
 
-		// 格式化输出
+		var m models.OrderBookMetrics
+		if ob.Metrics != nil {
+			m = *ob.Metrics
+		}
+
+		// 格式化输出，大单档位附加标记
 		result := "【卖盘】\n"
 		for i := len(ob.Asks) - 1; i >= 0; i-- {
 			a := ob.Asks[i]
-			result += fmt.Sprintf("卖%d: %.2f x %d手\n", i+1, a.Price, a.Size)
+			result += fmt.Sprintf("卖%d: %.2f x %d手%s\n", i+1, a.Price, a.Size, largeMark(m.LargeAsks, i))
 		}
 		result += "【买盘】\n"
 		for i, b := range ob.Bids {
-			result += fmt.Sprintf("买%d: %.2f x %d手\n", i+1, b.Price, b.Size)
+			result += fmt.Sprintf("买%d: %.2f x %d手%s\n", i+1, b.Price, b.Size, largeMark(m.LargeBids, i))
 		}
+		result += formatOrderBookMetrics(m)
 
 		fmt.Printf("[Tool:get_orderbook] 调用完成, 买盘%d档, 卖盘%d档\n", len(ob.Bids), len(ob.Asks))
 		return GetOrderBookOutput{Data: result}, nil
@@ -50,6 +61,36 @@ func (r *Registry) createOrderBookTool() (tool.Tool, error) {
 
 	return functiontool.New(functiontool.Config{
 		Name:        "get_orderbook",
-		Description: "获取股票五档盘口数据，显示买卖五档的价格和挂单量",
+		Description: i18n.T("获取股票五档盘口数据，显示买卖五档的价格和挂单量，并给出委比、大单与疑似撤单"),
 	}, handler)
 }
+
+// largeMark 大单档位标记
+func largeMark(large []int, i int) string {
+	if slices.Contains(large, i) {
+		return " [大单]"
+	}
+	return ""
+}
+
+// formatOrderBookMetrics 格式化盘口指标
+func formatOrderBookMetrics(m models.OrderBookMetrics) string {
+	if m.BidVolume+m.AskVolume == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("【盘口指标】\n")
+	fmt.Fprintf(&sb, "委比: %+.2f%%（买盘合计%d手 / 卖盘合计%d手）\n", m.Imbalance*100, m.BidVolume, m.AskVolume)
+	if len(m.Withdrawn) > 0 {
+		sb.WriteString("疑似撤单:")
+		for _, w := range m.Withdrawn {
+			side := "买"
+			if w.Side == "ask" {
+				side = "卖"
+			}
+			fmt.Fprintf(&sb, " %s盘%.2f撤%d手;", side, w.Price, w.Size)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
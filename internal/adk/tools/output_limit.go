@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/pkg/tokens"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// DefaultToolOutputLimit 未单独设置上限的工具结果上限（估算 token）
+const DefaultToolOutputLimit = 4000
+
+// CondensedKey 工具结果被压缩时注入的标记字段，提示专家数据不完整
+const CondensedKey = "condensed"
+
+var outputLimitLog = logger.New("tool:output")
+
+// summarizeTimeout 单次摘要工具结果的超时
+const summarizeTimeout = 30 * time.Second
+
+// defaultOutputLimits 结果通常很大的工具的默认上限
+var defaultOutputLimits = map[string]int{
+	"get_report_content":    3000, // 研报全文
+	"get_hottrend":          2000, // 多平台热搜榜单
+	"get_hottrend_stocks":   2000,
+	"get_stock_news":        2000,
+	"get_news":              1500,
+	"get_tick_data":         1500,
+	"get_longhubang":        2000,
+	"get_longhubang_detail": 1500,
+	"get_limit_board":       2000,
+}
+
+// SetOutputLimits 设置工具结果上限：ContextBudget.ToolOutput 替换通用默认值，ToolOutputLimits 按工具覆盖
+func (r *Registry) SetOutputLimits(cfg models.ContextBudgetConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outputDefault = cfg.ToolOutput
+	r.outputLimits = make(map[string]int, len(cfg.ToolOutputLimits))
+	for name, limit := range cfg.ToolOutputLimits {
+		r.outputLimits[name] = limit
+	}
+	r.summarize = cfg.ToolSummarize
+}
+
+// SetSummaryLLMProvider 设置摘要工具结果的 LLM 获取方式（按需创建，跟随 AI 配置变化）
+func (r *Registry) SetSummaryLLMProvider(provider func(ctx context.Context) (model.LLM, error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.summaryLLM = provider
+}
+
+// OutputLimit 工具结果上限，0 表示不限制
+func (r *Registry) OutputLimit(name string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if limit, ok := r.outputLimits[name]; ok && limit != 0 {
+		return max(limit, 0)
+	}
+	if r.outputDefault > 0 {
+		return r.outputDefault
+	}
+	if limit, ok := defaultOutputLimits[name]; ok {
+		return limit
+	}
+	return DefaultToolOutputLimit
+}
+
+// OutputLimitCallback 工具结果超出上限时压缩字符串字段并注入 condensed 标记
+// 开启摘要时用提取模型提炼，失败或未开启时截断；counter 为专家模型的 token 计数口径
+func (r *Registry) OutputLimitCallback(counter tokens.Counter) llmagent.AfterToolCallback {
+	return func(ctx tool.Context, t tool.Tool, args, result map[string]any, err error) (map[string]any, error) {
+		if err != nil || result == nil {
+			return nil, nil
+		}
+		name := t.Name()
+		if name == MarketDataToolName {
+			name = MarketDataActionTool(args["action"]) // 统一行情工具按实际转发的工具取上限
+		}
+		limit := r.OutputLimit(name)
+		if limit <= 0 {
+			return nil, nil
+		}
+
+		sizes := make(map[string]int)
+		total := 0
+		for k, v := range result {
+			if s, ok := v.(string); ok {
+				sizes[k] = counter.Count(s)
+				total += sizes[k]
+			}
+		}
+		if total <= limit {
+			return nil, nil
+		}
+
+		// 从短到长分配上限：短字段保持原样，剩余额度由长字段平分
+		keys := make([]string, 0, len(sizes))
+		for k := range sizes {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return sizes[keys[i]] < sizes[keys[j]] })
+
+		summarized := false
+		remaining := limit
+		for i, k := range keys {
+			share := max(remaining/(len(keys)-i), 1)
+			if sizes[k] <= share {
+				remaining -= sizes[k]
+				continue
+			}
+			remaining -= share
+			text := result[k].(string)
+			if summary, ok := r.summarizeOutput(ctx, name, text, share, counter); ok {
+				result[k] = summary
+				summarized = true
+				continue
+			}
+			result[k] = counter.Truncate(text, share)
+		}
+
+		if summarized {
+			result[CondensedKey] = i18n.Sprintf("结果约 %d tokens，超出上限 %d，已由 AI 摘要，细节可能缺失，需要时请缩小查询范围", total, limit)
+		} else {
+			result[CondensedKey] = i18n.Sprintf("结果约 %d tokens，超出上限 %d，已截断，需要时请缩小查询范围", total, limit)
+		}
+		return result, nil
+	}
+}
+
+// summarizeOutput 用摘要模型将工具结果压缩到约 maxTokens，未开启、失败或结果仍超长时返回 false
+func (r *Registry) summarizeOutput(ctx context.Context, toolName, text string, maxTokens int, counter tokens.Counter) (string, bool) {
+	r.mu.RLock()
+	enabled, provider := r.summarize, r.summaryLLM
+	r.mu.RUnlock()
+	if !enabled || provider == nil {
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, summarizeTimeout)
+	defer cancel()
+	llm, err := provider(ctx)
+	if err != nil {
+		outputLimitLog.Warn("工具 %s 结果摘要模型创建失败: %v", toolName, err)
+		return "", false
+	}
+
+	prompt := i18n.Sprintf("以下是数据工具 %s 返回的结果，请压缩到 %d 字以内：保留关键数字、日期、代码、名称与结论，删除重复和无关内容，直接输出压缩结果。\n\n", toolName, maxTokens) + text
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: prompt}}}},
+	}
+	var sb strings.Builder
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			outputLimitLog.Warn("工具 %s 结果摘要失败，改为截断: %v", toolName, err)
+			return "", false
+		}
+		if resp == nil || resp.Content == nil {
+			continue
+		}
+		for _, part := range resp.Content.Parts {
+			if !part.Thought && part.Text != "" {
+				sb.WriteString(part.Text)
+			}
+		}
+	}
+	summary := strings.TrimSpace(sb.String())
+	if summary == "" {
+		return "", false
+	}
+	return counter.Truncate(summary, maxTokens), true
+}
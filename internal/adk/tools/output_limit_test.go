@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/tokens"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func newNamedTool(t *testing.T, name string) tool.Tool {
+	t.Helper()
+	tl, err := functiontool.New(functiontool.Config{Name: name, Description: name}, func(ctx tool.Context, in struct{}) (map[string]any, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tl
+}
+
+func TestOutputLimitCallback(t *testing.T) {
+	r := &Registry{}
+	counter := tokens.Counter{Encoding: tokens.EncodingCJK}
+	cb := r.OutputLimitCallback(counter)
+	report := newNamedTool(t, "get_report_content")
+	content := strings.Repeat("贵州茅台2024年营收1505亿元，同比增长15.7%。", 500)
+
+	// 超出内置上限时截断并注入标记
+	result, err := cb(nil, report, nil, map[string]any{"title": "年报点评", "content": content}, nil)
+	if err != nil || result == nil {
+		t.Fatalf("result = %v, err = %v", result, err)
+	}
+	if got := counter.Count(result["content"].(string)); got > defaultOutputLimits["get_report_content"] {
+		t.Errorf("content = %d tokens, want <= %d", got, defaultOutputLimits["get_report_content"])
+	}
+	if result["title"] != "年报点评" {
+		t.Errorf("short field changed: %v", result["title"])
+	}
+	if _, ok := result[CondensedKey]; !ok {
+		t.Error("missing condensed marker")
+	}
+
+	// 未超出上限时不修改
+	if result, _ := cb(nil, report, nil, map[string]any{"content": "茅台"}, nil); result != nil {
+		t.Errorf("small result changed: %v", result)
+	}
+
+	// 按工具设置 -1 不限制
+	r.SetOutputLimits(models.ContextBudgetConfig{ToolOutputLimits: map[string]int{"get_report_content": -1}})
+	if result, _ := cb(nil, report, nil, map[string]any{"content": content}, nil); result != nil {
+		t.Error("unlimited tool was condensed")
+	}
+	if got := r.OutputLimit("get_news"); got != defaultOutputLimits["get_news"] {
+		t.Errorf("get_news limit = %d, want %d", got, defaultOutputLimits["get_news"])
+	}
+
+	// 通用上限替换内置默认值
+	r.SetOutputLimits(models.ContextBudgetConfig{ToolOutput: 500})
+	if got := r.OutputLimit("get_report_content"); got != 500 {
+		t.Errorf("limit = %d, want 500", got)
+	}
+}
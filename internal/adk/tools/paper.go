@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// PaperOrderInput 模拟盘下单输入参数
+type PaperOrderInput struct {
+	Code   string  `json:"code" jsonschema:"股票代码，如 sh600519、sz000001"`
+	Side   string  `json:"side" jsonschema:"方向: buy(买入) 或 sell(卖出)"`
+	Type   string  `json:"type,omitempty" jsonschema:"类型: market(市价，默认) 或 limit(限价)"`
+	Price  float64 `json:"price,omitzero" jsonschema:"限价单价格，市价单不填"`
+	Shares int64   `json:"shares" jsonschema:"委托数量（股），买入须为100的整数倍"`
+	Reason string  `json:"reason" jsonschema:"下单理由，展示给用户确认"`
+}
+
+// PaperOrderOutput 模拟盘下单输出
+type PaperOrderOutput struct {
+	Data string `json:"data" jsonschema:"委托结果"`
+}
+
+// createPaperOrderTool 创建模拟盘下单工具（委托需用户确认）
+func (r *Registry) createPaperOrderTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input PaperOrderInput) (PaperOrderOutput, error) {
+		fmt.Printf("[Tool:place_paper_order] 调用开始, code=%s, side=%s, type=%s, shares=%d\n", input.Code, input.Side, input.Type, input.Shares)
+
+		orderType := input.Type
+		if orderType == "" {
+			orderType = services.PaperOrderMarket
+		}
+		order, err := r.paperTradingService.PlaceOrder(services.PaperOrderRequest{
+			Symbol: input.Code,
+			Side:   input.Side,
+			Type:   orderType,
+			Price:  input.Price,
+			Shares: input.Shares,
+			Reason: input.Reason,
+		}, ctx.AgentName(), true)
+		if err != nil {
+			fmt.Printf("[Tool:place_paper_order] 错误: %v\n", err)
+			return PaperOrderOutput{}, err
+		}
+
+		side := "买入"
+		if order.Side == services.PaperSideSell {
+			side = "卖出"
+		}
+		price := "市价"
+		if order.Type == services.PaperOrderLimit {
+			price = fmt.Sprintf("限价 %.2f", order.Price)
+		}
+		fmt.Printf("[Tool:place_paper_order] 调用完成, 委托 %s 待确认\n", order.ID)
+		return PaperOrderOutput{Data: fmt.Sprintf("已提交模拟盘委托 #%s：%s %s(%s) %d股，%s。委托需用户确认后才会报单，当前状态：等待确认。",
+			order.ID, side, order.Name, order.Symbol, order.Shares, price)}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "place_paper_order",
+		Description: i18n.T("在模拟盘提交买卖委托（市价/限价），用于按分析结论进行模拟交易验证；委托不会立即执行，需用户确认后才报单撮合，不涉及真实资金"),
+	}, handler)
+}
@@ -0,0 +1,391 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/pkg/paths"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	pythonToolName       = "run_python"
+	pythonDefaultTimeout = 30 * time.Second
+	pythonWaitDelay      = 2 * time.Second // 超时后等待输出管道关闭的时间
+	pythonMaxOutput      = 8000            // 输出最大字节数
+	pythonMaxCodes       = 5
+	pythonPlotMaxAge     = 7 * 24 * time.Hour // 图表缓存保留时长
+	pythonPlotMaxFiles   = 200                // 图表缓存最多保留张数
+)
+
+// pythonRunner 入口脚本：使用无界面绘图后端，安装限制后再执行用户脚本
+// 通过 exec 执行 script.py，使报错行号与用户脚本一致
+// 限制分两层：
+//   - 审计钩子（sys.addaudithook，不可移除）：禁止联网、启动或结束进程，
+//     禁止写入工作目录以外的文件，读取仅限工作目录、Python 安装目录与系统字体/时区目录
+//   - 资源限制（仅类 Unix）：限制地址空间与单个文件大小
+//
+// 审计钩子无法约束原生扩展（如 ctypes 直接调用 libc），因此超时后仍按进程组结束所有子进程
+const pythonRunner = `import os, sys
+
+try:
+    import matplotlib
+    matplotlib.use("Agg")
+except ImportError:
+    pass
+
+
+def _jcp_restrict():
+    try:
+        import resource
+    except ImportError:
+        resource = None
+    if resource is not None:
+        for lim, val in ((resource.RLIMIT_AS, 4 << 30), (resource.RLIMIT_FSIZE, 64 << 20)):
+            try:
+                soft, hard = resource.getrlimit(lim)
+                if hard != resource.RLIM_INFINITY:
+                    val = min(val, hard)
+                resource.setrlimit(lim, (val, hard))
+            except (ValueError, OSError):
+                pass
+
+    norm = lambda p: os.path.normcase(os.path.realpath(p))
+    work = norm(os.getcwd())
+    readable = {work}
+    for p in [sys.prefix, sys.base_prefix, sys.exec_prefix, sys.base_exec_prefix, *sys.path,
+              "/usr/share/fonts", "/usr/local/share/fonts", "/usr/share/zoneinfo",
+              "/System/Library/Fonts", "/Library/Fonts",
+              os.path.join(os.environ.get("SYSTEMROOT", "C:\\Windows"), "Fonts")]:
+        if p:
+            readable.add(norm(p))
+    devnull = norm(os.devnull)
+
+    def inside(path, roots):
+        if isinstance(path, int):
+            return True
+        path = norm(os.fsdecode(path))
+        return path == devnull or any(path == r or path.startswith(r.rstrip(os.sep) + os.sep) for r in roots)
+
+    blocked = ("subprocess.Popen", "os.system", "os.exec", "os.posix_spawn", "os.spawn",
+               "os.fork", "os.forkpty", "os.startfile", "os.kill", "os.killpg",
+               "socket.connect", "socket.bind", "socket.sendto", "socket.sendmsg",
+               "socket.getaddrinfo", "socket.gethostbyname", "socket.gethostbyaddr")
+    writes = ("os.remove", "os.rename", "os.rmdir", "os.mkdir", "os.chmod", "os.chown",
+              "os.truncate", "os.link", "os.symlink", "os.utime", "shutil.rmtree",
+              "shutil.copyfile", "shutil.copymode", "shutil.copystat", "shutil.move")
+    write_flags = os.O_WRONLY | os.O_RDWR | os.O_CREAT | os.O_APPEND | os.O_TRUNC
+
+    def hook(event, args):
+        if event in blocked:
+            raise PermissionError("run_python 不允许 %s" % event)
+        if event == "open":
+            path, mode, flags = args
+            if path is None:
+                return
+            write = (isinstance(mode, str) and any(c in mode for c in "wax+")) or bool((flags or 0) & write_flags)
+            if not inside(path, [work] if write else readable):
+                raise PermissionError("run_python 不允许访问工作目录以外的文件: %s" % os.fsdecode(path))
+        elif event in writes:
+            for arg in args:
+                if isinstance(arg, (str, bytes, os.PathLike)) and not inside(arg, [work]):
+                    raise PermissionError("run_python 不允许修改工作目录以外的文件: %s" % os.fsdecode(arg))
+
+    sys.addaudithook(hook)
+
+
+_jcp_restrict()
+del _jcp_restrict
+
+with open("script.py", encoding="utf-8") as _f:
+    _code = compile(_f.read(), "script.py", "exec")
+exec(_code, {"__name__": "__main__"})
+`
+
+var pythonLog = logger.New("tool:python")
+
+// RunPythonInput Python 工具输入参数
+type RunPythonInput struct {
+	Script string   `json:"script" jsonschema:"Python 代码，用 print 输出结果；绘图请 plt.savefig('xxx.png')"`
+	Codes  []string `json:"codes,omitempty" jsonschema:"需要挂载K线数据的股票代码（最多5只），在工作目录生成 <代码>.csv，列为 time,open,high,low,close,volume,amount"`
+	Period string   `json:"period,omitempty" jsonschema:"K线周期: 5m/15m/30m/60m, 1d, 1w, 1mo，默认1d"`
+	Bars   int      `json:"bars,omitzero" jsonschema:"K线根数，默认120，最大500"`
+}
+
+// RunPythonOutput Python 工具输出
+type RunPythonOutput struct {
+	Data string `json:"data" jsonschema:"标准输出与生成的图片路径"`
+}
+
+// SetPythonTool 按配置启用或移除 run_python 工具（默认关闭）
+// 脚本由模型编写，可能受新闻、股吧等内容中的提示词注入影响，启用即意味着在本机执行不受信任的代码
+func (r *Registry) SetPythonTool(cfg models.PythonToolConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.tools, pythonToolName)
+	delete(r.toolInfos, pythonToolName)
+	if !cfg.Enabled {
+		return
+	}
+	t, err := r.createPythonTool(cfg)
+	if err != nil {
+		fmt.Printf("[Tool:%s] 创建失败: %v\n", pythonToolName, err)
+		return
+	}
+	pythonLog.Warn("已启用 %s：模型编写的脚本将以当前用户身份执行，审计钩子可拦截联网、子进程与工作目录外的文件访问，但无法约束原生扩展，不等同于系统级沙箱", pythonToolName)
+	r.tools[pythonToolName] = t
+	r.toolInfos[pythonToolName] = ToolInfo{Name: pythonToolName, Description: "在受限的本机 Python 子进程中执行代码片段（禁止联网、子进程与工作目录外的文件访问），可挂载个股K线CSV，返回输出与图表，用于统计分析与量化计算"}
+}
+
+// createPythonTool 创建 Python 执行工具
+func (r *Registry) createPythonTool(cfg models.PythonToolConfig) (tool.Tool, error) {
+	handler := func(ctx tool.Context, input RunPythonInput) (RunPythonOutput, error) {
+		fmt.Printf("[Tool:%s] 调用开始, codes=%v, period=%s, bars=%d\n", pythonToolName, input.Codes, input.Period, input.Bars)
+
+		if strings.TrimSpace(input.Script) == "" {
+			return RunPythonOutput{Data: "请提供 Python 代码"}, nil
+		}
+		if len(input.Codes) > pythonMaxCodes {
+			input.Codes = input.Codes[:pythonMaxCodes]
+		}
+
+		files, err := r.klineCSVFiles(input)
+		if err != nil {
+			fmt.Printf("[Tool:%s] 错误: %v\n", pythonToolName, err)
+			return RunPythonOutput{}, err
+		}
+
+		output, plots, err := runPython(ctx, cfg, input.Script, files)
+		if err != nil {
+			fmt.Printf("[Tool:%s] 错误: %v\n", pythonToolName, err)
+			// 脚本错误返回给模型以便修正
+			return RunPythonOutput{Data: fmt.Sprintf("执行失败: %v\n%s", err, output)}, nil
+		}
+
+		var sb strings.Builder
+		if len(files) > 0 {
+			names := make([]string, 0, len(files))
+			for name := range files {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			fmt.Fprintf(&sb, "已挂载数据: %s\n", strings.Join(names, ", "))
+		}
+		sb.WriteString(output)
+		for _, p := range plots {
+			fmt.Fprintf(&sb, "\n[图表] %s", p)
+		}
+
+		fmt.Printf("[Tool:%s] 调用完成, 输出%d字节, 图表%d张\n", pythonToolName, len(output), len(plots))
+		return RunPythonOutput{Data: sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        pythonToolName,
+		Description: i18n.T("在受限的本机 Python 子进程中执行代码片段，可挂载个股K线CSV，返回输出与图表，用于统计分析与量化计算。脚本只能读写工作目录中的文件，不能联网或启动子进程，单次执行有超时限制"),
+	}, handler)
+}
+
+// klineCSVFiles 拉取K线并生成 CSV 文件内容
+func (r *Registry) klineCSVFiles(input RunPythonInput) (map[string][]byte, error) {
+	period := input.Period
+	if period == "" {
+		period = "1d"
+	}
+	bars := input.Bars
+	if bars <= 0 {
+		bars = 120
+	}
+	bars = min(bars, calcMaxBars)
+
+	files := make(map[string][]byte)
+	for _, code := range input.Codes {
+		code = strings.TrimSpace(code)
+		if code == "" || strings.ContainsAny(code, `/\.`) {
+			continue
+		}
+		klines, err := r.marketService.GetKLineData(code, period, bars, services.AdjustQFQ)
+		if err != nil {
+			return nil, fmt.Errorf("获取 %s K线失败: %w", code, err)
+		}
+		var buf bytes.Buffer
+		buf.WriteString("time,open,high,low,close,volume,amount\n")
+		for _, k := range klines {
+			fmt.Fprintf(&buf, "%s,%g,%g,%g,%g,%d,%g\n", k.Time, k.Open, k.High, k.Low, k.Close, k.Volume, k.Amount)
+		}
+		files[code+".csv"] = buf.Bytes()
+	}
+	return files, nil
+}
+
+// runPython 在临时目录中执行脚本，返回输出与生成的图片路径
+// 超时时结束整个进程组，脚本启动的子进程持有输出管道也不会阻塞返回
+func runPython(ctx context.Context, cfg models.PythonToolConfig, script string, files map[string][]byte) (string, []string, error) {
+	dir, err := os.MkdirTemp("", "jcp-python-*")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	all := map[string][]byte{"script.py": []byte(script), "main.py": []byte(pythonRunner)}
+	maps.Copy(all, files)
+	for name, data := range all {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return "", nil, err
+		}
+	}
+
+	timeout := pythonDefaultTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// -I 隔离模式：忽略 PYTHON* 环境变量与用户 site-packages 目录
+	cmd := exec.CommandContext(ctx, pythonInterpreter(cfg), "-I", "main.py")
+	cmd.Dir = dir
+	cmd.Env = pythonEnv(dir)
+	cmd.WaitDelay = pythonWaitDelay
+	setProcessGroup(cmd)
+	out := &limitedBuffer{max: pythonMaxOutput}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return out.String(), nil, fmt.Errorf("执行超时（%s）", timeout)
+	}
+	if err != nil {
+		return out.String(), nil, err
+	}
+
+	plots, err := collectPlots(dir)
+	return out.String(), plots, err
+}
+
+func pythonInterpreter(cfg models.PythonToolConfig) string {
+	if cfg.Interpreter != "" {
+		return cfg.Interpreter
+	}
+	if runtime.GOOS == "windows" {
+		return "python"
+	}
+	return "python3"
+}
+
+// pythonEnv 最小化环境变量，不向子进程传递密钥等配置
+// 临时目录指向工作目录，tempfile 等模块写入的文件也在允许范围内
+func pythonEnv(dir string) []string {
+	env := []string{
+		"HOME=" + dir,
+		"MPLCONFIGDIR=" + dir,
+		"MPLBACKEND=Agg",
+		"PYTHONIOENCODING=utf-8",
+		"PYTHONDONTWRITEBYTECODE=1",
+		"TMPDIR=" + dir,
+		"TEMP=" + dir,
+		"TMP=" + dir,
+	}
+	for _, key := range []string{"PATH", "SYSTEMROOT"} {
+		if v := os.Getenv(key); v != "" {
+			env = append(env, key+"="+v)
+		}
+	}
+	return env
+}
+
+// collectPlots 将工作目录中的图片移到缓存目录，返回新路径
+func collectPlots(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.png"))
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+	target := paths.EnsureCacheDir("python")
+	defer prunePlots(target, pythonPlotMaxAge, pythonPlotMaxFiles)
+	prefix := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	var plots []string
+	for _, src := range matches {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return plots, err
+		}
+		dst := filepath.Join(target, prefix+"_"+filepath.Base(src))
+		if err := os.WriteFile(dst, data, 0644); err != nil {
+			return plots, err
+		}
+		plots = append(plots, dst)
+	}
+	return plots, nil
+}
+
+// prunePlots 删除过期的图表，并按修改时间只保留最新的 maxFiles 张
+func prunePlots(dir string, maxAge time.Duration, maxFiles int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	type file struct {
+		name    string
+		modTime time.Time
+	}
+	var files []file
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || e.IsDir() || filepath.Ext(e.Name()) != ".png" {
+			continue
+		}
+		if time.Since(info.ModTime()) > maxAge {
+			os.Remove(filepath.Join(dir, e.Name()))
+			continue
+		}
+		files = append(files, file{e.Name(), info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for i := 0; i < len(files)-maxFiles; i++ {
+		os.Remove(filepath.Join(dir, files[i].name))
+	}
+}
+
+// limitedBuffer 超出上限后丢弃后续输出
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	remain := b.max - b.buf.Len()
+	if len(p) > remain {
+		b.truncated = true
+		p = p[:max(remain, 0)]
+	}
+	b.buf.Write(p)
+	return n, nil
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "\n...(输出已截断)"
+	}
+	return b.buf.String()
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestRunPython(t *testing.T) {
+	interpreter, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not found")
+	}
+	cfg := models.PythonToolConfig{Enabled: true, Interpreter: interpreter, Timeout: 10}
+	files := map[string][]byte{"sh600519.csv": []byte("time,close\n2024-01-02,10\n2024-01-03,11\n")}
+
+	out, plots, err := runPython(context.Background(), cfg, "import csv\nrows = list(csv.DictReader(open('sh600519.csv')))\nprint(len(rows), rows[-1]['close'])", files)
+	if err != nil {
+		t.Fatalf("runPython: %v, output=%s", err, out)
+	}
+	if strings.TrimSpace(out) != "2 11" || len(plots) != 0 {
+		t.Errorf("output = %q, plots = %v", out, plots)
+	}
+	if len(files) != 1 {
+		t.Error("input files should not be modified")
+	}
+
+	// 错误行号对应用户脚本
+	out, _, err = runPython(context.Background(), cfg, "x = 1\ny = 2\nraise ValueError('bad')", nil)
+	if err == nil || !strings.Contains(out, "ValueError") || !strings.Contains(out, `"script.py", line 3`) {
+		t.Errorf("expected script error: err=%v, output=%s", err, out)
+	}
+
+	// 子进程不继承密钥等环境变量
+	os.Setenv("JCP_TEST_SECRET", "x")
+	defer os.Unsetenv("JCP_TEST_SECRET")
+	out, _, err = runPython(context.Background(), cfg, "import os\nprint(os.environ.get('JCP_TEST_SECRET', 'none'))", nil)
+	if err != nil || strings.TrimSpace(out) != "none" {
+		t.Errorf("env leaked: err=%v, output=%s", err, out)
+	}
+
+	cfg.Timeout = 1
+	if _, _, err := runPython(context.Background(), cfg, "while True: pass", nil); err == nil || !strings.Contains(err.Error(), "超时") {
+		t.Errorf("expected timeout, got %v", err)
+	}
+
+	// 原生调用绕过审计钩子 fork 出的子进程持有输出管道时，超时仍能及时返回
+	start := time.Now()
+	script := "import ctypes, time\nctypes.CDLL(None).fork()\ntime.sleep(999)"
+	if _, _, err := runPython(context.Background(), cfg, script, nil); err == nil || !strings.Contains(err.Error(), "超时") {
+		t.Errorf("expected timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("timeout took %v, child process kept the pipe open", elapsed)
+	}
+}
+
+func TestRunPythonRestrictions(t *testing.T) {
+	interpreter, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not found")
+	}
+	cfg := models.PythonToolConfig{Enabled: true, Interpreter: interpreter, Timeout: 10}
+	outside := filepath.Join(t.TempDir(), "outside.txt")
+	if err := os.WriteFile(outside, []byte("s3cr3t"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	blocked := []struct {
+		name   string
+		script string
+	}{
+		{"read outside", fmt.Sprintf("print(open(%q).read())", outside)},
+		{"write outside", fmt.Sprintf("open(%q, 'w').write('x')", outside)},
+		{"remove outside", fmt.Sprintf("import os\nos.remove(%q)", outside)},
+		{"network", "import socket\nsocket.create_connection(('127.0.0.1', 9), timeout=1)"},
+		{"subprocess", "import subprocess\nsubprocess.run(['echo', 'hi'])"},
+		{"os.system", "import os\nos.system('echo hi')"},
+	}
+	for _, tt := range blocked {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, err := runPython(context.Background(), cfg, tt.script, nil)
+			if err == nil || !strings.Contains(out, "PermissionError") || strings.Contains(out, "s3cr3t") {
+				t.Errorf("expected PermissionError: err=%v, output=%s", err, out)
+			}
+		})
+	}
+	if data, _ := os.ReadFile(outside); string(data) != "s3cr3t" {
+		t.Errorf("outside file modified: %q", data)
+	}
+
+	// 工作目录内读写、临时文件与标准库导入不受影响
+	script := "import json, tempfile, os\nopen('a.txt', 'w').write('ok')\nwith tempfile.NamedTemporaryFile() as f: pass\nos.remove('a.txt')\nprint(json.dumps([1]))"
+	out, _, err := runPython(context.Background(), cfg, script, nil)
+	if err != nil || strings.TrimSpace(out) != "[1]" {
+		t.Errorf("allowed operations failed: err=%v, output=%s", err, out)
+	}
+}
+
+func TestPrunePlots(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"old.png", "a.png", "b.png", "c.png", "keep.txt"} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, nil, 0644)
+		modTime := now.Add(time.Duration(i-5) * time.Minute)
+		if name == "old.png" {
+			modTime = now.Add(-48 * time.Hour)
+		}
+		os.Chtimes(path, modTime, modTime)
+	}
+
+	prunePlots(dir, 24*time.Hour, 2)
+
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if got := strings.Join(names, ","); got != "b.png,c.png,keep.txt" {
+		t.Errorf("remaining = %s", got)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{max: 5}
+	b.Write([]byte("abc"))
+	b.Write([]byte("de"))
+	if b.String() != "abcde" {
+		t.Errorf("got %q", b.String())
+	}
+	b.Write([]byte("f"))
+	if !strings.HasPrefix(b.String(), "abcde\n") || !b.truncated {
+		t.Errorf("got %q", b.String())
+	}
+}
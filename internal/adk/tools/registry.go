@@ -1,9 +1,18 @@
 package tools
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
 	"github.com/run-bigpig/jcp/internal/services"
 	"github.com/run-bigpig/jcp/internal/services/hottrend"
 
+	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
 )
 
@@ -11,18 +20,35 @@ import (
 type ToolInfo struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Custom      bool   `json:"custom"`  // 是否为用户自定义工具
+	Enabled     bool   `json:"enabled"` // 是否启用
 }
 
 // Registry 工具注册中心
 type Registry struct {
-	marketService         *services.MarketService
-	newsService           *services.NewsService
-	configService         *services.ConfigService
-	researchReportService *services.ResearchReportService
-	hotTrendService       *hottrend.HotTrendService
-	longHuBangService     *services.LongHuBangService
-	tools                 map[string]tool.Tool
-	toolInfos             map[string]ToolInfo // 工具信息映射
+	marketService          *services.MarketService
+	newsService            *services.NewsService
+	configService          *services.ConfigService
+	researchReportService  *services.ResearchReportService
+	hotTrendService        *hottrend.HotTrendService
+	longHuBangService      *services.LongHuBangService
+	limitBoardService      *services.LimitBoardService
+	stockNewsService       *services.StockNewsService
+	retailSentimentService *services.RetailSentimentService
+	eventService           *services.EventService
+	macroService           *services.MacroService
+	chartService           *services.ChartService
+	screenerService        *services.ScreenerService
+	paperTradingService    *services.PaperTradingService
+	tools                  map[string]tool.Tool
+	toolInfos              map[string]ToolInfo // 工具信息映射
+	customTools            map[string]bool     // 自定义工具名称
+	disabled               map[string]bool     // 运行时禁用的工具
+	outputDefault          int                 // 工具结果通用上限，0 时使用内置默认值
+	outputLimits           map[string]int      // 按工具名覆盖的结果上限，-1 表示不限制
+	summarize              bool                // 超出上限时用 AI 摘要
+	summaryLLM             func(ctx context.Context) (model.LLM, error)
+	mu                     sync.RWMutex
 }
 
 // NewRegistry 创建工具注册中心
@@ -33,16 +59,34 @@ func NewRegistry(
 	researchReportService *services.ResearchReportService,
 	hotTrendService *hottrend.HotTrendService,
 	longHuBangService *services.LongHuBangService,
+	limitBoardService *services.LimitBoardService,
+	stockNewsService *services.StockNewsService,
+	retailSentimentService *services.RetailSentimentService,
+	eventService *services.EventService,
+	macroService *services.MacroService,
+	chartService *services.ChartService,
+	screenerService *services.ScreenerService,
+	paperTradingService *services.PaperTradingService,
 ) *Registry {
 	r := &Registry{
-		marketService:         marketService,
-		newsService:           newsService,
-		configService:         configService,
-		researchReportService: researchReportService,
-		hotTrendService:       hotTrendService,
-		longHuBangService:     longHuBangService,
-		tools:                 make(map[string]tool.Tool),
-		toolInfos:             make(map[string]ToolInfo),
+		marketService:          marketService,
+		newsService:            newsService,
+		configService:          configService,
+		researchReportService:  researchReportService,
+		hotTrendService:        hotTrendService,
+		longHuBangService:      longHuBangService,
+		limitBoardService:      limitBoardService,
+		stockNewsService:       stockNewsService,
+		retailSentimentService: retailSentimentService,
+		eventService:           eventService,
+		macroService:           macroService,
+		chartService:           chartService,
+		screenerService:        screenerService,
+		paperTradingService:    paperTradingService,
+		tools:                  make(map[string]tool.Tool),
+		toolInfos:              make(map[string]ToolInfo),
+		customTools:            make(map[string]bool),
+		disabled:               make(map[string]bool),
 	}
 	r.registerAllTools()
 	return r
@@ -51,19 +95,49 @@ func NewRegistry(
 // registerAllTools 注册所有工具
 func (r *Registry) registerAllTools() {
 	// 注册股票实时数据工具
-	r.registerTool("get_stock_realtime", "获取股票实时行情数据，包括当前价格、涨跌幅、开盘价、最高价、最低价、成交量等", r.createStockRealtimeTool)
+	r.registerTool("get_stock_realtime", "获取股票实时行情数据，包括当前价格、涨跌幅、开盘价、最高价、最低价、成交量、换手率、市盈率、市净率、股本等", r.createStockRealtimeTool)
 
 	// 注册K线数据工具
-	r.registerTool("get_kline_data", "获取股票K线数据，支持5分钟线、日线、周线、月线", r.createKLineTool)
+	r.registerTool("get_kline_data", "获取股票K线数据（含MA5/10/20、ATR与未回补缺口），支持分时、5/15/30/60分钟线、日线、周线、月线，可选前复权/后复权", r.createKLineTool)
 
 	// 注册盘口数据工具
-	r.registerTool("get_orderbook", "获取股票五档盘口数据，包括买卖五档价格和数量", r.createOrderBookTool)
+	r.registerTool("get_orderbook", "获取股票五档盘口数据，包括买卖五档价格和数量，以及委比、大单与疑似撤单", r.createOrderBookTool)
+
+	// 注册逐笔成交工具
+	r.registerTool("get_tick_data", "获取股票最近的逐笔成交明细，包括成交时间、价格、手数和主动买卖方向，可用于分析盘中资金动向", r.createTickDataTool)
+
+	// 注册资金流向工具
+	r.registerTool("get_money_flow", "获取股票当日分时资金流向，包括分时均价、流入流出金额和滚动净流入，用于判断主力资金动向", r.createMoneyFlowTool)
 
 	// 注册快讯工具
 	r.registerTool("get_news", "获取最新财经快讯，来源于财联社", r.createNewsTool)
 
+	// 注册个股新闻工具
+	r.registerTool("get_stock_news", "获取提及指定股票的近期新闻，聚合东方财富、新浪财经、财联社多个来源并去重，按时间倒序", r.createStockNewsTool)
+
+	// 注册散户情绪工具
+	r.registerTool("get_retail_sentiment", "获取个股在东财股吧、雪球的近期散户讨论，去重后由AI总结情绪倾向、热议话题和代表观点", r.createRetailSentimentTool)
+
+	// 注册事件日历工具
+	r.registerTool("get_events", "获取个股或全部自选股近期的事件日历，包括财报预约披露日、股东大会、限售解禁、分红除权除息日，用于提示短期催化剂", r.createEventsTool)
+
+	// 注册宏观数据工具
+	r.registerTool("get_macro_data", "获取中国关键宏观经济数据的最新值与近期走势，包括CPI、PPI、PMI、LPR、M1/M2、社融规模增量、GDP", r.createMacroDataTool)
+
+	// 注册K线图表工具
+	r.registerTool("render_chart", "生成个股K线图（蜡烛图、MA5/10/20均线、成交量）PNG，返回可直接插入回复的 Markdown 图片链接", r.createChartTool)
+
+	// 注册计算工具
+	r.registerTool("run_calc", "在沙箱中确定性地执行数值计算脚本，可注入个股K线数组，用于计算波动率、回撤、收益率、均线等自定义指标", r.createCalcTool)
+
+	// 注册条件选股工具
+	r.registerTool("screen_stocks", "全市场A股条件选股，按估值(PE/PB/股息率)、动量(涨跌幅)、成交(换手率/量比/成交额)、市值、行业筛选并排序", r.createScreenStocksTool)
+
+	// 注册模拟盘下单工具
+	r.registerTool("place_paper_order", "在模拟盘提交买卖委托（市价/限价），委托需用户确认后才会报单撮合，不涉及真实交易", r.createPaperOrderTool)
+
 	// 注册股票搜索工具
-	r.registerTool("search_stocks", "搜索股票，根据关键词搜索股票代码和名称", r.createSearchStocksTool)
+	r.registerTool("search_stocks", "搜索股票，支持代码、名称、拼音首字母或全拼，结果按匹配度排序", r.createSearchStocksTool)
 
 	// 注册研报查询工具
 	r.registerTool("get_research_report", "获取个股研报列表，包括券商评级、研究员、预测EPS/PE等信息", r.createResearchReportTool)
@@ -72,13 +146,22 @@ func (r *Registry) registerAllTools() {
 	r.registerTool("get_report_content", "获取研报正文内容，需要先通过 get_research_report 获取 infoCode", r.createReportContentTool)
 
 	// 注册舆情热点工具
-	r.registerTool("get_hottrend", "获取全网舆情热点，支持微博、知乎、B站、百度、抖音、头条等平台的实时热搜榜单", r.createHotTrendTool)
+	r.registerTool("get_hottrend", "获取全网舆情热点，支持微博、知乎、B站、百度、抖音、头条等平台的实时热搜榜单，以及雪球、东财股吧、同花顺的个股人气榜", r.createHotTrendTool)
+
+	// 注册热点关联股票工具
+	r.registerTool("get_hottrend_stocks", "扫描全网热搜，将热点标题关联到相关A股股票与行业板块，用于发现舆情驱动的交易标的", r.createHotTrendStocksTool)
 
 	// 注册龙虎榜工具
 	r.registerTool("get_longhubang", "获取A股龙虎榜数据，包括上榜股票、净买入金额、买卖金额、上榜原因等信息", r.createLongHuBangTool)
 
 	// 注册龙虎榜营业部明细工具
 	r.registerTool("get_longhubang_detail", "获取个股龙虎榜营业部买卖明细，需要提供股票代码和交易日期", r.createLongHuBangDetailTool)
+
+	// 注册市场宽度工具
+	r.registerTool("get_market_breadth", "获取沪深两市涨跌家数与行业板块涨跌排行，包括板块领涨股和主力净流入，用于判断市场整体情绪与主线", r.createMarketBreadthTool)
+
+	// 注册涨跌停板工具
+	r.registerTool("get_limit_board", "获取A股涨停池、跌停池、炸板池及连板梯队，包括连板数、封板时间、炸板次数、封单资金", r.createLimitBoardTool)
 }
 
 // registerTool 注册单个工具并保存信息
@@ -89,55 +172,144 @@ func (r *Registry) registerTool(name, description string, creator func() (tool.T
 	}
 }
 
-// GetTool 获取指定工具
+// LoadCustomTools 按配置注册自定义 HTTP 工具，替换上次加载的全部自定义工具
+// 单个工具配置无效时跳过该工具，其余工具照常注册，错误合并返回
+func (r *Registry) LoadCustomTools(configs []models.CustomToolConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name := range r.customTools {
+		delete(r.tools, name)
+		delete(r.toolInfos, name)
+	}
+	r.customTools = make(map[string]bool)
+
+	var errs []error
+	for _, cfg := range configs {
+		if !cfg.Enabled {
+			continue
+		}
+		if _, exists := r.tools[cfg.Name]; exists || cfg.Name == pythonToolName {
+			errs = append(errs, fmt.Errorf("工具名称已存在: %s", cfg.Name))
+			continue
+		}
+		t, err := newCustomHTTPTool(cfg)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		r.tools[cfg.Name] = t
+		r.toolInfos[cfg.Name] = ToolInfo{Name: cfg.Name, Description: cfg.Description, Custom: true}
+		r.customTools[cfg.Name] = true
+	}
+	return errors.Join(errs...)
+}
+
+// SetDisabledTools 设置运行时禁用的工具，禁用后专家不再获得该工具
+func (r *Registry) SetDisabledTools(names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.disabled = make(map[string]bool, len(names))
+	for _, name := range names {
+		r.disabled[name] = true
+	}
+}
+
+// SetToolEnabled 启用或禁用单个工具
+func (r *Registry) SetToolEnabled(name string, enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if enabled {
+		delete(r.disabled, name)
+	} else {
+		r.disabled[name] = true
+	}
+}
+
+// GetDisabledTools 获取禁用的工具名称
+func (r *Registry) GetDisabledTools() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.disabled))
+	for name := range r.disabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetTool 获取指定工具（已禁用的工具视为不存在）
 func (r *Registry) GetTool(name string) (tool.Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.disabled[name] {
+		return nil, false
+	}
 	t, ok := r.tools[name]
 	return t, ok
 }
 
-// GetTools 根据名称列表获取工具
+// GetTools 根据名称列表获取已启用的工具
 func (r *Registry) GetTools(names []string) []tool.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var result []tool.Tool
 	for _, name := range names {
-		if t, ok := r.tools[name]; ok {
+		if t, ok := r.tools[name]; ok && !r.disabled[name] {
 			result = append(result, t)
 		}
 	}
 	return result
 }
 
-// GetAllTools 获取所有工具
+// GetAllTools 获取所有已启用的工具
 func (r *Registry) GetAllTools() []tool.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var result []tool.Tool
-	for _, t := range r.tools {
-		result = append(result, t)
+	for name, t := range r.tools {
+		if !r.disabled[name] {
+			result = append(result, t)
+		}
 	}
 	return result
 }
 
-// GetAllToolNames 获取所有工具名称
+// GetAllToolNames 获取所有已启用的工具名称
 func (r *Registry) GetAllToolNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var names []string
 	for name := range r.tools {
-		names = append(names, name)
+		if !r.disabled[name] {
+			names = append(names, name)
+		}
 	}
 	return names
 }
 
-// GetAllToolInfos 获取所有工具信息
+// GetAllToolInfos 获取所有工具信息（含已禁用的工具，Enabled 标记启用状态），说明按当前语言翻译
 func (r *Registry) GetAllToolInfos() []ToolInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var infos []ToolInfo
-	for _, info := range r.toolInfos {
+	for name, info := range r.toolInfos {
+		info.Description = i18n.T(info.Description)
+		info.Enabled = !r.disabled[name]
 		infos = append(infos, info)
 	}
 	return infos
 }
 
-// GetToolInfosByNames 根据名称列表获取工具信息
+// GetToolInfosByNames 根据名称列表获取已启用的工具信息
 func (r *Registry) GetToolInfosByNames(names []string) []ToolInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var infos []ToolInfo
 	for _, name := range names {
-		if info, ok := r.toolInfos[name]; ok {
+		if info, ok := r.toolInfos[name]; ok && !r.disabled[name] {
+			info.Description = i18n.T(info.Description)
+			info.Enabled = true
 			infos = append(infos, info)
 		}
 	}
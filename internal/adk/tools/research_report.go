@@ -3,15 +3,18 @@ package tools
 import (
 	"fmt"
 
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
 )
 
 // GetResearchReportInput 研报查询输入参数
 type GetResearchReportInput struct {
-	Code     string `json:"code" jsonschema:"股票代码，如 sz000001 或 000001"`
-	PageSize int    `json:"pageSize,omitzero" jsonschema:"每页数量，默认10"`
-	PageNo   int    `json:"pageNo,omitzero" jsonschema:"页码，默认1"`
+	Code      string `json:"code" jsonschema:"股票代码，如 sz000001 或 000001"`
+	PageSize  int    `json:"pageSize,omitzero" jsonschema:"每页数量，默认10"`
+	PageNo    int    `json:"pageNo,omitzero" jsonschema:"页码，默认1"`
+	MaxTokens int    `json:"maxTokens,omitzero" jsonschema:"返回文本的token预算，超出部分省略，默认2000"`
 }
 
 // GetResearchReportOutput 研报查询输出
@@ -20,6 +23,8 @@ type GetResearchReportOutput struct {
 	TotalCount int    `json:"totalCount" jsonschema:"总数量"`
 }
 
+const defaultReportTokenBudget = 2000
+
 // createResearchReportTool 创建研报查询工具
 func (r *Registry) createResearchReportTool() (tool.Tool, error) {
 	handler := func(ctx tool.Context, input GetResearchReportInput) (GetResearchReportOutput, error) {
@@ -46,7 +51,12 @@ func (r *Registry) createResearchReportTool() (tool.Tool, error) {
 			return GetResearchReportOutput{}, err
 		}
 
-		text := r.researchReportService.FormatReportsToText(result.Data)
+		maxTokens := input.MaxTokens
+		if maxTokens == 0 {
+			maxTokens = defaultReportTokenBudget
+		}
+
+		text := r.researchReportService.FormatReportsToTextWithBudget(result.Data, maxTokens)
 		fmt.Printf("[Tool:get_research_report] 调用完成, 返回%d条研报\n", len(result.Data))
 
 		return GetResearchReportOutput{
@@ -57,7 +67,7 @@ func (r *Registry) createResearchReportTool() (tool.Tool, error) {
 
 	return functiontool.New(functiontool.Config{
 		Name:        "get_research_report",
-		Description: "获取个股研报列表，包括券商评级、研究员、预测EPS/PE等信息",
+		Description: i18n.T("获取个股研报列表，包括券商评级、研究员、预测EPS/PE等信息"),
 	}, handler)
 }
 
@@ -98,6 +108,6 @@ func (r *Registry) createReportContentTool() (tool.Tool, error) {
 
 	return functiontool.New(functiontool.Config{
 		Name:        "get_report_content",
-		Description: "获取研报正文内容，需要先通过 get_research_report 获取研报列表中的 infoCode",
+		Description: i18n.T("获取研报正文内容，需要先通过 get_research_report 获取研报列表中的 infoCode"),
 	}, handler)
 }
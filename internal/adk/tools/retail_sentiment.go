@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetRetailSentimentInput 散户情绪输入参数
+type GetRetailSentimentInput struct {
+	Code string `json:"code" jsonschema:"股票代码，如 sh600519、sz000001"`
+}
+
+// GetRetailSentimentOutput 散户情绪输出
+type GetRetailSentimentOutput struct {
+	Data string `json:"data" jsonschema:"散户情绪总结与代表性讨论帖"`
+}
+
+// createRetailSentimentTool 创建散户情绪工具
+func (r *Registry) createRetailSentimentTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetRetailSentimentInput) (GetRetailSentimentOutput, error) {
+		fmt.Printf("[Tool:get_retail_sentiment] 调用开始, code=%s\n", input.Code)
+
+		if input.Code == "" {
+			return GetRetailSentimentOutput{}, fmt.Errorf("股票代码不能为空")
+		}
+
+		result, err := r.retailSentimentService.Analyze(ctx, strings.ToLower(input.Code))
+		if err != nil {
+			fmt.Printf("[Tool:get_retail_sentiment] 错误: %v\n", err)
+			return GetRetailSentimentOutput{}, err
+		}
+		if len(result.Posts) == 0 {
+			return GetRetailSentimentOutput{Data: "暂无该股票的散户讨论"}, nil
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "=== %s(%s) 散户情绪 ===\n", result.Name, result.Code)
+		if result.Summary != "" {
+			sb.WriteString(result.Summary)
+			sb.WriteString("\n")
+		}
+
+		// 附带热度最高的帖子，供核对总结或在未配置AI时自行判断
+		posts := append(result.Posts[:0:0], result.Posts...)
+		sort.SliceStable(posts, func(i, j int) bool {
+			return posts[i].Replies+posts[i].Likes > posts[j].Replies+posts[j].Likes
+		})
+		sb.WriteString("\n【热门讨论】\n")
+		for i, p := range posts {
+			if i >= 10 {
+				break
+			}
+			fmt.Fprintf(&sb, "[%s][%s] %s (回复%d 点赞%d)\n", p.Time, p.Source, p.Title, p.Replies, p.Likes)
+		}
+		fmt.Fprintf(&sb, "共%d条讨论\n", len(result.Posts))
+
+		fmt.Printf("[Tool:get_retail_sentiment] 调用完成, %d条讨论, 总结=%t\n", len(result.Posts), result.Summary != "")
+		return GetRetailSentimentOutput{Data: sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "get_retail_sentiment",
+		Description: i18n.T("获取个股在东财股吧、雪球的近期散户讨论，去重后由AI总结情绪倾向、热议话题和代表观点"),
+	}, handler)
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// ScreenConditionInput 单个筛选条件
+type ScreenConditionInput struct {
+	Field string  `json:"field" jsonschema:"字段: pe(市盈率TTM), pb(市净率), dividend_yield(股息率%), market_cap(总市值亿), price(现价), change_pct(今日涨跌幅%), change_60d(60日涨跌幅%), change_ytd(年初至今涨跌幅%), turnover(换手率%), volume_ratio(量比), amount(成交额亿)"`
+	Op    string  `json:"op" jsonschema:"运算符: > >= < <="`
+	Value float64 `json:"value" jsonschema:"比较值，百分比字段直接写数值，如股息率大于4%写4"`
+}
+
+// ScreenStocksInput 选股输入参数
+type ScreenStocksInput struct {
+	Conditions []ScreenConditionInput `json:"conditions,omitempty" jsonschema:"筛选条件（同时满足），区间用两个条件表示；低PE需同时加 pe > 0 排除亏损股"`
+	Industries []string               `json:"industries,omitempty" jsonschema:"行业关键词，匹配任一，如 银行、白酒、半导体"`
+	ExcludeST  bool                   `json:"exclude_st,omitempty" jsonschema:"是否排除ST及退市股，默认建议true"`
+	SortBy     string                 `json:"sort_by,omitempty" jsonschema:"排序字段，同筛选字段，默认 market_cap"`
+	Ascending  bool                   `json:"ascending,omitempty" jsonschema:"是否升序，默认降序"`
+	Limit      int                    `json:"limit,omitzero" jsonschema:"返回数量，默认20，最大100"`
+}
+
+// ScreenStocksOutput 选股输出
+type ScreenStocksOutput struct {
+	Data string `json:"data" jsonschema:"选股结果"`
+}
+
+// createScreenStocksTool 创建选股工具
+func (r *Registry) createScreenStocksTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ScreenStocksInput) (ScreenStocksOutput, error) {
+		fmt.Printf("[Tool:screen_stocks] 调用开始, conditions=%v, industries=%v, sort=%s\n", input.Conditions, input.Industries, input.SortBy)
+
+		q := services.ScreenQuery{
+			Industries: input.Industries,
+			ExcludeST:  input.ExcludeST,
+			SortBy:     input.SortBy,
+			Ascending:  input.Ascending,
+			Limit:      input.Limit,
+		}
+		for _, c := range input.Conditions {
+			q.Conditions = append(q.Conditions, services.ScreenCondition{Field: c.Field, Op: c.Op, Value: c.Value})
+		}
+
+		result, err := r.screenerService.Screen(q)
+		if err != nil {
+			fmt.Printf("[Tool:screen_stocks] 错误: %v\n", err)
+			return ScreenStocksOutput{}, err
+		}
+
+		fmt.Printf("[Tool:screen_stocks] 调用完成, 命中%d只\n", result.Total)
+		return ScreenStocksOutput{Data: formatScreenResult(q, result)}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "screen_stocks",
+		Description: i18n.T("全市场A股条件选股，按估值(PE/PB/股息率)、动量(当日/60日/年初至今涨跌幅)、成交(换手率/量比/成交额)、市值、行业筛选并排序，用于把「低PE高股息的银行股」这类需求转成筛选条件执行"),
+	}, handler)
+}
+
+// formatScreenResult 格式化选股结果，使用股息率时额外展示该列
+func formatScreenResult(q services.ScreenQuery, result *services.ScreenResult) string {
+	var sb strings.Builder
+	var conds []string
+	for _, c := range q.Conditions {
+		f := services.ScreenFields[c.Field]
+		conds = append(conds, fmt.Sprintf("%s%s%g%s", f.Name, c.Op, c.Value, f.Unit))
+	}
+	if len(q.Industries) > 0 {
+		conds = append(conds, "行业: "+strings.Join(q.Industries, "/"))
+	}
+	if len(conds) == 0 {
+		conds = append(conds, "无")
+	}
+	fmt.Fprintf(&sb, "筛选条件: %s\n命中 %d 只，展示前 %d 只\n\n", strings.Join(conds, "，"), result.Total, len(result.Stocks))
+
+	showYield := false
+	for _, c := range q.Conditions {
+		showYield = showYield || c.Field == "dividend_yield"
+	}
+	showYield = showYield || q.SortBy == "dividend_yield"
+
+	for i, s := range result.Stocks {
+		fmt.Fprintf(&sb, "%d. %s(%s) [%s] 现价%.2f 涨跌%s%% PE %s PB %s 市值%.0f亿 60日%s%% 换手%s%%",
+			i+1, s.Name, s.Symbol, s.Industry, s.Price, screenNum(s.ChangePct), screenNum(s.PE), screenNum(s.PB), s.MarketCap, screenNum(s.Change60d), screenNum(s.Turnover))
+		if showYield {
+			fmt.Fprintf(&sb, " 股息率%.2f%%", s.DividendYield)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// screenNum 缺失值显示为 -
+func screenNum(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", v)
+}
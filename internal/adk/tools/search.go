@@ -3,13 +3,15 @@ package tools
 import (
 	"fmt"
 
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
 )
 
 // SearchStocksInput 股票搜索输入参数
 type SearchStocksInput struct {
-	Keyword string `json:"keyword" jsonschema:"搜索关键词，支持股票代码或名称"`
+	Keyword string `json:"keyword" jsonschema:"搜索关键词，支持股票代码、名称、拼音首字母(如gzmt)或全拼"`
 	Limit   int    `json:"limit,omitzero" jsonschema:"返回条数，默认10条"`
 }
 
@@ -51,6 +53,6 @@ func (r *Registry) createSearchStocksTool() (tool.Tool, error) {
 
 	return functiontool.New(functiontool.Config{
 		Name:        "search_stocks",
-		Description: "搜索股票，支持按代码或名称搜索",
+		Description: i18n.T("搜索股票，支持按代码、名称、拼音首字母或全拼搜索，结果按匹配度排序"),
 	}, handler)
 }
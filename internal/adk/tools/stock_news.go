@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetStockNewsInput 个股新闻输入参数
+type GetStockNewsInput struct {
+	Code  string `json:"code" jsonschema:"股票代码，如 sh600519、sz000001"`
+	Limit int    `json:"limit,omitzero" jsonschema:"返回条数，默认15条，最大50"`
+}
+
+// GetStockNewsOutput 个股新闻输出
+type GetStockNewsOutput struct {
+	Data string `json:"data" jsonschema:"个股新闻列表"`
+}
+
+// createStockNewsTool 创建个股新闻工具
+func (r *Registry) createStockNewsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetStockNewsInput) (GetStockNewsOutput, error) {
+		fmt.Printf("[Tool:get_stock_news] 调用开始, code=%s, limit=%d\n", input.Code, input.Limit)
+
+		if input.Code == "" {
+			return GetStockNewsOutput{}, fmt.Errorf("股票代码不能为空")
+		}
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 15
+		}
+		if limit > 50 {
+			limit = 50
+		}
+
+		news, err := r.stockNewsService.GetStockNews(input.Code, limit)
+		if err != nil {
+			fmt.Printf("[Tool:get_stock_news] 错误: %v\n", err)
+			return GetStockNewsOutput{}, err
+		}
+		if len(news) == 0 {
+			return GetStockNewsOutput{Data: "暂无该股票相关新闻"}, nil
+		}
+
+		var sb strings.Builder
+		for _, n := range news {
+			fmt.Fprintf(&sb, "[%s][%s] %s\n", n.Time, n.Source, n.Title)
+			if n.Summary != "" {
+				fmt.Fprintf(&sb, "  %s\n", n.Summary)
+			}
+		}
+
+		fmt.Printf("[Tool:get_stock_news] 调用完成, 返回%d条新闻\n", len(news))
+		return GetStockNewsOutput{Data: sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "get_stock_news",
+		Description: i18n.T("获取提及指定股票的近期新闻，聚合东方财富、新浪财经、财联社多个来源并去重，按时间倒序"),
+	}, handler)
+}
@@ -3,6 +3,10 @@ package tools
 import (
 	"fmt"
 
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/services"
+
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
 )
@@ -28,17 +32,32 @@ func (r *Registry) createStockRealtimeTool() (tool.Tool, error) {
 			return GetStockRealtimeOutput{Data: "请提供股票代码"}, nil
 		}
 
-		stocks, err := r.marketService.GetStockRealTimeData(input.Codes...)
-		if err != nil {
-			fmt.Printf("[Tool:get_stock_realtime] 错误: %v\n", err)
-			return GetStockRealtimeOutput{}, err
+		stocks, quoteErrs := r.marketService.GetQuotes(input.Codes...)
+		if len(stocks) == 0 {
+			if err := services.QuoteFetchError(quoteErrs); err != nil {
+				fmt.Printf("[Tool:get_stock_realtime] 错误: %v\n", err)
+				return GetStockRealtimeOutput{}, err
+			}
 		}
 
+		// 附带交易日历给出的市场状态，避免专家把休市、收盘后的价格当成盘中实时数据
+		status := r.marketService.GetMarketStatus()
+		result := "市场状态: " + status.StatusText
+		if status.NextTradeDay != "" {
+			result += "，下一交易日 " + status.NextTradeDay
+		}
+		result += "\n"
+
 		// 格式化股票数据输出
-		var result string
 		for _, s := range stocks {
-			result += fmt.Sprintf("【%s(%s)】价格:%.2f 涨跌:%.2f%% 开盘:%.2f 最高:%.2f 最低:%.2f 成交量:%d\n",
+			result += fmt.Sprintf("【%s(%s)】价格:%.2f 涨跌:%.2f%% 开盘:%.2f 最高:%.2f 最低:%.2f 成交量:%d",
 				s.Name, s.Symbol, s.Price, s.ChangePercent, s.Open, s.High, s.Low, s.Volume)
+			result += formatValuation(s) + "\n"
+		}
+
+		// 个别代码失败不影响其余股票，逐个说明原因
+		for _, e := range quoteErrs {
+			result += fmt.Sprintf("【%s】未获取到行情: %v\n", e.Code, e.Err)
 		}
 
 		// 获取大盘指数数据
@@ -59,6 +78,22 @@ func (r *Registry) createStockRealtimeTool() (tool.Tool, error) {
 
 	return functiontool.New(functiontool.Config{
 		Name:        "get_stock_realtime",
-		Description: "获取股票实时行情数据，包括当前价格、涨跌幅、开盘价、最高价、最低价、成交量等，以及大盘指数数据",
+		Description: i18n.T("获取股票实时行情数据，包括当前价格、涨跌幅、开盘价、最高价、最低价、成交量、换手率、市盈率、市净率、股本等，以及大盘指数数据"),
 	}, handler)
 }
+
+// formatValuation 换手率、估值与股本，未获取到时不输出
+func formatValuation(s models.Stock) string {
+	if s.FloatShares == 0 && s.TurnoverRate == 0 && s.PB == 0 {
+		return ""
+	}
+	pe := "-"
+	switch {
+	case s.PE > 0:
+		pe = fmt.Sprintf("%.2f", s.PE)
+	case s.PE < 0:
+		pe = "亏损"
+	}
+	return fmt.Sprintf(" 换手率:%.2f%% 市盈率(动):%s 市净率:%.2f 流通股本:%.2f亿股 总股本:%.2f亿股",
+		s.TurnoverRate, pe, s.PB, float64(s.FloatShares)/1e8, float64(s.TotalShares)/1e8)
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetTickDataInput 逐笔成交输入参数
+type GetTickDataInput struct {
+	Code  string `json:"code" jsonschema:"股票代码，如 sh600519"`
+	Count int    `json:"count,omitempty" jsonschema:"获取最近的成交笔数，默认50，最多200"`
+}
+
+// GetTickDataOutput 逐笔成交输出
+type GetTickDataOutput struct {
+	Data string `json:"data" jsonschema:"逐笔成交明细及主动买卖统计"`
+}
+
+// createTickDataTool 创建逐笔成交工具
+func (r *Registry) createTickDataTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetTickDataInput) (GetTickDataOutput, error) {
+		fmt.Printf("[Tool:get_tick_data] 调用开始, code=%s, count=%d\n", input.Code, input.Count)
+
+		if input.Code == "" {
+			fmt.Println("[Tool:get_tick_data] 错误: 未提供股票代码")
+			return GetTickDataOutput{Data: "请提供股票代码"}, nil
+		}
+
+		ticks, err := r.marketService.GetTickData(input.Code, input.Count)
+		if err != nil {
+			fmt.Printf("[Tool:get_tick_data] 错误: %v\n", err)
+			return GetTickDataOutput{}, err
+		}
+		if len(ticks) == 0 {
+			return GetTickDataOutput{Data: "暂无逐笔成交数据（可能为非交易时段）"}, nil
+		}
+
+		// 统计主动买卖（手）
+		var buyLots, sellLots, neutralLots int64
+		var sb strings.Builder
+		sb.WriteString("时间 价格 手数 方向\n")
+		for _, t := range ticks {
+			lots := t.Volume / 100
+			label := "中性"
+			switch t.Direction {
+			case "buy":
+				buyLots += lots
+				label = "主买"
+			case "sell":
+				sellLots += lots
+				label = "主卖"
+			default:
+				neutralLots += lots
+			}
+			fmt.Fprintf(&sb, "%s %.2f %d %s\n", t.Time, t.Price, lots, label)
+		}
+
+		summary := fmt.Sprintf("最近%d笔成交（%s ~ %s）：主买 %d手，主卖 %d手，中性 %d手\n\n",
+			len(ticks), ticks[0].Time, ticks[len(ticks)-1].Time, buyLots, sellLots, neutralLots)
+
+		fmt.Printf("[Tool:get_tick_data] 调用完成, 返回%d笔\n", len(ticks))
+		return GetTickDataOutput{Data: summary + sb.String()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "get_tick_data",
+		Description: i18n.T("获取股票最近的逐笔成交明细（时间、价格、手数、主动买卖方向）及主动买卖统计"),
+	}, handler)
+}
@@ -0,0 +1,178 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestMA(t *testing.T) {
+	got := MA([]float64{1, 2, 3, 4}, 3)
+	want := []float64{0, 0, 2, 3}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("MA = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestATR(t *testing.T) {
+	high := []float64{10, 11, 12, 11}
+	low := []float64{9, 10, 10, 9}
+	closes := []float64{9.5, 10.5, 11, 10}
+	// TR: 1, max(1, 1.5, 0.5)=1.5, max(2, 1.5, 0.5)=2, max(2, 0, 2)=2
+	got := ATR(high, low, closes, 2)
+	want := []float64{0, 1.25, 1.625, 1.8125}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Fatalf("ATR = %v, want %v", got, want)
+		}
+	}
+	if got := ATR(high, low, closes, 10); got[3] != 0 {
+		t.Errorf("ATR with insufficient bars = %v", got)
+	}
+}
+
+func TestFillMA(t *testing.T) {
+	klines := make([]models.KLineData, 20)
+	for i := range klines {
+		klines[i].Close = float64(i + 1)
+		klines[i].MA5 = 99 // 数据源给出的旧值应被覆盖
+	}
+	FillMA(klines)
+	if klines[3].MA5 != 0 || klines[4].MA5 != 3 || klines[19].MA10 != 15.5 || klines[19].MA20 != 10.5 || klines[18].MA20 != 0 {
+		t.Errorf("FillMA: %+v / %+v", klines[4], klines[19])
+	}
+}
+
+func TestResample(t *testing.T) {
+	bar := func(tm string, o, h, l, c float64, v int64) models.KLineData {
+		return models.KLineData{Time: tm, Open: o, High: h, Low: l, Close: c, Volume: v, Amount: float64(v) * c}
+	}
+	minutes := []models.KLineData{
+		bar("2024-06-20 09:30:00", 10, 10, 10, 10, 100), // 集合竞价归入第一根
+		bar("2024-06-20 09:31:00", 10, 10.5, 9.9, 10.4, 100),
+		bar("2024-06-20 09:35:00", 10.4, 10.6, 10.3, 10.5, 100),
+		bar("2024-06-20 09:36:00", 10.5, 10.5, 10.1, 10.2, 100),
+		bar("2024-06-20 11:30:00", 10.2, 10.3, 10.2, 10.3, 100),
+		bar("2024-06-20 13:01:00", 10.3, 10.4, 10.3, 10.4, 100),
+		bar("2024-06-21 09:31:00", 11, 11, 11, 11, 100),
+	}
+
+	got := Resample(minutes, 5)
+	wantTimes := []string{"2024-06-20 09:35:00", "2024-06-20 09:40:00", "2024-06-20 11:30:00", "2024-06-20 13:05:00", "2024-06-21 09:35:00"}
+	if len(got) != len(wantTimes) {
+		t.Fatalf("Resample = %+v", got)
+	}
+	for i, tm := range wantTimes {
+		if got[i].Time != tm {
+			t.Errorf("bar %d time = %s, want %s", i, got[i].Time, tm)
+		}
+	}
+	if first := got[0]; first.Open != 10 || first.High != 10.6 || first.Low != 9.9 || first.Close != 10.5 || first.Volume != 300 {
+		t.Errorf("first bar = %+v", first)
+	}
+
+	hourly := Resample(minutes, 60)
+	if len(hourly) != 4 || hourly[0].Time != "2024-06-20 10:30:00" || hourly[1].Time != "2024-06-20 11:30:00" || hourly[2].Time != "2024-06-20 14:00:00" {
+		t.Errorf("hourly = %+v", hourly)
+	}
+}
+
+func TestGaps(t *testing.T) {
+	klines := []models.KLineData{
+		{Time: "d1", High: 10, Low: 9},
+		{Time: "d2", High: 11, Low: 10.5}, // 向上跳空 10~10.5
+		{Time: "d3", High: 11, Low: 10.2},
+		{Time: "d4", High: 9.8, Low: 9.5}, // 向下跳空 9.8~10.2，同时回补前一个缺口
+	}
+	gaps := Gaps(klines)
+	if len(gaps) != 2 {
+		t.Fatalf("Gaps = %+v", gaps)
+	}
+	if g := gaps[0]; !g.Up || g.Lower != 10 || g.Upper != 10.5 || !g.Filled {
+		t.Errorf("up gap = %+v", g)
+	}
+	if g := gaps[1]; g.Up || g.Lower != 9.8 || g.Upper != 10.2 || g.Filled {
+		t.Errorf("down gap = %+v", g)
+	}
+	if open := OpenGaps(klines); len(open) != 1 || open[0].Time != "d4" {
+		t.Errorf("OpenGaps = %+v", open)
+	}
+}
+
+func TestOrderBookMetrics(t *testing.T) {
+	level := func(price float64, size int64) models.OrderBookItem {
+		return models.OrderBookItem{Price: price, Size: size}
+	}
+	prev := models.OrderBook{
+		Bids: []models.OrderBookItem{level(9.99, 100), level(9.98, 100), level(9.97, 900), level(9.96, 100), level(9.95, 100)},
+		Asks: []models.OrderBookItem{level(10.00, 100), level(10.01, 100), level(10.02, 100), level(10.03, 100), level(10.04, 100)},
+	}
+	cur := models.OrderBook{
+		// 买一被成交吃掉不算撤单，9.97 的大单撤走
+		Bids: []models.OrderBookItem{level(9.98, 100), level(9.97, 100), level(9.96, 100), level(9.95, 100), level(9.94, 100)},
+		Asks: []models.OrderBookItem{level(9.99, 100), level(10.00, 100), level(10.01, 2000), level(10.02, 100), level(10.03, 100)},
+	}
+
+	m := OrderBookMetrics(cur, &prev)
+	if m.BidVolume != 500 || m.AskVolume != 2400 || math.Abs(m.Imbalance-(-1900.0/2900)) > 1e-9 {
+		t.Errorf("volumes = %+v", m)
+	}
+	if len(m.LargeBids) != 0 || len(m.LargeAsks) != 1 || m.LargeAsks[0] != 2 {
+		t.Errorf("large = %v / %v", m.LargeBids, m.LargeAsks)
+	}
+	if len(m.Withdrawn) != 1 || m.Withdrawn[0].Side != "bid" || m.Withdrawn[0].Price != 9.97 || m.Withdrawn[0].Size != 800 {
+		t.Errorf("withdrawn = %+v", m.Withdrawn)
+	}
+
+	if m := OrderBookMetrics(cur, nil); m.Withdrawn != nil {
+		t.Errorf("withdrawn without prev = %+v", m.Withdrawn)
+	}
+	if m := OrderBookMetrics(models.OrderBook{}, &prev); m.Imbalance != 0 || m.Withdrawn != nil {
+		t.Errorf("empty book = %+v", m)
+	}
+}
+
+func TestMoneyFlow(t *testing.T) {
+	bar := func(tm string, o, c float64, v int64) models.KLineData {
+		return models.KLineData{Time: tm, Open: o, Close: c, Volume: v, Amount: float64(v) * c}
+	}
+	klines := []models.KLineData{
+		bar("2024-06-20 09:31:00", 10, 10.2, 100),   // 高于开盘：流入 1020
+		bar("2024-06-20 09:32:00", 10.2, 10.1, 100), // 低于上一分钟：流出 1010
+		bar("2024-06-20 09:33:00", 10.1, 10.1, 100), // 持平不计
+		bar("2024-06-20 09:34:00", 10.1, 10.3, 200), // 流入 2060
+		bar("2024-06-21 09:31:00", 10.3, 10.0, 100), // 新的一天重新累计：流出 1000
+	}
+
+	points := MoneyFlow(klines)
+	if p := points[3]; p.Inflow != 2060 || p.CumNet != 2070 || math.Abs(p.VWAP-(1020+1010+1010+2060)/500.0) > 1e-9 {
+		t.Errorf("point 3 = %+v", p)
+	}
+	if p := points[4]; p.Outflow != 1000 || p.CumNet != -1000 || p.VWAP != 10 {
+		t.Errorf("next day = %+v", p)
+	}
+
+	rolling := RollingFlow(points, 2)
+	if r := rolling[3]; r.Inflow != 2060 || r.Outflow != 0 || r.Net != 2060 {
+		t.Errorf("rolling[3] = %+v", r)
+	}
+	if r := rolling[2]; r.Inflow != 0 || r.Outflow != 1010 {
+		t.Errorf("rolling[2] = %+v", r)
+	}
+	if r := rolling[4]; r.Outflow != 1000 || r.Inflow != 0 {
+		t.Errorf("rolling across days = %+v", r)
+	}
+
+	buckets := FlowBuckets(points, 30)
+	if len(buckets) != 2 || buckets[0].Time != "2024-06-20 10:00:00" || buckets[0].Net != 2070 || buckets[1].Net != -1000 {
+		t.Errorf("FlowBuckets = %+v", buckets)
+	}
+
+	FillIntraday(klines)
+	if klines[3].NetInflow != 2070 || klines[3].Avg != points[3].VWAP {
+		t.Errorf("FillIntraday = %+v", klines[3])
+	}
+}
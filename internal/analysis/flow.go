@@ -0,0 +1,94 @@
+package analysis
+
+import "github.com/run-bigpig/jcp/internal/models"
+
+// MoneyFlow 按 1 分钟K线计算资金流向：收盘价高于上一分钟（当日首根对比开盘价）计为流入，
+// 低于计为流出，持平不计；VWAP 与累计净流入跨日时重新累计
+func MoneyFlow(klines []models.KLineData) []models.MoneyFlowPoint {
+	out := make([]models.MoneyFlowPoint, len(klines))
+	var amount, cumNet float64
+	var volume int64
+	for i, k := range klines {
+		ref := k.Open
+		if i > 0 && sameDay(klines[i-1].Time, k.Time) {
+			ref = klines[i-1].Close
+		} else {
+			amount, volume, cumNet = 0, 0, 0
+		}
+		amount += k.Amount
+		volume += k.Volume
+
+		p := models.MoneyFlowPoint{Time: k.Time}
+		if volume > 0 {
+			p.VWAP = amount / float64(volume)
+		}
+		switch {
+		case k.Close > ref:
+			p.Inflow = k.Amount
+		case k.Close < ref:
+			p.Outflow = k.Amount
+		}
+		p.Net = p.Inflow - p.Outflow
+		cumNet += p.Net
+		p.CumNet = cumNet
+		out[i] = p
+	}
+	return out
+}
+
+// FillIntraday 为分时K线填充均价线与累计净流入（原地修改）
+func FillIntraday(klines []models.KLineData) {
+	for i, p := range MoneyFlow(klines) {
+		klines[i].Avg, klines[i].NetInflow = p.VWAP, p.CumNet
+	}
+}
+
+// RollingFlow 滚动资金流向：每个点的流入/流出/净流入为截至该分钟、当日最近 window 分钟的合计
+func RollingFlow(points []models.MoneyFlowPoint, window int) []models.MoneyFlowPoint {
+	out := make([]models.MoneyFlowPoint, len(points))
+	start := 0
+	var in, outflow float64
+	for i, p := range points {
+		if i > 0 && !sameDay(points[i-1].Time, p.Time) {
+			start, in, outflow = i, 0, 0
+		}
+		in += p.Inflow
+		outflow += p.Outflow
+		if window > 0 && i-start >= window {
+			in -= points[i-window].Inflow
+			outflow -= points[i-window].Outflow
+		}
+		p.Inflow, p.Outflow, p.Net = in, outflow, in-outflow
+		out[i] = p
+	}
+	return out
+}
+
+// FlowBuckets 按交易时段对齐的 minutes 分钟区间汇总资金流向，时间标记为区间结束时刻
+func FlowBuckets(points []models.MoneyFlowPoint, minutes int) []models.MoneyFlowPoint {
+	if minutes <= 1 {
+		return points
+	}
+	var out []models.MoneyFlowPoint
+	for _, p := range points {
+		date, offset, ok := sessionOffset(p.Time)
+		if !ok {
+			continue
+		}
+		end := date + " " + sessionClock(max((offset+minutes-1)/minutes, 1)*minutes)
+		if len(out) == 0 || out[len(out)-1].Time != end {
+			out = append(out, models.MoneyFlowPoint{Time: end})
+		}
+		b := &out[len(out)-1]
+		b.Inflow += p.Inflow
+		b.Outflow += p.Outflow
+		b.Net = b.Inflow - b.Outflow
+		b.VWAP, b.CumNet = p.VWAP, p.CumNet
+	}
+	return out
+}
+
+// sameDay 两个 "2006-01-02 ..." 格式的时间是否同一天
+func sameDay(a, b string) bool {
+	return len(a) >= 10 && len(b) >= 10 && a[:10] == b[:10]
+}
@@ -0,0 +1,48 @@
+package analysis
+
+import "github.com/run-bigpig/jcp/internal/models"
+
+// Gap 跳空缺口：当根K线与前一根的价格区间不重叠
+type Gap struct {
+	Time   string  `json:"time"`   // 出现缺口的K线时间
+	Up     bool    `json:"up"`     // 向上跳空
+	Lower  float64 `json:"lower"`  // 缺口下沿
+	Upper  float64 `json:"upper"`  // 缺口上沿
+	Filled bool    `json:"filled"` // 之后的K线是否已完全回补
+}
+
+// Gaps 识别K线中的跳空缺口，按时间升序返回
+func Gaps(klines []models.KLineData) []Gap {
+	var gaps []Gap
+	for i := 1; i < len(klines); i++ {
+		prev, cur := klines[i-1], klines[i]
+		var gap Gap
+		switch {
+		case cur.Low > prev.High:
+			gap = Gap{Time: cur.Time, Up: true, Lower: prev.High, Upper: cur.Low}
+		case cur.High < prev.Low:
+			gap = Gap{Time: cur.Time, Lower: cur.High, Upper: prev.Low}
+		default:
+			continue
+		}
+		for _, next := range klines[i+1:] {
+			if (gap.Up && next.Low <= gap.Lower) || (!gap.Up && next.High >= gap.Upper) {
+				gap.Filled = true
+				break
+			}
+		}
+		gaps = append(gaps, gap)
+	}
+	return gaps
+}
+
+// OpenGaps 尚未回补的缺口
+func OpenGaps(klines []models.KLineData) []Gap {
+	var open []Gap
+	for _, g := range Gaps(klines) {
+		if !g.Filled {
+			open = append(open, g)
+		}
+	}
+	return open
+}
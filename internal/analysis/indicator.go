@@ -0,0 +1,91 @@
+// Package analysis 行情分析工具：分钟线重采样、均线/ATR 计算、缺口识别与盘口指标，供行情服务、推送与工具共用
+package analysis
+
+import (
+	"math"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// MAWarmup 计算最长均线需要额外向前获取的K线根数
+const MAWarmup = 19
+
+// MA 简单移动平均，结果与输入等长，不足周期的位置为 0
+func MA(values []float64, period int) []float64 {
+	out := make([]float64, len(values))
+	if period <= 0 {
+		return out
+	}
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// TrueRange 真实波幅：max(最高-最低, |最高-昨收|, |最低-昨收|)，首根为最高-最低
+func TrueRange(high, low, closes []float64) []float64 {
+	n := min(len(high), len(low), len(closes))
+	out := make([]float64, n)
+	for i := range n {
+		tr := high[i] - low[i]
+		if i > 0 {
+			tr = math.Max(tr, math.Max(math.Abs(high[i]-closes[i-1]), math.Abs(low[i]-closes[i-1])))
+		}
+		out[i] = tr
+	}
+	return out
+}
+
+// ATR 平均真实波幅（Wilder 平滑），结果与输入等长，不足周期的位置为 0
+func ATR(high, low, closes []float64, period int) []float64 {
+	tr := TrueRange(high, low, closes)
+	out := make([]float64, len(tr))
+	if period <= 0 || len(tr) < period {
+		return out
+	}
+	var sum float64
+	for _, v := range tr[:period] {
+		sum += v
+	}
+	out[period-1] = sum / float64(period)
+	for i := period; i < len(tr); i++ {
+		out[i] = (out[i-1]*float64(period-1) + tr[i]) / float64(period)
+	}
+	return out
+}
+
+// Closes 提取收盘价序列
+func Closes(klines []models.KLineData) []float64 {
+	out := make([]float64, len(klines))
+	for i, k := range klines {
+		out[i] = k.Close
+	}
+	return out
+}
+
+// KLineATR 按K线计算 ATR
+func KLineATR(klines []models.KLineData, period int) []float64 {
+	n := len(klines)
+	high, low, closes := make([]float64, n), make([]float64, n), make([]float64, n)
+	for i, k := range klines {
+		high[i], low[i], closes[i] = k.High, k.Low, k.Close
+	}
+	return ATR(high, low, closes, period)
+}
+
+// FillMA 按收盘价重新计算 MA5/MA10/MA20（原地修改），不足周期的位置置 0
+// 复权后的价格需重新计算，不能沿用数据源按原始价格给出的均线
+func FillMA(klines []models.KLineData) {
+	closes := Closes(klines)
+	ma5, ma10, ma20 := MA(closes, 5), MA(closes, 10), MA(closes, 20)
+	for i := range klines {
+		klines[i].MA5, klines[i].MA10, klines[i].MA20 = ma5[i], ma10[i], ma20[i]
+	}
+}
@@ -0,0 +1,83 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 盘口指标阈值
+const (
+	LargeOrderRatio  = 3.0 // 挂单量达到全部档位均值的倍数视为大单
+	WithdrawMinRatio = 0.5 // 未被成交触及的档位挂单减少超过原量的比例视为撤单
+)
+
+// OrderBookMetrics 计算盘口衍生指标；prev 为上一份盘口快照（可为 nil），用于识别撤单
+func OrderBookMetrics(cur models.OrderBook, prev *models.OrderBook) models.OrderBookMetrics {
+	var m models.OrderBookMetrics
+	for _, b := range cur.Bids {
+		m.BidVolume += b.Size
+	}
+	for _, a := range cur.Asks {
+		m.AskVolume += a.Size
+	}
+	total := m.BidVolume + m.AskVolume
+	if total == 0 {
+		return m
+	}
+	m.Imbalance = float64(m.BidVolume-m.AskVolume) / float64(total)
+
+	avg := float64(total) / float64(len(cur.Bids)+len(cur.Asks))
+	m.LargeBids = largeLevels(cur.Bids, avg)
+	m.LargeAsks = largeLevels(cur.Asks, avg)
+	if prev != nil {
+		m.Withdrawn = append(withdrawnLevels("bid", prev.Bids, cur.Bids, avg), withdrawnLevels("ask", prev.Asks, cur.Asks, avg)...)
+	}
+	return m
+}
+
+// largeLevels 返回挂单量达到均值 LargeOrderRatio 倍的档位下标
+func largeLevels(levels []models.OrderBookItem, avg float64) []int {
+	var idx []int
+	for i, l := range levels {
+		if l.Size > 0 && float64(l.Size) >= avg*LargeOrderRatio {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// withdrawnLevels 对比同一侧前后两份盘口，找出疑似撤单的档位
+// 只看当前最优价之外、仍在可见档位范围内的价格：这些价格没有被成交触及，挂单减少只能是撤单
+func withdrawnLevels(side string, prev, cur []models.OrderBookItem, avg float64) []models.WithdrawnOrder {
+	if len(cur) == 0 {
+		return nil
+	}
+	best, worst := priceKey(cur[0].Price), priceKey(cur[len(cur)-1].Price)
+	sizes := make(map[int64]int64, len(cur))
+	for _, l := range cur {
+		sizes[priceKey(l.Price)] = l.Size
+	}
+
+	var out []models.WithdrawnOrder
+	for _, l := range prev {
+		key := priceKey(l.Price)
+		inside := key < best && key >= worst
+		if side == "ask" {
+			inside = key > best && key <= worst
+		}
+		if !inside || l.Size <= 0 {
+			continue
+		}
+		drop := l.Size - sizes[key]
+		if drop > 0 && float64(drop) >= float64(l.Size)*WithdrawMinRatio && float64(drop) >= avg {
+			out = append(out, models.WithdrawnOrder{Side: side, Price: l.Price, Size: drop})
+		}
+	}
+	return out
+}
+
+// priceKey 价格按 0.001 取整，避免浮点误差影响同价比较
+func priceKey(price float64) int64 {
+	return int64(math.Round(price * 1000))
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// A 股连续竞价时段（分钟）：上午 9:30-11:30，下午 13:00-15:00，共 240 分钟
+const (
+	morningOpen    = 9*60 + 30
+	morningClose   = 11*60 + 30
+	afternoonOpen  = 13 * 60
+	afternoonClose = 15 * 60
+	sessionMinutes = 240
+)
+
+// Resample 将 1 分钟K线合并为 minutes 分钟K线（如 5/15/30/60）
+// 按交易时段对齐且不跨午休、不跨日，时间标记为区间结束时刻（与数据源的分钟线一致）；
+// 最后一根可能尚未走完。minutes <= 1 时原样返回
+func Resample(klines []models.KLineData, minutes int) []models.KLineData {
+	if minutes <= 1 || len(klines) == 0 {
+		return klines
+	}
+
+	var out []models.KLineData
+	lastKey := ""
+	for _, k := range klines {
+		date, offset, ok := sessionOffset(k.Time)
+		if !ok {
+			continue
+		}
+		bucket := max((offset+minutes-1)/minutes, 1)
+		key := fmt.Sprintf("%s#%d", date, bucket)
+		if key != lastKey {
+			out = append(out, models.KLineData{
+				Time: date + " " + sessionClock(bucket*minutes),
+				Open: k.Open, High: k.High, Low: k.Low,
+			})
+			lastKey = key
+		}
+		bar := &out[len(out)-1]
+		bar.High = max(bar.High, k.High)
+		bar.Low = min(bar.Low, k.Low)
+		bar.Close = k.Close
+		bar.Volume += k.Volume
+		bar.Amount += k.Amount
+	}
+	return out
+}
+
+// sessionOffset 解析 "2006-01-02 15:04:05" 格式的时间，返回日期与距开盘的交易分钟数（1~240）
+// 集合竞价归入第一分钟，午休归入上午收盘，收盘后归入最后一分钟
+func sessionOffset(t string) (string, int, bool) {
+	if len(t) < 16 || t[10] != ' ' || t[13] != ':' {
+		return "", 0, false
+	}
+	h, errH := strconv.Atoi(t[11:13])
+	m, errM := strconv.Atoi(t[14:16])
+	if errH != nil || errM != nil {
+		return "", 0, false
+	}
+	clock := h*60 + m
+	var offset int
+	switch {
+	case clock <= morningClose:
+		offset = clock - morningOpen
+	case clock <= afternoonOpen:
+		offset = morningClose - morningOpen
+	default:
+		offset = morningClose - morningOpen + min(clock, afternoonClose) - afternoonOpen
+	}
+	return t[:10], min(max(offset, 1), sessionMinutes), true
+}
+
+// sessionClock 交易分钟数转换为时钟时间 "15:04:00"
+func sessionClock(offset int) string {
+	offset = min(offset, sessionMinutes)
+	clock := morningOpen + offset
+	if offset > morningClose-morningOpen {
+		clock = afternoonOpen + offset - (morningClose - morningOpen)
+	}
+	return fmt.Sprintf("%02d:%02d:00", clock/60, clock%60)
+}
@@ -0,0 +1,91 @@
+// Package broker 券商接口接入层，当前仅支持只读同步持仓与当日委托
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 接口类型
+const (
+	TypeQMT = "qmt" // 迅投 QMT（xtquant）HTTP 桥接
+)
+
+// 委托状态
+const (
+	OrderOpen      = "open"      // 已报未成交
+	OrderPartial   = "partial"   // 部分成交
+	OrderFilled    = "filled"    // 全部成交
+	OrderCancelled = "cancelled" // 已撤（含部撤）
+	OrderRejected  = "rejected"  // 废单
+)
+
+// Position 券商持仓
+type Position struct {
+	Symbol      string  `json:"symbol"` // 统一为 sh600519 格式
+	Name        string  `json:"name"`
+	Shares      int64   `json:"shares"`    // 持仓数量
+	Available   int64   `json:"available"` // 可用数量
+	CostPrice   float64 `json:"costPrice"`
+	MarketValue float64 `json:"marketValue"`
+}
+
+// Order 券商当日委托
+type Order struct {
+	ID           string  `json:"id"`
+	Symbol       string  `json:"symbol"`
+	Name         string  `json:"name"`
+	Side         string  `json:"side"` // buy/sell
+	Price        float64 `json:"price"`
+	Shares       int64   `json:"shares"`
+	FilledShares int64   `json:"filledShares"`
+	FilledPrice  float64 `json:"filledPrice"`
+	Status       string  `json:"status"`
+	Message      string  `json:"message,omitempty"`
+	Time         int64   `json:"time"` // 委托时间（毫秒）
+}
+
+// Broker 券商接口（只读）
+type Broker interface {
+	// Name 接口名称
+	Name() string
+	// Positions 查询当前持仓
+	Positions(ctx context.Context) ([]Position, error)
+	// Orders 查询当日委托
+	Orders(ctx context.Context) ([]Order, error)
+}
+
+// New 按配置创建券商接口
+func New(cfg models.BrokerConfig) (Broker, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("未配置券商接口地址")
+	}
+	switch cfg.Type {
+	case TypeQMT, "":
+		return NewQMT(cfg), nil
+	default:
+		return nil, fmt.Errorf("不支持的券商接口类型: %s", cfg.Type)
+	}
+}
+
+// normalizeSymbol 将 600519.SH / SH600519 等格式统一为 sh600519
+func normalizeSymbol(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if num, market, ok := strings.Cut(code, "."); ok {
+		return market + num
+	}
+	if len(code) == 6 {
+		switch code[0] {
+		case '6', '9':
+			return "sh" + code
+		case '4', '8':
+			return "bj" + code
+		default:
+			return "sz" + code
+		}
+	}
+	return code
+}
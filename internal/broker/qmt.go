@@ -0,0 +1,189 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// QMT 委托方向（xtconstant.STOCK_BUY / STOCK_SELL）
+const (
+	qmtStockBuy  = 23
+	qmtStockSell = 24
+)
+
+// QMTBroker 迅投 QMT 接口，通过本地运行的 xtquant HTTP 桥接服务查询
+// 桥接服务约定：GET {baseUrl}/positions、/orders，参数 account_id，
+// 返回 {"code":0,"msg":"","data":[...]}，data 字段与 xtquant 的 XtPosition/XtOrder 一致
+type QMTBroker struct {
+	baseURL   string
+	token     string
+	accountID string
+	client    *http.Client
+}
+
+// NewQMT 创建 QMT 接口
+func NewQMT(cfg models.BrokerConfig) *QMTBroker {
+	return &QMTBroker{
+		baseURL:   strings.TrimRight(cfg.BaseURL, "/"),
+		token:     cfg.Token,
+		accountID: cfg.AccountID,
+		// 桥接服务运行在本机，不走代理
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name 接口名称
+func (b *QMTBroker) Name() string {
+	return "QMT"
+}
+
+// qmtPosition xtquant XtPosition
+type qmtPosition struct {
+	StockCode    string  `json:"stock_code"`
+	StockName    string  `json:"stock_name"` // 桥接服务补充，可能为空
+	Volume       int64   `json:"volume"`
+	CanUseVolume int64   `json:"can_use_volume"`
+	OpenPrice    float64 `json:"open_price"`
+	AvgPrice     float64 `json:"avg_price"`
+	MarketValue  float64 `json:"market_value"`
+}
+
+// qmtOrder xtquant XtOrder
+type qmtOrder struct {
+	OrderID      int64   `json:"order_id"`
+	StockCode    string  `json:"stock_code"`
+	StockName    string  `json:"stock_name"`
+	OrderTime    int64   `json:"order_time"` // 秒级时间戳
+	OrderType    int     `json:"order_type"`
+	OrderVolume  int64   `json:"order_volume"`
+	Price        float64 `json:"price"`
+	TradedVolume int64   `json:"traded_volume"`
+	TradedPrice  float64 `json:"traded_price"`
+	OrderStatus  int     `json:"order_status"`
+	StatusMsg    string  `json:"status_msg"`
+}
+
+// Positions 查询当前持仓
+func (b *QMTBroker) Positions(ctx context.Context) ([]Position, error) {
+	var raw []qmtPosition
+	if err := b.get(ctx, "/positions", &raw); err != nil {
+		return nil, err
+	}
+	positions := make([]Position, 0, len(raw))
+	for _, p := range raw {
+		if p.Volume <= 0 {
+			continue
+		}
+		cost := p.OpenPrice
+		if cost <= 0 {
+			cost = p.AvgPrice
+		}
+		positions = append(positions, Position{
+			Symbol:      normalizeSymbol(p.StockCode),
+			Name:        p.StockName,
+			Shares:      p.Volume,
+			Available:   p.CanUseVolume,
+			CostPrice:   cost,
+			MarketValue: p.MarketValue,
+		})
+	}
+	return positions, nil
+}
+
+// Orders 查询当日委托
+func (b *QMTBroker) Orders(ctx context.Context) ([]Order, error) {
+	var raw []qmtOrder
+	if err := b.get(ctx, "/orders", &raw); err != nil {
+		return nil, err
+	}
+	orders := make([]Order, 0, len(raw))
+	for _, o := range raw {
+		side := "buy"
+		if o.OrderType == qmtStockSell {
+			side = "sell"
+		}
+		orders = append(orders, Order{
+			ID:           strconv.FormatInt(o.OrderID, 10),
+			Symbol:       normalizeSymbol(o.StockCode),
+			Name:         o.StockName,
+			Side:         side,
+			Price:        o.Price,
+			Shares:       o.OrderVolume,
+			FilledShares: o.TradedVolume,
+			FilledPrice:  o.TradedPrice,
+			Status:       qmtOrderStatus(o.OrderStatus),
+			Message:      o.StatusMsg,
+			Time:         o.OrderTime * 1000,
+		})
+	}
+	return orders, nil
+}
+
+// qmtOrderStatus 转换 xtconstant 委托状态（48 未报 ~ 57 废单）
+func qmtOrderStatus(status int) string {
+	switch status {
+	case 53, 54:
+		return OrderCancelled
+	case 55:
+		return OrderPartial
+	case 56:
+		return OrderFilled
+	case 57:
+		return OrderRejected
+	default:
+		return OrderOpen
+	}
+}
+
+// get 请求桥接服务并解析 data 字段
+func (b *QMTBroker) get(ctx context.Context, path string, out any) error {
+	u := b.baseURL + path
+	if b.accountID != "" {
+		u += "?account_id=" + url.QueryEscape(b.accountID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if b.token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.token)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("连接 QMT 桥接服务失败: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("QMT 桥接服务返回 HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Code int             `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("解析 QMT 响应失败: %w", err)
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("QMT 查询失败: %s", result.Msg)
+	}
+	if len(result.Data) == 0 || string(result.Data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(result.Data, out)
+}
@@ -0,0 +1,77 @@
+package broker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestQMTBroker(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.URL.Query().Get("account_id") != "8880001" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/positions":
+			w.Write([]byte(`{"code":0,"data":[
+				{"stock_code":"600519.SH","stock_name":"贵州茅台","volume":200,"can_use_volume":100,"open_price":1480.5,"market_value":300000},
+				{"stock_code":"000001.SZ","volume":0,"can_use_volume":0,"avg_price":11}
+			]}`))
+		case "/orders":
+			w.Write([]byte(`{"code":0,"data":[
+				{"order_id":101,"stock_code":"000001.SZ","order_time":1717380000,"order_type":24,"order_volume":500,"price":11.2,"traded_volume":500,"traded_price":11.2,"order_status":56},
+				{"order_id":102,"stock_code":"430047.BJ","order_type":23,"order_volume":100,"price":8,"order_status":57,"status_msg":"资金不足"}
+			]}`))
+		default:
+			w.Write([]byte(`{"code":-1,"msg":"unknown"}`))
+		}
+	}))
+	defer srv.Close()
+
+	b, err := New(models.BrokerConfig{Type: TypeQMT, BaseURL: srv.URL + "/", Token: "secret", AccountID: "8880001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	positions, err := b.Positions(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(positions) != 1 || positions[0].Symbol != "sh600519" || positions[0].CostPrice != 1480.5 || positions[0].Available != 100 {
+		t.Errorf("unexpected positions: %+v", positions)
+	}
+
+	orders, err := b.Orders(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 2 || orders[0].Side != "sell" || orders[0].Status != OrderFilled || orders[0].Time != 1717380000000 {
+		t.Errorf("unexpected orders: %+v", orders)
+	}
+	if orders[1].Symbol != "bj430047" || orders[1].Status != OrderRejected || orders[1].Message != "资金不足" {
+		t.Errorf("unexpected rejected order: %+v", orders[1])
+	}
+
+	bad, _ := New(models.BrokerConfig{BaseURL: srv.URL, Token: "wrong"})
+	if _, err := bad.Positions(context.Background()); err == nil {
+		t.Error("unauthorized request should fail")
+	}
+}
+
+func TestNormalizeSymbol(t *testing.T) {
+	for in, want := range map[string]string{
+		"600519.SH": "sh600519",
+		"000001.sz": "sz000001",
+		"SH600519":  "sh600519",
+		"300750":    "sz300750",
+		"688981":    "sh688981",
+		"830799":    "bj830799",
+	} {
+		if got := normalizeSymbol(in); got != want {
+			t.Errorf("normalizeSymbol(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
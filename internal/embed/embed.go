@@ -9,3 +9,9 @@ import (
 //
 //go:embed stock_basic.json
 var StockBasicJSON []byte
+
+// HolidaysJSON 嵌入的 A 股休市日历（holiday-cn 格式，按年份组成数组）
+// 交易日历以此为基础，运行时从缓存与 CDN 获取的数据覆盖补充
+//
+//go:embed holidays.json
+var HolidaysJSON []byte
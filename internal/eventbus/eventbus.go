@@ -0,0 +1,129 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Bus 事件总线，屏蔽 Wails 运行时：桌面模式推送到前端，无界面模式推送到 HTTP 订阅者
+type Bus interface {
+	// Emit 发送事件
+	Emit(name string, data ...any)
+	// On 监听客户端发来的事件，返回取消函数
+	On(name string, handler func(data ...any)) func()
+}
+
+// WailsBus 基于 Wails 运行时的事件总线
+type WailsBus struct {
+	ctx context.Context
+}
+
+// NewWailsBus 创建 Wails 事件总线，ctx 须为 Wails OnStartup 传入的上下文
+func NewWailsBus(ctx context.Context) *WailsBus {
+	return &WailsBus{ctx: ctx}
+}
+
+// Emit 发送事件到前端
+func (b *WailsBus) Emit(name string, data ...any) {
+	runtime.EventsEmit(b.ctx, name, data...)
+}
+
+// On 监听前端事件
+func (b *WailsBus) On(name string, handler func(data ...any)) func() {
+	return runtime.EventsOn(b.ctx, name, handler)
+}
+
+// Event 事件内容
+type Event struct {
+	Name string `json:"name"`
+	Data any    `json:"data"`
+}
+
+// subscriberBuffer 每个订阅者的缓冲，消费过慢时丢弃新事件而不阻塞发送方
+const subscriberBuffer = 256
+
+// MemoryBus 内存事件总线：事件扇出给所有订阅者，供无界面模式的 SSE 与测试使用
+type MemoryBus struct {
+	mu       sync.RWMutex
+	subs     map[chan Event]struct{}
+	handlers map[string]map[int]func(data ...any)
+	nextID   int
+}
+
+// NewMemoryBus 创建内存事件总线
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		subs:     make(map[chan Event]struct{}),
+		handlers: make(map[string]map[int]func(data ...any)),
+	}
+}
+
+// Emit 发送事件给所有订阅者与同名监听者
+func (b *MemoryBus) Emit(name string, data ...any) {
+	evt := Event{Name: name}
+	switch len(data) {
+	case 0:
+	case 1:
+		evt.Data = data[0]
+	default:
+		evt.Data = data
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subs {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+	for _, fn := range b.handlers[name] {
+		go fn(data...)
+	}
+}
+
+// Dispatch 将客户端发来的事件交给同名监听者，不广播给订阅者
+func (b *MemoryBus) Dispatch(name string, data ...any) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, fn := range b.handlers[name] {
+		go fn(data...)
+	}
+}
+
+// On 监听事件（如 HTTP 客户端发来的行情订阅）
+func (b *MemoryBus) On(name string, handler func(data ...any)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.handlers[name] == nil {
+		b.handlers[name] = make(map[int]func(data ...any))
+	}
+	id := b.nextID
+	b.nextID++
+	b.handlers[name][id] = handler
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers[name], id)
+	}
+}
+
+// Subscribe 订阅全部事件，返回事件通道与取消函数
+func (b *MemoryBus) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
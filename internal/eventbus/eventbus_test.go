@@ -0,0 +1,58 @@
+package eventbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHub(t *testing.T) {
+	b := NewMemoryBus()
+	ch, cancel := b.Subscribe()
+
+	got := make(chan any, 1)
+	off := b.On("market:subscribe", func(data ...any) { got <- data[0] })
+
+	b.Emit("meeting:message:sh600000", "hello")
+	if evt := <-ch; evt.Name != "meeting:message:sh600000" || evt.Data != "hello" {
+		t.Errorf("unexpected event: %+v", evt)
+	}
+
+	// 客户端事件只交给监听者，不广播
+	b.Dispatch("market:subscribe", []any{"sh600000"})
+	select {
+	case data := <-got:
+		if codes, ok := data.([]any); !ok || codes[0] != "sh600000" {
+			t.Errorf("unexpected handler data: %v", data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler not called")
+	}
+	select {
+	case evt := <-ch:
+		t.Errorf("dispatch should not broadcast: %+v", evt)
+	default:
+	}
+
+	off()
+	b.Dispatch("market:subscribe", []any{"sz000001"})
+	cancel()
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Error("channel should be closed after cancel")
+	}
+	select {
+	case data := <-got:
+		t.Errorf("removed handler called: %v", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// 订阅者消费过慢时不阻塞发送方
+	slow, cancelSlow := b.Subscribe()
+	defer cancelSlow()
+	for i := 0; i < subscriberBuffer+10; i++ {
+		b.Emit("market:stock:update", i)
+	}
+	if len(slow) != subscriberBuffer {
+		t.Errorf("buffered %d events", len(slow))
+	}
+}
@@ -0,0 +1,304 @@
+package headless
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/eventbus"
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+var log = logger.New("headless")
+
+// maxAlerts 保留的最近提醒条数
+const maxAlerts = 100
+
+// sseKeepAlive SSE 心跳间隔，避免反向代理断开空闲连接
+const sseKeepAlive = 20 * time.Second
+
+// alertEvents 记入提醒列表的事件
+var alertEvents = map[string]bool{
+	"tradeplan:alert":     true,
+	"notification":        true,
+	"paper:order:pending": true,
+}
+
+// MeetingRequest 会议发言请求
+type MeetingRequest struct {
+	StockCode  string   `json:"stockCode"`
+	Content    string   `json:"content"`
+	MentionIds []string `json:"mentionIds"`
+}
+
+// Backend 无界面模式下对外暴露的引擎能力
+type Backend interface {
+	Watchlist() []models.Stock
+	Quotes(codes []string) []models.Stock
+	Messages(stockCode string) []models.ChatMessage
+	// StartMeeting 异步开始会议，过程消息通过事件推送
+	StartMeeting(req MeetingRequest) error
+	CancelMeeting(stockCode string) bool
+}
+
+// Server 无界面模式 HTTP + SSE 服务
+type Server struct {
+	backend Backend
+	bus     *eventbus.MemoryBus
+	token   string
+	files   http.Handler // 图表等静态文件，可为 nil
+
+	mu     sync.Mutex
+	server *http.Server
+	cancel func()
+	alerts []eventbus.Event
+}
+
+// NewServer 创建服务，token 为空时不校验（仅限本机调试）
+func NewServer(backend Backend, bus *eventbus.MemoryBus, token string, files http.Handler) *Server {
+	return &Server{backend: backend, bus: bus, token: token, files: files}
+}
+
+// Handler 返回路由
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+	})
+	mux.HandleFunc("GET /api/watchlist", s.withAuth(s.handleWatchlist))
+	mux.HandleFunc("GET /api/quotes", s.withAuth(s.handleQuotes))
+	mux.HandleFunc("GET /api/alerts", s.withAuth(s.handleAlerts))
+	mux.HandleFunc("GET /api/meetings/{code}/messages", s.withAuth(s.handleMessages))
+	mux.HandleFunc("POST /api/meetings/{code}/messages", s.withAuth(s.handleSendMessage))
+	mux.HandleFunc("POST /api/meetings/{code}/cancel", s.withAuth(s.handleCancelMeeting))
+	mux.HandleFunc("POST /api/events/{name}", s.withAuth(s.handleClientEvent))
+	mux.HandleFunc("GET /events", s.withAuth(s.handleEvents))
+	if s.files != nil {
+		mux.Handle("GET /charts/", s.withAuth(s.files.ServeHTTP))
+	}
+	return mux
+}
+
+// Start 监听地址并启动服务
+func (s *Server) Start(addr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.server != nil {
+		return fmt.Errorf("服务已在运行")
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("监听 %s 失败: %w", addr, err)
+	}
+
+	ch, unsubscribe := s.bus.Subscribe()
+	go s.collectAlerts(ch)
+
+	// 请求上下文派生自 baseCtx，停止时先取消以结束 SSE 长连接
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	s.cancel = func() {
+		cancelBase()
+		unsubscribe()
+	}
+	s.server = &http.Server{
+		Handler:     s.Handler(),
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
+	}
+	go func() {
+		log.Info("无界面服务启动于 %s", ln.Addr())
+		if err := s.server.Serve(ln); err != http.ErrServerClosed {
+			log.Error("服务异常: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Stop 停止服务
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.server == nil {
+		return nil
+	}
+	s.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := s.server.Shutdown(ctx)
+	s.server = nil
+	log.Info("无界面服务已停止")
+	return err
+}
+
+// collectAlerts 记录最近的提醒，供客户端上线后补拉
+func (s *Server) collectAlerts(ch <-chan eventbus.Event) {
+	for evt := range ch {
+		if !alertEvents[evt.Name] {
+			continue
+		}
+		s.mu.Lock()
+		s.alerts = append(s.alerts, evt)
+		if len(s.alerts) > maxAlerts {
+			s.alerts = s.alerts[len(s.alerts)-maxAlerts:]
+		}
+		s.mu.Unlock()
+	}
+}
+
+// withAuth 鉴权中间件：Authorization: Bearer <token>，或 ?token=（浏览器 EventSource 无法设置请求头）
+func (s *Server) withAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token == "" {
+				token = r.URL.Query().Get("token")
+			}
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+				writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "unauthorized"})
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleWatchlist(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.backend.Watchlist())
+}
+
+func (s *Server) handleQuotes(w http.ResponseWriter, r *http.Request) {
+	var codes []string
+	for _, c := range strings.Split(r.URL.Query().Get("codes"), ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			codes = append(codes, c)
+		}
+	}
+	if len(codes) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "codes required"})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.backend.Quotes(codes))
+}
+
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	alerts := make([]eventbus.Event, len(s.alerts))
+	copy(alerts, s.alerts)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, alerts)
+}
+
+func (s *Server) handleMessages(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.backend.Messages(r.PathValue("code")))
+}
+
+func (s *Server) handleSendMessage(w http.ResponseWriter, r *http.Request) {
+	var req MeetingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid request body"})
+		return
+	}
+	req.StockCode = r.PathValue("code")
+	if strings.TrimSpace(req.Content) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "content required"})
+		return
+	}
+	if err := s.backend.StartMeeting(req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"success": true})
+}
+
+func (s *Server) handleCancelMeeting(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"success": s.backend.CancelMeeting(r.PathValue("code"))})
+}
+
+// handleClientEvent 转发客户端事件（如 market:subscribe 订阅行情），载荷为 JSON 值
+func (s *Server) handleClientEvent(w http.ResponseWriter, r *http.Request) {
+	var data any
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid request body"})
+		return
+	}
+	s.bus.Dispatch(r.PathValue("name"), data)
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+// handleEvents SSE 事件流，?prefix=meeting:,market:stock 按事件名前缀过滤
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "streaming unsupported"})
+		return
+	}
+	var prefixes []string
+	for _, p := range strings.Split(r.URL.Query().Get("prefix"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			prefixes = append(prefixes, p)
+		}
+	}
+
+	ch, cancel := s.bus.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case evt, ok := <-ch:
+			if !ok {
+				return
+			}
+			if !matchPrefix(evt.Name, prefixes) {
+				continue
+			}
+			data, err := json.Marshal(evt.Data)
+			if err != nil {
+				log.Warn("事件序列化失败: %s, %v", evt.Name, err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", evt.Name, data)
+			flusher.Flush()
+		}
+	}
+}
+
+// matchPrefix 事件名是否匹配任一前缀，未指定前缀时全部匹配
+func matchPrefix(name string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, code int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,150 @@
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/eventbus"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+type fakeBackend struct {
+	started chan MeetingRequest
+}
+
+func (f *fakeBackend) Watchlist() []models.Stock { return []models.Stock{{Symbol: "sh600000"}} }
+func (f *fakeBackend) Quotes(codes []string) []models.Stock {
+	stocks := make([]models.Stock, len(codes))
+	for i, c := range codes {
+		stocks[i] = models.Stock{Symbol: c, Price: 10}
+	}
+	return stocks
+}
+func (f *fakeBackend) Messages(stockCode string) []models.ChatMessage { return nil }
+func (f *fakeBackend) StartMeeting(req MeetingRequest) error {
+	f.started <- req
+	return nil
+}
+func (f *fakeBackend) CancelMeeting(stockCode string) bool { return true }
+
+func TestServerAuth(t *testing.T) {
+	s := NewServer(&fakeBackend{}, eventbus.NewMemoryBus(), "secret", nil)
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	cases := []struct {
+		path, auth string
+		want       int
+	}{
+		{"/health", "", http.StatusOK},
+		{"/api/watchlist", "", http.StatusUnauthorized},
+		{"/api/watchlist", "Bearer wrong", http.StatusUnauthorized},
+		{"/api/watchlist", "Bearer secret", http.StatusOK},
+		{"/api/watchlist?token=secret", "", http.StatusOK},
+		{"/api/quotes", "Bearer secret", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+c.path, nil)
+		if c.auth != "" {
+			req.Header.Set("Authorization", c.auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.want {
+			t.Errorf("%s (%q) = %d, want %d", c.path, c.auth, resp.StatusCode, c.want)
+		}
+	}
+
+	resp, err := http.Get(ts.URL + "/api/quotes?codes=sh600000,+sz000001&token=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var stocks []models.Stock
+	json.NewDecoder(resp.Body).Decode(&stocks)
+	if len(stocks) != 2 || stocks[1].Symbol != "sz000001" {
+		t.Errorf("unexpected quotes: %+v", stocks)
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	bus := eventbus.NewMemoryBus()
+	backend := &fakeBackend{started: make(chan MeetingRequest, 1)}
+	s := NewServer(backend, bus, "", nil)
+	if err := s.Start("127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+	defer s.Stop()
+
+	resp, err := http.Get(ts.URL + "/events?prefix=meeting:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type: %s", ct)
+	}
+
+	// 会议发言异步开始
+	post, err := http.Post(ts.URL+"/api/meetings/sh600000/messages", "application/json", strings.NewReader(`{"content":"能买吗"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.Body.Close()
+	if post.StatusCode != http.StatusAccepted {
+		t.Errorf("send message status: %d", post.StatusCode)
+	}
+	if req := <-backend.started; req.StockCode != "sh600000" || req.Content != "能买吗" {
+		t.Errorf("unexpected meeting request: %+v", req)
+	}
+
+	bus.Emit("market:stock:update", "filtered")
+	bus.Emit("meeting:message:sh600000", models.ChatMessage{Content: "可以关注"})
+	bus.Emit("tradeplan:alert", map[string]string{"stockCode": "sh600000"})
+
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+	var got []string
+	timeout := time.After(2 * time.Second)
+	for len(got) < 2 {
+		select {
+		case line := <-lines:
+			if line != "" {
+				got = append(got, line)
+			}
+		case <-timeout:
+			t.Fatalf("timed out, got %v", got)
+		}
+	}
+	if got[0] != "event: meeting:message:sh600000" || !strings.Contains(got[1], "可以关注") {
+		t.Errorf("unexpected sse frames: %v", got)
+	}
+
+	// 提醒事件记入列表，供补拉
+	time.Sleep(50 * time.Millisecond)
+	resp2, err := http.Get(ts.URL + "/api/alerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp2.Body.Close()
+	var alerts []eventbus.Event
+	json.NewDecoder(resp2.Body).Decode(&alerts)
+	if len(alerts) != 1 || alerts[0].Name != "tradeplan:alert" {
+		t.Errorf("unexpected alerts: %+v", alerts)
+	}
+}
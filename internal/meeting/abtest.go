@@ -0,0 +1,69 @@
+package meeting
+
+import (
+	"context"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// ABTestLabels A/B 对比中两个变体的标签
+var ABTestLabels = [2]string{"A", "B"}
+
+// RunABTest 将同一问题并行交给专家的两个变体（不同提示词或模型）作答
+// 回复按 A、B 顺序返回并标注 Variant，供老韭菜盲评哪个更好
+func (s *Service) RunABTest(ctx context.Context, defaultAIConfig *models.AIConfig, req ChatRequest, variants [2]models.AgentConfig) ([]ChatResponse, error) {
+	if defaultAIConfig == nil {
+		return nil, ErrNoAIConfig
+	}
+	run := s.newRun(defaultAIConfig)
+
+	abCtx, cancel := context.WithTimeout(ctx, MeetingTimeout)
+	defer cancel()
+
+	replyContent := req.ReplyContent
+	if run.settings.userProfile != "" {
+		replyContent = run.settings.userProfile + "\n" + replyContent
+	}
+
+	responses := make([]ChatResponse, len(variants))
+	var wg sync.WaitGroup
+	for i := range variants {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cfg := &variants[i]
+			agentAIConfig := s.resolveAgentAIConfig(cfg, defaultAIConfig)
+
+			recorder := newToolRecorder(cfg.ID)
+			content, result, err := s.runAgentWithFailover(abCtx, cfg, agentAIConfig, run.tracker, nil,
+				func(agentCtx context.Context, builder *adk.ExpertAgentBuilder) (string, error) {
+					return s.runSingleAgent(agentCtx, builder, cfg, &req.Stock, req.Query, replyContent, nil, req.Position, recorder)
+				})
+
+			resp := ChatResponse{
+				AgentID:     cfg.ID,
+				AgentName:   cfg.Name,
+				Role:        cfg.Role,
+				MsgType:     "opinion",
+				MeetingMode: MeetingModeABTest,
+				Variant:     ABTestLabels[i],
+			}
+			if err != nil {
+				log.Error("ab test variant %s of %s failed: %v", ABTestLabels[i], cfg.ID, err)
+				resp.setError(err)
+			} else {
+				resp.Content = content
+				resp.AnsweredBy = answeredByLabel(agentAIConfig, result.answeredBy)
+				resp.ToolCalls = recorder.records()
+			}
+			result.apply(&resp, recorder)
+			responses[i] = resp
+		}(i)
+	}
+	wg.Wait()
+
+	log.Info("ab test of %s done, cost: %s", variants[0].ID, run.tracker.Summary())
+	return run.stamp(responses), nil
+}
@@ -0,0 +1,75 @@
+package meeting
+
+import (
+	"context"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// BriefingTimeout 单只股票早报生成超时
+const BriefingTimeout = ModeratorTimeout
+
+// briefingTools 早报可调用的工具（资讯与盘前数据，只读）
+var briefingTools = []string{"get_stock_news", "get_news", "get_stock_realtime", "get_kline_data", "get_events"}
+
+// briefingQuery 早报任务描述（同时用于检索记忆）
+const briefingQuery = "请生成今日开盘前的个股早报"
+
+// moderatorBriefingInstruction 小韭菜生成盘前早报的指令
+const moderatorBriefingInstruction = `你是「财经会议室」的小韭菜，负责在开盘前为老韭菜准备自选股早报，不召集专家讨论。
+请调用工具查询个股隔夜新闻与公告、最新财经快讯中与该股或所属行业相关的内容、昨日行情与近期K线、近期事件日历，并结合上次会议的记忆，按以下结构输出：
+1. 隔夜要闻：与该股直接相关的新闻、公告、快讯，没有则写「无重要消息」
+2. 盘前关注：昨日收盘表现、关键支撑/压力位、今日可能的催化事件
+3. 延续上次会议：上次结论是否仍然成立，今天需要验证什么；没有会议记忆则省略本项
+每项一到两句，总字数200字以内，只陈述事实与关注点，不给出具体买卖指令。`
+
+// briefingAgentConfig 早报使用的 Agent 配置
+func briefingAgentConfig() models.AgentConfig {
+	return models.AgentConfig{
+		ID:          "moderator",
+		Name:        "小韭菜",
+		Role:        "会议主持",
+		Instruction: moderatorBriefingInstruction,
+		Tools:       briefingTools,
+		Enabled:     true,
+	}
+}
+
+// GenerateBriefing 为单只股票生成盘前早报：小韭菜调用资讯类工具并结合会议记忆，不召集专家
+// aiConfig 建议使用会议总结路由的便宜模型；与会议共用排队名额
+func (s *Service) GenerateBriefing(ctx context.Context, aiConfig *models.AIConfig, stock models.Stock, position *models.StockPosition, priority Priority) (string, error) {
+	if aiConfig == nil {
+		return "", ErrNoAIConfig
+	}
+	release, err := s.waitTurn(ctx, priority, nil)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	modelCtx, modelCancel := context.WithTimeout(ctx, ModelCreationTimeout)
+	llm, err := s.createModel(modelCtx, aiConfig, nil)
+	modelCancel()
+	if err != nil {
+		return "", err
+	}
+
+	var memoryContext string
+	if s.memoryManager != nil {
+		if stockMemory, err := s.memoryManager.GetOrCreate(stock.Symbol, stock.Name); err == nil {
+			budget := newContextBudgeter(s.settings().context, aiConfig)
+			memoryContext = budget.memory(ctx, s.memoryManager.BuildContext(stockMemory, briefingQuery))
+		}
+	}
+	if userProfile := s.settings().userProfile; userProfile != "" {
+		memoryContext = userProfile + "\n" + memoryContext
+	}
+
+	cfg := briefingAgentConfig()
+	builder := s.createBuilder(llm, aiConfig)
+	return retryRun(ctx, MaxAgentRetries, func() (string, error) {
+		briefCtx, cancel := context.WithTimeout(ctx, BriefingTimeout)
+		defer cancel()
+		return s.runSingleAgent(briefCtx, builder, &cfg, &stock, briefingQuery, memoryContext, nil, position, nil)
+	})
+}
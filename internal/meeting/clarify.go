@@ -0,0 +1,66 @@
+package meeting
+
+import (
+	"sync"
+	"time"
+)
+
+// ClarificationTTL 追问等待回答的有效期，超时后下一条消息视为新问题
+const ClarificationTTL = 10 * time.Minute
+
+// pendingClarification 等待老韭菜回答的追问
+type pendingClarification struct {
+	query     string // 原始问题
+	question  string // 小韭菜的追问
+	createdAt time.Time
+}
+
+// clarificationStore 追问状态缓存，key: stockCode
+type clarificationStore struct {
+	mu      sync.Mutex
+	pending map[string]pendingClarification
+}
+
+func newClarificationStore() *clarificationStore {
+	return &clarificationStore{pending: make(map[string]pendingClarification)}
+}
+
+// Set 记录一次追问
+func (c *clarificationStore) Set(stockCode, query, question string) {
+	if stockCode == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending[stockCode] = pendingClarification{query: query, question: question, createdAt: time.Now()}
+}
+
+// Resume 若该股票有未过期的追问，将回答合并进原始问题并清除追问状态
+func (c *clarificationStore) Resume(stockCode, answer string) (string, bool) {
+	if stockCode == "" {
+		return answer, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, ok := c.pending[stockCode]
+	if !ok {
+		return answer, false
+	}
+	delete(c.pending, stockCode)
+	if time.Since(p.createdAt) > ClarificationTTL {
+		return answer, false
+	}
+	return p.query + "\n【补充说明】" + p.question + " " + answer, true
+}
+
+// Clear 清除追问状态
+func (c *clarificationStore) Clear(stockCode string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, stockCode)
+}
+
+// CancelClarification 放弃回答追问（清空聊天记录时调用）
+func (s *Service) CancelClarification(stockCode string) {
+	s.clarifications.Clear(stockCode)
+}
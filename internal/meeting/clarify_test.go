@@ -0,0 +1,157 @@
+package meeting
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/fixture"
+)
+
+func TestClarificationStore(t *testing.T) {
+	tests := []struct {
+		name      string
+		setCode   string
+		age       time.Duration // 追问已等待的时长
+		clear     bool
+		wantQuery string
+		wantOK    bool
+	}{
+		{name: "resumed", setCode: "sh600519", wantQuery: "茅台怎么看？\n【补充说明】你关注短线还是长线？ 长线", wantOK: true},
+		{name: "no pending", wantQuery: "长线"},
+		{name: "other stock", setCode: "sz000001", wantQuery: "长线"},
+		{name: "expired", setCode: "sh600519", age: ClarificationTTL + time.Minute, wantQuery: "长线"},
+		{name: "cleared", setCode: "sh600519", clear: true, wantQuery: "长线"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClarificationStore()
+			if tt.setCode != "" {
+				c.Set(tt.setCode, "茅台怎么看？", "你关注短线还是长线？")
+				p := c.pending[tt.setCode]
+				p.createdAt = p.createdAt.Add(-tt.age)
+				c.pending[tt.setCode] = p
+			}
+			if tt.clear {
+				c.Clear(tt.setCode)
+			}
+
+			query, ok := c.Resume("sh600519", "长线")
+			if query != tt.wantQuery || ok != tt.wantOK {
+				t.Errorf("Resume = %q, %v, want %q, %v", query, ok, tt.wantQuery, tt.wantOK)
+			}
+			// 追问只能被回答一次
+			if _, ok := c.Resume("sh600519", "长线"); ok {
+				t.Error("clarification should be consumed after resume")
+			}
+		})
+	}
+
+	// 未指定股票时不记录追问
+	c := newClarificationStore()
+	c.Set("", "怎么看？", "哪只股票？")
+	if len(c.pending) != 0 {
+		t.Errorf("pending = %v, want empty", c.pending)
+	}
+}
+
+func TestRunSmartMeetingClarify(t *testing.T) {
+	agents := []models.AgentConfig{
+		{ID: "tech", Name: "技术派", Role: "技术分析师", Instruction: "你是技术分析师，专注K线形态。", Enabled: true},
+		{ID: "value", Name: "价值派", Role: "基本面分析师", Instruction: "你是基本面分析师，专注估值。", Enabled: true},
+	}
+	const (
+		clarifyDecision = `{"intent":"走势研判","clarify":"你关注短线还是长线？"}`
+		meetingDecision = `{"intent":"走势研判","selected":["tech","value"],"topic":"茅台后市","opening":"请两位专家谈谈茅台长线。"}`
+	)
+	// 合并了追问回答的问题给出正常决策，其余问题小韭菜都要求追问
+	newLLM := func() *fixture.LLM {
+		return fixture.NewLLM("暂无观点。").
+			On("请判断是否需要追加邀请", `{"invite":[]}`).
+			On("请总结讨论", testSummary).
+			On("专注K线形态", "均线多头排列。\n"+models.VerdictMarker+"看多，信心 7/10").
+			On("专注估值", "估值处于历史中枢。\n"+models.VerdictMarker+"中性，信心 5/10").
+			On("你关注短线还是长线？ 长线", meetingDecision).
+			On("负责组织专家讨论", clarifyDecision)
+	}
+
+	tests := []struct {
+		name        string
+		pending     bool // 会议前已有未回答的追问
+		query       string
+		mentions    []string
+		wantTypes   string
+		wantPending bool
+		wantPrompt  string // 小韭菜收到的问题
+	}{
+		{
+			name:        "triggered",
+			query:       "茅台怎么看？",
+			wantTypes:   "clarify:moderator",
+			wantPending: true,
+		},
+		{
+			name:      "skipped when experts mentioned",
+			query:     "@技术派 茅台怎么看？",
+			mentions:  []string{"tech"},
+			wantTypes: "opinion:tech,summary:moderator",
+		},
+		{
+			name:       "answer resumed",
+			pending:    true,
+			query:      "长线",
+			wantTypes:  "opening:moderator,opinion:tech,opinion:value,summary:moderator",
+			wantPrompt: "茅台怎么看？\n【补充说明】你关注短线还是长线？ 长线",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := newLLM()
+			s := newFixtureService(llm)
+			if tt.pending {
+				s.clarifications.Set("sh600519", "茅台怎么看？", "你关注短线还是长线？")
+			}
+
+			responses, err := s.RunSmartMeeting(context.Background(), &models.AIConfig{Provider: models.AIProviderOpenAI, ModelName: "fixture"}, ChatRequest{
+				StockCode: "sh600519",
+				Stock:     models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1495.20},
+				Query:     tt.query,
+				Mentions:  tt.mentions,
+				AllAgents: agents,
+			})
+			if err != nil {
+				t.Fatalf("RunSmartMeeting: %v", err)
+			}
+
+			var types []string
+			for _, r := range responses {
+				types = append(types, r.MsgType+":"+r.AgentID)
+			}
+			if got := strings.Join(types, ","); got != tt.wantTypes {
+				t.Errorf("responses = %s, want %s", got, tt.wantTypes)
+			}
+			if tt.wantTypes == "clarify:moderator" && responses[0].Content != "你关注短线还是长线？" {
+				t.Errorf("clarify content = %q", responses[0].Content)
+			}
+
+			s.clarifications.mu.Lock()
+			_, pending := s.clarifications.pending["sh600519"]
+			s.clarifications.mu.Unlock()
+			if pending != tt.wantPending {
+				t.Errorf("pending = %v, want %v", pending, tt.wantPending)
+			}
+
+			if tt.wantPrompt != "" {
+				var found bool
+				for _, p := range llm.Prompts() {
+					found = found || (strings.Contains(p, "负责组织专家讨论") && strings.Contains(p, tt.wantPrompt))
+				}
+				if !found {
+					t.Errorf("moderator prompt missing resumed query %q", tt.wantPrompt)
+				}
+			}
+		})
+	}
+}
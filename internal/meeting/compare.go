@@ -0,0 +1,255 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// compareSeparator 对比问题中的分隔词，如「茅台 vs 五粮液」「茅台PK五粮液」
+var compareSeparator = regexp.MustCompile(`(?i)\s*(?:vs\.?|pk|对比|对决|和|与|还是)\s*`)
+
+// compareKeywordRegex 股票名称/代码片段
+var compareKeywordRegex = regexp.MustCompile(`[\p{Han}A-Za-z0-9*]+`)
+
+// compareHintRegex 只有带明确对比意图时才按「和/与/还是」拆分，避免误判普通问题
+var compareHintRegex = regexp.MustCompile(`(?i)vs|pk|对比|对决|怎么选|选哪|哪个好|哪只|买哪`)
+
+// ParseCompareQuery 从「茅台 vs 五粮液 怎么选」这类问题中提取两侧的股票关键词
+// 右侧关键词可能带有后续问句（如「五粮液怎么选」），调用方需按前缀逐步缩短匹配
+func ParseCompareQuery(query string) (left, right string, ok bool) {
+	if !compareHintRegex.MatchString(query) {
+		return "", "", false
+	}
+	// 「对比茅台和五粮液」以首个左侧有关键词的分隔词为准
+	for _, loc := range compareSeparator.FindAllStringIndex(query, -1) {
+		leftTokens := compareKeywordRegex.FindAllString(query[:loc[0]], -1)
+		rightToken := compareKeywordRegex.FindString(query[loc[1]:])
+		if len(leftTokens) > 0 && rightToken != "" {
+			return leftTokens[len(leftTokens)-1], rightToken, true
+		}
+	}
+	return "", "", false
+}
+
+// AnalyzeCompare 双股对比：选择专家并拆解对比任务（不追问、不直答）
+func (m *Moderator) AnalyzeCompare(ctx context.Context, a, b *models.Stock, query string, agents []models.AgentConfig) (*ModeratorDecision, error) {
+	prompt := m.buildCompareAnalyzePrompt(a, b, query, agents)
+	content, err := m.generate(ctx, m.llm, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("moderator analyze error: %w", err)
+	}
+	return m.parseDecision(content)
+}
+
+// SummarizeCompare 双股对比总结，输出并列裁决表
+func (m *Moderator) SummarizeCompare(ctx context.Context, a, b *models.Stock, query string, history []DiscussionEntry) (string, error) {
+	prompt := m.buildCompareSummarizePrompt(a, b, query, history)
+	llm := m.summaryLLM
+	if llm == nil {
+		llm = m.llm
+	}
+	return m.generate(ctx, llm, prompt)
+}
+
+// buildCompareAnalyzePrompt 构建双股对比的意图分析 Prompt
+func (m *Moderator) buildCompareAnalyzePrompt(a, b *models.Stock, query string, agents []models.AgentConfig) string {
+	var sb strings.Builder
+	sb.WriteString("你是「财经会议室」的小韭菜，老韭菜想在两只股票之间做选择。\n\n")
+	sb.WriteString("## 对比股票\n")
+	fmt.Fprintf(&sb, "A: %s (%s)，现价 %.2f，涨跌幅 %.2f%%\n", a.Name, a.Symbol, a.Price, a.ChangePercent)
+	fmt.Fprintf(&sb, "B: %s (%s)，现价 %.2f，涨跌幅 %.2f%%\n\n", b.Name, b.Symbol, b.Price, b.ChangePercent)
+	sb.WriteString("## 老韭菜问题\n")
+	sb.WriteString(query + "\n\n")
+	if m.profile != "" {
+		sb.WriteString(m.profile + "\n")
+	}
+	sb.WriteString("## 可邀请的专家\n")
+	for _, ag := range agents {
+		fmt.Fprintf(&sb, "- %s（ID: %s）：%s\n", ag.Name, ag.ID, ag.Role)
+	}
+	sb.WriteString("\n## 你的任务\n")
+	fmt.Fprintf(&sb, "1. 选择 2-%d 位专家，覆盖估值、基本面、技术面等不同维度\n", max(2, len(agents)))
+	sb.WriteString("2. 为每位专家制定一个对比任务，明确要求同时分析 A、B 两只股票并在其专业维度给出孰优孰劣\n")
+	sb.WriteString("3. 生成讨论议题和开场白\n\n")
+	sb.WriteString("## 输出格式（仅输出JSON）\n")
+	sb.WriteString(`{"intent":"意图","selected":["id1","id2"],"tasks":{"id1":"对比任务","id2":"对比任务"},"topic":"议题","opening":"开场白"}`)
+	return sb.String()
+}
+
+// buildCompareSummarizePrompt 构建双股对比总结 Prompt
+func (m *Moderator) buildCompareSummarizePrompt(a, b *models.Stock, query string, history []DiscussionEntry) string {
+	var sb strings.Builder
+	sb.WriteString("你是会议小韭菜，请总结两只股票的对比讨论并给老韭菜结论。\n\n")
+	fmt.Fprintf(&sb, "## 对比股票\nA: %s (%s)\nB: %s (%s)\n\n", a.Name, a.Symbol, b.Name, b.Symbol)
+	sb.WriteString("## 老韭菜问题\n")
+	sb.WriteString(query + "\n\n")
+	if m.profile != "" {
+		sb.WriteString(m.profile + "\n")
+	}
+	sb.WriteString("## 讨论记录\n")
+	for _, e := range history {
+		fmt.Fprintf(&sb, "【%s（%s）】\n%s\n\n", e.AgentName, e.Role, e.Content)
+	}
+	sb.WriteString("## 输出要求\n")
+	sb.WriteString("1. 一句话核心结论：更推荐哪一只及理由\n")
+	fmt.Fprintf(&sb, "2. Markdown 并列裁决表，表头为 | 维度 | %s | %s | 胜出 |，", a.Name, b.Name)
+	sb.WriteString("按讨论涉及的维度（如估值、成长、盈利质量、技术面、资金面、风险）逐行对比，最后一行为综合结论\n")
+	sb.WriteString("3. 适合各自的投资者类型或操作建议\n\n")
+	sb.WriteString("表格以外的文字控制在 200 字以内。")
+	return sb.String()
+}
+
+// runCompareMeeting 双股对比会议：专家串行对比分析，小韭菜输出并列裁决表
+// 专家失败时跳过继续；对比结论不写入单只股票记忆
+func (s *Service) runCompareMeeting(ctx context.Context, run *meetingRun, aiConfig *models.AIConfig, req ChatRequest, respCallback ResponseCallback, progressCallback ProgressCallback) ([]ChatResponse, error) {
+	stockA, stockB := &req.Stock, req.CompareStock
+
+	meetingCtx, meetingCancel := context.WithTimeout(ctx, MeetingTimeout)
+	defer meetingCancel()
+
+	costTracker := run.tracker
+
+	modelCtx, modelCancel := context.WithTimeout(meetingCtx, ModelCreationTimeout)
+	llm, err := s.createModel(modelCtx, aiConfig, costTracker)
+	modelCancel()
+	if err != nil {
+		return nil, fmt.Errorf("create model error: %w", err)
+	}
+	moderator := s.createModerator(meetingCtx, run, llm)
+
+	emit := func(resp ChatResponse) ChatResponse {
+		resp.MeetingMode = MeetingModeCompare
+		if respCallback != nil {
+			respCallback(resp)
+		}
+		return resp
+	}
+
+	log.Info("compare: %s vs %s, query: %s, agents: %d", stockA.Symbol, stockB.Symbol, req.Query, len(req.AllAgents))
+
+	// 第0轮：小韭菜选择专家并拆解对比任务
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "拆解对比维度",
+	})
+	moderatorCtx, moderatorCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
+	decision, err := moderator.AnalyzeCompare(moderatorCtx, stockA, stockB, req.Query, req.AllAgents)
+	moderatorCancel()
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜",
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: 小韭菜分析超时", ErrModeratorTimeout)
+		}
+		return nil, fmt.Errorf("moderator analyze error: %w", err)
+	}
+
+	responses := []ChatResponse{emit(ChatResponse{
+		AgentID: "moderator", AgentName: "小韭菜", Role: "会议主持",
+		Content: decision.Opening, Round: 0, MsgType: "opening",
+	})}
+
+	selectedAgents := s.filterAgentsOrdered(req.AllAgents, decision.Selected)
+	selectedAgents = s.includeMentioned(selectedAgents, req.AllAgents, req.Mentions)
+	if len(selectedAgents) == 0 {
+		return responses, nil
+	}
+
+	// 第1轮：专家串行对比发言
+	var history []DiscussionEntry
+	for i, agentCfg := range selectedAgents {
+		if meetingCtx.Err() != nil {
+			log.Warn("compare meeting timeout, got %d/%d agents", i, len(selectedAgents))
+			return responses, ErrMeetingTimeout
+		}
+		if s.checkBudget(costTracker, progressCallback) {
+			break
+		}
+
+		agentAIConfig := s.resolveAgentAIConfig(&agentCfg, aiConfig)
+		emitProgress(progressCallback, ProgressEvent{
+			Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: agentCfg.Role,
+		})
+
+		agentQuery := req.Query
+		if task := decision.Tasks[agentCfg.ID]; task != "" {
+			agentQuery = task
+		}
+		previousContext := run.budget.previousContext(meetingCtx, history)
+		if run.settings.userProfile != "" {
+			previousContext = run.settings.userProfile + "\n" + previousContext
+		}
+
+		recorder := newToolRecorder(agentCfg.ID)
+		content, result, err := s.runAgentWithFailover(meetingCtx, &agentCfg, agentAIConfig, costTracker, progressCallback,
+			func(agentCtx context.Context, builder *adk.ExpertAgentBuilder) (string, error) {
+				builder.SetCompareStock(stockB)
+				return s.runSingleAgent(agentCtx, builder, &agentCfg, stockA, agentQuery, previousContext, progressCallback, req.Position, recorder)
+			})
+		emitProgress(progressCallback, ProgressEvent{
+			Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
+		})
+
+		if err != nil {
+			log.Error("compare agent %s failed, skip: %v", agentCfg.ID, err)
+			failedResp := ChatResponse{
+				AgentID: agentCfg.ID, AgentName: agentCfg.Name, Role: agentCfg.Role,
+				Round: 1, MsgType: "opinion",
+			}
+			failedResp.setError(err)
+			result.apply(&failedResp, recorder)
+			responses = append(responses, emit(failedResp))
+			continue
+		}
+		emitCostUpdate(progressCallback, costTracker)
+
+		resp := ChatResponse{
+			AgentID: agentCfg.ID, AgentName: agentCfg.Name, Role: agentCfg.Role,
+			Content: content, Round: 1, MsgType: "opinion",
+			AnsweredBy: answeredByLabel(agentAIConfig, result.answeredBy),
+			ToolCalls:  recorder.records(),
+		}
+		result.apply(&resp, recorder)
+		responses = append(responses, emit(resp))
+		history = append(history, DiscussionEntry{
+			Round: 1, AgentID: agentCfg.ID, AgentName: agentCfg.Name,
+			Role: agentCfg.Role, Content: content,
+		})
+	}
+
+	if len(history) == 0 {
+		return responses, nil
+	}
+
+	// 最终轮：小韭菜输出并列裁决表
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "汇总对比结论",
+	})
+	summaryCtx, summaryCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
+	summary, err := moderator.SummarizeCompare(summaryCtx, stockA, stockB, req.Query, history)
+	summaryCancel()
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜",
+	})
+	if err != nil {
+		// 总结失败不影响返回已有结果
+		log.Error("compare summary error: %v", err)
+		return responses, nil
+	}
+
+	if summary != "" {
+		responses = append(responses, emit(ChatResponse{
+			AgentID: "moderator", AgentName: "小韭菜", Role: "会议主持",
+			Content: summary, Round: 2, MsgType: "summary",
+		}))
+	}
+	emitCostUpdate(progressCallback, costTracker)
+	log.Info("compare meeting done for %s vs %s, cost: %s", stockA.Symbol, stockB.Symbol, costTracker.Summary())
+	return responses, nil
+}
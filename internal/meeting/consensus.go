@@ -0,0 +1,50 @@
+package meeting
+
+import "github.com/run-bigpig/jcp/internal/models"
+
+// VerdictWeigher 按专家历史准确率返回其观点权重
+type VerdictWeigher func(agentID string) float64
+
+// SetVerdictWeigher 设置专家观点权重函数，未设置时各专家权重均为 1
+func (s *Service) SetVerdictWeigher(weigher VerdictWeigher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.verdictWeigher = weigher
+}
+
+// consensus 根据各专家发言末尾的观点计算多空共识分，同一专家多次发言取最后一次
+func (s *Service) consensus(responses []ChatResponse, price float64) *models.ConsensusScore {
+	s.mu.RLock()
+	weigher := s.verdictWeigher
+	s.mu.RUnlock()
+
+	var votes []models.ConsensusVote
+	index := make(map[string]int)
+	for _, resp := range responses {
+		if resp.MsgType != "opinion" || resp.Error != "" {
+			continue
+		}
+		v := models.ParseVerdict(resp.Content)
+		if v == nil {
+			continue
+		}
+		vote := models.ConsensusVote{
+			AgentID: resp.AgentID, AgentName: resp.AgentName,
+			Stance: v.Stance, Confidence: v.Confidence, Weight: 1,
+		}
+		if weigher != nil {
+			vote.Weight = weigher(resp.AgentID)
+		}
+		if i, ok := index[resp.AgentID]; ok {
+			votes[i] = vote
+			continue
+		}
+		index[resp.AgentID] = len(votes)
+		votes = append(votes, vote)
+	}
+	c := models.ComputeConsensus(votes)
+	if c != nil {
+		c.Price = price
+	}
+	return c
+}
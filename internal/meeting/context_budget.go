@@ -0,0 +1,169 @@
+package meeting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/tokens"
+)
+
+// 上下文预算默认值（估算 token）
+const (
+	DefaultMemoryBudget    = 1500
+	DefaultSpeechesBudget  = 2000
+	DefaultToolGuideBudget = 800
+)
+
+// CompressTimeout 单次 LLM 压缩上下文的超时
+const CompressTimeout = 30 * time.Second
+
+// contextLimits 生效的上下文预算
+type contextLimits struct {
+	memory    int
+	speeches  int
+	toolGuide int
+	llm       bool
+}
+
+// newContextLimits 解析上下文预算配置，未设置的部分使用默认值
+func newContextLimits(cfg models.ContextBudgetConfig) contextLimits {
+	orDefault := func(v, def int) int {
+		if v <= 0 {
+			return def
+		}
+		return v
+	}
+	return contextLimits{
+		memory:    orDefault(cfg.Memory, DefaultMemoryBudget),
+		speeches:  orDefault(cfg.Speeches, DefaultSpeechesBudget),
+		toolGuide: orDefault(cfg.ToolGuide, DefaultToolGuideBudget),
+		llm:       cfg.LLMCompress,
+	}
+}
+
+// compressFunc 将文本压缩到约 maxTokens 个 token
+type compressFunc func(ctx context.Context, text string, maxTokens int) (string, error)
+
+// contextBudgeter 按预算裁剪注入专家提示词的记忆与前序发言
+// 启用 LLM 压缩时结果按原文缓存，同一场会议内不重复压缩
+type contextBudgeter struct {
+	limits   contextLimits
+	counter  tokens.Counter // 按会议模型的分词器计数
+	compress compressFunc   // 为空时只截断
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+func newContextBudgeter(limits contextLimits, aiConfig *models.AIConfig) *contextBudgeter {
+	return &contextBudgeter{limits: limits, counter: tokenCounter(aiConfig), cache: make(map[string]string)}
+}
+
+// tokenCounter 按 AI 配置选择 token 计数口径，未配置时使用粗略估算
+func tokenCounter(aiConfig *models.AIConfig) tokens.Counter {
+	if aiConfig == nil {
+		return tokens.Default
+	}
+	return tokens.ForModel(string(aiConfig.Provider), aiConfig.ModelName)
+}
+
+// setCompressor 设置 LLM 压缩函数（未启用 LLM 压缩时忽略）
+func (b *contextBudgeter) setCompressor(fn compressFunc) {
+	if b.limits.llm {
+		b.compress = fn
+	}
+}
+
+// fit 将文本控制在 maxTokens 内：优先 LLM 压缩，失败或未启用时截断
+func (b *contextBudgeter) fit(ctx context.Context, text string, maxTokens int) string {
+	if b.counter.Count(text) <= maxTokens {
+		return text
+	}
+	if b.compress != nil {
+		b.mu.Lock()
+		cached, ok := b.cache[text]
+		b.mu.Unlock()
+		if ok {
+			return cached
+		}
+		compressCtx, cancel := context.WithTimeout(ctx, CompressTimeout)
+		compressed, err := b.compress(compressCtx, text, maxTokens)
+		cancel()
+		if err == nil && strings.TrimSpace(compressed) != "" {
+			compressed = b.counter.Truncate(compressed, maxTokens)
+			b.mu.Lock()
+			b.cache[text] = compressed
+			b.mu.Unlock()
+			return compressed
+		}
+		if err != nil {
+			log.Warn("compress context error, fallback to truncation: %v", err)
+		}
+	}
+	return b.counter.Truncate(text, maxTokens)
+}
+
+// memory 按预算裁剪股票记忆（记忆按摘要、关键事实、近期讨论排列，截断时保留开头）
+func (b *contextBudgeter) memory(ctx context.Context, text string) string {
+	return b.fit(ctx, text, b.limits.memory)
+}
+
+// previousContext 按预算构建前面专家的发言：未超出时原样保留；
+// 超出时最近一位的发言最多占一半预算，更早的发言整体压缩，未启用 LLM 压缩时按条均分截断
+func (b *contextBudgeter) previousContext(ctx context.Context, history []DiscussionEntry) string {
+	if len(history) == 0 {
+		return ""
+	}
+	full := formatPreviousContext(history)
+	if b.counter.Count(full) <= b.limits.speeches {
+		return full
+	}
+
+	last := history[len(history)-1]
+	last.Content = b.fit(ctx, last.Content, b.limits.speeches/2)
+	latest := formatSpeech(last)
+	earlier := history[:len(history)-1]
+	remaining := b.limits.speeches - b.counter.Count(latest)
+
+	var sb strings.Builder
+	sb.WriteString("【前面专家的发言】\n")
+	if len(earlier) > 0 && remaining > 0 {
+		if b.compress != nil {
+			fmt.Fprintf(&sb, "- 更早发言摘要：%s\n\n", b.fit(ctx, formatSpeeches(earlier), remaining))
+		} else {
+			perEntry := max(remaining/len(earlier), 1)
+			for _, entry := range earlier {
+				entry.Content = b.counter.Truncate(entry.Content, perEntry)
+				sb.WriteString(formatSpeech(entry))
+			}
+		}
+	}
+	sb.WriteString(latest)
+	return sb.String()
+}
+
+// formatPreviousContext 格式化前面专家发言的完整上下文
+func formatPreviousContext(history []DiscussionEntry) string {
+	if len(history) == 0 {
+		return ""
+	}
+	return "【前面专家的发言】\n" + formatSpeeches(history)
+}
+
+// formatSpeeches 逐条格式化发言
+func formatSpeeches(history []DiscussionEntry) string {
+	var sb strings.Builder
+	for _, entry := range history {
+		sb.WriteString(formatSpeech(entry))
+	}
+	return sb.String()
+}
+
+// formatSpeech 格式化单条发言
+func formatSpeech(entry DiscussionEntry) string {
+	return fmt.Sprintf("- %s（%s）：%s\n\n", entry.AgentName, entry.Role, entry.Content)
+}
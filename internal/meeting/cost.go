@@ -0,0 +1,178 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/tokens"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// CostSummary 会议费用估算
+type CostSummary struct {
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	CachedTokens     int64   `json:"cachedTokens"`     // 输入中命中提示词缓存的部分
+	CacheWriteTokens int64   `json:"cacheWriteTokens"` // 输入中写入提示词缓存的部分（按溢价计费）
+	Cost             float64 `json:"cost"`             // 估算费用（美元）
+	Budget           float64 `json:"budget"`           // 费用上限（0 表示不限制）
+}
+
+// String 格式化费用摘要
+func (c CostSummary) String() string {
+	usage := fmt.Sprintf("输入 %d tokens，输出 %d tokens", c.PromptTokens, c.CompletionTokens)
+	switch {
+	case c.CachedTokens > 0 && c.CacheWriteTokens > 0:
+		usage = fmt.Sprintf("输入 %d tokens（缓存命中 %d，写入缓存 %d），输出 %d tokens", c.PromptTokens, c.CachedTokens, c.CacheWriteTokens, c.CompletionTokens)
+	case c.CachedTokens > 0:
+		usage = fmt.Sprintf("输入 %d tokens（缓存命中 %d），输出 %d tokens", c.PromptTokens, c.CachedTokens, c.CompletionTokens)
+	case c.CacheWriteTokens > 0:
+		usage = fmt.Sprintf("输入 %d tokens（写入缓存 %d），输出 %d tokens", c.PromptTokens, c.CacheWriteTokens, c.CompletionTokens)
+	}
+	if c.Budget > 0 {
+		return fmt.Sprintf("$%.4f / $%.2f（%s）", c.Cost, c.Budget, usage)
+	}
+	return fmt.Sprintf("$%.4f（%s）", c.Cost, usage)
+}
+
+// CostTracker 会议级费用累计器（并发安全）
+type CostTracker struct {
+	mu      sync.Mutex
+	summary CostSummary
+}
+
+// NewCostTracker 创建费用累计器，budget 为 0 表示不限制
+func NewCostTracker(budget float64) *CostTracker {
+	return &CostTracker{summary: CostSummary{Budget: budget}}
+}
+
+// Add 按 AI 配置的计费标准累计一次调用的用量
+// cachedTokens、cacheWriteTokens 分别为 promptTokens 中命中缓存与写入缓存的部分
+func (t *CostTracker) Add(aiConfig *models.AIConfig, promptTokens, cachedTokens, cacheWriteTokens, completionTokens int64) {
+	pricing := aiConfig.ResolvePricing()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.summary.PromptTokens += promptTokens
+	t.summary.CachedTokens += cachedTokens
+	t.summary.CacheWriteTokens += cacheWriteTokens
+	t.summary.CompletionTokens += completionTokens
+	t.summary.Cost += pricing.CostWithCacheWrite(promptTokens, cachedTokens, cacheWriteTokens, completionTokens)
+}
+
+// Exceeded 是否已超出预算
+func (t *CostTracker) Exceeded() bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.summary.Budget > 0 && t.summary.Cost >= t.summary.Budget
+}
+
+// Summary 获取当前费用快照
+func (t *CostTracker) Summary() CostSummary {
+	if t == nil {
+		return CostSummary{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.summary
+}
+
+// meteredLLM 包装 model.LLM，从响应的 UsageMetadata 中累计费用
+// 部分 OpenAI 兼容网关不返回用量，此时按模型分词器估算请求与回复的 token 数
+type meteredLLM struct {
+	model.LLM
+	aiConfig *models.AIConfig
+	tracker  *CostTracker
+	counter  tokens.Counter
+}
+
+// GenerateContent 透传生成结果，同时记录完整响应的 token 用量
+func (m *meteredLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		metered := false
+		var partial, final strings.Builder
+		defer func() {
+			if metered {
+				return
+			}
+			output := final.String()
+			if output == "" {
+				output = partial.String()
+			}
+			if output != "" {
+				m.tracker.Add(m.aiConfig, int64(m.counter.Count(requestText(req))), 0, 0, int64(m.counter.Count(output)))
+			}
+		}()
+
+		for resp, err := range m.LLM.GenerateContent(ctx, req, stream) {
+			if err == nil && resp != nil {
+				switch {
+				case !resp.Partial && resp.UsageMetadata != nil:
+					usage := resp.UsageMetadata
+					// 推理 tokens 按输出计费
+					output := int64(usage.CandidatesTokenCount) + int64(usage.ThoughtsTokenCount)
+					cacheWrite, _ := resp.CustomMetadata[models.CacheWriteTokensKey].(int64)
+					m.tracker.Add(m.aiConfig, int64(usage.PromptTokenCount), int64(usage.CachedContentTokenCount), cacheWrite, output)
+					metered = true
+				case resp.Partial:
+					writeContentText(&partial, resp.Content)
+				default:
+					writeContentText(&final, resp.Content)
+				}
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// requestText 拼接请求中的系统指令、对话文本与工具调用，用于估算输入 token
+func requestText(req *model.LLMRequest) string {
+	var sb strings.Builder
+	if req.Config != nil {
+		writeContentText(&sb, req.Config.SystemInstruction)
+	}
+	for _, content := range req.Contents {
+		writeContentText(&sb, content)
+	}
+	return sb.String()
+}
+
+// writeContentText 写入内容中的文本、工具调用参数与工具结果
+func writeContentText(sb *strings.Builder, content *genai.Content) {
+	if content == nil {
+		return
+	}
+	for _, part := range content.Parts {
+		switch {
+		case part.Text != "":
+			sb.WriteString(part.Text)
+		case part.FunctionCall != nil:
+			args, _ := json.Marshal(part.FunctionCall.Args)
+			sb.WriteString(part.FunctionCall.Name)
+			sb.Write(args)
+		case part.FunctionResponse != nil:
+			result, _ := json.Marshal(part.FunctionResponse.Response)
+			sb.Write(result)
+		}
+		sb.WriteByte('\n')
+	}
+}
+
+// withCostTracking 为 LLM 挂载费用统计（tracker 为 nil 时原样返回）
+func withCostTracking(llm model.LLM, aiConfig *models.AIConfig, tracker *CostTracker) model.LLM {
+	if tracker == nil || llm == nil {
+		return llm
+	}
+	return &meteredLLM{LLM: llm, aiConfig: aiConfig, tracker: tracker, counter: tokenCounter(aiConfig)}
+}
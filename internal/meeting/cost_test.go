@@ -0,0 +1,83 @@
+package meeting
+
+import (
+	"context"
+	"iter"
+	"math"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// noUsageLLM 不返回用量的模型，模拟不支持 stream_options 的 OpenAI 兼容网关
+type noUsageLLM struct{}
+
+func (noUsageLLM) Name() string { return "no-usage" }
+
+func (noUsageLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		if !yield(&model.LLMResponse{Content: genai.NewContentFromText("贵州茅台", genai.RoleModel), Partial: true}, nil) {
+			return
+		}
+		yield(&model.LLMResponse{Content: genai.NewContentFromText("贵州茅台估值合理", genai.RoleModel), TurnComplete: true}, nil)
+	}
+}
+
+// TestCostTrackingEstimatesMissingUsage 测试网关不返回用量时按分词器估算
+func TestCostTrackingEstimatesMissingUsage(t *testing.T) {
+	aiConfig := &models.AIConfig{Provider: models.AIProviderOpenAI, ModelName: "gpt-4o"}
+	tracker := NewCostTracker(0)
+	llm := withCostTracking(noUsageLLM{}, aiConfig, tracker)
+
+	req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("分析一下茅台", genai.RoleUser)}}
+	for range llm.GenerateContent(context.Background(), req, true) {
+	}
+
+	counter := tokenCounter(aiConfig)
+	summary := tracker.Summary()
+	if want := int64(counter.Count("分析一下茅台\n")); summary.PromptTokens != want {
+		t.Errorf("PromptTokens = %d, want %d", summary.PromptTokens, want)
+	}
+	// 只按完整回复计数，不重复累计流式片段
+	if want := int64(counter.Count("贵州茅台估值合理\n")); summary.CompletionTokens != want {
+		t.Errorf("CompletionTokens = %d, want %d", summary.CompletionTokens, want)
+	}
+	if summary.Cost <= 0 {
+		t.Errorf("Cost = %v, want > 0", summary.Cost)
+	}
+}
+
+// cacheWriteLLM 返回带缓存写入用量的模型，模拟 Anthropic 首次写入提示词缓存
+type cacheWriteLLM struct{}
+
+func (cacheWriteLLM) Name() string { return "cache-write" }
+
+func (cacheWriteLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{
+			Content:        genai.NewContentFromText("估值合理", genai.RoleModel),
+			UsageMetadata:  &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 1_000_000, CachedContentTokenCount: 100_000},
+			CustomMetadata: map[string]any{models.CacheWriteTokensKey: int64(800_000)},
+			TurnComplete:   true,
+		}, nil)
+	}
+}
+
+// TestCostTrackingCacheWrite 测试写入缓存的 tokens 单独累计并按溢价计费
+func TestCostTrackingCacheWrite(t *testing.T) {
+	aiConfig := &models.AIConfig{ModelName: "custom", Pricing: models.ModelPricing{InputPerMillion: 3, OutputPerMillion: 15, CachedInputPerMillion: 0.3}}
+	tracker := NewCostTracker(0)
+	for range withCostTracking(cacheWriteLLM{}, aiConfig, tracker).GenerateContent(context.Background(), &model.LLMRequest{}, false) {
+	}
+
+	summary := tracker.Summary()
+	if summary.CacheWriteTokens != 800_000 || summary.CachedTokens != 100_000 {
+		t.Errorf("summary = %+v", summary)
+	}
+	// 10 万普通输入 $0.3 + 10 万缓存命中 $0.03 + 80 万写入缓存 $3（1.25 倍）
+	if want := 3.33; math.Abs(summary.Cost-want) > 1e-9 {
+		t.Errorf("Cost = %v, want %v", summary.Cost, want)
+	}
+}
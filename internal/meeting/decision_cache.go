@@ -0,0 +1,115 @@
+package meeting
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// DecisionCacheTTL 小韭菜决策缓存有效期
+// 中断后短时间内重新发起同一问题时复用意图分析结果，避免重复计费
+const DecisionCacheTTL = 5 * time.Minute
+
+// decisionCacheEntry 决策缓存条目
+type decisionCacheEntry struct {
+	decision  *ModeratorDecision
+	expiresAt time.Time
+}
+
+// decisionCache 小韭菜意图分析结果缓存
+// key 由 (股票代码, 归一化问题, 专家集合) 组成
+type decisionCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]decisionCacheEntry
+}
+
+// newDecisionCache 创建决策缓存
+func newDecisionCache(ttl time.Duration) *decisionCache {
+	return &decisionCache{
+		ttl:     ttl,
+		entries: make(map[string]decisionCacheEntry),
+	}
+}
+
+// decisionCacheKey 生成缓存 key
+func decisionCacheKey(stockSymbol, query string, agents []models.AgentConfig) string {
+	ids := make([]string, 0, len(agents))
+	for _, a := range agents {
+		ids = append(ids, a.ID)
+	}
+	sort.Strings(ids)
+	return stockSymbol + "|" + normalizeQuery(query) + "|" + strings.Join(ids, ",")
+}
+
+// normalizeQuery 归一化用户问题：忽略大小写、空白和标点差异
+func normalizeQuery(query string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(query) {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// Get 获取未过期的缓存决策（返回副本，避免调用方修改缓存内容）
+func (c *decisionCache) Get(key string) (*ModeratorDecision, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return cloneDecision(entry.decision), true
+}
+
+// Set 写入缓存，同时清理过期条目
+func (c *decisionCache) Set(key string, decision *ModeratorDecision) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = decisionCacheEntry{
+		decision:  cloneDecision(decision),
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+// Clear 清空缓存
+func (c *decisionCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]decisionCacheEntry)
+}
+
+// cloneDecision 深拷贝决策结果
+func cloneDecision(d *ModeratorDecision) *ModeratorDecision {
+	if d == nil {
+		return nil
+	}
+	cp := *d
+	cp.Selected = append([]string(nil), d.Selected...)
+	if d.Tasks != nil {
+		cp.Tasks = make(map[string]string, len(d.Tasks))
+		for k, v := range d.Tasks {
+			cp.Tasks[k] = v
+		}
+	}
+	return &cp
+}
@@ -0,0 +1,84 @@
+package meeting
+
+import (
+	"context"
+	"slices"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// moderatorDirectInstruction 小韭菜直答事实类问题的指令
+const moderatorDirectInstruction = `你是「财经会议室」的小韭菜。老韭菜问的是一个纯事实问题，不需要召集专家讨论。
+请调用工具查询所需数据，用一两句话直接给出数字或事实，不要展开分析、不要给投资建议。
+如果老韭菜要求按条件选股，把描述转换为 screen_stocks 的筛选条件后执行：
+- 「低PE」「低估值」对应 pe > 0 且 pe < 15（银行等低估值行业可放宽到 pe < 8），「破净」对应 pb < 1
+- 「高股息」对应 dividend_yield >= 4，「大盘股」对应 market_cap >= 1000，「小盘股」对应 market_cap < 100
+- 「强势」「涨得多」对应 change_60d 或 change_ytd 排序，「放量」「活跃」对应 volume_ratio 或 turnover
+- 行业写入 industries，默认排除ST股，并选择最贴合需求的排序字段
+先用一句话说明采用的筛选条件，再按结果列出股票及关键指标；无结果时说明并建议放宽哪个条件。`
+
+// readOnlyTools 系统内置 Agent 可自动调用的只读行情与资讯工具
+// 不含下单、执行代码和用户自定义 HTTP 工具：这些工具有副作用或行为不可控，只能由用户挂载到专家上
+var readOnlyTools = []string{
+	"get_stock_realtime",
+	"get_kline_data",
+	"get_orderbook",
+	"get_tick_data",
+	"get_money_flow",
+	"get_news",
+	"get_stock_news",
+	"get_retail_sentiment",
+	"get_events",
+	"get_macro_data",
+	"screen_stocks",
+	"search_stocks",
+	"get_research_report",
+	"get_report_content",
+	"get_hottrend",
+	"get_hottrend_stocks",
+	"get_longhubang",
+	"get_longhubang_detail",
+	"get_market_breadth",
+	"get_limit_board",
+}
+
+// moderatorAgentConfig 小韭菜直答时使用的 Agent 配置（只可调用只读数据工具）
+func (s *Service) moderatorAgentConfig() models.AgentConfig {
+	cfg := models.AgentConfig{
+		ID:          "moderator",
+		Name:        "小韭菜",
+		Role:        "会议主持",
+		Instruction: moderatorDirectInstruction,
+		Enabled:     true,
+	}
+	if s.toolRegistry != nil {
+		cfg.Tools = slices.Clone(readOnlyTools)
+	}
+	return cfg
+}
+
+// answerDirectly 小韭菜使用工具直接回答事实类问题，不召集专家
+func (s *Service) answerDirectly(
+	ctx context.Context,
+	moderator *Moderator,
+	aiConfig *models.AIConfig,
+	stock *models.Stock,
+	query string,
+	memoryContext string,
+	progressCallback ProgressCallback,
+	position *models.StockPosition,
+	recorder *toolRecorder,
+) (string, error) {
+	// 意图分析使用独立模型时，直答沿用该模型的配置
+	if moderatorAI := s.settings().moderatorAI; moderatorAI != nil {
+		aiConfig = moderatorAI
+	}
+	cfg := s.moderatorAgentConfig()
+	builder := s.createBuilder(moderator.llm, aiConfig)
+
+	return retryRun(ctx, MaxAgentRetries, func() (string, error) {
+		answerCtx, cancel := context.WithTimeout(ctx, ModeratorTimeout)
+		defer cancel()
+		return s.runSingleAgent(answerCtx, builder, &cfg, stock, query, memoryContext, progressCallback, position, recorder)
+	})
+}
@@ -0,0 +1,119 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
+	"github.com/run-bigpig/jcp/internal/adk/openai"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+)
+
+// ErrorCode 专家失败原因分类，前端据此给出针对性的处理建议
+type ErrorCode string
+
+const (
+	ErrCodeRateLimited    ErrorCode = "rate_limited"     // 模型服务限流
+	ErrCodeAuthFailed     ErrorCode = "auth_failed"      // API Key 无效或无权限
+	ErrCodeContextTooLong ErrorCode = "context_too_long" // 上下文超出模型长度限制
+	ErrCodeToolFailed     ErrorCode = "tool_failed"      // 数据工具调用失败
+	ErrCodeTimeout        ErrorCode = "timeout"          // 模型响应或会议超时
+)
+
+// ToolError 工具执行失败导致专家发言中断
+type ToolError struct {
+	Tool string
+	Err  error
+}
+
+func (e *ToolError) Error() string {
+	return fmt.Sprintf("tool %s failed: %v", e.Tool, e.Err)
+}
+
+func (e *ToolError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError 归类错误，无法归类时返回空字符串
+func classifyError(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		return ErrCodeToolFailed
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrMeetingTimeout) ||
+		errors.Is(err, ErrModeratorTimeout) || errors.Is(err, openai.ErrStreamIdle) {
+		return ErrCodeTimeout
+	}
+
+	switch code := llmerr.StatusCode(err); {
+	case code == http.StatusTooManyRequests:
+		return ErrCodeRateLimited
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrCodeAuthFailed
+	case code == http.StatusRequestEntityTooLarge:
+		return ErrCodeContextTooLong
+	case code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout:
+		return ErrCodeTimeout
+	}
+
+	// 上下文超长多以 400 返回，只能从错误信息识别；没有状态码的网关错误同样按文本兜底
+	msg := strings.ToLower(err.Error())
+	switch {
+	case containsAny(msg, "429", "rate limit", "rate_limit", "too many requests"):
+		return ErrCodeRateLimited
+	case containsAny(msg, "401", "403", "api key", "api_key", "unauthorized", "authentication", "permission denied"):
+		return ErrCodeAuthFailed
+	case containsAny(msg, "context_length_exceeded", "context length", "maximum context", "too many tokens", "prompt is too long"):
+		return ErrCodeContextTooLong
+	case containsAny(msg, "timeout", "timed out", "超时"):
+		return ErrCodeTimeout
+	}
+	return ""
+}
+
+// describeError 返回错误码与展示给老韭菜的说明：已归类的错误给出本地化提示，未归类时保留原始信息
+func describeError(err error) (ErrorCode, string) {
+	code := classifyError(err)
+	switch code {
+	case ErrCodeRateLimited:
+		return code, i18n.T("模型服务限流，请稍后重试或降低并发")
+	case ErrCodeAuthFailed:
+		return code, i18n.T("鉴权失败，请检查 API Key")
+	case ErrCodeContextTooLong:
+		return code, i18n.T("上下文超出模型长度限制，请精简问题或调低上下文预算")
+	case ErrCodeToolFailed:
+		var toolErr *ToolError
+		errors.As(err, &toolErr)
+		return code, i18n.Sprintf("数据工具 %s 调用失败，请稍后重试", toolErr.Tool)
+	case ErrCodeTimeout:
+		return code, i18n.T("模型响应超时，请稍后重试或检查网络")
+	}
+	return code, err.Error()
+}
+
+// setError 记录失败原因
+func (r *ChatResponse) setError(err error) {
+	code, msg := describeError(err)
+	r.ErrorCode, r.Error = code, msg
+}
+
+// errorEvent 专家失败相关的进度事件（agent_error/meeting_interrupted），Detail 为本地化说明
+func errorEvent(eventType, agentID, agentName string, err error) ProgressEvent {
+	code, msg := describeError(err)
+	return ProgressEvent{Type: eventType, AgentID: agentID, AgentName: agentName, Detail: msg, ErrorCode: code}
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
+	"google.golang.org/genai"
+)
+
+func TestDescribeError(t *testing.T) {
+	cases := []struct {
+		err  error
+		code ErrorCode
+	}{
+		{llmerr.New(http.StatusTooManyRequests, "Rate limit reached"), ErrCodeRateLimited},
+		{errors.New("error, status code: 429, message: Rate limit reached"), ErrCodeRateLimited},
+		{llmerr.New(http.StatusUnauthorized, "Incorrect API key provided"), ErrCodeAuthFailed},
+		{errors.New("error, status code: 401, message: Incorrect API key provided"), ErrCodeAuthFailed},
+		{errors.New("This model's maximum context length is 65536 tokens"), ErrCodeContextTooLong},
+		{fmt.Errorf("重试 2 次后仍失败: %w", &ToolError{Tool: "get_kline", Err: errors.New("boom")}), ErrCodeToolFailed},
+		{fmt.Errorf("agent run: %w", context.DeadlineExceeded), ErrCodeTimeout},
+		{errors.New("unexpected EOF"), ""},
+	}
+	for _, c := range cases {
+		code, msg := describeError(c.err)
+		if code != c.code {
+			t.Errorf("describeError(%q) code = %q, want %q", c.err, code, c.code)
+		}
+		if code == "" && msg != c.err.Error() {
+			t.Errorf("unclassified error should keep raw message, got %q", msg)
+		}
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", llmerr.New(http.StatusTooManyRequests, "Rate limit reached"), true},
+		{"server error", fmt.Errorf("Responses API 错误: %w", llmerr.New(http.StatusInternalServerError, "internal error")), true},
+		{"overloaded", llmerr.New(529, "overloaded_error"), true},
+		{"auth", llmerr.New(http.StatusUnauthorized, "Incorrect API key provided"), false},
+		// 上游路由的 404 不再被文本中的 "not found" 误判，按状态码处理
+		{"model not found", llmerr.New(http.StatusNotFound, "model not found"), false},
+		{"transient not found", llmerr.New(http.StatusBadGateway, "upstream route not found"), true},
+		{"gemini", genai.APIError{Code: http.StatusServiceUnavailable, Message: "unavailable"}, true},
+		{"agent timeout", fmt.Errorf("agent run: %w", context.DeadlineExceeded), false},
+		{"canceled", context.Canceled, false},
+		{"invalid agent", fmt.Errorf("%w: duplicate tool", errInvalidAgent), false},
+		{"network", errors.New("read tcp: connection reset by peer"), true},
+	}
+	for _, c := range cases {
+		if got := isRetryableError(c.err); got != c.want {
+			t.Errorf("%s: isRetryableError(%v) = %v, want %v", c.name, c.err, got, c.want)
+		}
+	}
+}
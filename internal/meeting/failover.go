@@ -0,0 +1,135 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// agentRunFunc 使用指定模型构建器执行一次专家发言（ctx 已带单个专家超时）
+type agentRunFunc func(ctx context.Context, builder *adk.ExpertAgentBuilder) (string, error)
+
+// agentResult 专家发言的执行情况
+type agentResult struct {
+	answeredBy *models.AIConfig // 实际作答的 AI 配置，失败时为 nil
+	model      string           // 最后使用的模型名
+	retries    int              // 重试次数（不含降级切换）
+	duration   time.Duration    // 总耗时（含重试等待与降级重跑）
+}
+
+// apply 将耗时、重试次数、工具调用数与模型名写入响应
+func (r agentResult) apply(resp *ChatResponse, recorder *toolRecorder) {
+	resp.DurationMs = r.duration.Milliseconds()
+	resp.Retries = r.retries
+	resp.ToolCallCount = len(recorder.records())
+	resp.ModelName = r.model
+}
+
+// fallbackChain 解析 AI 配置的降级链：自身 + FallbackIDs（去重，跳过找不到的配置）
+func (s *Service) fallbackChain(aiConfig *models.AIConfig) []*models.AIConfig {
+	chain := []*models.AIConfig{aiConfig}
+	resolver := s.resolver()
+	if aiConfig == nil || resolver == nil {
+		return chain
+	}
+	seen := map[string]bool{aiConfig.ID: true}
+	for _, id := range aiConfig.FallbackIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		// 解析器找不到时会返回默认配置，这里只接受 ID 精确匹配的结果
+		if fallback := resolver(id); fallback != nil && fallback.ID == id {
+			chain = append(chain, fallback)
+		}
+	}
+	return chain
+}
+
+// shouldFailover 判断发言失败后是否切换到降级模型
+// 只在服务商明确拒绝（鉴权失败、模型不存在、额度用尽等不可重试的 HTTP 错误）时切换；
+// 429/5xx 等临时错误已由 retryRun 重试，专家配置错误换模型也无济于事，会议超时或取消时不再切换
+func shouldFailover(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, errInvalidAgent) {
+		return false
+	}
+	code := llmerr.StatusCode(err)
+	return code != 0 && !llmerr.RetryableStatus(code)
+}
+
+// runAgentWithFailover 按降级链执行专家发言
+// 模型创建失败或服务商明确拒绝时，透明地切换到下一个降级模型重跑本轮发言；
+// 返回实际作答的 AI 配置及耗时、重试次数，便于在响应中标注
+func (s *Service) runAgentWithFailover(
+	ctx context.Context,
+	agentCfg *models.AgentConfig,
+	aiConfig *models.AIConfig,
+	tracker *CostTracker,
+	progressCallback ProgressCallback,
+	run agentRunFunc,
+) (string, agentResult, error) {
+	start := time.Now()
+	var result agentResult
+	chain := s.fallbackChain(aiConfig)
+	var lastErr error
+	for i, cfg := range chain {
+		result.model = cfg.ModelName
+		if i > 0 {
+			log.Warn("agent %s failover to %s after error: %v", agentCfg.ID, cfg.ModelName, lastErr)
+			emitProgress(progressCallback, ProgressEvent{
+				Type: "failover", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
+				Detail: cfg.ModelName, Content: lastErr.Error(),
+			})
+		}
+
+		llm, err := s.createModel(ctx, cfg, tracker)
+		if err != nil {
+			lastErr = fmt.Errorf("create model %s error: %w", cfg.ModelName, err)
+			log.Error("agent %s: %v", agentCfg.ID, lastErr)
+			// 模型创建失败（如缺少 API Key）与所选模型有关，直接切换
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		builder := s.createBuilder(llm, cfg)
+
+		attempt := 0
+		content, err := retryRun(ctx, MaxAgentRetries, func() (string, error) {
+			if attempt++; attempt > 1 {
+				result.retries++
+				emitProgress(progressCallback, ProgressEvent{
+					Type: "retry", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
+					Detail: fmt.Sprintf("%d/%d", attempt-1, MaxAgentRetries), Content: cfg.ModelName,
+				})
+			}
+			agentCtx, agentCancel := context.WithTimeout(ctx, AgentTimeout)
+			defer agentCancel()
+			return run(agentCtx, builder)
+		})
+		if err == nil {
+			result.answeredBy = cfg
+			result.duration = time.Since(start)
+			return content, result, nil
+		}
+		lastErr = err
+		if !shouldFailover(ctx, err) {
+			break
+		}
+	}
+	result.duration = time.Since(start)
+	return "", result, lastErr
+}
+
+// answeredByLabel 发生降级时返回实际作答的模型名，未降级返回空
+func answeredByLabel(requested, answered *models.AIConfig) string {
+	if answered == nil || answered == requested {
+		return ""
+	}
+	return answered.ModelName
+}
@@ -0,0 +1,135 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/fixture"
+	"google.golang.org/adk/model"
+)
+
+func TestFallbackChain(t *testing.T) {
+	configs := map[string]*models.AIConfig{
+		"main":   {ID: "main", ModelName: "gpt-4o", FallbackIDs: []string{"backup", "", "main", "backup", "missing", "cheap"}},
+		"backup": {ID: "backup", ModelName: "deepseek-chat"},
+		"cheap":  {ID: "cheap", ModelName: "qwen-turbo"},
+	}
+	s := NewServiceFull(nil, nil)
+	if chain := s.fallbackChain(configs["main"]); len(chain) != 1 {
+		t.Errorf("chain without resolver = %d configs", len(chain))
+	}
+
+	// 解析器找不到时返回默认配置，不应被当作降级模型
+	s.SetAIConfigResolver(func(id string) *models.AIConfig {
+		if cfg, ok := configs[id]; ok {
+			return cfg
+		}
+		return configs["main"]
+	})
+	var names []string
+	for _, cfg := range s.fallbackChain(configs["main"]) {
+		names = append(names, cfg.ID)
+	}
+	if got := fmt.Sprint(names); got != "[main backup cheap]" {
+		t.Errorf("chain = %s", got)
+	}
+}
+
+func TestShouldFailover(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cases := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"auth failed", context.Background(), llmerr.New(401, "invalid api key"), true},
+		{"model not found", context.Background(), llmerr.New(404, "model not found"), true},
+		{"rate limited", context.Background(), llmerr.New(429, "too many requests"), false},
+		{"server error", context.Background(), llmerr.New(503, "overloaded"), false},
+		{"invalid agent", context.Background(), fmt.Errorf("%w: duplicate tool", errInvalidAgent), false},
+		{"agent timeout", context.Background(), context.DeadlineExceeded, false},
+		{"network", context.Background(), errors.New("connection reset"), false},
+		{"meeting canceled", canceled, llmerr.New(401, "invalid api key"), false},
+	}
+	for _, tc := range cases {
+		if got := shouldFailover(tc.ctx, tc.err); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// failingModels 指定模型名的配置创建失败
+type failingModels struct {
+	fixtureModels
+	broken string
+}
+
+func (f failingModels) CreateModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
+	if config.ModelName == f.broken {
+		return nil, errors.New("missing api key")
+	}
+	return f.fixtureModels.CreateModel(ctx, config)
+}
+
+func TestRunAgentWithFailover(t *testing.T) {
+	main := &models.AIConfig{ID: "main", ModelName: "main-model", FallbackIDs: []string{"backup"}}
+	backup := &models.AIConfig{ID: "backup", ModelName: "backup-model"}
+	newService := func(broken string) *Service {
+		s := NewServiceFull(nil, nil)
+		s.modelFactory = failingModels{fixtureModels: fixtureModels{llm: fixture.NewLLM("ok")}, broken: broken}
+		s.SetAIConfigResolver(func(id string) *models.AIConfig {
+			if id == "backup" {
+				return backup
+			}
+			return nil
+		})
+		return s
+	}
+	agentCfg := &models.AgentConfig{ID: "tech", Name: "技术派"}
+
+	// 服务商明确拒绝时切换到降级模型，并推送 failover 事件
+	var events []string
+	progress := func(e ProgressEvent) { events = append(events, e.Type+":"+e.Detail) }
+	calls := 0
+	content, result, err := newService("").runAgentWithFailover(context.Background(), agentCfg, main, nil, progress,
+		func(ctx context.Context, b *adk.ExpertAgentBuilder) (string, error) {
+			if calls++; calls == 1 {
+				return "", llmerr.New(401, "invalid api key")
+			}
+			return "backup answer", nil
+		})
+	if err != nil || content != "backup answer" || result.answeredBy != backup || result.model != "backup-model" || result.retries != 0 {
+		t.Fatalf("failover = %q, %+v, %v", content, result, err)
+	}
+	if fmt.Sprint(events) != "[failover:backup-model]" {
+		t.Errorf("events = %v", events)
+	}
+	if label := answeredByLabel(main, result.answeredBy); label != "backup-model" {
+		t.Errorf("answeredBy label = %q", label)
+	}
+
+	// 专家配置错误不切换
+	calls = 0
+	_, result, err = newService("").runAgentWithFailover(context.Background(), agentCfg, main, nil, nil,
+		func(ctx context.Context, b *adk.ExpertAgentBuilder) (string, error) {
+			calls++
+			return "", fmt.Errorf("%w: duplicate tool", errInvalidAgent)
+		})
+	if !errors.Is(err, errInvalidAgent) || calls != 1 || result.answeredBy != nil {
+		t.Errorf("invalid agent: calls = %d, result = %+v, err = %v", calls, result, err)
+	}
+
+	// 主模型创建失败时直接使用降级模型
+	content, result, err = newService("main-model").runAgentWithFailover(context.Background(), agentCfg, main, nil, nil,
+		func(ctx context.Context, b *adk.ExpertAgentBuilder) (string, error) { return "ok", nil })
+	if err != nil || content != "ok" || result.answeredBy != backup {
+		t.Errorf("create failure: %q, %+v, %v", content, result, err)
+	}
+}
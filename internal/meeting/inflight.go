@@ -0,0 +1,204 @@
+package meeting
+
+import (
+	"context"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+)
+
+// 会议锁错误
+var (
+	ErrMeetingRunning   = i18n.NewError("该股票已有会议正在进行")
+	ErrNoRunningMeeting = i18n.NewError("该股票没有正在进行的会议")
+)
+
+// inflight 进行中的会议：同一股票同时只允许一场，后来者可挂载回调等待结果
+type inflight struct {
+	mu        sync.Mutex
+	respSubs  []ResponseCallback
+	progSubs  []ProgressCallback
+	responses []ChatResponse
+	err       error
+	done      chan struct{} // 会议结束（结果已记录）时关闭
+	released  chan struct{} // 会议锁从 inflights 移除后关闭，供 ClaimWait 排队
+	once      sync.Once
+}
+
+// claimKey context 中携带的会议锁（调用方通过 Claim 预先占用）
+type claimKey struct{}
+
+type claim struct {
+	stockCode string
+	flight    *inflight
+}
+
+// Claim 占用该股票的会议锁，已有会议进行中时返回 ErrMeetingRunning
+// 返回的 ctx 需传给本次会议的 RunSmartMeeting*/SendMessage*/ContinueMeeting，会议结束后调用 release
+// 用于调用方在开会前还有其他准备工作（如保存老韭菜消息）时，避免重复点击发起两场会议
+func (s *Service) Claim(ctx context.Context, stockCode string) (context.Context, func(), error) {
+	f, err := s.lockMeeting(stockCode)
+	if err != nil {
+		return ctx, func() {}, err
+	}
+	return s.claimContext(ctx, stockCode, f)
+}
+
+// ClaimWait 与 Claim 相同，但已有会议进行中时排队等到其结束再占用，ctx 取消时返回 ctx.Err()
+// 用于不能丢弃的自动会议（如交易计划触发的复盘）
+func (s *Service) ClaimWait(ctx context.Context, stockCode string) (context.Context, func(), error) {
+	for {
+		f, running := s.acquire(stockCode)
+		if f != nil {
+			return s.claimContext(ctx, stockCode, f)
+		}
+		// 等锁真正移除后再重试，done 关闭到移除之间重试会空转
+		select {
+		case <-running.released:
+		case <-ctx.Done():
+			return ctx, func() {}, ctx.Err()
+		}
+	}
+}
+
+// claimContext 将已占用的会议锁放入 ctx，返回释放函数
+func (s *Service) claimContext(ctx context.Context, stockCode string, f *inflight) (context.Context, func(), error) {
+	return context.WithValue(ctx, claimKey{}, &claim{stockCode: stockCode, flight: f}), func() {
+		s.unlockMeeting(stockCode, f, nil, nil)
+	}, nil
+}
+
+// IsMeetingRunning 该股票是否有会议正在进行
+func (s *Service) IsMeetingRunning(stockCode string) bool {
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+	_, ok := s.inflights[stockCode]
+	return ok
+}
+
+// AttachMeeting 将回调挂到该股票进行中的会议上，阻塞到会议结束并返回会议的全部响应
+// 挂载前已产生的发言会先补发给 respCallback；没有进行中的会议时返回 ErrNoRunningMeeting
+func (s *Service) AttachMeeting(ctx context.Context, stockCode string, respCallback ResponseCallback, progressCallback ProgressCallback) ([]ChatResponse, error) {
+	s.inflightMu.Lock()
+	f, ok := s.inflights[stockCode]
+	s.inflightMu.Unlock()
+	if !ok {
+		return nil, ErrNoRunningMeeting
+	}
+
+	f.mu.Lock()
+	past := append([]ChatResponse(nil), f.responses...)
+	if respCallback != nil {
+		f.respSubs = append(f.respSubs, respCallback)
+	}
+	if progressCallback != nil {
+		f.progSubs = append(f.progSubs, progressCallback)
+	}
+	f.mu.Unlock()
+	if respCallback != nil {
+		for _, resp := range past {
+			respCallback(resp)
+		}
+	}
+
+	select {
+	case <-f.done:
+		return f.responses, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// enterMeeting 进入会议：ctx 已携带该股票的锁时沿用，否则占用新锁
+// 返回的 leave 记录会议结果并在自行占用时释放锁；stockCode 为空时不加锁
+func (s *Service) enterMeeting(ctx context.Context, stockCode string) (*inflight, func([]ChatResponse, error), error) {
+	if stockCode == "" {
+		return nil, func([]ChatResponse, error) {}, nil
+	}
+	if c, ok := ctx.Value(claimKey{}).(*claim); ok && c.stockCode == stockCode {
+		return c.flight, c.flight.finish, nil
+	}
+	f, err := s.lockMeeting(stockCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func(responses []ChatResponse, err error) {
+		s.unlockMeeting(stockCode, f, responses, err)
+	}, nil
+}
+
+func (s *Service) lockMeeting(stockCode string) (*inflight, error) {
+	f, _ := s.acquire(stockCode)
+	if f == nil {
+		return nil, ErrMeetingRunning
+	}
+	return f, nil
+}
+
+// acquire 占用该股票的会议锁；已被占用时返回进行中的会议（检查与占用在同一临界区内）
+func (s *Service) acquire(stockCode string) (f, running *inflight) {
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+	if running, ok := s.inflights[stockCode]; ok {
+		return nil, running
+	}
+	f = &inflight{done: make(chan struct{}), released: make(chan struct{})}
+	s.inflights[stockCode] = f
+	return f, nil
+}
+
+func (s *Service) unlockMeeting(stockCode string, f *inflight, responses []ChatResponse, err error) {
+	f.finish(responses, err)
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+	if s.inflights[stockCode] == f {
+		delete(s.inflights, stockCode)
+		close(f.released)
+	}
+}
+
+// finish 记录会议结果并唤醒等待者（只生效一次）
+func (f *inflight) finish(responses []ChatResponse, err error) {
+	f.once.Do(func() {
+		f.mu.Lock()
+		if responses != nil || err != nil {
+			f.responses, f.err = responses, err
+		}
+		f.mu.Unlock()
+		close(f.done)
+	})
+}
+
+// responseCallback 发言同时推送给挂载的回调，并记录供后来挂载者补发
+// cb 为 nil 时保持为 nil（不改变会议的流式与回放行为），挂载者只能在会议结束时拿到结果
+func (f *inflight) responseCallback(cb ResponseCallback) ResponseCallback {
+	if f == nil || cb == nil {
+		return cb
+	}
+	return func(resp ChatResponse) {
+		cb(resp)
+		f.mu.Lock()
+		f.responses = append(f.responses, resp)
+		subs := f.respSubs
+		f.mu.Unlock()
+		for _, sub := range subs {
+			sub(resp)
+		}
+	}
+}
+
+// progressCallback 进度事件同时推送给挂载的回调
+func (f *inflight) progressCallback(cb ProgressCallback) ProgressCallback {
+	if f == nil || cb == nil {
+		return cb
+	}
+	return func(event ProgressEvent) {
+		cb(event)
+		f.mu.Lock()
+		subs := f.progSubs
+		f.mu.Unlock()
+		for _, sub := range subs {
+			sub(event)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// MaxInvitedAgents 第1轮结束后小韭菜最多追加邀请的专家数
+const MaxInvitedAgents = 2
+
+// InviteDecision 小韭菜追加邀请专家的决策
+type InviteDecision struct {
+	Invite []string          `json:"invite"` // 追加邀请的专家 ID，为空表示无需追加
+	Tasks  map[string]string `json:"tasks"`  // 专家ID -> 专属分析任务
+	Reason string            `json:"reason"` // 追加邀请的原因
+}
+
+// ReviewDiscussion 审视第1轮讨论，判断是否需要追加邀请其他专家
+func (m *Moderator) ReviewDiscussion(ctx context.Context, stock *models.Stock, query string, history []DiscussionEntry, candidates []models.AgentConfig) (*InviteDecision, error) {
+	prompt := m.buildReviewPrompt(stock, query, history, candidates)
+	content, err := m.generate(ctx, m.llm, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("moderator review error: %w", err)
+	}
+	jsonStr := m.extractJSON(strings.TrimSpace(content))
+	if jsonStr == "" {
+		return nil, fmt.Errorf("无法从响应中提取 JSON: %s", truncateString(content, 200))
+	}
+	var decision InviteDecision
+	if err := json.Unmarshal([]byte(jsonStr), &decision); err != nil {
+		return nil, fmt.Errorf("JSON 解析失败: %w, 原文: %s", err, truncateString(jsonStr, 200))
+	}
+	return &decision, nil
+}
+
+// buildReviewPrompt 构建追加邀请评估 Prompt
+func (m *Moderator) buildReviewPrompt(stock *models.Stock, query string, history []DiscussionEntry, candidates []models.AgentConfig) string {
+	var sb strings.Builder
+	sb.WriteString("你是「财经会议室」的小韭菜，第一轮专家发言已经结束，请判断是否需要追加邀请其他专家。\n\n")
+	fmt.Fprintf(&sb, "## 股票：%s (%s)\n\n", stock.Name, stock.Symbol)
+	sb.WriteString("## 老韭菜问题\n")
+	sb.WriteString(query + "\n\n")
+	sb.WriteString("## 讨论记录\n")
+	for _, e := range history {
+		fmt.Fprintf(&sb, "【%s（%s）】\n%s\n\n", e.AgentName, e.Role, truncateString(e.Content, 800))
+	}
+	sb.WriteString("## 尚未发言的专家\n")
+	for _, a := range candidates {
+		fmt.Fprintf(&sb, "- %s（ID: %s）：%s\n", a.Name, a.ID, a.Role)
+	}
+	sb.WriteString("\n## 判断标准\n")
+	sb.WriteString("1. 只有当讨论暴露出现有专家无法回答的关键问题（如结论取决于财务造假风险、政策影响、行业景气度），且上面有专家擅长该问题时才邀请\n")
+	fmt.Fprintf(&sb, "2. 最多邀请 %d 位，为每位制定一个针对该关键问题的明确任务\n", MaxInvitedAgents)
+	sb.WriteString("3. 现有发言已足以回答老韭菜时不要邀请\n\n")
+	sb.WriteString("## 输出格式（仅输出JSON）\n")
+	sb.WriteString(`{"invite":["id1"],"tasks":{"id1":"该专家需要分析的具体问题"},"reason":"邀请原因（一句话）"}`)
+	sb.WriteString("\n无需邀请时输出：\n")
+	sb.WriteString(`{"invite":[]}`)
+	return sb.String()
+}
+
+// inviteAgents 第1轮结束后由小韭菜决定是否追加邀请专家，返回被邀请的专家及其任务
+// 评估失败时不追加，会议照常进入总结
+func (s *Service) inviteAgents(
+	ctx context.Context,
+	moderator *Moderator,
+	req ChatRequest,
+	history []DiscussionEntry,
+	selected []models.AgentConfig,
+	progressCallback ProgressCallback,
+) ([]models.AgentConfig, map[string]string) {
+	spoken := make(map[string]bool, len(selected))
+	for _, a := range selected {
+		spoken[a.ID] = true
+	}
+	var candidates []models.AgentConfig
+	for _, a := range req.AllAgents {
+		if !spoken[a.ID] {
+			candidates = append(candidates, a)
+		}
+	}
+	if len(candidates) == 0 || len(history) == 0 {
+		return nil, nil
+	}
+
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "评估是否追加专家",
+	})
+	reviewCtx, reviewCancel := context.WithTimeout(ctx, ModeratorTimeout)
+	decision, err := moderator.ReviewDiscussion(reviewCtx, &req.Stock, req.Query, history, candidates)
+	reviewCancel()
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜",
+	})
+	if err != nil {
+		log.Warn("moderator review error, skip inviting: %v", err)
+		return nil, nil
+	}
+
+	invited := s.filterAgentsOrdered(candidates, decision.Invite)
+	if len(invited) > MaxInvitedAgents {
+		invited = invited[:MaxInvitedAgents]
+	}
+	for _, a := range invited {
+		emitProgress(progressCallback, ProgressEvent{
+			Type: "agent_invited", AgentID: a.ID, AgentName: a.Name,
+			Detail: decision.Reason, Content: decision.Tasks[a.ID],
+		})
+	}
+	if len(invited) > 0 {
+		log.Info("moderator invited %d more agents for %s: %s", len(invited), req.Stock.Symbol, decision.Reason)
+	}
+	return invited, decision.Tasks
+}
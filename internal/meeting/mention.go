@@ -0,0 +1,55 @@
+package meeting
+
+import (
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// ParseMentions 从问题中解析 @专家名，返回专家 ID（按出现顺序去重）
+// 名称互为前缀时取最长匹配，如「@风控专家」不会同时匹配「风控」
+func ParseMentions(query string, agents []models.AgentConfig) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for rest := query; ; {
+		idx := strings.Index(rest, "@")
+		if idx < 0 {
+			break
+		}
+		rest = rest[idx+1:]
+		var matched *models.AgentConfig
+		for i := range agents {
+			a := &agents[i]
+			if a.Name != "" && strings.HasPrefix(rest, a.Name) && (matched == nil || len(a.Name) > len(matched.Name)) {
+				matched = a
+			}
+		}
+		if matched != nil && !seen[matched.ID] {
+			seen[matched.ID] = true
+			ids = append(ids, matched.ID)
+		}
+	}
+	return ids
+}
+
+// includeMentioned 确保老韭菜 @ 的专家参会：小韭菜已选的保持原顺序，未选的按 @ 顺序追加在后
+func (s *Service) includeMentioned(selected, all []models.AgentConfig, mentions []string) []models.AgentConfig {
+	if len(mentions) == 0 {
+		return selected
+	}
+	chosen := make(map[string]bool, len(selected))
+	for _, a := range selected {
+		chosen[a.ID] = true
+	}
+	var missing []string
+	for _, id := range mentions {
+		if !chosen[id] {
+			chosen[id] = true
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		log.Info("force include mentioned agents: %v", missing)
+	}
+	return append(selected, s.filterAgentsOrdered(all, missing)...)
+}
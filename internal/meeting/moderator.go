@@ -8,6 +8,7 @@ import (
 
 	"github.com/run-bigpig/jcp/internal/adk/openai"
 	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
 
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
@@ -15,7 +16,9 @@ import (
 
 // Moderator 小韭菜 Agent
 type Moderator struct {
-	llm model.LLM
+	llm        model.LLM // 意图分析
+	summaryLLM model.LLM // 会议总结（为空时使用 llm）
+	profile    string    // 老韭菜画像（用于个性化意图分析与总结）
 }
 
 // NewModerator 创建小韭菜
@@ -23,13 +26,25 @@ func NewModerator(llm model.LLM) *Moderator {
 	return &Moderator{llm: llm}
 }
 
+// SetSummaryLLM 设置会议总结使用的 LLM
+func (m *Moderator) SetSummaryLLM(llm model.LLM) {
+	m.summaryLLM = llm
+}
+
+// SetUserProfile 设置老韭菜画像描述
+func (m *Moderator) SetUserProfile(profile string) {
+	m.profile = profile
+}
+
 // ModeratorDecision 小韭菜决策结果
 type ModeratorDecision struct {
 	Intent   string            `json:"intent"`
 	Selected []string          `json:"selected"`
 	Topic    string            `json:"topic"`
 	Opening  string            `json:"opening"`
-	Tasks    map[string]string `json:"tasks"` // 专家ID -> 专属分析任务
+	Tasks    map[string]string `json:"tasks"`             // 专家ID -> 专属分析任务
+	Clarify  string            `json:"clarify,omitempty"` // 问题过于模糊时向老韭菜追问的问题（此时不选择专家）
+	Direct   bool              `json:"direct,omitempty"`  // 纯事实查询，小韭菜查数据直接回答，不召集专家
 }
 
 // DiscussionEntry 讨论条目
@@ -44,7 +59,7 @@ type DiscussionEntry struct {
 // Analyze 分析用户意图并选择专家
 func (m *Moderator) Analyze(ctx context.Context, stock *models.Stock, query string, agents []models.AgentConfig) (*ModeratorDecision, error) {
 	prompt := m.buildAnalyzePrompt(stock, query, agents)
-	content, err := m.generate(ctx, prompt)
+	content, err := m.generate(ctx, m.llm, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("moderator analyze error: %w", err)
 	}
@@ -54,19 +69,36 @@ func (m *Moderator) Analyze(ctx context.Context, stock *models.Stock, query stri
 // Summarize 总结讨论并给出结论
 func (m *Moderator) Summarize(ctx context.Context, stock *models.Stock, query string, history []DiscussionEntry) (string, error) {
 	prompt := m.buildSummarizePrompt(stock, query, history)
-	return m.generate(ctx, prompt)
+	llm := m.summaryLLM
+	if llm == nil {
+		llm = m.llm
+	}
+	return m.generate(ctx, llm, prompt)
 }
 
-// generate 调用 LLM 生成内容
-func (m *Moderator) generate(ctx context.Context, prompt string) (string, error) {
+// Compress 将会议上下文压缩到约 maxTokens 个 token，使用会议总结模型
+func (m *Moderator) Compress(ctx context.Context, text string, maxTokens int) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("请压缩以下会议上下文，供后续发言的专家参考。\n")
+	fmt.Fprintf(&sb, "要求：保留各专家的核心观点、关键数据与价位、分歧点，删除寒暄与重复论述；不超过 %d 字；直接输出压缩结果。\n\n", maxTokens)
+	sb.WriteString(text)
+	llm := m.summaryLLM
+	if llm == nil {
+		llm = m.llm
+	}
+	return m.generate(ctx, llm, sb.String())
+}
+
+// generate 调用 LLM 生成内容，按当前语言追加回复语言要求
+func (m *Moderator) generate(ctx context.Context, llm model.LLM, prompt string) (string, error) {
 	req := &model.LLMRequest{
 		Contents: []*genai.Content{
-			{Role: "user", Parts: []*genai.Part{genai.NewPartFromText(prompt)}},
+			{Role: "user", Parts: []*genai.Part{genai.NewPartFromText(prompt + i18n.ReplyInstruction())}},
 		},
 	}
 
 	var result strings.Builder
-	for resp, err := range m.llm.GenerateContent(ctx, req, false) {
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
 		if err != nil {
 			return "", err
 		}
@@ -93,6 +125,9 @@ func (m *Moderator) buildAnalyzePrompt(stock *models.Stock, query string, agents
 	fmt.Fprintf(&sb, "%s (%s)，现价 %.2f，涨跌幅 %.2f%%\n\n", stock.Name, stock.Symbol, stock.Price, stock.ChangePercent)
 	sb.WriteString("## 老韭菜问题\n")
 	sb.WriteString(query + "\n\n")
+	if m.profile != "" {
+		sb.WriteString(m.profile + "\n")
+	}
 	sb.WriteString("## 可邀请的专家\n")
 	for _, a := range agents {
 		fmt.Fprintf(&sb, "- %s（ID: %s）：%s\n", a.Name, a.ID, a.Role)
@@ -100,10 +135,18 @@ func (m *Moderator) buildAnalyzePrompt(stock *models.Stock, query string, agents
 	sb.WriteString("\n## 你的任务\n")
 	sb.WriteString("1. 分析老韭菜问题的核心意图\n")
 	sb.WriteString(fmt.Sprintf("2. 除非用户特别约束专家数量,否则选择 1-%d 位最相关的专家\n", len(agents)))
-	sb.WriteString("3. 为每位选中的专家制定一个明确的、与其专业匹配的分析任务（不要照搬用户原话，要根据专家角色拆解）\n")
-	sb.WriteString("4. 生成讨论议题和开场白\n\n")
+	sb.WriteString("3. 为每位选中的专家制定一个明确的、与其专业匹配的分析任务（不要照搬用户原话，要根据专家角色拆解；有画像时任务要贴合老韭菜的持有周期）\n")
+	sb.WriteString("4. 生成讨论议题和开场白\n")
+	sb.WriteString("5. 如果问题过于模糊（如「怎么看？」）无法判断老韭菜关心短线、长线还是持仓处理，不要勉强选择专家，改为追问一个简短的澄清问题；")
+	sb.WriteString("问题中已带有【补充说明】时不得再追问\n")
+	sb.WriteString("6. 如果只是纯事实查询（如「今天成交量多少」「现在什么价」），不需要观点和分析，不要召集专家，由你查询数据后直接回答\n")
+	sb.WriteString("7. 如果是按条件选股（如「帮我找低PE高股息的银行股」「找出近60日涨幅最大的半导体股」），同样按纯事实查询处理，由你执行选股后直接列出结果\n\n")
 	sb.WriteString("## 输出格式（仅输出JSON）\n")
 	sb.WriteString(`{"intent":"意图","selected":["id1","id2"],"tasks":{"id1":"该专家需要分析的具体问题","id2":"该专家需要分析的具体问题"},"topic":"议题","opening":"开场白"}`)
+	sb.WriteString("\n需要追问时输出：\n")
+	sb.WriteString(`{"intent":"意图","selected":[],"clarify":"向老韭菜追问的问题"}`)
+	sb.WriteString("\n纯事实查询或条件选股时输出：\n")
+	sb.WriteString(`{"intent":"意图","selected":[],"direct":true}`)
 	return sb.String()
 }
 
@@ -114,15 +157,21 @@ func (m *Moderator) buildSummarizePrompt(stock *models.Stock, query string, hist
 	fmt.Fprintf(&sb, "## 股票：%s (%s)\n\n", stock.Name, stock.Symbol)
 	sb.WriteString("## 老韭菜问题\n")
 	sb.WriteString(query + "\n\n")
+	if m.profile != "" {
+		sb.WriteString(m.profile + "\n")
+	}
 	sb.WriteString("## 讨论记录\n")
 	for _, e := range history {
 		fmt.Fprintf(&sb, "【%s（%s）】\n%s\n\n", e.AgentName, e.Role, e.Content)
 	}
 	sb.WriteString("## 输出要求\n")
-	sb.WriteString("1. 核心结论（直接回答老韭菜）\n")
-	sb.WriteString("2. 各方观点摘要\n")
-	sb.WriteString("3. 综合建议\n\n")
-	sb.WriteString("控制在 300 字以内。")
+	sb.WriteString("按以下五个小节输出，每节以「### 标题」开头，标题文字不得改动，某节确无内容时写「无」：\n")
+	sb.WriteString("### " + models.SummaryConclusion + "\n直接回答老韭菜的问题\n")
+	sb.WriteString("### " + models.SummaryDivergence + "\n专家之间看多与看空的主要分歧，观点一致时说明共识\n")
+	sb.WriteString("### " + models.SummaryKeyData + "\n支撑结论的关键数据（价位、估值、资金等）\n")
+	sb.WriteString("### " + models.SummaryRisks + "\n需要警惕的主要风险\n")
+	sb.WriteString("### " + models.SummaryActions + "\n具体操作建议，给出买卖建议时注明买入区间、止损价、目标价等关键价位\n\n")
+	sb.WriteString("控制在 400 字以内。")
 	return sb.String()
 }
 
@@ -141,8 +190,8 @@ func (m *Moderator) parseDecision(content string) (*ModeratorDecision, error) {
 		return nil, fmt.Errorf("JSON 解析失败: %w, 原文: %s", err, truncateString(jsonStr, 200))
 	}
 
-	// 验证必要字段
-	if len(decision.Selected) == 0 {
+	// 验证必要字段（追问、直答模式下允许不选专家）
+	if len(decision.Selected) == 0 && decision.Clarify == "" && !decision.Direct {
 		return nil, fmt.Errorf("小韭菜未选择任何专家")
 	}
 
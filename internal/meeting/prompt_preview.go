@@ -0,0 +1,96 @@
+package meeting
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// PromptPreviewRequest 专家提示词预览请求
+type PromptPreviewRequest struct {
+	Agent        models.AgentConfig
+	Stock        models.Stock
+	Query        string // 专家任务，智能会议中通常是小韭菜分配的任务
+	Position     *models.StockPosition
+	ExtraContext string            // 上次建议未执行等附加上下文
+	History      []DiscussionEntry // 假定已发言的专家，为空表示第一位发言
+}
+
+// PromptPreview 专家最终提示词及各组成部分（与智能会议的拼装顺序一致）
+type PromptPreview struct {
+	AgentID         string   `json:"agentId"`
+	AgentName       string   `json:"agentName"`
+	ModelName       string   `json:"modelName"`
+	Instruction     string   `json:"instruction"`     // 实际发送的系统指令
+	UserMessage     string   `json:"userMessage"`     // 实际发送的用户消息
+	Length          int      `json:"length"`          // 系统指令字数
+	Tokens          int      `json:"tokens"`          // 系统指令与用户消息的估算 token 数（按专家模型的分词器）
+	UserProfile     string   `json:"userProfile"`     // 老韭菜画像
+	ExtraContext    string   `json:"extraContext"`    // 附加上下文
+	MemoryContext   string   `json:"memoryContext"`   // 股票记忆
+	PreviousContext string   `json:"previousContext"` // 前面专家的发言
+	ToolsEnabled    bool     `json:"toolsEnabled"`    // 模型是否支持工具调用
+	Tools           []string `json:"tools"`           // 挂载的内置工具
+	MCPServers      []string `json:"mcpServers"`      // 挂载的 MCP 服务器
+}
+
+// PreviewPrompt 按智能会议的方式拼装专家提示词但不调用模型，用于调试与调整上下文
+// 只读取记忆，不创建或修改记忆文件
+func (s *Service) PreviewPrompt(aiConfig *models.AIConfig, req PromptPreviewRequest) *PromptPreview {
+	agentAIConfig := s.resolveAgentAIConfig(&req.Agent, aiConfig)
+	budget := newContextBudgeter(s.settings().context, agentAIConfig) // 预览不调用模型，超出预算时按截断展示
+	preview := &PromptPreview{
+		AgentID:         req.Agent.ID,
+		AgentName:       req.Agent.Name,
+		UserMessage:     req.Query,
+		UserProfile:     s.settings().userProfile,
+		ExtraContext:    req.ExtraContext,
+		PreviousContext: budget.previousContext(context.Background(), req.History),
+		ToolsEnabled:    agentAIConfig == nil || agentAIConfig.SupportsTools(),
+		Tools:           req.Agent.Tools,
+		MCPServers:      req.Agent.MCPServers,
+	}
+	if agentAIConfig != nil {
+		preview.ModelName = agentAIConfig.ModelName
+	}
+
+	if s.memoryManager != nil {
+		if stockMemory, err := s.memoryManager.GetOrCreate(req.Stock.Symbol, req.Stock.Name); err == nil {
+			preview.MemoryContext = budget.memory(context.Background(), s.memoryManager.BuildContext(stockMemory, req.Query))
+		}
+	}
+
+	// 与 RunSmartMeetingWithCallback 相同：画像 + 附加上下文 + 记忆，再接前面专家的发言
+	memoryContext := preview.MemoryContext
+	if preview.ExtraContext != "" {
+		memoryContext = preview.ExtraContext + "\n" + memoryContext
+	}
+	if preview.UserProfile != "" {
+		memoryContext = preview.UserProfile + "\n" + memoryContext
+	}
+	replyContent := preview.PreviousContext
+	if memoryContext != "" {
+		replyContent = memoryContext + "\n" + replyContent
+	}
+
+	builder := s.createBuilder(nil, agentAIConfig)
+	preview.Instruction = builder.BuildInstruction(&req.Agent, &req.Stock, req.Query, replyContent, req.Position)
+	preview.Length = utf8.RuneCountInString(preview.Instruction)
+	preview.Tokens = budget.counter.Count(preview.Instruction) + budget.counter.Count(preview.UserMessage)
+	return preview
+}
+
+// EstimatePromptTokens 估算提问时每位专家共同收到的 prompt token 数（老韭菜画像、股票记忆与问题），
+// 按专家模型的分词器计数，不含专家指令与前面专家的发言
+func (s *Service) EstimatePromptTokens(aiConfig *models.AIConfig, stock models.Stock, query string) int {
+	settings := s.settings()
+	budget := newContextBudgeter(settings.context, aiConfig)
+	total := budget.counter.Count(settings.userProfile) + budget.counter.Count(query)
+	if s.memoryManager != nil {
+		if stockMemory, err := s.memoryManager.GetOrCreate(stock.Symbol, stock.Name); err == nil {
+			total += budget.counter.Count(budget.memory(context.Background(), s.memoryManager.BuildContext(stockMemory, query)))
+		}
+	}
+	return total
+}
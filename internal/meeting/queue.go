@@ -0,0 +1,177 @@
+package meeting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultMaxConcurrentMeetings 默认同时进行的会议数上限
+const DefaultMaxConcurrentMeetings = 2
+
+// Priority 会议优先级，数值越小越优先
+type Priority int
+
+const (
+	PriorityManual    Priority = iota // 老韭菜手动发起
+	PriorityAlert                     // 提醒触发（如交易计划复盘）
+	PriorityScheduled                 // 定时任务（如盘前早报）
+)
+
+// queueTicket 排队中的会议
+type queueTicket struct {
+	priority   Priority
+	seq        uint64
+	position   int // 最近一次通知的排队位置
+	ready      chan struct{}
+	onPosition func(position int)
+}
+
+// Queue 会议排队：超出并发上限时按优先级排队，同优先级先到先得
+type Queue struct {
+	mu            sync.Mutex
+	maxConcurrent int
+	running       int
+	seq           uint64
+	waiting       []*queueTicket
+}
+
+// NewQueue 创建会议队列，maxConcurrent <= 0 时使用默认上限
+func NewQueue(maxConcurrent int) *Queue {
+	q := &Queue{}
+	q.maxConcurrent = normalizeMaxConcurrent(maxConcurrent)
+	return q
+}
+
+func normalizeMaxConcurrent(n int) int {
+	if n <= 0 {
+		return DefaultMaxConcurrentMeetings
+	}
+	return n
+}
+
+// SetMaxConcurrent 修改并发上限，调大时立即放行排队中的会议
+func (q *Queue) SetMaxConcurrent(n int) {
+	q.mu.Lock()
+	q.maxConcurrent = normalizeMaxConcurrent(n)
+	notify := q.dispatch()
+	q.mu.Unlock()
+	notify()
+}
+
+// Acquire 获取会议名额，名额不足时排队等待
+// 排队期间位置变化通过 onPosition 通知（从 1 开始）；成功后须调用返回的 release 归还名额
+func (q *Queue) Acquire(ctx context.Context, priority Priority, onPosition func(position int)) (func(), error) {
+	q.mu.Lock()
+	if len(q.waiting) == 0 && q.running < q.maxConcurrent {
+		q.running++
+		q.mu.Unlock()
+		return q.releaseFunc(), nil
+	}
+
+	q.seq++
+	t := &queueTicket{priority: priority, seq: q.seq, ready: make(chan struct{}), onPosition: onPosition}
+	q.waiting = append(q.waiting, t)
+	sort.SliceStable(q.waiting, func(i, j int) bool {
+		a, b := q.waiting[i], q.waiting[j]
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		return a.seq < b.seq
+	})
+	notify := q.positions()
+	q.mu.Unlock()
+	notify()
+
+	select {
+	case <-t.ready:
+		return q.releaseFunc(), nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	select {
+	case <-t.ready:
+		// 取消与放行同时发生：名额已分配，归还后再退出
+		q.mu.Unlock()
+		q.releaseFunc()()
+		return nil, ctx.Err()
+	default:
+	}
+	for i, w := range q.waiting {
+		if w == t {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			break
+		}
+	}
+	notify = q.positions()
+	q.mu.Unlock()
+	notify()
+	return nil, ctx.Err()
+}
+
+// Stats 返回进行中与排队中的会议数
+func (q *Queue) Stats() (running, waiting int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.running, len(q.waiting)
+}
+
+// releaseFunc 归还名额（多次调用只生效一次）
+func (q *Queue) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.mu.Lock()
+			q.running--
+			notify := q.dispatch()
+			q.mu.Unlock()
+			notify()
+		})
+	}
+}
+
+// dispatch 在名额允许时放行队首会议，返回位置变化通知（须在解锁后调用）
+func (q *Queue) dispatch() func() {
+	for len(q.waiting) > 0 && q.running < q.maxConcurrent {
+		t := q.waiting[0]
+		q.waiting = q.waiting[1:]
+		q.running++
+		close(t.ready)
+	}
+	return q.positions()
+}
+
+// positions 收集排队位置发生变化的会议，返回通知函数（须在解锁后调用，避免回调中重入死锁）
+func (q *Queue) positions() func() {
+	type change struct {
+		fn       func(int)
+		position int
+	}
+	var changes []change
+	for i, t := range q.waiting {
+		if t.position != i+1 {
+			t.position = i + 1
+			if t.onPosition != nil {
+				changes = append(changes, change{t.onPosition, t.position})
+			}
+		}
+	}
+	return func() {
+		for _, c := range changes {
+			c.fn(c.position)
+		}
+	}
+}
+
+// waitTurn 排队等待会议名额，排队期间推送 queued 进度事件
+func (s *Service) waitTurn(ctx context.Context, priority Priority, progressCallback ProgressCallback) (func(), error) {
+	return s.queue.Acquire(ctx, priority, func(position int) {
+		emitProgress(progressCallback, ProgressEvent{
+			Type: "queued", AgentID: "moderator", AgentName: "小韭菜",
+			Detail: fmt.Sprintf("排队中（第 %d 位）", position), Content: strconv.Itoa(position),
+		})
+	})
+}
@@ -0,0 +1,238 @@
+package meeting
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitQueued 等待队列中排队的会议数达到 n
+func waitQueued(t *testing.T, q *Queue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, waiting := q.Stats(); waiting == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("waiting never reached %d", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// acquireAsync 在后台排队获取名额，获得名额后将 name 写入 order
+func acquireAsync(q *Queue, priority Priority, name string, order chan<- string) <-chan func() {
+	released := make(chan func(), 1)
+	go func() {
+		release, err := q.Acquire(context.Background(), priority, nil)
+		if err != nil {
+			close(released)
+			return
+		}
+		order <- name
+		released <- release
+	}()
+	return released
+}
+
+func TestQueuePriorityAndFIFO(t *testing.T) {
+	q := NewQueue(1)
+	hold, err := q.Acquire(context.Background(), PriorityManual, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan string, 4)
+	var releases []<-chan func()
+	for i, w := range []struct {
+		priority Priority
+		name     string
+	}{
+		{PriorityScheduled, "briefing"},
+		{PriorityAlert, "alert-1"},
+		{PriorityManual, "manual"},
+		{PriorityAlert, "alert-2"},
+	} {
+		releases = append(releases, acquireAsync(q, w.priority, w.name, order))
+		waitQueued(t, q, i+1)
+	}
+
+	// 每次只放行一个：手动优先，提醒次之且先到先得，定时任务最后
+	var got []string
+	release := hold
+	for range releases {
+		release()
+		name := <-order
+		got = append(got, name)
+		idx := map[string]int{"briefing": 0, "alert-1": 1, "manual": 2, "alert-2": 3}[name]
+		release = <-releases[idx]
+	}
+	release()
+	if want := []string{"manual", "alert-1", "alert-2", "briefing"}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if running, waiting := q.Stats(); running != 0 || waiting != 0 {
+		t.Errorf("stats = %d running, %d waiting", running, waiting)
+	}
+}
+
+func TestQueuePositions(t *testing.T) {
+	q := NewQueue(1)
+	hold, _ := q.Acquire(context.Background(), PriorityManual, nil)
+
+	var mu sync.Mutex
+	positions := map[string][]int{}
+	record := func(name string) func(int) {
+		return func(p int) {
+			mu.Lock()
+			positions[name] = append(positions[name], p)
+			mu.Unlock()
+		}
+	}
+	go q.Acquire(context.Background(), PriorityScheduled, record("scheduled"))
+	waitQueued(t, q, 1)
+	go q.Acquire(context.Background(), PriorityManual, record("manual"))
+	waitQueued(t, q, 2)
+
+	// 位置通知在解锁后发出，等待回调完成
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		n := len(positions["scheduled"]) + len(positions["manual"])
+		mu.Unlock()
+		if n >= 3 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mu.Lock()
+	// 插队的手动会议排到第 1 位，定时任务后移到第 2 位
+	if !slices.Equal(positions["scheduled"], []int{1, 2}) || !slices.Equal(positions["manual"], []int{1}) {
+		t.Errorf("positions = %v", positions)
+	}
+	mu.Unlock()
+	hold()
+}
+
+func TestQueueSetMaxConcurrentWidens(t *testing.T) {
+	q := NewQueue(1)
+	hold, _ := q.Acquire(context.Background(), PriorityManual, nil)
+	order := make(chan string, 2)
+	r1 := acquireAsync(q, PriorityManual, "a", order)
+	waitQueued(t, q, 1)
+	r2 := acquireAsync(q, PriorityManual, "b", order)
+	waitQueued(t, q, 2)
+
+	// 调大上限立即放行排队中的会议
+	q.SetMaxConcurrent(3)
+	<-order
+	<-order
+	if running, waiting := q.Stats(); running != 3 || waiting != 0 {
+		t.Errorf("stats = %d running, %d waiting", running, waiting)
+	}
+	hold()
+	(<-r1)()
+	(<-r2)()
+
+	// 非正数恢复默认上限
+	q.SetMaxConcurrent(0)
+	if q.maxConcurrent != DefaultMaxConcurrentMeetings {
+		t.Errorf("maxConcurrent = %d", q.maxConcurrent)
+	}
+}
+
+func TestQueueCancelWhileQueued(t *testing.T) {
+	q := NewQueue(1)
+	hold, _ := q.Acquire(context.Background(), PriorityManual, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := q.Acquire(ctx, PriorityManual, nil)
+		done <- err
+	}()
+	order := make(chan string, 1)
+	after := acquireAsync(q, PriorityScheduled, "after", order)
+	waitQueued(t, q, 2)
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("canceled acquire err = %v", err)
+	}
+	// 取消的会议离开队列，后面的会议前移并在名额归还后放行
+	waitQueued(t, q, 1)
+	hold()
+	if name := <-order; name != "after" {
+		t.Errorf("next = %s", name)
+	}
+	(<-after)()
+	if running, waiting := q.Stats(); running != 0 || waiting != 0 {
+		t.Errorf("stats = %d running, %d waiting", running, waiting)
+	}
+}
+
+func TestQueueCancelRacesWithGrant(t *testing.T) {
+	for range 200 {
+		q := NewQueue(1)
+		if _, err := q.Acquire(context.Background(), PriorityManual, nil); err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		type result struct {
+			release func()
+			err     error
+		}
+		done := make(chan result)
+		go func() {
+			release, err := q.Acquire(ctx, PriorityManual, nil)
+			done <- result{release, err}
+		}()
+		waitQueued(t, q, 1)
+
+		// 持锁期间同时取消并分配名额，排队者两个分支都可能走到
+		q.mu.Lock()
+		cancel()
+		q.running--
+		notify := q.dispatch()
+		q.mu.Unlock()
+		notify()
+
+		r := <-done
+		if r.err == nil {
+			r.release()
+		} else if r.err != context.Canceled {
+			t.Fatalf("err = %v", r.err)
+		}
+		// 无论哪个分支，分配到的名额都必须归还
+		if running, waiting := q.Stats(); running != 0 || waiting != 0 {
+			t.Fatalf("slot leaked: %d running, %d waiting", running, waiting)
+		}
+	}
+}
+
+func TestQueueDoubleRelease(t *testing.T) {
+	q := NewQueue(1)
+	release, _ := q.Acquire(context.Background(), PriorityManual, nil)
+	order := make(chan string, 2)
+	first := acquireAsync(q, PriorityManual, "first", order)
+	waitQueued(t, q, 1)
+	second := acquireAsync(q, PriorityManual, "second", order)
+	waitQueued(t, q, 2)
+
+	release()
+	<-order
+	// 重复归还不会多放行一个会议
+	release()
+	if running, waiting := q.Stats(); running != 1 || waiting != 1 {
+		t.Fatalf("after double release: %d running, %d waiting", running, waiting)
+	}
+	(<-first)()
+	<-order
+	(<-second)()
+	if running, waiting := q.Stats(); running != 0 || waiting != 0 {
+		t.Errorf("stats = %d running, %d waiting", running, waiting)
+	}
+}
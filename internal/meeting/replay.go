@@ -0,0 +1,231 @@
+package meeting
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/pkg/vault"
+)
+
+// MaxReplays 最多保留的会议回放数，超出时删除最早的回放
+const MaxReplays = 200
+
+// ReplayEvent 回放中的一条事件：进度事件或专家发言，Offset 为距会议开始的毫秒数
+type ReplayEvent struct {
+	Offset   int64          `json:"offset"`
+	Progress *ProgressEvent `json:"progress,omitempty"`
+	Response *ChatResponse  `json:"response,omitempty"`
+}
+
+// MeetingReplay 一场会议的完整事件流
+type MeetingReplay struct {
+	MeetingID string        `json:"meetingId"`
+	StockCode string        `json:"stockCode"`
+	StockName string        `json:"stockName"`
+	Query     string        `json:"query"`
+	Mode      string        `json:"mode"`
+	StartedAt int64         `json:"startedAt"`
+	EndedAt   int64         `json:"endedAt"`
+	Events    []ReplayEvent `json:"events"`
+}
+
+// ReplaySummary 会议回放概要（列表展示用）
+type ReplaySummary struct {
+	MeetingID string `json:"meetingId"`
+	StockCode string `json:"stockCode"`
+	StockName string `json:"stockName"`
+	Query     string `json:"query"`
+	Mode      string `json:"mode"`
+	StartedAt int64  `json:"startedAt"`
+	EndedAt   int64  `json:"endedAt"`
+	Events    int    `json:"events"`
+}
+
+// ReplayStore 会议回放存储，每场会议一个文件（启用数据加密时加密保存）
+type ReplayStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewReplayStore 创建会议回放存储
+func NewReplayStore(dir string) *ReplayStore {
+	return &ReplayStore{dir: dir}
+}
+
+// path 回放文件路径，会议 ID 必须是合法 UUID，防止路径穿越
+func (s *ReplayStore) path(meetingID string) (string, error) {
+	if _, err := uuid.Parse(meetingID); err != nil {
+		return "", i18n.NewError("无效的会议 ID")
+	}
+	return filepath.Join(s.dir, meetingID+".json"), nil
+}
+
+// Save 保存回放并清理超出数量上限的旧回放
+func (s *ReplayStore) Save(replay *MeetingReplay) error {
+	target, err := s.path(replay.MeetingID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(replay)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	if err := vault.WriteFile(target, data); err != nil {
+		return err
+	}
+	s.pruneLocked()
+	return nil
+}
+
+// pruneLocked 按修改时间删除最早的回放(需要已持有锁)
+func (s *ReplayStore) pruneLocked() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil || len(entries) <= MaxReplays {
+		return
+	}
+	type file struct {
+		name    string
+		modTime time.Time
+	}
+	var files []file
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, file{e.Name(), info.ModTime()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for i := 0; i < len(files)-MaxReplays; i++ {
+		os.Remove(filepath.Join(s.dir, files[i].name))
+	}
+}
+
+// Get 读取会议回放
+func (s *ReplayStore) Get(meetingID string) (*MeetingReplay, error) {
+	target, err := s.path(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := vault.ReadFile(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, i18n.NewError("会议回放不存在")
+		}
+		return nil, err
+	}
+	var replay MeetingReplay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, err
+	}
+	return &replay, nil
+}
+
+// List 列出回放概要（最新的在前），stockCode 为空时返回全部
+func (s *ReplayStore) List(stockCode string) []ReplaySummary {
+	files, _ := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	result := make([]ReplaySummary, 0, len(files))
+	for _, f := range files {
+		replay, err := s.Get(strings.TrimSuffix(filepath.Base(f), ".json"))
+		if err != nil || (stockCode != "" && replay.StockCode != stockCode) {
+			continue
+		}
+		result = append(result, ReplaySummary{
+			MeetingID: replay.MeetingID,
+			StockCode: replay.StockCode,
+			StockName: replay.StockName,
+			Query:     replay.Query,
+			Mode:      replay.Mode,
+			StartedAt: replay.StartedAt,
+			EndedAt:   replay.EndedAt,
+			Events:    len(replay.Events),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt > result[j].StartedAt })
+	return result
+}
+
+// DeleteByStock 删除股票的全部回放（清空会话记录时调用）
+func (s *ReplayStore) DeleteByStock(stockCode string) {
+	for _, summary := range s.List(stockCode) {
+		if target, err := s.path(summary.MeetingID); err == nil {
+			os.Remove(target)
+		}
+	}
+}
+
+// replayRecorder 记录单场会议的事件流
+type replayRecorder struct {
+	store  *ReplayStore
+	start  time.Time
+	mu     sync.Mutex
+	replay MeetingReplay
+}
+
+// SetReplayStore 设置会议回放存储，为 nil 时不记录回放
+func (s *Service) SetReplayStore(store *ReplayStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replayStore = store
+}
+
+// startReplay 开始记录本场会议的事件流（未设置回放存储时不记录）
+func (s *Service) startReplay(run *meetingRun, req ChatRequest, mode string) {
+	s.mu.RLock()
+	store := s.replayStore
+	s.mu.RUnlock()
+	if store == nil {
+		return
+	}
+	now := time.Now()
+	run.replay = &replayRecorder{
+		store: store,
+		start: now,
+		replay: MeetingReplay{
+			MeetingID: run.id,
+			StockCode: req.StockCode,
+			StockName: req.Stock.Name,
+			Query:     req.Query,
+			Mode:      mode,
+			StartedAt: now.UnixMilli(),
+		},
+	}
+}
+
+// add 追加事件（nil 安全）
+func (r *replayRecorder) add(event ReplayEvent) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.Offset = time.Since(r.start).Milliseconds()
+	r.replay.Events = append(r.replay.Events, event)
+}
+
+// save 保存当前已记录的事件流；恢复中断会议后再次保存会覆盖为完整记录（nil 安全）
+func (r *replayRecorder) save() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.replay.EndedAt = time.Now().UnixMilli()
+	replay := r.replay
+	replay.Events = append([]ReplayEvent(nil), r.replay.Events...)
+	r.mu.Unlock()
+
+	if err := r.store.Save(&replay); err != nil {
+		log.Warn("save meeting replay %s error: %v", replay.MeetingID, err)
+	}
+}
@@ -0,0 +1,471 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/pkg/vault"
+)
+
+// 深度研究配置常量
+const (
+	ResearchTimeout     = 30 * time.Minute // 整个深度研究的最大时长（单步仍受 AgentTimeout 限制）
+	ResearchMinSteps    = 3                // 研究计划最少步数
+	ResearchMaxSteps    = 6                // 研究计划最多步数
+	MaxResearchSessions = 50               // 最多保留的研究检查点数，超出时删除最早的
+)
+
+// 深度研究状态
+const (
+	ResearchRunning = "running" // 进行中或异常中断（可恢复）
+	ResearchFailed  = "failed"  // 出错中止（可恢复）
+	ResearchDone    = "done"    // 已完成
+)
+
+// MsgTypeResearch 深度研究报告的消息类型
+const MsgTypeResearch = "research"
+
+// researchAgentInstruction 研究员的基础指令
+const researchAgentInstruction = `你是「财经会议室」的研究员，负责对单只股票做多步骤的深度研究。
+每一步只围绕当前子问题展开，必须调用工具获取一手数据（行情、K线、财报、资金流向、新闻公告、研报等），不要凭印象作答。
+结论要有数据支撑，标注数据口径与时间；数据缺失或相互矛盾时如实说明。`
+
+// researchPlanPrompt 生成研究计划的提示
+const researchPlanPrompt = `老韭菜委托你做一次深度研究，课题：%s
+请先拟定研究计划：拆分为 %d-%d 个相互独立、可以用工具数据回答的子问题（如经营与财务、估值、资金与筹码、技术面、行业与竞争、消息与催化、风险），
+按研究顺序每行输出一个，格式为「1. 子问题」，不要输出其他内容。`
+
+// researchStepPrompt 执行单个研究步骤的提示
+const researchStepPrompt = `深度研究课题：%s
+当前是第 %d/%d 步，子问题：%s
+请至少调用两次工具获取数据后作答，输出本步研究笔记：关键数据、发现与初步判断，300字以内。`
+
+// researchReportPrompt 撰写研究报告的提示
+const researchReportPrompt = `深度研究课题：%s
+各步研究笔记已在上下文中给出。请据此撰写完整的深度研究报告，使用 Markdown，按以下结构：
+## 核心结论
+## 研究发现（按子问题分节，引用笔记中的关键数据）
+## 多空因素
+## 风险提示
+## 跟踪要点
+不要再调用工具，不要编造笔记中没有的数据，总字数1500字以内。`
+
+// researchPlanLine 研究计划中的编号行
+var researchPlanLine = regexp.MustCompile(`^\s*(?:\d+[.、)）]|[-*•])\s*`)
+
+// ResearchNote 研究步骤的笔记，每完成一步保存一次检查点
+type ResearchNote struct {
+	Step      int                     `json:"step"`
+	Question  string                  `json:"question"`
+	Content   string                  `json:"content"`
+	ToolCalls []models.ToolCallRecord `json:"toolCalls,omitempty"`
+	CreatedAt int64                   `json:"createdAt"`
+}
+
+// ResearchCheckpoint 深度研究的检查点：研究计划、已完成步骤的笔记与最终报告
+type ResearchCheckpoint struct {
+	ID        string         `json:"id"`
+	StockCode string         `json:"stockCode"`
+	StockName string         `json:"stockName"`
+	Query     string         `json:"query"`
+	Plan      []string       `json:"plan"`
+	Notes     []ResearchNote `json:"notes"`
+	Report    string         `json:"report,omitempty"`
+	Status    string         `json:"status"`
+	Error     string         `json:"error,omitempty"`
+	StartedAt int64          `json:"startedAt"`
+	UpdatedAt int64          `json:"updatedAt"`
+}
+
+// toolCallCount 已完成步骤的工具调用总数
+func (c *ResearchCheckpoint) toolCallCount() int {
+	n := 0
+	for _, note := range c.Notes {
+		n += len(note.ToolCalls)
+	}
+	return n
+}
+
+// ResearchSummary 深度研究概要（列表展示用）
+type ResearchSummary struct {
+	ID        string `json:"id"`
+	StockCode string `json:"stockCode"`
+	Query     string `json:"query"`
+	Status    string `json:"status"`
+	Steps     int    `json:"steps"`     // 研究计划步数，0 表示尚未拟定计划
+	Completed int    `json:"completed"` // 已完成步数
+	Error     string `json:"error,omitempty"`
+	StartedAt int64  `json:"startedAt"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// ResearchStore 深度研究检查点存储，每次研究一个文件（启用数据加密时加密保存）
+type ResearchStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewResearchStore 创建深度研究检查点存储
+func NewResearchStore(dir string) *ResearchStore {
+	return &ResearchStore{dir: dir}
+}
+
+// path 检查点文件路径，研究 ID 必须是合法 UUID，防止路径穿越
+func (s *ResearchStore) path(researchID string) (string, error) {
+	if _, err := uuid.Parse(researchID); err != nil {
+		return "", i18n.NewError("无效的研究 ID")
+	}
+	return filepath.Join(s.dir, researchID+".json"), nil
+}
+
+// Save 保存检查点并清理超出数量上限的已完成研究
+func (s *ResearchStore) Save(cp *ResearchCheckpoint) error {
+	target, err := s.path(cp.ID)
+	if err != nil {
+		return err
+	}
+	cp.UpdatedAt = time.Now().UnixMilli()
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	if err := vault.WriteFile(target, data); err != nil {
+		return err
+	}
+	if cp.Status == ResearchDone {
+		s.pruneLocked()
+	}
+	return nil
+}
+
+// pruneLocked 按修改时间删除最早的检查点(需要已持有锁)
+func (s *ResearchStore) pruneLocked() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil || len(entries) <= MaxResearchSessions {
+		return
+	}
+	type file struct {
+		name    string
+		modTime time.Time
+	}
+	var files []file
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, file{e.Name(), info.ModTime()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for i := 0; i < len(files)-MaxResearchSessions; i++ {
+		os.Remove(filepath.Join(s.dir, files[i].name))
+	}
+}
+
+// Get 读取检查点
+func (s *ResearchStore) Get(researchID string) (*ResearchCheckpoint, error) {
+	target, err := s.path(researchID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := vault.ReadFile(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, i18n.NewError("深度研究不存在")
+		}
+		return nil, err
+	}
+	var cp ResearchCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// List 列出研究概要（最新的在前），stockCode 为空时返回全部
+func (s *ResearchStore) List(stockCode string) []ResearchSummary {
+	files, _ := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	result := make([]ResearchSummary, 0, len(files))
+	for _, f := range files {
+		cp, err := s.Get(strings.TrimSuffix(filepath.Base(f), ".json"))
+		if err != nil || (stockCode != "" && cp.StockCode != stockCode) {
+			continue
+		}
+		result = append(result, ResearchSummary{
+			ID:        cp.ID,
+			StockCode: cp.StockCode,
+			Query:     cp.Query,
+			Status:    cp.Status,
+			Steps:     len(cp.Plan),
+			Completed: len(cp.Notes),
+			Error:     cp.Error,
+			StartedAt: cp.StartedAt,
+			UpdatedAt: cp.UpdatedAt,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt > result[j].StartedAt })
+	return result
+}
+
+// DeleteByStock 删除股票的全部研究检查点（清空会话记录时调用）
+func (s *ResearchStore) DeleteByStock(stockCode string) {
+	for _, summary := range s.List(stockCode) {
+		if target, err := s.path(summary.ID); err == nil {
+			os.Remove(target)
+		}
+	}
+}
+
+// SetResearchStore 设置深度研究检查点存储
+func (s *Service) SetResearchStore(store *ResearchStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.researchStore = store
+}
+
+// NewResearch 创建深度研究检查点，之后调用 RunResearch 执行
+func (s *Service) NewResearch(stockCode string, stock models.Stock, query string) (*ResearchCheckpoint, error) {
+	s.mu.RLock()
+	store := s.researchStore
+	s.mu.RUnlock()
+	if store == nil {
+		return nil, i18n.NewError("未启用深度研究")
+	}
+	cp := &ResearchCheckpoint{
+		ID:        uuid.New().String(),
+		StockCode: stockCode,
+		StockName: stock.Name,
+		Query:     query,
+		Status:    ResearchRunning,
+		StartedAt: time.Now().UnixMilli(),
+	}
+	return cp, store.Save(cp)
+}
+
+// researchAgentConfig 研究员的 Agent 配置（只可调用只读数据工具）
+func (s *Service) researchAgentConfig() models.AgentConfig {
+	cfg := models.AgentConfig{
+		ID:          "researcher",
+		Name:        "研究员",
+		Role:        "深度研究",
+		Instruction: researchAgentInstruction,
+		Enabled:     true,
+	}
+	if s.toolRegistry != nil {
+		cfg.Tools = slices.Clone(readOnlyTools)
+	}
+	return cfg
+}
+
+// RunResearch 执行或恢复深度研究：拟定计划 → 逐步调用工具研究并保存检查点 → 撰写长篇报告
+// 从检查点中已完成的位置继续，中断（出错、取消或程序崩溃）后可再次调用恢复
+func (s *Service) RunResearch(ctx context.Context, aiConfig *models.AIConfig, researchID string, stock models.Stock, position *models.StockPosition, progressCallback ProgressCallback) (ChatResponse, error) {
+	if aiConfig == nil {
+		return ChatResponse{}, ErrNoAIConfig
+	}
+	s.mu.RLock()
+	store := s.researchStore
+	s.mu.RUnlock()
+	if store == nil {
+		return ChatResponse{}, i18n.NewError("未启用深度研究")
+	}
+	cp, err := store.Get(researchID)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	release, err := s.waitTurn(ctx, PriorityManual, progressCallback)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	defer release()
+
+	run := s.newRun(aiConfig)
+	researchCtx, cancel := context.WithTimeout(ctx, ResearchTimeout)
+	defer cancel()
+
+	started := time.Now()
+	cp.Status, cp.Error = ResearchRunning, ""
+	if err := s.researchSteps(researchCtx, run, aiConfig, store, cp, &stock, position, progressCallback); err != nil {
+		cp.Status, cp.Error = ResearchFailed, err.Error()
+		if saveErr := store.Save(cp); saveErr != nil {
+			log.Error("save research checkpoint %s failed: %v", cp.ID, saveErr)
+		}
+		return ChatResponse{}, err
+	}
+
+	cp.Status = ResearchDone
+	if err := store.Save(cp); err != nil {
+		log.Error("save research checkpoint %s failed: %v", cp.ID, err)
+	}
+	log.Info("research %s done, %d steps, cost: %s", cp.ID, len(cp.Notes), run.tracker.Summary())
+
+	cfg := s.researchAgentConfig()
+	resp := ChatResponse{
+		AgentID:       cfg.ID,
+		AgentName:     cfg.Name,
+		Role:          cfg.Role,
+		Content:       cp.Report,
+		MsgType:       MsgTypeResearch,
+		DurationMs:    time.Since(started).Milliseconds(),
+		ToolCallCount: cp.toolCallCount(),
+		ModelName:     aiConfig.ModelName,
+	}
+	for _, note := range cp.Notes {
+		resp.ToolCalls = append(resp.ToolCalls, note.ToolCalls...)
+	}
+	return run.stamp([]ChatResponse{resp})[0], nil
+}
+
+// researchSteps 按检查点依次执行尚未完成的研究步骤，每步完成后保存检查点
+func (s *Service) researchSteps(ctx context.Context, run *meetingRun, aiConfig *models.AIConfig, store *ResearchStore, cp *ResearchCheckpoint, stock *models.Stock, position *models.StockPosition, progressCallback ProgressCallback) error {
+	modelCtx, modelCancel := context.WithTimeout(ctx, ModelCreationTimeout)
+	llm, err := s.createModel(modelCtx, aiConfig, run.tracker)
+	modelCancel()
+	if err != nil {
+		return err
+	}
+	builder := s.createBuilder(llm, aiConfig)
+	builder.SetLongForm()
+	cfg := s.researchAgentConfig()
+
+	var memoryContext string
+	if s.memoryManager != nil {
+		if stockMemory, err := s.memoryManager.GetOrCreate(stock.Symbol, stock.Name); err == nil {
+			memoryContext = run.budget.memory(ctx, s.memoryManager.BuildContext(stockMemory, cp.Query))
+		}
+	}
+	if run.settings.userProfile != "" {
+		memoryContext = run.settings.userProfile + "\n" + memoryContext
+	}
+
+	step := func(query, replyContent string, recorder *toolRecorder) (string, error) {
+		return retryRun(ctx, MaxAgentRetries, func() (string, error) {
+			stepCtx, stepCancel := context.WithTimeout(ctx, AgentTimeout)
+			defer stepCancel()
+			return s.runSingleAgent(stepCtx, builder, &cfg, stock, query, replyContent, progressCallback, position, recorder)
+		})
+	}
+
+	if len(cp.Plan) == 0 {
+		emitResearchProgress(progressCallback, cfg, "拟定研究计划", "")
+		planCfg := cfg
+		planCfg.Tools = nil
+		plan, err := retryRun(ctx, MaxAgentRetries, func() (string, error) {
+			stepCtx, stepCancel := context.WithTimeout(ctx, ModeratorTimeout)
+			defer stepCancel()
+			return s.runSingleAgent(stepCtx, builder, &planCfg, stock,
+				fmt.Sprintf(researchPlanPrompt, cp.Query, ResearchMinSteps, ResearchMaxSteps), memoryContext, nil, position, nil)
+		})
+		if err != nil {
+			return fmt.Errorf("拟定研究计划失败: %w", err)
+		}
+		cp.Plan = parseResearchPlan(plan)
+		if len(cp.Plan) == 0 {
+			return i18n.NewError("未能拟定研究计划")
+		}
+		if err := store.Save(cp); err != nil {
+			return err
+		}
+		emitResearchProgress(progressCallback, cfg, "研究计划", strings.Join(cp.Plan, "\n"))
+	}
+
+	for i := len(cp.Notes); i < len(cp.Plan); i++ {
+		if s.checkBudget(run.tracker, progressCallback) {
+			return i18n.NewError("研究费用已超出预算")
+		}
+		question := cp.Plan[i]
+		emitResearchProgress(progressCallback, cfg, fmt.Sprintf("第 %d/%d 步：%s", i+1, len(cp.Plan), question), "")
+		recorder := newToolRecorder(cfg.ID)
+		content, err := step(fmt.Sprintf(researchStepPrompt, cp.Query, i+1, len(cp.Plan), question),
+			memoryContext+researchNotesContext(cp.Notes), recorder)
+		if err != nil {
+			return fmt.Errorf("第 %d 步研究失败: %w", i+1, err)
+		}
+		cp.Notes = append(cp.Notes, ResearchNote{
+			Step:      i + 1,
+			Question:  question,
+			Content:   content,
+			ToolCalls: recorder.records(),
+			CreatedAt: time.Now().UnixMilli(),
+		})
+		if err := store.Save(cp); err != nil {
+			return err
+		}
+		emitCostUpdate(progressCallback, run.tracker)
+	}
+
+	if cp.Report == "" {
+		emitResearchProgress(progressCallback, cfg, "撰写研究报告", "")
+		reportCfg := cfg
+		reportCfg.Tools = nil
+		report, err := retryRun(ctx, MaxAgentRetries, func() (string, error) {
+			stepCtx, stepCancel := context.WithTimeout(ctx, AgentTimeout)
+			defer stepCancel()
+			return s.runSingleAgent(stepCtx, builder, &reportCfg, stock,
+				fmt.Sprintf(researchReportPrompt, cp.Query), researchNotesContext(cp.Notes), progressCallback, position, nil)
+		})
+		if err != nil {
+			return fmt.Errorf("撰写研究报告失败: %w", err)
+		}
+		cp.Report = report
+	}
+	return nil
+}
+
+// emitResearchProgress 推送深度研究的阶段进度
+func emitResearchProgress(cb ProgressCallback, cfg models.AgentConfig, detail, content string) {
+	emitProgress(cb, ProgressEvent{
+		Type: "research_step", AgentID: cfg.ID, AgentName: cfg.Name,
+		Detail: detail, Content: content,
+	})
+}
+
+// researchNotesContext 将已完成的研究笔记拼接为上下文
+func researchNotesContext(notes []ResearchNote) string {
+	if len(notes) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n【已完成的研究笔记】\n")
+	for _, note := range notes {
+		fmt.Fprintf(&sb, "### 第 %d 步：%s\n%s\n\n", note.Step, note.Question, note.Content)
+	}
+	return sb.String()
+}
+
+// parseResearchPlan 从计划文本中提取子问题，最多 ResearchMaxSteps 个
+func parseResearchPlan(text string) []string {
+	var plan []string
+	for _, line := range strings.Split(text, "\n") {
+		if !researchPlanLine.MatchString(line) {
+			continue
+		}
+		question := strings.TrimSpace(researchPlanLine.ReplaceAllString(line, ""))
+		question = strings.Trim(question, "*")
+		if question == "" {
+			continue
+		}
+		plan = append(plan, question)
+		if len(plan) == ResearchMaxSteps {
+			break
+		}
+	}
+	return plan
+}
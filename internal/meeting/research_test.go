@@ -0,0 +1,189 @@
+package meeting
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/fixture"
+)
+
+func TestParseResearchPlan(t *testing.T) {
+	text := "好的，研究计划如下：\n" +
+		"1. 经营与财务\n" +
+		"2、估值水平\n" +
+		"3) **资金与筹码**\n" +
+		"- 技术面\n" +
+		"4.\n" +
+		"• 行业与竞争\n" +
+		"* 消息与催化\n" +
+		"5. 风险\n"
+	want := []string{"经营与财务", "估值水平", "资金与筹码", "技术面", "行业与竞争", "消息与催化"}
+	if got := parseResearchPlan(text); !slices.Equal(got, want) {
+		t.Errorf("plan = %q, want %q", got, want)
+	}
+	if got := parseResearchPlan("无法拟定计划"); len(got) != 0 {
+		t.Errorf("plan without numbered lines = %q", got)
+	}
+}
+
+func TestResearchStore(t *testing.T) {
+	store := NewResearchStore(t.TempDir())
+	older := &ResearchCheckpoint{ID: uuid.New().String(), StockCode: "sh600519", Query: "茅台", Plan: []string{"估值"}, Status: ResearchRunning, StartedAt: 1}
+	newer := &ResearchCheckpoint{ID: uuid.New().String(), StockCode: "sh600519", Query: "茅台2", Status: ResearchFailed, Error: "timeout", StartedAt: 2}
+	other := &ResearchCheckpoint{ID: uuid.New().String(), StockCode: "sz000001", Query: "平安", Status: ResearchDone, StartedAt: 3}
+	for _, cp := range []*ResearchCheckpoint{older, newer, other} {
+		if err := store.Save(cp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.Get(older.ID)
+	if err != nil || got.Query != "茅台" || len(got.Plan) != 1 || got.UpdatedAt == 0 {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+	if _, err := store.Get(uuid.New().String()); err == nil {
+		t.Error("missing research returned no error")
+	}
+	// 研究 ID 必须是 UUID，防止路径穿越
+	if _, err := store.Get("../config"); err == nil {
+		t.Error("invalid research id accepted")
+	}
+	if err := store.Save(&ResearchCheckpoint{ID: "../../evil"}); err == nil {
+		t.Error("saved checkpoint with invalid id")
+	}
+
+	list := store.List("sh600519")
+	if len(list) != 2 || list[0].ID != newer.ID || list[1].ID != older.ID {
+		t.Fatalf("List = %+v", list)
+	}
+	if list[1].Steps != 1 || list[1].Completed != 0 || list[0].Error != "timeout" {
+		t.Errorf("summary = %+v", list)
+	}
+	if all := store.List(""); len(all) != 3 {
+		t.Errorf("List all = %d entries", len(all))
+	}
+
+	store.DeleteByStock("sh600519")
+	if all := store.List(""); len(all) != 1 || all[0].ID != other.ID {
+		t.Errorf("after DeleteByStock = %+v", all)
+	}
+}
+
+func TestResearchStorePrune(t *testing.T) {
+	dir := t.TempDir()
+	store := NewResearchStore(dir)
+	base := time.Now().Add(-time.Hour)
+	var ids []string
+	for i := range MaxResearchSessions {
+		id := uuid.New().String()
+		path := filepath.Join(dir, id+".json")
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mod := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	// 非 json 文件不参与清理
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 进行中的研究保存时不清理
+	running := &ResearchCheckpoint{ID: uuid.New().String(), Status: ResearchRunning}
+	if err := store.Save(running); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ids[0]+".json")); err != nil {
+		t.Fatalf("pruned while running: %v", err)
+	}
+
+	// 完成时删除最早的检查点，保留 MaxResearchSessions 个
+	running.Status = ResearchDone
+	if err := store.Save(running); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != MaxResearchSessions {
+		t.Fatalf("files = %d, want %d", len(files), MaxResearchSessions)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ids[0]+".json")); !os.IsNotExist(err) {
+		t.Errorf("oldest checkpoint kept: %v", err)
+	}
+	for _, path := range []string{filepath.Join(dir, ids[1]+".json"), filepath.Join(dir, running.ID+".json"), filepath.Join(dir, "notes.txt")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s removed: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestRunResearchResumesAfterCompletedStep(t *testing.T) {
+	llm := fixture.NewLLM("暂无结论。").
+		On("撰写完整的深度研究报告", "## 核心结论\n估值合理，资金面偏弱。").
+		On("子问题：资金与筹码", "主力连续三日净流出。").
+		On("子问题：技术面", "站上20日均线。")
+	s := newFixtureService(llm)
+	store := NewResearchStore(t.TempDir())
+	s.SetResearchStore(store)
+
+	cp, err := s.NewResearch("sh600519", models.Stock{Symbol: "sh600519", Name: "贵州茅台"}, "茅台值得长期持有吗")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 模拟第一步完成后中断
+	cp.Plan = []string{"估值水平", "资金与筹码", "技术面"}
+	cp.Notes = []ResearchNote{{Step: 1, Question: "估值水平", Content: "PE 23.5，处于历史中枢。"}}
+	cp.Status, cp.Error = ResearchFailed, "context canceled"
+	if err := store.Save(cp); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.RunResearch(context.Background(), &models.AIConfig{Provider: models.AIProviderOpenAI, ModelName: "fixture"},
+		cp.ID, models.Stock{Symbol: "sh600519", Name: "贵州茅台"}, nil, nil)
+	if err != nil {
+		t.Fatalf("RunResearch: %v", err)
+	}
+	if resp.MsgType != MsgTypeResearch || !strings.Contains(resp.Content, "估值合理") {
+		t.Errorf("response = %+v", resp)
+	}
+
+	// 不重新拟定计划，也不重跑已完成的第一步
+	for _, p := range llm.Prompts() {
+		if strings.Contains(p, "请先拟定研究计划") || strings.Contains(p, "子问题：估值水平") {
+			t.Errorf("completed work rerun:\n%s", p)
+		}
+	}
+	// 后续步骤能看到已完成的笔记
+	var stepPrompt string
+	for _, p := range llm.Prompts() {
+		if strings.Contains(p, "子问题：资金与筹码") {
+			stepPrompt = p
+		}
+	}
+	if !strings.Contains(stepPrompt, "PE 23.5") {
+		t.Errorf("step 2 prompt missing previous note:\n%s", stepPrompt)
+	}
+
+	saved, err := store.Get(cp.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var steps []string
+	for _, note := range saved.Notes {
+		steps = append(steps, fmt.Sprintf("%d:%s", note.Step, note.Content))
+	}
+	want := []string{"1:PE 23.5，处于历史中枢。", "2:主力连续三日净流出。", "3:站上20日均线。"}
+	if saved.Status != ResearchDone || saved.Error != "" || !slices.Equal(steps, want) || saved.Report == "" {
+		t.Errorf("checkpoint = %+v, notes = %q", saved, steps)
+	}
+}
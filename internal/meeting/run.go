@@ -0,0 +1,101 @@
+package meeting
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/run-bigpig/jcp/internal/memory"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/model"
+)
+
+// meetingSettings 会议配置快照：开会时读取一次，会议进行中修改设置不影响本场会议
+type meetingSettings struct {
+	moderatorAI *models.AIConfig
+	summaryAI   *models.AIConfig
+	memoryAI    *models.AIConfig
+	extractAI   *models.AIConfig
+	budget      float64
+	userProfile string
+	context     contextLimits
+}
+
+// settings 读取当前配置快照
+func (s *Service) settings() meetingSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return meetingSettings{
+		moderatorAI: s.moderatorAIConfig,
+		summaryAI:   s.summaryAIConfig,
+		memoryAI:    s.memoryAIConfig,
+		extractAI:   s.extractAIConfig,
+		budget:      s.meetingBudget,
+		userProfile: s.userProfile,
+		context:     s.contextLimits,
+	}
+}
+
+// meetingRun 单场会议的运行上下文：会议 ID、配置快照、费用累计与记忆视图
+// 每场会议独立持有，多只股票同时开会时模型、费用与回调互不串扰
+type meetingRun struct {
+	id       string
+	settings meetingSettings
+	tracker  *CostTracker
+	memory   *memory.Scope    // 本场会议的记忆 LLM 视图，未启用记忆时为 nil
+	replay   *replayRecorder  // 本场会议的事件流记录，未启用回放时为 nil
+	budget   *contextBudgeter // 本场会议的上下文预算
+}
+
+// newRun 创建会议运行上下文，上下文预算按会议模型的分词器计数
+func (s *Service) newRun(aiConfig *models.AIConfig) *meetingRun {
+	settings := s.settings()
+	return &meetingRun{
+		id:       uuid.New().String(),
+		settings: settings,
+		tracker:  NewCostTracker(settings.budget),
+		budget:   newContextBudgeter(settings.context, aiConfig),
+	}
+}
+
+// setupMemory 为本场会议创建记忆 LLM 视图（按任务路由，未配置则使用会议 LLM）
+func (s *Service) setupMemory(ctx context.Context, run *meetingRun, meetingLLM model.LLM) {
+	if s.memoryManager == nil {
+		return
+	}
+	memoryLLM := s.createTaskModel(ctx, models.AITaskMemory, run.settings.memoryAI, meetingLLM, nil)
+	extractLLM := s.createTaskModel(ctx, models.AITaskExtraction, run.settings.extractAI, memoryLLM, nil)
+	run.memory = s.memoryManager.Scoped(memoryLLM, extractLLM)
+}
+
+// responseCallback 包装发言回调，附带会议 ID
+func (r *meetingRun) responseCallback(cb ResponseCallback) ResponseCallback {
+	if cb == nil {
+		return nil
+	}
+	return func(resp ChatResponse) {
+		resp.MeetingID = r.id
+		r.replay.add(ReplayEvent{Response: &resp})
+		cb(resp)
+	}
+}
+
+// progressCallback 包装进度回调，附带会议 ID
+func (r *meetingRun) progressCallback(cb ProgressCallback) ProgressCallback {
+	if cb == nil {
+		return nil
+	}
+	return func(event ProgressEvent) {
+		event.MeetingID = r.id
+		r.replay.add(ReplayEvent{Progress: &event})
+		cb(event)
+	}
+}
+
+// stamp 为返回的发言附带会议 ID
+func (r *meetingRun) stamp(responses []ChatResponse) []ChatResponse {
+	for i := range responses {
+		responses[i].MeetingID = r.id
+	}
+	return responses
+}
@@ -4,17 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime/debug"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/adk/llmerr"
 	"github.com/run-bigpig/jcp/internal/adk/mcp"
 	"github.com/run-bigpig/jcp/internal/adk/openai"
 	"github.com/run-bigpig/jcp/internal/adk/tools"
 	"github.com/run-bigpig/jcp/internal/logger"
 	"github.com/run-bigpig/jcp/internal/memory"
 	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/model"
@@ -43,14 +46,18 @@ const (
 
 // 错误定义
 var (
-	ErrMeetingTimeout   = errors.New("会议超时，已返回部分结果")
-	ErrModeratorTimeout = errors.New("小韭菜响应超时")
-	ErrNoAIConfig       = errors.New("未配置 AI 服务")
-	ErrNoAgents         = errors.New("没有可用的专家")
+	ErrMeetingTimeout   = i18n.NewError("会议超时，已返回部分结果")
+	ErrModeratorTimeout = i18n.NewError("小韭菜响应超时")
+	ErrNoAIConfig       = i18n.NewError("未配置 AI 服务")
+	ErrNoAgents         = i18n.NewError("没有可用的专家")
+
+	// errInvalidAgent 专家配置无法构建 Agent（如工具冲突），重试也不会成功
+	errInvalidAgent = errors.New("invalid agent config")
 )
 
 // isRetryableError 判断错误是否可重试
-// 超时、主动取消、配置错误不重试；网络错误、API 临时错误可重试
+// 超时、主动取消、专家配置错误不重试；带 HTTP 状态码的按状态码判断（限流、服务端错误可重试），
+// 其余（网络错误、流式空闲超时等）视为临时错误可重试
 func isRetryableError(err error) bool {
 	if err == nil {
 		return false
@@ -58,11 +65,12 @@ func isRetryableError(err error) bool {
 	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
 		return false
 	}
-	msg := err.Error()
-	// 配置类错误不重试
-	if strings.Contains(msg, "config") || strings.Contains(msg, "not found") {
+	if errors.Is(err, errInvalidAgent) {
 		return false
 	}
+	if code := llmerr.StatusCode(err); code != 0 {
+		return llmerr.RetryableStatus(code)
+	}
 	return true
 }
 
@@ -119,32 +127,60 @@ type MeetingState struct {
 	MemoryContext  string               // 记忆上下文
 	StockMemory    *memory.StockMemory  // 股票记忆引用
 	Moderator      *Moderator           // 主持人引用（用于最终总结）
+	run            *meetingRun          // 原会议运行上下文（恢复后沿用会议 ID、费用累计与记忆视图）
 	CreatedAt      time.Time            // 创建时间（用于 TTL 清理）
 }
 
 // MeetingStateTTL 中断状态缓存过期时间
 const MeetingStateTTL = 10 * time.Minute
 
+// modelCreator 按 AI 配置创建模型，默认为 adk.ModelFactory，测试中可替换为脚本化模型
+type modelCreator interface {
+	CreateModel(ctx context.Context, config *models.AIConfig) (model.LLM, error)
+}
+
 // Service 会议室服务，编排多专家并行分析
 type Service struct {
-	modelFactory      *adk.ModelFactory
+	modelFactory      modelCreator
 	toolRegistry      *tools.Registry
 	mcpManager        *mcp.Manager
 	memoryManager     *memory.Manager
+	mu                sync.RWMutex             // 保护以下 AI 配置、预算与画像
 	memoryAIConfig    *models.AIConfig         // 记忆管理使用的 LLM 配置
 	moderatorAIConfig *models.AIConfig         // 意图分析(小韭菜)使用的 LLM 配置
+	summaryAIConfig   *models.AIConfig         // 会议总结使用的 LLM 配置
+	extractAIConfig   *models.AIConfig         // 关键点/事实提取使用的 LLM 配置
 	aiConfigResolver  AIConfigResolver         // AI配置解析器
 	meetingStates     map[string]*MeetingState // 中断的会议状态缓存，key: stockCode
 	meetingStatesMu   sync.RWMutex
+	decisionCache     *decisionCache      // 小韭菜意图分析结果缓存
+	clarifications    *clarificationStore // 等待老韭菜回答的追问
+	queue             *Queue              // 会议排队（限制同时进行的会议数）
+	meetingBudget     float64             // 单次会议费用上限（美元，0 表示不限制）
+	userProfile       string              // 老韭菜画像描述（注入小韭菜与专家提示词）
+	contextLimits     contextLimits       // 专家提示词上下文预算
+	replayStore       *ReplayStore        // 会议回放存储，为 nil 时不记录
+	researchStore     *ResearchStore      // 深度研究检查点存储
+	verdictWeigher    VerdictWeigher      // 专家观点权重（历史准确率），为 nil 时权重均为 1
+	snapshotFetcher   SnapshotFetcher     // 个股快照来源，为 nil 时不注入快照
+	snapshots         map[string]*snapshotEntry
+	snapshotMu        sync.Mutex
+	inflights         map[string]*inflight // 进行中的会议，key: stockCode
+	inflightMu        sync.Mutex
 }
 
 // NewServiceFull 创建完整配置的会议室服务
 func NewServiceFull(registry *tools.Registry, mcpMgr *mcp.Manager) *Service {
 	return &Service{
-		modelFactory:  adk.NewModelFactory(),
-		toolRegistry:  registry,
-		mcpManager:    mcpMgr,
-		meetingStates: make(map[string]*MeetingState),
+		modelFactory:   adk.NewModelFactory(),
+		toolRegistry:   registry,
+		mcpManager:     mcpMgr,
+		meetingStates:  make(map[string]*MeetingState),
+		inflights:      make(map[string]*inflight),
+		decisionCache:  newDecisionCache(DecisionCacheTTL),
+		clarifications: newClarificationStore(),
+		queue:          NewQueue(DefaultMaxConcurrentMeetings),
+		contextLimits:  newContextLimits(models.ContextBudgetConfig{}),
 	}
 }
 
@@ -155,19 +191,81 @@ func (s *Service) SetMemoryManager(memMgr *memory.Manager) {
 
 // SetMemoryAIConfig 设置记忆管理使用的 LLM 配置
 func (s *Service) SetMemoryAIConfig(aiConfig *models.AIConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.memoryAIConfig = aiConfig
 }
 
 // SetModeratorAIConfig 设置意图分析(小韭菜)使用的 LLM 配置
 func (s *Service) SetModeratorAIConfig(aiConfig *models.AIConfig) {
+	s.mu.Lock()
 	s.moderatorAIConfig = aiConfig
+	s.mu.Unlock()
+	// 模型变更后旧的决策结果不再可复用
+	s.decisionCache.Clear()
+}
+
+// SetSummaryAIConfig 设置会议总结使用的 LLM 配置（为空则沿用小韭菜）
+func (s *Service) SetSummaryAIConfig(aiConfig *models.AIConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summaryAIConfig = aiConfig
+}
+
+// SetExtractionAIConfig 设置关键点/事实提取使用的 LLM 配置（为空则沿用记忆 LLM）
+func (s *Service) SetExtractionAIConfig(aiConfig *models.AIConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.extractAIConfig = aiConfig
+}
+
+// SetMeetingBudget 设置单次会议费用上限（美元，0 表示不限制）
+func (s *Service) SetMeetingBudget(budget float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.meetingBudget = budget
+}
+
+// SetMaxConcurrentMeetings 设置同时进行的会议数上限（<= 0 使用默认值）
+func (s *Service) SetMaxConcurrentMeetings(n int) {
+	s.queue.SetMaxConcurrent(n)
+}
+
+// SetUserProfile 设置老韭菜画像
+func (s *Service) SetUserProfile(profile models.UserProfile) {
+	prompt := profile.PromptContext()
+	s.mu.Lock()
+	changed := prompt != s.userProfile
+	s.userProfile = prompt
+	s.mu.Unlock()
+	if !changed {
+		return
+	}
+	// 画像变更会影响专家选择与任务拆解
+	s.decisionCache.Clear()
+}
+
+// SetContextBudget 设置专家提示词的上下文预算
+func (s *Service) SetContextBudget(cfg models.ContextBudgetConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contextLimits = newContextLimits(cfg)
 }
 
 // SetAIConfigResolver 设置 AI 配置解析器
 func (s *Service) SetAIConfigResolver(resolver AIConfigResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.aiConfigResolver = resolver
 }
 
+// resolver 读取 AI 配置解析器
+func (s *Service) resolver() AIConfigResolver {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.aiConfigResolver
+}
+
 // ChatRequest 聊天请求
 type ChatRequest struct {
 	StockCode    string                `json:"stockCode"` // 股票代码（用于状态缓存 key）
@@ -176,26 +274,43 @@ type ChatRequest struct {
 	Agents       []models.AgentConfig  `json:"agents"`
 	Query        string                `json:"query"`
 	ReplyContent string                `json:"replyContent"`
-	AllAgents    []models.AgentConfig  `json:"allAgents"` // 所有可用专家（智能模式用）
-	Position     *models.StockPosition `json:"position"`  // 用户持仓信息
+	AllAgents    []models.AgentConfig  `json:"allAgents"`              // 所有可用专家（智能模式用）
+	Position     *models.StockPosition `json:"position"`               // 用户持仓信息
+	ExtraContext string                `json:"extraContext,omitempty"` // 调用方注入的额外上下文（如上次建议执行情况）
+	CompareStock *models.Stock         `json:"compareStock,omitempty"` // 对比股票，设置后进入双股对比模式
+	Mentions     []string              `json:"mentions,omitempty"`     // 老韭菜 @ 的专家 ID，智能模式下必定参会（为空时从问题中解析）
+	Priority     Priority              `json:"-"`                      // 排队优先级（默认为手动发起）
 }
 
 // 会议模式常量
 const (
-	MeetingModeSmart  = "smart"  // 串行智能模式（小韭菜编排）
-	MeetingModeDirect = "direct" // 独立模式（@ 指定专家）
+	MeetingModeSmart   = "smart"   // 串行智能模式（小韭菜编排）
+	MeetingModeDirect  = "direct"  // 独立模式（@ 指定专家）
+	MeetingModeCompare = "compare" // 双股对比模式
+	MeetingModeABTest  = "abtest"  // 专家 A/B 对比模式
 )
 
 // ChatResponse 聊天响应
 type ChatResponse struct {
-	AgentID     string `json:"agentId"`
-	AgentName   string `json:"agentName"`
-	Role        string `json:"role"`
-	Content     string `json:"content"`
-	Round       int    `json:"round"`
-	MsgType     string `json:"msgType"`               // opening/opinion/summary
-	Error       string `json:"error,omitempty"`       // 失败时的错误信息，前端据此显示重试按钮
-	MeetingMode string `json:"meetingMode,omitempty"` // smart=串行, direct=独立
+	AgentID       string                  `json:"agentId"`
+	AgentName     string                  `json:"agentName"`
+	Role          string                  `json:"role"`
+	Content       string                  `json:"content"`
+	Round         int                     `json:"round"`
+	MsgType       string                  `json:"msgType"`                 // opening/opinion/summary/clarify/answer
+	Error         string                  `json:"error,omitempty"`         // 失败时的错误信息，前端据此显示重试按钮
+	ErrorCode     ErrorCode               `json:"errorCode,omitempty"`     // 失败原因分类，未归类时为空
+	MeetingMode   string                  `json:"meetingMode,omitempty"`   // smart=串行, direct=独立
+	AnsweredBy    string                  `json:"answeredBy,omitempty"`    // 发生降级时实际作答的模型名
+	ToolCalls     []models.ToolCallRecord `json:"toolCalls,omitempty"`     // 发言期间的工具调用（数据来源）
+	MeetingID     string                  `json:"meetingId,omitempty"`     // 所属会议 ID，多场会议同时进行时用于区分
+	Variant       string                  `json:"variant,omitempty"`       // A/B 对比模式下的变体标签
+	DurationMs    int64                   `json:"durationMs,omitempty"`    // 专家发言耗时(毫秒)，含重试与降级
+	Retries       int                     `json:"retries,omitempty"`       // 重试次数
+	ToolCallCount int                     `json:"toolCallCount,omitempty"` // 工具调用次数
+	ModelName     string                  `json:"modelName,omitempty"`     // 使用的模型名
+	Report        *models.SummaryReport   `json:"report,omitempty"`        // 结构化总结（仅 summary）
+	Consensus     *models.ConsensusScore  `json:"consensus,omitempty"`     // 多空共识分（仅 summary）
 }
 
 // ResponseCallback 响应回调函数类型
@@ -204,11 +319,13 @@ type ResponseCallback func(resp ChatResponse)
 
 // ProgressEvent 进度事件（细粒度实时反馈）
 type ProgressEvent struct {
-	Type      string `json:"type"`      // thinking/tool_call/tool_result/streaming/agent_start/agent_done
-	AgentID   string `json:"agentId"`   // 当前专家 ID
-	AgentName string `json:"agentName"` // 当前专家名称
-	Detail    string `json:"detail"`    // 工具名称或阶段描述
-	Content   string `json:"content"`   // 流式文本片段或工具结果摘要
+	Type      string    `json:"type"`                // thinking/tool_call/tool_result/streaming/agent_start/agent_done/agent_invited/retry/failover/cost_update/budget_exceeded/queued
+	AgentID   string    `json:"agentId"`             // 当前专家 ID
+	AgentName string    `json:"agentName"`           // 当前专家名称
+	Detail    string    `json:"detail"`              // 工具名称或阶段描述
+	Content   string    `json:"content"`             // 流式文本片段、工具结果摘要或排队位置
+	MeetingID string    `json:"meetingId,omitempty"` // 所属会议 ID
+	ErrorCode ErrorCode `json:"errorCode,omitempty"` // agent_error/meeting_interrupted 的失败原因分类
 }
 
 // ProgressCallback 进度回调函数类型
@@ -221,16 +338,40 @@ func emitProgress(cb ProgressCallback, event ProgressEvent) {
 	}
 }
 
+// safeEmit 发送进度事件并忽略回调中的 panic，用于 panic 恢复路径
+func safeEmit(cb ProgressCallback, event ProgressEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("progress callback panic recovered: %v", r)
+		}
+	}()
+	emitProgress(cb, event)
+}
+
 // SendMessage 发送会议消息，生成多专家回复（并行执行）
 func (s *Service) SendMessage(ctx context.Context, aiConfig *models.AIConfig, req ChatRequest) ([]ChatResponse, error) {
-	llm, err := s.modelFactory.CreateModel(ctx, aiConfig)
+	return s.SendMessageWithCallback(ctx, aiConfig, req, nil)
+}
+
+// SendMessageWithCallback 发送会议消息（带进度回调）
+// 各专家并行发言，进度事件按 AgentID 区分，流式片段交错推送
+func (s *Service) SendMessageWithCallback(ctx context.Context, aiConfig *models.AIConfig, req ChatRequest, progressCallback ProgressCallback) ([]ChatResponse, error) {
+	if aiConfig == nil {
+		return nil, ErrNoAIConfig
+	}
+	flight, leave, err := s.enterMeeting(ctx, req.StockCode)
 	if err != nil {
-		log.Error("CreateModel error: %v", err)
 		return nil, err
 	}
-	log.Info("model created successfully")
-
-	return s.runAgentsParallel(ctx, llm, aiConfig, req)
+	run := s.newRun(aiConfig)
+	s.startReplay(run, req, MeetingModeDirect)
+	defer run.replay.save()
+	progressCallback = run.progressCallback(flight.progressCallback(progressCallback))
+
+	responses, err := s.runAgentsParallel(ctx, run, aiConfig, req, progressCallback)
+	responses = run.stamp(responses)
+	leave(responses, err)
+	return responses, err
 }
 
 // RunSmartMeeting 智能会议模式（小韭菜编排）
@@ -248,59 +389,76 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 	if len(req.AllAgents) == 0 {
 		return "", ErrNoAgents
 	}
+	if len(req.Mentions) == 0 {
+		req.Mentions = ParseMentions(req.Query, req.AllAgents)
+	}
+
+	_, leave, err := s.enterMeeting(ctx, req.StockCode)
+	if err != nil {
+		return "", err
+	}
+	// 同步模式不记录发言，挂载者只会在会议结束时被唤醒
+	defer leave(nil, nil)
+
+	release, err := s.waitTurn(ctx, req.Priority, nil)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	run := s.newRun(aiConfig)
+
+	// 双股对比：只返回并列裁决表
+	if req.CompareStock != nil {
+		responses, err := s.runCompareMeeting(ctx, run, aiConfig, req, nil, nil)
+		if err != nil {
+			return "", err
+		}
+		for _, resp := range responses {
+			if resp.MsgType == "summary" {
+				return resp.Content, nil
+			}
+		}
+		return "", fmt.Errorf("所有专家均分析失败")
+	}
 
 	// 设置整个会议的超时上下文
 	meetingCtx, meetingCancel := context.WithTimeout(ctx, MeetingTimeout)
 	defer meetingCancel()
 
+	costTracker := run.tracker
+
 	// 创建模型
 	modelCtx, modelCancel := context.WithTimeout(meetingCtx, ModelCreationTimeout)
-	llm, err := s.modelFactory.CreateModel(modelCtx, aiConfig)
+	llm, err := s.createModel(modelCtx, aiConfig, costTracker)
 	modelCancel()
 	if err != nil {
 		return "", fmt.Errorf("create model error: %w", err)
 	}
 
-	// 创建 Moderator LLM
-	var moderatorLLM model.LLM
-	if s.moderatorAIConfig != nil {
-		moderatorLLM, err = s.modelFactory.CreateModel(meetingCtx, s.moderatorAIConfig)
-		if err != nil {
-			log.Warn("create moderator LLM error, fallback to default: %v", err)
-			moderatorLLM = llm
-		}
-	} else {
-		moderatorLLM = llm
-	}
-	moderator := NewModerator(moderatorLLM)
-
-	// 设置记忆 LLM
-	if s.memoryManager != nil {
-		if s.memoryAIConfig != nil {
-			memoryLLM, err := s.modelFactory.CreateModel(meetingCtx, s.memoryAIConfig)
-			if err == nil {
-				s.memoryManager.SetLLM(memoryLLM)
-			} else {
-				s.memoryManager.SetLLM(llm)
-			}
-		} else {
-			s.memoryManager.SetLLM(llm)
-		}
-	}
+	// 创建 Moderator 并设置记忆 LLM（按任务路由，未配置则使用会议 LLM）
+	moderator := s.createModerator(meetingCtx, run, llm)
+	s.setupMemory(meetingCtx, run, llm)
 
 	// 加载股票记忆
 	var stockMemory *memory.StockMemory
 	var memoryContext string
 	if s.memoryManager != nil {
 		stockMemory, _ = s.memoryManager.GetOrCreate(req.Stock.Symbol, req.Stock.Name)
-		memoryContext = s.memoryManager.BuildContext(stockMemory, req.Query)
+		memoryContext = run.budget.memory(meetingCtx, s.memoryManager.BuildContext(stockMemory, req.Query))
+	}
+	if req.ExtraContext != "" {
+		memoryContext = req.ExtraContext + "\n" + memoryContext
+	}
+	if run.settings.userProfile != "" {
+		memoryContext = run.settings.userProfile + "\n" + memoryContext
 	}
 
 	log.Info("[OpenClaw] stock: %s, query: %s, agents: %d", req.Stock.Symbol, req.Query, len(req.AllAgents))
 
 	// 第0轮：小韭菜分析意图并选择专家
 	moderatorCtx, moderatorCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
-	decision, err := moderator.Analyze(moderatorCtx, &req.Stock, req.Query, req.AllAgents)
+	decision, err := s.analyzeWithCache(moderatorCtx, moderator, &req.Stock, req.Query, req.AllAgents)
 	moderatorCancel()
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
@@ -311,7 +469,19 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 
 	log.Debug("[OpenClaw] decision: selected=%v, topic=%s", decision.Selected, decision.Topic)
 
+	// 问题过于模糊：直接返回追问，由调用方补充后重新发起（@ 了专家时不追问、不直答）
+	forced := len(req.Mentions) > 0
+	if decision.Clarify != "" && !forced {
+		return decision.Clarify, nil
+	}
+
+	// 纯事实查询：小韭菜查数据直接回答
+	if decision.Direct && !forced {
+		return s.answerDirectly(meetingCtx, moderator, aiConfig, &req.Stock, req.Query, memoryContext, nil, req.Position, nil)
+	}
+
 	selectedAgents := s.filterAgentsOrdered(req.AllAgents, decision.Selected)
+	selectedAgents = s.includeMentioned(selectedAgents, req.AllAgents, req.Mentions)
 	if len(selectedAgents) == 0 {
 		return "", fmt.Errorf("小韭菜未选中任何有效专家")
 	}
@@ -323,18 +493,16 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 			log.Warn("[OpenClaw] meeting timeout, got %d/%d agents", i, len(selectedAgents))
 			break
 		}
+		if costTracker.Exceeded() {
+			log.Warn("[OpenClaw] meeting budget exceeded, got %d/%d agents, cost: %s", i, len(selectedAgents), costTracker.Summary())
+			break
+		}
 
 		log.Debug("[OpenClaw] agent %d/%d: %s starting", i+1, len(selectedAgents), agentCfg.Name)
 
 		agentAIConfig := s.resolveAgentAIConfig(&agentCfg, aiConfig)
-		agentLLM, err := s.modelFactory.CreateModel(meetingCtx, agentAIConfig)
-		if err != nil {
-			log.Error("[OpenClaw] create agent LLM error, skip %s: %v", agentCfg.ID, err)
-			continue
-		}
-		builder := s.createBuilder(agentLLM, agentAIConfig)
 
-		previousContext := s.buildPreviousContext(history)
+		previousContext := run.budget.previousContext(meetingCtx, history)
 		if memoryContext != "" {
 			previousContext = memoryContext + "\n" + previousContext
 		}
@@ -346,11 +514,10 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 			}
 		}
 
-		content, err := retryRun(meetingCtx, MaxAgentRetries, func() (string, error) {
-			agentCtx, agentCancel := context.WithTimeout(meetingCtx, AgentTimeout)
-			defer agentCancel()
-			return s.runSingleAgent(agentCtx, builder, &agentCfg, &req.Stock, agentQuery, previousContext, nil, req.Position)
-		})
+		content, _, err := s.runAgentWithFailover(meetingCtx, &agentCfg, agentAIConfig, costTracker, nil,
+			func(agentCtx context.Context, builder *adk.ExpertAgentBuilder) (string, error) {
+				return s.runSingleAgent(agentCtx, builder, &agentCfg, &req.Stock, agentQuery, previousContext, nil, req.Position, nil)
+			})
 
 		if err != nil {
 			log.Error("[OpenClaw] agent %s failed, skip: %v", agentCfg.ID, err)
@@ -377,17 +544,17 @@ func (s *Service) RunSmartMeetingSync(ctx context.Context, aiConfig *models.AICo
 	}
 
 	// 异步保存记忆
-	if s.memoryManager != nil && stockMemory != nil && summary != "" {
+	if run.memory != nil && stockMemory != nil && summary != "" {
 		go func() {
 			bgCtx := context.Background()
-			keyPoints := s.extractKeyPointsFromHistory(bgCtx, history)
-			if err := s.memoryManager.AddRound(bgCtx, stockMemory, req.Query, summary, keyPoints); err != nil {
+			keyPoints := s.extractKeyPointsFromHistory(bgCtx, run.memory, history)
+			if err := run.memory.AddRound(bgCtx, stockMemory, req.Query, summary, keyPoints); err != nil {
 				log.Error("[OpenClaw] save memory error: %v", err)
 			}
 		}()
 	}
 
-	log.Info("[OpenClaw] meeting done for %s, summary len: %d", req.Stock.Symbol, len(summary))
+	log.Info("[OpenClaw] meeting done for %s, summary len: %d, cost: %s", req.Stock.Symbol, len(summary), costTracker.Summary())
 	return summary, nil
 }
 
@@ -402,13 +569,63 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 		return nil, ErrNoAgents
 	}
 
+	// 同一股票同时只开一场会议（重复点击时后一次直接返回 ErrMeetingRunning）
+	flight, leave, err := s.enterMeeting(ctx, req.StockCode)
+	if err != nil {
+		return nil, err
+	}
+	var responses []ChatResponse
+	defer func() { leave(responses, err) }()
+
+	// 上一轮小韭菜追问过时，本条消息视为回答，合并进原始问题继续会议
+	if query, ok := s.clarifications.Resume(req.StockCode, req.Query); ok {
+		log.Info("resume meeting with clarification for %s", req.StockCode)
+		req.Query = query
+	}
+	if len(req.Mentions) == 0 {
+		req.Mentions = ParseMentions(req.Query, req.AllAgents)
+	}
+
+	// 每场会议独立的运行上下文，回调事件附带会议 ID
+	run := s.newRun(aiConfig)
+	mode := MeetingModeSmart
+	if req.CompareStock != nil {
+		mode = MeetingModeCompare
+	}
+	s.startReplay(run, req, mode)
+	defer run.replay.save()
+	respCallback = run.responseCallback(flight.responseCallback(respCallback))
+	progressCallback = run.progressCallback(flight.progressCallback(progressCallback))
+
+	release, err := s.waitTurn(ctx, req.Priority, progressCallback)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if req.CompareStock != nil {
+		// 双股对比模式
+		responses, err = s.runCompareMeeting(ctx, run, aiConfig, req, respCallback, progressCallback)
+	} else {
+		responses, err = s.runSmartMeeting(ctx, run, aiConfig, req, respCallback, progressCallback)
+	}
+	responses = run.stamp(responses)
+	return responses, err
+}
+
+// runSmartMeeting 智能会议主流程：小韭菜选专家 → 专家串行发言 → 小韭菜总结
+func (s *Service) runSmartMeeting(ctx context.Context, run *meetingRun, aiConfig *models.AIConfig, req ChatRequest, respCallback ResponseCallback, progressCallback ProgressCallback) ([]ChatResponse, error) {
+
 	// 设置整个会议的超时上下文
 	meetingCtx, meetingCancel := context.WithTimeout(ctx, MeetingTimeout)
 	defer meetingCancel()
 
+	// 会议费用累计（超出预算后不再邀请后续专家发言）
+	costTracker := run.tracker
+
 	// 创建模型（带超时）
 	modelCtx, modelCancel := context.WithTimeout(meetingCtx, ModelCreationTimeout)
-	llm, err := s.modelFactory.CreateModel(modelCtx, aiConfig)
+	llm, err := s.createModel(modelCtx, aiConfig, costTracker)
 	modelCancel()
 	if err != nil {
 		return nil, fmt.Errorf("create model error: %w", err)
@@ -416,48 +633,28 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 
 	var responses []ChatResponse
 
-	// 创建 Moderator LLM（优先使用独立配置）
-	var moderatorLLM model.LLM
-	if s.moderatorAIConfig != nil {
-		moderatorLLM, err = s.modelFactory.CreateModel(meetingCtx, s.moderatorAIConfig)
-		if err != nil {
-			log.Warn("create moderator LLM error, fallback to default: %v", err)
-			moderatorLLM = llm
-		} else {
-			log.Debug("using dedicated moderator LLM: %s", s.moderatorAIConfig.ModelName)
-		}
-	} else {
-		moderatorLLM = llm
-	}
-	moderator := NewModerator(moderatorLLM)
+	// 创建 Moderator（优先使用独立配置）
+	moderator := s.createModerator(meetingCtx, run, llm)
 
-	// 设置 LLM 到记忆管理器（启用摘要功能）
-	if s.memoryManager != nil {
-		// 优先使用配置的记忆 LLM，否则使用会议 LLM
-		if s.memoryAIConfig != nil {
-			memoryLLM, err := s.modelFactory.CreateModel(meetingCtx, s.memoryAIConfig)
-			if err == nil {
-				s.memoryManager.SetLLM(memoryLLM)
-				log.Debug("using dedicated memory LLM: %s", s.memoryAIConfig.ModelName)
-			} else {
-				log.Warn("create memory LLM error, fallback to meeting LLM: %v", err)
-				s.memoryManager.SetLLM(llm)
-			}
-		} else {
-			s.memoryManager.SetLLM(llm)
-		}
-	}
+	// 创建本场会议的记忆 LLM 视图（启用摘要功能）
+	s.setupMemory(meetingCtx, run, llm)
 
 	// 加载股票记忆（如果启用了记忆管理）
 	var stockMemory *memory.StockMemory
 	var memoryContext string
 	if s.memoryManager != nil {
 		stockMemory, _ = s.memoryManager.GetOrCreate(req.Stock.Symbol, req.Stock.Name)
-		memoryContext = s.memoryManager.BuildContext(stockMemory, req.Query)
+		memoryContext = run.budget.memory(meetingCtx, s.memoryManager.BuildContext(stockMemory, req.Query))
 		if memoryContext != "" {
 			log.Debug("loaded memory context for %s, len: %d", req.Stock.Symbol, len(memoryContext))
 		}
 	}
+	if req.ExtraContext != "" {
+		memoryContext = req.ExtraContext + "\n" + memoryContext
+	}
+	if run.settings.userProfile != "" {
+		memoryContext = run.settings.userProfile + "\n" + memoryContext
+	}
 
 	log.Info("stock: %s, query: %s, agents: %d", req.Stock.Symbol, req.Query, len(req.AllAgents))
 
@@ -467,7 +664,7 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 	})
 
 	moderatorCtx, moderatorCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
-	decision, err := moderator.Analyze(moderatorCtx, &req.Stock, req.Query, req.AllAgents)
+	decision, err := s.analyzeWithCache(moderatorCtx, moderator, &req.Stock, req.Query, req.AllAgents)
 	moderatorCancel()
 
 	if err != nil {
@@ -486,31 +683,65 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 
 	log.Debug("decision: selected=%v, topic=%s", decision.Selected, decision.Topic)
 
-	// 添加开场白并立即回调
-	openingResp := ChatResponse{
-		AgentID:     "moderator",
-		AgentName:   "小韭菜",
-		Role:        "会议主持",
-		Content:     decision.Opening,
-		Round:       0,
-		MsgType:     "opening",
-		MeetingMode: MeetingModeSmart,
+	// 老韭菜 @ 了专家时一定召集专家发言，不追问也不由小韭菜直答
+	forced := len(req.Mentions) > 0
+
+	// 问题过于模糊：向老韭菜追问，等待回答后恢复会议
+	if decision.Clarify != "" && !forced {
+		s.clarifications.Set(req.StockCode, req.Query, decision.Clarify)
+		clarifyResp := ChatResponse{
+			AgentID:     "moderator",
+			AgentName:   "小韭菜",
+			Role:        "会议主持",
+			Content:     decision.Clarify,
+			Round:       0,
+			MsgType:     "clarify",
+			MeetingMode: MeetingModeSmart,
+		}
+		if respCallback != nil {
+			respCallback(clarifyResp)
+		}
+		return []ChatResponse{clarifyResp}, nil
 	}
-	responses = append(responses, openingResp)
-	if respCallback != nil {
-		respCallback(openingResp)
+
+	// 纯事实查询：小韭菜查数据直接回答，不召集专家
+	if decision.Direct && !forced {
+		return s.runDirectAnswer(meetingCtx, moderator, aiConfig, req, memoryContext, respCallback, progressCallback, costTracker)
 	}
 
-	// 筛选被选中的专家（按小韭菜选择的顺序）
+	// 添加开场白并立即回调（追问、直答被 @ 覆盖时没有开场白）
+	if decision.Opening != "" {
+		openingResp := ChatResponse{
+			AgentID:     "moderator",
+			AgentName:   "小韭菜",
+			Role:        "会议主持",
+			Content:     decision.Opening,
+			Round:       0,
+			MsgType:     "opening",
+			MeetingMode: MeetingModeSmart,
+		}
+		responses = append(responses, openingResp)
+		if respCallback != nil {
+			respCallback(openingResp)
+		}
+	}
+
+	// 筛选被选中的专家（按小韭菜选择的顺序），老韭菜 @ 的专家必定参会
 	selectedAgents := s.filterAgentsOrdered(req.AllAgents, decision.Selected)
+	selectedAgents = s.includeMentioned(selectedAgents, req.AllAgents, req.Mentions)
 	if len(selectedAgents) == 0 {
 		return responses, nil
 	}
 
 	// 第1轮：专家串行发言，后一个参考前面的内容
+	// 最后一位发言后小韭菜可追加邀请专家，被邀请的专家接在后面发言
 	var history []DiscussionEntry
+	var invitedTasks map[string]string
+	interrupted := false
+	reviewed := false
 
-	for i, agentCfg := range selectedAgents {
+	for i := 0; i < len(selectedAgents); i++ {
+		agentCfg := selectedAgents[i]
 		// 检查会议是否已超时
 		select {
 		case <-meetingCtx.Done():
@@ -519,26 +750,23 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 		default:
 		}
 
+		// 检查会议费用是否已超出预算
+		if s.checkBudget(costTracker, progressCallback) {
+			break
+		}
+
 		log.Debug("agent %d/%d: %s starting", i+1, len(selectedAgents), agentCfg.Name)
 
 		// 获取该专家的 AI 配置
 		agentAIConfig := s.resolveAgentAIConfig(&agentCfg, aiConfig)
 
-		// 为该专家创建 LLM
-		agentLLM, err := s.modelFactory.CreateModel(meetingCtx, agentAIConfig)
-		if err != nil {
-			log.Error("create agent LLM error: %v", err)
-			continue
-		}
-		builder := s.createBuilder(agentLLM, agentAIConfig)
-
 		// 发送专家开始事件
 		emitProgress(progressCallback, ProgressEvent{
 			Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: agentCfg.Role,
 		})
 
 		// 构建前面专家发言的上下文
-		previousContext := s.buildPreviousContext(history)
+		previousContext := run.budget.previousContext(meetingCtx, history)
 		// 合并记忆上下文
 		if memoryContext != "" {
 			previousContext = memoryContext + "\n" + previousContext
@@ -551,18 +779,19 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 				agentQuery = task
 			}
 		}
+		if task := invitedTasks[agentCfg.ID]; task != "" {
+			agentQuery = task
+		}
 
-		// 运行单个专家（带超时控制 + 指数退避重试）
-		content, err := retryRun(meetingCtx, MaxAgentRetries, func() (string, error) {
-			agentCtx, agentCancel := context.WithTimeout(meetingCtx, AgentTimeout)
-			defer agentCancel()
-			return s.runSingleAgent(agentCtx, builder, &agentCfg, &req.Stock, agentQuery, previousContext, progressCallback, req.Position)
-		})
+		// 运行单个专家（带超时控制 + 指数退避重试 + 降级模型切换）
+		recorder := newToolRecorder(agentCfg.ID)
+		content, result, err := s.runAgentWithFailover(meetingCtx, &agentCfg, agentAIConfig, costTracker, progressCallback,
+			func(agentCtx context.Context, builder *adk.ExpertAgentBuilder) (string, error) {
+				return s.runSingleAgent(agentCtx, builder, &agentCfg, &req.Stock, agentQuery, previousContext, progressCallback, req.Position, recorder)
+			})
 
 		if err != nil {
-			emitProgress(progressCallback, ProgressEvent{
-				Type: "agent_error", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: err.Error(),
-			})
+			emitProgress(progressCallback, errorEvent("agent_error", agentCfg.ID, agentCfg.Name, err))
 			emitProgress(progressCallback, ProgressEvent{
 				Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
 			})
@@ -576,9 +805,10 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 				Content:     "",
 				Round:       1,
 				MsgType:     "opinion",
-				Error:       err.Error(),
 				MeetingMode: MeetingModeSmart,
 			}
+			failedResp.setError(err)
+			result.apply(&failedResp, recorder)
 			responses = append(responses, failedResp)
 			if respCallback != nil {
 				respCallback(failedResp)
@@ -598,8 +828,10 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 					MemoryContext:  memoryContext,
 					StockMemory:    stockMemory,
 					Moderator:      moderator,
+					run:            run,
 					CreatedAt:      time.Now(),
 				})
+				interrupted = true
 
 				// 收集剩余专家 ID
 				remainingIDs := make([]string, 0, len(selectedAgents)-i-1)
@@ -608,10 +840,9 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 				}
 
 				// 发送 meeting_interrupted 事件
-				emitProgress(progressCallback, ProgressEvent{
-					Type: "meeting_interrupted", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
-					Detail: err.Error(), Content: strings.Join(remainingIDs, ","),
-				})
+				interruptedEvent := errorEvent("meeting_interrupted", agentCfg.ID, agentCfg.Name, err)
+				interruptedEvent.Content = strings.Join(remainingIDs, ",")
+				emitProgress(progressCallback, interruptedEvent)
 			}
 
 			// 中断串行执行，不再继续后续专家
@@ -622,6 +853,7 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 		emitProgress(progressCallback, ProgressEvent{
 			Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
 		})
+		emitCostUpdate(progressCallback, costTracker)
 
 		// 添加到响应并立即回调
 		resp := ChatResponse{
@@ -632,7 +864,10 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 			Round:       1,
 			MsgType:     "opinion",
 			MeetingMode: MeetingModeSmart,
+			AnsweredBy:  answeredByLabel(agentAIConfig, result.answeredBy),
+			ToolCalls:   recorder.records(),
 		}
+		result.apply(&resp, recorder)
 		responses = append(responses, resp)
 		if respCallback != nil {
 			respCallback(resp)
@@ -648,19 +883,22 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 		})
 
 		log.Debug("agent %s done, content len: %d", agentCfg.ID, len(content))
-	}
 
-	// 检查是否被中断（有缓存状态说明中断了，跳过总结）
-	if req.StockCode != "" {
-		s.meetingStatesMu.RLock()
-		_, interrupted := s.meetingStates[req.StockCode]
-		s.meetingStatesMu.RUnlock()
-		if interrupted {
-			log.Info("meeting interrupted for %s, skipping summary", req.StockCode)
-			return responses, nil
+		// 选中的专家发言完毕，小韭菜评估是否需要追加邀请（只评估一次）
+		if i == len(selectedAgents)-1 && !reviewed && !costTracker.Exceeded() {
+			reviewed = true
+			var invited []models.AgentConfig
+			invited, invitedTasks = s.inviteAgents(meetingCtx, moderator, req, history, selectedAgents, progressCallback)
+			selectedAgents = append(selectedAgents, invited...)
 		}
 	}
 
+	// 本场会议中断（已缓存状态等待恢复），跳过总结
+	if interrupted {
+		log.Info("meeting interrupted for %s, skipping summary", req.StockCode)
+		return responses, nil
+	}
+
 	// 最终轮：小韭菜总结（带超时）
 	emitProgress(progressCallback, ProgressEvent{
 		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "总结讨论",
@@ -693,21 +931,25 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 			Round:       2,
 			MsgType:     "summary",
 			MeetingMode: MeetingModeSmart,
+			Report:      models.ParseSummaryReport(summary),
+			Consensus:   s.consensus(responses, req.Stock.Price),
 		}
 		responses = append(responses, summaryResp)
 		if respCallback != nil {
 			respCallback(summaryResp)
 		}
 	}
+	emitCostUpdate(progressCallback, costTracker)
+	log.Info("meeting done for %s, cost: %s", req.Stock.Symbol, costTracker.Summary())
 
 	// 保存记忆（如果启用了记忆管理）
-	if s.memoryManager != nil && stockMemory != nil && summary != "" {
+	if run.memory != nil && stockMemory != nil && summary != "" {
 		// 异步保存记忆，不阻塞返回
 		go func() {
 			// 使用独立 context，因为会议 ctx 可能已取消
 			bgCtx := context.Background()
-			keyPoints := s.extractKeyPointsFromHistory(bgCtx, history)
-			if err := s.memoryManager.AddRound(bgCtx, stockMemory, req.Query, summary, keyPoints); err != nil {
+			keyPoints := s.extractKeyPointsFromHistory(bgCtx, run.memory, history)
+			if err := run.memory.AddRound(bgCtx, stockMemory, req.Query, summary, keyPoints); err != nil {
 				log.Error("save memory error: %v", err)
 			} else {
 				log.Debug("saved memory for %s", req.Stock.Symbol)
@@ -718,8 +960,50 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 	return responses, nil
 }
 
+// runDirectAnswer 小韭菜直答事实类问题，只返回一条小韭菜回复
+func (s *Service) runDirectAnswer(
+	ctx context.Context,
+	moderator *Moderator,
+	aiConfig *models.AIConfig,
+	req ChatRequest,
+	memoryContext string,
+	respCallback ResponseCallback,
+	progressCallback ProgressCallback,
+	costTracker *CostTracker,
+) ([]ChatResponse, error) {
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "查询数据",
+	})
+	recorder := newToolRecorder("moderator")
+	content, err := s.answerDirectly(ctx, moderator, aiConfig, &req.Stock, req.Query, memoryContext, progressCallback, req.Position, recorder)
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜",
+	})
+
+	resp := ChatResponse{
+		AgentID:     "moderator",
+		AgentName:   "小韭菜",
+		Role:        "会议主持",
+		Content:     content,
+		Round:       0,
+		MsgType:     "answer",
+		MeetingMode: MeetingModeSmart,
+		ToolCalls:   recorder.records(),
+	}
+	if err != nil {
+		log.Error("moderator direct answer error: %v", err)
+		resp.setError(err)
+	}
+	if respCallback != nil {
+		respCallback(resp)
+	}
+	emitCostUpdate(progressCallback, costTracker)
+	log.Info("direct answer done for %s, cost: %s", req.Stock.Symbol, costTracker.Summary())
+	return []ChatResponse{resp}, nil
+}
+
 // runAgentsParallel 并行运行多个 Agent（带超时控制）
-func (s *Service) runAgentsParallel(ctx context.Context, defaultLLM model.LLM, defaultAIConfig *models.AIConfig, req ChatRequest) ([]ChatResponse, error) {
+func (s *Service) runAgentsParallel(ctx context.Context, run *meetingRun, defaultAIConfig *models.AIConfig, req ChatRequest, progressCallback ProgressCallback) ([]ChatResponse, error) {
 	var (
 		wg        sync.WaitGroup
 		mu        sync.Mutex
@@ -732,59 +1016,89 @@ func (s *Service) runAgentsParallel(ctx context.Context, defaultLLM model.LLM, d
 
 	log.Debug("running %d agents in parallel", len(req.Agents))
 
+	replyContent := req.ReplyContent
+	if run.settings.userProfile != "" {
+		replyContent = run.settings.userProfile + "\n" + replyContent
+	}
+
 	for _, agentConfig := range req.Agents {
 		wg.Add(1)
 		go func(cfg models.AgentConfig) {
 			defer wg.Done()
+			// 单个专家 panic 不影响其他专家与整个应用，转为失败的发言
+			defer func() {
+				if r := recover(); r != nil {
+					log.Error("agent %s panic recovered: %v\n%s", cfg.ID, r, debug.Stack())
+					errMsg := fmt.Sprintf("专家运行异常: %v", r)
+					resp := ChatResponse{
+						AgentID:     cfg.ID,
+						AgentName:   cfg.Name,
+						Role:        cfg.Role,
+						MsgType:     "opinion",
+						Error:       errMsg,
+						MeetingMode: MeetingModeDirect,
+					}
+					mu.Lock()
+					responses = append(responses, resp)
+					mu.Unlock()
+					// 先记录失败发言，进度回调本身 panic 时不再重复触发
+					safeEmit(progressCallback, ProgressEvent{
+						Type: "agent_error", AgentID: cfg.ID, AgentName: cfg.Name, Detail: errMsg,
+					})
+					safeEmit(progressCallback, ProgressEvent{
+						Type: "agent_done", AgentID: cfg.ID, AgentName: cfg.Name,
+					})
+					run.replay.add(ReplayEvent{Response: &resp})
+				}
+			}()
 
 			// 获取该专家的 AI 配置
 			agentAIConfig := s.resolveAgentAIConfig(&cfg, defaultAIConfig)
 
-			// 为该专家创建 LLM
-			var agentLLM model.LLM
-			var err error
-			if agentAIConfig == defaultAIConfig {
-				agentLLM = defaultLLM
-			} else {
-				agentLLM, err = s.modelFactory.CreateModel(parallelCtx, agentAIConfig)
-				if err != nil {
-					log.Error("create agent LLM error: %v", err)
-					return
-				}
-			}
-			builder := s.createBuilder(agentLLM, agentAIConfig)
-
-			// 单个 Agent 带指数退避重试
-			content, err := retryRun(parallelCtx, MaxAgentRetries, func() (string, error) {
-				agentCtx, agentCancel := context.WithTimeout(parallelCtx, AgentTimeout)
-				defer agentCancel()
-				return s.runSingleAgent(agentCtx, builder, &cfg, &req.Stock, req.Query, req.ReplyContent, nil, req.Position)
+			emitProgress(progressCallback, ProgressEvent{
+				Type: "agent_start", AgentID: cfg.ID, AgentName: cfg.Name, Detail: cfg.Role,
 			})
+
+			// 单个 Agent 带指数退避重试，失败后切换降级模型
+			recorder := newToolRecorder(cfg.ID)
+			content, result, err := s.runAgentWithFailover(parallelCtx, &cfg, agentAIConfig, nil, progressCallback,
+				func(agentCtx context.Context, builder *adk.ExpertAgentBuilder) (string, error) {
+					return s.runSingleAgent(agentCtx, builder, &cfg, &req.Stock, req.Query, replyContent, progressCallback, req.Position, recorder)
+				})
+
+			var resp ChatResponse
 			if err != nil {
 				log.Error("agent %s failed after retries: %v", cfg.ID, err)
-				mu.Lock()
-				responses = append(responses, ChatResponse{
+				emitProgress(progressCallback, errorEvent("agent_error", cfg.ID, cfg.Name, err))
+				resp = ChatResponse{
 					AgentID:     cfg.ID,
 					AgentName:   cfg.Name,
 					Role:        cfg.Role,
 					MsgType:     "opinion",
-					Error:       err.Error(),
 					MeetingMode: MeetingModeDirect,
-				})
-				mu.Unlock()
-				return
+				}
+				resp.setError(err)
+			} else {
+				resp = ChatResponse{
+					AgentID:     cfg.ID,
+					AgentName:   cfg.Name,
+					Role:        cfg.Role,
+					Content:     content,
+					MeetingMode: MeetingModeDirect,
+					AnsweredBy:  answeredByLabel(agentAIConfig, result.answeredBy),
+					ToolCalls:   recorder.records(),
+				}
+				log.Debug("agent %s done, content len: %d", cfg.ID, len(content))
 			}
+			result.apply(&resp, recorder)
+			emitProgress(progressCallback, ProgressEvent{
+				Type: "agent_done", AgentID: cfg.ID, AgentName: cfg.Name,
+			})
+			run.replay.add(ReplayEvent{Response: &resp})
 
 			mu.Lock()
-			responses = append(responses, ChatResponse{
-				AgentID:     cfg.ID,
-				AgentName:   cfg.Name,
-				Role:        cfg.Role,
-				Content:     content,
-				MeetingMode: MeetingModeDirect,
-			})
+			responses = append(responses, resp)
 			mu.Unlock()
-			log.Debug("agent %s done, content len: %d", cfg.ID, len(content))
 		}(agentConfig)
 	}
 
@@ -794,7 +1108,7 @@ func (s *Service) runAgentsParallel(ctx context.Context, defaultLLM model.LLM, d
 }
 
 // runSingleAgent 运行单个 Agent（统一入口）
-// progressCallback 为 nil 时不发送进度事件，也不启用 streaming 模式
+// progressCallback 为 nil 时不发送进度事件，也不启用 streaming 模式；recorder 为 nil 时不记录工具调用
 func (s *Service) runSingleAgent(
 	ctx context.Context,
 	builder *adk.ExpertAgentBuilder,
@@ -804,10 +1118,12 @@ func (s *Service) runSingleAgent(
 	replyContent string,
 	progressCallback ProgressCallback,
 	position *models.StockPosition,
+	recorder *toolRecorder,
 ) (string, error) {
+	recorder.reset()
 	agentInstance, err := builder.BuildAgentWithContext(cfg, stock, query, replyContent, position)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("%w: %v", errInvalidAgent, err)
 	}
 
 	sessionService := session.InMemoryService()
@@ -834,15 +1150,21 @@ func (s *Service) runSingleAgent(
 		Parts: []*genai.Part{genai.NewPartFromText(query)},
 	}
 
-	// 有 progressCallback 时启用 streaming，否则普通模式
+	// 有 progressCallback 且模型支持时启用 streaming，否则普通模式
 	runCfg := agent.RunConfig{}
-	if progressCallback != nil {
+	streaming := progressCallback != nil && builder.SupportsStreaming()
+	if streaming {
 		runCfg.StreamingMode = agent.StreamingModeSSE
 	}
 
 	var sb strings.Builder
+	// 已发起但尚未返回结果的工具调用，此时出错视为工具调用失败
+	var pendingTool string
 	for event, err := range r.Run(ctx, "user", sessionID, userMsg, runCfg) {
 		if err != nil {
+			if pendingTool != "" && ctx.Err() == nil {
+				return "", &ToolError{Tool: pendingTool, Err: err}
+			}
 			return "", err
 		}
 		if event == nil || event.LLMResponse.Content == nil {
@@ -852,21 +1174,25 @@ func (s *Service) runSingleAgent(
 			if part.Thought {
 				continue
 			}
-			if part.FunctionCall != nil && progressCallback != nil {
-				progressCallback(ProgressEvent{
+			if part.FunctionCall != nil {
+				pendingTool = part.FunctionCall.Name
+				recorder.onCall(part.FunctionCall)
+				emitProgress(progressCallback, ProgressEvent{
 					Type: "tool_call", AgentID: cfg.ID, AgentName: cfg.Name,
 					Detail: part.FunctionCall.Name,
 				})
 			}
-			if part.FunctionResponse != nil && progressCallback != nil {
-				progressCallback(ProgressEvent{
+			if part.FunctionResponse != nil {
+				pendingTool = ""
+				recorder.onResponse(part.FunctionResponse)
+				emitProgress(progressCallback, ProgressEvent{
 					Type: "tool_result", AgentID: cfg.ID, AgentName: cfg.Name,
 					Detail: part.FunctionResponse.Name,
 				})
 			}
 			if part.Text != "" {
 				// streaming 模式下只累积 Partial 片段，避免重复
-				if progressCallback != nil {
+				if streaming {
 					if event.LLMResponse.Partial {
 						sb.WriteString(part.Text)
 						progressCallback(ProgressEvent{
@@ -881,7 +1207,60 @@ func (s *Service) runSingleAgent(
 		}
 	}
 
-	return openai.FilterVendorToolCallMarkers(sb.String()), nil
+	return recorder.cite(openai.FilterVendorToolCallMarkers(sb.String())), nil
+}
+
+// createModel 创建模型并挂载会议费用统计
+func (s *Service) createModel(ctx context.Context, aiConfig *models.AIConfig, tracker *CostTracker) (model.LLM, error) {
+	llm, err := s.modelFactory.CreateModel(ctx, aiConfig)
+	if err != nil {
+		return nil, err
+	}
+	return withCostTracking(llm, aiConfig, tracker), nil
+}
+
+// checkBudget 检查会议费用是否超出预算，超出时发送 budget_exceeded 事件
+func (s *Service) checkBudget(tracker *CostTracker, progressCallback ProgressCallback) bool {
+	if !tracker.Exceeded() {
+		return false
+	}
+	summary := tracker.Summary()
+	log.Warn("meeting budget exceeded: %s", summary)
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "budget_exceeded", AgentID: "moderator", AgentName: "小韭菜",
+		Detail: "会议费用已超出预算，后续专家不再发言", Content: summary.String(),
+	})
+	return true
+}
+
+// emitCostUpdate 推送会议费用估算
+func emitCostUpdate(cb ProgressCallback, tracker *CostTracker) {
+	if tracker == nil {
+		return
+	}
+	emitProgress(cb, ProgressEvent{
+		Type: "cost_update", AgentID: "moderator", AgentName: "小韭菜",
+		Content: tracker.Summary().String(),
+	})
+}
+
+// analyzeWithCache 小韭菜意图分析（命中缓存时直接复用，不再调用 LLM）
+func (s *Service) analyzeWithCache(ctx context.Context, moderator *Moderator, stock *models.Stock, query string, agents []models.AgentConfig) (*ModeratorDecision, error) {
+	key := decisionCacheKey(stock.Symbol, query, agents)
+	if decision, ok := s.decisionCache.Get(key); ok {
+		log.Info("moderator decision cache hit for %s", stock.Symbol)
+		return decision, nil
+	}
+
+	decision, err := moderator.Analyze(ctx, stock, query, agents)
+	if err != nil {
+		return nil, err
+	}
+	// 追问结果不缓存，重复提问时重新分析
+	if decision.Clarify == "" {
+		s.decisionCache.Set(key, decision)
+	}
+	return decision, nil
 }
 
 // filterAgentsOrdered 按指定顺序筛选专家（保持小韭菜选择的顺序）
@@ -899,23 +1278,10 @@ func (s *Service) filterAgentsOrdered(all []models.AgentConfig, ids []string) []
 	return result
 }
 
-// buildPreviousContext 构建前面专家发言的上下文
-func (s *Service) buildPreviousContext(history []DiscussionEntry) string {
-	if len(history) == 0 {
-		return ""
-	}
-	var sb strings.Builder
-	sb.WriteString("【前面专家的发言】\n")
-	for _, entry := range history {
-		fmt.Fprintf(&sb, "- %s（%s）：%s\n\n", entry.AgentName, entry.Role, entry.Content)
-	}
-	return sb.String()
-}
-
 // extractKeyPointsFromHistory 从讨论历史中提取关键点
-func (s *Service) extractKeyPointsFromHistory(ctx context.Context, history []DiscussionEntry) []string {
-	// 如果有记忆管理器，使用 LLM 智能提取
-	if s.memoryManager != nil {
+func (s *Service) extractKeyPointsFromHistory(ctx context.Context, scope *memory.Scope, history []DiscussionEntry) []string {
+	// 如果启用了记忆，使用本场会议的 LLM 智能提取
+	if scope != nil {
 		discussions := make([]memory.DiscussionInput, 0, len(history))
 		for _, entry := range history {
 			discussions = append(discussions, memory.DiscussionInput{
@@ -924,7 +1290,7 @@ func (s *Service) extractKeyPointsFromHistory(ctx context.Context, history []Dis
 				Content:   entry.Content,
 			})
 		}
-		keyPoints, err := s.memoryManager.ExtractKeyPoints(ctx, discussions)
+		keyPoints, err := scope.ExtractKeyPoints(ctx, discussions)
 		if err != nil {
 			log.Warn("LLM extract key points error, fallback: %v", err)
 		} else {
@@ -945,10 +1311,34 @@ func (s *Service) extractKeyPointsFromHistory(ctx context.Context, history []Dis
 	return keyPoints
 }
 
+// createTaskModel 按任务配置创建 LLM，未配置或创建失败时返回 fallback
+func (s *Service) createTaskModel(ctx context.Context, task models.AITask, aiConfig *models.AIConfig, fallback model.LLM, tracker *CostTracker) model.LLM {
+	if aiConfig == nil {
+		return fallback
+	}
+	llm, err := s.createModel(ctx, aiConfig, tracker)
+	if err != nil {
+		log.Warn("create %s LLM error, fallback: %v", task, err)
+		return fallback
+	}
+	log.Debug("using dedicated %s LLM: %s", task, aiConfig.ModelName)
+	return llm
+}
+
+// createModerator 创建小韭菜，意图分析与总结可分别路由到不同模型
+func (s *Service) createModerator(ctx context.Context, run *meetingRun, meetingLLM model.LLM) *Moderator {
+	moderatorLLM := s.createTaskModel(ctx, models.AITaskModerator, run.settings.moderatorAI, meetingLLM, run.tracker)
+	moderator := NewModerator(moderatorLLM)
+	moderator.SetUserProfile(run.settings.userProfile)
+	moderator.SetSummaryLLM(s.createTaskModel(ctx, models.AITaskSummary, run.settings.summaryAI, moderatorLLM, run.tracker))
+	run.budget.setCompressor(moderator.Compress)
+	return moderator
+}
+
 // resolveAgentAIConfig 解析专家的 AI 配置（优先使用专家自定义配置，否则降级为默认配置）
 func (s *Service) resolveAgentAIConfig(agentCfg *models.AgentConfig, defaultConfig *models.AIConfig) *models.AIConfig {
-	if s.aiConfigResolver != nil && agentCfg.AIConfigID != "" {
-		if resolved := s.aiConfigResolver(agentCfg.AIConfigID); resolved != nil {
+	if resolver := s.resolver(); resolver != nil && agentCfg.AIConfigID != "" {
+		if resolved := resolver(agentCfg.AIConfigID); resolved != nil {
 			log.Debug("agent %s using custom AI: %s", agentCfg.ID, resolved.ModelName)
 			return resolved
 		}
@@ -958,13 +1348,18 @@ func (s *Service) resolveAgentAIConfig(agentCfg *models.AgentConfig, defaultConf
 
 // createBuilder 创建 ExpertAgentBuilder
 func (s *Service) createBuilder(llm model.LLM, aiConfig *models.AIConfig) *adk.ExpertAgentBuilder {
-	if s.mcpManager != nil {
-		return adk.NewExpertAgentBuilderFull(llm, aiConfig, s.toolRegistry, s.mcpManager)
+	var builder *adk.ExpertAgentBuilder
+	switch {
+	case s.mcpManager != nil:
+		builder = adk.NewExpertAgentBuilderFull(llm, aiConfig, s.toolRegistry, s.mcpManager)
+	case s.toolRegistry != nil:
+		builder = adk.NewExpertAgentBuilderWithTools(llm, aiConfig, s.toolRegistry)
+	default:
+		builder = adk.NewExpertAgentBuilder(llm, aiConfig)
 	}
-	if s.toolRegistry != nil {
-		return adk.NewExpertAgentBuilderWithTools(llm, aiConfig, s.toolRegistry)
-	}
-	return adk.NewExpertAgentBuilder(llm, aiConfig)
+	builder.SetToolGuideBudget(s.settings().context.toolGuide)
+	builder.SetSnapshotFetcher(s.stockSnapshot)
+	return builder
 }
 
 // RetrySingleAgent 重试单个失败的专家（前端手动重试调用）
@@ -980,39 +1375,35 @@ func (s *Service) RetrySingleAgent(
 	// 获取该专家的 AI 配置
 	agentAIConfig := s.resolveAgentAIConfig(agentCfg, aiConfig)
 
-	agentLLM, err := s.modelFactory.CreateModel(ctx, agentAIConfig)
-	if err != nil {
-		return ChatResponse{}, fmt.Errorf("create model error: %w", err)
-	}
-	builder := s.createBuilder(agentLLM, agentAIConfig)
-
 	emitProgress(progressCallback, ProgressEvent{
 		Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: agentCfg.Role,
 	})
 
-	// 带指数退避重试
-	content, err := retryRun(ctx, MaxAgentRetries, func() (string, error) {
-		agentCtx, cancel := context.WithTimeout(ctx, AgentTimeout)
-		defer cancel()
-		return s.runSingleAgent(agentCtx, builder, agentCfg, stock, query, "", progressCallback, position)
-	})
+	// 带指数退避重试与降级模型切换
+	recorder := newToolRecorder(agentCfg.ID)
+	content, result, err := s.runAgentWithFailover(ctx, agentCfg, agentAIConfig, nil, progressCallback,
+		func(agentCtx context.Context, builder *adk.ExpertAgentBuilder) (string, error) {
+			return s.runSingleAgent(agentCtx, builder, agentCfg, stock, query, "", progressCallback, position, recorder)
+		})
 
 	emitProgress(progressCallback, ProgressEvent{
 		Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
 	})
 
 	if err != nil {
-		return ChatResponse{
+		resp := ChatResponse{
 			AgentID:     agentCfg.ID,
 			AgentName:   agentCfg.Name,
 			Role:        agentCfg.Role,
 			MsgType:     "opinion",
-			Error:       err.Error(),
 			MeetingMode: MeetingModeDirect,
-		}, err
+		}
+		resp.setError(err)
+		result.apply(&resp, recorder)
+		return resp, err
 	}
 
-	return ChatResponse{
+	resp := ChatResponse{
 		AgentID:     agentCfg.ID,
 		AgentName:   agentCfg.Name,
 		Role:        agentCfg.Role,
@@ -1020,7 +1411,11 @@ func (s *Service) RetrySingleAgent(
 		Round:       1,
 		MsgType:     "opinion",
 		MeetingMode: MeetingModeDirect,
-	}, nil
+		AnsweredBy:  answeredByLabel(agentAIConfig, result.answeredBy),
+		ToolCalls:   recorder.records(),
+	}
+	result.apply(&resp, recorder)
+	return resp, nil
 }
 
 // cacheMeetingState 缓存中断的会议状态
@@ -1061,6 +1456,13 @@ func (s *Service) ContinueMeeting(
 	respCallback ResponseCallback,
 	progressCallback ProgressCallback,
 ) ([]ChatResponse, error) {
+	flight, leave, err := s.enterMeeting(ctx, stockCode)
+	if err != nil {
+		return nil, err
+	}
+	var responses []ChatResponse
+	defer func() { leave(responses, err) }()
+
 	// 取出缓存状态
 	s.meetingStatesMu.Lock()
 	state, ok := s.meetingStates[stockCode]
@@ -1073,6 +1475,31 @@ func (s *Service) ContinueMeeting(
 		return nil, fmt.Errorf("没有可恢复的会议状态")
 	}
 
+	// 沿用原会议的运行上下文，恢复后的事件仍归属同一会议
+	run := state.run
+	defer run.replay.save()
+	respCallback = run.responseCallback(flight.responseCallback(respCallback))
+	progressCallback = run.progressCallback(flight.progressCallback(progressCallback))
+
+	release, err := s.waitTurn(ctx, PriorityManual, progressCallback)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	responses, err = s.continueMeeting(ctx, stockCode, state, respCallback, progressCallback)
+	responses = run.stamp(responses)
+	return responses, err
+}
+
+// continueMeeting 从失败的专家开始继续执行，全部完成后总结
+func (s *Service) continueMeeting(
+	ctx context.Context,
+	stockCode string,
+	state *MeetingState,
+	respCallback ResponseCallback,
+	progressCallback ProgressCallback,
+) ([]ChatResponse, error) {
 	log.Info("continuing meeting for %s, failedIndex=%d, total=%d",
 		stockCode, state.FailedIndex, len(state.SelectedAgents))
 
@@ -1082,6 +1509,7 @@ func (s *Service) ContinueMeeting(
 
 	responses := state.Responses
 	history := state.History
+	interrupted := false
 
 	// 从失败的专家开始，依次执行
 	startIndex := state.FailedIndex
@@ -1093,43 +1521,42 @@ func (s *Service) ContinueMeeting(
 		default:
 		}
 
+		if s.checkBudget(state.run.tracker, progressCallback) {
+			break
+		}
+
 		agentCfg := state.SelectedAgents[i]
 		log.Debug("continue: agent %d/%d: %s", i+1, len(state.SelectedAgents), agentCfg.Name)
 
 		// 获取该专家的 AI 配置
 		agentAIConfig := s.resolveAgentAIConfig(&agentCfg, state.AIConfig)
 
-		agentLLM, err := s.modelFactory.CreateModel(meetingCtx, agentAIConfig)
-		if err != nil {
-			log.Error("continue: create agent LLM error: %v", err)
-			continue
-		}
-		builder := s.createBuilder(agentLLM, agentAIConfig)
-
 		emitProgress(progressCallback, ProgressEvent{
 			Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: agentCfg.Role,
 		})
 
-		previousContext := s.buildPreviousContext(history)
+		previousContext := state.run.budget.previousContext(meetingCtx, history)
 		if state.MemoryContext != "" {
 			previousContext = state.MemoryContext + "\n" + previousContext
 		}
 
-		content, err := retryRun(meetingCtx, MaxAgentRetries, func() (string, error) {
-			agentCtx, agentCancel := context.WithTimeout(meetingCtx, AgentTimeout)
-			defer agentCancel()
-			return s.runSingleAgent(agentCtx, builder, &agentCfg, &state.Stock, state.Query, previousContext, progressCallback, state.Position)
-		})
+		recorder := newToolRecorder(agentCfg.ID)
+		content, result, err := s.runAgentWithFailover(meetingCtx, &agentCfg, agentAIConfig, state.run.tracker, progressCallback,
+			func(agentCtx context.Context, builder *adk.ExpertAgentBuilder) (string, error) {
+				return s.runSingleAgent(agentCtx, builder, &agentCfg, &state.Stock, state.Query, previousContext, progressCallback, state.Position, recorder)
+			})
 
 		if err != nil {
-			emitProgress(progressCallback, ProgressEvent{Type: "agent_error", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: err.Error()})
+			emitProgress(progressCallback, errorEvent("agent_error", agentCfg.ID, agentCfg.Name, err))
 			emitProgress(progressCallback, ProgressEvent{Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name})
 			log.Error("continue: agent %s failed: %v", agentCfg.ID, err)
 
 			failedResp := ChatResponse{
 				AgentID: agentCfg.ID, AgentName: agentCfg.Name, Role: agentCfg.Role,
-				Round: 1, MsgType: "opinion", Error: err.Error(), MeetingMode: MeetingModeSmart,
+				Round: 1, MsgType: "opinion", MeetingMode: MeetingModeSmart,
 			}
+			failedResp.setError(err)
+			result.apply(&failedResp, recorder)
 			responses = append(responses, failedResp)
 			if respCallback != nil {
 				respCallback(failedResp)
@@ -1148,26 +1575,31 @@ func (s *Service) ContinueMeeting(
 				MemoryContext:  state.MemoryContext,
 				StockMemory:    state.StockMemory,
 				Moderator:      state.Moderator,
+				run:            state.run,
 				CreatedAt:      time.Now(),
 			})
+			interrupted = true
 
 			remainingIDs := make([]string, 0, len(state.SelectedAgents)-i-1)
 			for _, ra := range state.SelectedAgents[i+1:] {
 				remainingIDs = append(remainingIDs, ra.ID)
 			}
-			emitProgress(progressCallback, ProgressEvent{
-				Type: "meeting_interrupted", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
-				Detail: err.Error(), Content: strings.Join(remainingIDs, ","),
-			})
+			interruptedEvent := errorEvent("meeting_interrupted", agentCfg.ID, agentCfg.Name, err)
+			interruptedEvent.Content = strings.Join(remainingIDs, ",")
+			emitProgress(progressCallback, interruptedEvent)
 			break
 		}
 
 		emitProgress(progressCallback, ProgressEvent{Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name})
+		emitCostUpdate(progressCallback, state.run.tracker)
 
 		resp := ChatResponse{
 			AgentID: agentCfg.ID, AgentName: agentCfg.Name, Role: agentCfg.Role,
 			Content: content, Round: 1, MsgType: "opinion", MeetingMode: MeetingModeSmart,
+			AnsweredBy: answeredByLabel(agentAIConfig, result.answeredBy),
+			ToolCalls:  recorder.records(),
 		}
+		result.apply(&resp, recorder)
 		responses = append(responses, resp)
 		if respCallback != nil {
 			respCallback(resp)
@@ -1179,11 +1611,8 @@ func (s *Service) ContinueMeeting(
 		})
 	}
 
-	// 检查是否再次中断
-	s.meetingStatesMu.RLock()
-	_, stillInterrupted := s.meetingStates[stockCode]
-	s.meetingStatesMu.RUnlock()
-	if stillInterrupted {
+	// 再次中断，等待下一次恢复
+	if interrupted {
 		return responses, nil
 	}
 
@@ -1226,6 +1655,8 @@ func (s *Service) runMeetingSummary(
 			AgentID: "moderator", AgentName: "小韭菜",
 			Role: "会议主持", Content: summary,
 			Round: 2, MsgType: "summary", MeetingMode: MeetingModeSmart,
+			Report:    models.ParseSummaryReport(summary),
+			Consensus: s.consensus(responses, state.Stock.Price),
 		}
 		responses = append(responses, summaryResp)
 		if respCallback != nil {
@@ -1234,11 +1665,11 @@ func (s *Service) runMeetingSummary(
 	}
 
 	// 异步保存记忆
-	if s.memoryManager != nil && state.StockMemory != nil && summary != "" {
+	if state.run.memory != nil && state.StockMemory != nil && summary != "" {
 		go func() {
 			bgCtx := context.Background()
-			keyPoints := s.extractKeyPointsFromHistory(bgCtx, history)
-			if err := s.memoryManager.AddRound(bgCtx, state.StockMemory, state.Query, summary, keyPoints); err != nil {
+			keyPoints := s.extractKeyPointsFromHistory(bgCtx, state.run.memory, history)
+			if err := state.run.memory.AddRound(bgCtx, state.StockMemory, state.Query, summary, keyPoints); err != nil {
 				log.Error("save memory error: %v", err)
 			}
 		}()
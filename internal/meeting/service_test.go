@@ -0,0 +1,268 @@
+package meeting
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/fixture"
+	"google.golang.org/adk/model"
+)
+
+// fixtureModels 所有 AI 配置都返回同一个脚本化模型
+type fixtureModels struct {
+	llm *fixture.LLM
+}
+
+func (f fixtureModels) CreateModel(context.Context, *models.AIConfig) (model.LLM, error) {
+	return f.llm, nil
+}
+
+const testSummary = "### " + models.SummaryConclusion + "\n短期震荡偏多，回调可分批关注。\n\n" +
+	"### " + models.SummaryDivergence + "\n技术面看多，基本面认为估值已不便宜。\n\n" +
+	"### " + models.SummaryKeyData + "\n现价 1495.20，PE 23.5。\n\n" +
+	"### " + models.SummaryRisks + "\n消费复苏不及预期。\n\n" +
+	"### " + models.SummaryActions + "\n仓位控制在三成以内。\n"
+
+func newFixtureService(llm *fixture.LLM) *Service {
+	s := NewServiceFull(nil, nil)
+	s.modelFactory = fixtureModels{llm: llm}
+	return s
+}
+
+func TestRunSmartMeeting(t *testing.T) {
+	agents := []models.AgentConfig{
+		{ID: "tech", Name: "技术派", Role: "技术分析师", Instruction: "你是技术分析师，专注K线形态。", Enabled: true},
+		{ID: "value", Name: "价值派", Role: "基本面分析师", Instruction: "你是基本面分析师，专注估值。", Enabled: true},
+		{ID: "macro", Name: "宏观派", Role: "宏观分析师", Instruction: "你是宏观分析师。", Enabled: true},
+	}
+	// 小韭菜的规则放在前面：追加邀请与总结的提示词中包含专家发言
+	llm := fixture.NewLLM("暂无观点。").
+		On("请判断是否需要追加邀请", `{"invite":[]}`).
+		On("请总结讨论", testSummary).
+		On("负责组织专家讨论", "```json\n"+`{"intent":"走势研判","selected":["tech","value"],"topic":"茅台后市","opening":"请两位专家谈谈茅台后市。","tasks":{"tech":"分析量价形态","value":"评估当前估值"}}`+"\n```").
+		On("专注K线形态", "均线多头排列，量能温和放大。\n"+models.VerdictMarker+"看多，信心 7/10").
+		On("专注估值", "估值处于历史中枢，上行空间有限。\n"+models.VerdictMarker+"中性，信心 5/10")
+
+	s := newFixtureService(llm)
+	responses, err := s.RunSmartMeeting(context.Background(), &models.AIConfig{Provider: models.AIProviderOpenAI, ModelName: "fixture"}, ChatRequest{
+		StockCode: "sh600519",
+		Stock:     models.Stock{Symbol: "sh600519", Name: "贵州茅台", Price: 1495.20},
+		Query:     "茅台后市怎么看？",
+		AllAgents: agents,
+	})
+	if err != nil {
+		t.Fatalf("RunSmartMeeting: %v", err)
+	}
+
+	var types []string
+	for _, r := range responses {
+		types = append(types, r.MsgType+":"+r.AgentID)
+	}
+	want := "opening:moderator,opinion:tech,opinion:value,summary:moderator"
+	if got := strings.Join(types, ","); got != want {
+		t.Fatalf("responses = %s, want %s", got, want)
+	}
+	if !strings.Contains(responses[1].Content, "均线多头排列") || responses[1].Error != "" {
+		t.Errorf("tech opinion = %+v", responses[1])
+	}
+
+	summary := responses[3]
+	if summary.Report == nil || summary.Report.Conclusion != "短期震荡偏多，回调可分批关注。" {
+		t.Errorf("report = %+v", summary.Report)
+	}
+	if summary.Consensus == nil || len(summary.Consensus.Votes) != 2 {
+		t.Fatalf("consensus = %+v", summary.Consensus)
+	}
+
+	// 专家拿到的是小韭菜分配的专属任务，后发言的专家能看到前面的发言
+	var valuePrompt string
+	for _, p := range llm.Prompts() {
+		if strings.Contains(p, "专注估值") {
+			valuePrompt = p
+		}
+	}
+	if !strings.Contains(valuePrompt, "评估当前估值") || !strings.Contains(valuePrompt, "均线多头排列") {
+		t.Errorf("value prompt missing task or previous opinion:\n%s", valuePrompt)
+	}
+}
+
+// panicModels 指定模型名的配置返回会 panic 的模型
+type panicModels struct {
+	fixtureModels
+	name string
+}
+
+type panicLLM struct{}
+
+func (panicLLM) Name() string { return "panic" }
+func (panicLLM) GenerateContent(context.Context, *model.LLMRequest, bool) iter.Seq2[*model.LLMResponse, error] {
+	panic("boom")
+}
+
+func (f panicModels) CreateModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
+	if config.ModelName == f.name {
+		return panicLLM{}, nil
+	}
+	return f.fixtureModels.CreateModel(ctx, config)
+}
+
+func TestSendMessagePanicIsolation(t *testing.T) {
+	s := NewServiceFull(nil, nil)
+	s.modelFactory = panicModels{fixtureModels: fixtureModels{llm: fixture.NewLLM("没问题。")}, name: "panic"}
+	s.SetAIConfigResolver(func(id string) *models.AIConfig {
+		if id == "bad" {
+			return &models.AIConfig{ID: "bad", Provider: models.AIProviderOpenAI, ModelName: "panic"}
+		}
+		return nil
+	})
+
+	responses, err := s.SendMessage(context.Background(), &models.AIConfig{Provider: models.AIProviderOpenAI, ModelName: "fixture"}, ChatRequest{
+		Stock: models.Stock{Symbol: "sh600519", Name: "贵州茅台"},
+		Query: "怎么看？",
+		Agents: []models.AgentConfig{
+			{ID: "ok", Name: "正常", Role: "分析师", Enabled: true},
+			{ID: "broken", Name: "异常", Role: "分析师", AIConfigID: "bad", Enabled: true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]ChatResponse)
+	for _, r := range responses {
+		got[r.AgentID] = r
+	}
+	if len(responses) != 2 || got["ok"].Content != "没问题。" || !strings.Contains(got["broken"].Error, "boom") {
+		t.Errorf("responses = %+v", responses)
+	}
+}
+
+func TestMeetingLock(t *testing.T) {
+	s := newFixtureService(fixture.NewLLM("没问题。"))
+	aiConfig := &models.AIConfig{Provider: models.AIProviderOpenAI, ModelName: "fixture"}
+	req := ChatRequest{
+		StockCode: "sh600519",
+		Stock:     models.Stock{Symbol: "sh600519", Name: "贵州茅台"},
+		Query:     "怎么看？",
+		Agents:    []models.AgentConfig{{ID: "ok", Name: "正常", Role: "分析师", Enabled: true}},
+	}
+
+	if _, err := s.AttachMeeting(context.Background(), "sh600519", nil, nil); err != ErrNoRunningMeeting {
+		t.Fatalf("attach without meeting: %v", err)
+	}
+
+	ctx, release, err := s.Claim(context.Background(), "sh600519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 重复发起被拒绝，持有锁的调用方可以正常开会
+	if _, err := s.SendMessage(context.Background(), aiConfig, req); err != ErrMeetingRunning {
+		t.Fatalf("duplicate meeting: %v", err)
+	}
+	attached := make(chan []ChatResponse)
+	go func() {
+		responses, _ := s.AttachMeeting(context.Background(), "sh600519", nil, nil)
+		attached <- responses
+	}()
+	responses, err := s.SendMessage(ctx, aiConfig, req)
+	if err != nil || len(responses) != 1 {
+		t.Fatalf("SendMessage = %+v, %v", responses, err)
+	}
+	if got := <-attached; len(got) != 1 || got[0].Content != "没问题。" {
+		t.Errorf("attached responses = %+v", got)
+	}
+	release()
+
+	if s.IsMeetingRunning("sh600519") {
+		t.Error("lock not released")
+	}
+}
+
+func TestClaimWait(t *testing.T) {
+	s := newFixtureService(fixture.NewLLM("没问题。"))
+	_, release, err := s.Claim(context.Background(), "sh600519")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 排队等待进行中的会议结束后再占用
+	claimed := make(chan func())
+	go func() {
+		_, queuedRelease, err := s.ClaimWait(context.Background(), "sh600519")
+		if err != nil {
+			t.Error(err)
+		}
+		claimed <- queuedRelease
+	}()
+	select {
+	case <-claimed:
+		t.Fatal("claimed while another meeting is running")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	queuedRelease := <-claimed
+	if !s.IsMeetingRunning("sh600519") {
+		t.Error("queued claim does not hold the lock")
+	}
+
+	// 等待中取消时返回 ctx 错误
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := s.ClaimWait(ctx, "sh600519"); err != context.Canceled {
+		t.Errorf("canceled ClaimWait: %v", err)
+	}
+	queuedRelease()
+	if s.IsMeetingRunning("sh600519") {
+		t.Error("lock not released")
+	}
+}
+
+func TestClaimWaitCompeting(t *testing.T) {
+	s := newFixtureService(fixture.NewLLM("没问题。"))
+	_, release, err := s.Claim(context.Background(), "sh600519")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 两个调用方排队抢同一只股票：同一时刻只有一个持有锁，释放后另一个才能拿到
+	var holders atomic.Int32
+	claimed := make(chan func(), 2)
+	for range 2 {
+		go func() {
+			_, queuedRelease, err := s.ClaimWait(context.Background(), "sh600519")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if n := holders.Add(1); n != 1 {
+				t.Errorf("%d callers hold the lock", n)
+			}
+			claimed <- func() {
+				holders.Add(-1)
+				queuedRelease()
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	if len(claimed) != 0 {
+		t.Fatal("claimed while another meeting is running")
+	}
+
+	release()
+	first := <-claimed
+	select {
+	case <-claimed:
+		t.Fatal("both callers claimed the lock")
+	case <-time.After(20 * time.Millisecond):
+	}
+	first()
+	second := <-claimed
+	second()
+	if s.IsMeetingRunning("sh600519") {
+		t.Error("lock not released")
+	}
+}
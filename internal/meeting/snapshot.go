@@ -0,0 +1,63 @@
+package meeting
+
+import (
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// snapshotCacheTTL 个股快照缓存时间：同一场会议的多位专家共用一次获取
+const snapshotCacheTTL = 30 * time.Second
+
+// SnapshotFetcher 按股票代码获取个股快照（行情服务提供）
+type SnapshotFetcher func(code string) (*models.StockSnapshot, error)
+
+type snapshotEntry struct {
+	once sync.Once
+	snap *models.StockSnapshot
+	at   time.Time
+}
+
+// SetSnapshotFetcher 设置个股快照来源，未设置时专家指令只包含价格与涨跌幅
+func (s *Service) SetSnapshotFetcher(fetch SnapshotFetcher) {
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+	s.snapshotFetcher = fetch
+	s.snapshots = make(map[string]*snapshotEntry)
+}
+
+// stockSnapshot 获取个股快照（带缓存），并发请求同一只股票时只获取一次，失败返回 nil
+func (s *Service) stockSnapshot(code string) *models.StockSnapshot {
+	s.snapshotMu.Lock()
+	fetch := s.snapshotFetcher
+	if fetch == nil {
+		s.snapshotMu.Unlock()
+		return nil
+	}
+	entry, ok := s.snapshots[code]
+	if !ok || (!entry.at.IsZero() && time.Since(entry.at) > snapshotCacheTTL) {
+		for c, e := range s.snapshots {
+			if !e.at.IsZero() && time.Since(e.at) > snapshotCacheTTL {
+				delete(s.snapshots, c)
+			}
+		}
+		entry = &snapshotEntry{}
+		s.snapshots[code] = entry
+	}
+	s.snapshotMu.Unlock()
+
+	entry.once.Do(func() {
+		snap, err := fetch(code)
+		if err != nil {
+			log.Warn("get snapshot for %s failed: %v", code, err)
+		}
+		s.snapshotMu.Lock()
+		entry.snap, entry.at = snap, time.Now()
+		s.snapshotMu.Unlock()
+	})
+
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+	return entry.snap
+}
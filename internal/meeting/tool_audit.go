@@ -0,0 +1,166 @@
+package meeting
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/genai"
+)
+
+// toolResultMaxRunes 工具结果保存的最大长度，避免会话文件膨胀
+const toolResultMaxRunes = 800
+
+// citationRegex 回答中的引用标注，兼容全角括号
+var citationRegex = regexp.MustCompile(`[\[【［](\d{1,2})[\]】］]`)
+
+// toolRecorder 记录单个专家发言期间的工具调用（数据来源）
+type toolRecorder struct {
+	agentID string
+	mu      sync.Mutex
+	calls   []models.ToolCallRecord
+	starts  []time.Time
+	pending map[string]int  // 调用 ID/工具名 → calls 下标，用于匹配结果
+	seen    map[string]bool // 已记录的调用 ID（流式模式下同一调用可能出现多次）
+}
+
+// newToolRecorder 创建工具调用记录器
+func newToolRecorder(agentID string) *toolRecorder {
+	r := &toolRecorder{agentID: agentID}
+	r.reset()
+	return r
+}
+
+// reset 清空记录（重试、降级重跑时只保留最后一次的调用）
+func (r *toolRecorder) reset() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+	r.starts = nil
+	r.pending = make(map[string]int)
+	r.seen = make(map[string]bool)
+}
+
+// onCall 记录工具调用开始
+func (r *toolRecorder) onCall(call *genai.FunctionCall) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if call.ID != "" {
+		if r.seen[call.ID] {
+			return
+		}
+		r.seen[call.ID] = true
+	}
+	r.pending[toolCallKey(call.ID, call.Name)] = len(r.calls)
+	r.calls = append(r.calls, models.ToolCallRecord{
+		Tool:    call.Name,
+		Args:    marshalCompact(call.Args),
+		AgentID: r.agentID,
+	})
+	r.starts = append(r.starts, time.Now())
+}
+
+// onResponse 记录工具结果与耗时
+func (r *toolRecorder) onResponse(resp *genai.FunctionResponse) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := toolCallKey(resp.ID, resp.Name)
+	idx, ok := r.pending[key]
+	if !ok {
+		return
+	}
+	delete(r.pending, key)
+	r.calls[idx].Duration = time.Since(r.starts[idx]).Milliseconds()
+	r.calls[idx].Citation = adk.ParseCitation(resp.Response[adk.CitationKey])
+	r.calls[idx].Result = truncateRunes(marshalCompact(resp.Response), toolResultMaxRunes)
+}
+
+// records 返回记录副本
+func (r *toolRecorder) records() []models.ToolCallRecord {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.calls) == 0 {
+		return nil
+	}
+	return append([]models.ToolCallRecord(nil), r.calls...)
+}
+
+// cite 将回答中的引用标注关联到工具调用记录：统一为 [n] 格式并标记被引用的记录，
+// 删除找不到对应工具结果的编号（模型编造的引用）；recorder 为 nil 时删除全部编号
+func (r *toolRecorder) cite(content string) string {
+	byCitation := make(map[int]*models.ToolCallRecord)
+	if r != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for i := range r.calls {
+			if c := &r.calls[i]; c.Citation > 0 {
+				byCitation[c.Citation] = c
+			}
+		}
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range citationRegex.FindAllStringSubmatchIndex(content, -1) {
+		// 跳过 Markdown 链接文本，如 [1](https://...)
+		if m[1] < len(content) && content[m[1]] == '(' {
+			continue
+		}
+		n, _ := strconv.Atoi(content[m[2]:m[3]])
+		sb.WriteString(content[last:m[0]])
+		if c, ok := byCitation[n]; ok {
+			c.Cited = true
+			fmt.Fprintf(&sb, "[%d]", n)
+		}
+		last = m[1]
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
+}
+
+func toolCallKey(id, name string) string {
+	if id != "" {
+		return id
+	}
+	return "name:" + name
+}
+
+// marshalCompact 序列化为紧凑 JSON，失败时返回空串
+func marshalCompact(v map[string]any) string {
+	if len(v) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// truncateRunes 按字符截断
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
@@ -0,0 +1,110 @@
+package meeting
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/run-bigpig/jcp/internal/adk/tools"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// minSpeechesForSuggestion 专家发言达到该次数后才建议移除未使用的工具，避免样本太少误判
+const minSpeechesForSuggestion = 5
+
+// ToolUsageStats 专家对单个工具的使用统计
+type ToolUsageStats struct {
+	Tool       string  `json:"tool"`
+	Calls      int     `json:"calls"`
+	Cited      int     `json:"cited"`      // 结果被回答引用的次数
+	CiteRate   float64 `json:"citeRate"`   // 引用率 = Cited / Calls
+	Configured bool    `json:"configured"` // 当前是否挂载在专家上
+	LastUsed   int64   `json:"lastUsed,omitempty"`
+}
+
+// AgentToolUsage 专家的工具使用统计与精简建议
+type AgentToolUsage struct {
+	AgentID   string           `json:"agentId"`
+	AgentName string           `json:"agentName"`
+	Speeches  int              `json:"speeches"` // 统计到的发言次数
+	ToolCalls int              `json:"toolCalls"`
+	Tools     []ToolUsageStats `json:"tools"`            // 按调用次数降序，含已挂载但从未调用的工具
+	Unused    []string         `json:"unused,omitempty"` // 建议移除：已挂载但从未调用，可缩短提示词
+}
+
+// AnalyzeToolUsage 按会话中保存的工具调用记录统计各专家的工具使用情况
+// agents 为当前专家配置，决定哪些工具算作已挂载；不在 agents 中的专家不统计
+// market_data 的调用按 action 计入实际转发的工具
+func AnalyzeToolUsage(messages []models.ChatMessage, agents []models.AgentConfig) []AgentToolUsage {
+	result := make([]AgentToolUsage, len(agents))
+	byTool := make([]map[string]*ToolUsageStats, len(agents))
+	index := make(map[string]int, len(agents))
+	for i, agent := range agents {
+		index[agent.ID] = i
+		result[i] = AgentToolUsage{AgentID: agent.ID, AgentName: agent.Name}
+		byTool[i] = make(map[string]*ToolUsageStats)
+		for _, name := range agent.Tools {
+			byTool[i][name] = &ToolUsageStats{Tool: name, Configured: true}
+		}
+	}
+
+	for _, msg := range messages {
+		i, ok := index[msg.AgentID]
+		if !ok || msg.Error != "" || msg.MsgType == "summary" {
+			continue
+		}
+		result[i].Speeches++
+		for _, call := range msg.ToolCalls {
+			name := usageToolName(call)
+			stats, ok := byTool[i][name]
+			if !ok {
+				stats = &ToolUsageStats{Tool: name}
+				byTool[i][name] = stats
+			}
+			stats.Calls++
+			if call.Cited {
+				stats.Cited++
+			}
+			stats.LastUsed = max(stats.LastUsed, msg.Timestamp)
+			result[i].ToolCalls++
+		}
+	}
+
+	for i := range result {
+		for _, stats := range byTool[i] {
+			if stats.Calls > 0 {
+				stats.CiteRate = float64(stats.Cited) / float64(stats.Calls)
+			}
+			result[i].Tools = append(result[i].Tools, *stats)
+		}
+		sort.Slice(result[i].Tools, func(a, b int) bool {
+			ta, tb := result[i].Tools[a], result[i].Tools[b]
+			if ta.Calls != tb.Calls {
+				return ta.Calls > tb.Calls
+			}
+			return ta.Tool < tb.Tool
+		})
+		if result[i].Speeches < minSpeechesForSuggestion {
+			continue
+		}
+		for _, stats := range result[i].Tools {
+			if stats.Configured && stats.Calls == 0 {
+				result[i].Unused = append(result[i].Unused, stats.Tool)
+			}
+		}
+	}
+	return result
+}
+
+// usageToolName 调用记录对应的工具名，market_data 还原为实际转发的工具
+func usageToolName(call models.ToolCallRecord) string {
+	if call.Tool != tools.MarketDataToolName {
+		return call.Tool
+	}
+	var args map[string]any
+	if json.Unmarshal([]byte(call.Args), &args) == nil {
+		if name := tools.MarketDataActionTool(args["action"]); name != "" {
+			return name
+		}
+	}
+	return call.Tool
+}
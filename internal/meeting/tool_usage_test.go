@@ -0,0 +1,50 @@
+package meeting
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestAnalyzeToolUsage(t *testing.T) {
+	agents := []models.AgentConfig{
+		{ID: "tech", Name: "技术分析师", Tools: []string{"get_kline_data", "get_orderbook", "get_news"}},
+		{ID: "macro", Name: "宏观分析师", Tools: []string{"get_macro_data"}},
+	}
+	var messages []models.ChatMessage
+	for i := range 5 {
+		msg := models.ChatMessage{AgentID: "tech", Timestamp: int64(i + 1)}
+		if i < 2 {
+			msg.ToolCalls = []models.ToolCallRecord{
+				{Tool: "get_kline_data", Cited: i == 0},
+				{Tool: "market_data", Args: `{"action":"kline","code":"sh600519"}`},
+			}
+		}
+		messages = append(messages, msg)
+	}
+	messages = append(messages,
+		models.ChatMessage{AgentID: "tech", Error: "timeout", ToolCalls: []models.ToolCallRecord{{Tool: "get_news"}}},
+		models.ChatMessage{AgentID: "macro"},
+		models.ChatMessage{AgentID: "user", Content: "茅台怎么看"},
+	)
+
+	usage := AnalyzeToolUsage(messages, agents)
+	tech := usage[0]
+	if tech.Speeches != 5 || tech.ToolCalls != 4 {
+		t.Errorf("speeches = %d, toolCalls = %d", tech.Speeches, tech.ToolCalls)
+	}
+	// market_data 计入实际转发的 get_kline_data
+	kline := tech.Tools[0]
+	if kline.Tool != "get_kline_data" || kline.Calls != 4 || kline.Cited != 1 || kline.CiteRate != 0.25 || kline.LastUsed != 2 {
+		t.Errorf("kline stats = %+v", kline)
+	}
+	// 失败的发言不计入，从未调用的已挂载工具建议移除
+	if want := []string{"get_news", "get_orderbook"}; !slices.Equal(tech.Unused, want) {
+		t.Errorf("unused = %v, want %v", tech.Unused, want)
+	}
+	// 发言太少时不给出建议
+	if macro := usage[1]; macro.Speeches != 1 || len(macro.Unused) != 0 {
+		t.Errorf("macro usage = %+v", macro)
+	}
+}
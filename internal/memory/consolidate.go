@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 记忆整理参数
+const (
+	defaultConsolidateInterval = 3   // 默认每讨论 3 轮整理一次
+	supersededWeightFactor     = 0.3 // 被推翻/过时的结论降权系数
+	minFactsToConsolidate      = 4   // 事实太少时无需整理
+)
+
+// FactRevision LLM 判定的事实修订结果
+type FactRevision struct {
+	ID     string `json:"id"`
+	Status string `json:"status"` // stale=已过时 / contradicted=被新结论推翻
+	By     string `json:"by"`     // 推翻它的新事实 ID（可选）
+}
+
+// consolidateInterval 获取整理间隔（轮）
+func (m *Manager) consolidateInterval() int {
+	if m.config.ConsolidateInterval > 0 {
+		return m.config.ConsolidateInterval
+	}
+	return defaultConsolidateInterval
+}
+
+// Consolidate 整理股票记忆：识别过时/矛盾的事实并降权，再按重要性淘汰超限条目
+func (m *Manager) Consolidate(ctx context.Context, mem *StockMemory) error {
+	return m.consolidate(ctx, m.currentSummarizer(), mem)
+}
+
+func (m *Manager) consolidate(ctx context.Context, summarizer Summarizer, mem *StockMemory) error {
+	defer m.evict(mem)
+
+	if summarizer == nil {
+		return nil
+	}
+	active := make([]MemoryEntry, 0, len(mem.KeyFacts))
+	for _, f := range mem.KeyFacts {
+		if !f.Superseded {
+			active = append(active, f)
+		}
+	}
+	if len(active) < minFactsToConsolidate {
+		return nil
+	}
+
+	revisions, err := summarizer.ReviewFacts(ctx, active)
+	if err != nil {
+		return err
+	}
+	if n := applyRevisions(mem.KeyFacts, revisions); n > 0 {
+		fmt.Printf("consolidated memory for %s, superseded %d facts\n", mem.StockCode, n)
+	}
+	return nil
+}
+
+// applyRevisions 将修订结果应用到事实列表：标记为已推翻并降权
+func applyRevisions(facts []MemoryEntry, revisions []FactRevision) int {
+	byID := make(map[string]FactRevision, len(revisions))
+	for _, r := range revisions {
+		if r.Status == "stale" || r.Status == "contradicted" {
+			byID[r.ID] = r
+		}
+	}
+	applied := 0
+	for i := range facts {
+		r, ok := byID[facts[i].ID]
+		if !ok || facts[i].Superseded {
+			continue
+		}
+		facts[i].Superseded = true
+		facts[i].SupersededBy = r.By
+		facts[i].Weight *= supersededWeightFactor
+		applied++
+	}
+	return applied
+}
+
+// importance 条目重要性：权重 × 时间衰减，已推翻的条目再降一档，优先被淘汰
+func (m *Manager) importance(f MemoryEntry) float64 {
+	score := f.Weight * m.relevance.timeDecay(f.Timestamp)
+	if f.Superseded {
+		score *= supersededWeightFactor
+	}
+	return score
+}
+
+// evict 事实数超过上限时按重要性淘汰，保留条目维持原有时间顺序
+func (m *Manager) evict(mem *StockMemory) {
+	limit := m.config.MaxKeyFacts
+	if limit <= 0 || len(mem.KeyFacts) <= limit {
+		return
+	}
+
+	idx := make([]int, len(mem.KeyFacts))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return m.importance(mem.KeyFacts[idx[a]]) > m.importance(mem.KeyFacts[idx[b]])
+	})
+	keep := idx[:limit]
+	sort.Ints(keep)
+
+	kept := make([]MemoryEntry, 0, limit)
+	for _, i := range keep {
+		kept = append(kept, mem.KeyFacts[i])
+	}
+	mem.KeyFacts = kept
+}
+
+// formatDate 格式化毫秒时间戳为日期
+func formatDate(ts int64) string {
+	return time.UnixMilli(ts).Format("2006-01-02")
+}
+
+// ReviewFacts 让 LLM 找出已过时或被后续结论推翻的事实
+func (s *LLMSummarizer) ReviewFacts(ctx context.Context, facts []MemoryEntry) ([]FactRevision, error) {
+	if len(facts) == 0 {
+		return nil, nil
+	}
+	result, err := s.generate(ctx, s.extractLLM, s.buildReviewPrompt(facts))
+	if err != nil {
+		return nil, err
+	}
+	return parseRevisions(result)
+}
+
+func (s *LLMSummarizer) buildReviewPrompt(facts []MemoryEntry) string {
+	var sb strings.Builder
+	sb.WriteString("以下是同一只股票按时间顺序积累的历史记忆，请找出已经过时或被后来的结论推翻的条目。\n\n")
+	for _, f := range facts {
+		fmt.Fprintf(&sb, "- id=%s [%s] %s\n", f.ID, formatDate(f.Timestamp), f.Content)
+	}
+	sb.WriteString("\n要求：\n")
+	sb.WriteString("1. stale：有时效性且已明显过期的信息（如某日的价格、短期催化已兑现）\n")
+	sb.WriteString("2. contradicted：与后来的结论矛盾，以时间更新的为准，by 填写推翻它的条目 id\n")
+	sb.WriteString("3. 仍然有效的条目不要列出\n\n")
+	sb.WriteString("只输出JSON数组，不要其他内容，如：\n")
+	sb.WriteString(`[{"id":"旧条目id","status":"contradicted","by":"新条目id"},{"id":"条目id","status":"stale"}]`)
+	return sb.String()
+}
+
+// parseRevisions 解析修订结果 JSON
+func parseRevisions(jsonStr string) ([]FactRevision, error) {
+	jsonStr = strings.TrimSpace(jsonStr)
+	jsonStr = strings.TrimPrefix(jsonStr, "```json")
+	jsonStr = strings.TrimPrefix(jsonStr, "```")
+	jsonStr = strings.TrimSuffix(jsonStr, "```")
+	jsonStr = strings.TrimSpace(jsonStr)
+
+	var revisions []FactRevision
+	if err := json.Unmarshal([]byte(jsonStr), &revisions); err != nil {
+		return nil, fmt.Errorf("parse revisions json error: %w", err)
+	}
+	return revisions, nil
+}
@@ -4,11 +4,19 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/run-bigpig/jcp/internal/pkg/tokens"
+
 	"google.golang.org/adk/model"
 )
 
+const (
+	defaultRecentTokenBudget = 1200 // 近期讨论默认 token 预算
+	roundOverheadTokens      = 12   // 每轮的时间、“问题/结论”标签等固定开销
+)
+
 // Manager 记忆管理器
 type Manager struct {
 	config     Config
@@ -16,9 +24,14 @@ type Manager struct {
 	tokenizer  Tokenizer
 	relevance  *Relevance
 	summarizer Summarizer
+	llmMu      sync.RWMutex // 保护 summarizer
 	dataDir    string
 	saveCh     chan *StockMemory // 异步保存通道
 	closeCh    chan struct{}     // 关闭信号
+
+	sectorResolver SectorResolver          // 股票 → 行业解析器
+	poolMu         sync.Mutex              // 保护跨股票共享的行业/宏观记忆池
+	pools          map[string]*StockMemory // 已加载的记忆池，所有读写都在 poolMu 内
 }
 
 // NewManager 创建记忆管理器（无 LLM，摘要功能禁用）
@@ -39,7 +52,56 @@ func NewManager(dataDir string) *Manager {
 
 // SetLLM 设置 LLM（启用摘要功能）
 func (m *Manager) SetLLM(llm model.LLM) {
-	m.summarizer = NewLLMSummarizer(llm, m.tokenizer)
+	m.SetLLMs(llm, nil)
+}
+
+// SetLLMs 分别设置摘要压缩与关键点/事实提取使用的 LLM（extractionLLM 为 nil 时复用 summaryLLM）
+func (m *Manager) SetLLMs(summaryLLM, extractionLLM model.LLM) {
+	summarizer := m.newSummarizer(summaryLLM, extractionLLM)
+	m.llmMu.Lock()
+	m.summarizer = summarizer
+	m.llmMu.Unlock()
+}
+
+// newSummarizer 创建摘要生成器，summaryLLM 为 nil 时返回 nil（禁用摘要）
+func (m *Manager) newSummarizer(summaryLLM, extractionLLM model.LLM) Summarizer {
+	if summaryLLM == nil {
+		return nil
+	}
+	summarizer := NewLLMSummarizer(summaryLLM, m.tokenizer)
+	if extractionLLM != nil {
+		summarizer.extractLLM = extractionLLM
+	}
+	return summarizer
+}
+
+// currentSummarizer 获取 SetLLMs 设置的全局摘要生成器
+func (m *Manager) currentSummarizer() Summarizer {
+	m.llmMu.RLock()
+	defer m.llmMu.RUnlock()
+	return m.summarizer
+}
+
+// Scope 绑定独立 LLM 的记忆操作视图
+// 并发会议各持一份，摘要与提取调用计入各自会议，不会互相覆盖 Manager 的全局 LLM
+type Scope struct {
+	m          *Manager
+	summarizer Summarizer
+}
+
+// Scoped 创建使用指定 LLM 的记忆操作视图（extractionLLM 为 nil 时复用 summaryLLM）
+func (m *Manager) Scoped(summaryLLM, extractionLLM model.LLM) *Scope {
+	return &Scope{m: m, summarizer: m.newSummarizer(summaryLLM, extractionLLM)}
+}
+
+// AddRound 同 Manager.AddRound，压缩与整理使用本视图的 LLM
+func (s *Scope) AddRound(ctx context.Context, mem *StockMemory, query, consensus string, keyPoints []string) error {
+	return s.m.addRound(ctx, s.summarizer, mem, query, consensus, keyPoints)
+}
+
+// ExtractKeyPoints 同 Manager.ExtractKeyPoints，使用本视图的 LLM
+func (s *Scope) ExtractKeyPoints(ctx context.Context, discussions []DiscussionInput) ([]string, error) {
+	return s.m.extractKeyPoints(ctx, s.summarizer, discussions)
 }
 
 // NewManagerWithConfig 使用自定义配置创建记忆管理器
@@ -56,6 +118,9 @@ func (m *Manager) GetOrCreate(stockCode, stockName string) (*StockMemory, error)
 		// 不存在则创建新的
 		mem = NewStockMemory(stockCode, stockName)
 	}
+	if mem.Sector == "" && m.sectorResolver != nil {
+		mem.Sector = m.sectorResolver(stockCode)
+	}
 	return mem, nil
 }
 
@@ -130,37 +195,98 @@ func (m *Manager) BuildContext(mem *StockMemory, currentQuery string) string {
 		}
 	}
 
+	// 4. 跨股票的行业与宏观记忆
+	sb.WriteString(m.buildPoolContext(mem.Sector, currentQuery))
+
 	return sb.String()
 }
 
 // AddRound 添加新一轮讨论并触发压缩检查
+// 关键点按范围标签分流：行业/宏观观点写入跨股票记忆池，其余保留在个股轮次中
 func (m *Manager) AddRound(ctx context.Context, mem *StockMemory, query, consensus string, keyPoints []string) error {
+	return m.addRound(ctx, m.currentSummarizer(), mem, query, consensus, keyPoints)
+}
+
+func (m *Manager) addRound(ctx context.Context, summarizer Summarizer, mem *StockMemory, query, consensus string, keyPoints []string) error {
+	var stockPoints, sectorPoints, macroPoints []string
+	for _, p := range keyPoints {
+		scope, content := classifyKeyPoint(p)
+		if content == "" {
+			continue
+		}
+		switch scope {
+		case ScopeSector:
+			sectorPoints = append(sectorPoints, content)
+		case ScopeMacro:
+			macroPoints = append(macroPoints, content)
+		default:
+			stockPoints = append(stockPoints, content)
+		}
+	}
+	if key, ok := sectorPoolKey(mem.Sector); ok {
+		m.addPoolFacts(key, mem.Sector, sectorPoints, mem.StockName)
+	}
+	m.addPoolFacts(macroPoolKey, macroPoolName, macroPoints, mem.StockName)
+	// 个股关键点沉淀为长期事实，供后续按相关性检索与整理
+	m.AddFacts(mem, m.pointsToFacts(stockPoints, EntryTypeOpinion, "会议"))
+
 	mem.TotalRounds++
 	round := RoundMemory{
 		Round:     mem.TotalRounds,
 		Query:     query,
 		Consensus: consensus,
-		KeyPoints: keyPoints,
+		KeyPoints: stockPoints,
 		Timestamp: time.Now().UnixMilli(),
 	}
 	mem.RecentRounds = append(mem.RecentRounds, round)
 
-	// 检查是否需要压缩
-	if len(mem.RecentRounds) >= m.config.CompressThreshold {
-		if err := m.compress(ctx, mem); err != nil {
+	// 近期讨论超出 token 预算（或达到轮数上限）时压缩
+	if m.recentTokens(mem.RecentRounds) > m.recentTokenBudget() || len(mem.RecentRounds) >= m.config.CompressThreshold {
+		if err := m.compress(ctx, summarizer, mem); err != nil {
 			// 压缩失败不影响主流程，记录日志即可
 			fmt.Printf("compress memory error: %v\n", err)
 		}
 	}
 
+	// 定期整理：识别过时/矛盾的结论并降权
+	if mem.TotalRounds%m.consolidateInterval() == 0 {
+		if err := m.consolidate(ctx, summarizer, mem); err != nil {
+			fmt.Printf("consolidate memory error: %v\n", err)
+		}
+	}
+
 	// 异步保存，不阻塞主流程
 	m.SaveAsync(mem)
 	return nil
 }
 
+// recentTokenBudget 近期讨论的 token 预算
+func (m *Manager) recentTokenBudget() int {
+	if m.config.RecentTokenBudget > 0 {
+		return m.config.RecentTokenBudget
+	}
+	return defaultRecentTokenBudget
+}
+
+// recentTokens 按 BuildContext 中【近期讨论】的写法估算轮次占用的 token 数
+func (m *Manager) recentTokens(rounds []RoundMemory) int {
+	n := 0
+	for _, round := range rounds {
+		n += roundOverheadTokens + tokens.Estimate(round.Query) + tokens.Estimate(round.Consensus)
+	}
+	return n
+}
+
 // compress 压缩旧轮次为摘要
-func (m *Manager) compress(ctx context.Context, mem *StockMemory) error {
-	keepCount := m.config.MaxRecentRounds
+// 从最新一轮往前保留，直到超出 token 预算或 MaxRecentRounds，最新一轮总会保留
+func (m *Manager) compress(ctx context.Context, summarizer Summarizer, mem *StockMemory) error {
+	keepCount, budget := 0, m.recentTokenBudget()
+	for i := len(mem.RecentRounds) - 1; i >= 0 && keepCount < max(m.config.MaxRecentRounds, 1); i-- {
+		if keepCount > 0 && m.recentTokens(mem.RecentRounds[i:]) > budget {
+			break
+		}
+		keepCount++
+	}
 	if len(mem.RecentRounds) <= keepCount {
 		return nil
 	}
@@ -169,13 +295,13 @@ func (m *Manager) compress(ctx context.Context, mem *StockMemory) error {
 	toKeep := mem.RecentRounds[len(mem.RecentRounds)-keepCount:]
 
 	// 如果没有 summarizer，只保留最近的轮次，不生成摘要
-	if m.summarizer == nil {
+	if summarizer == nil {
 		mem.RecentRounds = toKeep
 		return nil
 	}
 
 	// 生成新摘要
-	newSummary, err := m.summarizer.SummarizeRounds(ctx, toCompress)
+	newSummary, err := summarizer.SummarizeRounds(ctx, toCompress)
 	if err != nil {
 		return err
 	}
@@ -208,15 +334,17 @@ func (m *Manager) mergeSummaries(old, new string) string {
 // AddFacts 添加关键事实
 func (m *Manager) AddFacts(mem *StockMemory, facts []MemoryEntry) {
 	mem.KeyFacts = append(mem.KeyFacts, facts...)
-	// 限制数量
-	if len(mem.KeyFacts) > m.config.MaxKeyFacts {
-		mem.KeyFacts = mem.KeyFacts[len(mem.KeyFacts)-m.config.MaxKeyFacts:]
-	}
+	// 超出上限时按重要性淘汰
+	m.evict(mem)
 }
 
 // ExtractAndAddFacts 从内容中提取并添加事实
 func (m *Manager) ExtractAndAddFacts(ctx context.Context, mem *StockMemory, content, source string) error {
-	facts, err := m.summarizer.ExtractFacts(ctx, content, source)
+	summarizer := m.currentSummarizer()
+	if summarizer == nil {
+		return nil
+	}
+	facts, err := summarizer.ExtractFacts(ctx, content, source)
 	if err != nil {
 		return err
 	}
@@ -226,11 +354,15 @@ func (m *Manager) ExtractAndAddFacts(ctx context.Context, mem *StockMemory, cont
 
 // ExtractKeyPoints 智能提取讨论关键点
 func (m *Manager) ExtractKeyPoints(ctx context.Context, discussions []DiscussionInput) ([]string, error) {
-	if m.summarizer == nil {
+	return m.extractKeyPoints(ctx, m.currentSummarizer(), discussions)
+}
+
+func (m *Manager) extractKeyPoints(ctx context.Context, summarizer Summarizer, discussions []DiscussionInput) ([]string, error) {
+	if summarizer == nil {
 		// 无 LLM 时使用简单截取
 		return m.fallbackExtractKeyPoints(discussions), nil
 	}
-	return m.summarizer.ExtractKeyPoints(ctx, discussions)
+	return summarizer.ExtractKeyPoints(ctx, discussions)
 }
 
 // fallbackExtractKeyPoints 无 LLM 时的降级提取
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCompressByTokenBudget(t *testing.T) {
+	m := &Manager{config: Config{MaxRecentRounds: 3, CompressThreshold: 5, RecentTokenBudget: 200}}
+	short := func(q string) RoundMemory { return RoundMemory{Query: q, Consensus: "维持观望"} }
+	long := RoundMemory{Query: "长结论", Consensus: strings.Repeat("估值", 95)}
+
+	// 未超预算且轮数未达上限：不压缩
+	mem := &StockMemory{RecentRounds: []RoundMemory{short("a"), short("b")}}
+	if m.recentTokens(mem.RecentRounds) > m.recentTokenBudget() {
+		t.Fatalf("two short rounds over budget: %d", m.recentTokens(mem.RecentRounds))
+	}
+
+	// 单轮很长时超出预算，只保留最新一轮
+	mem.RecentRounds = []RoundMemory{short("a"), short("b"), long}
+	if m.recentTokens(mem.RecentRounds) <= m.recentTokenBudget() {
+		t.Fatalf("long round within budget: %d", m.recentTokens(mem.RecentRounds))
+	}
+	if err := m.compress(context.Background(), nil, mem); err != nil {
+		t.Fatal(err)
+	}
+	if len(mem.RecentRounds) != 1 || mem.RecentRounds[0].Query != "长结论" {
+		t.Errorf("kept rounds = %+v", mem.RecentRounds)
+	}
+
+	// 轮次都很短时按 MaxRecentRounds 保留
+	mem.RecentRounds = []RoundMemory{short("a"), short("b"), short("c"), short("d"), short("e")}
+	m.compress(context.Background(), nil, mem)
+	if len(mem.RecentRounds) != 3 || mem.RecentRounds[0].Query != "c" {
+		t.Errorf("kept rounds = %+v", mem.RecentRounds)
+	}
+}
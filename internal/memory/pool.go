@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PoolScope 记忆池范围
+type PoolScope string
+
+const (
+	ScopeStock  PoolScope = "stock"  // 个股记忆
+	ScopeSector PoolScope = "sector" // 行业/板块记忆（同行业股票共享）
+	ScopeMacro  PoolScope = "macro"  // 宏观环境记忆（全局共享）
+)
+
+// 关键点范围标签（由提取步骤输出）
+var scopeTags = map[string]PoolScope{
+	"[个股]": ScopeStock,
+	"[行业]": ScopeSector,
+	"[宏观]": ScopeMacro,
+}
+
+// 记忆池存储 key（与股票代码不冲突）
+const (
+	sectorPoolPrefix = "sector_"
+	macroPoolKey     = "macro"
+	macroPoolName    = "宏观环境"
+)
+
+// poolFactWeight 跨股票记忆池条目的默认权重
+const poolFactWeight = 0.6
+
+// SectorResolver 根据股票代码解析所属行业
+type SectorResolver func(stockCode string) string
+
+// SetSectorResolver 设置行业解析器（未设置时不使用行业记忆池）
+func (m *Manager) SetSectorResolver(resolver SectorResolver) {
+	m.sectorResolver = resolver
+}
+
+// classifyKeyPoint 解析关键点的范围标签，无标签视为个股
+func classifyKeyPoint(point string) (PoolScope, string) {
+	point = strings.TrimSpace(point)
+	for tag, scope := range scopeTags {
+		if strings.HasPrefix(point, tag) {
+			return scope, strings.TrimSpace(strings.TrimPrefix(point, tag))
+		}
+	}
+	return ScopeStock, point
+}
+
+// isPoolKey 存储 key 是否为跨股票记忆池（而非个股记忆）
+func isPoolKey(key string) bool {
+	return key == macroPoolKey || strings.HasPrefix(key, sectorPoolPrefix)
+}
+
+// sectorPoolKey 行业记忆池的存储 key，sector 为空时返回 false
+func sectorPoolKey(sector string) (string, bool) {
+	if sector == "" {
+		return "", false
+	}
+	// 行业名用作文件名，替换路径分隔符
+	return sectorPoolPrefix + strings.NewReplacer("/", "_", "\\", "_").Replace(sector), true
+}
+
+// loadPoolLocked 加载记忆池（不存在则创建），需已持有 poolMu
+// 加载后常驻 m.pools，并发会议读写的是同一份数据
+func (m *Manager) loadPoolLocked(key, name string) *StockMemory {
+	if pool, ok := m.pools[key]; ok {
+		return pool
+	}
+	pool, err := m.storage.Load(key)
+	if err != nil {
+		pool = NewStockMemory(key, name)
+	}
+	if m.pools == nil {
+		m.pools = make(map[string]*StockMemory)
+	}
+	m.pools[key] = pool
+	return pool
+}
+
+// addPoolFacts 将行业/宏观关键点写入对应记忆池
+// 加载与追加在同一临界区内完成，保存的是快照，异步保存时不会与后续追加竞争
+func (m *Manager) addPoolFacts(key, name string, points []string, source string) {
+	if len(points) == 0 {
+		return
+	}
+	facts := m.pointsToFacts(points, EntryTypeOpinion, source)
+
+	m.poolMu.Lock()
+	pool := m.loadPoolLocked(key, name)
+	m.AddFacts(pool, facts)
+	snapshot := *pool
+	snapshot.KeyFacts = slices.Clone(pool.KeyFacts)
+	m.poolMu.Unlock()
+	m.SaveAsync(&snapshot)
+}
+
+// pointsToFacts 将关键点转换为记忆条目
+func (m *Manager) pointsToFacts(points []string, entryType EntryType, source string) []MemoryEntry {
+	now := time.Now().UnixMilli()
+	facts := make([]MemoryEntry, 0, len(points))
+	for _, p := range points {
+		facts = append(facts, MemoryEntry{
+			ID:        uuid.New().String(),
+			Type:      entryType,
+			Content:   p,
+			Source:    source,
+			Keywords:  m.tokenizer.Extract(p, 5),
+			Timestamp: now,
+			Weight:    poolFactWeight,
+		})
+	}
+	return facts
+}
+
+// buildPoolContext 构建行业与宏观记忆上下文
+func (m *Manager) buildPoolContext(sector, currentQuery string) string {
+	var sb strings.Builder
+
+	m.poolMu.Lock()
+	defer m.poolMu.Unlock()
+
+	if key, ok := sectorPoolKey(sector); ok {
+		if facts := m.relevantOrRecent(m.loadPoolLocked(key, sector).KeyFacts, currentQuery+" "+sector, 3); len(facts) > 0 {
+			fmt.Fprintf(&sb, "【行业记忆（%s）】\n", sector)
+			writeFacts(&sb, facts)
+		}
+	}
+	if facts := m.relevantOrRecent(m.loadPoolLocked(macroPoolKey, macroPoolName).KeyFacts, currentQuery, 3); len(facts) > 0 {
+		sb.WriteString("【宏观环境记忆】\n")
+		writeFacts(&sb, facts)
+	}
+	return sb.String()
+}
+
+// relevantOrRecent 优先取与问题相关的条目，没有相关条目时取最近的条目
+// 行业/宏观观点很少与个股问题直接重合关键词，但仍是讨论的背景
+func (m *Manager) relevantOrRecent(facts []MemoryEntry, query string, limit int) []MemoryEntry {
+	if relevant := m.relevance.FindRelevant(facts, query, limit); len(relevant) > 0 {
+		return relevant
+	}
+	if len(facts) > limit {
+		facts = facts[len(facts)-limit:]
+	}
+	return facts
+}
+
+// writeFacts 按「- [日期] 内容」格式写入记忆条目
+func writeFacts(sb *strings.Builder, facts []MemoryEntry) {
+	for _, fact := range facts {
+		timeStr := time.UnixMilli(fact.Timestamp).Format("2006-01-02")
+		fmt.Fprintf(sb, "- [%s] %s（来源: %s）\n", timeStr, fact.Content, fact.Source)
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func nowMillis() int64 { return time.Now().UnixMilli() }
+
+func TestClassifyKeyPoint(t *testing.T) {
+	tests := []struct {
+		input       string
+		wantScope   PoolScope
+		wantContent string
+	}{
+		{input: "[个股]技术分析师: 放量突破年线", wantScope: ScopeStock, wantContent: "技术分析师: 放量突破年线"},
+		{input: "[行业] 行业研究员: 新能源产能出清接近尾声", wantScope: ScopeSector, wantContent: "行业研究员: 新能源产能出清接近尾声"},
+		{input: "[宏观]宏观分析师: 降息周期利好成长股", wantScope: ScopeMacro, wantContent: "宏观分析师: 降息周期利好成长股"},
+		{input: "基本面分析师: 估值偏高", wantScope: ScopeStock, wantContent: "基本面分析师: 估值偏高"},
+	}
+
+	for _, tc := range tests {
+		scope, content := classifyKeyPoint(tc.input)
+		if scope != tc.wantScope || content != tc.wantContent {
+			t.Errorf("classifyKeyPoint(%q) = (%q, %q), want (%q, %q)", tc.input, scope, content, tc.wantScope, tc.wantContent)
+		}
+	}
+}
+
+func TestApplyRevisionsAndEvict(t *testing.T) {
+	m := &Manager{config: Config{MaxKeyFacts: 2}, relevance: NewRelevance(nil)}
+	mem := NewStockMemory("sh600519", "贵州茅台")
+	mem.KeyFacts = []MemoryEntry{
+		{ID: "a", Content: "短期看空", Weight: 0.8},
+		{ID: "b", Content: "估值合理", Weight: 0.5},
+		{ID: "c", Content: "短期转多", Weight: 0.6},
+	}
+	for i := range mem.KeyFacts {
+		mem.KeyFacts[i].Timestamp = nowMillis()
+	}
+
+	if n := applyRevisions(mem.KeyFacts, []FactRevision{{ID: "a", Status: "contradicted", By: "c"}, {ID: "b", Status: "valid"}}); n != 1 {
+		t.Fatalf("applyRevisions applied %d, want 1", n)
+	}
+	if !mem.KeyFacts[0].Superseded || mem.KeyFacts[0].SupersededBy != "c" {
+		t.Fatalf("fact a not superseded: %+v", mem.KeyFacts[0])
+	}
+
+	m.evict(mem)
+	if len(mem.KeyFacts) != 2 || mem.KeyFacts[0].ID != "b" || mem.KeyFacts[1].ID != "c" {
+		t.Fatalf("evict kept %+v, want [b c]", mem.KeyFacts)
+	}
+}
+
+// splitTokenizer 按空格分词，避免测试加载词典
+type splitTokenizer struct{}
+
+func (splitTokenizer) Extract(text string, topK int) []string { return strings.Fields(text) }
+func (splitTokenizer) Cut(text string) []string               { return strings.Fields(text) }
+
+func TestAddPoolFactsConcurrent(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	m := &Manager{storage: storage, tokenizer: splitTokenizer{}, saveCh: make(chan *StockMemory, 100)}
+
+	// 多场会议同时写入同一行业池，不丢失观点
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.addPoolFacts("sector_白酒", "白酒", []string{fmt.Sprintf("观点%d", i)}, "会议")
+		}()
+	}
+	wg.Wait()
+
+	m.poolMu.Lock()
+	pool := m.loadPoolLocked("sector_白酒", "白酒")
+	n := len(pool.KeyFacts)
+	m.poolMu.Unlock()
+	if n != 20 {
+		t.Errorf("pool has %d facts, want 20", n)
+	}
+
+	// 记忆池不出现在个股记忆列表中
+	storage.Save(pool)
+	storage.Save(NewStockMemory(macroPoolKey, macroPoolName))
+	storage.Save(NewStockMemory("sh600519", "贵州茅台"))
+	codes, err := storage.List()
+	if err != nil || len(codes) != 1 || codes[0] != "sh600519" {
+		t.Errorf("List = %v, %v", codes, err)
+	}
+}
@@ -38,6 +38,10 @@ func (r *Relevance) FindRelevant(facts []MemoryEntry, query string, limit int) [
 	// 计算每个事实的相关性分数
 	scored := make([]ScoredEntry, 0, len(facts))
 	for _, fact := range facts {
+		// 已被推翻的结论不再作为上下文
+		if fact.Superseded {
+			continue
+		}
 		score := r.calculateScore(queryKeywords, fact)
 		if score > 0.1 {
 			scored = append(scored, ScoredEntry{Entry: fact, Score: score})
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"iter"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// fakeLLM 返回固定文本并记录调用次数
+type fakeLLM struct {
+	reply string
+	calls int
+}
+
+func (f *fakeLLM) Name() string { return "fake" }
+
+func (f *fakeLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	f.calls++
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{Content: genai.NewContentFromText(f.reply, genai.RoleModel)}, nil)
+	}
+}
+
+func TestScopedLLMs(t *testing.T) {
+	m := &Manager{}
+	global := &fakeLLM{reply: "[个股]全局: 不应被调用"}
+	m.SetLLMs(global, nil)
+
+	llmA := &fakeLLM{reply: "[个股]技术派: 放量突破"}
+	llmB := &fakeLLM{reply: "[行业]行业派: 景气回升"}
+	scopeA, scopeB := m.Scoped(llmA, nil), m.Scoped(llmB, nil)
+
+	discussions := []DiscussionInput{{AgentName: "技术派", Content: "放量突破年线"}}
+	pointsA, err := scopeA.ExtractKeyPoints(context.Background(), discussions)
+	if err != nil || len(pointsA) != 1 || pointsA[0] != "[个股]技术派: 放量突破" {
+		t.Errorf("scope A points: %v %v", pointsA, err)
+	}
+	pointsB, _ := scopeB.ExtractKeyPoints(context.Background(), discussions)
+	if len(pointsB) != 1 || pointsB[0] != "[行业]行业派: 景气回升" {
+		t.Errorf("scope B points: %v", pointsB)
+	}
+	if llmA.calls != 1 || llmB.calls != 1 || global.calls != 0 {
+		t.Errorf("calls: a=%d b=%d global=%d", llmA.calls, llmB.calls, global.calls)
+	}
+
+	// 未绑定 LLM 的视图降级为简单截取
+	points, _ := m.Scoped(nil, nil).ExtractKeyPoints(context.Background(), discussions)
+	if len(points) != 1 || points[0] != "技术派: 放量突破年线" {
+		t.Errorf("fallback points: %v", points)
+	}
+}
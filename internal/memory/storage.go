@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/run-bigpig/jcp/internal/pkg/vault"
 )
 
 // Storage 存储接口
@@ -47,7 +49,7 @@ func (s *FileStorage) Load(stockCode string) (*StockMemory, error) {
 	s.mu.RUnlock()
 
 	// 从文件加载
-	data, err := os.ReadFile(s.getPath(stockCode))
+	data, err := vault.ReadFile(s.getPath(stockCode))
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +77,7 @@ func (s *FileStorage) Save(mem *StockMemory) error {
 		return err
 	}
 
-	if err := os.WriteFile(s.getPath(mem.StockCode), data, 0644); err != nil {
+	if err := vault.WriteFile(s.getPath(mem.StockCode), data); err != nil {
 		return err
 	}
 
@@ -96,7 +98,7 @@ func (s *FileStorage) Delete(stockCode string) error {
 	return err
 }
 
-// List 列出所有股票记忆
+// List 列出所有股票记忆（不含行业/宏观记忆池）
 func (s *FileStorage) List() ([]string, error) {
 	entries, err := os.ReadDir(s.dir)
 	if err != nil {
@@ -107,6 +109,9 @@ func (s *FileStorage) List() ([]string, error) {
 	for _, e := range entries {
 		if !e.IsDir() && filepath.Ext(e.Name()) == ".json" {
 			code := e.Name()[:len(e.Name())-5]
+			if isPoolKey(code) {
+				continue
+			}
 			codes = append(codes, code)
 		}
 	}
@@ -17,6 +17,7 @@ type Summarizer interface {
 	SummarizeRounds(ctx context.Context, rounds []RoundMemory) (string, error)
 	ExtractFacts(ctx context.Context, content, agentName string) ([]MemoryEntry, error)
 	ExtractKeyPoints(ctx context.Context, discussions []DiscussionInput) ([]string, error)
+	ReviewFacts(ctx context.Context, facts []MemoryEntry) ([]FactRevision, error)
 }
 
 // DiscussionInput 讨论输入（用于关键点提取）
@@ -28,20 +29,22 @@ type DiscussionInput struct {
 
 // LLMSummarizer 基于 LLM 的摘要生成器
 type LLMSummarizer struct {
-	llm       model.LLM
-	tokenizer Tokenizer
+	llm        model.LLM // 摘要压缩
+	extractLLM model.LLM // 关键点/事实提取
+	tokenizer  Tokenizer
 }
 
 // NewLLMSummarizer 创建 LLM 摘要生成器
 func NewLLMSummarizer(llm model.LLM, tokenizer Tokenizer) *LLMSummarizer {
 	return &LLMSummarizer{
-		llm:       llm,
-		tokenizer: tokenizer,
+		llm:        llm,
+		extractLLM: llm,
+		tokenizer:  tokenizer,
 	}
 }
 
 // generate 调用 LLM 生成内容
-func (s *LLMSummarizer) generate(ctx context.Context, prompt string) (string, error) {
+func (s *LLMSummarizer) generate(ctx context.Context, llm model.LLM, prompt string) (string, error) {
 	req := &model.LLMRequest{
 		Contents: []*genai.Content{
 			{
@@ -52,7 +55,7 @@ func (s *LLMSummarizer) generate(ctx context.Context, prompt string) (string, er
 	}
 
 	var result string
-	for resp, err := range s.llm.GenerateContent(ctx, req, false) {
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
 		if err != nil {
 			return "", err
 		}
@@ -77,7 +80,7 @@ func (s *LLMSummarizer) SummarizeRounds(ctx context.Context, rounds []RoundMemor
 	}
 
 	prompt := s.buildSummarizePrompt(rounds)
-	return s.generate(ctx, prompt)
+	return s.generate(ctx, s.llm, prompt)
 }
 
 func (s *LLMSummarizer) buildSummarizePrompt(rounds []RoundMemory) string {
@@ -101,7 +104,7 @@ func (s *LLMSummarizer) buildSummarizePrompt(rounds []RoundMemory) string {
 // ExtractFacts 从讨论内容中提取关键事实
 func (s *LLMSummarizer) ExtractFacts(ctx context.Context, content, agentName string) ([]MemoryEntry, error) {
 	prompt := s.buildExtractPrompt(content)
-	result, err := s.generate(ctx, prompt)
+	result, err := s.generate(ctx, s.extractLLM, prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -131,9 +134,9 @@ func (s *LLMSummarizer) parseFacts(jsonStr, source string) ([]MemoryEntry, error
 	jsonStr = strings.TrimSpace(jsonStr)
 
 	var raw []struct {
-		Content string    `json:"content"`
-		Type    string    `json:"type"`
-		Weight  float64   `json:"weight"`
+		Content string  `json:"content"`
+		Type    string  `json:"type"`
+		Weight  float64 `json:"weight"`
 	}
 
 	if err := json.Unmarshal([]byte(jsonStr), &raw); err != nil {
@@ -166,7 +169,7 @@ func (s *LLMSummarizer) ExtractKeyPoints(ctx context.Context, discussions []Disc
 	}
 
 	prompt := s.buildKeyPointsPrompt(discussions)
-	result, err := s.generate(ctx, prompt)
+	result, err := s.generate(ctx, s.extractLLM, prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -184,8 +187,9 @@ func (s *LLMSummarizer) buildKeyPointsPrompt(discussions []DiscussionInput) stri
 	sb.WriteString("要求：\n")
 	sb.WriteString("1. 每条观点简洁明了，不超过30字\n")
 	sb.WriteString("2. 保留具体数据和结论\n")
-	sb.WriteString("3. 格式：专家名: 观点内容\n")
-	sb.WriteString("4. 每行一条，直接输出，不要编号\n")
+	sb.WriteString("3. 格式：[范围]专家名: 观点内容\n")
+	sb.WriteString("4. 范围标签三选一：[个股] 针对这只股票本身；[行业] 针对所在行业/板块整体（如新能源景气度）；[宏观] 针对利率、政策、大盘环境\n")
+	sb.WriteString("5. 每行一条，直接输出，不要编号\n")
 	return sb.String()
 }
 
@@ -21,6 +21,9 @@ type MemoryEntry struct {
 	Keywords  []string  `json:"keywords"`  // 关键词（用于文本匹配）
 	Timestamp int64     `json:"timestamp"`
 	Weight    float64   `json:"weight"` // 重要性权重 0-1
+
+	Superseded   bool   `json:"superseded,omitempty"`    // 已过时或被新结论推翻
+	SupersededBy string `json:"superseded_by,omitempty"` // 推翻它的条目 ID
 }
 
 // RoundMemory 单轮讨论记忆
@@ -36,6 +39,7 @@ type RoundMemory struct {
 type StockMemory struct {
 	StockCode    string        `json:"stock_code"`
 	StockName    string        `json:"stock_name"`
+	Sector       string        `json:"sector,omitempty"` // 所属行业（用于关联行业记忆池）
 	Summary      string        `json:"summary"`       // 历史摘要
 	KeyFacts     []MemoryEntry `json:"key_facts"`     // 关键事实
 	RecentRounds []RoundMemory `json:"recent_rounds"` // 最近几轮讨论
@@ -62,7 +66,9 @@ type Config struct {
 	MaxRecentRounds   int // 保留最近几轮讨论，默认 3
 	MaxKeyFacts       int // 最大关键事实数，默认 20
 	MaxSummaryLength  int // 摘要最大字数，默认 300
-	CompressThreshold int // 触发压缩的轮次数，默认 5
+	CompressThreshold   int // 近期讨论达到该轮数时即使未超出 token 预算也压缩，默认 5
+	RecentTokenBudget   int // 近期讨论的 token 预算，超出即压缩旧轮次，默认 1200
+	ConsolidateInterval int // 每隔几轮整理一次记忆（识别过时/矛盾结论），默认 3
 }
 
 // DefaultConfig 默认配置
@@ -71,6 +77,8 @@ func DefaultConfig() Config {
 		MaxRecentRounds:   3,
 		MaxKeyFacts:       20,
 		MaxSummaryLength:  300,
-		CompressThreshold: 5,
+		CompressThreshold:   5,
+		RecentTokenBudget:   defaultRecentTokenBudget,
+		ConsolidateInterval: defaultConsolidateInterval,
 	}
 }
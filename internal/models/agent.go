@@ -12,4 +12,6 @@ type AgentConfig struct {
 	MCPServers  []string `json:"mcpServers"`
 	Enabled     bool     `json:"enabled"`
 	AIConfigID  string   `json:"aiConfigId"` // 可选，空则用默认AI
+
+	UnifiedTools bool `json:"unifiedTools,omitempty"` // 将行情类工具合并为一个 market_data 工具，减少弱模型的工具数量
 }
@@ -0,0 +1,118 @@
+package models
+
+// AITask 调用 LLM 的任务类型
+type AITask string
+
+const (
+	AITaskExpert     AITask = "expert"     // 专家发言（默认）
+	AITaskModerator  AITask = "moderator"  // 意图分析(小韭菜)
+	AITaskSummary    AITask = "summary"    // 会议总结
+	AITaskMemory     AITask = "memory"     // 记忆压缩摘要
+	AITaskExtraction AITask = "extraction" // 关键点/事实提取
+	AITaskStrategy   AITask = "strategy"   // 策略生成、提示词增强
+)
+
+// AIRoutingConfig 任务 → AI 配置路由表（值为 AIConfig.ID，留空则按降级规则解析）
+// 便宜的模型负责提取/摘要，昂贵的模型只用于专家发言
+type AIRoutingConfig struct {
+	Expert     string `json:"expert"`
+	Moderator  string `json:"moderator"`
+	Summary    string `json:"summary"`
+	Memory     string `json:"memory"`
+	Extraction string `json:"extraction"`
+	Strategy   string `json:"strategy"`
+}
+
+// Get 获取任务路由的 AI 配置 ID
+func (r AIRoutingConfig) Get(task AITask) string {
+	switch task {
+	case AITaskExpert:
+		return r.Expert
+	case AITaskModerator:
+		return r.Moderator
+	case AITaskSummary:
+		return r.Summary
+	case AITaskMemory:
+		return r.Memory
+	case AITaskExtraction:
+		return r.Extraction
+	case AITaskStrategy:
+		return r.Strategy
+	}
+	return ""
+}
+
+// aiTaskFallback 未路由任务的降级链：总结沿用小韭菜，提取沿用记忆
+var aiTaskFallback = map[AITask]AITask{
+	AITaskSummary:    AITaskModerator,
+	AITaskExtraction: AITaskMemory,
+}
+
+// legacyAIID 兼容路由表之前的独立配置字段
+func (c *AppConfig) legacyAIID(task AITask) string {
+	switch task {
+	case AITaskModerator:
+		return c.ModeratorAIID
+	case AITaskMemory:
+		return c.Memory.AIConfigID
+	case AITaskStrategy:
+		return c.StrategyAIID
+	}
+	return ""
+}
+
+// FindAIConfig 按 ID 查找 AI 配置
+func (c *AppConfig) FindAIConfig(id string) *AIConfig {
+	if id == "" {
+		return nil
+	}
+	for i := range c.AIConfigs {
+		if c.AIConfigs[i].ID == id {
+			return &c.AIConfigs[i]
+		}
+	}
+	return nil
+}
+
+// DefaultAIConfig 获取默认 AI 配置
+func (c *AppConfig) DefaultAIConfig() *AIConfig {
+	for i := range c.AIConfigs {
+		if c.AIConfigs[i].ID == c.DefaultAIID {
+			return &c.AIConfigs[i]
+		}
+		if c.AIConfigs[i].IsDefault {
+			return &c.AIConfigs[i]
+		}
+	}
+	if len(c.AIConfigs) > 0 {
+		return &c.AIConfigs[0]
+	}
+	return nil
+}
+
+// RoutedAIConfig 获取任务显式路由的 AI 配置（含旧字段兼容与降级链），未配置返回 nil
+func (c *AppConfig) RoutedAIConfig(task AITask) *AIConfig {
+	for t := task; t != ""; t = aiTaskFallback[t] {
+		id := c.AIRouting.Get(t)
+		if id == "" {
+			id = c.legacyAIID(t)
+		}
+		if cfg := c.FindAIConfig(id); cfg != nil {
+			return cfg
+		}
+	}
+	return nil
+}
+
+// ResolveAIConfig 获取任务实际使用的 AI 配置：路由 → 专家路由 → 默认配置
+func (c *AppConfig) ResolveAIConfig(task AITask) *AIConfig {
+	if cfg := c.RoutedAIConfig(task); cfg != nil {
+		return cfg
+	}
+	if task != AITaskExpert {
+		if cfg := c.RoutedAIConfig(AITaskExpert); cfg != nil {
+			return cfg
+		}
+	}
+	return c.DefaultAIConfig()
+}
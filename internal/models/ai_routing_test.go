@@ -0,0 +1,34 @@
+package models
+
+import "testing"
+
+// TestResolveAIConfig 测试任务路由、旧字段兼容与降级链
+func TestResolveAIConfig(t *testing.T) {
+	cfg := &AppConfig{
+		AIConfigs:     []AIConfig{{ID: "cheap"}, {ID: "smart", IsDefault: true}, {ID: "mid"}},
+		ModeratorAIID: "mid",
+		AIRouting:     AIRoutingConfig{Memory: "cheap"},
+	}
+
+	cases := map[AITask]string{
+		AITaskModerator:  "mid",   // 旧字段兼容
+		AITaskSummary:    "mid",   // 沿用小韭菜
+		AITaskExtraction: "cheap", // 沿用记忆
+		AITaskExpert:     "smart", // 默认配置
+		AITaskStrategy:   "smart",
+	}
+	for task, want := range cases {
+		if got := cfg.ResolveAIConfig(task); got == nil || got.ID != want {
+			t.Errorf("%s => %+v, want %s", task, got, want)
+		}
+	}
+
+	// 路由表优先于旧字段
+	cfg.AIRouting.Moderator = "cheap"
+	if got := cfg.RoutedAIConfig(AITaskModerator); got == nil || got.ID != "cheap" {
+		t.Errorf("moderator => %+v, want cheap", got)
+	}
+	if got := cfg.RoutedAIConfig(AITaskExpert); got != nil {
+		t.Errorf("未路由的专家任务应返回 nil, got %+v", got)
+	}
+}
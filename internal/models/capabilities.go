@@ -0,0 +1,47 @@
+package models
+
+// ModelCapabilities 连接测试时自动探测的模型能力
+type ModelCapabilities struct {
+	ProbedAt    int64 `json:"probedAt"`    // 探测时间（毫秒，0 表示未探测）
+	Streaming   bool  `json:"streaming"`   // 流式输出
+	ToolCalling bool  `json:"toolCalling"` // 标准 function call
+	JSONMode    bool  `json:"jsonMode"`    // JSON 结构化输出
+	SystemRole  bool  `json:"systemRole"`  // system role / system 字段
+}
+
+// Probed 是否已完成探测
+func (c ModelCapabilities) Probed() bool {
+	return c.ProbedAt > 0
+}
+
+// ToolGuideMode 专家指令中工具描述的注入方式
+type ToolGuideMode string
+
+const (
+	ToolGuideAuto   ToolGuideMode = ""       // 探测确认支持标准 function call 时只列工具名称，否则附带描述
+	ToolGuideAlways ToolGuideMode = "always" // 始终附带工具描述
+	ToolGuideNever  ToolGuideMode = "never"  // 只列工具名称，描述仅随工具声明发送
+)
+
+// SupportsStreaming 是否可使用流式输出（未探测时默认支持）
+func (c *AIConfig) SupportsStreaming() bool {
+	return !c.Capabilities.Probed() || c.Capabilities.Streaming
+}
+
+// SupportsTools 是否可挂载工具（未探测时默认支持）
+func (c *AIConfig) SupportsTools() bool {
+	return !c.Capabilities.Probed() || c.Capabilities.ToolCalling
+}
+
+// NeedsToolDescriptions 专家指令中是否需要附带工具描述
+// 模型原生支持 function call 时工具描述已随工具声明发送，重复注入浪费 token，还可能让模型转述工具而不调用
+func (c *AIConfig) NeedsToolDescriptions() bool {
+	switch c.ToolGuide {
+	case ToolGuideAlways:
+		return true
+	case ToolGuideNever:
+		return false
+	default:
+		return !c.Capabilities.Probed() || !c.Capabilities.ToolCalling
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+// TestNeedsToolDescriptions 测试工具描述注入方式：自动模式按探测结果决定
+func TestNeedsToolDescriptions(t *testing.T) {
+	probed := ModelCapabilities{ProbedAt: 1, ToolCalling: true}
+	cases := []struct {
+		name string
+		cfg  AIConfig
+		want bool
+	}{
+		{"未探测", AIConfig{}, true},
+		{"原生工具调用", AIConfig{Capabilities: probed}, false},
+		{"不支持工具调用", AIConfig{Capabilities: ModelCapabilities{ProbedAt: 1}}, true},
+		{"始终注入", AIConfig{Capabilities: probed, ToolGuide: ToolGuideAlways}, true},
+		{"不注入", AIConfig{ToolGuide: ToolGuideNever}, false},
+	}
+	for _, c := range cases {
+		if got := c.cfg.NeedsToolDescriptions(); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+// TestResolvedAPIStyle 测试接口风格：未设置时沿用 UseResponses，auto 模式优先使用检测结果
+func TestResolvedAPIStyle(t *testing.T) {
+	cases := []struct {
+		cfg  AIConfig
+		want APIStyle
+	}{
+		{AIConfig{}, APIStyleChat},
+		{AIConfig{UseResponses: true}, APIStyleResponses},
+		{AIConfig{UseResponses: true, APIStyle: APIStyleChat}, APIStyleChat},
+		{AIConfig{APIStyle: APIStyleAuto}, APIStyleAuto},
+		{AIConfig{APIStyle: APIStyleAuto, DetectedAPIStyle: APIStyleResponses}, APIStyleResponses},
+	}
+	for i, c := range cases {
+		if got := c.cfg.ResolvedAPIStyle(); got != c.want {
+			t.Errorf("case %d: got %q, want %q", i, got, c.want)
+		}
+	}
+}
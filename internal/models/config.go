@@ -8,6 +8,7 @@ const (
 	AIProviderGemini    AIProvider = "gemini"
 	AIProviderVertexAI  AIProvider = "vertexai"
 	AIProviderAnthropic AIProvider = "anthropic"
+	AIProviderMock      AIProvider = "mock" // 按场景文件返回脚本化回复，用于演示与测试，无需 API Key
 )
 
 // AIConfig AI服务配置
@@ -17,19 +18,103 @@ type AIConfig struct {
 	Provider    AIProvider `json:"provider"`
 	BaseURL     string     `json:"baseUrl"`
 	APIKey      string     `json:"apiKey"`
+	APIKeys     []string   `json:"apiKeys,omitempty"` // 额外 API Key，与 APIKey 一起轮询分摊限流
 	ModelName   string     `json:"modelName"`
 	MaxTokens   int        `json:"maxTokens"`
 	Temperature float64    `json:"temperature"`
 	Timeout     int        `json:"timeout"`
 	IsDefault   bool       `json:"isDefault"`
-	// OpenAI Responses API 开关
+	// OpenAI Responses API 开关（旧配置，APIStyle 为空时生效）
 	UseResponses bool `json:"useResponses"`
+	// OpenAI 接口风格：chat / responses / auto（自动检测，不支持 Responses API 时回退 Chat Completions）
+	APIStyle APIStyle `json:"apiStyle,omitempty"`
+	// auto 模式连接测试时检测到的接口风格（自动写入，用户不可见）
+	DetectedAPIStyle APIStyle `json:"detectedApiStyle,omitempty"`
 	// 不支持 system role（自动检测，用户不可见）
 	NoSystemRole bool `json:"noSystemRole"`
+	// 自动探测的模型能力（连接测试时写入）
+	Capabilities ModelCapabilities `json:"capabilities"`
+	// 是否在专家指令中附带工具描述：auto（默认）/ always / never
+	ToolGuide ToolGuideMode `json:"toolGuide,omitempty"`
+	// 降级链：调用失败时依次切换到这些 AI 配置重试本轮发言
+	FallbackIDs []string `json:"fallbackIds,omitempty"`
+	// 计费标准（留空则按模型名查内置价格表）
+	Pricing ModelPricing `json:"pricing"`
+	// 单独的代理设置（模式为空时跟随全局代理），如海外模型走代理、国内行情直连
+	Proxy ProxyConfig `json:"proxy"`
+	// 连接、读取超时与 TLS 设置（零值使用默认值）
+	HTTP AIHTTPConfig `json:"http"`
+	// OpenAI 兼容网关的自定义请求头（如 X-Api-Version、租户 ID）与附加请求体字段（如 enable_thinking）
+	ExtraHeaders map[string]string `json:"extraHeaders,omitempty"`
+	ExtraBody    map[string]any    `json:"extraBody,omitempty"`
 	// Vertex AI 专用字段
 	Project         string `json:"project"`
 	Location        string `json:"location"`
 	CredentialsJSON string `json:"credentialsJson"`
+	// Gemini / Vertex AI 生成参数（BaseURL 非空时作为自定义端点）
+	ThinkingBudget *int32          `json:"thinkingBudget,omitempty"` // 思考预算（nil 不设置，0 关闭思考，-1 动态）
+	SafetySettings []SafetySetting `json:"safetySettings,omitempty"` // 安全过滤设置
+	// Mock 专用：场景文件路径（YAML），为空使用内置演示场景
+	Scenario string `json:"scenario,omitempty"`
+}
+
+// APIStyle OpenAI 兼容接口风格
+type APIStyle string
+
+const (
+	APIStyleChat      APIStyle = "chat"      // Chat Completions API
+	APIStyleResponses APIStyle = "responses" // Responses API
+	APIStyleAuto      APIStyle = "auto"      // 自动检测
+)
+
+// ResolvedAPIStyle 实际使用的接口风格：未设置 APIStyle 时沿用 UseResponses 开关，
+// auto 模式已检测过时使用检测结果，未检测时返回 APIStyleAuto
+func (c *AIConfig) ResolvedAPIStyle() APIStyle {
+	switch c.APIStyle {
+	case "":
+		if c.UseResponses {
+			return APIStyleResponses
+		}
+		return APIStyleChat
+	case APIStyleAuto:
+		if c.DetectedAPIStyle == APIStyleChat || c.DetectedAPIStyle == APIStyleResponses {
+			return c.DetectedAPIStyle
+		}
+		return APIStyleAuto
+	default:
+		return c.APIStyle
+	}
+}
+
+// AllAPIKeys 获取全部 API Key（APIKey 在前，去重去空）
+func (c *AIConfig) AllAPIKeys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, k := range append([]string{c.APIKey}, c.APIKeys...) {
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SafetySetting Gemini 安全过滤设置
+type SafetySetting struct {
+	Category  string `json:"category"`  // 如 HARM_CATEGORY_DANGEROUS_CONTENT
+	Threshold string `json:"threshold"` // 如 BLOCK_NONE / BLOCK_ONLY_HIGH
+}
+
+// AIHTTPConfig AI 请求的连接设置，自建网关使用私有证书时可指定 CA 或跳过校验
+type AIHTTPConfig struct {
+	ConnectTimeout     int    `json:"connectTimeout"`     // 建立连接超时（秒），默认 30
+	ReadTimeout        int    `json:"readTimeout"`        // 读取超时（秒）：收到响应头后两次读取数据间隔的上限，默认 120
+	StreamIdleTimeout  int    `json:"streamIdleTimeout"`  // 流式输出空闲超时（秒）：两个输出片段间隔超过该值时中止并重试，默认 60
+	IdleConnTimeout    int    `json:"idleConnTimeout"`    // 空闲连接保持时间（秒），默认 90
+	DisableKeepAlives  bool   `json:"disableKeepAlives"`  // 禁用连接复用（网关频繁断开空闲连接时开启）
+	CACert             string `json:"caCert"`             // 额外信任的 CA 证书（PEM）
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"` // 跳过证书校验（仅用于调试）
 }
 
 // MCPTransportType MCP传输类型
@@ -46,33 +131,122 @@ type MCPServerConfig struct {
 	ID            string           `json:"id"`
 	Name          string           `json:"name"`
 	TransportType MCPTransportType `json:"transportType"`
-	Endpoint      string           `json:"endpoint"`      // HTTP/SSE 端点 URL
-	Command       string           `json:"command"`       // 命令行传输的命令
-	Args          []string         `json:"args"`          // 命令行参数
-	ToolFilter    []string         `json:"toolFilter"`    // 工具过滤列表（空则全部）
-	Enabled       bool             `json:"enabled"`       // 是否启用
+	Endpoint      string           `json:"endpoint"`   // HTTP/SSE 端点 URL
+	Command       string           `json:"command"`    // 命令行传输的命令
+	Args          []string         `json:"args"`       // 命令行参数
+	ToolFilter    []string         `json:"toolFilter"` // 工具过滤列表（空则全部）
+	Enabled       bool             `json:"enabled"`    // 是否启用
+}
+
+// CustomToolConfig 用户自定义 HTTP 工具配置（无需重新编译即可作为专家工具）
+type CustomToolConfig struct {
+	Name        string            `json:"name"`        // 工具名称（字母、数字、下划线）
+	Description string            `json:"description"` // 工具描述，提供给模型
+	Method      string            `json:"method"`      // GET / POST，默认 GET
+	URL         string            `json:"url"`         // URL 模板，{{参数名}} 替换为参数值
+	Headers     map[string]string `json:"headers"`     // 请求头，值同样支持 {{参数名}}
+	Body        string            `json:"body"`        // POST 请求体模板（为空时发送参数 JSON）
+	InputSchema string            `json:"inputSchema"` // 参数 JSON Schema（为空表示无参数）
+	Extract     string            `json:"extract"`     // 结果提取路径，如 .data.items[].name（为空返回原文）
+	Enabled     bool              `json:"enabled"`     // 是否启用
+}
+
+// PythonToolConfig Python 代码执行工具配置
+type PythonToolConfig struct {
+	Enabled     bool   `json:"enabled"`     // 是否启用 run_python 工具，默认关闭；脚本以当前用户身份在受限子进程中运行，不等同于系统级沙箱
+	Interpreter string `json:"interpreter"` // 解释器路径，默认 python3（Windows 为 python）
+	Timeout     int    `json:"timeout"`     // 单次执行超时（秒），默认 30
+}
+
+// BrokerConfig 券商接口配置（只读同步持仓与委托）
+type BrokerConfig struct {
+	Enabled   bool   `json:"enabled"`   // 是否启用，启用后持仓由券商同步
+	Type      string `json:"type"`      // 接口类型: qmt
+	BaseURL   string `json:"baseUrl"`   // 桥接服务地址，如 http://127.0.0.1:8765
+	Token     string `json:"token"`     // 访问令牌（可选）
+	AccountID string `json:"accountId"` // 资金账号
+}
+
+// NotificationConfig 桌面通知配置（零值为全部开启、不设免打扰）
+type NotificationConfig struct {
+	Muted              bool     `json:"muted"`              // 关闭全部系统通知（应用内提示不受影响）
+	DisabledCategories []string `json:"disabledCategories"` // 关闭的通知类别: alert/meeting/briefing/mcp
+	QuietStart         string   `json:"quietStart"`         // 免打扰开始时间 HH:MM，为空不启用
+	QuietEnd           string   `json:"quietEnd"`           // 免打扰结束时间 HH:MM，可跨零点
+}
+
+// 推送渠道类型
+const (
+	PushChannelWeCom    = "wecom"    // 企业微信群机器人
+	PushChannelDingTalk = "dingtalk" // 钉钉群机器人
+	PushChannelTelegram = "telegram" // Telegram Bot
+	PushChannelBark     = "bark"     // Bark（iOS）
+)
+
+// PushChannelConfig 外部推送渠道配置
+type PushChannelConfig struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"` // wecom/dingtalk/telegram/bark
+	Enabled    bool     `json:"enabled"`
+	WebhookURL string   `json:"webhookUrl"`           // 机器人 Webhook 地址；Bark 为 https://api.day.app/<key>；Telegram 可填自建 API 地址
+	Secret     string   `json:"secret,omitempty"`     // 钉钉加签密钥
+	BotToken   string   `json:"botToken,omitempty"`   // Telegram Bot Token
+	ChatID     string   `json:"chatId,omitempty"`     // Telegram Chat ID
+	Categories []string `json:"categories,omitempty"` // 推送的通知类别，为空推送全部
+	Template   string   `json:"template,omitempty"`   // 消息模板（Go text/template），为空使用默认模板
+}
+
+// 语音朗读引擎
+const (
+	TTSProviderSystem = "system" // 系统语音（macOS say / Windows SAPI / Linux espeak-ng）
+	TTSProviderOpenAI = "openai" // OpenAI 兼容的 /audio/speech 接口
+)
+
+// TTSConfig 语音朗读配置
+type TTSConfig struct {
+	Provider     string  `json:"provider"`     // system/openai，为空使用系统语音
+	AIConfigID   string  `json:"aiConfigId"`   // openai 引擎复用该 AI 配置的接口地址与 API Key，为空使用默认配置
+	Model        string  `json:"model"`        // 语音模型，为空使用 tts-1
+	Voice        string  `json:"voice"`        // 音色；系统语音为语音名称（如 Tingting），为空使用系统默认
+	Speed        float64 `json:"speed"`        // 语速倍率 0.5-2，0 表示正常语速
+	AutoBriefing bool    `json:"autoBriefing"` // 早报生成后自动合成语音
 }
 
 // AppConfig 应用配置
 type AppConfig struct {
-	Theme           string            `json:"theme"`           // 主题色: military, ocean, purple, orange, dark
-	CandleColorMode string            `json:"candleColorMode"` // 涨跌颜色模式: red-up(红涨绿跌) / green-up(绿涨红跌)
-	AIConfigs       []AIConfig        `json:"aiConfigs"`
-	DefaultAIID     string            `json:"defaultAiId"`
-	StrategyAIID    string            `json:"strategyAiId"`  // 策略生成用AI
-	ModeratorAIID   string            `json:"moderatorAiId"` // 意图分析(小韭菜)用AI
-	MCPServers      []MCPServerConfig `json:"mcpServers"`    // MCP服务器配置列表
-	Memory          MemoryConfig      `json:"memory"`        // 记忆管理配置
-	Proxy           ProxyConfig       `json:"proxy"`         // 代理配置
-	Layout          LayoutConfig      `json:"layout"`        // 界面布局配置
-	OpenClaw        OpenClawConfig    `json:"openClaw"`      // OpenClaw 服务配置
-	Indicators      IndicatorConfig   `json:"indicators"`    // 技术指标配置
+	Theme                 string              `json:"theme"`           // 主题色: military, ocean, purple, orange, dark
+	CandleColorMode       string              `json:"candleColorMode"` // 涨跌颜色模式: red-up(红涨绿跌) / green-up(绿涨红跌)
+	Language              string              `json:"language"`        // 提示词、错误信息等后端文案语言: zh-CN(默认) / en-US
+	AIConfigs             []AIConfig          `json:"aiConfigs"`
+	DefaultAIID           string              `json:"defaultAiId"`
+	StrategyAIID          string              `json:"strategyAiId"`          // 策略生成用AI
+	ModeratorAIID         string              `json:"moderatorAiId"`         // 意图分析(小韭菜)用AI
+	MCPServers            []MCPServerConfig   `json:"mcpServers"`            // MCP服务器配置列表
+	CustomTools           []CustomToolConfig  `json:"customTools"`           // 自定义 HTTP 工具
+	DisabledTools         []string            `json:"disabledTools"`         // 运行时禁用的工具名称
+	PythonTool            PythonToolConfig    `json:"pythonTool"`            // Python 代码执行工具（默认关闭）
+	Memory                MemoryConfig        `json:"memory"`                // 记忆管理配置
+	Proxy                 ProxyConfig         `json:"proxy"`                 // 代理配置
+	Layout                LayoutConfig        `json:"layout"`                // 界面布局配置
+	OpenClaw              OpenClawConfig      `json:"openClaw"`              // OpenClaw 服务配置
+	Indicators            IndicatorConfig     `json:"indicators"`            // 技术指标配置
+	MeetingBudget         float64             `json:"meetingBudget"`         // 单次会议费用上限（美元，0 表示不限制）
+	MaxConcurrentMeetings int                 `json:"maxConcurrentMeetings"` // 同时进行的会议数上限（0 使用默认值 2），超出时排队
+	ContextBudget         ContextBudgetConfig `json:"contextBudget"`         // 专家提示词各部分的上下文预算
+	AIRouting             AIRoutingConfig     `json:"aiRouting"`             // 任务 → AI 配置路由表
+	UserProfile           UserProfile         `json:"userProfile"`           // 用户投资画像（注入会议提示词）
+	Broker                BrokerConfig        `json:"broker"`                // 券商接口配置
+	Notification          NotificationConfig  `json:"notification"`          // 桌面通知配置
+	PushChannels          []PushChannelConfig `json:"pushChannels"`          // 外部推送渠道
+	TTS                   TTSConfig           `json:"tts"`                   // 语音朗读
 }
 
 // ProxyMode 代理模式
 type ProxyMode string
 
 const (
+	ProxyModeGlobal ProxyMode = ""       // 跟随全局代理（AI 配置的默认值）
 	ProxyModeNone   ProxyMode = "none"   // 无代理，直连
 	ProxyModeSystem ProxyMode = "system" // 使用系统代理
 	ProxyModeCustom ProxyMode = "custom" // 自定义代理
@@ -81,17 +255,31 @@ const (
 // ProxyConfig 代理配置
 type ProxyConfig struct {
 	Mode      ProxyMode `json:"mode"`
-	CustomURL string    `json:"customUrl"` // 自定义代理地址
+	CustomURL string    `json:"customUrl"` // 自定义代理地址，支持 http(s):// 与 socks5(h)://
 }
 
 // MemoryConfig 记忆管理配置
 type MemoryConfig struct {
-	Enabled           bool   `json:"enabled"`           // 是否启用记忆管理
-	AIConfigID        string `json:"aiConfigId"`        // 使用的 LLM 配置 ID（空则使用默认）
-	MaxRecentRounds   int    `json:"maxRecentRounds"`   // 保留最近几轮讨论
-	MaxKeyFacts       int    `json:"maxKeyFacts"`       // 最大关键事实数
-	MaxSummaryLength  int    `json:"maxSummaryLength"`  // 摘要最大字数
-	CompressThreshold int    `json:"compressThreshold"` // 触发压缩的轮次数
+	Enabled             bool   `json:"enabled"`             // 是否启用记忆管理
+	AIConfigID          string `json:"aiConfigId"`          // 使用的 LLM 配置 ID（空则使用默认）
+	MaxRecentRounds     int    `json:"maxRecentRounds"`     // 保留最近几轮讨论
+	MaxKeyFacts         int    `json:"maxKeyFacts"`         // 最大关键事实数
+	MaxSummaryLength    int    `json:"maxSummaryLength"`    // 摘要最大字数
+	CompressThreshold   int    `json:"compressThreshold"`   // 触发压缩的轮次数
+	ConsolidateInterval int    `json:"consolidateInterval"` // 每隔几轮整理一次记忆（识别过时/矛盾结论）
+}
+
+// ContextBudgetConfig 专家提示词上下文预算（按估算 token 计，0 使用默认值）
+// 超出预算的部分压缩后再注入，避免靠后发言的专家提示词越滚越长
+type ContextBudgetConfig struct {
+	Memory      int  `json:"memory"`      // 股票记忆
+	Speeches    int  `json:"speeches"`    // 前面专家的发言
+	ToolGuide   int  `json:"toolGuide"`   // 工具使用说明
+	LLMCompress bool `json:"llmCompress"` // 超出预算时用会议总结模型压缩（关闭时直接截断）
+	// 单个工具结果的上限（0 使用各工具的默认值），研报正文、热搜榜单等大结果超出时截断或摘要
+	ToolOutput       int            `json:"toolOutput"`
+	ToolOutputLimits map[string]int `json:"toolOutputLimits,omitempty"` // 按工具名单独设置，-1 表示不限制
+	ToolSummarize    bool           `json:"toolSummarize"`              // 工具结果超出上限时用提取模型摘要（关闭时直接截断）
 }
 
 // LayoutConfig 界面布局配置
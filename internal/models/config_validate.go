@@ -0,0 +1,211 @@
+package models
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+)
+
+// Validate 校验配置结构：AI / MCP 配置缺少必填字段或取值非法时返回错误（多个错误合并返回）
+// 引用了不存在的 AI 配置等不影响运行的问题见 ReferenceWarnings
+func (c *AppConfig) Validate() error {
+	var errs []error
+
+	aiIDs := make(map[string]bool, len(c.AIConfigs))
+	for _, ai := range c.AIConfigs {
+		name := ai.Name
+		if name == "" {
+			name = ai.ID
+		}
+		switch {
+		case ai.ID == "":
+			errs = append(errs, fmt.Errorf("AI 配置 %q 缺少 ID", name))
+		case aiIDs[ai.ID]:
+			errs = append(errs, fmt.Errorf("AI 配置 ID 重复: %s", ai.ID))
+		}
+		aiIDs[ai.ID] = true
+
+		switch ai.Provider {
+		case AIProviderOpenAI, AIProviderGemini, AIProviderAnthropic, AIProviderMock:
+		case AIProviderVertexAI:
+			if ai.Project == "" {
+				errs = append(errs, fmt.Errorf("AI 配置 %q 缺少 Vertex AI 项目 ID", name))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("AI 配置 %q 的服务商不支持: %q", name, ai.Provider))
+		}
+		if ai.ModelName == "" {
+			errs = append(errs, fmt.Errorf("AI 配置 %q 缺少模型名称", name))
+		}
+		if ai.BaseURL != "" && !isHTTPURL(ai.BaseURL) {
+			errs = append(errs, fmt.Errorf("AI 配置 %q 的接口地址无效: %s", name, ai.BaseURL))
+		}
+		switch ai.ToolGuide {
+		case ToolGuideAuto, ToolGuideAlways, ToolGuideNever:
+		default:
+			errs = append(errs, fmt.Errorf("AI 配置 %q 的工具说明模式不支持: %q", name, ai.ToolGuide))
+		}
+		switch ai.APIStyle {
+		case "", APIStyleChat, APIStyleResponses, APIStyleAuto:
+		default:
+			errs = append(errs, fmt.Errorf("AI 配置 %q 的接口风格不支持: %q", name, ai.APIStyle))
+		}
+		if ai.Timeout < 0 || ai.MaxTokens < 0 {
+			errs = append(errs, fmt.Errorf("AI 配置 %q 的超时或最大 Token 不能为负数", name))
+		}
+		if err := ai.Proxy.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("AI 配置 %q 的%w", name, err))
+		}
+		if err := ai.HTTP.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("AI 配置 %q 的%w", name, err))
+		}
+		for header := range ai.ExtraHeaders {
+			if !isHeaderName(header) {
+				errs = append(errs, fmt.Errorf("AI 配置 %q 的自定义请求头名称无效: %q", name, header))
+			}
+		}
+	}
+
+	mcpIDs := make(map[string]bool, len(c.MCPServers))
+	for _, srv := range c.MCPServers {
+		name := srv.Name
+		if name == "" {
+			name = srv.ID
+		}
+		switch {
+		case srv.ID == "":
+			errs = append(errs, fmt.Errorf("MCP 服务器 %q 缺少 ID", name))
+		case mcpIDs[srv.ID]:
+			errs = append(errs, fmt.Errorf("MCP 服务器 ID 重复: %s", srv.ID))
+		}
+		mcpIDs[srv.ID] = true
+
+		// 未启用的服务器允许保存未填完的草稿
+		if !srv.Enabled {
+			continue
+		}
+		switch srv.TransportType {
+		case MCPTransportHTTP, MCPTransportSSE:
+			if !isHTTPURL(srv.Endpoint) {
+				errs = append(errs, fmt.Errorf("MCP 服务器 %q 的端点地址无效: %q", name, srv.Endpoint))
+			}
+		case MCPTransportCommand:
+			if srv.Command == "" {
+				errs = append(errs, fmt.Errorf("MCP 服务器 %q 缺少启动命令", name))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("MCP 服务器 %q 的传输类型不支持: %q", name, srv.TransportType))
+		}
+	}
+
+	if c.MeetingBudget < 0 {
+		errs = append(errs, errors.New("会议费用上限不能为负数"))
+	}
+	if c.MaxConcurrentMeetings < 0 {
+		errs = append(errs, errors.New("同时进行的会议数上限不能为负数"))
+	}
+	if b := c.ContextBudget; b.Memory < 0 || b.Speeches < 0 || b.ToolGuide < 0 || b.ToolOutput < 0 {
+		errs = append(errs, errors.New("上下文预算不能为负数"))
+	}
+	for name, limit := range c.ContextBudget.ToolOutputLimits {
+		if limit < -1 {
+			errs = append(errs, fmt.Errorf("工具 %s 的结果上限无效: %d", name, limit))
+		}
+	}
+	if c.OpenClaw.Enabled && (c.OpenClaw.Port <= 0 || c.OpenClaw.Port > 65535) {
+		errs = append(errs, fmt.Errorf("OpenClaw 端口无效: %d", c.OpenClaw.Port))
+	}
+	if err := c.Proxy.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("全局%w", err))
+	}
+	if !i18n.Supported(c.Language) {
+		errs = append(errs, fmt.Errorf("语言不支持: %q", c.Language))
+	}
+	switch c.TTS.Provider {
+	case "", TTSProviderSystem, TTSProviderOpenAI:
+	default:
+		errs = append(errs, fmt.Errorf("语音朗读引擎不支持: %q", c.TTS.Provider))
+	}
+	if c.TTS.Speed != 0 && (c.TTS.Speed < 0.5 || c.TTS.Speed > 2) {
+		errs = append(errs, fmt.Errorf("语音朗读语速应在 0.5-2 之间: %v", c.TTS.Speed))
+	}
+	return errors.Join(errs...)
+}
+
+// ReferenceWarnings 检查引用了不存在的 AI 配置的字段（运行时会降级为默认配置，不阻止保存）
+func (c *AppConfig) ReferenceWarnings() []string {
+	var warnings []string
+	check := func(field, id string) {
+		if id != "" && c.FindAIConfig(id) == nil {
+			warnings = append(warnings, fmt.Sprintf("%s 引用的 AI 配置不存在: %s", field, id))
+		}
+	}
+	check("默认 AI", c.DefaultAIID)
+	check("策略生成 AI", c.StrategyAIID)
+	check("小韭菜 AI", c.ModeratorAIID)
+	check("记忆 AI", c.Memory.AIConfigID)
+	check("语音朗读 AI", c.TTS.AIConfigID)
+	for _, task := range []AITask{AITaskExpert, AITaskModerator, AITaskSummary, AITaskMemory, AITaskExtraction, AITaskStrategy} {
+		check(fmt.Sprintf("任务路由 %s", task), c.AIRouting.Get(task))
+	}
+	for _, ai := range c.AIConfigs {
+		for _, id := range ai.FallbackIDs {
+			check(fmt.Sprintf("AI 配置 %q 的降级链", ai.Name), id)
+		}
+	}
+	return warnings
+}
+
+// validate 校验代理模式与自定义代理地址
+func (p ProxyConfig) validate() error {
+	switch p.Mode {
+	case ProxyModeGlobal, ProxyModeNone, ProxyModeSystem:
+	case ProxyModeCustom:
+		u, err := url.Parse(p.CustomURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("代理地址无效: %q", p.CustomURL)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("代理协议不支持: %q", u.Scheme)
+		}
+	default:
+		return fmt.Errorf("代理模式不支持: %q", p.Mode)
+	}
+	return nil
+}
+
+// validate 校验连接超时与 CA 证书
+func (h AIHTTPConfig) validate() error {
+	if h.ConnectTimeout < 0 || h.ReadTimeout < 0 || h.StreamIdleTimeout < 0 || h.IdleConnTimeout < 0 {
+		return errors.New("连接超时设置不能为负数")
+	}
+	if h.CACert != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(h.CACert)) {
+		return errors.New("CA 证书无效（需为 PEM 格式）")
+	}
+	return nil
+}
+
+// isHeaderName 判断是否为合法的 HTTP 请求头名称（RFC 7230 token）
+func isHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > 0x7e || r <= ' ' || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHTTPURL 判断是否为 http/https 地址
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
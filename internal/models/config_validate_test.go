@@ -0,0 +1,56 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateConfig 测试配置结构校验与引用检查
+func TestValidateConfig(t *testing.T) {
+	cfg := &AppConfig{
+		AIConfigs: []AIConfig{
+			{ID: "a", Name: "主力", Provider: AIProviderOpenAI, ModelName: "gpt-4o", BaseURL: "https://api.openai.com/v1",
+				Proxy: ProxyConfig{Mode: ProxyModeCustom, CustomURL: "socks5://127.0.0.1:1080"}},
+			{ID: "b", Name: "便宜", Provider: AIProviderGemini, ModelName: "gemini-flash", FallbackIDs: []string{"a"}},
+		},
+		MCPServers: []MCPServerConfig{
+			{ID: "m1", Name: "行情", TransportType: MCPTransportHTTP, Endpoint: "http://127.0.0.1:9000/mcp", Enabled: true},
+			{ID: "m2", Name: "草稿", Enabled: false},
+		},
+		DefaultAIID: "a",
+		AIRouting:   AIRoutingConfig{Summary: "b"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("valid config: %v", err)
+	}
+	if w := cfg.ReferenceWarnings(); len(w) != 0 {
+		t.Errorf("unexpected warnings: %v", w)
+	}
+
+	cfg.AIConfigs = append(cfg.AIConfigs, AIConfig{ID: "a", Name: "重复", Provider: "unknown", BaseURL: "api.example.com"})
+	cfg.MCPServers[0].Endpoint = ""
+	cfg.MaxConcurrentMeetings = -1
+	cfg.ContextBudget.Speeches = -1
+	cfg.TTS = TTSConfig{Provider: "azure", Speed: 3}
+	cfg.Language = "fr-FR"
+	cfg.Proxy = ProxyConfig{Mode: ProxyModeCustom, CustomURL: "ftp://127.0.0.1:21"}
+	cfg.AIConfigs[1].Proxy = ProxyConfig{Mode: ProxyModeCustom}
+	cfg.AIConfigs[1].ToolGuide = "sometimes"
+	cfg.AIConfigs[1].APIStyle = "graphql"
+	cfg.AIConfigs[0].HTTP = AIHTTPConfig{CACert: "not a pem", ReadTimeout: 60}
+	cfg.AIConfigs[0].ExtraHeaders = map[string]string{"X-Api-Version": "2024-05", "Bad Header": "x"}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("invalid config should fail")
+	}
+	for _, want := range []string{"ID 重复: a", "服务商不支持", "缺少模型名称", "接口地址无效", "端点地址无效", "会议数上限", "上下文预算", "语音朗读引擎不支持", "语速", "语言不支持", "全局代理协议不支持", "便宜\" 的代理地址无效", "主力\" 的CA 证书无效", "工具说明模式不支持", "接口风格不支持", "请求头名称无效: \"Bad Header\""} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q: %v", want, err)
+		}
+	}
+
+	cfg.AIRouting.Memory = "gone"
+	if w := cfg.ReferenceWarnings(); len(w) != 1 || !strings.Contains(w[0], "gone") {
+		t.Errorf("warnings: %v", w)
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+// PaperOrder 模拟盘委托
+type PaperOrder struct {
+	ID         string  `json:"id"`
+	Symbol     string  `json:"symbol"`
+	Name       string  `json:"name"`
+	Side       string  `json:"side"`             // buy/sell
+	Type       string  `json:"type"`             // market/limit
+	Price      float64 `json:"price"`            // 限价，市价单为 0
+	Shares     int64   `json:"shares"`           // 委托数量（股）
+	Status     string  `json:"status"`           // pending_approval/open/filled/cancelled/rejected
+	FillPrice  float64 `json:"fillPrice"`        // 成交价
+	Commission float64 `json:"commission"`       // 佣金+印花税
+	Source     string  `json:"source"`           // 下单来源：user 或专家名称
+	Reason     string  `json:"reason,omitempty"` // 下单理由或拒绝原因
+	CreatedAt  int64   `json:"createdAt"`
+	FilledAt   int64   `json:"filledAt,omitempty"`
+}
+
+// PaperPosition 模拟盘持仓
+type PaperPosition struct {
+	Symbol      string  `json:"symbol"`
+	Name        string  `json:"name"`
+	Shares      int64   `json:"shares"`      // 持仓数量
+	TodayShares int64   `json:"todayShares"` // 当日买入数量（T+1 不可卖）
+	TradeDate   string  `json:"tradeDate"`   // 最近买入日期 2006-01-02
+	CostPrice   float64 `json:"costPrice"`   // 成本价（含费用）
+	LastPrice   float64 `json:"lastPrice"`   // 最新价
+}
+
+// PaperEquityPoint 模拟盘每日权益
+type PaperEquityPoint struct {
+	Date   string  `json:"date"`
+	Equity float64 `json:"equity"`
+	Cash   float64 `json:"cash"`
+}
+
+// PaperAccount 模拟盘账户
+type PaperAccount struct {
+	InitialCash float64            `json:"initialCash"`
+	Cash        float64            `json:"cash"`
+	Positions   []PaperPosition    `json:"positions"`
+	Orders      []PaperOrder       `json:"orders"`
+	EquityCurve []PaperEquityPoint `json:"equityCurve"`
+	Equity      float64            `json:"equity"` // 现金+持仓市值（按最新价）
+	CreatedAt   int64              `json:"createdAt"`
+}
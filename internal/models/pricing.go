@@ -0,0 +1,110 @@
+package models
+
+import "strings"
+
+// CacheWriteTokensKey LLMResponse.CustomMetadata 中记录写入提示词缓存的输入 token 数
+// genai 的用量结构没有对应字段，由模型适配层（如 Anthropic 的 cache_creation_input_tokens）填写
+const CacheWriteTokensKey = "cache_write_input_tokens"
+
+// cacheWriteMultiplier 未配置缓存写入价格时按输入价格的倍数计（Anthropic 5 分钟缓存的写入溢价）
+const cacheWriteMultiplier = 1.25
+
+// ModelPricing 模型计费标准（美元 / 百万 tokens）
+type ModelPricing struct {
+	InputPerMillion           float64 `json:"inputPerMillion"`           // 输入价格
+	OutputPerMillion          float64 `json:"outputPerMillion"`          // 输出价格（含推理 tokens）
+	CachedInputPerMillion     float64 `json:"cachedInputPerMillion"`     // 命中提示词缓存的输入价格（0 则按输入价格计）
+	CacheWriteInputPerMillion float64 `json:"cacheWriteInputPerMillion"` // 写入提示词缓存的输入价格（0 则按输入价格的 1.25 倍计）
+}
+
+// IsZero 是否未设置价格
+func (p ModelPricing) IsZero() bool {
+	return p.InputPerMillion == 0 && p.OutputPerMillion == 0
+}
+
+// Cost 按 token 数计算费用（美元）
+func (p ModelPricing) Cost(inputTokens, outputTokens int64) float64 {
+	return p.CostWithCache(inputTokens, 0, outputTokens)
+}
+
+// CostWithCache 计算含提示词缓存的费用，cachedTokens 是 inputTokens 中命中缓存的部分
+func (p ModelPricing) CostWithCache(inputTokens, cachedTokens, outputTokens int64) float64 {
+	return p.CostWithCacheWrite(inputTokens, cachedTokens, 0, outputTokens)
+}
+
+// CostWithCacheWrite 在 CostWithCache 基础上单独计算写入缓存的部分
+// cachedTokens 与 cacheWriteTokens 都包含在 inputTokens 中
+func (p ModelPricing) CostWithCacheWrite(inputTokens, cachedTokens, cacheWriteTokens, outputTokens int64) float64 {
+	cachedTokens = min(max(cachedTokens, 0), inputTokens)
+	cacheWriteTokens = min(max(cacheWriteTokens, 0), inputTokens-cachedTokens)
+	cachedPrice := p.CachedInputPerMillion
+	if cachedPrice == 0 {
+		cachedPrice = p.InputPerMillion
+	}
+	writePrice := p.CacheWriteInputPerMillion
+	if writePrice == 0 {
+		writePrice = p.InputPerMillion * cacheWriteMultiplier
+	}
+	input := float64(inputTokens-cachedTokens-cacheWriteTokens)*p.InputPerMillion +
+		float64(cachedTokens)*cachedPrice + float64(cacheWriteTokens)*writePrice
+	return (input + float64(outputTokens)*p.OutputPerMillion) / 1e6
+}
+
+// defaultModelPricing 常见模型的公开价格（按模型名前缀匹配，越长越优先）
+// 仅用于用户未在 AIConfig 中配置价格时的估算
+var defaultModelPricing = map[string]ModelPricing{
+	"gpt-4o-mini":       {InputPerMillion: 0.15, OutputPerMillion: 0.6, CachedInputPerMillion: 0.075},
+	"gpt-4o":            {InputPerMillion: 2.5, OutputPerMillion: 10, CachedInputPerMillion: 1.25},
+	"gpt-4.1-nano":      {InputPerMillion: 0.1, OutputPerMillion: 0.4, CachedInputPerMillion: 0.025},
+	"gpt-4.1-mini":      {InputPerMillion: 0.4, OutputPerMillion: 1.6, CachedInputPerMillion: 0.1},
+	"gpt-4.1":           {InputPerMillion: 2, OutputPerMillion: 8, CachedInputPerMillion: 0.5},
+	"gpt-5-nano":        {InputPerMillion: 0.05, OutputPerMillion: 0.4, CachedInputPerMillion: 0.005},
+	"gpt-5-mini":        {InputPerMillion: 0.25, OutputPerMillion: 2, CachedInputPerMillion: 0.025},
+	"gpt-5":             {InputPerMillion: 1.25, OutputPerMillion: 10, CachedInputPerMillion: 0.125},
+	"o4-mini":           {InputPerMillion: 1.1, OutputPerMillion: 4.4, CachedInputPerMillion: 0.275},
+	"o3-mini":           {InputPerMillion: 1.1, OutputPerMillion: 4.4, CachedInputPerMillion: 0.55},
+	"o3":                {InputPerMillion: 2, OutputPerMillion: 8, CachedInputPerMillion: 0.5},
+	"o1":                {InputPerMillion: 15, OutputPerMillion: 60, CachedInputPerMillion: 7.5},
+	"claude-opus":       {InputPerMillion: 15, OutputPerMillion: 75, CachedInputPerMillion: 1.5},
+	"claude-sonnet":     {InputPerMillion: 3, OutputPerMillion: 15, CachedInputPerMillion: 0.3},
+	"claude-3-5-sonnet": {InputPerMillion: 3, OutputPerMillion: 15, CachedInputPerMillion: 0.3},
+	"claude-3-7-sonnet": {InputPerMillion: 3, OutputPerMillion: 15, CachedInputPerMillion: 0.3},
+	"claude-haiku":      {InputPerMillion: 1, OutputPerMillion: 5, CachedInputPerMillion: 0.1},
+	"claude-3-5-haiku":  {InputPerMillion: 0.8, OutputPerMillion: 4, CachedInputPerMillion: 0.08},
+	"gemini-2.5-pro":    {InputPerMillion: 1.25, OutputPerMillion: 10, CachedInputPerMillion: 0.31},
+	"gemini-2.5-flash":  {InputPerMillion: 0.3, OutputPerMillion: 2.5, CachedInputPerMillion: 0.075},
+	"gemini-2.0-flash":  {InputPerMillion: 0.1, OutputPerMillion: 0.4, CachedInputPerMillion: 0.025},
+	"deepseek-chat":     {InputPerMillion: 0.27, OutputPerMillion: 1.1, CachedInputPerMillion: 0.07},
+	"deepseek-reasoner": {InputPerMillion: 0.55, OutputPerMillion: 2.19, CachedInputPerMillion: 0.14},
+}
+
+// LookupModelPricing 按模型名查找内置价格表
+func LookupModelPricing(modelName string) (ModelPricing, bool) {
+	name := strings.ToLower(modelName)
+	// 兼容 openrouter 等 vendor/model 形式
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	var (
+		best    ModelPricing
+		bestLen int
+	)
+	for prefix, pricing := range defaultModelPricing {
+		if strings.HasPrefix(name, prefix) && len(prefix) > bestLen {
+			best, bestLen = pricing, len(prefix)
+		}
+	}
+	return best, bestLen > 0
+}
+
+// ResolvePricing 获取 AI 配置的计费标准（优先用户配置，否则查内置价格表）
+func (c *AIConfig) ResolvePricing() ModelPricing {
+	if c == nil {
+		return ModelPricing{}
+	}
+	if !c.Pricing.IsZero() {
+		return c.Pricing
+	}
+	pricing, _ := LookupModelPricing(c.ModelName)
+	return pricing
+}
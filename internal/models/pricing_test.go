@@ -0,0 +1,61 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+// TestLookupModelPricing 测试内置价格表前缀匹配
+func TestLookupModelPricing(t *testing.T) {
+	p, ok := LookupModelPricing("gpt-4o-mini-2024-07-18")
+	if !ok || p.InputPerMillion != 0.15 {
+		t.Errorf("gpt-4o-mini 应匹配最长前缀, got %+v", p)
+	}
+	if _, ok := LookupModelPricing("openai/gpt-4o"); !ok {
+		t.Error("应兼容 vendor/model 形式")
+	}
+	if _, ok := LookupModelPricing("my-local-model"); ok {
+		t.Error("未知模型不应命中")
+	}
+}
+
+// TestResolvePricing 测试用户配置优先
+func TestResolvePricing(t *testing.T) {
+	cfg := &AIConfig{ModelName: "gpt-4o", Pricing: ModelPricing{InputPerMillion: 1, OutputPerMillion: 2}}
+	cost := cfg.ResolvePricing().Cost(1_000_000, 500_000)
+	if math.Abs(cost-2) > 1e-9 {
+		t.Errorf("cost = %v, want 2", cost)
+	}
+}
+
+// TestCostWithCache 测试缓存命中部分按缓存价格计费
+func TestCostWithCache(t *testing.T) {
+	p := ModelPricing{InputPerMillion: 3, OutputPerMillion: 15, CachedInputPerMillion: 0.3}
+	cost := p.CostWithCache(1_000_000, 900_000, 0)
+	if math.Abs(cost-0.57) > 1e-9 {
+		t.Errorf("cost = %v, want 0.57", cost)
+	}
+	// 未配置缓存价格时按普通输入价格计
+	p.CachedInputPerMillion = 0
+	if cost := p.CostWithCache(1_000_000, 900_000, 0); math.Abs(cost-3) > 1e-9 {
+		t.Errorf("cost = %v, want 3", cost)
+	}
+}
+
+// TestCostWithCacheWrite 测试写入缓存的部分按溢价计费
+func TestCostWithCacheWrite(t *testing.T) {
+	p := ModelPricing{InputPerMillion: 3, OutputPerMillion: 15, CachedInputPerMillion: 0.3}
+	// 10 万普通输入 + 10 万缓存命中 + 80 万写入缓存（默认 1.25 倍）
+	cost := p.CostWithCacheWrite(1_000_000, 100_000, 800_000, 0)
+	if want := 0.3 + 0.03 + 3; math.Abs(cost-want) > 1e-9 {
+		t.Errorf("cost = %v, want %v", cost, want)
+	}
+	p.CacheWriteInputPerMillion = 6
+	if cost := p.CostWithCacheWrite(1_000_000, 0, 1_000_000, 0); math.Abs(cost-6) > 1e-9 {
+		t.Errorf("cost = %v, want 6", cost)
+	}
+	// 写入数超出剩余输入时截断
+	if cost := p.CostWithCacheWrite(100, 100, 100, 0); math.Abs(cost-0.00003) > 1e-12 {
+		t.Errorf("cost = %v, want 0.00003", cost)
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import "strings"
+
+// ModelPreset 预设模型信息
+type ModelPreset struct {
+	Name          string `json:"name"`
+	ContextWindow int    `json:"contextWindow"` // 上下文窗口（tokens）
+	Reasoning     bool   `json:"reasoning"`     // 是否通过 reasoning_content 输出思考过程
+}
+
+// ProviderPreset 服务商预设（OpenAI 兼容接口的国内厂商等）
+type ProviderPreset struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Provider   AIProvider    `json:"provider"`   // 实际使用的协议
+	BaseURL    string        `json:"baseUrl"`    // 默认接口地址
+	ListModels bool          `json:"listModels"` // 是否支持 /models 在线查询
+	Models     []ModelPreset `json:"models"`     // 已知模型
+}
+
+// providerPresets 内置服务商预设
+var providerPresets = []ProviderPreset{
+	{
+		ID: "deepseek", Name: "DeepSeek", Provider: AIProviderOpenAI,
+		BaseURL: "https://api.deepseek.com/v1", ListModels: true,
+		Models: []ModelPreset{
+			{Name: "deepseek-chat", ContextWindow: 131072},
+			{Name: "deepseek-reasoner", ContextWindow: 131072, Reasoning: true},
+		},
+	},
+	{
+		ID: "qwen", Name: "通义千问 (DashScope)", Provider: AIProviderOpenAI,
+		BaseURL: "https://dashscope.aliyuncs.com/compatible-mode/v1", ListModels: true,
+		Models: []ModelPreset{
+			{Name: "qwen3-max", ContextWindow: 262144},
+			{Name: "qwen-max", ContextWindow: 32768},
+			{Name: "qwen-plus", ContextWindow: 131072, Reasoning: true},
+			{Name: "qwen-turbo", ContextWindow: 1000000, Reasoning: true},
+			{Name: "qwq-plus", ContextWindow: 131072, Reasoning: true},
+		},
+	},
+	{
+		ID: "glm", Name: "智谱 GLM", Provider: AIProviderOpenAI,
+		BaseURL: "https://open.bigmodel.cn/api/paas/v4",
+		Models: []ModelPreset{
+			{Name: "glm-4.6", ContextWindow: 204800, Reasoning: true},
+			{Name: "glm-4.5", ContextWindow: 131072, Reasoning: true},
+			{Name: "glm-4.5-air", ContextWindow: 131072, Reasoning: true},
+			{Name: "glm-4-flash", ContextWindow: 131072},
+		},
+	},
+	{
+		ID: "moonshot", Name: "Moonshot (Kimi)", Provider: AIProviderOpenAI,
+		BaseURL: "https://api.moonshot.cn/v1", ListModels: true,
+		Models: []ModelPreset{
+			{Name: "kimi-k2-0905-preview", ContextWindow: 262144},
+			{Name: "kimi-k2-turbo-preview", ContextWindow: 262144},
+			{Name: "kimi-thinking-preview", ContextWindow: 131072, Reasoning: true},
+			{Name: "moonshot-v1-128k", ContextWindow: 131072},
+			{Name: "moonshot-v1-32k", ContextWindow: 32768},
+			{Name: "moonshot-v1-8k", ContextWindow: 8192},
+		},
+	},
+}
+
+// GetProviderPresets 获取内置服务商预设列表
+func GetProviderPresets() []ProviderPreset {
+	presets := make([]ProviderPreset, len(providerPresets))
+	copy(presets, providerPresets)
+	return presets
+}
+
+// FindProviderPreset 按 ID 查找服务商预设
+func FindProviderPreset(id string) (ProviderPreset, bool) {
+	for _, p := range providerPresets {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return ProviderPreset{}, false
+}
+
+// FindProviderPresetByBaseURL 按接口地址的主机名匹配服务商预设
+func FindProviderPresetByBaseURL(baseURL string) (ProviderPreset, bool) {
+	host := presetHost(baseURL)
+	if host == "" {
+		return ProviderPreset{}, false
+	}
+	for _, p := range providerPresets {
+		if presetHost(p.BaseURL) == host {
+			return p, true
+		}
+	}
+	return ProviderPreset{}, false
+}
+
+// presetHost 提取接口地址的主机名
+func presetHost(baseURL string) string {
+	host := strings.ToLower(strings.TrimSpace(baseURL))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	if idx := strings.Index(host, "/"); idx >= 0 {
+		host = host[:idx]
+	}
+	return host
+}
+
+// LookupModelPreset 按模型名查找预设模型信息（兼容 vendor/model 形式）
+func LookupModelPreset(modelName string) (ModelPreset, bool) {
+	name := strings.ToLower(modelName)
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	for _, p := range providerPresets {
+		for _, m := range p.Models {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return ModelPreset{}, false
+}
@@ -8,27 +8,92 @@ type StockPosition struct {
 
 // StockSession 股票会话（每个自选股独立）
 type StockSession struct {
-	ID        string         `json:"id"`
-	StockCode string         `json:"stockCode"` // 股票代码
-	StockName string         `json:"stockName"` // 股票名称
-	Messages  []ChatMessage  `json:"messages"`  // 讨论历史
-	Position  *StockPosition `json:"position"`  // 持仓信息
-	CreatedAt int64          `json:"createdAt"`
-	UpdatedAt int64          `json:"updatedAt"`
+	ID            string          `json:"id"`
+	StockCode     string          `json:"stockCode"`               // 股票代码
+	StockName     string          `json:"stockName"`               // 股票名称
+	Messages      []ChatMessage   `json:"messages"`                // 讨论历史
+	Position      *StockPosition  `json:"position"`                // 持仓信息
+	LastAdvice    *MeetingAdvice  `json:"lastAdvice,omitempty"`    // 最近一次会议的操作建议
+	LastConsensus *ConsensusScore `json:"lastConsensus,omitempty"` // 最近一次会议的多空共识分
+	TradePlan     *TradePlan      `json:"tradePlan,omitempty"`     // 由会议结论生成的交易计划
+	Briefing      *Briefing       `json:"briefing,omitempty"`      // 最近一次盘前早报
+	CreatedAt     int64           `json:"createdAt"`
+	UpdatedAt     int64           `json:"updatedAt"`
+}
+
+// MeetingAdvice 会议结论中的持仓操作建议（用于和用户实际持仓变动对比）
+type MeetingAdvice struct {
+	Action     string `json:"action"`               // 建议操作: reduce/add/hold
+	Summary    string `json:"summary"`              // 结论摘要
+	Shares     int64  `json:"shares"`               // 给出建议时的持仓数量
+	CreatedAt  int64  `json:"createdAt"`            // 建议时间
+	Diverged   bool   `json:"diverged"`             // 实际操作是否与建议背离
+	DivergedAt int64  `json:"divergedAt,omitempty"` // 检测到背离的时间
+}
+
+// Briefing 盘前早报
+type Briefing struct {
+	Date      string `json:"date"` // 交易日 2006-01-02
+	Content   string `json:"content"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// TradePlan 交易计划（买入区间、止损、目标价），由后台监控价格触发提醒
+type TradePlan struct {
+	EntryLow   float64        `json:"entryLow,omitempty"`  // 买入区间下沿
+	EntryHigh  float64        `json:"entryHigh,omitempty"` // 买入区间上沿
+	StopLoss   float64        `json:"stopLoss,omitempty"`  // 止损价
+	Targets    []float64      `json:"targets,omitempty"`   // 目标价（升序）
+	AutoReview bool           `json:"autoReview"`          // 触发止损/目标价时自动召开复盘会议
+	Summary    string         `json:"summary"`             // 来源结论摘要
+	Status     string         `json:"status"`              // active/stopped/done/cancelled
+	Hits       []TradePlanHit `json:"hits,omitempty"`      // 已触发的价位
+	CreatedAt  int64          `json:"createdAt"`
+}
+
+// TradePlanHit 交易计划价位触发记录
+type TradePlanHit struct {
+	Kind  string  `json:"kind"`  // entry/stop/target
+	Level float64 `json:"level"` // 计划价位
+	Price float64 `json:"price"` // 触发时现价
+	At    int64   `json:"at"`
 }
 
 // ChatMessage 聊天消息
 type ChatMessage struct {
-	ID        string   `json:"id"`
-	AgentID   string   `json:"agentId"`
-	AgentName string   `json:"agentName"`
-	Role      string   `json:"role"`
-	Content   string   `json:"content"`
-	Timestamp int64    `json:"timestamp"`
-	ReplyTo   string   `json:"replyTo,omitempty"`   // 引用的消息ID
-	Mentions  []string `json:"mentions,omitempty"`  // @的成员ID列表
-	Round     int      `json:"round,omitempty"`     // 讨论轮次
-	MsgType   string   `json:"msgType,omitempty"`   // 消息类型: opening/opinion/summary
-	Error       string   `json:"error,omitempty"`       // 失败时的错误信息
-	MeetingMode string   `json:"meetingMode,omitempty"` // smart=串行, direct=独立
+	ID            string           `json:"id"`
+	AgentID       string           `json:"agentId"`
+	AgentName     string           `json:"agentName"`
+	Role          string           `json:"role"`
+	Content       string           `json:"content"`
+	Timestamp     int64            `json:"timestamp"`
+	ReplyTo       string           `json:"replyTo,omitempty"`       // 引用的消息ID
+	Mentions      []string         `json:"mentions,omitempty"`      // @的成员ID列表
+	Round         int              `json:"round,omitempty"`         // 讨论轮次
+	MsgType       string           `json:"msgType,omitempty"`       // 消息类型: opening/opinion/summary
+	Error         string           `json:"error,omitempty"`         // 失败时的错误信息
+	ErrorCode     string           `json:"errorCode,omitempty"`     // 失败原因分类: rate_limited/auth_failed/context_too_long/tool_failed/timeout
+	MeetingMode   string           `json:"meetingMode,omitempty"`   // smart=串行, direct=独立
+	AnsweredBy    string           `json:"answeredBy,omitempty"`    // 发生降级时实际作答的模型名
+	ToolCalls     []ToolCallRecord `json:"toolCalls,omitempty"`     // 发言期间的工具调用（数据来源）
+	MeetingID     string           `json:"meetingId,omitempty"`     // 所属会议 ID
+	Variant       string           `json:"variant,omitempty"`       // A/B 对比模式下的变体标签
+	ABTestID      string           `json:"abTestId,omitempty"`      // 所属 A/B 对比记录 ID
+	DurationMs    int64            `json:"durationMs,omitempty"`    // 专家发言耗时(毫秒)
+	Retries       int              `json:"retries,omitempty"`       // 重试次数
+	ToolCallCount int              `json:"toolCallCount,omitempty"` // 工具调用次数
+	ModelName     string           `json:"modelName,omitempty"`     // 使用的模型名
+	Report        *SummaryReport   `json:"report,omitempty"`        // 结构化总结（仅 summary）
+	Consensus     *ConsensusScore  `json:"consensus,omitempty"`     // 多空共识分（仅 summary）
+}
+
+// ToolCallRecord 工具调用记录
+type ToolCallRecord struct {
+	Tool     string `json:"tool"`             // 工具名称
+	Args     string `json:"args,omitempty"`   // 调用参数 (JSON)
+	Result   string `json:"result,omitempty"` // 调用结果 (JSON，已截断)
+	Duration int64  `json:"duration"`         // 耗时(毫秒)
+	AgentID  string `json:"agentId"`          // 调用的专家 ID
+	Citation int    `json:"citation"`         // 引用编号，对应回答中的 [n]
+	Cited    bool   `json:"cited,omitempty"`  // 回答中是否引用了该结果
 }
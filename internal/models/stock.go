@@ -15,6 +15,42 @@ type Stock struct {
 	High          float64 `json:"high"`
 	Low           float64 `json:"low"`
 	PreClose      float64 `json:"preClose"`
+	TurnoverRate  float64 `json:"turnoverRate,omitempty"` // 换手率(%)
+	PE            float64 `json:"pe,omitempty"`           // 市盈率(动)，亏损为负
+	PB            float64 `json:"pb,omitempty"`           // 市净率
+	FloatShares   int64   `json:"floatShares,omitempty"`  // 流通股本(股)
+	TotalShares   int64   `json:"totalShares,omitempty"`  // 总股本(股)
+}
+
+// StockSnapshot 个股快照：实时行情与当日、近期关键统计，用于专家提示词开头的背景信息
+// 区间数据获取失败时对应字段为 0
+type StockSnapshot struct {
+	Stock
+	Amplitude float64 `json:"amplitude"` // 当日振幅(%)
+	High5D    float64 `json:"high5d"`    // 近5日最高
+	Low5D     float64 `json:"low5d"`     // 近5日最低
+	Change5D  float64 `json:"change5d"`  // 近5日涨跌幅(%)
+	High52W   float64 `json:"high52w"`   // 52周最高
+	Low52W    float64 `json:"low52w"`    // 52周最低
+}
+
+// WatchlistGroup 自选股分组
+type WatchlistGroup struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Symbols []string `json:"symbols"` // 组内股票代码（按拖拽排序）
+}
+
+// WatchlistGroupAll 「全部」分组 ID，对应完整自选股列表
+const WatchlistGroupAll = ""
+
+// DefaultWatchlistGroups 默认自选股分组
+func DefaultWatchlistGroups() []WatchlistGroup {
+	return []WatchlistGroup{
+		{ID: "holding", Name: "持仓", Symbols: []string{}},
+		{ID: "watch", Name: "观察", Symbols: []string{}},
+		{ID: "limitup", Name: "打板", Symbols: []string{}},
+	}
 }
 
 // KLineData K线数据
@@ -27,12 +63,24 @@ type KLineData struct {
 	Volume int64   `json:"volume"`
 	Amount float64 `json:"amount,omitempty"`
 	Avg    float64 `json:"avg,omitempty"` // 分时均价线
+	// 分时当日累计资金净流入（元）
+	NetInflow float64 `json:"netInflow,omitempty"`
 	// 均线数据
 	MA5  float64 `json:"ma5,omitempty"`
 	MA10 float64 `json:"ma10,omitempty"`
 	MA20 float64 `json:"ma20,omitempty"`
 }
 
+// MoneyFlowPoint 分时资金流向：按分钟涨跌将成交额划为流入或流出
+type MoneyFlowPoint struct {
+	Time    string  `json:"time"`
+	VWAP    float64 `json:"vwap"`    // 截至该分钟的当日均价
+	Inflow  float64 `json:"inflow"`  // 流入成交额（元）
+	Outflow float64 `json:"outflow"` // 流出成交额（元）
+	Net     float64 `json:"net"`     // 净流入 = 流入 - 流出
+	CumNet  float64 `json:"cumNet"`  // 当日累计净流入
+}
+
 // OrderBookItem 盘口单项
 type OrderBookItem struct {
 	Price   float64 `json:"price"`
@@ -43,8 +91,34 @@ type OrderBookItem struct {
 
 // OrderBook 盘口数据
 type OrderBook struct {
-	Bids []OrderBookItem `json:"bids"`
-	Asks []OrderBookItem `json:"asks"`
+	Bids    []OrderBookItem   `json:"bids"`
+	Asks    []OrderBookItem   `json:"asks"`
+	Metrics *OrderBookMetrics `json:"metrics,omitempty"` // 衍生指标，仅推送与工具查询时计算
+}
+
+// OrderBookMetrics 盘口衍生指标
+type OrderBookMetrics struct {
+	BidVolume int64            `json:"bidVolume"`           // 买盘挂单合计（手）
+	AskVolume int64            `json:"askVolume"`           // 卖盘挂单合计（手）
+	Imbalance float64          `json:"imbalance"`           // 委比：(买-卖)/(买+卖)，范围 -1~1
+	LargeBids []int            `json:"largeBids,omitempty"` // 大单所在的买盘档位下标
+	LargeAsks []int            `json:"largeAsks,omitempty"` // 大单所在的卖盘档位下标
+	Withdrawn []WithdrawnOrder `json:"withdrawn,omitempty"` // 与上一快照相比疑似撤单的档位
+}
+
+// WithdrawnOrder 疑似撤单：未被成交触及的档位挂单明显减少
+type WithdrawnOrder struct {
+	Side  string  `json:"side"` // bid / ask
+	Price float64 `json:"price"`
+	Size  int64   `json:"size"` // 减少的挂单量（手）
+}
+
+// TickTrade 逐笔成交
+type TickTrade struct {
+	Time      string  `json:"time"`      // 成交时间 HH:MM:SS
+	Price     float64 `json:"price"`     // 成交价
+	Volume    int64   `json:"volume"`    // 成交量(股)
+	Direction string  `json:"direction"` // 主动方向: buy(外盘) / sell(内盘) / neutral
 }
 
 // MarketIndex 大盘指数数据
@@ -95,3 +169,52 @@ type LongHuBangDetail struct {
 	NetAmt      float64 `json:"netAmt"`      // 净买入(元)
 	Direction   string  `json:"direction"`   // 方向: buy/sell
 }
+
+// LimitBoardItem 涨停/跌停/炸板池单条数据
+type LimitBoardItem struct {
+	Code          string  `json:"code"`          // 股票代码(含市场前缀，如sh600000)
+	Name          string  `json:"name"`          // 股票名称
+	Price         float64 `json:"price"`         // 最新价
+	ChangePercent float64 `json:"changePercent"` // 涨跌幅(%)
+	Amount        float64 `json:"amount"`        // 成交额(元)
+	TurnoverRate  float64 `json:"turnoverRate"`  // 换手率(%)
+	FloatCap      float64 `json:"floatCap"`      // 流通市值(元)
+	SealFund      float64 `json:"sealFund"`      // 封单资金(元)，炸板池为0
+	FirstSealTime string  `json:"firstSealTime"` // 首次封板时间 HH:MM:SS
+	LastSealTime  string  `json:"lastSealTime"`  // 最后封板时间 HH:MM:SS
+	OpenTimes     int     `json:"openTimes"`     // 炸板/开板次数
+	Boards        int     `json:"boards"`        // 连板数(跌停池为连续跌停天数)
+	Industry      string  `json:"industry"`      // 所属行业
+}
+
+// LimitBoard 涨跌停板监控数据
+type LimitBoard struct {
+	Date      string           `json:"date"`      // 交易日期 YYYY-MM-DD
+	LimitUp   []LimitBoardItem `json:"limitUp"`   // 涨停池（按连板数降序）
+	LimitDown []LimitBoardItem `json:"limitDown"` // 跌停池
+	Broken    []LimitBoardItem `json:"broken"`    // 炸板池（曾涨停后打开）
+	UpdatedAt int64            `json:"updatedAt"` // 更新时间(毫秒)
+}
+
+// SectorPerf 行业板块表现
+type SectorPerf struct {
+	Code          string  `json:"code"`          // 板块代码，如 BK0475
+	Name          string  `json:"name"`          // 板块名称
+	ChangePercent float64 `json:"changePercent"` // 涨跌幅(%)
+	Amount        float64 `json:"amount"`        // 成交额(元)
+	NetInflow     float64 `json:"netInflow"`     // 主力净流入(元)
+	UpCount       int     `json:"upCount"`       // 上涨家数
+	DownCount     int     `json:"downCount"`     // 下跌家数
+	LeaderCode    string  `json:"leaderCode"`    // 领涨股代码(含市场前缀)
+	LeaderName    string  `json:"leaderName"`    // 领涨股名称
+	LeaderChange  float64 `json:"leaderChange"`  // 领涨股涨跌幅(%)
+}
+
+// MarketBreadth 市场宽度与行业板块快照
+type MarketBreadth struct {
+	Up        int          `json:"up"`        // 上涨家数（沪深合计）
+	Down      int          `json:"down"`      // 下跌家数
+	Flat      int          `json:"flat"`      // 平盘家数
+	Sectors   []SectorPerf `json:"sectors"`   // 行业板块，按涨跌幅降序
+	UpdatedAt int64        `json:"updatedAt"` // 更新时间(毫秒)
+}
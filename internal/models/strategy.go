@@ -12,6 +12,21 @@ type StrategyAgent struct {
 	MCPServers  []string `json:"mcpServers"`
 	Enabled     bool     `json:"enabled"`
 	AIConfigID  string   `json:"aiConfigId"` // 可选，空则用默认AI
+
+	UnifiedTools bool `json:"unifiedTools,omitempty"` // 将行情类工具合并为一个 market_data 工具
+
+	Variant *AgentVariant `json:"variant,omitempty"` // A/B 对比的 B 变体，为空表示未配置
+
+	// 来自专家预设库时记录预设ID、版本与内容指纹，用于升级和识别用户修改
+	PresetID      string `json:"presetId,omitempty"`
+	PresetVersion int    `json:"presetVersion,omitempty"`
+	PresetHash    string `json:"presetHash,omitempty"`
+}
+
+// AgentVariant 专家的 A/B 对比变体，留空的字段沿用专家本身的配置
+type AgentVariant struct {
+	Instruction string `json:"instruction,omitempty"`
+	AIConfigID  string `json:"aiConfigId,omitempty"`
 }
 
 // Strategy 策略配置
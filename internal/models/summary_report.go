@@ -0,0 +1,159 @@
+package models
+
+import "strings"
+
+// 会议总结的分节标题，小韭菜按此顺序输出
+const (
+	SummaryConclusion = "结论"
+	SummaryDivergence = "多空分歧"
+	SummaryKeyData    = "关键数据"
+	SummaryRisks      = "风险提示"
+	SummaryActions    = "操作建议"
+)
+
+// SummarySectionTitles 总结分节标题（按输出顺序）
+var SummarySectionTitles = []string{SummaryConclusion, SummaryDivergence, SummaryKeyData, SummaryRisks, SummaryActions}
+
+// SummaryReport 结构化的会议总结，前端据此渲染卡片，导出时生成报告
+type SummaryReport struct {
+	Conclusion string `json:"conclusion"`           // 结论
+	Divergence string `json:"divergence,omitempty"` // 多空分歧
+	KeyData    string `json:"keyData,omitempty"`    // 关键数据
+	Risks      string `json:"risks,omitempty"`      // 风险提示
+	Actions    string `json:"actions,omitempty"`    // 操作建议
+}
+
+// SummarySection 总结中的一节
+type SummarySection struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// Sections 按固定顺序返回非空分节
+func (r *SummaryReport) Sections() []SummarySection {
+	var sections []SummarySection
+	for _, title := range SummarySectionTitles {
+		if content := *r.field(title); content != "" {
+			sections = append(sections, SummarySection{Title: title, Content: content})
+		}
+	}
+	return sections
+}
+
+// field 标题对应的字段
+func (r *SummaryReport) field(title string) *string {
+	switch title {
+	case SummaryConclusion:
+		return &r.Conclusion
+	case SummaryDivergence:
+		return &r.Divergence
+	case SummaryKeyData:
+		return &r.KeyData
+	case SummaryRisks:
+		return &r.Risks
+	default:
+		return &r.Actions
+	}
+}
+
+// ParseSummaryReport 将小韭菜的 Markdown 总结解析为结构化分节
+// 兼容「### 结论」「**结论**：」「1. 核心结论」等写法；缺少结论或识别出的分节不足两节时返回 nil，前端按原文展示
+func ParseSummaryReport(content string) *SummaryReport {
+	var report SummaryReport
+	var current *string
+	found := 0
+	for _, line := range strings.Split(content, "\n") {
+		if title, rest, ok := parseSummaryHeading(line); ok {
+			current = report.field(title)
+			if *current == "" {
+				found++
+			}
+			line = rest
+			if line == "" {
+				continue
+			}
+		}
+		if current == nil {
+			continue
+		}
+		if *current == "" {
+			*current = line
+		} else {
+			*current += "\n" + line
+		}
+	}
+	if found < 2 {
+		return nil
+	}
+	for _, title := range SummarySectionTitles {
+		field := report.field(title)
+		*field = strings.TrimSpace(*field)
+	}
+	if report.Conclusion == "" {
+		return nil
+	}
+	return &report
+}
+
+// parseSummaryHeading 识别分节标题行，返回标题与同一行中标题后的内容
+func parseSummaryHeading(line string) (title, rest string, ok bool) {
+	s := strings.TrimSpace(line)
+	heading := strings.HasPrefix(s, "#")
+	s = trimSectionNumber(strings.TrimLeft(s, "#*-> \t"))
+	bracket := strings.HasPrefix(s, "【")
+	s = strings.TrimPrefix(s, "【")
+	s = strings.TrimPrefix(s, "核心")
+	s = strings.TrimPrefix(s, "综合")
+	for _, t := range SummarySectionTitles {
+		if !strings.HasPrefix(s, t) {
+			continue
+		}
+		after := strings.TrimLeft(s[len(t):], "*")
+		if bracket {
+			if !strings.HasPrefix(after, "】") {
+				continue
+			}
+			return t, trimHeadingRest(strings.TrimPrefix(after, "】")), true
+		}
+		after = strings.TrimSpace(after)
+		switch {
+		case after == "":
+			return t, "", true
+		case strings.HasPrefix(after, "："), strings.HasPrefix(after, ":"):
+			return t, trimHeadingRest(after), true
+		case heading:
+			return t, "", true
+		}
+	}
+	return "", "", false
+}
+
+// trimHeadingRest 去掉标题后的冒号与加粗标记
+func trimHeadingRest(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "：")
+	s = strings.TrimPrefix(s, ":")
+	return strings.TrimSpace(strings.TrimLeft(s, "*"))
+}
+
+// trimSectionNumber 去掉「1.」「2、」「一、」等编号前缀
+func trimSectionNumber(s string) string {
+	for _, n := range []string{"一、", "二、", "三、", "四、", "五、"} {
+		if strings.HasPrefix(s, n) {
+			return strings.TrimLeft(s[len(n):], "* \t")
+		}
+	}
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return s
+	}
+	for _, sep := range []string{".", "、", ")", "）"} {
+		if strings.HasPrefix(s[i:], sep) {
+			return strings.TrimLeft(s[i+len(sep):], "* \t")
+		}
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package models
+
+import "testing"
+
+// TestParseSummaryReport 测试按分节标题解析会议总结
+func TestParseSummaryReport(t *testing.T) {
+	content := `### 结论
+短线观望，中线可分批建仓。
+
+### 多空分歧
+- 技术面：跌破 20 日线
+- 基本面：业绩超预期
+
+### 关键数据
+PE 18 倍，近 5 日主力净流入 2.3 亿
+
+### 风险提示
+大盘系统性回调
+
+### 操作建议
+买入区间 10.2-10.5，止损 9.8，目标 12`
+	r := ParseSummaryReport(content)
+	if r == nil {
+		t.Fatal("report should be parsed")
+	}
+	if r.Conclusion != "短线观望，中线可分批建仓。" {
+		t.Errorf("conclusion: %q", r.Conclusion)
+	}
+	if r.Divergence != "- 技术面：跌破 20 日线\n- 基本面：业绩超预期" {
+		t.Errorf("divergence: %q", r.Divergence)
+	}
+	if r.Actions != "买入区间 10.2-10.5，止损 9.8，目标 12" {
+		t.Errorf("actions: %q", r.Actions)
+	}
+	if sections := r.Sections(); len(sections) != 5 || sections[3].Title != SummaryRisks {
+		t.Errorf("sections: %+v", sections)
+	}
+}
+
+// TestParseSummaryReportVariants 测试加粗、编号与同行内容等写法
+func TestParseSummaryReportVariants(t *testing.T) {
+	content := "1. **核心结论**：可以持有\n2. **风险提示**：\n量能不足\n【操作建议】止损 9.8"
+	r := ParseSummaryReport(content)
+	if r == nil {
+		t.Fatal("report should be parsed")
+	}
+	if r.Conclusion != "可以持有" || r.Risks != "量能不足" || r.Actions != "止损 9.8" {
+		t.Errorf("report: %+v", r)
+	}
+	if r.KeyData != "" || len(r.Sections()) != 3 {
+		t.Errorf("sections: %+v", r.Sections())
+	}
+}
+
+// TestParseSummaryReportUnstructured 测试无分节的总结不解析
+func TestParseSummaryReportUnstructured(t *testing.T) {
+	for _, content := range []string{
+		"结论很明确：继续持有，跌破 9.8 止损。",
+		"### 结论\n继续持有",
+		"### 风险提示\n量能不足\n### 操作建议\n观望",
+	} {
+		if r := ParseSummaryReport(content); r != nil {
+			t.Errorf("%q should not be parsed: %+v", content, r)
+		}
+	}
+}
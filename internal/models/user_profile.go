@@ -0,0 +1,68 @@
+package models
+
+import "strings"
+
+// UserProfile 用户投资画像，用于让会议建议贴合用户的交易习惯
+type UserProfile struct {
+	RiskTolerance  string   `json:"riskTolerance"`  // 风险偏好: conservative / balanced / aggressive
+	HoldingHorizon string   `json:"holdingHorizon"` // 持有周期: short / medium / long
+	Strategies     []string `json:"strategies"`     // 偏好策略，如 趋势跟随、价值投资
+	AccountSize    string   `json:"accountSize"`    // 资金规模档位: small / medium / large
+	Notes          string   `json:"notes"`          // 其他补充说明
+}
+
+var (
+	riskToleranceLabels = map[string]string{
+		"conservative": "保守（重视本金安全，回撤容忍度低）",
+		"balanced":     "稳健（收益与风险平衡）",
+		"aggressive":   "激进（可承受较大回撤以博取高收益）",
+	}
+	holdingHorizonLabels = map[string]string{
+		"short":  "短线（数日以内，关注盘面与题材）",
+		"medium": "中线（数周到数月，关注趋势与业绩）",
+		"long":   "长线（一年以上，关注估值与基本面）",
+	}
+	accountSizeLabels = map[string]string{
+		"small":  "10万以下",
+		"medium": "10万-100万",
+		"large":  "100万以上",
+	}
+)
+
+// IsEmpty 画像是否未填写
+func (p UserProfile) IsEmpty() bool {
+	return p.RiskTolerance == "" && p.HoldingHorizon == "" && p.AccountSize == "" &&
+		len(p.Strategies) == 0 && strings.TrimSpace(p.Notes) == ""
+}
+
+// PromptContext 生成注入提示词的画像描述，未填写时返回空字符串
+func (p UserProfile) PromptContext() string {
+	if p.IsEmpty() {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("【老韭菜画像】\n")
+	writeProfileLine(&sb, "风险偏好", profileLabel(riskToleranceLabels, p.RiskTolerance))
+	writeProfileLine(&sb, "持有周期", profileLabel(holdingHorizonLabels, p.HoldingHorizon))
+	writeProfileLine(&sb, "偏好策略", strings.Join(p.Strategies, "、"))
+	writeProfileLine(&sb, "资金规模", profileLabel(accountSizeLabels, p.AccountSize))
+	writeProfileLine(&sb, "补充说明", strings.TrimSpace(p.Notes))
+	sb.WriteString("请让分析周期、方法和仓位建议匹配以上画像，不要给出与其持有周期或风险偏好明显不符的建议。\n")
+	return sb.String()
+}
+
+// profileLabel 将枚举值转换为描述，未知值原样返回（兼容用户手填）
+func profileLabel(labels map[string]string, value string) string {
+	if label, ok := labels[value]; ok {
+		return label
+	}
+	return value
+}
+
+func writeProfileLine(sb *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	sb.WriteString("- " + name + "：" + value + "\n")
+}
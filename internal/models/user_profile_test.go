@@ -0,0 +1,24 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestUserProfilePromptContext 测试画像描述生成
+func TestUserProfilePromptContext(t *testing.T) {
+	if got := (UserProfile{}).PromptContext(); got != "" {
+		t.Errorf("empty profile => %q, want empty", got)
+	}
+
+	p := UserProfile{HoldingHorizon: "short", Strategies: []string{"题材炒作", "打板"}, AccountSize: "自定义"}
+	got := p.PromptContext()
+	for _, want := range []string{"短线", "题材炒作、打板", "资金规模：自定义"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("prompt missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "- 风险偏好") {
+		t.Errorf("unset field should be omitted:\n%s", got)
+	}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 专家观点方向
+const (
+	StanceBull    = "bull"    // 看多
+	StanceBear    = "bear"    // 看空
+	StanceNeutral = "neutral" // 中性
+)
+
+// VerdictMarker 专家发言末尾的观点标记，如「【观点】看多，信心 7/10」
+const VerdictMarker = "【观点】"
+
+// DefaultVerdictConfidence 未给出信心时的默认值
+const DefaultVerdictConfidence = 5
+
+// ConsensusThreshold 共识分绝对值达到该值时视为偏多/偏空
+const ConsensusThreshold = 30
+
+var verdictConfidencePattern = regexp.MustCompile(`(?:信心|置信度?)\D{0,3}(\d{1,2})`)
+
+// stanceKeywords 观点方向关键词（按顺序匹配，「不看多」等否定写法少见，不做处理）
+var stanceKeywords = []struct {
+	keyword string
+	stance  string
+}{
+	{"看多", StanceBull}, {"看涨", StanceBull}, {"偏多", StanceBull},
+	{"看空", StanceBear}, {"看跌", StanceBear}, {"偏空", StanceBear},
+	{"中性", StanceNeutral}, {"观望", StanceNeutral},
+}
+
+// Verdict 专家的结构化观点
+type Verdict struct {
+	Stance     string `json:"stance"`     // bull/bear/neutral
+	Confidence int    `json:"confidence"` // 信心 1-10
+}
+
+// ParseVerdict 从专家发言中解析最后一个【观点】标记，没有或无法识别方向时返回 nil
+func ParseVerdict(content string) *Verdict {
+	idx := strings.LastIndex(content, VerdictMarker)
+	if idx < 0 {
+		return nil
+	}
+	line := content[idx+len(VerdictMarker):]
+	if end := strings.Index(line, "\n"); end >= 0 {
+		line = line[:end]
+	}
+
+	v := Verdict{Confidence: DefaultVerdictConfidence}
+	for _, k := range stanceKeywords {
+		if strings.Contains(line, k.keyword) {
+			v.Stance = k.stance
+			break
+		}
+	}
+	if v.Stance == "" {
+		return nil
+	}
+	if m := verdictConfidencePattern.FindStringSubmatch(line); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			v.Confidence = min(max(n, 1), 10)
+		}
+	}
+	return &v
+}
+
+// ConsensusVote 单个专家对共识分的贡献
+type ConsensusVote struct {
+	AgentID    string  `json:"agentId"`
+	AgentName  string  `json:"agentName"`
+	Stance     string  `json:"stance"`
+	Confidence int     `json:"confidence"`
+	Weight     float64 `json:"weight"` // 历史准确率权重，1 为无历史记录时的默认值
+}
+
+// ConsensusScore 按信心与历史准确率加权的多空共识分
+type ConsensusScore struct {
+	Score   float64         `json:"score"` // -100（一致看空）~ 100（一致看多）
+	Label   string          `json:"label"` // 偏多/偏空/分歧
+	Bull    int             `json:"bull"`
+	Bear    int             `json:"bear"`
+	Neutral int             `json:"neutral"`
+	Votes   []ConsensusVote `json:"votes"`
+	Price   float64         `json:"price,omitempty"` // 会议时的股价，用于事后检验观点
+}
+
+// ComputeConsensus 计算共识分：各专家方向（看多 +1、看空 -1、中性 0）按 信心×权重 加权平均
+// 没有有效观点时返回 nil
+func ComputeConsensus(votes []ConsensusVote) *ConsensusScore {
+	var c ConsensusScore
+	var sum, total float64
+	for _, v := range votes {
+		w := float64(v.Confidence) * v.Weight
+		if w <= 0 {
+			continue
+		}
+		switch v.Stance {
+		case StanceBull:
+			c.Bull++
+			sum += w
+		case StanceBear:
+			c.Bear++
+			sum -= w
+		case StanceNeutral:
+			c.Neutral++
+		default:
+			continue
+		}
+		total += w
+		c.Votes = append(c.Votes, v)
+	}
+	if total == 0 {
+		return nil
+	}
+	c.Score = math.Round(sum/total*1000) / 10
+	switch {
+	case c.Score >= ConsensusThreshold:
+		c.Label = "偏多"
+	case c.Score <= -ConsensusThreshold:
+		c.Label = "偏空"
+	default:
+		c.Label = "分歧"
+	}
+	return &c
+}
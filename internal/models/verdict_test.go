@@ -0,0 +1,52 @@
+package models
+
+import "testing"
+
+// TestParseVerdict 测试解析专家发言末尾的观点标记
+func TestParseVerdict(t *testing.T) {
+	cases := []struct {
+		content    string
+		stance     string
+		confidence int
+	}{
+		{"均线多头排列，量能温和放大。\n【观点】看多，信心 7/10", StanceBull, 7},
+		{"【观点】看空 置信度：9", StanceBear, 9},
+		{"估值合理\n【观点】中性", StanceNeutral, DefaultVerdictConfidence},
+		{"【观点】看多，信心 15/10", StanceBull, 10},
+	}
+	for _, c := range cases {
+		v := ParseVerdict(c.content)
+		if v == nil || v.Stance != c.stance || v.Confidence != c.confidence {
+			t.Errorf("%q: %+v", c.content, v)
+		}
+	}
+	for _, content := range []string{"没有观点标记，看多", "【观点】再看看"} {
+		if v := ParseVerdict(content); v != nil {
+			t.Errorf("%q should not be parsed: %+v", content, v)
+		}
+	}
+}
+
+// TestComputeConsensus 测试按信心与权重加权的共识分
+func TestComputeConsensus(t *testing.T) {
+	c := ComputeConsensus([]ConsensusVote{
+		{AgentID: "tech", Stance: StanceBull, Confidence: 8, Weight: 1.5},
+		{AgentID: "value", Stance: StanceBear, Confidence: 4, Weight: 1},
+		{AgentID: "macro", Stance: StanceNeutral, Confidence: 4, Weight: 0.5},
+		{AgentID: "bad", Stance: "", Confidence: 5, Weight: 1},
+	})
+	if c == nil {
+		t.Fatal("consensus should be computed")
+	}
+	// (12 - 4) / (12 + 4 + 2) = 0.444...
+	if c.Score != 44.4 || c.Label != "偏多" || c.Bull != 1 || c.Bear != 1 || c.Neutral != 1 || len(c.Votes) != 3 {
+		t.Errorf("consensus: %+v", c)
+	}
+
+	if c := ComputeConsensus([]ConsensusVote{{Stance: StanceBear, Confidence: 6, Weight: 1}}); c == nil || c.Score != -100 || c.Label != "偏空" {
+		t.Errorf("bear consensus: %+v", c)
+	}
+	if c := ComputeConsensus(nil); c != nil {
+		t.Errorf("empty votes: %+v", c)
+	}
+}
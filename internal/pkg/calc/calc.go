@@ -0,0 +1,626 @@
+// Package calc 沙箱表达式引擎
+// 只支持数值/数组运算、变量赋值与内置函数，没有循环、没有外部访问，执行必然终止
+package calc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	MaxSourceLen  = 4000 // 脚本最大长度
+	MaxStatements = 50   // 最大语句数
+	MaxDepth      = 64   // 括号、函数调用与一元运算的最大嵌套层数
+)
+
+// Value 计算值：标量或数组
+type Value struct {
+	Num   float64
+	Arr   []float64
+	IsArr bool
+}
+
+// Scalar 创建标量
+func Scalar(v float64) Value {
+	return Value{Num: v}
+}
+
+// Array 创建数组
+func Array(v []float64) Value {
+	return Value{Arr: v, IsArr: true}
+}
+
+// String 格式化输出，数组只展示最后若干项
+func (v Value) String() string {
+	if !v.IsArr {
+		return formatNum(v.Num)
+	}
+	const show = 10
+	items := v.Arr
+	prefix := ""
+	if len(items) > show {
+		items = items[len(items)-show:]
+		prefix = "..., "
+	}
+	parts := make([]string, len(items))
+	for i, x := range items {
+		parts[i] = formatNum(x)
+	}
+	return fmt.Sprintf("[%d项] [%s%s]", len(v.Arr), prefix, strings.Join(parts, ", "))
+}
+
+func formatNum(x float64) string {
+	return strconv.FormatFloat(x, 'g', 6, 64)
+}
+
+// Result 单条语句的计算结果
+type Result struct {
+	Expr  string // 赋值语句为变量名，否则为表达式原文
+	Value Value
+}
+
+// Eval 执行脚本：语句以分号或换行分隔，支持 name = expr 赋值；vars 为预置变量（不会被修改）
+func Eval(src string, vars map[string]Value) ([]Result, error) {
+	if len(src) > MaxSourceLen {
+		return nil, fmt.Errorf("脚本过长（最多%d字符）", MaxSourceLen)
+	}
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]Value, len(vars))
+	for k, v := range vars {
+		env[k] = v
+	}
+
+	var results []Result
+	for _, stmt := range splitStatements(tokens) {
+		if len(results) >= MaxStatements {
+			return nil, fmt.Errorf("语句过多（最多%d条）", MaxStatements)
+		}
+		name := ""
+		if len(stmt) > 2 && stmt[0].kind == tokIdent && stmt[1].text == "=" {
+			name = stmt[0].text
+			if _, ok := builtins[name]; ok {
+				return nil, fmt.Errorf("不能给内置函数赋值: %s", name)
+			}
+			stmt = stmt[2:]
+		}
+
+		p := &parser{tokens: stmt}
+		expr, err := p.parseExpr(1)
+		if err != nil {
+			return nil, err
+		}
+		if !p.done() {
+			return nil, fmt.Errorf("位置%d: 无法解析 %q", p.peek().pos, p.peek().text)
+		}
+		v, err := expr.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		if !finite(v) {
+			return nil, fmt.Errorf("计算结果溢出或无定义（如 log 负数、exp 过大）")
+		}
+
+		label := name
+		if name != "" {
+			env[name] = v
+		} else {
+			last := stmt[len(stmt)-1]
+			label = strings.TrimSpace(src[stmt[0].pos : last.pos+len(last.text)])
+		}
+		results = append(results, Result{Expr: label, Value: v})
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("脚本为空")
+	}
+	return results, nil
+}
+
+// ========== 词法分析 ==========
+
+type tokenKind int
+
+const (
+	tokNum tokenKind = iota
+	tokIdent
+	tokOp
+	tokSep
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+var twoCharOps = []string{"==", "!=", "<=", ">=", "&&", "||"}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			// 注释到行尾
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == ';' || c == '\n':
+			tokens = append(tokens, token{kind: tokSep, text: string(c), pos: i})
+			i++
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			j := i
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			if j < len(src) && (src[j] == 'e' || src[j] == 'E') {
+				k := j + 1
+				if k < len(src) && (src[k] == '+' || src[k] == '-') {
+					k++
+				}
+				if k < len(src) && isDigit(src[k]) {
+					for j = k; j < len(src) && isDigit(src[j]); j++ {
+					}
+				}
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("位置%d: 无效数字 %q", i, src[i:j])
+			}
+			tokens = append(tokens, token{kind: tokNum, text: src[i:j], num: n, pos: i})
+			i = j
+		case isIdentStart(c):
+			j := i
+			for j < len(src) && (isIdentStart(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[i:j], pos: i})
+			i = j
+		default:
+			op := ""
+			for _, two := range twoCharOps {
+				if strings.HasPrefix(src[i:], two) {
+					op = two
+					break
+				}
+			}
+			if op == "" && strings.IndexByte("+-*/%^()[],=<>!", c) >= 0 {
+				op = string(c)
+			}
+			if op == "" {
+				return nil, fmt.Errorf("位置%d: 不支持的字符 %q", i, c)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// splitStatements 按分隔符切分语句，括号内的换行不切分
+func splitStatements(tokens []token) [][]token {
+	var stmts [][]token
+	var cur []token
+	depth := 0
+	for _, t := range tokens {
+		switch {
+		case t.kind == tokOp && (t.text == "(" || t.text == "["):
+			depth++
+		case t.kind == tokOp && (t.text == ")" || t.text == "]"):
+			depth--
+		case t.kind == tokSep && (t.text == ";" || depth <= 0):
+			if len(cur) > 0 {
+				stmts = append(stmts, cur)
+			}
+			cur = nil
+			continue
+		case t.kind == tokSep:
+			continue
+		}
+		cur = append(cur, t)
+	}
+	if len(cur) > 0 {
+		stmts = append(stmts, cur)
+	}
+	return stmts
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// ========== 语法分析 ==========
+
+// binaryPrec 二元运算符优先级，数值越大结合越紧
+var binaryPrec = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{pos: -1}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) isOp(op string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.text == op
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOp(op) {
+		if p.done() {
+			return fmt.Errorf("缺少 %q", op)
+		}
+		return fmt.Errorf("位置%d: 期望 %q，实际为 %q", p.peek().pos, op, p.peek().text)
+	}
+	p.pos++
+	return nil
+}
+
+// parseExpr 按优先级解析二元表达式
+func (p *parser) parseExpr(minPrec int) (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		prec, ok := binaryPrec[t.text]
+		if t.kind != tokOp || !ok || prec < minPrec {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseExpr(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: t.text, left: left, right: right}
+	}
+}
+
+// parseUnary 一元负号/取反，优先级低于乘方（-2^2 = -4）
+func (p *parser) parseUnary() (node, error) {
+	// 所有递归都经过这里，限制深度避免超长嵌套耗尽栈
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > MaxDepth {
+		return nil, fmt.Errorf("嵌套过深（最多%d层）", MaxDepth)
+	}
+	if p.isOp("-") || p.isOp("+") || p.isOp("!") {
+		op := p.peek().text
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, x: x}, nil
+	}
+	return p.parsePower()
+}
+
+// parsePower 乘方，右结合
+func (p *parser) parsePower() (node, error) {
+	base, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("^") {
+		p.pos++
+		exp, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: "^", left: base, right: exp}, nil
+	}
+	return base, nil
+}
+
+// parsePostfix 下标访问 a[i]
+func (p *parser) parsePostfix() (node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("[") {
+		p.pos++
+		idx, err := p.parseExpr(1)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		x = &indexNode{x: x, index: idx}
+	}
+	return x, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.done() {
+		return nil, fmt.Errorf("表达式不完整")
+	}
+	t := p.peek()
+	p.pos++
+	switch {
+	case t.kind == tokNum:
+		return numNode(t.num), nil
+	case t.kind == tokIdent:
+		if !p.isOp("(") {
+			return varNode(t.text), nil
+		}
+		p.pos++
+		call := &callNode{name: t.text}
+		if _, ok := builtins[t.text]; !ok {
+			return nil, fmt.Errorf("未知函数: %s", t.text)
+		}
+		for !p.isOp(")") {
+			arg, err := p.parseExpr(1)
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if !p.isOp(",") {
+				break
+			}
+			p.pos++
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return call, nil
+	case t.kind == tokOp && t.text == "(":
+		x, err := p.parseExpr(1)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return nil, fmt.Errorf("位置%d: 意外的 %q", t.pos, t.text)
+}
+
+// ========== 求值 ==========
+
+type node interface {
+	eval(env map[string]Value) (Value, error)
+}
+
+type numNode float64
+
+func (n numNode) eval(map[string]Value) (Value, error) {
+	return Scalar(float64(n)), nil
+}
+
+type varNode string
+
+func (n varNode) eval(env map[string]Value) (Value, error) {
+	v, ok := env[string(n)]
+	if !ok {
+		return Value{}, fmt.Errorf("未定义的变量: %s", string(n))
+	}
+	return v, nil
+}
+
+type unaryNode struct {
+	op string
+	x  node
+}
+
+func (n *unaryNode) eval(env map[string]Value) (Value, error) {
+	v, err := n.x.eval(env)
+	if err != nil {
+		return Value{}, err
+	}
+	switch n.op {
+	case "-":
+		return mapValue(v, func(x float64) float64 { return -x }), nil
+	case "!":
+		return mapValue(v, func(x float64) float64 { return boolNum(x == 0) }), nil
+	}
+	return v, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(env map[string]Value) (Value, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return Value{}, err
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return Value{}, err
+	}
+	if (n.op == "/" || n.op == "%") && hasZero(r) {
+		return Value{}, fmt.Errorf("除数为零")
+	}
+	v, err := broadcast(l, r, n.op, binaryOps[n.op])
+	if err == nil && n.op == "^" && finite(l) && finite(r) && !finite(v) {
+		return Value{}, fmt.Errorf("乘方结果溢出或无定义")
+	}
+	return v, err
+}
+
+var binaryOps = map[string]func(a, b float64) float64{
+	"+":  func(a, b float64) float64 { return a + b },
+	"-":  func(a, b float64) float64 { return a - b },
+	"*":  func(a, b float64) float64 { return a * b },
+	"/":  func(a, b float64) float64 { return a / b },
+	"%":  math.Mod,
+	"^":  math.Pow,
+	"==": func(a, b float64) float64 { return boolNum(a == b) },
+	"!=": func(a, b float64) float64 { return boolNum(a != b) },
+	"<":  func(a, b float64) float64 { return boolNum(a < b) },
+	"<=": func(a, b float64) float64 { return boolNum(a <= b) },
+	">":  func(a, b float64) float64 { return boolNum(a > b) },
+	">=": func(a, b float64) float64 { return boolNum(a >= b) },
+	"&&": func(a, b float64) float64 { return boolNum(a != 0 && b != 0) },
+	"||": func(a, b float64) float64 { return boolNum(a != 0 || b != 0) },
+}
+
+type indexNode struct {
+	x, index node
+}
+
+func (n *indexNode) eval(env map[string]Value) (Value, error) {
+	v, err := n.x.eval(env)
+	if err != nil {
+		return Value{}, err
+	}
+	if !v.IsArr {
+		return Value{}, fmt.Errorf("只能对数组使用下标")
+	}
+	idx, err := n.index.eval(env)
+	if err != nil {
+		return Value{}, err
+	}
+	if idx.IsArr {
+		return Value{}, fmt.Errorf("下标必须为数字")
+	}
+	i, err := resolveIndex(idx.Num, len(v.Arr))
+	if err != nil {
+		return Value{}, err
+	}
+	return Scalar(v.Arr[i]), nil
+}
+
+type callNode struct {
+	name string
+	args []node
+}
+
+func (n *callNode) eval(env map[string]Value) (Value, error) {
+	args := make([]Value, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(env)
+		if err != nil {
+			return Value{}, err
+		}
+		args[i] = v
+	}
+	v, err := builtins[n.name].fn(args)
+	if err != nil {
+		return Value{}, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return v, nil
+}
+
+// broadcast 逐元素运算，标量自动扩展，数组长度需一致
+func broadcast(l, r Value, op string, f func(a, b float64) float64) (Value, error) {
+	switch {
+	case !l.IsArr && !r.IsArr:
+		return Scalar(f(l.Num, r.Num)), nil
+	case l.IsArr && r.IsArr && len(l.Arr) != len(r.Arr):
+		return Value{}, fmt.Errorf("数组长度不一致（%d vs %d），运算符 %s", len(l.Arr), len(r.Arr), op)
+	}
+	n := len(l.Arr)
+	if !l.IsArr {
+		n = len(r.Arr)
+	}
+	out := make([]float64, n)
+	for i := range out {
+		a, b := l.Num, r.Num
+		if l.IsArr {
+			a = l.Arr[i]
+		}
+		if r.IsArr {
+			b = r.Arr[i]
+		}
+		out[i] = f(a, b)
+	}
+	return Array(out), nil
+}
+
+// mapValue 对标量或数组逐元素变换
+func mapValue(v Value, f func(float64) float64) Value {
+	if !v.IsArr {
+		return Scalar(f(v.Num))
+	}
+	out := make([]float64, len(v.Arr))
+	for i, x := range v.Arr {
+		out[i] = f(x)
+	}
+	return Array(out)
+}
+
+// resolveIndex 支持负数下标（-1 为最后一项）
+func resolveIndex(f float64, n int) (int, error) {
+	i := int(f)
+	if float64(i) != f {
+		return 0, fmt.Errorf("下标必须为整数: %g", f)
+	}
+	if i < 0 {
+		i += n
+	}
+	if i < 0 || i >= n {
+		return 0, fmt.Errorf("下标越界: %g（长度%d）", f, n)
+	}
+	return i, nil
+}
+
+// hasZero 标量为零或数组含零
+func hasZero(v Value) bool {
+	if !v.IsArr {
+		return v.Num == 0
+	}
+	for _, x := range v.Arr {
+		if x == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// finite 标量或数组的每一项都是有限数值
+func finite(v Value) bool {
+	if !v.IsArr {
+		return !math.IsInf(v.Num, 0) && !math.IsNaN(v.Num)
+	}
+	for _, x := range v.Arr {
+		if math.IsInf(x, 0) || math.IsNaN(x) {
+			return false
+		}
+	}
+	return true
+}
+
+func boolNum(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
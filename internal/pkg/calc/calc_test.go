@@ -0,0 +1,184 @@
+package calc
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func evalLast(t *testing.T, src string, vars map[string]Value) Value {
+	t.Helper()
+	results, err := Eval(src, vars)
+	if err != nil {
+		t.Fatalf("Eval(%q): %v", src, err)
+	}
+	return results[len(results)-1].Value
+}
+
+func TestEvalScalar(t *testing.T) {
+	cases := map[string]float64{
+		"1 + 2 * 3":                     7,
+		"(1 + 2) * 3":                   9,
+		"-2 ^ 2":                        -4,
+		"2 ^ 3 ^ 2":                     512,
+		"2 ^ -1":                        0.5,
+		"10 % 4":                        2,
+		"1e2 / 4":                       25,
+		"3 > 2 && 1 == 1":               1,
+		"!0 || 0":                       1,
+		"round(3.14159, 2)":             3.14,
+		"max(1, 5, 3)":                  5,
+		"x = 4; y = x * 2\ny + sqrt(x)": 10,
+	}
+	for src, want := range cases {
+		if got := evalLast(t, src, nil); got.IsArr || math.Abs(got.Num-want) > 1e-9 {
+			t.Errorf("%s = %v, want %g", src, got, want)
+		}
+	}
+}
+
+func TestEvalArrays(t *testing.T) {
+	closes := Array([]float64{10, 12, 9, 11, 8, 13})
+	vars := map[string]Value{"close": closes}
+
+	if got := evalLast(t, "maxdd(close)", vars); math.Abs(got.Num-(12-8)/12.0) > 1e-9 {
+		t.Errorf("maxdd = %v", got)
+	}
+	if got := evalLast(t, "close[-1] / close[0] - 1", vars); math.Abs(got.Num-0.3) > 1e-9 {
+		t.Errorf("total return = %v", got)
+	}
+	if got := evalLast(t, "ma(close, 3)", vars); len(got.Arr) != 4 || got.Arr[0] != 31.0/3 {
+		t.Errorf("ma = %v", got)
+	}
+	if got := evalLast(t, "atr(close, close, close, 2)", vars); len(got.Arr) != 5 || got.Arr[0] != 1 {
+		t.Errorf("atr = %v", got)
+	}
+	if got := evalLast(t, "len(ret(close))", vars); got.Num != 5 {
+		t.Errorf("len(ret) = %v", got)
+	}
+	if got := evalLast(t, "tail(close, 2)", vars); len(got.Arr) != 2 || got.Arr[0] != 8 {
+		t.Errorf("tail = %v", got)
+	}
+	if got := evalLast(t, "slice(close, 1, -1)", vars); len(got.Arr) != 4 || got.Arr[0] != 12 {
+		t.Errorf("slice = %v", got)
+	}
+	if got := evalLast(t, "sum(close > 10)", vars); got.Num != 3 {
+		t.Errorf("count = %v", got)
+	}
+	if got := evalLast(t, "corr(close, close * 2 + 1)", vars); math.Abs(got.Num-1) > 1e-9 {
+		t.Errorf("corr = %v", got)
+	}
+
+	// 年化波动率：与手工计算一致
+	got := evalLast(t, "std(logret(close)) * sqrt(252)", vars)
+	var rets []float64
+	for i := 1; i < len(closes.Arr); i++ {
+		rets = append(rets, math.Log(closes.Arr[i]/closes.Arr[i-1]))
+	}
+	if want := stdDev(rets) * math.Sqrt(252); math.Abs(got.Num-want) > 1e-9 {
+		t.Errorf("volatility = %g, want %g", got.Num, want)
+	}
+
+	// 预置变量不被修改
+	if _, err := Eval("close = 1", vars); err != nil {
+		t.Fatal(err)
+	}
+	if !vars["close"].IsArr {
+		t.Error("vars should not be modified")
+	}
+}
+
+func TestEvalResults(t *testing.T) {
+	results, err := Eval("a = 1 + 1\na * 3 # 注释\n", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Expr != "a" || results[1].Expr != "a * 3" || results[1].Value.Num != 6 {
+		t.Errorf("results = %+v", results)
+	}
+	// 括号内换行不切分语句
+	if got := evalLast(t, "max(1,\n 2)", nil); got.Num != 2 {
+		t.Errorf("multiline = %v", got)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	vars := map[string]Value{"a": Array([]float64{1, 2, 3}), "b": Array([]float64{1, 2})}
+	cases := map[string]string{
+		"a + b":                            "长度不一致",
+		"a[5]":                             "越界",
+		"foo(1)":                           "未知函数",
+		"x + 1":                            "未定义",
+		"1 +":                              "不完整",
+		"(1 + 2":                           "缺少",
+		"1 $ 2":                            "不支持的字符",
+		"mean(1)":                          "数组",
+		"ma(a, 5)":                         "周期",
+		"sum = 1":                          "内置函数",
+		"":                                 "为空",
+		strings.Repeat("1+", MaxSourceLen): "过长",
+	}
+	for src, want := range cases {
+		_, err := Eval(src, vars)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Eval(%.20q) err = %v, want contains %q", src, err, want)
+		}
+	}
+}
+
+func TestEvalEdgeCases(t *testing.T) {
+	vars := map[string]Value{"a": Array([]float64{1, 0, 2})}
+	cases := map[string]string{
+		"1 / 0":                 "除数为零",
+		"5 % 0":                 "除数为零",
+		"1 / a":                 "除数为零",
+		"x = 0; 3 / x":          "除数为零",
+		"10 ^ 400":              "溢出",
+		"2 ^ 2 ^ 2 ^ 2 ^ 2 ^ 2": "溢出",
+		"(-8) ^ 0.5":            "无定义",
+		"pow(10, 1e6)":          "溢出",
+		"exp(1000)":             "溢出",
+		"log(-1)":               "无定义",
+		"1e999":                 "无效数字",
+		strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100):  "嵌套过深",
+		strings.Repeat("-", 200) + "1":                             "嵌套过深",
+		strings.Repeat("abs(", 80) + "1" + strings.Repeat(")", 80): "嵌套过深",
+	}
+	for src, want := range cases {
+		_, err := Eval(src, vars)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Eval(%.30q) err = %v, want contains %q", src, err, want)
+		}
+	}
+
+	// 限制以内的嵌套与极值仍可正常计算
+	ok := map[string]float64{
+		strings.Repeat("(", 30) + "1" + strings.Repeat(")", 30): 1,
+		"10 ^ 300 / 10 ^ 299": 10,
+		"2 ^ -1074 > 0":       1,
+		"0 / 5":               0,
+	}
+	for src, want := range ok {
+		if got := evalLast(t, src, nil); math.Abs(got.Num-want) > 1e-9 {
+			t.Errorf("%.30q = %v, want %g", src, got, want)
+		}
+	}
+}
+
+// FuzzEval 任意输入都只返回结果或错误，不会 panic 或挂起
+func FuzzEval(f *testing.F) {
+	for _, seed := range []string{
+		"1 + 2 * 3", "x = close[-1]; x / close[0]", "ma(close, 3)[0]", "slice(close, -2)",
+		"max(close) - min(close)", "corr(close, ret(close))", "((1", "a[", "1 / 0", "2 ^ 2 ^ 2",
+		"tail(close, -1)", "atr(close, close, close, 0)", "round(1.5, -400)", "-!-+1",
+	} {
+		f.Add(seed)
+	}
+	vars := map[string]Value{"close": Array([]float64{10, 12, 9, 11})}
+	f.Fuzz(func(t *testing.T, src string) {
+		results, err := Eval(src, vars)
+		if err == nil && len(results) == 0 {
+			t.Errorf("Eval(%q) returned no results and no error", src)
+		}
+	})
+}
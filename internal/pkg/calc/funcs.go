@@ -0,0 +1,378 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/analysis"
+)
+
+// builtin 内置函数
+type builtin struct {
+	usage string
+	fn    func(args []Value) (Value, error)
+}
+
+var builtins map[string]builtin
+
+func init() {
+	builtins = map[string]builtin{
+		// 逐元素数学函数
+		"abs":   elementwise("abs(x)", math.Abs),
+		"sqrt":  elementwise("sqrt(x)", math.Sqrt),
+		"log":   elementwise("log(x) 自然对数", math.Log),
+		"exp":   elementwise("exp(x)", math.Exp),
+		"pow":   {usage: "pow(x, y)", fn: powFn},
+		"round": {usage: "round(x, n) 保留n位小数", fn: roundFn},
+
+		// 数组聚合
+		"len":    reduce("len(a)", func(a []float64) float64 { return float64(len(a)) }),
+		"sum":    reduce("sum(a)", sum),
+		"mean":   reduce("mean(a)", mean),
+		"std":    reduce("std(a) 样本标准差", stdDev),
+		"var":    reduce("var(a) 样本方差", variance),
+		"median": reduce("median(a)", median),
+		"first":  reduce("first(a)", func(a []float64) float64 { return a[0] }),
+		"last":   reduce("last(a)", func(a []float64) float64 { return a[len(a)-1] }),
+		"maxdd":  reduce("maxdd(a) 最大回撤比例（正数，如0.25表示回撤25%）", maxDrawdown),
+		"min":    {usage: "min(a) 或 min(x, y, ...)", fn: extremum(math.Min)},
+		"max":    {usage: "max(a) 或 max(x, y, ...)", fn: extremum(math.Max)},
+		"corr":   {usage: "corr(a, b) 相关系数", fn: corrFn},
+
+		// 数组变换
+		"slice":  {usage: "slice(a, start, end) 含start不含end，支持负数", fn: sliceFn},
+		"tail":   {usage: "tail(a, n) 最后n项", fn: tailFn},
+		"diff":   transform("diff(a) 相邻差值（长度-1）", 1, func(a []float64, i int) float64 { return a[i] - a[i-1] }),
+		"ret":    transform("ret(a) 简单收益率（长度-1）", 1, func(a []float64, i int) float64 { return a[i]/a[i-1] - 1 }),
+		"logret": transform("logret(a) 对数收益率（长度-1）", 1, func(a []float64, i int) float64 { return math.Log(a[i] / a[i-1]) }),
+		"ma":     {usage: "ma(a, n) n期滚动均值（长度-n+1）", fn: maFn},
+		"atr":    {usage: "atr(high, low, close, n) n期平均真实波幅（长度-n+1）", fn: atrFn},
+		"cummax": {usage: "cummax(a) 累计最大值", fn: cumFn(math.Max)},
+		"cummin": {usage: "cummin(a) 累计最小值", fn: cumFn(math.Min)},
+	}
+}
+
+// Usage 内置函数说明，按名称排序
+func Usage() string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = builtins[name].usage
+	}
+	return strings.Join(lines, "; ")
+}
+
+func elementwise(usage string, f func(float64) float64) builtin {
+	return builtin{usage: usage, fn: func(args []Value) (Value, error) {
+		if err := argCount(args, 1, 1); err != nil {
+			return Value{}, err
+		}
+		return mapValue(args[0], f), nil
+	}}
+}
+
+func reduce(usage string, f func([]float64) float64) builtin {
+	return builtin{usage: usage, fn: func(args []Value) (Value, error) {
+		if err := argCount(args, 1, 1); err != nil {
+			return Value{}, err
+		}
+		a, err := arrayArg(args[0])
+		if err != nil {
+			return Value{}, err
+		}
+		return Scalar(f(a)), nil
+	}}
+}
+
+// transform 基于前 lag 项计算的数组变换，结果长度为 len-lag
+func transform(usage string, lag int, f func(a []float64, i int) float64) builtin {
+	return builtin{usage: usage, fn: func(args []Value) (Value, error) {
+		if err := argCount(args, 1, 1); err != nil {
+			return Value{}, err
+		}
+		a, err := arrayArg(args[0])
+		if err != nil {
+			return Value{}, err
+		}
+		out := make([]float64, 0, len(a))
+		for i := lag; i < len(a); i++ {
+			out = append(out, f(a, i))
+		}
+		return Array(out), nil
+	}}
+}
+
+func argCount(args []Value, minN, maxN int) error {
+	if len(args) < minN || len(args) > maxN {
+		if minN == maxN {
+			return fmt.Errorf("需要%d个参数，实际%d个", minN, len(args))
+		}
+		return fmt.Errorf("需要%d~%d个参数，实际%d个", minN, maxN, len(args))
+	}
+	return nil
+}
+
+// arrayArg 取非空数组参数
+func arrayArg(v Value) ([]float64, error) {
+	if !v.IsArr {
+		return nil, fmt.Errorf("参数必须为数组")
+	}
+	if len(v.Arr) == 0 {
+		return nil, fmt.Errorf("数组为空")
+	}
+	return v.Arr, nil
+}
+
+// intArg 取整数参数
+func intArg(v Value) (int, error) {
+	if v.IsArr || v.Num != math.Trunc(v.Num) {
+		return 0, fmt.Errorf("参数必须为整数")
+	}
+	return int(v.Num), nil
+}
+
+func powFn(args []Value) (Value, error) {
+	if err := argCount(args, 2, 2); err != nil {
+		return Value{}, err
+	}
+	v, err := broadcast(args[0], args[1], "pow", math.Pow)
+	if err == nil && finite(args[0]) && finite(args[1]) && !finite(v) {
+		return Value{}, fmt.Errorf("结果溢出或无定义")
+	}
+	return v, err
+}
+
+func roundFn(args []Value) (Value, error) {
+	if err := argCount(args, 1, 2); err != nil {
+		return Value{}, err
+	}
+	digits := 0
+	if len(args) == 2 {
+		n, err := intArg(args[1])
+		if err != nil {
+			return Value{}, err
+		}
+		digits = n
+	}
+	scale := math.Pow(10, float64(digits))
+	return mapValue(args[0], func(x float64) float64 { return math.Round(x*scale) / scale }), nil
+}
+
+func sum(a []float64) float64 {
+	var s float64
+	for _, x := range a {
+		s += x
+	}
+	return s
+}
+
+func mean(a []float64) float64 {
+	return sum(a) / float64(len(a))
+}
+
+func variance(a []float64) float64 {
+	if len(a) < 2 {
+		return 0
+	}
+	m := mean(a)
+	var s float64
+	for _, x := range a {
+		s += (x - m) * (x - m)
+	}
+	return s / float64(len(a)-1)
+}
+
+func stdDev(a []float64) float64 {
+	return math.Sqrt(variance(a))
+}
+
+func median(a []float64) float64 {
+	sorted := append([]float64(nil), a...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// maxDrawdown 价格序列从前高到后低的最大跌幅
+func maxDrawdown(a []float64) float64 {
+	peak, dd := a[0], 0.0
+	for _, x := range a {
+		if x > peak {
+			peak = x
+		}
+		if peak > 0 {
+			dd = math.Max(dd, (peak-x)/peak)
+		}
+	}
+	return dd
+}
+
+// extremum 单个数组参数时求极值，多个参数时逐元素比较
+func extremum(f func(a, b float64) float64) func(args []Value) (Value, error) {
+	return func(args []Value) (Value, error) {
+		if len(args) == 0 {
+			return Value{}, fmt.Errorf("至少需要1个参数")
+		}
+		if len(args) == 1 {
+			a, err := arrayArg(args[0])
+			if err != nil {
+				return Value{}, err
+			}
+			m := a[0]
+			for _, x := range a[1:] {
+				m = f(m, x)
+			}
+			return Scalar(m), nil
+		}
+		acc := args[0]
+		for _, v := range args[1:] {
+			var err error
+			if acc, err = broadcast(acc, v, "min/max", f); err != nil {
+				return Value{}, err
+			}
+		}
+		return acc, nil
+	}
+}
+
+func corrFn(args []Value) (Value, error) {
+	if err := argCount(args, 2, 2); err != nil {
+		return Value{}, err
+	}
+	a, err := arrayArg(args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	b, err := arrayArg(args[1])
+	if err != nil {
+		return Value{}, err
+	}
+	if len(a) != len(b) {
+		return Value{}, fmt.Errorf("数组长度不一致（%d vs %d）", len(a), len(b))
+	}
+	ma, mb := mean(a), mean(b)
+	var cov, va, vb float64
+	for i := range a {
+		cov += (a[i] - ma) * (b[i] - mb)
+		va += (a[i] - ma) * (a[i] - ma)
+		vb += (b[i] - mb) * (b[i] - mb)
+	}
+	return Scalar(cov / math.Sqrt(va*vb)), nil
+}
+
+func sliceFn(args []Value) (Value, error) {
+	if err := argCount(args, 2, 3); err != nil {
+		return Value{}, err
+	}
+	if !args[0].IsArr {
+		return Value{}, fmt.Errorf("参数必须为数组")
+	}
+	a := args[0].Arr
+	start, err := intArg(args[1])
+	if err != nil {
+		return Value{}, err
+	}
+	end := len(a)
+	if len(args) == 3 {
+		if end, err = intArg(args[2]); err != nil {
+			return Value{}, err
+		}
+	}
+	start, end = clampIndex(start, len(a)), clampIndex(end, len(a))
+	if start > end {
+		start = end
+	}
+	return Array(append([]float64(nil), a[start:end]...)), nil
+}
+
+// clampIndex 负数从末尾计，越界截断到 [0, n]
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	return max(0, min(i, n))
+}
+
+func tailFn(args []Value) (Value, error) {
+	if err := argCount(args, 2, 2); err != nil {
+		return Value{}, err
+	}
+	n, err := intArg(args[1])
+	if err != nil {
+		return Value{}, err
+	}
+	if n < 0 {
+		return Value{}, fmt.Errorf("n不能为负数")
+	}
+	return sliceFn([]Value{args[0], Scalar(float64(-min(n, len(args[0].Arr))))})
+}
+
+func maFn(args []Value) (Value, error) {
+	if err := argCount(args, 2, 2); err != nil {
+		return Value{}, err
+	}
+	a, err := arrayArg(args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	n, err := intArg(args[1])
+	if err != nil {
+		return Value{}, err
+	}
+	if n <= 0 || n > len(a) {
+		return Value{}, fmt.Errorf("周期需在1~%d之间", len(a))
+	}
+	return Array(analysis.MA(a, n)[n-1:]), nil
+}
+
+func atrFn(args []Value) (Value, error) {
+	if err := argCount(args, 4, 4); err != nil {
+		return Value{}, err
+	}
+	var hlc [3][]float64
+	for i := range hlc {
+		a, err := arrayArg(args[i])
+		if err != nil {
+			return Value{}, err
+		}
+		hlc[i] = a
+	}
+	if len(hlc[0]) != len(hlc[1]) || len(hlc[0]) != len(hlc[2]) {
+		return Value{}, fmt.Errorf("high/low/close 长度不一致")
+	}
+	n, err := intArg(args[3])
+	if err != nil {
+		return Value{}, err
+	}
+	if n <= 0 || n > len(hlc[0]) {
+		return Value{}, fmt.Errorf("周期需在1~%d之间", len(hlc[0]))
+	}
+	return Array(analysis.ATR(hlc[0], hlc[1], hlc[2], n)[n-1:]), nil
+}
+
+func cumFn(f func(a, b float64) float64) func(args []Value) (Value, error) {
+	return func(args []Value) (Value, error) {
+		if err := argCount(args, 1, 1); err != nil {
+			return Value{}, err
+		}
+		a, err := arrayArg(args[0])
+		if err != nil {
+			return Value{}, err
+		}
+		out := make([]float64, len(a))
+		out[0] = a[0]
+		for i := 1; i < len(a); i++ {
+			out[i] = f(out[i-1], a[i])
+		}
+		return Array(out), nil
+	}
+}
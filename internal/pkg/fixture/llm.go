@@ -0,0 +1,96 @@
+package fixture
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"sync"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// LLM 脚本化的 model.LLM：按提示词（系统指令与全部消息）包含的关键字返回固定回复，
+// 规则按添加顺序匹配，都不匹配时返回默认回复；记录收到的提示词便于断言
+type LLM struct {
+	fallback string
+
+	mu      sync.Mutex
+	rules   []llmRule
+	prompts []string
+}
+
+type llmRule struct {
+	contains string
+	reply    string
+}
+
+// NewLLM 创建脚本化 LLM，fallback 为没有规则匹配时的回复
+func NewLLM(fallback string) *LLM {
+	return &LLM{fallback: fallback}
+}
+
+// On 提示词包含 contains 时返回 reply
+func (l *LLM) On(contains, reply string) *LLM {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rules = append(l.rules, llmRule{contains: contains, reply: reply})
+	return l
+}
+
+// Prompts 已收到的提示词
+func (l *LLM) Prompts() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.prompts...)
+}
+
+func (l *LLM) Name() string { return "fixture" }
+
+// GenerateContent 返回匹配的回复；流式请求同样一次返回完整回复
+func (l *LLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	prompt := requestText(req)
+	l.mu.Lock()
+	l.prompts = append(l.prompts, prompt)
+	reply := l.fallback
+	for _, r := range l.rules {
+		if strings.Contains(prompt, r.contains) {
+			reply = r.reply
+			break
+		}
+	}
+	l.mu.Unlock()
+
+	return func(yield func(*model.LLMResponse, error) bool) {
+		if err := ctx.Err(); err != nil {
+			yield(nil, err)
+			return
+		}
+		yield(&model.LLMResponse{
+			Content:       genai.NewContentFromText(reply, genai.RoleModel),
+			TurnComplete:  true,
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: int32(len(prompt) / 4), CandidatesTokenCount: int32(len(reply) / 4)},
+		}, nil)
+	}
+}
+
+// requestText 拼接系统指令与全部消息文本
+func requestText(req *model.LLMRequest) string {
+	var sb strings.Builder
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		for _, p := range req.Config.SystemInstruction.Parts {
+			sb.WriteString(p.Text)
+			sb.WriteByte('\n')
+		}
+	}
+	for _, c := range req.Contents {
+		if c == nil {
+			continue
+		}
+		for _, p := range c.Parts {
+			sb.WriteString(p.Text)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
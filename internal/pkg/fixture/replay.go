@@ -0,0 +1,204 @@
+// Package fixture 提供离线测试支持：按请求回放录制的上游响应（golden 文件），以及脚本化的 LLM
+//
+// 回放目录下的 index.json 记录请求键到响应文件的映射，请求键为「方法 主机路径?参数」，
+// 去掉时间戳、随机数等易变参数。设置环境变量 JCP_RECORD_FIXTURES=1 时改为请求真实上游并写入目录。
+package fixture
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// RecordEnv 设置为 1 时录制真实上游响应
+const RecordEnv = "JCP_RECORD_FIXTURES"
+
+const indexFile = "index.json"
+
+// volatileParams 每次请求都会变化的参数，不参与请求键
+var volatileParams = map[string]bool{
+	"rn": true, "_": true, "t": true, "ts": true, "timestamp": true,
+	"r": true, "cb": true, "callback": true, "endTime": true,
+}
+
+// Entry 单个录制的响应
+type Entry struct {
+	File   string            `json:"file"`
+	Status int               `json:"status,omitempty"` // 默认 200
+	Header map[string]string `json:"header,omitempty"`
+}
+
+// Transport 回放（或录制）上游响应的 http.RoundTripper
+type Transport struct {
+	dir    string
+	record bool
+	next   http.RoundTripper // 录制时使用的真实传输
+
+	mu     sync.Mutex
+	index  map[string]Entry
+	misses []string
+}
+
+// NewTransport 加载 dir 下的录制索引
+func NewTransport(dir string) (*Transport, error) {
+	t := &Transport{
+		dir:    dir,
+		record: os.Getenv(RecordEnv) == "1",
+		next:   http.DefaultTransport,
+		index:  make(map[string]Entry),
+	}
+	data, err := os.ReadFile(filepath.Join(dir, indexFile))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &t.index); err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %w", indexFile, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+	return t, nil
+}
+
+// Install 让全局代理 Client 在测试期间回放 dir 下的响应，测试结束时恢复并报告没有录制的请求
+func Install(tb testing.TB, dir string) *Transport {
+	tb.Helper()
+	t, err := NewTransport(dir)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	restore := proxy.GetManager().SetRoundTripper(t)
+	tb.Cleanup(func() {
+		restore()
+		for _, key := range t.Misses() {
+			tb.Errorf("fixture 缺少录制: %s（设置 %s=1 重新录制）", key, RecordEnv)
+		}
+	})
+	return t
+}
+
+// Misses 回放时没有找到录制的请求键
+func (t *Transport) Misses() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.misses...)
+}
+
+// RoundTrip 按请求键返回录制的响应
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := Key(req)
+	if t.record {
+		return t.recordResponse(key, req)
+	}
+
+	t.mu.Lock()
+	entry, ok := t.index[key]
+	if !ok {
+		t.misses = append(t.misses, key)
+	}
+	t.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("fixture: 没有录制的响应: %s", key)
+	}
+
+	body, err := os.ReadFile(filepath.Join(t.dir, entry.File))
+	if err != nil {
+		return nil, err
+	}
+	status := entry.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := make(http.Header, len(entry.Header))
+	for k, v := range entry.Header {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// recordResponse 请求真实上游，写入响应文件并更新索引
+func (t *Transport) recordResponse(key string, req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	sum := sha1.Sum([]byte(key))
+	entry := Entry{File: req.URL.Hostname() + "_" + hex.EncodeToString(sum[:4]) + ".golden"}
+	if resp.StatusCode != http.StatusOK {
+		entry.Status = resp.StatusCode
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		entry.Header = map[string]string{"ETag": etag}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, entry.File), body, 0644); err != nil {
+		return nil, err
+	}
+	t.index[key] = entry
+	data, err := json.MarshalIndent(t.index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return resp, os.WriteFile(filepath.Join(t.dir, indexFile), append(data, '\n'), 0644)
+}
+
+// Key 请求键：方法 + 主机路径 + 去掉易变参数后的查询参数（保持原顺序）
+// 新浪行情等把参数写在路径里（/rn=xxx&list=...）的地址同样处理
+func Key(req *http.Request) string {
+	target := req.URL.Host + req.URL.Path
+	if req.URL.RawQuery != "" {
+		target += "?" + req.URL.RawQuery
+	}
+
+	base, query := target, ""
+	if i := strings.IndexByte(target, '?'); i >= 0 {
+		base, query = target[:i], target[i+1:]
+	} else if i := strings.LastIndexByte(target, '/'); i >= 0 && strings.Contains(target[i:], "=") {
+		base, query = target[:i+1], target[i+1:]
+	}
+
+	var params []string
+	for _, p := range strings.Split(query, "&") {
+		name, _, _ := strings.Cut(p, "=")
+		if p == "" || volatileParams[name] {
+			continue
+		}
+		params = append(params, p)
+	}
+	if len(params) == 0 {
+		return req.Method + " " + base
+	}
+	return req.Method + " " + base + "?" + strings.Join(params, "&")
+}
@@ -0,0 +1,40 @@
+package fixture
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	cases := []struct {
+		method, url, want string
+	}{
+		{"GET", "https://hq.sinajs.cn/rn=1718870400000&list=sh600519", "GET hq.sinajs.cn/?list=sh600519"},
+		{"GET", "https://push2.eastmoney.com/api/qt/clist/get?pn=1&_=1718870400000&fs=m:0", "GET push2.eastmoney.com/api/qt/clist/get?pn=1&fs=m:0"},
+		{"POST", "https://emappdata.eastmoney.com/stockrank/getAllCurrentList", "POST emappdata.eastmoney.com/stockrank/getAllCurrentList"},
+		{"GET", "https://www.cls.cn/telegraph?t=123", "GET www.cls.cn/telegraph"},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest(c.method, c.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Key(req); got != c.want {
+			t.Errorf("Key(%s) = %q, want %q", c.url, got, c.want)
+		}
+	}
+}
+
+func TestTransportMiss(t *testing.T) {
+	tr, err := NewTransport(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest("GET", "https://example.com/a?b=1", nil)
+	if _, err := tr.RoundTrip(req); err == nil {
+		t.Error("expected error for unrecorded request")
+	}
+	if misses := tr.Misses(); len(misses) != 1 || misses[0] != "GET example.com/a?b=1" {
+		t.Errorf("misses = %v", misses)
+	}
+}
@@ -0,0 +1,212 @@
+package i18n
+
+// enUS 英文译文，键为代码中的中文原文（格式串的占位符顺序须与原文一致）
+var enUS = map[string]string{
+	// 错误信息
+	"会议超时，已返回部分结果":      "Meeting timed out, partial results returned",
+	"小韭菜响应超时":           "The moderator did not respond in time",
+	"未配置 AI 服务":         "No AI service configured",
+	"未配置AI服务":           "No AI service configured",
+	"没有可用的专家":           "No experts available",
+	"专家不存在":             "Expert not found",
+	"会话不存在":             "Session not found",
+	"获取股票数据失败":          "Failed to fetch stock data",
+	"服务未初始化":            "Service not initialized",
+	"更新服务未初始化":          "Update service not initialized",
+	"数据已加密，请先输入密码解锁":    "Data is encrypted, please unlock with your password first",
+	"密码错误":              "Wrong password",
+	"无效的会议 ID":          "Invalid meeting ID",
+	"会议回放不存在":           "Meeting replay not found",
+	"该股票已有会议正在进行":       "A meeting is already running for this stock",
+	"该股票没有正在进行的会议":      "No meeting is running for this stock",
+	"模型服务限流，请稍后重试或降低并发": "The model service is rate limiting requests, retry later or lower concurrency",
+	"鉴权失败，请检查 API Key":  "Authentication failed, check the API Key",
+	"上下文超出模型长度限制，请精简问题或调低上下文预算": "Context exceeds the model's length limit, shorten the question or lower the context budget",
+	"数据工具 %s 调用失败，请稍后重试":        "Data tool %s failed, retry later",
+	"模型响应超时，请稍后重试或检查网络":         "The model timed out, retry later or check the network",
+	"无效的研究 ID":                "Invalid research ID",
+	"深度研究不存在":                 "Deep research not found",
+	"深度研究已完成":                 "Deep research already completed",
+	"未启用深度研究":                 "Deep research is not enabled",
+	"未能拟定研究计划":                "Failed to draw up a research plan",
+	"研究费用已超出预算":               "Research cost exceeded the budget",
+	"没有可朗读的内容":                "Nothing to read aloud",
+	"无效的语音文件":                 "Invalid speech file",
+	"语音文件不存在":                 "Speech file not found",
+	"语音朗读未配置可用的 AI 服务":        "No AI service available for text-to-speech",
+	"\n\n## 回复语言\n请使用简体中文回复。": "\n\n## Reply language\nAlways reply in English. Keep the markers, section headings, JSON keys and IDs required above exactly as given (for example 【观点】看多/看空/中性 and ### section titles); only the free text should be in English.",
+
+	// 专家提示词
+	"你是一位%s，名字是%s。": "You are a %s named %s.",
+	"休市（%s）":        "Closed (%s)",
+	"周末":            "weekend",
+	"元旦":            "New Year's Day",
+	"春节":            "Spring Festival",
+	"清明节":           "Qingming Festival",
+	"劳动节":           "Labour Day",
+	"端午节":           "Dragon Boat Festival",
+	"中秋节":           "Mid-Autumn Festival",
+	"国庆节":           "National Day",
+	"国庆节、中秋节":       "National Day and Mid-Autumn Festival",
+	"，下一交易日 %s":     ", next trading day %s",
+	"盘中（上午交易时段）":    "Trading (morning session)",
+	"盘中（下午交易时段）":    "Trading (afternoon session)",
+	"盘前":            "Pre-market",
+	"盘后":            "After hours",
+	"午间休市":          "Midday break",
+	`%s
+当前时间: %s
+市场状态: %s
+
+## 工具调用规范
+当你需要调用工具时，必须通过系统提供的标准 function call 机制进行调用。
+**重要：需要调用工具时，不要在工具调用前输出任何思考过程或分析文字，直接发起工具调用。工具返回结果后，再基于结果组织你的回答。**
+禁止在回复文本中输出任何自定义的工具调用标签，包括但不限于：
+- <tool_call>、</tool_call>
+- <tool_call_begin>、</tool_call_end>
+- <invoke>、</invoke>
+- <tool>、</tool>
+- 任何类似 <xxx:tool_call> 格式的标签
+直接使用 API 提供的 tool_calls 功能，不要在文本中模拟工具调用。
+
+`: `%s
+Current time: %s
+Market status: %s
+
+## Tool calling rules
+When you need a tool, call it through the standard function call mechanism provided by the system.
+**Important: do not output any reasoning or analysis before a tool call; call the tool directly, then build your answer from its results.**
+Never write custom tool-call tags in your reply text, including but not limited to:
+- <tool_call>, </tool_call>
+- <tool_call_begin>, </tool_call_end>
+- <invoke>, </invoke>
+- <tool>, </tool>
+- any tag like <xxx:tool_call>
+Use the tool_calls capability of the API directly instead of imitating tool calls in text.
+
+`,
+	`## 双股对比
+股票A: %s (%s)，当前价格 %.2f，涨跌幅 %.2f%%
+股票B: %s (%s)，当前价格 %.2f，涨跌幅 %.2f%%
+工具的股票代码参数可传入任意一只股票的代码，两只股票都要查询，并在同一维度下对比给出结论。
+`: `## Two-stock comparison
+Stock A: %s (%s), price %.2f, change %.2f%%
+Stock B: %s (%s), price %.2f, change %.2f%%
+Tool stock-code parameters accept either code. Query both stocks and compare them on the same dimensions before concluding.
+`,
+	`股票: %s (%s)
+当前价格: %.2f
+涨跌幅: %.2f%%
+`: `Stock: %s (%s)
+Current price: %.2f
+Change: %.2f%%
+`,
+	"今日: 开盘 %.2f，最高 %.2f，最低 %.2f，昨收 %.2f，振幅 %.2f%%，成交额 %.2f亿\n": "Today: open %.2f, high %.2f, low %.2f, prev close %.2f, amplitude %.2f%%, turnover %.2f hundred million CNY\n",
+	"亏损": "loss-making",
+	"换手率: %.2f%%，市盈率(动): %s，市净率: %.2f\n":    "Turnover rate: %.2f%%, P/E (dynamic): %s, P/B: %.2f\n",
+	"近5日: 区间 %.2f ~ %.2f，涨跌幅 %.2f%%\n":      "Last 5 days: range %.2f ~ %.2f, change %.2f%%\n",
+	"52周区间: %.2f ~ %.2f，当前处于区间 %.0f%% 位置\n": "52-week range: %.2f ~ %.2f, currently at %.0f%% of the range\n",
+	`
+用户持仓: %d股，成本价 %.2f
+持仓市值: %.2f，盈亏: %.2f (%.2f%%)
+`: `
+User position: %d shares, cost %.2f
+Market value: %.2f, P&L: %.2f (%.2f%%)
+`,
+	"\n回答最后单独一行给出你的观点：%s看多/看空/中性，信心 1-10（如「%s看多，信心 7/10」），不计入字数。": "\nEnd your answer with a separate line giving your view, written in Chinese exactly in this format: %s看多/看空/中性，信心 1-10 (e.g. \"%s看多，信心 7/10\"). This line does not count toward the word limit.",
+	`--- 引用的观点 ---
+%s
+---
+
+你的分析任务: %s
+
+请结合以上引用的观点，发表你的专业看法。可以赞同、补充或反驳。回复控制在%d字以内。%s`: `--- Quoted view ---
+%s
+---
+
+Your task: %s
+
+Give your professional opinion on the quoted view above. You may agree, add to it or rebut it. Keep your reply within %d words.%s`,
+	`你的分析任务: %s
+
+请用简洁专业的语言回答，控制在%d字以内。%s`: `Your task: %s
+
+Answer concisely and professionally within %d words.%s`,
+	"- %s: %s (来自 %s)":       "- %s: %s (from %s)",
+	"\n## 工具使用规则（必须遵守）\n\n":  "\n## Tool usage rules (mandatory)\n\n",
+	"### 搜索工具（遇到信息查询必须调用）\n": "### Search tools (must be used for information queries)\n",
+	"\n**重要**: 当用户询问新闻、事件、公告、研报、市场动态等信息时，你**必须先调用搜索工具**获取最新信息，**禁止凭记忆回答**。\n\n": "\n**Important**: when the user asks about news, events, announcements, research reports or market developments, you **must call a search tool first** to get the latest information. **Never answer from memory.**\n\n",
+	"### 数据查询工具\n": "### Data tools\n",
+	"### 其他工具\n":   "### Other tools\n",
+	"### 工具调用原则\n": "### Tool calling principles\n",
+	"1. 需要实时数据时，必须调用工具，不要编造数据\n": "1. Always call a tool for real-time data; never make data up\n",
+	"2. 搜索类工具优先用于获取最新信息\n":       "2. Prefer search tools for the latest information\n",
+	"3. 工具返回结果后再组织回答\n":          "3. Compose your answer after the tool returns\n",
+	"4. 每个工具结果都带有 citation 引用编号（如 [1]），引用工具数据时在对应句末标注该编号，如“市盈率约25倍[1]”，不要编造编号\n": "4. Every tool result carries a citation number (such as [1]). When using tool data, put that number at the end of the sentence, e.g. \"P/E is about 25x[1]\". Never invent citation numbers\n",
+
+	// 工具说明（注册信息）
+	"获取股票实时行情数据，包括当前价格、涨跌幅、开盘价、最高价、最低价、成交量、换手率、市盈率、市净率、股本等":                "Get real-time stock quotes: price, change, open, high, low, volume, turnover rate, P/E, P/B, share capital and more",
+	"获取股票K线数据（含MA5/10/20、ATR与未回补缺口），支持分时、5/15/30/60分钟线、日线、周线、月线，可选前复权/后复权": "Get stock candlestick data (with MA5/10/20, ATR and unfilled gaps): intraday, 5/15/30/60-minute, daily, weekly and monthly bars, optionally forward/backward adjusted",
+	"获取股票五档盘口数据，包括买卖五档价格和数量，以及委比、大单与疑似撤单":                                  "Get the five-level order book: bid/ask prices and sizes, plus imbalance, large orders and suspected withdrawals",
+	"获取股票最近的逐笔成交明细，包括成交时间、价格、手数和主动买卖方向，可用于分析盘中资金动向":                        "Get recent tick-by-tick trades with time, price, lots and aggressor side, useful for intraday money flow analysis",
+	"获取股票当日分时资金流向，包括分时均价、流入流出金额和滚动净流入，用于判断主力资金动向":                          "Get today's intraday money flow: VWAP, inflow/outflow amounts and rolling net inflow, for judging institutional money direction",
+	"获取最新财经快讯，来源于财联社": "Get the latest financial news flashes from Cailian Press",
+	"获取提及指定股票的近期新闻，聚合东方财富、新浪财经、财联社多个来源并去重，按时间倒序":                                   "Get recent news mentioning a stock, merged and deduplicated from East Money, Sina Finance and Cailian Press, newest first",
+	"获取个股在东财股吧、雪球的近期散户讨论，去重后由AI总结情绪倾向、热议话题和代表观点":                                   "Get recent retail investor discussions from Guba and Xueqiu, summarized by AI into sentiment, hot topics and representative views",
+	"获取个股或全部自选股近期的事件日历，包括财报预约披露日、股东大会、限售解禁、分红除权除息日，用于提示短期催化剂":                      "Get the upcoming event calendar for a stock or the whole watchlist: earnings dates, shareholder meetings, lock-up expiries and ex-dividend dates, to flag short-term catalysts",
+	"获取中国关键宏观经济数据的最新值与近期走势，包括CPI、PPI、PMI、LPR、M1/M2、社融规模增量、GDP":                     "Get the latest values and recent trends of key Chinese macro data: CPI, PPI, PMI, LPR, M1/M2, total social financing and GDP",
+	"生成个股K线图（蜡烛图、MA5/10/20均线、成交量）PNG，返回可直接插入回复的 Markdown 图片链接":                     "Render a stock candlestick chart (candles, MA5/10/20, volume) as PNG and return a Markdown image link for the reply",
+	"在沙箱中确定性地执行数值计算脚本，可注入个股K线数组，用于计算波动率、回撤、收益率、均线等自定义指标":                           "Run numeric scripts deterministically in a sandbox with optional candlestick arrays, to compute volatility, drawdown, returns, moving averages and other custom indicators",
+	"全市场A股条件选股，按估值(PE/PB/股息率)、动量(涨跌幅)、成交(换手率/量比/成交额)、市值、行业筛选并排序":                   "Screen all A-shares by valuation (PE/PB/dividend yield), momentum (change), trading (turnover/volume ratio/amount), market cap and industry, then sort",
+	"在模拟盘提交买卖委托（市价/限价），委托需用户确认后才会报单撮合，不涉及真实交易":                                     "Submit market/limit orders to the paper trading account; orders only execute after user confirmation and never involve real trading",
+	"搜索股票，支持代码、名称、拼音首字母或全拼，结果按匹配度排序":                                               "Search stocks by code, name, pinyin initials or full pinyin, ranked by relevance",
+	"获取个股研报列表，包括券商评级、研究员、预测EPS/PE等信息":                                              "Get broker research reports for a stock: ratings, analysts, EPS/PE forecasts and more",
+	"获取研报正文内容，需要先通过 get_research_report 获取 infoCode":                               "Get the full text of a research report; first get its infoCode from get_research_report",
+	"获取全网舆情热点，支持微博、知乎、B站、百度、抖音、头条等平台的实时热搜榜单，以及雪球、东财股吧、同花顺的个股人气榜":                   "Get trending topics from Weibo, Zhihu, Bilibili, Baidu, Douyin, Toutiao and more, plus stock popularity rankings from Xueqiu, Guba and 10jqka",
+	"扫描全网热搜，将热点标题关联到相关A股股票与行业板块，用于发现舆情驱动的交易标的":                                     "Scan trending topics and map them to related A-share stocks and sectors to find sentiment-driven trading ideas",
+	"获取A股龙虎榜数据，包括上榜股票、净买入金额、买卖金额、上榜原因等信息":                                          "Get the A-share Dragon Tiger list: listed stocks, net buying, buy/sell amounts and listing reasons",
+	"获取个股龙虎榜营业部买卖明细，需要提供股票代码和交易日期":                                                 "Get brokerage branch buy/sell details from the Dragon Tiger list for a stock; requires stock code and trade date",
+	"获取沪深两市涨跌家数与行业板块涨跌排行，包括板块领涨股和主力净流入，用于判断市场整体情绪与主线":                              "Get advancers/decliners in Shanghai and Shenzhen and sector rankings with leading stocks and main-force net inflow, to gauge market sentiment and themes",
+	"获取A股涨停池、跌停池、炸板池及连板梯队，包括连板数、封板时间、炸板次数、封单资金":                                    "Get A-share limit-up, limit-down and broken-limit pools and consecutive limit-up ladders, with streak count, seal time, break count and sealing funds",
+	"在受限的本机 Python 子进程中执行代码片段（禁止联网、子进程与工作目录外的文件访问），可挂载个股K线CSV，返回输出与图表，用于统计分析与量化计算": "Run Python snippets in a restricted local subprocess (no network, subprocesses or file access outside the working directory) with optional candlestick CSV, returning output and charts for statistics and quantitative analysis",
+
+	// 工具说明（模型声明）
+	"获取股票实时行情数据，包括当前价格、涨跌幅、开盘价、最高价、最低价、成交量、换手率、市盈率、市净率、股本等，以及大盘指数数据":    "Get real-time stock quotes (price, change, open, high, low, volume, turnover rate, P/E, P/B, share capital and more) as well as major index data",
+	"获取股票五档盘口数据，显示买卖五档的价格和挂单量，并给出委比、大单与疑似撤单":                            "Get the five-level order book showing bid/ask prices and resting sizes, with imbalance, large orders and suspected withdrawals",
+	"获取股票最近的逐笔成交明细（时间、价格、手数、主动买卖方向）及主动买卖统计":                             "Get recent tick-by-tick trades (time, price, lots, aggressor side) with aggressive buy/sell statistics",
+	"获取股票当日分时资金流向：分时均价(VWAP)、流入流出金额、近5/15/30分钟净流入及每30分钟分段净流入":           "Get today's intraday money flow: VWAP, inflow/outflow amounts, net inflow over the last 5/15/30 minutes and per 30-minute segment",
+	"生成个股K线图（蜡烛图、MA5/10/20均线、成交量）PNG，返回可直接插入回复的 Markdown 图片链接，让分析结论更直观": "Render a stock candlestick chart (candles, MA5/10/20, volume) as PNG and return a Markdown image link to make the analysis more visual",
+	"在沙箱中确定性地执行数值计算脚本，可注入个股K线数组，用于计算波动率、回撤、收益率、均线等自定义指标，避免在文字中手算。":      "Run numeric scripts deterministically in a sandbox with optional candlestick arrays, to compute volatility, drawdown, returns, moving averages and other custom indicators instead of calculating by hand. ",
+	"示例: %s。内置函数: %s": "Example: %s. Built-in functions: %s",
+	"全市场A股条件选股，按估值(PE/PB/股息率)、动量(当日/60日/年初至今涨跌幅)、成交(换手率/量比/成交额)、市值、行业筛选并排序，用于把「低PE高股息的银行股」这类需求转成筛选条件执行": "Screen all A-shares by valuation (PE/PB/dividend yield), momentum (daily/60-day/YTD change), trading (turnover/volume ratio/amount), market cap and industry, then sort; turns requests like \"low-PE high-dividend bank stocks\" into screening conditions",
+	"在模拟盘提交买卖委托（市价/限价），用于按分析结论进行模拟交易验证；委托不会立即执行，需用户确认后才报单撮合，不涉及真实资金":                                    "Submit market/limit orders to the paper trading account to test conclusions; orders do not execute immediately, require user confirmation and never use real money",
+	"搜索股票，支持按代码、名称、拼音首字母或全拼搜索，结果按匹配度排序":                                                                 "Search stocks by code, name, pinyin initials or full pinyin, ranked by relevance",
+	"获取研报正文内容，需要先通过 get_research_report 获取研报列表中的 infoCode":                                              "Get the full text of a research report; first get its infoCode from the get_research_report list",
+	"获取A股龙虎榜数据，包括上榜股票、净买入金额、买卖金额、上榜原因等信息，数据来源于东方财富":                                                     "Get the A-share Dragon Tiger list from East Money: listed stocks, net buying, buy/sell amounts and listing reasons",
+	"获取A股涨停池、跌停池、炸板池及连板梯队，包括连板数、封板时间、炸板次数、封单资金，适合短线情绪分析":                                                "Get A-share limit-up, limit-down and broken-limit pools and consecutive limit-up ladders, with streak count, seal time, break count and sealing funds; suited to short-term sentiment analysis",
+	"在受限的本机 Python 子进程中执行代码片段，可挂载个股K线CSV，返回输出与图表，用于统计分析与量化计算。脚本只能读写工作目录中的文件，不能联网或启动子进程，单次执行有超时限制":       "Run Python snippets in a restricted local subprocess with optional candlestick CSV, returning output and charts for statistics and quantitative analysis. Scripts can only read and write files in the working directory, cannot access the network or start subprocesses, and each run has a timeout",
+
+	// 数据格式
+	"解禁市值约%s元":                         "Unlock value approx. %s CNY",
+	"%d. %s %.2f%% 涨%d/跌%d 主力净流入%s":    "%d. %s %.2f%% up %d/down %d, main-force net inflow %s",
+	" 领涨:%s(%.2f%%)":                   " leader: %s(%.2f%%)",
+	"   净买:%s 买入:%s 卖出:%s 占比:%.2f%%\n": "   net buy: %s buy: %s sell: %s share: %.2f%%\n",
+	"结果约 %d tokens，超出上限 %d，已由 AI 摘要，细节可能缺失，需要时请缩小查询范围":                        "Result was about %d tokens, over the %d limit; condensed by AI summary, details may be missing. Narrow the query if needed",
+	"结果约 %d tokens，超出上限 %d，已截断，需要时请缩小查询范围":                                    "Result was about %d tokens, over the %d limit; truncated. Narrow the query if needed",
+	"以下是数据工具 %s 返回的结果，请压缩到 %d 字以内：保留关键数字、日期、代码、名称与结论，删除重复和无关内容，直接输出压缩结果。\n\n": "Below is the result returned by data tool %s. Condense it to under %d words: keep key numbers, dates, codes, names and conclusions, drop duplicates and irrelevant content, and output only the condensed result.\n\n",
+	"统一行情数据查询，通过 action 选择数据类型：":                                              "Unified market data query; choose the data type with action: ",
+	"实时行情与估值":         "realtime quotes and valuation",
+	"K线与均线":           "K-line and moving averages",
+	"五档盘口":            "level-2 order book",
+	"逐笔成交":            "tick trades",
+	"当日资金流向":          "intraday money flow",
+	"财经快讯":            "financial flash news",
+	"个股新闻":            "stock news",
+	"散户情绪":            "retail sentiment",
+	"事件日历":            "event calendar",
+	"研报列表":            "research reports",
+	"涨跌家数与板块排行":       "advance/decline and sector ranking",
+	"涨跌停池":            "limit-up/down pools",
+	"不支持的 action: %s": "Unsupported action: %s",
+	"数据工具 %s 已禁用":     "Data tool %s is disabled",
+}
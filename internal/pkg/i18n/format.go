@@ -0,0 +1,31 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+)
+
+// amountUnit 金额单位
+type amountUnit struct {
+	value  float64
+	suffix string
+}
+
+var amountUnits = map[string][]amountUnit{
+	ZhCN: {{1e8, "亿"}, {1e4, "万"}},
+	EnUS: {{1e9, "B"}, {1e6, "M"}, {1e3, "K"}},
+}
+
+// Amount 按当前语言的习惯格式化金额（元）：中文用万/亿，英文用 K/M/B，保留两位小数
+func Amount(v float64) string {
+	units, ok := amountUnits[Language()]
+	if !ok {
+		units = amountUnits[ZhCN]
+	}
+	for _, u := range units {
+		if math.Abs(v) >= u.value {
+			return fmt.Sprintf("%.2f%s", v/u.value, u.suffix)
+		}
+	}
+	return fmt.Sprintf("%.2f", v)
+}
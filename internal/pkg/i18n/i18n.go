@@ -0,0 +1,84 @@
+// Package i18n 提供后端文案（错误信息、提示词骨架、工具说明）的多语言支持与数据格式化
+// 以中文原文作为翻译键，未收录译文的文案原样返回，因此新增文案无需同步登记
+package i18n
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// 支持的语言
+const (
+	ZhCN = "zh-CN" // 简体中文（默认）
+	EnUS = "en-US" // 英文
+)
+
+// catalogs 各语言的译文，键为中文原文
+var catalogs = map[string]map[string]string{
+	EnUS: enUS,
+}
+
+var current atomic.Value
+
+func init() {
+	current.Store(ZhCN)
+}
+
+// Supported 是否为支持的语言，空字符串视为默认语言
+func Supported(lang string) bool {
+	return lang == "" || lang == ZhCN || lang == EnUS
+}
+
+// SetLanguage 设置当前语言，不支持的语言回退为简体中文
+func SetLanguage(lang string) {
+	if lang == "" || !Supported(lang) {
+		lang = ZhCN
+	}
+	current.Store(lang)
+}
+
+// Language 获取当前语言
+func Language() string {
+	return current.Load().(string)
+}
+
+// T 翻译文案，当前语言没有译文时返回原文
+func T(msg string) string {
+	if tr, ok := catalogs[Language()][msg]; ok {
+		return tr
+	}
+	return msg
+}
+
+// Sprintf 翻译格式串后格式化，参数本身不翻译
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Errorf 翻译格式串后构造错误，支持 %w 包装
+func Errorf(format string, args ...any) error {
+	return fmt.Errorf(T(format), args...)
+}
+
+// localizedError 在取错误信息时才翻译，用于包级别的哨兵错误（errors.Is 仍按实例比较）
+type localizedError struct {
+	msg string
+}
+
+func (e *localizedError) Error() string {
+	return T(e.msg)
+}
+
+// NewError 创建随当前语言翻译的错误
+func NewError(msg string) error {
+	return &localizedError{msg: msg}
+}
+
+// ReplyInstruction 追加到提示词末尾的回复语言要求，简体中文时为空
+// 结构化标记（如【观点】、总结小节标题、JSON 字段）由程序解析，要求保持原样
+func ReplyInstruction() string {
+	if Language() == ZhCN {
+		return ""
+	}
+	return T("\n\n## 回复语言\n请使用简体中文回复。")
+}
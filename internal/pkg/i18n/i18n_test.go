@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestTranslate 测试按当前语言翻译文案与错误
+func TestTranslate(t *testing.T) {
+	defer SetLanguage(ZhCN)
+	errTimeout := NewError("会议超时，已返回部分结果")
+
+	SetLanguage("")
+	if Language() != ZhCN || T("会话不存在") != "会话不存在" || ReplyInstruction() != "" {
+		t.Errorf("default language should keep original text")
+	}
+
+	SetLanguage(EnUS)
+	if got := T("会话不存在"); got != "Session not found" {
+		t.Errorf("T = %q", got)
+	}
+	if got := T("未收录的文案"); got != "未收录的文案" {
+		t.Errorf("untranslated text should pass through: %q", got)
+	}
+	if got := Sprintf("你是一位%s，名字是%s。", "analyst", "Bob"); got != "You are a analyst named Bob." {
+		t.Errorf("Sprintf = %q", got)
+	}
+	wrapped := fmt.Errorf("run: %w", errTimeout)
+	if !errors.Is(wrapped, errTimeout) || wrapped.Error() != "run: Meeting timed out, partial results returned" {
+		t.Errorf("localized error: %v", wrapped)
+	}
+	if ReplyInstruction() == "" {
+		t.Error("english should require english replies")
+	}
+
+	SetLanguage("fr-FR")
+	if Language() != ZhCN || Supported("fr-FR") {
+		t.Errorf("unsupported language should fall back to %s", ZhCN)
+	}
+}
+
+// TestAmount 测试金额按语言习惯格式化
+func TestAmount(t *testing.T) {
+	defer SetLanguage(ZhCN)
+	cases := []struct {
+		lang string
+		v    float64
+		want string
+	}{
+		{ZhCN, 1.234e9, "12.34亿"},
+		{ZhCN, -56780, "-5.68万"},
+		{ZhCN, 999, "999.00"},
+		{EnUS, 1.234e9, "1.23B"},
+		{EnUS, -56780, "-56.78K"},
+		{EnUS, 2.5e6, "2.50M"},
+	}
+	for _, c := range cases {
+		SetLanguage(c.lang)
+		if got := Amount(c.v); got != c.want {
+			t.Errorf("%s Amount(%v) = %q, want %q", c.lang, c.v, got, c.want)
+		}
+	}
+}
@@ -1,12 +1,28 @@
 package paths
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
 )
 
-// GetDataDir 获取应用数据目录
-func GetDataDir() string {
+// DefaultProfile 默认工作区，数据直接存放在数据根目录（兼容旧版本）
+const DefaultProfile = "default"
+
+// activeProfileFile 记录界面中选择的工作区，下次启动时使用
+const activeProfileFile = "active_profile"
+
+var (
+	profileMu sync.RWMutex
+	profile   = DefaultProfile
+)
+
+// GetRootDir 获取数据根目录（所有工作区共用）
+func GetRootDir() string {
 	userConfigDir, err := os.UserConfigDir()
 	if err != nil || userConfigDir == "" {
 		return filepath.Join(".", "data")
@@ -14,9 +30,109 @@ func GetDataDir() string {
 	return filepath.Join(userConfigDir, "jcp")
 }
 
-// GetCacheDir 获取缓存目录
+// GetDataDir 获取当前工作区的数据目录
+func GetDataDir() string {
+	return ProfileDir(GetProfile())
+}
+
+// ProfileDir 获取指定工作区的数据目录
+func ProfileDir(name string) string {
+	if name == "" || name == DefaultProfile {
+		return GetRootDir()
+	}
+	return filepath.Join(GetRootDir(), "profiles", name)
+}
+
+// GetProfile 获取当前工作区名称
+func GetProfile() string {
+	profileMu.RLock()
+	defer profileMu.RUnlock()
+	return profile
+}
+
+// SetProfile 设置当前进程使用的工作区，需在初始化各服务前调用
+func SetProfile(name string) error {
+	if name == "" {
+		name = DefaultProfile
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	profileMu.Lock()
+	profile = name
+	profileMu.Unlock()
+	return nil
+}
+
+// ValidateProfileName 校验工作区名称：仅允许文字、数字、- 和 _
+func ValidateProfileName(name string) error {
+	if name == "" || len([]rune(name)) > 32 {
+		return fmt.Errorf("工作区名称长度应为 1-32 个字符")
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return fmt.Errorf("工作区名称只能包含文字、数字、- 和 _: %s", name)
+		}
+	}
+	return nil
+}
+
+// ListProfiles 列出已有的工作区（默认工作区始终在首位）
+func ListProfiles() []string {
+	profiles := []string{DefaultProfile}
+	entries, err := os.ReadDir(filepath.Join(GetRootDir(), "profiles"))
+	if err != nil {
+		return profiles
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != DefaultProfile && ValidateProfileName(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return append(profiles, names...)
+}
+
+// ResolveProfile 确定启动时使用的工作区：指定名称 > JCP_PROFILE 环境变量 > 上次选择的工作区
+func ResolveProfile(name string) string {
+	if name == "" {
+		name = os.Getenv("JCP_PROFILE")
+	}
+	if name == "" {
+		name = LoadActiveProfile()
+	}
+	return name
+}
+
+// LoadActiveProfile 读取上次选择的工作区，无记录或名称无效时返回默认工作区
+func LoadActiveProfile() string {
+	data, err := os.ReadFile(filepath.Join(GetRootDir(), activeProfileFile))
+	if err != nil {
+		return DefaultProfile
+	}
+	name := strings.TrimSpace(string(data))
+	if ValidateProfileName(name) != nil {
+		return DefaultProfile
+	}
+	return name
+}
+
+// SaveActiveProfile 记录选择的工作区，下次启动时生效
+func SaveActiveProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	root := GetRootDir()
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(root, activeProfileFile), []byte(name), 0644)
+}
+
+// GetCacheDir 获取缓存目录（行情、日历等公共缓存，各工作区共用）
 func GetCacheDir() string {
-	return filepath.Join(GetDataDir(), "cache")
+	return filepath.Join(GetRootDir(), "cache")
 }
 
 // EnsureCacheDir 确保缓存目录存在并返回路径
@@ -0,0 +1,58 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("JCP_PROFILE", "")
+	defer SetProfile(DefaultProfile)
+
+	root := GetRootDir()
+	if GetDataDir() != root {
+		t.Fatalf("default profile should use root dir: %s", GetDataDir())
+	}
+
+	for _, name := range []string{"", "../real", "a/b", "实盘 账户"} {
+		if ValidateProfileName(name) == nil {
+			t.Errorf("name %q should be invalid", name)
+		}
+	}
+	if err := SetProfile("模拟盘"); err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "profiles", "模拟盘"); GetDataDir() != want {
+		t.Fatalf("data dir: %s, want %s", GetDataDir(), want)
+	}
+	if GetCacheDir() != filepath.Join(root, "cache") {
+		t.Errorf("cache dir should be shared: %s", GetCacheDir())
+	}
+
+	os.MkdirAll(ProfileDir("paper"), 0755)
+	os.MkdirAll(ProfileDir("demo"), 0755)
+	if got := ListProfiles(); !slices.Equal(got, []string{DefaultProfile, "demo", "paper"}) {
+		t.Errorf("profiles: %v", got)
+	}
+
+	if got := ResolveProfile(""); got != DefaultProfile {
+		t.Errorf("resolve without selection: %s", got)
+	}
+	if err := SaveActiveProfile("paper"); err != nil {
+		t.Fatal(err)
+	}
+	if got := ResolveProfile(""); got != "paper" {
+		t.Errorf("resolve saved profile: %s", got)
+	}
+	t.Setenv("JCP_PROFILE", "demo")
+	if got := ResolveProfile(""); got != "demo" {
+		t.Errorf("resolve env profile: %s", got)
+	}
+	if got := ResolveProfile("real"); got != "real" {
+		t.Errorf("resolve explicit profile: %s", got)
+	}
+}
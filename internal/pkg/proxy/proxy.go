@@ -1,5 +1,6 @@
 // Package proxy 提供应用级别的代理管理
-// 支持三种模式：无代理、系统代理、自定义代理
+// 支持三种模式：无代理、系统代理、自定义代理（HTTP/HTTPS/SOCKS5）
+// AI 配置可单独指定代理，未指定时跟随全局代理
 package proxy
 
 import (
@@ -21,6 +22,7 @@ type Manager struct {
 	config    *models.ProxyConfig
 	transport *http.Transport
 	client    *http.Client
+	override  http.RoundTripper // 替换全局 Transport（测试回放上游响应），为 nil 时不生效
 }
 
 var (
@@ -35,6 +37,10 @@ func GetManager() *Manager {
 			config: &models.ProxyConfig{Mode: models.ProxyModeNone},
 		}
 		instance.rebuildTransport()
+		instance.client = &http.Client{
+			Transport: globalTransport{instance},
+			Timeout:   30 * time.Second,
+		}
 	})
 	return instance
 }
@@ -45,7 +51,9 @@ func (m *Manager) SetConfig(cfg *models.ProxyConfig) {
 	defer m.mu.Unlock()
 
 	m.config = cfg
+	old := m.transport
 	m.rebuildTransport()
+	old.CloseIdleConnections()
 }
 
 // GetConfig 获取当前代理配置
@@ -62,24 +70,76 @@ func (m *Manager) GetTransport() *http.Transport {
 	return m.transport.Clone()
 }
 
-// GetClient 获取配置好代理的 HTTP Client
+// SetRoundTripper 替换全局代理 Client 的底层传输，返回恢复函数
+// 用于测试中回放录制的上游响应；按代理配置单独创建的 Client 不受影响
+func (m *Manager) SetRoundTripper(rt http.RoundTripper) (restore func()) {
+	m.mu.Lock()
+	prev := m.override
+	m.override = rt
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		m.override = prev
+		m.mu.Unlock()
+	}
+}
+
+// GetClient 获取使用全局代理的 HTTP Client，代理设置变更后立即生效
 func (m *Manager) GetClient() *http.Client {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
 	return m.client
 }
 
-// GetClientWithTimeout 获取带自定义超时的 HTTP Client
+// GetClientWithTimeout 获取使用全局代理、带自定义超时的 HTTP Client，代理设置变更后立即生效
+// 行情、资讯等服务在启动时创建 Client 并长期持有，因此不能绑定创建时的 Transport
 func (m *Manager) GetClientWithTimeout(timeout time.Duration) *http.Client {
 	return &http.Client{
-		Transport: m.GetTransport(),
+		Transport: globalTransport{m},
+		Timeout:   timeout,
+	}
+}
+
+// TransportFor 获取指定代理配置的 Transport，配置为空或跟随全局时返回全局代理 Transport
+func (m *Manager) TransportFor(cfg *models.ProxyConfig) *http.Transport {
+	if cfg == nil || cfg.Mode == models.ProxyModeGlobal {
+		return m.GetTransport()
+	}
+	return m.newTransport(cfg)
+}
+
+// ClientFor 获取指定代理配置、带自定义超时的 HTTP Client（timeout 为 0 表示不限制）
+func (m *Manager) ClientFor(cfg *models.ProxyConfig, timeout time.Duration) *http.Client {
+	if cfg == nil || cfg.Mode == models.ProxyModeGlobal {
+		return m.GetClientWithTimeout(timeout)
+	}
+	return &http.Client{
+		Transport: m.newTransport(cfg),
 		Timeout:   timeout,
 	}
 }
 
+// globalTransport 每次请求都使用当前的全局代理 Transport
+type globalTransport struct {
+	m *Manager
+}
+
+func (t globalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.m.mu.RLock()
+	var transport http.RoundTripper = t.m.transport
+	if t.m.override != nil {
+		transport = t.m.override
+	}
+	t.m.mu.RUnlock()
+	return transport.RoundTrip(req)
+}
+
 // rebuildTransport 根据当前配置重建 Transport
 func (m *Manager) rebuildTransport() {
-	m.transport = &http.Transport{
+	m.transport = m.newTransport(m.config)
+}
+
+// newTransport 按代理配置创建 Transport，自定义代理支持 http(s):// 与 socks5(h):// 地址
+func (m *Manager) newTransport(cfg *models.ProxyConfig) *http.Transport {
+	transport := &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   30 * time.Second,
 			KeepAlive: 30 * time.Second,
@@ -91,25 +151,21 @@ func (m *Manager) rebuildTransport() {
 		ExpectContinueTimeout: 1 * time.Second,
 	}
 
-	switch m.config.Mode {
+	switch cfg.Mode {
 	case models.ProxyModeNone:
-		m.transport.Proxy = nil
+		transport.Proxy = nil
 
 	case models.ProxyModeSystem:
-		m.transport.Proxy = m.systemProxyFunc
+		transport.Proxy = m.systemProxyFunc
 
 	case models.ProxyModeCustom:
-		if m.config.CustomURL != "" {
-			if proxyURL, err := url.Parse(m.config.CustomURL); err == nil {
-				m.transport.Proxy = http.ProxyURL(proxyURL)
+		if cfg.CustomURL != "" {
+			if proxyURL, err := url.Parse(cfg.CustomURL); err == nil {
+				transport.Proxy = http.ProxyURL(proxyURL)
 			}
 		}
 	}
-
-	m.client = &http.Client{
-		Transport: m.transport,
-		Timeout:   30 * time.Second,
-	}
+	return transport
 }
 
 // systemProxyFunc 获取系统代理（作为 Transport.Proxy 函数）
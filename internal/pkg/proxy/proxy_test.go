@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// TestGlobalClientFollowsConfig 测试启动时创建的 Client 在全局代理变更后立即走新代理，AI 配置可单独直连
+func TestGlobalClientFollowsConfig(t *testing.T) {
+	m := GetManager()
+	defer m.SetConfig(&models.ProxyConfig{Mode: models.ProxyModeNone})
+
+	var proxied []string
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		io.WriteString(w, "via proxy")
+	}))
+	defer proxySrv.Close()
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "direct")
+	}))
+	defer target.Close()
+
+	m.SetConfig(&models.ProxyConfig{Mode: models.ProxyModeNone})
+	client := m.GetClientWithTimeout(5 * time.Second)
+	m.SetConfig(&models.ProxyConfig{Mode: models.ProxyModeCustom, CustomURL: proxySrv.URL})
+
+	get := func(c *http.Client) string {
+		resp, err := c.Get(target.URL + "/quote")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	if got := get(client); got != "via proxy" || len(proxied) != 1 || proxied[0] != target.URL+"/quote" {
+		t.Errorf("global client should use new proxy: %q %v", got, proxied)
+	}
+	if got := get(m.ClientFor(&models.ProxyConfig{Mode: models.ProxyModeGlobal}, 0)); got != "via proxy" {
+		t.Errorf("global mode should inherit proxy: %q", got)
+	}
+	if got := get(m.ClientFor(&models.ProxyConfig{Mode: models.ProxyModeNone}, 0)); got != "direct" {
+		t.Errorf("per-config none should bypass proxy: %q", got)
+	}
+}
+
+// TestTransportForSOCKS5 测试自定义 SOCKS5 代理地址
+func TestTransportForSOCKS5(t *testing.T) {
+	transport := GetManager().TransportFor(&models.ProxyConfig{Mode: models.ProxyModeCustom, CustomURL: "socks5://127.0.0.1:1080"})
+	req, _ := http.NewRequest(http.MethodGet, "https://api.openai.com/v1/models", nil)
+	u, err := transport.Proxy(req)
+	if err != nil || u == nil || u.String() != "socks5://127.0.0.1:1080" {
+		t.Errorf("proxy = %v, %v", u, err)
+	}
+}
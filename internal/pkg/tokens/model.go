@@ -0,0 +1,203 @@
+package tokens
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 按模型区分口径的估算同样是启发式的，不是真正的 BPE 分词：
+// OpenAI 系列模仿 tiktoken（cl100k_base / o200k_base）的预分词规则切分英文、数字与标点，
+// 中文按各分词器实测的每字平均 token 数折算，不加载词表；无法识别的模型沿用 Estimate 的口径
+// 结果只用于预算与预估，与服务端实际计费可能有偏差
+
+// Encoding 分词器类型
+type Encoding string
+
+const (
+	EncodingCL100K    Encoding = "cl100k_base" // GPT-4 / GPT-3.5
+	EncodingO200K     Encoding = "o200k_base"  // GPT-4o / GPT-4.1 / GPT-5 / o 系列
+	EncodingClaude    Encoding = "claude"
+	EncodingGemini    Encoding = "gemini"    // SentencePiece 大词表
+	EncodingCJK       Encoding = "cjk"       // DeepSeek、通义千问、智谱等针对中文优化的词表
+	EncodingHeuristic Encoding = "heuristic" // 中文每字 1 个，其余每 4 个字符 1 个
+)
+
+// hanRates 各分词器下每个汉字的平均 token 数
+var hanRates = map[Encoding]float64{
+	EncodingCL100K: 1.2,
+	EncodingO200K:  0.8,
+	EncodingClaude: 1.2,
+	EncodingGemini: 0.7,
+	EncodingCJK:    0.6,
+}
+
+// Counter 按某种分词器口径估算 token 数
+type Counter struct {
+	Encoding Encoding
+}
+
+// Default 不区分模型的粗略估算
+var Default = Counter{Encoding: EncodingHeuristic}
+
+// modelEncodings 按模型名前缀识别分词器（越长越优先），OpenAI 兼容网关上的其他厂商模型同样适用
+var modelEncodings = map[string]Encoding{
+	"gpt-4o":     EncodingO200K,
+	"chatgpt-4o": EncodingO200K,
+	"gpt-4.1":    EncodingO200K,
+	"gpt-4.5":    EncodingO200K,
+	"gpt-5":      EncodingO200K,
+	"o1":         EncodingO200K,
+	"o3":         EncodingO200K,
+	"o4":         EncodingO200K,
+	"gpt-oss":    EncodingO200K,
+	"gpt-4":      EncodingCL100K,
+	"gpt-3.5":    EncodingCL100K,
+	"claude":     EncodingClaude,
+	"gemini":     EncodingGemini,
+	"gemma":      EncodingGemini,
+	"deepseek":   EncodingCJK,
+	"qwen":       EncodingCJK,
+	"qwq":        EncodingCJK,
+	"glm":        EncodingCJK,
+	"moonshot":   EncodingCJK,
+	"kimi":       EncodingCJK,
+	"doubao":     EncodingCJK,
+	"ernie":      EncodingCJK,
+	"hunyuan":    EncodingCJK,
+	"minimax":    EncodingCJK,
+	"abab":       EncodingCJK,
+	"yi-":        EncodingCJK,
+	"baichuan":   EncodingCJK,
+	"internlm":   EncodingCJK,
+	"step-":      EncodingCJK,
+	"spark":      EncodingCJK,
+	"mock":       EncodingHeuristic,
+}
+
+// providerEncodings 模型名无法识别时按服务商兜底
+var providerEncodings = map[string]Encoding{
+	"gemini":    EncodingGemini,
+	"vertexai":  EncodingGemini,
+	"anthropic": EncodingClaude,
+}
+
+// ForModel 按服务商与模型名选择分词器，模型名优先（如 OpenAI 兼容网关上的 Claude、Gemini）
+func ForModel(provider, modelName string) Counter {
+	name := strings.ToLower(modelName)
+	// 去掉网关常见的厂商前缀，如 openai/gpt-4o、deepseek-ai/DeepSeek-V3
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	best, bestLen := Encoding(""), 0
+	for prefix, enc := range modelEncodings {
+		if strings.HasPrefix(name, prefix) && len(prefix) > bestLen {
+			best, bestLen = enc, len(prefix)
+		}
+	}
+	if best == "" {
+		best = providerEncodings[provider]
+	}
+	if best == "" {
+		return Default
+	}
+	return Counter{Encoding: best}
+}
+
+// Count 估算文本的 token 数
+func (c Counter) Count(text string) int {
+	rate, ok := hanRates[c.Encoding]
+	if !ok {
+		return Estimate(text)
+	}
+
+	han, n := 0, 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+			i += size
+		case unicode.IsLetter(r):
+			// 连续字母为一个词，常见词一个 token，长词约每 6 个字母一个
+			length := 0
+			for i < len(text) {
+				r, size := utf8.DecodeRuneInString(text[i:])
+				if !unicode.IsLetter(r) || unicode.Is(unicode.Han, r) {
+					break
+				}
+				length++
+				i += size
+			}
+			n += max(1, (length+4)/6)
+		case unicode.IsDigit(r):
+			// tiktoken 将数字按最多 3 位切分
+			length := 0
+			for i < len(text) {
+				r, size := utf8.DecodeRuneInString(text[i:])
+				if !unicode.IsDigit(r) {
+					break
+				}
+				length++
+				i += size
+			}
+			n += (length + 2) / 3
+		case unicode.IsSpace(r):
+			// 单个空格并入后面的词，其余连续空白计 1 个
+			start := i
+			for i < len(text) {
+				r, size := utf8.DecodeRuneInString(text[i:])
+				if !unicode.IsSpace(r) {
+					break
+				}
+				i += size
+			}
+			next, _ := utf8.DecodeRuneInString(text[i:])
+			if text[start:i] == " " && i < len(text) && (unicode.IsLetter(next) || unicode.IsDigit(next)) && !unicode.Is(unicode.Han, next) {
+				continue
+			}
+			n++
+		case r < utf8.RuneSelf:
+			// 连续 ASCII 标点约每 2 个一个 token
+			length := 0
+			for i < len(text) && text[i] < utf8.RuneSelf && isPunct(rune(text[i])) {
+				length++
+				i++
+			}
+			n += (length + 1) / 2
+		default:
+			// 中文标点、符号与表情
+			n++
+			i += size
+		}
+	}
+	return n + int(math.Ceil(float64(han)*rate))
+}
+
+func isPunct(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+}
+
+// Truncate 将文本截断到约 maxTokens 个 token，截断时以省略号结尾
+// maxTokens <= 0 表示不限制
+func (c Counter) Truncate(text string, maxTokens int) string {
+	if _, ok := hanRates[c.Encoding]; !ok {
+		return Truncate(text, maxTokens)
+	}
+	if maxTokens <= 0 || c.Count(text) <= maxTokens {
+		return text
+	}
+	// 按字符二分查找最长前缀，为省略号预留 1 个 token
+	runes := []rune(text)
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if c.Count(string(runes[:mid])) <= maxTokens-1 {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return string(runes[:lo]) + "…"
+}
@@ -0,0 +1,60 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestForModel(t *testing.T) {
+	cases := []struct {
+		provider, model string
+		want            Encoding
+	}{
+		{"openai", "gpt-4o-mini", EncodingO200K},
+		{"openai", "gpt-4-turbo", EncodingCL100K},
+		{"openai", "openai/gpt-4.1", EncodingO200K},
+		{"openai", "deepseek-ai/DeepSeek-V3", EncodingCJK},
+		{"openai", "claude-3-5-sonnet", EncodingClaude},
+		{"anthropic", "my-proxy-model", EncodingClaude},
+		{"vertexai", "custom", EncodingGemini},
+		{"openai", "unknown-model", EncodingHeuristic},
+	}
+	for _, c := range cases {
+		if got := ForModel(c.provider, c.model).Encoding; got != c.want {
+			t.Errorf("ForModel(%q, %q) = %q, want %q", c.provider, c.model, got, c.want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	o200k := Counter{Encoding: EncodingO200K}
+	// tiktoken 对该句的结果为 10
+	if got := o200k.Count("The quick brown fox jumps over the lazy dog."); got != 10 {
+		t.Errorf("english sentence = %d, want 10", got)
+	}
+	// 数字按 3 位切分：150 | 5
+	if got := o200k.Count("1505"); got != 2 {
+		t.Errorf("digits = %d, want 2", got)
+	}
+
+	text := "贵州茅台2024年营收1505亿元，同比增长15.7%。"
+	cl100k := Counter{Encoding: EncodingCL100K}
+	cjk := Counter{Encoding: EncodingCJK}
+	if !(cjk.Count(text) < o200k.Count(text) && o200k.Count(text) < cl100k.Count(text)) {
+		t.Errorf("chinese counts not ordered: cjk=%d o200k=%d cl100k=%d", cjk.Count(text), o200k.Count(text), cl100k.Count(text))
+	}
+	if got, want := Default.Count(text), Estimate(text); got != want {
+		t.Errorf("Default = %d, want Estimate %d", got, want)
+	}
+}
+
+func TestCounterTruncate(t *testing.T) {
+	c := Counter{Encoding: EncodingO200K}
+	if got := c.Truncate("短文本", 10); got != "短文本" {
+		t.Errorf("short text changed: %q", got)
+	}
+	got := c.Truncate(strings.Repeat("茅台 price ", 100), 30)
+	if !strings.HasSuffix(got, "…") || c.Count(got) > 30 {
+		t.Errorf("Truncate = %q (%d tokens)", got, c.Count(got))
+	}
+}
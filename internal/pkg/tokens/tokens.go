@@ -0,0 +1,39 @@
+// Package tokens 提供不依赖分词器词表的 token 启发式估算与按预算截断
+// Estimate 不区分模型，Counter 按模型的分词器类型调整口径，二者都是估算值
+package tokens
+
+import "unicode"
+
+// Estimate 粗略估算 token 数：中文按每字 1 个，其余按每 4 个字符 1 个
+func Estimate(text string) int {
+	cjk, other := 0, 0
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// Truncate 按 Estimate 的口径将文本截断到约 maxTokens 个 token，截断时以省略号结尾
+// maxTokens <= 0 表示不限制
+func Truncate(text string, maxTokens int) string {
+	if maxTokens <= 0 || Estimate(text) <= maxTokens {
+		return text
+	}
+	// 以 1/4 token 为单位累计，为省略号预留 1 个 token
+	limit, used := (maxTokens-1)*4, 0
+	for i, r := range text {
+		cost := 1
+		if unicode.Is(unicode.Han, r) {
+			cost = 4
+		}
+		if used+cost > limit {
+			return text[:i] + "…"
+		}
+		used += cost
+	}
+	return text
+}
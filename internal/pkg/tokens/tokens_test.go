@@ -0,0 +1,39 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	cases := map[string]int{
+		"":         0,
+		"贵州茅台":     4,
+		"abcd":     1,
+		"abcde":    2,
+		"茅台 price": 4,
+	}
+	for text, want := range cases {
+		if got := Estimate(text); got != want {
+			t.Errorf("Estimate(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("短文本", 10); got != "短文本" {
+		t.Errorf("short text changed: %q", got)
+	}
+	if got := Truncate(strings.Repeat("长", 100), 0); len([]rune(got)) != 100 {
+		t.Errorf("maxTokens 0 should not truncate")
+	}
+
+	got := Truncate(strings.Repeat("长", 100), 10)
+	if !strings.HasSuffix(got, "…") || Estimate(got) > 10 {
+		t.Errorf("Truncate = %q (%d tokens)", got, Estimate(got))
+	}
+	mixed := Truncate(strings.Repeat("ab茅台", 50), 20)
+	if Estimate(mixed) > 20 {
+		t.Errorf("mixed text over budget: %q (%d tokens)", mixed, Estimate(mixed))
+	}
+}
@@ -0,0 +1,297 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+)
+
+// magic 加密文件头，没有该文件头的文件按明文读取（兼容启用加密前的数据）
+const magic = "JCPENC1\n"
+
+// keyFileName 密钥参数文件（盐值与校验值，不含密钥本身）
+const keyFileName = "encryption.json"
+
+// checkText 用于校验密码是否正确的固定明文
+const checkText = "jcp-vault-check"
+
+// pbkdf2Iterations PBKDF2-SHA256 迭代次数
+const pbkdf2Iterations = 600000
+
+var (
+	// ErrLocked 数据已加密但尚未解锁
+	ErrLocked = i18n.NewError("数据已加密，请先输入密码解锁")
+	// ErrWrongPassphrase 密码错误
+	ErrWrongPassphrase = i18n.NewError("密码错误")
+)
+
+// keyParams 密钥派生参数
+type keyParams struct {
+	Salt       []byte `json:"salt"`
+	Iterations int    `json:"iterations"`
+	Check      []byte `json:"check"` // 用派生密钥加密 checkText 的结果
+}
+
+// Vault 数据目录加密：口令经 PBKDF2 派生 AES-256-GCM 密钥，加密会话、记忆、持仓等敏感文件
+type Vault struct {
+	mu      sync.RWMutex
+	keyPath string
+	params  *keyParams // nil 表示未启用加密
+	key     []byte     // nil 表示未解锁
+}
+
+var (
+	defaultMu    sync.RWMutex
+	defaultVault = &Vault{}
+)
+
+// Init 加载数据目录的加密设置并设为全局默认实例
+func Init(dataDir string) (*Vault, error) {
+	v := &Vault{keyPath: filepath.Join(dataDir, keyFileName)}
+	data, err := os.ReadFile(v.keyPath)
+	switch {
+	case err == nil:
+		var params keyParams
+		if err := json.Unmarshal(data, &params); err != nil {
+			return nil, fmt.Errorf("解析加密设置失败: %w", err)
+		}
+		v.params = &params
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	defaultMu.Lock()
+	defaultVault = v
+	defaultMu.Unlock()
+	return v, nil
+}
+
+// Default 获取全局默认实例（未调用 Init 时不加密，且无法读取已加密文件）
+func Default() *Vault {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultVault
+}
+
+// ReadFile 使用默认实例读取文件
+func ReadFile(path string) ([]byte, error) {
+	return Default().ReadFile(path)
+}
+
+// WriteFile 使用默认实例写入文件
+func WriteFile(path string, data []byte) error {
+	return Default().WriteFile(path, data)
+}
+
+// Enabled 是否已启用加密
+func (v *Vault) Enabled() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.params != nil
+}
+
+// Locked 是否已启用加密但尚未解锁
+func (v *Vault) Locked() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.params != nil && v.key == nil
+}
+
+// Unlock 校验密码并解锁
+func (v *Vault) Unlock(passphrase string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.params == nil {
+		return nil
+	}
+	key, err := v.params.verify(passphrase)
+	if err != nil {
+		return err
+	}
+	v.key = key
+	return nil
+}
+
+// Enable 启用加密，并将 files 中已有的明文文件重新加密写入
+func (v *Vault) Enable(passphrase string, files []string) error {
+	if len([]rune(passphrase)) < 6 {
+		return errors.New("密码至少 6 个字符")
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.params != nil {
+		return errors.New("已启用数据加密")
+	}
+
+	params := &keyParams{Salt: make([]byte, 16), Iterations: pbkdf2Iterations}
+	if _, err := rand.Read(params.Salt); err != nil {
+		return err
+	}
+	key, err := params.derive(passphrase)
+	if err != nil {
+		return err
+	}
+	if params.Check, err = seal(key, []byte(checkText)); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(v.keyPath, data, 0600); err != nil {
+		return err
+	}
+	v.params, v.key = params, key
+
+	// 逐个重写：中途失败时已加密与未加密的文件都可正常读取
+	return v.resealLocked(files)
+}
+
+// Disable 校验密码后关闭加密，并将 files 解密为明文写回
+func (v *Vault) Disable(passphrase string, files []string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.params == nil {
+		return nil
+	}
+	key, err := v.params.verify(passphrase)
+	if err != nil {
+		return err
+	}
+	// 保留密钥直到全部写回明文，期间仍能读取尚未处理的加密文件
+	params := v.params
+	v.key, v.params = key, nil
+	if err := v.resealLocked(files); err != nil {
+		v.params = params
+		return err
+	}
+	v.key = nil
+	return os.Remove(v.keyPath)
+}
+
+// resealLocked 按当前加密状态重写文件(需要已持有锁)
+func (v *Vault) resealLocked(files []string) error {
+	for _, path := range files {
+		data, err := v.readLocked(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		if err := v.writeLocked(path, data); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
+
+// ReadFile 读取文件，加密文件自动解密；未解锁时返回 ErrLocked
+func (v *Vault) ReadFile(path string) ([]byte, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.readLocked(path)
+}
+
+func (v *Vault) readLocked(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.HasPrefix(data, []byte(magic)) {
+		return data, err
+	}
+	if v.key == nil {
+		return nil, ErrLocked
+	}
+	return open(v.key, data[len(magic):])
+}
+
+// WriteFile 写入文件，启用加密时加密后写入；未解锁时返回 ErrLocked，避免用空数据覆盖加密文件
+func (v *Vault) WriteFile(path string, data []byte) error {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.writeLocked(path, data)
+}
+
+func (v *Vault) writeLocked(path string, data []byte) error {
+	if v.params == nil {
+		return os.WriteFile(path, data, 0644)
+	}
+	if v.key == nil {
+		return ErrLocked
+	}
+	sealed, err := seal(v.key, data)
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再替换，避免写入中断损坏原文件
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append([]byte(magic), sealed...), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// derive 由口令派生密钥
+func (p *keyParams) derive(passphrase string) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, p.Salt, p.Iterations, 32)
+}
+
+// verify 派生密钥并校验密码
+func (p *keyParams) verify(passphrase string) ([]byte, error) {
+	key, err := p.derive(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if text, err := open(key, p.Check); err != nil || string(text) != checkText {
+		return nil, ErrWrongPassphrase
+	}
+	return key, nil
+}
+
+// seal AES-256-GCM 加密，输出 nonce + 密文
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, []byte(magic)), nil
+}
+
+// open 解密 seal 的输出
+func open(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("加密文件已损坏")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(magic))
+	if err != nil {
+		return nil, errors.New("加密文件已损坏或密钥不匹配")
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
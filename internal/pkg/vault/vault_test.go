@@ -0,0 +1,68 @@
+package vault
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVault(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.json")
+	secret := filepath.Join(dir, "secret.json")
+	os.WriteFile(plain, []byte(`{"shares":1000}`), 0644)
+
+	v, err := Init(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Enabled() || v.Locked() || Default() != v {
+		t.Fatal("new vault should be disabled default instance")
+	}
+	if err := v.Enable("123", nil); err == nil {
+		t.Error("short passphrase should be rejected")
+	}
+	if err := v.Enable("correct horse", []string{plain, filepath.Join(dir, "missing.json")}); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(plain); bytes.Contains(raw, []byte("shares")) {
+		t.Fatal("existing file should be encrypted")
+	}
+	if err := WriteFile(secret, []byte("position")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重新加载后未解锁：读写均返回 ErrLocked
+	v, _ = Init(dir)
+	if !v.Locked() {
+		t.Fatal("reloaded vault should be locked")
+	}
+	if _, err := ReadFile(secret); !errors.Is(err, ErrLocked) {
+		t.Errorf("read locked: %v", err)
+	}
+	if err := WriteFile(secret, []byte("empty")); !errors.Is(err, ErrLocked) {
+		t.Errorf("write locked: %v", err)
+	}
+	if err := v.Unlock("wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("wrong passphrase: %v", err)
+	}
+	if err := v.Unlock("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ReadFile(secret); err != nil || string(data) != "position" {
+		t.Errorf("read unlocked: %q %v", data, err)
+	}
+
+	// 关闭加密后文件恢复明文
+	if err := v.Disable("correct horse", []string{plain, secret}); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(plain); string(raw) != `{"shares":1000}` {
+		t.Errorf("decrypted file: %q", raw)
+	}
+	if v, _ := Init(dir); v.Enabled() {
+		t.Error("encryption should stay disabled after reload")
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 会议建议操作类型
+const (
+	AdviceActionReduce = "reduce" // 减仓/卖出
+	AdviceActionAdd    = "add"    // 加仓/买入
+	AdviceActionHold   = "hold"   // 持有/观望
+)
+
+// AdviceTrackWindow 建议跟踪窗口，超过该时间的建议不再做背离检测
+const AdviceTrackWindow = 30 * 24 * time.Hour
+
+// AdviceReviewDelay 建议给出后的观察期，期满时持仓仍与建议相反才视为背离（期间的调仓可能只是分批执行）
+const AdviceReviewDelay = 7 * 24 * time.Hour
+
+// adviceKeywords 各类操作建议的关键词
+var adviceKeywords = map[string][]string{
+	AdviceActionReduce: {"减仓", "卖出", "清仓", "止盈", "止损", "离场", "逢高出", "降低仓位"},
+	AdviceActionAdd:    {"加仓", "买入", "增持", "建仓", "补仓", "低吸", "提高仓位"},
+	AdviceActionHold:   {"持有", "观望", "持股待涨", "按兵不动"},
+}
+
+// adviceNegations 否定前缀（如"不建议加仓"不计入加仓）
+var adviceNegations = []string{"不建议", "暂不", "不要", "不宜", "避免", "勿", "不"}
+
+// ClassifyAdviceAction 从会议总结中识别操作建议
+// 按关键词出现次数打分，否定表述不计分；无法判断时返回空字符串
+func ClassifyAdviceAction(summary string) string {
+	best, bestScore := "", 0
+	for _, action := range []string{AdviceActionReduce, AdviceActionAdd, AdviceActionHold} {
+		score := 0
+		for _, kw := range adviceKeywords[action] {
+			score += countAffirmative(summary, kw)
+		}
+		if score > bestScore {
+			best, bestScore = action, score
+		}
+	}
+	return best
+}
+
+// countAffirmative 统计关键词的肯定出现次数
+func countAffirmative(text, keyword string) int {
+	count := 0
+	for offset := 0; ; {
+		idx := strings.Index(text[offset:], keyword)
+		if idx < 0 {
+			return count
+		}
+		pos := offset + idx
+		prefix := text[:pos]
+		negated := false
+		for _, neg := range adviceNegations {
+			if strings.HasSuffix(prefix, neg) {
+				negated = true
+				break
+			}
+		}
+		if !negated {
+			count++
+		}
+		offset = pos + len(keyword)
+	}
+}
+
+// IsAdviceDiverged 判断当前持仓变动是否与建议背离
+// 观察期满后，建议减仓却加仓，或建议加仓却减仓，视为背离
+func IsAdviceDiverged(advice *models.MeetingAdvice, shares int64) bool {
+	if advice == nil {
+		return false
+	}
+	age := time.Since(time.UnixMilli(advice.CreatedAt))
+	if age < AdviceReviewDelay || age > AdviceTrackWindow {
+		return false
+	}
+	switch advice.Action {
+	case AdviceActionReduce:
+		return shares > advice.Shares
+	case AdviceActionAdd:
+		return shares < advice.Shares
+	}
+	return false
+}
+
+// adviceActionLabel 操作建议的中文描述
+func adviceActionLabel(action string) string {
+	switch action {
+	case AdviceActionReduce:
+		return "减仓"
+	case AdviceActionAdd:
+		return "加仓"
+	case AdviceActionHold:
+		return "持有观望"
+	}
+	return action
+}
+
+// BuildAdviceDivergenceContext 构建"用户上次未执行建议"的会议上下文
+func BuildAdviceDivergenceContext(advice *models.MeetingAdvice, position *models.StockPosition) string {
+	if advice == nil || !advice.Diverged {
+		return ""
+	}
+	var current int64
+	if position != nil {
+		current = position.Shares
+	}
+	return fmt.Sprintf("【用户上次未执行建议】\n%s 会议建议%s（当时持仓 %d 股），但用户随后将持仓调整为 %d 股，与建议方向相反。\n上次结论摘要：%s\n请在分析中温和地关注这一差异，了解用户的考量，不要指责。\n",
+		time.UnixMilli(advice.CreatedAt).Format("2006-01-02"),
+		adviceActionLabel(advice.Action), advice.Shares, current, advice.Summary)
+}
+
+// DivergenceNotice 持仓与建议背离的提醒内容
+type DivergenceNotice struct {
+	StockCode string `json:"stockCode"`
+	StockName string `json:"stockName"`
+	Action    string `json:"action"`
+	Message   string `json:"message"`
+}
+
+// BuildDivergenceNotice 构建背离提醒
+func BuildDivergenceNotice(session *models.StockSession) DivergenceNotice {
+	advice := session.LastAdvice
+	var shares int64
+	if session.Position != nil {
+		shares = session.Position.Shares
+	}
+	return DivergenceNotice{
+		StockCode: session.StockCode,
+		StockName: session.StockName,
+		Action:    advice.Action,
+		Message: fmt.Sprintf("%s 上次会议建议%s，当前持仓由 %d 股调整为 %d 股，与建议方向不同。如有新的判断，可以再开一次会议聊聊。",
+			session.StockName, adviceActionLabel(advice.Action), advice.Shares, shares),
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// TestClassifyAdviceAction 测试会议结论操作识别
+func TestClassifyAdviceAction(t *testing.T) {
+	cases := []struct {
+		summary string
+		want    string
+	}{
+		{"综合来看短期风险较大，建议逢高减仓，控制仓位。", AdviceActionReduce},
+		{"基本面稳健，回调至支撑位可考虑加仓。", AdviceActionAdd},
+		{"不建议加仓，继续持有观望即可。", AdviceActionHold},
+		{"公司发布了三季报。", ""},
+	}
+	for _, c := range cases {
+		if got := ClassifyAdviceAction(c.summary); got != c.want {
+			t.Errorf("ClassifyAdviceAction(%q) = %q, want %q", c.summary, got, c.want)
+		}
+	}
+}
+
+// TestIsAdviceDiverged 测试持仓背离判断
+func TestIsAdviceDiverged(t *testing.T) {
+	weekAgo := time.Now().Add(-AdviceReviewDelay - time.Hour).UnixMilli()
+	reduce := &models.MeetingAdvice{Action: AdviceActionReduce, Shares: 1000, CreatedAt: weekAgo}
+	if !IsAdviceDiverged(reduce, 1500) {
+		t.Error("建议减仓后加仓应视为背离")
+	}
+	if IsAdviceDiverged(reduce, 500) {
+		t.Error("建议减仓后减仓不应视为背离")
+	}
+
+	add := &models.MeetingAdvice{Action: AdviceActionAdd, Shares: 1000, CreatedAt: weekAgo}
+	if !IsAdviceDiverged(add, 0) {
+		t.Error("建议加仓后清仓应视为背离")
+	}
+
+	// 观察期内的调仓可能是分批执行，不判定背离
+	fresh := &models.MeetingAdvice{Action: AdviceActionReduce, Shares: 1000, CreatedAt: time.Now().Add(-24 * time.Hour).UnixMilli()}
+	if IsAdviceDiverged(fresh, 1500) {
+		t.Error("观察期内不应检测背离")
+	}
+
+	expired := &models.MeetingAdvice{Action: AdviceActionReduce, Shares: 1000,
+		CreatedAt: time.Now().Add(-AdviceTrackWindow - time.Hour).UnixMilli()}
+	if IsAdviceDiverged(expired, 1500) {
+		t.Error("超出跟踪窗口的建议不应检测背离")
+	}
+}
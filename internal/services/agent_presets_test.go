@@ -0,0 +1,86 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func findPresetInfo(infos []AgentPresetInfo, id string) AgentPresetInfo {
+	for _, info := range infos {
+		if info.ID == id {
+			return info
+		}
+	}
+	return AgentPresetInfo{}
+}
+
+// TestAgentPresets 测试预设安装、自动升级与用户修改保护
+func TestAgentPresets(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, p := range agentPresets {
+		if seen[p.ID] || p.Version < 1 || p.Agent.Instruction == "" {
+			t.Errorf("invalid preset: %s", p.ID)
+		}
+		seen[p.ID] = true
+	}
+
+	dir := t.TempDir()
+	s := NewStrategyService(dir)
+	if n, err := s.InstallAgentPresets([]string{"preset_trend", "preset_value"}); err != nil || n != 2 {
+		t.Fatalf("install: %d %v", n, err)
+	}
+	if n, _ := s.InstallAgentPresets([]string{"preset_trend"}); n != 0 {
+		t.Error("installed preset should be skipped")
+	}
+	if _, err := s.InstallAgentPresets([]string{"missing"}); err == nil {
+		t.Error("unknown preset should be rejected")
+	}
+
+	// 用户修改其中一个专家，并设置专属模型
+	for _, a := range s.GetActiveStrategy().Agents {
+		if a.ID == "preset_value" {
+			a.Instruction = "自定义提示词"
+			a.AIConfigID = "my-model"
+			if err := s.UpdateAgentInActiveStrategy(models.StrategyAgent{
+				ID: a.ID, Name: a.Name, Role: a.Role, Avatar: a.Avatar, Color: a.Color,
+				Instruction: a.Instruction, Tools: a.Tools, Enabled: true, AIConfigID: a.AIConfigID,
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	info := findPresetInfo(s.GetAgentPresets(), "preset_value")
+	if !info.Installed || !info.Customized {
+		t.Fatalf("customized preset: %+v", info)
+	}
+
+	// 预设发布新版本后重新加载：未修改的自动升级，修改过的保留
+	original := append([]AgentPreset(nil), agentPresets...)
+	defer func() { agentPresets = original }()
+	agentPresets = append([]AgentPreset(nil), original...)
+	for i := range agentPresets {
+		agentPresets[i].Version++
+		agentPresets[i].Agent.Instruction += "\n新增内容"
+	}
+	s = NewStrategyService(dir)
+	infos := s.GetAgentPresets()
+	if info := findPresetInfo(infos, "preset_trend"); info.Upgradable || info.Customized {
+		t.Errorf("unmodified preset should be upgraded on load: %+v", info)
+	}
+	if info := findPresetInfo(infos, "preset_value"); !info.Upgradable {
+		t.Errorf("customized preset should wait for user: %+v", info)
+	}
+
+	if upgraded, _ := s.UpgradeAgentPresets(nil, false); len(upgraded) != 0 {
+		t.Errorf("customized preset upgraded without overwrite: %v", upgraded)
+	}
+	if upgraded, err := s.UpgradeAgentPresets([]string{"preset_value"}, true); err != nil || len(upgraded) != 1 {
+		t.Fatalf("overwrite: %v %v", upgraded, err)
+	}
+	for _, a := range s.GetActiveStrategy().Agents {
+		if a.ID == "preset_value" && (a.AIConfigID != "my-model" || a.Instruction == "自定义提示词") {
+			t.Errorf("upgraded agent: %+v", a)
+		}
+	}
+}
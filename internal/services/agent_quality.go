@@ -0,0 +1,171 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+var qualityLog = logger.New("quality")
+
+// maxABTestRecords 质量日志最多保留的对比记录数，超出时丢弃最早的记录
+const maxABTestRecords = 500
+
+// A/B 对比的偏好结果
+const (
+	ABPreferA   = "A"
+	ABPreferB   = "B"
+	ABPreferTie = "tie"
+)
+
+// ABTestVariant 参与对比的专家变体
+type ABTestVariant struct {
+	Label       string `json:"label"` // A/B
+	Instruction string `json:"instruction"`
+	AIConfigID  string `json:"aiConfigId,omitempty"`
+	Model       string `json:"model,omitempty"` // 作答的模型名
+	Content     string `json:"content"`         // 回复内容
+	Error       string `json:"error,omitempty"` // 作答失败的错误信息
+}
+
+// ABTestRecord 一次 A/B 对比记录
+type ABTestRecord struct {
+	ID        string          `json:"id"`
+	AgentID   string          `json:"agentId"`
+	AgentName string          `json:"agentName"`
+	StockCode string          `json:"stockCode"`
+	Query     string          `json:"query"`
+	Variants  []ABTestVariant `json:"variants"`
+	Preferred string          `json:"preferred,omitempty"` // A/B/tie，为空表示尚未评价
+	CreatedAt int64           `json:"createdAt"`
+	RatedAt   int64           `json:"ratedAt,omitempty"`
+}
+
+// AgentQualityStats 专家的 A/B 对比统计
+type AgentQualityStats struct {
+	AgentID   string `json:"agentId"`
+	AgentName string `json:"agentName"`
+	Tests     int    `json:"tests"`
+	Rated     int    `json:"rated"`
+	WinsA     int    `json:"winsA"`
+	WinsB     int    `json:"winsB"`
+	Ties      int    `json:"ties"`
+}
+
+// AgentQualityService 专家质量日志：记录 A/B 对比及老韭菜的偏好，用于迭代专家提示词与模型
+type AgentQualityService struct {
+	path    string
+	records []ABTestRecord
+	mu      sync.RWMutex
+}
+
+// NewAgentQualityService 创建专家质量日志服务
+func NewAgentQualityService(dataDir string) *AgentQualityService {
+	s := &AgentQualityService{path: filepath.Join(dataDir, "agent_quality.json")}
+	if data, err := os.ReadFile(s.path); err == nil {
+		if err := json.Unmarshal(data, &s.records); err != nil {
+			qualityLog.Error("解析专家质量日志失败: %v", err)
+		}
+	}
+	return s
+}
+
+// saveLocked 保存质量日志(需要已持有锁)
+func (s *AgentQualityService) saveLocked() error {
+	data, err := json.MarshalIndent(s.records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// RecordABTest 记录一次 A/B 对比，返回带 ID 的记录
+func (s *AgentQualityService) RecordABTest(record ABTestRecord) (ABTestRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.ID = uuid.New().String()
+	record.CreatedAt = time.Now().UnixMilli()
+	s.records = append(s.records, record)
+	if len(s.records) > maxABTestRecords {
+		s.records = s.records[len(s.records)-maxABTestRecords:]
+	}
+	return record, s.saveLocked()
+}
+
+// RateABTest 记录老韭菜对一次对比的偏好，可重复评价以修改结果
+func (s *AgentQualityService) RateABTest(id, preferred string) error {
+	switch preferred {
+	case ABPreferA, ABPreferB, ABPreferTie:
+	default:
+		return fmt.Errorf("无效的评价: %s", preferred)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.records {
+		if s.records[i].ID == id {
+			s.records[i].Preferred = preferred
+			s.records[i].RatedAt = time.Now().UnixMilli()
+			return s.saveLocked()
+		}
+	}
+	return fmt.Errorf("对比记录不存在: %s", id)
+}
+
+// GetABTests 获取专家的对比记录（最新的在前），agentID 为空时返回全部
+func (s *AgentQualityService) GetABTests(agentID string, limit int) []ABTestRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]ABTestRecord, 0)
+	for i := len(s.records) - 1; i >= 0; i-- {
+		if agentID != "" && s.records[i].AgentID != agentID {
+			continue
+		}
+		result = append(result, s.records[i])
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+// GetStats 按专家汇总对比结果
+func (s *AgentQualityService) GetStats() []AgentQualityStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	index := make(map[string]int)
+	result := make([]AgentQualityStats, 0)
+	for _, r := range s.records {
+		i, ok := index[r.AgentID]
+		if !ok {
+			i = len(result)
+			index[r.AgentID] = i
+			result = append(result, AgentQualityStats{AgentID: r.AgentID})
+		}
+		stats := &result[i]
+		stats.AgentName = r.AgentName
+		stats.Tests++
+		switch r.Preferred {
+		case ABPreferA:
+			stats.WinsA++
+		case ABPreferB:
+			stats.WinsB++
+		case ABPreferTie:
+			stats.Ties++
+		}
+		if r.Preferred != "" {
+			stats.Rated++
+		}
+	}
+	return result
+}
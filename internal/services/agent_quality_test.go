@@ -0,0 +1,47 @@
+package services
+
+import "testing"
+
+// TestAgentQuality 测试 A/B 对比记录、评价与按专家统计
+func TestAgentQuality(t *testing.T) {
+	dir := t.TempDir()
+	s := NewAgentQualityService(dir)
+
+	first, err := s.RecordABTest(ABTestRecord{AgentID: "tech", AgentName: "技术分析师", Query: "能买吗"})
+	if err != nil || first.ID == "" {
+		t.Fatalf("record: %+v %v", first, err)
+	}
+	second, _ := s.RecordABTest(ABTestRecord{AgentID: "tech", AgentName: "技术分析师", Query: "压力位"})
+	s.RecordABTest(ABTestRecord{AgentID: "risk", AgentName: "风控专家", Query: "止损"})
+
+	if err := s.RateABTest(first.ID, "C"); err == nil {
+		t.Error("invalid preference should be rejected")
+	}
+	if err := s.RateABTest("missing", ABPreferA); err == nil {
+		t.Error("unknown record should be rejected")
+	}
+	s.RateABTest(first.ID, ABPreferA)
+	s.RateABTest(first.ID, ABPreferB) // 重新评价覆盖原结果
+	s.RateABTest(second.ID, ABPreferTie)
+
+	// 重新加载后记录保留，最新的在前
+	s = NewAgentQualityService(dir)
+	tests := s.GetABTests("tech", 0)
+	if len(tests) != 2 || tests[0].ID != second.ID || tests[1].Preferred != ABPreferB {
+		t.Fatalf("tests: %+v", tests)
+	}
+	if got := s.GetABTests("", 1); len(got) != 1 || got[0].AgentID != "risk" {
+		t.Errorf("limit: %+v", got)
+	}
+
+	stats := s.GetStats()
+	if len(stats) != 2 {
+		t.Fatalf("stats: %+v", stats)
+	}
+	if tech := stats[0]; tech.Tests != 2 || tech.Rated != 2 || tech.WinsA != 0 || tech.WinsB != 1 || tech.Ties != 1 {
+		t.Errorf("tech stats: %+v", tech)
+	}
+	if risk := stats[1]; risk.Tests != 1 || risk.Rated != 0 {
+		t.Errorf("risk stats: %+v", risk)
+	}
+}
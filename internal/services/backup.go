@@ -0,0 +1,286 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/vault"
+)
+
+// BackupSchemaVersion 当前备份格式版本，格式变化时递增并在 backupMigrations 中补充迁移
+const BackupSchemaVersion = 1
+
+// backupManifestName 备份清单文件名
+const backupManifestName = "manifest.json"
+
+// backupPatterns 备份包含的数据：配置、自选股与分组、策略、专家质量日志与观点记录、会议模板、模拟盘、会话（含持仓、交易计划）与记忆
+var backupPatterns = []string{
+	"config.json",
+	"watchlist.json",
+	"watchlist_groups.json",
+	"strategies.json",
+	"agent_quality.json",
+	"verdicts.json",
+	"meeting_templates.json",
+	"paper_account.json",
+	"sessions/*.json",
+	"memories/*.json",
+}
+
+// BackupManifest 备份清单
+type BackupManifest struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	AppVersion    string `json:"appVersion"`
+	Profile       string `json:"profile"`
+	CreatedAt     int64  `json:"createdAt"`
+	Files         int    `json:"files"`
+}
+
+// backupMigrations 按来源版本升级备份内容，key 为迁移前的版本
+var backupMigrations = map[int]func(files map[string][]byte) (map[string][]byte, error){
+	// 版本 0：直接压缩数据目录得到的 zip（无清单），文件可能位于 jcp/ 等顶层目录下
+	0: func(files map[string][]byte) (map[string][]byte, error) {
+		if _, ok := files["config.json"]; ok {
+			return files, nil
+		}
+		var prefix string
+		for name := range files {
+			if dir, base := path.Split(name); base == "config.json" && strings.Count(dir, "/") == 1 {
+				prefix = dir
+				break
+			}
+		}
+		if prefix == "" {
+			return nil, errors.New("不是有效的备份文件：缺少 config.json")
+		}
+		migrated := make(map[string][]byte, len(files))
+		for name, data := range files {
+			if rest, ok := strings.CutPrefix(name, prefix); ok {
+				migrated[rest] = data
+			}
+		}
+		return migrated, nil
+	},
+}
+
+// isBackupFile 判断路径是否属于备份范围（同时防止恢复时写出数据目录）
+// path.Match 的 * 可以匹配反斜杠与 ..，Windows 下会被当作路径分隔符，需先排除
+func isBackupFile(name string) bool {
+	if strings.ContainsRune(name, '\\') || strings.Contains(name, "..") {
+		return false
+	}
+	for _, pattern := range backupPatterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// collectBackupFiles 读取数据目录中需要备份的文件（加密文件解密后写入备份）
+func collectBackupFiles(dataDir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, pattern := range backupPatterns {
+		matches, err := filepath.Glob(filepath.Join(dataDir, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			rel, err := filepath.Rel(dataDir, match)
+			if err != nil {
+				return nil, err
+			}
+			data, err := vault.ReadFile(match)
+			if err != nil {
+				return nil, fmt.Errorf("读取 %s 失败: %w", rel, err)
+			}
+			files[filepath.ToSlash(rel)] = data
+		}
+	}
+	return files, nil
+}
+
+// ExportBackup 将数据目录中的用户数据导出为 zip 备份
+// 启用数据加密时备份内容为解密后的明文，需妥善保管备份文件
+func ExportBackup(dataDir string, w io.Writer, manifest BackupManifest) (*BackupManifest, error) {
+	files, err := collectBackupFiles(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	manifest.SchemaVersion = BackupSchemaVersion
+	manifest.CreatedAt = time.Now().UnixMilli()
+	manifest.Files = len(files)
+
+	zw := zip.NewWriter(w)
+	if err := writeZipJSON(zw, backupManifestName, manifest); err != nil {
+		return nil, err
+	}
+	for name, data := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// ExportBackupFile 导出备份到文件
+func ExportBackupFile(dataDir, target string, manifest BackupManifest) (*BackupManifest, error) {
+	f, err := os.Create(target)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ExportBackup(dataDir, f, manifest)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(target)
+		return nil, err
+	}
+	return result, nil
+}
+
+func writeZipJSON(zw *zip.Writer, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+// readBackup 读取备份并迁移到当前格式版本
+func readBackup(source string) (*BackupManifest, map[string][]byte, error) {
+	zr, err := zip.OpenReader(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("无法打开备份文件: %w", err)
+	}
+	defer zr.Close()
+
+	manifest := &BackupManifest{}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取 %s 失败: %w", f.Name, err)
+		}
+		if f.Name == backupManifestName {
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, nil, fmt.Errorf("备份清单格式错误: %w", err)
+			}
+			continue
+		}
+		files[f.Name] = data
+	}
+
+	if manifest.SchemaVersion > BackupSchemaVersion {
+		return nil, nil, fmt.Errorf("备份来自更新版本的应用（格式版本 %d），请先升级后再恢复", manifest.SchemaVersion)
+	}
+	for v := manifest.SchemaVersion; v < BackupSchemaVersion; v++ {
+		migrate, ok := backupMigrations[v]
+		if !ok {
+			return nil, nil, fmt.Errorf("不支持的备份格式版本: %d", v)
+		}
+		if files, err = migrate(files); err != nil {
+			return nil, nil, err
+		}
+	}
+	manifest.SchemaVersion = BackupSchemaVersion
+
+	// 只恢复备份范围内的文件，并确认内容为合法 JSON
+	restored := make(map[string][]byte, len(files))
+	for name, data := range files {
+		if !isBackupFile(name) {
+			continue
+		}
+		if !json.Valid(data) {
+			return nil, nil, fmt.Errorf("备份中的 %s 已损坏", name)
+		}
+		restored[name] = data
+	}
+	if _, ok := restored["config.json"]; !ok {
+		return nil, nil, errors.New("不是有效的备份文件：缺少 config.json")
+	}
+	manifest.Files = len(restored)
+	return manifest, restored, nil
+}
+
+// ImportBackup 从备份恢复数据目录：先将当前数据另存到 backups/ 下，再以备份内容替换
+// 会话与记忆以备份为准（备份中没有的记录会被移除），恢复后需重启应用重新加载
+func ImportBackup(dataDir, source string, manifest BackupManifest) (*BackupManifest, error) {
+	restored, files, err := readBackup(source)
+	if err != nil {
+		return nil, err
+	}
+
+	backupDir := filepath.Join(dataDir, "backups")
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return nil, err
+	}
+	safety := filepath.Join(backupDir, fmt.Sprintf("pre-restore-%s.zip", time.Now().Format("20060102-150405")))
+	if _, err := ExportBackupFile(dataDir, safety, manifest); err != nil {
+		return nil, fmt.Errorf("备份当前数据失败: %w", err)
+	}
+
+	for _, dir := range []string{"sessions", "memories"} {
+		old, _ := filepath.Glob(filepath.Join(dataDir, dir, "*.json"))
+		for _, f := range old {
+			os.Remove(f)
+		}
+	}
+	for name, data := range files {
+		target, err := backupTarget(dataDir, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		if isEncryptedDataFile(name) {
+			err = vault.WriteFile(target, data)
+		} else {
+			err = os.WriteFile(target, data, 0644)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("恢复 %s 失败（当前数据已保存到 %s）: %w", name, safety, err)
+		}
+	}
+	return restored, nil
+}
+
+// backupTarget 备份文件在数据目录中的恢复路径，越出数据目录时返回错误
+func backupTarget(dataDir, name string) (string, error) {
+	target := filepath.Join(dataDir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(dataDir, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("备份中的 %s 路径非法", name)
+	}
+	return target, nil
+}
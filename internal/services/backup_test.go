@@ -0,0 +1,144 @@
+package services
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(target), 0755)
+		if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writeTestZip(t *testing.T, target string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	f.Close()
+}
+
+// TestBackupRoundTrip 测试导出备份后恢复到另一数据目录
+func TestBackupRoundTrip(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	writeTestFiles(t, src, map[string]string{
+		"config.json":            `{"theme":"ocean"}`,
+		"watchlist.json":         `[{"symbol":"sh600519"}]`,
+		"paper_account.json":     `{"cash":100000}`,
+		"sessions/sh600519.json": `{"stockCode":"sh600519"}`,
+		"memories/sh600519.json": `{"stockCode":"sh600519"}`,
+		"logs/app.log":           "not backed up",
+	})
+	writeTestFiles(t, dst, map[string]string{
+		"config.json":            `{"theme":"purple"}`,
+		"sessions/sz000001.json": `{"stockCode":"sz000001"}`,
+	})
+
+	archive := filepath.Join(t.TempDir(), "backup.zip")
+	manifest, err := ExportBackupFile(src, archive, BackupManifest{AppVersion: "1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Files != 5 || manifest.SchemaVersion != BackupSchemaVersion {
+		t.Fatalf("manifest: %+v", manifest)
+	}
+
+	restored, err := ImportBackup(dst, archive, BackupManifest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.AppVersion != "1.0.0" || restored.Files != 5 {
+		t.Errorf("restored manifest: %+v", restored)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "config.json")); string(data) != `{"theme":"ocean"}` {
+		t.Errorf("config: %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "sessions", "sz000001.json")); !os.IsNotExist(err) {
+		t.Error("sessions not in backup should be removed")
+	}
+	if safety, _ := filepath.Glob(filepath.Join(dst, "backups", "pre-restore-*.zip")); len(safety) != 1 {
+		t.Errorf("current data should be saved before restore: %v", safety)
+	}
+}
+
+// TestBackupMigration 测试旧格式备份迁移与非法内容拦截
+func TestBackupMigration(t *testing.T) {
+	dir := t.TempDir()
+
+	// 直接压缩数据目录得到的备份：无清单、带顶层目录，且包含越界路径
+	legacy := filepath.Join(dir, "legacy.zip")
+	writeTestZip(t, legacy, map[string]string{
+		"jcp/config.json":            `{"theme":"ocean"}`,
+		"jcp/sessions/sh600000.json": `{}`,
+		"jcp/../evil.json":           `{}`,
+	})
+	manifest, files, err := readBackup(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.SchemaVersion != BackupSchemaVersion || len(files) != 2 || files["sessions/sh600000.json"] == nil {
+		t.Errorf("migrated: %+v %v", manifest, files)
+	}
+
+	newer := filepath.Join(dir, "newer.zip")
+	writeTestZip(t, newer, map[string]string{"manifest.json": `{"schemaVersion":99}`, "config.json": `{}`})
+	if _, _, err := readBackup(newer); err == nil || !strings.Contains(err.Error(), "升级") {
+		t.Errorf("newer backup: %v", err)
+	}
+
+	broken := filepath.Join(dir, "broken.zip")
+	writeTestZip(t, broken, map[string]string{"manifest.json": `{"schemaVersion":1}`, "config.json": `{"theme":`})
+	if _, _, err := readBackup(broken); err == nil {
+		t.Error("corrupted file should be rejected")
+	}
+}
+
+// TestBackupRejectsTraversal 测试恢复时拦截可能写出数据目录的路径
+func TestBackupRejectsTraversal(t *testing.T) {
+	for _, name := range []string{`sessions/..\..\evil.json`, `memories/..\config.json`, "sessions/...json", "sessions/../config.json"} {
+		if isBackupFile(name) {
+			t.Errorf("isBackupFile(%q) = true", name)
+		}
+	}
+	if !isBackupFile("sessions/sh600519.json") {
+		t.Error("normal session file rejected")
+	}
+
+	dataDir := t.TempDir()
+	if _, err := backupTarget(dataDir, "sessions/sh600519.json"); err != nil {
+		t.Errorf("backupTarget: %v", err)
+	}
+	if _, err := backupTarget(dataDir, "../evil.json"); err == nil {
+		t.Error("path outside data dir accepted")
+	}
+
+	archive := filepath.Join(t.TempDir(), "evil.zip")
+	writeTestZip(t, archive, map[string]string{
+		"manifest.json":            `{"schemaVersion":1}`,
+		"config.json":              `{}`,
+		`sessions/..\..\evil.json`: `{}`,
+	})
+	_, files, err := readBackup(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files["config.json"] == nil {
+		t.Errorf("restored files = %v", files)
+	}
+}
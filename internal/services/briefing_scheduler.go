@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// briefingCheckEvery 早报调度检查间隔
+const briefingCheckEvery = time.Minute
+
+// briefingStartMinute 盘前早报生成时间（北京时间 8:30 起，开盘前完成）
+const briefingStartMinute = 8*60 + 30
+
+// BriefingScheduler 盘前早报调度：交易日盘前触发一次早报生成
+type BriefingScheduler struct {
+	marketService *MarketService
+	generate      func(ctx context.Context, date string)
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	lastDate string
+}
+
+// NewBriefingScheduler 创建早报调度，generate 负责为自选股逐只生成早报（需自行跳过当日已生成的股票）
+func NewBriefingScheduler(marketService *MarketService, generate func(ctx context.Context, date string)) *BriefingScheduler {
+	return &BriefingScheduler{marketService: marketService, generate: generate}
+}
+
+// Start 启动后台调度
+func (s *BriefingScheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.mu.Unlock()
+		return
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.mu.Unlock()
+
+	go s.loop(ctx)
+}
+
+// Stop 停止后台调度
+func (s *BriefingScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+func (s *BriefingScheduler) loop(ctx context.Context) {
+	ticker := time.NewTicker(briefingCheckEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			date, ok := s.due(time.Now())
+			if !ok {
+				continue
+			}
+			s.mu.Lock()
+			s.lastDate = date
+			s.mu.Unlock()
+			safeCall(func() { s.generate(ctx, date) })
+		}
+	}
+}
+
+// due 判断当前是否需要生成早报，返回交易日日期
+func (s *BriefingScheduler) due(now time.Time) (string, bool) {
+	now = now.In(time.FixedZone("CST", 8*60*60))
+	date := now.Format("2006-01-02")
+
+	s.mu.Lock()
+	done := s.lastDate == date
+	s.mu.Unlock()
+	if done || now.Hour()*60+now.Minute() < briefingStartMinute {
+		return "", false
+	}
+	return date, s.marketService.GetMarketStatus().Status == "pre_market"
+}
+
+// BriefingDateToday 当前北京时间日期，用于早报去重
+func BriefingDateToday() string {
+	return time.Now().In(time.FixedZone("CST", 8*60*60)).Format("2006-01-02")
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+// 5x7 点阵字体，仅含数字、大写字母与常用符号，用于图表坐标与图例（服务端无字体依赖，含中文的标题由前端渲染）
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+var glyphSource = map[rune]string{
+	'0': ".###. #...# #..## #.#.# ##..# #...# .###.",
+	'1': "..#.. .##.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'2': ".###. #...# ....# ...#. ..#.. .#... #####",
+	'3': "##### ...#. ..#.. ...#. ....# #...# .###.",
+	'4': "...#. ..##. .#.#. #..#. ##### ...#. ...#.",
+	'5': "##### #.... ####. ....# ....# #...# .###.",
+	'6': "..##. .#... #.... ####. #...# #...# .###.",
+	'7': "##### ....# ...#. ..#.. .#... .#... .#...",
+	'8': ".###. #...# #...# .###. #...# #...# .###.",
+	'9': ".###. #...# #...# .#### ....# ...#. .##..",
+	'.': "..... ..... ..... ..... ..... .##.. .##..",
+	'-': "..... ..... ..... .###. ..... ..... .....",
+	'+': "..... ..#.. ..#.. ##### ..#.. ..#.. .....",
+	':': "..... .##.. .##.. ..... .##.. .##.. .....",
+	'%': "##... ##..# ...#. ..#.. .#... #..## ...##",
+	'/': "..... ....# ...#. ..#.. .#... #.... .....",
+	'A': ".###. #...# #...# ##### #...# #...# #...#",
+	'B': "####. #...# #...# ####. #...# #...# ####.",
+	'C': ".###. #...# #.... #.... #.... #...# .###.",
+	'D': "###.. #..#. #...# #...# #...# #..#. ###..",
+	'E': "##### #.... #.... ####. #.... #.... #####",
+	'F': "##### #.... #.... ####. #.... #.... #....",
+	'G': ".###. #...# #.... #.### #...# #...# .####",
+	'H': "#...# #...# #...# ##### #...# #...# #...#",
+	'I': ".###. ..#.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'J': "..### ...#. ...#. ...#. ...#. #..#. .##..",
+	'K': "#...# #..#. #.#.. ##... #.#.. #..#. #...#",
+	'L': "#.... #.... #.... #.... #.... #.... #####",
+	'M': "#...# ##.## #.#.# #.#.# #...# #...# #...#",
+	'N': "#...# #...# ##..# #.#.# #..## #...# #...#",
+	'O': ".###. #...# #...# #...# #...# #...# .###.",
+	'P': "####. #...# #...# ####. #.... #.... #....",
+	'Q': ".###. #...# #...# #...# #.#.# #..#. .##.#",
+	'R': "####. #...# #...# ####. #.#.. #..#. #...#",
+	'S': ".#### #.... #.... .###. ....# ....# ####.",
+	'T': "##### ..#.. ..#.. ..#.. ..#.. ..#.. ..#..",
+	'U': "#...# #...# #...# #...# #...# #...# .###.",
+	'V': "#...# #...# #...# #...# #...# .#.#. ..#..",
+	'W': "#...# #...# #...# #.#.# #.#.# #.#.# .#.#.",
+	'X': "#...# #...# .#.#. ..#.. .#.#. #...# #...#",
+	'Y': "#...# #...# .#.#. ..#.. ..#.. ..#.. ..#..",
+	'Z': "##### ....# ...#. ..#.. .#... #.... #####",
+}
+
+// glyphs 解析后的点阵：每行低 5 位，最高位为最左像素
+var glyphs = func() map[rune][glyphHeight]uint8 {
+	m := make(map[rune][glyphHeight]uint8, len(glyphSource))
+	for r, src := range glyphSource {
+		var g [glyphHeight]uint8
+		for y, row := range strings.Fields(src) {
+			for x, c := range row {
+				if c == '#' {
+					g[y] |= 1 << (glyphWidth - 1 - x)
+				}
+			}
+		}
+		m[r] = g
+	}
+	return m
+}()
+
+// textWidth 文本像素宽度（字间距 1 像素）
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*(glyphWidth+1) - 1) * scale
+}
+
+// drawText 在 (x, y) 左上角绘制文本，不支持的字符留空
+func drawText(img *image.RGBA, x, y int, s string, c color.RGBA, scale int) {
+	for _, r := range strings.ToUpper(s) {
+		if g, ok := glyphs[r]; ok {
+			for gy := 0; gy < glyphHeight; gy++ {
+				for gx := 0; gx < glyphWidth; gx++ {
+					if g[gy]&(1<<(glyphWidth-1-gx)) != 0 {
+						fillRect(img, x+gx*scale, y+gy*scale, scale, scale, c)
+					}
+				}
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/run-bigpig/jcp/internal/analysis"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+const (
+	chartWidth  = 960
+	chartHeight = 560
+	chartScale  = 2 // 点阵字体放大倍数
+)
+
+var (
+	chartBg      = color.RGBA{27, 38, 54, 255}
+	chartGrid    = color.RGBA{52, 65, 85, 255}
+	chartText    = color.RGBA{170, 180, 195, 255}
+	chartRed     = color.RGBA{239, 68, 68, 255}
+	chartGreen   = color.RGBA{34, 197, 94, 255}
+	chartMAColor = map[int]color.RGBA{
+		5:  {250, 204, 21, 255},
+		10: {192, 132, 252, 255},
+		20: {56, 189, 248, 255},
+	}
+)
+
+var chartMAPeriods = []int{5, 10, 20}
+
+// renderKLineChart 绘制K线图：上方蜡烛与 MA5/10/20，下方成交量，右侧价格刻度
+// greenUp 为 true 时绿涨红跌；位图字体仅含 ASCII，中文标题由前端渲染
+func renderKLineChart(klines []models.KLineData, greenUp bool) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	fillRect(img, 0, 0, chartWidth, chartHeight, chartBg)
+
+	upColor, downColor := chartRed, chartGreen
+	if greenUp {
+		upColor, downColor = chartGreen, chartRed
+	}
+	candleColor := func(k models.KLineData) color.RGBA {
+		if k.Close >= k.Open {
+			return upColor
+		}
+		return downColor
+	}
+
+	const (
+		left      = 12
+		axisWidth = 100
+		top       = 40
+		bottom    = 30
+		gap       = 12
+	)
+	plotW := chartWidth - left - axisWidth
+	panelH := chartHeight - top - bottom - gap
+	priceH := panelH * 72 / 100
+	priceBottom := top + priceH
+	volTop, volBottom := priceBottom+gap, chartHeight-bottom
+
+	// 均线图例
+	legendX := chartWidth - axisWidth
+	for i := len(chartMAPeriods) - 1; i >= 0; i-- {
+		label := fmt.Sprintf("MA%d", chartMAPeriods[i])
+		legendX -= textWidth(label, chartScale) + 16
+		drawText(img, legendX, 12, label, chartMAColor[chartMAPeriods[i]], chartScale)
+	}
+
+	if len(klines) == 0 {
+		return img
+	}
+
+	mas := make(map[int][]float64, len(chartMAPeriods))
+	for _, p := range chartMAPeriods {
+		mas[p] = analysis.MA(analysis.Closes(klines), p)
+	}
+
+	// 价格范围包含均线，留 5% 边距
+	lo, hi := math.Inf(1), math.Inf(-1)
+	var maxVol int64
+	for i, k := range klines {
+		lo, hi = math.Min(lo, k.Low), math.Max(hi, k.High)
+		for _, p := range chartMAPeriods {
+			if v := mas[p][i]; v > 0 {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+		}
+		maxVol = max(maxVol, k.Volume)
+	}
+	if hi <= lo {
+		hi, lo = lo+1, lo-1
+	}
+	pad := (hi - lo) * 0.05
+	lo, hi = lo-pad, hi+pad
+	priceY := func(p float64) int {
+		return priceBottom - int(math.Round((p-lo)/(hi-lo)*float64(priceH)))
+	}
+
+	// 价格网格与刻度
+	const gridLines = 5
+	for i := 0; i <= gridLines; i++ {
+		p := lo + (hi-lo)*float64(i)/gridLines
+		y := priceY(p)
+		dashedHLine(img, left, left+plotW, y, chartGrid)
+		drawText(img, left+plotW+8, y-glyphHeight*chartScale/2, formatChartPrice(p), chartText, chartScale)
+	}
+	dashedHLine(img, left, left+plotW, volTop, chartGrid)
+
+	slot := float64(plotW) / float64(len(klines))
+	bodyW := max(1, int(slot*0.7))
+	centerX := func(i int) int {
+		return left + int(slot*float64(i)+slot/2)
+	}
+
+	for i, k := range klines {
+		c := candleColor(k)
+		x := centerX(i)
+		// 影线
+		fillRect(img, x, priceY(k.High), 1, priceY(k.Low)-priceY(k.High)+1, c)
+		// 实体
+		yOpen, yClose := priceY(k.Open), priceY(k.Close)
+		fillRect(img, x-bodyW/2, min(yOpen, yClose), bodyW, max(1, absInt(yOpen-yClose)), c)
+		// 成交量
+		if maxVol > 0 {
+			h := int(float64(k.Volume) / float64(maxVol) * float64(volBottom-volTop))
+			fillRect(img, x-bodyW/2, volBottom-h, bodyW, max(1, h), c)
+		}
+	}
+
+	for _, p := range chartMAPeriods {
+		prevX, prevY := -1, -1
+		for i, v := range mas[p] {
+			if v <= 0 {
+				continue
+			}
+			x, y := centerX(i), priceY(v)
+			if prevX >= 0 {
+				drawLine(img, prevX, prevY, x, y, chartMAColor[p])
+			}
+			prevX, prevY = x, y
+		}
+	}
+
+	// 时间刻度：首、中、尾
+	labelY := volBottom + 8
+	for _, i := range []int{0, len(klines) / 2, len(klines) - 1} {
+		label := klines[i].Time
+		w := textWidth(label, chartScale)
+		x := min(max(centerX(i)-w/2, left), left+plotW-w)
+		drawText(img, x, labelY, label, chartText, chartScale)
+	}
+	return img
+}
+
+// formatChartPrice 按价格量级选择小数位
+func formatChartPrice(p float64) string {
+	switch {
+	case math.Abs(p) >= 1000:
+		return fmt.Sprintf("%.1f", p)
+	case math.Abs(p) >= 10:
+		return fmt.Sprintf("%.2f", p)
+	}
+	return fmt.Sprintf("%.3f", p)
+}
+
+// fillRect 填充矩形（超出画布部分裁剪）
+func fillRect(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	r := image.Rect(x, y, x+w, y+h).Intersect(img.Bounds())
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			img.SetRGBA(px, py, c)
+		}
+	}
+}
+
+// dashedHLine 水平虚线
+func dashedHLine(img *image.RGBA, x0, x1, y int, c color.RGBA) {
+	for x := x0; x < x1; x += 6 {
+		fillRect(img, x, y, min(3, x1-x), 1, c)
+	}
+}
+
+// drawLine Bresenham 画线
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := absInt(x1-x0), -absInt(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		if image.Pt(x0, y0).In(img.Bounds()) {
+			img.SetRGBA(x0, y0, c)
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"fmt"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+const (
+	// ChartURLPrefix 图表资源路径前缀，由应用资源服务器提供访问
+	ChartURLPrefix = "/charts/"
+
+	chartDefaultBars = 60
+	chartMaxBars     = 250
+	chartRetention   = 7 * 24 * time.Hour // 图表文件保留时长
+)
+
+var chartFileRegex = regexp.MustCompile(`^[a-z0-9_]+\.png$`)
+
+// ChartImage 生成的图表
+type ChartImage struct {
+	File  string `json:"file"` // 本地文件路径
+	URL   string `json:"url"`  // 前端可访问的地址
+	Bars  int    `json:"bars"`
+	Title string `json:"title"` // 图表标题，含中文名称，由前端以文本渲染（位图字体不含中文字形）
+}
+
+// ChartService K线图表渲染服务：服务端绘制 PNG，供会议消息内嵌展示
+type ChartService struct {
+	marketService *MarketService
+	dir           string
+}
+
+// NewChartService 创建图表服务，dir 为图表存放目录（首次生成图表时创建），启动时清理过期文件
+func NewChartService(marketService *MarketService, dir string) *ChartService {
+	s := &ChartService{marketService: marketService, dir: dir}
+	s.cleanup()
+	return s
+}
+
+// RenderKLine 渲染K线图，greenUp 为 true 时绿涨红跌
+func (s *ChartService) RenderKLine(code, period string, bars int, adjust string, greenUp bool) (*ChartImage, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return nil, fmt.Errorf("股票代码不能为空")
+	}
+	if period == "" {
+		period = "1d"
+	}
+	if bars <= 0 {
+		bars = chartDefaultBars
+	}
+	bars = min(bars, chartMaxBars)
+	if adjust == "" {
+		adjust = AdjustQFQ
+	}
+	if period == "1m" {
+		adjust = AdjustNone
+	}
+
+	klines, err := s.marketService.GetKLineData(code, period, bars, adjust)
+	if err != nil {
+		return nil, err
+	}
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("未获取到 %s 的K线数据", code)
+	}
+	if len(klines) > bars {
+		klines = klines[len(klines)-bars:]
+	}
+
+	var name string
+	if stocks, err := s.marketService.GetStockRealTimeData(code); err == nil && len(stocks) > 0 {
+		name = stocks[0].Name
+	}
+	img := renderKLineChart(klines, greenUp)
+
+	file := fmt.Sprintf("%s_%s_%d.png", sanitizeChartName(code), sanitizeChartName(period), time.Now().UnixMilli())
+	path := filepath.Join(s.dir, file)
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		return nil, err
+	}
+	return &ChartImage{File: path, URL: ChartURLPrefix + file, Bars: len(klines), Title: chartTitle(code, name, period, klines)}, nil
+}
+
+// chartTitle 生成图表标题：名称、代码、周期、最新收盘价与涨跌幅
+func chartTitle(code, name, period string, klines []models.KLineData) string {
+	title := code
+	if name != "" {
+		title = fmt.Sprintf("%s(%s)", name, code)
+	}
+	title += " " + period
+	if len(klines) == 0 {
+		return title
+	}
+	last := klines[len(klines)-1]
+	title += " 收 " + formatChartPrice(last.Close)
+	if len(klines) >= 2 && klines[len(klines)-2].Close > 0 {
+		title += fmt.Sprintf(" %+.2f%%", (last.Close/klines[len(klines)-2].Close-1)*100)
+	}
+	return title
+}
+
+// FileHandler 提供 /charts/ 下的图表文件访问，仅允许目录内的 PNG 文件
+func (s *ChartService) FileHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, ChartURLPrefix)
+		if !strings.HasPrefix(r.URL.Path, ChartURLPrefix) || !chartFileRegex.MatchString(name) {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=86400")
+		http.ServeFile(w, r, filepath.Join(s.dir, name))
+	})
+}
+
+// cleanup 删除过期图表
+func (s *ChartService) cleanup() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err == nil && !e.IsDir() && time.Since(info.ModTime()) > chartRetention {
+			os.Remove(filepath.Join(s.dir, e.Name()))
+		}
+	}
+}
+
+// sanitizeChartName 文件名只保留小写字母与数字
+func sanitizeChartName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, strings.ToLower(s))
+}
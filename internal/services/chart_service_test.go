@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"image/png"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func sampleKLines(n int) []models.KLineData {
+	klines := make([]models.KLineData, n)
+	price := 100.0
+	for i := range klines {
+		open := price
+		price += 3 * math.Sin(float64(i)/4)
+		klines[i] = models.KLineData{
+			Time:   fmt.Sprintf("2024-05-%02d", i%28+1),
+			Open:   open,
+			Close:  price,
+			High:   math.Max(open, price) + 1,
+			Low:    math.Min(open, price) - 1,
+			Volume: int64(1000 + i*37%500),
+		}
+	}
+	return klines
+}
+
+func TestRenderKLineChart(t *testing.T) {
+	img := renderKLineChart(sampleKLines(60), false)
+	if b := img.Bounds(); b.Dx() != chartWidth || b.Dy() != chartHeight {
+		t.Fatalf("bounds = %v", b)
+	}
+
+	// 上涨K线使用红色（红涨绿跌）
+	var red, green int
+	for y := 0; y < chartHeight; y++ {
+		for x := 0; x < chartWidth; x++ {
+			switch img.RGBAAt(x, y) {
+			case chartRed:
+				red++
+			case chartGreen:
+				green++
+			}
+		}
+	}
+	if red == 0 || green == 0 {
+		t.Errorf("expected both candle colors, red=%d green=%d", red, green)
+	}
+
+	// 空数据与单根K线不应 panic
+	renderKLineChart(nil, false)
+	renderKLineChart(sampleKLines(1), true)
+}
+
+func TestChartTitle(t *testing.T) {
+	klines := []models.KLineData{{Close: 100}, {Close: 101}}
+	cases := []struct {
+		name, stock string
+		klines      []models.KLineData
+		want        string
+	}{
+		{"with name", "贵州茅台", klines, "贵州茅台(sh600519) 1d 收 101.00 +1.00%"},
+		{"without name", "", klines[:1], "sh600519 1d 收 100.00"},
+		{"empty", "贵州茅台", nil, "贵州茅台(sh600519) 1d"},
+	}
+	for _, tt := range cases {
+		if got := chartTitle("sh600519", tt.stock, "1d", tt.klines); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChartFileHandler(t *testing.T) {
+	dir := t.TempDir()
+	s := NewChartService(nil, dir)
+
+	f, err := os.Create(filepath.Join(dir, "sh600519_1d_1.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, renderKLineChart(sampleKLines(10), false))
+	f.Close()
+	os.WriteFile(filepath.Join(filepath.Dir(dir), "secret.png"), []byte("x"), 0644)
+
+	cases := map[string]int{
+		"/charts/sh600519_1d_1.png": http.StatusOK,
+		"/charts/missing.png":       http.StatusNotFound,
+		"/charts/../secret.png":     http.StatusNotFound,
+		"/charts/..%2fsecret.png":   http.StatusNotFound,
+		"/charts/sh600519_1d_1.txt": http.StatusNotFound,
+		"/other/sh600519_1d_1.png":  http.StatusNotFound,
+	}
+	for path, want := range cases {
+		rec := httptest.NewRecorder()
+		s.FileHandler().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// ConfigSection 配置分区，监听者据此判断是否需要重新加载
+type ConfigSection string
+
+const (
+	ConfigSectionAI       ConfigSection = "ai"       // AI 配置、默认 AI 与任务路由
+	ConfigSectionMCP      ConfigSection = "mcp"      // MCP 服务器
+	ConfigSectionTools    ConfigSection = "tools"    // Python 工具、自定义工具与禁用列表
+	ConfigSectionProxy    ConfigSection = "proxy"    // 代理
+	ConfigSectionMeeting  ConfigSection = "meeting"  // 会议费用上限、并发上限与用户画像
+	ConfigSectionOpenClaw ConfigSection = "openclaw" // OpenClaw 服务
+	ConfigSectionOther    ConfigSection = "other"    // 界面、指标、通知等其他设置
+)
+
+// ConfigSource 配置变更来源
+type ConfigSource string
+
+const (
+	ConfigSourceAPI  ConfigSource = "api"  // 界面或接口保存
+	ConfigSourceFile ConfigSource = "file" // 配置文件被外部编辑
+)
+
+// ConfigChange 配置变更事件
+type ConfigChange struct {
+	Old      *models.AppConfig // 变更前配置（副本）
+	New      *models.AppConfig // 变更后配置
+	Sections []ConfigSection
+	Source   ConfigSource
+}
+
+// Has 判断变更是否涉及指定分区
+func (c ConfigChange) Has(section ConfigSection) bool {
+	for _, s := range c.Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// configSections 各分区包含的配置字段
+var configSections = []struct {
+	section ConfigSection
+	fields  func(c *models.AppConfig) any
+}{
+	{ConfigSectionAI, func(c *models.AppConfig) any {
+		return []any{c.AIConfigs, c.DefaultAIID, c.StrategyAIID, c.ModeratorAIID, c.AIRouting, c.Memory.AIConfigID}
+	}},
+	{ConfigSectionMCP, func(c *models.AppConfig) any { return c.MCPServers }},
+	{ConfigSectionTools, func(c *models.AppConfig) any { return []any{c.PythonTool, c.CustomTools, c.DisabledTools} }},
+	{ConfigSectionProxy, func(c *models.AppConfig) any { return c.Proxy }},
+	{ConfigSectionMeeting, func(c *models.AppConfig) any {
+		return []any{c.MeetingBudget, c.MaxConcurrentMeetings, c.UserProfile, c.ContextBudget}
+	}},
+	{ConfigSectionOpenClaw, func(c *models.AppConfig) any { return c.OpenClaw }},
+}
+
+// diffConfig 比较两份配置，返回发生变化的分区
+func diffConfig(old, new *models.AppConfig) []ConfigSection {
+	var sections []ConfigSection
+	for _, s := range configSections {
+		if !sameJSON(s.fields(old), s.fields(new)) {
+			sections = append(sections, s.section)
+		}
+	}
+	// 上述分区之外的字段：逐个分区置空后比较剩余部分
+	rest := func(c *models.AppConfig) models.AppConfig {
+		r := *c
+		r.AIConfigs, r.DefaultAIID, r.StrategyAIID, r.ModeratorAIID = nil, "", "", ""
+		r.AIRouting, r.Memory.AIConfigID = models.AIRoutingConfig{}, ""
+		r.MCPServers = nil
+		r.PythonTool, r.CustomTools, r.DisabledTools = models.PythonToolConfig{}, nil, nil
+		r.Proxy = models.ProxyConfig{}
+		r.MeetingBudget, r.MaxConcurrentMeetings, r.UserProfile = 0, 0, models.UserProfile{}
+		r.ContextBudget = models.ContextBudgetConfig{}
+		r.OpenClaw = models.OpenClawConfig{}
+		return r
+	}
+	if oldRest, newRest := rest(old), rest(new); !sameJSON(oldRest, newRest) {
+		sections = append(sections, ConfigSectionOther)
+	}
+	return sections
+}
+
+func sameJSON(a, b any) bool {
+	da, errA := json.Marshal(a)
+	db, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(da) == string(db)
+}
+
+// cloneConfig 深拷贝配置
+func cloneConfig(c *models.AppConfig) *models.AppConfig {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return c
+	}
+	var clone models.AppConfig
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return c
+	}
+	return &clone
+}
+
+// CloneConfig 获取当前配置的深拷贝，修改后通过 UpdateConfig 整体提交
+func (cs *ConfigService) CloneConfig() *models.AppConfig {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cloneConfig(cs.config)
+}
+
+// OnChange 注册配置变更监听（监听者中不可再调用 UpdateConfig）
+func (cs *ConfigService) OnChange(listener func(ConfigChange)) {
+	cs.applyMu.Lock()
+	defer cs.applyMu.Unlock()
+	cs.listeners = append(cs.listeners, listener)
+}
+
+// apply 校验并原子替换配置：校验失败时保持原配置不变；生效后按顺序通知监听者
+func (cs *ConfigService) apply(config *models.AppConfig, source ConfigSource) error {
+	cs.applyMu.Lock()
+	defer cs.applyMu.Unlock()
+
+	cs.mu.Lock()
+	old := cs.applied
+	if err := introducedErrors(old.Validate(), config.Validate()); err != nil {
+		cs.mu.Unlock()
+		return err
+	}
+	prev := cs.config
+	cs.config = config
+	// 外部编辑的配置已在文件中，无需回写
+	if source != ConfigSourceFile {
+		if err := cs.saveConfigLocked(); err != nil {
+			cs.config = prev
+			cs.mu.Unlock()
+			return err
+		}
+	}
+	cs.applied = cloneConfig(config)
+	sections := diffConfig(old, cs.applied)
+	cs.mu.Unlock()
+
+	if len(sections) == 0 {
+		return nil
+	}
+	change := ConfigChange{Old: old, New: config, Sections: sections, Source: source}
+	for _, listener := range cs.listeners {
+		safeCall(func() { listener(change) })
+	}
+	return nil
+}
+
+// introducedErrors 返回本次修改新引入的校验错误
+// 配置文件中原有的问题不阻止保存其他设置（如调整主题、布局）
+func introducedErrors(before, after error) error {
+	existing := make(map[string]bool)
+	for _, err := range joinedErrors(before) {
+		existing[err.Error()] = true
+	}
+	var errs []error
+	for _, err := range joinedErrors(after) {
+		if !existing[err.Error()] {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func joinedErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// Reload 重新读取配置文件，校验通过后生效（外部编辑配置文件后热加载）
+func (cs *ConfigService) Reload() error {
+	info, err := os.Stat(cs.configPath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(cs.configPath)
+	if err != nil {
+		return err
+	}
+	// 无论能否生效都记录本次修改时间，同一次错误编辑只提示一次
+	cs.mu.Lock()
+	cs.modTime = info.ModTime()
+	cs.mu.Unlock()
+
+	config, err := cs.parseConfig(data)
+	if err != nil {
+		return fmt.Errorf("配置文件格式错误: %w", err)
+	}
+	return cs.apply(config, ConfigSourceFile)
+}
+
+// fileChanged 配置文件是否在外部被修改过
+func (cs *ConfigService) fileChanged() bool {
+	info, err := os.Stat(cs.configPath)
+	if err != nil {
+		return false
+	}
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return !info.ModTime().Equal(cs.modTime)
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// TestConfigChange 测试配置校验、原子替换与变更分区通知
+func TestConfigChange(t *testing.T) {
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes []ConfigChange
+	cs.OnChange(func(c ConfigChange) { changes = append(changes, c) })
+
+	cfg := cs.CloneConfig()
+	cfg.AIConfigs = []models.AIConfig{{ID: "a", Name: "主力", Provider: models.AIProviderOpenAI, ModelName: "gpt-4o", APIKey: "k1"}}
+	cfg.MeetingBudget = 0.5
+	if err := cs.UpdateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || !slices.Equal(changes[0].Sections, []ConfigSection{ConfigSectionAI, ConfigSectionMeeting}) {
+		t.Fatalf("changes: %+v", changes)
+	}
+
+	// 无实际变化不通知
+	if err := cs.UpdateConfig(cs.CloneConfig()); err != nil || len(changes) != 1 {
+		t.Fatalf("no-op update: %v %d", err, len(changes))
+	}
+
+	// 校验失败时保持原配置
+	bad := cs.CloneConfig()
+	bad.AIConfigs[0].ModelName = ""
+	bad.Theme = "ocean"
+	if err := cs.UpdateConfig(bad); err == nil || !strings.Contains(err.Error(), "缺少模型名称") {
+		t.Fatalf("invalid update should fail: %v", err)
+	}
+	if got := cs.GetConfig(); got.AIConfigs[0].ModelName != "gpt-4o" || got.Theme == "ocean" || len(changes) != 1 {
+		t.Fatalf("config changed after rejected update: %+v", got)
+	}
+
+	// 只修改界面设置归入其他分区，变更前配置为副本
+	ui := cs.CloneConfig()
+	ui.Theme = "ocean"
+	if err := cs.UpdateConfig(ui); err != nil {
+		t.Fatal(err)
+	}
+	if last := changes[len(changes)-1]; !slices.Equal(last.Sections, []ConfigSection{ConfigSectionOther}) || last.Old.Theme == "ocean" {
+		t.Fatalf("ui change: %+v", last)
+	}
+}
+
+// TestConfigReload 测试外部编辑配置文件后的热加载
+func TestConfigReload(t *testing.T) {
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes []ConfigChange
+	cs.OnChange(func(c ConfigChange) { changes = append(changes, c) })
+	invalid := 0
+	w := NewConfigWatcher(cs, func(error) { invalid++ })
+
+	// 自身保存不触发重新加载
+	if cs.fileChanged() {
+		t.Fatal("own write should not count as external change")
+	}
+
+	write := func(content string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(cs.configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(cs.configPath, mtime, mtime)
+	}
+
+	write(`{"theme":"purple","aiConfigs":[{"id":"a","name":"主力","provider":"openai","modelName":"gpt-4o","apiKey":"new"}]}`, time.Now().Add(time.Minute))
+	w.check()
+	if len(changes) != 1 || changes[0].Source != ConfigSourceFile || !changes[0].Has(ConfigSectionAI) {
+		t.Fatalf("reload changes: %+v", changes)
+	}
+	if got := cs.GetConfig(); got.Theme != "purple" || got.AIConfigs[0].APIKey != "new" || got.Indicators.MACD.Fast != 12 {
+		t.Fatalf("reloaded config: %+v", got)
+	}
+
+	// 格式错误或校验失败的编辑不生效，且只提示一次
+	write(`{"theme":`, time.Now().Add(2*time.Minute))
+	w.check()
+	w.check()
+	write(`{"aiConfigs":[{"id":"a","provider":"unknown","modelName":"x"}]}`, time.Now().Add(3*time.Minute))
+	w.check()
+	if invalid != 2 || len(changes) != 1 || cs.GetConfig().Theme != "purple" {
+		t.Fatalf("invalid edits: invalid=%d changes=%d theme=%s", invalid, len(changes), cs.GetConfig().Theme)
+	}
+}
+
+func TestOpenConfigServiceDefersWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	cs, err := OpenConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs.GetConfig() == nil || len(cs.GetWatchlistGroups()) == 0 {
+		t.Fatal("defaults should be loaded in memory")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("data dir should not be created on open: %v", err)
+	}
+
+	// 首次保存时创建数据目录
+	if err := cs.AddToWatchlist(models.Stock{Symbol: "sh600000", Name: "浦发银行"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "watchlist.json")); err != nil {
+		t.Errorf("watchlist should be written on first save: %v", err)
+	}
+}
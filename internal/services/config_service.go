@@ -4,10 +4,9 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
-	"strings"
 	"sync"
+	"time"
 
-	"github.com/run-bigpig/jcp/internal/embed"
 	"github.com/run-bigpig/jcp/internal/models"
 )
 
@@ -15,20 +14,39 @@ import (
 type ConfigService struct {
 	configPath    string
 	watchlistPath string
+	groupsPath    string
+	deferWrite    bool // 缺失的配置文件只使用默认值，首次保存时才创建数据目录
 	config        *models.AppConfig
+	applied       *models.AppConfig // 最近一次生效配置的深拷贝，作为变更比较基准
+	modTime       time.Time         // 配置文件最近一次读写时的修改时间
 	watchlist     []models.Stock
+	groups        []models.WatchlistGroup
 	mu            sync.RWMutex
+
+	applyMu   sync.Mutex // 串行化配置变更，保证监听者按顺序收到
+	listeners []func(ConfigChange)
 }
 
-// NewConfigService 创建配置服务
+// NewConfigService 创建配置服务，缺失的配置文件以默认值写入数据目录
 func NewConfigService(dataDir string) (*ConfigService, error) {
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return nil, err
 	}
+	return newConfigService(dataDir, false)
+}
+
+// OpenConfigService 打开配置服务但不写入磁盘：数据目录或配置文件不存在时使用默认值，
+// 首次保存时才创建，供作为库嵌入时避免创建即产生目录
+func OpenConfigService(dataDir string) (*ConfigService, error) {
+	return newConfigService(dataDir, true)
+}
 
+func newConfigService(dataDir string, deferWrite bool) (*ConfigService, error) {
 	cs := &ConfigService{
 		configPath:    filepath.Join(dataDir, "config.json"),
 		watchlistPath: filepath.Join(dataDir, "watchlist.json"),
+		groupsPath:    filepath.Join(dataDir, "watchlist_groups.json"),
+		deferWrite:    deferWrite,
 	}
 
 	if err := cs.loadConfig(); err != nil {
@@ -37,6 +55,9 @@ func NewConfigService(dataDir string) (*ConfigService, error) {
 	if err := cs.loadWatchlist(); err != nil {
 		return nil, err
 	}
+	if err := cs.loadGroups(); err != nil {
+		return nil, err
+	}
 
 	return cs, nil
 }
@@ -49,15 +70,33 @@ func (cs *ConfigService) loadConfig() error {
 	data, err := os.ReadFile(cs.configPath)
 	if os.IsNotExist(err) {
 		cs.config = cs.defaultConfig()
+		cs.applied = cloneConfig(cs.config)
+		if cs.deferWrite {
+			return nil
+		}
 		return cs.saveConfigLocked()
 	}
 	if err != nil {
 		return err
 	}
 
+	config, err := cs.parseConfig(data)
+	if err != nil {
+		return err
+	}
+	cs.config = config
+	cs.applied = cloneConfig(config)
+	if info, err := os.Stat(cs.configPath); err == nil {
+		cs.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// parseConfig 解析配置文件内容，并用默认值补全旧配置缺失的字段
+func (cs *ConfigService) parseConfig(data []byte) (*models.AppConfig, error) {
 	var config models.AppConfig
 	if err := json.Unmarshal(data, &config); err != nil {
-		return err
+		return nil, err
 	}
 
 	// 用于识别字段是否在 JSON 中显式存在（避免把用户明确设置的 false 当成缺失字段）
@@ -84,7 +123,7 @@ func (cs *ConfigService) loadConfig() error {
 		} `json:"indicators"`
 	}
 	if err := json.Unmarshal(data, &raw); err != nil {
-		return err
+		return nil, err
 	}
 
 	// 旧配置文件可能缺少 indicators 字段，Go 零值（nil/0/0.0）会导致前端异常
@@ -142,8 +181,7 @@ func (cs *ConfigService) loadConfig() error {
 	if ind.KDJ.D == 0 {
 		ind.KDJ.D = d.KDJ.D
 	}
-	cs.config = &config
-	return nil
+	return &config, nil
 }
 
 // defaultConfig 默认配置
@@ -154,11 +192,12 @@ func (cs *ConfigService) defaultConfig() *models.AppConfig {
 		AIConfigs:       []models.AIConfig{},
 		DefaultAIID:     "",
 		Memory: models.MemoryConfig{
-			Enabled:           true,
-			MaxRecentRounds:   3,
-			MaxKeyFacts:       20,
-			MaxSummaryLength:  300,
-			CompressThreshold: 5,
+			Enabled:             true,
+			MaxRecentRounds:     3,
+			MaxKeyFacts:         20,
+			MaxSummaryLength:    300,
+			CompressThreshold:   5,
+			ConsolidateInterval: 3,
 		},
 		Indicators: models.IndicatorConfig{
 			MA:   models.MAConfig{Enabled: true, Periods: []int{5, 10, 20}},
@@ -177,7 +216,14 @@ func (cs *ConfigService) saveConfigLocked() error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(cs.configPath, data, 0644)
+	if err := writeConfigFile(cs.configPath, data); err != nil {
+		return err
+	}
+	// 记录自身写入的修改时间，避免被当作外部修改重新加载
+	if info, err := os.Stat(cs.configPath); err == nil {
+		cs.modTime = info.ModTime()
+	}
+	return nil
 }
 
 // GetConfig 获取配置
@@ -187,12 +233,9 @@ func (cs *ConfigService) GetConfig() *models.AppConfig {
 	return cs.config
 }
 
-// UpdateConfig 更新配置
+// UpdateConfig 校验并更新配置，生效后通知变更监听者
 func (cs *ConfigService) UpdateConfig(config *models.AppConfig) error {
-	cs.mu.Lock()
-	defer cs.mu.Unlock()
-	cs.config = config
-	return cs.saveConfigLocked()
+	return cs.apply(config, ConfigSourceAPI)
 }
 
 // loadWatchlist 加载自选股列表
@@ -204,6 +247,9 @@ func (cs *ConfigService) loadWatchlist() error {
 	if os.IsNotExist(err) {
 		// 文件不存在时，初始化为空列表
 		cs.watchlist = []models.Stock{}
+		if cs.deferWrite {
+			return nil
+		}
 		return cs.saveWatchlistLocked()
 	}
 	if err != nil {
@@ -225,7 +271,15 @@ func (cs *ConfigService) saveWatchlistLocked() error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(cs.watchlistPath, data, 0644)
+	return writeConfigFile(cs.watchlistPath, data)
+}
+
+// writeConfigFile 写入配置文件，数据目录不存在时先创建
+func writeConfigFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
 }
 
 // GetWatchlist 获取自选股列表
@@ -257,7 +311,14 @@ func (cs *ConfigService) RemoveFromWatchlist(symbol string) error {
 	for i, s := range cs.watchlist {
 		if s.Symbol == symbol {
 			cs.watchlist = append(cs.watchlist[:i], cs.watchlist[i+1:]...)
-			return cs.saveWatchlistLocked()
+			if err := cs.saveWatchlistLocked(); err != nil {
+				return err
+			}
+			// 同步移出所有分组
+			if cs.removeFromGroupsLocked(symbol) {
+				return cs.saveGroupsLocked()
+			}
+			return nil
 		}
 	}
 	return nil
@@ -285,74 +346,15 @@ func (cs *ConfigService) SearchStocks(keyword string, limit int) []StockSearchRe
 		return []StockSearchResult{}
 	}
 
-	keyword = strings.ToUpper(keyword)
-
-	// 使用嵌入的股票数据
-	var basicData stockBasicData
-	if err := json.Unmarshal(embed.StockBasicJSON, &basicData); err != nil {
-		return []StockSearchResult{}
-	}
-
-	// 找到字段索引
-	var symbolIdx, nameIdx, industryIdx, tsCodeIdx int = -1, -1, -1, -1
-	for i, field := range basicData.Data.Fields {
-		switch field {
-		case "symbol":
-			symbolIdx = i
-		case "name":
-			nameIdx = i
-		case "industry":
-			industryIdx = i
-		case "ts_code":
-			tsCodeIdx = i
-		}
-	}
-
-	if symbolIdx < 0 || nameIdx < 0 {
-		return []StockSearchResult{}
-	}
-
-	var results []StockSearchResult
-	for _, item := range basicData.Data.Items {
-		if len(results) >= limit {
-			break
-		}
-
-		symbol, _ := item[symbolIdx].(string)
-		name, _ := item[nameIdx].(string)
-
-		// 匹配代码或名称
-		upperSymbol := strings.ToUpper(symbol)
-		upperName := strings.ToUpper(name)
-
-		if strings.Contains(upperSymbol, keyword) || strings.Contains(upperName, keyword) {
-			var industry, market, fullSymbol string
-			if industryIdx >= 0 && industryIdx < len(item) {
-				industry, _ = item[industryIdx].(string)
-			}
-			// 从 ts_code 获取市场前缀
-			if tsCodeIdx >= 0 && tsCodeIdx < len(item) {
-				tsCode, _ := item[tsCodeIdx].(string)
-				if strings.HasSuffix(tsCode, ".SH") {
-					market = "上海"
-					fullSymbol = "sh" + symbol
-				} else if strings.HasSuffix(tsCode, ".SZ") {
-					market = "深圳"
-					fullSymbol = "sz" + symbol
-				}
-			}
-			if fullSymbol == "" {
-				fullSymbol = symbol
-			}
-
-			results = append(results, StockSearchResult{
-				Symbol:   fullSymbol,
-				Name:     name,
-				Industry: industry,
-				Market:   market,
-			})
-		}
+	matches := GetStockIndex().Search(keyword, limit)
+	results := make([]StockSearchResult, 0, len(matches))
+	for _, info := range matches {
+		results = append(results, StockSearchResult{
+			Symbol:   info.Symbol,
+			Name:     info.Name,
+			Industry: info.Industry,
+			Market:   info.Market,
+		})
 	}
-
 	return results
 }
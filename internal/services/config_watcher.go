@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+)
+
+var configLog = logger.New("config")
+
+// configCheckEvery 配置文件检查间隔
+const configCheckEvery = 2 * time.Second
+
+// ConfigWatcher 监听配置文件的外部修改，校验通过后热加载并通知变更监听者
+type ConfigWatcher struct {
+	configService *ConfigService
+	onInvalid     func(err error)
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewConfigWatcher 创建配置文件监听，外部修改未通过校验时回调 onInvalid（原配置保持生效）
+func NewConfigWatcher(configService *ConfigService, onInvalid func(err error)) *ConfigWatcher {
+	return &ConfigWatcher{configService: configService, onInvalid: onInvalid}
+}
+
+// Start 启动后台监听
+func (w *ConfigWatcher) Start(ctx context.Context) {
+	w.mu.Lock()
+	if w.cancel != nil {
+		w.mu.Unlock()
+		return
+	}
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.mu.Unlock()
+
+	go w.loop(ctx)
+}
+
+// Stop 停止后台监听
+func (w *ConfigWatcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+	}
+}
+
+func (w *ConfigWatcher) loop(ctx context.Context) {
+	ticker := time.NewTicker(configCheckEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check 检查一次配置文件，有外部修改时重新加载
+func (w *ConfigWatcher) check() {
+	if !w.configService.fileChanged() {
+		return
+	}
+	if err := w.configService.Reload(); err != nil {
+		configLog.Warn("配置文件修改未生效: %v", err)
+		if w.onInvalid != nil {
+			safeCall(func() { w.onInvalid(err) })
+		}
+		return
+	}
+	configLog.Info("配置文件已重新加载")
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"path"
+	"path/filepath"
+)
+
+// encryptedDataPatterns 启用数据加密时需要加密保存的文件：会话（含持仓、交易计划）、记忆、模拟盘账户、会议回放与深度研究检查点
+var encryptedDataPatterns = []string{"sessions/*.json", "memories/*.json", "paper_account.json", "replays/*.json", "research/*.json"}
+
+// EncryptedDataFiles 返回数据目录中需要加密保存的文件
+func EncryptedDataFiles(dataDir string) []string {
+	var files []string
+	for _, pattern := range encryptedDataPatterns {
+		matches, _ := filepath.Glob(filepath.Join(dataDir, filepath.FromSlash(pattern)))
+		files = append(files, matches...)
+	}
+	return files
+}
+
+// isEncryptedDataFile 判断数据目录内的相对路径（/ 分隔）是否需要加密保存
+func isEncryptedDataFile(name string) bool {
+	for _, pattern := range encryptedDataPatterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
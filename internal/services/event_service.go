@@ -0,0 +1,411 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// 东方财富数据中心通用查询（reportName / filter / sortColumns / pageSize）
+const emDatacenterURL = "https://datacenter-web.eastmoney.com/api/data/v1/get?reportName=%s&columns=ALL&filter=%s&sortColumns=%s&sortTypes=-1&pageNumber=1&pageSize=%d&source=WEB&client=WEB"
+
+const (
+	eventCacheTTL     = 1 * time.Hour // 事件日历变动少，缓存1小时
+	eventRowsPerQuery = 5
+)
+
+// 事件类型
+const (
+	EventEarnings = "earnings" // 财报披露
+	EventMeeting  = "meeting"  // 股东大会
+	EventLockup   = "lockup"   // 限售解禁
+	EventDividend = "dividend" // 分红派息
+)
+
+// EventTypeNames 事件类型中文名
+var EventTypeNames = map[string]string{
+	EventEarnings: "财报披露",
+	EventMeeting:  "股东大会",
+	EventLockup:   "限售解禁",
+	EventDividend: "分红派息",
+}
+
+// StockEvent 个股事件（催化剂）
+type StockEvent struct {
+	Code   string `json:"code"`   // 6位代码
+	Name   string `json:"name"`   // 股票名称
+	Type   string `json:"type"`   // 事件类型: earnings / meeting / lockup / dividend
+	Date   string `json:"date"`   // 事件日期 YYYY-MM-DD
+	Title  string `json:"title"`  // 事件标题
+	Detail string `json:"detail"` // 补充说明
+}
+
+// eventCache 个股事件缓存（未按时间窗口过滤）
+type eventCache struct {
+	data      []StockEvent
+	timestamp time.Time
+}
+
+// EventService 个股事件日历服务：财报预约披露、股东大会、限售解禁、分红派息
+type EventService struct {
+	client *http.Client
+	cache  map[string]*eventCache
+	mu     sync.RWMutex
+}
+
+// NewEventService 创建事件日历服务
+func NewEventService() *EventService {
+	return &EventService{
+		client: proxy.GetManager().GetClientWithTimeout(10 * time.Second),
+		cache:  make(map[string]*eventCache),
+	}
+}
+
+// GetStockEvents 获取个股未来 days 天内的事件，按日期升序
+func (s *EventService) GetStockEvents(code string, days int) ([]StockEvent, error) {
+	events, err := s.loadEvents(strings.TrimLeft(code, "shzbj"))
+	if err != nil {
+		return nil, err
+	}
+	return upcomingEvents(events, time.Now(), days), nil
+}
+
+// GetEvents 获取多只股票（如自选股）未来 days 天内的事件，按日期升序
+func (s *EventService) GetEvents(codes []string, days int) ([]StockEvent, error) {
+	results := make([][]StockEvent, len(codes))
+	errs := make([]error, len(codes))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 4) // 限制并发，避免触发限流
+	for i, code := range codes {
+		wg.Add(1)
+		go func(i int, code string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i], errs[i] = s.loadEvents(strings.TrimLeft(code, "shzbj"))
+		}(i, code)
+	}
+	wg.Wait()
+
+	var all []StockEvent
+	var failed []string
+	for i, r := range results {
+		if errs[i] != nil {
+			failed = append(failed, errs[i].Error())
+			continue
+		}
+		all = append(all, r...)
+	}
+	if len(codes) > 0 && len(failed) == len(codes) {
+		return nil, fmt.Errorf("获取事件日历失败: %s", failed[0])
+	}
+	return upcomingEvents(all, time.Now(), days), nil
+}
+
+// loadEvents 读取缓存或并发查询四类事件
+func (s *EventService) loadEvents(code string) ([]StockEvent, error) {
+	s.mu.RLock()
+	if cached, ok := s.cache[code]; ok && time.Since(cached.timestamp) < eventCacheTTL {
+		s.mu.RUnlock()
+		return cached.data, nil
+	}
+	s.mu.RUnlock()
+
+	filter := fmt.Sprintf(`(SECURITY_CODE="%s")`, code)
+	queries := []struct {
+		report string
+		sortBy string
+		parse  func([]byte) ([]StockEvent, error)
+	}{
+		{"RPT_PUBLIC_BS_APPOIN", "REPORT_DATE", parseEarningsEvents},
+		{"RPT_GENERALMEETING_DETAIL", "START_ADJUST_DATE", parseMeetingEvents},
+		{"RPT_LIFT_STAGE", "FREE_DATE", parseLockupEvents},
+		{"RPT_SHAREBONUS_DET", "REPORT_DATE", parseDividendEvents},
+	}
+
+	results := make([][]StockEvent, len(queries))
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body, err := s.get(fmt.Sprintf(emDatacenterURL, q.report, url.QueryEscape(filter), q.sortBy, eventRowsPerQuery))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			results[i], errs[i] = q.parse(body)
+		}(i)
+	}
+	wg.Wait()
+
+	var events []StockEvent
+	var failed []string
+	for i, r := range results {
+		if errs[i] != nil {
+			failed = append(failed, errs[i].Error())
+			continue
+		}
+		events = append(events, r...)
+	}
+	// 所有查询都失败才报错
+	if len(failed) == len(queries) {
+		return nil, fmt.Errorf("获取%s事件失败: %s", code, strings.Join(failed, "; "))
+	}
+
+	market := 0
+	if strings.HasPrefix(code, "6") {
+		market = 1
+	}
+	name := GetStockIndex().Name(emSymbol(code, market))
+	for i := range events {
+		events[i].Code = code
+		if events[i].Name == "" {
+			events[i].Name = name
+		}
+	}
+
+	s.mu.Lock()
+	s.cache[code] = &eventCache{data: events, timestamp: time.Now()}
+	s.mu.Unlock()
+	return events, nil
+}
+
+func (s *EventService) get(target string) ([]byte, error) {
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", "https://data.eastmoney.com/")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// decodeDatacenter 解析数据中心响应的 result.data，无数据时 result 为 null
+func decodeDatacenter(body []byte, rows any) error {
+	var resp struct {
+		Result *struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("解析数据中心响应失败: %w", err)
+	}
+	if resp.Result == nil || len(resp.Result.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Result.Data, rows)
+}
+
+// parseEarningsEvents 解析财报预约披露时间
+func parseEarningsEvents(body []byte) ([]StockEvent, error) {
+	var rows []struct {
+		Name         string `json:"SECURITY_NAME_ABBR"`
+		ReportDate   string `json:"REPORT_DATE"`
+		FirstAppoint string `json:"FIRST_APPOINT_DATE"`
+		FirstChange  string `json:"FIRST_CHANGE_DATE"`
+		SecondChange string `json:"SECOND_CHANGE_DATE"`
+		ThirdChange  string `json:"THIRD_CHANGE_DATE"`
+		Actual       string `json:"ACTUAL_PUBLISH_DATE"`
+	}
+	if err := decodeDatacenter(body, &rows); err != nil {
+		return nil, err
+	}
+
+	events := make([]StockEvent, 0, len(rows))
+	for _, r := range rows {
+		date, detail := eventDate(r.FirstAppoint), "预约披露"
+		for _, changed := range []string{r.FirstChange, r.SecondChange, r.ThirdChange} {
+			if d := eventDate(changed); d != "" {
+				date, detail = d, fmt.Sprintf("预约日期已变更（原定%s）", eventDate(r.FirstAppoint))
+			}
+		}
+		if d := eventDate(r.Actual); d != "" {
+			date, detail = d, "已披露"
+		}
+		if date == "" {
+			continue
+		}
+		events = append(events, StockEvent{
+			Name:   r.Name,
+			Type:   EventEarnings,
+			Date:   date,
+			Title:  reportPeriodName(eventDate(r.ReportDate)) + "披露",
+			Detail: detail,
+		})
+	}
+	return events, nil
+}
+
+// parseMeetingEvents 解析股东大会
+func parseMeetingEvents(body []byte) ([]StockEvent, error) {
+	var rows []struct {
+		Name       string `json:"SECURITY_NAME_ABBR"`
+		Title      string `json:"MEETING_TITLE"`
+		Date       string `json:"START_ADJUST_DATE"`
+		RecordDate string `json:"EQUITY_RECORD_DATE"`
+	}
+	if err := decodeDatacenter(body, &rows); err != nil {
+		return nil, err
+	}
+
+	events := make([]StockEvent, 0, len(rows))
+	for _, r := range rows {
+		date := eventDate(r.Date)
+		if date == "" {
+			continue
+		}
+		var detail string
+		if d := eventDate(r.RecordDate); d != "" {
+			detail = "股权登记日 " + d
+		}
+		events = append(events, StockEvent{
+			Name:   r.Name,
+			Type:   EventMeeting,
+			Date:   date,
+			Title:  strings.TrimSpace(r.Title),
+			Detail: detail,
+		})
+	}
+	return events, nil
+}
+
+// parseLockupEvents 解析限售解禁
+func parseLockupEvents(body []byte) ([]StockEvent, error) {
+	var rows []struct {
+		Name       string  `json:"SECURITY_NAME_ABBR"`
+		Date       string  `json:"FREE_DATE"`
+		SharesType string  `json:"FREE_SHARES_TYPE"`
+		MarketCap  float64 `json:"LIFT_MARKET_CAP"` // 元
+	}
+	if err := decodeDatacenter(body, &rows); err != nil {
+		return nil, err
+	}
+
+	events := make([]StockEvent, 0, len(rows))
+	for _, r := range rows {
+		date := eventDate(r.Date)
+		if date == "" {
+			continue
+		}
+		title := "限售股解禁"
+		if r.SharesType != "" {
+			title = r.SharesType + "解禁"
+		}
+		var detail string
+		if r.MarketCap > 0 {
+			detail = i18n.Sprintf("解禁市值约%s元", i18n.Amount(r.MarketCap))
+		}
+		events = append(events, StockEvent{
+			Name:   r.Name,
+			Type:   EventLockup,
+			Date:   date,
+			Title:  title,
+			Detail: detail,
+		})
+	}
+	return events, nil
+}
+
+// parseDividendEvents 解析分红送转，以除权除息日为事件日期（未公布时用股权登记日）
+func parseDividendEvents(body []byte) ([]StockEvent, error) {
+	var rows []struct {
+		Name       string `json:"SECURITY_NAME_ABBR"`
+		Plan       string `json:"IMPL_PLAN_PROFILE"`
+		Progress   string `json:"ASSIGN_PROGRESS"`
+		RecordDate string `json:"EQUITY_RECORD_DATE"`
+		ExDate     string `json:"EX_DIVIDEND_DATE"`
+	}
+	if err := decodeDatacenter(body, &rows); err != nil {
+		return nil, err
+	}
+
+	events := make([]StockEvent, 0, len(rows))
+	for _, r := range rows {
+		date, title := eventDate(r.ExDate), "除权除息"
+		if date == "" {
+			date, title = eventDate(r.RecordDate), "股权登记"
+		}
+		if date == "" || r.Plan == "" {
+			continue
+		}
+		var parts []string
+		if d := eventDate(r.RecordDate); d != "" && d != date {
+			parts = append(parts, "股权登记日 "+d)
+		}
+		if r.Progress != "" {
+			parts = append(parts, r.Progress)
+		}
+		events = append(events, StockEvent{
+			Name:   r.Name,
+			Type:   EventDividend,
+			Date:   date,
+			Title:  title + ": " + r.Plan,
+			Detail: strings.Join(parts, " | "),
+		})
+	}
+	return events, nil
+}
+
+// upcomingEvents 过滤出 [now, now+days] 内的事件，按日期升序
+func upcomingEvents(events []StockEvent, now time.Time, days int) []StockEvent {
+	from := now.Format("2006-01-02")
+	to := now.AddDate(0, 0, days).Format("2006-01-02")
+
+	result := make([]StockEvent, 0, len(events))
+	for _, e := range events {
+		if e.Date >= from && e.Date <= to {
+			result = append(result, e)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Date != result[j].Date {
+			return result[i].Date < result[j].Date
+		}
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
+
+// eventDate 截取日期部分，"2024-08-10 00:00:00" -> "2024-08-10"
+func eventDate(s string) string {
+	if len(s) < 10 {
+		return ""
+	}
+	return s[:10]
+}
+
+// reportPeriodName 报告期名称，如 "2024-06-30" -> "2024年半年报"
+func reportPeriodName(reportDate string) string {
+	if len(reportDate) < 10 {
+		return "定期报告"
+	}
+	year := reportDate[:4]
+	switch reportDate[5:] {
+	case "03-31":
+		return year + "年一季报"
+	case "06-30":
+		return year + "年半年报"
+	case "09-30":
+		return year + "年三季报"
+	case "12-31":
+		return year + "年年报"
+	}
+	return year + "年定期报告"
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEarningsEvents(t *testing.T) {
+	body := []byte(`{"success":true,"result":{"data":[{"SECURITY_NAME_ABBR":"贵州茅台","REPORT_DATE":"2024-06-30 00:00:00","FIRST_APPOINT_DATE":"2024-08-08 00:00:00","FIRST_CHANGE_DATE":"2024-08-09 00:00:00","SECOND_CHANGE_DATE":null,"THIRD_CHANGE_DATE":null,"ACTUAL_PUBLISH_DATE":null}]}}`)
+	events, err := parseEarningsEvents(body)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	e := events[0]
+	if e.Type != EventEarnings || e.Date != "2024-08-09" || e.Title != "2024年半年报披露" || e.Detail != "预约日期已变更（原定2024-08-08）" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+}
+
+func TestParseDividendAndLockupEvents(t *testing.T) {
+	dividend := []byte(`{"result":{"data":[{"SECURITY_NAME_ABBR":"贵州茅台","IMPL_PLAN_PROFILE":"10派308.76元(含税)","ASSIGN_PROGRESS":"实施分配","EQUITY_RECORD_DATE":"2024-06-18 00:00:00","EX_DIVIDEND_DATE":"2024-06-19 00:00:00"}]}}`)
+	events, err := parseDividendEvents(dividend)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("parse dividend: %v, %d events", err, len(events))
+	}
+	if events[0].Date != "2024-06-19" || events[0].Title != "除权除息: 10派308.76元(含税)" || events[0].Detail != "股权登记日 2024-06-18 | 实施分配" {
+		t.Errorf("unexpected dividend event: %+v", events[0])
+	}
+
+	lockup := []byte(`{"result":{"data":[{"SECURITY_NAME_ABBR":"某股份","FREE_DATE":"2024-07-01 00:00:00","FREE_SHARES_TYPE":"首发原股东限售股份","LIFT_MARKET_CAP":1234000000}]}}`)
+	events, err = parseLockupEvents(lockup)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("parse lockup: %v, %d events", err, len(events))
+	}
+	if events[0].Title != "首发原股东限售股份解禁" || events[0].Detail != "解禁市值约12.34亿元" {
+		t.Errorf("unexpected lockup event: %+v", events[0])
+	}
+
+	// 无数据时 result 为 null
+	events, err = parseMeetingEvents([]byte(`{"success":false,"message":"返回数据为空","result":null}`))
+	if err != nil || len(events) != 0 {
+		t.Errorf("empty result should yield no events: %v, %+v", err, events)
+	}
+}
+
+func TestUpcomingEvents(t *testing.T) {
+	now := time.Date(2024, 6, 20, 15, 0, 0, 0, time.Local)
+	events := upcomingEvents([]StockEvent{
+		{Code: "600519", Date: "2024-07-15"},
+		{Code: "000001", Date: "2024-06-19"},
+		{Code: "000001", Date: "2024-06-20"},
+		{Code: "600519", Date: "2024-08-30"},
+	}, now, 30)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if events[0].Date != "2024-06-20" || events[1].Date != "2024-07-15" {
+		t.Errorf("events should be sorted ascending within window: %+v", events)
+	}
+}
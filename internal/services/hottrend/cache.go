@@ -21,11 +21,8 @@ type FileCache struct {
 	mu       sync.RWMutex
 }
 
-// NewFileCache 创建文件缓存
+// NewFileCache 创建文件缓存，缓存目录在首次写入时创建
 func NewFileCache(cacheDir string, ttl time.Duration) (*FileCache, error) {
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return nil, err
-	}
 	return &FileCache{
 		cacheDir: cacheDir,
 		ttl:      ttl,
@@ -76,5 +73,8 @@ func (c *FileCache) Set(platform string, items []HotItem) error {
 		return err
 	}
 
+	if err := os.MkdirAll(c.cacheDir, 0755); err != nil {
+		return err
+	}
 	return os.WriteFile(c.cacheFilePath(platform), data, 0644)
 }
@@ -2,10 +2,13 @@ package hottrend
 
 import (
 	"testing"
+
+	"github.com/run-bigpig/jcp/internal/pkg/fixture"
 )
 
 // TestAllFetchers 测试所有平台的 fetcher
 func TestAllFetchers(t *testing.T) {
+	fixture.Install(t, "testdata/fixtures")
 	fetchers := []Fetcher{
 		NewWeiboFetcher(),
 		NewZhihuFetcher(),
@@ -13,6 +16,9 @@ func TestAllFetchers(t *testing.T) {
 		NewBaiduFetcher(),
 		NewDouyinFetcher(),
 		NewToutiaoFetcher(),
+		NewXueqiuFetcher(),
+		NewGubaFetcher(),
+		NewTHSFetcher(),
 	}
 
 	for _, f := range fetchers {
@@ -23,6 +29,9 @@ func TestAllFetchers(t *testing.T) {
 				return
 			}
 			t.Logf("%s: 获取到 %d 条热点", f.PlatformCN(), len(items))
+			if len(items) == 0 {
+				t.Errorf("%s: 没有解析到热点", f.PlatformCN())
+			}
 			if len(items) > 0 {
 				t.Logf("  第1条: %s", items[0].Title)
 			}
@@ -0,0 +1,88 @@
+package hottrend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+// GubaFetcher 东方财富股吧人气榜获取器
+type GubaFetcher struct {
+	client *http.Client
+}
+
+// NewGubaFetcher 创建东方财富股吧人气榜获取器
+func NewGubaFetcher() *GubaFetcher {
+	return &GubaFetcher{
+		client: proxy.GetManager().GetClientWithTimeout(10 * time.Second),
+	}
+}
+
+func (f *GubaFetcher) Platform() string   { return "guba" }
+func (f *GubaFetcher) PlatformCN() string { return "股吧人气榜" }
+
+// gubaResponse 股吧人气榜API响应结构（仅返回代码，名称从本地股票索引补全）
+type gubaResponse struct {
+	Data []struct {
+		Code   string `json:"sc"` // 如 SZ000001
+		Rank   int    `json:"rk"` // 当前排名
+		Change int    `json:"rc"` // 排名变化
+		HisRC  int    `json:"hisRc"`
+	} `json:"data"`
+}
+
+// Fetch 获取东方财富股吧人气榜
+func (f *GubaFetcher) Fetch() ([]HotItem, error) {
+	url := "https://emappdata.eastmoney.com/stockrank/getAllCurrentList"
+	payload := []byte(`{"appId":"appId01","globalId":"786e4c21-70dc-435a-93bb-38","marketType":"","pageNo":1,"pageSize":50}`)
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Referer", "https://guba.eastmoney.com/rank/")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result gubaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	index := services.GetStockIndex()
+	var items []HotItem
+	for _, item := range result.Data {
+		symbol := strings.ToLower(item.Code)
+		name := index.Name(symbol)
+		if name == "" {
+			name = symbol
+		}
+		extra := symbol
+		if item.Change > 0 {
+			extra += fmt.Sprintf(" ↑%d", item.Change)
+		} else if item.Change < 0 {
+			extra += fmt.Sprintf(" ↓%d", -item.Change)
+		}
+		items = append(items, HotItem{
+			ID:       fmt.Sprintf("guba_%d", item.Rank),
+			Title:    name,
+			URL:      fmt.Sprintf("https://guba.eastmoney.com/list,%s.html", strings.TrimLeft(symbol, "shzbj")),
+			Rank:     item.Rank,
+			Platform: "guba",
+			Extra:    extra,
+		})
+	}
+	return items, nil
+}
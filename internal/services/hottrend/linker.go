@@ -0,0 +1,221 @@
+package hottrend
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+// LinkedStock 热点标题关联的股票
+type LinkedStock struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Industry string `json:"industry"`
+	Keyword  string `json:"keyword"` // 命中的关键词（股票简称或别名）
+}
+
+// HotStockLink 热点条目与股票/板块的关联结果
+type HotStockLink struct {
+	HotItem
+	PlatformCN string        `json:"platform_cn"`
+	Stocks     []LinkedStock `json:"stocks"`
+	Sectors    []string      `json:"sectors"`
+}
+
+// stockAliases 常用简称/品牌 → 股票代码，补充股票简称匹配不到的叫法
+var stockAliases = map[string][]string{
+	"茅台":  {"sh600519"},
+	"宁德":  {"sz300750"},
+	"讯飞":  {"sz002230"},
+	"问界":  {"sh601127"},
+	"中芯":  {"sh688981"},
+	"万科":  {"sz000002"},
+	"京东方": {"sz000725"},
+	"格力":  {"sz000651"},
+	"海天":  {"sh603288"},
+	"伊利":  {"sh600887"},
+	"中免":  {"sh601888"},
+	"顺丰":  {"sz002352"},
+	"国航":  {"sh601111"},
+	"东航":  {"sh600115"},
+	"南航":  {"sh600029"},
+	"隆基":  {"sh601012"},
+	"海康":  {"sz002415"},
+	"立讯":  {"sz002475"},
+	"中石油": {"sh601857"},
+	"中石化": {"sh600028"},
+	"招行":  {"sh600036"},
+}
+
+// sectorKeywords 热点关键词 → 行业（与 stock_basic 行业名称一致）
+var sectorKeywords = map[string]string{
+	"芯片":   "半导体",
+	"光刻机":  "半导体",
+	"新能源车": "汽车整车",
+	"电动车":  "汽车整车",
+	"锂电":   "电气设备",
+	"光伏":   "电气设备",
+	"储能":   "电气设备",
+	"券商":   "证券",
+	"牛市":   "证券",
+	"A股":   "证券",
+	"房价":   "全国地产",
+	"楼市":   "全国地产",
+	"房地产":  "全国地产",
+	"票房":   "影视音像",
+	"电影":   "影视音像",
+	"短剧":   "影视音像",
+	"游戏":   "软件服务",
+	"人工智能": "软件服务",
+	"大模型":  "软件服务",
+	"AI":   "软件服务",
+	"机器人":  "专用机械",
+	"金价":   "黄金",
+	"油价":   "石油开采",
+	"机票":   "空运",
+	"航班":   "空运",
+	"集采":   "化学制药",
+	"疫苗":   "生物制药",
+	"猪价":   "农业综合",
+	"猪肉":   "农业综合",
+	"奶粉":   "乳制品",
+	"家电":   "家用电器",
+	"手机":   "通信设备",
+	"5G":   "通信设备",
+	"稀土":   "小金属",
+	"铜价":   "铜",
+	"煤价":   "煤炭开采",
+	"快递":   "仓储物流",
+	"免税":   "旅游服务",
+	"景区":   "旅游景点",
+	"酒店":   "酒店餐饮",
+}
+
+// genericIndustries 过于宽泛、不参与标题直接匹配的行业名
+var genericIndustries = map[string]bool{
+	"综合类":  true,
+	"其他商业": true,
+	"其他建材": true,
+	"互联网":  true,
+}
+
+// linkKey 匹配关键词
+type linkKey struct {
+	keyword string
+	symbols []string
+}
+
+// StockLinker 热点标题实体链接：按股票简称、别名和行业关键词关联A股标的
+type StockLinker struct {
+	stocks     map[string]services.StockBasicInfo // symbol → 基础信息
+	keys       []linkKey                          // 按关键词长度降序，优先匹配长词
+	sectors    map[string]string                  // 关键词 → 行业
+	sectorKeys []string                           // 行业关键词，按长度降序
+}
+
+// NewStockLinker 基于股票基础数据构建实体链接器
+func NewStockLinker(stocks []services.StockBasicInfo) *StockLinker {
+	l := &StockLinker{
+		stocks:  make(map[string]services.StockBasicInfo, len(stocks)),
+		sectors: make(map[string]string, len(sectorKeywords)),
+	}
+
+	byKeyword := make(map[string][]string)
+	for _, s := range stocks {
+		l.stocks[s.Symbol] = s
+		if s.Industry != "" && !genericIndustries[s.Industry] {
+			l.sectors[s.Industry] = s.Industry
+		}
+		if key := stockNameKey(s.Name); key != "" {
+			byKeyword[key] = append(byKeyword[key], s.Symbol)
+		}
+	}
+	for alias, symbols := range stockAliases {
+		for _, sym := range symbols {
+			if _, ok := l.stocks[sym]; ok {
+				byKeyword[alias] = append(byKeyword[alias], sym)
+			}
+		}
+	}
+	for kw, industry := range sectorKeywords {
+		l.sectors[kw] = industry
+	}
+
+	for kw, symbols := range byKeyword {
+		l.keys = append(l.keys, linkKey{keyword: kw, symbols: symbols})
+	}
+	sort.Slice(l.keys, func(i, j int) bool { return longerFirst(l.keys[i].keyword, l.keys[j].keyword) })
+	for kw := range l.sectors {
+		l.sectorKeys = append(l.sectorKeys, kw)
+	}
+	sort.Slice(l.sectorKeys, func(i, j int) bool { return longerFirst(l.sectorKeys[i], l.sectorKeys[j]) })
+	return l
+}
+
+// Link 识别标题中提及的股票与行业，未命中时两者均为空
+func (l *StockLinker) Link(title string) ([]LinkedStock, []string) {
+	var stocks []LinkedStock
+	var matched []string
+	seen := make(map[string]bool)
+	for _, k := range l.keys {
+		if !strings.Contains(title, k.keyword) || coveredBy(k.keyword, matched) {
+			continue
+		}
+		matched = append(matched, k.keyword)
+		for _, sym := range k.symbols {
+			if seen[sym] {
+				continue
+			}
+			seen[sym] = true
+			info := l.stocks[sym]
+			stocks = append(stocks, LinkedStock{Symbol: sym, Name: info.Name, Industry: info.Industry, Keyword: k.keyword})
+		}
+	}
+
+	var sectors []string
+	sectorSeen := make(map[string]bool)
+	for _, kw := range l.sectorKeys {
+		if !strings.Contains(title, kw) {
+			continue
+		}
+		if industry := l.sectors[kw]; !sectorSeen[industry] {
+			sectorSeen[industry] = true
+			sectors = append(sectors, industry)
+		}
+	}
+	return stocks, sectors
+}
+
+// stockNameKey 股票简称匹配键：去掉 ST 前缀与 A/B 股后缀，过短的简称不参与匹配
+func stockNameKey(name string) string {
+	key := strings.TrimPrefix(strings.TrimPrefix(name, "*"), "ST")
+	minLen := 2
+	if key != name {
+		minLen = 3 // ST 股去前缀后的两字短名容易误匹配
+	}
+	key = strings.TrimRight(key, "AB")
+	if utf8.RuneCountInString(key) < minLen {
+		return ""
+	}
+	return key
+}
+
+// coveredBy 关键词是否为已命中长词的一部分（如「中免」被「中国中免」覆盖）
+func coveredBy(keyword string, matched []string) bool {
+	for _, m := range matched {
+		if strings.Contains(m, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func longerFirst(a, b string) bool {
+	la, lb := utf8.RuneCountInString(a), utf8.RuneCountInString(b)
+	if la != lb {
+		return la > lb
+	}
+	return a < b
+}
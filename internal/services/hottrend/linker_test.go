@@ -0,0 +1,40 @@
+package hottrend
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+func TestStockLinker(t *testing.T) {
+	linker := NewStockLinker([]services.StockBasicInfo{
+		{Symbol: "sh600519", Name: "贵州茅台", Industry: "白酒"},
+		{Symbol: "sz000002", Name: "万科A", Industry: "全国地产"},
+		{Symbol: "sh601888", Name: "中国中免", Industry: "旅游服务"},
+		{Symbol: "sz300001", Name: "*ST高斯", Industry: "综合类"},
+	})
+
+	stocks, sectors := linker.Link("茅台回应涨价传闻 白酒板块集体走强")
+	if len(stocks) != 1 || stocks[0].Symbol != "sh600519" || stocks[0].Keyword != "茅台" {
+		t.Errorf("stocks = %+v", stocks)
+	}
+	if len(sectors) != 1 || sectors[0] != "白酒" {
+		t.Errorf("sectors = %v", sectors)
+	}
+
+	// 全称命中后别名不重复计入
+	stocks, _ = linker.Link("中国中免三亚免税店客流创新高")
+	if len(stocks) != 1 || stocks[0].Keyword != "中国中免" {
+		t.Errorf("full name should cover alias, got %+v", stocks)
+	}
+
+	// A 股后缀去除后匹配
+	if stocks, _ := linker.Link("万科最新债务进展"); len(stocks) != 1 || stocks[0].Symbol != "sz000002" {
+		t.Errorf("A-share suffix not stripped: %+v", stocks)
+	}
+
+	// ST 股两字短名与泛化行业不参与匹配
+	if stocks, sectors := linker.Link("高斯定理与综合类考试"); len(stocks) != 0 || len(sectors) != 0 {
+		t.Errorf("expected no match, got %+v %v", stocks, sectors)
+	}
+}
@@ -0,0 +1,134 @@
+package hottrend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/eventbus"
+	"github.com/run-bigpig/jcp/internal/logger"
+)
+
+var refresherLog = logger.New("hottrend")
+
+// EventHotTrendUpdate 热点榜单发生明显变化时推送，载荷为变化平台的 []HotTrendResult
+const EventHotTrendUpdate = "hottrend:update"
+
+const (
+	refreshInterval = 5*time.Minute + 30*time.Second // 略长于文件缓存 TTL，保证每轮取到新数据
+	changeTopN      = 10                             // 只比较前 N 条
+	changeMinNew    = 3                              // 前 N 条新增至少 3 条视为明显变化
+)
+
+// refresher 后台刷新状态
+type refresher struct {
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	lastTops map[string][]string // 平台 → 上次推送时的前 N 条标题
+}
+
+// Start 启动后台刷新：定时拉取所有平台，榜单明显变化时推送 EventHotTrendUpdate
+func (s *HotTrendService) Start(ctx context.Context, bus eventbus.Bus) {
+	s.refresher.mu.Lock()
+	if s.refresher.cancel != nil {
+		s.refresher.mu.Unlock()
+		return
+	}
+	loopCtx, cancel := context.WithCancel(ctx)
+	s.refresher.cancel = cancel
+	s.refresher.lastTops = make(map[string][]string)
+	s.refresher.mu.Unlock()
+
+	go s.refreshLoop(loopCtx, bus)
+}
+
+// Stop 停止后台刷新
+func (s *HotTrendService) Stop() {
+	s.refresher.mu.Lock()
+	defer s.refresher.mu.Unlock()
+	if s.refresher.cancel != nil {
+		s.refresher.cancel()
+		s.refresher.cancel = nil
+	}
+}
+
+// refreshLoop 刷新循环
+func (s *HotTrendService) refreshLoop(ctx context.Context, bus eventbus.Bus) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	// 启动时先记录一次基线（首次出现的平台不推送）
+	s.refreshOnce(bus)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshOnce(bus)
+		}
+	}
+}
+
+// refreshOnce 拉取所有平台并推送明显变化的平台
+func (s *HotTrendService) refreshOnce(bus eventbus.Bus) {
+	defer func() {
+		if r := recover(); r != nil {
+			refresherLog.Error("panic recovered: %v", r)
+		}
+	}()
+
+	var changed []HotTrendResult
+	for _, result := range s.GetAllHotTrends() {
+		if result.Error != "" {
+			continue
+		}
+		tops := topTitles(result.Items, changeTopN)
+
+		s.refresher.mu.Lock()
+		prev, seen := s.refresher.lastTops[result.Platform]
+		material := !seen || rankingChanged(prev, tops)
+		if material {
+			s.refresher.lastTops[result.Platform] = tops
+		}
+		s.refresher.mu.Unlock()
+
+		if material && seen {
+			changed = append(changed, result)
+		}
+	}
+
+	if len(changed) > 0 {
+		refresherLog.Debug("热点榜单变化, 推送%d个平台", len(changed))
+		bus.Emit(EventHotTrendUpdate, changed)
+	}
+}
+
+// topTitles 取前 n 条标题
+func topTitles(items []HotItem, n int) []string {
+	titles := make([]string, 0, n)
+	for i := 0; i < len(items) && i < n; i++ {
+		titles = append(titles, items[i].Title)
+	}
+	return titles
+}
+
+// rankingChanged 判断榜单是否明显变化：榜首易主，或前 N 条新增不少于 changeMinNew 条
+func rankingChanged(prev, cur []string) bool {
+	if len(cur) == 0 {
+		return false
+	}
+	if len(prev) == 0 || prev[0] != cur[0] {
+		return true
+	}
+	old := make(map[string]bool, len(prev))
+	for _, t := range prev {
+		old[t] = true
+	}
+	added := 0
+	for _, t := range cur {
+		if !old[t] {
+			added++
+		}
+	}
+	return added >= changeMinNew
+}
@@ -1,22 +1,29 @@
 package hottrend
 
 import (
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/run-bigpig/jcp/internal/pkg/paths"
+	"github.com/run-bigpig/jcp/internal/services"
 )
 
 // HotTrendService 舆情热点聚合服务
 type HotTrendService struct {
 	fetchers map[string]Fetcher
 	cache    *FileCache
+
+	linker     *StockLinker
+	linkerOnce sync.Once
+
+	refresher refresher
 }
 
 // NewHotTrendService 创建舆情热点服务
 func NewHotTrendService() (*HotTrendService, error) {
 	// 获取缓存目录
-	cacheDir := paths.EnsureCacheDir("hottrend")
+	cacheDir := filepath.Join(paths.GetCacheDir(), "hottrend")
 
 	// 创建文件缓存，TTL 5分钟
 	cache, err := NewFileCache(cacheDir, 5*time.Minute)
@@ -32,6 +39,9 @@ func NewHotTrendService() (*HotTrendService, error) {
 		"baidu":    NewBaiduFetcher(),
 		"douyin":   NewDouyinFetcher(),
 		"toutiao":  NewToutiaoFetcher(),
+		"xueqiu":   NewXueqiuFetcher(),
+		"guba":     NewGubaFetcher(),
+		"ths":      NewTHSFetcher(),
 	}
 
 	return &HotTrendService{
@@ -113,3 +123,34 @@ func (s *HotTrendService) GetHotTrends(platforms []string) []HotTrendResult {
 	wg.Wait()
 	return results
 }
+
+// GetHotTrendStocks 获取指定平台（为空则全部平台）中能关联到A股标的或行业的热点
+func (s *HotTrendService) GetHotTrendStocks(platforms []string) []HotStockLink {
+	s.linkerOnce.Do(func() {
+		s.linker = NewStockLinker(services.GetStockIndex().All())
+	})
+
+	var results []HotTrendResult
+	if len(platforms) == 0 {
+		results = s.GetAllHotTrends()
+	} else {
+		results = s.GetHotTrends(platforms)
+	}
+
+	var links []HotStockLink
+	for _, r := range results {
+		for _, item := range r.Items {
+			stocks, sectors := s.linker.Link(item.Title)
+			if len(stocks) == 0 && len(sectors) == 0 {
+				continue
+			}
+			links = append(links, HotStockLink{
+				HotItem:    item,
+				PlatformCN: r.PlatformCN,
+				Stocks:     stocks,
+				Sectors:    sectors,
+			})
+		}
+	}
+	return links
+}
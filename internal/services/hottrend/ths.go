@@ -0,0 +1,98 @@
+package hottrend
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// THSFetcher 同花顺热榜获取器
+type THSFetcher struct {
+	client *http.Client
+}
+
+// NewTHSFetcher 创建同花顺热榜获取器
+func NewTHSFetcher() *THSFetcher {
+	return &THSFetcher{
+		client: proxy.GetManager().GetClientWithTimeout(10 * time.Second),
+	}
+}
+
+func (f *THSFetcher) Platform() string   { return "ths" }
+func (f *THSFetcher) PlatformCN() string { return "同花顺热榜" }
+
+// thsResponse 同花顺热榜API响应结构
+type thsResponse struct {
+	StatusCode int    `json:"status_code"`
+	StatusMsg  string `json:"status_msg"`
+	Data       struct {
+		StockList []struct {
+			Code        string  `json:"code"`
+			Name        string  `json:"name"`
+			Rate        string  `json:"rate"` // 热度值
+			RiseAndFall float64 `json:"rise_and_fall"`
+			Order       int     `json:"order"`
+			Tag         struct {
+				ConceptTag    []string `json:"concept_tag"`
+				PopularityTag string   `json:"popularity_tag"`
+			} `json:"tag"`
+		} `json:"stock_list"`
+	} `json:"data"`
+}
+
+// Fetch 获取同花顺A股小时热榜
+func (f *THSFetcher) Fetch() ([]HotItem, error) {
+	url := "https://dq.10jqka.com.cn/fuyao/hot_list_data/out/hot_list/v1/stock?stock_type=a&type=hour&list_type=normal"
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (iPhone; CPU iPhone OS 14_0 like Mac OS X)")
+	req.Header.Set("Referer", "https://eq.10jqka.com.cn/")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result thsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.StatusCode != 0 {
+		return nil, fmt.Errorf("ths api error: %d %s", result.StatusCode, result.StatusMsg)
+	}
+
+	var items []HotItem
+	for i, item := range result.Data.StockList {
+		if item.Name == "" {
+			continue
+		}
+		rank := item.Order
+		if rank == 0 {
+			rank = i + 1
+		}
+		score, _ := strconv.ParseFloat(item.Rate, 64)
+		extra := fmt.Sprintf("%+.2f%%", item.RiseAndFall)
+		if len(item.Tag.ConceptTag) > 0 {
+			extra += " " + strings.Join(item.Tag.ConceptTag, "/")
+		}
+		items = append(items, HotItem{
+			ID:       fmt.Sprintf("ths_%d", rank),
+			Title:    item.Name,
+			URL:      fmt.Sprintf("https://stockpage.10jqka.com.cn/%s/", item.Code),
+			HotScore: int(score),
+			Rank:     rank,
+			Platform: "ths",
+			Extra:    extra,
+		})
+	}
+	return items, nil
+}
@@ -38,6 +38,9 @@ var SupportedPlatforms = []PlatformInfo{
 	{ID: "baidu", Name: "百度热搜", HomeURL: "https://www.baidu.com"},
 	{ID: "douyin", Name: "抖音热点", HomeURL: "https://www.douyin.com"},
 	{ID: "toutiao", Name: "头条热榜", HomeURL: "https://www.toutiao.com"},
+	{ID: "xueqiu", Name: "雪球热股", HomeURL: "https://xueqiu.com"},
+	{ID: "guba", Name: "股吧人气榜", HomeURL: "https://guba.eastmoney.com/rank/"},
+	{ID: "ths", Name: "同花顺热榜", HomeURL: "https://eq.10jqka.com.cn/"},
 }
 
 // Fetcher 热点数据获取接口
@@ -0,0 +1,96 @@
+package hottrend
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// XueqiuFetcher 雪球热股榜获取器
+type XueqiuFetcher struct {
+	client *http.Client
+}
+
+// NewXueqiuFetcher 创建雪球热股榜获取器
+func NewXueqiuFetcher() *XueqiuFetcher {
+	client := proxy.GetManager().GetClientWithTimeout(10 * time.Second)
+	// 雪球接口需要先访问首页获取 xq_a_token cookie
+	client.Jar, _ = cookiejar.New(nil)
+	return &XueqiuFetcher{client: client}
+}
+
+func (f *XueqiuFetcher) Platform() string   { return "xueqiu" }
+func (f *XueqiuFetcher) PlatformCN() string { return "雪球热股" }
+
+// xueqiuResponse 雪球热股API响应结构
+type xueqiuResponse struct {
+	Data struct {
+		Items []struct {
+			Code    string  `json:"code"` // 如 SH600519
+			Name    string  `json:"name"`
+			Value   float64 `json:"value"` // 热度
+			Percent float64 `json:"percent"`
+			Current float64 `json:"current"`
+		} `json:"items"`
+	} `json:"data"`
+	ErrorCode int    `json:"error_code"`
+	ErrorDesc string `json:"error_description"`
+}
+
+// Fetch 获取雪球沪深热股榜
+func (f *XueqiuFetcher) Fetch() ([]HotItem, error) {
+	const ua = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36"
+
+	// 预热 cookie
+	if warm, err := http.NewRequest("GET", "https://xueqiu.com/", nil); err == nil {
+		warm.Header.Set("User-Agent", ua)
+		if resp, err := f.client.Do(warm); err == nil {
+			resp.Body.Close()
+		}
+	}
+
+	url := "https://stock.xueqiu.com/v5/stock/hot_stock/list.json?size=50&_type=12&type=12"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", ua)
+	req.Header.Set("Referer", "https://xueqiu.com/")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result xueqiuResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.ErrorCode != 0 {
+		return nil, fmt.Errorf("xueqiu api error: %d %s", result.ErrorCode, result.ErrorDesc)
+	}
+
+	var items []HotItem
+	for i, item := range result.Data.Items {
+		if item.Name == "" {
+			continue
+		}
+		rank := i + 1
+		items = append(items, HotItem{
+			ID:       fmt.Sprintf("xueqiu_%d", rank),
+			Title:    item.Name,
+			URL:      fmt.Sprintf("https://xueqiu.com/S/%s", item.Code),
+			HotScore: int(item.Value),
+			Rank:     rank,
+			Platform: "xueqiu",
+			Extra:    fmt.Sprintf("%s %+.2f%%", strings.ToLower(item.Code), item.Percent),
+		})
+	}
+	return items, nil
+}
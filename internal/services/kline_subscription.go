@@ -0,0 +1,161 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// K线订阅参数
+const (
+	maxKLineSubscriptions = 9                      // 同时订阅上限（多图网格最多 3x3）
+	minKLineThrottle      = 500 * time.Millisecond // 单个订阅最小推送间隔
+)
+
+// K线订阅操作
+const (
+	KLineSubAdd    = "add"
+	KLineSubRemove = "remove"
+)
+
+// KLineSubscription K线订阅信息
+type KLineSubscription struct {
+	Code     string        // 股票代码
+	Period   string        // K线周期: 1m, 1d, 1w, 1mo
+	Adjust   string        // 复权方式: 空(不复权), qfq, hfq
+	Throttle time.Duration // 最小推送间隔（0 表示跟随推送循环）
+}
+
+// key 订阅唯一标识：同一股票同一周期同一复权方式只订阅一次
+func (s KLineSubscription) key() string {
+	return s.Code + "|" + s.Period + "|" + s.Adjust
+}
+
+// klineSubOp 前端下发的订阅变更
+type klineSubOp struct {
+	Action string
+	Sub    KLineSubscription
+}
+
+// klineSubState 单个订阅的推送状态
+type klineSubState struct {
+	sub           KLineSubscription
+	lastKLineTime int64     // 最后一根K线的时间戳，用于增量推送
+	lastPush      time.Time // 上次推送时间，用于节流
+}
+
+// klineSubscriptions K线订阅集合（支持多图同时订阅）
+type klineSubscriptions struct {
+	mu    sync.Mutex
+	subs  map[string]*klineSubState
+	order []string // 订阅顺序，超出上限时淘汰最早的
+}
+
+func newKLineSubscriptions() *klineSubscriptions {
+	return &klineSubscriptions{subs: make(map[string]*klineSubState)}
+}
+
+// parseKLineSubOps 解析订阅事件载荷：[{action, code, period, adjust, throttleMs}]
+func parseKLineSubOps(payload any) []klineSubOp {
+	items, ok := payload.([]any)
+	if !ok {
+		return nil
+	}
+	ops := make([]klineSubOp, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		action, _ := m["action"].(string)
+		code, _ := m["code"].(string)
+		period, _ := m["period"].(string)
+		if code == "" || period == "" || (action != KLineSubAdd && action != KLineSubRemove) {
+			continue
+		}
+		sub := KLineSubscription{Code: code, Period: period}
+		if adjust, _ := m["adjust"].(string); period != "1m" {
+			// 分时不复权；非法复权参数按不复权处理
+			sub.Adjust, _ = normalizeAdjust(adjust)
+		}
+		if ms, ok := m["throttleMs"].(float64); ok && ms > 0 {
+			sub.Throttle = max(time.Duration(ms)*time.Millisecond, minKLineThrottle)
+		}
+		ops = append(ops, klineSubOp{Action: action, Sub: sub})
+	}
+	return ops
+}
+
+// Apply 应用订阅变更，返回新增的订阅（需要立即推送全量数据）
+func (k *klineSubscriptions) Apply(ops []klineSubOp) []KLineSubscription {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	var added []KLineSubscription
+	for _, op := range ops {
+		key := op.Sub.key()
+		switch op.Action {
+		case KLineSubRemove:
+			k.removeLocked(key)
+		case KLineSubAdd:
+			if state, ok := k.subs[key]; ok {
+				state.sub.Throttle = op.Sub.Throttle
+				continue
+			}
+			if len(k.order) >= maxKLineSubscriptions {
+				k.removeLocked(k.order[0])
+			}
+			k.subs[key] = &klineSubState{sub: op.Sub}
+			k.order = append(k.order, key)
+			added = append(added, op.Sub)
+		}
+	}
+	return added
+}
+
+func (k *klineSubscriptions) removeLocked(key string) {
+	if _, ok := k.subs[key]; !ok {
+		return
+	}
+	delete(k.subs, key)
+	for i, o := range k.order {
+		if o == key {
+			k.order = append(k.order[:i], k.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Due 返回满足过滤条件且已过节流间隔的订阅，并记录本次推送时间
+func (k *klineSubscriptions) Due(filter func(KLineSubscription) bool) []KLineSubscription {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	var due []KLineSubscription
+	for _, key := range k.order {
+		state := k.subs[key]
+		if !filter(state.sub) {
+			continue
+		}
+		if state.sub.Throttle > 0 && now.Sub(state.lastPush) < state.sub.Throttle {
+			continue
+		}
+		state.lastPush = now
+		due = append(due, state.sub)
+	}
+	return due
+}
+
+// SwapLastTime 更新订阅的最新K线时间，返回旧值；订阅已取消时返回 ok=false
+func (k *klineSubscriptions) SwapLastTime(sub KLineSubscription, latest int64) (int64, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	state, ok := k.subs[sub.key()]
+	if !ok {
+		return 0, false
+	}
+	last := state.lastKLineTime
+	state.lastKLineTime = latest
+	return last, true
+}
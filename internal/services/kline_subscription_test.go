@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// TestKLineSubscriptions 测试批量订阅的增删、上限淘汰与节流
+func TestKLineSubscriptions(t *testing.T) {
+	ops := parseKLineSubOps([]any{
+		map[string]any{"action": "add", "code": "sh600000", "period": "1m", "throttleMs": float64(100)},
+		map[string]any{"action": "add", "code": "sz000001", "period": "1d"},
+		map[string]any{"action": "bogus", "code": "sz000002", "period": "1d"},
+		map[string]any{"action": "add", "code": "", "period": "1d"},
+	})
+	if len(ops) != 2 {
+		t.Fatalf("want 2 valid ops, got %+v", ops)
+	}
+	if ops[0].Sub.Throttle != minKLineThrottle {
+		t.Errorf("throttle should be clamped to %v, got %v", minKLineThrottle, ops[0].Sub.Throttle)
+	}
+
+	k := newKLineSubscriptions()
+	if added := k.Apply(ops); len(added) != 2 {
+		t.Fatalf("want 2 added, got %+v", added)
+	}
+	// 重复订阅不会再次触发全量推送
+	if added := k.Apply(ops[:1]); len(added) != 0 {
+		t.Errorf("duplicate add should not be reported: %+v", added)
+	}
+
+	minute := func(s KLineSubscription) bool { return s.Period == "1m" }
+	if due := k.Due(minute); len(due) != 1 {
+		t.Fatalf("first push should be due, got %+v", due)
+	}
+	if due := k.Due(minute); len(due) != 0 {
+		t.Errorf("throttled subscription pushed again: %+v", due)
+	}
+
+	k.Apply([]klineSubOp{{Action: KLineSubRemove, Sub: KLineSubscription{Code: "sh600000", Period: "1m"}}})
+	if _, ok := k.SwapLastTime(KLineSubscription{Code: "sh600000", Period: "1m"}, time.Now().Unix()); ok {
+		t.Error("removed subscription should not be tracked")
+	}
+
+	// 超出上限时淘汰最早的订阅
+	for i := 0; i < maxKLineSubscriptions; i++ {
+		k.Apply([]klineSubOp{{Action: KLineSubAdd, Sub: KLineSubscription{Code: string(rune('a' + i)), Period: "1w"}}})
+	}
+	if len(k.subs) != maxKLineSubscriptions {
+		t.Errorf("want %d subscriptions, got %d", maxKLineSubscriptions, len(k.subs))
+	}
+	if _, ok := k.subs["sz000001|1d"]; ok {
+		t.Error("oldest subscription should be evicted")
+	}
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// 东方财富涨跌停池API（date 格式 YYYYMMDD）
+const (
+	ztPoolURL = "https://push2ex.eastmoney.com/getTopicZTPool?ut=7eea3edcaed734bea9cbfc24409ed989&dpt=wz.ztzt&Pageindex=0&pagesize=1000&sort=fbt%%3Aasc&date=%s"
+	dtPoolURL = "https://push2ex.eastmoney.com/getTopicDTPool?ut=7eea3edcaed734bea9cbfc24409ed989&dpt=wz.ztzt&Pageindex=0&pagesize=1000&sort=fund%%3Aasc&date=%s"
+	zbPoolURL = "https://push2ex.eastmoney.com/getTopicZBPool?ut=7eea3edcaed734bea9cbfc24409ed989&dpt=wz.ztzt&Pageindex=0&pagesize=1000&sort=fbt%%3Aasc&date=%s"
+)
+
+const (
+	limitBoardTTLToday   = 30 * time.Second // 当日数据盘中持续变化
+	limitBoardTTLHistory = 1 * time.Hour    // 历史交易日数据不再变化
+)
+
+// limitBoardCache 涨跌停池缓存
+type limitBoardCache struct {
+	data      *models.LimitBoard
+	timestamp time.Time
+}
+
+// LimitBoardService 涨跌停板监控服务
+type LimitBoardService struct {
+	client  *http.Client
+	cache   map[string]*limitBoardCache
+	cacheMu sync.RWMutex
+}
+
+// NewLimitBoardService 创建涨跌停板监控服务
+func NewLimitBoardService() *LimitBoardService {
+	return &LimitBoardService{
+		client: proxy.GetManager().GetClientWithTimeout(10 * time.Second),
+		cache:  make(map[string]*limitBoardCache),
+	}
+}
+
+// GetLimitBoard 获取涨停池、跌停池、炸板池
+// date: 交易日期，格式 YYYY-MM-DD，为空则取当日
+func (s *LimitBoardService) GetLimitBoard(date string) (*models.LimitBoard, error) {
+	today := time.Now().Format("2006-01-02")
+	if date == "" {
+		date = today
+	}
+	ttl := limitBoardTTLHistory
+	if date == today {
+		ttl = limitBoardTTLToday
+	}
+
+	s.cacheMu.RLock()
+	if cached, ok := s.cache[date]; ok && time.Since(cached.timestamp) < ttl {
+		s.cacheMu.RUnlock()
+		return cached.data, nil
+	}
+	s.cacheMu.RUnlock()
+
+	board, err := s.fetchLimitBoard(date)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMu.Lock()
+	s.cache[date] = &limitBoardCache{data: board, timestamp: time.Now()}
+	s.cacheMu.Unlock()
+	return board, nil
+}
+
+// fetchLimitBoard 并发拉取三个池
+func (s *LimitBoardService) fetchLimitBoard(date string) (*models.LimitBoard, error) {
+	apiDate := strings.ReplaceAll(date, "-", "")
+	urls := []string{ztPoolURL, dtPoolURL, zbPoolURL}
+	pools := make([][]models.LimitBoardItem, len(urls))
+	errs := make([]error, len(urls))
+
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			pools[i], errs[i] = s.fetchPool(fmt.Sprintf(u, apiDate))
+		}(i, u)
+	}
+	wg.Wait()
+
+	// 涨停池是核心数据，失败时整体失败；跌停/炸板池失败时返回空列表
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+	for i := range pools {
+		if pools[i] == nil {
+			pools[i] = []models.LimitBoardItem{}
+		}
+	}
+
+	board := &models.LimitBoard{
+		Date:      date,
+		LimitUp:   pools[0],
+		LimitDown: pools[1],
+		Broken:    pools[2],
+		UpdatedAt: time.Now().UnixMilli(),
+	}
+	sortLimitUp(board.LimitUp)
+	return board, nil
+}
+
+// fetchPool 拉取单个池
+func (s *LimitBoardService) fetchPool(url string) ([]models.LimitBoardItem, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", "https://quote.eastmoney.com/")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseLimitPool(body)
+}
+
+// limitPoolResponse 涨跌停池API响应（三个池字段基本一致，缺失字段为零值）
+type limitPoolResponse struct {
+	RC   int `json:"rc"`
+	Data *struct {
+		Pool []limitPoolItem `json:"pool"`
+	} `json:"data"`
+}
+
+type limitPoolItem struct {
+	Code     string  `json:"c"`
+	Market   int     `json:"m"` // 1=沪市 0=深市/北交所
+	Name     string  `json:"n"`
+	Price    float64 `json:"p"`   // 价格×1000
+	Change   float64 `json:"zdp"` // 涨跌幅(%)
+	Amount   float64 `json:"amount"`
+	FloatCap float64 `json:"ltsz"`
+	Turnover float64 `json:"hs"`
+	Fund     float64 `json:"fund"` // 封单资金
+	FBT      int     `json:"fbt"`  // 首次封板时间 HHMMSS
+	LBT      int     `json:"lbt"`  // 最后封板时间 HHMMSS
+	ZBC      int     `json:"zbc"`  // 炸板次数
+	OC       int     `json:"oc"`   // 开板次数（跌停池）
+	LBC      int     `json:"lbc"`  // 连板数（涨停池）
+	Days     int     `json:"days"` // 连续跌停天数（跌停池）
+	Industry string  `json:"hybk"`
+}
+
+// parseLimitPool 解析涨跌停池响应，非交易日 data 为 null，返回空列表
+func parseLimitPool(body []byte) ([]models.LimitBoardItem, error) {
+	var resp limitPoolResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析涨跌停池数据失败: %w", err)
+	}
+	if resp.Data == nil {
+		return []models.LimitBoardItem{}, nil
+	}
+
+	items := make([]models.LimitBoardItem, 0, len(resp.Data.Pool))
+	for _, p := range resp.Data.Pool {
+		items = append(items, models.LimitBoardItem{
+			Code:          emSymbol(p.Code, p.Market),
+			Name:          p.Name,
+			Price:         p.Price / 1000,
+			ChangePercent: p.Change,
+			Amount:        p.Amount,
+			TurnoverRate:  p.Turnover,
+			FloatCap:      p.FloatCap,
+			SealFund:      p.Fund,
+			FirstSealTime: formatHHMMSS(p.FBT),
+			LastSealTime:  formatHHMMSS(p.LBT),
+			OpenTimes:     max(p.ZBC, p.OC),
+			Boards:        max(p.LBC, p.Days),
+			Industry:      p.Industry,
+		})
+	}
+	return items, nil
+}
+
+// sortLimitUp 涨停池按连板数降序，同连板数按首次封板时间升序
+func sortLimitUp(items []models.LimitBoardItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Boards != items[j].Boards {
+			return items[i].Boards > items[j].Boards
+		}
+		return items[i].FirstSealTime < items[j].FirstSealTime
+	})
+}
+
+// emSymbol 东方财富代码+市场转换为带前缀的代码
+func emSymbol(code string, market int) string {
+	if market == 1 {
+		return "sh" + code
+	}
+	if strings.HasPrefix(code, "8") || strings.HasPrefix(code, "4") || strings.HasPrefix(code, "92") {
+		return "bj" + code
+	}
+	return "sz" + code
+}
+
+// formatHHMMSS 将 93015 格式的整数时间转换为 09:30:15
+func formatHHMMSS(t int) string {
+	if t <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", t/10000, t/100%100, t%100)
+}
+
+// limitBoardSignature 涨跌停池签名：代码、连板数、炸板次数、封单变化都会改变签名
+func limitBoardSignature(board *models.LimitBoard) string {
+	var sb strings.Builder
+	for _, pool := range [][]models.LimitBoardItem{board.LimitUp, board.LimitDown, board.Broken} {
+		for _, item := range pool {
+			fmt.Fprintf(&sb, "%s:%d:%d:%.0f,", item.Code, item.Boards, item.OpenTimes, item.SealFund)
+		}
+		sb.WriteString("|")
+	}
+	return sb.String()
+}
+
+// BoardLadder 统计连板梯队：连板数 → 股票数
+func BoardLadder(items []models.LimitBoardItem) map[int]int {
+	ladder := make(map[int]int)
+	for _, item := range items {
+		ladder[max(item.Boards, 1)]++
+	}
+	return ladder
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestParseLimitPool(t *testing.T) {
+	body := []byte(`{"rc":0,"data":{"tc":2,"pool":[
+		{"c":"600000","m":1,"n":"浦发银行","p":10230,"zdp":10.01,"amount":1.5e8,"ltsz":3e10,"hs":2.5,"fund":5e7,"fbt":93015,"lbt":101500,"zbc":1,"lbc":3,"hybk":"银行"},
+		{"c":"830799","m":0,"n":"艾融软件","p":25100,"zdp":29.98,"fbt":140000,"lbt":140000,"lbc":1,"hybk":"软件开发"}
+	]}}`)
+	items, err := parseLimitPool(body)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	first := items[0]
+	if first.Code != "sh600000" || first.Price != 10.23 || first.Boards != 3 || first.OpenTimes != 1 {
+		t.Errorf("unexpected first item: %+v", first)
+	}
+	if first.FirstSealTime != "09:30:15" || first.LastSealTime != "10:15:00" {
+		t.Errorf("seal times = %s, %s", first.FirstSealTime, first.LastSealTime)
+	}
+	if items[1].Code != "bj830799" {
+		t.Errorf("bj code = %s", items[1].Code)
+	}
+
+	// 非交易日 data 为 null
+	empty, err := parseLimitPool([]byte(`{"rc":0,"data":null}`))
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("null data should give empty list, got %v, %v", empty, err)
+	}
+}
+
+func TestSortLimitUpAndLadder(t *testing.T) {
+	items := []models.LimitBoardItem{
+		{Code: "a", Boards: 1, FirstSealTime: "09:31:00"},
+		{Code: "b", Boards: 3, FirstSealTime: "10:00:00"},
+		{Code: "c", Boards: 1, FirstSealTime: "09:30:00"},
+		{Code: "d", Boards: 0, FirstSealTime: "11:00:00"},
+	}
+	sortLimitUp(items)
+	if items[0].Code != "b" || items[1].Code != "c" || items[2].Code != "a" {
+		t.Errorf("unexpected order: %v, %v, %v", items[0].Code, items[1].Code, items[2].Code)
+	}
+
+	ladder := BoardLadder(items)
+	if ladder[3] != 1 || ladder[1] != 3 {
+		t.Errorf("ladder = %v", ladder)
+	}
+}
+
+func TestLimitBoardSignature(t *testing.T) {
+	board := &models.LimitBoard{LimitUp: []models.LimitBoardItem{{Code: "sh600000", Boards: 2}}}
+	sig := limitBoardSignature(board)
+	board.LimitUp[0].OpenTimes = 1
+	if limitBoardSignature(board) == sig {
+		t.Error("signature should change when a board opens")
+	}
+}
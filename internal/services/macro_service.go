@@ -0,0 +1,316 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// 商务部社会融资规模增量（POST，按月，单位亿元）
+const mofcomSocialFinancingURL = "https://data.mofcom.gov.cn/datamofcom/front/gnmy/shrzgmQuery"
+
+const (
+	macroCacheTTL       = 6 * time.Hour // 宏观数据按月发布，缓存6小时
+	macroDefaultPeriods = 6
+	macroMaxPeriods     = 24
+)
+
+// MacroIndicators 支持的宏观指标分组
+var MacroIndicators = []string{"cpi", "ppi", "pmi", "lpr", "m2", "social_financing", "gdp"}
+
+// macroSpec 宏观序列定义：同一分组的序列来自同一张数据中心报表
+type macroSpec struct {
+	group  string
+	name   string
+	unit   string
+	column string
+}
+
+// macroGroup 宏观指标分组对应的数据来源
+type macroGroup struct {
+	report    string // 东方财富数据中心报表名，为空表示使用其他来源
+	periodCol string
+	periodLen int // 期间展示长度：7 为月份，10 为日期
+}
+
+var macroGroups = map[string]macroGroup{
+	"cpi": {report: "RPT_ECONOMY_CPI", periodCol: "REPORT_DATE", periodLen: 7},
+	"ppi": {report: "RPT_ECONOMY_PPI", periodCol: "REPORT_DATE", periodLen: 7},
+	"pmi": {report: "RPT_ECONOMY_PMI", periodCol: "REPORT_DATE", periodLen: 7},
+	"lpr": {report: "RPTA_WEB_RATE", periodCol: "TRADE_DATE", periodLen: 10},
+	"m2":  {report: "RPT_ECONOMY_CURRENCY_SUPPLY", periodCol: "REPORT_DATE", periodLen: 7},
+	"gdp": {report: "RPT_ECONOMY_GDP", periodCol: "REPORT_DATE", periodLen: 7},
+
+	"social_financing": {periodLen: 7},
+}
+
+var macroSpecs = []macroSpec{
+	{group: "cpi", name: "CPI同比", unit: "%", column: "NATIONAL_SAME"},
+	{group: "cpi", name: "CPI环比", unit: "%", column: "NATIONAL_SEQUENTIAL"},
+	{group: "ppi", name: "PPI同比", unit: "%", column: "BASE_SAME"},
+	{group: "pmi", name: "制造业PMI", column: "MAKE_INDEX"},
+	{group: "pmi", name: "非制造业PMI", column: "NMAKE_INDEX"},
+	{group: "lpr", name: "1年期LPR", unit: "%", column: "LPR1Y"},
+	{group: "lpr", name: "5年期以上LPR", unit: "%", column: "LPR5Y"},
+	{group: "m2", name: "M2同比", unit: "%", column: "BASIC_CURRENCY_SAME"},
+	{group: "m2", name: "M1同比", unit: "%", column: "CURRENCY_SAME"},
+	{group: "social_financing", name: "社融规模增量", unit: "亿元", column: "tiosfs"},
+	{group: "social_financing", name: "其中人民币贷款", unit: "亿元", column: "rmblaon"},
+	{group: "gdp", name: "GDP累计同比", unit: "%", column: "SUM_SAME"},
+}
+
+// MacroPoint 宏观数据点
+type MacroPoint struct {
+	Period string  `json:"period"` // 2024-05 或 2024-05-20
+	Value  float64 `json:"value"`
+}
+
+// MacroSeries 宏观数据序列
+type MacroSeries struct {
+	Group  string       `json:"group"`
+	Name   string       `json:"name"`
+	Unit   string       `json:"unit"`
+	Points []MacroPoint `json:"points"` // 按时间倒序，首个为最新值
+	Change float64      `json:"change"` // 最新值较前值变化
+	Trend  string       `json:"trend"`  // 趋势描述
+}
+
+// macroCache 宏观分组缓存（原始行）
+type macroCache struct {
+	rows      []map[string]any
+	timestamp time.Time
+}
+
+// MacroService 宏观经济数据服务
+type MacroService struct {
+	client *http.Client
+	cache  map[string]*macroCache
+	mu     sync.RWMutex
+}
+
+// NewMacroService 创建宏观经济数据服务
+func NewMacroService() *MacroService {
+	return &MacroService{
+		client: proxy.GetManager().GetClientWithTimeout(15 * time.Second),
+		cache:  make(map[string]*macroCache),
+	}
+}
+
+// GetMacroData 获取指定分组的宏观序列（groups 为空表示全部），每个序列保留最近 periods 期
+func (s *MacroService) GetMacroData(groups []string, periods int) ([]MacroSeries, error) {
+	if periods <= 0 {
+		periods = macroDefaultPeriods
+	}
+	if periods > macroMaxPeriods {
+		periods = macroMaxPeriods
+	}
+	if len(groups) == 0 {
+		groups = MacroIndicators
+	}
+
+	var result []MacroSeries
+	var failed []string
+	for _, group := range groups {
+		group = strings.ToLower(strings.TrimSpace(group))
+		g, ok := macroGroups[group]
+		if !ok {
+			failed = append(failed, fmt.Sprintf("不支持的指标: %s", group))
+			continue
+		}
+		rows, err := s.loadGroup(group, g)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", group, err))
+			continue
+		}
+		for _, spec := range macroSpecs {
+			if spec.group == group {
+				result = append(result, buildMacroSeries(spec, g, rows, periods))
+			}
+		}
+	}
+	if len(result) == 0 && len(failed) > 0 {
+		return nil, fmt.Errorf("获取宏观数据失败: %s", strings.Join(failed, "; "))
+	}
+	return result, nil
+}
+
+// loadGroup 读取缓存或拉取分组原始数据
+func (s *MacroService) loadGroup(group string, g macroGroup) ([]map[string]any, error) {
+	s.mu.RLock()
+	if cached, ok := s.cache[group]; ok && time.Since(cached.timestamp) < macroCacheTTL {
+		s.mu.RUnlock()
+		return cached.rows, nil
+	}
+	s.mu.RUnlock()
+
+	var rows []map[string]any
+	var err error
+	if g.report != "" {
+		rows, err = s.fetchDatacenter(g)
+	} else {
+		rows, err = s.fetchSocialFinancing()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[group] = &macroCache{rows: rows, timestamp: time.Now()}
+	s.mu.Unlock()
+	return rows, nil
+}
+
+// fetchDatacenter 拉取东方财富数据中心宏观报表（按期间倒序）
+func (s *MacroService) fetchDatacenter(g macroGroup) ([]map[string]any, error) {
+	target := fmt.Sprintf(emDatacenterURL, g.report, "", g.periodCol, macroMaxPeriods)
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", "https://data.eastmoney.com/cjsj/")
+
+	body, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]any
+	if err := decodeDatacenter(body, &rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		row[macroPeriodKey] = row[g.periodCol]
+	}
+	return rows, nil
+}
+
+// fetchSocialFinancing 拉取商务部社融规模增量（按月份升序返回）
+func (s *MacroService) fetchSocialFinancing() ([]map[string]any, error) {
+	req, err := http.NewRequest("POST", mofcomSocialFinancingURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+
+	body, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseSocialFinancing(body)
+}
+
+func (s *MacroService) do(req *http.Request) ([]byte, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// macroPeriodKey 统一的期间字段
+const macroPeriodKey = "_period"
+
+// parseSocialFinancing 解析社融数据，月份 "202405" 转为 "2024-05"，按期间倒序
+func parseSocialFinancing(body []byte) ([]map[string]any, error) {
+	var rows []map[string]any
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("解析社融数据失败: %w", err)
+	}
+	for _, row := range rows {
+		if date, _ := row["date"].(string); len(date) == 6 {
+			row[macroPeriodKey] = date[:4] + "-" + date[4:]
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		pi, _ := rows[i][macroPeriodKey].(string)
+		pj, _ := rows[j][macroPeriodKey].(string)
+		return pi > pj
+	})
+	return rows, nil
+}
+
+// buildMacroSeries 从原始行提取单个序列并计算变化与趋势
+func buildMacroSeries(spec macroSpec, g macroGroup, rows []map[string]any, periods int) MacroSeries {
+	series := MacroSeries{Group: spec.group, Name: spec.name, Unit: spec.unit}
+	for _, row := range rows {
+		if len(series.Points) >= periods {
+			break
+		}
+		period, _ := row[macroPeriodKey].(string)
+		value, ok := macroValue(row[spec.column])
+		if period == "" || !ok {
+			continue
+		}
+		if len(period) > g.periodLen {
+			period = period[:g.periodLen]
+		}
+		series.Points = append(series.Points, MacroPoint{Period: period, Value: value})
+	}
+
+	if len(series.Points) >= 2 {
+		series.Change = round2(series.Points[0].Value - series.Points[1].Value)
+	}
+	series.Trend = macroTrend(series.Points)
+	return series
+}
+
+// macroValue 数值字段可能为数字、数字字符串或 null
+func macroValue(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case string:
+		var f float64
+		if _, err := fmt.Sscanf(x, "%g", &f); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// macroTrend 根据最近几期描述趋势：连续上升/下降的期数，否则为持平或震荡
+func macroTrend(points []MacroPoint) string {
+	if len(points) < 2 {
+		return ""
+	}
+	dir := func(i int) int {
+		d := points[i].Value - points[i+1].Value
+		switch {
+		case math.Abs(d) < 1e-9:
+			return 0
+		case d > 0:
+			return 1
+		}
+		return -1
+	}
+
+	first := dir(0)
+	if first == 0 {
+		return "与前值持平"
+	}
+	n := 1
+	for i := 1; i+1 < len(points) && dir(i) == first; i++ {
+		n++
+	}
+	word := "上升"
+	if first < 0 {
+		word = "下降"
+	}
+	if n == 1 {
+		return "较前值" + word
+	}
+	return fmt.Sprintf("连续%d期%s", n, word)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
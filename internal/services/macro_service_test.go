@@ -0,0 +1,53 @@
+package services
+
+import "testing"
+
+func TestBuildMacroSeries(t *testing.T) {
+	rows := []map[string]any{
+		{macroPeriodKey: "2024-05-01 00:00:00", "MAKE_INDEX": 49.5},
+		{macroPeriodKey: "2024-04-01 00:00:00", "MAKE_INDEX": 50.4},
+		{macroPeriodKey: "2024-03-01 00:00:00", "MAKE_INDEX": nil},
+		{macroPeriodKey: "2024-02-01 00:00:00", "MAKE_INDEX": "49.1"},
+	}
+	s := buildMacroSeries(macroSpec{group: "pmi", name: "制造业PMI", column: "MAKE_INDEX"}, macroGroups["pmi"], rows, 6)
+	if len(s.Points) != 3 {
+		t.Fatalf("got %d points, want 3 (null skipped)", len(s.Points))
+	}
+	if s.Points[0].Period != "2024-05" || s.Points[2].Value != 49.1 {
+		t.Errorf("unexpected points: %+v", s.Points)
+	}
+	if s.Change != -0.9 || s.Trend != "较前值下降" {
+		t.Errorf("change=%v trend=%q", s.Change, s.Trend)
+	}
+}
+
+func TestMacroTrend(t *testing.T) {
+	cases := []struct {
+		values []float64
+		want   string
+	}{
+		{[]float64{0.5, 0.3, 0.1, 0.2}, "连续2期上升"},
+		{[]float64{3.45, 3.45}, "与前值持平"},
+		{[]float64{1}, ""},
+	}
+	for _, c := range cases {
+		points := make([]MacroPoint, len(c.values))
+		for i, v := range c.values {
+			points[i] = MacroPoint{Value: v}
+		}
+		if got := macroTrend(points); got != c.want {
+			t.Errorf("macroTrend(%v) = %q, want %q", c.values, got, c.want)
+		}
+	}
+}
+
+func TestParseSocialFinancing(t *testing.T) {
+	rows, err := parseSocialFinancing([]byte(`[{"date":"202404","tiosfs":-658},{"date":"202405","tiosfs":20629}]`))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	s := buildMacroSeries(macroSpec{group: "social_financing", name: "社融规模增量", column: "tiosfs"}, macroGroups["social_financing"], rows, 6)
+	if len(s.Points) != 2 || s.Points[0].Period != "2024-05" || s.Points[0].Value != 20629 {
+		t.Errorf("unexpected series: %+v", s.Points)
+	}
+}
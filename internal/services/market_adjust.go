@@ -0,0 +1,146 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 复权方式
+const (
+	AdjustNone = ""    // 不复权
+	AdjustQFQ  = "qfq" // 前复权：以最新价为基准向前调整历史价格
+	AdjustHFQ  = "hfq" // 后复权：以上市首日为基准向后调整价格
+)
+
+const (
+	sinaAdjustFactorURL = "https://finance.sina.com.cn/realstock/company/%s/%s.js"
+	adjustFactorTTL     = 6 * time.Hour // 复权因子仅在除权除息日变化
+)
+
+// adjustFactor 复权因子：自 Date 起（含）适用 Factor
+type adjustFactor struct {
+	Date   string
+	Factor float64
+}
+
+// adjustFactorCache 复权因子缓存
+type adjustFactorCache struct {
+	factors   []adjustFactor
+	timestamp time.Time
+}
+
+// normalizeAdjust 校验复权参数，"none" 与空字符串均视为不复权
+func normalizeAdjust(adjust string) (string, error) {
+	switch adjust {
+	case "", "none":
+		return AdjustNone, nil
+	case AdjustQFQ, AdjustHFQ:
+		return adjust, nil
+	}
+	return "", fmt.Errorf("不支持的复权方式: %s（可选 none/qfq/hfq）", adjust)
+}
+
+// getAdjustFactors 获取复权因子（带缓存，按日期正序）
+func (ms *MarketService) getAdjustFactors(code, adjust string) ([]adjustFactor, error) {
+	key := code + ":" + adjust
+
+	ms.adjustCacheMu.Lock()
+	if cached, ok := ms.adjustCache[key]; ok && time.Since(cached.timestamp) < adjustFactorTTL {
+		ms.adjustCacheMu.Unlock()
+		return cached.factors, nil
+	}
+	ms.adjustCacheMu.Unlock()
+
+	resp, err := ms.client.Get(fmt.Sprintf(sinaAdjustFactorURL, code, adjust))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	factors, err := parseAdjustFactors(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	ms.adjustCacheMu.Lock()
+	ms.adjustCache[key] = &adjustFactorCache{factors: factors, timestamp: time.Now()}
+	ms.adjustCacheMu.Unlock()
+	return factors, nil
+}
+
+// parseAdjustFactors 解析新浪复权因子脚本：var qfq_data = {"total":N,"data":[{"d":"2024-06-20","f":"1.0"}]}
+func parseAdjustFactors(body string) ([]adjustFactor, error) {
+	start := strings.Index(body, "{")
+	end := strings.LastIndex(body, "}")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("复权因子数据格式错误")
+	}
+
+	var raw struct {
+		Data []struct {
+			D string `json:"d"`
+			F string `json:"f"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(body[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("parse adjust factors error: %w", err)
+	}
+
+	factors := make([]adjustFactor, 0, len(raw.Data))
+	for _, item := range raw.Data {
+		f, err := strconv.ParseFloat(item.F, 64)
+		if err != nil || f <= 0 || item.D == "" {
+			continue
+		}
+		factors = append(factors, adjustFactor{Date: item.D, Factor: f})
+	}
+	sort.Slice(factors, func(i, j int) bool { return factors[i].Date < factors[j].Date })
+	return factors, nil
+}
+
+// applyAdjust 按复权因子调整K线价格，返回新切片（不修改缓存中的原始数据）
+// 前复权为 价格/因子，后复权为 价格×因子；成交量与成交额保持原值
+func applyAdjust(klines []models.KLineData, factors []adjustFactor, adjust string) []models.KLineData {
+	if adjust == AdjustNone || len(factors) == 0 {
+		return klines
+	}
+
+	adjusted := make([]models.KLineData, len(klines))
+	for i, k := range klines {
+		f := factorAt(factors, k.Time)
+		if adjust == AdjustQFQ {
+			f = 1 / f
+		}
+		k.Open *= f
+		k.High *= f
+		k.Low *= f
+		k.Close *= f
+		k.Avg *= f
+		adjusted[i] = k
+	}
+	return adjusted
+}
+
+// factorAt 查找K线日期适用的复权因子（日期不早于因子生效日的最后一个）
+func factorAt(factors []adjustFactor, klineTime string) float64 {
+	date := klineTime
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	i := sort.Search(len(factors), func(i int) bool { return factors[i].Date > date })
+	if i == 0 {
+		return 1
+	}
+	return factors[i-1].Factor
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestParseAdjustFactors(t *testing.T) {
+	body := `var qfq_data = {"total":3,"data":[{"d":"2024-06-20","f":"1.0000"},{"d":"2023-06-30","f":"1.0200"},{"d":"1900-01-01","f":"1.0500"}]}
+/* 注释 */`
+	factors, err := parseAdjustFactors(body)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(factors) != 3 || factors[0].Date != "1900-01-01" || factors[2].Date != "2024-06-20" {
+		t.Fatalf("factors not sorted ascending: %+v", factors)
+	}
+
+	if _, err := parseAdjustFactors("var qfq_data = null"); err == nil {
+		t.Fatal("expected error for malformed body")
+	}
+}
+
+func TestApplyAdjust(t *testing.T) {
+	factors := []adjustFactor{
+		{Date: "1900-01-01", Factor: 2},
+		{Date: "2024-06-20", Factor: 1},
+	}
+	klines := []models.KLineData{
+		{Time: "2024-06-19", Close: 10},
+		{Time: "2024-06-20 10:30:00", Close: 10},
+	}
+
+	qfq := applyAdjust(klines, factors, AdjustQFQ)
+	if math.Abs(qfq[0].Close-5) > 1e-9 || qfq[1].Close != 10 {
+		t.Errorf("qfq closes = %v, %v", qfq[0].Close, qfq[1].Close)
+	}
+	hfq := applyAdjust(klines, factors, AdjustHFQ)
+	if hfq[0].Close != 20 || hfq[1].Close != 10 {
+		t.Errorf("hfq closes = %v, %v", hfq[0].Close, hfq[1].Close)
+	}
+	if klines[0].Close != 10 {
+		t.Error("applyAdjust must not modify source klines")
+	}
+}
+
+func TestNormalizeAdjust(t *testing.T) {
+	for _, in := range []string{"", "none"} {
+		if got, err := normalizeAdjust(in); err != nil || got != AdjustNone {
+			t.Errorf("normalizeAdjust(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := normalizeAdjust("bad"); err == nil {
+		t.Error("expected error for unknown adjust")
+	}
+}
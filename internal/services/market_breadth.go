@@ -0,0 +1,203 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 东方财富行业板块与涨跌家数API
+const (
+	emSectorListURL = "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=200&po=1&np=1&fltt=2&invt=2&fid=f3&fs=m:90+t:2&fields=f3,f6,f12,f14,f62,f104,f105,f128,f136,f140,f141"
+	emBreadthURL    = "https://push2.eastmoney.com/api/qt/ulist.np/get?fltt=2&secids=1.000001,0.399001&fields=f12,f104,f105,f106"
+)
+
+const breadthCacheTTL = 30 * time.Second
+
+// breadthCache 市场宽度缓存
+type breadthCache struct {
+	data      *models.MarketBreadth
+	timestamp time.Time
+}
+
+// emNumber 东方财富数值字段，停牌或无数据时返回 "-"
+type emNumber float64
+
+func (n *emNumber) UnmarshalJSON(data []byte) error {
+	v, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		*n = 0
+		return nil
+	}
+	*n = emNumber(v)
+	return nil
+}
+
+type emSectorItem struct {
+	Change       emNumber `json:"f3"`
+	Amount       emNumber `json:"f6"`
+	Code         string   `json:"f12"`
+	Name         string   `json:"f14"`
+	NetInflow    emNumber `json:"f62"`
+	Up           emNumber `json:"f104"`
+	Down         emNumber `json:"f105"`
+	LeaderName   string   `json:"f128"`
+	LeaderChange emNumber `json:"f136"`
+	LeaderCode   string   `json:"f140"`
+	LeaderMarket emNumber `json:"f141"`
+}
+
+type emBreadthItem struct {
+	Up   emNumber `json:"f104"`
+	Down emNumber `json:"f105"`
+	Flat emNumber `json:"f106"`
+}
+
+// GetMarketBreadth 获取沪深涨跌家数与行业板块涨跌排行（带缓存）
+func (ms *MarketService) GetMarketBreadth() (*models.MarketBreadth, error) {
+	ms.breadthMu.Lock()
+	if c := ms.breadthCache; c != nil && time.Since(c.timestamp) < breadthCacheTTL {
+		ms.breadthMu.Unlock()
+		return c.data, nil
+	}
+	ms.breadthMu.Unlock()
+
+	var (
+		wg                    sync.WaitGroup
+		sectors               []models.SectorPerf
+		breadth               *models.MarketBreadth
+		sectorErr, breadthErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sectors, sectorErr = ms.fetchSectors()
+	}()
+	go func() {
+		defer wg.Done()
+		breadth, breadthErr = ms.fetchBreadth()
+	}()
+	wg.Wait()
+
+	if sectorErr != nil {
+		return nil, sectorErr
+	}
+	if breadthErr != nil {
+		// 涨跌家数失败时用板块内家数汇总近似
+		breadth = &models.MarketBreadth{}
+		for _, s := range sectors {
+			breadth.Up += s.UpCount
+			breadth.Down += s.DownCount
+		}
+	}
+	breadth.Sectors = sectors
+	breadth.UpdatedAt = time.Now().UnixMilli()
+
+	ms.breadthMu.Lock()
+	ms.breadthCache = &breadthCache{data: breadth, timestamp: time.Now()}
+	ms.breadthMu.Unlock()
+	return breadth, nil
+}
+
+// fetchSectors 获取行业板块列表
+func (ms *MarketService) fetchSectors() ([]models.SectorPerf, error) {
+	body, err := ms.getEastMoney(emSectorListURL)
+	if err != nil {
+		return nil, err
+	}
+	return parseSectors(body)
+}
+
+// parseSectors 解析行业板块列表，按涨跌幅降序
+func parseSectors(body []byte) ([]models.SectorPerf, error) {
+	var resp struct {
+		Data *struct {
+			Diff []emSectorItem `json:"diff"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析行业板块数据失败: %w", err)
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("行业板块数据为空")
+	}
+
+	sectors := make([]models.SectorPerf, 0, len(resp.Data.Diff))
+	for _, d := range resp.Data.Diff {
+		sector := models.SectorPerf{
+			Code:          d.Code,
+			Name:          d.Name,
+			ChangePercent: float64(d.Change),
+			Amount:        float64(d.Amount),
+			NetInflow:     float64(d.NetInflow),
+			UpCount:       int(d.Up),
+			DownCount:     int(d.Down),
+			LeaderName:    d.LeaderName,
+			LeaderChange:  float64(d.LeaderChange),
+		}
+		if d.LeaderCode != "" {
+			sector.LeaderCode = emSymbol(d.LeaderCode, int(d.LeaderMarket))
+		}
+		sectors = append(sectors, sector)
+	}
+	sort.SliceStable(sectors, func(i, j int) bool {
+		return sectors[i].ChangePercent > sectors[j].ChangePercent
+	})
+	return sectors, nil
+}
+
+// fetchBreadth 获取沪深两市涨跌平家数
+func (ms *MarketService) fetchBreadth() (*models.MarketBreadth, error) {
+	body, err := ms.getEastMoney(emBreadthURL)
+	if err != nil {
+		return nil, err
+	}
+	return parseBreadth(body)
+}
+
+// parseBreadth 汇总上证、深证的涨跌平家数
+func parseBreadth(body []byte) (*models.MarketBreadth, error) {
+	var resp struct {
+		Data *struct {
+			Diff []emBreadthItem `json:"diff"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析涨跌家数失败: %w", err)
+	}
+	if resp.Data == nil || len(resp.Data.Diff) == 0 {
+		return nil, fmt.Errorf("涨跌家数数据为空")
+	}
+
+	breadth := &models.MarketBreadth{}
+	for _, d := range resp.Data.Diff {
+		breadth.Up += int(d.Up)
+		breadth.Down += int(d.Down)
+		breadth.Flat += int(d.Flat)
+	}
+	return breadth, nil
+}
+
+// getEastMoney 请求东方财富行情接口
+func (ms *MarketService) getEastMoney(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", "https://quote.eastmoney.com/")
+
+	resp, err := ms.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,41 @@
+package services
+
+import "testing"
+
+func TestParseSectors(t *testing.T) {
+	body := []byte(`{"rc":0,"data":{"total":3,"diff":[
+		{"f3":-1.2,"f6":1e9,"f12":"BK0475","f14":"银行","f62":-2e8,"f104":10,"f105":30,"f128":"浦发银行","f136":0.5,"f140":"600000","f141":1},
+		{"f3":3.5,"f6":2e9,"f12":"BK0447","f14":"半导体","f62":5e8,"f104":80,"f105":5,"f128":"中芯国际","f136":9.99,"f140":"688981","f141":1},
+		{"f3":"-","f6":"-","f12":"BK9999","f14":"停牌板块","f62":"-","f104":"-","f105":"-","f128":"-","f136":"-","f140":"-","f141":"-"}
+	]}}`)
+	sectors, err := parseSectors(body)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(sectors) != 3 || sectors[0].Name != "半导体" || sectors[2].Name != "银行" {
+		t.Fatalf("sectors not sorted by change: %+v", sectors)
+	}
+	if sectors[0].LeaderCode != "sh688981" || sectors[0].UpCount != 80 {
+		t.Errorf("unexpected leader fields: %+v", sectors[0])
+	}
+	if sectors[1].ChangePercent != 0 {
+		t.Errorf("'-' should parse as 0, got %v", sectors[1].ChangePercent)
+	}
+}
+
+func TestParseBreadth(t *testing.T) {
+	body := []byte(`{"rc":0,"data":{"total":2,"diff":[
+		{"f12":"000001","f104":1200,"f105":900,"f106":50},
+		{"f12":"399001","f104":1800,"f105":1000,"f106":80}
+	]}}`)
+	b, err := parseBreadth(body)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if b.Up != 3000 || b.Down != 1900 || b.Flat != 130 {
+		t.Errorf("breadth = %+v", b)
+	}
+	if _, err := parseBreadth([]byte(`{"rc":0,"data":null}`)); err == nil {
+		t.Error("expected error for empty data")
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/analysis"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 分钟级历史K线参数
+const (
+	maxIntradayBars      = 1023 // 新浪接口单次最多返回的K线根数
+	defaultIntradayLimit = 240
+	intradayHistoryTTL   = 30 * time.Second
+	intradayPeriodsHint  = "1m,5m,15m,30m,60m"
+	dayMinuteBars        = 250 // 覆盖一整个交易日的 1 分钟K线（含集合竞价），与前端分时图请求一致以共用缓存
+)
+
+// IntradayPage 分钟级历史K线分页结果（按时间正序）
+type IntradayPage struct {
+	Data    []models.KLineData `json:"data"`
+	HasMore bool               `json:"hasMore"` // 是否还有更早的数据
+}
+
+// isIntradayPeriod 是否为分钟级周期
+func isIntradayPeriod(period string) bool {
+	switch period {
+	case "1m", "5m", "15m", "30m", "60m":
+		return true
+	}
+	return false
+}
+
+// GetIntradayHistory 获取跨多个交易日的分钟级K线，按 before 游标向前翻页
+// before 为空时返回最新一页，否则返回时间早于 before 的 limit 根K线
+// 受数据源限制，最多回溯约 1000 根（1分钟线约4个交易日，60分钟线约一年）
+func (ms *MarketService) GetIntradayHistory(code, period, before string, limit int) (IntradayPage, error) {
+	if !isIntradayPeriod(period) {
+		return IntradayPage{}, fmt.Errorf("不支持的分钟周期: %s（可选 %s）", period, intradayPeriodsHint)
+	}
+	if limit <= 0 {
+		limit = defaultIntradayLimit
+	}
+
+	cacheKey := fmt.Sprintf("%s:%s:history", code, period)
+	klines, err := ms.cachedKLines(cacheKey, intradayHistoryTTL, func() ([]models.KLineData, error) {
+		return ms.fetchIntradayHistory(code, period)
+	})
+	if err != nil {
+		return IntradayPage{}, err
+	}
+	return pageKLines(klines, before, limit), nil
+}
+
+// GetMoneyFlow 获取当日分时资金流向（每分钟流入/流出、均价与累计净流入）
+func (ms *MarketService) GetMoneyFlow(code string) ([]models.MoneyFlowPoint, error) {
+	klines, err := ms.GetKLineData(code, "1m", dayMinuteBars, AdjustNone)
+	if err != nil {
+		return nil, err
+	}
+	return analysis.MoneyFlow(klines), nil
+}
+
+// fetchIntradayHistory 拉取分钟级K线（不做当日过滤）
+func (ms *MarketService) fetchIntradayHistory(code, period string) ([]models.KLineData, error) {
+	url := fmt.Sprintf(sinaKLineURL, code, ms.periodToScale(period), maxIntradayBars)
+
+	resp, err := ms.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	klines, err := ms.parseKLineData(string(body))
+	if err != nil {
+		return nil, err
+	}
+	analysis.FillMA(klines)
+	return klines, nil
+}
+
+// pageKLines 截取时间早于 before 的最后 limit 根K线
+func pageKLines(klines []models.KLineData, before string, limit int) IntradayPage {
+	end := len(klines)
+	if before != "" {
+		end = sort.Search(len(klines), func(i int) bool { return klines[i].Time >= before })
+	}
+	start := max(end-limit, 0)
+	return IntradayPage{
+		Data:    klines[start:end],
+		HasMore: start > 0,
+	}
+}
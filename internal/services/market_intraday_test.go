@@ -0,0 +1,35 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// TestPageKLines 测试分钟K线游标翻页
+func TestPageKLines(t *testing.T) {
+	klines := make([]models.KLineData, 10)
+	for i := range klines {
+		klines[i].Time = fmt.Sprintf("2025-01-%02d 10:%02d:00", 2+i/4, i)
+	}
+
+	page := pageKLines(klines, "", 4)
+	if len(page.Data) != 4 || page.Data[0] != klines[6] || !page.HasMore {
+		t.Fatalf("latest page = %+v", page)
+	}
+
+	page = pageKLines(klines, page.Data[0].Time, 4)
+	if len(page.Data) != 4 || page.Data[3] != klines[5] || !page.HasMore {
+		t.Fatalf("second page = %+v", page)
+	}
+
+	page = pageKLines(klines, page.Data[0].Time, 4)
+	if len(page.Data) != 2 || page.HasMore {
+		t.Fatalf("last page = %+v", page)
+	}
+
+	if isIntradayPeriod("1d") || !isIntradayPeriod("15m") {
+		t.Error("isIntradayPeriod mismatch")
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/analysis"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// orderBookSnapshotTTL 盘口快照有效期，超过后不再用于撤单识别
+const orderBookSnapshotTTL = time.Minute
+
+// orderBookSnapshot 某只股票最近获取的盘口
+type orderBookSnapshot struct {
+	book      models.OrderBook
+	prev      *models.OrderBook // 与 book 不同的上一份盘口
+	timestamp time.Time
+}
+
+// GetOrderBookWithMetrics 获取盘口并附带委比、大单与疑似撤单指标
+// 撤单通过对比该股票 1 分钟内获取过的上一份不同盘口识别，推送与工具查询共用同一份快照
+func (ms *MarketService) GetOrderBookWithMetrics(code string) (models.OrderBook, error) {
+	ob, err := ms.GetRealOrderBook(code)
+	if err != nil {
+		return ob, err
+	}
+	metrics := analysis.OrderBookMetrics(ob, ms.rememberOrderBook(code, ob))
+	ob.Metrics = &metrics
+	return ob, nil
+}
+
+// rememberOrderBook 记录盘口快照，返回用于对比的上一份不同盘口（没有时为 nil）
+// 盘口未变化（如命中行情缓存）时沿用之前的对比基准，避免撤单标记一闪而过
+func (ms *MarketService) rememberOrderBook(code string, ob models.OrderBook) *models.OrderBook {
+	now := time.Now()
+	ms.orderBookMu.Lock()
+	defer ms.orderBookMu.Unlock()
+
+	snap, ok := ms.orderBooks[code]
+	if !ok || now.Sub(snap.timestamp) > orderBookSnapshotTTL {
+		ms.orderBooks[code] = &orderBookSnapshot{book: ob, timestamp: now}
+		return nil
+	}
+	snap.timestamp = now
+	if delta, full := diffOrderBook(snap.book, ob); full || !delta.empty() {
+		prev := snap.book
+		snap.book, snap.prev = ob, &prev
+	}
+	return snap.prev
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestRememberOrderBook(t *testing.T) {
+	ms := &MarketService{orderBooks: make(map[string]*orderBookSnapshot)}
+	book := func(size int64) models.OrderBook {
+		return models.OrderBook{Bids: []models.OrderBookItem{{Price: 10, Size: size}}}
+	}
+
+	if prev := ms.rememberOrderBook("sh600000", book(100)); prev != nil {
+		t.Fatalf("first snapshot prev = %+v", prev)
+	}
+	prev := ms.rememberOrderBook("sh600000", book(200))
+	if prev == nil || prev.Bids[0].Size != 100 {
+		t.Fatalf("changed book prev = %+v", prev)
+	}
+	// 盘口未变化时沿用上一次的对比基准
+	if again := ms.rememberOrderBook("sh600000", book(200)); again == nil || again.Bids[0].Size != 100 {
+		t.Errorf("unchanged book prev = %+v", again)
+	}
+
+	ms.orderBooks["sh600000"].timestamp = time.Now().Add(-2 * orderBookSnapshotTTL)
+	if prev := ms.rememberOrderBook("sh600000", book(300)); prev != nil {
+		t.Errorf("expired snapshot should not be compared, got %+v", prev)
+	}
+}
@@ -1,16 +1,16 @@
 package services
 
 import (
-	"context"
-	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/run-bigpig/jcp/internal/analysis"
+	"github.com/run-bigpig/jcp/internal/eventbus"
 	"github.com/run-bigpig/jcp/internal/logger"
 	"github.com/run-bigpig/jcp/internal/models"
-
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 var pusherLog = logger.New("pusher")
@@ -19,12 +19,18 @@ var pusherLog = logger.New("pusher")
 const (
 	EventStockUpdate         = "market:stock:update"
 	EventOrderBookUpdate     = "market:orderbook:update"
+	EventOrderBookDelta      = "market:orderbook:delta"
 	EventTelegraphUpdate     = "market:telegraph:update"
 	EventMarketIndicesUpdate = "market:indices:update"
 	EventMarketSubscribe     = "market:subscribe"
 	EventOrderBookSubscribe  = "market:orderbook:subscribe"
 	EventKLineUpdate         = "market:kline:update"
+	EventTicksUpdate         = "market:ticks:update"
 	EventKLineSubscribe      = "market:kline:subscribe"
+	EventGroupSubscribe      = "market:group:subscribe"
+	EventLimitBoardUpdate    = "market:limitboard:update"
+	EventMarketBreadthUpdate = "market:breadth:update"
+	EventWindowVisibility    = "market:visibility" // 前端窗口可见性变化（载荷为 bool）
 )
 
 // 推送频率常量
@@ -33,6 +39,8 @@ const (
 	tickerNormal   = 3 * time.Second  // 股票、指数、分时K线
 	tickerSlow     = 30 * time.Second // 快讯、非交易时段降频
 	tickerKLineDay = 5 * time.Minute  // 日/周/月K线
+
+	hiddenPushEvery = 10 // 窗口隐藏时股票与指数每隔多少个 normal 周期推送一次（约 30 秒）
 )
 
 // safeCall 安全调用，捕获 panic 避免崩溃
@@ -45,34 +53,43 @@ func safeCall(fn func()) {
 	fn()
 }
 
-// KLineSubscription K线订阅信息
-type KLineSubscription struct {
-	Code   string // 股票代码
-	Period string // K线周期: 1m, 1d, 1w, 1mo
-}
-
 // MarketDataPusher 市场数据推送服务
 type MarketDataPusher struct {
-	ctx           context.Context
+	bus           eventbus.Bus
+	listenerOffs  []func() // 取消客户端事件监听
 	marketService *MarketService
 	configService *ConfigService
 	newsService   *NewsService
+	limitBoard    *LimitBoardService
 
 	// 订阅管理
 	subscribedCodes  []string
+	visibleGroup     string // 前端当前显示的自选股分组（空为全部）
 	currentOrderBook string // 当前订阅盘口的股票代码
+	hidden           bool   // 前端窗口已隐藏或最小化
 	mu               sync.RWMutex
 
-	// K线订阅管理
-	klineSub      KLineSubscription
-	klineSubMu    sync.RWMutex
-	lastKLineTime int64 // 最后一根K线的时间戳，用于增量推送
+	// K线订阅管理（支持多图同时订阅）
+	klineSubs *klineSubscriptions
 
 	// 快讯缓存（用于检测新快讯）
 	lastTelegraphContent string
 
-	// 盘口缓存（用于diff检测）
-	lastOrderBookHash string
+	// 涨跌停池签名（无变化时不推送）
+	lastLimitBoardSig string
+
+	// 行情快照缓存（用于只推送有变化的股票）
+	lastStocks stockSnapshotCache
+	// 上次行情获取失败的代码（避免重复记录日志）
+	lastQuoteErrSig string
+
+	// 盘口缓存（用于逐档diff）
+	lastOrderBook     models.OrderBook
+	lastOrderBookCode string
+
+	// 逐笔成交缓存（跟随盘口订阅的股票，只推送新增成交）
+	lastTick     models.TickTrade
+	lastTickCode string
 
 	// 控制
 	stopChan  chan struct{}
@@ -86,25 +103,28 @@ type MarketDataPusher struct {
 }
 
 // NewMarketDataPusher 创建市场数据推送服务
-func NewMarketDataPusher(marketService *MarketService, configService *ConfigService, newsService *NewsService) *MarketDataPusher {
+func NewMarketDataPusher(bus eventbus.Bus, marketService *MarketService, configService *ConfigService, newsService *NewsService, limitBoard *LimitBoardService) *MarketDataPusher {
 	return &MarketDataPusher{
+		bus:             bus,
 		marketService:   marketService,
 		configService:   configService,
 		newsService:     newsService,
+		limitBoard:      limitBoard,
 		subscribedCodes: make([]string, 0),
+		klineSubs:       newKLineSubscriptions(),
+		lastStocks:      make(stockSnapshotCache),
 		stopChan:        make(chan struct{}),
 		readyChan:       make(chan struct{}),
 	}
 }
 
 // Start 启动推送服务
-func (p *MarketDataPusher) Start(ctx context.Context) {
+func (p *MarketDataPusher) Start() {
 	p.ctrlMu.Lock()
 	if p.stopped {
 		p.ctrlMu.Unlock()
 		return
 	}
-	p.ctx = ctx
 	p.ctrlMu.Unlock()
 
 	p.setupEventListeners()
@@ -134,15 +154,16 @@ func (p *MarketDataPusher) Stop() {
 	p.stopped = true
 	close(p.stopChan)
 	// 清理事件监听
-	runtime.EventsOff(p.ctx, EventMarketSubscribe)
-	runtime.EventsOff(p.ctx, EventOrderBookSubscribe)
-	runtime.EventsOff(p.ctx, EventKLineSubscribe)
+	for _, off := range p.listenerOffs {
+		off()
+	}
+	p.listenerOffs = nil
 }
 
 // setupEventListeners 设置事件监听
 func (p *MarketDataPusher) setupEventListeners() {
 	// 监听订阅请求
-	runtime.EventsOn(p.ctx, EventMarketSubscribe, func(data ...any) {
+	p.on(EventMarketSubscribe, func(data ...any) {
 		if len(data) > 0 {
 			if codes, ok := data[0].([]any); ok {
 				p.updateSubscriptions(codes)
@@ -151,39 +172,56 @@ func (p *MarketDataPusher) setupEventListeners() {
 	})
 
 	// 监听盘口订阅请求
-	runtime.EventsOn(p.ctx, EventOrderBookSubscribe, func(data ...any) {
+	p.on(EventOrderBookSubscribe, func(data ...any) {
 		if len(data) > 0 {
 			if code, ok := data[0].(string); ok {
 				p.mu.Lock()
 				p.currentOrderBook = code
 				p.mu.Unlock()
+				// 逐笔成交跟随盘口股票，切换后立即推送全量
+				go safeCall(p.pushTickData)
+			}
+		}
+	})
+
+	// 监听分组切换：只推送当前可见分组的股票
+	p.on(EventGroupSubscribe, func(data ...any) {
+		if len(data) > 0 {
+			if groupID, ok := data[0].(string); ok {
+				p.SetVisibleGroup(groupID)
+				go safeCall(p.pushStockData)
 			}
 		}
 	})
 
-	// 监听K线订阅请求
-	runtime.EventsOn(p.ctx, EventKLineSubscribe, func(data ...any) {
-		if len(data) >= 2 {
-			code, _ := data[0].(string)
-			period, _ := data[1].(string)
-			if code != "" && period != "" {
-				p.klineSubMu.Lock()
-				p.klineSub = KLineSubscription{Code: code, Period: period}
-				p.lastKLineTime = 0 // 重置增量时间戳
-				p.klineSubMu.Unlock()
-				go safeCall(p.pushKLineData)
+	// 监听窗口可见性：隐藏时降频，恢复可见时补推
+	p.on(EventWindowVisibility, func(data ...any) {
+		if len(data) > 0 {
+			if visible, ok := data[0].(bool); ok {
+				p.SetVisible(visible)
+			}
+		}
+	})
+
+	// 监听K线订阅请求：载荷为 [{action: add/remove, code, period, throttleMs}]
+	p.on(EventKLineSubscribe, func(data ...any) {
+		if len(data) > 0 {
+			added := p.klineSubs.Apply(parseKLineSubOps(data[0]))
+			for _, sub := range added {
+				go safeCall(func() { p.pushKLineFull(sub, 240) })
 			}
 		}
 	})
 }
 
+// on 注册客户端事件监听，Stop 时统一取消
+func (p *MarketDataPusher) on(name string, handler func(data ...any)) {
+	p.listenerOffs = append(p.listenerOffs, p.bus.On(name, handler))
+}
+
 // initSubscriptions 从自选股初始化订阅
 func (p *MarketDataPusher) initSubscriptions() {
-	watchlist := p.configService.GetWatchlist()
-	codes := make([]string, len(watchlist))
-	for i, stock := range watchlist {
-		codes[i] = stock.Symbol
-	}
+	codes := p.configService.GroupSymbols(models.WatchlistGroupAll)
 
 	p.mu.Lock()
 	p.subscribedCodes = codes
@@ -194,17 +232,101 @@ func (p *MarketDataPusher) initSubscriptions() {
 	p.mu.Unlock()
 }
 
-// updateSubscriptions 更新订阅列表
+// updateSubscriptions 更新订阅列表，新增与移除股票的快照一并清除，
+// 保证新增股票下一轮全量推送、移除后再订阅时不与过期快照比较
 func (p *MarketDataPusher) updateSubscriptions(codes []any) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	previous := p.subscribedCodes
 	p.subscribedCodes = make([]string, 0, len(codes))
 	for _, code := range codes {
 		if s, ok := code.(string); ok {
 			p.subscribedCodes = append(p.subscribedCodes, s)
 		}
 	}
+	for _, code := range previous {
+		if !slices.Contains(p.subscribedCodes, code) {
+			delete(p.lastStocks, code)
+		}
+	}
+	for _, code := range p.subscribedCodes {
+		if !slices.Contains(previous, code) {
+			delete(p.lastStocks, code)
+		}
+	}
+}
+
+// SetVisibleGroup 切换可见分组，订阅列表替换为该分组的股票
+func (p *MarketDataPusher) SetVisibleGroup(groupID string) {
+	codes := p.configService.GroupSymbols(groupID)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.visibleGroup = groupID
+	p.subscribedCodes = codes
+	// 新分组的股票此前未推送，清空快照保证下一轮全量推送
+	p.lastStocks = make(stockSnapshotCache)
+}
+
+// RefreshSubscriptions 自选股或分组变更后，按当前可见分组重建订阅列表
+func (p *MarketDataPusher) RefreshSubscriptions() {
+	p.mu.RLock()
+	groupID := p.visibleGroup
+	p.mu.RUnlock()
+	p.SetVisibleGroup(groupID)
+}
+
+// SetVisible 前端窗口可见性变化：隐藏时暂停盘口、K线、逐笔等高频推送，股票与指数降频；
+// 恢复可见时立即补推全量，隐藏期间错过的增量无法拼接
+func (p *MarketDataPusher) SetVisible(visible bool) {
+	p.mu.Lock()
+	changed := p.hidden == visible
+	p.hidden = !visible
+	if changed && visible {
+		p.lastStocks = make(stockSnapshotCache)
+		p.lastOrderBookCode = ""
+		p.lastTickCode = ""
+	}
+	p.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if visible {
+		pusherLog.Info("前端窗口恢复可见，补推行情")
+		go p.catchUp()
+	} else {
+		pusherLog.Info("前端窗口已隐藏，推送降频")
+	}
+}
+
+// isHidden 前端窗口是否已隐藏
+func (p *MarketDataPusher) isHidden() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.hidden
+}
+
+// catchUp 窗口恢复可见后补推全量数据；正在进行的推送轮次结束前稍后重试
+func (p *MarketDataPusher) catchUp() {
+	p.ctrlMu.Lock()
+	ready := p.ready && !p.stopped
+	p.ctrlMu.Unlock()
+	if !ready {
+		return
+	}
+	for range 10 {
+		if p.runParallel(15*time.Second, p.pushStockData, p.pushOrderBookData, p.pushMarketIndices,
+			p.pushKLineData, p.pushTickData, p.pushLimitBoard, p.pushMarketBreadth) {
+			return
+		}
+		select {
+		case <-p.stopChan:
+			return
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
 }
 
 // pushLoop 数据推送循环（并行推送 + 超时控制 + 时段感知）
@@ -229,7 +351,7 @@ func (p *MarketDataPusher) pushLoop() {
 
 	// 立即并行推送一次（启动时5个并发请求，冷启动给足时间）
 	p.runParallel(15*time.Second, p.pushStockData, p.pushOrderBookData,
-		p.pushTelegraphData, p.pushMarketIndices, p.pushKLineData)
+		p.pushTelegraphData, p.pushMarketIndices, p.pushKLineData, p.pushTickData, p.pushLimitBoard, p.pushMarketBreadth)
 
 	var normalCount int
 
@@ -239,18 +361,26 @@ func (p *MarketDataPusher) pushLoop() {
 			return
 		case <-fastTicker.C:
 			status := p.getMarketPhase()
-			// 仅交易时段高频推送盘口
-			if status == "trading" {
+			// 仅交易时段且窗口可见时高频推送盘口
+			if status == "trading" && !p.isHidden() {
 				p.runParallel(2*time.Second, p.pushOrderBookData)
 			}
 		case <-normalTicker.C:
 			normalCount++
 			status := p.getMarketPhase()
 
+			// 窗口隐藏：只低频推送股票与指数，其余等恢复可见时补推
+			if p.isHidden() {
+				if normalCount%hiddenPushEvery == 0 {
+					p.runParallel(8*time.Second, p.pushStockData, p.pushMarketIndices)
+				}
+				continue
+			}
+
 			switch status {
 			case "trading":
 				// 交易时段：正常频率
-				p.runParallel(8*time.Second, p.pushStockData, p.pushMarketIndices, p.pushKLineMinute)
+				p.runParallel(8*time.Second, p.pushStockData, p.pushMarketIndices, p.pushKLineMinute, p.pushTickData)
 			case "pre_market":
 				// 集合竞价：推送盘口（虚拟撮合价）和股票，降频
 				if normalCount%3 == 0 {
@@ -269,9 +399,14 @@ func (p *MarketDataPusher) pushLoop() {
 				}
 			}
 		case <-slowTicker.C:
-			p.runParallel(8*time.Second, p.pushTelegraphData)
+			// 涨跌停池、板块排行仅盘中且窗口可见时推送
+			if p.getMarketPhase() == "trading" && !p.isHidden() {
+				p.runParallel(8*time.Second, p.pushTelegraphData, p.pushLimitBoard, p.pushMarketBreadth)
+			} else {
+				p.runParallel(8*time.Second, p.pushTelegraphData)
+			}
 		case <-klineDayTicker.C:
-			if p.getMarketPhase() == "trading" {
+			if p.getMarketPhase() == "trading" && !p.isHidden() {
 				p.runParallel(8*time.Second, p.pushKLineDay)
 			}
 		}
@@ -279,11 +414,11 @@ func (p *MarketDataPusher) pushLoop() {
 }
 
 // runParallel 带超时的并行执行，防止协程堆积
-// 使用 TryLock 防止重入：上一轮未完成则跳过本轮
-func (p *MarketDataPusher) runParallel(timeout time.Duration, fns ...func()) {
+// 使用 TryLock 防止重入：上一轮未完成则跳过本轮并返回 false
+func (p *MarketDataPusher) runParallel(timeout time.Duration, fns ...func()) bool {
 	if !p.pushMu.TryLock() {
 		// 上一轮推送还未完成，跳过本轮避免 goroutine 堆积
-		return
+		return false
 	}
 	var unlockOnce sync.Once
 	unlock := func() {
@@ -318,6 +453,7 @@ func (p *MarketDataPusher) runParallel(timeout time.Duration, fns ...func()) {
 			unlock()
 		}()
 	}
+	return true
 }
 
 // getMarketPhase 获取市场时段
@@ -336,42 +472,114 @@ func (p *MarketDataPusher) pushStockData() {
 		return
 	}
 
-	stocks, err := p.marketService.GetStockRealTimeData(codes...)
-	if err != nil {
+	// 个别代码无效或某批请求失败时照常推送其余股票
+	stocks, errs := p.marketService.GetQuotes(codes...)
+	p.logQuoteErrors(errs)
+	if len(stocks) == 0 {
 		return
 	}
 
-	// 推送到前端
-	runtime.EventsEmit(p.ctx, EventStockUpdate, stocks)
+	// 仅推送有变化的股票，前端按 symbol 合并
+	p.mu.Lock()
+	changed := p.lastStocks.diff(stocks)
+	p.mu.Unlock()
+	if len(changed) == 0 {
+		return
+	}
+	p.bus.Emit(EventStockUpdate, changed)
+}
+
+// logQuoteErrors 记录行情获取失败的代码，失败集合不变时不重复记录
+func (p *MarketDataPusher) logQuoteErrors(errs []QuoteError) {
+	codes := make([]string, len(errs))
+	for i, e := range errs {
+		codes[i] = e.Code
+	}
+	sig := strings.Join(codes, ",")
+
+	p.mu.Lock()
+	changed := sig != p.lastQuoteErrSig
+	p.lastQuoteErrSig = sig
+	p.mu.Unlock()
+	if changed && len(errs) > 0 {
+		pusherLog.Warn("%d 只股票行情获取失败: %v", len(errs), errs)
+	}
 }
 
-// pushOrderBookData 推送盘口数据（带diff检测）
+// pushOrderBookData 推送盘口数据（切换股票时推送全量，之后只推送变化的档位）
 func (p *MarketDataPusher) pushOrderBookData() {
 	p.mu.RLock()
 	code := p.currentOrderBook
-	lastHash := p.lastOrderBookHash
 	p.mu.RUnlock()
 
 	if code == "" {
 		return
 	}
 
-	orderBook, err := p.marketService.GetRealOrderBook(code)
+	orderBook, err := p.marketService.GetOrderBookWithMetrics(code)
 	if err != nil {
 		return
 	}
 
-	// 简单hash：买一卖一价格+数量
-	hash := orderBookHash(orderBook)
-	if hash == lastHash {
+	p.mu.Lock()
+	prev, prevCode := p.lastOrderBook, p.lastOrderBookCode
+	if p.currentOrderBook != code {
+		p.mu.Unlock()
+		return // 拉取期间已切换股票
+	}
+	p.lastOrderBook, p.lastOrderBookCode = orderBook, code
+	p.mu.Unlock()
+
+	delta, full := diffOrderBook(prev, orderBook)
+	if full || prevCode != code {
+		p.bus.Emit(EventOrderBookUpdate, orderBook)
+		return
+	}
+	if delta.empty() {
 		return // 无变化，跳过推送
 	}
+	delta.Code = code
+	delta.Metrics = orderBook.Metrics
+	p.bus.Emit(EventOrderBookDelta, delta)
+}
+
+// pushTickData 推送当前盘口股票的逐笔成交（切换股票时全量，之后只推送新增）
+func (p *MarketDataPusher) pushTickData() {
+	p.mu.RLock()
+	code := p.currentOrderBook
+	p.mu.RUnlock()
+
+	if code == "" {
+		return
+	}
+
+	ticks, err := p.marketService.GetTickData(code, defaultTickLimit)
+	if err != nil || len(ticks) == 0 {
+		return
+	}
 
 	p.mu.Lock()
-	p.lastOrderBookHash = hash
+	if p.currentOrderBook != code {
+		p.mu.Unlock()
+		return // 拉取期间已切换股票
+	}
+	incremental := p.lastTickCode == code
+	if incremental {
+		ticks = newTicksSince(ticks, p.lastTick)
+	}
+	if len(ticks) > 0 {
+		p.lastTick, p.lastTickCode = ticks[len(ticks)-1], code
+	}
 	p.mu.Unlock()
 
-	runtime.EventsEmit(p.ctx, EventOrderBookUpdate, orderBook)
+	if len(ticks) == 0 {
+		return
+	}
+	p.bus.Emit(EventTicksUpdate, map[string]any{
+		"code":        code,
+		"data":        ticks,
+		"incremental": incremental,
+	})
 }
 
 // pushTelegraphData 推送快讯数据
@@ -398,7 +606,39 @@ func (p *MarketDataPusher) pushTelegraphData() {
 	p.mu.Unlock()
 
 	// 推送到前端
-	runtime.EventsEmit(p.ctx, EventTelegraphUpdate, latest)
+	p.bus.Emit(EventTelegraphUpdate, latest)
+}
+
+// pushLimitBoard 推送当日涨停/跌停/炸板池（有变化才推送）
+func (p *MarketDataPusher) pushLimitBoard() {
+	if p.limitBoard == nil {
+		return
+	}
+
+	board, err := p.limitBoard.GetLimitBoard("")
+	if err != nil {
+		return
+	}
+
+	sig := limitBoardSignature(board)
+	p.mu.Lock()
+	if sig == p.lastLimitBoardSig {
+		p.mu.Unlock()
+		return
+	}
+	p.lastLimitBoardSig = sig
+	p.mu.Unlock()
+
+	p.bus.Emit(EventLimitBoardUpdate, board)
+}
+
+// pushMarketBreadth 推送涨跌家数与行业板块排行
+func (p *MarketDataPusher) pushMarketBreadth() {
+	breadth, err := p.marketService.GetMarketBreadth()
+	if err != nil {
+		return
+	}
+	p.bus.Emit(EventMarketBreadthUpdate, breadth)
 }
 
 // pushMarketIndices 推送大盘指数
@@ -407,65 +647,107 @@ func (p *MarketDataPusher) pushMarketIndices() {
 	if err != nil {
 		return
 	}
-	runtime.EventsEmit(p.ctx, EventMarketIndicesUpdate, indices)
+	p.bus.Emit(EventMarketIndicesUpdate, indices)
 }
 
-// pushKLineData 推送K线数据（初始化时调用）
+// pushKLineData 推送全部订阅的K线数据（初始化及收盘后调用）
 func (p *MarketDataPusher) pushKLineData() {
-	p.klineSubMu.RLock()
-	sub := p.klineSub
-	p.klineSubMu.RUnlock()
-
-	if sub.Code == "" {
-		return
+	for _, sub := range p.klineSubs.Due(func(KLineSubscription) bool { return true }) {
+		p.pushKLineFull(sub, 240)
 	}
+}
 
-	klines, err := p.marketService.GetKLineData(sub.Code, sub.Period, 240)
+// pushKLineFull 推送单个订阅的全量K线
+func (p *MarketDataPusher) pushKLineFull(sub KLineSubscription, days int) {
+	klines, err := p.marketService.GetKLineData(sub.Code, sub.Period, days, sub.Adjust)
 	if err != nil {
 		return
 	}
 
-	runtime.EventsEmit(p.ctx, EventKLineUpdate, map[string]any{
+	p.bus.Emit(EventKLineUpdate, map[string]any{
 		"code":   sub.Code,
 		"period": sub.Period,
+		"adjust": sub.Adjust,
 		"data":   klines,
 	})
 }
 
-// pushKLineMinute 推送分时K线（增量模式，仅推送最新1根）
+// pushKLineMinute 推送分钟K线（增量模式，每个订阅仅推送最新1根）
+// 每出现一根新的 1 分钟K线推送一次，5/15/30/60 分钟线无需等待定时全量刷新
 func (p *MarketDataPusher) pushKLineMinute() {
-	p.klineSubMu.RLock()
-	sub := p.klineSub
-	lastTime := p.lastKLineTime
-	p.klineSubMu.RUnlock()
+	subs := p.klineSubs.Due(func(sub KLineSubscription) bool { return isIntradayPeriod(sub.Period) })
+	for _, sub := range subs {
+		latest, latestTime, ok := p.latestIntradayBar(sub)
+		if !ok {
+			continue
+		}
+		lastTime, ok := p.klineSubs.SwapLastTime(sub, latestTime)
+		if !ok {
+			continue // 推送期间已取消订阅
+		}
 
-	if sub.Code == "" || sub.Period != "1m" {
-		return
+		// 首次或时间变化才推送
+		if lastTime == 0 || latestTime != lastTime {
+			payload := map[string]any{
+				"code":        sub.Code,
+				"period":      sub.Period,
+				"data":        []models.KLineData{latest},
+				"incremental": true,
+			}
+			if sub.Period != "1m" {
+				payload["adjust"] = sub.Adjust
+			}
+			p.bus.Emit(EventKLineUpdate, payload)
+		}
 	}
+}
 
-	// 只获取最新几根用于增量判断
-	klines, err := p.marketService.GetKLineData(sub.Code, "1m", 5)
-	if err != nil || len(klines) == 0 {
-		return
+// latestIntradayBar 返回订阅周期最新一根K线及最新 1 分钟K线的时间戳
+// 5/15/30/60 分钟线由当日 1 分钟K线重采样出正在形成的一根，接在该周期历史K线之后计算均线
+func (p *MarketDataPusher) latestIntradayBar(sub KLineSubscription) (models.KLineData, int64, bool) {
+	if sub.Period == "1m" {
+		// 获取全天分时，均价与累计净流入需从开盘累计
+		klines, err := p.marketService.GetKLineData(sub.Code, "1m", dayMinuteBars, AdjustNone)
+		if err != nil || len(klines) == 0 {
+			return models.KLineData{}, 0, false
+		}
+		latest := klines[len(klines)-1]
+		return latest, parseKLineTime(latest.Time), true
 	}
 
-	latest := klines[len(klines)-1]
-	latestTime := parseKLineTime(latest.Time)
-
-	// 推送最新一根（增量）
-	p.klineSubMu.Lock()
-	p.lastKLineTime = latestTime
-	p.klineSubMu.Unlock()
-
-	// 首次或时间变化才推送
-	if lastTime == 0 || latestTime != lastTime {
-		runtime.EventsEmit(p.ctx, EventKLineUpdate, map[string]any{
-			"code":        sub.Code,
-			"period":      "1m",
-			"data":        []models.KLineData{latest},
-			"incremental": true,
-		})
+	minutes, err := p.marketService.GetKLineData(sub.Code, "1m", dayMinuteBars, AdjustNone)
+	if err != nil || len(minutes) == 0 {
+		return models.KLineData{}, 0, false
+	}
+	scale, _ := strconv.Atoi(strings.TrimSuffix(sub.Period, "m"))
+	bars := analysis.Resample(minutes, scale)
+	if len(bars) == 0 {
+		return models.KLineData{}, 0, false
+	}
+	bars = bars[len(bars)-1:]
+	if sub.Adjust != AdjustNone {
+		factors, err := p.marketService.getAdjustFactors(sub.Code, sub.Adjust)
+		if err != nil {
+			return models.KLineData{}, 0, false
+		}
+		bars = applyAdjust(bars, factors, sub.Adjust)
 	}
+
+	history, err := p.marketService.GetKLineData(sub.Code, sub.Period, analysis.MAWarmup+1, sub.Adjust)
+	if err != nil {
+		return models.KLineData{}, 0, false
+	}
+	latest := bars[0]
+	switch n := len(history); {
+	case n > 0 && history[n-1].Time == latest.Time:
+		history[n-1] = latest
+	case n == 0 || history[n-1].Time < latest.Time:
+		history = append(history, latest)
+	default:
+		return models.KLineData{}, 0, false // 历史数据已更新，等待全量刷新
+	}
+	analysis.FillMA(history)
+	return history[len(history)-1], parseKLineTime(minutes[len(minutes)-1].Time), true
 }
 
 // parseKLineTime 解析K线时间为时间戳
@@ -476,61 +758,10 @@ func parseKLineTime(t string) int64 {
 	return 0
 }
 
-// orderBookHash 生成盘口简单hash（买一卖一）
-func orderBookHash(ob models.OrderBook) string {
-	var b1Price, b1Size, a1Price, a1Size float64
-	if len(ob.Bids) > 0 {
-		b1Price, b1Size = ob.Bids[0].Price, float64(ob.Bids[0].Size)
-	}
-	if len(ob.Asks) > 0 {
-		a1Price, a1Size = ob.Asks[0].Price, float64(ob.Asks[0].Size)
-	}
-	return fmt.Sprintf("%.2f:%.0f:%.2f:%.0f", b1Price, b1Size, a1Price, a1Size)
-}
-
-// pushKLineDay 推送日/周/月K线（5分钟间隔，仅当订阅周期非1m时推送）
+// pushKLineDay 推送日/周/月K线（5分钟间隔，仅推送非1m周期的订阅）
 func (p *MarketDataPusher) pushKLineDay() {
-	p.klineSubMu.RLock()
-	sub := p.klineSub
-	p.klineSubMu.RUnlock()
-
-	// 仅推送日K/周K/月K
-	if sub.Code == "" || sub.Period == "1m" {
-		return
-	}
-
-	klines, err := p.marketService.GetKLineData(sub.Code, sub.Period, 120)
-	if err != nil {
-		return
-	}
-
-	runtime.EventsEmit(p.ctx, EventKLineUpdate, map[string]any{
-		"code":   sub.Code,
-		"period": sub.Period,
-		"data":   klines,
-	})
-}
-
-// AddSubscription 添加订阅
-func (p *MarketDataPusher) AddSubscription(code string) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	if !slices.Contains(p.subscribedCodes, code) {
-		p.subscribedCodes = append(p.subscribedCodes, code)
-	}
-}
-
-// RemoveSubscription 移除订阅
-func (p *MarketDataPusher) RemoveSubscription(code string) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	for i, c := range p.subscribedCodes {
-		if c == code {
-			p.subscribedCodes = append(p.subscribedCodes[:i], p.subscribedCodes[i+1:]...)
-			return
-		}
+	for _, sub := range p.klineSubs.Due(func(sub KLineSubscription) bool { return sub.Period != "1m" }) {
+		p.pushKLineFull(sub, 120)
 	}
 }
 
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/eventbus"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// TestMarketPusherSubscriptions 测试通过事件总线接收客户端订阅
+func TestMarketPusherSubscriptions(t *testing.T) {
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.AddToWatchlist(models.Stock{Symbol: "sh600000", Name: "浦发银行"})
+	cs.AddToWatchlist(models.Stock{Symbol: "sz000001", Name: "平安银行"})
+	group, _ := cs.AddWatchlistGroup("银行")
+	cs.AddToGroup(group.ID, "sz000001")
+
+	bus := eventbus.NewMemoryBus()
+	p := NewMarketDataPusher(bus, NewMarketService(), cs, nil, nil)
+	p.Start()
+
+	subscribed := func() []string {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		return slices.Clone(p.subscribedCodes)
+	}
+	waitFor := func(want []string) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !slices.Equal(subscribed(), want) {
+			if time.Now().After(deadline) {
+				t.Fatalf("subscriptions = %v, want %v", subscribed(), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// 启动时订阅全部自选股
+	waitFor([]string{"sh600000", "sz000001"})
+
+	bus.Dispatch(EventMarketSubscribe, []any{"sh600519"})
+	waitFor([]string{"sh600519"})
+
+	bus.Dispatch(EventGroupSubscribe, group.ID)
+	waitFor([]string{"sz000001"})
+
+	// 停止后不再响应客户端事件
+	p.Stop()
+	bus.Dispatch(EventMarketSubscribe, []any{"sh600036"})
+	time.Sleep(50 * time.Millisecond)
+	if got := subscribed(); !slices.Equal(got, []string{"sz000001"}) {
+		t.Errorf("listener should be removed after Stop: %v", got)
+	}
+}
+
+// TestMarketPusherSubscriptionSnapshots 测试订阅变更时清除新增与移除股票的增量快照
+func TestMarketPusherSubscriptionSnapshots(t *testing.T) {
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewMarketDataPusher(eventbus.NewMemoryBus(), NewMarketService(), cs, nil, nil)
+	p.updateSubscriptions([]any{"sh600000", "sz000001"})
+	for _, code := range []string{"sh600000", "sz000001", "sh600519"} {
+		p.lastStocks[code] = models.Stock{Symbol: code}
+	}
+
+	// 保留 sh600000，移除 sz000001，新增 sh600519（残留的过期快照）
+	p.updateSubscriptions([]any{"sh600000", "sh600519"})
+	if _, ok := p.lastStocks["sh600000"]; !ok {
+		t.Error("snapshot of unchanged code should be kept")
+	}
+	for _, code := range []string{"sz000001", "sh600519"} {
+		if _, ok := p.lastStocks[code]; ok {
+			t.Errorf("snapshot of %s should be cleared", code)
+		}
+	}
+}
+
+// TestMarketPusherVisibility 测试窗口隐藏时降频、恢复可见时重置快照以补推全量
+func TestMarketPusherVisibility(t *testing.T) {
+	cs, err := NewConfigService(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := eventbus.NewMemoryBus()
+	p := NewMarketDataPusher(bus, NewMarketService(), cs, nil, nil)
+	p.Start()
+	defer p.Stop()
+
+	waitHidden := func(want bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for p.isHidden() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("hidden = %v, want %v", p.isHidden(), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	bus.Dispatch(EventWindowVisibility, false)
+	waitHidden(true)
+
+	p.mu.Lock()
+	p.lastStocks["sh600000"] = models.Stock{Symbol: "sh600000"}
+	p.lastOrderBookCode, p.lastTickCode = "sh600000", "sh600000"
+	p.mu.Unlock()
+
+	bus.Dispatch(EventWindowVisibility, true)
+	waitHidden(false)
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.lastStocks) != 0 || p.lastOrderBookCode != "" || p.lastTickCode != "" {
+		t.Errorf("snapshots should be reset after becoming visible")
+	}
+}
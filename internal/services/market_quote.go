@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+// 批量行情请求参数
+const (
+	quoteBatchSize        = 80 // 单次请求的股票数，避免 URL 过长被上游拒绝
+	quoteBatchConcurrency = 4  // 同时进行的批次数
+	quoteMinFields        = 32 // 新浪行情有效数据的最少字段数
+)
+
+var (
+	errNoQuote      = errors.New("无效代码或暂无行情数据")
+	errInvalidQuote = errors.New("股票代码格式无效")
+)
+
+// QuoteError 单只股票的行情获取错误
+type QuoteError struct {
+	Code string
+	Err  error
+}
+
+func (e QuoteError) Error() string { return e.Code + ": " + e.Err.Error() }
+
+func (e QuoteError) Unwrap() error { return e.Err }
+
+// GetQuotes 批量获取股票实时行情：按批请求后合并，结果按传入顺序排列（重复代码只保留一次），并补充换手率与估值；
+// 无效代码或某一批请求失败只影响相应代码，其余数据照常返回，失败的代码逐个记录在 errs 中
+func (ms *MarketService) GetQuotes(codes ...string) ([]models.Stock, []QuoteError) {
+	fields, order, errs := ms.fetchQuoteFields(codes)
+	stocks := make([]models.Stock, 0, len(fields))
+	for _, code := range order {
+		if parts, ok := fields[code]; ok {
+			stocks = append(stocks, ms.parseStockFields(code, parts))
+		}
+	}
+	ms.fillValuations(stocks)
+	return stocks, errs
+}
+
+// fetchQuoteFields 分批请求行情，返回代码 -> 原始字段、去重后的代码顺序与失败的代码
+func (ms *MarketService) fetchQuoteFields(codes []string) (map[string][]string, []string, []QuoteError) {
+	var errs []QuoteError
+	seen := make(map[string]bool, len(codes))
+	order := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.TrimSpace(code)
+		switch {
+		case seen[code]:
+			continue
+		case code == "" || strings.ContainsAny(code, ",&=? \t"):
+			errs = append(errs, QuoteError{Code: code, Err: errInvalidQuote})
+			continue
+		}
+		seen[code] = true
+		order = append(order, code)
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		fields    = make(map[string][]string, len(order))
+		batchErrs = make(map[string]error)
+		sem       = make(chan struct{}, quoteBatchConcurrency)
+	)
+	for start := 0; start < len(order); start += quoteBatchSize {
+		batch := order[start:min(start+quoteBatchSize, len(order))]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			got, err := ms.fetchQuoteBatch(batch)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, code := range batch {
+				if err != nil {
+					batchErrs[code] = err
+				} else if parts, ok := got[code]; ok {
+					fields[code] = parts
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, code := range order {
+		if _, ok := fields[code]; ok {
+			continue
+		}
+		err := errNoQuote
+		if batchErr, ok := batchErrs[code]; ok {
+			err = batchErr
+		}
+		errs = append(errs, QuoteError{Code: code, Err: err})
+	}
+	return fields, order, errs
+}
+
+// fetchQuoteBatch 请求一批股票的行情，返回有数据的代码 -> 原始字段
+func (ms *MarketService) fetchQuoteBatch(codes []string) (map[string][]string, error) {
+	url := fmt.Sprintf(sinaStockURL, time.Now().UnixNano(), strings.Join(codes, ","))
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Referer", "http://finance.sina.com.cn")
+
+	resp, err := ms.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("行情接口返回 HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(transform.NewReader(resp.Body, simplifiedchinese.GBK.NewDecoder()))
+	if err != nil {
+		return nil, err
+	}
+	return parseQuoteFields(string(body)), nil
+}
+
+// parseQuoteFields 解析新浪行情响应，跳过空数据与字段不足的代码
+func parseQuoteFields(data string) map[string][]string {
+	fields := make(map[string][]string)
+	for _, match := range sinaStockRegex.FindAllStringSubmatch(data, -1) {
+		if len(match) < 3 || match[2] == "" {
+			continue
+		}
+		parts := strings.Split(match[2], ",")
+		if len(parts) < quoteMinFields {
+			continue
+		}
+		fields[match[1]] = parts
+	}
+	return fields
+}
+
+// QuoteFetchError 用于全部代码都没有取到数据时：返回上游请求错误（仅为无效代码时返回 nil，与单只查询无数据的行为一致）
+func QuoteFetchError(errs []QuoteError) error {
+	for _, e := range errs {
+		if !errors.Is(e.Err, errNoQuote) && !errors.Is(e.Err, errInvalidQuote) {
+			return fmt.Errorf("获取行情失败: %w", e.Err)
+		}
+	}
+	return nil
+}
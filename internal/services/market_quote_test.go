@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// quoteTransport 模拟新浪行情接口：按请求中的代码返回数据，fail 中的代码所在批次整体失败
+type quoteTransport struct {
+	fail     string
+	requests atomic.Int32
+}
+
+func (tr *quoteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != "hq.sinajs.cn" {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"data":null}`)), Header: make(http.Header)}, nil
+	}
+	tr.requests.Add(1)
+	_, list, _ := strings.Cut(req.URL.String(), "list=")
+	if tr.fail != "" && strings.Contains(list, tr.fail) {
+		return nil, errors.New("connection reset")
+	}
+	var body strings.Builder
+	for _, code := range strings.Split(list, ",") {
+		if code == "sz000000" {
+			body.WriteString(`var hq_str_sz000000="";` + "\n")
+			continue
+		}
+		body.WriteString(`var hq_str_` + code + `="` + strings.Repeat("1,", quoteMinFields) + `";` + "\n")
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body.String())), Header: make(http.Header)}, nil
+}
+
+func TestParseQuoteFields(t *testing.T) {
+	data := `var hq_str_sh600000="` + strings.Repeat("1,", quoteMinFields) + `";
+var hq_str_sz000000="";
+var hq_str_sz000001="a,b,c";`
+	fields := parseQuoteFields(data)
+	if len(fields) != 1 || fields["sh600000"] == nil {
+		t.Errorf("parseQuoteFields = %v", fields)
+	}
+}
+
+func TestFetchQuoteFields(t *testing.T) {
+	tr := &quoteTransport{}
+	ms := &MarketService{client: &http.Client{Transport: tr}, valuations: make(map[string]stockValuation)}
+
+	codes := []string{"sh600000", " sh600000", "", "bad,code", "sz000000"}
+	for i := range quoteBatchSize {
+		codes = append(codes, fmt.Sprintf("sz3%05d", i))
+	}
+	fields, order, errs := ms.fetchQuoteFields(codes)
+	if tr.requests.Load() != 2 {
+		t.Errorf("requests = %d, want 2 batches", tr.requests.Load())
+	}
+	if len(order) != quoteBatchSize+2 || order[0] != "sh600000" || len(fields) != quoteBatchSize+1 {
+		t.Errorf("order = %d, fields = %d", len(order), len(fields))
+	}
+	if len(errs) != 3 || !errors.Is(errs[0], errInvalidQuote) || !errors.Is(errs[2], errNoQuote) || errs[2].Code != "sz000000" {
+		t.Errorf("errs = %v", errs)
+	}
+	if err := QuoteFetchError(errs); err != nil {
+		t.Errorf("invalid codes only should not be a fetch error: %v", err)
+	}
+
+	// 一批失败只影响该批代码
+	ms.client.Transport = &quoteTransport{fail: "sz300079"}
+	stocks, errs := ms.GetQuotes(codes...)
+	if len(stocks) != quoteBatchSize-1 || QuoteFetchError(errs) == nil {
+		t.Errorf("stocks = %d, errs = %v", len(stocks), len(errs))
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/run-bigpig/jcp/internal/analysis"
 	"github.com/run-bigpig/jcp/internal/logger"
 	"github.com/run-bigpig/jcp/internal/models"
 	"github.com/run-bigpig/jcp/internal/pkg/paths"
@@ -33,7 +35,7 @@ var (
 
 const (
 	sinaStockURL = "http://hq.sinajs.cn/rn=%d&list=%s"
-	sinaKLineURL = "http://quotes.sina.cn/cn/api/json_v2.php/CN_MarketDataService.getKLineData?symbol=%s&scale=%s&ma=5,10,20&datalen=%d"
+	sinaKLineURL = "http://quotes.sina.cn/cn/api/json_v2.php/CN_MarketDataService.getKLineData?symbol=%s&scale=%s&ma=no&datalen=%d"
 )
 
 const (
@@ -73,6 +75,8 @@ type MarketStatus struct {
 	StatusText  string `json:"statusText"`  // 中文状态描述
 	IsTradeDay  bool   `json:"isTradeDay"`  // 是否交易日
 	HolidayName string `json:"holidayName"` // 节假日名称（如有）
+	// 下一交易日 YYYY-MM-DD（收盘后与休市时给出）
+	NextTradeDay string `json:"nextTradeDay,omitempty"`
 }
 
 // TradingPeriod 交易时段
@@ -103,6 +107,22 @@ type MarketService struct {
 	klineCache    map[string]*klineCache
 	klineCacheMu  sync.RWMutex
 	klineCacheTTL time.Duration
+
+	// 复权因子缓存
+	adjustCache   map[string]*adjustFactorCache
+	adjustCacheMu sync.Mutex
+
+	// 市场宽度缓存
+	breadthCache *breadthCache
+	breadthMu    sync.Mutex
+
+	// 盘口快照，用于撤单识别
+	orderBooks  map[string]*orderBookSnapshot
+	orderBookMu sync.Mutex
+
+	// 估值与股本缓存
+	valuations  map[string]stockValuation
+	valuationMu sync.Mutex
 }
 
 // NewMarketService 创建市场数据服务
@@ -113,6 +133,9 @@ func NewMarketService() *MarketService {
 		cacheTTL:      2 * time.Second, // 股票缓存2秒
 		klineCache:    make(map[string]*klineCache),
 		klineCacheTTL: klineCacheTTLDefault, // 日/周/月K使用较长缓存，减少API调用
+		adjustCache:   make(map[string]*adjustFactorCache),
+		orderBooks:    make(map[string]*orderBookSnapshot),
+		valuations:    make(map[string]stockValuation),
 	}
 	// 启动缓存清理协程
 	go ms.cleanCacheLoop()
@@ -154,6 +177,24 @@ func (ms *MarketService) cleanExpiredCache() {
 		}
 	}
 	ms.klineCacheMu.Unlock()
+
+	// 清理盘口快照
+	ms.orderBookMu.Lock()
+	for code, snap := range ms.orderBooks {
+		if now.Sub(snap.timestamp) > orderBookSnapshotTTL {
+			delete(ms.orderBooks, code)
+		}
+	}
+	ms.orderBookMu.Unlock()
+
+	// 清理估值缓存
+	ms.valuationMu.Lock()
+	for code, v := range ms.valuations {
+		if now.Sub(v.timestamp) > valuationCacheTTL*3 {
+			delete(ms.valuations, code)
+		}
+	}
+	ms.valuationMu.Unlock()
 }
 
 // getKLineCacheTTL 返回不同周期的缓存策略
@@ -204,97 +245,32 @@ func (ms *MarketService) GetStockDataWithOrderBook(codes ...string) ([]StockWith
 	return data, nil
 }
 
-// fetchStockDataWithOrderBook 从API获取股票数据（含盘口）
+// fetchStockDataWithOrderBook 从API获取股票数据（含盘口），分批请求，部分代码失败时返回其余数据
 func (ms *MarketService) fetchStockDataWithOrderBook(codes ...string) ([]StockWithOrderBook, error) {
-	codeList := strings.Join(codes, ",")
-	url := fmt.Sprintf(sinaStockURL, time.Now().UnixNano(), codeList)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Referer", "http://finance.sina.com.cn")
-
-	resp, err := ms.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	reader := transform.NewReader(resp.Body, simplifiedchinese.GBK.NewDecoder())
-	body, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, err
-	}
-
-	return ms.parseSinaStockDataWithOrderBook(string(body))
-}
-
-// parseSinaStockDataWithOrderBook 解析新浪股票数据（含盘口）
-func (ms *MarketService) parseSinaStockDataWithOrderBook(data string) ([]StockWithOrderBook, error) {
-	var stocks []StockWithOrderBook
-	matches := sinaStockRegex.FindAllStringSubmatch(data, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 || match[2] == "" {
-			continue
-		}
-		parts := strings.Split(match[2], ",")
-		if len(parts) < 32 {
-			continue
+	fields, order, errs := ms.fetchQuoteFields(codes)
+	stocks := make([]StockWithOrderBook, 0, len(fields))
+	for _, code := range order {
+		if parts, ok := fields[code]; ok {
+			stocks = append(stocks, ms.parseStockWithOrderBook(code, parts))
 		}
-		stock := ms.parseStockWithOrderBook(match[1], parts)
-		stocks = append(stocks, stock)
+	}
+	if len(stocks) == 0 {
+		return nil, QuoteFetchError(errs)
 	}
 	return stocks, nil
 }
 
-// GetStockRealTimeData 获取股票实时数据
+// GetStockRealTimeData 获取股票实时数据；只要有代码取到数据就不返回错误，需要逐个代码的失败原因时使用 GetQuotes
 func (ms *MarketService) GetStockRealTimeData(codes ...string) ([]models.Stock, error) {
 	if len(codes) == 0 {
 		return nil, nil
 	}
-
-	codeList := strings.Join(codes, ",")
-	url := fmt.Sprintf(sinaStockURL, time.Now().UnixNano(), codeList)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Referer", "http://finance.sina.com.cn")
-
-	resp, err := ms.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	reader := transform.NewReader(resp.Body, simplifiedchinese.GBK.NewDecoder())
-	body, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, err
+	stocks, errs := ms.GetQuotes(codes...)
+	if len(stocks) == 0 {
+		return nil, QuoteFetchError(errs)
 	}
-
-	return ms.parseSinaStockData(string(body), codes)
-}
-
-// parseSinaStockData 解析新浪股票数据
-func (ms *MarketService) parseSinaStockData(data string, codes []string) ([]models.Stock, error) {
-	var stocks []models.Stock
-	matches := sinaStockRegex.FindAllStringSubmatch(data, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 || match[2] == "" {
-			continue
-		}
-		parts := strings.Split(match[2], ",")
-		if len(parts) < 32 {
-			continue
-		}
-
-		stock := ms.parseStockFields(match[1], parts)
-		stocks = append(stocks, stock)
+	if len(errs) > 0 {
+		log.Debug("%d 只股票行情获取失败: %v", len(errs), errs)
 	}
 	return stocks, nil
 }
@@ -409,11 +385,47 @@ func (ms *MarketService) calculateOrderBookTotals(items []models.OrderBookItem)
 	}
 }
 
-// GetKLineData 获取K线数据（带缓存）
-func (ms *MarketService) GetKLineData(code string, period string, days int) ([]models.KLineData, error) {
-	cacheKey := fmt.Sprintf("%s:%s:%d", code, period, days)
-	ttl := ms.getKLineCacheTTL(period)
+// GetKLineData 获取K线数据（带缓存），adjust 为复权方式: none/qfq/hfq
+func (ms *MarketService) GetKLineData(code string, period string, days int, adjust string) ([]models.KLineData, error) {
+	adjust, err := normalizeAdjust(adjust)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分时之外多取若干根用于计算均线，保证返回的每根K线均线完整
+	fetchDays := days
+	if period != "1m" {
+		fetchDays += analysis.MAWarmup
+	}
+	cacheKey := fmt.Sprintf("%s:%s:%d", code, period, fetchDays)
+	klines, err := ms.cachedKLines(cacheKey, ms.getKLineCacheTTL(period), func() ([]models.KLineData, error) {
+		return ms.fetchKLineData(code, period, fetchDays)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if adjust != AdjustNone {
+		factors, err := ms.getAdjustFactors(code, adjust)
+		if err != nil {
+			return nil, fmt.Errorf("获取复权因子失败: %w", err)
+		}
+		klines = applyAdjust(klines, factors, adjust)
+	}
+	if period == "1m" {
+		return klines, nil
+	}
+	return withMA(klines, days), nil
+}
+
+// withMA 按（复权后的）收盘价计算均线并保留最后 days 根，返回新切片（不修改缓存中的数据）
+func withMA(klines []models.KLineData, days int) []models.KLineData {
+	klines = slices.Clone(klines)
+	analysis.FillMA(klines)
+	return klines[max(len(klines)-days, 0):]
+}
 
+// cachedKLines 读取K线缓存，未命中或过期时调用 fetch 并写入缓存
+func (ms *MarketService) cachedKLines(cacheKey string, ttl time.Duration, fetch func() ([]models.KLineData, error)) ([]models.KLineData, error) {
 	// 检查缓存
 	ms.klineCacheMu.RLock()
 	if cached, ok := ms.klineCache[cacheKey]; ok {
@@ -429,7 +441,7 @@ func (ms *MarketService) GetKLineData(code string, period string, days int) ([]m
 	ms.klineCacheMu.RUnlock()
 
 	// 从API获取数据
-	klines, err := ms.fetchKLineData(code, period, days)
+	klines, err := fetch()
 	if err != nil {
 		return nil, err
 	}
@@ -467,10 +479,10 @@ func (ms *MarketService) fetchKLineData(code string, period string, days int) ([
 		return nil, err
 	}
 
-	// 分时模式下只返回当天的数据，并计算均价线
+	// 分时模式下只返回当天的数据，并计算均价线与累计净流入
 	if period == "1m" {
 		klines = ms.filterTodayKLines(klines)
-		klines = ms.calculateAvgLine(klines)
+		analysis.FillIntraday(klines)
 	}
 
 	return klines, nil
@@ -481,6 +493,8 @@ func (ms *MarketService) periodToScale(period string) string {
 	switch period {
 	case "1m":
 		return "1" // 1分钟线（分时图）
+	case "5m", "15m", "30m", "60m":
+		return strings.TrimSuffix(period, "m") // 5/15/30/60分钟线
 	case "1d":
 		return "240" // 日线
 	case "1w":
@@ -521,41 +535,17 @@ func (ms *MarketService) filterTodayKLines(klines []models.KLineData) []models.K
 	return result
 }
 
-// calculateAvgLine 计算分时均价线 (VWAP = 累计成交额 / 累计成交量)
-func (ms *MarketService) calculateAvgLine(klines []models.KLineData) []models.KLineData {
-	if len(klines) == 0 {
-		return klines
-	}
-
-	var totalAmount float64
-	var totalVolume int64
-
-	for i := range klines {
-		totalAmount += klines[i].Amount
-		totalVolume += klines[i].Volume
-
-		if totalVolume > 0 {
-			klines[i].Avg = totalAmount / float64(totalVolume)
-		}
-	}
-
-	return klines
-}
-
 // parseKLineData 解析K线数据 - 使用标准JSON解析
 func (ms *MarketService) parseKLineData(data string) ([]models.KLineData, error) {
-	// 新浪API返回的K线数据结构（含均线和成交额）
+	// 新浪API返回的K线数据结构（含成交额；均线由 analysis.FillMA 按复权后价格统一计算）
 	type sinaKLine struct {
-		Day       string  `json:"day"`
-		Open      string  `json:"open"`
-		High      string  `json:"high"`
-		Low       string  `json:"low"`
-		Close     string  `json:"close"`
-		Volume    string  `json:"volume"`
-		Amount    string  `json:"amount"`
-		MAPrice5  float64 `json:"ma_price5"`
-		MAPrice10 float64 `json:"ma_price10"`
-		MAPrice20 float64 `json:"ma_price20"`
+		Day    string `json:"day"`
+		Open   string `json:"open"`
+		High   string `json:"high"`
+		Low    string `json:"low"`
+		Close  string `json:"close"`
+		Volume string `json:"volume"`
+		Amount string `json:"amount"`
 	}
 
 	var sinaData []sinaKLine
@@ -580,9 +570,6 @@ func (ms *MarketService) parseKLineData(data string) ([]models.KLineData, error)
 			Close:  closePrice,
 			Volume: volume,
 			Amount: amount,
-			MA5:    item.MAPrice5,
-			MA10:   item.MAPrice10,
-			MA20:   item.MAPrice20,
 		})
 	}
 	return klines, nil
@@ -622,60 +609,14 @@ func (ms *MarketService) GenerateOrderBook(price float64) models.OrderBook {
 	return models.OrderBook{Bids: bids, Asks: asks}
 }
 
-// GetMarketStatus 获取当前市场交易状态
+// GetMarketStatus 获取当前市场交易状态（由交易日历判断，含节假日）
 func (ms *MarketService) GetMarketStatus() MarketStatus {
-	now := time.Now()
-	// 使用固定时区 UTC+8，避免 Windows 缺少时区数据库的问题
-	loc := time.FixedZone("CST", 8*60*60)
-	now = now.In(loc)
-	// 检查是否为交易日
-	isTradeDay, holidayName := ms.isTradeDay(now)
-	if !isTradeDay {
-		statusText := "休市"
-		if holidayName != "" {
-			statusText = holidayName + "休市"
-		} else if now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
-			statusText = "周末休市"
-		}
-		result := MarketStatus{
-			Status:      "closed",
-			StatusText:  statusText,
-			IsTradeDay:  false,
-			HolidayName: holidayName,
-		}
-		return result
-	}
-
-	// 交易日，判断当前时间段
-	hour, minute := now.Hour(), now.Minute()
-	currentMinutes := hour*60 + minute
-
-	// A股交易时间: 9:30-11:30, 13:00-15:00
-	var result MarketStatus
-	switch {
-	case currentMinutes < 9*60+15:
-		result = MarketStatus{Status: "pre_market", StatusText: "盘前", IsTradeDay: true}
-	case currentMinutes < 9*60+30:
-		result = MarketStatus{Status: "pre_market", StatusText: "集合竞价", IsTradeDay: true}
-	case currentMinutes < 11*60+30:
-		result = MarketStatus{Status: "trading", StatusText: "交易中", IsTradeDay: true}
-	case currentMinutes < 13*60:
-		result = MarketStatus{Status: "lunch_break", StatusText: "午间休市", IsTradeDay: true}
-	case currentMinutes < 15*60:
-		result = MarketStatus{Status: "trading", StatusText: "交易中", IsTradeDay: true}
-	default:
-		result = MarketStatus{Status: "closed", StatusText: "已收盘", IsTradeDay: true}
-	}
-	return result
+	return GetTradingCalendar().Status(time.Now())
 }
 
 // GetTradingSchedule 获取交易时间表（供前端判断市场状态）
 func (ms *MarketService) GetTradingSchedule() TradingSchedule {
-	now := time.Now()
-	loc := time.FixedZone("CST", 8*60*60)
-	now = now.In(loc)
-
-	isTradeDay, holidayName := ms.isTradeDay(now)
+	isTradeDay, holidayName := GetTradingCalendar().IsTradeDay(time.Now())
 
 	// A股交易时段配置
 	periods := []TradingPeriod{
@@ -694,176 +635,12 @@ func (ms *MarketService) GetTradingSchedule() TradingSchedule {
 	}
 }
 
-// isTradeDay 判断指定日期是否为交易日
-// A股交易日判定：非周末 且 非节假日（调休上班也不算交易日）
-func (ms *MarketService) isTradeDay(date time.Time) (bool, string) {
-
-	// 周末一律不是交易日
-	weekday := date.Weekday()
-	if weekday == time.Saturday || weekday == time.Sunday {
-		return false, "周末"
-	}
-
-	// 工作日：检查是否为节假日
-	isOffDay, inList, note := ms.getHolidayStatus(date)
-	if inList && isOffDay {
-		return false, note
-	}
-
-	return true, ""
-}
-
-// getHolidayStatus 获取指定日期的节假日状态
-// 返回: isOffDay=true表示休息日, inList=是否在节假日列表中, note为节假日名称
-func (ms *MarketService) getHolidayStatus(date time.Time) (isOffDay bool, inList bool, note string) {
-	dateStr := date.Format("2006-01-02")
-	year := date.Year()
-
-	// 加载该年份的节假日数据
-	yearData, err := ms.loadHolidayData(year)
-	if err != nil {
-		log.Warn("加载 %d 年节假日数据失败: %v", year, err)
-		return false, false, ""
-	}
-
-	// 查找该日期
-	if isOff, exists := yearData[dateStr]; exists {
-		noteName := ms.getHolidayNote(year, dateStr)
-		return isOff, true, noteName
-	}
-
-	// 不在节假日列表中
-	return false, false, ""
-}
-
-// getHolidayNote 获取节假日名称
-func (ms *MarketService) getHolidayNote(year int, dateStr string) string {
-	cacheFile := getHolidayCacheFile(year)
-	fileData, err := os.ReadFile(cacheFile)
-	if err != nil {
-		return ""
-	}
-
-	var hd holidayData
-	if json.Unmarshal(fileData, &hd) != nil {
-		return ""
-	}
-
-	for _, day := range hd.Days {
-		if day.Date == dateStr {
-			return day.Name
-		}
-	}
-	return ""
-}
-
 // tradeDatesCache 交易日缓存文件结构
 type tradeDatesCache struct {
 	TradeDates []string  `json:"tradeDates"` // 交易日列表
 	UpdatedAt  time.Time `json:"updatedAt"`  // 更新时间
 }
 
-// holidayData 节假日数据结构
-type holidayData struct {
-	Year int          `json:"year"`
-	Days []holidayDay `json:"days"`
-}
-
-type holidayDay struct {
-	Name     string `json:"name"`
-	Date     string `json:"date"`
-	IsOffDay bool   `json:"isOffDay"`
-}
-
-// holidayCache 节假日缓存（按年份）
-var (
-	holidayCacheMu   sync.RWMutex
-	holidayCacheData = make(map[int]map[string]bool) // year -> date -> isOffDay
-)
-
-const holidayCDNURL = "https://cdn.jsdelivr.net/gh/NateScarlet/holiday-cn@master/%d.json"
-
-// getHolidayCacheFile 获取节假日缓存文件路径
-func getHolidayCacheFile(year int) string {
-	return filepath.Join(paths.EnsureCacheDir("holiday"), fmt.Sprintf("%d.json", year))
-}
-
-// loadHolidayData 加载指定年份的节假日数据
-func (ms *MarketService) loadHolidayData(year int) (map[string]bool, error) {
-	// 先检查内存缓存
-	holidayCacheMu.RLock()
-	if data, ok := holidayCacheData[year]; ok {
-		holidayCacheMu.RUnlock()
-		return data, nil
-	}
-	holidayCacheMu.RUnlock()
-
-	// 尝试从文件缓存加载
-	cacheFile := getHolidayCacheFile(year)
-	if fileData, err := os.ReadFile(cacheFile); err == nil {
-		var hd holidayData
-		if json.Unmarshal(fileData, &hd) == nil {
-			data := ms.parseHolidayData(&hd)
-			holidayCacheMu.Lock()
-			holidayCacheData[year] = data
-			holidayCacheMu.Unlock()
-			return data, nil
-		}
-	}
-
-	// 从CDN获取
-	return ms.fetchHolidayData(year)
-}
-
-// fetchHolidayData 从CDN获取节假日数据
-func (ms *MarketService) fetchHolidayData(year int) (map[string]bool, error) {
-	url := fmt.Sprintf(holidayCDNURL, year)
-	resp, err := ms.client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("获取节假日数据失败: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var hd holidayData
-	if err := json.Unmarshal(body, &hd); err != nil {
-		return nil, err
-	}
-
-	// 保存到文件缓存
-	cacheFile := getHolidayCacheFile(year)
-	os.WriteFile(cacheFile, body, 0644)
-
-	// 解析并缓存到内存
-	data := ms.parseHolidayData(&hd)
-	holidayCacheMu.Lock()
-	holidayCacheData[year] = data
-	holidayCacheMu.Unlock()
-
-	log.Info("加载 %d 年节假日数据，共 %d 条", year, len(hd.Days))
-	return data, nil
-}
-
-// parseHolidayData 解析节假日数据为 map
-func (ms *MarketService) parseHolidayData(hd *holidayData) map[string]bool {
-	data := make(map[string]bool)
-	for _, day := range hd.Days {
-		data[day.Date] = day.IsOffDay
-	}
-	return data
-}
-
-// isTradeDate 判断指定日期是否为交易日
-// A股交易日 = 非周末 且 非节假日（调休上班也不算交易日）
-func (ms *MarketService) isTradeDate(date time.Time) bool {
-	isTradeDay, _ := ms.isTradeDay(date)
-	return isTradeDay
-}
-
 // getTradeDatesCacheFile 获取交易日缓存文件路径
 func getTradeDatesCacheFile() string {
 	return filepath.Join(paths.EnsureCacheDir(""), "trade_dates.json")
@@ -940,22 +717,12 @@ func (ms *MarketService) fetchTradeDates(days int) ([]string, error) {
 	var tradeDates []string
 	today := time.Now()
 
-	// 预加载需要的年份节假日数据
-	yearsNeeded := make(map[int]bool)
-	for i := 0; i < days; i++ {
-		yearsNeeded[today.AddDate(0, 0, -i).Year()] = true
-	}
-	for year := range yearsNeeded {
-		if _, err := ms.loadHolidayData(year); err != nil {
-			log.Warn("加载 %d 年节假日数据失败: %v", year, err)
-		}
-	}
-
+	calendar := GetTradingCalendar()
 	for i := 0; i < days; i++ {
 		date := today.AddDate(0, 0, -i)
 		dateStr := date.Format("2006-01-02")
 
-		if ms.isTradeDate(date) {
+		if ok, _ := calendar.IsTradeDay(date); ok {
 			tradeDates = append(tradeDates, dateStr)
 		}
 	}
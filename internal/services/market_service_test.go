@@ -2,10 +2,13 @@ package services
 
 import (
 	"testing"
+
+	"github.com/run-bigpig/jcp/internal/pkg/fixture"
 )
 
 // TestGetStockRealTimeData 测试获取实时股票数据
 func TestGetStockRealTimeData(t *testing.T) {
+	fixture.Install(t, "testdata/fixtures")
 	ms := NewMarketService()
 
 	// 测试上海股票 (贵州茅台)
@@ -28,8 +31,11 @@ func TestGetStockRealTimeData(t *testing.T) {
 		if stock.Name == "" {
 			t.Error("股票名称为空")
 		}
-		if stock.Price <= 0 {
-			t.Logf("警告: 股票价格为0，可能是非交易时间")
+		if stock.Price != 1495.20 || stock.PreClose != 1480.50 {
+			t.Errorf("价格 = %.2f, 昨收 = %.2f，与录制数据不符", stock.Price, stock.PreClose)
+		}
+		if stock.PE <= 0 || stock.TotalShares <= 0 {
+			t.Errorf("估值数据缺失: PE=%.2f, 总股本=%d", stock.PE, stock.TotalShares)
 		}
 	})
 
@@ -70,6 +76,7 @@ func TestGetStockRealTimeData(t *testing.T) {
 
 // TestGetStockDataWithOrderBook 测试获取股票数据含盘口
 func TestGetStockDataWithOrderBook(t *testing.T) {
+	fixture.Install(t, "testdata/fixtures")
 	ms := NewMarketService()
 
 	t.Run("获取盘口数据", func(t *testing.T) {
@@ -108,10 +115,11 @@ func TestGetStockDataWithOrderBook(t *testing.T) {
 
 // TestGetKLineData 测试获取K线数据
 func TestGetKLineData(t *testing.T) {
+	fixture.Install(t, "testdata/fixtures")
 	ms := NewMarketService()
 
 	t.Run("日K线", func(t *testing.T) {
-		data, err := ms.GetKLineData("sh600519", "1d", 10)
+		data, err := ms.GetKLineData("sh600519", "1d", 10, AdjustNone)
 		if err != nil {
 			t.Fatalf("获取K线数据失败: %v", err)
 		}
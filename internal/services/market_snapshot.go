@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// snapshotDailyBars 快照使用的日线数量，约一年的交易日
+const snapshotDailyBars = 250
+
+// GetStockSnapshot 获取个股快照：实时行情必须成功，日线区间数据获取失败时留空
+func (ms *MarketService) GetStockSnapshot(code string) (*models.StockSnapshot, error) {
+	var (
+		wg       sync.WaitGroup
+		daily    []models.KLineData
+		dailyErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		daily, dailyErr = ms.GetKLineData(code, "1d", snapshotDailyBars, AdjustQFQ)
+	}()
+
+	stocks, errs := ms.GetQuotes(code)
+	wg.Wait()
+	if len(stocks) == 0 {
+		if len(errs) > 0 {
+			return nil, errs[0]
+		}
+		return nil, fmt.Errorf("%s: %w", code, errNoQuote)
+	}
+
+	snap := &models.StockSnapshot{Stock: stocks[0]}
+	if snap.PreClose > 0 && snap.High > 0 {
+		snap.Amplitude = round2((snap.High - snap.Low) / snap.PreClose * 100)
+	}
+	if dailyErr != nil {
+		log.Debug("获取 %s 日线失败: %v", code, dailyErr)
+	} else {
+		fillSnapshotRanges(snap, daily)
+	}
+	return snap, nil
+}
+
+// fillSnapshotRanges 根据日线计算近5日与52周区间，当日盘中价格也计入区间
+func fillSnapshotRanges(snap *models.StockSnapshot, daily []models.KLineData) {
+	if len(daily) == 0 {
+		return
+	}
+	recent := daily[max(len(daily)-5, 0):]
+	snap.High5D, snap.Low5D = priceRange(recent, snap.High, snap.Low)
+	snap.High52W, snap.Low52W = priceRange(daily, snap.High, snap.Low)
+	if len(daily) > 5 && daily[len(daily)-6].Close > 0 && snap.Price > 0 {
+		base := daily[len(daily)-6].Close
+		snap.Change5D = round2((snap.Price - base) / base * 100)
+	}
+}
+
+// priceRange K线区间最高、最低价，high/low 为区间外需要一并计入的价格（0 表示无）
+func priceRange(bars []models.KLineData, high, low float64) (float64, float64) {
+	for _, k := range bars {
+		high = max(high, k.High)
+		if low <= 0 || (k.Low > 0 && k.Low < low) {
+			low = k.Low
+		}
+	}
+	return high, low
+}
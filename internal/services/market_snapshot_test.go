@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestFillSnapshotRanges(t *testing.T) {
+	daily := make([]models.KLineData, 10)
+	for i := range daily {
+		daily[i] = models.KLineData{High: 10 + float64(i), Low: 9 + float64(i), Close: 9.5 + float64(i)}
+	}
+	daily[0].Low = 5
+
+	snap := &models.StockSnapshot{Stock: models.Stock{Price: 20, High: 20.5, Low: 18}}
+	fillSnapshotRanges(snap, daily)
+	if snap.High5D != 20.5 || snap.Low5D != 14 {
+		t.Errorf("5d range = %.2f ~ %.2f", snap.Low5D, snap.High5D)
+	}
+	if snap.High52W != 20.5 || snap.Low52W != 5 {
+		t.Errorf("52w range = %.2f ~ %.2f", snap.Low52W, snap.High52W)
+	}
+	// 基准为 5 根之前的收盘价 13.5
+	if snap.Change5D != 48.15 {
+		t.Errorf("change5d = %.2f", snap.Change5D)
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// sinaTickURL 新浪逐笔成交接口（按时间倒序）
+const sinaTickURL = "https://vip.stock.finance.sina.com.cn/quotes_service/view/CN_TransListV2.php?num=%d&symbol=%s&rn=%d"
+
+// 逐笔成交参数
+const (
+	defaultTickLimit = 50
+	maxTickLimit     = 200
+)
+
+// 形如 trade_item_list[0] = new Array('14:56:57', '1100', '1688.010', 'UP');
+var sinaTickRegex = regexp.MustCompile(`new Array\('([^']*)',\s*'([^']*)',\s*'([^']*)',\s*'([^']*)'\)`)
+
+// 新浪成交方向 → 主动方向
+var tickDirections = map[string]string{
+	"UP":    "buy",
+	"DOWN":  "sell",
+	"EQUAL": "neutral",
+}
+
+// GetTickData 获取最近的逐笔成交（按时间正序返回）
+func (ms *MarketService) GetTickData(code string, limit int) ([]models.TickTrade, error) {
+	if limit <= 0 {
+		limit = defaultTickLimit
+	}
+	limit = min(limit, maxTickLimit)
+
+	url := fmt.Sprintf(sinaTickURL, limit, code, time.Now().UnixNano())
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Referer", "http://finance.sina.com.cn")
+
+	resp, err := ms.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseSinaTicks(string(body)), nil
+}
+
+// parseSinaTicks 解析新浪逐笔成交数据，接口按时间倒序返回，这里翻转为正序
+func parseSinaTicks(data string) []models.TickTrade {
+	matches := sinaTickRegex.FindAllStringSubmatch(data, -1)
+	ticks := make([]models.TickTrade, 0, len(matches))
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		volume, _ := strconv.ParseInt(m[2], 10, 64)
+		price, _ := strconv.ParseFloat(m[3], 64)
+		direction, ok := tickDirections[m[4]]
+		if !ok {
+			direction = "neutral"
+		}
+		ticks = append(ticks, models.TickTrade{
+			Time:      m[1],
+			Price:     price,
+			Volume:    volume,
+			Direction: direction,
+		})
+	}
+	return ticks
+}
+
+// newTicksSince 返回 last 之后的新成交；last 不在当前窗口内时返回全部
+func newTicksSince(ticks []models.TickTrade, last models.TickTrade) []models.TickTrade {
+	for i := len(ticks) - 1; i >= 0; i-- {
+		if ticks[i] == last {
+			return ticks[i+1:]
+		}
+	}
+	return ticks
+}
@@ -0,0 +1,32 @@
+package services
+
+import "testing"
+
+// TestParseSinaTicks 测试逐笔成交解析与增量截取
+func TestParseSinaTicks(t *testing.T) {
+	data := `var trade_item_list = new Array();
+ trade_item_list[0] = new Array('14:57:00', '300', '10.02', 'UP');
+ trade_item_list[1] = new Array('14:56:57', '1100', '10.01', 'DOWN');
+ trade_item_list[2] = new Array('14:56:54', '500', '10.01', 'EQUAL');`
+
+	ticks := parseSinaTicks(data)
+	if len(ticks) != 3 {
+		t.Fatalf("want 3 ticks, got %d", len(ticks))
+	}
+	if ticks[0].Time != "14:56:54" || ticks[2].Time != "14:57:00" {
+		t.Errorf("ticks should be in ascending time order: %+v", ticks)
+	}
+	if ticks[1].Direction != "sell" || ticks[2].Direction != "buy" || ticks[0].Direction != "neutral" {
+		t.Errorf("unexpected directions: %+v", ticks)
+	}
+	if ticks[1].Volume != 1100 || ticks[1].Price != 10.01 {
+		t.Errorf("unexpected tick: %+v", ticks[1])
+	}
+
+	if got := newTicksSince(ticks, ticks[1]); len(got) != 1 || got[0].Time != "14:57:00" {
+		t.Errorf("newTicksSince = %+v", got)
+	}
+	if got := newTicksSince(ticks, ticks[2]); len(got) != 0 {
+		t.Errorf("no new ticks expected, got %+v", got)
+	}
+}
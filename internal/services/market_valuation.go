@@ -0,0 +1,164 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// emValuationURL 东方财富批量行情：f8 换手率、f9 市盈率(动)、f23 市净率、f38 总股本、f39 流通股本
+const emValuationURL = "https://push2.eastmoney.com/api/qt/ulist.np/get?fltt=2&invt=2&secids=%s&fields=f8,f9,f12,f13,f23,f38,f39"
+
+// valuationCacheTTL 估值与股本数据变化慢，缓存一分钟；换手率按实时成交量重新计算
+const valuationCacheTTL = time.Minute
+
+// stockValuation 缓存的估值与股本数据
+type stockValuation struct {
+	turnoverRate float64
+	pe           float64
+	pb           float64
+	floatShares  int64
+	totalShares  int64
+	timestamp    time.Time
+}
+
+type emValuationItem struct {
+	Turnover    emNumber `json:"f8"`
+	PE          emNumber `json:"f9"`
+	Code        string   `json:"f12"`
+	Market      emNumber `json:"f13"`
+	PB          emNumber `json:"f23"`
+	TotalShares emNumber `json:"f38"`
+	FloatShares emNumber `json:"f39"`
+}
+
+// fillValuations 为行情补充换手率、估值与股本，缓存过期的代码按批重新获取；获取失败时保留为 0
+func (ms *MarketService) fillValuations(stocks []models.Stock) {
+	if len(stocks) == 0 {
+		return
+	}
+
+	now := time.Now()
+	var stale []string
+	ms.valuationMu.Lock()
+	for _, s := range stocks {
+		if v, ok := ms.valuations[s.Symbol]; !ok || now.Sub(v.timestamp) > valuationCacheTTL {
+			stale = append(stale, s.Symbol)
+		}
+	}
+	ms.valuationMu.Unlock()
+
+	if len(stale) > 0 {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, quoteBatchConcurrency)
+		for start := 0; start < len(stale); start += quoteBatchSize {
+			batch := stale[start:min(start+quoteBatchSize, len(stale))]
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() { <-sem; wg.Done() }()
+				got, err := ms.fetchValuations(batch)
+				if err != nil {
+					log.Debug("获取估值数据失败: %v", err)
+					return
+				}
+				ms.valuationMu.Lock()
+				for code, v := range got {
+					v.timestamp = now
+					ms.valuations[code] = v
+				}
+				ms.valuationMu.Unlock()
+			}()
+		}
+		wg.Wait()
+	}
+
+	ms.valuationMu.Lock()
+	defer ms.valuationMu.Unlock()
+	for i := range stocks {
+		v, ok := ms.valuations[stocks[i].Symbol]
+		if !ok {
+			continue
+		}
+		applyValuation(&stocks[i], v)
+	}
+}
+
+// applyValuation 写入估值字段，有流通股本时用实时成交量计算换手率
+func applyValuation(stock *models.Stock, v stockValuation) {
+	stock.PE = v.pe
+	stock.PB = v.pb
+	stock.FloatShares = v.floatShares
+	stock.TotalShares = v.totalShares
+	stock.TurnoverRate = v.turnoverRate
+	if v.floatShares > 0 && stock.Volume > 0 {
+		stock.TurnoverRate = round2(float64(stock.Volume) / float64(v.floatShares) * 100)
+	}
+}
+
+// fetchValuations 获取一批股票的估值与股本，返回带前缀代码 -> 数据
+func (ms *MarketService) fetchValuations(codes []string) (map[string]stockValuation, error) {
+	secids := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if secid, ok := emSecID(code); ok {
+			secids = append(secids, secid)
+		}
+	}
+	if len(secids) == 0 {
+		return nil, nil
+	}
+
+	body, err := ms.getEastMoney(fmt.Sprintf(emValuationURL, strings.Join(secids, ",")))
+	if err != nil {
+		return nil, err
+	}
+	return parseValuations(body)
+}
+
+// parseValuations 解析批量估值数据
+func parseValuations(body []byte) (map[string]stockValuation, error) {
+	var resp struct {
+		Data *struct {
+			Diff []emValuationItem `json:"diff"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析估值数据失败: %w", err)
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+
+	result := make(map[string]stockValuation, len(resp.Data.Diff))
+	for _, d := range resp.Data.Diff {
+		if d.Code == "" {
+			continue
+		}
+		result[emSymbol(d.Code, int(d.Market))] = stockValuation{
+			turnoverRate: float64(d.Turnover),
+			pe:           float64(d.PE),
+			pb:           float64(d.PB),
+			floatShares:  int64(d.FloatShares),
+			totalShares:  int64(d.TotalShares),
+		}
+	}
+	return result, nil
+}
+
+// emSecID 带前缀的代码转换为东方财富 secid（沪市 1.，深市与北交所 0.）
+func emSecID(code string) (string, bool) {
+	if len(code) < 3 {
+		return "", false
+	}
+	switch strings.ToLower(code[:2]) {
+	case "sh":
+		return "1." + code[2:], true
+	case "sz", "bj":
+		return "0." + code[2:], true
+	}
+	return "", false
+}
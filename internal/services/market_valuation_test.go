@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestParseValuations(t *testing.T) {
+	body := []byte(`{"data":{"diff":[
+		{"f8":1.2,"f9":5.6,"f12":"600000","f13":1,"f23":0.45,"f38":29352000000,"f39":29352000000},
+		{"f8":"-","f9":-12.3,"f12":"000001","f13":0,"f23":"-","f38":0,"f39":0}
+	]}}`)
+	got, err := parseValuations(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sh := got["sh600000"]
+	if sh.pe != 5.6 || sh.pb != 0.45 || sh.floatShares != 29352000000 {
+		t.Errorf("sh600000 = %+v", sh)
+	}
+
+	// 有流通股本时换手率按实时成交量计算，否则沿用接口值
+	stock := models.Stock{Volume: 293520000}
+	applyValuation(&stock, sh)
+	if stock.TurnoverRate != 1 || stock.TotalShares != 29352000000 {
+		t.Errorf("applyValuation = %+v", stock)
+	}
+	stock = models.Stock{Volume: 100}
+	applyValuation(&stock, got["sz000001"])
+	if stock.TurnoverRate != 0 || stock.PE != -12.3 {
+		t.Errorf("loss-making stock = %+v", stock)
+	}
+}
+
+func TestEmSecID(t *testing.T) {
+	cases := map[string]string{"sh600000": "1.600000", "sz000001": "0.000001", "bj830799": "0.830799", "hk00700": ""}
+	for code, want := range cases {
+		if got, _ := emSecID(code); got != want {
+			t.Errorf("emSecID(%s) = %q, want %q", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+var templateLog = logger.New("template")
+
+// 会议模板的开会方式
+const (
+	TemplateModeSmart  = "smart"  // 小韭菜召开会议，模板中的专家必定参会
+	TemplateModeDirect = "direct" // 模板中的专家各自独立作答
+)
+
+// MeetingTemplate 会议模板：固定的专家阵容、开会方式与带占位符的问题
+// 问题支持 {stock}（股票名称）、{code}（股票代码）与 {date}（当天日期）
+type MeetingTemplate struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	AgentIDs  []string `json:"agentIds"`
+	Mode      string   `json:"mode"`
+	Query     string   `json:"query"`
+	CreatedAt int64    `json:"createdAt"`
+	UpdatedAt int64    `json:"updatedAt"`
+}
+
+// RenderQuery 用股票信息替换问题中的占位符
+func (t MeetingTemplate) RenderQuery(stockName, stockCode string, now time.Time) string {
+	if stockName == "" {
+		stockName = stockCode
+	}
+	return strings.NewReplacer(
+		"{stock}", stockName,
+		"{code}", stockCode,
+		"{date}", now.Format("2006-01-02"),
+	).Replace(t.Query)
+}
+
+// validate 校验模板
+func (t MeetingTemplate) validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("模板名称不能为空")
+	}
+	if strings.TrimSpace(t.Query) == "" {
+		return fmt.Errorf("模板问题不能为空")
+	}
+	switch t.Mode {
+	case TemplateModeSmart:
+	case TemplateModeDirect:
+		if len(t.AgentIDs) == 0 {
+			return fmt.Errorf("独立作答模式至少需要一位专家")
+		}
+	default:
+		return fmt.Errorf("无效的开会方式: %s", t.Mode)
+	}
+	return nil
+}
+
+// MeetingTemplateService 会议模板服务
+type MeetingTemplateService struct {
+	path      string
+	templates []MeetingTemplate
+	mu        sync.RWMutex
+}
+
+// NewMeetingTemplateService 创建会议模板服务
+func NewMeetingTemplateService(dataDir string) *MeetingTemplateService {
+	s := &MeetingTemplateService{path: filepath.Join(dataDir, "meeting_templates.json")}
+	if data, err := os.ReadFile(s.path); err == nil {
+		if err := json.Unmarshal(data, &s.templates); err != nil {
+			templateLog.Error("解析会议模板失败: %v", err)
+		}
+	}
+	return s
+}
+
+// saveLocked 保存模板(需要已持有锁)
+func (s *MeetingTemplateService) saveLocked() error {
+	data, err := json.MarshalIndent(s.templates, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// GetTemplates 获取全部模板
+func (s *MeetingTemplateService) GetTemplates() []MeetingTemplate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]MeetingTemplate, len(s.templates))
+	copy(result, s.templates)
+	return result
+}
+
+// GetTemplate 按 ID 获取模板
+func (s *MeetingTemplateService) GetTemplate(id string) (MeetingTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, t := range s.templates {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return MeetingTemplate{}, fmt.Errorf("会议模板不存在: %s", id)
+}
+
+// AddTemplate 新增模板，返回带 ID 的模板
+func (s *MeetingTemplateService) AddTemplate(t MeetingTemplate) (MeetingTemplate, error) {
+	if err := t.validate(); err != nil {
+		return MeetingTemplate{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.ID = uuid.New().String()
+	t.CreatedAt = time.Now().UnixMilli()
+	t.UpdatedAt = t.CreatedAt
+	s.templates = append(s.templates, t)
+	return t, s.saveLocked()
+}
+
+// UpdateTemplate 更新模板
+func (s *MeetingTemplateService) UpdateTemplate(t MeetingTemplate) error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.templates {
+		if s.templates[i].ID == t.ID {
+			t.CreatedAt = s.templates[i].CreatedAt
+			t.UpdatedAt = time.Now().UnixMilli()
+			s.templates[i] = t
+			return s.saveLocked()
+		}
+	}
+	return fmt.Errorf("会议模板不存在: %s", t.ID)
+}
+
+// DeleteTemplate 删除模板
+func (s *MeetingTemplateService) DeleteTemplate(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.templates {
+		if s.templates[i].ID == id {
+			s.templates = append(s.templates[:i], s.templates[i+1:]...)
+			return s.saveLocked()
+		}
+	}
+	return fmt.Errorf("会议模板不存在: %s", id)
+}
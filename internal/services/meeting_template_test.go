@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMeetingTemplate 测试会议模板的增删改查与占位符替换
+func TestMeetingTemplate(t *testing.T) {
+	dir := t.TempDir()
+	s := NewMeetingTemplateService(dir)
+
+	if _, err := s.AddTemplate(MeetingTemplate{Name: "前瞻", Mode: TemplateModeDirect, Query: "对 {stock} 做财报季前瞻"}); err == nil {
+		t.Error("direct template without agents should be rejected")
+	}
+	if _, err := s.AddTemplate(MeetingTemplate{Name: "前瞻", Mode: "vote", Query: "q"}); err == nil {
+		t.Error("invalid mode should be rejected")
+	}
+
+	tpl, err := s.AddTemplate(MeetingTemplate{
+		Name:     "财报季前瞻",
+		AgentIDs: []string{"fundamental", "risk"},
+		Mode:     TemplateModeSmart,
+		Query:    "对 {stock}({code}) 做财报季前瞻，截至 {date}",
+	})
+	if err != nil || tpl.ID == "" || tpl.CreatedAt == 0 {
+		t.Fatalf("add: %+v %v", tpl, err)
+	}
+	s.AddTemplate(MeetingTemplate{Name: "盘后复盘", Mode: TemplateModeSmart, Query: "复盘 {stock}"})
+
+	tpl.Name = "财报前瞻"
+	if err := s.UpdateTemplate(tpl); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := s.UpdateTemplate(MeetingTemplate{ID: "missing", Name: "x", Mode: TemplateModeSmart, Query: "q"}); err == nil {
+		t.Error("unknown template should be rejected")
+	}
+
+	// 重新加载后模板保留
+	s = NewMeetingTemplateService(dir)
+	got, err := s.GetTemplate(tpl.ID)
+	if err != nil || got.Name != "财报前瞻" || got.CreatedAt != tpl.CreatedAt || len(got.AgentIDs) != 2 {
+		t.Fatalf("get: %+v %v", got, err)
+	}
+
+	now := time.Date(2026, 4, 1, 10, 0, 0, 0, time.Local)
+	if q := got.RenderQuery("贵州茅台", "sh600519", now); q != "对 贵州茅台(sh600519) 做财报季前瞻，截至 2026-04-01" {
+		t.Errorf("render: %s", q)
+	}
+	if q := got.RenderQuery("", "sh600519", now); q != "对 sh600519(sh600519) 做财报季前瞻，截至 2026-04-01" {
+		t.Errorf("render without name: %s", q)
+	}
+
+	if err := s.DeleteTemplate(tpl.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if list := s.GetTemplates(); len(list) != 1 || list[0].Name != "盘后复盘" {
+		t.Errorf("list after delete: %+v", list)
+	}
+}
@@ -2,9 +2,12 @@ package services
 
 import (
 	"testing"
+
+	"github.com/run-bigpig/jcp/internal/pkg/fixture"
 )
 
 func TestGetTelegraphList(t *testing.T) {
+	fixture.Install(t, "testdata/fixtures")
 	service := NewNewsService()
 
 	telegraphs, err := service.GetTelegraphList()
@@ -38,11 +41,12 @@ func TestGetTelegraphList(t *testing.T) {
 		}
 	}
 	if !hasURL {
-		t.Log("警告: 没有解析到任何快讯 URL")
+		t.Error("没有解析到任何快讯 URL")
 	}
 }
 
 func TestGetLatestTelegraph(t *testing.T) {
+	fixture.Install(t, "testdata/fixtures")
 	service := NewNewsService()
 
 	// 先获取列表填充缓存
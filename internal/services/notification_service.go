@@ -0,0 +1,172 @@
+package services
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+var notifyLog = logger.New("notify")
+
+// 通知类别
+const (
+	NotifyAlert    = "alert"    // 交易计划触发、待确认委托等需要关注的提醒
+	NotifyMeeting  = "meeting"  // 会议结束
+	NotifyBriefing = "briefing" // 盘前早报生成完成
+	NotifyMCP      = "mcp"      // MCP 服务器断开
+)
+
+// notifyDedupWindow 相同 Key 的通知在该时间内只发送一次
+const notifyDedupWindow = 10 * time.Minute
+
+// Notification 通知内容
+type Notification struct {
+	Category  string `json:"category"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	Detail    string `json:"-"`             // 完整内容，仅用于外部推送渠道
+	Key       string `json:"key,omitempty"` // 去重键，为空时使用类别+标题
+	Native    bool   `json:"native"`        // 是否已发送系统通知
+	Timestamp int64  `json:"timestamp"`
+}
+
+// NotificationService 桌面通知：按类别开关、免打扰时段与去重过滤后发送系统通知，并同步推送应用内提示
+type NotificationService struct {
+	config func() models.NotificationConfig
+	emit   func(Notification)
+	send   func(title, body string) error // 系统通知发送方式（测试时替换）
+	push   func(Notification)             // 外部推送渠道（不受桌面开关与免打扰影响）
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// NewNotificationService 创建通知服务，emit 用于推送应用内提示（可为 nil）
+func NewNotificationService(config func() models.NotificationConfig, emit func(Notification)) *NotificationService {
+	return &NotificationService{
+		config: config,
+		emit:   emit,
+		send:   sendNativeNotification,
+		sent:   make(map[string]time.Time),
+	}
+}
+
+// SetPusher 设置外部推送渠道，去重后的通知都会转发
+func (s *NotificationService) SetPusher(push func(Notification)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.push = push
+}
+
+// Notify 发送通知，返回是否实际弹出了系统通知
+// 重复的通知直接丢弃；外部推送不受桌面设置影响；桌面类别被关闭时不提示，静音与免打扰时段只推送应用内提示
+func (s *NotificationService) Notify(n Notification) bool {
+	cfg := s.config()
+	now := time.Now()
+	key := n.Key
+	if key == "" {
+		key = n.Category + ":" + n.Title
+	}
+
+	s.mu.Lock()
+	if last, ok := s.sent[key]; ok && now.Sub(last) < notifyDedupWindow {
+		s.mu.Unlock()
+		return false
+	}
+	s.sent[key] = now
+	for k, t := range s.sent {
+		if now.Sub(t) >= notifyDedupWindow {
+			delete(s.sent, k)
+		}
+	}
+	push := s.push
+	s.mu.Unlock()
+
+	n.Timestamp = now.UnixMilli()
+	if push != nil {
+		push(n)
+	}
+	if slices.Contains(cfg.DisabledCategories, n.Category) {
+		return false
+	}
+	if !cfg.Muted && !inQuietHours(cfg.QuietStart, cfg.QuietEnd, now) {
+		if err := s.send(n.Title, n.Body); err != nil {
+			notifyLog.Warn("系统通知发送失败: %v", err)
+		} else {
+			n.Native = true
+		}
+	}
+	if s.emit != nil {
+		s.emit(n)
+	}
+	return n.Native
+}
+
+// inQuietHours 判断是否处于免打扰时段（支持跨零点，如 22:00-08:00）
+func inQuietHours(start, end string, now time.Time) bool {
+	from, ok1 := parseClock(start)
+	to, ok2 := parseClock(end)
+	if !ok1 || !ok2 || from == to {
+		return false
+	}
+	cur := now.Hour()*60 + now.Minute()
+	if from < to {
+		return cur >= from && cur < to
+	}
+	return cur >= from || cur < to
+}
+
+// parseClock 解析 HH:MM 为当天分钟数
+func parseClock(s string) (int, bool) {
+	var h, m int
+	if _, err := fmt.Sscanf(strings.TrimSpace(s), "%d:%d", &h, &m); err != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return 0, false
+	}
+	return h*60 + m, true
+}
+
+// sendNativeNotification 调用系统通知：macOS osascript、Linux notify-send、Windows 托盘气泡
+func sendNativeNotification(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux":
+		cmd = exec.Command("notify-send", "-a", "韭菜盘", title, body)
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms; `+
+			`$n = New-Object System.Windows.Forms.NotifyIcon; `+
+			`$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; `+
+			`$n.ShowBalloonTip(5000, %s, %s, 'Info'); Start-Sleep -Seconds 6; $n.Dispose()`,
+			powerShellString(title), powerShellString(body))
+		cmd = exec.Command("powershell.exe", "-NoProfile", "-Command", script)
+	default:
+		return fmt.Errorf("不支持的操作系统: %s", runtime.GOOS)
+	}
+	setSysProcAttr(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// appleScriptString 转义为 AppleScript 字符串字面量
+func appleScriptString(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s)
+	return `"` + s + `"`
+}
+
+// powerShellString 转义为 PowerShell 单引号字符串字面量
+func powerShellString(s string) string {
+	s = strings.NewReplacer(`'`, `''`, "\n", " ").Replace(s)
+	return `'` + s + `'`
+}
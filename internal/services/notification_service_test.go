@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestNotificationService(t *testing.T) {
+	cfg := models.NotificationConfig{DisabledCategories: []string{NotifyMCP}}
+	var native, emitted int
+	s := NewNotificationService(func() models.NotificationConfig { return cfg }, func(Notification) { emitted++ })
+	s.send = func(title, body string) error { native++; return nil }
+
+	if !s.Notify(Notification{Category: NotifyAlert, Title: "浦发银行", Body: "跌破止损价", Key: "sh600000:stop"}) {
+		t.Fatal("first alert should be sent")
+	}
+	// 相同 Key 去重
+	if s.Notify(Notification{Category: NotifyAlert, Title: "浦发银行", Body: "跌破止损价", Key: "sh600000:stop"}) {
+		t.Error("duplicate alert should be dropped")
+	}
+	// 关闭的类别不发送也不推送
+	s.Notify(Notification{Category: NotifyMCP, Title: "MCP 断开"})
+	if native != 1 || emitted != 1 {
+		t.Errorf("native=%d emitted=%d", native, emitted)
+	}
+
+	// 静音时只推送应用内提示
+	cfg.Muted = true
+	if s.Notify(Notification{Category: NotifyMeeting, Title: "会议结束"}) {
+		t.Error("muted should not send native notification")
+	}
+	if native != 1 || emitted != 2 {
+		t.Errorf("native=%d emitted=%d", native, emitted)
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 6, 3, h, m, 0, 0, time.Local) }
+	cases := []struct {
+		start, end string
+		now        time.Time
+		want       bool
+	}{
+		{"22:00", "08:00", at(23, 30), true},
+		{"22:00", "08:00", at(7, 59), true},
+		{"22:00", "08:00", at(8, 0), false},
+		{"12:00", "13:30", at(12, 45), true},
+		{"12:00", "13:30", at(14, 0), false},
+		{"", "08:00", at(3, 0), false},
+		{"25:00", "08:00", at(3, 0), false},
+	}
+	for _, c := range cases {
+		if got := inQuietHours(c.start, c.end, c.now); got != c.want {
+			t.Errorf("inQuietHours(%s, %s, %s) = %v, want %v", c.start, c.end, c.now.Format("15:04"), got, c.want)
+		}
+	}
+}
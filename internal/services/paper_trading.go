@@ -0,0 +1,534 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/vault"
+
+	"github.com/google/uuid"
+)
+
+var paperLog = logger.New("paper")
+
+// 委托方向与类型
+const (
+	PaperSideBuy     = "buy"
+	PaperSideSell    = "sell"
+	PaperOrderMarket = "market"
+	PaperOrderLimit  = "limit"
+)
+
+// 委托状态
+const (
+	PaperOrderPending   = "pending_approval" // 专家下单，等待用户确认
+	PaperOrderOpen      = "open"             // 已报，等待成交
+	PaperOrderFilled    = "filled"
+	PaperOrderCancelled = "cancelled"
+	PaperOrderRejected  = "rejected"
+)
+
+const (
+	paperDefaultCash    = 1000000
+	paperCommissionRate = 0.00025 // 佣金万2.5
+	paperMinCommission  = 5       // 最低佣金5元
+	paperStampTaxRate   = 0.0005  // 印花税（仅卖出）
+	paperLotSize        = 100     // 买入最小单位一手
+	paperMaxOrders      = 500     // 保留的委托记录数
+	paperMatchEvery     = 10 * time.Second
+)
+
+// PaperOrderRequest 模拟盘下单请求
+type PaperOrderRequest struct {
+	Symbol string  `json:"symbol"`
+	Side   string  `json:"side"`
+	Type   string  `json:"type"`
+	Price  float64 `json:"price"` // 限价单价格
+	Shares int64   `json:"shares"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+// validate 校验下单参数
+func (r PaperOrderRequest) validate() error {
+	if r.Symbol == "" {
+		return fmt.Errorf("股票代码不能为空")
+	}
+	if r.Side != PaperSideBuy && r.Side != PaperSideSell {
+		return fmt.Errorf("无效的委托方向: %s", r.Side)
+	}
+	if r.Type != PaperOrderMarket && r.Type != PaperOrderLimit {
+		return fmt.Errorf("无效的委托类型: %s", r.Type)
+	}
+	if r.Type == PaperOrderLimit && r.Price <= 0 {
+		return fmt.Errorf("限价单需指定价格")
+	}
+	if r.Shares <= 0 {
+		return fmt.Errorf("委托数量必须大于0")
+	}
+	if r.Side == PaperSideBuy && r.Shares%paperLotSize != 0 {
+		return fmt.Errorf("买入数量须为%d股的整数倍", paperLotSize)
+	}
+	return nil
+}
+
+// PaperTradingService 模拟盘：按实时行情撮合市价/限价单，计算佣金与印花税，记录每日权益
+type PaperTradingService struct {
+	path          string
+	marketService *MarketService
+	onPending     func(models.PaperOrder)
+
+	mu      sync.Mutex
+	account *models.PaperAccount
+	cancel  context.CancelFunc
+}
+
+// NewPaperTradingService 创建模拟盘服务
+func NewPaperTradingService(dataDir string, marketService *MarketService) *PaperTradingService {
+	s := &PaperTradingService{
+		path:          filepath.Join(dataDir, "paper_account.json"),
+		marketService: marketService,
+	}
+	s.load()
+	return s
+}
+
+// SetPendingHandler 设置专家委托待确认时的回调
+func (s *PaperTradingService) SetPendingHandler(fn func(models.PaperOrder)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onPending = fn
+}
+
+// load 加载账户，不存在时按默认资金开户
+func (s *PaperTradingService) load() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := vault.ReadFile(s.path)
+	if err == nil {
+		var account models.PaperAccount
+		if err = json.Unmarshal(data, &account); err == nil {
+			s.account = &account
+			return
+		}
+		paperLog.Error("解析模拟盘账户失败: %v", err)
+	}
+	s.account = newPaperAccount(paperDefaultCash)
+}
+
+// saveLocked 保存账户(需要已持有锁)
+func (s *PaperTradingService) saveLocked() error {
+	if n := len(s.account.Orders); n > paperMaxOrders {
+		s.account.Orders = slices.Clone(s.account.Orders[n-paperMaxOrders:])
+	}
+	data, err := json.MarshalIndent(s.account, "", "  ")
+	if err != nil {
+		return err
+	}
+	return vault.WriteFile(s.path, data)
+}
+
+// Reload 重新加载账户（数据解锁后调用）
+func (s *PaperTradingService) Reload() {
+	s.load()
+}
+
+func newPaperAccount(cash float64) *models.PaperAccount {
+	return &models.PaperAccount{
+		InitialCash: cash,
+		Cash:        cash,
+		Positions:   []models.PaperPosition{},
+		Orders:      []models.PaperOrder{},
+		EquityCurve: []models.PaperEquityPoint{},
+		Equity:      cash,
+		CreatedAt:   time.Now().UnixMilli(),
+	}
+}
+
+// GetAccount 获取账户快照，持仓按最新行情估值
+func (s *PaperTradingService) GetAccount() models.PaperAccount {
+	s.refreshPrices()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	account := *s.account
+	account.Positions = slices.Clone(s.account.Positions)
+	account.Orders = slices.Clone(s.account.Orders)
+	account.EquityCurve = slices.Clone(s.account.EquityCurve)
+	account.Equity = paperEquity(s.account)
+	return account
+}
+
+// Reset 清空账户并按指定资金重新开户
+func (s *PaperTradingService) Reset(initialCash float64) error {
+	if initialCash <= 0 {
+		initialCash = paperDefaultCash
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.account = newPaperAccount(initialCash)
+	return s.saveLocked()
+}
+
+// PlaceOrder 下单；needApproval 为 true 时委托进入待确认状态，由用户确认后才报单
+func (s *PaperTradingService) PlaceOrder(req PaperOrderRequest, source string, needApproval bool) (*models.PaperOrder, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	stocks, err := s.marketService.GetStockRealTimeData(req.Symbol)
+	if err != nil || len(stocks) == 0 {
+		return nil, fmt.Errorf("获取 %s 行情失败", req.Symbol)
+	}
+	quote := stocks[0]
+
+	s.mu.Lock()
+	if req.Side == PaperSideSell {
+		pos := findPaperPosition(s.account, req.Symbol)
+		if pos == nil || paperAvailableShares(*pos, time.Now()) < req.Shares {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("可卖数量不足（当日买入需T+1卖出）")
+		}
+	} else {
+		price := req.Price
+		if req.Type == PaperOrderMarket {
+			price = quote.Price
+		}
+		amount := price * float64(req.Shares)
+		if amount+paperFee(PaperSideBuy, amount) > s.account.Cash {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("可用资金不足")
+		}
+	}
+
+	order := models.PaperOrder{
+		ID:        uuid.NewString()[:8],
+		Symbol:    req.Symbol,
+		Name:      quote.Name,
+		Side:      req.Side,
+		Type:      req.Type,
+		Price:     req.Price,
+		Shares:    req.Shares,
+		Status:    PaperOrderOpen,
+		Source:    source,
+		Reason:    req.Reason,
+		CreatedAt: time.Now().UnixMilli(),
+	}
+	if order.Type == PaperOrderMarket {
+		order.Price = 0
+	}
+	if needApproval {
+		order.Status = PaperOrderPending
+	}
+	s.account.Orders = append(s.account.Orders, order)
+	err = s.saveLocked()
+	onPending := s.onPending
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	paperLog.Info("模拟委托 %s: %s %s %d股 (%s, %s)", order.ID, order.Side, order.Symbol, order.Shares, order.Type, order.Status)
+	if needApproval {
+		if onPending != nil {
+			onPending(order)
+		}
+		return &order, nil
+	}
+	return s.submit(order.ID, quote.Price)
+}
+
+// ApproveOrder 用户确认专家委托并报单
+func (s *PaperTradingService) ApproveOrder(id string) (*models.PaperOrder, error) {
+	s.mu.Lock()
+	order := findPaperOrder(s.account, id)
+	if order == nil || order.Status != PaperOrderPending {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("委托不存在或无需确认")
+	}
+	order.Status = PaperOrderOpen
+	symbol := order.Symbol
+	err := s.saveLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var price float64
+	if stocks, err := s.marketService.GetStockRealTimeData(symbol); err == nil && len(stocks) > 0 {
+		price = stocks[0].Price
+	}
+	return s.submit(id, price)
+}
+
+// RejectOrder 用户拒绝专家委托
+func (s *PaperTradingService) RejectOrder(id string) error {
+	return s.closeOrder(id, PaperOrderRejected, PaperOrderPending)
+}
+
+// CancelOrder 撤销未成交委托
+func (s *PaperTradingService) CancelOrder(id string) error {
+	return s.closeOrder(id, PaperOrderCancelled, PaperOrderPending, PaperOrderOpen)
+}
+
+func (s *PaperTradingService) closeOrder(id, status string, from ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := findPaperOrder(s.account, id)
+	if order == nil || !slices.Contains(from, order.Status) {
+		return fmt.Errorf("委托不存在或已结束")
+	}
+	order.Status = status
+	return s.saveLocked()
+}
+
+// submit 报单后立即按现价尝试撮合（仅交易时段），未成交的留待后台撮合
+func (s *PaperTradingService) submit(id string, price float64) (*models.PaperOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := findPaperOrder(s.account, id)
+	if order == nil {
+		return nil, fmt.Errorf("委托不存在")
+	}
+	if price > 0 && s.marketService.GetMarketStatus().Status == "trading" {
+		if fill, ok := paperMarketable(*order, price); ok {
+			fillPaperOrder(s.account, order, fill, time.Now())
+			if err := s.saveLocked(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	result := *order
+	return &result, nil
+}
+
+// Start 启动后台撮合
+func (s *PaperTradingService) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.mu.Unlock()
+		return
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.mu.Unlock()
+
+	go s.loop(ctx)
+}
+
+// Stop 停止后台撮合
+func (s *PaperTradingService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+func (s *PaperTradingService) loop(ctx context.Context) {
+	ticker := time.NewTicker(paperMatchEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.marketService.GetMarketStatus().Status == "trading" {
+				safeCall(s.match)
+			}
+		}
+	}
+}
+
+// match 按实时行情撮合未成交委托，并更新当日权益
+func (s *PaperTradingService) match() {
+	prices := s.refreshPrices()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for i := range s.account.Orders {
+		order := &s.account.Orders[i]
+		if order.Status != PaperOrderOpen {
+			continue
+		}
+		if fill, ok := paperMarketable(*order, prices[order.Symbol]); ok {
+			fillPaperOrder(s.account, order, fill, now)
+		}
+	}
+	recordPaperEquity(s.account, now)
+	if err := s.saveLocked(); err != nil {
+		paperLog.Error("保存模拟盘账户失败: %v", err)
+	}
+}
+
+// refreshPrices 拉取持仓与未成交委托的最新价，更新持仓估值
+func (s *PaperTradingService) refreshPrices() map[string]float64 {
+	s.mu.Lock()
+	var codes []string
+	for _, p := range s.account.Positions {
+		codes = append(codes, p.Symbol)
+	}
+	for _, o := range s.account.Orders {
+		if o.Status == PaperOrderOpen && !slices.Contains(codes, o.Symbol) {
+			codes = append(codes, o.Symbol)
+		}
+	}
+	s.mu.Unlock()
+	if len(codes) == 0 {
+		return nil
+	}
+
+	stocks, err := s.marketService.GetStockRealTimeData(codes...)
+	if err != nil {
+		return nil
+	}
+	prices := make(map[string]float64, len(stocks))
+	for _, st := range stocks {
+		if st.Price > 0 {
+			prices[st.Symbol] = st.Price
+		}
+	}
+
+	s.mu.Lock()
+	for i := range s.account.Positions {
+		if p, ok := prices[s.account.Positions[i].Symbol]; ok {
+			s.account.Positions[i].LastPrice = p
+		}
+	}
+	s.mu.Unlock()
+	return prices
+}
+
+// paperFee 计算交易费用：佣金（最低5元），卖出另收印花税
+func paperFee(side string, amount float64) float64 {
+	fee := max(amount*paperCommissionRate, paperMinCommission)
+	if side == PaperSideSell {
+		fee += amount * paperStampTaxRate
+	}
+	return math.Round(fee*100) / 100
+}
+
+// paperMarketable 判断委托能否按现价成交，返回成交价
+// 市价单按现价成交；限价买单现价不高于限价、限价卖单现价不低于限价时按现价成交
+func paperMarketable(order models.PaperOrder, price float64) (float64, bool) {
+	if price <= 0 {
+		return 0, false
+	}
+	switch {
+	case order.Type == PaperOrderMarket:
+		return price, true
+	case order.Side == PaperSideBuy && price <= order.Price:
+		return price, true
+	case order.Side == PaperSideSell && price >= order.Price:
+		return price, true
+	}
+	return 0, false
+}
+
+// paperAvailableShares 可卖数量（扣除当日买入）
+func paperAvailableShares(pos models.PaperPosition, now time.Time) int64 {
+	if pos.TradeDate == now.Format("2006-01-02") {
+		return pos.Shares - pos.TodayShares
+	}
+	return pos.Shares
+}
+
+// fillPaperOrder 按成交价成交委托并更新资金与持仓；资金或可卖数量不足时委托被拒绝
+func fillPaperOrder(account *models.PaperAccount, order *models.PaperOrder, price float64, now time.Time) {
+	amount := price * float64(order.Shares)
+	fee := paperFee(order.Side, amount)
+	today := now.Format("2006-01-02")
+	pos := findPaperPosition(account, order.Symbol)
+
+	if order.Side == PaperSideBuy {
+		if account.Cash < amount+fee {
+			order.Status, order.Reason = PaperOrderRejected, "可用资金不足"
+			return
+		}
+		account.Cash -= amount + fee
+		if pos == nil {
+			account.Positions = append(account.Positions, models.PaperPosition{Symbol: order.Symbol, Name: order.Name})
+			pos = &account.Positions[len(account.Positions)-1]
+		}
+		if pos.TradeDate != today {
+			pos.TodayShares = 0
+		}
+		pos.CostPrice = (pos.CostPrice*float64(pos.Shares) + amount + fee) / float64(pos.Shares+order.Shares)
+		pos.Shares += order.Shares
+		pos.TodayShares += order.Shares
+		pos.TradeDate = today
+	} else {
+		if pos == nil || paperAvailableShares(*pos, now) < order.Shares {
+			order.Status, order.Reason = PaperOrderRejected, "可卖数量不足"
+			return
+		}
+		account.Cash += amount - fee
+		pos.Shares -= order.Shares
+		if pos.Shares == 0 {
+			account.Positions = slices.DeleteFunc(account.Positions, func(p models.PaperPosition) bool {
+				return p.Symbol == order.Symbol
+			})
+			pos = nil
+		}
+	}
+	if pos != nil {
+		pos.LastPrice = price
+	}
+	account.Cash = math.Round(account.Cash*100) / 100
+
+	order.Status = PaperOrderFilled
+	order.FillPrice = price
+	order.Commission = fee
+	order.FilledAt = now.UnixMilli()
+}
+
+// paperEquity 账户权益 = 现金 + 持仓市值
+func paperEquity(account *models.PaperAccount) float64 {
+	equity := account.Cash
+	for _, p := range account.Positions {
+		price := p.LastPrice
+		if price <= 0 {
+			price = p.CostPrice
+		}
+		equity += price * float64(p.Shares)
+	}
+	return math.Round(equity*100) / 100
+}
+
+// recordPaperEquity 记录当日权益（同一天覆盖，收盘前最后一次即为当日收盘权益）
+func recordPaperEquity(account *models.PaperAccount, now time.Time) {
+	account.Equity = paperEquity(account)
+	point := models.PaperEquityPoint{Date: now.Format("2006-01-02"), Equity: account.Equity, Cash: account.Cash}
+	if n := len(account.EquityCurve); n > 0 && account.EquityCurve[n-1].Date == point.Date {
+		account.EquityCurve[n-1] = point
+		return
+	}
+	account.EquityCurve = append(account.EquityCurve, point)
+}
+
+func findPaperPosition(account *models.PaperAccount, symbol string) *models.PaperPosition {
+	for i := range account.Positions {
+		if account.Positions[i].Symbol == symbol {
+			return &account.Positions[i]
+		}
+	}
+	return nil
+}
+
+func findPaperOrder(account *models.PaperAccount, id string) *models.PaperOrder {
+	for i := range account.Orders {
+		if account.Orders[i].ID == id {
+			return &account.Orders[i]
+		}
+	}
+	return nil
+}
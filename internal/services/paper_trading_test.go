@@ -0,0 +1,110 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestPaperFee(t *testing.T) {
+	if fee := paperFee(PaperSideBuy, 10000); fee != 5 {
+		t.Errorf("min commission: %v", fee)
+	}
+	// 佣金 25 + 印花税 50
+	if fee := paperFee(PaperSideSell, 100000); fee != 75 {
+		t.Errorf("sell fee: %v", fee)
+	}
+}
+
+func TestPaperOrderRequestValidate(t *testing.T) {
+	valid := PaperOrderRequest{Symbol: "sh600000", Side: PaperSideBuy, Type: PaperOrderLimit, Price: 10, Shares: 200}
+	if err := valid.validate(); err != nil {
+		t.Errorf("valid request: %v", err)
+	}
+	// 卖出允许零股
+	if err := (PaperOrderRequest{Symbol: "sh600000", Side: PaperSideSell, Type: PaperOrderMarket, Shares: 50}).validate(); err != nil {
+		t.Errorf("odd lot sell: %v", err)
+	}
+	for _, r := range []PaperOrderRequest{
+		{Symbol: "sh600000", Side: PaperSideBuy, Type: PaperOrderMarket, Shares: 150},
+		{Symbol: "sh600000", Side: PaperSideBuy, Type: PaperOrderLimit, Shares: 100},
+		{Symbol: "sh600000", Side: "short", Type: PaperOrderMarket, Shares: 100},
+		{Side: PaperSideBuy, Type: PaperOrderMarket, Shares: 100},
+	} {
+		if err := r.validate(); err == nil {
+			t.Errorf("expected error for %+v", r)
+		}
+	}
+}
+
+func TestPaperMarketable(t *testing.T) {
+	buy := models.PaperOrder{Side: PaperSideBuy, Type: PaperOrderLimit, Price: 10}
+	if _, ok := paperMarketable(buy, 10.1); ok {
+		t.Error("buy limit above price should not fill")
+	}
+	if fill, ok := paperMarketable(buy, 9.9); !ok || fill != 9.9 {
+		t.Errorf("buy limit fill: %v %v", fill, ok)
+	}
+	sell := models.PaperOrder{Side: PaperSideSell, Type: PaperOrderLimit, Price: 10}
+	if _, ok := paperMarketable(sell, 9.9); ok {
+		t.Error("sell limit below price should not fill")
+	}
+	if _, ok := paperMarketable(models.PaperOrder{Type: PaperOrderMarket}, 0); ok {
+		t.Error("no quote should not fill")
+	}
+}
+
+func TestFillPaperOrder(t *testing.T) {
+	day1 := time.Date(2025, 6, 3, 10, 0, 0, 0, time.Local)
+	account := newPaperAccount(100000)
+
+	buy := models.PaperOrder{Symbol: "sh600000", Side: PaperSideBuy, Type: PaperOrderMarket, Shares: 1000, Status: PaperOrderOpen}
+	fillPaperOrder(account, &buy, 10, day1)
+	if buy.Status != PaperOrderFilled || buy.Commission != 5 || account.Cash != 89995 {
+		t.Fatalf("buy: %+v cash %v", buy, account.Cash)
+	}
+	pos := account.Positions[0]
+	if pos.Shares != 1000 || pos.CostPrice != 10.005 {
+		t.Errorf("position: %+v", pos)
+	}
+
+	// T+1：当日买入不可卖出
+	sell := models.PaperOrder{Symbol: "sh600000", Side: PaperSideSell, Type: PaperOrderMarket, Shares: 1000, Status: PaperOrderOpen}
+	fillPaperOrder(account, &sell, 10.5, day1)
+	if sell.Status != PaperOrderRejected {
+		t.Fatalf("same-day sell should be rejected: %+v", sell)
+	}
+
+	day2 := day1.AddDate(0, 0, 1)
+	sell = models.PaperOrder{Symbol: "sh600000", Side: PaperSideSell, Type: PaperOrderMarket, Shares: 1000, Status: PaperOrderOpen}
+	fillPaperOrder(account, &sell, 11, day2)
+	// 卖出 11000，佣金 5 + 印花税 5.5
+	if sell.Status != PaperOrderFilled || sell.Commission != 10.5 || account.Cash != 100984.5 || len(account.Positions) != 0 {
+		t.Fatalf("sell: %+v cash %v positions %+v", sell, account.Cash, account.Positions)
+	}
+
+	big := models.PaperOrder{Symbol: "sz000001", Side: PaperSideBuy, Type: PaperOrderMarket, Shares: 100000, Status: PaperOrderOpen}
+	fillPaperOrder(account, &big, 10, day2)
+	if big.Status != PaperOrderRejected || big.Reason == "" {
+		t.Errorf("insufficient cash should reject: %+v", big)
+	}
+}
+
+func TestRecordPaperEquity(t *testing.T) {
+	day := time.Date(2025, 6, 3, 10, 0, 0, 0, time.Local)
+	account := newPaperAccount(50000)
+	account.Positions = []models.PaperPosition{{Symbol: "sh600000", Shares: 1000, CostPrice: 10, LastPrice: 12}}
+	account.Cash = 40000
+
+	recordPaperEquity(account, day)
+	account.Positions[0].LastPrice = 11
+	recordPaperEquity(account, day.Add(time.Hour))
+	if len(account.EquityCurve) != 1 || account.EquityCurve[0].Equity != 51000 {
+		t.Errorf("same day should overwrite: %+v", account.EquityCurve)
+	}
+	recordPaperEquity(account, day.AddDate(0, 0, 1))
+	if len(account.EquityCurve) != 2 {
+		t.Errorf("next day should append: %+v", account.EquityCurve)
+	}
+}
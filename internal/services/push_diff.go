@@ -0,0 +1,59 @@
+package services
+
+import "github.com/run-bigpig/jcp/internal/models"
+
+// OrderBookLevelChange 盘口单档变更
+type OrderBookLevelChange struct {
+	Index int                  `json:"index"` // 档位下标（0 为买一/卖一）
+	Item  models.OrderBookItem `json:"item"`
+}
+
+// OrderBookDelta 盘口增量，仅包含发生变化的档位
+type OrderBookDelta struct {
+	Code    string                   `json:"code"`
+	Bids    []OrderBookLevelChange   `json:"bids"`
+	Asks    []OrderBookLevelChange   `json:"asks"`
+	Metrics *models.OrderBookMetrics `json:"metrics,omitempty"` // 变化后整份盘口的衍生指标
+}
+
+// stockSnapshotCache 股票行情快照缓存，用于只推送有变化的股票
+type stockSnapshotCache map[string]models.Stock
+
+// diff 返回与上次快照相比发生变化的股票，并更新快照
+func (c stockSnapshotCache) diff(stocks []models.Stock) []models.Stock {
+	changed := make([]models.Stock, 0, len(stocks))
+	for _, s := range stocks {
+		if last, ok := c[s.Symbol]; ok && last == s {
+			continue
+		}
+		c[s.Symbol] = s
+		changed = append(changed, s)
+	}
+	return changed
+}
+
+// diffOrderBook 对比两次盘口，档位数量变化时需要推送全量（full=true）
+func diffOrderBook(prev, cur models.OrderBook) (delta OrderBookDelta, full bool) {
+	if len(prev.Bids) != len(cur.Bids) || len(prev.Asks) != len(cur.Asks) {
+		return delta, true
+	}
+	delta.Bids = diffLevels(prev.Bids, cur.Bids)
+	delta.Asks = diffLevels(prev.Asks, cur.Asks)
+	return delta, false
+}
+
+// diffLevels 逐档对比，返回变化的档位
+func diffLevels(prev, cur []models.OrderBookItem) []OrderBookLevelChange {
+	var changes []OrderBookLevelChange
+	for i := range cur {
+		if prev[i] != cur[i] {
+			changes = append(changes, OrderBookLevelChange{Index: i, Item: cur[i]})
+		}
+	}
+	return changes
+}
+
+// empty 是否无任何变化
+func (d OrderBookDelta) empty() bool {
+	return len(d.Bids) == 0 && len(d.Asks) == 0
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// TestStockSnapshotDiff 测试只推送有变化的股票
+func TestStockSnapshotDiff(t *testing.T) {
+	cache := make(stockSnapshotCache)
+	stocks := []models.Stock{{Symbol: "sh600000", Price: 10}, {Symbol: "sz000001", Price: 12}}
+	if got := cache.diff(stocks); len(got) != 2 {
+		t.Fatalf("first push should be full, got %d", len(got))
+	}
+	if got := cache.diff(stocks); len(got) != 0 {
+		t.Errorf("unchanged stocks pushed: %+v", got)
+	}
+	stocks[1].Price = 12.1
+	if got := cache.diff(stocks); len(got) != 1 || got[0].Symbol != "sz000001" {
+		t.Errorf("want only sz000001, got %+v", got)
+	}
+}
+
+// TestDiffOrderBook 测试盘口逐档对比
+func TestDiffOrderBook(t *testing.T) {
+	levels := func(prices ...float64) []models.OrderBookItem {
+		items := make([]models.OrderBookItem, len(prices))
+		for i, p := range prices {
+			items[i] = models.OrderBookItem{Price: p, Size: 100}
+		}
+		return items
+	}
+	prev := models.OrderBook{Bids: levels(9.9, 9.8, 9.7), Asks: levels(10, 10.1, 10.2)}
+	cur := models.OrderBook{Bids: levels(9.9, 9.8, 9.7), Asks: levels(10, 10.1, 10.2)}
+	cur.Bids[2].Size = 300
+
+	delta, full := diffOrderBook(prev, cur)
+	if full {
+		t.Fatal("same depth should not require full push")
+	}
+	if len(delta.Asks) != 0 || len(delta.Bids) != 1 || delta.Bids[0].Index != 2 {
+		t.Errorf("unexpected delta: %+v", delta)
+	}
+
+	cur.Asks = cur.Asks[:2]
+	if _, full := diffOrderBook(prev, cur); !full {
+		t.Error("depth change should require full push")
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+const (
+	pushMaxAttempts = 3
+	pushRetryDelay  = 2 * time.Second // 首次重试间隔，之后翻倍
+	pushMaxRunes    = 3500            // 单条消息最大长度（各平台限制取最小）
+	pushMaxResponse = 64 << 10        // 读取响应体的最大字节数
+)
+
+// defaultPushTemplate 默认消息模板
+const defaultPushTemplate = "【{{.Title}}】\n{{.Content}}\n\n{{.Time}}"
+
+// PushMessage 推送模板可用字段
+type PushMessage struct {
+	Category string
+	Title    string
+	Content  string // 完整内容（会议总结、早报全文），无则为通知摘要
+	Time     string
+}
+
+// PushService 外部推送渠道（企业微信/钉钉/Telegram/Bark），失败自动重试
+type PushService struct {
+	channels func() []models.PushChannelConfig
+	client   func() *http.Client
+	delay    time.Duration
+}
+
+// NewPushService 创建推送服务，channels 返回当前配置的推送渠道
+func NewPushService(channels func() []models.PushChannelConfig) *PushService {
+	return &PushService{
+		channels: channels,
+		client:   func() *http.Client { return proxy.GetManager().GetClientWithTimeout(10 * time.Second) },
+		delay:    pushRetryDelay,
+	}
+}
+
+// Push 将通知异步推送到所有订阅了该类别的已启用渠道
+func (s *PushService) Push(n Notification) {
+	for _, ch := range s.channels() {
+		if !ch.Enabled || (len(ch.Categories) > 0 && !slices.Contains(ch.Categories, n.Category)) {
+			continue
+		}
+		go func(ch models.PushChannelConfig) {
+			if err := s.SendWithRetry(ch, n); err != nil {
+				notifyLog.Warn("推送到 %s 失败: %v", ch.Name, err)
+			}
+		}(ch)
+	}
+}
+
+// SendWithRetry 发送到单个渠道，失败按指数退避重试
+func (s *PushService) SendWithRetry(ch models.PushChannelConfig, n Notification) error {
+	text, err := renderPushText(ch.Template, n)
+	if err != nil {
+		return err
+	}
+	delay := s.delay
+	for attempt := 1; ; attempt++ {
+		err = s.send(ch, n.Title, text)
+		if err == nil || attempt >= pushMaxAttempts {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// renderPushText 按模板渲染消息文本
+func renderPushText(tmpl string, n Notification) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = defaultPushTemplate
+	}
+	t, err := template.New("push").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("推送模板解析失败: %w", err)
+	}
+	content := n.Detail
+	if content == "" {
+		content = n.Body
+	}
+	ts := time.Now()
+	if n.Timestamp > 0 {
+		ts = time.UnixMilli(n.Timestamp)
+	}
+	var buf bytes.Buffer
+	msg := PushMessage{Category: n.Category, Title: n.Title, Content: content, Time: ts.Format("2006-01-02 15:04")}
+	if err := t.Execute(&buf, msg); err != nil {
+		return "", fmt.Errorf("推送模板渲染失败: %w", err)
+	}
+	text := []rune(buf.String())
+	if len(text) > pushMaxRunes {
+		text = append(text[:pushMaxRunes], []rune("...")...)
+	}
+	return string(text), nil
+}
+
+// send 按渠道类型构造请求并发送
+func (s *PushService) send(ch models.PushChannelConfig, title, text string) error {
+	var (
+		endpoint = ch.WebhookURL
+		payload  any
+	)
+	switch ch.Type {
+	case models.PushChannelWeCom:
+		payload = map[string]any{"msgtype": "markdown", "markdown": map[string]string{"content": text}}
+	case models.PushChannelDingTalk:
+		if ch.Secret != "" {
+			endpoint = dingTalkSignedURL(endpoint, ch.Secret, time.Now())
+		}
+		payload = map[string]any{"msgtype": "markdown", "markdown": map[string]string{"title": title, "text": text}}
+	case models.PushChannelTelegram:
+		base := strings.TrimRight(ch.WebhookURL, "/")
+		if base == "" {
+			base = "https://api.telegram.org"
+		}
+		endpoint = base + "/bot" + ch.BotToken + "/sendMessage"
+		payload = map[string]string{"chat_id": ch.ChatID, "text": text}
+	case models.PushChannelBark:
+		payload = map[string]string{"title": title, "body": text, "group": "韭菜盘"}
+	default:
+		return fmt.Errorf("不支持的推送渠道: %s", ch.Type)
+	}
+	if endpoint == "" {
+		return fmt.Errorf("未配置推送地址")
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client().Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return redactEndpoint(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, pushMaxResponse))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, body)
+	}
+	return checkPushResponse(ch.Type, body)
+}
+
+// redactEndpoint 隐去请求错误中的推送地址路径与参数（含 Telegram Token、Webhook 密钥），避免写入日志
+func redactEndpoint(err error) error {
+	var ue *url.Error
+	if !errors.As(err, &ue) {
+		return err
+	}
+	redacted := "***"
+	if u, perr := url.Parse(ue.URL); perr == nil && u.Host != "" {
+		redacted = u.Scheme + "://" + u.Host + "/***"
+	}
+	return &url.Error{Op: ue.Op, URL: redacted, Err: ue.Err}
+}
+
+// checkPushResponse 检查各平台业务返回码
+func checkPushResponse(channelType string, body []byte) error {
+	var result struct {
+		ErrCode     int    `json:"errcode"`
+		ErrMsg      string `json:"errmsg"`
+		OK          *bool  `json:"ok"`
+		Description string `json:"description"`
+		Code        *int   `json:"code"`
+		Message     string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("解析推送响应失败: %w", err)
+	}
+	switch channelType {
+	case models.PushChannelTelegram:
+		if result.OK == nil || !*result.OK {
+			return fmt.Errorf("telegram: %s", result.Description)
+		}
+	case models.PushChannelBark:
+		if result.Code != nil && *result.Code != http.StatusOK {
+			return fmt.Errorf("bark: %s", result.Message)
+		}
+	default:
+		if result.ErrCode != 0 {
+			return fmt.Errorf("errcode %d: %s", result.ErrCode, result.ErrMsg)
+		}
+	}
+	return nil
+}
+
+// dingTalkSignedURL 钉钉加签：timestamp + "\n" + secret 做 HmacSHA256 后 Base64
+func dingTalkSignedURL(webhook, secret string, now time.Time) string {
+	ts := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "\n" + secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	sep := "?"
+	if strings.Contains(webhook, "?") {
+		sep = "&"
+	}
+	return webhook + sep + "timestamp=" + ts + "&sign=" + url.QueryEscape(sign)
+}
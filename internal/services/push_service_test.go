@@ -0,0 +1,118 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestPushChannels(t *testing.T) {
+	var failures atomic.Int32
+	failures.Store(1) // 首次请求失败，验证重试
+	var lastPath string
+	var lastBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		lastPath = r.URL.Path
+		lastBody = nil
+		json.NewDecoder(r.Body).Decode(&lastBody)
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/bot"):
+			w.Write([]byte(`{"ok":true}`))
+		case r.URL.Path == "/barkkey":
+			w.Write([]byte(`{"code":200,"message":"success"}`))
+		default:
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		}
+	}))
+	defer srv.Close()
+
+	s := &PushService{client: func() *http.Client { return srv.Client() }, delay: time.Millisecond}
+	n := Notification{Category: NotifyMeeting, Title: "会议结束：浦发银行", Body: "摘要", Detail: "完整总结", Timestamp: time.Date(2025, 6, 3, 15, 5, 0, 0, time.Local).UnixMilli()}
+
+	wecom := models.PushChannelConfig{Type: models.PushChannelWeCom, WebhookURL: srv.URL + "/wecom"}
+	if err := s.SendWithRetry(wecom, n); err != nil {
+		t.Fatalf("wecom with retry: %v", err)
+	}
+	md := lastBody["markdown"].(map[string]any)
+	if md["content"] != "【会议结束：浦发银行】\n完整总结\n\n2025-06-03 15:05" {
+		t.Errorf("unexpected wecom content: %q", md["content"])
+	}
+
+	tg := models.PushChannelConfig{Type: models.PushChannelTelegram, WebhookURL: srv.URL, BotToken: "123:abc", ChatID: "42", Template: "{{.Title}} | {{.Content}}"}
+	if err := s.SendWithRetry(tg, n); err != nil {
+		t.Fatal(err)
+	}
+	if lastPath != "/bot123:abc/sendMessage" || lastBody["chat_id"] != "42" || lastBody["text"] != "会议结束：浦发银行 | 完整总结" {
+		t.Errorf("unexpected telegram request: %s %v", lastPath, lastBody)
+	}
+
+	bark := models.PushChannelConfig{Type: models.PushChannelBark, WebhookURL: srv.URL + "/barkkey"}
+	if err := s.SendWithRetry(bark, n); err != nil || lastBody["title"] != n.Title {
+		t.Errorf("bark: %v %v", err, lastBody)
+	}
+
+	// 连续失败超过重试次数
+	failures.Store(pushMaxAttempts)
+	if err := s.SendWithRetry(wecom, n); err == nil {
+		t.Error("expected error after retries exhausted")
+	}
+
+	if err := s.SendWithRetry(models.PushChannelConfig{Type: models.PushChannelWeCom, WebhookURL: srv.URL, Template: "{{.Oops"}, n); err == nil {
+		t.Error("bad template should fail")
+	}
+}
+
+func TestPushErrorRedactsEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close() // 连接失败，触发 *url.Error
+
+	s := &PushService{client: func() *http.Client { return srv.Client() }, delay: time.Millisecond}
+	cases := []models.PushChannelConfig{
+		{Type: models.PushChannelTelegram, WebhookURL: srv.URL, BotToken: "123:secret-token", ChatID: "42"},
+		{Type: models.PushChannelWeCom, WebhookURL: srv.URL + "/cgi-bin/webhook/send?key=secret-token"},
+	}
+	for _, ch := range cases {
+		err := s.send(ch, "t", "x")
+		if err == nil {
+			t.Fatalf("%s: expected error", ch.Type)
+		}
+		if msg := err.Error(); strings.Contains(msg, "secret-token") || !strings.Contains(msg, srv.Listener.Addr().String()) {
+			t.Errorf("%s: endpoint not redacted: %s", ch.Type, msg)
+		}
+	}
+}
+
+func TestCheckPushResponse(t *testing.T) {
+	if err := checkPushResponse(models.PushChannelDingTalk, []byte(`{"errcode":310000,"errmsg":"sign not match"}`)); err == nil {
+		t.Error("dingtalk errcode should fail")
+	}
+	if err := checkPushResponse(models.PushChannelTelegram, []byte(`{"ok":false,"description":"chat not found"}`)); err == nil {
+		t.Error("telegram ok=false should fail")
+	}
+	if err := checkPushResponse(models.PushChannelBark, []byte(`{"code":400,"message":"bad key"}`)); err == nil {
+		t.Error("bark code 400 should fail")
+	}
+}
+
+func TestDingTalkSignedURL(t *testing.T) {
+	u := dingTalkSignedURL("https://oapi.dingtalk.com/robot/send?access_token=x", "SECabc", time.UnixMilli(1717380000000))
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := parsed.Query()
+	if q.Get("access_token") != "x" || q.Get("timestamp") != "1717380000000" || q.Get("sign") == "" {
+		t.Errorf("unexpected signed url: %s", u)
+	}
+}
@@ -5,14 +5,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+	"github.com/run-bigpig/jcp/internal/pkg/tokens"
 )
 
 const (
 	eastmoneyReportAPI = "https://reportapi.eastmoney.com/report/list"
+
+	reportCacheTTL        = 30 * time.Minute // 研报更新频率低，缓存半小时
+	reportBeginTime       = "2020-01-01"
+	reportIncrementalSize = 20 // 增量拉取时的每页数量
 )
 
 // ResearchReport 个股研报数据结构
@@ -46,15 +53,27 @@ type ResearchReportResponse struct {
 	TotalCount int              `json:"TotalCount"`
 }
 
+// reportCache 个股研报缓存（仅缓存第一页，即最新研报）
+type reportCache struct {
+	reports   []ResearchReport
+	total     int
+	totalPage int
+	etag      string
+	timestamp time.Time
+}
+
 // ResearchReportService 研报服务
 type ResearchReportService struct {
 	client *http.Client
+	cache  map[string]*reportCache
+	mu     sync.RWMutex
 }
 
 // NewResearchReportService 创建研报服务
 func NewResearchReportService() *ResearchReportService {
 	return &ResearchReportService{
 		client: proxy.GetManager().GetClientWithTimeout(15 * time.Second),
+		cache:  make(map[string]*reportCache),
 	}
 }
 
@@ -62,58 +81,212 @@ func NewResearchReportService() *ResearchReportService {
 // stockCode: 股票代码 (如 "000001"，支持带前缀如 "sz000001")
 // pageSize: 每页数量
 // pageNo: 页码
+// 第一页按股票缓存，过期后只增量拉取缓存中最新发布日期之后的研报
 func (s *ResearchReportService) GetResearchReports(stockCode string, pageSize, pageNo int) (*ResearchReportResponse, error) {
 	// 去除股票代码前缀
 	code := strings.TrimPrefix(stockCode, "sz")
 	code = strings.TrimPrefix(code, "sh")
 
+	if pageNo != 1 {
+		result, _, err := s.fetchReports(code, pageSize, pageNo, reportBeginTime, "")
+		return result, err
+	}
+
+	s.mu.RLock()
+	cached, ok := s.cache[code]
+	s.mu.RUnlock()
+
+	// 缓存条数不足本次请求时重新全量拉取
+	if !ok || (len(cached.reports) < pageSize && len(cached.reports) < cached.total) {
+		result, etag, err := s.fetchReports(code, pageSize, 1, reportBeginTime, "")
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.cache[code] = &reportCache{
+			reports:   result.Data,
+			total:     result.TotalCount,
+			totalPage: result.TotalPage,
+			etag:      etag,
+			timestamp: time.Now(),
+		}
+		s.mu.Unlock()
+		return result, nil
+	}
+
+	if time.Since(cached.timestamp) >= reportCacheTTL {
+		if err := s.refreshReports(code, cached); err != nil {
+			// 增量更新失败时继续使用旧缓存
+			if len(cached.reports) == 0 {
+				return nil, err
+			}
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cached = s.cache[code]
+	data := cached.reports
+	if len(data) > pageSize {
+		data = data[:pageSize]
+	}
+	return &ResearchReportResponse{
+		Data:       data,
+		TotalPage:  cached.totalPage,
+		TotalCount: cached.total,
+	}, nil
+}
+
+// refreshReports 增量更新缓存：带 ETag 条件请求，只拉取最新发布日期之后的研报并合并
+func (s *ResearchReportService) refreshReports(code string, cached *reportCache) error {
+	beginTime := reportBeginTime
+	if len(cached.reports) > 0 {
+		beginTime = reportDate(cached.reports[0].PublishDate)
+	}
+
+	result, etag, err := s.fetchReports(code, reportIncrementalSize, 1, beginTime, cached.etag)
+	if err != nil {
+		return err
+	}
+
+	next := *cached
+	next.timestamp = time.Now()
+	if etag != "" {
+		next.etag = etag
+	}
+	if result != nil {
+		var added int
+		next.reports, added = mergeReports(cached.reports, result.Data)
+		next.total += added
+	}
+
+	s.mu.Lock()
+	s.cache[code] = &next
+	s.mu.Unlock()
+	return nil
+}
+
+// fetchReports 请求东方财富研报列表，etag 非空时发送条件请求
+// 返回 nil 结果表示数据未变化 (304)
+func (s *ResearchReportService) fetchReports(code string, pageSize, pageNo int, beginTime, etag string) (*ResearchReportResponse, string, error) {
 	// 构建请求URL
-	url := fmt.Sprintf("%s?industryCode=*&pageSize=%d&industry=*&rating=*&ratingChange=*&beginTime=2020-01-01&endTime=%d-01-01&pageNo=%d&fields=&qType=0&orgCode=&code=%s&rcode=",
-		eastmoneyReportAPI, pageSize, time.Now().Year()+1, pageNo, code)
+	url := fmt.Sprintf("%s?industryCode=*&pageSize=%d&industry=*&rating=*&ratingChange=*&beginTime=%s&endTime=%d-01-01&pageNo=%d&fields=&qType=0&orgCode=&code=%s&rcode=",
+		eastmoneyReportAPI, pageSize, beginTime, time.Now().Year()+1, pageNo, code)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
+		return nil, "", fmt.Errorf("创建请求失败: %w", err)
 	}
 
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 	req.Header.Set("Referer", "https://data.eastmoney.com/")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("请求失败: %w", err)
+		return nil, "", fmt.Errorf("请求失败: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, etag, nil
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+		return nil, "", fmt.Errorf("读取响应失败: %w", err)
 	}
 
 	var result ResearchReportResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("解析响应失败: %w", err)
+		return nil, "", fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	return &result, resp.Header.Get("ETag"), nil
+}
+
+// mergeReports 将新拉取的研报合并到缓存，按 infoCode 去重、按发布日期倒序，返回新增条数
+func mergeReports(cached, fresh []ResearchReport) ([]ResearchReport, int) {
+	seen := make(map[string]bool, len(cached))
+	for _, r := range cached {
+		seen[reportKey(r)] = true
+	}
+
+	merged := make([]ResearchReport, 0, len(cached)+len(fresh))
+	added := 0
+	for _, r := range fresh {
+		key := reportKey(r)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, r)
+		added++
 	}
+	merged = append(merged, cached...)
 
-	return &result, nil
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].PublishDate > merged[j].PublishDate
+	})
+	return merged, added
+}
+
+// reportKey 研报去重键
+func reportKey(r ResearchReport) string {
+	if r.InfoCode != "" {
+		return r.InfoCode
+	}
+	return r.Title + "|" + r.OrgSName
+}
+
+// reportDate 截取发布日期 (YYYY-MM-DD)
+func reportDate(publishDate string) string {
+	if len(publishDate) < 10 {
+		return reportBeginTime
+	}
+	return publishDate[:10]
 }
 
 // FormatReportsToText 将研报数据格式化为文本
 func (s *ResearchReportService) FormatReportsToText(reports []ResearchReport) string {
+	return s.FormatReportsToTextWithBudget(reports, 0)
+}
+
+// FormatReportsToTextWithBudget 将研报数据格式化为文本，并按 token 预算截断
+// maxTokens <= 0 表示不限制；超出预算的研报只注明省略条数
+func (s *ResearchReportService) FormatReportsToTextWithBudget(reports []ResearchReport, maxTokens int) string {
 	if len(reports) == 0 {
 		return "暂无研报数据"
 	}
 
 	var sb strings.Builder
+	used := 0
 	for i, r := range reports {
-		sb.WriteString(fmt.Sprintf("%d. 【%s】%s\n", i+1, r.EmRatingName, r.Title))
-		sb.WriteString(fmt.Sprintf("   券商: %s | 研究员: %s\n", r.OrgSName, r.Researcher))
-		sb.WriteString(fmt.Sprintf("   发布日期: %s | 行业: %s\n", r.PublishDate, r.IndvInduName))
-		if r.PredictThisYearEps != "" || r.PredictThisYearPe != "" {
-			sb.WriteString(fmt.Sprintf("   预测EPS: %s | 预测PE: %s\n", r.PredictThisYearEps, r.PredictThisYearPe))
+		entry := formatReport(i+1, r)
+		cost := tokens.Estimate(entry)
+		// 至少保留一条
+		if maxTokens > 0 && i > 0 && used+cost > maxTokens {
+			sb.WriteString(fmt.Sprintf("（另有%d条研报因长度限制省略）\n", len(reports)-i))
+			break
 		}
-		sb.WriteString("\n")
+		sb.WriteString(entry)
+		used += cost
+	}
+	return sb.String()
+}
+
+// formatReport 格式化单条研报
+func formatReport(n int, r ResearchReport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d. 【%s】%s\n", n, r.EmRatingName, r.Title))
+	sb.WriteString(fmt.Sprintf("   券商: %s | 研究员: %s\n", r.OrgSName, r.Researcher))
+	sb.WriteString(fmt.Sprintf("   发布日期: %s | 行业: %s\n", r.PublishDate, r.IndvInduName))
+	if r.PredictThisYearEps != "" || r.PredictThisYearPe != "" {
+		sb.WriteString(fmt.Sprintf("   预测EPS: %s | 预测PE: %s\n", r.PredictThisYearEps, r.PredictThisYearPe))
 	}
+	sb.WriteString("\n")
 	return sb.String()
 }
 
@@ -2,10 +2,15 @@ package services
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/run-bigpig/jcp/internal/pkg/fixture"
+	"github.com/run-bigpig/jcp/internal/pkg/tokens"
 )
 
 func TestGetResearchReports(t *testing.T) {
+	fixture.Install(t, "testdata/fixtures")
 	service := NewResearchReportService()
 
 	// 测试获取平安银行(000001)的研报
@@ -23,6 +28,7 @@ func TestGetResearchReports(t *testing.T) {
 }
 
 func TestGetResearchReportsWithPrefix(t *testing.T) {
+	fixture.Install(t, "testdata/fixtures")
 	service := NewResearchReportService()
 
 	// 测试带前缀的股票代码
@@ -35,6 +41,7 @@ func TestGetResearchReportsWithPrefix(t *testing.T) {
 }
 
 func TestGetReportContent(t *testing.T) {
+	fixture.Install(t, "testdata/fixtures")
 	service := NewResearchReportService()
 
 	// 先获取研报列表，拿到 infoCode
@@ -60,3 +67,44 @@ func TestGetReportContent(t *testing.T) {
 	fmt.Printf("PDF链接: %s\n\n", content.PDFUrl)
 	fmt.Printf("研报正文:\n%s\n", content.Content)
 }
+
+func TestMergeReports(t *testing.T) {
+	cached := []ResearchReport{
+		{InfoCode: "AP2", Title: "b", PublishDate: "2024-06-10 00:00:00.000"},
+		{InfoCode: "AP1", Title: "a", PublishDate: "2024-06-01 00:00:00.000"},
+	}
+	fresh := []ResearchReport{
+		{InfoCode: "AP3", Title: "c", PublishDate: "2024-06-20 00:00:00.000"},
+		{InfoCode: "AP2", Title: "b", PublishDate: "2024-06-10 00:00:00.000"},
+	}
+	merged, added := mergeReports(cached, fresh)
+	if added != 1 || len(merged) != 3 {
+		t.Fatalf("added=%d len=%d, want 1 and 3", added, len(merged))
+	}
+	if merged[0].InfoCode != "AP3" || merged[2].InfoCode != "AP1" {
+		t.Errorf("reports should be sorted by publish date desc: %+v", merged)
+	}
+	if d := reportDate(merged[0].PublishDate); d != "2024-06-20" {
+		t.Errorf("reportDate = %q", d)
+	}
+}
+
+func TestFormatReportsToTextWithBudget(t *testing.T) {
+	service := &ResearchReportService{}
+	reports := make([]ResearchReport, 10)
+	for i := range reports {
+		reports[i] = ResearchReport{Title: "业绩稳健增长，维持买入评级", OrgSName: "某证券", EmRatingName: "买入"}
+	}
+
+	full := service.FormatReportsToText(reports)
+	limited := service.FormatReportsToTextWithBudget(reports, tokens.Estimate(full)/3)
+	if len(limited) >= len(full) {
+		t.Fatalf("budgeted text should be shorter than full text")
+	}
+	if !strings.Contains(limited, "因长度限制省略") {
+		t.Errorf("budgeted text should note omitted reports: %s", limited)
+	}
+	if one := service.FormatReportsToTextWithBudget(reports, 1); !strings.HasPrefix(one, "1. 【买入】") {
+		t.Errorf("at least one report should be kept: %s", one)
+	}
+}
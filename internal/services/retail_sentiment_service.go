@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+var retailLog = logger.New("retail")
+
+// 股吧/雪球帖子列表接口
+const (
+	gubaPostListURL   = "https://gbapi.eastmoney.com/webarticlelist/api/Article/Articlelist?code=%s&sorttype=1&ps=%d&from=CommonBaPost&deviceid=0&version=200&product=Guba&plat=Web"
+	xueqiuPostListURL = "https://xueqiu.com/query/v1/symbol/search/status.json?count=%d&comment=0&symbol=%s&hl=0&source=all&sort=time&page=1&q=&type=11"
+)
+
+const (
+	retailPostCacheTTL   = 3 * time.Minute
+	retailPostsPerSource = 40
+	retailPromptMaxPosts = 60 // 送入 LLM 的帖子上限，控制 token
+)
+
+// RetailPost 散户讨论帖
+type RetailPost struct {
+	Source  string `json:"source"` // 股吧 / 雪球
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Author  string `json:"author"`
+	Time    string `json:"time"` // YYYY-MM-DD HH:MM
+	Replies int    `json:"replies"`
+	Likes   int    `json:"likes"`
+	URL     string `json:"url"`
+}
+
+// RetailSentiment 散户情绪分析结果
+type RetailSentiment struct {
+	Code    string       `json:"code"`
+	Name    string       `json:"name"`
+	Posts   []RetailPost `json:"posts"`
+	Summary string       `json:"summary"` // LLM 生成的情绪与话题总结，未配置 AI 时为空
+}
+
+// retailPostCache 讨论帖缓存
+type retailPostCache struct {
+	posts     []RetailPost
+	timestamp time.Time
+}
+
+// RetailSentimentService 股吧/雪球散户情绪服务
+type RetailSentimentService struct {
+	client      *http.Client
+	llmProvider func(ctx context.Context) (model.LLM, error)
+	cache       map[string]*retailPostCache
+	mu          sync.RWMutex
+}
+
+// NewRetailSentimentService 创建散户情绪服务
+func NewRetailSentimentService() *RetailSentimentService {
+	client := proxy.GetManager().GetClientWithTimeout(10 * time.Second)
+	// 雪球接口依赖首页下发的 cookie
+	client.Jar, _ = cookiejar.New(nil)
+	return &RetailSentimentService{
+		client: client,
+		cache:  make(map[string]*retailPostCache),
+	}
+}
+
+// SetLLMProvider 设置用于情绪总结的 LLM 获取方式（按需创建，跟随 AI 配置变化）
+func (s *RetailSentimentService) SetLLMProvider(provider func(ctx context.Context) (model.LLM, error)) {
+	s.llmProvider = provider
+}
+
+// GetPosts 获取个股近期讨论帖（股吧+雪球去重，按时间倒序）
+func (s *RetailSentimentService) GetPosts(code string) ([]RetailPost, error) {
+	s.mu.RLock()
+	if cached, ok := s.cache[code]; ok && time.Since(cached.timestamp) < retailPostCacheTTL {
+		s.mu.RUnlock()
+		return cached.posts, nil
+	}
+	s.mu.RUnlock()
+
+	var guba, xueqiu []RetailPost
+	var gubaErr, xueqiuErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		guba, gubaErr = s.fetchGuba(code)
+	}()
+	go func() {
+		defer wg.Done()
+		xueqiu, xueqiuErr = s.fetchXueqiu(code)
+	}()
+	wg.Wait()
+
+	if gubaErr != nil && xueqiuErr != nil {
+		return nil, fmt.Errorf("获取讨论帖失败: 股吧: %v; 雪球: %v", gubaErr, xueqiuErr)
+	}
+	if gubaErr != nil {
+		retailLog.Warn("股吧获取失败: %v", gubaErr)
+	}
+	if xueqiuErr != nil {
+		retailLog.Warn("雪球获取失败: %v", xueqiuErr)
+	}
+
+	posts := mergeRetailPosts(append(guba, xueqiu...))
+
+	s.mu.Lock()
+	s.cache[code] = &retailPostCache{posts: posts, timestamp: time.Now()}
+	s.mu.Unlock()
+	return posts, nil
+}
+
+// Analyze 获取讨论帖并调用 LLM 生成情绪与话题总结
+// 未配置 AI 或 LLM 调用失败时仍返回帖子，Summary 为空
+func (s *RetailSentimentService) Analyze(ctx context.Context, code string) (*RetailSentiment, error) {
+	posts, err := s.GetPosts(code)
+	if err != nil {
+		return nil, err
+	}
+	result := &RetailSentiment{
+		Code:  code,
+		Name:  GetStockIndex().Name(code),
+		Posts: posts,
+	}
+	if len(posts) == 0 || s.llmProvider == nil {
+		return result, nil
+	}
+
+	llm, err := s.llmProvider(ctx)
+	if err != nil {
+		retailLog.Warn("创建情绪总结 LLM 失败: %v", err)
+		return result, nil
+	}
+	summary, err := s.summarize(ctx, llm, result)
+	if err != nil {
+		retailLog.Warn("情绪总结失败: %v", err)
+		return result, nil
+	}
+	result.Summary = summary
+	return result, nil
+}
+
+// summarize 调用 LLM 总结散户情绪
+func (s *RetailSentimentService) summarize(ctx context.Context, llm model.LLM, r *RetailSentiment) (string, error) {
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{
+				Role:  "user",
+				Parts: []*genai.Part{{Text: buildRetailPrompt(r)}},
+			},
+		},
+	}
+
+	var sb strings.Builder
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return "", err
+		}
+		if resp == nil || resp.Content == nil {
+			continue
+		}
+		for _, part := range resp.Content.Parts {
+			if !part.Thought && part.Text != "" {
+				sb.WriteString(part.Text)
+			}
+		}
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// buildRetailPrompt 构建情绪总结提示词
+func buildRetailPrompt(r *RetailSentiment) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "以下是%s(%s)在股吧、雪球上的近期散户讨论，请总结散户情绪。\n\n", r.Name, r.Code)
+	sb.WriteString("【输出要求】\n")
+	sb.WriteString("1. 情绪倾向：偏多/偏空/分歧，并给出情绪分数（-100极度悲观 ~ 100极度乐观）\n")
+	sb.WriteString("2. 热议话题：3-5个主要讨论主题\n")
+	sb.WriteString("3. 代表观点：多空双方各1-2条有代表性的观点\n")
+	sb.WriteString("4. 反向提示：情绪是否过热或过冷，有无反向指标意义\n")
+	sb.WriteString("总字数200字以内，不要复述原帖。\n\n【讨论帖】\n")
+	for i, p := range r.Posts {
+		if i >= retailPromptMaxPosts {
+			break
+		}
+		text := p.Title
+		if p.Content != "" && p.Content != p.Title {
+			text += "｜" + rune2string(p.Content, 120)
+		}
+		fmt.Fprintf(&sb, "- [%s %s 回复%d] %s\n", p.Source, p.Time, p.Replies, text)
+	}
+	return sb.String()
+}
+
+// fetchGuba 东方财富股吧最新发帖
+func (s *RetailSentimentService) fetchGuba(code string) ([]RetailPost, error) {
+	plain := strings.TrimLeft(code, "shzbj")
+	body, err := s.get(fmt.Sprintf(gubaPostListURL, plain, retailPostsPerSource), "https://guba.eastmoney.com/")
+	if err != nil {
+		return nil, err
+	}
+	return parseGubaPosts(body)
+}
+
+// parseGubaPosts 解析股吧帖子列表
+func parseGubaPosts(body []byte) ([]RetailPost, error) {
+	var resp struct {
+		Re []struct {
+			PostID      int64  `json:"post_id"`
+			Title       string `json:"post_title"`
+			PublishTime string `json:"post_publish_time"`
+			Comments    int    `json:"post_comment_count"`
+			Likes       int    `json:"post_like_count"`
+			Nickname    string `json:"user_nickname"`
+			StockbarID  string `json:"stockbar_code"`
+		} `json:"re"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析股吧帖子失败: %w", err)
+	}
+
+	posts := make([]RetailPost, 0, len(resp.Re))
+	for _, p := range resp.Re {
+		posts = append(posts, RetailPost{
+			Source:  "股吧",
+			Title:   strings.TrimSpace(p.Title),
+			Author:  p.Nickname,
+			Time:    normalizeNewsTime(p.PublishTime),
+			Replies: p.Comments,
+			Likes:   p.Likes,
+			URL:     fmt.Sprintf("https://guba.eastmoney.com/news,%s,%d.html", p.StockbarID, p.PostID),
+		})
+	}
+	return posts, nil
+}
+
+// fetchXueqiu 雪球个股讨论
+func (s *RetailSentimentService) fetchXueqiu(code string) ([]RetailPost, error) {
+	// 预热 cookie
+	if _, err := s.get("https://xueqiu.com/", ""); err != nil {
+		return nil, err
+	}
+	body, err := s.get(fmt.Sprintf(xueqiuPostListURL, retailPostsPerSource, strings.ToUpper(code)), "https://xueqiu.com/")
+	if err != nil {
+		return nil, err
+	}
+	return parseXueqiuPosts(body)
+}
+
+// parseXueqiuPosts 解析雪球讨论列表
+func parseXueqiuPosts(body []byte) ([]RetailPost, error) {
+	var resp struct {
+		List []struct {
+			ID        int64  `json:"id"`
+			Title     string `json:"title"`
+			Text      string `json:"text"`
+			CreatedAt int64  `json:"created_at"` // 毫秒时间戳
+			Replies   int    `json:"reply_count"`
+			Likes     int    `json:"like_count"`
+			Target    string `json:"target"`
+			User      struct {
+				ScreenName string `json:"screen_name"`
+			} `json:"user"`
+		} `json:"list"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析雪球讨论失败: %w", err)
+	}
+
+	posts := make([]RetailPost, 0, len(resp.List))
+	for _, p := range resp.List {
+		content := stripTags(p.Text)
+		title := strings.TrimSpace(p.Title)
+		if title == "" {
+			title = rune2string(content, 40)
+		}
+		posts = append(posts, RetailPost{
+			Source:  "雪球",
+			Title:   title,
+			Content: content,
+			Author:  p.User.ScreenName,
+			Time:    time.UnixMilli(p.CreatedAt).Format("2006-01-02 15:04"),
+			Replies: p.Replies,
+			Likes:   p.Likes,
+			URL:     "https://xueqiu.com" + p.Target,
+		})
+	}
+	return posts, nil
+}
+
+func (s *RetailSentimentService) get(target, referer string) ([]byte, error) {
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	if referer != "" {
+		req.Header.Set("Referer", referer)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// mergeRetailPosts 按正文前缀去重（转发、刷屏帖），按时间倒序
+func mergeRetailPosts(posts []RetailPost) []RetailPost {
+	seen := make(map[string]bool, len(posts))
+	merged := make([]RetailPost, 0, len(posts))
+	for _, p := range posts {
+		text := p.Content
+		if text == "" {
+			text = p.Title
+		}
+		key := []rune(newsTitleTrim.Replace(text))
+		if len(key) > 30 {
+			key = key[:30]
+		}
+		if len(key) == 0 || seen[string(key)] {
+			continue
+		}
+		seen[string(key)] = true
+		merged = append(merged, p)
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Time > merged[j].Time })
+	return merged
+}
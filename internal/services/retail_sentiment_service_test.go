@@ -0,0 +1,55 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGubaPosts(t *testing.T) {
+	body := []byte(`{"re":[{"post_id":123,"post_title":" 明天必涨停 ","post_publish_time":"2024-06-20 10:30:15","post_comment_count":12,"post_like_count":3,"user_nickname":"股友A","stockbar_code":"600519"}]}`)
+	posts, err := parseGubaPosts(body)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	p := posts[0]
+	if p.Title != "明天必涨停" || p.Time != "2024-06-20 10:30" || p.Replies != 12 || p.URL != "https://guba.eastmoney.com/news,600519,123.html" {
+		t.Errorf("unexpected post: %+v", p)
+	}
+}
+
+func TestParseXueqiuPosts(t *testing.T) {
+	body := []byte(`{"list":[{"id":1,"title":"","text":"<p>估值已经到底部区域，<b>长期看好</b></p>","created_at":1718850615000,"reply_count":5,"like_count":20,"target":"/1/1","user":{"screen_name":"价值投资者"}}]}`)
+	posts, err := parseXueqiuPosts(body)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	p := posts[0]
+	if p.Content != "估值已经到底部区域，长期看好" || p.Title != p.Content || p.URL != "https://xueqiu.com/1/1" {
+		t.Errorf("unexpected post: %+v", p)
+	}
+}
+
+func TestMergeRetailPosts(t *testing.T) {
+	posts := mergeRetailPosts([]RetailPost{
+		{Source: "股吧", Title: "主力出货了 快跑", Time: "2024-06-20 10:00"},
+		{Source: "股吧", Title: "主力出货了快跑", Time: "2024-06-20 10:05"},
+		{Source: "雪球", Title: "业绩超预期", Content: "业绩超预期，继续持有", Time: "2024-06-20 11:00"},
+	})
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2 after dedup", len(posts))
+	}
+	if posts[0].Source != "雪球" {
+		t.Errorf("posts should be sorted by time desc: %+v", posts)
+	}
+
+	prompt := buildRetailPrompt(&RetailSentiment{Code: "sh600519", Name: "贵州茅台", Posts: posts})
+	if !strings.Contains(prompt, "贵州茅台(sh600519)") || !strings.Contains(prompt, "业绩超预期｜业绩超预期，继续持有") {
+		t.Errorf("unexpected prompt: %s", prompt)
+	}
+}
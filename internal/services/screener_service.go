@@ -0,0 +1,433 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+// 东方财富沪深京A股行情快照：f2现价 f3涨跌幅 f6成交额 f8换手率 f10量比 f20总市值 f23市净率 f24六十日涨跌幅 f25年初至今涨跌幅 f100行业 f115市盈率TTM
+const emScreenerURL = "https://push2.eastmoney.com/api/qt/clist/get?pn=%d&pz=%d&po=1&np=1&fltt=2&invt=2&fid=f20&fs=m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23,m:0+t:81+s:2048&fields=f2,f3,f6,f8,f10,f12,f13,f14,f20,f23,f24,f25,f100,f115"
+
+// 东方财富分红送配（按报告期过滤，PRETAX_BONUS_RMB 为每10股税前派息）
+const emDividendURL = "https://datacenter-web.eastmoney.com/api/data/v1/get?reportName=RPT_SHAREBONUS_DET&columns=SECURITY_CODE,PRETAX_BONUS_RMB&filter=(REPORT_DATE%%3D%%27%s%%27)&pageNumber=%d&pageSize=%d&source=WEB&client=WEB"
+
+const (
+	screenerPageSize      = 500
+	screenerSnapshotTTL   = 5 * time.Minute
+	screenerDividendTTL   = 12 * time.Hour
+	screenerDefaultLimit  = 20
+	screenerMaxLimit      = 100
+	screenerDividendPages = 20
+)
+
+// ScreenerStock 选股快照中的单只股票
+type ScreenerStock struct {
+	Symbol        string  `json:"symbol"`
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	Industry      string  `json:"industry"`
+	Price         float64 `json:"price"`
+	ChangePct     float64 `json:"changePct"`     // 当日涨跌幅%
+	Change60d     float64 `json:"change60d"`     // 60日涨跌幅%
+	ChangeYTD     float64 `json:"changeYtd"`     // 年初至今涨跌幅%
+	Turnover      float64 `json:"turnover"`      // 换手率%
+	VolumeRatio   float64 `json:"volumeRatio"`   // 量比
+	Amount        float64 `json:"amount"`        // 成交额（亿元）
+	MarketCap     float64 `json:"marketCap"`     // 总市值（亿元）
+	PE            float64 `json:"pe"`            // 市盈率TTM，亏损为负，缺失为 NaN
+	PB            float64 `json:"pb"`            // 市净率
+	DividendYield float64 `json:"dividendYield"` // 股息率%（最近年度分红/现价）
+}
+
+// ScreenField 可筛选字段
+type ScreenField struct {
+	Name string // 中文名称
+	Unit string
+	get  func(s *ScreenerStock) float64
+}
+
+// ScreenFields 支持的筛选/排序字段
+var ScreenFields = map[string]ScreenField{
+	"pe":             {Name: "市盈率TTM", get: func(s *ScreenerStock) float64 { return s.PE }},
+	"pb":             {Name: "市净率", get: func(s *ScreenerStock) float64 { return s.PB }},
+	"dividend_yield": {Name: "股息率", Unit: "%", get: func(s *ScreenerStock) float64 { return s.DividendYield }},
+	"market_cap":     {Name: "总市值", Unit: "亿", get: func(s *ScreenerStock) float64 { return s.MarketCap }},
+	"price":          {Name: "现价", get: func(s *ScreenerStock) float64 { return s.Price }},
+	"change_pct":     {Name: "今日涨跌幅", Unit: "%", get: func(s *ScreenerStock) float64 { return s.ChangePct }},
+	"change_60d":     {Name: "60日涨跌幅", Unit: "%", get: func(s *ScreenerStock) float64 { return s.Change60d }},
+	"change_ytd":     {Name: "年初至今涨跌幅", Unit: "%", get: func(s *ScreenerStock) float64 { return s.ChangeYTD }},
+	"turnover":       {Name: "换手率", Unit: "%", get: func(s *ScreenerStock) float64 { return s.Turnover }},
+	"volume_ratio":   {Name: "量比", get: func(s *ScreenerStock) float64 { return s.VolumeRatio }},
+	"amount":         {Name: "成交额", Unit: "亿", get: func(s *ScreenerStock) float64 { return s.Amount }},
+}
+
+// ScreenCondition 筛选条件，如 {pe < 10}
+type ScreenCondition struct {
+	Field string  `json:"field"`
+	Op    string  `json:"op"` // > >= < <=
+	Value float64 `json:"value"`
+}
+
+// ScreenQuery 选股查询
+type ScreenQuery struct {
+	Conditions []ScreenCondition `json:"conditions"`
+	Industries []string          `json:"industries"` // 行业关键词，匹配任一
+	ExcludeST  bool              `json:"excludeSt"`
+	SortBy     string            `json:"sortBy"` // 默认按总市值
+	Ascending  bool              `json:"ascending"`
+	Limit      int               `json:"limit"`
+}
+
+// ScreenResult 选股结果
+type ScreenResult struct {
+	Total  int             `json:"total"` // 满足条件的股票数
+	Stocks []ScreenerStock `json:"stocks"`
+}
+
+// ScreenerService 选股服务：全市场行情快照 + 分红数据，按估值、动量、成交、行业筛选
+type ScreenerService struct {
+	client *http.Client
+
+	snapshot     []ScreenerStock
+	snapshotTime time.Time
+	dividends    map[string]float64 // 代码 -> 每股税前派息（元）
+	dividendTime time.Time
+	mu           sync.RWMutex
+}
+
+// NewScreenerService 创建选股服务
+func NewScreenerService() *ScreenerService {
+	return &ScreenerService{
+		client: proxy.GetManager().GetClientWithTimeout(15 * time.Second),
+	}
+}
+
+// Screen 执行选股
+func (s *ScreenerService) Screen(q ScreenQuery) (*ScreenResult, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	stocks, err := s.loadSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	if q.usesField("dividend_yield") {
+		dividends, err := s.loadDividends()
+		if err != nil {
+			return nil, err
+		}
+		stocks = withDividendYield(stocks, dividends)
+	}
+	return screenStocks(stocks, q), nil
+}
+
+// validate 校验字段与运算符
+func (q *ScreenQuery) validate() error {
+	for _, c := range q.Conditions {
+		if _, ok := ScreenFields[c.Field]; !ok {
+			return fmt.Errorf("不支持的筛选字段: %s（支持: %s）", c.Field, strings.Join(screenFieldNames(), ", "))
+		}
+		switch c.Op {
+		case ">", ">=", "<", "<=":
+		default:
+			return fmt.Errorf("不支持的运算符: %s（支持 > >= < <=）", c.Op)
+		}
+	}
+	if q.SortBy != "" {
+		if _, ok := ScreenFields[q.SortBy]; !ok {
+			return fmt.Errorf("不支持的排序字段: %s", q.SortBy)
+		}
+	}
+	return nil
+}
+
+func (q *ScreenQuery) usesField(field string) bool {
+	if q.SortBy == field {
+		return true
+	}
+	for _, c := range q.Conditions {
+		if c.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func screenFieldNames() []string {
+	names := make([]string, 0, len(ScreenFields))
+	for name := range ScreenFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// screenStocks 过滤、排序并截取结果；缺失值（NaN）不满足任何条件，排序时置后
+func screenStocks(stocks []ScreenerStock, q ScreenQuery) *ScreenResult {
+	var matched []ScreenerStock
+	for i := range stocks {
+		st := &stocks[i]
+		if st.Price <= 0 {
+			continue // 停牌或未上市
+		}
+		if q.ExcludeST && (strings.Contains(st.Name, "ST") || strings.Contains(st.Name, "退")) {
+			continue
+		}
+		if len(q.Industries) > 0 && !matchIndustry(st.Industry, q.Industries) {
+			continue
+		}
+		if matchConditions(st, q.Conditions) {
+			matched = append(matched, *st)
+		}
+	}
+
+	sortBy := q.SortBy
+	if sortBy == "" {
+		sortBy = "market_cap"
+	}
+	get := ScreenFields[sortBy].get
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := get(&matched[i]), get(&matched[j])
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return !math.IsNaN(a) && math.IsNaN(b)
+		}
+		if q.Ascending {
+			return a < b
+		}
+		return a > b
+	})
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = screenerDefaultLimit
+	}
+	limit = min(limit, screenerMaxLimit)
+	result := &ScreenResult{Total: len(matched)}
+	result.Stocks = matched[:min(limit, len(matched))]
+	return result
+}
+
+// matchIndustry 行业与关键词互相包含即匹配（如 "银行股" 匹配行业 "银行"）
+func matchIndustry(industry string, keywords []string) bool {
+	if industry == "" {
+		return false
+	}
+	for _, kw := range keywords {
+		kw = strings.TrimSpace(kw)
+		if kw != "" && (strings.Contains(industry, kw) || strings.Contains(kw, industry)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchConditions(st *ScreenerStock, conditions []ScreenCondition) bool {
+	for _, c := range conditions {
+		v := ScreenFields[c.Field].get(st)
+		if math.IsNaN(v) {
+			return false
+		}
+		var ok bool
+		switch c.Op {
+		case ">":
+			ok = v > c.Value
+		case ">=":
+			ok = v >= c.Value
+		case "<":
+			ok = v < c.Value
+		case "<=":
+			ok = v <= c.Value
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// withDividendYield 按现价计算股息率，无分红记录的股票股息率为 0
+func withDividendYield(stocks []ScreenerStock, dividends map[string]float64) []ScreenerStock {
+	out := make([]ScreenerStock, len(stocks))
+	copy(out, stocks)
+	for i := range out {
+		if d := dividends[out[i].Code]; d > 0 && out[i].Price > 0 {
+			out[i].DividendYield = round2(d / out[i].Price * 100)
+		}
+	}
+	return out
+}
+
+// loadSnapshot 读取缓存或分页拉取全市场快照
+func (s *ScreenerService) loadSnapshot() ([]ScreenerStock, error) {
+	s.mu.RLock()
+	if s.snapshot != nil && time.Since(s.snapshotTime) < screenerSnapshotTTL {
+		cached := s.snapshot
+		s.mu.RUnlock()
+		return cached, nil
+	}
+	s.mu.RUnlock()
+
+	var all []ScreenerStock
+	for page := 1; ; page++ {
+		body, err := s.get(fmt.Sprintf(emScreenerURL, page, screenerPageSize), "https://quote.eastmoney.com/")
+		if err != nil {
+			return nil, err
+		}
+		items, total, err := parseScreenerSnapshot(body)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) == 0 || len(all) >= total {
+			break
+		}
+	}
+	if len(all) == 0 {
+		return nil, fmt.Errorf("行情快照为空")
+	}
+
+	s.mu.Lock()
+	s.snapshot, s.snapshotTime = all, time.Now()
+	s.mu.Unlock()
+	return all, nil
+}
+
+// loadDividends 读取缓存或拉取最近年度分红
+func (s *ScreenerService) loadDividends() (map[string]float64, error) {
+	s.mu.RLock()
+	if s.dividends != nil && time.Since(s.dividendTime) < screenerDividendTTL {
+		cached := s.dividends
+		s.mu.RUnlock()
+		return cached, nil
+	}
+	s.mu.RUnlock()
+
+	reportDate := latestAnnualReportDate(time.Now())
+	dividends := make(map[string]float64)
+	for page := 1; page <= screenerDividendPages; page++ {
+		body, err := s.get(fmt.Sprintf(emDividendURL, reportDate, page, screenerPageSize), "https://data.eastmoney.com/yjfp/")
+		if err != nil {
+			return nil, err
+		}
+		n, err := parseDividendPage(body, dividends)
+		if err != nil {
+			return nil, err
+		}
+		if n < screenerPageSize {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	s.dividends, s.dividendTime = dividends, time.Now()
+	s.mu.Unlock()
+	return dividends, nil
+}
+
+func (s *ScreenerService) get(url, referer string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", referer)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// latestAnnualReportDate 最近已全部披露的年报期：年报于4月底前披露完毕
+func latestAnnualReportDate(now time.Time) string {
+	year := now.Year() - 1
+	if now.Month() < time.May {
+		year--
+	}
+	return fmt.Sprintf("%d-12-31", year)
+}
+
+// parseScreenerSnapshot 解析行情快照分页，缺失值（"-"）记为 NaN
+func parseScreenerSnapshot(body []byte) ([]ScreenerStock, int, error) {
+	var resp struct {
+		Data *struct {
+			Total int              `json:"total"`
+			Diff  []map[string]any `json:"diff"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("解析行情快照失败: %w", err)
+	}
+	if resp.Data == nil {
+		return nil, 0, nil
+	}
+
+	num := func(d map[string]any, key string) float64 {
+		if v, ok := macroValue(d[key]); ok {
+			return v
+		}
+		return math.NaN()
+	}
+	items := make([]ScreenerStock, 0, len(resp.Data.Diff))
+	for _, d := range resp.Data.Diff {
+		code, _ := d["f12"].(string)
+		name, _ := d["f14"].(string)
+		market, _ := d["f13"].(float64)
+		if code == "" || name == "" {
+			continue
+		}
+		industry, _ := d["f100"].(string)
+		if industry == "-" {
+			industry = ""
+		}
+		price := num(d, "f2")
+		if math.IsNaN(price) {
+			price = 0
+		}
+		items = append(items, ScreenerStock{
+			Symbol:      emSymbol(code, int(market)),
+			Code:        code,
+			Name:        name,
+			Industry:    industry,
+			Price:       price,
+			ChangePct:   num(d, "f3"),
+			Change60d:   num(d, "f24"),
+			ChangeYTD:   num(d, "f25"),
+			Turnover:    num(d, "f8"),
+			VolumeRatio: num(d, "f10"),
+			Amount:      round2(num(d, "f6") / 1e8),
+			MarketCap:   round2(num(d, "f20") / 1e8),
+			PE:          num(d, "f115"),
+			PB:          num(d, "f23"),
+		})
+	}
+	return items, resp.Data.Total, nil
+}
+
+// parseDividendPage 解析分红分页并累加每股派息（同一报告期可能有多条），返回本页条数
+func parseDividendPage(body []byte, dividends map[string]float64) (int, error) {
+	var rows []struct {
+		Code  string   `json:"SECURITY_CODE"`
+		Bonus *float64 `json:"PRETAX_BONUS_RMB"`
+	}
+	if err := decodeDatacenter(body, &rows); err != nil {
+		return 0, err
+	}
+	for _, r := range rows {
+		if r.Bonus != nil && *r.Bonus > 0 {
+			dividends[r.Code] += *r.Bonus / 10
+		}
+	}
+	return len(rows), nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseScreenerSnapshot(t *testing.T) {
+	body := []byte(`{"data":{"total":2,"diff":[
+		{"f2":5.12,"f3":0.59,"f6":812345678,"f8":0.21,"f10":0.95,"f12":"601398","f13":1,"f14":"工商银行","f20":1824500000000,"f23":0.58,"f24":6.1,"f25":12.3,"f100":"银行","f115":5.3},
+		{"f2":"-","f3":"-","f6":"-","f8":"-","f10":"-","f12":"300999","f13":0,"f14":"某新股","f20":"-","f23":"-","f24":"-","f25":"-","f100":"-","f115":"-"}
+	]}}`)
+	items, total, err := parseScreenerSnapshot(body)
+	if err != nil || total != 2 || len(items) != 2 {
+		t.Fatalf("items=%d total=%d err=%v", len(items), total, err)
+	}
+	icbc := items[0]
+	if icbc.Symbol != "sh601398" || icbc.Industry != "银行" || icbc.PE != 5.3 || icbc.MarketCap != 18245 || icbc.Amount != 8.12 {
+		t.Errorf("unexpected stock: %+v", icbc)
+	}
+	blank := items[1]
+	if blank.Price != 0 || blank.Industry != "" || !math.IsNaN(blank.PE) || !math.IsNaN(blank.Turnover) {
+		t.Errorf("missing values should be NaN: %+v", blank)
+	}
+
+	if items, _, err := parseScreenerSnapshot([]byte(`{"rc":0,"data":null}`)); err != nil || items != nil {
+		t.Errorf("null data: %v, %v", items, err)
+	}
+}
+
+func TestScreenStocks(t *testing.T) {
+	nan := math.NaN()
+	stocks := []ScreenerStock{
+		{Code: "601398", Name: "工商银行", Industry: "银行", Price: 5, PE: 5.3, MarketCap: 18000, DividendYield: 6.1},
+		{Code: "600036", Name: "招商银行", Industry: "银行", Price: 35, PE: 6.5, MarketCap: 8800, DividendYield: 5.0},
+		{Code: "000001", Name: "平安银行", Industry: "银行", Price: 11, PE: 4.5, MarketCap: 2100, DividendYield: 3.5},
+		{Code: "600000", Name: "*ST某银", Industry: "银行", Price: 2, PE: 3, MarketCap: 50, DividendYield: 8},
+		{Code: "600519", Name: "贵州茅台", Industry: "酿酒行业", Price: 1500, PE: 22, MarketCap: 19000, DividendYield: 3.3},
+		{Code: "688001", Name: "某亏损股", Industry: "半导体", Price: 20, PE: -30, MarketCap: 100},
+		{Code: "688002", Name: "停牌股", Industry: "银行", Price: 0, PE: 4, MarketCap: 300},
+		{Code: "688003", Name: "缺失股", Industry: "银行", Price: 9, PE: nan, MarketCap: 400},
+	}
+
+	// 低PE高股息的银行股
+	res := screenStocks(stocks, ScreenQuery{
+		Conditions: []ScreenCondition{{"pe", ">", 0}, {"pe", "<", 10}, {"dividend_yield", ">=", 4}},
+		Industries: []string{"银行股"},
+		ExcludeST:  true,
+	})
+	if res.Total != 2 || res.Stocks[0].Code != "601398" || res.Stocks[1].Code != "600036" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	// 按 PE 升序，缺失值置后，停牌股排除
+	res = screenStocks(stocks, ScreenQuery{Industries: []string{"银行"}, SortBy: "pe", Ascending: true})
+	if res.Total != 5 || res.Stocks[0].Code != "600000" || res.Stocks[4].Code != "688003" {
+		t.Errorf("unexpected order: %+v", res.Stocks)
+	}
+
+	res = screenStocks(stocks, ScreenQuery{Limit: 2})
+	if res.Total != 7 || len(res.Stocks) != 2 || res.Stocks[0].Code != "600519" {
+		t.Errorf("default sort by market cap with limit: %+v", res)
+	}
+}
+
+func TestScreenQueryValidate(t *testing.T) {
+	valid := ScreenQuery{Conditions: []ScreenCondition{{"pb", "<=", 1}}, SortBy: "turnover"}
+	if err := valid.validate(); err != nil {
+		t.Errorf("valid query: %v", err)
+	}
+	for _, q := range []ScreenQuery{
+		{Conditions: []ScreenCondition{{"roe", ">", 10}}},
+		{Conditions: []ScreenCondition{{"pe", "==", 10}}},
+		{SortBy: "name"},
+	} {
+		if err := q.validate(); err == nil {
+			t.Errorf("expected error for %+v", q)
+		}
+	}
+	if !(&ScreenQuery{SortBy: "dividend_yield"}).usesField("dividend_yield") {
+		t.Error("sort field should count as used")
+	}
+}
+
+func TestDividendYield(t *testing.T) {
+	dividends := map[string]float64{}
+	body := []byte(`{"result":{"data":[{"SECURITY_CODE":"601398","PRETAX_BONUS_RMB":1.43},{"SECURITY_CODE":"601398","PRETAX_BONUS_RMB":1.57},{"SECURITY_CODE":"600519","PRETAX_BONUS_RMB":null}]}}`)
+	n, err := parseDividendPage(body, dividends)
+	if err != nil || n != 3 {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	if math.Abs(dividends["601398"]-0.3) > 1e-9 || len(dividends) != 1 {
+		t.Errorf("unexpected dividends: %v", dividends)
+	}
+
+	stocks := []ScreenerStock{{Code: "601398", Price: 5}, {Code: "600519", Price: 1500}}
+	out := withDividendYield(stocks, dividends)
+	if out[0].DividendYield != 6 || out[1].DividendYield != 0 || stocks[0].DividendYield != 0 {
+		t.Errorf("unexpected yields: %+v (source must be untouched: %+v)", out, stocks)
+	}
+}
+
+func TestLatestAnnualReportDate(t *testing.T) {
+	if got := latestAnnualReportDate(time.Date(2025, 4, 15, 0, 0, 0, 0, time.Local)); got != "2023-12-31" {
+		t.Errorf("before May: %s", got)
+	}
+	if got := latestAnnualReportDate(time.Date(2025, 5, 1, 0, 0, 0, 0, time.Local)); got != "2024-12-31" {
+		t.Errorf("after April: %s", got)
+	}
+}
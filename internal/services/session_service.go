@@ -2,13 +2,16 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/vault"
 
 	"github.com/google/uuid"
 )
@@ -58,6 +61,10 @@ func (ss *SessionService) GetOrCreateSession(stockCode, stockName string) (*mode
 		ss.sessions[stockCode] = session
 		return session, nil
 	}
+	// 数据未解锁时不能新建，否则解锁后新 Session 会遮盖原有记录
+	if errors.Is(err, vault.ErrLocked) {
+		return nil, err
+	}
 
 	// 创建新Session
 	now := time.Now().UnixMilli()
@@ -77,7 +84,7 @@ func (ss *SessionService) GetOrCreateSession(stockCode, stockName string) (*mode
 // loadSession 从文件加载Session
 func (ss *SessionService) loadSession(stockCode string) (*models.StockSession, error) {
 	path := ss.getSessionPath(stockCode)
-	data, err := os.ReadFile(path)
+	data, err := vault.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +103,15 @@ func (ss *SessionService) saveSession(session *models.StockSession) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	return vault.WriteFile(path, data)
+}
+
+// Reset 清空内存缓存并重新加载监控中的交易计划（数据解锁后调用）
+func (ss *SessionService) Reset() {
+	ss.mu.Lock()
+	ss.sessions = make(map[string]*models.StockSession)
+	ss.mu.Unlock()
+	ss.LoadTradePlans()
 }
 
 // GetSession 获取Session
@@ -189,6 +204,29 @@ func (ss *SessionService) GetMessages(stockCode string) []models.ChatMessage {
 	return session.Messages
 }
 
+// ForEachMessage 遍历全部 Session 的消息，未缓存的 Session 只读取文件不加入缓存
+func (ss *SessionService) ForEachMessage(fn func(stockCode string, msg models.ChatMessage)) {
+	files, err := filepath.Glob(filepath.Join(ss.sessionsDir, "*.json"))
+	if err != nil {
+		return
+	}
+
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	for _, file := range files {
+		stockCode := strings.TrimSuffix(filepath.Base(file), ".json")
+		session, ok := ss.sessions[stockCode]
+		if !ok {
+			if session, err = ss.loadSession(stockCode); err != nil {
+				continue
+			}
+		}
+		for _, msg := range session.Messages {
+			fn(stockCode, msg)
+		}
+	}
+}
+
 // ClearMessages 清空Session消息
 func (ss *SessionService) ClearMessages(stockCode string) error {
 	ss.mu.Lock()
@@ -251,3 +289,340 @@ func (ss *SessionService) GetPosition(stockCode string) *models.StockPosition {
 	}
 	return session.Position
 }
+
+// SyncPositions 以券商持仓覆盖本地持仓：held 为 代码→持仓，names 为 代码→名称（新建 Session 用）
+// 券商未持有但本地仍有持仓的 Session 清空持仓，返回被清空的股票代码
+func (ss *SessionService) SyncPositions(held map[string]models.StockPosition, names map[string]string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(ss.sessionsDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for _, file := range files {
+		stockCode := strings.TrimSuffix(filepath.Base(file), ".json")
+		if _, ok := ss.sessions[stockCode]; ok {
+			continue
+		}
+		if session, err := ss.loadSession(stockCode); err == nil {
+			ss.sessions[stockCode] = session
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	var cleared []string
+	for code, session := range ss.sessions {
+		if _, ok := held[code]; ok || session.Position == nil || session.Position.Shares == 0 {
+			continue
+		}
+		session.Position = nil
+		session.UpdatedAt = now
+		if err := ss.saveSession(session); err != nil {
+			return cleared, err
+		}
+		cleared = append(cleared, code)
+	}
+	for code, pos := range held {
+		session, ok := ss.sessions[code]
+		if !ok {
+			session = &models.StockSession{
+				ID:        uuid.New().String(),
+				StockCode: code,
+				StockName: names[code],
+				Messages:  []models.ChatMessage{},
+				CreatedAt: now,
+			}
+			ss.sessions[code] = session
+		}
+		if session.Position != nil && *session.Position == pos {
+			continue
+		}
+		session.Position = &models.StockPosition{Shares: pos.Shares, CostPrice: pos.CostPrice}
+		session.UpdatedAt = now
+		if err := ss.saveSession(session); err != nil {
+			return cleared, err
+		}
+	}
+	return cleared, nil
+}
+
+// SaveBriefing 保存盘前早报，同时作为小韭菜消息追加到讨论历史
+func (ss *SessionService) SaveBriefing(stockCode, stockName, date, content string) (models.ChatMessage, error) {
+	session, err := ss.GetOrCreateSession(stockCode, stockName)
+	if err != nil {
+		return models.ChatMessage{}, err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	now := time.Now().UnixMilli()
+	msg := models.ChatMessage{
+		ID:        uuid.New().String(),
+		AgentID:   "moderator",
+		AgentName: "小韭菜",
+		Role:      "会议主持",
+		Content:   content,
+		Timestamp: now,
+		MsgType:   "briefing",
+	}
+	session.Briefing = &models.Briefing{Date: date, Content: content, CreatedAt: now}
+	session.Messages = append(session.Messages, msg)
+	session.UpdatedAt = now
+	return msg, ss.saveSession(session)
+}
+
+// BriefingDate 获取最近一次早报的交易日，没有早报返回空
+func (ss *SessionService) BriefingDate(stockCode string) string {
+	if session := ss.GetSession(stockCode); session != nil && session.Briefing != nil {
+		return session.Briefing.Date
+	}
+	return ""
+}
+
+// RecordAdvice 记录会议结论中的操作建议，并快照当前持仓用于后续背离检测
+func (ss *SessionService) RecordAdvice(stockCode, summary string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		// 尝试从文件加载
+		var err error
+		session, err = ss.loadSession(stockCode)
+		if err != nil {
+			return fmt.Errorf("session not found: %s", stockCode)
+		}
+		ss.sessions[stockCode] = session
+	}
+
+	action := ClassifyAdviceAction(summary)
+	if action == "" {
+		// 无明确操作建议，上一次的建议（及其背离状态）已在本次会议中消化
+		session.LastAdvice = nil
+	} else {
+		var shares int64
+		if session.Position != nil {
+			shares = session.Position.Shares
+		}
+		excerpt := []rune(summary)
+		if len(excerpt) > 100 {
+			excerpt = append(excerpt[:100], []rune("...")...)
+		}
+		session.LastAdvice = &models.MeetingAdvice{
+			Action:    action,
+			Summary:   string(excerpt),
+			Shares:    shares,
+			CreatedAt: time.Now().UnixMilli(),
+		}
+	}
+	session.UpdatedAt = time.Now().UnixMilli()
+	return ss.saveSession(session)
+}
+
+// RecordConsensus 记录最近一次会议的多空共识分
+func (ss *SessionService) RecordConsensus(stockCode string, consensus *models.ConsensusScore) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		var err error
+		session, err = ss.loadSession(stockCode)
+		if err != nil {
+			return fmt.Errorf("session not found: %s", stockCode)
+		}
+		ss.sessions[stockCode] = session
+	}
+	session.LastConsensus = consensus
+	session.UpdatedAt = time.Now().UnixMilli()
+	return ss.saveSession(session)
+}
+
+// CheckAdviceDivergence 检查持仓变动是否与上次建议背离
+// 建议观察期满后首次检测到背离时标记并返回提醒内容，已标记过的不重复提醒
+func (ss *SessionService) CheckAdviceDivergence(stockCode string) *DivergenceNotice {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		return nil
+	}
+	return ss.markDivergenceLocked(session)
+}
+
+// CheckAllAdviceDivergence 检查全部会话的持仓是否与上次建议背离，返回新发现的背离提醒
+// 观察期内的调仓不会立即判定，由每日盘前的检查在期满后补发提醒
+func (ss *SessionService) CheckAllAdviceDivergence() []DivergenceNotice {
+	files, err := filepath.Glob(filepath.Join(ss.sessionsDir, "*.json"))
+	if err != nil {
+		return nil
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	var notices []DivergenceNotice
+	for _, file := range files {
+		stockCode := strings.TrimSuffix(filepath.Base(file), ".json")
+		session, ok := ss.sessions[stockCode]
+		if !ok {
+			if session, err = ss.loadSession(stockCode); err != nil {
+				continue
+			}
+		}
+		if notice := ss.markDivergenceLocked(session); notice != nil {
+			ss.sessions[stockCode] = session
+			notices = append(notices, *notice)
+		}
+	}
+	return notices
+}
+
+// markDivergenceLocked 持仓与上次建议背离时标记并保存，返回提醒内容（需要已持有锁）
+func (ss *SessionService) markDivergenceLocked(session *models.StockSession) *DivergenceNotice {
+	if session.LastAdvice == nil || session.LastAdvice.Diverged || session.Position == nil {
+		return nil
+	}
+	if !IsAdviceDiverged(session.LastAdvice, session.Position.Shares) {
+		return nil
+	}
+
+	session.LastAdvice.Diverged = true
+	session.LastAdvice.DivergedAt = time.Now().UnixMilli()
+	session.UpdatedAt = time.Now().UnixMilli()
+	if err := ss.saveSession(session); err != nil {
+		fmt.Printf("保存session失败: %v\n", err)
+	}
+	notice := BuildDivergenceNotice(session)
+	return &notice
+}
+
+// GetAdviceContext 获取"用户上次未执行建议"上下文（无背离时返回空字符串）
+func (ss *SessionService) GetAdviceContext(stockCode string) string {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		return ""
+	}
+	ss.markDivergenceLocked(session)
+	return BuildAdviceDivergenceContext(session.LastAdvice, session.Position)
+}
+
+// CreateTradePlan 将最近一次会议结论转换为交易计划并开始监控（覆盖原有计划）
+func (ss *SessionService) CreateTradePlan(stockCode string, price float64, autoReview bool) (*models.TradePlan, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok {
+		// 尝试从文件加载
+		var err error
+		session, err = ss.loadSession(stockCode)
+		if err != nil {
+			return nil, fmt.Errorf("session not found: %s", stockCode)
+		}
+		ss.sessions[stockCode] = session
+	}
+
+	var summary string
+	for i := len(session.Messages) - 1; i >= 0; i-- {
+		msg := session.Messages[i]
+		if msg.MsgType == "summary" && msg.MeetingMode != "compare" && msg.Content != "" {
+			summary = msg.Content
+			break
+		}
+	}
+	if summary == "" {
+		return nil, fmt.Errorf("暂无会议结论，请先召开一次会议")
+	}
+
+	plan, err := ParseTradePlan(summary, price)
+	if err != nil {
+		return nil, err
+	}
+	plan.AutoReview = autoReview
+	session.TradePlan = plan
+	session.UpdatedAt = time.Now().UnixMilli()
+	return plan, ss.saveSession(session)
+}
+
+// CancelTradePlan 取消交易计划的监控
+func (ss *SessionService) CancelTradePlan(stockCode string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	session, ok := ss.sessions[stockCode]
+	if !ok || session.TradePlan == nil {
+		return fmt.Errorf("trade plan not found: %s", stockCode)
+	}
+	if session.TradePlan.Status == TradePlanActive {
+		session.TradePlan.Status = TradePlanCancelled
+	}
+	session.UpdatedAt = time.Now().UnixMilli()
+	return ss.saveSession(session)
+}
+
+// LoadTradePlans 加载所有带监控中交易计划的 Session（启动时调用，之后由内存缓存维护）
+func (ss *SessionService) LoadTradePlans() {
+	files, err := filepath.Glob(filepath.Join(ss.sessionsDir, "*.json"))
+	if err != nil {
+		return
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for _, file := range files {
+		stockCode := strings.TrimSuffix(filepath.Base(file), ".json")
+		if _, ok := ss.sessions[stockCode]; ok {
+			continue
+		}
+		session, err := ss.loadSession(stockCode)
+		if err == nil && session.TradePlan != nil && session.TradePlan.Status == TradePlanActive {
+			ss.sessions[stockCode] = session
+		}
+	}
+}
+
+// ActiveTradePlanCodes 获取交易计划监控中的股票代码
+func (ss *SessionService) ActiveTradePlanCodes() []string {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	var codes []string
+	for code, session := range ss.sessions {
+		if session.TradePlan != nil && session.TradePlan.Status == TradePlanActive {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// CheckTradePlans 按最新价格检查交易计划，返回新触发的提醒
+func (ss *SessionService) CheckTradePlans(prices map[string]float64) []TradePlanAlert {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	var alerts []TradePlanAlert
+	now := time.Now()
+	for code, session := range ss.sessions {
+		price, ok := prices[code]
+		if !ok {
+			continue
+		}
+		hits := EvaluateTradePlan(session.TradePlan, price, now)
+		if len(hits) == 0 {
+			continue
+		}
+		for _, hit := range hits {
+			alerts = append(alerts, BuildTradePlanAlert(session, hit))
+		}
+		session.UpdatedAt = now.UnixMilli()
+		if err := ss.saveSession(session); err != nil {
+			fmt.Printf("保存session失败: %v\n", err)
+		}
+	}
+	return alerts
+}
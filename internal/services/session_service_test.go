@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/vault"
+)
+
+// TestSyncPositions 测试券商持仓覆盖本地持仓
+func TestSyncPositions(t *testing.T) {
+	dir := t.TempDir()
+	ss := NewSessionService(dir)
+	ss.GetOrCreateSession("sh600000", "浦发银行")
+	ss.GetOrCreateSession("sh600519", "贵州茅台")
+	ss.UpdatePosition("sh600000", 1000, 9.5)
+	ss.UpdatePosition("sh600519", 100, 1500)
+
+	// 新实例从文件加载，确认未缓存的 Session 也会被同步
+	ss = NewSessionService(dir)
+	held := map[string]models.StockPosition{
+		"sh600519": {Shares: 200, CostPrice: 1480},
+		"sz000001": {Shares: 500, CostPrice: 11},
+	}
+	cleared, err := ss.SyncPositions(held, map[string]string{"sz000001": "平安银行"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cleared) != 1 || cleared[0] != "sh600000" {
+		t.Errorf("cleared: %v", cleared)
+	}
+	if p := ss.GetPosition("sh600000"); p != nil {
+		t.Errorf("position not held at broker should be cleared: %+v", p)
+	}
+	if p := ss.GetPosition("sh600519"); p == nil || p.Shares != 200 || p.CostPrice != 1480 {
+		t.Errorf("position should follow broker: %+v", p)
+	}
+	if s := ss.GetSession("sz000001"); s == nil || s.StockName != "平安银行" || s.Position.Shares != 500 {
+		t.Errorf("new holding should create session: %+v", s)
+	}
+}
+
+// TestSaveBriefing 测试早报保存与按日去重
+func TestSaveBriefing(t *testing.T) {
+	ss := NewSessionService(t.TempDir())
+	if date := ss.BriefingDate("sh600000"); date != "" {
+		t.Errorf("no briefing yet: %q", date)
+	}
+	msg, err := ss.SaveBriefing("sh600000", "浦发银行", "2025-06-03", "隔夜要闻：无重要消息")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MsgType != "briefing" || msg.ID == "" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	if date := ss.BriefingDate("sh600000"); date != "2025-06-03" {
+		t.Errorf("briefing date: %q", date)
+	}
+	if msgs := ss.GetMessages("sh600000"); len(msgs) != 1 || msgs[0].Content != "隔夜要闻：无重要消息" {
+		t.Errorf("briefing should be appended to history: %+v", msgs)
+	}
+}
+
+// TestRecordConsensus 测试共识分保存到会话
+func TestRecordConsensus(t *testing.T) {
+	dir := t.TempDir()
+	ss := NewSessionService(dir)
+	if err := ss.RecordConsensus("sh600000", &models.ConsensusScore{Score: 40}); err == nil {
+		t.Error("missing session should fail")
+	}
+	ss.GetOrCreateSession("sh600000", "浦发银行")
+	if err := ss.RecordConsensus("sh600000", &models.ConsensusScore{Score: 40, Label: "偏多"}); err != nil {
+		t.Fatal(err)
+	}
+	ss = NewSessionService(dir)
+	if s := ss.GetSession("sh600000"); s == nil || s.LastConsensus == nil || s.LastConsensus.Score != 40 {
+		t.Errorf("consensus should be persisted: %+v", s)
+	}
+}
+
+// TestEncryptedSessions 测试加密后的 Session 在解锁前不可读写、解锁后恢复
+func TestEncryptedSessions(t *testing.T) {
+	dir := t.TempDir()
+	defer vault.Init(t.TempDir())
+
+	ss := NewSessionService(dir)
+	ss.GetOrCreateSession("sh600519", "贵州茅台")
+	ss.UpdatePosition("sh600519", 100, 1500)
+	v, err := vault.Init(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Enable("passphrase", EncryptedDataFiles(dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	v, _ = vault.Init(dir)
+	ss = NewSessionService(dir)
+	if _, err := ss.GetOrCreateSession("sh600519", "贵州茅台"); !errors.Is(err, vault.ErrLocked) {
+		t.Fatalf("locked session: %v", err)
+	}
+	if p := ss.GetPosition("sh600519"); p != nil {
+		t.Errorf("locked position should be hidden: %+v", p)
+	}
+
+	if err := v.Unlock("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	ss.Reset()
+	if p := ss.GetPosition("sh600519"); p == nil || p.Shares != 100 {
+		t.Errorf("position after unlock: %+v", p)
+	}
+}
+
+// TestAdviceDivergenceAfterReviewDelay 测试观察期内调仓不提醒、期满后由全量检查补发提醒
+func TestAdviceDivergenceAfterReviewDelay(t *testing.T) {
+	dir := t.TempDir()
+	ss := NewSessionService(dir)
+	ss.GetOrCreateSession("sh600519", "贵州茅台")
+	ss.UpdatePosition("sh600519", 1000, 1500)
+	if err := ss.RecordAdvice("sh600519", "短期风险较大，建议逢高减仓。"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 建议次日加仓：观察期内不提醒
+	ss.UpdatePosition("sh600519", 1500, 1500)
+	if notice := ss.CheckAdviceDivergence("sh600519"); notice != nil {
+		t.Fatalf("diverged within review delay: %+v", notice)
+	}
+	if notices := ss.CheckAllAdviceDivergence(); len(notices) != 0 {
+		t.Fatalf("sweep within review delay: %+v", notices)
+	}
+
+	// 一周后持仓仍高于建议时的持仓：新实例从文件加载后补发一次提醒
+	ss.GetSession("sh600519").LastAdvice.CreatedAt = time.Now().Add(-AdviceReviewDelay - time.Hour).UnixMilli()
+	ss.UpdatePosition("sh600519", 1500, 1500)
+	ss = NewSessionService(dir)
+	notices := ss.CheckAllAdviceDivergence()
+	if len(notices) != 1 || notices[0].StockCode != "sh600519" || notices[0].Action != AdviceActionReduce {
+		t.Fatalf("notices = %+v", notices)
+	}
+	if notices := ss.CheckAllAdviceDivergence(); len(notices) != 0 {
+		t.Errorf("duplicate notices: %+v", notices)
+	}
+	if ctx := ss.GetAdviceContext("sh600519"); ctx == "" {
+		t.Error("diverged advice should be injected into the next meeting")
+	}
+}
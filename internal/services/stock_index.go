@@ -0,0 +1,281 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/run-bigpig/jcp/internal/embed"
+)
+
+// StockBasicInfo 股票基础信息
+type StockBasicInfo struct {
+	Symbol   string `json:"symbol"`   // 带市场前缀的代码，如 sh600519
+	Code     string `json:"code"`     // 纯数字代码，如 600519
+	Name     string `json:"name"`     // 股票名称
+	Industry string `json:"industry"` // 所属行业
+	Market   string `json:"market"`   // 交易所：上海/深圳
+	Area     string `json:"area"`     // 地域
+	Spell    string `json:"spell"`    // 拼音首字母
+}
+
+// StockIndex 股票名称/行业内存索引（单例）
+// 启动时从嵌入的 stock_basic.json 构建，供 prompt、推送、工具格式化等处查询
+type StockIndex struct {
+	mu       sync.RWMutex
+	items    []StockBasicInfo
+	bySymbol map[string]int // 带前缀代码 -> items 下标
+	byCode   map[string]int // 纯数字代码 -> items 下标
+}
+
+var (
+	stockIndexInstance *StockIndex
+	stockIndexOnce     sync.Once
+)
+
+// GetStockIndex 获取股票索引单例
+func GetStockIndex() *StockIndex {
+	stockIndexOnce.Do(func() {
+		stockIndexInstance = &StockIndex{}
+		if err := stockIndexInstance.Load(embed.StockBasicJSON); err != nil {
+			log.Error("加载股票基础数据失败: %v", err)
+		}
+	})
+	return stockIndexInstance
+}
+
+// Load 从 stock_basic.json 格式的数据重建索引
+func (idx *StockIndex) Load(data []byte) error {
+	var basicData stockBasicData
+	if err := json.Unmarshal(data, &basicData); err != nil {
+		return err
+	}
+
+	fieldIdx := make(map[string]int, len(basicData.Data.Fields))
+	for i, field := range basicData.Data.Fields {
+		fieldIdx[field] = i
+	}
+	getField := func(item []interface{}, name string) string {
+		i, ok := fieldIdx[name]
+		if !ok || i >= len(item) {
+			return ""
+		}
+		s, _ := item[i].(string)
+		return s
+	}
+
+	items := make([]StockBasicInfo, 0, len(basicData.Data.Items))
+	bySymbol := make(map[string]int, len(basicData.Data.Items))
+	byCode := make(map[string]int, len(basicData.Data.Items))
+	for _, item := range basicData.Data.Items {
+		code := getField(item, "symbol")
+		if code == "" {
+			continue
+		}
+		info := StockBasicInfo{
+			Code:     code,
+			Symbol:   code,
+			Name:     getField(item, "name"),
+			Industry: getField(item, "industry"),
+			Area:     getField(item, "area"),
+			Spell:    strings.ToUpper(getField(item, "cnspell")),
+		}
+		// 从 ts_code 获取市场前缀
+		tsCode := getField(item, "ts_code")
+		if strings.HasSuffix(tsCode, ".SH") {
+			info.Market = "上海"
+			info.Symbol = "sh" + code
+		} else if strings.HasSuffix(tsCode, ".SZ") {
+			info.Market = "深圳"
+			info.Symbol = "sz" + code
+		}
+
+		bySymbol[info.Symbol] = len(items)
+		if _, exists := byCode[code]; !exists {
+			byCode[code] = len(items)
+		}
+		items = append(items, info)
+	}
+
+	idx.mu.Lock()
+	idx.items = items
+	idx.bySymbol = bySymbol
+	idx.byCode = byCode
+	idx.mu.Unlock()
+	return nil
+}
+
+// Overlay 用较新的股票列表覆盖索引：已有股票更新名称（行业为空时补全），新股追加到末尾
+// 嵌入数据中的行业、地域、拼音等字段保持不变，保证行业口径一致
+func (idx *StockIndex) Overlay(latest []StockBasicInfo) (updated, added int) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, info := range latest {
+		if info.Symbol == "" || info.Name == "" {
+			continue
+		}
+		i, ok := idx.bySymbol[info.Symbol]
+		if !ok {
+			// 嵌入数据中北交所股票没有市场前缀，按纯代码兜底匹配
+			if j, exists := idx.byCode[info.Code]; exists && idx.items[j].Symbol == info.Code {
+				i, ok = j, true
+				delete(idx.bySymbol, info.Code)
+				idx.items[i].Symbol = info.Symbol
+				idx.bySymbol[info.Symbol] = i
+			}
+		}
+		if ok {
+			item := &idx.items[i]
+			if item.Name != info.Name {
+				item.Name = info.Name
+				updated++
+			}
+			if item.Industry == "" {
+				item.Industry = info.Industry
+			}
+			if item.Market == "" {
+				item.Market = info.Market
+			}
+			continue
+		}
+
+		idx.bySymbol[info.Symbol] = len(idx.items)
+		if _, exists := idx.byCode[info.Code]; !exists {
+			idx.byCode[info.Code] = len(idx.items)
+		}
+		idx.items = append(idx.items, info)
+		added++
+	}
+	return updated, added
+}
+
+// Lookup 按代码查询股票基础信息，支持 sh600519 / 600519 / 600519.SH 等写法
+func (idx *StockIndex) Lookup(symbol string) (StockBasicInfo, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	key := strings.ToLower(strings.TrimSpace(symbol))
+	if i, ok := idx.bySymbol[key]; ok {
+		return idx.items[i], true
+	}
+	// 兼容 600519.SH 形式
+	if dot := strings.Index(key, "."); dot > 0 {
+		key = key[dot+1:] + key[:dot]
+		if i, ok := idx.bySymbol[key]; ok {
+			return idx.items[i], true
+		}
+	}
+	// 只有不带市场前缀的纯代码才按代码查找：sh000001（上证指数）不能落到 sz000001（平安银行）
+	if strings.TrimLeft(key, "abcdefghijklmnopqrstuvwxyz") != key {
+		return StockBasicInfo{}, false
+	}
+	if i, ok := idx.byCode[key]; ok {
+		return idx.items[i], true
+	}
+	return StockBasicInfo{}, false
+}
+
+// Name 获取股票名称，未找到时返回空字符串
+func (idx *StockIndex) Name(symbol string) string {
+	info, _ := idx.Lookup(symbol)
+	return info.Name
+}
+
+// Industry 获取股票所属行业，未找到时返回空字符串
+func (idx *StockIndex) Industry(symbol string) string {
+	info, _ := idx.Lookup(symbol)
+	return info.Industry
+}
+
+// Search 按代码、名称、拼音首字母、全拼搜索，结果按匹配度排序
+// 匹配优先级：完全匹配 > 前缀匹配 > 包含匹配 > 拼音匹配 > 名称模糊（字符按序出现）
+func (idx *StockIndex) Search(keyword string, limit int) []StockBasicInfo {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" || limit <= 0 {
+		return nil
+	}
+	upper := strings.ToUpper(keyword)
+	lower := strings.ToLower(keyword)
+	letters := isLetters(keyword)
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	type scored struct {
+		info  StockBasicInfo
+		score int
+	}
+	var matches []scored
+	for _, info := range idx.items {
+		if score := searchScore(info, upper, lower, letters); score > 0 {
+			matches = append(matches, scored{info, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		if li, lj := len(matches[i].info.Name), len(matches[j].info.Name); li != lj {
+			return li < lj
+		}
+		return matches[i].info.Code < matches[j].info.Code
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	results := make([]StockBasicInfo, len(matches))
+	for i, m := range matches {
+		results[i] = m.info
+	}
+	return results
+}
+
+// searchScore 计算单只股票的匹配分数，0 表示不匹配
+func searchScore(info StockBasicInfo, upper, lower string, letters bool) int {
+	name := strings.ToUpper(info.Name)
+	switch {
+	case info.Code == upper || name == upper || info.Spell == upper:
+		return 100
+	case strings.HasPrefix(info.Code, upper):
+		return 90
+	case strings.HasPrefix(name, upper):
+		return 85
+	case letters && strings.HasPrefix(info.Spell, upper):
+		return 80
+	case strings.Contains(info.Code, upper) || strings.Contains(name, upper):
+		return 70
+	case letters && strings.Contains(info.Spell, upper):
+		return 65
+	}
+	if letters {
+		switch matchPinyin(lower, info.Spell) {
+		case -1:
+		case 0:
+			return 60
+		default:
+			return 50
+		}
+	}
+	if !letters && isSubsequence(upper, name) {
+		return 30
+	}
+	return 0
+}
+
+// All 返回全部股票基础信息的副本
+func (idx *StockIndex) All() []StockBasicInfo {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return append([]StockBasicInfo(nil), idx.items...)
+}
+
+// Len 返回索引中的股票数量
+func (idx *StockIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.items)
+}
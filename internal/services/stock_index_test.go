@@ -0,0 +1,140 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestStockIndexLookup 测试股票索引查询
+func TestStockIndexLookup(t *testing.T) {
+	idx := GetStockIndex()
+	if idx.Len() == 0 {
+		t.Fatal("股票索引为空")
+	}
+
+	for _, symbol := range []string{"sz000001", "000001", "000001.SZ", "SZ000001"} {
+		info, ok := idx.Lookup(symbol)
+		if !ok {
+			t.Fatalf("未找到 %s", symbol)
+		}
+		if info.Symbol != "sz000001" || info.Name != "平安银行" || info.Industry != "银行" {
+			t.Errorf("%s 查询结果不符: %+v", symbol, info)
+		}
+	}
+
+	if _, ok := idx.Lookup("sh999999"); ok {
+		t.Error("不存在的代码不应命中")
+	}
+	// 带市场前缀时不按纯代码回退：sh000001（上证指数）不能查到 sz000001（平安银行）
+	for _, symbol := range []string{"sh000001", "000001.SH"} {
+		if info, ok := idx.Lookup(symbol); ok && info.Symbol != "sh000001" {
+			t.Errorf("%s 错误命中 %+v", symbol, info)
+		}
+	}
+}
+
+// TestStockIndexSearch 测试股票索引搜索
+func TestStockIndexSearch(t *testing.T) {
+	results := GetStockIndex().Search("平安", 5)
+	if len(results) == 0 {
+		t.Fatal("搜索结果为空")
+	}
+	if len(results) > 5 {
+		t.Errorf("搜索结果超过限制: %d", len(results))
+	}
+}
+
+// TestStockIndexOverlay 测试最新股票列表覆盖
+func TestStockIndexOverlay(t *testing.T) {
+	idx := &StockIndex{}
+	if err := idx.Load([]byte(`{"data":{"fields":["ts_code","symbol","name","industry"],"items":[
+		["600001.SH","600001","旧名称","银行"],
+		["830799.BJ","830799","艾融软件","软件服务"]
+	]}}`)); err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+
+	updated, added := idx.Overlay([]StockBasicInfo{
+		{Symbol: "sh600001", Code: "600001", Name: "新名称", Industry: "银行Ⅱ"},
+		{Symbol: "bj830799", Code: "830799", Name: "艾融软件", Market: "北京"},
+		{Symbol: "sh688999", Code: "688999", Name: "新股上市", Industry: "半导体", Market: "上海"},
+	})
+	if updated != 1 || added != 1 {
+		t.Errorf("updated=%d added=%d, want 1 1", updated, added)
+	}
+	if info, _ := idx.Lookup("sh600001"); info.Name != "新名称" || info.Industry != "银行" {
+		t.Errorf("rename should keep baseline industry: %+v", info)
+	}
+	if info, ok := idx.Lookup("bj830799"); !ok || info.Market != "北京" {
+		t.Errorf("bj stock should gain prefix: %+v", info)
+	}
+	if idx.Name("688999") != "新股上市" || idx.Len() != 3 {
+		t.Errorf("new listing not added, len=%d", idx.Len())
+	}
+}
+
+// TestParseStockList 测试东方财富股票列表解析
+func TestParseStockList(t *testing.T) {
+	body := []byte(`{"data":{"total":2,"diff":[
+		{"f12":"600519","f13":1,"f14":"贵州茅台","f100":"酿酒行业"},
+		{"f12":"920001","f13":0,"f14":"纬达光电","f100":"-"}
+	]}}`)
+	items, total, err := parseStockList(body)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if total != 2 || len(items) != 2 {
+		t.Fatalf("total=%d len=%d", total, len(items))
+	}
+	if items[0].Symbol != "sh600519" || items[0].Market != "上海" {
+		t.Errorf("unexpected sh item: %+v", items[0])
+	}
+	if items[1].Symbol != "bj920001" || items[1].Industry != "" {
+		t.Errorf("unexpected bj item: %+v", items[1])
+	}
+}
+
+// TestStockIndexPinyinSearch 测试拼音首字母、全拼、混拼与模糊搜索
+func TestStockIndexPinyinSearch(t *testing.T) {
+	idx := GetStockIndex()
+	for _, kw := range []string{"gzmt", "GZMT", "guizhoumaotai", "gzmaotai", "贵茅"} {
+		results := idx.Search(kw, 5)
+		if len(results) == 0 || results[0].Symbol != "sh600519" {
+			t.Errorf("%s 首个结果应为贵州茅台, got %+v", kw, results)
+		}
+	}
+
+	// 末尾音节未输入完整时，拼音首字母相同的股票一并返回
+	found := false
+	for _, r := range idx.Search("guizhoumao", 10) {
+		found = found || r.Symbol == "sh600519"
+	}
+	if !found {
+		t.Error("guizhoumao 应包含贵州茅台")
+	}
+
+	// 前缀匹配优先于包含匹配
+	results := idx.Search("平安", 5)
+	if len(results) == 0 || !strings.HasPrefix(results[0].Name, "平安") {
+		t.Errorf("前缀匹配应排在最前: %+v", results)
+	}
+}
+
+func TestMatchPinyin(t *testing.T) {
+	cases := []struct {
+		query, spell string
+		want         int
+	}{
+		{"gzmt", "GZMT", 0},
+		{"maotai", "GZMT", 2},
+		{"zhoumt", "GZMT", 1},
+		{"xian", "XA", 0}, // 西安：xi + an
+		{"gzmtx", "GZMT", -1},
+		{"gzmx", "GZMT", -1},
+	}
+	for _, c := range cases {
+		if got := matchPinyin(c.query, c.spell); got != c.want {
+			t.Errorf("matchPinyin(%q, %q) = %d, want %d", c.query, c.spell, got, c.want)
+		}
+	}
+}
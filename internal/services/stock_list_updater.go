@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+var stockListLog = logger.New("stocklist")
+
+// 东方财富沪深京A股列表（f12代码 f13市场 f14名称 f100行业）
+const emStockListURL = "https://push2.eastmoney.com/api/qt/clist/get?pn=%d&pz=%d&po=0&np=1&fltt=2&invt=2&fid=f12&fs=m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23,m:0+t:81+s:2048&fields=f12,f13,f14,f100"
+
+const (
+	stockListFile       = "stock_list.json"
+	stockListPageSize   = 500
+	stockListMaxAge     = 24 * time.Hour // 超过该时长重新拉取
+	stockListCheckEvery = 1 * time.Hour
+)
+
+// stockListSnapshot 持久化的最新股票列表
+type stockListSnapshot struct {
+	UpdatedAt int64            `json:"updatedAt"`
+	Items     []StockBasicInfo `json:"items"`
+}
+
+// StockListUpdater 股票列表自动更新：定期拉取最新A股列表，持久化到数据目录并覆盖到嵌入的基础数据上
+type StockListUpdater struct {
+	path      string
+	client    *http.Client
+	updatedAt time.Time
+	cancel    context.CancelFunc
+	mu        sync.Mutex
+}
+
+// NewStockListUpdater 创建股票列表更新器
+func NewStockListUpdater(dataDir string) *StockListUpdater {
+	return &StockListUpdater{
+		path:   filepath.Join(dataDir, stockListFile),
+		client: proxy.GetManager().GetClientWithTimeout(15 * time.Second),
+	}
+}
+
+// Start 加载本地快照并启动后台更新
+func (u *StockListUpdater) Start(ctx context.Context) {
+	u.mu.Lock()
+	if u.cancel != nil {
+		u.mu.Unlock()
+		return
+	}
+	ctx, u.cancel = context.WithCancel(ctx)
+	u.mu.Unlock()
+
+	u.loadSnapshot()
+	go u.loop(ctx)
+}
+
+// Stop 停止后台更新
+func (u *StockListUpdater) Stop() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.cancel != nil {
+		u.cancel()
+		u.cancel = nil
+	}
+}
+
+func (u *StockListUpdater) loop(ctx context.Context) {
+	ticker := time.NewTicker(stockListCheckEvery)
+	defer ticker.Stop()
+
+	for {
+		u.mu.Lock()
+		stale := time.Since(u.updatedAt) >= stockListMaxAge
+		u.mu.Unlock()
+		if stale {
+			if err := u.Update(); err != nil {
+				stockListLog.Warn("更新股票列表失败: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// loadSnapshot 读取本地快照并覆盖到股票索引
+func (u *StockListUpdater) loadSnapshot() {
+	data, err := os.ReadFile(u.path)
+	if err != nil {
+		return
+	}
+	var snap stockListSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		stockListLog.Warn("解析股票列表快照失败: %v", err)
+		return
+	}
+
+	updated, added := GetStockIndex().Overlay(snap.Items)
+	u.mu.Lock()
+	u.updatedAt = time.UnixMilli(snap.UpdatedAt)
+	u.mu.Unlock()
+	stockListLog.Info("加载股票列表快照: 更名%d只, 新增%d只", updated, added)
+}
+
+// Update 立即拉取最新股票列表、持久化并覆盖到股票索引
+func (u *StockListUpdater) Update() error {
+	items, err := u.fetchAll()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	data, err := json.Marshal(stockListSnapshot{UpdatedAt: now.UnixMilli(), Items: items})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(u.path, data, 0644); err != nil {
+		return err
+	}
+
+	updated, added := GetStockIndex().Overlay(items)
+	u.mu.Lock()
+	u.updatedAt = now
+	u.mu.Unlock()
+	stockListLog.Info("股票列表已更新: 共%d只, 更名%d只, 新增%d只", len(items), updated, added)
+	return nil
+}
+
+// fetchAll 分页拉取全部A股
+func (u *StockListUpdater) fetchAll() ([]StockBasicInfo, error) {
+	var all []StockBasicInfo
+	for page := 1; ; page++ {
+		body, err := u.get(fmt.Sprintf(emStockListURL, page, stockListPageSize))
+		if err != nil {
+			return nil, err
+		}
+		items, total, err := parseStockList(body)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) == 0 || len(all) >= total {
+			break
+		}
+	}
+	// 防止接口异常返回过少数据时覆盖
+	if len(all) < 1000 {
+		return nil, fmt.Errorf("股票列表数据异常: 仅%d条", len(all))
+	}
+	return all, nil
+}
+
+func (u *StockListUpdater) get(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", "https://quote.eastmoney.com/")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// parseStockList 解析东方财富股票列表分页响应
+func parseStockList(body []byte) ([]StockBasicInfo, int, error) {
+	var resp struct {
+		Data *struct {
+			Total int `json:"total"`
+			Diff  []struct {
+				Code     string `json:"f12"`
+				Market   int    `json:"f13"`
+				Name     string `json:"f14"`
+				Industry string `json:"f100"`
+			} `json:"diff"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("解析股票列表失败: %w", err)
+	}
+	if resp.Data == nil {
+		return nil, 0, nil
+	}
+
+	items := make([]StockBasicInfo, 0, len(resp.Data.Diff))
+	for _, d := range resp.Data.Diff {
+		if d.Code == "" || d.Name == "" {
+			continue
+		}
+		symbol := emSymbol(d.Code, d.Market)
+		industry := d.Industry
+		if industry == "-" {
+			industry = ""
+		}
+		items = append(items, StockBasicInfo{
+			Symbol:   symbol,
+			Code:     d.Code,
+			Name:     d.Name,
+			Industry: industry,
+			Market:   stockMarketName(symbol),
+		})
+	}
+	return items, resp.Data.Total, nil
+}
+
+// stockMarketName 代码前缀对应的交易所名称
+func stockMarketName(symbol string) string {
+	switch symbol[:2] {
+	case "sh":
+		return "上海"
+	case "sz":
+		return "深圳"
+	case "bj":
+		return "北京"
+	}
+	return ""
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+// 个股新闻来源
+const (
+	eastMoneyNewsSearchURL = "https://search-api-web.eastmoney.com/search/jsonp?cb=jQuery&param=%s"
+	sinaStockNewsURL       = "https://vip.stock.finance.sina.com.cn/corp/go.php/vCB_AllNewsStock/symbol/%s.phtml"
+)
+
+const (
+	stockNewsCacheTTL     = 5 * time.Minute
+	defaultStockNewsLimit = 20
+)
+
+var (
+	jsonpRegex    = regexp.MustCompile(`(?s)^[^(]*\((.*)\)[;\s]*$`)
+	htmlTagRegex  = regexp.MustCompile(`<[^>]+>`)
+	newsTitleTrim = strings.NewReplacer(" ", "", "　", "", "“", "", "”", "", "\"", "")
+)
+
+// StockNews 个股新闻
+type StockNews struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Source  string `json:"source"` // 来源: 东方财富 / 新浪财经 / 财联社
+	Time    string `json:"time"`   // 发布时间 YYYY-MM-DD HH:MM
+	URL     string `json:"url"`
+}
+
+// stockNewsCache 个股新闻缓存
+type stockNewsCache struct {
+	data      []StockNews
+	timestamp time.Time
+}
+
+// StockNewsService 个股新闻聚合服务（多来源去重，按时间倒序）
+type StockNewsService struct {
+	client      *http.Client
+	newsService *NewsService // 财联社快讯，按股票名称过滤
+	cache       map[string]*stockNewsCache
+	mu          sync.RWMutex
+}
+
+// NewStockNewsService 创建个股新闻聚合服务
+func NewStockNewsService(newsService *NewsService) *StockNewsService {
+	return &StockNewsService{
+		client:      proxy.GetManager().GetClientWithTimeout(10 * time.Second),
+		newsService: newsService,
+		cache:       make(map[string]*stockNewsCache),
+	}
+}
+
+// GetStockNews 获取提及指定股票的近期新闻，code 为带市场前缀的代码（如 sh600519）
+func (s *StockNewsService) GetStockNews(code string, limit int) ([]StockNews, error) {
+	if limit <= 0 {
+		limit = defaultStockNewsLimit
+	}
+
+	s.mu.RLock()
+	if cached, ok := s.cache[code]; ok && time.Since(cached.timestamp) < stockNewsCacheTTL {
+		s.mu.RUnlock()
+		return headNews(cached.data, limit), nil
+	}
+	s.mu.RUnlock()
+
+	name := GetStockIndex().Name(code)
+	fetchers := []func() ([]StockNews, error){
+		func() ([]StockNews, error) { return s.fetchEastMoney(code, name) },
+		func() ([]StockNews, error) { return s.fetchSina(code) },
+		func() ([]StockNews, error) { return s.fetchTelegraph(name) },
+	}
+
+	results := make([][]StockNews, len(fetchers))
+	errs := make([]error, len(fetchers))
+	var wg sync.WaitGroup
+	for i, fetch := range fetchers {
+		wg.Add(1)
+		go func(i int, fetch func() ([]StockNews, error)) {
+			defer wg.Done()
+			results[i], errs[i] = fetch()
+		}(i, fetch)
+	}
+	wg.Wait()
+
+	var all []StockNews
+	var failed []string
+	for i, r := range results {
+		if errs[i] != nil {
+			failed = append(failed, errs[i].Error())
+			continue
+		}
+		all = append(all, r...)
+	}
+	// 所有来源都失败才报错，部分来源失败不影响结果
+	if len(failed) == len(fetchers) {
+		return nil, fmt.Errorf("获取个股新闻失败: %s", strings.Join(failed, "; "))
+	}
+
+	news := mergeStockNews(all)
+
+	s.mu.Lock()
+	s.cache[code] = &stockNewsCache{data: news, timestamp: time.Now()}
+	s.mu.Unlock()
+	return headNews(news, limit), nil
+}
+
+// fetchEastMoney 东方财富资讯搜索
+func (s *StockNewsService) fetchEastMoney(code, name string) ([]StockNews, error) {
+	keyword := strings.TrimLeft(code, "shzbj")
+	if name != "" {
+		keyword = name
+	}
+	param := fmt.Sprintf(`{"uid":"","keyword":%q,"type":["cmsArticleWebOld"],"client":"web","clientType":"web","clientVersion":"curr","param":{"cmsArticleWebOld":{"searchScope":"default","sort":"time","pageIndex":1,"pageSize":20,"preTag":"","postTag":""}}}`, keyword)
+
+	body, err := s.get(fmt.Sprintf(eastMoneyNewsSearchURL, url.QueryEscape(param)), "https://so.eastmoney.com/")
+	if err != nil {
+		return nil, err
+	}
+	return parseEastMoneyNews(body)
+}
+
+// parseEastMoneyNews 解析东方财富资讯搜索 JSONP 响应
+func parseEastMoneyNews(body []byte) ([]StockNews, error) {
+	m := jsonpRegex.FindSubmatch(body)
+	if m == nil {
+		return nil, fmt.Errorf("东方财富资讯响应格式错误")
+	}
+
+	var resp struct {
+		Result struct {
+			Articles []struct {
+				Date      string `json:"date"`
+				Title     string `json:"title"`
+				Content   string `json:"content"`
+				URL       string `json:"url"`
+				MediaName string `json:"mediaName"`
+			} `json:"cmsArticleWebOld"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(m[1], &resp); err != nil {
+		return nil, fmt.Errorf("解析东方财富资讯失败: %w", err)
+	}
+
+	news := make([]StockNews, 0, len(resp.Result.Articles))
+	for _, a := range resp.Result.Articles {
+		news = append(news, StockNews{
+			Title:   stripTags(a.Title),
+			Summary: stripTags(a.Content),
+			Source:  "东方财富",
+			Time:    normalizeNewsTime(a.Date),
+			URL:     a.URL,
+		})
+	}
+	return news, nil
+}
+
+// fetchSina 新浪财经个股资讯列表
+func (s *StockNewsService) fetchSina(code string) ([]StockNews, error) {
+	body, err := s.get(fmt.Sprintf(sinaStockNewsURL, code), "https://finance.sina.com.cn/")
+	if err != nil {
+		return nil, err
+	}
+	utf8Body, err := io.ReadAll(transform.NewReader(strings.NewReader(string(body)), simplifiedchinese.GBK.NewDecoder()))
+	if err != nil {
+		return nil, err
+	}
+	return parseSinaStockNews(string(utf8Body))
+}
+
+// parseSinaStockNews 解析新浪个股资讯页：.datelist 内每行为「日期 时间 <a>标题</a>」
+func parseSinaStockNews(html string) ([]StockNews, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, err
+	}
+
+	var news []StockNews
+	doc.Find(".datelist ul a").Each(func(_ int, a *goquery.Selection) {
+		title := strings.TrimSpace(a.Text())
+		href, _ := a.Attr("href")
+		if title == "" {
+			return
+		}
+		// 时间位于链接前的文本节点
+		var ts string
+		if prev := a.Get(0).PrevSibling; prev != nil {
+			ts = strings.TrimSpace(strings.ReplaceAll(prev.Data, " ", " "))
+		}
+		news = append(news, StockNews{
+			Title:  title,
+			Source: "新浪财经",
+			Time:   normalizeNewsTime(ts),
+			URL:    href,
+		})
+	})
+	return news, nil
+}
+
+// fetchTelegraph 从财联社快讯中筛选提及该股票的条目
+func (s *StockNewsService) fetchTelegraph(name string) ([]StockNews, error) {
+	if s.newsService == nil || name == "" {
+		return nil, nil
+	}
+	telegraphs, err := s.newsService.GetTelegraphList()
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().Format("2006-01-02")
+	var news []StockNews
+	for _, t := range telegraphs {
+		if !strings.Contains(t.Content, name) {
+			continue
+		}
+		news = append(news, StockNews{
+			Title:  rune2string(t.Content, 60),
+			Source: "财联社",
+			Time:   normalizeNewsTime(today + " " + t.Time),
+			URL:    t.URL,
+		})
+	}
+	return news, nil
+}
+
+func (s *StockNewsService) get(target, referer string) ([]byte, error) {
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", referer)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// mergeStockNews 按标题去重（保留先出现的来源），按时间倒序
+func mergeStockNews(news []StockNews) []StockNews {
+	seen := make(map[string]bool, len(news))
+	merged := make([]StockNews, 0, len(news))
+	for _, n := range news {
+		key := newsTitleTrim.Replace(n.Title)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, n)
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Time > merged[j].Time })
+	return merged
+}
+
+// normalizeNewsTime 统一为 YYYY-MM-DD HH:MM，无法识别时原样返回
+func normalizeNewsTime(s string) string {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02 15:04")
+		}
+	}
+	return s
+}
+
+func stripTags(s string) string {
+	return strings.TrimSpace(htmlTagRegex.ReplaceAllString(s, ""))
+}
+
+// rune2string 按字符截断，超出部分用省略号
+func rune2string(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
+
+func headNews(news []StockNews, n int) []StockNews {
+	if len(news) > n {
+		return news[:n]
+	}
+	return news
+}
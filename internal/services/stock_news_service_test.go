@@ -0,0 +1,51 @@
+package services
+
+import "testing"
+
+func TestParseEastMoneyNews(t *testing.T) {
+	body := []byte(`jQuery({"code":0,"result":{"cmsArticleWebOld":[
+		{"date":"2024-06-20 10:30:15","title":"<em>贵州茅台</em>发布公告","content":"公司拟每股派现","url":"http://finance.eastmoney.com/a/1.html","mediaName":"证券时报"}
+	]}});`)
+	news, err := parseEastMoneyNews(body)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(news) != 1 {
+		t.Fatalf("got %d items, want 1", len(news))
+	}
+	if news[0].Title != "贵州茅台发布公告" || news[0].Time != "2024-06-20 10:30" || news[0].Source != "东方财富" {
+		t.Errorf("unexpected item: %+v", news[0])
+	}
+}
+
+func TestParseSinaStockNews(t *testing.T) {
+	html := "<div class=\"datelist\"><ul>\n" +
+		"2024-06-20 10:31  <a target='_blank' href='https://finance.sina.com.cn/1.shtml'>茅台分红方案出炉</a><br>\n" +
+		"2024-06-19 09:00  <a target='_blank' href='https://finance.sina.com.cn/2.shtml'>白酒板块走强</a><br>\n" +
+		"</ul></div>"
+	news, err := parseSinaStockNews(html)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(news) != 2 {
+		t.Fatalf("got %d items, want 2", len(news))
+	}
+	if news[0].Time != "2024-06-20 10:31" || news[0].Title != "茅台分红方案出炉" || news[1].Time != "2024-06-19 09:00" {
+		t.Errorf("unexpected items: %+v", news)
+	}
+}
+
+func TestMergeStockNews(t *testing.T) {
+	news := mergeStockNews([]StockNews{
+		{Title: "茅台 分红方案出炉", Source: "东方财富", Time: "2024-06-20 10:30"},
+		{Title: "白酒板块走强", Source: "新浪财经", Time: "2024-06-19 09:00"},
+		{Title: "茅台分红方案出炉", Source: "新浪财经", Time: "2024-06-20 10:31"},
+		{Title: "盘中快讯", Source: "财联社", Time: "2024-06-21 09:35"},
+	})
+	if len(news) != 3 {
+		t.Fatalf("got %d items, want 3 after dedup", len(news))
+	}
+	if news[0].Source != "财联社" || news[1].Source != "东方财富" || news[2].Title != "白酒板块走强" {
+		t.Errorf("unexpected order: %+v", news)
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import "strings"
+
+// pinyinSyllables 普通话全部拼音音节（不含声调），用于把全拼/混拼查询切分为音节
+var pinyinSyllables = func() map[string]bool {
+	const list = "a ai an ang ao " +
+		"ba bai ban bang bao bei ben beng bi bian biao bie bin bing bo bu " +
+		"ca cai can cang cao ce cen ceng cha chai chan chang chao che chen cheng chi chong chou chu chua chuai chuan chuang chui chun chuo ci cong cou cu cuan cui cun cuo " +
+		"da dai dan dang dao de dei den deng di dia dian diao die ding diu dong dou du duan dui dun duo " +
+		"e ei en eng er " +
+		"fa fan fang fei fen feng fo fou fu " +
+		"ga gai gan gang gao ge gei gen geng gong gou gu gua guai guan guang gui gun guo " +
+		"ha hai han hang hao he hei hen heng hong hou hu hua huai huan huang hui hun huo " +
+		"ji jia jian jiang jiao jie jin jing jiong jiu ju juan jue jun " +
+		"ka kai kan kang kao ke kei ken keng kong kou ku kua kuai kuan kuang kui kun kuo " +
+		"la lai lan lang lao le lei leng li lia lian liang liao lie lin ling liu long lou lu lv luan lve lun luo " +
+		"ma mai man mang mao me mei men meng mi mian miao mie min ming miu mo mou mu " +
+		"na nai nan nang nao ne nei nen neng ni nian niang niao nie nin ning niu nong nou nu nv nuan nve nuo " +
+		"o ou " +
+		"pa pai pan pang pao pei pen peng pi pian piao pie pin ping po pou pu " +
+		"qi qia qian qiang qiao qie qin qing qiong qiu qu quan que qun " +
+		"ran rang rao re ren reng ri rong rou ru rua ruan rui run ruo " +
+		"sa sai san sang sao se sen seng sha shai shan shang shao she shei shen sheng shi shou shu shua shuai shuan shuang shui shun shuo si song sou su suan sui sun suo " +
+		"ta tai tan tang tao te teng ti tian tiao tie ting tong tou tu tuan tui tun tuo " +
+		"wa wai wan wang wei wen weng wo wu " +
+		"xi xia xian xiang xiao xie xin xing xiong xiu xu xuan xue xun " +
+		"ya yan yang yao ye yi yin ying yo yong you yu yuan yue yun " +
+		"za zai zan zang zao ze zei zen zeng zha zhai zhan zhang zhao zhe zhei zhen zheng zhi zhong zhou zhu zhua zhuai zhuan zhuang zhui zhun zhuo zi zong zou zu zuan zui zun zuo"
+	m := make(map[string]bool, 420)
+	for _, s := range strings.Fields(list) {
+		m[s] = true
+	}
+	return m
+}()
+
+// pinyinPrefixes 音节前缀集合，用于支持输入到一半的末尾音节（如 "guizhoumao"）
+var pinyinPrefixes = func() map[string]bool {
+	m := make(map[string]bool, 1200)
+	for s := range pinyinSyllables {
+		for i := 1; i <= len(s); i++ {
+			m[s[:i]] = true
+		}
+	}
+	return m
+}()
+
+const maxSyllableLen = 6 // 最长音节如 zhuang/shuang/chuang
+
+// matchPinyin 判断小写字母查询能否按拼音匹配首字母串 spell（大写）的某一段
+// 查询可以是首字母（gzmt）、全拼（guizhoumaotai）或混拼（gzmaotai），末尾音节允许不完整
+// 返回匹配起始位置，-1 表示不匹配
+func matchPinyin(query, spell string) int {
+	if query == "" || spell == "" {
+		return -1
+	}
+	spell = strings.ToLower(spell)
+	for start := 0; start < len(spell); start++ {
+		if spell[start] == query[0] && matchPinyinAt(query, spell[start:]) {
+			return start
+		}
+	}
+	return -1
+}
+
+// matchPinyinAt 从 spell 开头匹配：查询切分出的每个音节（或单个首字母）对应 spell 的一个字母
+func matchPinyinAt(query, spell string) bool {
+	if query == "" {
+		return true
+	}
+	if spell == "" || query[0] != spell[0] {
+		return false
+	}
+	// 优先尝试较长的音节，减少回溯
+	for n := min(maxSyllableLen, len(query)); n >= 1; n-- {
+		token := query[:n]
+		rest := query[n:]
+		ok := n == 1 || pinyinSyllables[token] || (rest == "" && pinyinPrefixes[token])
+		if ok && matchPinyinAt(rest, spell[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubsequence 判断 query 的字符是否按顺序出现在 s 中（模糊匹配）
+func isSubsequence(query, s string) bool {
+	qr := []rune(query)
+	i := 0
+	for _, r := range s {
+		if i < len(qr) && r == qr[i] {
+			i++
+		}
+	}
+	return i == len(qr)
+}
+
+// isLetters 判断是否全为 ASCII 字母
+func isLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
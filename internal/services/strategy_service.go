@@ -16,6 +16,7 @@ import (
 
 	"github.com/run-bigpig/jcp/internal/logger"
 	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
 )
 
 var strategyLog = logger.New("strategy")
@@ -63,7 +64,7 @@ func getDefaultStrategyAgents() []models.StrategyAgent {
 			Avatar:      "资",
 			Color:       "#F59E0B",
 			Instruction: "你是钱姐，私募圈出身的资金流向专家。你深谙'跟着主力走'的生存法则。\n\n【分析框架】\n1. 主力动向：大单净流入、主力持仓变化\n2. 北向资金：外资流向、重仓股变化\n3. 筹码分布：集中度、套牢盘、获利盘\n4. 盘口异动：大单托盘、压盘信号\n\n【回复风格】直白实在，150字以内。重点说清资金动向和主力意图。",
-			Tools:       []string{"get_orderbook", "get_stock_realtime", "get_kline_data"},
+			Tools:       []string{"get_money_flow", "get_orderbook", "get_stock_realtime", "get_kline_data"},
 			Enabled:     true,
 		},
 		{
@@ -136,6 +137,9 @@ func (s *StrategyService) load() {
 
 	// 确保内置策略存在
 	s.ensureBuiltinStrategies()
+	if s.upgradeUnmodifiedPresetsLocked() {
+		s.saveNoLock()
+	}
 	strategyLog.Info("加载策略配置成功，共 %d 个策略", len(s.store.Strategies))
 }
 
@@ -199,6 +203,16 @@ func (s *StrategyService) GetActiveStrategy() *models.Strategy {
 	return nil
 }
 
+// activeStrategyLocked 返回当前激活策略的指针（需要已持有锁）
+func (s *StrategyService) activeStrategyLocked() *models.Strategy {
+	for i := range s.store.Strategies {
+		if s.store.Strategies[i].ID == s.store.ActiveID {
+			return &s.store.Strategies[i]
+		}
+	}
+	return nil
+}
+
 // GetActiveID 获取当前激活策略ID
 func (s *StrategyService) GetActiveID() string {
 	s.mu.RLock()
@@ -325,6 +339,9 @@ func (s *StrategyService) UpdateAgentInActiveStrategy(agent models.StrategyAgent
 		if st.ID == s.store.ActiveID {
 			for j, a := range st.Agents {
 				if a.ID == agent.ID {
+					// 预设来源与 A/B 变体不在专家配置中编辑，沿用原值
+					agent.PresetID, agent.PresetVersion, agent.PresetHash = a.PresetID, a.PresetVersion, a.PresetHash
+					agent.Variant = a.Variant
 					s.store.Strategies[i].Agents[j] = agent
 					return s.saveNoLock()
 				}
@@ -494,7 +511,7 @@ func (s *StrategyService) callLLM(ctx context.Context, prompt string) (string, e
 		Contents: []*genai.Content{
 			{
 				Role:  "user",
-				Parts: []*genai.Part{{Text: prompt}},
+				Parts: []*genai.Part{{Text: prompt + i18n.ReplyInstruction()}},
 			},
 		},
 	}
@@ -590,6 +607,8 @@ func (s *StrategyService) getAgentConfigsFromStrategy() []models.AgentConfig {
 			MCPServers:  sa.MCPServers,
 			Enabled:     sa.Enabled,
 			AIConfigID:  sa.AIConfigID,
+
+			UnifiedTools: sa.UnifiedTools,
 		}
 	}
 	return agents
@@ -640,6 +659,38 @@ func (s *StrategyService) GetAgentsByIDs(ids []string) []models.AgentConfig {
 	return result
 }
 
+// GetABTestVariants 获取当前策略中专家的 A/B 对比配置：A 为专家本身，B 为套用变体后的配置
+func (s *StrategyService) GetABTestVariants(agentID string) ([2]models.AgentConfig, error) {
+	var variants [2]models.AgentConfig
+	agent := s.GetAgentByID(agentID)
+	if agent == nil {
+		return variants, fmt.Errorf("专家不存在: %s", agentID)
+	}
+	var variant *models.AgentVariant
+	if st := s.GetActiveStrategy(); st != nil {
+		for _, a := range st.Agents {
+			if a.ID == agentID {
+				variant = a.Variant
+			}
+		}
+	}
+	if variant == nil || (variant.Instruction == "" && variant.AIConfigID == "") {
+		return variants, fmt.Errorf("专家 %s 未配置 B 变体", agent.Name)
+	}
+	if variant.Instruction == agent.Instruction && variant.AIConfigID == agent.AIConfigID {
+		return variants, fmt.Errorf("专家 %s 的 B 变体与当前配置相同", agent.Name)
+	}
+
+	variants[0], variants[1] = *agent, *agent
+	if variant.Instruction != "" {
+		variants[1].Instruction = variant.Instruction
+	}
+	if variant.AIConfigID != "" {
+		variants[1].AIConfigID = variant.AIConfigID
+	}
+	return variants, nil
+}
+
 // EnhancePromptInput 提示词增强输入
 type EnhancePromptInput struct {
 	OriginalPrompt string `json:"originalPrompt"` // 原始提示词
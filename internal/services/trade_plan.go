@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 交易计划状态
+const (
+	TradePlanActive    = "active"    // 监控中
+	TradePlanStopped   = "stopped"   // 已触发止损
+	TradePlanDone      = "done"      // 已达到全部目标价
+	TradePlanCancelled = "cancelled" // 用户取消
+)
+
+// 交易计划触发类型
+const (
+	TradePlanHitEntry  = "entry"
+	TradePlanHitStop   = "stop"
+	TradePlanHitTarget = "target"
+)
+
+// tradePlanMaxDeviation 价位偏离现价超过该比例视为误识别（如年份、均线周期）
+const tradePlanMaxDeviation = 0.5
+
+// planNumber 价位数字，后缀单位（如「20日」「5%」）用于排除非价格数字
+const planNumber = `(\d+(?:\.\d+)?)([日天%个成倍周月年])?`
+
+// planGap 关键词与价位之间的间隔（不跨越分句）
+const planGap = `[^0-9\n。，,；;]{0,8}`
+
+var (
+	planStopRegex   = regexp.MustCompile(`止损` + planGap + planNumber)
+	planTargetRegex = regexp.MustCompile(`(?:目标|止盈)` + planGap + planNumber + `(?:\s*[-~～至到/、和]\s*` + planNumber + `)?`)
+	planEntryRegex  = regexp.MustCompile(`(?:买入|建仓|加仓|低吸|入场|介入)` + planGap + planNumber + `(?:\s*[-~～至到]\s*` + planNumber + `)?`)
+	// 价位在前的写法，如「1480-1500 区间低吸」
+	planEntryAfterRegex = regexp.MustCompile(planNumber + `(?:\s*[-~～至到]\s*` + planNumber + `)?\s*元?\s*(?:区间|附近|一带|左右)?\s*(?:买入|建仓|加仓|低吸|入场|介入|布局)`)
+)
+
+// ParseTradePlan 从会议结论中提取交易计划（买入区间、止损、目标价）
+// price 为当前价，用于过滤偏离过大的误识别数字；结论中既无止损也无目标价时返回错误
+func ParseTradePlan(summary string, price float64) (*models.TradePlan, error) {
+	level := func(num, suffix string) float64 {
+		if num == "" || suffix != "" {
+			return 0
+		}
+		v, err := strconv.ParseFloat(num, 64)
+		if err != nil || v <= 0 {
+			return 0
+		}
+		if price > 0 && math.Abs(v/price-1) > tradePlanMaxDeviation {
+			return 0
+		}
+		return v
+	}
+
+	plan := &models.TradePlan{Status: TradePlanActive, CreatedAt: time.Now().UnixMilli()}
+	for _, m := range planStopRegex.FindAllStringSubmatch(summary, -1) {
+		if v := level(m[1], m[2]); v > 0 {
+			plan.StopLoss = v
+			break
+		}
+	}
+	for _, m := range planTargetRegex.FindAllStringSubmatch(summary, -1) {
+		for _, v := range []float64{level(m[1], m[2]), level(m[3], m[4])} {
+			if v > plan.StopLoss && !slices.Contains(plan.Targets, v) {
+				plan.Targets = append(plan.Targets, v)
+			}
+		}
+	}
+	slices.Sort(plan.Targets)
+	entries := append(planEntryRegex.FindAllStringSubmatch(summary, -1), planEntryAfterRegex.FindAllStringSubmatch(summary, -1)...)
+	for _, m := range entries {
+		low, high := level(m[1], m[2]), level(m[3], m[4])
+		if low <= 0 {
+			continue
+		}
+		if high <= 0 {
+			high = low
+		}
+		plan.EntryLow, plan.EntryHigh = min(low, high), max(low, high)
+		break
+	}
+
+	if plan.StopLoss == 0 && len(plan.Targets) == 0 {
+		return nil, fmt.Errorf("会议结论中未找到止损或目标价位")
+	}
+
+	excerpt := []rune(summary)
+	if len(excerpt) > 100 {
+		excerpt = append(excerpt[:100], []rune("...")...)
+	}
+	plan.Summary = string(excerpt)
+	return plan, nil
+}
+
+// EvaluateTradePlan 按现价检查交易计划，返回本次新触发的价位并更新计划状态
+// 止损优先：跌破止损后计划结束；达到最高目标价后计划完成
+func EvaluateTradePlan(plan *models.TradePlan, price float64, now time.Time) []models.TradePlanHit {
+	if plan == nil || plan.Status != TradePlanActive || price <= 0 {
+		return nil
+	}
+	var hits []models.TradePlanHit
+	record := func(kind string, level float64) {
+		hit := models.TradePlanHit{Kind: kind, Level: level, Price: price, At: now.UnixMilli()}
+		plan.Hits = append(plan.Hits, hit)
+		hits = append(hits, hit)
+	}
+
+	if plan.StopLoss > 0 && price <= plan.StopLoss {
+		record(TradePlanHitStop, plan.StopLoss)
+		plan.Status = TradePlanStopped
+		return hits
+	}
+	for _, target := range plan.Targets {
+		if price >= target && !tradePlanHasHit(plan, TradePlanHitTarget, target) {
+			record(TradePlanHitTarget, target)
+		}
+	}
+	if n := len(plan.Targets); n > 0 && tradePlanHasHit(plan, TradePlanHitTarget, plan.Targets[n-1]) {
+		plan.Status = TradePlanDone
+		return hits
+	}
+	if plan.EntryLow > 0 && price >= plan.EntryLow && price <= plan.EntryHigh && !tradePlanHasHit(plan, TradePlanHitEntry, plan.EntryLow) {
+		record(TradePlanHitEntry, plan.EntryLow)
+	}
+	return hits
+}
+
+func tradePlanHasHit(plan *models.TradePlan, kind string, level float64) bool {
+	return slices.ContainsFunc(plan.Hits, func(h models.TradePlanHit) bool {
+		return h.Kind == kind && h.Level == level
+	})
+}
+
+// TradePlanAlert 交易计划触发提醒
+type TradePlanAlert struct {
+	StockCode string  `json:"stockCode"`
+	StockName string  `json:"stockName"`
+	Kind      string  `json:"kind"`
+	Level     float64 `json:"level"`
+	Price     float64 `json:"price"`
+	Message   string  `json:"message"`
+	Review    bool    `json:"review"` // 是否自动召开复盘会议
+}
+
+// BuildTradePlanAlert 构建触发提醒，止损与目标价触发时按计划设置自动复盘
+func BuildTradePlanAlert(session *models.StockSession, hit models.TradePlanHit) TradePlanAlert {
+	plan := session.TradePlan
+	var event string
+	switch hit.Kind {
+	case TradePlanHitStop:
+		event = fmt.Sprintf("跌破止损价 %.2f", hit.Level)
+	case TradePlanHitTarget:
+		event = fmt.Sprintf("达到目标价 %.2f", hit.Level)
+	default:
+		event = fmt.Sprintf("进入买入区间 %.2f-%.2f", plan.EntryLow, plan.EntryHigh)
+	}
+	return TradePlanAlert{
+		StockCode: session.StockCode,
+		StockName: session.StockName,
+		Kind:      hit.Kind,
+		Level:     hit.Level,
+		Price:     hit.Price,
+		Message:   fmt.Sprintf("%s 现价 %.2f，%s", session.StockName, hit.Price, event),
+		Review:    plan.AutoReview && hit.Kind != TradePlanHitEntry,
+	}
+}
+
+// TradePlanReviewQuery 触发后自动复盘会议的问题
+func TradePlanReviewQuery(alert TradePlanAlert) string {
+	return fmt.Sprintf("交易计划触发：%s。请复盘原计划是否仍然成立，并给出下一步操作建议。", alert.Message)
+}
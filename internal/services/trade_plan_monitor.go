@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tradePlanCheckEvery 交易计划价格检查间隔（仅交易时段）
+const tradePlanCheckEvery = 10 * time.Second
+
+// TradePlanMonitor 交易计划监控：交易时段轮询监控中股票的价格，触发止损/目标/买入区间时回调
+type TradePlanMonitor struct {
+	sessions      *SessionService
+	marketService *MarketService
+	onAlert       func(TradePlanAlert)
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewTradePlanMonitor 创建交易计划监控
+func NewTradePlanMonitor(sessions *SessionService, marketService *MarketService, onAlert func(TradePlanAlert)) *TradePlanMonitor {
+	return &TradePlanMonitor{sessions: sessions, marketService: marketService, onAlert: onAlert}
+}
+
+// Start 加载监控中的计划并启动后台检查
+func (m *TradePlanMonitor) Start(ctx context.Context) {
+	m.mu.Lock()
+	if m.cancel != nil {
+		m.mu.Unlock()
+		return
+	}
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.mu.Unlock()
+
+	m.sessions.LoadTradePlans()
+	go m.loop(ctx)
+}
+
+// Stop 停止后台检查
+func (m *TradePlanMonitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
+func (m *TradePlanMonitor) loop(ctx context.Context) {
+	ticker := time.NewTicker(tradePlanCheckEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if m.marketService.GetMarketStatus().Status == "trading" {
+				safeCall(m.check)
+			}
+		}
+	}
+}
+
+// check 拉取监控中股票的实时价格并检查计划
+func (m *TradePlanMonitor) check() {
+	codes := m.sessions.ActiveTradePlanCodes()
+	if len(codes) == 0 {
+		return
+	}
+	stocks, err := m.marketService.GetStockRealTimeData(codes...)
+	if err != nil {
+		return
+	}
+	prices := make(map[string]float64, len(stocks))
+	for _, s := range stocks {
+		if s.Price > 0 {
+			prices[s.Symbol] = s.Price
+		}
+	}
+	for _, alert := range m.sessions.CheckTradePlans(prices) {
+		if m.onAlert != nil {
+			m.onAlert(alert)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestParseTradePlan(t *testing.T) {
+	summary := "核心结论：可以逢低布局。建议在 1480-1500 区间低吸，止损设在 1420，跌破20日线离场；" +
+		"第一目标 1600，第二目标价 1680。"
+	plan, err := ParseTradePlan(summary, 1520)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.StopLoss != 1420 || plan.EntryLow != 1480 || plan.EntryHigh != 1500 {
+		t.Errorf("unexpected levels: %+v", plan)
+	}
+	if !slices.Equal(plan.Targets, []float64{1600, 1680}) || plan.Status != TradePlanActive {
+		t.Errorf("unexpected targets: %+v", plan)
+	}
+
+	// 带单位的数字（均线周期、百分比）与偏离现价过大的数字不视为价位
+	plan, err = ParseTradePlan("止损参考20日线，目标涨幅 15%，止盈 12.5~13", 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.StopLoss != 0 || !slices.Equal(plan.Targets, []float64{12.5, 13}) {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+
+	if _, err := ParseTradePlan("建议继续持有观望，等待业绩落地。", 10); err == nil {
+		t.Error("summary without levels should fail")
+	}
+}
+
+func TestEvaluateTradePlan(t *testing.T) {
+	now := time.Now()
+	plan := &models.TradePlan{EntryLow: 9.5, EntryHigh: 10, StopLoss: 9, Targets: []float64{11, 12}, Status: TradePlanActive}
+
+	if hits := EvaluateTradePlan(plan, 10.5, now); len(hits) != 0 {
+		t.Errorf("no level crossed: %+v", hits)
+	}
+	if hits := EvaluateTradePlan(plan, 9.8, now); len(hits) != 1 || hits[0].Kind != TradePlanHitEntry {
+		t.Errorf("expected entry hit: %+v", hits)
+	}
+	// 同一价位不重复提醒
+	if hits := EvaluateTradePlan(plan, 9.7, now); len(hits) != 0 {
+		t.Errorf("entry should only fire once: %+v", hits)
+	}
+	if hits := EvaluateTradePlan(plan, 11.2, now); len(hits) != 1 || hits[0].Level != 11 || plan.Status != TradePlanActive {
+		t.Errorf("expected first target: %+v, status %s", hits, plan.Status)
+	}
+	if hits := EvaluateTradePlan(plan, 12.3, now); len(hits) != 1 || plan.Status != TradePlanDone {
+		t.Errorf("expected plan done: %+v, status %s", hits, plan.Status)
+	}
+	if hits := EvaluateTradePlan(plan, 8, now); hits != nil {
+		t.Errorf("finished plan should not fire: %+v", hits)
+	}
+
+	stop := &models.TradePlan{StopLoss: 9, Targets: []float64{11}, Status: TradePlanActive, AutoReview: true}
+	hits := EvaluateTradePlan(stop, 8.9, now)
+	if len(hits) != 1 || hits[0].Kind != TradePlanHitStop || stop.Status != TradePlanStopped {
+		t.Fatalf("expected stop hit: %+v, status %s", hits, stop.Status)
+	}
+	alert := BuildTradePlanAlert(&models.StockSession{StockCode: "sh600000", StockName: "浦发银行", TradePlan: stop}, hits[0])
+	if !alert.Review || alert.Message != "浦发银行 现价 8.90，跌破止损价 9.00" {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+}
+
+func TestSessionTradePlan(t *testing.T) {
+	ss := NewSessionService(t.TempDir())
+	if _, err := ss.GetOrCreateSession("sh600000", "浦发银行"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ss.CreateTradePlan("sh600000", 10, false); err == nil {
+		t.Error("no summary yet, expected error")
+	}
+	ss.AddMessage("sh600000", models.ChatMessage{AgentID: "moderator", MsgType: "summary", Content: "止损 9.2，目标 11"})
+	if _, err := ss.CreateTradePlan("sh600000", 10, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重新加载后仍在监控
+	reloaded := &SessionService{sessionsDir: ss.sessionsDir, sessions: make(map[string]*models.StockSession)}
+	reloaded.LoadTradePlans()
+	if codes := reloaded.ActiveTradePlanCodes(); !slices.Equal(codes, []string{"sh600000"}) {
+		t.Fatalf("active codes: %v", codes)
+	}
+	alerts := reloaded.CheckTradePlans(map[string]float64{"sh600000": 9.1})
+	if len(alerts) != 1 || !alerts[0].Review {
+		t.Fatalf("unexpected alerts: %+v", alerts)
+	}
+	if codes := reloaded.ActiveTradePlanCodes(); len(codes) != 0 {
+		t.Errorf("stopped plan should leave monitoring: %v", codes)
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/embed"
+	"github.com/run-bigpig/jcp/internal/pkg/paths"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+const (
+	holidayCDNURL         = "https://cdn.jsdelivr.net/gh/NateScarlet/holiday-cn@master/%d.json"
+	holidayUpdateInterval = 24 * time.Hour // 后台检查节假日数据更新的间隔
+	holidayFileMaxAge     = 7 * 24 * time.Hour
+)
+
+// cst A 股所在时区 UTC+8，使用固定时区避免 Windows 缺少时区数据库
+var cst = time.FixedZone("CST", 8*60*60)
+
+// holidayData 节假日数据结构（holiday-cn 格式）
+type holidayData struct {
+	Year int          `json:"year"`
+	Days []holidayDay `json:"days"`
+}
+
+type holidayDay struct {
+	Name     string `json:"name"`
+	Date     string `json:"date"`
+	IsOffDay bool   `json:"isOffDay"`
+}
+
+// TradingCalendar A 股交易日历（单例）
+// 以嵌入的休市表为基础，合并缓存目录与 CDN 获取的节假日数据；后台定期更新，判断状态时不访问网络
+type TradingCalendar struct {
+	mu       sync.RWMutex
+	closures map[string]closure // 休市日期 -> 节假日
+
+	client *http.Client
+}
+
+// closure 休市日，year 为数据所属年份（跨年假期的日期可能属于相邻年份的数据）
+type closure struct {
+	name string
+	year int
+}
+
+var (
+	tradingCalendarInstance *TradingCalendar
+	tradingCalendarOnce     sync.Once
+)
+
+// GetTradingCalendar 获取交易日历单例，首次调用时启动后台更新
+func GetTradingCalendar() *TradingCalendar {
+	tradingCalendarOnce.Do(func() {
+		tradingCalendarInstance = newTradingCalendar()
+		if err := tradingCalendarInstance.merge(embed.HolidaysJSON); err != nil {
+			log.Error("加载内置休市日历失败: %v", err)
+		}
+		tradingCalendarInstance.loadCacheFiles()
+		go tradingCalendarInstance.updateLoop()
+	})
+	return tradingCalendarInstance
+}
+
+func newTradingCalendar() *TradingCalendar {
+	return &TradingCalendar{
+		closures: make(map[string]closure),
+		client:   proxy.GetManager().GetClientWithTimeout(10 * time.Second),
+	}
+}
+
+// merge 合并节假日数据：单个年份对象或年份数组均可；只记录休息日，调休上班的周末同样不开市。
+// 数据覆盖的年份整体替换，官方调整后被取消的休市日不会残留
+func (c *TradingCalendar) merge(data []byte) error {
+	var list []holidayData
+	if err := json.Unmarshal(data, &list); err != nil {
+		var single holidayData
+		if err := json.Unmarshal(data, &single); err != nil {
+			return err
+		}
+		list = []holidayData{single}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, hd := range list {
+		for date, cl := range c.closures {
+			if cl.year == hd.Year {
+				delete(c.closures, date)
+			}
+		}
+		for _, day := range hd.Days {
+			if day.IsOffDay {
+				c.closures[day.Date] = closure{name: day.Name, year: hd.Year}
+			}
+		}
+	}
+	return nil
+}
+
+// IsTradeDay 判断指定日期是否为交易日，非交易日返回节假日名称（周末为"周末"）
+func (c *TradingCalendar) IsTradeDay(date time.Time) (bool, string) {
+	date = date.In(cst)
+	c.mu.RLock()
+	cl, closed := c.closures[date.Format("2006-01-02")]
+	c.mu.RUnlock()
+	if closed {
+		return false, cl.name
+	}
+	if weekday := date.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		return false, "周末"
+	}
+	return true, ""
+}
+
+// NextTradeDay 返回 date 之后（不含当天）的第一个交易日
+func (c *TradingCalendar) NextTradeDay(date time.Time) time.Time {
+	day := date.In(cst)
+	for range 30 { // 最长假期也不会超过 30 天，防止数据异常时死循环
+		day = day.AddDate(0, 0, 1)
+		if ok, _ := c.IsTradeDay(day); ok {
+			break
+		}
+	}
+	return day
+}
+
+// Status 计算指定时刻的市场状态；收盘后与休市时给出下一交易日
+func (c *TradingCalendar) Status(now time.Time) MarketStatus {
+	now = now.In(cst)
+	isTradeDay, holidayName := c.IsTradeDay(now)
+	if !isTradeDay {
+		statusText := "休市"
+		if holidayName != "" {
+			statusText = holidayName + "休市"
+		}
+		return MarketStatus{
+			Status:       "closed",
+			StatusText:   statusText,
+			IsTradeDay:   false,
+			HolidayName:  holidayName,
+			NextTradeDay: c.NextTradeDay(now).Format("2006-01-02"),
+		}
+	}
+
+	// A股交易时间: 9:30-11:30, 13:00-15:00
+	currentMinutes := now.Hour()*60 + now.Minute()
+	switch {
+	case currentMinutes < 9*60+15:
+		return MarketStatus{Status: "pre_market", StatusText: "盘前", IsTradeDay: true}
+	case currentMinutes < 9*60+30:
+		return MarketStatus{Status: "pre_market", StatusText: "集合竞价", IsTradeDay: true}
+	case currentMinutes < 11*60+30:
+		return MarketStatus{Status: "trading", StatusText: "交易中", IsTradeDay: true}
+	case currentMinutes < 13*60:
+		return MarketStatus{Status: "lunch_break", StatusText: "午间休市", IsTradeDay: true}
+	case currentMinutes < 15*60:
+		return MarketStatus{Status: "trading", StatusText: "交易中", IsTradeDay: true}
+	default:
+		return MarketStatus{Status: "closed", StatusText: "已收盘", IsTradeDay: true, NextTradeDay: c.NextTradeDay(now).Format("2006-01-02")}
+	}
+}
+
+// getHolidayCacheFile 获取节假日缓存文件路径
+func getHolidayCacheFile(year int) string {
+	return filepath.Join(paths.EnsureCacheDir("holiday"), fmt.Sprintf("%d.json", year))
+}
+
+// loadCacheFiles 合并缓存目录中之前下载的节假日文件
+func (c *TradingCalendar) loadCacheFiles() {
+	files, _ := filepath.Glob(filepath.Join(paths.EnsureCacheDir("holiday"), "*.json"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if err := c.merge(data); err != nil {
+			log.Warn("解析节假日缓存 %s 失败: %v", filepath.Base(file), err)
+		}
+	}
+}
+
+// updateLoop 后台更新今明两年的节假日数据（次年安排通常在年底公布）
+func (c *TradingCalendar) updateLoop() {
+	ticker := time.NewTicker(holidayUpdateInterval)
+	defer ticker.Stop()
+	for {
+		year := time.Now().In(cst).Year()
+		for _, y := range []int{year, year + 1} {
+			if !c.needsUpdate(y) {
+				continue
+			}
+			if err := c.Update(y); err != nil {
+				log.Debug("更新 %d 年节假日数据失败: %v", y, err)
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// needsUpdate 缓存文件不存在或已超过有效期时需要更新
+func (c *TradingCalendar) needsUpdate(year int) bool {
+	info, err := os.Stat(getHolidayCacheFile(year))
+	return err != nil || time.Since(info.ModTime()) > holidayFileMaxAge
+}
+
+// Update 从 CDN 获取指定年份的节假日数据，写入缓存并合并到日历
+func (c *TradingCalendar) Update(year int) error {
+	resp, err := c.client.Get(fmt.Sprintf(holidayCDNURL, year))
+	if err != nil {
+		return fmt.Errorf("获取节假日数据失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("获取节假日数据失败: HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := c.merge(body); err != nil {
+		return err
+	}
+	if err := os.WriteFile(getHolidayCacheFile(year), body, 0644); err != nil {
+		log.Warn("保存节假日缓存失败: %v", err)
+	}
+	log.Info("已更新 %d 年节假日数据", year)
+	return nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/embed"
+)
+
+func TestTradingCalendarStatus(t *testing.T) {
+	c := newTradingCalendar()
+	if err := c.merge(embed.HolidaysJSON); err != nil {
+		t.Fatalf("merge embedded holidays: %v", err)
+	}
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, cst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	st := c.Status(at("2026-10-02 10:00"))
+	if st.Status != "closed" || st.IsTradeDay || st.HolidayName != "国庆节" || st.NextTradeDay != "2026-10-08" {
+		t.Errorf("national day status = %+v", st)
+	}
+	// 调休上班的周六同样不开市
+	if ok, name := c.IsTradeDay(at("2026-10-10 10:00")); ok || name != "周末" {
+		t.Errorf("make-up Saturday = %v %q", ok, name)
+	}
+	if st := c.Status(at("2026-10-09 10:00")); st.Status != "trading" || st.NextTradeDay != "" {
+		t.Errorf("trading status = %+v", st)
+	}
+	if st := c.Status(at("2026-10-09 15:30")); st.Status != "closed" || !st.IsTradeDay || st.NextTradeDay != "2026-10-12" {
+		t.Errorf("after close status = %+v", st)
+	}
+	if st := c.Status(at("2026-10-09 12:00")); st.Status != "lunch_break" {
+		t.Errorf("lunch status = %+v", st)
+	}
+}
+
+func TestTradingCalendarMergeSingleYear(t *testing.T) {
+	c := newTradingCalendar()
+	data := []byte(`{"year":2027,"days":[{"name":"元旦","date":"2027-01-01","isOffDay":true},{"name":"春节","date":"2027-02-20","isOffDay":false}]}`)
+	if err := c.merge(data); err != nil {
+		t.Fatal(err)
+	}
+	if ok, name := c.IsTradeDay(time.Date(2027, 1, 1, 10, 0, 0, 0, cst)); ok || name != "元旦" {
+		t.Errorf("2027-01-01 = %v %q", ok, name)
+	}
+	if ok, _ := c.IsTradeDay(time.Date(2027, 1, 4, 10, 0, 0, 0, cst)); !ok {
+		t.Error("2027-01-04 should be a trade day")
+	}
+}
+
+func TestTradingCalendarMergeReplacesYear(t *testing.T) {
+	c := newTradingCalendar()
+	old := []byte(`[{"year":2027,"days":[{"name":"元旦","date":"2027-01-01","isOffDay":true},{"name":"元旦","date":"2027-01-04","isOffDay":true}]},` +
+		`{"year":2028,"days":[{"name":"元旦","date":"2027-12-31","isOffDay":true}]}]`)
+	if err := c.merge(old); err != nil {
+		t.Fatal(err)
+	}
+
+	// 2027 年数据更新后取消了 01-04 休市；2028 年数据（含跨年日期）不受影响
+	updated := []byte(`{"year":2027,"days":[{"name":"元旦","date":"2027-01-01","isOffDay":true}]}`)
+	if err := c.merge(updated); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[time.Time]bool{
+		time.Date(2027, 1, 1, 10, 0, 0, 0, cst):   false,
+		time.Date(2027, 1, 4, 10, 0, 0, 0, cst):   true,
+		time.Date(2027, 12, 31, 10, 0, 0, 0, cst): false,
+	}
+	for date, want := range cases {
+		if ok, _ := c.IsTradeDay(date); ok != want {
+			t.Errorf("%s trade day = %v, want %v", date.Format("2006-01-02"), ok, want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+var ttsLog = logger.New("tts")
+
+const (
+	ttsMaxRunes     = 4000            // 单次合成的最大字数（OpenAI 接口上限 4096 字符）
+	ttsTimeout      = 3 * time.Minute // 单次合成超时
+	maxSpeechFiles  = 100             // 最多保留的语音文件数，超出时删除最早的
+	defaultTTSModel = "tts-1"
+	defaultTTSVoice = "alloy"
+)
+
+// 朗读前去除的 Markdown 标记与引用编号
+var (
+	speechCodeBlock  = regexp.MustCompile("(?s)```.*?```")
+	speechLink       = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	speechCitation   = regexp.MustCompile(`\[\d+\]`)
+	speechLinePrefix = regexp.MustCompile(`(?m)^\s*(?:#{1,6}\s+|>\s*|[-*+]\s+|\|)`)
+	speechSymbols    = strings.NewReplacer("**", "", "__", "", "`", "", "~~", "", "▍", "", "|", "，", "---", "")
+	speechBlankLine  = regexp.MustCompile(`\n{2,}`)
+	speechFileID     = regexp.MustCompile(`^[0-9a-f]{32}\.(mp3|wav)$`)
+	apiVersionPath   = regexp.MustCompile(`/v\d+[a-z]*$`)
+)
+
+// SpeechResult 合成的语音文件
+type SpeechResult struct {
+	ID       string `json:"id"`   // 文件名，可通过 Load 重新读取
+	Path     string `json:"path"` // 语音文件完整路径
+	MimeType string `json:"mimeType"`
+}
+
+// TTSService 语音朗读：将会议总结、早报等文本合成为语音文件，相同文本与设置复用已合成的文件
+type TTSService struct {
+	dir      string
+	config   func() models.TTSConfig
+	aiConfig func(id string) *models.AIConfig
+	client   func(proxyCfg *models.ProxyConfig) *http.Client // 按 AI 配置的代理创建
+	mu       sync.Mutex
+}
+
+// NewTTSService 创建语音朗读服务，语音文件保存在 dataDir/audio
+// aiConfig 按 ID 查找 openai 引擎使用的 AI 配置，ID 为空时返回默认配置
+func NewTTSService(dataDir string, config func() models.TTSConfig, aiConfig func(id string) *models.AIConfig) *TTSService {
+	return &TTSService{
+		dir:      filepath.Join(dataDir, "audio"),
+		config:   config,
+		aiConfig: aiConfig,
+		client: func(proxyCfg *models.ProxyConfig) *http.Client {
+			return proxy.GetManager().ClientFor(proxyCfg, ttsTimeout)
+		},
+	}
+}
+
+// SpeechText 将 Markdown 文本整理为适合朗读的纯文本，超出长度时截断
+func SpeechText(content string) string {
+	text := speechCodeBlock.ReplaceAllString(content, "")
+	text = speechLink.ReplaceAllString(text, "$1")
+	text = speechCitation.ReplaceAllString(text, "")
+	text = speechLinePrefix.ReplaceAllString(text, "")
+	text = speechSymbols.Replace(text)
+	text = speechBlankLine.ReplaceAllString(strings.TrimSpace(text), "\n")
+	if runes := []rune(text); len(runes) > ttsMaxRunes {
+		text = string(runes[:ttsMaxRunes])
+	}
+	return text
+}
+
+// Synthesize 合成语音文件
+func (s *TTSService) Synthesize(ctx context.Context, content string) (*SpeechResult, error) {
+	text := SpeechText(content)
+	if text == "" {
+		return nil, i18n.NewError("没有可朗读的内容")
+	}
+	cfg := s.config()
+	if cfg.Provider == "" {
+		cfg.Provider = models.TTSProviderSystem
+	}
+	ext := "wav"
+	if cfg.Provider == models.TTSProviderOpenAI {
+		ext = "mp3"
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%v|%s", cfg.Provider, cfg.Model, cfg.Voice, cfg.Speed, text)))
+	result := &SpeechResult{ID: hex.EncodeToString(sum[:16]) + "." + ext, MimeType: speechMimeType(ext)}
+	result.Path = filepath.Join(s.dir, result.ID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if info, err := os.Stat(result.Path); err == nil && info.Size() > 0 {
+		return result, nil
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ttsTimeout)
+	defer cancel()
+	var err error
+	switch cfg.Provider {
+	case models.TTSProviderOpenAI:
+		err = s.synthesizeOpenAI(ctx, cfg, text, result.Path)
+	case models.TTSProviderSystem:
+		err = synthesizeSystem(ctx, cfg, text, result.Path)
+	default:
+		err = fmt.Errorf("不支持的语音朗读引擎: %s", cfg.Provider)
+	}
+	if err != nil {
+		os.Remove(result.Path)
+		return nil, err
+	}
+	s.pruneLocked()
+	return result, nil
+}
+
+// Load 读取已合成的语音文件
+func (s *TTSService) Load(id string) (*SpeechResult, []byte, error) {
+	m := speechFileID.FindStringSubmatch(id)
+	if m == nil {
+		return nil, nil, i18n.NewError("无效的语音文件")
+	}
+	result := &SpeechResult{ID: id, Path: filepath.Join(s.dir, id), MimeType: speechMimeType(m[1])}
+	data, err := os.ReadFile(result.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, i18n.NewError("语音文件不存在")
+		}
+		return nil, nil, err
+	}
+	return result, data, nil
+}
+
+// pruneLocked 按修改时间删除最早的语音文件(需要已持有锁)
+func (s *TTSService) pruneLocked() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil || len(entries) <= maxSpeechFiles {
+		return
+	}
+	type file struct {
+		name    string
+		modTime time.Time
+	}
+	var files []file
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && speechFileID.MatchString(e.Name()) {
+			files = append(files, file{e.Name(), info.ModTime()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for i := 0; i < len(files)-maxSpeechFiles; i++ {
+		os.Remove(filepath.Join(s.dir, files[i].name))
+	}
+}
+
+// speechMimeType 语音文件的 MIME 类型
+func speechMimeType(ext string) string {
+	if ext == "mp3" {
+		return "audio/mpeg"
+	}
+	return "audio/wav"
+}
+
+// synthesizeOpenAI 调用 OpenAI 兼容的 /audio/speech 接口合成 mp3
+func (s *TTSService) synthesizeOpenAI(ctx context.Context, cfg models.TTSConfig, text, path string) error {
+	aiConfig := s.aiConfig(cfg.AIConfigID)
+	if aiConfig == nil {
+		return i18n.NewError("语音朗读未配置可用的 AI 服务")
+	}
+	model, voice := cfg.Model, cfg.Voice
+	if model == "" {
+		model = defaultTTSModel
+	}
+	if voice == "" {
+		voice = defaultTTSVoice
+	}
+	payload := map[string]any{"model": model, "input": text, "voice": voice, "response_format": "mp3"}
+	if cfg.Speed > 0 {
+		payload["speed"] = cfg.Speed
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, openAIAPIBase(aiConfig.BaseURL)+"/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+aiConfig.APIKey)
+	resp, err := s.client(&aiConfig.Proxy).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("语音合成失败: HTTP %d %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// openAIAPIBase 规范化 OpenAI 兼容接口地址，未带版本路径时补 /v1
+func openAIAPIBase(baseURL string) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		return "https://api.openai.com/v1"
+	}
+	if !apiVersionPath.MatchString(baseURL) {
+		baseURL += "/v1"
+	}
+	return baseURL
+}
+
+// synthesizeSystem 调用系统语音合成 wav，文本经标准输入传入
+func synthesizeSystem(ctx context.Context, cfg models.TTSConfig, text, path string) error {
+	cmd, err := systemSpeechCommand(ctx, runtime.GOOS, cfg, path)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = strings.NewReader(text)
+	setSysProcAttr(cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		ttsLog.Warn("系统语音合成失败: %v %s", err, strings.TrimSpace(string(out)))
+		return fmt.Errorf("系统语音合成失败: %w", err)
+	}
+	return nil
+}
+
+// systemSpeechCommand 构造系统语音合成命令：macOS say、Windows SAPI、Linux espeak-ng
+func systemSpeechCommand(ctx context.Context, goos string, cfg models.TTSConfig, path string) (*exec.Cmd, error) {
+	speed := cfg.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	switch goos {
+	case "darwin":
+		voice := cfg.Voice
+		if voice == "" {
+			voice = "Tingting"
+		}
+		return exec.CommandContext(ctx, "say", "-v", voice, "-r", fmt.Sprint(math.Round(175*speed)),
+			"--file-format=WAVE", "--data-format=LEI16@22050", "-o", path, "-f", "-"), nil
+	case "linux":
+		voice := cfg.Voice
+		if voice == "" {
+			voice = "cmn"
+		}
+		return exec.CommandContext(ctx, "espeak-ng", "-v", voice, "-s", fmt.Sprint(math.Round(175*speed)),
+			"-w", path, "--stdin"), nil
+	case "windows":
+		script := "[Console]::InputEncoding = [Text.Encoding]::UTF8; Add-Type -AssemblyName System.Speech; " +
+			"$s = New-Object System.Speech.Synthesis.SpeechSynthesizer; "
+		if cfg.Voice != "" {
+			script += fmt.Sprintf("$s.SelectVoice(%s); ", powerShellString(cfg.Voice))
+		}
+		script += fmt.Sprintf("$s.Rate = %d; $s.SetOutputToWaveFile(%s); $s.Speak([Console]::In.ReadToEnd()); $s.Dispose()",
+			min(max(int(math.Round((speed-1)*10)), -10), 10), powerShellString(path))
+		return exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-Command", script), nil
+	default:
+		return nil, fmt.Errorf("不支持的操作系统: %s", goos)
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// TestSpeechText 测试朗读文本去除 Markdown 标记与引用编号
+func TestSpeechText(t *testing.T) {
+	got := SpeechText("### 结论\n**短线偏多**[1]，详见[公告](https://example.com)。\n\n- 风险：`量能不足`\n\n```\ncode\n```")
+	want := "结论\n短线偏多，详见公告。\n风险：量能不足"
+	if got != want {
+		t.Errorf("SpeechText = %q, want %q", got, want)
+	}
+	if n := len([]rune(SpeechText(strings.Repeat("字", ttsMaxRunes+10)))); n != ttsMaxRunes {
+		t.Errorf("truncated length = %d", n)
+	}
+}
+
+// TestTTSServiceOpenAI 测试 OpenAI 兼容接口合成、相同内容复用与读取
+func TestTTSServiceOpenAI(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/v1/audio/speech" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("unexpected request: %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["model"] != "tts-1" || body["voice"] != "nova" || body["input"] != "早报内容" || body["speed"] != 1.2 {
+			t.Errorf("unexpected body: %v", body)
+		}
+		w.Write([]byte("ID3audio"))
+	}))
+	defer srv.Close()
+
+	cfg := models.TTSConfig{Provider: models.TTSProviderOpenAI, Voice: "nova", Speed: 1.2}
+	svc := NewTTSService(t.TempDir(), func() models.TTSConfig { return cfg }, func(id string) *models.AIConfig {
+		return &models.AIConfig{BaseURL: srv.URL, APIKey: "sk-test"}
+	})
+	svc.client = func(*models.ProxyConfig) *http.Client { return srv.Client() }
+
+	res, err := svc.Synthesize(context.Background(), "**早报内容**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MimeType != "audio/mpeg" || !strings.HasSuffix(res.ID, ".mp3") {
+		t.Errorf("result: %+v", res)
+	}
+	if again, err := svc.Synthesize(context.Background(), "早报内容"); err != nil || again.ID != res.ID || calls != 1 {
+		t.Errorf("same text should reuse file: %+v %v calls=%d", again, err, calls)
+	}
+
+	loaded, data, err := svc.Load(res.ID)
+	if err != nil || string(data) != "ID3audio" || loaded.Path != res.Path {
+		t.Errorf("load: %+v %q %v", loaded, data, err)
+	}
+	if _, _, err := svc.Load("../config.json"); err == nil {
+		t.Error("load should reject invalid id")
+	}
+	if _, err := svc.Synthesize(context.Background(), "```\n```"); err == nil {
+		t.Error("empty speech text should fail")
+	}
+}
+
+// TestSystemSpeechCommand 测试各平台系统语音命令参数
+func TestSystemSpeechCommand(t *testing.T) {
+	cfg := models.TTSConfig{Speed: 1.2}
+	cmd, err := systemSpeechCommand(context.Background(), "darwin", cfg, "/tmp/a.wav")
+	if err != nil || !strings.Contains(strings.Join(cmd.Args, " "), "-v Tingting -r 210") {
+		t.Errorf("darwin: %v %v", cmd, err)
+	}
+	cmd, _ = systemSpeechCommand(context.Background(), "linux", models.TTSConfig{Voice: "zh"}, "/tmp/a.wav")
+	if got := strings.Join(cmd.Args, " "); got != "espeak-ng -v zh -s 175 -w /tmp/a.wav --stdin" {
+		t.Errorf("linux: %s", got)
+	}
+	cmd, _ = systemSpeechCommand(context.Background(), "windows", models.TTSConfig{Voice: "Huihui's", Speed: 0.5}, `C:\a.wav`)
+	script := cmd.Args[len(cmd.Args)-1]
+	if !strings.Contains(script, "SelectVoice('Huihui''s')") || !strings.Contains(script, "$s.Rate = -5") || !strings.Contains(script, `SetOutputToWaveFile('C:\a.wav')`) {
+		t.Errorf("windows: %s", script)
+	}
+	if _, err := systemSpeechCommand(context.Background(), "plan9", cfg, "a.wav"); err == nil {
+		t.Error("unsupported os should fail")
+	}
+}
@@ -1,7 +1,6 @@
 package services
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,8 +10,8 @@ import (
 
 	"github.com/blang/semver"
 	"github.com/run-bigpig/go-github-selfupdate/selfupdate"
+	"github.com/run-bigpig/jcp/internal/eventbus"
 	"github.com/run-bigpig/jcp/internal/logger"
-	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 var updateLog = logger.New("update")
@@ -20,7 +19,7 @@ var updateLog = logger.New("update")
 // UpdateService 更新检测服务
 // 负责从 GitHub Releases 检测和下载更新
 type UpdateService struct {
-	ctx            context.Context
+	bus            eventbus.Bus
 	repoOwner      string // GitHub 仓库所有者
 	repoName       string // GitHub 仓库名称
 	currentVersion string // 当前版本号
@@ -53,8 +52,8 @@ func NewUpdateService(repoOwner, repoName, currentVersion string) *UpdateService
 }
 
 // Startup 在应用启动时调用
-func (u *UpdateService) Startup(ctx context.Context) {
-	u.ctx = ctx
+func (u *UpdateService) Startup(bus eventbus.Bus) {
+	u.bus = bus
 	// 启动时清理旧文件
 	if err := u.CleanupOldFiles(); err != nil {
 		updateLog.Warn("清理旧文件失败: %v", err)
@@ -118,7 +117,7 @@ func (u *UpdateService) CheckForUpdate() UpdateInfo {
 
 // emitProgress 发送更新进度事件
 func (u *UpdateService) emitProgress(status, message string, percent int) {
-	if u.ctx == nil {
+	if u.bus == nil {
 		return
 	}
 	progress := UpdateProgress{
@@ -126,7 +125,7 @@ func (u *UpdateService) emitProgress(status, message string, percent int) {
 		Message: message,
 		Percent: percent,
 	}
-	wailsruntime.EventsEmit(u.ctx, "update:progress", progress)
+	u.bus.Emit("update:progress", progress)
 }
 
 // Update 执行更新（下载并替换当前可执行文件）
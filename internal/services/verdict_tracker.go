@@ -0,0 +1,225 @@
+package services
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+var verdictLog = logger.New("verdict")
+
+const (
+	// VerdictHorizonBars 观点的检验周期（交易日）
+	VerdictHorizonBars = 5
+	// VerdictNeutralBand 中性观点判定为准确的涨跌幅区间(%)
+	VerdictNeutralBand = 3.0
+	// maxVerdictRecords 最多保留的观点记录数，超出时丢弃最早的记录
+	maxVerdictRecords = 5000
+)
+
+// 观点检验结果
+const (
+	VerdictHit  = "hit"
+	VerdictMiss = "miss"
+)
+
+// VerdictRecord 专家的一次观点记录，检验周期结束后按实际涨跌判定是否准确
+type VerdictRecord struct {
+	AgentID    string  `json:"agentId"`
+	AgentName  string  `json:"agentName"`
+	StockCode  string  `json:"stockCode"`
+	Stance     string  `json:"stance"`
+	Confidence int     `json:"confidence"`
+	Price      float64 `json:"price"` // 给出观点时的价格
+	CreatedAt  int64   `json:"createdAt"`
+	Outcome    string  `json:"outcome,omitempty"`   // hit/miss，为空表示尚未检验
+	ReturnPct  float64 `json:"returnPct,omitempty"` // 检验周期内的涨跌幅(%)
+}
+
+// AgentAccuracy 专家观点的历史准确率
+type AgentAccuracy struct {
+	AgentID   string  `json:"agentId"`
+	AgentName string  `json:"agentName"`
+	Total     int     `json:"total"`
+	Evaluated int     `json:"evaluated"`
+	Hits      int     `json:"hits"`
+	Weight    float64 `json:"weight"` // 共识分中的权重
+}
+
+// VerdictTracker 专家观点回测：记录每次会议的多空观点，到期后用日K线检验准确率
+type VerdictTracker struct {
+	path    string
+	records []VerdictRecord
+	mu      sync.RWMutex
+}
+
+// NewVerdictTracker 创建专家观点回测服务
+func NewVerdictTracker(dataDir string) *VerdictTracker {
+	t := &VerdictTracker{path: filepath.Join(dataDir, "verdicts.json")}
+	if data, err := os.ReadFile(t.path); err == nil {
+		if err := json.Unmarshal(data, &t.records); err != nil {
+			verdictLog.Error("解析专家观点记录失败: %v", err)
+		}
+	}
+	return t
+}
+
+// saveLocked 保存观点记录(需要已持有锁)
+func (t *VerdictTracker) saveLocked() error {
+	data, err := json.MarshalIndent(t.records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(t.path, data, 0644)
+}
+
+// Record 记录一场会议中各专家的观点
+func (t *VerdictTracker) Record(stockCode string, price float64, votes []models.ConsensusVote) error {
+	if price <= 0 || len(votes) == 0 {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	for _, v := range votes {
+		t.records = append(t.records, VerdictRecord{
+			AgentID:    v.AgentID,
+			AgentName:  v.AgentName,
+			StockCode:  stockCode,
+			Stance:     v.Stance,
+			Confidence: v.Confidence,
+			Price:      price,
+			CreatedAt:  now,
+		})
+	}
+	if len(t.records) > maxVerdictRecords {
+		t.records = t.records[len(t.records)-maxVerdictRecords:]
+	}
+	return t.saveLocked()
+}
+
+// PendingStocks 存在到期未检验观点的股票
+func (t *VerdictTracker) PendingStocks(now time.Time) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	// 检验周期为交易日，自然日至少要过去同样天数
+	cutoff := now.AddDate(0, 0, -VerdictHorizonBars).UnixMilli()
+	seen := make(map[string]bool)
+	var stocks []string
+	for _, r := range t.records {
+		if r.Outcome == "" && r.CreatedAt <= cutoff && !seen[r.StockCode] {
+			seen[r.StockCode] = true
+			stocks = append(stocks, r.StockCode)
+		}
+	}
+	return stocks
+}
+
+// Evaluate 用日K线检验股票到期的观点，返回本次检验的记录数
+// 以观点日之后第 VerdictHorizonBars 根日K收盘价计算涨跌：看多涨、看空跌、中性涨跌幅在区间内为准确
+func (t *VerdictTracker) Evaluate(stockCode string, dailyKLines []models.KLineData) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	evaluated := 0
+	for i := range t.records {
+		r := &t.records[i]
+		if r.Outcome != "" || r.StockCode != stockCode {
+			continue
+		}
+		day := time.UnixMilli(r.CreatedAt).Format("2006-01-02")
+		n := 0
+		for _, k := range dailyKLines {
+			if k.Time <= day {
+				continue
+			}
+			if n++; n == VerdictHorizonBars {
+				r.ReturnPct = math.Round((k.Close-r.Price)/r.Price*10000) / 100
+				r.Outcome = verdictOutcome(r.Stance, r.ReturnPct)
+				evaluated++
+				break
+			}
+		}
+	}
+	if evaluated == 0 {
+		return 0, nil
+	}
+	return evaluated, t.saveLocked()
+}
+
+// verdictOutcome 判定观点是否准确
+func verdictOutcome(stance string, returnPct float64) string {
+	hit := false
+	switch stance {
+	case models.StanceBull:
+		hit = returnPct > 0
+	case models.StanceBear:
+		hit = returnPct < 0
+	case models.StanceNeutral:
+		hit = math.Abs(returnPct) <= VerdictNeutralBand
+	}
+	if hit {
+		return VerdictHit
+	}
+	return VerdictMiss
+}
+
+// accuracyWeight 准确率权重：按 (命中+1)/(检验+2) 平滑后乘 2，无历史记录时为 1，范围 0~2
+func accuracyWeight(hits, evaluated int) float64 {
+	return math.Round(2*float64(hits+1)/float64(evaluated+2)*100) / 100
+}
+
+// Weight 专家在共识分中的权重
+func (t *VerdictTracker) Weight(agentID string) float64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	hits, evaluated := 0, 0
+	for _, r := range t.records {
+		if r.AgentID != agentID || r.Outcome == "" {
+			continue
+		}
+		evaluated++
+		if r.Outcome == VerdictHit {
+			hits++
+		}
+	}
+	return accuracyWeight(hits, evaluated)
+}
+
+// GetAccuracy 按专家汇总观点准确率
+func (t *VerdictTracker) GetAccuracy() []AgentAccuracy {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	index := make(map[string]int)
+	result := make([]AgentAccuracy, 0)
+	for _, r := range t.records {
+		i, ok := index[r.AgentID]
+		if !ok {
+			i = len(result)
+			index[r.AgentID] = i
+			result = append(result, AgentAccuracy{AgentID: r.AgentID})
+		}
+		acc := &result[i]
+		acc.AgentName = r.AgentName
+		acc.Total++
+		if r.Outcome != "" {
+			acc.Evaluated++
+		}
+		if r.Outcome == VerdictHit {
+			acc.Hits++
+		}
+	}
+	for i := range result {
+		result[i].Weight = accuracyWeight(result[i].Hits, result[i].Evaluated)
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// TestVerdictTracker 测试观点记录、到期检验与准确率权重
+func TestVerdictTracker(t *testing.T) {
+	dir := t.TempDir()
+	tr := NewVerdictTracker(dir)
+
+	if w := tr.Weight("tech"); w != 1 {
+		t.Errorf("weight without history = %v, want 1", w)
+	}
+	err := tr.Record("sh600000", 10, []models.ConsensusVote{
+		{AgentID: "tech", AgentName: "技术派", Stance: models.StanceBull, Confidence: 7},
+		{AgentID: "value", AgentName: "价值派", Stance: models.StanceBear, Confidence: 5},
+		{AgentID: "macro", AgentName: "宏观派", Stance: models.StanceNeutral, Confidence: 5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 未到期不检验
+	if stocks := tr.PendingStocks(time.Now()); len(stocks) != 0 {
+		t.Errorf("pending before horizon: %v", stocks)
+	}
+	if stocks := tr.PendingStocks(time.Now().AddDate(0, 0, 7)); len(stocks) != 1 || stocks[0] != "sh600000" {
+		t.Fatalf("pending after horizon: %v", stocks)
+	}
+
+	day := time.Now()
+	var klines []models.KLineData
+	for i := -1; i <= VerdictHorizonBars; i++ {
+		klines = append(klines, models.KLineData{Time: day.AddDate(0, 0, i).Format("2006-01-02"), Close: 10 + float64(i)*0.1})
+	}
+	if n, err := tr.Evaluate("sh600000", klines[:VerdictHorizonBars]); err != nil || n != 0 {
+		t.Errorf("evaluate with too few bars: %d %v", n, err)
+	}
+	if n, err := tr.Evaluate("sh600000", klines); err != nil || n != 3 {
+		t.Fatalf("evaluate: %d %v", n, err)
+	}
+
+	// 重新加载后检验结果保留：5 日后涨 5%，看多准确，看空与中性（超出 ±3%）不准确
+	tr = NewVerdictTracker(dir)
+	if w := tr.Weight("tech"); w != 1.33 {
+		t.Errorf("tech weight = %v, want 1.33", w)
+	}
+	if w := tr.Weight("value"); w != 0.67 {
+		t.Errorf("value weight = %v, want 0.67", w)
+	}
+	acc := tr.GetAccuracy()
+	if len(acc) != 3 || acc[0].Hits != 1 || acc[2].Evaluated != 1 || acc[2].Hits != 0 {
+		t.Errorf("accuracy: %+v", acc)
+	}
+	if n, _ := tr.Evaluate("sh600000", klines); n != 0 {
+		t.Errorf("evaluated records should not be evaluated again: %d", n)
+	}
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// loadGroups 加载自选股分组
+func (cs *ConfigService) loadGroups() error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	data, err := os.ReadFile(cs.groupsPath)
+	if os.IsNotExist(err) {
+		cs.groups = models.DefaultWatchlistGroups()
+		if cs.deferWrite {
+			return nil
+		}
+		return cs.saveGroupsLocked()
+	}
+	if err != nil {
+		return err
+	}
+
+	var groups []models.WatchlistGroup
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return err
+	}
+	cs.groups = groups
+	return nil
+}
+
+// saveGroupsLocked 保存自选股分组(需要已持有锁)
+func (cs *ConfigService) saveGroupsLocked() error {
+	data, err := json.MarshalIndent(cs.groups, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeConfigFile(cs.groupsPath, data)
+}
+
+// findGroupLocked 查找分组(需要已持有锁)
+func (cs *ConfigService) findGroupLocked(id string) *models.WatchlistGroup {
+	for i := range cs.groups {
+		if cs.groups[i].ID == id {
+			return &cs.groups[i]
+		}
+	}
+	return nil
+}
+
+// removeFromGroupsLocked 将股票移出所有分组，返回是否有变更(需要已持有锁)
+func (cs *ConfigService) removeFromGroupsLocked(symbol string) bool {
+	changed := false
+	for i := range cs.groups {
+		if idx := slices.Index(cs.groups[i].Symbols, symbol); idx >= 0 {
+			cs.groups[i].Symbols = slices.Delete(cs.groups[i].Symbols, idx, idx+1)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// GetWatchlistGroups 获取自选股分组
+func (cs *ConfigService) GetWatchlistGroups() []models.WatchlistGroup {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	groups := make([]models.WatchlistGroup, len(cs.groups))
+	for i, g := range cs.groups {
+		groups[i] = g
+		groups[i].Symbols = slices.Clone(g.Symbols)
+	}
+	return groups
+}
+
+// GroupSymbols 获取分组内的股票代码，「全部」分组返回完整自选股列表
+func (cs *ConfigService) GroupSymbols(groupID string) []string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	if groupID == models.WatchlistGroupAll {
+		codes := make([]string, len(cs.watchlist))
+		for i, s := range cs.watchlist {
+			codes[i] = s.Symbol
+		}
+		return codes
+	}
+	if g := cs.findGroupLocked(groupID); g != nil {
+		return slices.Clone(g.Symbols)
+	}
+	return []string{}
+}
+
+// AddWatchlistGroup 新建自选股分组
+func (cs *ConfigService) AddWatchlistGroup(name string) (models.WatchlistGroup, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.WatchlistGroup{}, fmt.Errorf("分组名称不能为空")
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for _, g := range cs.groups {
+		if g.Name == name {
+			return models.WatchlistGroup{}, fmt.Errorf("分组已存在: %s", name)
+		}
+	}
+	group := models.WatchlistGroup{ID: uuid.New().String(), Name: name, Symbols: []string{}}
+	cs.groups = append(cs.groups, group)
+	return group, cs.saveGroupsLocked()
+}
+
+// RemoveWatchlistGroup 删除自选股分组（组内股票仍保留在自选股中）
+func (cs *ConfigService) RemoveWatchlistGroup(id string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for i, g := range cs.groups {
+		if g.ID == id {
+			cs.groups = slices.Delete(cs.groups, i, i+1)
+			return cs.saveGroupsLocked()
+		}
+	}
+	return fmt.Errorf("分组不存在: %s", id)
+}
+
+// AddToGroup 将自选股加入分组
+func (cs *ConfigService) AddToGroup(groupID, symbol string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	g := cs.findGroupLocked(groupID)
+	if g == nil {
+		return fmt.Errorf("分组不存在: %s", groupID)
+	}
+	if !slices.ContainsFunc(cs.watchlist, func(s models.Stock) bool { return s.Symbol == symbol }) {
+		return fmt.Errorf("股票不在自选股中: %s", symbol)
+	}
+	if slices.Contains(g.Symbols, symbol) {
+		return nil
+	}
+	g.Symbols = append(g.Symbols, symbol)
+	return cs.saveGroupsLocked()
+}
+
+// RemoveFromGroup 将股票移出分组（不影响自选股）
+func (cs *ConfigService) RemoveFromGroup(groupID, symbol string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	g := cs.findGroupLocked(groupID)
+	if g == nil {
+		return fmt.Errorf("分组不存在: %s", groupID)
+	}
+	idx := slices.Index(g.Symbols, symbol)
+	if idx < 0 {
+		return nil
+	}
+	g.Symbols = slices.Delete(g.Symbols, idx, idx+1)
+	return cs.saveGroupsLocked()
+}
+
+// ReorderWatchlist 按拖拽结果保存排序，「全部」分组调整自选股本身的顺序
+// 未出现在 symbols 中的股票保持原有相对顺序追加到末尾，避免前端列表不完整时丢失数据
+func (cs *ConfigService) ReorderWatchlist(groupID string, symbols []string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if groupID == models.WatchlistGroupAll {
+		bySymbol := make(map[string]models.Stock, len(cs.watchlist))
+		current := make([]string, len(cs.watchlist))
+		for i, s := range cs.watchlist {
+			bySymbol[s.Symbol] = s
+			current[i] = s.Symbol
+		}
+		ordered := reorderSymbols(current, symbols)
+		cs.watchlist = make([]models.Stock, len(ordered))
+		for i, code := range ordered {
+			cs.watchlist[i] = bySymbol[code]
+		}
+		return cs.saveWatchlistLocked()
+	}
+
+	g := cs.findGroupLocked(groupID)
+	if g == nil {
+		return fmt.Errorf("分组不存在: %s", groupID)
+	}
+	g.Symbols = reorderSymbols(g.Symbols, symbols)
+	return cs.saveGroupsLocked()
+}
+
+// reorderSymbols 按 order 重排 current，忽略 current 中不存在的代码
+func reorderSymbols(current, order []string) []string {
+	result := make([]string, 0, len(current))
+	seen := make(map[string]bool, len(current))
+	for _, code := range order {
+		if slices.Contains(current, code) && !seen[code] {
+			result = append(result, code)
+			seen[code] = true
+		}
+	}
+	for _, code := range current {
+		if !seen[code] {
+			result = append(result, code)
+		}
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// TestWatchlistGroups 测试分组归属、排序持久化与移除自选股时的分组清理
+func TestWatchlistGroups(t *testing.T) {
+	dir := t.TempDir()
+	cs, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []string{"sh600000", "sz000001", "sz300750"} {
+		if err := cs.AddToWatchlist(models.Stock{Symbol: code}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(cs.GetWatchlistGroups()) != 3 {
+		t.Fatalf("want 3 default groups, got %+v", cs.GetWatchlistGroups())
+	}
+	if err := cs.AddToGroup("holding", "sh999999"); err == nil {
+		t.Error("stock outside watchlist should be rejected")
+	}
+	cs.AddToGroup("holding", "sh600000")
+	cs.AddToGroup("holding", "sz300750")
+
+	// 排序时遗漏的股票追加到末尾，不存在的代码被忽略
+	if err := cs.ReorderWatchlist("holding", []string{"sz300750", "sh999999"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := cs.GroupSymbols("holding"); !slices.Equal(got, []string{"sz300750", "sh600000"}) {
+		t.Errorf("holding order = %v", got)
+	}
+	cs.ReorderWatchlist(models.WatchlistGroupAll, []string{"sz000001", "sh600000", "sz300750"})
+
+	// 重新加载后顺序保持
+	reloaded, err := NewConfigService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.GroupSymbols(models.WatchlistGroupAll); !slices.Equal(got, []string{"sz000001", "sh600000", "sz300750"}) {
+		t.Errorf("watchlist order = %v", got)
+	}
+
+	reloaded.RemoveFromWatchlist("sz300750")
+	if got := reloaded.GroupSymbols("holding"); !slices.Equal(got, []string{"sh600000"}) {
+		t.Errorf("holding after remove = %v", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"runtime/debug"
 
+	"github.com/run-bigpig/jcp/internal/pkg/paths"
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
@@ -26,12 +27,32 @@ func main() {
 		}
 	}()
 
+	// 无界面模式：jcp serve -addr :8765 -token <令牌>
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := runHeadless(os.Args[2:]); err != nil {
+			println("Error:", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 工作区：jcp -profile <名称>
+	if err := paths.SetProfile(paths.ResolveProfile(profileArg(os.Args[1:]))); err != nil {
+		println("Error:", err.Error())
+		os.Exit(1)
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
 
+	title := "韭菜盘"
+	if p := paths.GetProfile(); p != paths.DefaultProfile {
+		title += " - " + p
+	}
+
 	// Create application with options
 	err := wails.Run(&options.App{
-		Title:           "韭菜盘",
+		Title:           title,
 		Width:           1920,
 		Height:          1080,
 		MinWidth:        1366,
@@ -41,6 +62,8 @@ func main() {
 		CSSDragValue:    "drag",
 		AssetServer: &assetserver.Options{
 			Assets: assets,
+			// 嵌入资源之外的请求：服务端生成的图表
+			Handler: app.chartService.FileHandler(),
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
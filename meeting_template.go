@@ -0,0 +1,69 @@
+package main
+
+import (
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/meeting"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+// MeetingTemplateRunResponse 按模板开会的结果，发言同时通过 meeting:message 事件推送
+type MeetingTemplateRunResponse struct {
+	Success  bool                 `json:"success"`
+	Query    string               `json:"query,omitempty"` // 替换占位符后的问题
+	Messages []models.ChatMessage `json:"messages"`
+	Error    string               `json:"error,omitempty"`
+}
+
+// GetMeetingTemplates 获取全部会议模板
+func (a *App) GetMeetingTemplates() []services.MeetingTemplate {
+	return a.templateService.GetTemplates()
+}
+
+// AddMeetingTemplate 新增会议模板
+func (a *App) AddMeetingTemplate(tpl services.MeetingTemplate) string {
+	if _, err := a.templateService.AddTemplate(tpl); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// UpdateMeetingTemplate 更新会议模板
+func (a *App) UpdateMeetingTemplate(tpl services.MeetingTemplate) string {
+	if err := a.templateService.UpdateTemplate(tpl); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// DeleteMeetingTemplate 删除会议模板
+func (a *App) DeleteMeetingTemplate(id string) string {
+	if err := a.templateService.DeleteTemplate(id); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// RunMeetingTemplate 对指定股票按模板开会：替换问题占位符，按模板的专家阵容与开会方式发起会议
+func (a *App) RunMeetingTemplate(templateID, stockCode string) MeetingTemplateRunResponse {
+	tpl, err := a.templateService.GetTemplate(templateID)
+	if err != nil {
+		return MeetingTemplateRunResponse{Error: err.Error()}
+	}
+	session := a.sessionService.GetSession(stockCode)
+	if session == nil {
+		return MeetingTemplateRunResponse{Error: i18n.T("会话不存在")}
+	}
+
+	query := tpl.RenderQuery(session.StockName, stockCode, time.Now())
+	messages := a.sendMeetingMessage(MeetingMessageRequest{
+		StockCode:  stockCode,
+		Content:    query,
+		MentionIds: tpl.AgentIDs,
+		Smart:      tpl.Mode == services.TemplateModeSmart,
+		echoUser:   true,
+	}, meeting.PriorityManual)
+	return MeetingTemplateRunResponse{Success: true, Query: query, Messages: messages}
+}
@@ -0,0 +1,101 @@
+// Package market 提供可被外部 Go 程序复用的 A 股行情能力
+//
+// 该包是对 jcp 内部行情服务的稳定封装，字段含义与桌面端保持一致：
+//
+//	client := market.New()
+//	quotes, err := client.Quotes("sh600519", "sz000001")
+package market
+
+import (
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+// 行情数据类型
+type (
+	Stock       = models.Stock
+	KLineData   = models.KLineData
+	OrderBook   = models.OrderBook
+	MarketIndex = models.MarketIndex
+	StockInfo   = services.StockBasicInfo
+	Status      = services.MarketStatus
+)
+
+// 复权方式
+const (
+	AdjustNone = services.AdjustNone // 不复权
+	AdjustQFQ  = services.AdjustQFQ  // 前复权
+	AdjustHFQ  = services.AdjustHFQ  // 后复权
+)
+
+// K 线周期
+const (
+	Period5Min  = "1m"  // 5分钟线
+	PeriodDay   = "1d"  // 日线
+	PeriodWeek  = "1w"  // 周线
+	PeriodMonth = "1mo" // 月线
+)
+
+// Client 行情客户端
+type Client struct {
+	svc *services.MarketService
+}
+
+// New 创建行情客户端
+func New() *Client {
+	return &Client{svc: services.NewMarketService()}
+}
+
+// Service 返回底层行情服务（供 meeting/tools 包复用同一实例和缓存）
+func (c *Client) Service() *services.MarketService {
+	return c.svc
+}
+
+// Quotes 批量获取实时行情
+func (c *Client) Quotes(codes ...string) ([]Stock, error) {
+	return c.svc.GetStockRealTimeData(codes...)
+}
+
+// Quote 获取单只股票实时行情，未找到时返回 nil
+func (c *Client) Quote(code string) (*Stock, error) {
+	stocks, err := c.svc.GetStockRealTimeData(code)
+	if err != nil || len(stocks) == 0 {
+		return nil, err
+	}
+	return &stocks[0], nil
+}
+
+// KLine 获取不复权 K 线数据
+func (c *Client) KLine(code, period string, days int) ([]KLineData, error) {
+	return c.svc.GetKLineData(code, period, days, AdjustNone)
+}
+
+// AdjustedKLine 获取复权 K 线数据，adjust 取 AdjustNone/AdjustQFQ/AdjustHFQ
+func (c *Client) AdjustedKLine(code, period string, days int, adjust string) ([]KLineData, error) {
+	return c.svc.GetKLineData(code, period, days, adjust)
+}
+
+// OrderBook 获取五档盘口
+func (c *Client) OrderBook(code string) (OrderBook, error) {
+	return c.svc.GetRealOrderBook(code)
+}
+
+// Indices 获取大盘指数
+func (c *Client) Indices() ([]MarketIndex, error) {
+	return c.svc.GetMarketIndices()
+}
+
+// Status 获取当前市场交易状态
+func (c *Client) Status() Status {
+	return c.svc.GetMarketStatus()
+}
+
+// Lookup 按代码查询股票名称/行业等基础信息
+func Lookup(symbol string) (StockInfo, bool) {
+	return services.GetStockIndex().Lookup(symbol)
+}
+
+// Search 按代码或名称搜索股票
+func Search(keyword string, limit int) []StockInfo {
+	return services.GetStockIndex().Search(keyword, limit)
+}
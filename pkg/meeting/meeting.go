@@ -0,0 +1,140 @@
+// Package meeting 提供 jcp 多专家会议能力的公共 API
+//
+// 典型用法：
+//
+//	room, _ := meeting.New(meeting.Options{})
+//	summary, err := room.Ask(ctx, aiConfig, "sh600519", "现在适合加仓吗？", experts)
+package meeting
+
+import (
+	"context"
+	"errors"
+
+	"github.com/run-bigpig/jcp/internal/meeting"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/pkg/market"
+	"github.com/run-bigpig/jcp/pkg/tools"
+)
+
+// 会议相关类型
+type (
+	AIConfig      = models.AIConfig
+	AIProvider    = models.AIProvider
+	AgentConfig   = models.AgentConfig
+	StockPosition = models.StockPosition
+	Response      = meeting.ChatResponse
+	ProgressEvent = meeting.ProgressEvent
+	ContextBudget = models.ContextBudgetConfig
+)
+
+// AI 服务提供商
+const (
+	ProviderOpenAI    = models.AIProviderOpenAI
+	ProviderGemini    = models.AIProviderGemini
+	ProviderVertexAI  = models.AIProviderVertexAI
+	ProviderAnthropic = models.AIProviderAnthropic
+	ProviderMock      = models.AIProviderMock
+)
+
+// ErrStockNotFound 未获取到股票行情
+var ErrStockNotFound = errors.New("未获取到股票行情")
+
+// Options 会议室创建选项
+type Options struct {
+	Market      *market.Client  // 行情客户端（为空时新建）
+	Tools       *tools.Registry // 工具注册中心（为空时创建全部内置工具）
+	ModeratorAI *AIConfig       // 小韭菜使用的模型（为空时与专家共用）
+	Budget      float64         // 单次会议费用上限（美元，0 表示不限制）
+
+	// ContextBudget 专家提示词中记忆、前序发言与工具说明的 token 预算（零值使用默认预算）
+	ContextBudget ContextBudget
+}
+
+// Room 多专家会议室
+type Room struct {
+	svc    *meeting.Service
+	market *market.Client
+}
+
+// New 创建会议室
+func New(opts Options) (*Room, error) {
+	marketClient := opts.Market
+	if marketClient == nil {
+		marketClient = market.New()
+	}
+	registry := opts.Tools
+	if registry == nil {
+		var err error
+		registry, err = tools.New(tools.Options{Market: marketClient})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	svc := meeting.NewServiceFull(registry, nil)
+	if opts.ModeratorAI != nil {
+		svc.SetModeratorAIConfig(opts.ModeratorAI)
+	}
+	svc.SetMeetingBudget(opts.Budget)
+	svc.SetContextBudget(opts.ContextBudget)
+	return &Room{svc: svc, market: marketClient}, nil
+}
+
+// Request 会议请求
+type Request struct {
+	StockCode string         // 股票代码，如 sh600519
+	Query     string         // 用户问题
+	Experts   []AgentConfig  // 可邀请的专家
+	Position  *StockPosition // 用户持仓（可选）
+
+	// CompareCode 对比股票代码（可选），设置后进入双股对比模式，总结输出并列裁决表
+	CompareCode string
+}
+
+// Run 运行智能会议（小韭菜选择专家、专家串行发言、最终总结）
+// onResponse/onProgress 可为 nil
+func (r *Room) Run(ctx context.Context, ai *AIConfig, req Request, onResponse func(Response), onProgress func(ProgressEvent)) ([]Response, error) {
+	chatReq, err := r.buildRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return r.svc.RunSmartMeetingWithCallback(ctx, ai, chatReq, onResponse, onProgress)
+}
+
+// Ask 运行智能会议并只返回小韭菜的最终总结
+func (r *Room) Ask(ctx context.Context, ai *AIConfig, stockCode, query string, experts []AgentConfig) (string, error) {
+	chatReq, err := r.buildRequest(Request{StockCode: stockCode, Query: query, Experts: experts})
+	if err != nil {
+		return "", err
+	}
+	return r.svc.RunSmartMeetingSync(ctx, ai, chatReq)
+}
+
+// buildRequest 拉取实时行情并构建内部会议请求
+func (r *Room) buildRequest(req Request) (meeting.ChatRequest, error) {
+	stock, err := r.market.Quote(req.StockCode)
+	if err != nil {
+		return meeting.ChatRequest{}, err
+	}
+	if stock == nil {
+		return meeting.ChatRequest{}, ErrStockNotFound
+	}
+	chatReq := meeting.ChatRequest{
+		StockCode: req.StockCode,
+		Stock:     *stock,
+		Query:     req.Query,
+		AllAgents: req.Experts,
+		Position:  req.Position,
+	}
+	if req.CompareCode != "" {
+		compare, err := r.market.Quote(req.CompareCode)
+		if err != nil {
+			return meeting.ChatRequest{}, err
+		}
+		if compare == nil {
+			return meeting.ChatRequest{}, ErrStockNotFound
+		}
+		chatReq.CompareStock = compare
+	}
+	return chatReq, nil
+}
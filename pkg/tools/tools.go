@@ -0,0 +1,73 @@
+// Package tools 提供 jcp 内置 Agent 工具集（行情、K线、盘口、快讯、研报、舆情、龙虎榜、涨跌停等）
+//
+// 返回的工具实现了 google.golang.org/adk/tool.Tool 接口，可直接挂载到自定义 ADK Agent 上。
+package tools
+
+import (
+	"context"
+	"path/filepath"
+
+	"google.golang.org/adk/model"
+
+	"github.com/run-bigpig/jcp/internal/adk/tools"
+	"github.com/run-bigpig/jcp/internal/pkg/paths"
+	"github.com/run-bigpig/jcp/internal/services"
+	"github.com/run-bigpig/jcp/internal/services/hottrend"
+	"github.com/run-bigpig/jcp/pkg/market"
+)
+
+// Registry 工具注册中心
+type Registry = tools.Registry
+
+// ToolInfo 工具信息
+type ToolInfo = tools.ToolInfo
+
+// Options 工具集创建选项
+type Options struct {
+	DataDir string         // 数据目录（为空时使用 jcp 默认数据目录）
+	Market  *market.Client // 复用的行情客户端（为空时新建）
+	LLM     model.LLM      // 散户情绪总结使用的模型（为空时仅返回讨论帖）
+}
+
+// New 创建包含全部内置工具的注册中心
+// 创建时不写入磁盘，数据目录与缓存目录在工具首次需要保存数据时才创建
+func New(opts Options) (*Registry, error) {
+	dataDir := opts.DataDir
+	if dataDir == "" {
+		dataDir = paths.GetDataDir()
+	}
+	configService, err := services.OpenConfigService(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	marketClient := opts.Market
+	if marketClient == nil {
+		marketClient = market.New()
+	}
+
+	// 舆情服务初始化失败时对应工具不可用，不影响其他工具
+	hotTrendService, _ := hottrend.NewHotTrendService()
+	newsService := services.NewNewsService()
+	retailSentimentService := services.NewRetailSentimentService()
+	if opts.LLM != nil {
+		retailSentimentService.SetLLMProvider(func(context.Context) (model.LLM, error) { return opts.LLM, nil })
+	}
+
+	return tools.NewRegistry(
+		marketClient.Service(),
+		newsService,
+		configService,
+		services.NewResearchReportService(),
+		hotTrendService,
+		services.NewLongHuBangService(),
+		services.NewLimitBoardService(),
+		services.NewStockNewsService(newsService),
+		retailSentimentService,
+		services.NewEventService(),
+		services.NewMacroService(),
+		services.NewChartService(marketClient.Service(), filepath.Join(dataDir, "cache", "charts")),
+		services.NewScreenerService(),
+		services.NewPaperTradingService(dataDir, marketClient.Service()),
+	), nil
+}
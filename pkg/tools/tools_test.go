@@ -0,0 +1,25 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewCreatesNoDirectories(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "config"))
+	t.Setenv("HOME", filepath.Join(root, "home"))
+	dataDir := filepath.Join(root, "data")
+
+	r, err := New(Options{DataDir: dataDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.GetAllTools()) == 0 {
+		t.Fatal("expected built-in tools")
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 0 {
+		t.Fatalf("New should not create directories, found %v", entries)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/pkg/paths"
+)
+
+// profileArg 从启动参数中读取工作区名称（支持 -profile x、--profile=x）
+// 桌面端参数不经过 flag 解析，避免与 Wails 自身参数冲突
+func profileArg(args []string) string {
+	for i, arg := range args {
+		name := strings.TrimLeft(arg, "-")
+		if name == arg {
+			continue
+		}
+		if value, ok := strings.CutPrefix(name, "profile="); ok {
+			return value
+		}
+		if name == "profile" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// ProfilesInfo 工作区信息
+type ProfilesInfo struct {
+	Current  string   `json:"current"`
+	Profiles []string `json:"profiles"`
+	DataDir  string   `json:"dataDir"`
+}
+
+// GetProfiles 获取当前工作区与已有工作区列表
+func (a *App) GetProfiles() ProfilesInfo {
+	return ProfilesInfo{
+		Current:  paths.GetProfile(),
+		Profiles: paths.ListProfiles(),
+		DataDir:  paths.GetDataDir(),
+	}
+}
+
+// SwitchProfile 切换工作区并重启应用，工作区不存在时创建
+// copyConfig 为 true 时新工作区沿用当前的配置（AI、MCP 等），其余数据相互独立
+func (a *App) SwitchProfile(name string, copyConfig bool) string {
+	if err := paths.ValidateProfileName(name); err != nil {
+		return err.Error()
+	}
+	if name == paths.GetProfile() {
+		return "success"
+	}
+	dir := paths.ProfileDir(name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err.Error()
+	}
+	if copyConfig {
+		if err := copyConfigFile(paths.GetDataDir(), dir); err != nil {
+			return err.Error()
+		}
+	}
+	if err := paths.SaveActiveProfile(name); err != nil {
+		return err.Error()
+	}
+	log.Info("切换工作区: %s -> %s", paths.GetProfile(), name)
+	return a.RestartApp()
+}
+
+// copyConfigFile 将配置文件复制到新工作区（目标已有配置时保持不变）
+func copyConfigFile(fromDir, toDir string) error {
+	target := filepath.Join(toDir, "config.json")
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+	src, err := os.Open(filepath.Join(fromDir, "config.json"))
+	if err != nil {
+		return fmt.Errorf("读取当前配置失败: %w", err)
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
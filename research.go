@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/meeting"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/i18n"
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+// DeepResearchResponse 深度研究结果，报告同时通过 meeting:message 事件推送
+type DeepResearchResponse struct {
+	Success    bool                `json:"success"`
+	ResearchID string              `json:"researchId,omitempty"` // 失败时可据此恢复
+	Message    *models.ChatMessage `json:"message,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// StartDeepResearch 发起深度研究：研究员分步调用工具调研，每步保存检查点，最后生成长篇报告并保存到会话
+// query 为空时研究该股的整体投资价值
+func (a *App) StartDeepResearch(stockCode, query string) DeepResearchResponse {
+	if a.sessionService.GetSession(stockCode) == nil {
+		return DeepResearchResponse{Error: i18n.T("会话不存在")}
+	}
+	stock := a.researchStock(stockCode)
+	query = strings.TrimSpace(query)
+	if query == "" {
+		query = fmt.Sprintf("%s的投资价值与主要风险", stock.Name)
+	}
+	cp, err := a.meetingService.NewResearch(stockCode, stock, query)
+	if err != nil {
+		return DeepResearchResponse{Error: err.Error()}
+	}
+
+	userMsg := models.ChatMessage{
+		AgentID:   "user",
+		AgentName: "老韭菜",
+		Content:   "【深度研究】" + query,
+	}
+	a.sessionService.AddMessage(stockCode, userMsg)
+	a.eventBus.Emit("meeting:message:"+stockCode, userMsg)
+
+	return a.runDeepResearch(stockCode, stock, cp.ID)
+}
+
+// ResumeDeepResearch 从检查点恢复中断的深度研究（出错、取消或程序退出后），已完成的步骤不再重复
+func (a *App) ResumeDeepResearch(researchID string) DeepResearchResponse {
+	cp, err := a.researchStore.Get(researchID)
+	if err != nil {
+		return DeepResearchResponse{Error: err.Error()}
+	}
+	if cp.Status == meeting.ResearchDone {
+		return DeepResearchResponse{Error: i18n.T("深度研究已完成")}
+	}
+	if a.sessionService.GetSession(cp.StockCode) == nil {
+		return DeepResearchResponse{Error: i18n.T("会话不存在")}
+	}
+	return a.runDeepResearch(cp.StockCode, a.researchStock(cp.StockCode), cp.ID)
+}
+
+// GetDeepResearches 获取股票的深度研究列表（最新的在前），含未完成可恢复的研究
+func (a *App) GetDeepResearches(stockCode string) []meeting.ResearchSummary {
+	return a.researchStore.List(stockCode)
+}
+
+// researchStock 获取深度研究使用的实时行情
+func (a *App) researchStock(stockCode string) models.Stock {
+	stock := models.Stock{Symbol: stockCode}
+	if stocks, _ := a.marketService.GetStockRealTimeData(stockCode); len(stocks) > 0 {
+		stock = stocks[0]
+	}
+	return stock
+}
+
+// runDeepResearch 执行深度研究并保存、推送报告
+func (a *App) runDeepResearch(stockCode string, stock models.Stock, researchID string) DeepResearchResponse {
+	aiConfig := a.configService.GetConfig().ResolveAIConfig(models.AITaskExpert)
+	if aiConfig == nil {
+		return DeepResearchResponse{ResearchID: researchID, Error: i18n.T("未配置 AI 服务")}
+	}
+
+	ctx, endMeeting := a.beginMeeting(a.ctx, stockCode)
+	defer endMeeting()
+
+	progressCallback := func(event meeting.ProgressEvent) {
+		a.eventBus.Emit("meeting:progress:"+stockCode, event)
+	}
+	resp, err := a.meetingService.RunResearch(ctx, aiConfig, researchID, stock, a.sessionService.GetPosition(stockCode), progressCallback)
+	if err != nil {
+		log.Error("deep research %s error: %v", researchID, err)
+		return DeepResearchResponse{ResearchID: researchID, Error: err.Error()}
+	}
+
+	msg := chatMessageFromResponse(resp)
+	a.sessionService.AddMessage(stockCode, msg)
+	a.eventBus.Emit("meeting:message:"+stockCode, msg)
+	a.notify(services.Notification{
+		Category: services.NotifyMeeting,
+		Title:    "深度研究完成：" + stock.Name,
+		Body:     fmt.Sprintf("共 %d 次工具调用，报告已保存到会话", resp.ToolCallCount),
+		Detail:   resp.Content,
+		Key:      fmt.Sprintf("research:%s:%d", stockCode, time.Now().UnixNano()),
+	})
+	return DeepResearchResponse{Success: true, ResearchID: researchID, Message: &msg}
+}
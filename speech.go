@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/run-bigpig/jcp/internal/services"
+)
+
+// SpeechResponse 语音合成结果，Audio 为 base64 编码的音频数据，前端可直接播放
+type SpeechResponse struct {
+	Success  bool   `json:"success"`
+	ID       string `json:"id,omitempty"`
+	Path     string `json:"path,omitempty"` // 语音文件路径，可拷贝到手机收听
+	MimeType string `json:"mimeType,omitempty"`
+	Audio    string `json:"audio,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SpeechReadyEvent 后台合成语音完成事件（tts:ready）
+type SpeechReadyEvent struct {
+	Kind  string `json:"kind"` // briefing
+	Title string `json:"title"`
+	ID    string `json:"id"`
+	Path  string `json:"path"`
+}
+
+// SynthesizeSpeech 将会议总结、早报等文本合成为语音（Markdown 标记与引用编号不朗读）
+func (a *App) SynthesizeSpeech(text string) SpeechResponse {
+	result, err := a.ttsService.Synthesize(a.ctx, text)
+	if err != nil {
+		return SpeechResponse{Error: err.Error()}
+	}
+	return a.LoadSpeech(result.ID)
+}
+
+// LoadSpeech 读取已合成的语音
+func (a *App) LoadSpeech(id string) SpeechResponse {
+	result, data, err := a.ttsService.Load(id)
+	if err != nil {
+		return SpeechResponse{Error: err.Error()}
+	}
+	return SpeechResponse{
+		Success:  true,
+		ID:       result.ID,
+		Path:     result.Path,
+		MimeType: result.MimeType,
+		Audio:    base64.StdEncoding.EncodeToString(data),
+	}
+}
+
+// speakBriefings 将当日自选股早报合成为一段语音，推送 tts:ready 事件
+func (a *App) speakBriefings(ctx context.Context, date, content string) *services.SpeechResult {
+	result, err := a.ttsService.Synthesize(ctx, "盘前早报，"+date+"。\n"+content)
+	if err != nil {
+		log.Warn("早报语音合成失败: %v", err)
+		return nil
+	}
+	log.Info("早报语音已生成: %s", result.Path)
+	a.eventBus.Emit("tts:ready", SpeechReadyEvent{Kind: "briefing", Title: "盘前早报 " + date, ID: result.ID, Path: result.Path})
+	return result
+}